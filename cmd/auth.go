@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Service account credential operations",
+	Long: `Manage the GZCTF service account configured in .gzctf/conf.yaml, including
+rotating its password and refreshing the cached login session.`,
+	Example: `  # Rotate the service account password
+  gzcli auth rotate --generate`,
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+}
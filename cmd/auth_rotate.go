@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/sethvargo/go-password/password"
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	rotatePassword   string
+	rotateGenerate   bool
+	rotateUpdateOnly bool
+)
+
+// authRotateResult is the --output json result of auth rotate
+type authRotateResult struct {
+	Username        string `json:"username"`
+	ChangedOnServer bool   `json:"changed_on_server"`
+	Verified        bool   `json:"verified"`
+}
+
+var authRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the service account password",
+	Long: `Change the password of the service account and store it in .gzctf/conf.yaml.
+
+The password is changed on GZCTF, which signs out every other session of the
+account. The cached login cookies are then replaced by a fresh login with the
+new password, and API access is verified.
+
+If the password was already changed on the server (e.g. in the GZCTF UI), use
+--update-only to store it and refresh the session without changing it again.
+
+The new password comes from --password, --generate or an interactive prompt.`,
+	Example: `  # Rotate to a generated password
+  gzcli auth rotate --generate
+
+  # Rotate to a password typed at the prompt
+  gzcli auth rotate
+
+  # Record a password already changed in the GZCTF UI
+  gzcli auth rotate --update-only`,
+	Run: func(_ *cobra.Command, _ []string) {
+		newPassword, err := rotateNewPassword()
+		if err != nil {
+			log.Fatal("Failed to get the new password: ", err)
+		}
+
+		profile, err := gzcli.RotatePassword(newPassword, !rotateUpdateOnly)
+		if err != nil {
+			log.Fatal("Password rotation failed: ", err)
+		}
+
+		result := authRotateResult{Username: profile.UserName, ChangedOnServer: !rotateUpdateOnly, Verified: true}
+		printResult(result, func() {
+			log.Info("Password of %s rotated, conf.yaml updated and API access verified", profile.UserName)
+		})
+	},
+}
+
+// rotateNewPassword returns the new password from the flags or a prompt
+func rotateNewPassword() (string, error) {
+	switch {
+	case rotatePassword != "" && rotateGenerate:
+		return "", fmt.Errorf("--password and --generate are mutually exclusive")
+	case rotatePassword != "":
+		return rotatePassword, nil
+	case rotateGenerate:
+		return password.Generate(32, 10, 0, false, false)
+	}
+
+	var answers struct {
+		Password string
+		Confirm  string
+	}
+	if err := survey.Ask([]*survey.Question{
+		{Name: "password", Prompt: &survey.Password{Message: "New password:"}, Validate: survey.Required},
+		{Name: "confirm", Prompt: &survey.Password{Message: "Confirm new password:"}, Validate: survey.Required},
+	}, &answers); err != nil {
+		return "", err
+	}
+	if answers.Password != answers.Confirm {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return answers.Password, nil
+}
+
+func init() {
+	authCmd.AddCommand(authRotateCmd)
+
+	authRotateCmd.Flags().StringVar(&rotatePassword, "password", "", "New password (visible in shell history; prefer --generate or the prompt)")
+	authRotateCmd.Flags().BoolVar(&rotateGenerate, "generate", false, "Generate a random new password")
+	authRotateCmd.Flags().BoolVar(&rotateUpdateOnly, "update-only", false, "The password was already changed on the server: only store it and refresh the session")
+}
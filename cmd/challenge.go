@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var challengeCmd = &cobra.Command{
+	Use:     "challenge",
+	Aliases: []string{"chal"},
+	Short:   "Challenge authoring operations",
+	Long: `Work on the challenges of an event:
+  - Listing challenges, optionally only those with given tags
+  - Scaffolding new challenges from the example challenges
+  - Linting challenge.yml files and their attachments and compose files
+  - Starting GZCTF test containers of synced challenges, optionally running
+    their solver against them`,
+	Example: `  # List the beginner challenges of all events
+  gzcli challenge list --tag beginner
+
+  # Scaffold a new challenge in the current event
+  gzcli challenge new
+
+  # Lint the challenges of all events
+  gzcli challenge lint
+
+  # Check a synced challenge is solvable on its GZCTF test container
+  gzcli challenge test "Baby SQLi" --solve --destroy`,
+}
+
+func init() {
+	rootCmd.AddCommand(challengeCmd)
+}
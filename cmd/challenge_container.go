@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	testContainerSolve   bool
+	testContainerDestroy bool
+	testContainerStop    bool
+	testContainerTimeout time.Duration
+)
+
+var challengeTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Start the GZCTF test container of a challenge",
+	Long: `Start the test container of a synced container challenge on GZCTF, as the
+"Test" button of the challenge editor does, and print the entry to connect to.
+
+With --solve, the solver of the challenge (solver/) then runs against the test
+container with HOST and PORT set to its entry, and the command exits non-zero
+unless the solver prints a flag of the challenge. GZCTF stops test containers
+after their lifetime; --destroy stops it as soon as the command is done, and
+--stop only stops a test container started before.`,
+	Example: `  # Start a test container and connect to it
+  gzcli challenge test "Baby SQLi"
+
+  # Check the challenge is solvable, then clean up
+  gzcli challenge test --event ctf2024 "Baby SQLi" --solve --destroy
+
+  # Stop the test container
+  gzcli challenge test "Baby SQLi" --stop`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		name := args[0]
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		if testContainerStop {
+			if err := gz.StopTestContainer(name); err != nil {
+				log.Fatal("Failed to stop the test container: ", err)
+			}
+			log.Info("Stopped the test container of %s", name)
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		log.Info("Starting the test container of %s...", name)
+		tc, err := gz.StartTestContainer(ctx, name)
+		if err != nil {
+			log.Fatal("Failed to start the test container: ", err)
+		}
+
+		result := map[string]any{"challenge": name, "container": tc.Info}
+		var verify *solver.VerifyResult
+		if testContainerSolve {
+			log.Info("Running the solver against %s...", tc.Info.Entry)
+			run, err := tc.Verify(ctx, testContainerTimeout)
+			if err != nil {
+				run = solver.VerifyResult{Status: solver.VerifyError, Err: err}
+			}
+			verify = &run
+			result["solver"] = map[string]string{"status": run.Status, "flag": run.Flag, "output": run.Output}
+		}
+		if testContainerDestroy {
+			if err := tc.Remote.DestroyTestContainer(); err != nil {
+				log.Error("Failed to stop the test container: %v", err)
+			}
+		}
+
+		printResult(result, func() {
+			log.Info("Test container of %s: %s", name, tc.Info.Entry)
+			if !testContainerDestroy && tc.Info.ExpectStopAt.Unix() > 0 {
+				log.Info("Stops at %s", tc.Info.ExpectStopAt.Local().Format(time.DateTime))
+			}
+			switch {
+			case verify == nil:
+			case verify.Status == solver.VerifyPassed:
+				log.Info("✅ Solver recovered the flag %s", verify.Flag)
+			default:
+				log.Error("❌ Solver %s: %v", verify.Status, verify.Err)
+			}
+		})
+		if verify != nil && verify.Status != solver.VerifyPassed {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	challengeCmd.AddCommand(challengeTestCmd)
+
+	challengeTestCmd.Flags().BoolVar(&testContainerSolve, "solve", false, "Run the solver of the challenge against the test container")
+	challengeTestCmd.Flags().BoolVar(&testContainerDestroy, "destroy", false, "Stop the test container when done")
+	challengeTestCmd.Flags().BoolVar(&testContainerStop, "stop", false, "Only stop the test container started before")
+	challengeTestCmd.Flags().DurationVar(&testContainerTimeout, "timeout", solver.DefaultVerifyTimeout, "Maximum run time of the solver")
+
+	challengeTestCmd.ValidArgsFunction = validChallengeNames
+}
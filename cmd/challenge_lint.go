@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	lintEvents        []string
+	lintExcludeEvents []string
+	lintStrict        bool
+	lintVerbose       bool
+)
+
+var challengeLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Validate challenge files without contacting GZCTF",
+	Long: `Run all local validation of challenge.yml files across events and print a
+report per challenge, without contacting the GZCTF API:
+  - required fields and values, as checked by sync
+  - duplicate challenge names
+  - challenge directories outside any category, and renamed categories
+  - flag whitespace, duplicates, prefix{...} format and prefix consistency
+  - missing local attachments and launcher configuration files
+  - docker-compose files that do not parse
+  - fixed host ports published by several challenges
+
+Issues are errors, warnings or info. The command exits non-zero when any
+challenge has errors (or warnings, with --strict), for use in CI.`,
+	Example: `  # Lint the challenges of all events
+  gzcli challenge lint
+
+  # Lint one event and fail CI on warnings too
+  gzcli challenge lint --event ctf2024 --strict
+
+  # Machine-readable report
+  gzcli challenge lint --output json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		events, err := ResolveTargetEvents(lintEvents, lintExcludeEvents)
+		if err != nil {
+			log.Fatal("Failed to resolve target events: ", err)
+		}
+
+		reports := make([]*challenge.LintReport, 0, len(events))
+		failed := false
+		for _, eventName := range events {
+			report, err := gzcli.LintEvent(eventName)
+			if err != nil {
+				log.Error("[%s] %v", eventName, err)
+				failed = true
+				continue
+			}
+			reports = append(reports, report)
+			failed = failed || report.Failed(lintStrict)
+		}
+
+		printResult(reports, func() {
+			for _, report := range reports {
+				report.Print(os.Stdout, lintVerbose)
+			}
+		})
+		if failed {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	challengeCmd.AddCommand(challengeLintCmd)
+
+	challengeLintCmd.Flags().StringSliceVarP(&lintEvents, "event", "e", []string{}, "Specific event(s) to lint (can be specified multiple times)")
+	challengeLintCmd.Flags().StringSliceVar(&lintExcludeEvents, "exclude-event", []string{}, "Event(s) to exclude from linting (can be specified multiple times)")
+	challengeLintCmd.Flags().BoolVar(&lintStrict, "strict", false, "Exit non-zero on warnings too")
+	challengeLintCmd.Flags().BoolVarP(&lintVerbose, "verbose", "v", false, "Also list challenges without issues")
+}
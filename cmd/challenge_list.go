@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	listEvents        []string
+	listExcludeEvents []string
+	listTags          []string
+)
+
+var challengeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the challenges of events without contacting GZCTF",
+	Long: `List the challenges of the events from their challenge.yml files: their
+category, name, type, value and tags. With --tag, only the challenges carrying
+at least one of the given tags are listed.`,
+	Example: `  # List the challenges of all events
+  gzcli challenge list
+
+  # List the beginner and warmup challenges of ctf2024
+  gzcli challenge list --event ctf2024 --tag beginner --tag warmup
+
+  # Machine-readable list
+  gzcli challenge list --output json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		events, err := ResolveTargetEvents(listEvents, listExcludeEvents)
+		if err != nil {
+			log.Fatal("Failed to resolve target events: ", err)
+		}
+
+		listings := []gzcli.ChallengeListing{}
+		failed := false
+		for _, eventName := range events {
+			eventListings, err := gzcli.ListChallenges(eventName, listTags)
+			if err != nil {
+				log.Error("[%s] %v", eventName, err)
+				failed = true
+				continue
+			}
+			listings = append(listings, eventListings...)
+		}
+
+		printResult(listings, func() { printChallengeListings(listings) })
+		if failed {
+			exit(1)
+		}
+	},
+}
+
+// printChallengeListings prints challenges as a table
+func printChallengeListings(listings []gzcli.ChallengeListing) {
+	if len(listings) == 0 {
+		log.Info("No challenges")
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "EVENT\tCATEGORY\tNAME\tTYPE\tVALUE\tTAGS")
+	for _, l := range listings {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", l.Event, l.Category, l.Name, l.Type, l.Value, strings.Join(l.Tags, ", "))
+	}
+	_ = tw.Flush()
+}
+
+func init() {
+	challengeCmd.AddCommand(challengeListCmd)
+
+	challengeListCmd.Flags().StringSliceVarP(&listEvents, "event", "e", []string{}, "Specific event(s) to list (can be specified multiple times)")
+	challengeListCmd.Flags().StringSliceVar(&listExcludeEvents, "exclude-event", []string{}, "Event(s) to exclude from the list (can be specified multiple times)")
+	challengeListCmd.Flags().StringSliceVar(&listTags, "tag", []string{}, "Only list challenges with one of these tags (can be specified multiple times)")
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/structure"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	newChallengeCategory string
+	newChallengeName     string
+	newChallengeAuthor   string
+	newChallengeType     string
+	newChallengeCompose  bool
+)
+
+var challengeNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Scaffold a new challenge in the current event",
+	Long: `Create the directory of a new challenge in a category of the current event
+from the example challenge of its type, as offered by the upload server:
+  - StaticAttachment: challenge.yml, src/, dist/ and solver/; with --compose,
+    also a Dockerfile and docker-compose.yml started by the launcher
+  - StaticContainer and DynamicContainer: also a Dockerfile and
+    docker-compose.yml in src/
+
+The challenge is created in <event>/<category>/<name>, with the name lowercased
+and non-alphanumeric characters replaced by dashes. Values missing from the
+flags are asked for on a terminal.`,
+	Example: `  # Answer prompts for the category, type, name and author
+  gzcli challenge new
+
+  # Without prompts
+  gzcli challenge new --category Web --type DynamicContainer --name "Baby SQLi" --author alice
+
+  # Attachment challenge with a compose launcher
+  gzcli challenge new --category Pwn --type StaticAttachment --compose --name heap`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		eventName, err := config.GetCurrentEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to determine event: ", err)
+		}
+		eventPath, err := config.GetEventPath(eventName)
+		if err != nil {
+			log.Fatal("Failed to find event: ", err)
+		}
+
+		opts := structure.ScaffoldOptions{
+			EventPath: eventPath,
+			Category:  newChallengeCategory,
+			Name:      newChallengeName,
+			Author:    newChallengeAuthor,
+			Type:      newChallengeType,
+			Compose:   newChallengeCompose,
+		}
+		if err := askNewChallenge(&opts); err != nil {
+			log.Fatal(err)
+		}
+
+		dir, err := structure.Scaffold(opts)
+		if err != nil {
+			log.Fatal("Failed to create challenge: ", err)
+		}
+
+		result := map[string]string{"event": eventName, "path": dir, "type": opts.Type}
+		printResult(result, func() {
+			log.Info("Created %s challenge %q in %s", opts.Type, opts.Name, dir)
+			log.Info("Edit %s, then run \"gzcli sync\"", filepath.Join(dir, "challenge.yml"))
+		})
+	},
+}
+
+// askNewChallenge prompts for the options missing from the flags when stdin
+// is a terminal
+func askNewChallenge(opts *structure.ScaffoldOptions) error {
+	if opts.Category != "" && opts.Type != "" && opts.Name != "" {
+		return nil
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("--category, --type and --name are required without a terminal")
+	}
+
+	var questions []*survey.Question
+	if opts.Category == "" {
+		questions = append(questions, &survey.Question{
+			Name:   "category",
+			Prompt: &survey.Select{Message: "Category:", Options: config.CHALLENGE_CATEGORY},
+		})
+	}
+	if opts.Type == "" {
+		questions = append(questions, &survey.Question{
+			Name:   "type",
+			Prompt: &survey.Select{Message: "Type:", Options: structure.ChallengeTypes},
+		})
+	}
+	if opts.Name == "" {
+		questions = append(questions, &survey.Question{
+			Name:     "name",
+			Prompt:   &survey.Input{Message: "Name:"},
+			Validate: survey.Required,
+		})
+	}
+	if opts.Author == "" {
+		questions = append(questions, &survey.Question{
+			Name:   "author",
+			Prompt: &survey.Input{Message: "Author:"},
+		})
+	}
+
+	answers := struct {
+		Category string
+		Type     string
+		Name     string
+		Author   string
+	}{opts.Category, opts.Type, opts.Name, opts.Author}
+	if err := survey.Ask(questions, &answers); err != nil {
+		return err
+	}
+	opts.Category, opts.Type, opts.Name, opts.Author = answers.Category, answers.Type, answers.Name, answers.Author
+
+	if opts.Type == structure.TypeStaticAttachment && !opts.Compose {
+		if err := survey.AskOne(&survey.Confirm{Message: "Start it with a docker-compose launcher?"}, &opts.Compose); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	challengeCmd.AddCommand(challengeNewCmd)
+
+	challengeNewCmd.Flags().StringVar(&newChallengeCategory, "category", "", "Category of the challenge, e.g. Web")
+	challengeNewCmd.Flags().StringVar(&newChallengeType, "type", "", "StaticAttachment, StaticContainer or DynamicContainer")
+	challengeNewCmd.Flags().StringVar(&newChallengeName, "name", "", "Name of the challenge")
+	challengeNewCmd.Flags().StringVar(&newChallengeAuthor, "author", "", "Author of the challenge")
+	challengeNewCmd.Flags().BoolVar(&newChallengeCompose, "compose", false, "Give a StaticAttachment challenge a docker-compose launcher")
+
+	_ = challengeNewCmd.RegisterFlagCompletionFunc("category", validCategories)
+	_ = challengeNewCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(structure.ChallengeTypes, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/compat"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var compatGZCTFVersion string
+
+var compatCmd = &cobra.Command{
+	Use:   "compat",
+	Short: "Show gzcli, GZCTF and tooling versions and whether they are tested together",
+	Long: `Print the gzcli version, the GZCTF version, and the Docker, Docker Compose and
+kubectl versions, checked against the support matrix of this gzcli release.
+
+The GZCTF version is asked from the server of .gzctf/conf.yaml first. When
+the server cannot tell, it is read from the version label of the local image
+of the gzctf service in .gzctf/compose.yml, or from its tag. Pass it with
+--gzctf-version when neither knows it.
+
+Untested versions are reported as warnings; the command does not fail on
+them. Include its output in bug reports.`,
+	Example: `  gzcli compat
+  gzcli compat --gzctf-version 1.5.0 --output json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		versions := compat.Detect(context.Background(), compat.Options{
+			GZCTFVersion: compatGZCTFVersion,
+			ServerURL:    gzctfServerURL(),
+			ComposeFile:  gzctfComposeFile(),
+		})
+		report := compat.Check(Version, versions, compat.DefaultMatrix())
+		printResult(report, func() {
+			report.Print(os.Stdout)
+		})
+	},
+}
+
+// gzctfServerURL returns the URL of the configured GZCTF server, or "" when
+// the workspace has no server config
+func gzctfServerURL() string {
+	serverConfig, err := config.GetServerConfig()
+	if err != nil {
+		return ""
+	}
+	return serverConfig.Url
+}
+
+// gzctfComposeFile returns the compose file of the workspace's GZCTF
+// deployment, or "" when there is none
+func gzctfComposeFile() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Error("Failed to get working directory: %v", err)
+		return ""
+	}
+	for _, name := range []string{"compose.yml", "compose.yaml", "docker-compose.yml", "docker-compose.yaml"} {
+		path := filepath.Join(cwd, config.GZCTF_DIR, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(compatCmd)
+
+	compatCmd.Flags().StringVar(&compatGZCTFVersion, "gzctf-version", "", "GZCTF version, when it cannot be detected from the server or .gzctf/compose.yml")
+}
@@ -3,10 +3,13 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
 )
 
 // validEventNames returns a list of valid event names for completion
@@ -51,6 +54,90 @@ func getAvailableEvents() ([]string, error) {
 	return eventNames, nil
 }
 
+// completionEventName returns the event whose challenges are completed: the
+// (first) --event of the command, or the current event
+func completionEventName(cmd *cobra.Command) (string, error) {
+	if flag := cmd.Flags().Lookup("event"); flag != nil {
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			if events := values.GetSlice(); len(events) > 0 {
+				return events[0], nil
+			}
+		} else if flag.Value.String() != "" {
+			return flag.Value.String(), nil
+		}
+	}
+	return config.GetCurrentEvent(GetEventFlag())
+}
+
+// localChallenges discovers the challenges in the directory of the event
+// completed by cmd
+func localChallenges(cmd *cobra.Command) ([]registry.Entry, error) {
+	eventName, err := completionEventName(cmd)
+	if err != nil {
+		return nil, err
+	}
+	eventPath, err := config.GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+	return registry.Discover(eventName, eventPath)
+}
+
+// validChallengeFolders completes the challenges of the event as
+// "category/folder", the way the watcher and --only name them, skipping
+// those already given as arguments
+func validChallengeFolders(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	entries, err := localChallenges(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if slices.Contains(args, entry.Key()) {
+			continue
+		}
+		completion := entry.Key()
+		if challenge, err := entry.Load(); err == nil && challenge.Name != "" {
+			completion += "\t" + challenge.Name
+		}
+		completions = append(completions, completion)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// validChallengeFolder completes a single challenge folder argument
+func validChallengeFolder(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return validChallengeFolders(cmd, args, toComplete)
+}
+
+// validChallengeNames completes the names of the challenges of the event
+// synced to GZCTF, as the first argument only
+func validChallengeNames(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	entries, err := localChallenges(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.KnownCategory() {
+			continue
+		}
+		if challenge, err := entry.Load(); err == nil && challenge.Name != "" {
+			completions = append(completions, challenge.Name+"\t"+entry.Category)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// validCategories completes the categories synced to GZCTF
+var validCategories = cobra.FixedCompletions(config.CHALLENGE_CATEGORY, cobra.ShellCompDirectiveNoFileComp)
+
 // completionCmd represents the completion command
 var completionCmd = &cobra.Command{
 	Use:   "completion [bash|zsh|fish|powershell]",
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// remoteCompletionTTL is how long completions fetched from the server are
+// reused by the next tab presses
+const remoteCompletionTTL = 30 * time.Second
+
+var (
+	// remoteCompletionTimeout bounds a completion querying the server, so a
+	// slow or unreachable server never hangs the shell
+	remoteCompletionTimeout = 3 * time.Second
+
+	// remoteCompletionDir holds the cached completions fetched from the server
+	remoteCompletionDir = filepath.Join(".gzcli", "completion")
+
+	// completionClient creates the API client of completions; a variable so
+	// tests can replace it
+	completionClient = completionAPI
+)
+
+// remoteCompletionCache is a cached list of completions
+type remoteCompletionCache struct {
+	FetchedAt   time.Time `yaml:"fetched_at"`
+	Completions []string  `yaml:"completions"`
+}
+
+// remoteCompletions returns the completions cached under key if they are
+// fresh, otherwise fetches them with an API client of the server in
+// .gzctf/conf.yaml. Completion gives up on the server after
+// remoteCompletionTimeout.
+func remoteCompletions(key string, fetch func(api *gzapi.GZAPI) ([]string, error)) ([]string, cobra.ShellCompDirective) {
+	path := filepath.Join(remoteCompletionDir, key+".yaml")
+	var cached remoteCompletionCache
+	//nolint:gosec // G304: Cache files are created by gzcli itself
+	if data, err := os.ReadFile(path); err == nil && yaml.Unmarshal(data, &cached) == nil &&
+		time.Since(cached.FetchedAt) < remoteCompletionTTL {
+		return cached.Completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Keep stdout for the completions
+	log.SetInfoOutput(io.Discard)
+
+	type result struct {
+		completions []string
+		err         error
+	}
+	done := make(chan result, 1)
+	go func() {
+		api, err := completionClient()
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		completions, err := fetch(api)
+		done <- result{completions, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if data, err := yaml.Marshal(remoteCompletionCache{FetchedAt: time.Now(), Completions: r.completions}); err == nil {
+			if err := os.MkdirAll(remoteCompletionDir, 0700); err == nil {
+				_ = os.WriteFile(path, data, 0600)
+			}
+		}
+		return r.completions, cobra.ShellCompDirectiveNoFileComp
+	case <-time.After(remoteCompletionTimeout):
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionAPI returns an API client of the configured server whose
+// requests time out within the completion timeout
+func completionAPI() (*gzapi.GZAPI, error) {
+	conf, err := config.GetServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	api, err := gzapi.Init(conf.Url, &conf.Creds)
+	if err != nil {
+		return nil, err
+	}
+	return api.WithTimeouts(gzapi.Timeouts{Connect: remoteCompletionTimeout, Request: remoteCompletionTimeout}), nil
+}
+
+// completionGame returns the game of the current event on the server
+func completionGame(api *gzapi.GZAPI) (*gzapi.Game, error) {
+	eventName, err := config.GetCurrentEvent(GetEventFlag())
+	if err != nil {
+		return nil, err
+	}
+	event, err := config.GetEventConfig(eventName)
+	if err != nil {
+		return nil, err
+	}
+	games, err := api.GetGames()
+	if err != nil {
+		return nil, err
+	}
+	game := challenge.FindCurrentGame(games, event.Title, api)
+	if game == nil {
+		return nil, fmt.Errorf("game %q not found", event.Title)
+	}
+	return game, nil
+}
+
+// remoteGameIDs completes the IDs of the games on the server
+func remoteGameIDs(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return remoteCompletions("games", func(api *gzapi.GZAPI) ([]string, error) {
+		games, err := api.GetGames()
+		if err != nil {
+			return nil, err
+		}
+		completions := make([]string, 0, len(games))
+		for _, game := range games {
+			completions = append(completions, fmt.Sprintf("%d\t%s", game.Id, game.Title))
+		}
+		return completions, nil
+	})
+}
+
+// remoteChallengeTitles completes the titles of the challenges of the
+// current event's game on the server
+func remoteChallengeTitles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return remoteCompletions("challenges-"+completionEventKey(), func(api *gzapi.GZAPI) ([]string, error) {
+		game, err := completionGame(api)
+		if err != nil {
+			return nil, err
+		}
+		challenges, err := game.ListChallenges()
+		if err != nil {
+			return nil, err
+		}
+		completions := make([]string, 0, len(challenges))
+		for _, c := range challenges {
+			completions = append(completions, fmt.Sprintf("%s\t%s", c.Title, c.Category))
+		}
+		return completions, nil
+	})
+}
+
+// remoteNoticeIDs completes the IDs of the notices of the current event's
+// game on the server, as the first argument only
+func remoteNoticeIDs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return remoteCompletions("notices-"+completionEventKey(), func(api *gzapi.GZAPI) ([]string, error) {
+		game, err := completionGame(api)
+		if err != nil {
+			return nil, err
+		}
+		notices, err := game.GetNotices()
+		if err != nil {
+			return nil, err
+		}
+		completions := make([]string, 0, len(notices))
+		for _, n := range notices {
+			completions = append(completions, strconv.Itoa(n.Id)+"\t"+firstLine(n.Content()))
+		}
+		return completions, nil
+	})
+}
+
+// remoteUserNames completes the username of a user command's first argument
+func remoteUserNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return remoteCompletions("users", func(api *gzapi.GZAPI) ([]string, error) {
+		users, err := api.Users()
+		if err != nil {
+			return nil, err
+		}
+		completions := make([]string, 0, len(users))
+		for _, u := range users {
+			completions = append(completions, u.UserName+"\t"+u.Email)
+		}
+		return completions, nil
+	})
+}
+
+// validUserRoleArgs completes the user, then the role of "gzcli user role"
+func validUserRoleArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 1 {
+		return gzapi.UserRoles(), cobra.ShellCompDirectiveNoFileComp
+	}
+	return remoteUserNames(cmd, args, toComplete)
+}
+
+// completionEventKey names the cache of completions of the current event
+func completionEventKey() string {
+	eventName, err := config.GetCurrentEvent(GetEventFlag())
+	if err != nil || eventName == "" {
+		return "default"
+	}
+	return eventName
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestRemoteCompletions_CachesAndTimesOut(t *testing.T) {
+	origDir, origTimeout, origClient := remoteCompletionDir, remoteCompletionTimeout, completionClient
+	remoteCompletionDir = filepath.Join(t.TempDir(), "completion")
+	remoteCompletionTimeout = 100 * time.Millisecond
+	completionClient = func() (*gzapi.GZAPI, error) { return &gzapi.GZAPI{}, nil }
+	t.Cleanup(func() {
+		remoteCompletionDir, remoteCompletionTimeout, completionClient = origDir, origTimeout, origClient
+	})
+
+	calls := 0
+	fetch := func(*gzapi.GZAPI) ([]string, error) {
+		calls++
+		return []string{"1\tCTF 2024", "2\tCTF 2025"}, nil
+	}
+	want := []string{"1\tCTF 2024", "2\tCTF 2025"}
+	for i := 0; i < 2; i++ {
+		got, _ := remoteCompletions("games", fetch)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("remoteCompletions() = %q, want %q", got, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want the second completion from the cache", calls)
+	}
+
+	// A hanging server does not hang the shell
+	block := make(chan struct{})
+	defer close(block)
+	start := time.Now()
+	got, _ := remoteCompletions("slow", func(*gzapi.GZAPI) ([]string, error) {
+		<-block
+		return []string{"late"}, nil
+	})
+	if got != nil || time.Since(start) > time.Second {
+		t.Errorf("slow completion = %q after %v, want none within the timeout", got, time.Since(start))
+	}
+
+	// Failures are not cached
+	got, _ = remoteCompletions("failing", func(*gzapi.GZAPI) ([]string, error) {
+		return nil, errors.New("unauthorized")
+	})
+	if got != nil {
+		t.Errorf("failed completion = %q, want none", got)
+	}
+}
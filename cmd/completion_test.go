@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -206,3 +207,50 @@ func TestCompletionCommand(t *testing.T) {
 
 	t.Log("Completion command validated successfully")
 }
+
+// TestValidChallengeFolders tests completion of local challenges
+func TestValidChallengeFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(oldWd) }()
+	_ = os.Chdir(tmpDir)
+
+	eventDir := filepath.Join(tmpDir, "events", "ctf2025")
+	_ = os.MkdirAll(eventDir, 0750)
+	//nolint:gosec // G306: Test file permissions are acceptable
+	_ = os.WriteFile(filepath.Join(eventDir, ".gzevent"), []byte("title: Test\n"), 0644)
+	for dir, name := range map[string]string{"Web/login": "Login Bypass", "Pwn/heap": "Heap", "Notes/todo": "Todo"} {
+		_ = os.MkdirAll(filepath.Join(eventDir, dir), 0750)
+		//nolint:gosec // G306: Test file permissions are acceptable
+		_ = os.WriteFile(filepath.Join(eventDir, dir, "challenge.yml"), []byte("name: "+name+"\n"), 0644)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("event", "", "")
+	_ = cmd.Flags().Set("event", "ctf2025")
+
+	completions, directive := validChallengeFolders(cmd, []string{"Pwn/heap"}, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected NoFileComp directive, got %v", directive)
+	}
+	want := []string{"Notes/todo\tTodo", "Web/login\tLogin Bypass"}
+	if !slices.Equal(completions, want) {
+		t.Errorf("validChallengeFolders() = %q, want %q", completions, want)
+	}
+
+	names, _ := validChallengeNames(cmd, nil, "")
+	want = []string{"Heap\tPwn", "Login Bypass\tWeb"}
+	if !slices.Equal(names, want) {
+		t.Errorf("validChallengeNames() = %q, want %q", names, want)
+	}
+	if names, _ := validChallengeNames(cmd, []string{"Heap"}, ""); len(names) != 0 {
+		t.Errorf("validChallengeNames() after the argument = %q, want none", names)
+	}
+
+	missing := &cobra.Command{}
+	missing.Flags().String("event", "", "")
+	_ = missing.Flags().Set("event", "missing")
+	if _, directive := validChallengeFolders(missing, nil, ""); directive != cobra.ShellCompDirectiveError {
+		t.Errorf("Expected Error directive for a missing event, got %v", directive)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the workspace configuration files",
+	Long:  `Commands working on .gzctf/conf.yaml, .gzctf/appsettings.json and events/*/.gzevent.`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: "Validate configuration files without contacting GZCTF",
+	Long: `Check the configuration files of the workspace and report every problem with
+its file, line and YAML (or JSON) path, instead of the unmarshal error a sync
+would stop at:
+  - YAML syntax errors, values of the wrong type and unknown fields
+  - conf.yaml: url is an http(s) URL, credentials are set, sync tuning is not negative
+  - .gzevent: title, start and end are set, dates are RFC 3339 and in order,
+    limits are not negative, the poster exists, and the defaults, upload and
+    git blocks are valid
+  - appsettings.json: container provider type, port mapping, public entry,
+    Docker URI, Kubernetes CIDRs and DNS servers, and registry settings
+
+Without arguments, .gzctf/conf.yaml, .gzctf/appsettings.json and the .gzevent of
+every event are checked. The command exits non-zero when any problem is found,
+for use in CI.`,
+	Example: `  # Validate the whole workspace
+  gzcli config validate
+
+  # Validate one event
+  gzcli config validate events/ctf2024/.gzevent
+
+  # Machine-readable report
+  gzcli config validate --output json`,
+	Run: func(_ *cobra.Command, args []string) {
+		var problems []config.Problem
+		if len(args) == 0 {
+			problems = config.ValidateWorkspace(".")
+		}
+		for _, file := range args {
+			found, err := config.ValidateFile(file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			problems = append(problems, found...)
+		}
+		if problems == nil {
+			problems = []config.Problem{}
+		}
+
+		printResult(problems, func() {
+			for _, p := range problems {
+				fmt.Println(p)
+			}
+			if len(problems) == 0 {
+				log.Info("✅ Configuration is valid")
+			} else {
+				log.Error("%d configuration problem(s) found", len(problems))
+			}
+		})
+		if len(problems) > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/metrics"
+	"github.com/dimasma0305/gzcli/internal/gzcli/supervisor"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	daemonConfigPath   string
+	daemonWatcher      bool
+	daemonLauncher     bool
+	daemonUploadServer bool
+	daemonLogFile      string
+	daemonStatusAddr   string
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the watcher, launcher and upload server in one process",
+	Long: `Run the file watcher, challenge launcher and upload server as a single
+process sharing one configuration file, one log stream and one status endpoint.
+
+Configuration is read from .gzctf/daemon.yaml when present:
+
+  logFile: .gzcli/daemon.log
+  statusAddr: 127.0.0.1:7374
+  shutdownTimeout: 30s
+  watcher:
+    enabled: true
+    events: []
+    excludeEvents: [practice]
+    debounce: 2s
+  launcher:
+    enabled: true
+    host: localhost
+    port: 8080
+    probeHost: ctf.example.com  # address used to self-test challenge ports
+  uploadServer:
+    enabled: false
+    port: 8090
+
+The --watcher, --launcher and --upload-server flags override the enabled
+setting of each subsystem. On SIGINT/SIGTERM all subsystems are stopped
+together; if one subsystem fails, the others are shut down as well.`,
+	Example: `  # Run with .gzctf/daemon.yaml (or defaults: watcher + launcher)
+  gzcli daemon
+
+  # Also host the upload server
+  gzcli daemon --upload-server
+
+  # Launcher only, with a status endpoint
+  gzcli daemon --watcher=false --status-addr 127.0.0.1:7374`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		conf, err := supervisor.LoadConfig(daemonConfigPath)
+		if err != nil {
+			log.Fatal("Failed to load daemon config: ", err)
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("watcher") {
+			conf.Watcher.Enabled = daemonWatcher
+		}
+		if flags.Changed("launcher") {
+			conf.Launcher.Enabled = daemonLauncher
+		}
+		if flags.Changed("upload-server") {
+			conf.UploadServer.Enabled = daemonUploadServer
+		}
+		if flags.Changed("log-file") {
+			conf.LogFile = daemonLogFile
+		}
+		if flags.Changed("status-addr") {
+			conf.StatusAddr = daemonStatusAddr
+		}
+		if err := conf.Validate(); err != nil {
+			log.Fatal("Invalid daemon config: ", err)
+		}
+
+		if conf.LogFile != "" {
+			log.Info("Logging to %s", conf.LogFile)
+			restore, err := supervisor.RedirectOutput(conf.LogFile)
+			if err != nil {
+				log.Fatal("Failed to redirect logs: ", err)
+			}
+			defer restore()
+		}
+
+		sup := supervisor.New(conf.ShutdownTimeout)
+
+		if conf.Watcher.Enabled {
+			sup.Add(supervisor.NewWatcherSubsystem(daemonWatcherGZ(), daemonWatcherConfig(conf.Watcher, conf.Launcher.Socket)))
+		}
+		if conf.Launcher.Enabled {
+			sup.Add(supervisor.NewLauncherSubsystem(conf.Launcher))
+		}
+		if conf.UploadServer.Enabled {
+			sup.Add(supervisor.NewUploadSubsystem(conf.UploadServer))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if conf.StatusAddr != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/status", sup.StatusHandler())
+			mux.Handle("GET /metrics", metrics.Default.Handler(""))
+			statusSrv := &http.Server{
+				Addr:              conf.StatusAddr,
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				if err := statusSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("Status endpoint error: %v", err)
+				}
+			}()
+			defer func() { _ = statusSrv.Close() }()
+			log.Info("Daemon status available at http://%s/status, metrics at /metrics", conf.StatusAddr)
+		}
+
+		if err := sup.Run(ctx); err != nil {
+			log.Error("Daemon stopped with errors: %v", err)
+			os.Exit(1)
+		}
+		log.Info("Daemon stopped")
+	},
+}
+
+// daemonWatcherGZ initializes the API client used by the watcher subsystem
+func daemonWatcherGZ() *gzcli.GZ {
+	gz, err := gzcli.InitWithEvent("")
+	if err != nil {
+		log.Fatal("Failed to initialize: ", err)
+	}
+	return gz
+}
+
+// daemonWatcherConfig converts the daemon's watcher section into a watcher
+// configuration that notifies the co-hosted launcher's socket
+func daemonWatcherConfig(conf supervisor.WatcherConfig, launcherSocket string) gzcli.WatcherConfig {
+	events, err := ResolveTargetEvents(conf.Events, conf.ExcludeEvents)
+	if err != nil {
+		log.Fatal("Failed to resolve target events: ", err)
+	}
+	log.InfoH2("Watching %d event(s): %v", len(events), events)
+
+	watcherConf := gzcli.DefaultWatcherConfig
+	watcherConf.Events = events
+	watcherConf.DaemonMode = false
+	watcherConf.GitPullEnabled = conf.GitPull
+	watcherConf.Polling = conf.Poll
+	watcherConf.DryRun = conf.DryRun
+	watcherConf.FormatYaml = conf.FormatYaml
+	watcherConf.ImageScanEnabled = conf.ImageScan || conf.ImageScanBlock
+	watcherConf.ImageScanCommand = conf.ImageScanCommand
+	watcherConf.ImageScanBlock = conf.ImageScanBlock
+	watcherConf.AnnounceWebhook = conf.AnnounceWebhook
+	watcherConf.LiveLock = conf.LiveLock
+	watcherConf.Targets = conf.Targets
+	if watcherConf.AnnounceWebhook == "" {
+		watcherConf.AnnounceWebhook = os.Getenv(announce.WebhookEnv)
+	}
+	watcherConf.LauncherSocketPath = launcherSocket
+	if conf.Debounce > 0 {
+		watcherConf.DebounceTime = conf.Debounce
+	}
+	if conf.PollInterval > 0 {
+		watcherConf.PollInterval = conf.PollInterval
+	}
+	if conf.GitInterval > 0 {
+		watcherConf.GitPullInterval = conf.GitInterval
+	}
+	if conf.GitRepository != "" {
+		watcherConf.GitRepository = conf.GitRepository
+	}
+	if len(conf.Ignore) > 0 {
+		watcherConf.IgnorePatterns = append(append([]string{}, watcherConf.IgnorePatterns...), conf.Ignore...)
+	}
+	return watcherConf
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringVar(&daemonConfigPath, "config", supervisor.DefaultConfigPath, "Daemon configuration file")
+	daemonCmd.Flags().BoolVar(&daemonWatcher, "watcher", true, "Run the file watcher")
+	daemonCmd.Flags().BoolVar(&daemonLauncher, "launcher", true, "Run the challenge launcher")
+	daemonCmd.Flags().BoolVar(&daemonUploadServer, "upload-server", false, "Run the upload server")
+	daemonCmd.Flags().StringVar(&daemonLogFile, "log-file", "", "Write all subsystem logs to this file")
+	daemonCmd.Flags().StringVar(&daemonStatusAddr, "status-addr", "", "Serve subsystem status at http://<addr>/status and Prometheus metrics at /metrics")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	deploymentsFormat    string
+	deploymentsFile      string
+	deploymentsChallenge string
+	deploymentsHistory   bool
+	deploymentsLimit     int
+	deploymentsDatabase  string
+)
+
+var deploymentsCmd = &cobra.Command{
+	Use:   "deployments",
+	Short: "Export the reproducibility report of deployed challenges",
+	Long: `Show what "gzcli sync" and the watcher deployed to GZCTF, for post-event audits.
+
+Every successful sync records, in the watcher database, the SHA-256 of the
+challenge.yaml, the SHA-256 of the attachment as uploaded, the container image
+and the digest it is pinned to. Images built and pushed by gzcli are deployed by
+digest. The revision of a challenge, a counter kept by gzcli rather than a
+GZCTF value, increases each time one of these changes.
+
+By default the latest deployment of each challenge is shown; --history lists
+every recorded sync, newest first.`,
+	Example: `  # Latest deployment of every challenge of ctf2024
+  gzcli deployments --event ctf2024
+
+  # Export the audit report as CSV
+  gzcli deployments --event ctf2024 --format csv --file deployments.csv
+
+  # Every recorded sync of one challenge
+  gzcli deployments --challenge "Baby ROP" --history`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if jsonOutput() && !cmd.Flags().Changed("format") {
+			deploymentsFormat = "json"
+		}
+		if deploymentsFile == "" && deploymentsFormat != "text" {
+			// Keep the export on stdout parseable
+			log.SetInfoOutput(os.Stderr)
+		}
+
+		dbPath := gzcli.WatcherDatabase()
+		if deploymentsDatabase != "" {
+			dbPath = deploymentsDatabase
+		}
+		deployments, err := gzcli.Deployments(dbPath, GetEventFlag(), deploymentsChallenge, !deploymentsHistory, deploymentsLimit)
+		if err != nil {
+			log.Fatal("Failed to read deployments: ", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if deploymentsFile != "" {
+			//nolint:gosec // G304: Output path is provided by the user
+			f, err := os.Create(deploymentsFile)
+			if err != nil {
+				log.Fatal("Failed to create output file: ", err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			out = f
+		}
+
+		if err := gzcli.RenderDeployments(out, deployments, deploymentsFormat); err != nil {
+			log.Fatal("Failed to render deployments: ", err)
+		}
+		if deploymentsFile != "" {
+			log.Info("%d deployment(s) written to %s", len(deployments), deploymentsFile)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deploymentsCmd)
+
+	deploymentsCmd.Flags().StringVar(&deploymentsFormat, "format", "text", "Output format: text, json or csv")
+	deploymentsCmd.Flags().StringVarP(&deploymentsFile, "file", "f", "", "Write to a file instead of stdout")
+	deploymentsCmd.Flags().StringVar(&deploymentsChallenge, "challenge", "", "Only show deployments of the challenge with this title")
+	deploymentsCmd.Flags().BoolVar(&deploymentsHistory, "history", false, "List every recorded sync instead of the latest per challenge")
+	deploymentsCmd.Flags().IntVar(&deploymentsLimit, "limit", 0, "Maximum number of entries to show (0 for all)")
+	deploymentsCmd.Flags().StringVar(&deploymentsDatabase, "db", "", "Watcher database to read, a file or postgres:// URL (default "+gzcli.DefaultWatcherConfig.DatabasePath+")")
+
+	_ = deploymentsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{"text", "json", "csv"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = deploymentsCmd.RegisterFlagCompletionFunc("challenge", remoteChallengeTitles)
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/compat"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/doctor"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/server"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var doctorAPI bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment, workspace and GZCTF server compatibility",
+	Long: `Check the environment gzcli runs in and print a pass/warn/fail report with a
+hint fixing each problem:
+
+  - Tools: Docker, Docker Compose and kubectl are installed, the Docker
+    engine is reachable and their versions are tested (see "gzcli compat")
+  - GZCTF server: .gzctf/conf.yaml loads, the server accepts connections and
+    logs in with the configured credentials, which have admin access
+  - Workspace: every directory of events/ has a valid .gzevent and its
+    challenges are in category directories
+  - Watcher: the inotify watch limit fits the event directories, the watcher
+    and launcher sockets accept connections when present, and the watcher
+    database opens and passes an integrity check
+
+Once the server checks pass, the current event is checked as well.
+
+With --api, gzcli also calls the read-only endpoints it depends on and
+compares every JSON response against the structures it decodes them into.
+Unknown fields (data gzcli silently drops) and missing expected fields (data
+gzcli silently zeroes) are reported per endpoint, so incompatibilities
+introduced by GZCTF upgrades are caught before a sync goes wrong.
+
+Shared services of the event (see "gzcli services") are listed with their
+status and the challenges depending on them. Challenges referencing a
+service the event does not define fail the check.
+
+Local attachments are checked against the event's attachment policy
+(defaults.maxAttachmentSizeMB, forbiddenExtensions and requireReadme of
+.gzevent), and violations fail the check.
+
+The command exits non-zero when any check fails; warnings do not.
+Set GZCLI_API_STRICT=1 to log the same drift during any other command.`,
+	Example: `  # Check the environment, configuration and login
+  gzcli doctor
+
+  # Machine-readable report of the environment checks
+  gzcli doctor --output json
+
+  # Also check API response schemas for the current event
+  gzcli doctor --api --event ctf2024`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if doctorAPI {
+			gzapi.SetSchemaCheck(true)
+		}
+
+		serverChecks := doctor.Server()
+		checks := doctorToolChecks()
+		checks = append(checks, serverChecks...)
+		checks = append(checks, doctor.Events()...)
+		checks = append(checks,
+			doctor.Inotify(config.EVENTS_DIR),
+			doctor.Socket("watcher socket", gzcli.DefaultWatcherConfig.SocketPath, "start it with 'gzcli watch start'"),
+			doctor.Socket("launcher socket", server.DefaultSocketPath, "start it with 'gzcli serve'"),
+			doctor.Database(gzcli.WatcherDatabase()),
+		)
+		printResult(checks, func() {
+			doctor.Print(os.Stdout, checks)
+		})
+		if doctor.Failed(serverChecks) {
+			os.Exit(1)
+		}
+		envOK := !doctor.Failed(checks)
+
+		if !jsonOutput() {
+			fmt.Println()
+		}
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Configuration or login failed: ", err)
+		}
+		log.Info("✅ Configuration loaded and logged in")
+
+		servicesOK := reportServices(gz)
+		policyOK := reportAttachmentPolicy(gz)
+		if !doctorAPI {
+			if !envOK || !servicesOK || !policyOK {
+				os.Exit(1)
+			}
+			return
+		}
+
+		log.Info("Probing API endpoints...")
+		probes, err := gz.ProbeAPI()
+		if err != nil {
+			log.Fatal("API probe failed: ", err)
+		}
+
+		failed := 0
+		for _, p := range probes {
+			if p.Err != nil {
+				failed++
+				log.ErrorH2("%s: %v", p.Name, p.Err)
+			} else {
+				log.InfoH2("%s: ok", p.Name)
+			}
+		}
+
+		report := gzapi.SchemaDriftReport()
+		if len(report) == 0 {
+			log.Info("✅ No API schema drift detected")
+		} else {
+			log.Error("API schema drift detected on %d endpoint(s):", len(report))
+			for _, drift := range report {
+				fmt.Printf("  %s\n", drift.Endpoint)
+				if len(drift.Unknown) > 0 {
+					fmt.Printf("    unknown: %s\n", strings.Join(drift.Unknown, ", "))
+				}
+				if len(drift.Missing) > 0 {
+					fmt.Printf("    missing: %s\n", strings.Join(drift.Missing, ", "))
+				}
+			}
+		}
+
+		if failed > 0 || len(report) > 0 || !envOK || !servicesOK || !policyOK {
+			os.Exit(1)
+		}
+	},
+}
+
+// doctorToolChecks checks the container tooling against the support matrix
+func doctorToolChecks() []doctor.Check {
+	versions := compat.Detect(context.Background(), compat.Options{ServerURL: gzctfServerURL(), ComposeFile: gzctfComposeFile()})
+	return doctor.Tools(compat.Check(Version, versions, compat.DefaultMatrix()))
+}
+
+// reportServices prints the shared services of the event and returns false
+// if a challenge references an undefined service
+func reportServices(gz *gzcli.GZ) bool {
+	checks, missing, err := gz.CheckServices(context.Background())
+	if err != nil {
+		log.Error("Shared services check failed: %v", err)
+		return false
+	}
+	if len(checks) == 0 && len(missing) == 0 {
+		return true
+	}
+
+	log.Info("Shared services:")
+	for _, c := range checks {
+		status := c.Status.String()
+		if c.Err != nil {
+			status = fmt.Sprintf("unknown (%v)", c.Err)
+		}
+		log.InfoH2("%s: %s", c.Name, status)
+		if len(c.Dependents) > 0 {
+			log.InfoH3("used by: %s", strings.Join(c.Dependents, ", "))
+		}
+	}
+	for _, m := range missing {
+		log.ErrorH2("%s references unknown service %q", m.Challenge, m.Service)
+	}
+	return len(missing) == 0
+}
+
+// reportAttachmentPolicy prints the attachment policy report of the event
+// and returns false if a challenge violates it
+func reportAttachmentPolicy(gz *gzcli.GZ) bool {
+	report, err := gz.CheckAttachmentPolicy()
+	if err != nil {
+		log.Error("Attachment policy check failed: %v", err)
+		return false
+	}
+	out := os.Stdout
+	if jsonOutput() {
+		out = os.Stderr // Keep stdout for the JSON report
+	}
+	report.Print(out)
+	return report.Err() == nil
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorAPI, "api", false, "Check GZCTF API responses for schema drift")
+}
@@ -43,29 +43,44 @@ var eventListCmd = &cobra.Command{
 	Run: func(_ *cobra.Command, _ []string) {
 		events, err := config.ListEvents()
 		if err != nil {
+			if jsonOutput() {
+				log.Fatal("Failed to list events: ", err)
+			}
 			log.Error("Failed to list events: %v", err)
 			return
 		}
 
-		if len(events) == 0 {
-			log.Info("No events found. Run 'gzcli event create <name>' to create one")
-			return
-		}
-
 		// Get current event (if set)
 		currentEvent, _ := config.GetCurrentEvent("")
 
-		log.Info("Available events:")
+		result := make([]eventListEntry, 0, len(events))
 		for _, event := range events {
-			if event == currentEvent {
-				log.Info("  • %s (current)", event)
-			} else {
-				log.Info("  • %s", event)
-			}
+			result = append(result, eventListEntry{Name: event, Current: event == currentEvent})
 		}
+
+		printResult(result, func() {
+			if len(result) == 0 {
+				log.Info("No events found. Run 'gzcli event create <name>' to create one")
+				return
+			}
+			log.Info("Available events:")
+			for _, event := range result {
+				if event.Current {
+					log.Info("  • %s (current)", event.Name)
+				} else {
+					log.Info("  • %s", event.Name)
+				}
+			}
+		})
 	},
 }
 
+// eventListEntry is an event listed by "gzcli event list"
+type eventListEntry struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+}
+
 var eventCurrentCmd = &cobra.Command{
 	Use:   "current",
 	Short: "Show the current active event",
@@ -73,24 +88,43 @@ var eventCurrentCmd = &cobra.Command{
 	Run: func(_ *cobra.Command, _ []string) {
 		currentEvent, err := config.GetCurrentEvent(GetEventFlag())
 		if err != nil {
+			if jsonOutput() {
+				log.Fatal("Failed to determine current event: ", err)
+			}
 			log.Error("Failed to determine current event: %v", err)
 			log.Info("Use 'gzcli event switch <name>' to set a default event")
 			return
 		}
 
-		log.Info("Current event: %s", currentEvent)
-
 		// Show how it was determined
+		result := currentEventResult{Event: currentEvent, Source: "default"}
 		if GetEventFlag() != "" {
-			log.Info("(set via --event flag)")
+			result.Source = "flag"
 		} else if envEvent := config.GetEnvEvent(); envEvent != "" {
-			log.Info("(set via GZCLI_EVENT environment variable)")
-		} else {
-			log.Info("(auto-detected or set as default)")
+			result.Source = "env"
 		}
+
+		printResult(result, func() {
+			log.Info("Current event: %s", result.Event)
+			switch result.Source {
+			case "flag":
+				log.Info("(set via --event flag)")
+			case "env":
+				log.Info("(set via GZCLI_EVENT environment variable)")
+			default:
+				log.Info("(auto-detected or set as default)")
+			}
+		})
 	},
 }
 
+// currentEventResult is the event shown by "gzcli event current" and how it
+// was selected: "flag", "env" or "default"
+type currentEventResult struct {
+	Event  string `json:"event"`
+	Source string `json:"source"`
+}
+
 var eventSwitchCmd = &cobra.Command{
 	Use:   "switch [event-name]",
 	Short: "Switch to a different event as the default",
@@ -138,3 +138,21 @@ func TestResolveEventTimes_PropagatesFlagName(t *testing.T) {
 		t.Fatalf("expected error mentioning --duration, got: %v", err)
 	}
 }
+
+func TestParseNoticeSchedule(t *testing.T) {
+	now := time.Date(2026, 5, 18, 8, 0, 0, 0, time.UTC)
+
+	got, err := parseNoticeSchedule("2026-05-18T12:00:00Z", now)
+	if err != nil || !got.Equal(now.Add(4*time.Hour)) {
+		t.Errorf("parseNoticeSchedule(time) = %s, %v; want 12:00 UTC", got, err)
+	}
+	got, err = parseNoticeSchedule("90m", now)
+	if err != nil || !got.Equal(now.Add(90*time.Minute)) {
+		t.Errorf("parseNoticeSchedule(delay) = %s, %v; want 09:30 UTC", got, err)
+	}
+	for _, in := range []string{"2026-05-17", "soon"} {
+		if _, err := parseNoticeSchedule(in, now); err == nil {
+			t.Errorf("parseNoticeSchedule(%q) succeeded, want an error", in)
+		}
+	}
+}
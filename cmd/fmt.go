@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	fmtCheck         bool
+	fmtEvents        []string
+	fmtExcludeEvents []string
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Normalize challenge.yml files",
+	Long: `Rewrite challenge.yml files in a canonical layout to keep diffs between
+authors small: top-level keys follow the schema order (unknown keys last),
+string values are double-quoted and files end with a single newline.
+Comments and multi-line values are kept as written.
+
+Files whose formatting would change what they decode to are reported and
+left untouched. With --check, nothing is written and the command exits
+non-zero if any file is not formatted, for use in CI.
+
+"gzcli sync --format" and "gzcli watch start --format-yaml" format the
+challenge.yml of every challenge they sync successfully.`,
+	Example: `  # Format all challenges of all events
+  gzcli fmt
+
+  # Fail CI when a challenge.yml is not formatted
+  gzcli fmt --check
+
+  # Format one event only
+  gzcli fmt --event ctf2024`,
+	Run: func(_ *cobra.Command, _ []string) {
+		events, err := ResolveTargetEvents(fmtEvents, fmtExcludeEvents)
+		if err != nil {
+			log.Fatal("Failed to resolve target events: ", err)
+		}
+
+		var unformatted, failed, total int
+		for _, eventName := range events {
+			files, err := config.ListChallengeFiles(eventName)
+			if err != nil {
+				log.Error("[%s] %v", eventName, err)
+				failed++
+				continue
+			}
+			for _, path := range files {
+				total++
+				changed, err := challenge.FormatFile(path, !fmtCheck)
+				switch {
+				case err != nil:
+					log.Error("%s: %v", path, err)
+					failed++
+				case changed && fmtCheck:
+					fmt.Println(path)
+					unformatted++
+				case changed:
+					log.InfoH2("Formatted %s", path)
+					unformatted++
+				}
+			}
+		}
+
+		switch {
+		case fmtCheck && unformatted > 0:
+			log.Error("%d of %d challenge file(s) are not formatted; run 'gzcli fmt'", unformatted, total)
+		case fmtCheck:
+			log.Info("✅ %d challenge file(s) formatted", total)
+		default:
+			log.Info("Formatted %d of %d challenge file(s)", unformatted, total)
+		}
+		if failed > 0 || (fmtCheck && unformatted > 0) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "Report unformatted files and exit non-zero instead of rewriting them")
+	fmtCmd.Flags().StringSliceVarP(&fmtEvents, "event", "e", []string{}, "Specific event(s) to format (can be specified multiple times)")
+	fmtCmd.Flags().StringSliceVar(&fmtExcludeEvents, "exclude-event", []string{}, "Event(s) to exclude from formatting (can be specified multiple times)")
+}
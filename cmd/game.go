@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	gameExportID    int
+	gameExportFile  string
+	gameImportTitle string
+)
+
+var gameCmd = &cobra.Command{
+	Use:   "game",
+	Short: "Export and import the configuration of games",
+	Long: `Copy the configuration of a game between GZCTF servers as a portable JSON
+bundle: its settings and its challenges with their flags, hints, container
+settings and attachments. Submissions, teams, the poster and server-specific
+IDs are not included.
+
+This promotes a game from staging to production, or recreates its setup after
+losing a server.`,
+}
+
+var gameExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the configuration of a game as a JSON bundle",
+	Example: `  # Export the game of the current event
+  gzcli game export --file ctf2024.json
+
+  # Export another game of the server
+  gzcli game export --game-id 3 > game.json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		bundle, err := gz.ExportGame(gameExportID)
+		if err != nil {
+			log.Fatal("Failed to export game: ", err)
+		}
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			log.Fatal("Failed to encode game bundle: ", err)
+		}
+		data = append(data, '\n')
+
+		if gameExportFile == "" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		// The bundle holds flags
+		if err := os.WriteFile(gameExportFile, data, 0600); err != nil {
+			log.Fatal("Failed to write game bundle: ", err)
+		}
+		log.Info("Exported %q with %d challenge(s) to %s", bundle.Game.Title, len(bundle.Challenges), gameExportFile)
+	},
+}
+
+var gameImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create a game from a JSON bundle",
+	Long: `Create a new game from a bundle written by "gzcli game export", with its
+settings, challenges, flags and attachments. A game with the same title must
+not exist on the server; use --title to import it under another one.
+
+Remote attachments are recreated. Local attachments are attached when the
+server already has their file, otherwise they are reported and uploaded by the
+next "gzcli sync" of the challenge.`,
+	Example: `  # Promote a game exported from staging
+  gzcli game import ctf2024.json
+
+  # Import a copy under another title
+  gzcli game import ctf2024.json --title "CTF 2024 (rehearsal)"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		//nolint:gosec // G304: Bundle chosen by the user
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatal("Failed to read game bundle: ", err)
+		}
+		var bundle gzapi.GameBundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			log.Fatal("Invalid game bundle: ", err)
+		}
+
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		result, importErr := gz.ImportGame(&bundle, gameImportTitle)
+		if result == nil {
+			log.Fatal(importErr)
+		}
+		printResult(result, func() {
+			log.Info("Created game %q (ID %d) with %d challenge(s) and %d flag(s)", result.Title, result.GameID, result.Challenges, result.Flags)
+			for _, warning := range result.Warnings {
+				log.Error("Warning: %s", warning)
+			}
+		})
+		if importErr != nil {
+			log.Fatal("Some challenges failed to import: ", importErr)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gameCmd)
+	gameCmd.AddCommand(gameExportCmd)
+	gameCmd.AddCommand(gameImportCmd)
+
+	gameExportCmd.Flags().IntVar(&gameExportID, "game-id", 0, "Game to export (default: the game of the current event)")
+	gameExportCmd.Flags().StringVarP(&gameExportFile, "file", "f", "", "Write the bundle to a file instead of stdout")
+	gameImportCmd.Flags().StringVar(&gameImportTitle, "title", "", "Title of the new game (default: the title in the bundle)")
+
+	_ = gameExportCmd.RegisterFlagCompletionFunc("game-id", remoteGameIDs)
+}
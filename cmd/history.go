@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/audit"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// auditedCommands are the state-changing commands recorded in the audit log
+var auditedCommands = map[string]bool{
+	"init":          true,
+	"sync":          true,
+	"migrate":       true,
+	"script":        true,
+	"auth rotate":   true,
+	"event create":  true,
+	"event switch":  true,
+	"team create":   true,
+	"team delete":   true,
+	"team prune":    true,
+	"services up":   true,
+	"services down": true,
+	"watch start":   true,
+	"watch stop":    true,
+	"watch remap":   true,
+	"notice send":   true,
+	"notice edit":   true,
+	"notice delete": true,
+
+	"user role":           true,
+	"user reset-password": true,
+	"user delete":         true,
+	"snapshot import":     true,
+	"game import":         true,
+
+	"upload-server token create":   true,
+	"upload-server token revoke":   true,
+	"upload-server review approve": true,
+	"upload-server review reject":  true,
+}
+
+// auditRecorder records the running command, if it is audited
+var auditRecorder *audit.Recorder
+
+// startAudit records the start of cmd when it changes state, and arranges
+// for its outcome to be recorded however it exits
+func startAudit(cmd *cobra.Command) {
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if !auditedCommands[name] {
+		return
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	recorder, err := audit.Start(root, name, os.Args[1:], auditEvent(root))
+	if err != nil {
+		log.Debug("Failed to record command in audit log: %v", err)
+	}
+	auditRecorder = recorder
+	log.SetFatalHook(func(message string) {
+		finishAudit(errors.New(message))
+	})
+}
+
+// auditEvent returns the event selected by --event, GZCLI_EVENT or
+// "gzcli event switch", without the logging of config.GetCurrentEvent
+func auditEvent(root string) string {
+	if event := GetEventFlag(); event != "" {
+		return event
+	}
+	if event := config.GetEnvEvent(); event != "" {
+		return event
+	}
+	//nolint:gosec // G304: path is inside the workspace
+	data, err := os.ReadFile(filepath.Join(root, ".gzcli", "current-event"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// finishAudit records the outcome of the running command
+func finishAudit(err error) {
+	if auditRecorder == nil {
+		return
+	}
+	if err := auditRecorder.Finish(err, log.ErrorCount()); err != nil {
+		log.Debug("Failed to record command in audit log: %v", err)
+	}
+}
+
+// exit records the outcome of the running command and exits with code
+func exit(code int) {
+	if code != 0 {
+		finishAudit(fmt.Errorf("exit status %d", code))
+	} else {
+		finishAudit(nil)
+	}
+	os.Exit(code)
+}
+
+var (
+	historyUser    string
+	historyCommand string
+	historySince   string
+	historyFailed  bool
+	historyLimit   int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the audit log of state-changing commands",
+	Long: `Show who ran which state-changing command in this workspace, when, with
+which arguments, and whether it succeeded.
+
+Commands such as sync, team create/delete/prune, event switch and auth
+rotate are appended to .gzctf/audit.log as they start and finish. A command
+still shown as "started" is running or was killed. Values of flags like
+--password or --token are redacted.
+
+The user is GZCLI_AUDIT_USER when set (for organizers sharing one account on
+a bastion host), else the user who invoked sudo, else the login user.`,
+	Example: `  # Show the last 20 commands
+  gzcli history
+
+  # Failed syncs of the last two days
+  gzcli history --command sync --failed --since 2d
+
+  # Commands of one event
+  gzcli history --event ctf2024
+
+  # Everything alice did to teams, as JSON
+  gzcli history --user alice --command team --limit 0 --output json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		filter := audit.Filter{
+			User:    historyUser,
+			Command: historyCommand,
+			Event:   GetEventFlag(),
+			Failed:  historyFailed,
+			Limit:   historyLimit,
+		}
+		if historySince != "" {
+			window, err := parseEventDuration(historySince)
+			if err != nil {
+				log.Fatal("Invalid --since: ", err)
+			}
+			filter.Since = time.Now().Add(-window)
+		}
+
+		root, err := os.Getwd()
+		if err != nil {
+			log.Fatal("Failed to get working directory: ", err)
+		}
+		entries, err := audit.Read(root)
+		if err != nil {
+			log.Fatal("Failed to read audit log: ", err)
+		}
+		entries = filter.Apply(entries)
+
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		printResult(entries, func() {
+			if len(entries) == 0 {
+				log.Info("No matching commands in %s", audit.Path(root))
+				return
+			}
+			printHistory(entries)
+		})
+	},
+}
+
+// printHistory prints audit log entries as a table
+func printHistory(entries []audit.Entry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tUSER\tEVENT\tOUTCOME\tDURATION\tCOMMAND")
+	for _, e := range entries {
+		outcome := e.Outcome
+		if e.Outcome == audit.OutcomeSuccess && e.Errors > 0 {
+			outcome = fmt.Sprintf("%s, %d error(s) logged", outcome, e.Errors)
+		}
+		duration := "-"
+		if e.Outcome != audit.OutcomeStarted {
+			duration = (time.Duration(e.DurationMS) * time.Millisecond).Round(100 * time.Millisecond).String()
+		}
+		event := e.Event
+		if event == "" {
+			event = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\tgzcli %s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"), e.User, event, outcome, duration, strings.Join(e.Args, " "))
+		if e.Error != "" {
+			_, _ = fmt.Fprintf(w, "\t\t\t\t\t  error: %s\n", firstLine(e.Error))
+		}
+	}
+	_ = w.Flush()
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyUser, "user", "", "Only commands run by this user")
+	historyCmd.Flags().StringVar(&historyCommand, "command", "", "Only this command and its subcommands (e.g. sync, team)")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only commands started within this window (e.g. 2h, 7d)")
+	historyCmd.Flags().BoolVar(&historyFailed, "failed", false, "Only failed commands")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Show at most this many recent commands (0 shows all)")
+}
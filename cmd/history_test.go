@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAuditedCommandsExist guards the audit list against names that no
+// longer match a command, which would silently stop recording it
+func TestAuditedCommandsExist(t *testing.T) {
+	for name := range auditedCommands {
+		cmd, rest, err := rootCmd.Find(strings.Fields(name))
+		if err != nil || len(rest) != 0 || cmd == rootCmd {
+			t.Errorf("audited command %q does not exist", name)
+			continue
+		}
+		if got := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "); got != name {
+			t.Errorf("audited command %q resolves to %q", name, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/linkcheck"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	linksRemote        bool
+	linksConcurrency   int
+	linksTimeout       time.Duration
+	linksCacheTTL      time.Duration
+	linksEvents        []string
+	linksExcludeEvents []string
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Check the links of challenge descriptions",
+	Long: `Find the http(s) URLs in challenge descriptions and report, per challenge,
+the ones that do not answer with a 2xx status, catching broken handout and
+documentation links before players do.
+
+Local challenge.yml descriptions are always checked. With --remote, the
+descriptions of the challenges on the GZCTF server are checked too, which
+requires logging in.
+
+Each URL is checked once, with a HEAD request falling back to GET, by
+--concurrency parallel requests. Links that answered are cached in .gzcli/
+for --cache-ttl and not requested again; dead links are always rechecked.
+
+The command exits non-zero if any link is dead.`,
+	Example: `  # Check local descriptions of all events
+  gzcli links
+
+  # Also check the descriptions on the server, ignoring cached results
+  gzcli links --event ctf2024 --remote --cache-ttl 0`,
+	Run: func(_ *cobra.Command, _ []string) {
+		events, err := ResolveTargetEvents(linksEvents, linksExcludeEvents)
+		if err != nil {
+			log.Fatal("Failed to resolve target events: ", err)
+		}
+
+		var links []gzcli.ChallengeLinks
+		failed := false
+		for _, eventName := range events {
+			local, err := gzcli.LocalChallengeLinks(eventName)
+			if err != nil {
+				log.Error("[%s] %v", eventName, err)
+				failed = true
+				continue
+			}
+			links = append(links, local...)
+
+			if !linksRemote {
+				continue
+			}
+			gz, err := gzcli.InitWithEvent(eventName)
+			if err != nil {
+				log.Error("[%s] Initialization failed: %v", eventName, err)
+				failed = true
+				continue
+			}
+			remote, err := gz.RemoteChallengeLinks()
+			if err != nil {
+				log.Error("[%s] %v", eventName, err)
+				failed = true
+				continue
+			}
+			links = append(links, remote...)
+		}
+
+		total := 0
+		for _, l := range links {
+			total += len(l.URLs)
+		}
+		log.Info("Checking %d link(s) of %d challenge description(s)...", total, len(links))
+
+		checker := linkcheck.NewChecker()
+		checker.Client = &http.Client{Timeout: linksTimeout}
+		checker.Concurrency = linksConcurrency
+		checker.MaxAge = linksCacheTTL
+		dead := gzcli.CheckLinks(context.Background(), checker, links)
+
+		var last gzcli.ChallengeLinks
+		for _, d := range dead {
+			if d.Event != last.Event || d.Challenge != last.Challenge || d.Source != last.Source {
+				log.Error("[%s] %s (%s):", d.Event, d.Challenge, d.Source)
+				last = d.ChallengeLinks
+			}
+			fmt.Printf("    %s: %s\n", d.URL, d.Result)
+		}
+
+		if len(dead) > 0 {
+			log.Error("%d dead link(s) found", len(dead))
+		} else {
+			log.Info("✅ All %d link(s) answered", total)
+		}
+		if failed || len(dead) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linksCmd)
+
+	linksCmd.Flags().BoolVar(&linksRemote, "remote", false, "Also check the descriptions of the challenges on the server")
+	linksCmd.Flags().IntVar(&linksConcurrency, "concurrency", linkcheck.DefaultConcurrency, "Number of links checked at once")
+	linksCmd.Flags().DurationVar(&linksTimeout, "timeout", linkcheck.DefaultTimeout, "Timeout of each link check")
+	linksCmd.Flags().DurationVar(&linksCacheTTL, "cache-ttl", time.Hour, "Reuse results of links that answered within this duration (0 disables the cache)")
+	linksCmd.Flags().StringSliceVarP(&linksEvents, "event", "e", []string{}, "Specific event(s) to check (can be specified multiple times)")
+	linksCmd.Flags().StringSliceVar(&linksExcludeEvents, "exclude-event", []string{}, "Event(s) to exclude from checking (can be specified multiple times)")
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/loadtest"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	loadtestTeams int
+	loadtestOpts  = loadtest.DefaultOptions()
+	loadtestJSON  bool
+)
+
+var loadtestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Load-test the GZCTF deployment before an event",
+	Long: `Generate realistic load against the GZCTF server with test accounts to size
+the deployment before an event.
+
+Run load tests against a staging game, never a live event: submissions are
+recorded by the platform and count towards submission statistics.`,
+}
+
+var loadtestSubmissionsCmd = &cobra.Command{
+	Use:   "submissions",
+	Short: "Load-test flag submissions and scoreboard recomputation",
+	Long: `Submit flags as many teams at a fixed rate and measure latency percentiles
+and error rates of the submission endpoint, flag judgement and scoreboard.
+
+Test accounts are the team credentials created by "gzcli team create" for the
+event; their teams must have joined the game and the game must be running.
+Submissions use random wrong flags except for the --correct-ratio share,
+which uses the static flags from the local challenge.yml files so solves
+trigger scoreboard recomputation.
+
+The command exits with status 1 if any request failed.`,
+	Example: `  # 200 teams submitting 50 flags per second for one minute
+  gzcli loadtest submissions --teams 200 --rps 50
+
+  # Include 10% correct flags and export the report
+  gzcli loadtest submissions --teams 200 --rps 50 --duration 5m --correct-ratio 0.1 --json > report.json`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := loadtestOpts.Validate(); err != nil {
+			log.Fatal("Invalid options: ", err)
+		}
+
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		participants, challenges, err := gz.LoadTestTargets(loadtestTeams)
+		if err != nil {
+			log.Fatal("Failed to prepare load test: ", err)
+		}
+
+		log.Info("Submitting %.1f flags/s as %d teams to %d challenges for %s...",
+			loadtestOpts.RPS, len(participants), len(challenges), loadtestOpts.Duration)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		report, err := loadtest.Run(ctx, participants, challenges, loadtestOpts)
+		if err != nil {
+			log.Fatal("Load test failed: ", err)
+		}
+
+		if loadtestJSON || jsonOutput() {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				log.Fatal("JSON encoding failed: ", err)
+			}
+		} else {
+			report.Print(os.Stdout)
+		}
+
+		for _, op := range report.Operations {
+			if op.Errors > 0 {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loadtestCmd)
+	loadtestCmd.AddCommand(loadtestSubmissionsCmd)
+
+	flags := loadtestSubmissionsCmd.Flags()
+	flags.IntVar(&loadtestTeams, "teams", 200, "Number of test accounts to submit as")
+	flags.Float64Var(&loadtestOpts.RPS, "rps", loadtestOpts.RPS, "Target flag submissions per second across all teams")
+	flags.DurationVar(&loadtestOpts.Duration, "duration", loadtestOpts.Duration, "How long to submit flags")
+	flags.DurationVar(&loadtestOpts.ScoreboardInterval, "scoreboard-interval", loadtestOpts.ScoreboardInterval, "Interval between scoreboard reads (0 to disable)")
+	flags.Float64Var(&loadtestOpts.CorrectRatio, "correct-ratio", loadtestOpts.CorrectRatio, "Share of submissions using a correct flag (0-1)")
+	flags.DurationVar(&loadtestOpts.JudgeTimeout, "judge-timeout", loadtestOpts.JudgeTimeout, "How long to wait for each flag judgement (0 to skip polling)")
+	flags.IntVar(&loadtestOpts.MaxInFlight, "max-in-flight", loadtestOpts.MaxInFlight, "Outstanding submissions before new ones are skipped")
+	flags.BoolVar(&loadtestJSON, "json", false, "Print the report as JSON")
+}
@@ -47,7 +47,7 @@ The old structure will be preserved in a backup directory.`,
 	Run: func(_ *cobra.Command, _ []string) {
 		if err := runMigration(); err != nil {
 			log.Error("Migration failed: %v", err)
-			os.Exit(1)
+			exit(1)
 		}
 		log.Info("✅ Migration completed successfully!")
 	},
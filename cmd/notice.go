@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	noticeFile      string
+	noticeSchedule  string
+	noticeScheduled bool
+	noticeDatabase  string
+)
+
+var noticeCmd = &cobra.Command{
+	Use:   "notice",
+	Short: "Manage the notices of the event's game",
+	Long: `List, publish, edit and delete the notices shown to participants of the
+event's game, e.g. to script hint releases instead of using the web UI.
+
+Notices sent with --schedule are stored in the watcher database and posted
+at their time by the watcher of the event ('gzcli watch start' or
+'gzcli daemon'), which must be running then.`,
+}
+
+var noticeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the notices of the game",
+	Example: `  gzcli notice list --event ctf2024
+  gzcli notice list --output json
+
+  # Notices scheduled with 'notice send --schedule'
+  gzcli notice list --scheduled`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if noticeScheduled {
+			listScheduledNotices()
+			return
+		}
+		game := noticeGame()
+		notices, err := game.GetNotices()
+		if err != nil {
+			log.Fatal("Failed to list notices: ", err)
+		}
+		if notices == nil {
+			notices = []gzapi.GameNotice{}
+		}
+
+		printResult(notices, func() {
+			if len(notices) == 0 {
+				log.Info("No notices")
+				return
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "ID\tTIME\tTYPE\tCONTENT")
+			for _, n := range notices {
+				_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", n.Id, n.Time.Local().Format(time.DateTime), n.Type, firstLine(n.Content()))
+			}
+			_ = tw.Flush()
+		})
+	},
+}
+
+var noticeSendCmd = &cobra.Command{
+	Use:     "send [content]",
+	Aliases: []string{"create"},
+	Short:   "Publish a notice to the participants",
+	Example: `  # Release a hint
+  gzcli notice send "Hint for Baby ROP: look at the GOT"
+
+  # Publish a Markdown notice from a file
+  gzcli notice send --file hints/web-1.md
+
+  # Release a hint later, posted by the running watcher
+  gzcli notice send --file hints/web-2.md --schedule 2026-05-18T12:00:00Z
+  gzcli notice send "Second wave is out" --schedule 6h`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		content := noticeContent(args)
+		if noticeSchedule != "" {
+			scheduleNotice(content)
+			return
+		}
+		notice, err := noticeGame().CreateNotice(content)
+		if err != nil {
+			log.Fatal("Failed to publish notice: ", err)
+		}
+		printResult(notice, func() {
+			log.Info("📢 Published notice %d", notice.Id)
+		})
+	},
+}
+
+var noticeEditCmd = &cobra.Command{
+	Use:   "edit <id> [content]",
+	Short: "Replace the content of a notice",
+	Example: `  gzcli notice edit 12 "Hint for Baby ROP: look at the GOT entry of puts"
+  gzcli notice edit 12 --file hints/web-1.md`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: remoteNoticeIDs,
+	Run: func(_ *cobra.Command, args []string) {
+		id := noticeID(args[0])
+		content := noticeContent(args[1:])
+		notice, err := noticeGame().UpdateNotice(id, content)
+		if err != nil {
+			log.Fatal("Failed to edit notice: ", err)
+		}
+		printResult(notice, func() {
+			log.Info("✏️  Updated notice %d", notice.Id)
+		})
+	},
+}
+
+var noticeDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a notice",
+	Example: `  gzcli notice delete 12
+
+  # Cancel a scheduled notice that was not posted yet
+  gzcli notice delete --scheduled 3`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: remoteNoticeIDs,
+	Run: func(_ *cobra.Command, args []string) {
+		id := noticeID(args[0])
+		if noticeScheduled {
+			if err := noticeGZ().CancelScheduledNotice(noticeDB(), int64(id)); err != nil {
+				log.Fatal("Failed to cancel scheduled notice: ", err)
+			}
+			log.Info("🗑️  Canceled scheduled notice %d", id)
+			return
+		}
+		if err := noticeGame().DeleteNotice(id); err != nil {
+			log.Fatal("Failed to delete notice: ", err)
+		}
+		log.Info("🗑️  Deleted notice %d", id)
+	},
+}
+
+// noticeGZ initializes gzcli for the selected event
+func noticeGZ() *gzcli.GZ {
+	gz, err := gzcli.InitWithEvent(GetEventFlag())
+	if err != nil {
+		log.Fatal("Failed to initialize: ", err)
+	}
+	return gz
+}
+
+// noticeGame returns the game of the selected event
+func noticeGame() *gzapi.Game {
+	game, err := noticeGZ().EventGame()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return game
+}
+
+// noticeDB returns the watcher database of the scheduled notices: --db, or
+// the one the watcher uses by default
+func noticeDB() string {
+	if noticeDatabase != "" {
+		return noticeDatabase
+	}
+	return gzcli.WatcherDatabase()
+}
+
+// scheduleNotice stores a notice to be posted at the time of --schedule
+func scheduleNotice(content string) {
+	postAt, err := parseNoticeSchedule(noticeSchedule, time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+	notice, err := noticeGZ().ScheduleNotice(noticeDB(), content, postAt)
+	if err != nil {
+		log.Fatal("Failed to schedule notice: ", err)
+	}
+	printResult(notice, func() {
+		log.Info("⏰ Scheduled notice %d for %s", notice.ID, notice.PostAt.Local().Format(time.DateTime))
+		log.InfoH2("It is posted by the watcher of the event, which must be running then")
+	})
+}
+
+// parseNoticeSchedule parses --schedule: a time in one of the formats of
+// --start of 'event create', or a delay from now such as 30m or 2d. The time
+// must be in the future.
+func parseNoticeSchedule(s string, now time.Time) (time.Time, error) {
+	postAt, err := parseEventTime(s)
+	if err != nil {
+		delay, durationErr := parseEventDuration(s)
+		if durationErr != nil {
+			return time.Time{}, fmt.Errorf("invalid --schedule %q (try 2026-05-18T08:30:00Z or a delay such as 2h)", s)
+		}
+		postAt = now.Add(delay).UTC()
+	}
+	if !postAt.After(now) {
+		return time.Time{}, fmt.Errorf("--schedule %s is not in the future", postAt.Local().Format(time.DateTime))
+	}
+	return postAt, nil
+}
+
+// listScheduledNotices prints the scheduled notices of the selected event
+func listScheduledNotices() {
+	notices, err := noticeGZ().ScheduledNotices(noticeDB())
+	if err != nil {
+		log.Fatal("Failed to list scheduled notices: ", err)
+	}
+
+	printResult(notices, func() {
+		if len(notices) == 0 {
+			log.Info("No scheduled notices")
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tPOST AT\tSTATUS\tCONTENT")
+		for _, n := range notices {
+			status := "pending"
+			switch {
+			case n.SentAt != nil:
+				status = fmt.Sprintf("sent (notice %d)", n.NoticeID)
+			case n.FailedAt != nil:
+				status = "failed: " + n.Error
+			case n.Error != "":
+				status = fmt.Sprintf("retrying (%d/%d): %s", n.Attempts, watchertypes.MaxNoticeAttempts, n.Error)
+			}
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", n.ID, n.PostAt.Local().Format(time.DateTime), status, firstLine(n.Content))
+		}
+		_ = tw.Flush()
+	})
+}
+
+// noticeContent returns the content of a notice from the argument or --file
+func noticeContent(args []string) string {
+	var content string
+	switch {
+	case noticeFile != "" && len(args) > 0:
+		log.Fatal("Give the notice content as an argument or with --file, not both")
+	case noticeFile != "":
+		//nolint:gosec // G304: File chosen by the user
+		data, err := os.ReadFile(noticeFile)
+		if err != nil {
+			log.Fatal("Failed to read notice: ", err)
+		}
+		content = string(data)
+	case len(args) > 0:
+		content = args[0]
+	}
+	if strings.TrimSpace(content) == "" {
+		log.Fatal("The notice content is empty")
+	}
+	return strings.TrimSpace(content)
+}
+
+func noticeID(arg string) int {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		log.Fatal("Invalid notice ID: ", arg)
+	}
+	return id
+}
+
+func init() {
+	rootCmd.AddCommand(noticeCmd)
+	noticeCmd.AddCommand(noticeListCmd, noticeSendCmd, noticeEditCmd, noticeDeleteCmd)
+
+	for _, c := range []*cobra.Command{noticeSendCmd, noticeEditCmd} {
+		c.Flags().StringVarP(&noticeFile, "file", "f", "", "Read the notice content from a file")
+	}
+	noticeSendCmd.Flags().StringVar(&noticeSchedule, "schedule", "", "Post the notice at this time (e.g. 2026-05-18T12:00:00Z) or after this delay (e.g. 2h) through the watcher")
+	noticeListCmd.Flags().BoolVar(&noticeScheduled, "scheduled", false, "List the scheduled notices instead of the game's")
+	noticeDeleteCmd.Flags().BoolVar(&noticeScheduled, "scheduled", false, "Cancel a scheduled notice instead of deleting a posted one")
+	for _, c := range []*cobra.Command{noticeSendCmd, noticeListCmd, noticeDeleteCmd} {
+		c.Flags().StringVar(&noticeDatabase, "db", "", "Watcher database of scheduled notices, a file or postgres:// URL (default "+gzcli.DefaultWatcherConfig.DatabasePath+")")
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Formats of the global --output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormat is the value of the global --output flag
+var outputFormat = outputText
+
+// validateOutputFormat checks the --output flag and, for JSON, moves log
+// lines to stderr so stdout only carries the result
+func validateOutputFormat() error {
+	switch outputFormat {
+	case outputText:
+	case outputJSON:
+		log.SetInfoOutput(os.Stderr)
+	default:
+		return fmt.Errorf("invalid --output %q: must be %s or %s", outputFormat, outputText, outputJSON)
+	}
+	return nil
+}
+
+// jsonOutput reports whether commands print their results as JSON
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// writeJSON prints v as indented JSON on stdout
+func writeJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Fatal("JSON encoding failed: ", err)
+	}
+}
+
+// printResult prints the result of a command: as JSON with --output json,
+// otherwise by calling text
+func printResult(result any, text func()) {
+	if jsonOutput() {
+		writeJSON(result)
+		return
+	}
+	text()
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// captureStdout returns what fn prints on stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestPrintResult(t *testing.T) {
+	defer func() {
+		outputFormat = outputText
+		log.SetInfoOutput(nil)
+	}()
+
+	result := syncResult{Events: []syncEventResult{{Event: "ctf", Success: true}}, Succeeded: 1}
+	textCalled := false
+
+	outputFormat = outputText
+	if err := validateOutputFormat(); err != nil {
+		t.Fatalf("validateOutputFormat(text) error = %v", err)
+	}
+	if out := captureStdout(t, func() { printResult(result, func() { textCalled = true }) }); out != "" || !textCalled {
+		t.Errorf("text output: printed %q, text called %v; want text callback only", out, textCalled)
+	}
+
+	outputFormat = outputJSON
+	if err := validateOutputFormat(); err != nil {
+		t.Fatalf("validateOutputFormat(json) error = %v", err)
+	}
+	textCalled = false
+	out := captureStdout(t, func() {
+		log.Info("progress goes to stderr")
+		printResult(result, func() { textCalled = true })
+	})
+	var got syncResult
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("JSON output %q does not parse: %v", out, err)
+	}
+	if textCalled || got.Succeeded != 1 || len(got.Events) != 1 || got.Events[0].Event != "ctf" {
+		t.Errorf("JSON output = %+v (text called %v), want the result only", got, textCalled)
+	}
+
+	outputFormat = "yaml"
+	if err := validateOutputFormat(); err == nil {
+		t.Error("validateOutputFormat(yaml) succeeded, want an error")
+	}
+}
+
+// TestNoLocalOutputFlag guards the global --output flag against commands
+// shadowing it with a flag of their own
+func TestNoLocalOutputFlag(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if cmd != rootCmd && cmd.LocalNonPersistentFlags().Lookup("output") != nil {
+			t.Errorf("%s defines its own --output flag", cmd.CommandPath())
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
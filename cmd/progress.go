@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+const (
+	// progressMinSize is the smallest upload worth a progress bar
+	progressMinSize = 1 << 20
+
+	progressBarWidth = 30
+)
+
+// uploadProgressBar renders the combined progress of the uploads in flight
+// on one terminal line
+type uploadProgressBar struct {
+	out     io.Writer
+	mu      sync.Mutex
+	uploads map[string][2]int64 // file -> sent, total
+	drawn   bool
+}
+
+// enableUploadProgress shows a progress bar for large uploads when stderr is
+// a terminal and the output is not machine-readable
+func enableUploadProgress() {
+	if jsonOutput() {
+		return
+	}
+	if info, err := os.Stderr.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	bar := &uploadProgressBar{out: os.Stderr, uploads: make(map[string][2]int64)}
+	gzapi.SetUploadProgress(bar.update)
+}
+
+func (b *uploadProgressBar) update(file string, sent, total int64) {
+	if total < progressMinSize {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sent >= total {
+		delete(b.uploads, file)
+	} else {
+		b.uploads[file] = [2]int64{sent, total}
+	}
+
+	if len(b.uploads) == 0 {
+		if b.drawn {
+			fmt.Fprintf(b.out, "\r%s\r", strings.Repeat(" ", progressBarWidth+60))
+			b.drawn = false
+		}
+		return
+	}
+
+	var allSent, allTotal int64
+	for _, upload := range b.uploads {
+		allSent += upload[0]
+		allTotal += upload[1]
+	}
+	fmt.Fprintf(b.out, "\r%s", formatProgress(len(b.uploads), allSent, allTotal))
+	b.drawn = true
+}
+
+// formatProgress renders a progress line such as
+// "Uploading 1 file [=====>    ] 52% 130.0/250.0 MiB"
+func formatProgress(files int, sent, total int64) string {
+	filled := int(sent * progressBarWidth / total)
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+
+	plural := "s"
+	if files == 1 {
+		plural = ""
+	}
+	return fmt.Sprintf("Uploading %d file%s [%s] %3d%% %.1f/%.1f MiB", files, plural, bar,
+		sent*100/total, float64(sent)/(1<<20), float64(total)/(1<<20))
+}
+
+// formatSyncProgress renders the progress of a watcher sync of all challenges
+// of an event, such as
+// "ctf2024 [=====>    ] 12/40 (1 failed) ETA 2m30s, syncing web/login"
+func formatSyncProgress(p watchertypes.SyncProgress) string {
+	filled := progressBarWidth
+	if p.Total > 0 {
+		filled = p.Done * progressBarWidth / p.Total
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+
+	line := fmt.Sprintf("%s [%s] %d/%d", p.Event, bar, p.Done, p.Total)
+	if p.Failed > 0 {
+		line += fmt.Sprintf(" (%d failed)", p.Failed)
+	}
+	if p.FinishedAt != nil {
+		return line + fmt.Sprintf(" done in %s", p.FinishedAt.Sub(p.StartedAt).Round(time.Second))
+	}
+	if p.ETASeconds >= 0 {
+		line += fmt.Sprintf(" ETA %s", time.Duration(p.ETASeconds)*time.Second)
+	} else {
+		line += " ETA unknown"
+	}
+	switch len(p.Current) {
+	case 0:
+	case 1:
+		line += ", syncing " + p.Current[0]
+	default:
+		line += fmt.Sprintf(", syncing %s and %d more", p.Current[0], len(p.Current)-1)
+	}
+	return line
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestFormatProgress(t *testing.T) {
+	got := formatProgress(1, 5<<20, 10<<20)
+	want := "Uploading 1 file [===============>              ]  50% 5.0/10.0 MiB"
+	if got != want {
+		t.Errorf("formatProgress() = %q, want %q", got, want)
+	}
+	if got := formatProgress(2, 10<<20, 10<<20); !strings.Contains(got, "2 files ["+strings.Repeat("=", progressBarWidth)+"] 100%") {
+		t.Errorf("formatProgress(done) = %q", got)
+	}
+}
+
+func TestUploadProgressBar(t *testing.T) {
+	var out bytes.Buffer
+	bar := &uploadProgressBar{out: &out, uploads: make(map[string][2]int64)}
+
+	bar.update("small.txt", 10, 100)
+	if out.Len() != 0 {
+		t.Errorf("small upload drew a progress bar: %q", out.String())
+	}
+
+	bar.update("a.zip", 1<<20, 4<<20)
+	bar.update("b.zip", 1<<20, 4<<20)
+	if !strings.Contains(out.String(), "Uploading 2 files") || !strings.Contains(out.String(), "2.0/8.0 MiB") {
+		t.Errorf("progress = %q, want both uploads combined", out.String())
+	}
+
+	bar.update("a.zip", 4<<20, 4<<20)
+	bar.update("b.zip", 4<<20, 4<<20)
+	if len(bar.uploads) != 0 || bar.drawn {
+		t.Error("finished uploads were not cleared")
+	}
+}
+
+func TestFormatSyncProgress(t *testing.T) {
+	startedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := watchertypes.SyncProgress{
+		Event:      "ctf2024",
+		Total:      40,
+		Done:       10,
+		Failed:     1,
+		Current:    []string{"crypto/rsa", "web/login"},
+		StartedAt:  startedAt,
+		ETASeconds: 150,
+	}
+	want := "ctf2024 [=======>                      ] 10/40 (1 failed) ETA 2m30s, syncing crypto/rsa and 1 more"
+	if got := formatSyncProgress(p); got != want {
+		t.Errorf("formatSyncProgress() = %q, want %q", got, want)
+	}
+
+	p.Done, p.Failed, p.Current, p.ETASeconds = 0, 0, []string{"web/login"}, -1
+	if got := formatSyncProgress(p); !strings.HasSuffix(got, "0/40 ETA unknown, syncing web/login") {
+		t.Errorf("formatSyncProgress(started) = %q", got)
+	}
+
+	finishedAt := startedAt.Add(5 * time.Minute)
+	p.Done, p.Current, p.FinishedAt = 40, nil, &finishedAt
+	if got := formatSyncProgress(p); !strings.HasSuffix(got, "] 40/40 done in 5m0s") {
+		t.Errorf("formatSyncProgress(finished) = %q", got)
+	}
+}
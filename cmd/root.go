@@ -60,6 +60,12 @@ Features:
   # Generate CTFTime scoreboard
   gzcli scoreboard > scoreboard.json`,
 	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		if err := validateOutputFormat(); err != nil {
+			log.Fatal(err)
+		}
+		enableUploadProgress()
+		startAudit(cmd)
+
 		// Enable debug mode if flag is set
 		if debug, _ := cmd.Flags().GetBool("debug"); debug {
 			log.SetDebugMode(true)
@@ -72,6 +78,7 @@ Features:
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	finishAudit(err)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -89,8 +96,13 @@ func init() {
 	// Add global event selection flag
 	rootCmd.PersistentFlags().StringVarP(&globalEventFlag, "event", "e", "", "Specify which event to use (overrides GZCLI_EVENT env var)")
 
+	// Add global output format flag
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "Output format: text or json (JSON results on stdout, logs on stderr)")
+
 	// Register completion for global --event flag
 	_ = rootCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	_ = rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{outputText, outputJSON}, cobra.ShellCompDirectiveNoFileComp))
 }
 
 // GetEventFlag returns the current event flag value
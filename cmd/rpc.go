@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/rpc"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	rpcAddr           string
+	rpcToken          string
+	rpcWatcherSocket  string
+	rpcLauncherSocket string
+)
+
+var rpcCmd = &cobra.Command{
+	Use:   "rpc",
+	Short: "Start a local JSON-RPC API for embedding gzcli in other tools",
+	Long: `Start a JSON-RPC 2.0 server over HTTP exposing core gzcli operations so
+organizer tooling (Discord bots, dashboards) can call gzcli without shelling
+out and parsing log output.
+
+Methods:
+  events.list                              List events in the workspace
+  challenges.sync   {event, challenge?}    Sync an event or a single challenge
+  watcher.status                           Watcher daemon status
+  instances.list                           Launcher instances and their status
+  instances.start   {slug}                 Start a launcher instance
+
+Requests are POSTed as application/json to the server root. Binding to a non-loopback address
+requires a token, sent as "Authorization: Bearer <token>".`,
+	Example: `  # Start on the default address
+  gzcli rpc
+
+  # Call a method
+  curl -s localhost:7373 -H 'Content-Type: application/json' \
+    -d '{"jsonrpc":"2.0","id":1,"method":"events.list"}'
+
+  # Require a token
+  GZCLI_RPC_TOKEN=secret gzcli rpc --addr 0.0.0.0:7373`,
+	Run: func(_ *cobra.Command, _ []string) {
+		token := rpcToken
+		if token == "" {
+			token = os.Getenv("GZCLI_RPC_TOKEN")
+		}
+		if token == "" && !rpc.IsLoopbackAddr(rpcAddr) {
+			log.Fatal("Refusing to expose the RPC API on a non-loopback address without --token or GZCLI_RPC_TOKEN")
+		}
+
+		backend := rpc.NewDefaultBackend(rpcWatcherSocket, rpcLauncherSocket)
+		rpcServer := rpc.NewServer(backend, token)
+
+		srv := &http.Server{
+			Addr:              rpcAddr,
+			Handler:           rpcServer,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		serverErrors := make(chan error, 1)
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErrors <- err
+			}
+		}()
+
+		log.Info("JSON-RPC API listening on http://%s", rpcAddr)
+		log.InfoH2("Methods: %v", rpcServer.Methods())
+
+		shutdown := make(chan os.Signal, 1)
+		signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+
+		select {
+		case err := <-serverErrors:
+			log.Fatal("RPC server error: ", err)
+		case <-shutdown:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Error("RPC server shutdown failed: %v", err)
+			}
+			log.Info("RPC server stopped")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rpcCmd)
+
+	rpcCmd.Flags().StringVar(&rpcAddr, "addr", "127.0.0.1:7373", "Address to listen on")
+	rpcCmd.Flags().StringVar(&rpcToken, "token", "", "Bearer token required by clients (default: $GZCLI_RPC_TOKEN)")
+	rpcCmd.Flags().StringVar(&rpcWatcherSocket, "watcher-socket", "", "Watcher daemon socket path")
+	rpcCmd.Flags().StringVar(&rpcLauncherSocket, "launcher-socket", "", "Challenge launcher socket path")
+}
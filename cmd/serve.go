@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/server"
@@ -8,13 +10,22 @@ import (
 )
 
 var (
-	serveHost string
-	servePort int
+	serveHost       string
+	servePort       int
+	serveSocketPath string
+	serveProbeHost  string
+	serveAPIToken   string
+	serveTeamIso    bool
+	serveTeamTokens string
+	serveStateFile  string
+	serveKeepInst   bool
+	serveProxy      bool
 )
 
 var serveCmd = &cobra.Command{
-	Use:   "serve",
-	Short: "Start the challenge launcher web server",
+	Use:     "serve",
+	Aliases: []string{"server"},
+	Short:   "Start the challenge launcher web server",
 	Long: `Start an HTTP/WebSocket server for managing challenge launchers.
 
 The server provides a web interface to start, stop, and restart challenges
@@ -28,9 +39,57 @@ with dashboard configuration. Features include:
   • Rate limiting per IP
   • Health monitoring
   • Browser notifications
+  • Automatic cleanup of instances whose challenge was removed (via watcher)
+  • Port reachability self-test (TCP and UDP) shown on the challenge page
 
 The server discovers all challenges with dashboard configuration across
-all events and makes them accessible via secret URLs based on their slugs.`,
+all events and makes them accessible via secret URLs based on their slugs.
+
+After a challenge starts, every allocated port is probed from --probe-host
+(default: the launcher host itself). Set it to the public address players
+connect to so firewall and NAT problems show up as unreachable ports.
+
+With --api-token (or $GZCLI_LAUNCHER_API_TOKEN), a JSON REST API lets
+scripts and CI pipelines manage instances. Requests must send the token as
+"Authorization: Bearer <token>":
+
+  GET  /api/challenges                  List challenges and their status
+  GET  /api/challenges/{slug}/status    Status of one challenge
+  POST /api/challenges/{slug}/start     Start an instance
+  POST /api/challenges/{slug}/stop      Stop an instance
+  POST /api/challenges/{slug}/restart   Restart an instance, skipping the vote
+
+With --team-isolation, every team gets its own instance of each challenge
+with its own Compose project, container and ports. Players open the
+challenge URL with ?token=<team token> and only see their team's instance.
+It requires --team-tokens <file>, a YAML file mapping team names to
+tokens; only those tokens are accepted. Team names must stay distinct once
+lowercased and reduced to letters, digits and hyphens, which name the
+instances. API calls address a team instance with ?team=<team>.
+
+Instance state (status, ports, last restart, connected users) is kept in
+--state-file. On startup it is reconciled with the running containers:
+instances still up are taken over with their ports and stopped as usual
+once nobody connects, and restart cooldowns carry over. With
+--keep-instances, shutting down leaves running instances up so a restarted
+launcher takes them over instead of restarting them. Instance sessions and
+votes are recorded there too, for "gzcli serve report".
+
+With --proxy, the launcher also reverse-proxies HTTP requests to running
+instances, for players who cannot reach randomized host ports:
+/c/<slug>/... reaches the first TCP port of the instance and
+/c/<slug>:<port>/... the one mapped to that container port. WebSocket
+upgrades are proxied too, so only the launcher's port needs to be exposed.
+The challenge page links the proxied URLs. Team instances are selected by
+the ?token= of the first request, remembered in a cookie.
+
+Proxied challenges are served from the launcher's own origin. Their
+responses carry a "Content-Security-Policy: sandbox" header so their scripts
+cannot reach the launcher's pages, storage or API, and the cookies they set
+are scoped to their proxy path. Sandboxed pages have no cookies or storage
+of their own in the browser, so challenges relying on document.cookie,
+localStorage or credentialed fetch() must be reached on their host ports.
+Only enable --proxy for challenges you trust not to attack the launcher.`,
 	Example: `  # Start server on default localhost:8080
   gzcli serve
 
@@ -38,11 +97,40 @@ all events and makes them accessible via secret URLs based on their slugs.`,
   gzcli serve --host 0.0.0.0 --port 3000
 
   # Start server with short flags
-  gzcli serve -H 0.0.0.0 -p 3000`,
+  gzcli serve -H 0.0.0.0 -p 3000
+
+  # Self-test challenge ports through the public address
+  gzcli serve -H 0.0.0.0 --probe-host ctf.example.com
+
+  # Enable the REST API and restart a challenge from a script
+  gzcli serve --api-token "$TOKEN"
+  curl -X POST -H "Authorization: Bearer $TOKEN" http://localhost:8080/api/challenges/<slug>/restart
+
+  # Give every team its own instances, authenticated by a token list
+  gzcli serve -H 0.0.0.0 --team-tokens teams.yaml
+
+  # Keep instances running across launcher restarts
+  gzcli serve --keep-instances
+
+  # Only expose the launcher's port, proxying HTTP challenges through it
+  gzcli serve -H 0.0.0.0 --proxy`,
 	Run: func(_ *cobra.Command, _ []string) {
 		log.Info("Starting GZCLI Challenge Launcher Server...")
 
-		if err := server.RunServer(serveHost, servePort); err != nil {
+		server.SetProbeHost(serveProbeHost)
+		server.SetStatePath(serveStateFile)
+		server.SetKeepInstances(serveKeepInst)
+		server.SetProxyMode(serveProxy)
+		token := serveAPIToken
+		if token == "" {
+			token = os.Getenv(server.APITokenEnv)
+		}
+		server.SetAPIToken(token)
+		if err := server.ConfigureTeamIsolation(serveTeamIso, serveTeamTokens); err != nil {
+			log.Fatal("Failed to configure team isolation: ", err)
+		}
+
+		if err := server.RunServer(serveHost, servePort, serveSocketPath); err != nil {
 			log.Error("Server error: %v", err)
 		}
 	},
@@ -54,4 +142,12 @@ func init() {
 	// Flags
 	serveCmd.Flags().StringVarP(&serveHost, "host", "H", "localhost", "Host to bind the server to")
 	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to bind the server to")
+	serveCmd.Flags().StringVar(&serveSocketPath, "socket", server.DefaultSocketPath, "Unix socket for watcher notifications (empty to disable)")
+	serveCmd.Flags().StringVar(&serveProbeHost, "probe-host", "", "Address used to self-test allocated challenge ports (default 127.0.0.1)")
+	serveCmd.Flags().StringVar(&serveAPIToken, "api-token", "", "Bearer token enabling the REST API under /api/challenges (default: $GZCLI_LAUNCHER_API_TOKEN)")
+	serveCmd.Flags().BoolVar(&serveTeamIso, "team-isolation", false, "Give each team its own instance of every challenge (requires --team-tokens)")
+	serveCmd.Flags().StringVar(&serveTeamTokens, "team-tokens", "", "YAML file mapping team names to tokens (implies --team-isolation)")
+	serveCmd.Flags().StringVar(&serveStateFile, "state-file", server.DefaultStatePath, "File persisting instance state across restarts (empty to disable)")
+	serveCmd.Flags().BoolVar(&serveKeepInst, "keep-instances", false, "Leave running instances up on shutdown to take them over on the next start")
+	serveCmd.Flags().BoolVar(&serveProxy, "proxy", false, "Reverse-proxy HTTP requests under /c/<slug>/ to running instances (served sandboxed from the launcher's origin)")
 }
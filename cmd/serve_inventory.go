@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/server"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	inventoryFormat     string
+	inventoryOutput     string
+	inventoryHost       string
+	inventorySocketPath string
+)
+
+var serveInventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Export running launcher instances for Terraform or Ansible",
+	Long: `Export the running instances of a launcher server (slugs, hosts, ports and
+container IDs) for infrastructure automation, e.g. to point an external load
+balancer at stable player-facing endpoints.
+
+Formats:
+  ansible     YAML/JSON inventory: every instance is a host in the
+              gzcli_launcher group and in gzcli_event_<event> and
+              gzcli_type_<type> groups, with gzcli_* host variables
+  terraform   Terraform v4 state: the "instances" output maps slugs to their
+              details, readable with the terraform_remote_state data source
+
+Hosts default to the launcher's --probe-host; use --host to set the address
+players connect to.`,
+	Example: `  # Ansible inventory
+  gzcli serve inventory --host ctf.example.com -o inventory.json
+  ansible-inventory -i inventory.json --list
+
+  # Terraform state for terraform_remote_state
+  gzcli serve inventory --format terraform -o launcher.tfstate`,
+	Run: func(_ *cobra.Command, _ []string) {
+		client := gzcli.NewWatcherClient(inventorySocketPath)
+		response, err := client.SendCommand(server.ActionInventory, map[string]interface{}{"host": inventoryHost})
+		if err != nil {
+			log.Fatal("Failed to communicate with launcher server: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Inventory request failed: ", response.Error)
+		}
+
+		instances, err := server.DecodeInventory(response.Data["instances"])
+		if err != nil {
+			log.Fatal("Failed to read inventory: ", err)
+		}
+
+		data, err := server.FormatInventory(inventoryFormat, instances)
+		if err != nil {
+			log.Fatal("Failed to format inventory: ", err)
+		}
+
+		if inventoryOutput == "" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(inventoryOutput, data, 0600); err != nil {
+			log.Fatal("Failed to write inventory: ", err)
+		}
+		log.Info("Inventory of %d running instance(s) written to %s", len(instances), inventoryOutput)
+	},
+}
+
+func init() {
+	serveCmd.AddCommand(serveInventoryCmd)
+
+	serveInventoryCmd.Flags().StringVar(&inventoryFormat, "format", server.InventoryFormatAnsible, "Output format: ansible or terraform")
+	serveInventoryCmd.Flags().StringVarP(&inventoryOutput, "out-file", "o", "", "Write to a file instead of stdout")
+	serveInventoryCmd.Flags().StringVar(&inventoryHost, "host", "", "Player-facing host of the instances (default: the launcher's probe host)")
+	serveInventoryCmd.Flags().StringVar(&inventorySocketPath, "socket", server.DefaultSocketPath, "Launcher server socket")
+
+	_ = serveInventoryCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		server.InventoryFormats(), cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/server"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	reportStateFile string
+	reportFormat    string
+	reportOutput    string
+	reportSince     time.Duration
+)
+
+var serveReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize launcher usage per challenge for capacity planning",
+	Long: `Summarize the instance sessions recorded by the launcher in its --state-file,
+per challenge: how many sessions ran and for how long, the most instances
+running at once, the unique IPs that connected, restarts, the peak CPU and
+memory usage sampled, and the outcomes of restart and extension votes.
+
+A session lasts from an instance starting to it stopping. Sessions still
+running count up to now, and one that ended while the launcher was down ends
+when the launcher started again. The report reads the state file directly,
+so it also works after the launcher was stopped.`,
+	Example: `  # Usage of the whole event
+  gzcli serve report
+
+  # Usage of the last two days as CSV
+  gzcli serve report --since 48h --format csv -o usage.csv`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if jsonOutput() && !cmd.Flags().Changed("format") {
+			reportFormat = server.UsageFormatJSON
+		}
+		if _, err := os.Stat(reportStateFile); err != nil {
+			log.Fatal("Failed to read launcher state: ", err)
+		}
+		store, err := server.OpenStateStore(reportStateFile)
+		if err != nil {
+			log.Fatal("Failed to open launcher state: ", err)
+		}
+		defer func() {
+			_ = store.Close()
+		}()
+
+		now := time.Now()
+		var since time.Time
+		if reportSince > 0 {
+			since = now.Add(-reportSince)
+		}
+		report, err := store.UsageReport(since, now)
+		if err != nil {
+			log.Fatal("Failed to build usage report: ", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if reportOutput != "" {
+			//nolint:gosec // G304: Output path is provided by the user
+			f, err := os.Create(reportOutput)
+			if err != nil {
+				log.Fatal("Failed to create output file: ", err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			out = f
+		}
+		if err := server.RenderUsageReport(out, reportFormat, report); err != nil {
+			log.Fatal("Failed to render usage report: ", err)
+		}
+		if reportOutput != "" {
+			log.Info("Usage report of %d challenge(s) written to %s", len(report), reportOutput)
+		}
+	},
+}
+
+func init() {
+	serveCmd.AddCommand(serveReportCmd)
+
+	serveReportCmd.Flags().StringVar(&reportStateFile, "state-file", server.DefaultStatePath, "State file of the launcher server")
+	serveReportCmd.Flags().StringVar(&reportFormat, "format", server.UsageFormatText, "Output format: text, json or csv")
+	serveReportCmd.Flags().StringVarP(&reportOutput, "out-file", "o", "", "Write to a file instead of stdout")
+	serveReportCmd.Flags().DurationVar(&reportSince, "since", 0, "Only report sessions running and votes ended within this duration (default: all)")
+
+	_ = serveReportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(server.UsageFormats(), cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/services"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var servicesCmd = &cobra.Command{
+	Use:     "services",
+	Aliases: []string{"svc"},
+	Short:   "Manage shared services of an event",
+	Long: `Manage infrastructure shared by several challenges of an event, such as
+a common bot checker or database.
+
+Each directory in events/<event>/services/ containing a docker-compose.yml
+(or compose.yml) is a shared service, run as the Compose project
+gzcli-svc-<event>-<name>. Challenges declare the services they need in
+challenge.yml:
+
+  services:
+    - bot
+    - postgres
+
+References are checked at sync and shown by "gzcli doctor". Challenges can
+reach a service on its project network, gzcli-svc-<event>-<name>_default.`,
+	Example: `  # Start all shared services of the current event
+  gzcli services up
+
+  # Start one service of another event
+  gzcli services up bot --event ctf2024
+
+  # Show service status and the challenges depending on them
+  gzcli services status
+
+  # Stop all shared services
+  gzcli services down`,
+}
+
+var servicesUpCmd = &cobra.Command{
+	Use:               "up [service...]",
+	Short:             "Start shared services",
+	ValidArgsFunction: validServiceNames,
+	Run: func(_ *cobra.Command, args []string) {
+		runServices(args, "Starting", services.Up)
+	},
+}
+
+var servicesDownCmd = &cobra.Command{
+	Use:               "down [service...]",
+	Short:             "Stop shared services",
+	ValidArgsFunction: validServiceNames,
+	Run: func(_ *cobra.Command, args []string) {
+		runServices(args, "Stopping", services.Down)
+	},
+}
+
+var servicesStatusCmd = &cobra.Command{
+	Use:               "status [service...]",
+	Short:             "Show the status of shared services",
+	ValidArgsFunction: validServiceNames,
+	Run: func(_ *cobra.Command, args []string) {
+		eventName, svcs := selectServices(args)
+		if len(svcs) == 0 {
+			log.Info("No shared services in event %s", eventName)
+			return
+		}
+
+		// Dependents are best effort: the status is still useful when a
+		// challenge.yml fails to parse
+		challenges, _ := gzcli.LoadEventChallenges(eventName)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "SERVICE\tSTATUS\tPROJECT\tUSED BY")
+		for _, svc := range svcs {
+			status, err := services.GetStatus(context.Background(), svc)
+			state := status.String()
+			if err != nil {
+				state = "unknown"
+			}
+			usedBy := strings.Join(services.Dependents(challenges, svc.Name), ", ")
+			if usedBy == "" {
+				usedBy = "-"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", svc.Name, state, svc.Project(), usedBy)
+		}
+		_ = w.Flush()
+	},
+}
+
+// selectServices resolves the current event and the named services of it,
+// or all of them when no names are given
+func selectServices(names []string) (string, []services.Service) {
+	eventName, err := config.GetCurrentEvent(GetEventFlag())
+	if err != nil {
+		log.Fatal("Failed to determine event: ", err)
+	}
+	svcs, err := services.DiscoverEvent(eventName)
+	if err != nil {
+		log.Fatal("Failed to discover shared services: ", err)
+	}
+	svcs, err = services.Select(svcs, names)
+	if err != nil {
+		log.Fatal("Invalid service: ", err)
+	}
+	return eventName, svcs
+}
+
+// runServices applies a lifecycle action to the selected services, exiting
+// non-zero if any of them fails
+func runServices(names []string, verb string, action func(context.Context, services.Service) error) {
+	eventName, svcs := selectServices(names)
+	if len(svcs) == 0 {
+		log.Info("No shared services in event %s", eventName)
+		return
+	}
+
+	failed := 0
+	for _, svc := range svcs {
+		log.InfoH2("%s %s (%s)", verb, svc.Name, svc.Project())
+		if err := action(context.Background(), svc); err != nil {
+			log.ErrorH2("%s: %v", svc.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Error("%d of %d service(s) failed", failed, len(svcs))
+		exit(1)
+	}
+	log.Info("✅ %d service(s) done", len(svcs))
+}
+
+func validServiceNames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	eventName, err := config.GetCurrentEvent(GetEventFlag())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	svcs, err := services.DiscoverEvent(eventName)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(svcs))
+	for _, svc := range svcs {
+		names = append(names, svc.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(servicesCmd)
+	servicesCmd.AddCommand(servicesUpCmd, servicesDownCmd, servicesStatusCmd)
+}
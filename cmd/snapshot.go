@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	snapshotFile       string
+	snapshotSocketPath string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export and import the full state of an event",
+	Long: `Copy an event between GZCTF servers and workspaces as a portable archive
+(.tar.gz): the configuration of its game and challenges on GZCTF with their
+flags, the .gzevent and challenge files of the event, the IDs of the remote
+challenges its challenge folders are synced to, and a manifest of the
+challenge attachments.
+
+Importing recreates the game on the server and records the challenge folders
+as synced to the new challenges, so the watcher and "gzcli sync" update them
+instead of creating duplicates. This promotes an event from staging to
+production, or recovers it after losing a server.`,
+}
+
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a snapshot of the event to an archive",
+	Long: `Write a snapshot of the event to an archive, <event>-snapshot.tar.gz by
+default. The archive holds flags; keep it private.
+
+Local attachment files are listed but not included: they are uploaded from
+the challenge folders by the first sync after importing.`,
+	Example: `  # Snapshot the current event
+  gzcli snapshot export
+
+  # Snapshot another event to a given file
+  gzcli snapshot export --event ctf2024 --file backups/ctf2024.tar.gz`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		snapshot, err := gz.ExportSnapshot()
+		if err != nil {
+			log.Fatal("Failed to export snapshot: ", err)
+		}
+		file := snapshotFile
+		if file == "" {
+			file = fmt.Sprintf("%s-snapshot.tar.gz", snapshot.Manifest.Event)
+		}
+		if err := gzcli.WriteSnapshot(file, snapshot); err != nil {
+			log.Fatal("Failed to write snapshot: ", err)
+		}
+
+		result := map[string]any{
+			"file":        file,
+			"event":       snapshot.Manifest.Event,
+			"challenges":  len(snapshot.Game.Challenges),
+			"files":       len(snapshot.Manifest.Files),
+			"mappings":    len(snapshot.Manifest.Mappings),
+			"attachments": len(snapshot.Manifest.Attachments),
+		}
+		printResult(result, func() {
+			log.Info("Exported %q with %d challenge(s), %d file(s) and %d mapping(s) to %s",
+				snapshot.Game.Game.Title, len(snapshot.Game.Challenges), len(snapshot.Manifest.Files), len(snapshot.Manifest.Mappings), file)
+		})
+	},
+}
+
+var snapshotImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Recreate an event from a snapshot",
+	Long: `Recreate an event from an archive written by "gzcli snapshot export".
+
+The event files of the snapshot are written to events/<event>, where <event>
+is --event or the event of the snapshot; files that already exist are kept.
+The game is then created on the server with its challenges, flags and
+attachments; a game with the same title must not exist. Finally, the
+challenge folders of the event are mapped to the IDs of the new challenges,
+through the running watcher or straight into its database.
+
+Local attachments missing on the server are reported and uploaded by the next
+"gzcli sync" of their challenge.`,
+	Example: `  # Promote an event from staging to the production server
+  gzcli snapshot import ctf2024-snapshot.tar.gz
+
+  # Restore the snapshot as another event of the workspace
+  gzcli snapshot import --event ctf2024-rehearsal ctf2024-snapshot.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		snapshot, err := gzcli.ReadSnapshot(args[0])
+		if err != nil {
+			log.Fatal("Failed to read snapshot: ", err)
+		}
+		eventName := GetEventFlag()
+		if eventName == "" {
+			eventName = snapshot.Manifest.Event
+		}
+
+		written, kept, err := snapshot.RestoreFiles(eventName)
+		if err != nil {
+			log.Fatal("Failed to restore event files: ", err)
+		}
+		if len(kept) > 0 {
+			log.Info("Kept %d existing file(s) of event %s: %s", len(kept), eventName, strings.Join(kept, ", "))
+		}
+
+		gz, err := gzcli.InitWithEvent(eventName)
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+		result, importErr := gz.ImportSnapshot(snapshot)
+		if result == nil {
+			log.Fatal("Failed to import snapshot: ", importErr)
+		}
+		if len(result.Mappings) > 0 {
+			if err := recordMappings(snapshotSocketPath, eventName, result.GameID, result.Mappings); err != nil {
+				log.Fatal("Failed to record challenge mappings: ", err)
+			}
+		}
+
+		printResult(result, func() {
+			log.Info("Restored %d file(s) of event %s", len(written), eventName)
+			log.Info("Created game %q (ID %d) with %d challenge(s) and %d flag(s)", result.Title, result.GameID, result.Challenges, result.Flags)
+			log.Info("Mapped %d challenge folder(s) to the new challenges", len(result.Mappings))
+			for _, folder := range result.Unmapped {
+				log.Error("Warning: %s is not mapped, its challenge was not imported", folder)
+			}
+			for _, warning := range result.Warnings {
+				log.Error("Warning: %s", warning)
+			}
+		})
+		if importErr != nil {
+			log.Fatal("Some challenges failed to import: ", importErr)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotExportCmd)
+	snapshotCmd.AddCommand(snapshotImportCmd)
+
+	snapshotExportCmd.Flags().StringVarP(&snapshotFile, "file", "f", "", "Archive to write (default: <event>-snapshot.tar.gz)")
+	snapshotImportCmd.Flags().StringVar(&snapshotSocketPath, "socket", "", "Custom socket file location")
+}
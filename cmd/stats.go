@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/stats"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	statsFormat        string
+	statsFile          string
+	statsNoSubmissions bool
+	statsTags          []string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show solve and submission analytics of an event",
+	Long: `Show analytics of the event's game from its scoreboard: the ranking, solve
+counts, submissions, accuracy and first blood of each challenge, how hard each
+category was, and how the top teams ranked over the course of the game.
+
+The markdown format can be pasted in Discord or a post-event report; csv
+writes one table per section, separated by empty lines.
+
+--tag reports only the challenges whose challenge.yml carries one of the
+given tags; the ranking still covers the whole game.`,
+	Example: `  # Print analytics in the terminal
+  gzcli stats
+
+  # Write a Markdown report of ctf2024
+  gzcli stats --event ctf2024 --format markdown --file report.md
+
+  # Solves of the beginner challenges only
+  gzcli stats --tag beginner
+
+  # Skip fetching submissions on large events
+  gzcli stats --no-submissions --format csv`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if jsonOutput() && !cmd.Flags().Changed("format") {
+			statsFormat = stats.FormatJSON
+		}
+
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		report, err := gz.Stats(!statsNoSubmissions, statsTags)
+		if err != nil {
+			log.Fatal("Failed to compute stats: ", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if statsFile != "" {
+			//nolint:gosec // G304: Output path is provided by the user
+			f, err := os.Create(statsFile)
+			if err != nil {
+				log.Fatal("Failed to create output file: ", err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			out = f
+		}
+
+		if err := report.Render(out, statsFormat); err != nil {
+			log.Fatal("Failed to render stats: ", err)
+		}
+		if statsFile != "" {
+			log.Info("Stats written to %s", statsFile)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsFormat, "format", stats.FormatText, "Output format: text, json, csv or markdown")
+	statsCmd.Flags().StringVarP(&statsFile, "file", "f", "", "Write to a file instead of stdout")
+	statsCmd.Flags().BoolVar(&statsNoSubmissions, "no-submissions", false, "Skip fetching submissions (no attempt counts or accuracy)")
+	statsCmd.Flags().StringSliceVar(&statsTags, "tag", []string{}, "Only report challenges with one of these tags (can be specified multiple times)")
+
+	_ = statsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{stats.FormatText, stats.FormatJSON, stats.FormatCSV, stats.FormatMarkdown}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
+	"github.com/dimasma0305/gzcli/internal/gzcli/structure"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
 var (
 	structureEvents        []string
 	structureExcludeEvents []string
+	structureSolver        string
 )
 
 var structureCmd = &cobra.Command{
@@ -18,7 +23,15 @@ var structureCmd = &cobra.Command{
 	Long: `Generate directory structure for each challenge folder based on .structure template file.
 
 This command reads the .structure file in the challenge directory and creates
-the specified directory structure and placeholder files.
+the specified directory structure and placeholder files. Challenges are generated
+in parallel; a failing challenge does not stop the others, and a table lists which
+challenges were generated, skipped (every file already exists) or failed.
+
+Challenges that set "solver: python|go|bash" in challenge.yml also get a solver
+skeleton in solver/ unless one already exists. Use --solver to pick a language
+for challenges that don't set one. Solvers read the target from the HOST and
+PORT environment variables, a flag regular expression from FLAG_FORMAT, and
+print the flag to stdout.
 
 By default, generates structures for all events. Use --event to specify specific events,
 or --exclude-event to exclude certain events.`,
@@ -29,8 +42,15 @@ or --exclude-event to exclude certain events.`,
   gzcli structure --event ctf2024 --event ctf2025
 
   # Generate structure for all except practice event
-  gzcli structure --exclude-event practice`,
+  gzcli structure --exclude-event practice
+
+  # Scaffold pwntools solvers for challenges without a solver language
+  gzcli structure --solver python`,
 	Run: func(_ *cobra.Command, _ []string) {
+		if structureSolver != "" && !solver.IsLanguage(structureSolver) {
+			log.Fatal("Invalid --solver: ", structureSolver)
+		}
+
 		// Resolve which events to generate structure for
 		events, err := ResolveTargetEvents(structureEvents, structureExcludeEvents)
 		if err != nil {
@@ -57,10 +77,19 @@ or --exclude-event to exclude certain events.`,
 				continue
 			}
 
-			if err := gz.GenerateStructure(); err != nil {
+			report, err := gz.GenerateStructure(structureSolver)
+			if err != nil {
 				log.Error("[%s] Failed to generate structure: %v", eventName, err)
 				failureCount++
 				failedEvents = append(failedEvents, eventName)
+				continue
+			}
+
+			_ = report.Render(os.Stdout)
+			if failed := report.Count(structure.StatusFailed); failed > 0 {
+				log.Error("[%s] %d challenge structure(s) failed to generate", eventName, failed)
+				failureCount++
+				failedEvents = append(failedEvents, eventName)
 			} else {
 				log.Info("[%s] Challenge structures generated successfully", eventName)
 				successCount++
@@ -81,4 +110,7 @@ func init() {
 
 	structureCmd.Flags().StringSliceVarP(&structureEvents, "event", "e", []string{}, "Specific event(s) to generate structure for (can be specified multiple times)")
 	structureCmd.Flags().StringSliceVar(&structureExcludeEvents, "exclude-event", []string{}, "Event(s) to exclude from structure generation (can be specified multiple times)")
+	structureCmd.Flags().StringVar(&structureSolver, "solver", "", "Solver language for challenges without one in challenge.yml (python, go, bash)")
+
+	_ = structureCmd.RegisterFlagCompletionFunc("solver", cobra.FixedCompletions(solver.Languages(), cobra.ShellCompDirectiveNoFileComp))
 }
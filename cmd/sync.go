@@ -1,18 +1,37 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
 var (
 	syncUpdateGame    bool
+	syncFormat        bool
+	syncConcurrency   int
+	syncRateLimit     float64
+	syncTags          []string
+	syncOnly          []string
+	syncCategories    []string
+	syncChangedSince  string
 	syncEvents        []string
 	syncExcludeEvents []string
+
+	syncPreflight            bool
+	syncPreflightMaxCalls    int
+	syncPreflightMaxUploadMB int
+	syncYes                  bool
+
+	syncPolicyExempt []string
+	syncPolicySkip   []string
+	syncForceUpload  bool
 )
 
 var syncCmd = &cobra.Command{
@@ -28,7 +47,45 @@ This command:
   - Syncs challenge visibility and scoring
 
 By default, syncs all events. Use --event to specify specific events,
-or --exclude-event to exclude certain events.`,
+or --exclude-event to exclude certain events.
+
+With --preflight, each event is checked before it is synced: games,
+challenges and existing assets are fetched concurrently (which also verifies
+the credentials have admin access), and the API calls and attachment upload
+bytes of the sync are estimated. If the estimate exceeds --preflight-max-calls
+or --preflight-max-upload-mb, the sync asks for confirmation unless --yes is
+given.
+
+--only, --category and --changed-since limit the sync to some challenges of
+each event: challenge directories given as <category>/<challenge>, whole
+categories, or the challenges with files changed since a git ref (committed,
+uncommitted or untracked). Combined filters must all match.
+
+With --format, the challenge.yml of every successfully synced challenge is
+normalized in place (see "gzcli fmt").
+
+Local attachments are checked against the attachment policy of the event's
+.gzevent defaults (maxAttachmentSizeMB, forbiddenExtensions, requireReadme)
+before anything is synced. For exceptional cases, --policy-exempt lifts the
+policy from a challenge and --policy-skip lifts one rule (size, extension or
+readme) from every challenge.
+
+The SHA-256 of each local attachment is cached once attached, and attachments
+whose hash is unchanged are not uploaded again. --force-upload attaches them
+regardless, e.g. after they were changed in the GZCTF web UI.
+
+Challenges are synced by a pool of --concurrency workers, and API requests
+are limited to --rate-limit per second per server. Either falls back to the
+sync section of .gzctf/conf.yaml:
+
+  sync:
+    concurrency: 8
+    rateLimit: 20
+    burst: 5
+
+Without either, GZCLI_SYNC_WORKERS or min(4, CPUs) workers are used and
+requests are not limited. Every challenge that failed is reported, not just
+the first.`,
 	Example: `  # Sync all events
   gzcli sync
 
@@ -39,23 +96,49 @@ or --exclude-event to exclude certain events.`,
   gzcli sync --exclude-event practice
 
   # Sync and update game configuration
-  gzcli sync --update-game`,
+  gzcli sync --update-game
+
+  # Sync and normalize the challenge.yml files that synced
+  gzcli sync --format
+
+  # Sync only the challenges tagged beginner or warmup
+  gzcli sync --tag beginner --tag warmup
+
+  # Push just your challenge, or a category
+  gzcli sync --event ctf2024 --only web/my-chall
+  gzcli sync --event ctf2024 --category pwn
+
+  # Sync the challenges touched since the main branch
+  gzcli sync --changed-since origin/main
+
+  # Sync large events gently
+  gzcli sync --concurrency 2 --rate-limit 5
+
+  # Estimate the sync first and confirm large ones
+  gzcli sync --preflight --preflight-max-upload-mb 50
+
+  # Allow one challenge to ship an oversized attachment
+  gzcli sync --policy-exempt "Big Forensics" --policy-skip readme
+
+  # Attach every local attachment again
+  gzcli sync --force-upload`,
 	Run: func(_ *cobra.Command, _ []string) {
 		// Resolve which events to sync
 		events, err := ResolveTargetEvents(syncEvents, syncExcludeEvents)
 		if err != nil {
 			log.Error("Failed to resolve target events: %v", err)
-			os.Exit(1)
+			exit(1)
+		}
+		policy := challenge.PolicyOverrides{Challenges: syncPolicyExempt, Rules: syncPolicySkip}
+		if err := policy.Validate(); err != nil {
+			log.Fatal("Invalid policy override: ", err)
 		}
 
-		// Track results
-		successCount := 0
-		failureCount := 0
-		type failedEvent struct {
-			name string
-			err  error
+		result := syncResult{Events: make([]syncEventResult, 0, len(events))}
+		fail := func(eventName string, err error) {
+			result.Failed++
+			result.Events = append(result.Events, syncEventResult{Event: eventName, Error: err.Error()})
 		}
-		var failedEvents []failedEvent
 
 		log.Info("Syncing %d event(s): %v", len(events), events)
 
@@ -66,42 +149,144 @@ or --exclude-event to exclude certain events.`,
 			gz, err := gzcli.InitWithEvent(eventName)
 			if err != nil {
 				log.Error("[%s] Failed to initialize: %v", eventName, err)
-				failureCount++
-				failedEvents = append(failedEvents, failedEvent{name: eventName, err: err})
+				fail(eventName, err)
 				continue
 			}
 
 			gz.UpdateGame = syncUpdateGame
+			gz.FormatYaml = syncFormat
+			gz.Concurrency = syncConcurrency
+			gz.RateLimit = syncRateLimit
+			gz.Tags = syncTags
+			gz.Filter = gzcli.SyncFilter{Only: syncOnly, Categories: syncCategories, ChangedSince: syncChangedSince}
+			gz.Policy = policy
+			gz.ForceUpload = syncForceUpload
+			if syncPreflight {
+				if err := runSyncPreflight(gz, eventName); err != nil {
+					log.Error("[%s] Preflight failed: %v", eventName, err)
+					fail(eventName, err)
+					continue
+				}
+			}
+
 			if err := gz.Sync(); err != nil {
 				log.Error("[%s] Sync failed: %v", eventName, err)
-				failureCount++
-				failedEvents = append(failedEvents, failedEvent{name: eventName, err: err})
+				fail(eventName, err)
 			} else {
 				log.Info("[%s] Sync completed successfully", eventName)
-				successCount++
+				result.Succeeded++
+				result.Events = append(result.Events, syncEventResult{Event: eventName, Success: true})
 			}
 		}
 
 		// Display summary
-		log.InfoH2("Sync Summary: %d succeeded, %d failed", successCount, failureCount)
-		if failureCount > 0 {
+		printResult(result, func() {
+			log.InfoH2("Sync Summary: %d succeeded, %d failed", result.Succeeded, result.Failed)
+			if result.Failed == 0 {
+				return
+			}
 			log.Error("Failed events:")
-			for _, fe := range failedEvents {
-				log.Error("  - %s: %v", fe.name, fe.err)
+			for _, e := range result.Events {
+				if !e.Success {
+					log.Error("  - %s: %s", e.Event, e.Error)
+				}
 			}
 			log.Error("\nPlease check:")
 			log.Error("  1. Event directories exist in events/")
-			log.Error("  2. Each event has a valid .gzevent configuration file")
+			log.Error("  2. Each event has a valid .gzevent configuration file (see 'gzcli config validate')")
 			log.Error("  3. Server is accessible and credentials are correct")
-			os.Exit(1)
+		})
+		if result.Failed > 0 {
+			exit(1)
 		}
 	},
 }
 
+// syncResult is the outcome of "gzcli sync"
+type syncResult struct {
+	Events    []syncEventResult `json:"events"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// syncEventResult is the outcome of syncing one event
+type syncEventResult struct {
+	Event   string `json:"event"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// runSyncPreflight prints the preflight estimate for an event and asks for
+// confirmation when it exceeds the configured thresholds
+func runSyncPreflight(gz *gzcli.GZ, eventName string) error {
+	result, err := gz.Preflight()
+	if err != nil {
+		return err
+	}
+	if result.GameMissing {
+		log.Info("[%s] Game does not exist on the server yet and will be created", eventName)
+	}
+
+	est := result.Estimate
+	out := os.Stdout
+	if jsonOutput() {
+		out = os.Stderr
+	}
+	est.Print(out)
+	result.Policy.Print(out)
+	if err := result.Policy.Err(); err != nil {
+		return err
+	}
+
+	var exceeded []string
+	if syncPreflightMaxCalls > 0 && est.APICalls > syncPreflightMaxCalls {
+		exceeded = append(exceeded, fmt.Sprintf("%d API calls (limit %d)", est.APICalls, syncPreflightMaxCalls))
+	}
+	if maxBytes := int64(syncPreflightMaxUploadMB) << 20; syncPreflightMaxUploadMB > 0 && est.UploadBytes > maxBytes {
+		exceeded = append(exceeded, fmt.Sprintf("%d MiB of uploads (limit %d MiB)", est.UploadBytes>>20, syncPreflightMaxUploadMB))
+	}
+	if len(exceeded) == 0 || syncYes {
+		return nil
+	}
+	if jsonOutput() {
+		return fmt.Errorf("sync needs %v (use --yes to proceed without confirmation)", exceeded)
+	}
+
+	proceed := false
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("[%s] Sync needs %v. Continue?", eventName, exceeded),
+	}
+	if err := survey.AskOne(prompt, &proceed); err != nil {
+		return fmt.Errorf("preflight thresholds exceeded and confirmation failed (use --yes to skip): %w", err)
+	}
+	if !proceed {
+		return fmt.Errorf("sync aborted at preflight")
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
 
 	syncCmd.Flags().BoolVar(&syncUpdateGame, "update-game", false, "Update game configuration during sync")
+	syncCmd.Flags().BoolVar(&syncFormat, "format", false, "Normalize the challenge.yml of each successfully synced challenge")
+	syncCmd.Flags().IntVar(&syncConcurrency, "concurrency", 0, "Number of challenges synced in parallel (0 uses sync.concurrency of .gzctf/conf.yaml)")
+	syncCmd.Flags().Float64Var(&syncRateLimit, "rate-limit", 0, "Maximum API requests per second to the server (0 uses sync.rateLimit of .gzctf/conf.yaml)")
+	syncCmd.Flags().StringSliceVar(&syncTags, "tag", []string{}, "Only sync challenges with one of these tags (can be specified multiple times)")
+	syncCmd.Flags().StringSliceVar(&syncOnly, "only", []string{}, "Only sync this challenge, as <category>/<challenge> directory or name (can be specified multiple times)")
+	syncCmd.Flags().StringSliceVar(&syncCategories, "category", []string{}, "Only sync challenges of this category (can be specified multiple times)")
+	syncCmd.Flags().StringVar(&syncChangedSince, "changed-since", "", "Only sync challenges with files changed since this git ref, committed or not")
 	syncCmd.Flags().StringSliceVarP(&syncEvents, "event", "e", []string{}, "Specific event(s) to sync (can be specified multiple times)")
 	syncCmd.Flags().StringSliceVar(&syncExcludeEvents, "exclude-event", []string{}, "Event(s) to exclude from sync (can be specified multiple times)")
+	syncCmd.Flags().BoolVar(&syncPreflight, "preflight", false, "Prefetch server state and estimate API calls and uploads before syncing")
+	syncCmd.Flags().IntVar(&syncPreflightMaxCalls, "preflight-max-calls", 500, "Ask for confirmation when the preflight estimates more API calls than this (0 disables)")
+	syncCmd.Flags().IntVar(&syncPreflightMaxUploadMB, "preflight-max-upload-mb", 100, "Ask for confirmation when the preflight estimates more MiB of uploads than this (0 disables)")
+	syncCmd.Flags().StringSliceVar(&syncPolicyExempt, "policy-exempt", []string{}, "Exempt a challenge from the attachment policy (can be specified multiple times)")
+	syncCmd.Flags().StringSliceVar(&syncPolicySkip, "policy-skip", []string{}, "Skip an attachment policy rule for all challenges: size, extension or readme (can be specified multiple times)")
+	syncCmd.Flags().BoolVar(&syncForceUpload, "force-upload", false, "Attach local attachments even when their hash is unchanged since the last upload")
+	syncCmd.Flags().BoolVarP(&syncYes, "yes", "y", false, "Do not ask for confirmation when preflight thresholds are exceeded")
+
+	_ = syncCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	_ = syncCmd.RegisterFlagCompletionFunc("only", validChallengeFolders)
+	_ = syncCmd.RegisterFlagCompletionFunc("category", validCategories)
 }
@@ -12,7 +12,9 @@ var teamCmd = &cobra.Command{
   - Creating teams from CSV files
   - Sending registration emails
   - Registering teams to games
-  - Deleting teams and users`,
+  - Inspecting encrypted team credentials
+  - Deleting teams and users
+  - Pruning teams that left the CSV`,
 	Example: `  # Create teams from CSV
   gzcli team create teams.csv
 
@@ -22,6 +24,12 @@ var teamCmd = &cobra.Command{
   # Register teams to a game
   gzcli team register teams.csv --game "My CTF" --division "Open"
 
+  # Show cached team credentials
+  gzcli team creds show --decrypt
+
+  # Delete teams no longer in the CSV and recreate changed ones
+  gzcli team prune --csv teams.csv --dry-run
+
   # Delete all teams and users
   gzcli team delete --all`,
 }
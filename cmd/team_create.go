@@ -14,6 +14,7 @@ var (
 	createForceInitMapping  bool
 	createCommunicationType string
 	createCommunicationLink string
+	createResendFailed      bool
 )
 
 var teamCreateCmd = &cobra.Command{
@@ -26,7 +27,20 @@ The CSV file should have the following format:
 
 Example:
   John Doe,john@example.com,TeamAlpha
-  Jane Smith,jane@example.com,TeamBeta`,
+  Jane Smith,jane@example.com,TeamBeta
+
+An optional division column places each team in a division (organization) of
+the games it joins, for separate rankings such as students and professionals.
+Missing divisions are added to the game, and teams that joined before are
+moved when their division in the CSV changes. Run with --force-init-mapping
+to map the column after an earlier import.
+
+Emails are sent over the SMTP server of appsettings.json, or the provider set
+in the email section of .gzctf/conf.yaml (SendGrid, Mailgun or Amazon SES),
+with its branding or HTML template. Failed deliveries are recorded with the
+cached credentials; --resend-failed sends those again, without a CSV file.
+Only failures reported while sending are recorded: emails accepted and bounced
+later by the server or provider are not retried.`,
 	Example: `  # Create teams from CSV
   gzcli team create teams.csv
 
@@ -34,10 +48,17 @@ Example:
   gzcli team create teams.csv --send-email
 
   # Create teams into specific event
-  gzcli team create teams.csv --event-id 1 --invite-code "secret"`,
-	Args: cobra.ExactArgs(1),
+  gzcli team create teams.csv --event-id 1 --invite-code "secret"
+
+  # Retry the emails that failed to be delivered
+  gzcli team create --resend-failed`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if createResendFailed {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(_ *cobra.Command, args []string) {
-		csvFile := args[0]
 		// Use event from flag if provided
 		gz, err := gzcli.InitWithEvent(GetEventFlag())
 		if err != nil {
@@ -45,6 +66,27 @@ Example:
 			return
 		}
 
+		if createResendFailed {
+			sent, failed, err := gz.ResendFailedEmails()
+			if err != nil {
+				log.Fatal(err)
+			}
+			result := map[string]int{"sent": sent, "failed": failed}
+			printResult(result, func() {
+				if sent+failed == 0 {
+					log.Info("No failed emails to resend")
+					return
+				}
+				log.Info("Resent %d email(s), %d failed again", sent, failed)
+			})
+			if failed > 0 {
+				exit(1)
+			}
+			return
+		}
+
+		csvFile := args[0]
+
 		if err := gz.CreateTeams(csvFile, createSendEmail, createEventID, createInviteCode, createForceInitMapping, createCommunicationType, createCommunicationLink); err != nil {
 			log.Fatal(err)
 		}
@@ -63,4 +105,7 @@ func init() {
 	teamCreateCmd.Flags().BoolVar(&createForceInitMapping, "force-init-mapping", false, "Force initialization of column mapping")
 	teamCreateCmd.Flags().StringVar(&createCommunicationType, "communication-type", "", "Global communication type for all team emails (e.g. Discord, WhatsApp)")
 	teamCreateCmd.Flags().StringVar(&createCommunicationLink, "communication-link", "", "Global communication link for all team emails")
+	teamCreateCmd.Flags().BoolVar(&createResendFailed, "resend-failed", false, "Only send the emails whose delivery failed before again (later bounces are not known)")
+
+	_ = teamCreateCmd.RegisterFlagCompletionFunc("event-id", remoteGameIDs)
 }
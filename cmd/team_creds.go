@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/team"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	credsDecrypt bool
+	credsFormat  string
+)
+
+var teamCredsCmd = &cobra.Command{
+	Use:   "creds",
+	Short: "Inspect cached team credentials",
+	Long: `Inspect the team credentials generated by "gzcli team create".
+
+Credentials are cached encrypted and scoped to the current event and GZCTF
+server. The key is stored in the user config directory, or can be provided
+with the GZCLI_CREDS_KEY environment variable. Passwords are only decrypted
+when --decrypt is passed explicitly.
+
+show and export read the local cache only and never contact the server.`,
+}
+
+var teamCredsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show cached team credentials",
+	Example: `  # List teams with passwords masked
+  gzcli team creds show
+
+  # Reveal passwords
+  gzcli team creds show --decrypt --event ctf2024`,
+	Run: func(_ *cobra.Command, _ []string) {
+		creds := loadTeamCreds()
+		for _, c := range creds {
+			password := maskPassword(c.Password)
+			if credsDecrypt {
+				password = c.Password
+			}
+			fmt.Printf("%-30s %-20s %-35s %s\n", c.TeamName, c.Username, c.Email, password)
+		}
+		log.Info("%d credential(s) cached", len(creds))
+	},
+}
+
+var teamCredsExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export decrypted team credentials",
+	Example: `  # Export credentials to CSV
+  gzcli team creds export creds.csv --decrypt
+
+  # Export credentials to JSON
+  gzcli team creds export creds.json --decrypt --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if !credsDecrypt {
+			log.Fatal("Exporting writes plaintext passwords; pass --decrypt to confirm")
+		}
+
+		creds := loadTeamCreds()
+		data, err := encodeTeamCreds(creds, credsFormat)
+		if err != nil {
+			log.Fatal("Failed to encode credentials: ", err)
+		}
+
+		if err := os.WriteFile(args[0], data, 0600); err != nil {
+			log.Fatal("Failed to write credentials: ", err)
+		}
+		log.Info("Exported %d credential(s) to %s", len(creds), args[0])
+	},
+}
+
+func loadTeamCreds() []*team.TeamCreds {
+	creds, err := gzcli.TeamsCreds(GetEventFlag())
+	if err != nil {
+		log.Fatal("Failed to load team credentials: ", err)
+	}
+	return creds
+}
+
+func maskPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+	return strings.Repeat("*", 8)
+}
+
+func encodeTeamCreds(creds []*team.TeamCreds, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		return json.MarshalIndent(creds, "", "  ")
+	case "csv":
+		var sb strings.Builder
+		w := csv.NewWriter(&sb)
+		_ = w.Write([]string{"team_name", "username", "email", "password"})
+		for _, c := range creds {
+			_ = w.Write([]string{c.TeamName, c.Username, c.Email, c.Password})
+		}
+		w.Flush()
+		return []byte(sb.String()), w.Error()
+	default:
+		return nil, fmt.Errorf("unsupported format %q (use csv or json)", format)
+	}
+}
+
+func init() {
+	teamCmd.AddCommand(teamCredsCmd)
+	teamCredsCmd.AddCommand(teamCredsShowCmd)
+	teamCredsCmd.AddCommand(teamCredsExportCmd)
+
+	teamCredsCmd.PersistentFlags().BoolVar(&credsDecrypt, "decrypt", false, "Decrypt and reveal passwords")
+	teamCredsExportCmd.Flags().StringVar(&credsFormat, "format", "csv", "Export format (csv, json)")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/team"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	pruneCSV       string
+	pruneDryRun    bool
+	pruneSendEmail bool
+)
+
+// teamPruneResult is the --output json result of team prune
+type teamPruneResult struct {
+	DryRun    bool     `json:"dry_run"`
+	Removed   []string `json:"removed"`
+	Recreated []string `json:"recreated"`
+	Kept      int      `json:"kept"`
+	Missing   []string `json:"missing"`
+}
+
+var teamPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete teams no longer in a CSV and recreate changed ones",
+	Long: `Diff a CSV file against the accounts created from it by "gzcli team create".
+
+Accounts whose email is no longer in the CSV are deleted together with their
+team. Accounts whose real name or team name changed are deleted and created
+again with new credentials (use --send-email to mail them). Rows without an
+account are only reported; "gzcli team create" creates them.
+
+Teams and users not created by "gzcli team create" are never touched. The CSV
+column mapping chosen when the teams were created is reused.`,
+	Example: `  # Preview which accounts would be deleted or recreated
+  gzcli team prune --csv teams.csv --dry-run
+
+  # Apply the changes and mail the new credentials
+  gzcli team prune --csv https://example.com/teams.csv --send-email`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if pruneCSV == "" {
+			log.Error("Please specify the CSV with --csv")
+			_ = cmd.Help()
+			return
+		}
+
+		gz, err := gzcli.InitWithEvent(GetEventFlag())
+		if err != nil {
+			log.Fatal("Failed to initialize: ", err)
+		}
+
+		plan, err := gz.PruneTeams(pruneCSV, pruneDryRun, pruneSendEmail)
+		if err != nil {
+			log.Fatal("Team prune failed: ", err)
+		}
+
+		result := teamPruneResult{DryRun: pruneDryRun, Removed: []string{}, Recreated: []string{}, Kept: len(plan.Keep), Missing: []string{}}
+		for _, creds := range plan.Remove {
+			result.Removed = append(result.Removed, creds.Email)
+		}
+		for _, change := range plan.Recreate {
+			result.Recreated = append(result.Recreated, change.Row.Email)
+		}
+		for _, row := range plan.Missing {
+			result.Missing = append(result.Missing, row.Email)
+		}
+		printResult(result, func() { printPrunePlan(plan, pruneDryRun) })
+	},
+}
+
+// printPrunePlan prints the accounts a prune deleted or recreated
+func printPrunePlan(plan *team.PrunePlan, dryRun bool) {
+	removeVerb, recreateVerb := "Deleted", "Recreated"
+	if dryRun {
+		removeVerb, recreateVerb = "Would delete", "Would recreate"
+	}
+
+	for _, creds := range plan.Remove {
+		fmt.Printf("%s %s (team %s, user %s)\n", removeVerb, creds.Email, creds.TeamName, creds.Username)
+	}
+	for _, change := range plan.Recreate {
+		fmt.Printf("%s %s (team %s → %s, name %s)\n", recreateVerb, change.Row.Email, change.Current.TeamName, change.Row.TeamName, change.Row.RealName)
+	}
+	for _, row := range plan.Missing {
+		fmt.Printf("Not created yet: %s (run 'gzcli team create')\n", row.Email)
+	}
+	log.Info("%d to delete, %d to recreate, %d unchanged", len(plan.Remove), len(plan.Recreate), len(plan.Keep))
+}
+
+func init() {
+	teamCmd.AddCommand(teamPruneCmd)
+
+	teamPruneCmd.Flags().StringVar(&pruneCSV, "csv", "", "CSV file or URL listing the teams to keep")
+	teamPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show the accounts that would be deleted or recreated without changing anything")
+	teamPruneCmd.Flags().BoolVar(&pruneSendEmail, "send-email", false, "Send registration emails to recreated accounts")
+}
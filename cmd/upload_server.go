@@ -8,28 +8,75 @@ import (
 )
 
 var (
-	uploadServerHost  string
-	uploadServerPort  int
-	uploadServerEvent string
+	uploadServerHost    string
+	uploadServerPort    int
+	uploadServerEvent   string
+	uploadServerWorkers int
+
+	uploadServerReviewWebhook string
+	uploadServerReviewEmails  []string
+	uploadServerSMTPAddr      string
+	uploadServerSMTPFrom      string
+	uploadServerPublicURL     string
+
+	uploadServerSocketPath     string
+	uploadServerAllowAnonymous bool
 )
 
 var uploadServerCmd = &cobra.Command{
-	Use:   "upload-server",
-	Short: "Start the challenge upload web server",
+	Use:     "upload-server",
+	Aliases: []string{"uploadserver"},
+	Short:   "Start the challenge upload web server",
 	Long: `Start an HTTP server dedicated to uploading challenge packages.
 
 The upload server lets contributors download the challenge template ZIP and
-submit completed challenge archives that comply with the gzcli structure.`,
+submit completed challenge archives that comply with the gzcli structure.
+
+The required directories and naming convention can be customized per event
+and category with the "upload" block of the event's .gzevent.
+
+Uploads are processed in the background: the upload endpoint answers with a
+job ID right away, and GET /jobs/{id} reports the job's progress and any
+validation error (GET /jobs/{id}/events streams it as server-sent events).
+
+Uploads require an author token created with "gzcli upload-server token
+create", and installed challenges are recorded with their author in
+.gzcli/uploadserver/manifest.yaml. Until a token exists, uploads are refused,
+unless --allow-anonymous opens them to anyone reaching the server.
+
+Each installed challenge is put up for review. With --review-webhook or
+--review-email, reviewers are notified of the upload with its validation
+report and a link to approve or reject it; "gzcli upload-server review"
+lists and decides reviews from the command line. SMTP credentials are read
+from GZCLI_SMTP_USERNAME and GZCLI_SMTP_PASSWORD.
+
+Events setting "autoSync: true" in their "upload" block have each installed
+challenge synced to GZCTF right away through the running watcher ("gzcli
+watch start"); the job reports whether the sync succeeded.`,
 	Example: `  # Start server on default localhost:8090
   gzcli upload-server
 
   # Start server on custom host and port
-  gzcli upload-server --host 0.0.0.0 --port 4000`,
+  gzcli upload-server --host 0.0.0.0 --port 4000
+
+  # Email reviewers about new uploads
+  gzcli upload-server --host 0.0.0.0 --public-url https://upload.example.com \
+    --review-email reviewers@example.com --smtp-addr smtp.example.com:587 --smtp-from gzcli@example.com`,
 	Run: func(_ *cobra.Command, _ []string) {
 		opts := uploadserver.Options{
-			Host:  uploadServerHost,
-			Port:  uploadServerPort,
-			Event: uploadServerEvent,
+			Host:    uploadServerHost,
+			Port:    uploadServerPort,
+			Event:   uploadServerEvent,
+			Workers: uploadServerWorkers,
+
+			ReviewWebhook: uploadServerReviewWebhook,
+			ReviewEmails:  uploadServerReviewEmails,
+			SMTPAddr:      uploadServerSMTPAddr,
+			SMTPFrom:      uploadServerSMTPFrom,
+			PublicURL:     uploadServerPublicURL,
+
+			WatcherSocket:  uploadServerSocketPath,
+			AllowAnonymous: uploadServerAllowAnonymous,
 		}
 
 		log.Info("Starting GZCLI Challenge Upload Server...")
@@ -45,4 +92,12 @@ func init() {
 	uploadServerCmd.Flags().StringVarP(&uploadServerHost, "host", "H", "localhost", "Host to bind the upload server")
 	uploadServerCmd.Flags().IntVarP(&uploadServerPort, "port", "p", 8090, "Port to bind the upload server")
 	uploadServerCmd.Flags().StringVarP(&uploadServerEvent, "event", "e", "", "Restrict uploads to a specific event")
+	uploadServerCmd.Flags().IntVar(&uploadServerWorkers, "workers", 2, "Number of uploads processed concurrently")
+	uploadServerCmd.Flags().StringVar(&uploadServerReviewWebhook, "review-webhook", "", "Webhook URL notified of uploads to review")
+	uploadServerCmd.Flags().StringSliceVar(&uploadServerReviewEmails, "review-email", nil, "Reviewer email notified of uploads (repeatable)")
+	uploadServerCmd.Flags().StringVar(&uploadServerSMTPAddr, "smtp-addr", "", "SMTP server host:port sending review emails")
+	uploadServerCmd.Flags().StringVar(&uploadServerSMTPFrom, "smtp-from", "", "Sender address of review emails")
+	uploadServerCmd.Flags().StringVar(&uploadServerPublicURL, "public-url", "", "Base URL of review links (default http://host:port)")
+	uploadServerCmd.Flags().StringVar(&uploadServerSocketPath, "socket", "", "Watcher socket syncing uploads of events with autoSync")
+	uploadServerCmd.Flags().BoolVar(&uploadServerAllowAnonymous, "allow-anonymous", false, "Accept uploads without a token while no upload token exists")
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/uploadserver"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	reviewStatusFilter string
+	reviewNote         string
+)
+
+var uploadServerReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Query and decide the reviews of uploaded challenges",
+	Long: `Every challenge installed by the upload server is put up for review as
+pending, and goes back to pending when uploaded again. Reviews are named
+"event/category/directory" and stored in .gzcli/uploadserver/reviews.yaml.`,
+}
+
+var uploadServerReviewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the reviews of uploaded challenges",
+	Example: `  gzcli upload-server review list --status pending
+  gzcli upload-server review list --output json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		var status uploadserver.ReviewStatus
+		if reviewStatusFilter != "" {
+			var err error
+			if status, err = uploadserver.ParseReviewStatus(reviewStatusFilter); err != nil {
+				log.Fatal(err)
+			}
+		}
+		reviews, err := uploadserver.NewReviewStore("").List(status)
+		if err != nil {
+			log.Fatal("Failed to list reviews: ", err)
+		}
+		if reviews == nil {
+			reviews = []uploadserver.Review{}
+		}
+
+		printResult(reviews, func() {
+			if len(reviews) == 0 {
+				log.Info("No reviews")
+				return
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "ID\tCHALLENGE\tAUTHOR\tSTATUS\tISSUES\tUPLOADED")
+			for _, r := range reviews {
+				author := r.Author
+				if author == "" {
+					author = "-"
+				}
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", r.ID, r.Challenge, author, r.Status, len(r.Issues), r.UploadedAt.Local().Format(time.DateTime))
+			}
+			_ = tw.Flush()
+		})
+	},
+}
+
+var uploadServerReviewShowCmd = &cobra.Command{
+	Use:     "show <id>",
+	Short:   "Show the review and validation report of an upload",
+	Example: `  gzcli upload-server review show ctf2024/Web/my-challenge`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		review, err := uploadserver.NewReviewStore("").Get(args[0])
+		if err != nil {
+			log.Fatal("Failed to get review: ", err)
+		}
+		printResult(review, func() {
+			fmt.Printf("%s (%s)\n", review.Challenge, review.ID)
+			if review.Author != "" {
+				fmt.Printf("  Author:   %s\n", review.Author)
+			}
+			fmt.Printf("  Uploaded: %s (%d upload(s))\n", review.UploadedAt.Local().Format(time.DateTime), review.Uploads)
+			fmt.Printf("  Status:   %s\n", review.Status)
+			if review.Note != "" {
+				fmt.Printf("  Note:     %s\n", review.Note)
+			}
+			if len(review.Issues) == 0 {
+				fmt.Println("  No validation issues")
+			}
+			for _, issue := range review.Issues {
+				fmt.Printf("  %-7s %-11s %s\n", issue.Severity, issue.Check, issue.Message)
+			}
+		})
+	},
+}
+
+var uploadServerReviewApproveCmd = &cobra.Command{
+	Use:     "approve <id>",
+	Short:   "Approve an uploaded challenge",
+	Example: `  gzcli upload-server review approve ctf2024/Web/my-challenge`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		setReviewStatus(args[0], uploadserver.ReviewApproved)
+	},
+}
+
+var uploadServerReviewRejectCmd = &cobra.Command{
+	Use:     "reject <id>",
+	Short:   "Reject an uploaded challenge",
+	Example: `  gzcli upload-server review reject ctf2024/Web/my-challenge --note "The flag is in the attachment"`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		setReviewStatus(args[0], uploadserver.ReviewRejected)
+	},
+}
+
+func setReviewStatus(id string, status uploadserver.ReviewStatus) {
+	review, err := uploadserver.NewReviewStore("").SetStatus(id, status, reviewNote)
+	if err != nil {
+		log.Fatal("Failed to record review: ", err)
+	}
+	printResult(review, func() {
+		log.Info("✅ Review of %s set to %s", review.ID, review.Status)
+	})
+}
+
+func init() {
+	uploadServerCmd.AddCommand(uploadServerReviewCmd)
+	uploadServerReviewCmd.AddCommand(uploadServerReviewListCmd, uploadServerReviewShowCmd, uploadServerReviewApproveCmd, uploadServerReviewRejectCmd)
+
+	uploadServerReviewListCmd.Flags().StringVar(&reviewStatusFilter, "status", "", "Only list reviews in this state (pending, approved, rejected)")
+	for _, c := range []*cobra.Command{uploadServerReviewApproveCmd, uploadServerReviewRejectCmd} {
+		c.Flags().StringVar(&reviewNote, "note", "", "Note for the author")
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/uploadserver"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var uploadServerTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage the author tokens of the upload server",
+	Long: `Issue and revoke the per-author tokens required to upload challenges.
+
+Once a token exists, the upload server rejects uploads without a valid token,
+given as a bearer token in the Authorization header or in the token field of
+the upload page. Tokens are stored hashed in .gzcli/uploadserver/tokens.yaml
+and apply to a running server right away. Each installed challenge is
+recorded with the author of its token in .gzcli/uploadserver/manifest.yaml.`,
+}
+
+var uploadServerTokenCreateCmd = &cobra.Command{
+	Use:   "create <author>",
+	Short: "Issue an upload token to an author",
+	Example: `  gzcli upload-server token create alice
+
+  # Upload with the token
+  curl -H "Authorization: Bearer gzu_..." -H "Accept: application/json" \
+    -F event=ctf2024 -F category=Web -F challenge=@challenge.zip \
+    http://localhost:8090/upload`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		token, record, err := uploadserver.NewTokenStore("").Create(args[0])
+		if err != nil {
+			log.Fatal("Failed to create upload token: ", err)
+		}
+		result := struct {
+			uploadserver.AuthorToken
+			Token string `json:"token"`
+		}{record, token}
+		printResult(result, func() {
+			log.Info("🔑 Created upload token %s for %s; it is not shown again:", record.ID, record.Author)
+			fmt.Println(token)
+		})
+	},
+}
+
+var uploadServerTokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the issued upload tokens",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		tokens, err := uploadserver.NewTokenStore("").List()
+		if err != nil {
+			log.Fatal("Failed to list upload tokens: ", err)
+		}
+		if tokens == nil {
+			tokens = []uploadserver.AuthorToken{}
+		}
+
+		printResult(tokens, func() {
+			if len(tokens) == 0 {
+				log.Info("No upload tokens, uploads are open to anyone")
+				return
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "ID\tAUTHOR\tCREATED")
+			for _, t := range tokens {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", t.ID, t.Author, t.CreatedAt.Local().Format(time.DateTime))
+			}
+			_ = tw.Flush()
+		})
+	},
+}
+
+var uploadServerTokenRevokeCmd = &cobra.Command{
+	Use:     "revoke <id>",
+	Short:   "Revoke an upload token",
+	Example: `  gzcli upload-server token revoke 3fa94c1e`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		record, err := uploadserver.NewTokenStore("").Revoke(args[0])
+		if err != nil {
+			log.Fatal("Failed to revoke upload token: ", err)
+		}
+		log.Info("🗑️  Revoked upload token %s of %s", record.ID, record.Author)
+	},
+}
+
+func init() {
+	uploadServerCmd.AddCommand(uploadServerTokenCmd)
+	uploadServerTokenCmd.AddCommand(uploadServerTokenCreateCmd, uploadServerTokenListCmd, uploadServerTokenRevokeCmd)
+}
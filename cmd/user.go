@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	userSkip  int
+	userCount int
+	userAll   bool
+	userYes   bool
+)
+
+// userListResult is the --output json result of user list
+type userListResult struct {
+	Users []*gzapi.User `json:"users"`
+	Skip  int           `json:"skip"`
+	Total int           `json:"total"`
+}
+
+// userPasswordResult is the --output json result of user reset-password
+type userPasswordResult struct {
+	Id       string `json:"id"` //nolint:revive // Matches the user ID field
+	UserName string `json:"username"`
+	Password string `json:"password"`
+}
+
+var userCmd = &cobra.Command{
+	Use:     "user",
+	Aliases: []string{"u"},
+	Short:   "Manage the users of the server",
+	Long: `List, search and correct the user accounts of the GZCTF server: change
+their role, reset their password or delete a single account, without running
+SQL against the GZCTF database.
+
+Users are given by ID, username or email; usernames and emails must match
+exactly, ignoring case.`,
+	Example: `  # List the first 100 users
+  gzcli user list
+
+  # Find accounts by part of their name, email or student number
+  gzcli user search alice
+
+  # Make a user a monitor, or ban them
+  gzcli user role alice Monitor
+  gzcli user role cheater@example.com Banned
+
+  # Reset a password and delete a duplicate account
+  gzcli user reset-password alice
+  gzcli user delete alice2 --yes`,
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the users of the server",
+	Example: `  gzcli user list --skip 100 --count 50
+  gzcli user list --all --output json`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if userCount <= 0 || userSkip < 0 {
+			log.Fatal("--count must be positive and --skip not negative")
+		}
+		gz := userGZ()
+		result := userListResult{Skip: userSkip}
+		if userAll {
+			for skip := 0; ; skip += userCount {
+				page, total, err := gz.ListUsers(skip, userCount)
+				if err != nil {
+					log.Fatal("Failed to list users: ", err)
+				}
+				result.Users = append(result.Users, page...)
+				result.Total = total
+				if len(page) < userCount {
+					break
+				}
+			}
+			result.Skip = 0
+		} else {
+			users, total, err := gz.ListUsers(userSkip, userCount)
+			if err != nil {
+				log.Fatal("Failed to list users: ", err)
+			}
+			result.Users, result.Total = users, total
+		}
+		if result.Users == nil {
+			result.Users = []*gzapi.User{}
+		}
+
+		printResult(result, func() {
+			printUsers(result.Users)
+			if len(result.Users) > 0 {
+				log.Info("Users %d-%d of %d", result.Skip+1, result.Skip+len(result.Users), result.Total)
+			}
+		})
+	},
+}
+
+var userSearchCmd = &cobra.Command{
+	Use:     "search <hint>",
+	Short:   "Search users by name, email, real name, student number or ID",
+	Example: `  gzcli user search example.com`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		users, err := userGZ().SearchUsers(args[0])
+		if err != nil {
+			log.Fatal("Failed to search users: ", err)
+		}
+		if users == nil {
+			users = []*gzapi.User{}
+		}
+		printResult(users, func() { printUsers(users) })
+	},
+}
+
+var userRoleCmd = &cobra.Command{
+	Use:               "role <user> <role>",
+	Short:             "Change the role of a user (Banned, User, Monitor or Admin)",
+	Example:           `  gzcli user role alice Monitor`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: validUserRoleArgs,
+	Run: func(_ *cobra.Command, args []string) {
+		user, err := userGZ().SetUserRole(args[0], args[1])
+		if err != nil {
+			log.Fatal("Failed to change role: ", err)
+		}
+		printResult(user, func() {
+			log.Info("Changed the role of %s to %s", user.UserName, user.Role)
+		})
+	},
+}
+
+var userResetPasswordCmd = &cobra.Command{
+	Use:               "reset-password <user>",
+	Short:             "Replace the password of a user with a random one",
+	Example:           `  gzcli user reset-password alice@example.com`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: remoteUserNames,
+	Run: func(_ *cobra.Command, args []string) {
+		user, err := userGZ().FindUser(args[0])
+		if err != nil {
+			log.Fatal("Failed to find user: ", err)
+		}
+		password, err := user.ResetPassword()
+		if err != nil {
+			log.Fatal("Failed to reset password: ", err)
+		}
+		printResult(userPasswordResult{Id: user.Id, UserName: user.UserName, Password: password}, func() {
+			log.Info("New password of %s: %s", user.UserName, password)
+		})
+	},
+}
+
+var userDeleteCmd = &cobra.Command{
+	Use:               "delete <user>",
+	Short:             "Delete a single user",
+	Example:           `  gzcli user delete alice2 --yes`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: remoteUserNames,
+	Run: func(_ *cobra.Command, args []string) {
+		user, err := userGZ().FindUser(args[0])
+		if err != nil {
+			log.Fatal("Failed to find user: ", err)
+		}
+		if !userYes {
+			if jsonOutput() {
+				log.Fatal("Use --yes to delete a user with --output json")
+			}
+			proceed := false
+			prompt := &survey.Confirm{Message: fmt.Sprintf("Delete user %s (%s)?", user.UserName, user.Email)}
+			if err := survey.AskOne(prompt, &proceed); err != nil || !proceed {
+				log.Info("User deletion cancelled")
+				return
+			}
+		}
+		if err := user.Delete(); err != nil {
+			log.Fatal("Failed to delete user: ", err)
+		}
+		log.Info("🗑️  Deleted user %s", user.UserName)
+	},
+}
+
+// userGZ initializes the client of the server
+func userGZ() *gzcli.GZ {
+	gz, err := gzcli.InitWithEvent(GetEventFlag())
+	if err != nil {
+		log.Fatal("Failed to initialize: ", err)
+	}
+	return gz
+}
+
+// printUsers prints users as a table
+func printUsers(users []*gzapi.User) {
+	if len(users) == 0 {
+		log.Info("No users")
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tUSERNAME\tEMAIL\tROLE\tREAL NAME")
+	for _, u := range users {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", u.Id, u.UserName, u.Email, u.Role, u.RealName)
+	}
+	_ = tw.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(userListCmd, userSearchCmd, userRoleCmd, userResetPasswordCmd, userDeleteCmd)
+
+	userListCmd.Flags().IntVar(&userSkip, "skip", 0, "Number of users to skip")
+	userListCmd.Flags().IntVar(&userCount, "count", 100, "Number of users to list (page size with --all)")
+	userListCmd.Flags().BoolVar(&userAll, "all", false, "List every user, paging through them")
+	userDeleteCmd.Flags().BoolVarP(&userYes, "yes", "y", false, "Delete without confirmation")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	approveEvent      string
+	approveSocketPath string
+)
+
+var watchApproveCmd = &cobra.Command{
+	Use:   "approve <challenge>",
+	Short: "Run a sync held by the live lock",
+	Long: `Approve and run the sync of a challenge held by a watcher started with
+--live-lock.
+
+Between the start and end of the event's game (from its .gzevent), such a
+watcher only syncs metadata changes of challenge.yml. Attachment updates and
+full redeploys, including those of unhealthy challenges, are held until
+approved with this command. "gzcli watch status --challenge" shows the held
+update. Challenges are named "category/folder", as listed by
+"gzcli watch status --event".`,
+	Example: `  gzcli watch approve --event ctf2024 web/my-challenge`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if approveEvent == "" {
+			log.Fatal("Missing --event: choose the event of the challenge")
+		}
+
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if approveSocketPath != "" {
+			socketPath = approveSocketPath
+		}
+
+		client := gzcli.NewWatcherClient(socketPath)
+		response, err := client.ApproveSync(approveEvent, args[0])
+		if err != nil {
+			log.Fatal("Failed to communicate with watcher daemon: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Failed to approve sync: ", response.Error)
+		}
+		log.Info("✅ %s", response.Message)
+	},
+}
+
+func init() {
+	watchCmd.AddCommand(watchApproveCmd)
+
+	watchApproveCmd.Flags().StringVar(&approveEvent, "event", "", "Event of the challenge")
+	watchApproveCmd.Flags().StringVar(&approveSocketPath, "socket", "", "Custom socket file location")
+
+	// Register completion for --event flag
+	_ = watchApproveCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	watchApproveCmd.ValidArgsFunction = validChallengeFolder
+}
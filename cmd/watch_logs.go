@@ -1,50 +1,146 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/spf13/cobra"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
 var (
-	logsFile string
+	logsFile       string
+	logsFollow     bool
+	logsLevel      string
+	logsChallenge  string
+	logsLimit      int
+	logsSocketPath string
 )
 
 var watchLogsCmd = &cobra.Command{
 	Use:   "logs",
-	Short: "Follow and display watcher logs in real-time",
-	Long:  `Stream the file watcher daemon log file in real-time (like tail -f).`,
-	Example: `  # View logs
+	Short: "Show and follow watcher logs",
+	Long: `Show the latest entries of the watcher log recorded in the watcher database,
+oldest first, through the socket of the running watcher.
+
+With --follow, entries are then printed as the watcher writes them until
+Ctrl+C. --level keeps entries at least as severe as INFO, WARN or ERROR, and
+--challenge the entries of one challenge. With --output json, entries are
+printed as a JSON array, or as one JSON object per line when following.
+
+When the watcher socket or database is unavailable, the raw log file of the
+daemon is followed instead (like tail -f); --log-file follows a given one.`,
+	Example: `  # Show the latest 100 entries
   gzcli watch logs
 
-  # View custom log file
+  # Follow warnings and errors of one challenge
+  gzcli watch logs -f --level warn --challenge web/my-challenge
+
+  # Stream entries as JSON lines to another tool
+  gzcli watch logs -f --output json | jq .message
+
+  # Follow a daemon log file
   gzcli watch logs --log-file /custom/path/watcher.log`,
 	Run: func(_ *cobra.Command, _ []string) {
-		gz := gzcli.MustInit()
+		if logsFile != "" {
+			followLogFile(logsFile)
+			return
+		}
 
-		watcher, err := gzcli.NewWatcher(gz)
-		if err != nil {
-			log.Fatal("Failed to create watcher: ", err)
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if logsSocketPath != "" {
+			socketPath = logsSocketPath
 		}
+		client := gzcli.NewWatcherClient(socketPath)
+		filter := watchertypes.LogFilter{Level: logsLevel, Challenge: logsChallenge, Limit: logsLimit}
 
-		logFile := gzcli.DefaultWatcherConfig.LogFile
-		if logsFile != "" {
-			logFile = logsFile
+		if !logsFollow {
+			logs, err := client.Logs(filter)
+			if err != nil {
+				fallbackToLogFile(err)
+				return
+			}
+			printResult(logs, func() {
+				if len(logs) == 0 {
+					log.Info("No logs recorded")
+					return
+				}
+				client.PrintLogEntries(logs)
+			})
+			return
+		}
+
+		if _, err := client.Logs(watchertypes.LogFilter{Limit: 1}); err != nil {
+			fallbackToLogFile(err)
+			return
 		}
 
-		log.Info("📋 Following GZCTF Watcher logs: %s", logFile)
-		log.Info("Press Ctrl+C to stop following logs")
-		log.Info("==========================================")
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-		if err := watcher.FollowLogs(logFile); err != nil {
+		enc := json.NewEncoder(os.Stdout)
+		err := client.FollowLogs(ctx, filter, func(logs []watchertypes.WatcherLog) error {
+			if !jsonOutput() {
+				client.PrintLogEntries(logs)
+				return nil
+			}
+			for _, entry := range logs {
+				if err := enc.Encode(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
 			log.Fatal("Failed to follow logs: ", err)
 		}
 	},
 }
 
+// fallbackToLogFile follows the default daemon log file when the watcher log
+// entries cannot be read through the socket
+func fallbackToLogFile(err error) {
+	logFile := gzcli.DefaultWatcherConfig.LogFile
+	if _, statErr := os.Stat(logFile); statErr != nil {
+		log.Fatal("Failed to get logs: ", err)
+	}
+	log.Info("Watcher logs unavailable (%v), following %s instead", err, logFile)
+	followLogFile(logFile)
+}
+
+// followLogFile follows the raw log file of the watcher daemon
+func followLogFile(logFile string) {
+	gz := gzcli.MustInit()
+
+	watcher, err := gzcli.NewWatcher(gz)
+	if err != nil {
+		log.Fatal("Failed to create watcher: ", err)
+	}
+
+	log.Info("📋 Following GZCTF Watcher logs: %s", logFile)
+	log.Info("Press Ctrl+C to stop following logs")
+	log.Info("==========================================")
+
+	if err := watcher.FollowLogs(logFile); err != nil {
+		log.Fatal("Failed to follow logs: ", err)
+	}
+}
+
 func init() {
 	watchCmd.AddCommand(watchLogsCmd)
 
-	watchLogsCmd.Flags().StringVar(&logsFile, "log-file", "", "Custom log file location")
+	watchLogsCmd.Flags().StringVar(&logsFile, "log-file", "", "Follow this daemon log file instead of the watcher database")
+	watchLogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Print entries as they are written until Ctrl+C")
+	watchLogsCmd.Flags().StringVar(&logsLevel, "level", "", "Minimum level of entries: info, warn or error")
+	watchLogsCmd.Flags().StringVar(&logsChallenge, "challenge", "", "Only entries of this challenge")
+	watchLogsCmd.Flags().IntVar(&logsLimit, "limit", 100, "Number of latest entries to show first")
+	watchLogsCmd.Flags().StringVar(&logsSocketPath, "socket", "", "Custom socket path")
+
+	_ = watchLogsCmd.RegisterFlagCompletionFunc("challenge", validChallengeFolders)
 }
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	pauseEvent      string
+	pauseReason     string
+	pauseSocketPath string
+)
+
+var watchPauseCmd = &cobra.Command{
+	Use:   "pause [challenge]",
+	Short: "Pause syncs of a challenge or a whole event",
+	Long: `Pause the syncs of a challenge of an event, or of every challenge of the
+event when no challenge is given, without stopping the watcher.
+
+The watcher keeps watching paused challenges: changes are recorded and skipped,
+and synced once resumed with "gzcli watch resume". Syncs already running finish.
+Pauses are stored in the watcher database, so they survive restarts. The status
+of the event lists them. Challenges are named "category/folder", as listed by
+"gzcli watch status --event".`,
+	Example: `  # Pause one challenge while reworking it
+  gzcli watch pause --event ctf2024 web/my-challenge --reason "rewriting the solver"
+
+  # Pause every challenge of an event
+  gzcli watch pause --event ctf2024`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if pauseEvent == "" {
+			log.Fatal("Missing --event: choose the event to pause")
+		}
+
+		client := gzcli.NewWatcherClient(pauseSocket())
+		response, err := client.PauseSync(pauseEvent, pauseChallenge(args), pauseReason)
+		if err != nil {
+			log.Fatal("Failed to communicate with watcher daemon: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Failed to pause syncs: ", response.Error)
+		}
+		log.Info("⏸️  %s", response.Message)
+	},
+}
+
+var watchResumeCmd = &cobra.Command{
+	Use:   "resume [challenge]",
+	Short: "Resume syncs paused with 'gzcli watch pause'",
+	Long: `Resume the syncs of a challenge, or of the whole event when no challenge is
+given, paused with "gzcli watch pause". Challenges that changed while paused are
+synced right away.
+
+A challenge paused by itself stays paused when its event is resumed, and the
+other way around.`,
+	Example: `  gzcli watch resume --event ctf2024 web/my-challenge
+  gzcli watch resume --event ctf2024`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if pauseEvent == "" {
+			log.Fatal("Missing --event: choose the event to resume")
+		}
+
+		client := gzcli.NewWatcherClient(pauseSocket())
+		response, err := client.ResumeSync(pauseEvent, pauseChallenge(args))
+		if err != nil {
+			log.Fatal("Failed to communicate with watcher daemon: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Failed to resume syncs: ", response.Error)
+		}
+		log.Info("▶️  %s", response.Message)
+	},
+}
+
+// pauseSocket returns the socket of the watcher to pause or resume
+func pauseSocket() string {
+	if pauseSocketPath != "" {
+		return pauseSocketPath
+	}
+	return gzcli.DefaultWatcherConfig.SocketPath
+}
+
+// pauseChallenge returns the challenge argument, or "" for the whole event
+func pauseChallenge(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+func init() {
+	watchCmd.AddCommand(watchPauseCmd)
+	watchCmd.AddCommand(watchResumeCmd)
+
+	for _, c := range []*cobra.Command{watchPauseCmd, watchResumeCmd} {
+		c.Flags().StringVar(&pauseEvent, "event", "", "Event to pause or resume")
+		c.Flags().StringVar(&pauseSocketPath, "socket", "", "Custom socket file location")
+
+		// Register completion for --event flag
+		_ = c.RegisterFlagCompletionFunc("event", validEventNames)
+		c.ValidArgsFunction = validChallengeFolder
+	}
+	watchPauseCmd.Flags().StringVar(&pauseReason, "reason", "", "Why syncs are paused, shown in the status")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	planEvent      string
+	planLimit      int
+	planSocketPath string
+)
+
+var watchPlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show syncs computed by a dry-run watcher",
+	Long: `Display the syncs recorded by a watcher started with --dry-run, including
+the kind of update the file changes call for (metadata, attachment or full
+redeploy) and the diff between the remote challenge and the local configuration.
+"gzcli watch status" lists the latest pending change per challenge.
+
+No API mutations are made while the watcher runs in dry-run mode.`,
+	Example: `  # Show the last 20 dry-run syncs
+  gzcli watch plan
+
+  # Show dry-run syncs for a specific event
+  gzcli watch plan --event ctf2024 --limit 50`,
+	Run: func(_ *cobra.Command, _ []string) {
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if planSocketPath != "" {
+			socketPath = planSocketPath
+		}
+
+		client := gzcli.NewWatcherClient(socketPath)
+		if err := client.PrintDryRunSyncs(planEvent, planLimit); err != nil {
+			log.Fatal("Failed to get dry-run syncs: ", err)
+		}
+	},
+}
+
+func init() {
+	watchCmd.AddCommand(watchPlanCmd)
+
+	watchPlanCmd.Flags().StringVar(&planEvent, "event", "", "Show dry-run syncs for a specific event")
+	watchPlanCmd.Flags().IntVar(&planLimit, "limit", 20, "Maximum number of entries to show")
+	watchPlanCmd.Flags().StringVar(&planSocketPath, "socket", "", "Custom socket file location")
+
+	// Register completion for --event flag
+	_ = watchPlanCmd.RegisterFlagCompletionFunc("event", validEventNames)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	progressEvent      string
+	progressFollow     bool
+	progressSocketPath string
+)
+
+var watchProgressCmd = &cobra.Command{
+	Use:   "progress",
+	Short: "Show the progress of the watcher syncing all challenges of an event",
+	Long: `Show the progress of the latest sync of all challenges of each watched event,
+such as the one after a git pull brought new commits: challenges done out of
+the total, failures, the challenges syncing now and an ETA extrapolated from
+the rate challenges were done at so far. The same progress is part of
+"gzcli watch status --output json".
+
+With --follow, a line is printed whenever the progress changes until every
+sync is done.`,
+	Example: `  gzcli watch progress
+  gzcli watch progress --event ctf2024 --follow`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if progressSocketPath != "" {
+			socketPath = progressSocketPath
+		}
+		client := gzcli.NewWatcherClient(socketPath)
+
+		progress, err := client.SyncProgress(progressEvent)
+		if err != nil {
+			log.Fatal("Failed to get sync progress: ", err)
+		}
+		if jsonOutput() || !progressFollow {
+			printResult(progress, func() {
+				if len(progress) == 0 {
+					log.Info("No sync of all challenges has run")
+					return
+				}
+				for _, p := range progress {
+					fmt.Println(formatSyncProgress(p))
+				}
+			})
+			return
+		}
+
+		last := make(map[string]string)
+		for {
+			running := false
+			for _, p := range progress {
+				if line := formatSyncProgress(p); line != last[p.Event] {
+					fmt.Println(line)
+					last[p.Event] = line
+				}
+				running = running || p.FinishedAt == nil
+			}
+			if !running {
+				return
+			}
+			time.Sleep(time.Second)
+			if progress, err = client.SyncProgress(progressEvent); err != nil {
+				log.Fatal("Failed to get sync progress: ", err)
+			}
+		}
+	},
+}
+
+func init() {
+	watchCmd.AddCommand(watchProgressCmd)
+
+	watchProgressCmd.Flags().StringVar(&progressEvent, "event", "", "Show progress for a specific event")
+	watchProgressCmd.Flags().BoolVarP(&progressFollow, "follow", "f", false, "Print progress changes until every sync is done")
+	watchProgressCmd.Flags().StringVar(&progressSocketPath, "socket", "", "Custom socket file location")
+
+	// Register completion for --event flag
+	_ = watchProgressCmd.RegisterFlagCompletionFunc("event", validEventNames)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	remapEvent      string
+	remapSocketPath string
+	remapRebuild    bool
+	remapYes        bool
+	remapThreshold  float64
+)
+
+var watchRemapCmd = &cobra.Command{
+	Use:   "remap [challenge-folder...]",
+	Short: "Drop challenge mappings so they are matched again by title",
+	Long: `Drop the watcher's mapping between challenge folders and GZCTF challenge IDs
+so the next sync of each challenge finds it by title in the event's game, or
+creates it.
+
+The watcher refuses to sync a challenge whose mapping points at a challenge of
+another game, which happens when an event was cloned from another one. Without
+folders, every mapping of the event pointing at another game is dropped.
+
+Folders are relative to the event directory, e.g. "web/my-challenge".
+
+With --rebuild, fresh mappings are written instead, e.g. after the watcher
+database was lost: local challenges are matched with the challenges of the
+event's game by normalized title and category, and titles without an exact
+match with the most similar title of their category. Each fuzzy match is
+confirmed interactively unless --yes is given. The mappings are recorded by the
+running watcher, or straight into its database when it is not running, so the
+next sync updates the existing challenges instead of creating duplicates.`,
+	Example: `  # Drop every mapping of ctf2024 that points at another game
+  gzcli watch remap --event ctf2024
+
+  # Drop the mapping of a single challenge
+  gzcli watch remap --event ctf2024 web/my-challenge
+
+  # Rebuild every mapping of ctf2024 from the challenges on the server
+  gzcli watch remap --event ctf2024 --rebuild`,
+	Run: func(_ *cobra.Command, args []string) {
+		if remapEvent == "" {
+			log.Fatal("Missing --event: choose the event whose challenges to remap")
+		}
+		if remapRebuild {
+			if len(args) > 0 {
+				log.Fatal("--rebuild maps every challenge of the event and takes no folders")
+			}
+			if err := rebuildMappings(); err != nil {
+				log.Fatal("Failed to rebuild challenge mappings: ", err)
+			}
+			return
+		}
+
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if remapSocketPath != "" {
+			socketPath = remapSocketPath
+		}
+
+		client := gzcli.NewWatcherClient(socketPath)
+		response, err := client.RemapChallenges(remapEvent, args)
+		if err != nil {
+			log.Fatal("Failed to communicate with watcher daemon: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Failed to remap challenges: ", response.Error)
+		}
+
+		log.Info("✅ %s", response.Message)
+		if remapped, ok := response.Data["remapped"].([]interface{}); ok {
+			for _, folder := range remapped {
+				log.InfoH3("%v", folder)
+			}
+		}
+	},
+}
+
+// rebuildMappings proposes mappings for every challenge of the event, asks
+// to confirm the fuzzy ones and records the accepted ones
+func rebuildMappings() error {
+	gz, err := gzcli.InitWithEvent(remapEvent)
+	if err != nil {
+		return err
+	}
+	plan, err := gz.ProposeMappings(remapThreshold)
+	if err != nil {
+		return err
+	}
+
+	var accepted []gzcli.MappingProposal
+	for _, p := range plan.Proposals {
+		if p.Exact() {
+			log.InfoH3("%s → %q (ID %d)", p.Folder, p.Title, p.ChallengeID)
+			accepted = append(accepted, p)
+			continue
+		}
+		if !remapYes {
+			confirm := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf("Map %s (%q) to %q (ID %d, %.0f%% similar)?", p.Folder, p.Name, p.Title, p.ChallengeID, p.Score*100),
+			}
+			if err := survey.AskOne(prompt, &confirm); err != nil {
+				return fmt.Errorf("confirmation failed (use --yes to accept fuzzy matches): %w", err)
+			}
+			if !confirm {
+				continue
+			}
+		}
+		log.InfoH3("%s → %q (ID %d, fuzzy)", p.Folder, p.Title, p.ChallengeID)
+		accepted = append(accepted, p)
+	}
+	for _, l := range plan.UnmatchedLocal {
+		log.Info("No remote challenge for %s (%q), it will be created on its next sync", l.Folder, l.Name)
+	}
+	for _, r := range plan.UnmatchedRemote {
+		log.Info("No local challenge for remote %q (ID %d)", r.Title, r.Id)
+	}
+	if len(accepted) == 0 {
+		log.Info("No mappings to record")
+		return nil
+	}
+
+	mappings := gzcli.ProposalMappings(accepted)
+	if err := recordMappings(remapSocketPath, plan.Event, plan.GameID, mappings); err != nil {
+		return err
+	}
+
+	log.Info("✅ Recorded %d challenge mapping(s) in event '%s'", len(mappings), plan.Event)
+	return nil
+}
+
+// recordMappings records challenge mappings of an event through the watcher
+// listening on socketPath, or straight into its database when it is not running
+func recordMappings(socketPath, eventName string, gameID int, mappings []watchertypes.ChallengeMapping) error {
+	if socketPath == "" {
+		socketPath = gzcli.DefaultWatcherConfig.SocketPath
+	}
+	client := gzcli.NewWatcherClient(socketPath)
+	if !client.IsWatcherRunning() {
+		return gzcli.SaveChallengeMappings(gzcli.WatcherDatabase(), eventName, gameID, mappings)
+	}
+	response, err := client.SetMappings(eventName, gameID, mappings)
+	if err != nil {
+		return fmt.Errorf("failed to communicate with watcher daemon: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("%s", response.Error)
+	}
+	return nil
+}
+
+func init() {
+	watchCmd.AddCommand(watchRemapCmd)
+
+	watchRemapCmd.Flags().StringVar(&remapEvent, "event", "", "Event whose challenge mappings to drop or rebuild")
+	watchRemapCmd.Flags().StringVar(&remapSocketPath, "socket", "", "Custom socket file location")
+	watchRemapCmd.Flags().BoolVar(&remapRebuild, "rebuild", false, "Rebuild the mappings by matching local and remote challenges by title")
+	watchRemapCmd.Flags().BoolVarP(&remapYes, "yes", "y", false, "Accept fuzzy matches of --rebuild without confirmation")
+	watchRemapCmd.Flags().Float64Var(&remapThreshold, "threshold", gzcli.DefaultRemapThreshold, "Lowest title similarity (0-1) proposed as a fuzzy match by --rebuild")
+
+	// Register completion for --event flag
+	_ = watchRemapCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	watchRemapCmd.ValidArgsFunction = validChallengeFolders
+}
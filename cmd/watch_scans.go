@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	scansEvent      string
+	scansChallenge  string
+	scansLimit      int
+	scansVerbose    bool
+	scansSocketPath string
+)
+
+var watchScansCmd = &cobra.Command{
+	Use:   "scans",
+	Short: "Show image scans run before full redeploys",
+	Long: `Display the vulnerability scans a watcher started with --image-scan ran against
+challenge images before full redeploys, with their result and whether they
+blocked the deployment. "gzcli watch status --challenge" shows the latest scan
+of a challenge.`,
+	Example: `  # Show the last 20 image scans
+  gzcli watch scans
+
+  # Show the scans of one challenge with the scanner output
+  gzcli watch scans --event ctf2024 --challenge "Web Challenge" --verbose`,
+	Run: func(_ *cobra.Command, _ []string) {
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if scansSocketPath != "" {
+			socketPath = scansSocketPath
+		}
+
+		client := gzcli.NewWatcherClient(socketPath)
+		if jsonOutput() {
+			response, err := client.GetImageScans(scansEvent, scansChallenge, scansLimit)
+			if err != nil {
+				log.Fatal("Failed to get image scans: ", err)
+			}
+			if !response.Success {
+				log.Fatal("Failed to get image scans: ", response.Error)
+			}
+			writeJSON(response.Data["scans"])
+			return
+		}
+		if err := client.PrintImageScans(scansEvent, scansChallenge, scansLimit, scansVerbose); err != nil {
+			log.Fatal("Failed to get image scans: ", err)
+		}
+	},
+}
+
+func init() {
+	watchCmd.AddCommand(watchScansCmd)
+
+	watchScansCmd.Flags().StringVar(&scansEvent, "event", "", "Show image scans for a specific event")
+	watchScansCmd.Flags().StringVar(&scansChallenge, "challenge", "", "Show image scans for a specific challenge")
+	watchScansCmd.Flags().IntVar(&scansLimit, "limit", 20, "Maximum number of entries to show")
+	watchScansCmd.Flags().BoolVarP(&scansVerbose, "verbose", "v", false, "Include the scanner output")
+	watchScansCmd.Flags().StringVar(&scansSocketPath, "socket", "", "Custom socket file location")
+
+	// Register completion for --event flag
+	_ = watchScansCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	_ = watchScansCmd.RegisterFlagCompletionFunc("challenge", validChallengeFolders)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	searchSince      string
+	searchLimit      int
+	searchRaw        bool
+	searchJSON       bool
+	searchSocketPath string
+)
+
+var watchSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search watcher logs and script output",
+	Long: `Full-text search over watcher log messages and the output of challenge
+scripts, newest matches first.
+
+Every word of the query must appear in a match. Use --raw to pass the query
+to SQLite FTS5 unchanged, enabling phrases ("..."), prefixes (conn*),
+OR/NOT and column filters (challenge:web).
+Requires the watcher database (enabled by default).`,
+	Example: `  # Find connection errors in the last 6 hours
+  gzcli watch search "connection refused" --since 6h
+
+  # FTS5 query syntax
+  gzcli watch search --raw 'timeout OR "no space left"' --since 7d`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		window, err := parseEventDuration(searchSince)
+		if err != nil {
+			log.Fatal("Invalid --since: ", err)
+		}
+
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if searchSocketPath != "" {
+			socketPath = searchSocketPath
+		}
+
+		query := strings.Join(args, " ")
+		since := time.Now().Add(-window)
+		client := gzcli.NewWatcherClient(socketPath)
+
+		if !searchJSON && !jsonOutput() {
+			if err := client.PrintSearchResults(query, searchRaw, since, searchLimit); err != nil {
+				log.Fatal("Search failed: ", err)
+			}
+			return
+		}
+
+		response, err := client.SearchLogs(query, searchRaw, since, searchLimit)
+		if err != nil {
+			log.Fatal("Search failed: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Search request failed: ", response.Error)
+		}
+
+		results := response.Data["results"]
+		if results == nil {
+			results = []interface{}{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatal("JSON encoding failed: ", err)
+		}
+	},
+}
+
+func init() {
+	watchCmd.AddCommand(watchSearchCmd)
+
+	watchSearchCmd.Flags().StringVar(&searchSince, "since", "24h", "How far back to search (e.g. 6h, 24h, 7d)")
+	watchSearchCmd.Flags().IntVar(&searchLimit, "limit", 50, "Maximum number of matches to show")
+	watchSearchCmd.Flags().BoolVar(&searchRaw, "raw", false, "Pass the query to FTS5 unchanged")
+	watchSearchCmd.Flags().BoolVar(&searchJSON, "json", false, "Print matches as JSON")
+	watchSearchCmd.Flags().StringVar(&searchSocketPath, "socket", "", "Custom socket file location")
+}
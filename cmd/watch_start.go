@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
@@ -18,11 +20,29 @@ var (
 	watchLogFile       string
 	watchDebounce      time.Duration
 	watchPollInterval  time.Duration
+	watchPoll          bool
 	watchIgnore        []string
 	watchPatterns      []string
 	watchGitPull       bool
 	watchGitInterval   time.Duration
 	watchGitRepo       string
+	watchDryRun        bool
+	watchFormatYaml    bool
+	watchImageScan     bool
+	watchScanCommand   string
+	watchScanBlock     bool
+	watchAnnounce      string
+	watchLiveLock      bool
+	watchTargets       []string
+	watchMetricsAddr   string
+	watchDatabaseURL   string
+	watchGitCommit     bool
+	watchGitBranch     string
+	watchGitMessage    string
+	watchGitPaths      []string
+	watchGitPush       bool
+	watchGitRemote     string
+	watchLauncherSock  string
 	watchEvents        []string // Multiple events to watch
 	watchExcludeEvents []string // Events to exclude from watching
 )
@@ -52,13 +72,43 @@ The watcher runs as a daemon by default. Use --foreground to run in the current
   gzcli watch start --debounce 5s
 
   # Start with custom ignore patterns
-  gzcli watch start --ignore "*.tmp" --ignore "*.log"`,
+  gzcli watch start --ignore "*.tmp" --ignore "*.log"
+
+  # Poll for changes every 10 seconds, e.g. on an NFS mount
+  gzcli watch start --poll --poll-interval 10s
+
+  # Rehearse against production without mutating the API
+  gzcli watch start --dry-run
+
+  # Normalize challenge.yml files after they sync
+  gzcli watch start --format-yaml
+
+  # Scan challenge images with trivy before full redeploys, blocking on critical findings
+  gzcli watch start --image-scan-block
+
+  # Scan with grype and only warn on findings
+  gzcli watch start --image-scan --image-scan-command "grype {{.Image}} --fail-on critical"
+
+  # Announce newly created challenges in the organizers' Discord channel
+  gzcli watch start --announce-webhook https://discord.com/api/webhooks/<id>/<token>
+
+  # Only sync metadata fixes while the game is running, holding redeploys for approval
+  gzcli watch start --live-lock
+
+  # Mirror syncs only to the conf.yaml targets named or tagged staging
+  gzcli watch start --target staging
+
+  # Share mappings, logs and challenge states with the watchers of other hosts
+  gzcli watch start --database-url postgres://gzctf:<password>@db:5432/gzcli
+
+  # Commit generated dist files to a dedicated branch and push them
+  gzcli watch start --git-commit --git-commit-path dist --git-push`,
 	Run: func(_ *cobra.Command, _ []string) {
 		// Determine which events to watch
 		eventsToWatch, err := ResolveTargetEvents(watchEvents, watchExcludeEvents)
 		if err != nil {
 			log.Error("Failed to resolve target events: %v", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		log.InfoH2("Watching %d event(s): %v", len(eventsToWatch), eventsToWatch)
@@ -67,12 +117,13 @@ The watcher runs as a daemon by default. Use --foreground to run in the current
 		gz, err := gzcli.InitWithEvent("")
 		if err != nil {
 			log.Error("Failed to initialize: %v", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		config := gzcli.WatcherConfig{
 			Events:                    eventsToWatch,
 			PollInterval:              watchPollInterval,
+			Polling:                   watchPoll,
 			DebounceTime:              watchDebounce,
 			IgnorePatterns:            gzcli.DefaultWatcherConfig.IgnorePatterns,
 			WatchPatterns:             gzcli.DefaultWatcherConfig.WatchPatterns,
@@ -83,10 +134,33 @@ The watcher runs as a daemon by default. Use --foreground to run in the current
 			GitPullEnabled:            watchGitPull,
 			GitPullInterval:           watchGitInterval,
 			GitRepository:             watchGitRepo,
+			DryRun:                    watchDryRun,
+			FormatYaml:                watchFormatYaml,
+			ImageScanEnabled:          watchImageScan || watchScanBlock,
+			ImageScanCommand:          watchScanCommand,
+			ImageScanBlock:            watchScanBlock,
+			AnnounceWebhook:           watchAnnounce,
+			LiveLock:                  watchLiveLock,
+			Targets:                   watchTargets,
+			GitCommitEnabled:          watchGitCommit,
+			GitCommitBranch:           watchGitBranch,
+			GitCommitMessage:          watchGitMessage,
+			GitCommitPaths:            watchGitPaths,
+			GitPushEnabled:            watchGitPush,
+			GitPushRemote:             watchGitRemote,
 			DatabaseEnabled:           true,
 			SocketEnabled:             true,
+			LauncherSocketPath:        watchLauncherSock,
+			MetricsAddr:               watchMetricsAddr,
+			DatabaseURL:               watchDatabaseURL,
 		}
 
+		if config.DatabaseURL == "" {
+			config.DatabaseURL = os.Getenv(database.URLEnv)
+		}
+		if config.AnnounceWebhook == "" {
+			config.AnnounceWebhook = os.Getenv(announce.WebhookEnv)
+		}
 		if watchPidFile != "" {
 			config.PidFile = watchPidFile
 		}
@@ -100,6 +174,14 @@ The watcher runs as a daemon by default. Use --foreground to run in the current
 			config.WatchPatterns = watchPatterns
 		}
 
+		if config.DryRun {
+			log.Info("Dry-run mode enabled: syncs are computed and logged, no API mutations are made")
+		}
+
+		if config.LiveLock {
+			log.Info("Live lock enabled: during the game only metadata syncs run, other syncs wait for 'gzcli watch approve'")
+		}
+
 		if config.DaemonMode {
 			log.Info("Starting file watcher as daemon...")
 		} else {
@@ -135,12 +217,31 @@ func init() {
 	watchStartCmd.Flags().StringVar(&watchPidFile, "pid-file", "", "Custom PID file location (default: /tmp/gzctf-watcher.pid)")
 	watchStartCmd.Flags().StringVar(&watchLogFile, "log-file", "", "Custom log file location (default: /tmp/gzctf-watcher.log)")
 	watchStartCmd.Flags().DurationVar(&watchDebounce, "debounce", 2*time.Second, "Debounce time for file changes")
-	watchStartCmd.Flags().DurationVar(&watchPollInterval, "poll-interval", 5*time.Second, "Polling interval for file changes")
+	watchStartCmd.Flags().DurationVar(&watchPollInterval, "poll-interval", 5*time.Second, "Interval between scans of challenge directories when polling")
+	watchStartCmd.Flags().BoolVar(&watchPoll, "poll", false, "Poll challenge directories for changes instead of using inotify, e.g. on network filesystems")
 	watchStartCmd.Flags().StringSliceVar(&watchIgnore, "ignore", []string{}, "Additional patterns to ignore")
 	watchStartCmd.Flags().StringSliceVar(&watchPatterns, "patterns", []string{}, "File patterns to watch (overrides default)")
 	watchStartCmd.Flags().BoolVar(&watchGitPull, "git-pull", true, "Enable automatic git pull")
 	watchStartCmd.Flags().DurationVar(&watchGitInterval, "git-interval", 1*time.Minute, "Git pull interval")
 	watchStartCmd.Flags().StringVar(&watchGitRepo, "git-repo", ".", "Git repository path")
+	watchStartCmd.Flags().StringVar(&watchLauncherSock, "launcher-socket", gzcli.DefaultWatcherConfig.LauncherSocketPath, "Launcher socket notified when challenges are removed (empty to disable)")
+	watchStartCmd.Flags().BoolVar(&watchGitCommit, "git-commit", false, "Commit generated files to a dedicated branch after successful syncs")
+	watchStartCmd.Flags().StringVar(&watchGitBranch, "git-commit-branch", gzcli.DefaultWatcherConfig.GitCommitBranch, "Branch receiving generated-file commits")
+	watchStartCmd.Flags().StringVar(&watchGitMessage, "git-commit-message", "", "Commit message template (fields: .Event, .Challenge, .Files, .Time)")
+	watchStartCmd.Flags().StringSliceVar(&watchGitPaths, "git-commit-path", gzcli.DefaultWatcherConfig.GitCommitPaths, "Path relative to each challenge to commit")
+	watchStartCmd.Flags().BoolVar(&watchGitPush, "git-push", false, "Push the generated-files branch after committing")
+	watchStartCmd.Flags().StringVar(&watchGitRemote, "git-push-remote", gzcli.DefaultWatcherConfig.GitPushRemote, "Remote to push generated-file commits to")
+	watchStartCmd.Flags().BoolVar(&watchDryRun, "dry-run", false, "Record pending syncs (update type and diff) without mutating the GZCTF API")
+	watchStartCmd.Flags().BoolVar(&watchFormatYaml, "format-yaml", false, "Normalize challenge.yml after successful syncs (see 'gzcli fmt')")
+	watchStartCmd.Flags().BoolVar(&watchImageScan, "image-scan", false, "Scan container images before full redeploys and warn on findings")
+	watchStartCmd.Flags().StringVar(&watchScanCommand, "image-scan-command", "", "Scan command template, exiting non-zero on findings (fields: .Image, .Event, .Challenge; default: trivy, critical only)")
+	watchStartCmd.Flags().BoolVar(&watchScanBlock, "image-scan-block", false, "Scan container images before full redeploys and abort on findings or scanner errors (implies --image-scan)")
+	watchStartCmd.Flags().StringVar(&watchAnnounce, "announce-webhook", "", "Webhook notified of each newly created challenge with its category, value and author (default: $GZCLI_ANNOUNCE_WEBHOOK)")
+
+	watchStartCmd.Flags().BoolVar(&watchLiveLock, "live-lock", false, "Between the .gzevent start and end, only sync metadata and hold attachment updates and redeploys until 'gzcli watch approve'")
+	watchStartCmd.Flags().StringSliceVar(&watchTargets, "target", []string{}, "Only mirror syncs to the conf.yaml targets with this name or tag (repeatable)")
+	watchStartCmd.Flags().StringVar(&watchDatabaseURL, "database-url", "", "Postgres URL of a watcher database shared by several hosts (default $"+database.URLEnv+", or a local SQLite file)")
+	watchStartCmd.Flags().StringVar(&watchMetricsAddr, "metrics-addr", "", "Serve Prometheus metrics of syncs and API requests at http://<addr>/metrics")
 
 	// Register completion for --event flag
 	_ = watchStartCmd.RegisterFlagCompletionFunc("event", validEventNames)
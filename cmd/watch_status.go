@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
@@ -14,22 +21,45 @@ var (
 	statusLogFile    string
 	statusJSON       bool
 	statusEvent      string
+	statusChallenge  string
 	statusSocketPath string
+	statusVerbose    bool
 )
 
 var watchStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show file watcher status",
-	Long:  `Display the current status of the file watcher daemon, optionally filtered by event.`,
+	Long: `Display the current status of the file watcher daemon, optionally filtered by event.
+
+With --challenge, show the sync state of one challenge of the event instead: its
+recorded state, last sync and error, active interval scripts and the GZCTF
+challenge ID it is mapped to. Challenges are named "category/folder", as listed
+by "gzcli watch status --event".
+
+With --verbose, also show the sync statistics of each challenge recorded by the
+watcher: syncs, failures, failure rate, average duration and last failure, most
+failing first, to spot flaky challenges that repeatedly fail to deploy.
+
+The status of an event lists its challenges paused with "gzcli watch pause".`,
 	Example: `  # Show status for all events
   gzcli watch status
 
   # Show status for a specific event
   gzcli watch status --event ctf2024
 
+  # Show the sync state of one challenge
+  gzcli watch status --event ctf2024 --challenge web/my-challenge
+
+  # Show the sync statistics of every challenge of an event
+  gzcli watch status --event ctf2024 --verbose
+
   # Show status in JSON format
-  gzcli watch status --json`,
+  gzcli watch status --output json`,
 	Run: func(_ *cobra.Command, _ []string) {
+		if jsonOutput() {
+			statusJSON = true
+		}
+
 		gz := gzcli.MustInit()
 
 		watcher, err := gzcli.NewWatcher(gz)
@@ -52,11 +82,34 @@ var watchStatusCmd = &cobra.Command{
 			socketPath = statusSocketPath
 		}
 
-		// If requesting status for a specific event, use socket command
-		if statusEvent != "" {
+		if statusChallenge != "" {
+			if statusEvent == "" {
+				log.Fatal("Missing --event: --challenge needs the event of the challenge")
+			}
+			client := gzcli.NewWatcherClient(socketPath)
+			if statusJSON {
+				response, err := client.ChallengeStatus(statusEvent, statusChallenge)
+				if err != nil {
+					log.Fatal("Failed to communicate with watcher daemon: ", err)
+				}
+				if !response.Success {
+					log.Fatal("Failed to get challenge status: ", response.Error)
+				}
+				writeJSON(response.Data)
+				return
+			}
+			if err := client.PrintChallengeStatus(statusEvent, statusChallenge); err != nil {
+				log.Fatal("Failed to get challenge status: ", err)
+			}
+			return
+		}
+
+		// If requesting status for a specific event or statistics, use socket command
+		if statusEvent != "" || statusVerbose {
 			client := gzcli.NewWatcherClient(socketPath)
 			response, err := client.SendCommand("status", map[string]interface{}{
-				"event": statusEvent,
+				"event":   statusEvent,
+				"verbose": statusVerbose,
 			})
 			if err != nil {
 				log.Fatal("Failed to communicate with watcher daemon: ", err)
@@ -68,11 +121,25 @@ var watchStatusCmd = &cobra.Command{
 
 			// Print the response
 			if statusJSON {
-				// Print raw JSON
-				fmt.Printf("%+v\n", response.Data)
-			} else {
+				writeJSON(response.Data)
+				return
+			}
+			stats, verbose := response.Data["sync_stats"]
+			delete(response.Data, "sync_stats")
+			paused := response.Data["paused_syncs"]
+			delete(response.Data, "paused_syncs")
+			if statusEvent != "" {
 				log.Info("Status for event '%s':", statusEvent)
-				fmt.Printf("%+v\n", response.Data)
+			} else {
+				log.Info("Status for all events:")
+			}
+			fmt.Printf("%+v\n", response.Data)
+			socket.PrintPausedSyncs(paused)
+			if statusVerbose {
+				if !verbose {
+					log.Fatal("Failed to get sync statistics: database logging is disabled")
+				}
+				printSyncStats(stats)
 			}
 			return
 		}
@@ -88,11 +155,45 @@ func init() {
 	watchCmd.AddCommand(watchStatusCmd)
 
 	watchStatusCmd.Flags().StringVar(&statusEvent, "event", "", "Show status for a specific event")
+	watchStatusCmd.Flags().StringVar(&statusChallenge, "challenge", "", "Show the sync state of a challenge of --event")
 	watchStatusCmd.Flags().StringVar(&statusPidFile, "pid-file", "", "Custom PID file location")
 	watchStatusCmd.Flags().StringVar(&statusLogFile, "log-file", "", "Custom log file location")
 	watchStatusCmd.Flags().StringVar(&statusSocketPath, "socket", "", "Custom socket file location")
-	watchStatusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output status in JSON format")
+	watchStatusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "v", false, "Also show the sync statistics of each challenge")
+	watchStatusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output status in JSON format (same as --output json)")
 
 	// Register completion for --event flag
 	_ = watchStatusCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	_ = watchStatusCmd.RegisterFlagCompletionFunc("challenge", validChallengeFolders)
+}
+
+// printSyncStats prints the sync statistics of a status response, the most
+// failing challenges first
+func printSyncStats(data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Fatal("Failed to encode sync statistics: ", err)
+	}
+	var stats []watchertypes.SyncStats
+	if err := json.Unmarshal(raw, &stats); err != nil {
+		log.Fatal("Failed to decode sync statistics: ", err)
+	}
+	if len(stats) == 0 {
+		log.Info("No syncs recorded")
+		return
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].FailureRate > stats[j].FailureRate })
+
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "EVENT\tCHALLENGE\tSYNCS\tFAILED\tFAILURE RATE\tAVG DURATION\tLAST FAILURE")
+	for _, s := range stats {
+		lastFailure := "-"
+		if s.LastFailureAt != nil {
+			lastFailure = fmt.Sprintf("%s: %s", s.LastFailureAt.Local().Format(time.DateTime), s.LastFailure)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f%%\t%s\t%s\n", s.Event, s.Challenge, s.Syncs, s.Failures, s.FailureRate*100,
+			(time.Duration(s.AvgDurationMs) * time.Millisecond).Round(100*time.Millisecond), lastFailure)
+	}
+	_ = tw.Flush()
 }
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/timeline"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+var (
+	timelineEvent      string
+	timelineSince      string
+	timelineFormat     string
+	timelineOutput     string
+	timelineSocketPath string
+)
+
+var watchTimelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Export a timeline of watcher sync activity",
+	Long: `Show when the watcher synced each challenge and pulled from git, how long
+each run took, and which runs failed.
+
+The html format produces a self-contained Gantt chart with one lane per
+challenge plus a lane for git pulls, suitable for sharing after an incident.
+Requires the watcher database (enabled by default).`,
+	Example: `  # Summarize the last 24 hours in the terminal
+  gzcli watch timeline
+
+  # Export an HTML Gantt chart of the last week
+  gzcli watch timeline --since 7d --format html -o timeline.html
+
+  # Raw entries for one event
+  gzcli watch timeline --event ctf2024 --since 2h --format json`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if jsonOutput() && !cmd.Flags().Changed("format") {
+			timelineFormat = timeline.FormatJSON
+		}
+		window, err := parseEventDuration(timelineSince)
+		if err != nil {
+			log.Fatal("Invalid --since: ", err)
+		}
+
+		socketPath := gzcli.DefaultWatcherConfig.SocketPath
+		if timelineSocketPath != "" {
+			socketPath = timelineSocketPath
+		}
+
+		until := time.Now()
+		since := until.Add(-window)
+
+		client := gzcli.NewWatcherClient(socketPath)
+		response, err := client.GetSyncActivity(timelineEvent, since)
+		if err != nil {
+			log.Fatal("Failed to get sync activity: ", err)
+		}
+		if !response.Success {
+			log.Fatal("Get sync activity request failed: ", response.Error)
+		}
+
+		activity, err := timeline.DecodeActivity(response.Data["activity"])
+		if err != nil {
+			log.Fatal("Failed to read sync activity: ", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if timelineOutput != "" {
+			//nolint:gosec // G304: Output path is provided by the user
+			f, err := os.Create(timelineOutput)
+			if err != nil {
+				log.Fatal("Failed to create output file: ", err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+			out = f
+		}
+
+		if err := timeline.Build(activity, since, until).Render(out, timelineFormat); err != nil {
+			log.Fatal("Failed to render timeline: ", err)
+		}
+		if timelineOutput != "" {
+			log.Info("Timeline written to %s", timelineOutput)
+		}
+	},
+}
+
+func init() {
+	watchCmd.AddCommand(watchTimelineCmd)
+
+	watchTimelineCmd.Flags().StringVar(&timelineEvent, "event", "", "Show activity for a specific event")
+	watchTimelineCmd.Flags().StringVar(&timelineSince, "since", "24h", "How far back to look (e.g. 2h, 24h, 7d)")
+	watchTimelineCmd.Flags().StringVar(&timelineFormat, "format", timeline.FormatText, "Output format: text, json or html")
+	watchTimelineCmd.Flags().StringVarP(&timelineOutput, "out-file", "o", "", "Write to a file instead of stdout")
+	watchTimelineCmd.Flags().StringVar(&timelineSocketPath, "socket", "", "Custom socket file location")
+
+	// Register completion for --event flag
+	_ = watchTimelineCmd.RegisterFlagCompletionFunc("event", validEventNames)
+	_ = watchTimelineCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{timeline.FormatText, timeline.FormatJSON, timeline.FormatHTML}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,259 @@
+// Package audit keeps an append-only log of the state-changing gzcli
+// commands run in a workspace, so organizers sharing a host can tell who
+// changed what and whether it worked. Entries are JSON lines in
+// .gzctf/audit.log.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the audit log inside the workspace's .gzctf directory
+const FileName = "audit.log"
+
+// Outcomes of a recorded command. A command without an end entry is still
+// running or was killed, and keeps OutcomeStarted.
+const (
+	OutcomeStarted = "started"
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Entry is one invocation of a command
+type Entry struct {
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	Host       string    `json:"host,omitempty"`
+	PID        int       `json:"pid"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	Event      string    `json:"event,omitempty"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+	Errors     int64     `json:"errors,omitempty"` // Errors logged while the command ran
+	DurationMS int64     `json:"duration_ms,omitempty"`
+}
+
+// Path returns the audit log of the workspace at root
+func Path(root string) string {
+	return filepath.Join(root, ".gzctf", FileName)
+}
+
+// Append adds entry to the audit log of the workspace at root. Nothing is
+// written outside a workspace, i.e. when root has no .gzctf directory.
+func Append(root string, entry Entry) error {
+	if info, err := os.Stat(filepath.Dir(Path(root))); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(Path(root), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // G304: path is inside the workspace
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// One write per entry, so concurrent gzcli processes do not interleave lines
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Read returns the commands recorded in the audit log of the workspace at
+// root, oldest first, with the start and end entries of each merged.
+// Malformed lines are skipped.
+func Read(root string) ([]Entry, error) {
+	buf, err := os.ReadFile(Path(root))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var entries []Entry
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.ID == "" {
+			continue
+		}
+		if i, ok := index[entry.ID]; ok {
+			entries[i] = entry
+			continue
+		}
+		index[entry.ID] = len(entries)
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Filter selects entries of the audit log
+type Filter struct {
+	User    string
+	Command string // Matches the command and its subcommands, e.g. "team"
+	Event   string
+	Since   time.Time
+	Failed  bool // Only failed commands
+	Limit   int  // Keep the most recent entries; 0 keeps all
+}
+
+// Match reports whether entry passes the filter, ignoring Limit
+func (f Filter) Match(entry Entry) bool {
+	switch {
+	case f.User != "" && entry.User != f.User:
+		return false
+	case f.Command != "" && entry.Command != f.Command && !strings.HasPrefix(entry.Command, f.Command+" "):
+		return false
+	case f.Event != "" && entry.Event != f.Event:
+		return false
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	case f.Failed && entry.Outcome != OutcomeFailure:
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries passing the filter, oldest first
+func (f Filter) Apply(entries []Entry) []Entry {
+	var out []Entry
+	for _, entry := range entries {
+		if f.Match(entry) {
+			out = append(out, entry)
+		}
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[len(out)-f.Limit:]
+	}
+	return out
+}
+
+// Recorder records one command invocation
+type Recorder struct {
+	root  string
+	entry Entry
+	once  sync.Once
+}
+
+// Start records the start of command in the audit log of the workspace at
+// root. Sensitive flag values in args are redacted.
+func Start(root, command string, args []string, event string) (*Recorder, error) {
+	host, _ := os.Hostname()
+	r := &Recorder{
+		root: root,
+		entry: Entry{
+			ID:      newID(),
+			Time:    time.Now().UTC(),
+			User:    CurrentUser(),
+			Host:    host,
+			PID:     os.Getpid(),
+			Command: command,
+			Args:    RedactArgs(args),
+			Event:   event,
+			Outcome: OutcomeStarted,
+		},
+	}
+	return r, Append(root, r.entry)
+}
+
+// Finish records the outcome of the command: a failure when err is set.
+// errorsLogged counts the errors the command logged without failing. Only
+// the first call is recorded.
+func (r *Recorder) Finish(err error, errorsLogged int64) error {
+	var appendErr error
+	r.once.Do(func() {
+		entry := r.entry
+		entry.Outcome = OutcomeSuccess
+		if err != nil {
+			entry.Outcome = OutcomeFailure
+			entry.Error = err.Error()
+		}
+		entry.Errors = errorsLogged
+		entry.DurationMS = time.Since(entry.Time).Milliseconds()
+		appendErr = Append(r.root, entry)
+	})
+	return appendErr
+}
+
+// CurrentUser returns who runs gzcli: GZCLI_AUDIT_USER when set (for
+// organizers sharing one account), else the user who invoked sudo, else the
+// login user
+func CurrentUser() string {
+	if name := os.Getenv("GZCLI_AUDIT_USER"); name != "" {
+		return name
+	}
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// sensitiveFlags are substrings of flag names whose values are not logged
+var sensitiveFlags = []string{"password", "passwd", "token", "secret", "api-key", "apikey", "webhook"}
+
+// RedactArgs replaces the values of sensitive flags, given as --flag=value
+// or --flag value, with "***"
+func RedactArgs(args []string) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+	for i := 0; i < len(out); i++ {
+		arg := out[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !isSensitive(name) {
+			continue
+		}
+		if hasValue {
+			out[i] = arg[:strings.Index(arg, "=")+1] + "***"
+		} else if i+1 < len(out) && !strings.HasPrefix(out[i+1], "-") {
+			out[i+1] = "***"
+			i++
+		}
+	}
+	return out
+}
+
+func isSensitive(flag string) bool {
+	flag = strings.ToLower(flag)
+	for _, s := range sensitiveFlags {
+		if strings.Contains(flag, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid())
+	}
+	return hex.EncodeToString(b)
+}
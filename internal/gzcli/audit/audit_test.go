@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".gzctf"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GZCLI_AUDIT_USER", "alice")
+
+	ok, err := Start(root, "sync", []string{"sync", "--event", "ctf"}, "ctf")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	killed, err := Start(root, "team create", []string{"team", "create", "teams.csv"}, "ctf")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	failed, err := Start(root, "team delete", []string{"team", "delete", "--all"}, "other")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := ok.Finish(nil, 2); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if err := failed.Finish(errors.New("forbidden"), 0); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	_ = failed.Finish(nil, 0) // Only the first outcome counts
+	_ = killed
+
+	entries, err := Read(root)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Read() = %d entries, want 3: %+v", len(entries), entries)
+	}
+	outcomes := []string{entries[0].Outcome, entries[1].Outcome, entries[2].Outcome}
+	if want := []string{OutcomeSuccess, OutcomeStarted, OutcomeFailure}; !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("outcomes = %v, want %v", outcomes, want)
+	}
+	if entries[0].User != "alice" || entries[0].Errors != 2 || entries[2].Error != "forbidden" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "all", filter: Filter{}, want: []string{"sync", "team create", "team delete"}},
+		{name: "command prefix", filter: Filter{Command: "team"}, want: []string{"team create", "team delete"}},
+		{name: "no partial word", filter: Filter{Command: "tea"}, want: nil},
+		{name: "event", filter: Filter{Event: "ctf"}, want: []string{"sync", "team create"}},
+		{name: "failed", filter: Filter{Failed: true}, want: []string{"team delete"}},
+		{name: "user", filter: Filter{User: "bob"}, want: nil},
+		{name: "since", filter: Filter{Since: time.Now().Add(time.Hour)}, want: nil},
+		{name: "limit", filter: Filter{Limit: 1}, want: []string{"team delete"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range tt.filter.Apply(entries) {
+				got = append(got, e.Command)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppend_OutsideWorkspace(t *testing.T) {
+	root := t.TempDir()
+	if err := Append(root, Entry{ID: "x", Command: "sync"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if _, err := os.Stat(Path(root)); !os.IsNotExist(err) {
+		t.Errorf("Append() wrote an audit log outside a workspace: %v", err)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := []string{"team", "create", "--password", "hunter2", "--api-token=abc", "--event", "ctf", "--client-secret"}
+	want := []string{"team", "create", "--password", "***", "--api-token=***", "--event", "ctf", "--client-secret"}
+	if got := RedactArgs(args); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactArgs() = %v, want %v", got, want)
+	}
+	if args[3] != "hunter2" {
+		t.Error("RedactArgs() modified its input")
+	}
+}
@@ -0,0 +1,70 @@
+package gzcli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// RotatePassword rotates the password of the service account in
+// .gzctf/conf.yaml to newPassword. With changeOnServer, the password is
+// first changed on GZCTF, which invalidates every other session of the
+// account; otherwise it is assumed to be changed there already. Either way
+// the cached session is replaced by a fresh login and API access is verified
+// with the new password.
+func RotatePassword(newPassword string, changeOnServer bool) (*gzapi.Profile, error) {
+	if newPassword == "" {
+		return nil, fmt.Errorf("new password cannot be empty")
+	}
+
+	conf, err := config.GetServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	creds := conf.Creds
+	oldPassword := creds.Password
+
+	var api *gzapi.GZAPI
+	if changeOnServer {
+		api, err = gzapi.Init(conf.Url, &creds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to log in with the stored password (use --update-only if it was already changed): %w", err)
+		}
+	}
+
+	// Write the config first, so a server-side change is never left unrecorded
+	if err := config.SetServerPassword(newPassword); err != nil {
+		return nil, err
+	}
+
+	if changeOnServer {
+		if err := api.ChangePassword(newPassword); err != nil {
+			if restoreErr := config.SetServerPassword(oldPassword); restoreErr != nil {
+				log.Error("Failed to restore the previous password in conf.yaml: %v", restoreErr)
+			}
+			return nil, fmt.Errorf("failed to change password: %w", err)
+		}
+		log.Info("Password of %s changed on %s; other sessions are invalidated", creds.Username, conf.Url)
+	} else {
+		creds.Password = newPassword
+		if api, err = gzapi.Init(conf.Url, &creds); err != nil {
+			return nil, fmt.Errorf("failed to log in with the new password: %w", err)
+		}
+	}
+
+	if err := api.Relogin(); err != nil {
+		return nil, fmt.Errorf("failed to log in with the new password: %w", err)
+	}
+
+	profile, err := api.Profile()
+	if err != nil {
+		return nil, fmt.Errorf("API access check failed: %w", err)
+	}
+	if !strings.EqualFold(profile.UserName, creds.Username) {
+		return nil, fmt.Errorf("API access check failed: logged in as %s, expected %s", profile.UserName, creds.Username)
+	}
+	return profile, nil
+}
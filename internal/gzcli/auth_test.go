@@ -0,0 +1,109 @@
+package gzcli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// fakeAccountServer serves login, password change and profile for one
+// account whose password is *password. The password "weak" is rejected.
+func fakeAccountServer(t *testing.T, password *string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/account/login", func(w http.ResponseWriter, r *http.Request) {
+		var creds gzapi.Creds
+		_ = json.NewDecoder(r.Body).Decode(&creds)
+		if creds.Password != *password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: creds.Password, Path: "/"})
+		_, _ = w.Write([]byte(`{"succeeded": true}`))
+	})
+	mux.HandleFunc("/api/account/changepassword", func(w http.ResponseWriter, r *http.Request) {
+		var form gzapi.PasswordChangeForm
+		_ = json.NewDecoder(r.Body).Decode(&form)
+		if form.Old != *password || form.New == "weak" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*password = form.New
+	})
+	mux.HandleFunc("/api/account/profile", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != *password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"userName": "admin"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeServerConfig(t *testing.T, url, password string) {
+	t.Helper()
+	originalDir, _ := os.Getwd()
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	if err := os.MkdirAll(filepath.Join(dir, ".gzctf"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	conf := "url: " + url + "\ncreds:\n  username: admin\n  password: " + password + "\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gzctf", "conf.yaml"), []byte(conf), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotatePassword(t *testing.T) {
+	serverPassword := "old"
+	server := fakeAccountServer(t, &serverPassword)
+	writeServerConfig(t, server.URL, "old")
+
+	if _, err := RotatePassword("new", true); err != nil {
+		t.Fatalf("RotatePassword() error = %v", err)
+	}
+	if serverPassword != "new" {
+		t.Errorf("server password = %q, want new", serverPassword)
+	}
+	conf, err := config.GetServerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Creds.Password != "new" {
+		t.Errorf("stored password = %q, want new", conf.Creds.Password)
+	}
+
+	// A password changed elsewhere is only recorded
+	serverPassword = "from-ui"
+	if _, err := RotatePassword("from-ui", false); err != nil {
+		t.Fatalf("RotatePassword(update only) error = %v", err)
+	}
+	if conf, _ := config.GetServerConfig(); conf.Creds.Password != "from-ui" {
+		t.Errorf("stored password = %q, want from-ui", conf.Creds.Password)
+	}
+}
+
+func TestRotatePassword_RestoresConfigOnFailure(t *testing.T) {
+	serverPassword := "old"
+	server := fakeAccountServer(t, &serverPassword)
+	writeServerConfig(t, server.URL, "old")
+
+	if _, err := RotatePassword("weak", true); err == nil {
+		t.Fatal("RotatePassword() succeeded with a rejected password")
+	}
+	if conf, _ := config.GetServerConfig(); conf.Creds.Password != "old" {
+		t.Errorf("stored password = %q, want old", conf.Creds.Password)
+	}
+}
@@ -95,7 +95,56 @@ func (c *assetsCache) set(file gzapi.FileInfo) {
 	c.mu.Unlock()
 }
 
+// AttachmentStore remembers the hash of the local attachment last attached to
+// each challenge, so that syncs skip uploading an unchanged attachment
+type AttachmentStore interface {
+	// AttachmentHash returns the hash last attached to the challenge, or ""
+	AttachmentHash(challengeID int) string
+	// SetAttachmentHash records the hash attached to the challenge; "" forgets it
+	SetAttachmentHash(challengeID int, hash string)
+}
+
+// cachedAttachment is the cache record of cacheAttachmentStore
+type cachedAttachment struct {
+	ChallengeID int    `yaml:"challenge_id"`
+	Hash        string `yaml:"hash"`
+}
+
+// cacheAttachmentStore keeps the attachment hash of one challenge in the
+// gzcli cache
+type cacheAttachmentStore struct {
+	key      string
+	getCache func(string, interface{}) error
+	setCache func(string, interface{}) error
+}
+
+// buildAttachmentCacheKey constructs the attachment cache key of a challenge
+// Format: <eventname>/<category>/<challenge>/attachment
+func buildAttachmentCacheKey(eventName, category, challengeName string) string {
+	return fmt.Sprintf("%s/%s/%s/attachment", eventName, category, challengeName)
+}
+
+func (c cacheAttachmentStore) AttachmentHash(challengeID int) string {
+	var cached cachedAttachment
+	if err := c.getCache(c.key, &cached); err != nil || cached.ChallengeID != challengeID {
+		return ""
+	}
+	return cached.Hash
+}
+
+func (c cacheAttachmentStore) SetAttachmentHash(challengeID int, hash string) {
+	if err := c.setCache(c.key, cachedAttachment{ChallengeID: challengeID, Hash: hash}); err != nil {
+		log.Error("Failed to cache attachment hash: %v", err)
+	}
+}
+
 func HandleChallengeAttachments(challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
+	return handleChallengeAttachments(challengeConf, challengeData, api, nil)
+}
+
+// handleChallengeAttachments handles the attachment of a challenge, skipping
+// local attachments whose hash matches the one recorded in store
+func handleChallengeAttachments(challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI, store AttachmentStore) error {
 	log.DebugH3("Processing attachments for challenge: %s", challengeConf.Name)
 
 	switch {
@@ -118,9 +167,10 @@ func HandleChallengeAttachments(challengeConf config.ChallengeYaml, challengeDat
 				return fmt.Errorf("remote attachment creation failed for %s: %w", challengeConf.Name, err)
 			}
 			log.DebugH3("Successfully created remote attachment for %s", challengeConf.Name)
+			forgetAttachmentHash(store, challengeData.Id)
 		default:
 			log.DebugH3("Processing local attachment for %s: %s", challengeConf.Name, *challengeConf.Provide)
-			return HandleLocalAttachment(challengeConf, challengeData, api)
+			return handleLocalAttachment(challengeConf, challengeData, api, store)
 		}
 	case challengeData.Attachment != nil:
 		log.DebugH3("Removing existing attachment for %s", challengeConf.Name)
@@ -131,6 +181,7 @@ func HandleChallengeAttachments(challengeConf config.ChallengeYaml, challengeDat
 			return fmt.Errorf("attachment removal failed for %s: %w", challengeConf.Name, err)
 		}
 		log.DebugH3("Successfully removed attachment for %s", challengeConf.Name)
+		forgetAttachmentHash(store, challengeData.Id)
 	default:
 		log.DebugH3("No attachment processing needed for %s", challengeConf.Name)
 	}
@@ -139,7 +190,19 @@ func HandleChallengeAttachments(challengeConf config.ChallengeYaml, challengeDat
 	return nil
 }
 
+// forgetAttachmentHash clears the recorded hash once a challenge no longer
+// has a local attachment
+func forgetAttachmentHash(store AttachmentStore, challengeID int) {
+	if store != nil && store.AttachmentHash(challengeID) != "" {
+		store.SetAttachmentHash(challengeID, "")
+	}
+}
+
 func HandleLocalAttachment(challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
+	return handleLocalAttachment(challengeConf, challengeData, api, nil)
+}
+
+func handleLocalAttachment(challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI, store AttachmentStore) error {
 	log.DebugH3("Creating local attachment for %s", challengeConf.Name)
 
 	zipFilename := "dist.zip"
@@ -151,6 +214,10 @@ func HandleLocalAttachment(challengeConf config.ChallengeYaml, challengeData *gz
 	var artifactPath string
 	var artifactBase string
 
+	if err := checkAttachmentContent(challengeConf); err != nil {
+		return err
+	}
+
 	log.DebugH3("Checking attachment path: %s", attachmentPath)
 	if info, err := os.Stat(attachmentPath); err != nil || info.IsDir() {
 		log.DebugH3("Creating zip file for %s from: %s", challengeConf.Name, attachmentPath)
@@ -168,18 +235,40 @@ func HandleLocalAttachment(challengeConf config.ChallengeYaml, challengeData *gz
 		artifactBase = filepath.Base(attachmentPath)
 	}
 
+	if challengeConf.MaxAttachmentSize > 0 {
+		info, err := os.Stat(artifactPath)
+		if err != nil {
+			return fmt.Errorf("failed to stat attachment for %s: %w", challengeConf.Name, err)
+		}
+		if info.Size() > challengeConf.MaxAttachmentSize {
+			if artifactPath == zipOutput {
+				_ = os.Remove(zipOutput)
+			}
+			return fmt.Errorf("attachment for %s is %d bytes, exceeding the event limit of %d bytes", challengeConf.Name, info.Size(), challengeConf.MaxAttachmentSize)
+		}
+	}
+
 	artifactHash, err := fileutil.GetFileHashHex(artifactPath)
 	if err != nil {
 		return fmt.Errorf("failed to hash attachment for %s: %w", challengeConf.Name, err)
 	}
 
-	// Skip all copy/upload work when the challenge already points at the same file hash.
-	if challengeData.Attachment != nil && strings.Contains(challengeData.Attachment.Url, artifactHash) {
-		log.DebugH3("Attachment for %s is unchanged (hash: %s)", challengeConf.Name, artifactHash)
-		if strings.HasSuffix(zipOutput, ".zip") {
-			_ = os.Remove(zipOutput)
+	// Skip all copy/upload work when the challenge already points at the same
+	// file hash, or was last given this attachment by gzcli. Challenges listed
+	// by GZCTF come without their attachment, so the recorded hash is what
+	// usually spares the upload.
+	if !challengeConf.ForceUpload {
+		unchanged := challengeData.Attachment != nil && strings.Contains(challengeData.Attachment.Url, artifactHash)
+		if !unchanged && store != nil && store.AttachmentHash(challengeData.Id) == artifactHash {
+			unchanged = true
+		}
+		if unchanged {
+			log.DebugH3("Attachment for %s is unchanged (hash: %s)", challengeConf.Name, artifactHash)
+			if strings.HasSuffix(zipOutput, ".zip") {
+				_ = os.Remove(zipOutput)
+			}
+			return nil
 		}
-		return nil
 	}
 
 	// Create a unique attachment file name while preserving extension
@@ -207,7 +296,7 @@ func HandleLocalAttachment(challengeConf config.ChallengeYaml, challengeData *gz
 	log.DebugH3("Asset info for %s: Hash=%s, Name=%s", challengeConf.Name, fileinfo.Hash, fileinfo.Name)
 
 	// Check if the challenge already has the same attachment hash
-	if challengeData.Attachment != nil && strings.Contains(challengeData.Attachment.Url, fileinfo.Hash) {
+	if !challengeConf.ForceUpload && challengeData.Attachment != nil && strings.Contains(challengeData.Attachment.Url, fileinfo.Hash) {
 		log.DebugH3("Attachment for %s is unchanged (hash: %s)", challengeConf.Name, fileinfo.Hash)
 	} else {
 		var attachmentUrl string
@@ -230,6 +319,9 @@ func HandleLocalAttachment(challengeConf config.ChallengeYaml, challengeData *gz
 			log.DebugH3("Successfully created local attachment for %s", challengeConf.Name)
 		}
 	}
+	if store != nil {
+		store.SetAttachmentHash(challengeData.Id, artifactHash)
+	}
 
 	// Clean up temporary files
 	if strings.HasSuffix(zipOutput, ".zip") {
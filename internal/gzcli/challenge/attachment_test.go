@@ -538,3 +538,108 @@ func TestHandleLocalAttachment_ExistingFile(t *testing.T) {
 		t.Errorf("HandleLocalAttachment() with existing file error = %v, want nil", err)
 	}
 }
+
+// mapAttachmentStore is an in-memory AttachmentStore
+type mapAttachmentStore map[int]string
+
+func (m mapAttachmentStore) AttachmentHash(challengeID int) string { return m[challengeID] }
+
+func (m mapAttachmentStore) SetAttachmentHash(challengeID int, hash string) { m[challengeID] = hash }
+
+func TestHandleLocalAttachment_SkipsUnchangedHash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "dist.tar.gz"), []byte("attachment"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	provide := "dist.tar.gz"
+	challengeConf := config.ChallengeYaml{Name: "Hashed", Category: "Misc", Provide: &provide, Cwd: dir}
+
+	attached := 0
+	api, cleanup := mockGZAPI(t, map[string]http.HandlerFunc{
+		"/api/admin/files": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []gzapi.FileInfo{}})
+		},
+		"/api/assets": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode([]gzapi.FileInfo{{Hash: "uploaded", Name: "dist.tar.gz"}})
+		},
+		"/api/edit/games/5/challenges/9/attachment": func(w http.ResponseWriter, r *http.Request) {
+			attached++
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		},
+	})
+	defer cleanup()
+
+	store := mapAttachmentStore{}
+	attach := func(conf config.ChallengeYaml) {
+		t.Helper()
+		// Challenges listed by GZCTF come without their attachment
+		challengeData := &gzapi.Challenge{Id: 9, GameId: 5, Title: "Hashed", CS: api}
+		if err := handleChallengeAttachments(conf, challengeData, api, store); err != nil {
+			t.Fatalf("handleChallengeAttachments() error = %v", err)
+		}
+	}
+
+	attach(challengeConf)
+	if attached != 1 || store[9] == "" {
+		t.Fatalf("first sync attached %d time(s) and recorded %q, want 1 and the hash", attached, store[9])
+	}
+	attach(challengeConf)
+	if attached != 1 {
+		t.Errorf("unchanged attachment was attached again")
+	}
+
+	forced := challengeConf
+	forced.ForceUpload = true
+	attach(forced)
+	if attached != 2 {
+		t.Errorf("--force-upload did not attach the unchanged attachment")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "dist.tar.gz"), []byte("changed attachment"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	attach(challengeConf)
+	if attached != 3 {
+		t.Errorf("changed attachment was not attached")
+	}
+
+	// Hash forgotten, e.g. after the attachment was removed
+	store[9] = ""
+	attach(challengeConf)
+	if attached != 4 {
+		t.Errorf("attachment without a recorded hash was not attached")
+	}
+}
+
+func TestCacheAttachmentStore(t *testing.T) {
+	cache := map[string]cachedAttachment{}
+	store := cacheAttachmentStore{
+		key: buildAttachmentCacheKey("ctf", "Misc", "Hashed"),
+		getCache: func(key string, data interface{}) error {
+			cached, ok := cache[key]
+			if !ok {
+				return os.ErrNotExist
+			}
+			*data.(*cachedAttachment) = cached
+			return nil
+		},
+		setCache: func(key string, data interface{}) error {
+			cache[key] = data.(cachedAttachment)
+			return nil
+		},
+	}
+
+	if got := store.AttachmentHash(9); got != "" {
+		t.Errorf("AttachmentHash() before any upload = %q, want empty", got)
+	}
+	store.SetAttachmentHash(9, "abc")
+	if got := store.AttachmentHash(9); got != "abc" {
+		t.Errorf("AttachmentHash() = %q, want abc", got)
+	}
+	if got := store.AttachmentHash(10); got != "" {
+		t.Errorf("AttachmentHash() of a recreated challenge = %q, want empty", got)
+	}
+	if _, ok := cache["ctf/Misc/Hashed/attachment"]; !ok {
+		t.Errorf("hash cached under %v, want ctf/Misc/Hashed/attachment", cache)
+	}
+}
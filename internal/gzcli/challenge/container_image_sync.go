@@ -3,6 +3,7 @@ package challenge
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -107,15 +108,35 @@ func dockerPush(ctx context.Context, image string) error {
 	return runDocker(ctx, "", []string{"push", image}, "")
 }
 
+// dockerRepoDigest returns the digest the registry assigned to a pushed
+// image, as found in its RepoDigests for repository
+func dockerRepoDigest(ctx context.Context, image, repository string) (string, error) {
+	out, err := dockerOutputCommand(ctx, []string{"image", "inspect", "--format", "{{json .RepoDigests}}", image})
+	if err != nil {
+		return "", err
+	}
+	var repoDigests []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &repoDigests); err != nil {
+		return "", fmt.Errorf("unexpected docker image inspect output %q: %w", strings.TrimSpace(out), err)
+	}
+	for _, ref := range repoDigests {
+		if repo, digest, ok := strings.Cut(ref, "@"); ok && repo == repository {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("no digest of %s found for %s", repository, image)
+}
+
 type registryLoginState struct {
 	done bool
 	err  error
 }
 
 var (
-	registryLoginMu    sync.Mutex
-	registryLoginCache = make(map[string]registryLoginState)
-	runDockerCommand   = runDocker
+	registryLoginMu     sync.Mutex
+	registryLoginCache  = make(map[string]registryLoginState)
+	runDockerCommand    = runDocker
+	dockerOutputCommand = dockerOutput
 )
 
 const (
@@ -216,6 +237,21 @@ func runDocker(ctx context.Context, dir string, args []string, stdin string) err
 	return nil
 }
 
+// dockerOutput runs docker and returns its standard output
+func dockerOutput(ctx context.Context, args []string) (string, error) {
+	// #nosec G204 -- program is the hard-coded literal "docker"; args are
+	// assembled internally, as for runDocker.
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s failed: %w\n--- output ---\n%s",
+			strings.Join(args, " "), err, tailForDocker(stderr.String(), 200))
+	}
+	return stdout.String(), nil
+}
+
 func tailForDocker(s string, maxLines int) string {
 	if maxLines <= 0 || s == "" {
 		return ""
@@ -3,8 +3,12 @@ package challenge
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 )
 
 func resetRegistryLoginStateForTest() {
@@ -65,3 +69,61 @@ func TestDockerLoginOnce_DoesNotCacheFailure(t *testing.T) {
 		t.Fatalf("docker login calls = %d, want 2", got)
 	}
 }
+
+func TestDockerRepoDigest(t *testing.T) {
+	origDockerOutput := dockerOutputCommand
+	defer func() { dockerOutputCommand = origDockerOutput }()
+
+	dockerOutputCommand = func(_ context.Context, _ []string) (string, error) {
+		return `["mirror.example.com/web@sha256:aaa","registry.example.com/ctf/web@sha256:bbb"]` + "\n", nil
+	}
+
+	digest, err := dockerRepoDigest(context.Background(), "registry.example.com/ctf/web:latest", "registry.example.com/ctf/web")
+	if err != nil {
+		t.Fatalf("dockerRepoDigest() failed: %v", err)
+	}
+	if digest != "sha256:bbb" {
+		t.Errorf("digest = %q, want sha256:bbb", digest)
+	}
+
+	if _, err := dockerRepoDigest(context.Background(), "other:latest", "registry.example.com/ctf/other"); err == nil {
+		t.Error("expected an error when the repository has no digest")
+	}
+}
+
+func TestCheckContainerImage(t *testing.T) {
+	cwd := t.TempDir()
+	if err := os.Mkdir(filepath.Join(cwd, "src"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	blocked := errors.New("blocked")
+
+	tests := []struct {
+		name      string
+		typ       string
+		image     string
+		wantImage string
+	}{
+		{"pushed image", "DynamicContainer", "registry.example.com/ctf/web@sha256:abc", "registry.example.com/ctf/web@sha256:abc"},
+		{"local build directory", "DynamicContainer", "./src", ""},
+		{"static challenge", "StaticAttachment", "registry.example.com/ctf/web:1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked string
+			s := &SyncOrchestrator{challengeConf: config.ChallengeYaml{Type: tt.typ, Cwd: cwd}}
+			s.challengeConf.Container.ContainerImage = tt.image
+			s.SetImageCheck(func(image string) error {
+				checked = image
+				return blocked
+			})
+			err := s.checkContainerImage()
+			if checked != tt.wantImage {
+				t.Errorf("checked image = %q, want %q", checked, tt.wantImage)
+			}
+			if (err != nil) != (tt.wantImage != "") {
+				t.Errorf("checkContainerImage() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package challenge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// challengeFileNames are the names a challenge definition may have, in order
+// of preference
+var challengeFileNames = []string{"challenge.yaml", "challenge.yml"}
+
+// FindChallengeFile returns the challenge definition in dir, or "" if there is none
+func FindChallengeFile(dir string) string {
+	for _, name := range challengeFileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// yamlKeys describes the top-level keys of config.ChallengeYaml: their
+// canonical order and which of them hold strings or lists of strings
+var yamlKeys = func() (keys struct {
+	order   map[string]int
+	strings map[string]bool
+	lists   map[string]bool
+}) {
+	keys.order = map[string]int{}
+	keys.strings = map[string]bool{}
+	keys.lists = map[string]bool{}
+
+	t := reflect.TypeOf(config.ChallengeYaml{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		keys.order[name] = len(keys.order)
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.String:
+			keys.strings[name] = true
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.String:
+			keys.lists[name] = true
+		}
+	}
+	return keys
+}()
+
+// yamlEntry is a top-level key with its value lines and the comments leading it
+type yamlEntry struct {
+	key        string
+	lines      []string
+	keyLine    int  // Index of the key line in lines
+	blankAfter bool // Followed by a blank line in the source
+}
+
+// FormatYaml normalizes a challenge definition: top-level keys follow the
+// field order of the challenge schema (unknown keys last, in their original
+// order), string scalars are double-quoted, trailing whitespace outside
+// values is dropped and the file ends with a single newline. Comments and
+// multi-line values are kept as written. An error is returned when the
+// document cannot be formatted without changing what it decodes to.
+func FormatYaml(data []byte) ([]byte, error) {
+	var original interface{}
+	if err := yaml.Unmarshal(data, &original); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	header, entries, err := splitEntries(lines)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		normalizeEntry(e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return keyRank(entries[i].key) < keyRank(entries[j].key)
+	})
+
+	var out bytes.Buffer
+	for _, line := range trimBlankTail(header) {
+		out.WriteString(strings.TrimRight(line, " \t"))
+		out.WriteByte('\n')
+	}
+	if len(header) > 0 && len(entries) > 0 && isBlank(header[len(header)-1]) {
+		out.WriteByte('\n')
+	}
+	for i, e := range entries {
+		for _, line := range e.lines {
+			out.WriteString(line)
+			out.WriteByte('\n')
+		}
+		if e.blankAfter && i < len(entries)-1 {
+			out.WriteByte('\n')
+		}
+	}
+
+	var formatted interface{}
+	if err := yaml.Unmarshal(out.Bytes(), &formatted); err != nil || !reflect.DeepEqual(original, formatted) {
+		return nil, fmt.Errorf("formatting would change the document; format it by hand")
+	}
+	return out.Bytes(), nil
+}
+
+// FormatFile formats a challenge definition in place. It returns whether the
+// file was not formatted; with write unset the file is only checked.
+func FormatFile(path string, write bool) (bool, error) {
+	//nolint:gosec // G304: Reading challenge configuration files is intentional
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	formatted, err := FormatYaml(data)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(data, formatted) {
+		return false, nil
+	}
+	if write {
+		info, err := os.Stat(path)
+		if err != nil {
+			return true, err
+		}
+		if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func keyRank(key string) int {
+	if rank, ok := yamlKeys.order[key]; ok {
+		return rank
+	}
+	return len(yamlKeys.order)
+}
+
+// splitEntries splits a document into the comments heading it and its
+// top-level entries. Comment lines directly above a key lead that key.
+func splitEntries(lines []string) ([]string, []*yamlEntry, error) {
+	var (
+		header  []string
+		entries []*yamlEntry
+		current *yamlEntry
+		pending []string
+	)
+	flush := func(to *[]string) {
+		*to = append(*to, pending...)
+		pending = nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+		switch {
+		case trimmed == "---" || trimmed == "...":
+			return nil, nil, fmt.Errorf("multi-document YAML is not supported")
+		case isBlank(line) || strings.HasPrefix(line, "#"):
+			pending = append(pending, line)
+		case line[0] == ' ' || line[0] == '\t' || line[0] == '-':
+			if current == nil {
+				return nil, nil, fmt.Errorf("unexpected content before the first key: %q", line)
+			}
+			flush(&current.lines)
+			current.lines = append(current.lines, line)
+		default:
+			key, ok := topLevelKey(line)
+			if !ok {
+				return nil, nil, fmt.Errorf("unsupported top-level line: %q", line)
+			}
+			lead := len(pending)
+			for lead > 0 && !isBlank(pending[lead-1]) {
+				lead--
+			}
+			leading := append([]string(nil), pending[lead:]...)
+			pending = pending[:lead]
+			if current == nil {
+				flush(&header)
+			} else {
+				flush(&current.lines)
+			}
+			current = &yamlEntry{key: key, lines: append(leading, line), keyLine: len(leading)}
+			entries = append(entries, current)
+		}
+	}
+	if current == nil {
+		flush(&header)
+	} else {
+		flush(&current.lines)
+	}
+	return header, entries, nil
+}
+
+// topLevelKey returns the key of an unindented "key: value" line
+func topLevelKey(line string) (string, bool) {
+	if strings.ContainsRune("?[{&*!|>%@`", rune(line[0])) {
+		return "", false
+	}
+	idx := strings.Index(line, ":")
+	for idx >= 0 && idx+1 < len(line) && line[idx+1] != ' ' && line[idx+1] != '\t' {
+		next := strings.Index(line[idx+1:], ":")
+		if next < 0 {
+			return "", false
+		}
+		idx += next + 1
+	}
+	if idx <= 0 {
+		return "", false
+	}
+	key := strings.TrimSpace(line[:idx])
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		key = key[1 : len(key)-1]
+	}
+	return key, true
+}
+
+// normalizeEntry trims the entry's trailing blank lines and whitespace and
+// double-quotes its string scalars
+func normalizeEntry(e *yamlEntry) {
+	for len(e.lines) > e.keyLine+1 && isBlank(e.lines[len(e.lines)-1]) {
+		e.lines = e.lines[:len(e.lines)-1]
+		e.blankAfter = true
+	}
+	for i := 0; i <= e.keyLine; i++ {
+		e.lines[i] = strings.TrimRight(e.lines[i], " \t")
+	}
+
+	body := e.lines[e.keyLine+1:]
+	keyLine := e.lines[e.keyLine]
+	if yamlKeys.strings[e.key] && onlyComments(body) {
+		prefix, value := splitKeyLine(keyLine)
+		if quoted, ok := requote(value); ok {
+			e.lines[e.keyLine] = prefix + " " + quoted
+		}
+	}
+	if !yamlKeys.lists[e.key] {
+		return
+	}
+	for i, line := range body {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if !strings.HasPrefix(line[indent:], "- ") || !itemEnds(body[i+1:], indent) {
+			continue
+		}
+		if quoted, ok := requote(line[indent+2:]); ok {
+			body[i] = line[:indent] + "- " + quoted
+		}
+	}
+}
+
+// itemEnds reports whether a list item is not continued on the lines after it
+func itemEnds(rest []string, indent int) bool {
+	for _, line := range rest {
+		if isBlank(line) {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		return lineIndent <= indent
+	}
+	return true
+}
+
+func splitKeyLine(line string) (string, string) {
+	idx := strings.Index(line, ": ")
+	if idx < 0 {
+		return line, ""
+	}
+	return line[:idx+1], strings.TrimSpace(line[idx+2:])
+}
+
+// requote rewrites a single-line scalar, with an optional trailing comment,
+// as a double-quoted string. Scalars that are not strings, such as numbers,
+// booleans or block scalars, are left alone, and so are template actions,
+// whose own quotes must not be escaped before the template is executed.
+func requote(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.ContainsRune("|>&*![{#", rune(raw[0])) || strings.Contains(raw, "{{") {
+		return "", false
+	}
+
+	scalar, comment, ok := splitComment(raw)
+	if !ok {
+		return "", false
+	}
+
+	var decoded map[string]interface{}
+	if err := yaml.Unmarshal([]byte("v: "+scalar), &decoded); err != nil {
+		return "", false
+	}
+	value, isString := decoded["v"].(string)
+	if !isString {
+		return "", false
+	}
+
+	quoted := doubleQuote(value)
+	if comment != "" {
+		quoted += " " + comment
+	}
+	return quoted, true
+}
+
+// splitComment separates a scalar from a trailing "# comment"
+func splitComment(raw string) (scalar, comment string, ok bool) {
+	var end int
+	switch raw[0] {
+	case '"':
+		end = -1
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '\\' {
+				i++
+				continue
+			}
+			if raw[i] == '"' {
+				end = i + 1
+				break
+			}
+		}
+	case '\'':
+		end = -1
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '\'' {
+				if i+1 < len(raw) && raw[i+1] == '\'' {
+					i++
+					continue
+				}
+				end = i + 1
+				break
+			}
+		}
+	default:
+		end = len(raw)
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return "", "", false
+	}
+
+	scalar = strings.TrimSpace(raw[:end])
+	comment = strings.TrimSpace(raw[end:])
+	if comment != "" && !strings.HasPrefix(comment, "#") {
+		return "", "", false
+	}
+	return scalar, comment, true
+}
+
+// doubleQuote renders s as a YAML double-quoted scalar
+func doubleQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\x%02x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func onlyComments(lines []string) bool {
+	for _, line := range lines {
+		if !isBlank(line) && !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func trimBlankTail(lines []string) []string {
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
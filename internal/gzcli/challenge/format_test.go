@@ -0,0 +1,120 @@
+package challenge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatYaml(t *testing.T) {
+	input := `# header comment
+
+type: StaticAttachment   
+flags:
+- 'it''s'
+- plain # inline
+value: 100
+
+# about the author
+author: someone
+name: Demo
+custom: kept
+description: |
+  line one
+
+  line two
+`
+	want := `# header comment
+
+name: "Demo"
+# about the author
+author: "someone"
+description: |
+  line one
+
+  line two
+flags:
+- "it's"
+- "plain" # inline
+value: 100
+
+type: "StaticAttachment"
+custom: kept
+`
+	got, err := FormatYaml([]byte(input))
+	if err != nil {
+		t.Fatalf("FormatYaml() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("FormatYaml() =\n%s\nwant\n%s", got, want)
+	}
+
+	again, err := FormatYaml(got)
+	if err != nil || string(again) != string(got) {
+		t.Errorf("FormatYaml() is not idempotent: %v\n%s", err, again)
+	}
+}
+
+func TestFormatYaml_KeepsLeadingComments(t *testing.T) {
+	input := "value: 1\n# the name\nname: x\n"
+	got, err := FormatYaml([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# the name\nname: \"x\"\nvalue: 1\n"; string(got) != want {
+		t.Errorf("FormatYaml() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatYaml_LeavesNonStrings(t *testing.T) {
+	input := "name: yes\nauthor: \"{{ .host }}\"\nvisible: true\nhints:\n  - 123\n"
+	got, err := FormatYaml([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "name: yes\nauthor: \"{{ .host }}\"\nvisible: true\nhints:\n  - 123\n" {
+		t.Errorf("FormatYaml() changed non-string or templated scalars:\n%s", got)
+	}
+}
+
+func TestFormatYaml_Errors(t *testing.T) {
+	tests := map[string]string{
+		"invalid":        "name: [\n",
+		"multi-document": "name: a\n---\nname: b\n",
+		"flow mapping":   "{name: a}\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := FormatYaml([]byte(input)); err == nil {
+				t.Error("FormatYaml() should fail")
+			}
+		})
+	}
+}
+
+func TestFormatFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "challenge.yml")
+	if err := os.WriteFile(path, []byte("value: 1\nname: x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindChallengeFile(dir); got != path {
+		t.Fatalf("FindChallengeFile() = %q, want %q", got, path)
+	}
+
+	changed, err := FormatFile(path, false)
+	if err != nil || !changed {
+		t.Fatalf("FormatFile(check) = %v, %v; want unformatted", changed, err)
+	}
+	if data, _ := os.ReadFile(path); !strings.HasPrefix(string(data), "value") {
+		t.Error("FormatFile() in check mode should not write")
+	}
+
+	if changed, err := FormatFile(path, true); err != nil || !changed {
+		t.Fatalf("FormatFile(write) = %v, %v", changed, err)
+	}
+	if changed, err := FormatFile(path, false); err != nil || changed {
+		t.Errorf("FormatFile() after formatting = %v, %v; want formatted", changed, err)
+	}
+}
@@ -0,0 +1,108 @@
+package challenge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Scripts of challenge.yml run around each sync of the challenge
+const (
+	PreSyncScript  = "pre_sync"  // Before attachments are uploaded; failing aborts the sync
+	PostSyncScript = "post_sync" // After the challenge is updated; failing is only logged
+)
+
+// DefaultUpdateType is the UPDATE_TYPE of hooks of syncs that update
+// everything, such as those of "gzcli sync"
+const DefaultUpdateType = "full_redeploy"
+
+// SetUpdateType sets what changed in the challenge, passed to its hooks as
+// UPDATE_TYPE (default: full_redeploy)
+func (s *SyncOrchestrator) SetUpdateType(updateType string) {
+	s.updateType = updateType
+}
+
+// preSync runs the pre_sync hook once the challenge exists, so it can build
+// the attachment uploaded next
+func (s *SyncOrchestrator) preSync() error {
+	return s.runHook(PreSyncScript)
+}
+
+// postSync runs the post_sync hook. The challenge is synced by then, so a
+// failure is logged without failing the sync.
+func (s *SyncOrchestrator) postSync() error {
+	if err := s.runHook(PostSyncScript); err != nil {
+		log.Error("Hook %s of %s failed: %v", PostSyncScript, s.challengeConf.Name, err)
+	}
+	return nil
+}
+
+// runHook runs a hook script of the challenge from its directory
+func (s *SyncOrchestrator) runHook(script string) error {
+	scriptValue, exists := s.challengeConf.Scripts[script]
+	if !exists || scriptValue.GetCommand() == "" {
+		return nil
+	}
+	log.InfoH3("Running %s hook of %s", script, s.challengeConf.Name)
+	return runHookShell(scriptValue.GetCommand(), s.challengeConf.Cwd, s.hookEnv())
+}
+
+// hookEnv returns the variables describing the sync to its hooks
+func (s *SyncOrchestrator) hookEnv() []string {
+	updateType := s.updateType
+	if updateType == "" {
+		updateType = DefaultUpdateType
+	}
+	challengeID := ""
+	if s.challengeData != nil && s.challengeData.Id != 0 {
+		challengeID = strconv.Itoa(s.challengeData.Id)
+	}
+	url := ""
+	if s.api != nil {
+		url = s.api.Url
+	} else if s.conf != nil {
+		url = s.conf.Url
+	}
+	eventName := ""
+	if s.conf != nil {
+		eventName = s.conf.EventName
+	}
+	return []string{
+		"CHALLENGE_NAME=" + s.challengeConf.Name,
+		"CHALLENGE_CATEGORY=" + s.challengeConf.Category,
+		"CHALLENGE_ID=" + challengeID,
+		"EVENT=" + eventName,
+		"UPDATE_TYPE=" + updateType,
+		"GZCTF_URL=" + url,
+	}
+}
+
+// runHookShell runs a hook with env added to the environment, stopping it
+// after DefaultScriptTimeout
+//
+//nolint:gosec // G204: Script execution is the intended purpose of this function
+func runHookShell(script, cwd string, env []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultScriptTimeout)
+	defer cancel()
+
+	args := append(getShellArgs(), script)
+	cmd := exec.CommandContext(ctx, getShell(), args...)
+	cmd.Dir = cwd
+	cmd.Env = append(os.Environ(), env...)
+
+	var buf bytes.Buffer
+	writer := io.MultiWriter(os.Stdout, &buf)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command failed (cwd=%s): %w\n--- output tail ---\n%s", cwd, err, tailLines(buf.String(), 20))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package challenge
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestSyncOrchestrator_Hooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use a POSIX shell")
+	}
+	dir := t.TempDir()
+	challengeConf := config.ChallengeYaml{
+		Name:     "Web 1",
+		Category: "Web",
+		Cwd:      dir,
+		Scripts: map[string]config.ScriptValue{
+			PreSyncScript:  {Simple: `echo "$CHALLENGE_NAME|$CHALLENGE_ID|$EVENT|$UPDATE_TYPE|$GZCTF_URL" > pre.txt`},
+			PostSyncScript: {Simple: "exit 3"},
+		},
+	}
+	conf := &config.Config{EventName: "ctf2024", Url: "https://ctf.example.com"}
+	s := NewSyncOrchestrator(conf, challengeConf, nil, nil, nil, nil, nil)
+	s.challengeData = &gzapi.Challenge{Id: 42}
+
+	if err := s.preSync(); err != nil {
+		t.Fatalf("preSync() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "pre.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Web 1|42|ctf2024|full_redeploy|https://ctf.example.com"; strings.TrimSpace(string(got)) != want {
+		t.Errorf("pre_sync environment = %q, want %q", got, want)
+	}
+
+	s.SetUpdateType("metadata")
+	if env := s.hookEnv(); !strings.Contains(strings.Join(env, "\n"), "UPDATE_TYPE=metadata") {
+		t.Errorf("hookEnv() = %v, want UPDATE_TYPE=metadata", env)
+	}
+
+	if err := s.postSync(); err != nil {
+		t.Errorf("postSync() error = %v, want a failed post_sync hook only logged", err)
+	}
+
+	s.challengeConf.Scripts[PreSyncScript] = config.ScriptValue{Simple: "exit 1"}
+	if err := s.preSync(); err == nil {
+		t.Error("preSync() accepted a failed pre_sync hook")
+	}
+}
@@ -0,0 +1,497 @@
+package challenge
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// Lint severities, from most to least severe. Errors stop a challenge from
+// syncing or deploying correctly; warnings are likely mistakes.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Lint checks
+const (
+	LintParse      = "parse"
+	LintSchema     = "schema"
+	LintName       = "name"
+	LintCategory   = "category"
+	LintFlag       = "flag"
+	LintAttachment = "attachment"
+	LintCompose    = "compose"
+	LintPort       = "port"
+)
+
+// flagFormatRegex matches a whole flag of the prefix{...} form
+var flagFormatRegex = regexp.MustCompile(`^([A-Za-z0-9_]+)\{[^}]*\}$`)
+
+// composeFileNames are the compose files looked up in a challenge directory
+// and its src directory when the launcher does not name one
+var composeFileNames = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+// LintIssue is one problem found in a challenge
+type LintIssue struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+}
+
+// LintResult holds the issues of one challenge file, or of a directory of
+// the event when Challenge is empty
+type LintResult struct {
+	Challenge string      `json:"challenge,omitempty"`
+	Category  string      `json:"category,omitempty"`
+	Path      string      `json:"path"`
+	Issues    []LintIssue `json:"issues"`
+}
+
+// Count returns the number of issues of the given severity
+func (r LintResult) Count(severity string) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// LintFile is a challenge file to lint: its configuration with the event
+// defaults applied, or the error of loading it
+type LintFile struct {
+	Path      string
+	Category  string // Category directory, before normalization
+	Challenge config.ChallengeYaml
+	Err       error
+}
+
+// LintReport is the outcome of linting the challenges of an event
+type LintReport struct {
+	Event   string       `json:"event"`
+	Checked int          `json:"checked"` // Challenge files linted
+	Results []LintResult `json:"results"`
+}
+
+// Lint validates the challenge files of an event without contacting the
+// API: required fields and values (as IsGoodChallenge), duplicate names,
+// category normalization, flag formats, local attachments, launcher and
+// docker-compose files, and host ports published by several challenges.
+// unknownDirs are top-level directories holding challenges outside any
+// category (see config.UnknownCategoryDirs).
+func Lint(event string, files []LintFile, unknownDirs []string) *LintReport {
+	report := &LintReport{Event: event, Checked: len(files)}
+
+	for _, dir := range unknownDirs {
+		message := fmt.Sprintf("directory %s is not a category, its challenges are never synced", dir)
+		if category := matchCategory(dir); category != "" {
+			message += fmt.Sprintf(" (did you mean %s?)", category)
+		}
+		report.Results = append(report.Results, LintResult{
+			Path:   dir,
+			Issues: []LintIssue{{Severity: SeverityError, Check: LintCategory, Message: message}},
+		})
+	}
+
+	names := make(map[string]int)
+	prefixes := make(map[string]int)
+	var ports []publishedPort
+	for _, f := range files {
+		if f.Err == nil {
+			names[f.Challenge.Name]++
+			for _, prefix := range flagPrefixes(f.Challenge.Flags) {
+				prefixes[prefix]++
+			}
+		}
+	}
+	eventPrefix := mostCommon(prefixes)
+
+	results := make([]LintResult, len(files))
+	for i, f := range files {
+		result := LintResult{Challenge: f.Challenge.Name, Category: f.Challenge.Category, Path: f.Path}
+		add := func(severity, check, format string, args ...any) {
+			result.Issues = append(result.Issues, LintIssue{Severity: severity, Check: check, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if f.Err != nil {
+			add(SeverityError, LintParse, "%v", f.Err)
+			results[i] = result
+			continue
+		}
+		c := f.Challenge
+
+		for _, problem := range validationErrors(c) {
+			add(SeverityError, LintSchema, "%s", problem)
+		}
+		if c.Name != "" && names[c.Name] > 1 {
+			add(SeverityError, LintName, "%d challenges of the event are named %q", names[c.Name], c.Name)
+		}
+		if f.Category != "" && f.Category != c.Category {
+			add(SeverityInfo, LintCategory, "published in category %s as %q", c.Category, c.Name)
+		}
+		lintFlags(c, eventPrefix, add)
+		lintAttachment(c, add)
+
+		challengePorts, composeIssues := lintCompose(c)
+		result.Issues = append(result.Issues, composeIssues...)
+		for _, p := range challengePorts {
+			p.result = i
+			ports = append(ports, p)
+		}
+		results[i] = result
+	}
+
+	for _, collision := range portCollisions(ports) {
+		a, b := collision[0], collision[1]
+		for _, pair := range [][2]publishedPort{{a, b}, {b, a}} {
+			p, other := pair[0], pair[1]
+			where := "service " + other.service
+			if other.result != p.result {
+				where = fmt.Sprintf("%s (service %s)", results[other.result].Challenge, other.service)
+			}
+			results[p.result].Issues = append(results[p.result].Issues, LintIssue{
+				Severity: SeverityError,
+				Check:    LintPort,
+				Message:  fmt.Sprintf("service %s publishes host port %s, also published by %s", p.service, p.String(), where),
+			})
+		}
+	}
+
+	for i := range results {
+		sortIssues(results[i].Issues)
+	}
+	report.Results = append(report.Results, results...)
+	return report
+}
+
+// Count returns the number of issues of the given severity across the event
+func (r *LintReport) Count(severity string) int {
+	n := 0
+	for _, result := range r.Results {
+		n += result.Count(severity)
+	}
+	return n
+}
+
+// Failed reports whether the report has errors, or warnings when strict
+func (r *LintReport) Failed(strict bool) bool {
+	return r.Count(SeverityError) > 0 || (strict && r.Count(SeverityWarning) > 0)
+}
+
+// Print writes the issues of each challenge, then a summary. Challenges
+// without issues are listed only when verbose.
+func (r *LintReport) Print(w io.Writer, verbose bool) {
+	var b strings.Builder
+	for _, result := range r.Results {
+		if len(result.Issues) == 0 && !verbose {
+			continue
+		}
+		title := result.Path
+		if result.Challenge != "" {
+			title = fmt.Sprintf("%s (%s)", result.Challenge, result.Path)
+		}
+		if len(result.Issues) == 0 {
+			fmt.Fprintf(&b, "%s: ok\n", title)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n", title)
+		for _, issue := range result.Issues {
+			fmt.Fprintf(&b, "  %-7s %-11s %s\n", issue.Severity, issue.Check, issue.Message)
+		}
+	}
+	fmt.Fprintf(&b, "[%s] %d challenge(s) linted: %d error(s), %d warning(s)\n",
+		r.Event, r.Checked, r.Count(SeverityError), r.Count(SeverityWarning))
+	_, _ = io.WriteString(w, b.String())
+}
+
+// matchCategory returns the category differing from dir only in case
+func matchCategory(dir string) string {
+	for _, category := range config.CHALLENGE_CATEGORY {
+		if strings.EqualFold(category, dir) {
+			return category
+		}
+	}
+	return ""
+}
+
+// lintFlags checks static flags: whitespace, duplicates, the prefix{...}
+// form and a prefix shared with the rest of the event
+func lintFlags(c config.ChallengeYaml, eventPrefix string, add func(severity, check, format string, args ...any)) {
+	if c.Type == "DynamicContainer" && len(c.Flags) > 0 {
+		add(SeverityWarning, LintFlag, "flags are ignored by DynamicContainer challenges; the flag comes from container.flagTemplate")
+	}
+
+	seen := make(map[string]bool, len(c.Flags))
+	for _, flag := range c.Flags {
+		switch {
+		case strings.TrimSpace(flag) == "":
+			add(SeverityError, LintFlag, "empty flag")
+			continue
+		case strings.TrimSpace(flag) != flag:
+			add(SeverityError, LintFlag, "flag %q has leading or trailing whitespace", flag)
+		case flag == "flag{testing}":
+			add(SeverityWarning, LintFlag, "flag %q is the template default", flag)
+		}
+		if seen[flag] {
+			add(SeverityWarning, LintFlag, "flag %q is listed twice", flag)
+		}
+		seen[flag] = true
+		if !flagFormatRegex.MatchString(strings.TrimSpace(flag)) {
+			add(SeverityWarning, LintFlag, "flag %q does not match the prefix{...} format", flag)
+		}
+	}
+
+	prefixes := flagPrefixes(c.Flags)
+	if template := strings.TrimSpace(c.Container.FlagTemplate); template != "" && c.Type == "DynamicContainer" {
+		if m := flagFormatRegex.FindStringSubmatch(template); m != nil {
+			prefixes = append(prefixes, m[1])
+		}
+	}
+	slices.Sort(prefixes)
+	prefixes = slices.Compact(prefixes)
+	switch {
+	case len(prefixes) > 1:
+		add(SeverityWarning, LintFlag, "flags use different prefixes: %s", strings.Join(prefixes, ", "))
+	case len(prefixes) == 1 && eventPrefix != "" && prefixes[0] != eventPrefix:
+		add(SeverityWarning, LintFlag, "flag prefix %s differs from the event's %s", prefixes[0], eventPrefix)
+	}
+}
+
+// flagPrefixes returns the prefix of each flag of the prefix{...} form
+func flagPrefixes(flags []string) []string {
+	var prefixes []string
+	for _, flag := range flags {
+		if m := flagFormatRegex.FindStringSubmatch(strings.TrimSpace(flag)); m != nil {
+			prefixes = append(prefixes, m[1])
+		}
+	}
+	return prefixes
+}
+
+// mostCommon returns the key with the highest count, the smallest on ties
+func mostCommon(counts map[string]int) string {
+	best := ""
+	for key, n := range counts {
+		if n > counts[best] || (n == counts[best] && (best == "" || key < best)) {
+			best = key
+		}
+	}
+	return best
+}
+
+// lintAttachment checks that a local attachment exists
+func lintAttachment(c config.ChallengeYaml, add func(severity, check, format string, args ...any)) {
+	if c.Provide == nil || strings.HasPrefix(*c.Provide, "http") {
+		return
+	}
+	if strings.TrimSpace(*c.Provide) == "" {
+		add(SeverityError, LintAttachment, "provide is empty")
+		return
+	}
+	if _, err := os.Stat(filepath.Join(c.Cwd, *c.Provide)); err != nil {
+		add(SeverityError, LintAttachment, "attachment %s not found", *c.Provide)
+	}
+}
+
+// publishedPort is a host port published by a compose service
+type publishedPort struct {
+	result   int // Index of the challenge publishing it
+	service  string
+	hostIP   string // Empty for all interfaces
+	port     int
+	protocol string
+}
+
+func (p publishedPort) String() string {
+	s := strconv.Itoa(p.port) + "/" + p.protocol
+	if p.hostIP != "" {
+		s = p.hostIP + ":" + s
+	}
+	return s
+}
+
+// collides reports whether p and other cannot be bound at the same time
+func (p publishedPort) collides(other publishedPort) bool {
+	return p.port == other.port && p.protocol == other.protocol &&
+		(p.hostIP == "" || other.hostIP == "" || p.hostIP == other.hostIP)
+}
+
+// portCollisions returns the pairs of colliding published ports
+func portCollisions(ports []publishedPort) [][2]publishedPort {
+	var collisions [][2]publishedPort
+	for i := range ports {
+		for j := i + 1; j < len(ports); j++ {
+			if ports[i].collides(ports[j]) {
+				collisions = append(collisions, [2]publishedPort{ports[i], ports[j]})
+			}
+		}
+	}
+	return collisions
+}
+
+// composeFile is the subset of a docker-compose file checked by Lint
+type composeFile struct {
+	Services map[string]struct {
+		Ports []any `yaml:"ports"`
+	} `yaml:"services"`
+}
+
+// lintCompose checks the launcher configuration file and parses the
+// compose files of a challenge. It returns the fixed host ports published
+// by compose files the launcher does not start; those the launcher starts
+// get random host ports.
+func lintCompose(c config.ChallengeYaml) ([]publishedPort, []LintIssue) {
+	var issues []LintIssue
+	add := func(severity, format string, args ...any) {
+		issues = append(issues, LintIssue{Severity: severity, Check: LintCompose, Message: fmt.Sprintf(format, args...)})
+	}
+
+	launcherFile := ""
+	if c.Dashboard != nil && c.Dashboard.Config != "" {
+		launcherFile = filepath.Join(c.Cwd, c.Dashboard.Config)
+		if _, err := os.Stat(launcherFile); err != nil {
+			add(SeverityError, "launcher config %s not found", c.Dashboard.Config)
+			launcherFile = ""
+		}
+	}
+
+	var files []string
+	if launcherFile != "" && c.Dashboard.Type == "compose" {
+		files = append(files, launcherFile)
+	}
+	for _, dir := range []string{c.Cwd, filepath.Join(c.Cwd, "src")} {
+		for _, name := range composeFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil && !slices.Contains(files, path) {
+				files = append(files, path)
+			}
+		}
+	}
+
+	var ports []publishedPort
+	for _, path := range files {
+		rel, err := filepath.Rel(c.Cwd, path)
+		if err != nil {
+			rel = path
+		}
+		//nolint:gosec // G304: compose files of the challenge directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			add(SeverityError, "cannot read %s: %v", rel, err)
+			continue
+		}
+		var compose composeFile
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			add(SeverityError, "cannot parse %s: %v", rel, err)
+			continue
+		}
+		if len(compose.Services) == 0 {
+			add(SeverityWarning, "%s defines no services", rel)
+			continue
+		}
+		if path == launcherFile {
+			continue
+		}
+		services := make([]string, 0, len(compose.Services))
+		for name := range compose.Services {
+			services = append(services, name)
+		}
+		sort.Strings(services)
+		for _, name := range services {
+			for _, entry := range compose.Services[name].Ports {
+				published, err := parseComposePort(entry)
+				if err != nil {
+					add(SeverityWarning, "%s: service %s: %v", rel, name, err)
+					continue
+				}
+				for _, p := range published {
+					p.service = name
+					ports = append(ports, p)
+				}
+			}
+		}
+	}
+	return ports, issues
+}
+
+// parseComposePort returns the fixed host ports of a compose ports entry,
+// in short ("[ip:]host:container[/proto]") or long syntax. Entries without
+// a host port or with variables publish no fixed port.
+func parseComposePort(entry any) ([]publishedPort, error) {
+	switch v := entry.(type) {
+	case int:
+		return nil, nil
+	case string:
+		spec, protocol, found := strings.Cut(v, "/")
+		if !found {
+			protocol = "tcp"
+		}
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 {
+			return nil, nil
+		}
+		hostIP := strings.Trim(strings.Join(parts[:len(parts)-2], ":"), "[]")
+		return hostPorts(hostIP, parts[len(parts)-2], protocol)
+	case map[any]any:
+		published := fmt.Sprint(v["published"])
+		if v["published"] == nil {
+			return nil, nil
+		}
+		protocol := "tcp"
+		if p, ok := v["protocol"].(string); ok && p != "" {
+			protocol = p
+		}
+		hostIP, _ := v["host_ip"].(string)
+		return hostPorts(hostIP, published, protocol)
+	default:
+		return nil, fmt.Errorf("unsupported ports entry %v", entry)
+	}
+}
+
+// hostPorts expands a host port or port range of a compose ports entry
+func hostPorts(hostIP, spec, protocol string) ([]publishedPort, error) {
+	if spec == "" || strings.Contains(spec, "$") {
+		return nil, nil
+	}
+	if hostIP == "0.0.0.0" || hostIP == "::" {
+		hostIP = ""
+	}
+	first, last, isRange := strings.Cut(spec, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host port %q", spec)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(last); err != nil || end < start || end-start > 65535 {
+			return nil, fmt.Errorf("invalid host port range %q", spec)
+		}
+	}
+	ports := make([]publishedPort, 0, end-start+1)
+	for port := start; port <= end; port++ {
+		ports = append(ports, publishedPort{hostIP: hostIP, port: port, protocol: protocol})
+	}
+	return ports, nil
+}
+
+// severityRank orders severities from most to least severe
+var severityRank = map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+func sortIssues(issues []LintIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return severityRank[issues[i].Severity] < severityRank[issues[j].Severity]
+	})
+}
@@ -0,0 +1,167 @@
+package challenge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func lintChallenge(t *testing.T, name, category string, files map[string]string) config.ChallengeYaml {
+	t.Helper()
+	dir := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return config.ChallengeYaml{
+		Name:     name,
+		Author:   "alice",
+		Type:     "StaticAttachment",
+		Flags:    []string{"CTF{" + name + "}"},
+		Category: category,
+		Cwd:      dir,
+	}
+}
+
+// issues returns the "severity check" pairs of a result
+func issues(r LintResult) []string {
+	out := make([]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		out[i] = issue.Severity + " " + issue.Check + ": " + issue.Message
+	}
+	return out
+}
+
+func hasIssue(r LintResult, severity, check, substr string) bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == severity && issue.Check == check && strings.Contains(issue.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLint(t *testing.T) {
+	compose := func(port string) string {
+		return "services:\n  web:\n    image: nginx\n    ports:\n      - \"" + port + "\"\n"
+	}
+
+	clean := lintChallenge(t, "clean", "Web", map[string]string{"dist/a.txt": "a", "src/docker-compose.yml": compose("80")})
+	dist := "./dist"
+	clean.Provide = &dist
+
+	broken := lintChallenge(t, "broken", "Web", map[string]string{"docker-compose.yml": "services: [\n"})
+	broken.Author = ""
+	missing := "missing.zip"
+	broken.Provide = &missing
+	broken.Flags = []string{" CTF{x} ", "CTF{x}", "CTF{x}", "nope"}
+
+	portA := lintChallenge(t, "port-a", "Pwn", map[string]string{"src/docker-compose.yml": compose("9001:9001")})
+	portB := lintChallenge(t, "port-b", "Pwn", map[string]string{"compose.yaml": compose("0.0.0.0:9000-9002:9000-9002/tcp")})
+	launched := lintChallenge(t, "launched", "Pwn", map[string]string{"docker-compose.yml": compose("9001:9001")})
+	launched.Dashboard = &config.Dashboard{Type: "compose", Config: "docker-compose.yml"}
+
+	renamed := lintChallenge(t, "[Game Hacking] aimbot", "Reverse", nil)
+	renamed.Flags = []string{"OTHER{aimbot}"}
+
+	files := []LintFile{
+		{Path: "Web/clean/challenge.yml", Category: "Web", Challenge: clean},
+		{Path: "Web/broken/challenge.yml", Category: "Web", Challenge: broken},
+		{Path: "Pwn/a/challenge.yml", Category: "Pwn", Challenge: portA},
+		{Path: "Pwn/b/challenge.yml", Category: "Pwn", Challenge: portB},
+		{Path: "Pwn/launched/challenge.yml", Category: "Pwn", Challenge: launched},
+		{Path: "Game Hacking/aimbot/challenge.yml", Category: "Game Hacking", Challenge: renamed},
+		{Path: "Misc/bad/challenge.yml", Category: "Misc", Err: errors.New("yaml parse error")},
+	}
+	report := Lint("ctf", files, []string{"web", "notes"})
+
+	if len(report.Results) != 9 || report.Checked != 7 {
+		t.Fatalf("Lint() = %d results, %d checked; want 9, 7", len(report.Results), report.Checked)
+	}
+	byPath := make(map[string]LintResult)
+	for _, r := range report.Results {
+		byPath[r.Path] = r
+	}
+
+	if r := byPath["web"]; !hasIssue(r, SeverityError, LintCategory, "did you mean Web?") {
+		t.Errorf("web directory issues = %v", issues(r))
+	}
+	if r := byPath["notes"]; !hasIssue(r, SeverityError, LintCategory, "not a category") || hasIssue(r, SeverityError, LintCategory, "did you mean") {
+		t.Errorf("notes directory issues = %v", issues(r))
+	}
+	if r := byPath["Web/clean/challenge.yml"]; len(r.Issues) != 0 {
+		t.Errorf("clean challenge issues = %v", issues(r))
+	}
+
+	brokenResult := byPath["Web/broken/challenge.yml"]
+	for _, want := range []struct{ severity, check, substr string }{
+		{SeverityError, LintSchema, "missing author"},
+		{SeverityError, LintFlag, "leading or trailing whitespace"},
+		{SeverityWarning, LintFlag, "listed twice"},
+		{SeverityWarning, LintFlag, `"nope" does not match`},
+		{SeverityError, LintAttachment, "missing.zip not found"},
+		{SeverityError, LintCompose, "cannot parse docker-compose.yml"},
+	} {
+		if !hasIssue(brokenResult, want.severity, want.check, want.substr) {
+			t.Errorf("broken challenge is missing %s %s %q: %v", want.severity, want.check, want.substr, issues(brokenResult))
+		}
+	}
+	if brokenResult.Issues[len(brokenResult.Issues)-1].Severity != SeverityWarning {
+		t.Errorf("issues are not sorted by severity: %v", issues(brokenResult))
+	}
+
+	if r := byPath["Pwn/a/challenge.yml"]; !hasIssue(r, SeverityError, LintPort, "9001/tcp, also published by port-b (service web)") {
+		t.Errorf("port-a issues = %v", issues(r))
+	}
+	if r := byPath["Pwn/b/challenge.yml"]; !hasIssue(r, SeverityError, LintPort, "also published by port-a") || r.Count(SeverityError) != 1 {
+		t.Errorf("port-b issues = %v", issues(r))
+	}
+	if r := byPath["Pwn/launched/challenge.yml"]; len(r.Issues) != 0 {
+		t.Errorf("launcher compose ports are randomized and must not collide: %v", issues(r))
+	}
+
+	renamedResult := byPath["Game Hacking/aimbot/challenge.yml"]
+	if !hasIssue(renamedResult, SeverityInfo, LintCategory, "published in category Reverse") ||
+		!hasIssue(renamedResult, SeverityWarning, LintFlag, "prefix OTHER differs from the event's CTF") {
+		t.Errorf("renamed challenge issues = %v", issues(renamedResult))
+	}
+	if r := byPath["Misc/bad/challenge.yml"]; !hasIssue(r, SeverityError, LintParse, "yaml parse error") {
+		t.Errorf("unparsable challenge issues = %v", issues(r))
+	}
+
+	if !report.Failed(false) {
+		t.Error("Failed() = false with errors")
+	}
+	var out strings.Builder
+	report.Print(&out, false)
+	if !strings.Contains(out.String(), "[ctf] 7 challenge(s) linted") || strings.Contains(out.String(), "clean") {
+		t.Errorf("Print() = %s", out.String())
+	}
+}
+
+func TestLintReport_Failed(t *testing.T) {
+	warned := LintReport{Results: []LintResult{{Issues: []LintIssue{{Severity: SeverityWarning}, {Severity: SeverityInfo}}}}}
+	if warned.Failed(false) || !warned.Failed(true) {
+		t.Errorf("Failed() with warnings = %v, strict %v; want false, true", warned.Failed(false), warned.Failed(true))
+	}
+}
+
+func TestLint_DuplicateNames(t *testing.T) {
+	a := lintChallenge(t, "same", "Web", nil)
+	b := lintChallenge(t, "same", "Crypto", nil)
+	report := Lint("ctf", []LintFile{{Path: "a", Challenge: a}, {Path: "b", Challenge: b}}, nil)
+	for _, r := range report.Results {
+		if !hasIssue(r, SeverityError, LintName, `2 challenges of the event are named "same"`) {
+			t.Errorf("%s issues = %v", r.Path, issues(r))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package challenge
+
+import (
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Sync plan actions
+const (
+	PlanActionCreate = "create"
+	PlanActionUpdate = "update"
+	PlanActionNone   = "none"
+)
+
+// SyncPlan describes what a sync would change on the remote challenge without
+// performing any API mutation. It is used by dry-run modes.
+type SyncPlan struct {
+	Challenge     string   `json:"challenge"`
+	Action        string   `json:"action"`
+	ChallengeID   int      `json:"challenge_id,omitempty"`
+	Diff          string   `json:"diff,omitempty"`
+	FlagsToAdd    []string `json:"flags_to_add,omitempty"`
+	FlagsToRemove []string `json:"flags_to_remove,omitempty"`
+	TagsToAdd     []string `json:"tags_to_add,omitempty"`
+	TagsToRemove  []string `json:"tags_to_remove,omitempty"`
+}
+
+// HasChanges reports whether applying the plan would modify the remote challenge
+func (p *SyncPlan) HasChanges() bool {
+	return p.Action != PlanActionNone
+}
+
+// PlanSync computes the changes SyncChallenge would apply for challengeConf.
+// If existing is nil, the challenge is looked up by title in challenges.
+// Only the provided data and local files are inspected; no API requests are
+// made.
+func PlanSync(challengeConf config.ChallengeYaml, challenges []gzapi.Challenge, existing *gzapi.Challenge) *SyncPlan {
+	_, normalizedName := config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
+	plan := &SyncPlan{Challenge: normalizedName}
+
+	remote := existing
+	if remote == nil {
+		remote = findChallengeByTitle(challenges, challengeConf.Name)
+	}
+
+	var current gzapi.Challenge
+	if remote != nil {
+		current = *remote
+		current.IsEnabled = nil
+		plan.ChallengeID = remote.Id
+	}
+
+	if err := applyReadmeDescription(&challengeConf, plannedReadmeAssetURL); err != nil {
+		log.Error("Failed to render README of %s: %v", challengeConf.Name, err)
+	}
+
+	desired := current
+	MergeChallengeData(&challengeConf, &desired)
+
+	plan.Diff = cmp.Diff(toComparableChallenge(current), toComparableChallenge(desired))
+	plan.FlagsToAdd, plan.FlagsToRemove = diffFlags(challengeConf.Flags, current.Flags)
+	if remote != nil {
+		plan.TagsToAdd, plan.TagsToRemove = diffTags(challengeConf.Tags, ParseTags(current.Content))
+	}
+
+	switch {
+	case remote == nil:
+		plan.Action = PlanActionCreate
+	case plan.Diff != "" || len(plan.FlagsToAdd) > 0 || len(plan.FlagsToRemove) > 0:
+		plan.Action = PlanActionUpdate
+	default:
+		plan.Action = PlanActionNone
+	}
+
+	return plan
+}
+
+// diffFlags returns the flags that must be created and deleted to reach the desired set
+func diffFlags(desired []string, existing []gzapi.Flag) (toAdd []string, toRemove []string) {
+	desiredSet := make(map[string]struct{}, len(desired))
+	for _, f := range desired {
+		desiredSet[f] = struct{}{}
+	}
+	existingSet := make(map[string]struct{}, len(existing))
+	for _, f := range existing {
+		existingSet[f.Flag] = struct{}{}
+		if _, keep := desiredSet[f.Flag]; !keep {
+			toRemove = append(toRemove, f.Flag)
+		}
+	}
+	for f := range desiredSet {
+		if _, ok := existingSet[f]; !ok {
+			toAdd = append(toAdd, f)
+		}
+	}
+	sort.Strings(toAdd)
+	sort.Strings(toRemove)
+	return toAdd, toRemove
+}
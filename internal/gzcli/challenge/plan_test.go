@@ -0,0 +1,92 @@
+package challenge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestPlanSync_NewChallenge(t *testing.T) {
+	conf := config.ChallengeYaml{
+		Name:     "Fresh",
+		Category: "Web",
+		Type:     "StaticAttachment",
+		Value:    500,
+		Flags:    []string{"flag{a}"},
+	}
+
+	plan := PlanSync(conf, nil, nil)
+	if plan.Action != PlanActionCreate {
+		t.Fatalf("Action = %q, want %q", plan.Action, PlanActionCreate)
+	}
+	if !plan.HasChanges() {
+		t.Error("HasChanges() = false, want true")
+	}
+	if !reflect.DeepEqual(plan.FlagsToAdd, []string{"flag{a}"}) {
+		t.Errorf("FlagsToAdd = %v, want [flag{a}]", plan.FlagsToAdd)
+	}
+	if plan.Diff == "" {
+		t.Error("expected a non-empty diff for a new challenge")
+	}
+}
+
+func TestPlanSync_UpToDate(t *testing.T) {
+	conf := config.ChallengeYaml{
+		Name:        "Same",
+		Author:      "alice",
+		Description: "desc",
+		Category:    "Misc",
+		Type:        "StaticAttachment",
+		Value:       100,
+		Flags:       []string{"flag{same}"},
+	}
+
+	remote := gzapi.Challenge{Id: 7, Flags: []gzapi.Flag{{Id: 1, Flag: "flag{same}"}}}
+	MergeChallengeData(&conf, &remote)
+
+	plan := PlanSync(conf, []gzapi.Challenge{remote}, nil)
+	if plan.Action != PlanActionNone {
+		t.Fatalf("Action = %q, want %q (diff: %s)", plan.Action, PlanActionNone, plan.Diff)
+	}
+	if plan.ChallengeID != 7 {
+		t.Errorf("ChallengeID = %d, want 7", plan.ChallengeID)
+	}
+}
+
+func TestPlanSync_UpdateWithFlagChanges(t *testing.T) {
+	conf := config.ChallengeYaml{
+		Name:     "Changed",
+		Category: "Pwn",
+		Type:     "StaticContainer",
+		Value:    300,
+		Flags:    []string{"flag{new}"},
+	}
+
+	remote := gzapi.Challenge{
+		Id:            3,
+		Title:         "Changed",
+		Category:      "Pwn",
+		OriginalScore: 100,
+		Flags:         []gzapi.Flag{{Id: 9, Flag: "flag{old}"}},
+	}
+
+	plan := PlanSync(conf, nil, &remote)
+	if plan.Action != PlanActionUpdate {
+		t.Fatalf("Action = %q, want %q", plan.Action, PlanActionUpdate)
+	}
+	if !strings.Contains(plan.Diff, "OriginalScore") {
+		t.Errorf("diff should mention OriginalScore, got: %s", plan.Diff)
+	}
+	if !reflect.DeepEqual(plan.FlagsToAdd, []string{"flag{new}"}) {
+		t.Errorf("FlagsToAdd = %v", plan.FlagsToAdd)
+	}
+	if !reflect.DeepEqual(plan.FlagsToRemove, []string{"flag{old}"}) {
+		t.Errorf("FlagsToRemove = %v", plan.FlagsToRemove)
+	}
+	if remote.OriginalScore != 100 {
+		t.Error("PlanSync must not mutate the existing challenge")
+	}
+}
@@ -0,0 +1,292 @@
+package challenge
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+)
+
+// Attachment policy rules, set by the `defaults` block of .gzevent
+const (
+	PolicyMaxSize            = "size"
+	PolicyForbiddenExtension = "extension"
+	PolicyReadme             = "readme"
+)
+
+// PolicyRules are the rule names accepted by PolicyOverrides
+var PolicyRules = []string{PolicyMaxSize, PolicyForbiddenExtension, PolicyReadme}
+
+// PolicyViolation is a local attachment breaking a rule of its event's
+// attachment policy
+type PolicyViolation struct {
+	Challenge  string `json:"challenge"`
+	Rule       string `json:"rule"`
+	Detail     string `json:"detail"`
+	Overridden bool   `json:"overridden,omitempty"`
+}
+
+// PolicyOverrides exempt challenges from the attachment policy for
+// exceptional cases: whole challenges by name, or single rules for all
+// challenges
+type PolicyOverrides struct {
+	Challenges []string
+	Rules      []string
+}
+
+// Validate checks that the overridden rules exist
+func (o PolicyOverrides) Validate() error {
+	for _, rule := range o.Rules {
+		if !slices.Contains(PolicyRules, rule) {
+			return fmt.Errorf("unknown attachment policy rule %q (valid: %s)", rule, strings.Join(PolicyRules, ", "))
+		}
+	}
+	return nil
+}
+
+func (o PolicyOverrides) exempts(challenge, rule string) bool {
+	return slices.Contains(o.Challenges, challenge) || slices.Contains(o.Rules, rule)
+}
+
+// Apply lifts the overridden rules from a challenge, so attachment uploads no
+// longer enforce them
+func (o PolicyOverrides) Apply(conf config.ChallengeYaml) config.ChallengeYaml {
+	if o.exempts(conf.Name, PolicyMaxSize) {
+		conf.MaxAttachmentSize = 0
+	}
+	if o.exempts(conf.Name, PolicyForbiddenExtension) {
+		conf.ForbiddenExtensions = nil
+	}
+	if o.exempts(conf.Name, PolicyReadme) {
+		conf.RequireReadme = false
+	}
+	return conf
+}
+
+// PolicyReport is the consolidated attachment policy check of an event
+type PolicyReport struct {
+	Checked    int               `json:"checked"`
+	Violations []PolicyViolation `json:"violations,omitempty"`
+}
+
+// CheckPolicy checks the local attachments of all challenges against their
+// attachment policy. Violations exempted by overrides are reported but do
+// not fail the report.
+func CheckPolicy(challengesConf []config.ChallengeYaml, overrides PolicyOverrides) *PolicyReport {
+	report := &PolicyReport{}
+	for _, conf := range challengesConf {
+		report.Checked++
+		for _, v := range CheckAttachmentPolicy(conf) {
+			v.Overridden = overrides.exempts(v.Challenge, v.Rule)
+			report.Violations = append(report.Violations, v)
+		}
+	}
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		return report.Violations[i].Challenge < report.Violations[j].Challenge
+	})
+	return report
+}
+
+// Blocking returns the violations that are not overridden
+func (r *PolicyReport) Blocking() []PolicyViolation {
+	var blocking []PolicyViolation
+	for _, v := range r.Violations {
+		if !v.Overridden {
+			blocking = append(blocking, v)
+		}
+	}
+	return blocking
+}
+
+// Err returns an error when violations are not overridden
+func (r *PolicyReport) Err() error {
+	if n := len(r.Blocking()); n > 0 {
+		return fmt.Errorf("%d attachment policy violation(s); fix them or override with --policy-exempt/--policy-skip", n)
+	}
+	return nil
+}
+
+// Print writes a human-readable summary of the report
+func (r *PolicyReport) Print(w io.Writer) {
+	var b strings.Builder
+	if len(r.Violations) == 0 {
+		fmt.Fprintf(&b, "Attachment policy: %d challenge(s) checked, no violations\n", r.Checked)
+	} else {
+		fmt.Fprintf(&b, "Attachment policy: %d challenge(s) checked, %d violation(s), %d blocking\n", r.Checked, len(r.Violations), len(r.Blocking()))
+	}
+	for _, v := range r.Violations {
+		status := "FAIL"
+		if v.Overridden {
+			status = "skip"
+		}
+		fmt.Fprintf(&b, "  %-4s %-40s %-9s %s\n", status, v.Challenge, v.Rule, v.Detail)
+	}
+	_, _ = io.WriteString(w, b.String())
+}
+
+// CheckAttachmentPolicy checks the local attachment of a challenge against
+// the size limit, forbidden extensions and README requirement of its event.
+// Challenges without a local attachment pass. Directories are measured at
+// their zipped size, as uploaded.
+func CheckAttachmentPolicy(conf config.ChallengeYaml) []PolicyViolation {
+	if conf.Provide == nil || strings.HasPrefix(*conf.Provide, "http") {
+		return nil
+	}
+	if conf.MaxAttachmentSize == 0 && len(conf.ForbiddenExtensions) == 0 && !conf.RequireReadme {
+		return nil
+	}
+
+	violation := func(rule, format string, args ...any) PolicyViolation {
+		return PolicyViolation{Challenge: conf.Name, Rule: rule, Detail: fmt.Sprintf(format, args...)}
+	}
+
+	attachment := filepath.Join(conf.Cwd, *conf.Provide)
+	info, err := os.Stat(attachment)
+	if err != nil {
+		return nil // Reported by the sync itself
+	}
+
+	var violations []PolicyViolation
+	names, err := attachmentContents(attachment, info)
+	if err != nil {
+		return []PolicyViolation{violation(PolicyForbiddenExtension, "cannot inspect attachment: %v", err)}
+	}
+	var reported []string
+	for _, name := range names {
+		// Report a forbidden directory once, not every file inside it
+		if slices.ContainsFunc(reported, func(dir string) bool { return strings.HasPrefix(name, dir+"/") }) {
+			continue
+		}
+		if ext := forbiddenExtension(name, conf.ForbiddenExtensions); ext != "" {
+			reported = append(reported, name)
+			violations = append(violations, violation(PolicyForbiddenExtension, "%s has forbidden extension %s", name, ext))
+		}
+	}
+	if conf.RequireReadme && !slices.ContainsFunc(names, isReadme) {
+		violations = append(violations, violation(PolicyReadme, "no README at the top level of %s", *conf.Provide))
+	}
+
+	if conf.MaxAttachmentSize > 0 {
+		size, err := attachmentSize(attachment, info, conf.MaxAttachmentSize)
+		if err != nil {
+			violations = append(violations, violation(PolicyMaxSize, "cannot measure attachment: %v", err))
+		} else if size > conf.MaxAttachmentSize {
+			violations = append(violations, violation(PolicyMaxSize, "%s exceeds the limit of %s", FormatBytes(size), FormatBytes(conf.MaxAttachmentSize)))
+		}
+	}
+	return violations
+}
+
+// checkAttachmentContent enforces the forbidden extensions and README
+// requirement before an attachment is uploaded
+func checkAttachmentContent(conf config.ChallengeYaml) error {
+	limitless := conf
+	limitless.MaxAttachmentSize = 0 // Checked on the zipped artifact
+	violations := CheckAttachmentPolicy(limitless)
+	if len(violations) == 0 {
+		return nil
+	}
+	details := make([]string, len(violations))
+	for i, v := range violations {
+		details[i] = v.Detail
+	}
+	return fmt.Errorf("attachment for %s breaks the event policy: %s", conf.Name, strings.Join(details, "; "))
+}
+
+// attachmentContents lists the slash-separated paths of the files an
+// attachment hands to players: the files of a directory, the entries of a
+// zip archive, or the file itself
+func attachmentContents(attachment string, info fs.FileInfo) ([]string, error) {
+	if !info.IsDir() {
+		if !strings.EqualFold(filepath.Ext(attachment), ".zip") {
+			return []string{info.Name()}, nil
+		}
+		archive, err := zip.OpenReader(attachment)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = archive.Close() }()
+		names := make([]string, 0, len(archive.File))
+		for _, f := range archive.File {
+			names = append(names, strings.TrimSuffix(f.Name, "/"))
+		}
+		return names, nil
+	}
+
+	var names []string
+	err := filepath.WalkDir(attachment, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == attachment {
+			return nil
+		}
+		rel, err := filepath.Rel(attachment, p)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	return names, err
+}
+
+// attachmentSize returns the uploaded size of an attachment. Directories
+// are only zipped when their uncompressed size is above limit.
+func attachmentSize(attachment string, info fs.FileInfo, limit int64) (int64, error) {
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+	size, err := dirSize(attachment)
+	if err != nil || size <= limit {
+		return size, err
+	}
+
+	tmp, err := os.CreateTemp("", "gzcli-policy-*.zip")
+	if err != nil {
+		return 0, err
+	}
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := fileutil.ZipSource(attachment, tmp.Name()); err != nil {
+		return 0, err
+	}
+	zipped, err := os.Stat(tmp.Name())
+	if err != nil {
+		return 0, err
+	}
+	return zipped.Size(), nil
+}
+
+// forbiddenExtension returns the forbidden extension name ends with, also
+// matching directories such as ".git" anywhere in the path
+func forbiddenExtension(name string, forbidden []string) string {
+	for _, part := range strings.Split(name, "/") {
+		lower := strings.ToLower(part)
+		for _, ext := range forbidden {
+			if strings.HasSuffix(lower, ext) {
+				return ext
+			}
+		}
+	}
+	return ""
+}
+
+// isReadme reports whether name is a README at the top level, e.g.
+// README, README.md or readme.txt
+func isReadme(name string) bool {
+	if strings.Contains(name, "/") {
+		return false
+	}
+	base := strings.ToLower(name)
+	return strings.TrimSuffix(base, path.Ext(base)) == "readme"
+}
@@ -0,0 +1,135 @@
+package challenge
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func rules(violations []PolicyViolation) []string {
+	var out []string
+	for _, v := range violations {
+		out = append(out, v.Rule)
+	}
+	return out
+}
+
+func TestCheckAttachmentPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"good/dist/README.md":    "read me",
+		"good/dist/chall":        "binary",
+		"bad/dist/chall":         "binary",
+		"bad/dist/.git/HEAD":     "ref: refs/heads/main",
+		"bad/dist/conf/prod.ENV": "SECRET=1",
+		"single/dist.tar.gz":     strings.Repeat("x", 512),
+	})
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, name := range []string{"readme.txt", "notes/key.pem"} {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeTree(t, dir, map[string]string{"zipped/dist.zip": archive.String()})
+
+	provide := func(p string) *string { return &p }
+	policy := func(name, cwd, attachment string) config.ChallengeYaml {
+		return config.ChallengeYaml{
+			Name:                name,
+			Cwd:                 filepath.Join(dir, cwd),
+			Provide:             provide(attachment),
+			MaxAttachmentSize:   300,
+			ForbiddenExtensions: []string{".env", ".git", ".pem"},
+			RequireReadme:       true,
+		}
+	}
+
+	tests := []struct {
+		name string
+		conf config.ChallengeYaml
+		want []string
+	}{
+		{name: "compliant directory", conf: policy("good", "good", "./dist"), want: nil},
+		{name: "forbidden files", conf: policy("bad", "bad", "./dist"), want: []string{PolicyForbiddenExtension, PolicyForbiddenExtension, PolicyReadme}},
+		{name: "oversized single file", conf: policy("single", "single", "dist.tar.gz"), want: []string{PolicyReadme, PolicyMaxSize}},
+		{name: "zip entries", conf: policy("zipped", "zipped", "dist.zip"), want: []string{PolicyForbiddenExtension}},
+		{name: "remote attachment", conf: policy("remote", "good", "https://example.com/dist.zip"), want: nil},
+		{name: "no policy", conf: config.ChallengeYaml{Name: "free", Cwd: filepath.Join(dir, "bad"), Provide: provide("./dist")}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules(CheckAttachmentPolicy(tt.conf))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("CheckAttachmentPolicy() rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPolicy_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a/dist/chall": "binary",
+		"b/dist/.env":  "SECRET=1",
+	})
+	provide := "./dist"
+	confs := []config.ChallengeYaml{
+		{Name: "B", Cwd: filepath.Join(dir, "b"), Provide: &provide, ForbiddenExtensions: []string{".env"}, RequireReadme: true},
+		{Name: "A", Cwd: filepath.Join(dir, "a"), Provide: &provide, RequireReadme: true},
+	}
+
+	report := CheckPolicy(confs, PolicyOverrides{})
+	if report.Checked != 2 || len(report.Violations) != 3 || report.Violations[0].Challenge != "A" {
+		t.Fatalf("CheckPolicy() = %+v, want 3 violations sorted by challenge", report)
+	}
+	if report.Err() == nil {
+		t.Error("Err() = nil with blocking violations")
+	}
+
+	overrides := PolicyOverrides{Challenges: []string{"B"}, Rules: []string{PolicyReadme}}
+	if err := overrides.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	report = CheckPolicy(confs, overrides)
+	if len(report.Violations) != 3 || len(report.Blocking()) != 0 || report.Err() != nil {
+		t.Errorf("CheckPolicy() with overrides = %+v, want every violation overridden", report)
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	if !strings.Contains(out.String(), "0 blocking") || !strings.Contains(out.String(), "skip") {
+		t.Errorf("Print() = %q", out.String())
+	}
+
+	applied := overrides.Apply(confs[0])
+	if applied.ForbiddenExtensions != nil || applied.RequireReadme {
+		t.Errorf("Apply() left the policy of an exempt challenge: %+v", applied)
+	}
+	if err := (PolicyOverrides{Rules: []string{"banana"}}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown rule")
+	}
+}
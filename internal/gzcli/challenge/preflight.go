@@ -0,0 +1,188 @@
+package challenge
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// SyncEstimate is the preflight estimate of the API calls and upload bytes a
+// sync of an event will need
+type SyncEstimate struct {
+	Challenges  []ChallengeEstimate `json:"challenges"`
+	APICalls    int                 `json:"api_calls"`
+	UploadBytes int64               `json:"upload_bytes"`
+	Creates     int                 `json:"creates"`
+	Updates     int                 `json:"updates"`
+	Unchanged   int                 `json:"unchanged"`
+}
+
+// ChallengeEstimate is the preflight estimate for one challenge
+type ChallengeEstimate struct {
+	Challenge   string `json:"challenge"`
+	Action      string `json:"action"` // create, update, none
+	APICalls    int    `json:"api_calls"`
+	UploadBytes int64  `json:"upload_bytes,omitempty"`
+	Attachment  string `json:"attachment,omitempty"` // Why the attachment is uploaded, if it is
+}
+
+// EstimateSync estimates the API calls and attachment upload bytes needed to
+// sync challengesConf against the remote challenges, which must carry full
+// details. hasAsset reports whether a file hash is already stored on the
+// server and may be nil. Directory attachments are counted at their
+// uncompressed size, so UploadBytes is an upper bound. No API requests are
+// made.
+func EstimateSync(challengesConf []config.ChallengeYaml, remote []gzapi.Challenge, hasAsset func(hash string) bool) *SyncEstimate {
+	est := &SyncEstimate{}
+	for _, conf := range challengesConf {
+		plan := PlanSync(conf, remote, nil)
+		ce := ChallengeEstimate{Challenge: plan.Challenge, Action: plan.Action}
+
+		switch plan.Action {
+		case PlanActionCreate:
+			est.Creates++
+			ce.APICalls += 2 // Create, then update with the full configuration
+		case PlanActionUpdate:
+			est.Updates++
+			if plan.Diff != "" {
+				ce.APICalls++
+			}
+		default:
+			est.Unchanged++
+		}
+
+		if len(plan.FlagsToAdd) > 0 {
+			ce.APICalls += 2 // Create flags, then refresh their IDs
+		}
+		ce.APICalls += len(plan.FlagsToRemove)
+
+		var existing *gzapi.Attachment
+		if remoteChallenge := findChallengeByTitle(remote, conf.Name); remoteChallenge != nil {
+			existing = remoteChallenge.Attachment
+		}
+		calls, bytes, reason := estimateAttachment(conf, existing, hasAsset)
+		ce.APICalls += calls
+		ce.UploadBytes = bytes
+		ce.Attachment = reason
+
+		est.APICalls += ce.APICalls
+		est.UploadBytes += ce.UploadBytes
+		est.Challenges = append(est.Challenges, ce)
+	}
+
+	sort.Slice(est.Challenges, func(i, j int) bool { return est.Challenges[i].Challenge < est.Challenges[j].Challenge })
+	return est
+}
+
+func estimateAttachment(conf config.ChallengeYaml, existing *gzapi.Attachment, hasAsset func(string) bool) (calls int, bytes int64, reason string) {
+	switch {
+	case conf.Provide == nil:
+		if existing != nil {
+			return 1, 0, "removed"
+		}
+		return 0, 0, ""
+	case strings.HasPrefix(*conf.Provide, "http"):
+		if existing != nil && existing.Type == "Remote" && existing.Url == *conf.Provide {
+			return 0, 0, ""
+		}
+		return 1, 0, "remote URL changed"
+	}
+
+	path := filepath.Join(conf.Cwd, *conf.Provide)
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, fmt.Sprintf("missing: %v", err)
+	}
+	if !info.IsDir() {
+		hash, err := fileutil.GetFileHashHex(path)
+		if err == nil {
+			if existing != nil && strings.Contains(existing.Url, hash) {
+				return 0, 0, ""
+			}
+			if hasAsset != nil && hasAsset(hash) {
+				return 1, 0, "file changed, already on server"
+			}
+		}
+		return 2, info.Size(), "file changed"
+	}
+
+	size, err := dirSize(path)
+	if err != nil {
+		return 2, 0, fmt.Sprintf("unreadable: %v", err)
+	}
+	return 2, size, "directory zipped"
+}
+
+// WarmAssetsCache loads the server's asset list into the cache attachment
+// uploads consult, and returns a lookup over it for EstimateSync
+func WarmAssetsCache(api *gzapi.GZAPI) (func(hash string) bool, error) {
+	cache := getAssetsCache(api)
+	if err := cache.ensureLoaded(api); err != nil {
+		return nil, err
+	}
+	return func(hash string) bool {
+		_, ok := cache.get(hash)
+		return ok
+	}, nil
+}
+
+func dirSize(root string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Print writes a human-readable summary of the estimate
+func (e *SyncEstimate) Print(w io.Writer) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preflight: %d to create, %d to update, %d unchanged\n", e.Creates, e.Updates, e.Unchanged)
+	fmt.Fprintf(&b, "Estimated API calls: %d, attachment uploads: up to %s\n", e.APICalls, FormatBytes(e.UploadBytes))
+	for _, ce := range e.Challenges {
+		if ce.APICalls == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  %-8s %-40s %3d call(s)", ce.Action, ce.Challenge, ce.APICalls)
+		if ce.Attachment != "" {
+			fmt.Fprintf(&b, "  attachment %s", ce.Attachment)
+			if ce.UploadBytes > 0 {
+				fmt.Fprintf(&b, " (%s)", FormatBytes(ce.UploadBytes))
+			}
+		}
+		b.WriteString("\n")
+	}
+	_, _ = io.WriteString(w, b.String())
+}
+
+// FormatBytes formats a byte count with a binary unit
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
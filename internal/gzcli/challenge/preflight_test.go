@@ -0,0 +1,91 @@
+package challenge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestEstimateSync(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "dist.zip"), make([]byte, 1500), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "a.c"), make([]byte, 300), 0600); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := fileutil.GetFileHashHex(filepath.Join(dir, "dist.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provide := func(p string) *string { return &p }
+	upToDate := config.ChallengeYaml{
+		Name: "Same", Category: "Misc", Type: "StaticAttachment", Value: 100,
+		Flags: []string{"flag{same}"}, Cwd: dir, Provide: provide("dist.zip"),
+	}
+	remote := gzapi.Challenge{
+		Id: 7, Flags: []gzapi.Flag{{Id: 1, Flag: "flag{same}"}},
+		Attachment: &gzapi.Attachment{Type: "Local", Url: "/assets/" + hash + "/dist.zip"},
+	}
+	MergeChallengeData(&upToDate, &remote)
+
+	fresh := config.ChallengeYaml{
+		Name: "Fresh", Category: "Web", Type: "StaticAttachment", Value: 500,
+		Flags: []string{"flag{a}"}, Cwd: dir, Provide: provide("src"),
+	}
+	reused := config.ChallengeYaml{
+		Name: "Reused", Category: "Web", Type: "StaticAttachment", Value: 500,
+		Cwd: dir, Provide: provide("dist.zip"),
+	}
+
+	est := EstimateSync(
+		[]config.ChallengeYaml{upToDate, fresh, reused},
+		[]gzapi.Challenge{remote},
+		func(h string) bool { return h == hash },
+	)
+
+	if est.Creates != 2 || est.Updates != 0 || est.Unchanged != 1 {
+		t.Errorf("creates/updates/unchanged = %d/%d/%d, want 2/0/1", est.Creates, est.Updates, est.Unchanged)
+	}
+
+	byName := map[string]ChallengeEstimate{}
+	for _, ce := range est.Challenges {
+		byName[ce.Challenge] = ce
+	}
+	if ce := byName["Same"]; ce.APICalls != 0 || ce.UploadBytes != 0 {
+		t.Errorf("Same = %+v, want no calls", ce)
+	}
+	// Create + update, flags + refresh, asset upload + attachment
+	if ce := byName["Fresh"]; ce.APICalls != 6 || ce.UploadBytes != 300 {
+		t.Errorf("Fresh = %+v, want 6 calls and 300 bytes", ce)
+	}
+	// Create + update, attachment pointing at the existing asset
+	if ce := byName["Reused"]; ce.APICalls != 3 || ce.UploadBytes != 0 {
+		t.Errorf("Reused = %+v, want 3 calls and no upload", ce)
+	}
+	if est.APICalls != 9 || est.UploadBytes != 300 {
+		t.Errorf("totals = %d calls, %d bytes, want 9 and 300", est.APICalls, est.UploadBytes)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:         "0 B",
+		1023:      "1023 B",
+		1536:      "1.5 KiB",
+		100 << 20: "100.0 MiB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
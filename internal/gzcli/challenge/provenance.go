@@ -0,0 +1,136 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Provenance identifies what a sync deployed, so the challenge can be
+// reproduced and verified after the event
+type Provenance struct {
+	ChallengeID int
+	Title       string
+	Category    string
+	DistHash    string // SHA-256 of the local attachment as zipped for upload, empty otherwise
+	Image       string // Container image set on the challenge
+	ImageDigest string // Digest the image is pinned to, empty when unknown
+
+	cwd string
+}
+
+// Deployment converts p into the record stored in the watcher database.
+// challenge.yaml is hashed now, so that formatting it after the sync is
+// part of the record.
+func (p Provenance) Deployment(event, source string) watchertypes.Deployment {
+	yamlHash := ""
+	if path, err := registry.FindChallengeFile(p.cwd); err == nil {
+		if yamlHash, err = fileutil.GetFileHashHex(path); err != nil {
+			log.Error("Failed to hash challenge.yaml of %s: %v", p.Title, err)
+		}
+	}
+
+	return watchertypes.Deployment{
+		Timestamp:   time.Now().UTC(),
+		Event:       event,
+		Challenge:   p.Title,
+		Category:    p.Category,
+		ChallengeID: p.ChallengeID,
+		YamlHash:    yamlHash,
+		DistHash:    p.DistHash,
+		Image:       p.Image,
+		ImageDigest: p.ImageDigest,
+		Source:      source,
+	}
+}
+
+// buildProvenance hashes the attachment and records the image of a synced
+// challenge. Hashing failures leave the field empty and never fail the sync.
+func buildProvenance(challengeConf config.ChallengeYaml, challengeID int) Provenance {
+	p := Provenance{
+		ChallengeID: challengeID,
+		Title:       challengeConf.Name,
+		Category:    challengeConf.Category,
+		cwd:         challengeConf.Cwd,
+	}
+
+	if challengeConf.Provide != nil && !strings.HasPrefix(*challengeConf.Provide, "http") {
+		hash, err := distHash(filepath.Join(challengeConf.Cwd, *challengeConf.Provide), challengeConf.Name)
+		if err != nil {
+			log.Error("Failed to hash attachment of %s: %v", challengeConf.Name, err)
+		}
+		p.DistHash = hash
+	}
+
+	if isContainerChallengeType(challengeConf.Type) {
+		p.Image = challengeConf.Container.ContainerImage
+		p.ImageDigest = imageDigest(p.Image)
+		if p.ImageDigest == "" && p.Image != "" && !containerImageResolvesToLocalPath(challengeConf.Cwd, p.Image) {
+			digest, err := registryDigest(p.Image)
+			if err != nil {
+				log.Error("Failed to resolve the digest of %s for %s: %v", p.Image, challengeConf.Name, err)
+			}
+			p.ImageDigest = digest
+		}
+	}
+	return p
+}
+
+// distHash hashes the attachment at path the way HandleLocalAttachment
+// uploads it: files as they are, directories as their deterministic zip
+func distHash(path, challengeName string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return fileutil.GetFileHashHex(path)
+	}
+
+	zipPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s-provenance-dist.zip", fileutil.NormalizeFileName(challengeName)))
+	defer func() { _ = os.Remove(zipPath) }()
+	if err := fileutil.ZipSource(path, zipPath); err != nil {
+		return "", err
+	}
+	return fileutil.GetFileHashHex(zipPath)
+}
+
+// registryDigest returns the digest the registry currently serves for an
+// image tag, so that deployments by tag record what GZCTF pulls
+func registryDigest(ref string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), getDockerTagTimeout())
+	defer cancel()
+	out, err := dockerOutputCommand(ctx, []string{"buildx", "imagetools", "inspect", "--format", "{{json .Manifest}}", ref})
+	if err != nil {
+		return "", err
+	}
+	var manifest struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse the manifest of %s: %w", ref, err)
+	}
+	if !strings.HasPrefix(manifest.Digest, "sha256:") {
+		return "", fmt.Errorf("no digest found for %s", ref)
+	}
+	return manifest.Digest, nil
+}
+
+// imageDigest returns the digest of an image reference pinned with
+// "@sha256:...", or "" when the reference is a tag
+func imageDigest(ref string) string {
+	if _, digest, ok := strings.Cut(ref, "@"); ok && strings.HasPrefix(digest, "sha256:") {
+		return digest
+	}
+	return ""
+}
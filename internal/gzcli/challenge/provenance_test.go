@@ -0,0 +1,85 @@
+package challenge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func TestImageDigest(t *testing.T) {
+	tests := map[string]string{
+		"registry.example.com/ctf/web@sha256:abc": "sha256:abc",
+		"registry.example.com/ctf/web:latest":     "",
+		"nginx":                                   "",
+	}
+	for ref, want := range tests {
+		if got := imageDigest(ref); got != want {
+			t.Errorf("imageDigest(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestBuildProvenance(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "dist"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dist", "chall.py"), []byte("print(1)\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "chall.zip"), []byte("zip"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	provide := "./dist"
+	conf := config.ChallengeYaml{
+		Name:     "Web",
+		Category: "Web",
+		Type:     "DynamicContainer",
+		Cwd:      dir,
+		Provide:  &provide,
+		Container: config.Container{
+			ContainerImage: "registry.example.com/ctf/web@sha256:abc",
+		},
+	}
+	p := buildProvenance(conf, 7)
+	if p.ChallengeID != 7 || p.ImageDigest != "sha256:abc" {
+		t.Errorf("provenance = %+v, want challenge 7 pinned to sha256:abc", p)
+	}
+	if p.DistHash == "" {
+		t.Fatal("expected the dist directory to be hashed")
+	}
+	if again := buildProvenance(conf, 7); again.DistHash != p.DistHash {
+		t.Errorf("dist hash is not deterministic: %s != %s", again.DistHash, p.DistHash)
+	}
+
+	file := "chall.zip"
+	conf.Provide = &file
+	if fileHash := buildProvenance(conf, 7).DistHash; fileHash == "" || fileHash == p.DistHash {
+		t.Errorf("file dist hash = %q, want a hash different from the directory", fileHash)
+	}
+
+	origDockerOutput := dockerOutputCommand
+	defer func() { dockerOutputCommand = origDockerOutput }()
+	dockerOutputCommand = func(_ context.Context, args []string) (string, error) {
+		if strings.Join(args[:3], " ") != "buildx imagetools inspect" || args[len(args)-1] != "registry.example.com/ctf/web:1" {
+			t.Errorf("unexpected docker call: %v", args)
+		}
+		return `{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"sha256:def","size":856}` + "\n", nil
+	}
+	conf.Container.ContainerImage = "registry.example.com/ctf/web:1"
+	if p := buildProvenance(conf, 7); p.ImageDigest != "sha256:def" {
+		t.Errorf("image digest = %q, want the digest the tag resolves to", p.ImageDigest)
+	}
+
+	remote := "https://example.com/chall.zip"
+	conf.Provide = &remote
+	conf.Type = "StaticAttachment"
+	if p := buildProvenance(conf, 7); p.DistHash != "" || p.Image != "" {
+		t.Errorf("provenance = %+v, want no dist hash nor image", p)
+	}
+}
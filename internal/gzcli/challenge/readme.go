@@ -0,0 +1,197 @@
+package challenge
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// ReadmeFile is the README rendered as the description of a challenge
+// without one
+const ReadmeFile = "README.md"
+
+// readmeInlineLimit is the size up to which README images are inlined as
+// data URIs; larger ones are uploaded as assets
+const readmeInlineLimit = 64 << 10
+
+var (
+	// readmeMarkdownImageRegex matches ![alt](path "title")
+	readmeMarkdownImageRegex = regexp.MustCompile(`(!\[[^\]]*\]\(\s*)<?([^)\s>]+)>?((?:\s+"[^"]*")?\s*\))`)
+	// readmeHTMLImageRegex matches <img src="path">
+	readmeHTMLImageRegex = regexp.MustCompile(`(<img\b[^>]*?\bsrc=["'])([^"']+)(["'])`)
+	// readmeTitleRegex matches a leading "# Title" line, shown by GZCTF already
+	readmeTitleRegex = regexp.MustCompile(`\A\s*#[ \t]+[^\n]*\n*`)
+)
+
+// assetURLFunc returns the URL of a local file referenced by a README
+type assetURLFunc func(path string) (string, error)
+
+// findReadme returns the path of the README of a challenge directory, or ""
+func findReadme(cwd string) string {
+	entries, err := os.ReadDir(cwd)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(entry.Name(), ReadmeFile) {
+			return filepath.Join(cwd, entry.Name())
+		}
+	}
+	return ""
+}
+
+// ReadmeImages returns the local images referenced by the README of a
+// challenge directory, relative to it with forward slashes
+func ReadmeImages(cwd string) []string {
+	path := findReadme(cwd)
+	if path == "" {
+		return nil
+	}
+	//nolint:gosec // G304: README of a challenge directory
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var images []string
+	for _, re := range []*regexp.Regexp{readmeMarkdownImageRegex, readmeHTMLImageRegex} {
+		for _, m := range re.FindAllStringSubmatch(string(content), -1) {
+			if local, ok := localReadmeImage(cwd, m[2]); ok {
+				rel, _ := filepath.Rel(cwd, local)
+				images = append(images, filepath.ToSlash(rel))
+			}
+		}
+	}
+	return images
+}
+
+// localReadmeImage resolves a README image reference to a file inside the
+// challenge directory; URLs, absolute paths and paths leaving it are not
+// local images
+func localReadmeImage(cwd, ref string) (string, bool) {
+	if ref == "" || strings.Contains(ref, ":") || strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "#") {
+		return "", false
+	}
+	ref, _, _ = strings.Cut(ref, "#")
+	ref, _, _ = strings.Cut(ref, "?")
+	path := filepath.Join(cwd, filepath.FromSlash(ref))
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+// RenderReadme converts the README of a challenge into its description:
+// the leading title is dropped and local images are inlined as data URIs up
+// to readmeInlineLimit, or replaced with the URL returned by assetURL.
+// Images that cannot be read are left as they are.
+func RenderReadme(cwd string, content []byte, assetURL assetURLFunc) string {
+	description := readmeTitleRegex.ReplaceAllString(string(content), "")
+
+	replace := func(re *regexp.Regexp) {
+		description = re.ReplaceAllStringFunc(description, func(match string) string {
+			m := re.FindStringSubmatch(match)
+			path, ok := localReadmeImage(cwd, m[2])
+			if !ok {
+				return match
+			}
+			url, err := readmeImageURL(path, assetURL)
+			if err != nil {
+				log.Error("Failed to embed README image %s: %v", m[2], err)
+				return match
+			}
+			return m[1] + url + m[3]
+		})
+	}
+	replace(readmeMarkdownImageRegex)
+	replace(readmeHTMLImageRegex)
+
+	return strings.TrimSpace(description) + "\n"
+}
+
+// readmeImageURL returns a data URI of a small image, otherwise the URL of
+// its asset
+func readmeImageURL(path string, assetURL assetURLFunc) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > readmeInlineLimit {
+		return assetURL(path)
+	}
+
+	//nolint:gosec // G304: Image referenced by a challenge README
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// readmeAssetURL is the URL of an asset uploaded from path with the given
+// hash
+func readmeAssetURL(path, hash string) string {
+	return "/assets/" + hash + "/" + filepath.Base(path)
+}
+
+// plannedReadmeAssetURL returns the URL an image will have once uploaded,
+// without uploading it
+func plannedReadmeAssetURL(path string) (string, error) {
+	hash, err := fileutil.GetFileHashHex(path)
+	if err != nil {
+		return "", err
+	}
+	return readmeAssetURL(path, hash), nil
+}
+
+// uploadReadmeAsset returns an assetURLFunc uploading images as assets
+// unless the platform has them already
+func uploadReadmeAsset(api *gzapi.GZAPI) assetURLFunc {
+	return func(path string) (string, error) {
+		hash, err := fileutil.GetFileHashHex(path)
+		if err != nil {
+			return "", err
+		}
+		if _, err := CreateAssetsIfNotExistOrDifferentWithHash(path, hash, api); err != nil {
+			return "", err
+		}
+		return readmeAssetURL(path, hash), nil
+	}
+}
+
+// applyReadmeDescription sets the description of a challenge without one to
+// its rendered README, if it has one
+func applyReadmeDescription(challengeConf *config.ChallengeYaml, assetURL assetURLFunc) error {
+	if strings.TrimSpace(challengeConf.Description) != "" {
+		return nil
+	}
+	path := findReadme(challengeConf.Cwd)
+	if path == "" {
+		return nil
+	}
+	//nolint:gosec // G304: README of a challenge directory
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	challengeConf.Description = RenderReadme(challengeConf.Cwd, content, assetURL)
+	return nil
+}
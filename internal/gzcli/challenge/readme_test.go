@@ -0,0 +1,95 @@
+package challenge
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func writeReadmeFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"README.md": []byte("# Baby Web\n\nFind the flag.\n\n![diagram](images/small.png)\n" +
+			"![screenshot](<images/large.png> \"Screenshot\")\n<img src=\"images/small.png\" width=\"200\">\n" +
+			"![remote](https://example.com/x.png) ![outside](../secret.png) ![missing](images/missing.png)\n"),
+		"images/small.png": []byte("\x89PNG\r\n\x1a\nsmall"),
+		"images/large.png": bytes.Repeat([]byte{0xff}, readmeInlineLimit+1),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRenderReadme(t *testing.T) {
+	dir := writeReadmeFixture(t)
+	content, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+
+	var uploaded []string
+	got := RenderReadme(dir, content, func(path string) (string, error) {
+		uploaded = append(uploaded, filepath.Base(path))
+		return "/assets/abc/" + filepath.Base(path), nil
+	})
+
+	if strings.Contains(got, "# Baby Web") {
+		t.Error("title should be dropped")
+	}
+	if !strings.HasPrefix(got, "Find the flag.") {
+		t.Errorf("description = %q", got)
+	}
+	for _, want := range []string{
+		"![diagram](data:image/png;base64,",
+		"<img src=\"data:image/png;base64,",
+		"![screenshot](/assets/abc/large.png \"Screenshot\")",
+		"![remote](https://example.com/x.png)",
+		"![outside](../secret.png)",
+		"![missing](images/missing.png)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("description lacks %q:\n%s", want, got)
+		}
+	}
+	if !reflect.DeepEqual(uploaded, []string{"large.png"}) {
+		t.Errorf("uploaded %v, want only the large image", uploaded)
+	}
+}
+
+func TestReadmeImages(t *testing.T) {
+	dir := writeReadmeFixture(t)
+	got := ReadmeImages(dir)
+	want := []string{"images/small.png", "images/large.png", "images/missing.png", "images/small.png"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadmeImages() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyReadmeDescription_KeepsDescription(t *testing.T) {
+	dir := writeReadmeFixture(t)
+	conf := config.ChallengeYaml{Cwd: dir, Description: "Written by hand"}
+	if err := applyReadmeDescription(&conf, plannedReadmeAssetURL); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Description != "Written by hand" {
+		t.Errorf("description = %q, want it kept", conf.Description)
+	}
+
+	conf.Description = ""
+	if err := applyReadmeDescription(&conf, plannedReadmeAssetURL); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(conf.Description, "![screenshot](/assets/") {
+		t.Errorf("description = %q, want the rendered README", conf.Description)
+	}
+}
@@ -0,0 +1,40 @@
+package challenge
+
+import (
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// EnableChallenges enables the challenges of a game with the given IDs in a
+// batch of parallel requests, keeping their other settings as they are in
+// GZCTF. It returns the enabled challenges together with the joined errors of
+// the ones that failed.
+func EnableChallenges(game *gzapi.Game, ids []int) ([]gzapi.Challenge, error) {
+	current, err := game.GetChallengesByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	enabled := true
+	for i := range current {
+		current[i].IsEnabled = &enabled
+	}
+	return game.BulkUpdate(current)
+}
+
+// DueReleases returns the IDs of the remote challenges still disabled although
+// the release_at of their challenge.yml is not after now
+func DueReleases(challengesConf []config.ChallengeYaml, remote []gzapi.Challenge, now time.Time) []int {
+	var ids []int
+	for _, c := range challengesConf {
+		if c.ReleaseAt == nil || c.ReleaseAt.After(now) {
+			continue
+		}
+		existing := findChallengeByTitle(remote, c.Name)
+		if existing != nil && existing.IsEnabled != nil && !*existing.IsEnabled {
+			ids = append(ids, existing.Id)
+		}
+	}
+	return ids
+}
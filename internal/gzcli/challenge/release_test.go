@@ -0,0 +1,73 @@
+package challenge
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestDueReleases(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	enabled, disabled := true, false
+	challengesConf := []config.ChallengeYaml{
+		{Name: "Due", ReleaseAt: &past},
+		{Name: "Released", ReleaseAt: &past},
+		{Name: "Later", ReleaseAt: &future},
+		{Name: "Always"},
+		{Name: "Unsynced", ReleaseAt: &past},
+	}
+	remote := []gzapi.Challenge{
+		{Id: 1, Title: "Due", IsEnabled: &disabled},
+		{Id: 2, Title: "Released", IsEnabled: &enabled},
+		{Id: 3, Title: "Later", IsEnabled: &disabled},
+		{Id: 4, Title: "Always", IsEnabled: &disabled},
+	}
+
+	ids := DueReleases(challengesConf, remote, now)
+	if len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("DueReleases() = %v, want [1]", ids)
+	}
+}
+
+func TestEnableChallenges(t *testing.T) {
+	var mu sync.Mutex
+	updated := make(map[string]bool)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			id := strings.TrimPrefix(r.URL.Path, "/api/edit/games/3/challenges/")
+			_, _ = w.Write([]byte(`{"id": ` + id + `, "title": "Wave", "isEnabled": false, "originalScore": 300}`))
+		case http.MethodPut:
+			var body gzapi.Challenge
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			mu.Lock()
+			updated[r.URL.Path] = body.IsEnabled != nil && *body.IsEnabled && body.OriginalScore == 300
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}
+	api, cleanup := mockGZAPI(t, map[string]http.HandlerFunc{
+		"/api/edit/games/3/challenges/1": handler,
+		"/api/edit/games/3/challenges/2": handler,
+	})
+	defer cleanup()
+
+	released, err := EnableChallenges(&gzapi.Game{Id: 3, CS: api}, []int{1, 2})
+	if err != nil || len(released) != 2 {
+		t.Fatalf("EnableChallenges() = %d challenges, %v", len(released), err)
+	}
+	for _, path := range []string{"/api/edit/games/3/challenges/1", "/api/edit/games/3/challenges/2"} {
+		if !updated[path] {
+			t.Errorf("%s was not enabled with its settings kept", path)
+		}
+	}
+}
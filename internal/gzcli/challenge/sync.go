@@ -3,13 +3,16 @@ package challenge
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
@@ -136,10 +139,18 @@ func ensureFreshChallengeData(conf *config.Config, challengeConf config.Challeng
 	return refreshed, nil
 }
 
+// isNotFoundError reports whether err means the remote challenge is gone.
+// API errors are matched by status code; other errors fall back to their text.
 func isNotFoundError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, gzapi.ErrNotFound) {
+		return true
+	}
+	if gzapi.StatusCode(err) != 0 {
+		return false
+	}
 	errLower := strings.ToLower(err.Error())
 	return strings.Contains(errLower, "404") || strings.Contains(errLower, "not found")
 }
@@ -241,7 +252,9 @@ func MergeChallengeData(challengeConf *config.ChallengeYaml, challengeData *gzap
 
 	challengeData.Title = normalizedName
 	challengeData.Category = normalizedCategory
-	challengeData.Content = fmt.Sprintf("Author: **%s**\n\n%s", challengeConf.Author, challengeConf.Description)
+	challengeData.Content = fmt.Sprintf("Author: **%s**\n\n", challengeConf.Author)
+	challengeData.Content += FormatTags(challengeConf.Tags)
+	challengeData.Content += challengeConf.Description
 	challengeData.Type = challengeConf.Type
 	challengeData.Hints = challengeConf.Hints
 	challengeData.FlagTemplate = challengeConf.Container.FlagTemplate
@@ -266,11 +279,21 @@ func MergeChallengeData(challengeConf *config.ChallengeYaml, challengeData *gzap
 		challengeData.MinScoreRate = 1
 	}
 
+	// Keep challenges released later hidden; the watcher enables them at
+	// release_at. Otherwise the enabled state set in GZCTF is left alone.
+	if challengeConf.ReleaseAt != nil && time.Now().Before(*challengeConf.ReleaseAt) {
+		disabled := false
+		challengeData.IsEnabled = &disabled
+	}
+
 	return challengeData
 }
 
 // isDuplicateError checks if an error is a duplicate creation error
 func isDuplicateError(err error) bool {
+	if errors.Is(err, gzapi.ErrConflict) {
+		return true
+	}
 	errLower := strings.ToLower(err.Error())
 	return strings.Contains(errLower, "already exists") ||
 		strings.Contains(errLower, "duplicate") ||
@@ -354,7 +377,11 @@ type SyncOrchestrator struct {
 	getCache          func(string, interface{}) error
 	setCache          func(string, interface{}) error
 	existingChallenge *gzapi.Challenge
+	attachments       AttachmentStore
 	challengeData     *gzapi.Challenge
+	provenance        Provenance
+	updateType        string
+	imageCheck        func(image string) error
 	err               error
 }
 
@@ -368,25 +395,52 @@ func NewSyncOrchestrator(conf *config.Config, challengeConf config.ChallengeYaml
 		getCache:          getCache,
 		setCache:          setCache,
 		existingChallenge: existingChallenge,
+		attachments: cacheAttachmentStore{
+			key:      buildAttachmentCacheKey(conf.EventName, challengeConf.Category, challengeConf.Name),
+			getCache: getCache,
+			setCache: setCache,
+		},
 	}
 }
 
+// SetAttachmentStore replaces the gzcli cache as the record of the attachment
+// hashes uploaded to the challenge
+func (s *SyncOrchestrator) SetAttachmentStore(store AttachmentStore) {
+	s.attachments = store
+}
+
+// SetImageCheck makes the sync run check on the container image the
+// challenge deploys, once it is built and pushed, and fail when it fails
+func (s *SyncOrchestrator) SetImageCheck(check func(image string) error) {
+	s.imageCheck = check
+}
+
 // Execute runs the synchronization process.
 func (s *SyncOrchestrator) Execute() error {
 	s.handle("determining sync path", s.determineSyncPath)
+	s.handle("running pre_sync hook", s.preSync)
 	s.handle("processing attachments and flags", s.processAttachmentsAndFlags)
 	s.handle("building/pushing container image", s.prepareContainerImage)
+	s.handle("checking container image", s.checkContainerImage)
+	s.handle("rendering README description", s.renderReadme)
 	s.handle("merging and updating challenge", s.mergeAndupdate)
+	s.handle("running post_sync hook", s.postSync)
 
 	if s.err != nil {
 		log.Error("Failed to sync challenge '%s': %v", s.challengeConf.Name, s.err)
 		return s.err
 	}
 
+	s.provenance = buildProvenance(s.challengeConf, s.challengeData.Id)
 	log.Info("✓ %s", s.challengeConf.Name)
 	return nil
 }
 
+// Provenance returns what a successful Execute deployed
+func (s *SyncOrchestrator) Provenance() Provenance {
+	return s.provenance
+}
+
 // handle wraps a function call with error checking.
 func (s *SyncOrchestrator) handle(step string, fn func() error) {
 	if s.err != nil {
@@ -422,8 +476,14 @@ func (s *SyncOrchestrator) determineSyncPath() error {
 
 // processAttachmentsAndFlags handles attachments and flags for the challenge.
 func (s *SyncOrchestrator) processAttachmentsAndFlags() error {
+	refresher := func() (*gzapi.Challenge, error) {
+		return s.conf.Event.GetChallenge(s.challengeConf.Name)
+	}
+	attach := func(challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
+		return handleChallengeAttachments(challengeConf, challengeData, api, s.attachments)
+	}
 	var err error
-	s.challengeData, err = processAttachmentsAndFlags(s.conf, s.challengeConf, s.challengeData, s.api)
+	s.challengeData, err = processAttachmentsAndFlagsWithHandlers(s.conf, s.challengeConf, s.challengeData, s.api, refresher, attach, UpdateChallengeFlags)
 	return err
 }
 
@@ -438,6 +498,12 @@ func (s *SyncOrchestrator) mergeAndupdate() error {
 	return updateChallengeIfNeeded(s.conf, &s.challengeConf, s.challengeData, &preMerge, s.getCache, s.setCache)
 }
 
+// renderReadme renders the README of a challenge without a description,
+// uploading its large images as assets
+func (s *SyncOrchestrator) renderReadme() error {
+	return applyReadmeDescription(&s.challengeConf, uploadReadmeAsset(s.api))
+}
+
 func (s *SyncOrchestrator) prepareContainerImage() error {
 	// Only do anything when a registry is configured in appsettings.json.
 	// This avoids making docker a hard requirement for sync in environments
@@ -462,7 +528,7 @@ func (s *SyncOrchestrator) prepareContainerImage() error {
 		return nil
 	}
 
-	slug := config.GenerateSlug(s.conf.EventName, s.challengeConf.Category, s.challengeConf.Name)
+	slug := registry.Slug(s.conf.EventName, s.challengeConf.Category, s.challengeConf.Name)
 	localTag := fmt.Sprintf("%s:latest", slug)
 
 	// Build local image first.
@@ -505,11 +571,35 @@ func (s *SyncOrchestrator) prepareContainerImage() error {
 		return err
 	}
 
-	// Ensure the challenge config synced to the API points at the registry image.
+	// Ensure the challenge config synced to the API points at the registry
+	// image, pinned to the pushed digest so GZCTF runs exactly this build.
 	s.challengeConf.Container.ContainerImage = remoteTag
+	remoteRepo := fmt.Sprintf("%s/%s", repoPrefix, slug)
+	inspectCtx, cancelInspect := context.WithTimeout(context.Background(), getDockerTagTimeout())
+	defer cancelInspect()
+	digest, err := dockerRepoDigest(inspectCtx, remoteTag, remoteRepo)
+	if err != nil {
+		log.Error("Failed to resolve the digest of %s, deploying it unpinned: %v", remoteTag, err)
+		return nil
+	}
+	s.challengeConf.Container.ContainerImage = remoteRepo + "@" + digest
 	return nil
 }
 
+// checkContainerImage runs the image check on the image GZCTF will pull.
+// Images left as a local build directory, without a registry to push them
+// to, are not checked.
+func (s *SyncOrchestrator) checkContainerImage() error {
+	if s.imageCheck == nil || !isContainerChallengeType(s.challengeConf.Type) {
+		return nil
+	}
+	image := strings.TrimSpace(s.challengeConf.Container.ContainerImage)
+	if image == "" || containerImageResolvesToLocalPath(s.challengeConf.Cwd, image) {
+		return nil
+	}
+	return s.imageCheck(image)
+}
+
 // SyncChallenge synchronizes a single challenge.
 func SyncChallenge(conf *config.Config, challengeConf config.ChallengeYaml, challenges []gzapi.Challenge, api *gzapi.GZAPI, getCache func(string, interface{}) error, setCache func(string, interface{}) error) error {
 	return NewSyncOrchestrator(conf, challengeConf, challenges, api, getCache, setCache, nil).Execute()
@@ -520,14 +610,6 @@ func SyncChallengeWithExisting(conf *config.Config, challengeConf config.Challen
 	return NewSyncOrchestrator(conf, challengeConf, challenges, api, getCache, setCache, existingChallenge).Execute()
 }
 
-// processAttachmentsAndFlags handles attachments and flags for a challenge
-func processAttachmentsAndFlags(conf *config.Config, challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) (*gzapi.Challenge, error) {
-	refresher := func() (*gzapi.Challenge, error) {
-		return conf.Event.GetChallenge(challengeConf.Name)
-	}
-	return processAttachmentsAndFlagsWithHandlers(conf, challengeConf, challengeData, api, refresher, HandleChallengeAttachments, UpdateChallengeFlags)
-}
-
 func processAttachmentsAndFlagsWithHandlers(conf *config.Config, challengeConf config.ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI, refresher challengeRefresher, attach attachmentHandler, updateFlags flagHandler) (*gzapi.Challenge, error) {
 	current, err := ensureFreshChallengeData(conf, challengeConf, api, challengeData, refresher)
 	if err != nil {
@@ -572,7 +654,7 @@ func updateChallengeWithRetry(conf *config.Config, challengeConf *config.Challen
 
 	log.Error("Update failed for %s: %v", challengeConf.Name, err.Error())
 
-	if !strings.Contains(err.Error(), "404") {
+	if !errors.Is(err, gzapi.ErrNotFound) {
 		return nil, fmt.Errorf("update challenge %s: %w", challengeConf.Name, err)
 	}
 
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
@@ -609,6 +610,26 @@ func TestIsConfigEdited_IgnoresRuntimeFields(t *testing.T) {
 	}
 }
 
+func TestMergeChallengeData_ReleaseAt(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	earlier := time.Now().Add(-time.Hour)
+
+	data := MergeChallengeData(&config.ChallengeYaml{Name: "Wave 2", ReleaseAt: &later}, &gzapi.Challenge{})
+	if data.IsEnabled == nil || *data.IsEnabled {
+		t.Errorf("future release_at: IsEnabled = %v, want false", data.IsEnabled)
+	}
+
+	data = MergeChallengeData(&config.ChallengeYaml{Name: "Wave 1", ReleaseAt: &earlier}, &gzapi.Challenge{})
+	if data.IsEnabled != nil {
+		t.Errorf("past release_at: IsEnabled = %v, want untouched", *data.IsEnabled)
+	}
+
+	data = MergeChallengeData(&config.ChallengeYaml{Name: "Always"}, &gzapi.Challenge{})
+	if data.IsEnabled != nil {
+		t.Errorf("no release_at: IsEnabled = %v, want untouched", *data.IsEnabled)
+	}
+}
+
 func TestMergeChallengeDataWithCategoryNormalization(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -704,3 +725,24 @@ func TestIsChallengeExistWithNormalizedNames(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNotFoundError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&gzapi.APIError{StatusCode: 404}, true},
+		{fmt.Errorf("get challenge x: %w", &gzapi.APIError{StatusCode: 404}), true},
+		{fmt.Errorf("challenge %w", gzapi.ErrNotFound), true},
+		// A server error whose body happens to say "not found" is not a 404
+		{&gzapi.APIError{StatusCode: 500, Body: "file not found"}, false},
+		{fmt.Errorf("challenge not found: 404"), true},
+		{fmt.Errorf("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := isNotFoundError(tt.err); got != tt.want {
+			t.Errorf("isNotFoundError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
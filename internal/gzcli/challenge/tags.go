@@ -0,0 +1,88 @@
+package challenge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GZCTF has no tag field on challenges, so tags are kept in a line at the top
+// of the challenge content, which is what players see
+const tagsLinePrefix = "Tags: `"
+
+// FormatTags renders the content line holding tags, or "" without tags
+func FormatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Tags: `%s`\n\n", strings.Join(tags, "` `"))
+}
+
+// ParseTags returns the tags of a remote challenge from the tags line that
+// FormatTags wrote into its content
+func ParseTags(content string) []string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, tagsLinePrefix) || !strings.HasSuffix(line, "`") {
+			continue
+		}
+		inner := strings.TrimSuffix(strings.TrimPrefix(line, tagsLinePrefix), "`")
+		return strings.Split(inner, "` `")
+	}
+	return nil
+}
+
+// HasAnyTag reports whether tags contains at least one of wanted
+func HasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(tag, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// disallowedTags returns the tags that are not in allowed, or none when
+// allowed is empty
+func disallowedTags(tags, allowed []string) []string {
+	if len(allowed) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(allowed))
+	for _, tag := range allowed {
+		set[tag] = struct{}{}
+	}
+	var bad []string
+	for _, tag := range tags {
+		if _, ok := set[tag]; !ok {
+			bad = append(bad, tag)
+		}
+	}
+	return bad
+}
+
+// diffTags returns the tags that must be added to and removed from the remote
+// challenge to reach the desired set
+func diffTags(desired, existing []string) (toAdd []string, toRemove []string) {
+	desiredSet := make(map[string]struct{}, len(desired))
+	for _, tag := range desired {
+		desiredSet[tag] = struct{}{}
+	}
+	existingSet := make(map[string]struct{}, len(existing))
+	for _, tag := range existing {
+		existingSet[tag] = struct{}{}
+		if _, keep := desiredSet[tag]; !keep {
+			toRemove = append(toRemove, tag)
+		}
+	}
+	for tag := range desiredSet {
+		if _, ok := existingSet[tag]; !ok {
+			toAdd = append(toAdd, tag)
+		}
+	}
+	sort.Strings(toAdd)
+	sort.Strings(toRemove)
+	return toAdd, toRemove
+}
@@ -0,0 +1,67 @@
+package challenge
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestTagsRoundTrip(t *testing.T) {
+	tags := []string{"web", "sqli", "beginner friendly"}
+	conf := config.ChallengeYaml{Name: "Tagged", Author: "alice", Description: "desc", Tags: tags}
+
+	var remote gzapi.Challenge
+	MergeChallengeData(&conf, &remote)
+
+	if got := ParseTags(remote.Content); !reflect.DeepEqual(got, tags) {
+		t.Errorf("ParseTags() = %v, want %v", got, tags)
+	}
+}
+
+func TestParseTags_None(t *testing.T) {
+	for _, content := range []string{"", "Author: **alice**\n\ndesc", "Tags mentioned in passing"} {
+		if got := ParseTags(content); got != nil {
+			t.Errorf("ParseTags(%q) = %v, want nil", content, got)
+		}
+	}
+	if FormatTags(nil) != "" {
+		t.Error("FormatTags(nil) should be empty")
+	}
+}
+
+func TestHasAnyTag(t *testing.T) {
+	tags := []string{"web", "Easy"}
+	if !HasAnyTag(tags, []string{"crypto", "easy"}) {
+		t.Error("expected a case-insensitive match on easy")
+	}
+	if HasAnyTag(tags, []string{"crypto"}) {
+		t.Error("unexpected match")
+	}
+	if HasAnyTag(nil, []string{"web"}) {
+		t.Error("untagged challenge matched")
+	}
+}
+
+func TestPlanSync_TagChanges(t *testing.T) {
+	old := config.ChallengeYaml{Name: "Tagged", Category: "Web", Type: "StaticAttachment", Author: "alice", Tags: []string{"web", "old"}}
+	var remote gzapi.Challenge
+	MergeChallengeData(&old, &remote)
+	remote.Id = 7
+
+	conf := old
+	conf.Tags = []string{"web", "new"}
+	plan := PlanSync(conf, nil, &remote)
+
+	if plan.Action != PlanActionUpdate {
+		t.Fatalf("Action = %q, want %q", plan.Action, PlanActionUpdate)
+	}
+	if !reflect.DeepEqual(plan.TagsToAdd, []string{"new"}) || !reflect.DeepEqual(plan.TagsToRemove, []string{"old"}) {
+		t.Errorf("tags +%v -%v, want +[new] -[old]", plan.TagsToAdd, plan.TagsToRemove)
+	}
+
+	if plan := PlanSync(old, nil, &remote); len(plan.TagsToAdd)+len(plan.TagsToRemove) != 0 {
+		t.Errorf("unchanged tags planned +%v -%v", plan.TagsToAdd, plan.TagsToRemove)
+	}
+}
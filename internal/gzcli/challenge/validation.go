@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
@@ -20,10 +21,27 @@ var validTypes = map[string]struct{}{
 const (
 	MinInterval = 30 * time.Second
 	MaxInterval = 24 * time.Hour
+
+	// MaxWatchWindow bounds the watcher debounce and cooldown of a challenge
+	MaxWatchWindow = time.Hour
 )
 
 // IsGoodChallenge validates a challenge configuration for required fields and correct values
 func IsGoodChallenge(challenge config.ChallengeYaml) error {
+	errors := validationErrors(challenge)
+	if len(errors) > 0 {
+		log.Error("Validation errors for %s:", challenge.Name)
+		for _, e := range errors {
+			log.Error("  - %s", e)
+		}
+		return fmt.Errorf("invalid challenge: %s", challenge.Name)
+	}
+
+	return nil
+}
+
+// validationErrors lists the problems IsGoodChallenge rejects a challenge for
+func validationErrors(challenge config.ChallengeYaml) []string {
 	var errors []string
 
 	if challenge.Name == "" {
@@ -38,6 +56,27 @@ func IsGoodChallenge(challenge config.ChallengeYaml) error {
 	if challenge.Value < 0 {
 		errors = append(errors, "negative value")
 	}
+	for _, tag := range challenge.Tags {
+		if strings.TrimSpace(tag) == "" || strings.Contains(tag, "`") {
+			errors = append(errors, fmt.Sprintf("invalid tag: %q", tag))
+		}
+	}
+	if bad := disallowedTags(challenge.Tags, challenge.AllowedTags); len(bad) > 0 {
+		errors = append(errors, fmt.Sprintf("tags not allowed by the event: %s (allowed: %s)", strings.Join(bad, ", "), strings.Join(challenge.AllowedTags, ", ")))
+	}
+	if challenge.Solver != "" && !solver.IsLanguage(challenge.Solver) {
+		errors = append(errors, fmt.Sprintf("invalid solver: %s", challenge.Solver))
+	}
+	if w := challenge.Watch; w != nil {
+		if w.Debounce < 0 || w.Debounce > MaxWatchWindow {
+			errors = append(errors, fmt.Sprintf("watch debounce %v out of range [0, %v]", w.Debounce, MaxWatchWindow))
+		}
+		if w.Cooldown < 0 || w.Cooldown > MaxWatchWindow {
+			errors = append(errors, fmt.Sprintf("watch cooldown %v out of range [0, %v]", w.Cooldown, MaxWatchWindow))
+		}
+	}
+
+	errors = append(errors, flagTemplateErrors(challenge)...)
 
 	switch {
 	case len(challenge.Flags) == 0 && (challenge.Type == "StaticAttachment" || challenge.Type == "StaticContainer"):
@@ -45,16 +84,31 @@ func IsGoodChallenge(challenge config.ChallengeYaml) error {
 	case challenge.Type == "DynamicContainer" && challenge.Container.FlagTemplate == "":
 		errors = append(errors, "missing flag template for dynamic container")
 	}
+	return errors
+}
 
-	if len(errors) > 0 {
-		log.Error("Validation errors for %s:", challenge.Name)
-		for _, e := range errors {
-			log.Error("  - %s", e)
-		}
-		return fmt.Errorf("invalid challenge: %s", challenge.Name)
+// flagTemplateErrors checks the flag_template of a challenge against its type
+// and the placeholders GZCTF supports
+func flagTemplateErrors(challenge config.ChallengeYaml) []string {
+	flag := challenge.FlagTemplate
+	if flag == "" {
+		return nil
 	}
 
-	return nil
+	var errors []string
+	if challenge.Type == "DynamicContainer" {
+		if challenge.Container.FlagTemplate != flag {
+			errors = append(errors, fmt.Sprintf("flag_template %q conflicts with container.flagTemplate %q", flag, challenge.Container.FlagTemplate))
+		}
+	} else if config.IsDynamicFlag(flag) {
+		errors = append(errors, fmt.Sprintf("flag_template %q has per-team placeholders, which need a DynamicContainer challenge", flag))
+	}
+	for _, leet := range []string{config.FlagPlaceholderLeet, config.FlagPlaceholderCLeet} {
+		if strings.Contains(strings.TrimPrefix(flag, leet), leet) {
+			errors = append(errors, fmt.Sprintf("flag_template %q must start with %s to use it", flag, leet))
+		}
+	}
+	return errors
 }
 
 // ValidateChallenges validates all challenges and checks for duplicate names
@@ -157,6 +157,59 @@ func TestIsGoodChallenge_Invalid(t *testing.T) {
 			},
 			expectedError: "missing flag template",
 		},
+		{
+			name: "watch cooldown too long",
+			challenge: config.ChallengeYaml{
+				Name:        "Test",
+				Author:      "test-author",
+				Description: "Test",
+				Type:        "StaticAttachment",
+				Value:       100,
+				Flags:       []string{"FLAG{test}"},
+				Watch:       &config.WatchConfig{Cooldown: 2 * time.Hour},
+			},
+			expectedError: "watch cooldown",
+		},
+		{
+			name: "negative watch debounce",
+			challenge: config.ChallengeYaml{
+				Name:        "Test",
+				Author:      "test-author",
+				Description: "Test",
+				Type:        "StaticAttachment",
+				Value:       100,
+				Flags:       []string{"FLAG{test}"},
+				Watch:       &config.WatchConfig{Debounce: -time.Second},
+			},
+			expectedError: "watch debounce",
+		},
+		{
+			name: "tag not allowed by the event",
+			challenge: config.ChallengeYaml{
+				Name:        "Test",
+				Author:      "test-author",
+				Description: "Test",
+				Type:        "StaticAttachment",
+				Value:       100,
+				Flags:       []string{"FLAG{test}"},
+				Tags:        []string{"easy", "typo"},
+				AllowedTags: []string{"easy", "hard"},
+			},
+			expectedError: "tags not allowed",
+		},
+		{
+			name: "empty tag",
+			challenge: config.ChallengeYaml{
+				Name:        "Test",
+				Author:      "test-author",
+				Description: "Test",
+				Type:        "StaticAttachment",
+				Value:       100,
+				Flags:       []string{"FLAG{test}"},
+				Tags:        []string{" "},
+			},
+			expectedError: "invalid tag",
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,6 +226,62 @@ func TestIsGoodChallenge_Invalid(t *testing.T) {
 	}
 }
 
+func TestIsGoodChallenge_FlagTemplate(t *testing.T) {
+	base := config.ChallengeYaml{Name: "Dyn", Author: "author", Type: "DynamicContainer", Value: 100}
+	tests := []struct {
+		name    string
+		mutate  func(*config.ChallengeYaml)
+		wantErr string
+	}{
+		{
+			name: "dynamic container",
+			mutate: func(c *config.ChallengeYaml) {
+				c.FlagTemplate = "flag{[TEAM_HASH]}"
+				c.Container.FlagTemplate = c.FlagTemplate
+			},
+		},
+		{
+			name: "conflicting container flag template",
+			mutate: func(c *config.ChallengeYaml) {
+				c.FlagTemplate = "flag{[TEAM_HASH]}"
+				c.Container.FlagTemplate = "flag{[GUID]}"
+			},
+			wantErr: "conflicts with container.flagTemplate",
+		},
+		{
+			name: "per-team placeholder on a static challenge",
+			mutate: func(c *config.ChallengeYaml) {
+				c.Type = "StaticAttachment"
+				c.FlagTemplate = "flag{[TEAM_HASH]}"
+				c.Flags = []string{"flag{static}"}
+			},
+			wantErr: "need a DynamicContainer",
+		},
+		{
+			name: "leet marker not at the start",
+			mutate: func(c *config.ChallengeYaml) {
+				c.FlagTemplate = "flag{[LEET]}"
+				c.Container.FlagTemplate = c.FlagTemplate
+			},
+			wantErr: "must start with [LEET]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := base
+			tt.mutate(&challenge)
+			errs := strings.Join(validationErrors(challenge), "; ")
+			if tt.wantErr == "" && errs != "" {
+				t.Errorf("validationErrors() = %q, want none", errs)
+			}
+			if !strings.Contains(errs, tt.wantErr) {
+				t.Errorf("validationErrors() = %q, want it to contain %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateChallenges_NoDuplicates(t *testing.T) {
 	challenges := []config.ChallengeYaml{
 		{
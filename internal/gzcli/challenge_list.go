@@ -0,0 +1,65 @@
+package gzcli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// ChallengeListing is a local challenge of an event as listed by challenge list
+type ChallengeListing struct {
+	Event    string   `json:"event"`
+	Category string   `json:"category"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Value    int      `json:"value"`
+	Tags     []string `json:"tags"`
+}
+
+// ListChallenges returns the local challenges of an event sorted by category
+// and name, without contacting GZCTF. With tags, only the challenges carrying
+// at least one of them are listed.
+func ListChallenges(eventName string, tags []string) ([]ChallengeListing, error) {
+	conf, err := config.GetConfigWithEvent(nil, eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	challengesConf, err := config.GetChallengesYaml(conf)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 0 {
+		challengesConf = filterChallengesByTags(challengesConf, tags)
+	}
+
+	listings := make([]ChallengeListing, 0, len(challengesConf))
+	for _, c := range challengesConf {
+		listing := ChallengeListing{Event: eventName, Category: c.Category, Name: c.Name, Type: c.Type, Value: c.Value, Tags: c.Tags}
+		if listing.Tags == nil {
+			listing.Tags = []string{}
+		}
+		listings = append(listings, listing)
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		if listings[i].Category != listings[j].Category {
+			return listings[i].Category < listings[j].Category
+		}
+		return listings[i].Name < listings[j].Name
+	})
+	return listings, nil
+}
+
+// taggedChallengeTitles returns the names of the local challenges of an event
+// carrying at least one of tags, which are their titles on GZCTF
+func taggedChallengeTitles(eventName string, tags []string) (map[string]bool, error) {
+	listings, err := ListChallenges(eventName, tags)
+	if err != nil {
+		return nil, err
+	}
+	titles := make(map[string]bool, len(listings))
+	for _, l := range listings {
+		titles[l.Name] = true
+	}
+	return titles, nil
+}
@@ -0,0 +1,207 @@
+// Package compat reports the versions of gzcli, GZCTF and the container
+// tooling gzcli drives, and checks them against the support matrix embedded
+// in the build.
+package compat
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Components checked by the support matrix
+const (
+	ComponentGZCTF   = "gzctf"
+	ComponentDocker  = "docker"
+	ComponentCompose = "compose"
+	ComponentKubectl = "kubectl"
+)
+
+// Component statuses
+const (
+	StatusTested   = "tested"
+	StatusUntested = "untested"
+	StatusUnknown  = "unknown"   // Installed or configured, but the version could not be read
+	StatusNotFound = "not found" // Not installed; only the features using it are unavailable
+)
+
+// Verdicts of a report
+const (
+	VerdictCompatible = "compatible"
+	VerdictUntested   = "untested"
+)
+
+//go:embed matrix.yaml
+var matrixYAML []byte
+
+// Range is an inclusive range of tested versions
+type Range struct {
+	Min string `yaml:"min" json:"min"`
+	Max string `yaml:"max" json:"max"`
+}
+
+// String returns the range as "min - max"
+func (r Range) String() string {
+	return r.Min + " - " + r.Max
+}
+
+// Contains reports whether version is within the range. Bounds compare only
+// the parts they give.
+func (r Range) Contains(version string) bool {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	if lower, ok := parseVersion(r.Min); ok && compareVersion(v, lower) < 0 {
+		return false
+	}
+	if upper, ok := parseVersion(r.Max); ok && compareVersion(v, upper) > 0 {
+		return false
+	}
+	return true
+}
+
+// Matrix is the support matrix, by component
+type Matrix map[string]Range
+
+// DefaultMatrix returns the support matrix embedded in this build
+func DefaultMatrix() Matrix {
+	var m Matrix
+	if err := yaml.Unmarshal(matrixYAML, &m); err != nil {
+		panic(fmt.Sprintf("invalid embedded support matrix: %v", err))
+	}
+	return m
+}
+
+// Version is a detected component version. An empty Version with Err set
+// means the component could not be queried.
+type Version struct {
+	Version  string
+	Source   string // Where the version was read
+	Err      error
+	NotFound bool // The component is not installed
+}
+
+// Component is the check of one component against the matrix
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Tested  Range  `json:"tested"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Report is the compatibility report of gzcli and its environment
+type Report struct {
+	Gzcli      string      `json:"gzcli"`
+	Components []Component `json:"components"`
+	Verdict    string      `json:"verdict"`
+	Warnings   []string    `json:"warnings,omitempty"`
+}
+
+// Check checks the detected versions against the matrix. Components that are
+// not installed do not affect the verdict; untested versions and an unknown
+// GZCTF version do.
+func Check(gzcliVersion string, versions map[string]Version, matrix Matrix) Report {
+	report := Report{Gzcli: gzcliVersion, Verdict: VerdictCompatible}
+	for _, name := range []string{ComponentGZCTF, ComponentDocker, ComponentCompose, ComponentKubectl} {
+		v := versions[name]
+		c := Component{Name: name, Version: v.Version, Source: v.Source, Tested: matrix[name]}
+		switch {
+		case v.NotFound:
+			c.Status = StatusNotFound
+		case v.Version == "":
+			c.Status = StatusUnknown
+			if v.Err != nil {
+				c.Detail = v.Err.Error()
+			}
+		case matrix[name].Contains(v.Version):
+			c.Status = StatusTested
+		default:
+			c.Status = StatusUntested
+		}
+
+		switch {
+		case c.Status == StatusUntested:
+			report.Verdict = VerdictUntested
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s %s is untested with gzcli %s (tested: %s)", name, c.Version, gzcliVersion, c.Tested))
+		case c.Status == StatusUnknown && name == ComponentGZCTF:
+			report.Verdict = VerdictUntested
+			report.Warnings = append(report.Warnings, "the GZCTF version could not be detected; pass it with --gzctf-version")
+		}
+		report.Components = append(report.Components, c)
+	}
+	return report
+}
+
+// Print writes the report as a table followed by its warnings and verdict
+func (r Report) Print(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "gzcli %s\n\n", r.Gzcli)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "COMPONENT\tVERSION\tTESTED\tSTATUS")
+	for _, c := range r.Components {
+		version := c.Version
+		if version == "" {
+			version = "-"
+		}
+		if c.Source != "" {
+			version += " (" + c.Source + ")"
+		}
+		status := c.Status
+		if c.Detail != "" {
+			status += ": " + c.Detail
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Name, version, c.Tested, status)
+	}
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintln(w)
+	for _, warning := range r.Warnings {
+		_, _ = fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+	_, _ = fmt.Fprintf(w, "Verdict: %s\n", r.Verdict)
+}
+
+// parseVersion parses the numeric parts of a version such as "v1.5.2",
+// "28.0.1-rc.1" or "2.29.1-desktop.1", ignoring any pre-release suffix
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// compareVersion compares v to bound over the parts bound gives
+func compareVersion(v, bound []int) int {
+	for i, b := range bound {
+		n := 0
+		if i < len(v) {
+			n = v[i]
+		}
+		if n != b {
+			if n < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
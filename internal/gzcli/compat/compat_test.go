@@ -0,0 +1,194 @@
+package compat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRange_Contains(t *testing.T) {
+	r := Range{Min: "1.2", Max: "1.7"}
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.2.0", true},
+		{"v1.5.3", true},
+		{"1.7.9", true},
+		{"1.7.0-rc.1", true},
+		{"1.1.9", false},
+		{"1.8.0", false},
+		{"2.0", false},
+		{"latest", false},
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.version); got != tt.want {
+			t.Errorf("Range%v.Contains(%q) = %v, want %v", r, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultMatrix(t *testing.T) {
+	m := DefaultMatrix()
+	for _, name := range []string{ComponentGZCTF, ComponentDocker, ComponentCompose, ComponentKubectl} {
+		r, ok := m[name]
+		if !ok || r.Min == "" || r.Max == "" {
+			t.Errorf("matrix has no range for %s: %+v", name, r)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	matrix := Matrix{
+		ComponentGZCTF:   {Min: "1.0", Max: "1.7"},
+		ComponentDocker:  {Min: "20.10", Max: "28"},
+		ComponentCompose: {Min: "2.0", Max: "2"},
+		ComponentKubectl: {Min: "1.26", Max: "1.33"},
+	}
+
+	report := Check("1.2.0", map[string]Version{
+		ComponentGZCTF:   {Version: "1.5.0"},
+		ComponentDocker:  {Version: "27.3.1"},
+		ComponentCompose: {Version: "2.29.7"},
+		ComponentKubectl: {NotFound: true},
+	}, matrix)
+	if report.Verdict != VerdictCompatible || len(report.Warnings) != 0 {
+		t.Errorf("Check() = %s %v, want compatible without warnings", report.Verdict, report.Warnings)
+	}
+	if got := report.Components[3].Status; got != StatusNotFound {
+		t.Errorf("kubectl status = %q, want %q", got, StatusNotFound)
+	}
+
+	report = Check("1.2.0", map[string]Version{
+		ComponentGZCTF:   {Version: "1.5.0"},
+		ComponentDocker:  {Version: "29.0.0"},
+		ComponentCompose: {Err: errors.New("docker: 'compose' is not a docker command")},
+		ComponentKubectl: {NotFound: true},
+	}, matrix)
+	if report.Verdict != VerdictUntested {
+		t.Errorf("Check() with docker 29 = %s, want untested", report.Verdict)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "docker 29.0.0") {
+		t.Errorf("Check() warnings = %v, want one about docker 29.0.0", report.Warnings)
+	}
+	if got := report.Components[2]; got.Status != StatusUnknown || got.Detail == "" {
+		t.Errorf("compose = %+v, want unknown with the error", got)
+	}
+
+	report = Check("1.2.0", map[string]Version{ComponentGZCTF: {Err: errors.New("no compose file")}}, matrix)
+	if report.Verdict != VerdictUntested || len(report.Warnings) != 1 {
+		t.Errorf("Check() without a GZCTF version = %s %v, want untested with a warning", report.Verdict, report.Warnings)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yml")
+	if err := os.WriteFile(composeFile, []byte("services:\n  gzctf:\n    image: gztime/gzctf:latest\n  db:\n    image: postgres:16\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := runCommand
+	defer func() { runCommand = orig }()
+	runCommand = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		switch command := name + " " + strings.Join(args, " "); {
+		case strings.HasPrefix(command, "docker image inspect"):
+			if args[len(args)-1] != "gztime/gzctf:latest" {
+				t.Errorf("inspected image %s, want gztime/gzctf:latest", args[len(args)-1])
+			}
+			return []byte("v1.5.2\n"), nil
+		case command == "docker version --format {{.Server.Version}}":
+			return nil, errors.New("cannot connect to the Docker daemon")
+		case command == "docker version --format {{.Client.Version}}":
+			return []byte("27.3.1\n"), nil
+		case command == "docker compose version --short":
+			return []byte("2.29.7\n"), nil
+		case name == "kubectl":
+			return nil, &exec.Error{Name: "kubectl", Err: exec.ErrNotFound}
+		}
+		t.Errorf("unexpected command %s %v", name, args)
+		return nil, errors.New("unexpected command")
+	}
+
+	versions := Detect(context.Background(), Options{ComposeFile: composeFile})
+	if got := versions[ComponentGZCTF]; got.Version != "v1.5.2" || got.Source != "image label" {
+		t.Errorf("gzctf = %+v, want v1.5.2 from the image label", got)
+	}
+	if got := versions[ComponentDocker]; got.Version != "27.3.1" || !strings.HasPrefix(got.Source, "client") {
+		t.Errorf("docker = %+v, want the 27.3.1 client", got)
+	}
+	if got := versions[ComponentCompose]; got.Version != "2.29.7" {
+		t.Errorf("compose = %+v, want 2.29.7", got)
+	}
+	if got := versions[ComponentKubectl]; !got.NotFound {
+		t.Errorf("kubectl = %+v, want not found", got)
+	}
+
+	versions = Detect(context.Background(), Options{GZCTFVersion: "1.4.0", ComposeFile: composeFile})
+	if got := versions[ComponentGZCTF]; got.Version != "1.4.0" || got.Source != "--gzctf-version" {
+		t.Errorf("gzctf with an override = %+v, want 1.4.0", got)
+	}
+}
+
+func TestDetectGZCTF_Server(t *testing.T) {
+	var body string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != serverVersionPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	orig := runCommand
+	defer func() { runCommand = orig }()
+	runCommand = func(_ context.Context, _ string, _ ...string) ([]byte, error) {
+		return nil, errors.New("image not pulled")
+	}
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yml")
+	if err := os.WriteFile(composeFile, []byte("services:\n  gzctf:\n    image: gztime/gzctf:v1.4.0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range []string{`{"tag": "v1.6.1", "sha": "abc"}`, `"v1.6.1"`, "v1.6.1\n"} {
+		body = b
+		got := detectGZCTF(context.Background(), Options{ServerURL: server.URL + "/", ComposeFile: composeFile})
+		if got.Version != "v1.6.1" || got.Source != "server" {
+			t.Errorf("gzctf with server answer %q = %+v, want v1.6.1 from the server", b, got)
+		}
+	}
+
+	body = "<!DOCTYPE html>"
+	if got := detectGZCTF(context.Background(), Options{ServerURL: server.URL, ComposeFile: composeFile}); got.Version != "v1.4.0" || got.Source != "image tag" {
+		t.Errorf("gzctf without a server version = %+v, want v1.4.0 from the compose file", got)
+	}
+	status = http.StatusNotFound
+	if got := detectGZCTF(context.Background(), Options{ServerURL: server.URL}); got.Err == nil {
+		t.Errorf("gzctf without server version or compose file = %+v, want an error", got)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"gztime/gzctf:v1.5.0":                      "v1.5.0",
+		"gztime/gzctf":                             "",
+		"registry.local:5000/gzctf":                "",
+		"registry.local:5000/gzctf:1.4":            "1.4",
+		"gztime/gzctf:1.5@sha256:0123456789abcdef": "1.5",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
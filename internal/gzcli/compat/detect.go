@@ -0,0 +1,223 @@
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// imageVersionLabel is the OCI label GZCTF images carry their release in
+const imageVersionLabel = "org.opencontainers.image.version"
+
+// serverVersionPath is the endpoint a GZCTF server reports its release on
+const serverVersionPath = "/api/version"
+
+// runCommand runs a command and returns its standard output. Replaced in tests.
+var runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	//nolint:gosec // G204: Fixed version commands of the container tooling
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// Options selects where Detect reads the GZCTF version
+type Options struct {
+	GZCTFVersion string // Used as is when set
+	ServerURL    string // URL of the configured GZCTF server, asked first
+	ComposeFile  string // Compose file of the GZCTF deployment, e.g. .gzctf/compose.yml
+}
+
+// Detect reads the versions of GZCTF, Docker, Docker Compose and kubectl.
+// Without Options.GZCTFVersion, the GZCTF version is the one the configured
+// server reports, or else the version label of the local GZCTF image of the
+// compose file, or else its tag.
+func Detect(ctx context.Context, opts Options) map[string]Version {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return map[string]Version{
+		ComponentGZCTF:   detectGZCTF(ctx, opts),
+		ComponentDocker:  detectDocker(ctx),
+		ComponentCompose: detectCommand(ctx, "docker", "compose", "version", "--short"),
+		ComponentKubectl: detectKubectl(ctx),
+	}
+}
+
+// detectCommand runs a command printing a bare version
+func detectCommand(ctx context.Context, name string, args ...string) Version {
+	output, err := runCommand(ctx, name, args...)
+	if err != nil {
+		return commandError(err)
+	}
+	return Version{Version: strings.TrimSpace(string(output)), Source: name}
+}
+
+// detectDocker reads the Docker engine version, falling back to the client
+// when the daemon is not reachable
+func detectDocker(ctx context.Context) Version {
+	if output, err := runCommand(ctx, "docker", "version", "--format", "{{.Server.Version}}"); err == nil {
+		if v := strings.TrimSpace(string(output)); v != "" {
+			return Version{Version: v, Source: "engine"}
+		}
+	}
+	output, err := runCommand(ctx, "docker", "version", "--format", "{{.Client.Version}}")
+	if err != nil {
+		return commandError(err)
+	}
+	return Version{Version: strings.TrimSpace(string(output)), Source: "client, engine unreachable"}
+}
+
+// detectKubectl reads the kubectl client version
+func detectKubectl(ctx context.Context) Version {
+	output, err := runCommand(ctx, "kubectl", "version", "--client", "-o", "json")
+	if err != nil {
+		return commandError(err)
+	}
+	var info struct {
+		ClientVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"clientVersion"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil || info.ClientVersion.GitVersion == "" {
+		return Version{Err: fmt.Errorf("unexpected kubectl version output")}
+	}
+	return Version{Version: info.ClientVersion.GitVersion, Source: "client"}
+}
+
+// detectGZCTF reads the GZCTF version from the override, the configured
+// server or the GZCTF image of the compose file
+func detectGZCTF(ctx context.Context, opts Options) Version {
+	if opts.GZCTFVersion != "" {
+		return Version{Version: opts.GZCTFVersion, Source: "--gzctf-version"}
+	}
+	var serverErr error
+	if opts.ServerURL != "" {
+		v, err := serverVersion(ctx, opts.ServerURL)
+		if err == nil {
+			return Version{Version: v, Source: "server"}
+		}
+		serverErr = err
+	}
+	if opts.ComposeFile == "" {
+		if serverErr != nil {
+			return Version{Err: fmt.Errorf("server: %w, and no GZCTF compose file", serverErr)}
+		}
+		return Version{Err: fmt.Errorf("no GZCTF server or compose file")}
+	}
+
+	image, err := gzctfImage(opts.ComposeFile)
+	if err != nil {
+		return Version{Err: err}
+	}
+	format := fmt.Sprintf("{{index .Config.Labels %q}}", imageVersionLabel)
+	if output, err := runCommand(ctx, "docker", "image", "inspect", "--format", format, image); err == nil {
+		if v := strings.TrimSpace(string(output)); v != "" && v != "<no value>" {
+			return Version{Version: v, Source: "image label"}
+		}
+	}
+	if tag := imageTag(image); tag != "" {
+		if _, ok := parseVersion(tag); ok {
+			return Version{Version: tag, Source: "image tag"}
+		}
+	}
+	return Version{Err: fmt.Errorf("image %s has no version tag and is not pulled", image)}
+}
+
+// serverVersion asks a GZCTF server for its release. The endpoint answers a
+// JSON object with the release in its tag or version field, or the bare
+// release as a JSON string or text.
+func serverVersion(ctx context.Context, serverURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(serverURL, "/")+serverVersionPath, nil)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s answered %s", serverVersionPath, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", err
+	}
+
+	var info struct {
+		Tag     string `json:"tag"`
+		Version string `json:"version"`
+	}
+	var bare string
+	version := strings.TrimSpace(string(body))
+	switch {
+	case json.Unmarshal(body, &info) == nil:
+		version = info.Tag
+		if version == "" {
+			version = info.Version
+		}
+	case json.Unmarshal(body, &bare) == nil:
+		version = bare
+	}
+	if _, ok := parseVersion(version); !ok {
+		return "", fmt.Errorf("%s reported no version", serverVersionPath)
+	}
+	return version, nil
+}
+
+// gzctfImage returns the image of the gzctf service of a compose file
+func gzctfImage(composeFile string) (string, error) {
+	//nolint:gosec // G304: Compose file of the workspace
+	data, err := os.ReadFile(composeFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", composeFile, err)
+	}
+	var compose struct {
+		Services map[string]struct {
+			Image string `yaml:"image"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", composeFile, err)
+	}
+	if service, ok := compose.Services["gzctf"]; ok && service.Image != "" {
+		return service.Image, nil
+	}
+	return "", fmt.Errorf("%s has no gzctf service image", composeFile)
+}
+
+// imageTag returns the tag of an image reference, or "" without one
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// commandError converts a failed version command into a Version
+func commandError(err error) Version {
+	if errors.Is(err, exec.ErrNotFound) {
+		return Version{NotFound: true}
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return Version{Err: fmt.Errorf("%s", strings.TrimSpace(firstLine(string(exitErr.Stderr))))}
+	}
+	return Version{Err: err}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -4,9 +4,12 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"text/template"
@@ -46,6 +49,7 @@ type ChallengeYaml struct {
 	Author            string                 `yaml:"author"`
 	Description       string                 `yaml:"description"`
 	Flags             []string               `yaml:"flags"`
+	FlagTemplate      string                 `yaml:"flag_template,omitempty"` // Flag with GZCTF placeholders, e.g. flag{{{.team_hash}}}
 	Value             int                    `yaml:"value"`
 	Provide           *string                `yaml:"provide,omitempty"`
 	Visible           *bool                  `yaml:"visible"`
@@ -57,8 +61,21 @@ type ChallengeYaml struct {
 	DisableBloodBonus bool                   `yaml:"disableBloodBonus"`
 	DeadlineUtc       int64                  `yaml:"deadlineUtc"`
 	SubmissionLimit   int                    `yaml:"submissionLimit"`
+	ReleaseAt         *time.Time             `yaml:"release_at,omitempty"` // Kept disabled until then, when the watcher enables it
+	Tags              []string               `yaml:"tags,omitempty"`
+	Solver            string                 `yaml:"solver,omitempty"` // Solver language scaffolded by "gzcli structure"
+	Watch             *WatchConfig           `yaml:"watch,omitempty"`
+	Verify            *VerifyConfig          `yaml:"verify,omitempty"`
+	Services          []string               `yaml:"services,omitempty"` // Shared services of the event this challenge depends on
 	Category          string                 `yaml:"-"`
 	Cwd               string                 `yaml:"-"`
+	MaxAttachmentSize int64                  `yaml:"-"` // Bytes; set from event defaults, 0 means unlimited
+	AllowedTags       []string               `yaml:"-"` // Set from event defaults; empty allows any tag
+	// Attachment content policy, set from event defaults
+	ForbiddenExtensions []string `yaml:"-"`
+	RequireReadme       bool     `yaml:"-"`
+	// Attach the local attachment even when its hash is unchanged
+	ForceUpload bool `yaml:"-"`
 }
 
 // Container represents container configuration
@@ -73,10 +90,32 @@ type Container struct {
 	EnableTrafficCapture bool   `yaml:"enableTrafficCapture"`
 }
 
+// WatchConfig overrides how the watcher schedules syncs of a challenge
+type WatchConfig struct {
+	Debounce time.Duration `yaml:"debounce,omitempty"` // Quiet period after a change before syncing
+	Cooldown time.Duration `yaml:"cooldown,omitempty"` // Minimum time between the end of one sync and the start of the next
+}
+
+// VerifyConfig has the watcher run the challenge's solver against the
+// deployed challenge after each sync
+type VerifyConfig struct {
+	Enabled *bool         `yaml:"enabled,omitempty"` // Defaults to true when the block is present
+	Host    string        `yaml:"host,omitempty"`    // Defaults to the PublicEntry of the container provider
+	Port    int           `yaml:"port,omitempty"`    // Defaults to the exposePort of the container
+	Timeout time.Duration `yaml:"timeout,omitempty"` // Defaults to 2 minutes
+}
+
+// VerifyEnabled reports whether the watcher verifies the challenge with its
+// solver after syncing it
+func (c ChallengeYaml) VerifyEnabled() bool {
+	return c.Verify != nil && (c.Verify.Enabled == nil || *c.Verify.Enabled)
+}
+
 // ScriptConfig represents a script configuration with interval and execute parameters
 type ScriptConfig struct {
-	Execute  string        `yaml:"execute,omitempty"`
-	Interval time.Duration `yaml:"interval,omitempty"`
+	Execute          string        `yaml:"execute,omitempty"`
+	Interval         time.Duration `yaml:"interval,omitempty"`
+	FailureThreshold int           `yaml:"failureThreshold,omitempty"` // Consecutive failures before a healthcheck redeploys
 }
 
 // ScriptValue holds either a simple command string or a complex ScriptConfig
@@ -135,10 +174,75 @@ func (sv *ScriptValue) HasInterval() bool {
 	return sv.Complex != nil && sv.Complex.Interval > 0
 }
 
+// GetFailureThreshold returns the consecutive failures configured for a
+// healthcheck script, or 0 to use the default
+func (sv *ScriptValue) GetFailureThreshold() int {
+	if sv.Complex != nil {
+		return sv.Complex.FailureThreshold
+	}
+	return 0
+}
+
 // Dashboard represents dashboard configuration
 type Dashboard struct {
-	Type   string `yaml:"type"`
-	Config string `yaml:"config"`
+	Type     string    `yaml:"type"`
+	Config   string    `yaml:"config"`
+	WarmPool *WarmPool `yaml:"warmPool,omitempty"`
+	Idle     *Idle     `yaml:"idle,omitempty"`
+	// Resources caps the CPU, memory and processes of every container of an
+	// instance
+	Resources *Resources `yaml:"resources,omitempty"`
+	// IsolatedNetwork runs every instance on its own bridge network, so
+	// instances cannot reach each other or other containers
+	IsolatedNetwork bool `yaml:"isolatedNetwork,omitempty"`
+	// TTL stops every instance after a hard lifetime, even with players
+	// connected
+	TTL *TTL `yaml:"ttl,omitempty"`
+	// Gate makes players solve a proof of work or a CAPTCHA before starting
+	// or restarting an instance
+	Gate *Gate `yaml:"gate,omitempty"`
+}
+
+// TTL is the hard lifetime of a launcher instance. Players on the challenge
+// page see the remaining time and may vote to extend it.
+type TTL struct {
+	Duration      time.Duration `yaml:"duration"`                // Lifetime from the start of the instance, e.g. 2h
+	Extension     time.Duration `yaml:"extension,omitempty"`     // Time added by an approved extension vote; 0 uses the default
+	MaxExtensions int           `yaml:"maxExtensions,omitempty"` // Extensions allowed per start; 0 disables extending
+}
+
+// Gate keeps bots from starting launcher instances: players solve a
+// hashcash-style proof of work or a Turnstile or hCaptcha CAPTCHA on the
+// challenge page before each start or restart request
+type Gate struct {
+	Type       string `yaml:"type"`                 // pow, turnstile or hcaptcha
+	Difficulty int    `yaml:"difficulty,omitempty"` // Leading zero bits of a pow solution; 0 uses the default
+	SiteKey    string `yaml:"siteKey,omitempty"`    // Site key of the turnstile or hcaptcha widget
+	SecretEnv  string `yaml:"secretEnv,omitempty"`  // Environment variable holding the turnstile or hcaptcha secret key
+}
+
+// Resources are the resource limits of a launched container
+type Resources struct {
+	CPUs      string `yaml:"cpus,omitempty"`      // CPU cores, e.g. "0.5"
+	Memory    string `yaml:"memory,omitempty"`    // Memory limit, e.g. "256m" or "1g"
+	PidsLimit int    `yaml:"pidsLimit,omitempty"` // Maximum processes
+}
+
+// Idle configures when a launcher instance without connected challenge pages
+// is auto-stopped. In traffic mode, an instance whose containers still send
+// or receive network traffic, e.g. players using nc or curl with the page
+// closed, is kept running.
+type Idle struct {
+	Mode        string        `yaml:"mode,omitempty"`        // websocket (default) or traffic
+	MinTraffic  string        `yaml:"minTraffic,omitempty"`  // Traffic per grace period keeping the instance running, e.g. "64KiB"
+	GracePeriod time.Duration `yaml:"gracePeriod,omitempty"` // Delay before an idle instance is stopped; 0 uses the default
+}
+
+// WarmPool keeps pre-started idle launcher instances of a challenge with a
+// slow cold start, so requested instances are handed out immediately
+type WarmPool struct {
+	Size   int           `yaml:"size"`             // Idle instances kept running
+	MaxAge time.Duration `yaml:"maxAge,omitempty"` // Idle instances older than this are replaced; 0 keeps them
 }
 
 func generateSlug(eventName string, challengeConf ChallengeYaml) string {
@@ -184,18 +288,21 @@ func processChallengeFile(path string, category string, content []byte) (Challen
 
 // ProcessChallengeTemplate processes challenge template and returns final challenge
 func ProcessChallengeTemplate(eventName string, content []byte, challenge ChallengeYaml, path string) (ChallengeYaml, error) {
-	t, err := template.New("chall").Parse(string(content))
+	t, err := template.New("chall").Parse(flagBraces.Replace(string(content)))
 	if err != nil {
 		log.ErrorH2("template error: %v", err)
 		return challenge, nil
 	}
 
-	var buf bytes.Buffer
-	err = t.Execute(&buf, map[string]string{
+	data := map[string]string{
 		"host": hostCache.host,
 		"slug": generateSlug(eventName, challenge),
-	})
-	if err != nil {
+	}
+	for key, placeholder := range flagTemplateData {
+		data[key] = placeholder
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
 		return challenge, fmt.Errorf("template execution error: %w", err)
 	}
 
@@ -203,7 +310,7 @@ func ProcessChallengeTemplate(eventName string, content []byte, challenge Challe
 		return challenge, fmt.Errorf("yaml parse error: %w %s", err, path)
 	}
 
-	return challenge, nil
+	return applyFlagTemplate(challenge), nil
 }
 
 // walkCategoryPath walks a category directory and processes challenge files
@@ -234,6 +341,100 @@ func walkCategoryPath(eventName, categoryPath, category string, challengeChan ch
 	})
 }
 
+// ListChallengeFiles returns the challenge definition files of an event,
+// sorted by path, without parsing them
+func ListChallengeFiles(eventName string) ([]string, error) {
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event path: %w", err)
+	}
+
+	var files []string
+	for _, category := range CHALLENGE_CATEGORY {
+		categoryPath := filepath.Join(eventPath, category)
+		if _, err := os.Stat(categoryPath); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.Walk(categoryPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !challengeFileRegex.MatchString(info.Name()) {
+				return err
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("category %s: %w", category, err)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// UnknownCategoryDirs returns the top-level directories of an event that
+// hold challenge files but are not categories, so their challenges are never
+// synced (e.g. "web" instead of "Web")
+func UnknownCategoryDirs(eventName string) ([]string, error) {
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event path: %w", err)
+	}
+	entries, err := os.ReadDir(eventPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || slices.Contains(CHALLENGE_CATEGORY, name) {
+			continue
+		}
+		found := false
+		err := filepath.WalkDir(filepath.Join(eventPath, name), func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && challengeFileRegex.MatchString(d.Name()) {
+				found = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			dirs = append(dirs, name)
+		}
+	}
+	return dirs, nil
+}
+
+// LoadChallengeFile parses one challenge definition file of an event, as
+// listed by ListChallengeFiles, without applying the event defaults
+func LoadChallengeFile(eventName, path string) (ChallengeYaml, error) {
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return ChallengeYaml{}, fmt.Errorf("failed to get event path: %w", err)
+	}
+	rel, err := filepath.Rel(eventPath, path)
+	if err != nil {
+		return ChallengeYaml{}, err
+	}
+	category, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+
+	//nolint:gosec // G304: File paths come from validated challenges directory
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ChallengeYaml{}, fmt.Errorf("reading file error: %w", err)
+	}
+	challenge, err := processChallengeFile(path, category, content)
+	if err != nil {
+		return challenge, err
+	}
+	return ProcessChallengeTemplate(eventName, content, challenge, path)
+}
+
 // processCategoryAsync processes a category directory asynchronously
 func processCategoryAsync(eventName, dir, category string, challengeChan chan<- ChallengeYaml, errChan chan<- error, wg *sync.WaitGroup) {
 	defer wg.Done()
@@ -271,7 +472,7 @@ func GetChallengesYaml(config *Config) ([]ChallengeYaml, error) {
 	go func() {
 		var challenges []ChallengeYaml
 		for c := range challengeChan {
-			challenges = append(challenges, c)
+			challenges = append(challenges, ApplyChallengeDefaults(c, config.Defaults))
 		}
 		resultChan <- challenges
 	}()
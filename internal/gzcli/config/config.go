@@ -2,6 +2,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
@@ -15,11 +16,14 @@ const (
 
 // Config represents the combined application configuration (server + event)
 type Config struct {
-	Url         string       `yaml:"url"`
-	Creds       gzapi.Creds  `yaml:"creds"`
-	Event       gzapi.Game   `yaml:"event"`
-	Appsettings *AppSettings `yaml:"-"`
-	EventName   string       `yaml:"-"` // Current event name
+	Url         string             `yaml:"url"`
+	Creds       gzapi.Creds        `yaml:"creds"`
+	Event       gzapi.Game         `yaml:"event"`
+	Appsettings *AppSettings       `yaml:"-"`
+	EventName   string             `yaml:"-"` // Current event name
+	Defaults    *ChallengeDefaults `yaml:"-"` // Event-level challenge defaults
+	Sync        SyncConfig         `yaml:"-"` // Server-level sync tuning
+	Email       MailConfig         `yaml:"-"` // Server-level email delivery
 }
 
 // loadConfigFromCache loads cached config data (backward compatibility wrapper)
@@ -91,6 +95,9 @@ func ensureGameExistsWithKey(config *Config, api *gzapi.GZAPI, setCache func(str
 	}
 
 	game, err := api.GetGameByTitle(config.Event.Title)
+	if err != nil && !errors.Is(err, gzapi.ErrNotFound) {
+		return fmt.Errorf("API games fetch error: %w", err)
+	}
 	if err != nil {
 		log.Info("Game '%s' not found by title, creating new game...", config.Event.Title)
 		_, err = createNewGame(config, api)
@@ -146,6 +153,9 @@ func GetConfigWithEvent(api *gzapi.GZAPI, eventName string, getCache func(string
 		Creds:     serverConfig.Creds,
 		Event:     eventConfig.Game,
 		EventName: eventName,
+		Defaults:  eventConfig.Defaults,
+		Sync:      serverConfig.Sync,
+		Email:     serverConfig.Email,
 	}
 
 	// Load cache for this specific event
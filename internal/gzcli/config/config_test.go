@@ -82,6 +82,81 @@ func TestScriptValue_UnmarshalYAML_Invalid(t *testing.T) {
 	}
 }
 
+func TestChallengeYaml_ReleaseAt(t *testing.T) {
+	var challenge ChallengeYaml
+	if err := yaml.Unmarshal([]byte("name: Wave 2\nrelease_at: 2024-05-01T14:00:00+02:00\n"), &challenge); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if challenge.ReleaseAt == nil {
+		t.Fatal("Expected release_at to be set")
+	}
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC); !challenge.ReleaseAt.Equal(want) {
+		t.Errorf("Expected release_at %v, got %v", want, challenge.ReleaseAt)
+	}
+
+	challenge = ChallengeYaml{}
+	if err := yaml.Unmarshal([]byte("name: Wave 1\n"), &challenge); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if challenge.ReleaseAt != nil {
+		t.Errorf("Expected no release_at, got %v", challenge.ReleaseAt)
+	}
+}
+
+func TestProcessChallengeTemplate_FlagTemplate(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantTemplate  string
+		wantContainer string
+		wantFlags     []string
+	}{
+		{
+			name:          "dynamic container",
+			content:       "name: Dyn\ntype: DynamicContainer\nflag_template: flag{{{.team_hash}}}\n",
+			wantTemplate:  "flag{[TEAM_HASH]}",
+			wantContainer: "flag{[TEAM_HASH]}",
+		},
+		{
+			name:          "leet marker",
+			content:       "name: Dyn\ntype: DynamicContainer\nflag_template: \"{{.leet}}flag{{{.guid}}}\"\n",
+			wantTemplate:  "[LEET]flag{[GUID]}",
+			wantContainer: "[LEET]flag{[GUID]}",
+		},
+		{
+			name:         "static flag",
+			content:      "name: Static\ntype: StaticAttachment\nflags:\n  - flag{one}\nflag_template: flag{two}\n",
+			wantTemplate: "flag{two}",
+			wantFlags:    []string{"flag{one}", "flag{two}"},
+		},
+		{
+			name:         "per-team placeholder on a static challenge",
+			content:      "name: Static\ntype: StaticAttachment\nflag_template: flag{{{.team_hash}}}\n",
+			wantTemplate: "flag{[TEAM_HASH]}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge ChallengeYaml
+			if err := yaml.Unmarshal([]byte(tt.content), &challenge); err != nil {
+				t.Fatalf("Unmarshal() failed: %v", err)
+			}
+			got, err := ProcessChallengeTemplate("ctf", []byte(tt.content), challenge, "challenge.yaml")
+			if err != nil {
+				t.Fatalf("ProcessChallengeTemplate() failed: %v", err)
+			}
+			if got.FlagTemplate != tt.wantTemplate || got.Container.FlagTemplate != tt.wantContainer {
+				t.Errorf("flag_template = %q, container.flagTemplate = %q, want %q and %q",
+					got.FlagTemplate, got.Container.FlagTemplate, tt.wantTemplate, tt.wantContainer)
+			}
+			if strings.Join(got.Flags, ",") != strings.Join(tt.wantFlags, ",") {
+				t.Errorf("flags = %v, want %v", got.Flags, tt.wantFlags)
+			}
+		})
+	}
+}
+
 func TestGenerateSlug(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+)
+
+// ChallengeDefaults holds event-level defaults from the `defaults` block of
+// .gzevent. They are merged into every challenge of the event at load time.
+//
+// Precedence: a value set in challenge.yaml always wins; defaults only fill
+// in what the challenge leaves empty. Tags are additive (event tags first).
+type ChallengeDefaults struct {
+	Value int `yaml:"value,omitempty"`
+	// AuthorSuffix is appended to every author, e.g. "@ Org" gives "alice @ Org"
+	AuthorSuffix string   `yaml:"authorSuffix,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`
+	// AllowedTags, when set, are the only tags challenges of the event may carry
+	AllowedTags []string `yaml:"allowedTags,omitempty"`
+	// MaxAttachmentSizeMB rejects local attachments larger than this size (0 disables)
+	MaxAttachmentSizeMB int `yaml:"maxAttachmentSizeMB,omitempty"`
+	// ForbiddenExtensions rejects local attachments containing files with
+	// these extensions, e.g. ".env" or ".git"
+	ForbiddenExtensions []string `yaml:"forbiddenExtensions,omitempty"`
+	// RequireReadme rejects local attachments without a README at their top level
+	RequireReadme bool `yaml:"requireReadme,omitempty"`
+	// Verify has the watcher run the solver of every challenge after syncing
+	// it, unless the challenge sets verify.enabled: false
+	Verify bool `yaml:"verify,omitempty"`
+}
+
+// eventDefaultsFile is the subset of .gzevent holding challenge defaults
+type eventDefaultsFile struct {
+	Defaults *ChallengeDefaults `yaml:"defaults"`
+}
+
+// loadChallengeDefaults reads the `defaults` block from an event's .gzevent
+func loadChallengeDefaults(eventDir string) (*ChallengeDefaults, error) {
+	var file eventDefaultsFile
+	if err := fileutil.ParseYamlFromFile(filepath.Join(eventDir, GZEVENT_FILE), &file); err != nil {
+		return nil, err
+	}
+	if file.Defaults == nil {
+		return nil, nil
+	}
+	if err := file.Defaults.validate(); err != nil {
+		return nil, fmt.Errorf("defaults.%w", err)
+	}
+	return file.Defaults, nil
+}
+
+// validate checks the defaults and normalizes forbidden extensions to a
+// lowercase ".ext"
+func (d *ChallengeDefaults) validate() error {
+	if d.MaxAttachmentSizeMB < 0 {
+		return fmt.Errorf("maxAttachmentSizeMB: must not be negative")
+	}
+	for i, ext := range d.ForbiddenExtensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" || ext == "." || strings.ContainsAny(ext, `/\`) {
+			return fmt.Errorf("forbiddenExtensions: invalid extension %q", d.ForbiddenExtensions[i])
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		d.ForbiddenExtensions[i] = ext
+	}
+	return nil
+}
+
+// GetChallengeDefaults reads the challenge defaults of an event. Events
+// without a .gzevent or a `defaults` block get none.
+func GetChallengeDefaults(eventName string) (*ChallengeDefaults, error) {
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := loadChallengeDefaults(eventPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return defaults, err
+}
+
+// ApplyChallengeDefaults merges event defaults into a challenge
+func ApplyChallengeDefaults(challenge ChallengeYaml, defaults *ChallengeDefaults) ChallengeYaml {
+	if defaults == nil {
+		return challenge
+	}
+
+	if challenge.Value == 0 {
+		challenge.Value = defaults.Value
+	}
+
+	if defaults.AuthorSuffix != "" && challenge.Author != "" && !strings.HasSuffix(challenge.Author, defaults.AuthorSuffix) {
+		challenge.Author = challenge.Author + " " + defaults.AuthorSuffix
+	}
+
+	if len(defaults.Tags) > 0 {
+		seen := make(map[string]struct{}, len(defaults.Tags)+len(challenge.Tags))
+		tags := make([]string, 0, len(defaults.Tags)+len(challenge.Tags))
+		for _, tag := range append(append([]string{}, defaults.Tags...), challenge.Tags...) {
+			if _, dup := seen[tag]; dup || tag == "" {
+				continue
+			}
+			seen[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+		challenge.Tags = tags
+	}
+
+	challenge.AllowedTags = defaults.AllowedTags
+
+	if defaults.Verify && challenge.Verify == nil {
+		challenge.Verify = &VerifyConfig{}
+	}
+
+	return ApplyAttachmentPolicy(challenge, defaults)
+}
+
+// ApplyAttachmentPolicy sets the attachment limits of the event defaults on
+// a challenge, leaving its other fields untouched
+func ApplyAttachmentPolicy(challenge ChallengeYaml, defaults *ChallengeDefaults) ChallengeYaml {
+	if defaults == nil {
+		return challenge
+	}
+	if challenge.MaxAttachmentSize == 0 && defaults.MaxAttachmentSizeMB > 0 {
+		challenge.MaxAttachmentSize = int64(defaults.MaxAttachmentSizeMB) * 1024 * 1024
+	}
+	challenge.ForbiddenExtensions = defaults.ForbiddenExtensions
+	challenge.RequireReadme = defaults.RequireReadme
+	return challenge
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyChallengeDefaults(t *testing.T) {
+	defaults := &ChallengeDefaults{
+		Value:               500,
+		AuthorSuffix:        "@ Org",
+		Tags:                []string{"ctf2025", "web"},
+		MaxAttachmentSizeMB: 2,
+	}
+
+	tests := []struct {
+		name      string
+		challenge ChallengeYaml
+		want      ChallengeYaml
+	}{
+		{
+			name:      "fills empty fields",
+			challenge: ChallengeYaml{Author: "alice"},
+			want: ChallengeYaml{
+				Value:             500,
+				Author:            "alice @ Org",
+				Tags:              []string{"ctf2025", "web"},
+				MaxAttachmentSize: 2 * 1024 * 1024,
+			},
+		},
+		{
+			name:      "challenge values take precedence",
+			challenge: ChallengeYaml{Value: 100, Author: "bob @ Org", Tags: []string{"web", "easy"}},
+			want: ChallengeYaml{
+				Value:             100,
+				Author:            "bob @ Org",
+				Tags:              []string{"ctf2025", "web", "easy"},
+				MaxAttachmentSize: 2 * 1024 * 1024,
+			},
+		},
+		{
+			name:      "empty author stays empty",
+			challenge: ChallengeYaml{Value: 50},
+			want: ChallengeYaml{
+				Value:             50,
+				Tags:              []string{"ctf2025", "web"},
+				MaxAttachmentSize: 2 * 1024 * 1024,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyChallengeDefaults(tt.challenge, defaults)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyChallengeDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyChallengeDefaults_Nil(t *testing.T) {
+	challenge := ChallengeYaml{Name: "x", Value: 10}
+	if got := ApplyChallengeDefaults(challenge, nil); !reflect.DeepEqual(got, challenge) {
+		t.Errorf("ApplyChallengeDefaults(nil) = %+v, want unchanged", got)
+	}
+}
+
+func TestApplyChallengeDefaults_Verify(t *testing.T) {
+	disabled := false
+	defaults := &ChallengeDefaults{Verify: true}
+
+	if got := ApplyChallengeDefaults(ChallengeYaml{Name: "x"}, defaults); !got.VerifyEnabled() {
+		t.Error("expected verification enabled by the event defaults")
+	}
+	optOut := ChallengeYaml{Name: "x", Verify: &VerifyConfig{Enabled: &disabled}}
+	if got := ApplyChallengeDefaults(optOut, defaults); got.VerifyEnabled() {
+		t.Error("expected verify.enabled: false to win over the event defaults")
+	}
+	optIn := ChallengeYaml{Name: "x", Verify: &VerifyConfig{Port: 1337}}
+	if got := ApplyChallengeDefaults(optIn, &ChallengeDefaults{}); !got.VerifyEnabled() || got.Verify.Port != 1337 {
+		t.Errorf("expected a verify block to enable verification, got %+v", got.Verify)
+	}
+	if got := ApplyChallengeDefaults(ChallengeYaml{Name: "x"}, &ChallengeDefaults{}); got.VerifyEnabled() {
+		t.Error("expected verification disabled by default")
+	}
+}
+
+func TestGetEventConfig_Defaults(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	eventName := "defaults-event"
+	eventDir := filepath.Join(tmpDir, EVENTS_DIR, eventName)
+	if err := os.MkdirAll(eventDir, 0750); err != nil {
+		t.Fatalf("Failed to create event dir: %v", err)
+	}
+
+	gzeventContent := `title: "Defaults Event"
+start: "2024-10-01T12:00:00Z"
+end: "2024-10-02T12:00:00Z"
+defaults:
+  value: 300
+  authorSuffix: "@ Org"
+  tags: ["ctf"]
+  maxAttachmentSizeMB: 10
+`
+	//nolint:gosec // G306: Test file permissions are acceptable
+	if err := os.WriteFile(filepath.Join(eventDir, GZEVENT_FILE), []byte(gzeventContent), 0644); err != nil {
+		t.Fatalf("Failed to create .gzevent: %v", err)
+	}
+
+	eventConfig, err := GetEventConfig(eventName)
+	if err != nil {
+		t.Fatalf("GetEventConfig() error = %v", err)
+	}
+	if eventConfig.Title != "Defaults Event" {
+		t.Errorf("Event title = %q, want %q", eventConfig.Title, "Defaults Event")
+	}
+
+	want := &ChallengeDefaults{Value: 300, AuthorSuffix: "@ Org", Tags: []string{"ctf"}, MaxAttachmentSizeMB: 10}
+	if !reflect.DeepEqual(eventConfig.Defaults, want) {
+		t.Errorf("Defaults = %+v, want %+v", eventConfig.Defaults, want)
+	}
+}
+
+func TestApplyChallengeDefaults_AllowedTags(t *testing.T) {
+	defaults := &ChallengeDefaults{AllowedTags: []string{"easy", "hard"}}
+	got := ApplyChallengeDefaults(ChallengeYaml{Tags: []string{"easy"}}, defaults)
+	if !reflect.DeepEqual(got.AllowedTags, defaults.AllowedTags) {
+		t.Errorf("AllowedTags = %v, want %v", got.AllowedTags, defaults.AllowedTags)
+	}
+}
+
+func TestGetChallengeDefaults_AttachmentPolicy(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	writeEvent := func(name, content string) {
+		t.Helper()
+		eventDir := filepath.Join(tmpDir, EVENTS_DIR, name)
+		if err := os.MkdirAll(eventDir, 0750); err != nil {
+			t.Fatalf("Failed to create event dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(eventDir, GZEVENT_FILE), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to create .gzevent: %v", err)
+		}
+	}
+
+	writeEvent("policy", `title: "Policy"
+defaults:
+  forbiddenExtensions: [".ENV", "git", " pyc "]
+  requireReadme: true
+`)
+	defaults, err := GetChallengeDefaults("policy")
+	if err != nil {
+		t.Fatalf("GetChallengeDefaults() error = %v", err)
+	}
+	if want := []string{".env", ".git", ".pyc"}; !reflect.DeepEqual(defaults.ForbiddenExtensions, want) {
+		t.Errorf("ForbiddenExtensions = %v, want %v", defaults.ForbiddenExtensions, want)
+	}
+
+	got := ApplyAttachmentPolicy(ChallengeYaml{Name: "x", MaxAttachmentSize: 5}, defaults)
+	if !got.RequireReadme || len(got.ForbiddenExtensions) != 3 || got.MaxAttachmentSize != 5 {
+		t.Errorf("ApplyAttachmentPolicy() = %+v", got)
+	}
+
+	writeEvent("bad", `title: "Bad"
+defaults:
+  forbiddenExtensions: ["dist/.env"]
+`)
+	if _, err := GetChallengeDefaults("bad"); err == nil {
+		t.Error("GetChallengeDefaults() accepted an extension containing a path separator")
+	}
+
+	writeEvent("none", `title: "None"
+`)
+	if defaults, err := GetChallengeDefaults("none"); err != nil || defaults != nil {
+		t.Errorf("GetChallengeDefaults() without defaults = %+v, %v; want nil, nil", defaults, err)
+	}
+}
@@ -52,7 +52,8 @@ const (
 
 // EventConfig represents event-specific configuration
 type EventConfig struct {
-	Name string // Event name (directory name)
+	Name     string             // Event name (directory name)
+	Defaults *ChallengeDefaults // Challenge defaults from the `defaults` block
 	gzapi.Game
 }
 
@@ -96,9 +97,15 @@ func GetEventConfig(eventName string) (*EventConfig, error) {
 		}
 	}
 
+	defaults, err := loadChallengeDefaults(eventDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read challenge defaults %s: %w", eventPath, err)
+	}
+
 	return &EventConfig{
-		Name: eventName,
-		Game: game,
+		Name:     eventName,
+		Defaults: defaults,
+		Game:     game,
 	}, nil
 }
 
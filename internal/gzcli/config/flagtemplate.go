@@ -0,0 +1,61 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// Placeholders GZCTF replaces when generating the flag of a team instance
+const (
+	FlagPlaceholderTeamHash = "[TEAM_HASH]"
+	FlagPlaceholderGUID     = "[GUID]"
+	FlagPlaceholderLeet     = "[LEET]"
+	FlagPlaceholderCLeet    = "[CLEET]"
+)
+
+// flagTemplateData exposes the GZCTF flag placeholders to challenge.yaml
+// templates, e.g. flag_template: "flag{{{.team_hash}}}"
+var flagTemplateData = map[string]string{
+	"team_hash": FlagPlaceholderTeamHash,
+	"guid":      FlagPlaceholderGUID,
+	"leet":      FlagPlaceholderLeet,
+	"cleet":     FlagPlaceholderCLeet,
+}
+
+// flagBraces lets flag templates write a literal brace right against a
+// template action, as in flag{{{.team_hash}}}, which text/template would
+// otherwise read as an action starting with "{"
+var flagBraces = strings.NewReplacer("{{{", `{{"{"}}{{`, "}}}", `}}{{"}"}}`)
+
+// IsDynamicFlag reports whether a flag holds placeholders GZCTF fills per
+// team, so it can only be the flag template of a DynamicContainer
+func IsDynamicFlag(flag string) bool {
+	for _, placeholder := range flagTemplateData {
+		if strings.Contains(flag, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFlagTemplate turns the flag_template of a challenge into the flag
+// configuration of its type: the container flag template of a
+// DynamicContainer, or a static flag otherwise. Conflicting definitions and
+// per-team placeholders on static challenges are left to validation.
+func applyFlagTemplate(challenge ChallengeYaml) ChallengeYaml {
+	flag := strings.TrimSpace(challenge.FlagTemplate)
+	if flag == "" {
+		return challenge
+	}
+	challenge.FlagTemplate = flag
+
+	switch {
+	case challenge.Type == "DynamicContainer":
+		if challenge.Container.FlagTemplate == "" {
+			challenge.Container.FlagTemplate = flag
+		}
+	case !IsDynamicFlag(flag) && !slices.Contains(challenge.Flags, flag):
+		challenge.Flags = append(challenge.Flags, flag)
+	}
+	return challenge
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+)
+
+// GitPullConfig selects what the watcher pulls into an event's repository,
+// from the `git` block of .gzevent
+type GitPullConfig struct {
+	// Remote to pull from (default: the upstream of the branch, or origin
+	// when Branch is set)
+	Remote string `yaml:"remote,omitempty"`
+	// Branch to check out and pull (default: the current branch)
+	Branch string `yaml:"branch,omitempty"`
+	// SparsePaths limits the checkout to these directories of the repository
+	SparsePaths []string `yaml:"sparsePaths,omitempty"`
+	// ForceReset fetches and resets hard to the remote branch instead of
+	// merging, for repositories whose history is rewritten. Local changes
+	// are discarded.
+	ForceReset bool `yaml:"forceReset,omitempty"`
+}
+
+// eventGitFile is the subset of .gzevent holding the git pull configuration
+type eventGitFile struct {
+	Git *GitPullConfig `yaml:"git"`
+}
+
+// GetGitPullConfig reads the git pull configuration of an event. Events
+// without a .gzevent or a `git` block get an empty configuration, which
+// pulls the current branch from its upstream.
+func GetGitPullConfig(eventName string) (*GitPullConfig, error) {
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	var file eventGitFile
+	if err := fileutil.ParseYamlFromFile(filepath.Join(eventPath, GZEVENT_FILE), &file); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &GitPullConfig{}, nil
+		}
+		return nil, err
+	}
+	if file.Git == nil {
+		return &GitPullConfig{}, nil
+	}
+	if err := file.Git.validate(); err != nil {
+		return nil, fmt.Errorf("invalid git configuration of event %s: %w", eventName, err)
+	}
+	return file.Git, nil
+}
+
+func (c *GitPullConfig) validate() error {
+	// Names starting with "-" would be taken for git options
+	if strings.HasPrefix(c.Remote, "-") || strings.ContainsAny(c.Remote, " \t\n") {
+		return fmt.Errorf("remote: invalid remote name %q", c.Remote)
+	}
+	if strings.HasPrefix(c.Branch, "-") || strings.ContainsAny(c.Branch, " \t\n~^:?*[\\") || strings.Contains(c.Branch, "..") {
+		return fmt.Errorf("branch: invalid branch name %q", c.Branch)
+	}
+	for i, p := range c.SparsePaths {
+		clean := path.Clean(strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(p)), "/"))
+		if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || strings.HasPrefix(clean, "-") {
+			return fmt.Errorf("sparsePaths: invalid path %q", p)
+		}
+		c.SparsePaths[i] = clean
+	}
+	return nil
+}
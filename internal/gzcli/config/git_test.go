@@ -0,0 +1,46 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetGitPullConfig(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	writeUploadEvent(t, tmpDir, "custom", `title: "Custom"
+git:
+  remote: upstream
+  branch: release/2025
+  sparsePaths: ["/web/", "pwn/heap"]
+  forceReset: true
+`)
+	writeUploadEvent(t, tmpDir, "plain", `title: "Plain"`)
+	writeUploadEvent(t, tmpDir, "badbranch", `title: "Bad"
+git:
+  branch: "--upload-pack=evil"
+`)
+	writeUploadEvent(t, tmpDir, "badpath", `title: "Bad"
+git:
+  sparsePaths: ["../outside"]
+`)
+
+	got, err := GetGitPullConfig("custom")
+	if err != nil {
+		t.Fatalf("GetGitPullConfig() error = %v", err)
+	}
+	want := &GitPullConfig{Remote: "upstream", Branch: "release/2025", SparsePaths: []string{"web", "pwn/heap"}, ForceReset: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGitPullConfig() = %+v, want %+v", got, want)
+	}
+
+	if got, err := GetGitPullConfig("plain"); err != nil || !reflect.DeepEqual(got, &GitPullConfig{}) {
+		t.Errorf("GetGitPullConfig() without git block = %+v, %v; want empty", got, err)
+	}
+	for _, event := range []string{"badbranch", "badpath"} {
+		if _, err := GetGitPullConfig(event); err == nil {
+			t.Errorf("GetGitPullConfig(%q) accepted an invalid configuration", event)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// positions maps the paths of a configuration document ("creds.password",
+// "organizations[0]") to the 1-based line they are set on
+type positions map[string]int
+
+// line returns the line of path, or of its closest parent found in the
+// document, 0 when none is
+func (p positions) line(path string) int {
+	for path != "" {
+		if line, ok := p[path]; ok {
+			return line
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return 0
+}
+
+// path returns the most nested path set on line, "" when none is
+func (p positions) path(line int) string {
+	var paths []string
+	for path, l := range p {
+		if l == line {
+			paths = append(paths, path)
+		}
+	}
+	// A sequence item holding a mapping shares its line with its first key
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
+
+// yamlPositions indexes the block mappings and sequences of a YAML document.
+// Flow collections ({...}, [...]) are not indexed; their values resolve to
+// the line of the key holding them.
+func yamlPositions(data []byte) positions {
+	type level struct {
+		indent int    // Column of the keys or "- " items of the collection
+		path   string // Path of the collection
+		seq    bool
+		items  int // Items of a sequence seen so far
+	}
+
+	pos := make(positions)
+	var stack []level
+	lastPath, lastOpen := "", false // Last key seen, and whether its value is on the next lines
+	blockIndent := -1               // Column of the key whose block scalar is being skipped
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line := strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if blockIndent >= 0 {
+			if trimmed == "" || indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent > indent {
+			stack = stack[:len(stack)-1]
+		}
+		isItem := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+		top := len(stack) - 1
+		switch {
+		case top >= 0 && stack[top].indent == indent && stack[top].seq && !isItem:
+			// A key back at the level of the mapping holding the sequence
+			stack = stack[:top]
+		case top >= 0 && stack[top].indent == indent && !stack[top].seq && isItem && lastOpen:
+			// "key:\n- item" with items at the indentation of the key
+			stack = append(stack, level{indent: indent, path: lastPath, seq: true})
+		case top < 0 || stack[top].indent < indent:
+			parent := ""
+			if top >= 0 {
+				parent = lastPath
+			}
+			stack = append(stack, level{indent: indent, path: parent, seq: isItem})
+		}
+
+		column, content := indent, trimmed
+		for content == "-" || strings.HasPrefix(content, "- ") {
+			cur := &stack[len(stack)-1]
+			itemPath := cur.path + "[" + strconv.Itoa(cur.items) + "]"
+			cur.items++
+			pos[itemPath] = lineNo
+			lastPath, lastOpen = itemPath, true
+
+			rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			if rest == "" || strings.HasPrefix(rest, "#") {
+				content = ""
+				break
+			}
+			column += len(content) - len(rest)
+			content = rest
+			nested := strings.HasPrefix(rest, "- ") || rest == "-"
+			if !nested {
+				if _, _, ok := splitYamlKey(rest); !ok {
+					content = ""
+					break
+				}
+			}
+			stack = append(stack, level{indent: column, path: itemPath, seq: nested})
+		}
+		if content == "" {
+			continue
+		}
+
+		key, value, ok := splitYamlKey(content)
+		if !ok {
+			continue
+		}
+		path := joinPath(stack[len(stack)-1].path, key)
+		pos[path] = lineNo
+		lastPath = path
+		lastOpen = value == "" || strings.HasPrefix(value, "#")
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = column
+		}
+	}
+	return pos
+}
+
+// splitYamlKey splits a "key: value" line into its unquoted key and value
+func splitYamlKey(s string) (string, string, bool) {
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'") {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 || !strings.HasPrefix(s[end+2:], ":") {
+			return "", "", false
+		}
+		rest := s[end+3:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+		return s[1 : end+1], strings.TrimSpace(rest), true
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '#', '{', '[':
+			return "", "", false
+		case ':':
+			if i+1 == len(s) || s[i+1] == ' ' {
+				return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), i > 0
+			}
+		}
+	}
+	return "", "", false
+}
+
+// jsonPositions indexes the objects and arrays of a JSON document, up to
+// where it stops parsing
+func jsonPositions(data []byte) positions {
+	type level struct {
+		path    string
+		array   bool
+		items   int    // Items of an array seen so far
+		key     string // Key of the object member being read
+		haveKey bool
+	}
+
+	pos := make(positions)
+	var stack []level
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return pos
+		}
+		for offset < len(data) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+			offset++
+		}
+		line := bytes.Count(data[:offset], []byte("\n")) + 1
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		path := ""
+		if n := len(stack); n > 0 {
+			cur := &stack[n-1]
+			switch {
+			case cur.array:
+				path = cur.path + "[" + strconv.Itoa(cur.items) + "]"
+				cur.items++
+				pos[path] = line
+			case !cur.haveKey:
+				cur.key, cur.haveKey = tok.(string), true
+				pos[joinPath(cur.path, cur.key)] = line
+				continue
+			default:
+				path = joinPath(cur.path, cur.key)
+				cur.haveKey = false
+			}
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			stack = append(stack, level{path: path, array: delim == '['})
+		}
+	}
+}
+
+// joinPath appends key to the path of its mapping
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretRefPrefix starts a string value of conf.yaml read from a file
+// (secretref://file:<path>) or from the output of a command
+// (secretref://exec:<command>) when the configuration is loaded
+const secretRefPrefix = "secretref://"
+
+// secretExecTimeout bounds the command of a secretref://exec: reference
+const secretExecTimeout = 30 * time.Second
+
+// resolvedSecrets caches the secret of each reference for the lifetime of the
+// process: the configuration is reloaded on every sync of the watcher, which
+// must not run the commands of secretref://exec: references each time
+var resolvedSecrets = struct {
+	sync.Mutex
+	values map[string]string
+}{values: map[string]string{}}
+
+// envRefRegex matches ${NAME} references to environment variables
+var envRefRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveSecrets replaces, in every string field of v, ${NAME} references
+// with the value of the environment variable, then resolves values that are
+// secret references. Unset variables and failed references are errors, so
+// a missing secret is never sent as an empty credential.
+func resolveSecrets(v any) error {
+	return resolveValue(reflect.ValueOf(v), "")
+}
+
+func resolveValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return resolveValue(v.Elem(), path)
+	case reflect.Struct:
+		t := v.Type()
+		for i := range v.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if err := resolveValue(v.Field(i), joinYamlPath(path, field)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := resolveValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		for _, key := range v.MapKeys() {
+			resolved, err := resolveString(v.MapIndex(key).String())
+			if err != nil {
+				return fmt.Errorf("%s.%v: %w", path, key, err)
+			}
+			v.SetMapIndex(key, reflect.ValueOf(resolved).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		resolved, err := resolveString(v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetString(resolved)
+	}
+	return nil
+}
+
+// joinYamlPath returns the path of a struct field by its YAML key, for errors
+func joinYamlPath(path string, field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		if field.Anonymous {
+			return path
+		}
+		name = strings.ToLower(field.Name)
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// resolveString interpolates the environment variables of a value and, if
+// it is a secret reference, returns the secret it refers to
+func resolveString(value string) (string, error) {
+	if !strings.Contains(value, "${") && !isSecretReference(value) {
+		return value, nil
+	}
+
+	var unset []string
+	value = envRefRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		name := envRefRegex.FindStringSubmatch(ref)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return env
+	})
+	if len(unset) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(unset, ", "))
+	}
+
+	if !isSecretReference(value) {
+		return value, nil
+	}
+	// Held while resolving, so concurrent loads run a command only once
+	resolvedSecrets.Lock()
+	defer resolvedSecrets.Unlock()
+	if secret, ok := resolvedSecrets.values[value]; ok {
+		return secret, nil
+	}
+	secret, err := resolveReference(value)
+	if err != nil {
+		return "", err
+	}
+	resolvedSecrets.values[value] = secret
+	return secret, nil
+}
+
+// resolveReference returns the secret a secret reference refers to
+func resolveReference(value string) (string, error) {
+	kind, target, _ := strings.Cut(strings.TrimPrefix(value, secretRefPrefix), ":")
+	if target == "" {
+		return "", fmt.Errorf("invalid secret reference %q, expected %sfile:<path> or %sexec:<command>", value, secretRefPrefix, secretRefPrefix)
+	}
+	switch kind {
+	case "file":
+		//nolint:gosec // G304: Path is chosen by the owner of conf.yaml
+		data, err := os.ReadFile(target)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case "exec":
+		ctx, cancel := context.WithTimeout(context.Background(), secretExecTimeout)
+		defer cancel()
+		var stderr bytes.Buffer
+		//nolint:gosec // G204: Command is chosen by the owner of conf.yaml
+		cmd := exec.CommandContext(ctx, "sh", "-c", target)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("secret command %q failed: %w: %s", target, err, msg)
+			}
+			return "", fmt.Errorf("secret command %q failed: %w", target, err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unknown secret reference type %q, expected file or exec", kind)
+	}
+}
+
+// isSecretReference reports whether a configuration value is resolved from
+// a secret reference when loaded
+func isSecretReference(value string) bool {
+	return strings.HasPrefix(value, secretRefPrefix)
+}
+
+// hasReference reports whether a configuration value is only known once its
+// environment variables and secret references are resolved
+func hasReference(value string) bool {
+	return isSecretReference(value) || envRefRegex.MatchString(value)
+}
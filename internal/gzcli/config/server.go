@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
@@ -14,9 +17,52 @@ import (
 type ServerConfig struct {
 	Url   string      `yaml:"url"`
 	Creds gzapi.Creds `yaml:"creds"`
+	Sync  SyncConfig  `yaml:"sync,omitempty"`
+	// Timeouts of API calls by operation class; unset ones keep the defaults
+	Timeouts gzapi.Timeouts `yaml:"timeouts,omitempty"`
+	// Language sent as Accept-Language, so GZCTF errors come in that language
+	Language string `yaml:"language,omitempty"`
+	// Email delivery of team credentials; unset keeps the SMTP server of appsettings.json
+	Email MailConfig `yaml:"email,omitempty"`
+	// Other servers the watcher mirrors events to
+	Targets []Target `yaml:"targets,omitempty"`
+}
+
+// MailConfig selects the provider delivering team credential emails and how
+// they look
+type MailConfig struct {
+	Provider        string        `yaml:"provider,omitempty"`        // smtp (default), sendgrid, mailgun or ses
+	From            string        `yaml:"from,omitempty"`            // Sender address (default: SenderAddress or UserName of appsettings.json)
+	FromName        string        `yaml:"fromName,omitempty"`        // Sender name (default: SenderName of appsettings.json)
+	APIKey          string        `yaml:"apiKey,omitempty"`          // SendGrid or Mailgun API key (default: GZCLI_EMAIL_API_KEY)
+	Domain          string        `yaml:"domain,omitempty"`          // Mailgun sending domain
+	Region          string        `yaml:"region,omitempty"`          // Mailgun region (us or eu) or SES region (default: AWS_REGION)
+	AccessKeyID     string        `yaml:"accessKeyId,omitempty"`     // SES access key (default: AWS_ACCESS_KEY_ID)
+	SecretAccessKey string        `yaml:"secretAccessKey,omitempty"` // SES secret key (default: AWS_SECRET_ACCESS_KEY)
+	RateLimit       float64       `yaml:"rateLimit,omitempty"`       // Emails per second; 0 uses the provider default
+	Subject         string        `yaml:"subject,omitempty"`         // Subject (default: "Your Team Credentials")
+	Template        string        `yaml:"template,omitempty"`        // HTML template file replacing the default email
+	Branding        EmailBranding `yaml:"branding,omitempty"`
+}
+
+// EmailBranding customizes the default credentials email
+type EmailBranding struct {
+	Name   string `yaml:"name,omitempty"`   // Shown above the greeting
+	Logo   string `yaml:"logo,omitempty"`   // Logo image URL
+	Color  string `yaml:"color,omitempty"`  // Accent color of the button, e.g. "#e11d48"
+	Footer string `yaml:"footer,omitempty"` // Text at the bottom of the email
 }
 
-// GetServerConfig reads server configuration from .gzctf/conf.yaml
+// SyncConfig tunes how challenges are synced to the server
+type SyncConfig struct {
+	Concurrency int     `yaml:"concurrency,omitempty"` // Challenges synced in parallel; 0 picks a default
+	RateLimit   float64 `yaml:"rateLimit,omitempty"`   // API requests per second to the server; 0 is unlimited
+	Burst       int     `yaml:"burst,omitempty"`       // Requests allowed at once above the rate limit
+}
+
+// GetServerConfig reads server configuration from .gzctf/conf.yaml, with
+// ${NAME} environment variables and secretref:// references of its values
+// resolved
 func GetServerConfig() (*ServerConfig, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -28,6 +74,86 @@ func GetServerConfig() (*ServerConfig, error) {
 	if err := fileutil.ParseYamlFromFile(confPath, &config); err != nil {
 		return nil, fmt.Errorf("failed to read server config %s: %w", confPath, err)
 	}
+	if err := resolveSecrets(&config); err != nil {
+		return nil, fmt.Errorf("failed to resolve server config %s: %w", confPath, err)
+	}
+	gzapi.SetTimeouts(config.Timeouts)
+	if config.Language != "" {
+		gzapi.SetAcceptLanguage(config.Language)
+	}
 
 	return &config, nil
 }
+
+// SetServerPassword replaces creds.password in .gzctf/conf.yaml, leaving the
+// rest of the file, comments included, untouched
+func SetServerPassword(password string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	confPath := filepath.Join(dir, GZCTF_DIR, CONFIG_FILE)
+	info, err := os.Stat(confPath)
+	if err != nil {
+		return fmt.Errorf("failed to read server config %s: %w", confPath, err)
+	}
+	//nolint:gosec // G304: Path is the project's server config
+	data, err := os.ReadFile(confPath)
+	if err != nil {
+		return fmt.Errorf("failed to read server config %s: %w", confPath, err)
+	}
+
+	updated, err := replaceCredsPassword(data, password)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", confPath, err)
+	}
+	if err := os.WriteFile(confPath, updated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write server config %s: %w", confPath, err)
+	}
+	return nil
+}
+
+// replaceCredsPassword rewrites the value of the password key of the
+// top-level creds mapping in a conf.yaml document
+func replaceCredsPassword(data []byte, password string) ([]byte, error) {
+	var current ServerConfig
+	if err := yaml.Unmarshal(data, &current); err == nil && hasReference(current.Creds.Password) {
+		return nil, fmt.Errorf("creds.password is read from %q; update the secret it refers to instead", current.Creds.Password)
+	}
+
+	value, err := yaml.Marshal(password)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	inCreds, replaced := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 {
+			inCreds = strings.HasPrefix(trimmed, "creds:") && strings.TrimSpace(strings.SplitN(strings.TrimPrefix(trimmed, "creds:"), "#", 2)[0]) == ""
+			continue
+		}
+		if inCreds && strings.HasPrefix(trimmed, "password:") {
+			eol := line[len(strings.TrimRight(line, "\r\n")):]
+			lines[i] = line[:indent] + "password: " + strings.TrimSpace(string(value)) + eol
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		return nil, fmt.Errorf("creds.password not found (set it as a block mapping entry)")
+	}
+
+	updated := []byte(strings.Join(lines, ""))
+	var check ServerConfig
+	if err := yaml.Unmarshal(updated, &check); err != nil || check.Creds.Password != password {
+		return nil, fmt.Errorf("password cannot be written as a plain YAML line")
+	}
+	return updated, nil
+}
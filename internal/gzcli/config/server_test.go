@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetServerPassword(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	confPath := filepath.Join(tmpDir, GZCTF_DIR, CONFIG_FILE)
+	if err := os.MkdirAll(filepath.Dir(confPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	original := `# GZCTF server
+url: "http://localhost:8080"
+creds:
+  username: admin
+  # rotated quarterly
+  password: old-secret # keep in sync with the vault
+sync:
+  concurrency: 4
+`
+	if err := os.WriteFile(confPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetServerPassword(`n3w: "pa#ss'`); err != nil {
+		t.Fatalf("SetServerPassword() error = %v", err)
+	}
+
+	conf, err := GetServerConfig()
+	if err != nil {
+		t.Fatalf("GetServerConfig() error = %v", err)
+	}
+	if conf.Creds.Password != `n3w: "pa#ss'` || conf.Creds.Username != "admin" || conf.Sync.Concurrency != 4 {
+		t.Errorf("config after rotation = %+v", conf)
+	}
+
+	data, _ := os.ReadFile(confPath)
+	for _, kept := range []string{"# GZCTF server", "# rotated quarterly", "url: \"http://localhost:8080\""} {
+		if !strings.Contains(string(data), kept) {
+			t.Errorf("conf.yaml lost %q:\n%s", kept, data)
+		}
+	}
+	if info, _ := os.Stat(confPath); info.Mode().Perm() != 0600 {
+		t.Errorf("conf.yaml mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestReplaceCredsPassword_NotFound(t *testing.T) {
+	for _, doc := range []string{
+		"url: x\ncreds: {username: admin, password: old}\n",
+		"url: x\nother:\n  password: old\n",
+		"url: x\ncreds:\n  password: ${GZCTF_PASSWORD}\n",
+		"url: x\ncreds:\n  password: secretref://file:/run/secrets/gzctf\n",
+	} {
+		if _, err := replaceCredsPassword([]byte(doc), "new"); err == nil {
+			t.Errorf("replaceCredsPassword(%q) succeeded, want an error", doc)
+		}
+	}
+}
+
+func TestGetServerConfig_ResolvesSecrets(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	secretFile := filepath.Join(tmpDir, "password")
+	if err := os.WriteFile(secretFile, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_GZCTF_HOST", "ctf.example.com")
+	t.Setenv("TEST_GZCTF_USER", "admin")
+	confPath := filepath.Join(tmpDir, GZCTF_DIR, CONFIG_FILE)
+	if err := os.MkdirAll(filepath.Dir(confPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	conf := `url: "https://${TEST_GZCTF_HOST}"
+creds:
+  username: ${TEST_GZCTF_USER}
+  password: secretref://file:` + secretFile + `
+email:
+  apiKey: "secretref://exec:printf 'exec-secret\\n'"
+`
+	if err := os.WriteFile(confPath, []byte(conf), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetServerConfig()
+	if err != nil {
+		t.Fatalf("GetServerConfig() error = %v", err)
+	}
+	if got.Url != "https://ctf.example.com" || got.Creds.Username != "admin" || got.Creds.Password != "file-secret" || got.Email.APIKey != "exec-secret" {
+		t.Errorf("GetServerConfig() = %+v, want resolved values", got)
+	}
+
+	for _, bad := range []string{"${TEST_GZCTF_UNSET}", "secretref://vault:x", "secretref://file:" + filepath.Join(tmpDir, "missing"), "secretref://exec:exit 1"} {
+		if err := os.WriteFile(confPath, []byte("url: http://x\ncreds:\n  password: "+bad+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := GetServerConfig(); err == nil || !strings.Contains(err.Error(), "creds.password") {
+			t.Errorf("GetServerConfig() with password %q = %v, want an error naming creds.password", bad, err)
+		}
+	}
+}
+
+func TestResolveString_RunsSecretCommandOnce(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	ref := "secretref://exec:echo run >> '" + counter + "'; echo token"
+
+	for i := 0; i < 3; i++ {
+		got, err := resolveString(ref)
+		if err != nil {
+			t.Fatalf("resolveString() error = %v", err)
+		}
+		if got != "token" {
+			t.Errorf("resolveString() = %q, want %q", got, "token")
+		}
+	}
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "run"); runs != 1 {
+		t.Errorf("secret command ran %d times, want once per process", runs)
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// Target is another GZCTF server the watcher mirrors events to, such as a
+// staging server next to production, from the `targets` list of conf.yaml
+type Target struct {
+	Name  string      `yaml:"name"`
+	Url   string      `yaml:"url"`
+	Creds gzapi.Creds `yaml:"creds"`
+	// Tags select groups of targets from .gzevent and the watcher
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// Matches reports whether the name or one of the tags of the target is
+// among selectors
+func (t Target) Matches(selectors []string) bool {
+	if slices.Contains(selectors, t.Name) {
+		return true
+	}
+	for _, tag := range t.Tags {
+		if slices.Contains(selectors, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Target) validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if t.Url == "" {
+		return fmt.Errorf("url is required")
+	}
+	if !t.Creds.HasToken() && (t.Creds.Username == "" || t.Creds.Password == "") {
+		return fmt.Errorf("creds need a token or a username and password")
+	}
+	return nil
+}
+
+// eventTargetsFile is the subset of .gzevent selecting the targets the event
+// is mirrored to
+type eventTargetsFile struct {
+	Targets []string `yaml:"targets"`
+}
+
+// GetEventTargets returns the targets of conf.yaml an event is mirrored to:
+// those whose name or a tag is listed in the `targets` of its .gzevent, or
+// every target when it lists none. Non-empty selectors further keep the
+// targets matching one of them by name or tag.
+func GetEventTargets(eventName string, selectors []string) ([]Target, error) {
+	serverConfig, err := GetServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(serverConfig.Targets) == 0 {
+		return nil, nil
+	}
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	var file eventTargetsFile
+	if err := fileutil.ParseYamlFromFile(filepath.Join(eventPath, GZEVENT_FILE), &file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var targets []Target
+	for i, target := range serverConfig.Targets {
+		if err := target.validate(); err != nil {
+			return nil, fmt.Errorf("targets[%d]: %w", i, err)
+		}
+		if len(file.Targets) > 0 && !target.Matches(file.Targets) {
+			continue
+		}
+		if len(selectors) > 0 && !target.Matches(selectors) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
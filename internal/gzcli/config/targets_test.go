@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeServerConfig(t *testing.T, tmpDir, conf string) {
+	t.Helper()
+	confPath := filepath.Join(tmpDir, GZCTF_DIR, CONFIG_FILE)
+	if err := os.MkdirAll(filepath.Dir(confPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(confPath, []byte(conf), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func targetNames(targets []Target) []string {
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+	return names
+}
+
+func TestGetEventTargets(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	writeServerConfig(t, tmpDir, `url: "https://ctf.example.com"
+creds:
+  username: admin
+  password: secret
+targets:
+  - name: staging
+    url: "https://staging.example.com"
+    creds:
+      token: staging-token
+    tags: [preview]
+  - name: backup
+    url: "https://backup.example.com"
+    creds:
+      username: admin
+      password: secret
+    tags: [preview, dr]
+`)
+	writeUploadEvent(t, tmpDir, "all", `title: "All"`)
+	writeUploadEvent(t, tmpDir, "staged", `title: "Staged"
+targets: [staging]
+`)
+	writeUploadEvent(t, tmpDir, "preview", `title: "Preview"
+targets: [preview]
+`)
+
+	tests := []struct {
+		event     string
+		selectors []string
+		want      []string
+	}{
+		{"all", nil, []string{"staging", "backup"}},
+		{"all", []string{"dr"}, []string{"backup"}},
+		{"staged", nil, []string{"staging"}},
+		{"staged", []string{"backup"}, []string{}},
+		{"preview", nil, []string{"staging", "backup"}},
+	}
+	for _, tt := range tests {
+		targets, err := GetEventTargets(tt.event, tt.selectors)
+		if err != nil {
+			t.Fatalf("GetEventTargets(%q, %v) error = %v", tt.event, tt.selectors, err)
+		}
+		if got := targetNames(targets); !slices.Equal(got, tt.want) {
+			t.Errorf("GetEventTargets(%q, %v) = %v, want %v", tt.event, tt.selectors, got, tt.want)
+		}
+	}
+}
+
+func TestGetEventTargets_Invalid(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	writeServerConfig(t, tmpDir, `url: "https://ctf.example.com"
+creds:
+  username: admin
+  password: secret
+targets:
+  - name: staging
+    url: "https://staging.example.com"
+`)
+	writeUploadEvent(t, tmpDir, "ctf", `title: "CTF"`)
+
+	if _, err := GetEventTargets("ctf", nil); err == nil {
+		t.Error("GetEventTargets() accepted a target without creds")
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+)
+
+// DefaultUploadDirs are the directories an uploaded challenge must contain
+// when its event sets no upload profile
+var DefaultUploadDirs = []string{"dist", "src", "solver"}
+
+// UploadRules are the packaging rules the upload server applies to a challenge
+type UploadRules struct {
+	// RequiredDirs must exist at the challenge root (default: DefaultUploadDirs)
+	RequiredDirs []string `yaml:"requiredDirs,omitempty"`
+	// NamePattern is a regular expression challenge names must match
+	NamePattern string `yaml:"namePattern,omitempty"`
+}
+
+// UploadProfile holds the upload rules of an event from the `upload` block
+// of .gzevent. Rules set for a category replace the event-wide ones for
+// challenges of that category, field by field.
+type UploadProfile struct {
+	UploadRules `yaml:",inline"`
+	Categories  map[string]UploadRules `yaml:"categories,omitempty"`
+	// AutoSync syncs each installed challenge through the watcher right away
+	AutoSync bool `yaml:"autoSync,omitempty"`
+}
+
+// eventUploadFile is the subset of .gzevent holding the upload profile
+type eventUploadFile struct {
+	Upload *UploadProfile `yaml:"upload"`
+}
+
+// GetUploadProfile reads the upload profile of an event. Events without a
+// .gzevent or an `upload` block get an empty profile.
+func GetUploadProfile(eventName string) (*UploadProfile, error) {
+	eventPath, err := GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	var file eventUploadFile
+	if err := fileutil.ParseYamlFromFile(filepath.Join(eventPath, GZEVENT_FILE), &file); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &UploadProfile{}, nil
+		}
+		return nil, err
+	}
+	if file.Upload == nil {
+		return &UploadProfile{}, nil
+	}
+	if err := file.Upload.validate(); err != nil {
+		return nil, fmt.Errorf("invalid upload profile of event %s: %w", eventName, err)
+	}
+	return file.Upload, nil
+}
+
+func (p *UploadProfile) validate() error {
+	if err := p.UploadRules.validate(); err != nil {
+		return err
+	}
+	for category, rules := range p.Categories {
+		if err := rules.validate(); err != nil {
+			return fmt.Errorf("category %s: %w", category, err)
+		}
+	}
+	return nil
+}
+
+func (r UploadRules) validate() error {
+	for _, dir := range r.RequiredDirs {
+		if dir == "" || dir == "." || dir == ".." || strings.ContainsAny(dir, `/\`) || dir == "challenge.yml" {
+			return fmt.Errorf("requiredDirs: invalid directory name %q", dir)
+		}
+	}
+	if r.NamePattern != "" {
+		if _, err := regexp.Compile(r.NamePattern); err != nil {
+			return fmt.Errorf("namePattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// Rules returns the upload rules for challenges of category, with defaults
+// filled in
+func (p *UploadProfile) Rules(category string) UploadRules {
+	var rules UploadRules
+	if p != nil {
+		rules = p.UploadRules
+		if override, ok := p.Categories[category]; ok {
+			if override.RequiredDirs != nil {
+				rules.RequiredDirs = override.RequiredDirs
+			}
+			if override.NamePattern != "" {
+				rules.NamePattern = override.NamePattern
+			}
+		}
+	}
+	if rules.RequiredDirs == nil {
+		rules.RequiredDirs = DefaultUploadDirs
+	}
+	return rules
+}
+
+// Requires reports whether dir must exist at the challenge root
+func (r UploadRules) Requires(dir string) bool {
+	for _, d := range r.RequiredDirs {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
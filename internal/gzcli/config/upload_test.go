@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeUploadEvent(t *testing.T, tmpDir, eventName, gzevent string) {
+	t.Helper()
+	eventDir := filepath.Join(tmpDir, EVENTS_DIR, eventName)
+	if err := os.MkdirAll(eventDir, 0750); err != nil {
+		t.Fatalf("Failed to create event dir: %v", err)
+	}
+	if gzevent == "" {
+		return
+	}
+	//nolint:gosec // G306: Test file permissions are acceptable
+	if err := os.WriteFile(filepath.Join(eventDir, GZEVENT_FILE), []byte(gzevent), 0644); err != nil {
+		t.Fatalf("Failed to create .gzevent: %v", err)
+	}
+}
+
+func TestGetUploadProfile(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	writeUploadEvent(t, tmpDir, "custom", `title: "Custom"
+upload:
+  namePattern: "^[a-z ]+$"
+  categories:
+    OSINT:
+      requiredDirs: [dist, solver]
+    Hardware:
+      requiredDirs: [dist, src, solver, docs]
+      namePattern: "^hw "
+`)
+	writeUploadEvent(t, tmpDir, "plain", `title: "Plain"`)
+	writeUploadEvent(t, tmpDir, "bare", "")
+
+	profile, err := GetUploadProfile("custom")
+	if err != nil {
+		t.Fatalf("GetUploadProfile() error = %v", err)
+	}
+
+	tests := []struct {
+		category string
+		want     UploadRules
+	}{
+		{"Web", UploadRules{RequiredDirs: DefaultUploadDirs, NamePattern: "^[a-z ]+$"}},
+		{"OSINT", UploadRules{RequiredDirs: []string{"dist", "solver"}, NamePattern: "^[a-z ]+$"}},
+		{"Hardware", UploadRules{RequiredDirs: []string{"dist", "src", "solver", "docs"}, NamePattern: "^hw "}},
+	}
+	for _, tt := range tests {
+		if got := profile.Rules(tt.category); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Rules(%q) = %+v, want %+v", tt.category, got, tt.want)
+		}
+	}
+
+	for _, event := range []string{"plain", "bare"} {
+		profile, err := GetUploadProfile(event)
+		if err != nil {
+			t.Fatalf("GetUploadProfile(%q) error = %v", event, err)
+		}
+		if got := profile.Rules("Web"); !reflect.DeepEqual(got.RequiredDirs, DefaultUploadDirs) {
+			t.Errorf("%s: RequiredDirs = %v, want defaults", event, got.RequiredDirs)
+		}
+	}
+}
+
+func TestGetUploadProfile_Invalid(t *testing.T) {
+	tmpDir, cleanup := setupEventTestDir(t)
+	defer cleanup()
+
+	writeUploadEvent(t, tmpDir, "traversal", "upload:\n  requiredDirs: [\"../x\"]\n")
+	writeUploadEvent(t, tmpDir, "pattern", "upload:\n  categories:\n    Web:\n      namePattern: \"(\"\n")
+
+	for event, want := range map[string]string{"traversal": "invalid directory name", "pattern": "category Web: namePattern"} {
+		if _, err := GetUploadProfile(event); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("GetUploadProfile(%q) error = %v, want %q", event, err, want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// Problem is one problem found in a configuration file, located by the path
+// of the offending value ("creds.password", "git.sparsePaths[1]") and the
+// line it is set on, 0 when the value is missing from the file
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location += ":" + strconv.Itoa(p.Line)
+	}
+	if p.Path != "" {
+		return fmt.Sprintf("%s: %s: %s", location, p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", location, p.Message)
+}
+
+// Container provider settings of appsettings.json accepted by GZCTF
+var (
+	containerProviderTypes = []string{"Docker", "Kubernetes"}
+	portMappingTypes       = []string{"Default", "PlatformProxy"}
+)
+
+// yamlLineRegex matches the line prefix of yaml.v2 errors
+var yamlLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// eventFile is every block of .gzevent, decoded strictly to report unknown
+// fields
+type eventFile struct {
+	gzapi.Game `yaml:",inline"`
+	Defaults   *ChallengeDefaults `yaml:"defaults,omitempty"`
+	Upload     *UploadProfile     `yaml:"upload,omitempty"`
+	Git        *GitPullConfig     `yaml:"git,omitempty"`
+	Targets    []string           `yaml:"targets,omitempty"`
+}
+
+// problems collects the problems of one file
+type problems struct {
+	file string
+	pos  positions
+	list []Problem
+}
+
+// add reports a problem with the value at path
+func (p *problems) add(path, format string, args ...any) {
+	p.list = append(p.list, Problem{File: p.file, Line: p.pos.line(path), Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// addErr reports the error of a validate method of the block at path. The
+// leading "field: " segments of the error are moved to the path.
+func (p *problems) addErr(path string, err error) {
+	message := err.Error()
+	for {
+		field, rest, ok := strings.Cut(message, ": ")
+		if !ok {
+			break
+		}
+		if category, isCategory := strings.CutPrefix(field, "category "); isCategory {
+			field = "categories." + category
+		} else if strings.ContainsAny(field, " \"'") {
+			break
+		}
+		path, message = joinPath(path, field), rest
+	}
+	p.add(path, "%s", message)
+}
+
+// addYamlErr reports a yaml.v2 error, one problem per line it names
+func (p *problems) addYamlErr(err error) {
+	var typeErr *yaml.TypeError
+	messages := []string{err.Error()}
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+	for _, message := range messages {
+		problem := Problem{File: p.file, Message: message}
+		if m := yamlLineRegex.FindStringSubmatch(message); m != nil {
+			problem.Line, _ = strconv.Atoi(m[1])
+			problem.Path = p.pos.path(problem.Line)
+			problem.Message = m[2]
+		}
+		if rest, ok := strings.CutPrefix(problem.Message, "field "); ok {
+			if field, _, found := strings.Cut(rest, " not found in type"); found {
+				problem.Message = fmt.Sprintf("unknown field %q", field)
+			}
+		}
+		p.list = append(p.list, problem)
+	}
+}
+
+// ValidateFile checks a configuration file, whose kind is told by its name:
+// conf.yaml, .gzevent or appsettings.json. A file that cannot be read is a
+// problem; an unknown kind of file is an error.
+func ValidateFile(path string) ([]Problem, error) {
+	var validate func(p *problems, data []byte)
+	switch filepath.Base(path) {
+	case CONFIG_FILE:
+		validate = validateServerConfig
+	case GZEVENT_FILE:
+		validate = validateEventConfig
+	case APPSETTINGS_FILE:
+		validate = validateAppSettings
+	default:
+		return nil, fmt.Errorf("%s is not a configuration file (expected %s, %s or %s)", path, CONFIG_FILE, GZEVENT_FILE, APPSETTINGS_FILE)
+	}
+
+	p := &problems{file: path}
+	//nolint:gosec // G304: Path is a configuration file chosen by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		p.add("", "%v", err)
+		return p.list, nil
+	}
+	validate(p, data)
+	sort.SliceStable(p.list, func(i, j int) bool { return p.list[i].Line < p.list[j].Line })
+	return p.list, nil
+}
+
+// ValidateWorkspace checks the configuration files of the workspace at root:
+// .gzctf/conf.yaml, .gzctf/appsettings.json when present, and the .gzevent
+// of every event
+func ValidateWorkspace(root string) []Problem {
+	files := []string{filepath.Join(root, GZCTF_DIR, CONFIG_FILE)}
+	if appsettings := filepath.Join(root, GZCTF_DIR, APPSETTINGS_FILE); fileExists(appsettings) {
+		files = append(files, appsettings)
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, EVENTS_DIR))
+	for _, entry := range entries {
+		if gzevent := filepath.Join(root, EVENTS_DIR, entry.Name(), GZEVENT_FILE); entry.IsDir() && fileExists(gzevent) {
+			files = append(files, gzevent)
+		}
+	}
+
+	var all []Problem
+	for _, file := range files {
+		found, _ := ValidateFile(file)
+		all = append(all, found...)
+	}
+	return all
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// decodeYaml parses data strictly into v and indexes its positions. It
+// returns false when the document does not parse at all; type errors and
+// unknown fields are reported, and the rest of the document still decoded.
+func (p *problems) decodeYaml(data []byte, v any) bool {
+	p.pos = yamlPositions(data)
+	err := yaml.UnmarshalStrict(data, v)
+	if err == nil {
+		return true
+	}
+	p.addYamlErr(err)
+	var typeErr *yaml.TypeError
+	return errors.As(err, &typeErr)
+}
+
+func validateServerConfig(p *problems, data []byte) {
+	var conf ServerConfig
+	if !p.decodeYaml(data, &conf) {
+		return
+	}
+
+	// Referenced values are only known once resolved when loading
+	switch u, err := url.Parse(conf.Url); {
+	case hasReference(conf.Url):
+	case conf.Url == "":
+		p.add("url", "is required")
+	case err != nil:
+		p.add("url", "invalid URL: %v", err)
+	case u.Scheme != "http" && u.Scheme != "https" || u.Host == "":
+		p.add("url", "must be an http(s) URL with a host, got %q", conf.Url)
+	}
+	// An API token replaces the username and password
+	if !conf.Creds.HasToken() {
+		if conf.Creds.Username == "" {
+			p.add("creds.username", "is required")
+		}
+		if conf.Creds.Password == "" {
+			p.add("creds.password", "is required")
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, target := range conf.Targets {
+		if err := target.validate(); err != nil {
+			p.add(fmt.Sprintf("targets[%d]", i), "%v", err)
+		}
+		if names[target.Name] {
+			p.add(fmt.Sprintf("targets[%d].name", i), "duplicate target %q", target.Name)
+		}
+		names[target.Name] = true
+	}
+
+	if conf.Sync.Concurrency < 0 {
+		p.add("sync.concurrency", "must not be negative")
+	}
+	if conf.Sync.RateLimit < 0 {
+		p.add("sync.rateLimit", "must not be negative")
+	}
+	if conf.Sync.Burst < 0 {
+		p.add("sync.burst", "must not be negative")
+	}
+}
+
+func validateEventConfig(p *problems, data []byte) {
+	// Dates failing to parse abort the decoding without a line, so they are
+	// checked first
+	p.pos = yamlPositions(data)
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		p.addYamlErr(err)
+		return
+	}
+	badDate := false
+	for _, key := range []string{"start", "end", "writeupDeadline"} {
+		if value, ok := raw[key].(string); ok {
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				p.add(key, "invalid date %q, expected RFC 3339 such as 2024-10-11T12:00:00+00:00", value)
+				badDate = true
+			}
+		}
+	}
+	if badDate {
+		return
+	}
+
+	var event eventFile
+	if !p.decodeYaml(data, &event) {
+		return
+	}
+
+	if strings.TrimSpace(event.Title) == "" {
+		p.add("title", "is required")
+	}
+	if event.Start.IsZero() {
+		p.add("start", "is required")
+	}
+	if event.End.IsZero() {
+		p.add("end", "is required")
+	}
+	if !event.Start.IsZero() && !event.End.IsZero() && !event.End.After(event.Start.Time) {
+		p.add("end", "must be after start (%s)", event.Start.Format(time.RFC3339))
+	}
+	if !event.WriteupDeadline.IsZero() && !event.End.IsZero() && event.WriteupDeadline.Before(event.End.Time) {
+		p.add("writeupDeadline", "must not be before end (%s)", event.End.Format(time.RFC3339))
+	}
+	if event.TeamMemberCountLimit < 0 {
+		p.add("teamMemberCountLimit", "must not be negative")
+	}
+	if event.ContainerCountLimit < 0 {
+		p.add("containerCountLimit", "must not be negative")
+	}
+	if event.BloodBonus < 0 {
+		p.add("bloodBonus", "must not be negative")
+	}
+	if event.Poster != "" && !strings.HasPrefix(event.Poster, "http") && !posterExists(p.file, event.Poster) {
+		p.add("poster", "file %s not found", event.Poster)
+	}
+
+	if event.Defaults != nil {
+		if event.Defaults.Value < 0 {
+			p.add("defaults.value", "must not be negative")
+		}
+		if err := event.Defaults.validate(); err != nil {
+			p.addErr("defaults", err)
+		}
+	}
+	if event.Upload != nil {
+		if err := event.Upload.validate(); err != nil {
+			p.addErr("upload", err)
+		}
+	}
+	if event.Git != nil {
+		if err := event.Git.validate(); err != nil {
+			p.addErr("git", err)
+		}
+	}
+}
+
+// posterExists resolves a poster path the way GetEventConfig does: from the
+// event directory, then from the workspace root
+func posterExists(gzeventPath, poster string) bool {
+	if filepath.IsAbs(poster) {
+		return fileExists(poster)
+	}
+	eventDir := filepath.Dir(gzeventPath)
+	return fileExists(filepath.Join(eventDir, poster)) || fileExists(filepath.Join(eventDir, "..", "..", poster))
+}
+
+func validateAppSettings(p *problems, data []byte) {
+	p.pos = jsonPositions(data)
+	var settings AppSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			p.list = append(p.list, Problem{File: p.file, Line: lineAtOffset(data, syntaxErr.Offset), Message: syntaxErr.Error()})
+		case errors.As(err, &typeErr):
+			p.add(typeErr.Field, "cannot use a JSON %s as %s", typeErr.Value, typeErr.Type)
+		default:
+			p.add("", "%v", err)
+		}
+		return
+	}
+
+	// Empty settings keep the GZCTF defaults: Docker with the Default mapping
+	provider := settings.ContainerProvider
+	if provider.Type != "" && !slices.Contains(containerProviderTypes, provider.Type) {
+		p.add("ContainerProvider.Type", "must be one of %s, got %q", strings.Join(containerProviderTypes, ", "), provider.Type)
+	}
+	if provider.PortMappingType != "" && !slices.Contains(portMappingTypes, provider.PortMappingType) {
+		p.add("ContainerProvider.PortMappingType", "must be one of %s, got %q", strings.Join(portMappingTypes, ", "), provider.PortMappingType)
+	}
+	if provider.Type != "" && provider.PortMappingType != "PlatformProxy" && provider.PublicEntry == "" {
+		p.add("ContainerProvider.PublicEntry", "is required with the Default port mapping, to tell players where containers are")
+	}
+
+	switch provider.Type {
+	case "Docker":
+		if uri := provider.DockerConfig.Uri; uri != "" {
+			if u, err := url.Parse(uri); err != nil || u.Scheme == "" {
+				p.add("ContainerProvider.DockerConfig.Uri", "must be a URI such as unix:///var/run/docker.sock or tcp://host:2375, got %q", uri)
+			}
+		}
+	case "Kubernetes":
+		k8s := provider.KubernetesConfig
+		for i, cidr := range k8s.AllowCIDR {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				p.add(fmt.Sprintf("ContainerProvider.KubernetesConfig.AllowCIDR[%d]", i), "invalid CIDR %q", cidr)
+			}
+		}
+		for i, dns := range k8s.DNS {
+			if net.ParseIP(dns) == nil {
+				p.add(fmt.Sprintf("ContainerProvider.KubernetesConfig.DNS[%d]", i), "invalid IP address %q", dns)
+			}
+		}
+	}
+
+	registry := settings.RegistryConfig
+	if registry.UserName != "" && registry.ServerAddress == "" {
+		p.add("RegistryConfig.ServerAddress", "is required when RegistryConfig.UserName is set")
+	}
+}
+
+// lineAtOffset returns the 1-based line of a byte offset of data
+func lineAtOffset(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return strings.Count(string(data[:offset]), "\n") + 1
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestYamlPositions(t *testing.T) {
+	pos := yamlPositions([]byte(`# comment
+title: "CTF"
+content: |
+  key: not a key
+organizations:
+- first
+- second
+git:
+  sparsePaths:
+    - web
+    - pwn
+upload:
+  categories:
+    "Web":
+      namePattern: x
+items:
+  - name: a
+    value: 1
+  - name: b
+`))
+
+	want := map[string]int{
+		"title":                              2,
+		"content":                            3,
+		"organizations[0]":                   6,
+		"organizations[1]":                   7,
+		"git":                                8,
+		"git.sparsePaths[1]":                 11,
+		"upload.categories.Web.namePattern":  15,
+		"items[0].name":                      17,
+		"items[0].value":                     18,
+		"items[1].name":                      19,
+		"upload.categories.Web.requiredDirs": 14, // Resolves to its parent
+		"content.key":                        3,
+	}
+	for path, line := range want {
+		if got := pos.line(path); got != line {
+			t.Errorf("line(%q) = %d, want %d", path, got, line)
+		}
+	}
+	if got := pos.path(18); got != "items[0].value" {
+		t.Errorf("path(18) = %q, want items[0].value", got)
+	}
+}
+
+func TestJSONPositions(t *testing.T) {
+	pos := jsonPositions([]byte(`{
+  "ContainerProvider": {
+    "Type": "Docker",
+    "KubernetesConfig": {
+      "DNS": [
+        "8.8.8.8",
+        "bad"
+      ]
+    }
+  },
+  "XorKey": ""
+}`))
+
+	want := map[string]int{
+		"ContainerProvider.Type":                    3,
+		"ContainerProvider.KubernetesConfig.DNS[1]": 7,
+		"XorKey": 11,
+	}
+	for path, line := range want {
+		if got := pos.line(path); got != line {
+			t.Errorf("line(%q) = %d, want %d", path, got, line)
+		}
+	}
+}
+
+func writeValidateFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateFile_ServerConfig(t *testing.T) {
+	path := writeValidateFile(t, filepath.Join(t.TempDir(), GZCTF_DIR, CONFIG_FILE), `url: "ftp://example.com"
+creds:
+  username: admin
+  pasword: typo
+sync:
+  concurrency: many
+`)
+
+	problems, err := ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		{File: path, Line: 1, Path: "url", Message: `must be an http(s) URL with a host, got "ftp://example.com"`},
+		{File: path, Line: 2, Path: "creds.password", Message: "is required"},
+		{File: path, Line: 4, Path: "creds.pasword", Message: `unknown field "pasword"`},
+		{File: path, Line: 6, Path: "sync.concurrency", Message: "cannot unmarshal !!str `many` into int"},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidateFile() =\n%v\nwant\n%v", problems, want)
+	}
+}
+
+func TestValidateFile_ServerConfigReferences(t *testing.T) {
+	path := writeValidateFile(t, filepath.Join(t.TempDir(), GZCTF_DIR, CONFIG_FILE), `url: ${GZCTF_URL}
+creds:
+  username: admin
+  password: secretref://exec:pass show gzctf/admin
+`)
+
+	problems, err := ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("ValidateFile() = %v, want referenced values accepted", problems)
+	}
+}
+
+func TestValidateFile_EventConfig(t *testing.T) {
+	path := writeValidateFile(t, filepath.Join(t.TempDir(), EVENTS_DIR, "ctf", GZEVENT_FILE), `title: "CTF"
+start: "2024-10-13T12:00:00+00:00"
+end: "2024-10-11T12:00:00+00:00"
+containerCountLimit: -1
+poster: missing.png
+defaults:
+  forbiddenExtensions: [".env", "a/b"]
+upload:
+  categories:
+    Web:
+      namePattern: "("
+git:
+  sparsePaths:
+    - web
+    - ../outside
+`)
+
+	problems, err := ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Path)
+	}
+	want := []string{"end", "containerCountLimit", "poster", "defaults.forbiddenExtensions", "upload.categories.Web.namePattern", "git.sparsePaths"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problem paths = %v, want %v", got, want)
+	}
+	if problems[4].Line != 11 {
+		t.Errorf("namePattern problem on line %d, want 11", problems[4].Line)
+	}
+}
+
+func TestValidateFile_SyntaxError(t *testing.T) {
+	path := writeValidateFile(t, filepath.Join(t.TempDir(), EVENTS_DIR, "ctf", GZEVENT_FILE), "title: \"CTF\"\nstart: [\n")
+
+	problems, err := ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Line == 0 {
+		t.Errorf("problems = %v, want one syntax error with its line", problems)
+	}
+
+	writeValidateFile(t, path, "title: CTF\nstart: tomorrow\nend: \"2024-10-11T12:00:00+00:00\"\n")
+	problems, err = ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Path != "start" || problems[0].Line != 2 {
+		t.Errorf("problems = %v, want the invalid start date on line 2", problems)
+	}
+}
+
+func TestValidateFile_AppSettings(t *testing.T) {
+	path := writeValidateFile(t, filepath.Join(t.TempDir(), GZCTF_DIR, APPSETTINGS_FILE), `{
+  "ContainerProvider": {
+    "Type": "Kubernetes",
+    "PortMappingType": "Proxy",
+    "PublicEntry": "ctf.example.com",
+    "KubernetesConfig": {
+      "AllowCIDR": ["10.0.0.0/8", "10.0.0.0"],
+      "DNS": ["8.8.8.8"]
+    }
+  }
+}`)
+
+	problems, err := ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		{File: path, Line: 4, Path: "ContainerProvider.PortMappingType", Message: `must be one of Default, PlatformProxy, got "Proxy"`},
+		{File: path, Line: 7, Path: "ContainerProvider.KubernetesConfig.AllowCIDR[1]", Message: `invalid CIDR "10.0.0.0"`},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidateFile() =\n%v\nwant\n%v", problems, want)
+	}
+
+	if _, err := ValidateFile(filepath.Join(t.TempDir(), "other.yaml")); err == nil {
+		t.Error("expected an error for an unknown configuration file")
+	}
+}
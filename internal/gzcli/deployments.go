@@ -0,0 +1,108 @@
+package gzcli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// WatcherDatabase returns the source of the watcher database: the Postgres
+// URL of the GZCLI_WATCHER_DATABASE_URL environment variable if set,
+// otherwise the default SQLite file
+func WatcherDatabase() string {
+	return database.Source(DefaultWatcherConfig.DatabasePath)
+}
+
+// openDeploymentLog opens the watcher database the deployments of a sync are
+// recorded in. Without it the sync goes on unrecorded, so nil is returned on
+// failure.
+func openDeploymentLog() *database.DB {
+	db := database.New(WatcherDatabase(), true)
+	if err := db.Init(); err != nil {
+		log.Error("Deployments of this sync will not be recorded: %v", err)
+		return nil
+	}
+	return db
+}
+
+// recordDeployment stores what "gzcli sync" deployed. Failures are logged only.
+func recordDeployment(db *database.DB, event string, p challenge.Provenance) {
+	if db == nil {
+		return
+	}
+	dep, err := db.RecordDeployment(p.Deployment(event, watchertypes.DeploymentSourceSync))
+	if err != nil {
+		log.Error("Failed to record the deployment of %s: %v", p.Title, err)
+		return
+	}
+	log.Debug("Recorded deployment of %s (revision %d)", p.Title, dep.Revision)
+}
+
+// Deployments reads the deployments recorded in the watcher database at
+// dbPath, a file or a postgres:// URL, see database.GetDeployments
+func Deployments(dbPath, eventName, challengeName string, latest bool, limit int) ([]watchertypes.Deployment, error) {
+	db := database.New(dbPath, true)
+	if err := db.Init(); err != nil {
+		return nil, fmt.Errorf("failed to open watcher database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	return db.GetDeployments(eventName, challengeName, latest, limit)
+}
+
+// RenderDeployments writes deployments as a text table, JSON or CSV
+func RenderDeployments(w io.Writer, deployments []watchertypes.Deployment, format string) error {
+	switch format {
+	case "text", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "DEPLOYED\tEVENT\tCHALLENGE\tID\tREVISION\tYAML\tDIST\tIMAGE DIGEST\tSOURCE")
+		for _, d := range deployments {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+				d.Timestamp.Local().Format(time.DateTime), d.Event, d.Challenge, d.ChallengeID, d.Revision,
+				shortHash(d.YamlHash), shortHash(d.DistHash), shortHash(d.ImageDigest), d.Source)
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(deployments)
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"deployed_at", "event", "category", "challenge", "challenge_id", "revision",
+			"yaml_hash", "dist_hash", "image", "image_digest", "source"})
+		for _, d := range deployments {
+			_ = cw.Write([]string{
+				d.Timestamp.Format(time.RFC3339), d.Event, d.Category, d.Challenge, strconv.Itoa(d.ChallengeID),
+				strconv.Itoa(d.Revision), d.YamlHash, d.DistHash, d.Image, d.ImageDigest, d.Source,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format %q (expected text, json or csv)", format)
+	}
+}
+
+// shortHash abbreviates a hash or digest for tables, "-" when empty
+func shortHash(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+	prefix := ""
+	if algo, rest, ok := strings.Cut(hash, ":"); ok {
+		prefix, hash = algo+":", rest
+	}
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	return prefix + hash
+}
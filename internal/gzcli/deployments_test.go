@@ -0,0 +1,57 @@
+package gzcli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestRenderDeployments(t *testing.T) {
+	deployments := []watchertypes.Deployment{{
+		Timestamp:   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Event:       "ctf2025",
+		Challenge:   "web",
+		Category:    "Web",
+		ChallengeID: 3,
+		Revision:    2,
+		YamlHash:    "0123456789abcdef0123",
+		Image:       "registry/web@sha256:fedcba9876543210",
+		ImageDigest: "sha256:fedcba9876543210",
+		Source:      watchertypes.DeploymentSourceSync,
+	}}
+
+	var text bytes.Buffer
+	if err := RenderDeployments(&text, deployments, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "0123456789ab ") || !strings.Contains(text.String(), "sha256:fedcba987654") {
+		t.Errorf("text output does not abbreviate hashes:\n%s", text.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := RenderDeployments(&jsonOut, deployments, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []watchertypes.Deployment
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || len(decoded) != 1 || decoded[0].ImageDigest != "sha256:fedcba9876543210" {
+		t.Errorf("JSON output = %s (err %v)", jsonOut.String(), err)
+	}
+
+	var csvOut bytes.Buffer
+	if err := RenderDeployments(&csvOut, deployments, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil || len(records) != 2 || records[1][9] != "sha256:fedcba9876543210" {
+		t.Errorf("CSV output = %v (err %v)", records, err)
+	}
+
+	if err := RenderDeployments(&csvOut, deployments, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,153 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/services"
+)
+
+// APIProbe is the outcome of one read-only API call made by ProbeAPI
+type APIProbe struct {
+	Name string
+	Err  error
+}
+
+// ProbeAPI calls the read-only GZCTF endpoints gzcli depends on for the
+// current event, so schema drift (see gzapi.SetSchemaCheck) is recorded for
+// each of them. Nothing is created or modified on the server.
+func (gz *GZ) ProbeAPI() ([]APIProbe, error) {
+	conf, err := config.GetConfigWithEvent(nil, gz.eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	var probes []APIProbe
+	probe := func(name string, fn func() error) {
+		probes = append(probes, APIProbe{Name: name, Err: fn()})
+	}
+
+	var game *gzapi.Game
+	probe("games.list", func() error {
+		games, err := gz.api.GetGames()
+		if err != nil {
+			return err
+		}
+		for _, g := range games {
+			if g.Title == conf.Event.Title {
+				game = g
+				return nil
+			}
+		}
+		return fmt.Errorf("game %q not found on server", conf.Event.Title)
+	})
+	if game == nil {
+		return probes, nil
+	}
+
+	probe("games.get", func() error {
+		_, err := gz.api.GetGameById(game.Id)
+		return err
+	})
+	probe("challenges.list", func() error {
+		_, err := game.GetChallenges()
+		return err
+	})
+	probe("scoreboard", func() error {
+		_, err := game.GetScoreboard()
+		return err
+	})
+	probe("teams.list", func() error {
+		_, err := gz.api.Teams()
+		return err
+	})
+	probe("assets.list", func() error {
+		_, err := gz.api.GetAssets()
+		return err
+	})
+
+	return probes, nil
+}
+
+// ServiceCheck is the state of a shared service reported by CheckServices
+type ServiceCheck struct {
+	services.Service
+	Status     services.Status
+	Err        error    // Set when the status could not be read
+	Dependents []string // Challenges referencing the service
+}
+
+// CheckServices reports the shared services of the current event, the
+// challenges depending on each, and challenge references to services the
+// event does not define
+func (gz *GZ) CheckServices(ctx context.Context) ([]ServiceCheck, []services.MissingReference, error) {
+	challengesConf, err := LoadEventChallenges(gz.eventName)
+	if err != nil {
+		return nil, nil, err
+	}
+	svcs, err := services.DiscoverEvent(gz.eventName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to discover shared services: %w", err)
+	}
+
+	checks := make([]ServiceCheck, 0, len(svcs))
+	for _, svc := range svcs {
+		status, err := services.GetStatus(ctx, svc)
+		checks = append(checks, ServiceCheck{
+			Service:    svc,
+			Status:     status,
+			Err:        err,
+			Dependents: services.Dependents(challengesConf, svc.Name),
+		})
+	}
+	return checks, services.MissingReferences(challengesConf, svcs), nil
+}
+
+// CheckAttachmentPolicy checks the local attachments of the current event
+// against its attachment policy, honouring gz.Policy
+func (gz *GZ) CheckAttachmentPolicy() (*challenge.PolicyReport, error) {
+	challengesConf, err := LoadEventChallenges(gz.eventName)
+	if err != nil {
+		return nil, err
+	}
+	return challenge.CheckPolicy(challengesConf, gz.Policy), nil
+}
+
+// LoadEventChallenges reads the local challenge configurations of an event
+// without contacting the server
+func LoadEventChallenges(eventName string) ([]config.ChallengeYaml, error) {
+	conf, err := config.GetConfigWithEvent(nil, eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	challengesConf, err := config.GetChallengesYaml(conf)
+	if err != nil {
+		return nil, fmt.Errorf("challenges config error: %w", err)
+	}
+	return challengesConf, nil
+}
+
+// validateServiceReferences checks that the shared services referenced by
+// challenges exist in the event. Services are only discovered when a
+// challenge references one.
+func validateServiceReferences(eventName string, challengesConf []config.ChallengeYaml) error {
+	referenced := false
+	for _, c := range challengesConf {
+		if len(c.Services) > 0 {
+			referenced = true
+			break
+		}
+	}
+	if !referenced {
+		return nil
+	}
+
+	svcs, err := services.DiscoverEvent(eventName)
+	if err != nil {
+		return fmt.Errorf("failed to discover shared services: %w", err)
+	}
+	return services.ValidateReferences(challengesConf, svcs)
+}
@@ -0,0 +1,100 @@
+// Package doctor diagnoses the environment gzcli runs in: the container
+// tooling, the GZCTF server, the events directory and the local resources of
+// the watcher. Every check reports a status with a hint fixing a failure, so
+// one run shows everything that needs attention.
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Statuses of a check
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn" // Works, but some features may not
+	StatusFail = "fail"
+)
+
+// Groups of checks, in report order
+const (
+	GroupTools     = "Tools"
+	GroupServer    = "GZCTF server"
+	GroupWorkspace = "Workspace"
+	GroupWatcher   = "Watcher"
+)
+
+// Check is the outcome of one diagnostic
+type Check struct {
+	Group  string `json:"group"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"` // How to fix a warning or failure
+}
+
+func pass(group, name, detail string) Check {
+	return Check{Group: group, Name: name, Status: StatusPass, Detail: detail}
+}
+
+func warn(group, name, detail, hint string) Check {
+	return Check{Group: group, Name: name, Status: StatusWarn, Detail: detail, Hint: hint}
+}
+
+func fail(group, name, detail, hint string) Check {
+	return Check{Group: group, Name: name, Status: StatusFail, Detail: detail, Hint: hint}
+}
+
+// sentence capitalizes the first letter of a hint
+func sentence(hint string) string {
+	if hint == "" {
+		return hint
+	}
+	return strings.ToUpper(hint[:1]) + hint[1:]
+}
+
+// Failed reports whether any check failed
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes the checks under their group headings, colored by status,
+// with the hint of each warning and failure, followed by a summary line
+func Print(w io.Writer, checks []Check) {
+	marks := map[string]string{
+		StatusPass: color.GreenString("✔ PASS"),
+		StatusWarn: color.YellowString("! WARN"),
+		StatusFail: color.RedString("✘ FAIL"),
+	}
+	counts := map[string]int{}
+	group := ""
+	for _, c := range checks {
+		if c.Group != group {
+			if group != "" {
+				_, _ = fmt.Fprintln(w)
+			}
+			group = c.Group
+			_, _ = fmt.Fprintln(w, color.New(color.Bold).Sprint(group))
+		}
+		counts[c.Status]++
+
+		line := fmt.Sprintf("  %s  %s", marks[c.Status], c.Name)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		_, _ = fmt.Fprintln(w, line)
+		if c.Hint != "" && c.Status != StatusPass {
+			_, _ = fmt.Fprintf(w, "          %s %s\n", color.CyanString("→"), c.Hint)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d passed, %d warning(s), %d failed\n", counts[StatusPass], counts[StatusWarn], counts[StatusFail])
+}
@@ -0,0 +1,178 @@
+package doctor
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/compat"
+)
+
+func TestTools(t *testing.T) {
+	report := compat.Report{Components: []compat.Component{
+		{Name: compat.ComponentGZCTF, Status: compat.StatusUnknown},
+		{Name: compat.ComponentDocker, Version: "20.10.0", Source: "client, engine unreachable", Status: compat.StatusTested},
+		{Name: compat.ComponentCompose, Status: compat.StatusNotFound},
+		{Name: compat.ComponentKubectl, Status: compat.StatusNotFound},
+	}}
+	checks := Tools(report)
+
+	want := map[string]string{"docker": StatusWarn, "docker compose": StatusFail, "kubectl": StatusWarn}
+	if len(checks) != len(want) {
+		t.Fatalf("Tools() = %+v, want %d checks", checks, len(want))
+	}
+	for _, c := range checks {
+		if c.Status != want[c.Name] {
+			t.Errorf("%s status = %s, want %s", c.Name, c.Status, want[c.Name])
+		}
+		if c.Hint == "" {
+			t.Errorf("%s has no hint", c.Name)
+		}
+	}
+	if !Failed(checks) {
+		t.Error("Failed() = false with a missing docker compose")
+	}
+}
+
+func TestInotify(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inotify is Linux only")
+	}
+	eventsDir := t.TempDir()
+	for _, dir := range []string{"ctf/Web/a", "ctf/Web/a/node_modules/x", "ctf/.git/objects"} {
+		if err := os.MkdirAll(filepath.Join(eventsDir, dir), 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := watchedDirs(eventsDir); got != 4 {
+		t.Errorf("watchedDirs() = %d, want 4", got)
+	}
+
+	limitFile := filepath.Join(t.TempDir(), "max_user_watches")
+	old := inotifyWatchLimit
+	inotifyWatchLimit = limitFile
+	t.Cleanup(func() { inotifyWatchLimit = old })
+
+	for limit, want := range map[string]string{"8192\n": StatusPass, "6": StatusWarn, "4": StatusFail} {
+		if err := os.WriteFile(limitFile, []byte(limit), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if c := Inotify(eventsDir); c.Status != want {
+			t.Errorf("limit %q: status = %s (%s), want %s", limit, c.Status, c.Detail, want)
+		}
+	}
+}
+
+func TestSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix sockets")
+	}
+	// Unix socket paths are limited to about 100 bytes
+	dir, err := os.MkdirTemp("", "doctor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "d.sock")
+
+	if c := Socket("watcher socket", path, "start it"); c.Status != StatusPass {
+		t.Errorf("missing socket status = %s, want %s", c.Status, StatusPass)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := Socket("watcher socket", path, "start it"); c.Status != StatusPass {
+		t.Errorf("listening socket status = %s (%s), want %s", c.Status, c.Detail, StatusPass)
+	}
+
+	// Keep the socket file of a daemon that is gone
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = listener.Close()
+	c := Socket("watcher socket", path, "start it")
+	if c.Status != StatusWarn || c.Hint != "Start it" {
+		t.Errorf("stale socket = %+v, want a warning with the start hint", c)
+	}
+
+	file := filepath.Join(dir, "file.sock")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if c := Socket("watcher socket", file, "start it"); c.Status != StatusFail {
+		t.Errorf("regular file status = %s, want %s", c.Status, StatusFail)
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "watcher.db")
+	if c := Database(path); c.Status != StatusPass || !strings.Contains(c.Detail, "not created") {
+		t.Errorf("missing database = %+v, want a pass", c)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Database() created the missing database")
+	}
+
+	if err := os.WriteFile(path, []byte("not a database, but long enough to be read as a header"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if c := Database(path); c.Status != StatusFail || c.Hint == "" {
+		t.Errorf("corrupted database = %+v, want a failure with a hint", c)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	Print(&buf, []Check{
+		pass(GroupTools, "docker", "27.0.1"),
+		fail(GroupServer, "connectivity", "refused", "Start GZCTF"),
+	})
+	out := buf.String()
+	for _, want := range []string{"Tools", "docker: 27.0.1", "GZCTF server", "Start GZCTF", "1 passed, 0 warning(s), 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Print() output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	wd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if checks := Events(); !Failed(checks) {
+		t.Errorf("Events() = %+v without events/, want a failure", checks)
+	}
+
+	files := map[string]string{
+		"events/ctf/.gzevent":                "title: \"CTF\"\nstart: 2024-01-01T00:00:00Z\nend: 2024-01-02T00:00:00Z\n",
+		"events/ctf/Web/login/challenge.yml": "name: Login\n",
+		"events/ctf/web/old/challenge.yml":   "name: Old\n",
+		"events/notes/todo.md":               "",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checks := Events()
+	want := map[string]string{"event ctf": StatusWarn, "events/notes": StatusWarn}
+	if len(checks) != len(want) {
+		t.Fatalf("Events() = %+v, want %d checks", checks, len(want))
+	}
+	for _, c := range checks {
+		if c.Status != want[c.Name] {
+			t.Errorf("%s = %+v, want status %s", c.Name, c, want[c.Name])
+		}
+	}
+}
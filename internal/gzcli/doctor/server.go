@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// dialTimeout bounds the connection to the GZCTF server
+const dialTimeout = 5 * time.Second
+
+// confFile is the server configuration of the workspace
+var confFile = config.GZCTF_DIR + "/" + config.CONFIG_FILE
+
+// Server checks .gzctf/conf.yaml and the GZCTF server it points at: the
+// server must accept connections, log in with the configured credentials and
+// give them admin access. Later checks are skipped once one fails.
+func Server() []Check {
+	conf, err := config.GetServerConfig()
+	if err != nil {
+		return []Check{fail(GroupServer, "configuration", err.Error(),
+			"Run 'gzcli init' to create the workspace, or run gzcli from its root")}
+	}
+	checks := []Check{pass(GroupServer, "configuration", confFile)}
+
+	address, err := dialAddress(conf.Url)
+	if err != nil {
+		return append(checks, fail(GroupServer, "connectivity", err.Error(),
+			"Set url in "+confFile+" to the address of GZCTF, e.g. http://localhost:8080"))
+	}
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return append(checks, fail(GroupServer, "connectivity", fmt.Sprintf("%s: %v", conf.Url, err),
+			"Start GZCTF ('docker compose up -d' in "+config.GZCTF_DIR+") or fix url in "+confFile))
+	}
+	_ = conn.Close()
+	checks = append(checks, pass(GroupServer, "connectivity", conf.Url))
+
+	api, err := gzapi.Init(conf.Url, &conf.Creds)
+	if err != nil {
+		return append(checks, fail(GroupServer, "credentials", err.Error(),
+			"Check creds in "+confFile+" against the account on the GZCTF server"))
+	}
+	checks = append(checks, pass(GroupServer, "credentials", "logged in as "+conf.Creds.Username))
+
+	if _, err := api.GetGames(); err != nil {
+		return append(checks, fail(GroupServer, "admin access", err.Error(),
+			"Give "+conf.Creds.Username+" the Admin role in the GZCTF admin panel, or configure an admin account"))
+	}
+	return append(checks, pass(GroupServer, "admin access", "games can be managed"))
+}
+
+// dialAddress returns the host:port of a server URL
+func dialAddress(serverURL string) (string, error) {
+	if serverURL == "" {
+		return "", fmt.Errorf("no url configured")
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid url %q", serverURL)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
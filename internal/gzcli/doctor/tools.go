@@ -0,0 +1,71 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/compat"
+)
+
+// toolHints tell how to install the container tooling
+var toolHints = map[string]string{
+	compat.ComponentDocker:  "Install Docker Engine: https://docs.docker.com/engine/install/",
+	compat.ComponentCompose: "Install the Docker Compose plugin: https://docs.docker.com/compose/install/",
+	compat.ComponentKubectl: "Install kubectl to deploy challenges to Kubernetes: https://kubernetes.io/docs/tasks/tools/",
+}
+
+// Tools turns the Docker, Docker Compose and kubectl components of a
+// compatibility report (see compat.Check) into checks. Docker and Compose
+// deploy GZCTF and challenge services, so they fail when missing; kubectl is
+// only needed with Kubernetes.
+func Tools(report compat.Report) []Check {
+	var checks []Check
+	for _, c := range report.Components {
+		if c.Name == compat.ComponentGZCTF {
+			continue
+		}
+		name := c.Name
+		if name == compat.ComponentCompose {
+			name = "docker compose"
+		}
+
+		switch c.Status {
+		case compat.StatusNotFound:
+			if c.Name == compat.ComponentKubectl {
+				checks = append(checks, warn(GroupTools, name, "not installed", toolHints[c.Name]))
+			} else {
+				checks = append(checks, fail(GroupTools, name, "not installed", toolHints[c.Name]))
+			}
+		case compat.StatusUnknown:
+			checks = append(checks, fail(GroupTools, name, "version could not be read: "+c.Detail,
+				fmt.Sprintf("Run '%s' to see the error", versionCommand(c.Name))))
+		default:
+			detail := c.Version
+			if c.Source != "" {
+				detail += " (" + c.Source + ")"
+			}
+			switch {
+			case c.Name == compat.ComponentDocker && c.Source != "engine":
+				checks = append(checks, warn(GroupTools, name, detail,
+					"Start the Docker daemon, or add your user to the docker group: sudo usermod -aG docker $USER"))
+			case c.Status == compat.StatusUntested:
+				checks = append(checks, warn(GroupTools, name, detail+", untested (tested: "+c.Tested.String()+")",
+					"Use a tested version if deployments misbehave, see 'gzcli compat'"))
+			default:
+				checks = append(checks, pass(GroupTools, name, detail))
+			}
+		}
+	}
+	return checks
+}
+
+// versionCommand returns the command printing the version of a component
+func versionCommand(component string) string {
+	switch component {
+	case compat.ComponentCompose:
+		return "docker compose version"
+	case compat.ComponentKubectl:
+		return "kubectl version --client"
+	default:
+		return component + " version"
+	}
+}
@@ -0,0 +1,126 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzignore"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+)
+
+// inotifyWatchLimit is the file holding the inotify watch limit of a Linux
+// user. Replaced in tests.
+var inotifyWatchLimit = "/proc/sys/fs/inotify/max_user_watches"
+
+// socketTimeout bounds the connection to a daemon socket
+const socketTimeout = 2 * time.Second
+
+// Inotify checks that the inotify watch limit fits the directories below
+// eventsDir, which the watcher adds one watch each for. Editors and other
+// programs of the user take watches from the same limit, so using more than
+// half of it is a warning.
+func Inotify(eventsDir string) Check {
+	const name = "inotify watches"
+	if runtime.GOOS != "linux" {
+		return pass(GroupWatcher, name, "not used on "+runtime.GOOS)
+	}
+
+	data, err := os.ReadFile(inotifyWatchLimit)
+	if err != nil {
+		return warn(GroupWatcher, name, "limit could not be read: "+err.Error(), "")
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return warn(GroupWatcher, name, fmt.Sprintf("invalid limit %q", strings.TrimSpace(string(data))), "")
+	}
+
+	dirs := watchedDirs(eventsDir)
+	detail := fmt.Sprintf("%d directories to watch, limit %d", dirs, limit)
+	hint := sentence(challenge.WatchLimitHint)
+	switch {
+	case dirs >= limit:
+		return fail(GroupWatcher, name, detail, hint)
+	case dirs*2 > limit:
+		return warn(GroupWatcher, name, detail, hint)
+	default:
+		return pass(GroupWatcher, name, detail)
+	}
+}
+
+// watchedDirs counts the directories below root the watcher watches,
+// skipping hidden directories and those ignored by .gzignore
+func watchedDirs(root string) int {
+	count := 0
+	ignore := gzignore.New(root)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || ignore.Match(path, true)) {
+			return filepath.SkipDir
+		}
+		ignore.AddFile(path)
+		count++
+		return nil
+	})
+	return count
+}
+
+// Socket checks the Unix socket a daemon listens on at path. A missing
+// socket means the daemon is not running; an existing one must accept
+// connections from the current user. startHint tells how to start the daemon.
+func Socket(name, path, startHint string) Check {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return pass(GroupWatcher, name, "not running")
+	case err != nil:
+		return fail(GroupWatcher, name, err.Error(), "")
+	case info.Mode()&fs.ModeSocket == 0:
+		return fail(GroupWatcher, name, path+" is not a socket", "Remove "+path+", then "+startHint)
+	}
+
+	conn, err := net.DialTimeout("unix", path, socketTimeout)
+	switch {
+	case err == nil:
+		_ = conn.Close()
+		return pass(GroupWatcher, name, "running, "+path)
+	case errors.Is(err, syscall.EACCES):
+		return fail(GroupWatcher, name, "permission denied on "+path,
+			"Run gzcli as the user running the daemon, or restart the daemon so it recreates the socket")
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return warn(GroupWatcher, name, "stale socket "+path+", the daemon is not running", sentence(startHint))
+	default:
+		return fail(GroupWatcher, name, err.Error(), sentence(startHint))
+	}
+}
+
+// Database checks the watcher database at source, a SQLite file or a
+// postgres:// URL, see database.Check. A SQLite file that does not exist yet
+// is created by the watcher or the next sync.
+func Database(source string) Check {
+	const name = "database"
+	detail := source
+	hint := "Stop the watcher and move " + source + " away; it is recreated on the next start"
+	if database.IsURL(source) {
+		detail = "Postgres of $" + database.URLEnv
+		hint = "Check $" + database.URLEnv + " and that the Postgres server accepts connections"
+	} else if _, err := os.Stat(source); errors.Is(err, fs.ErrNotExist) {
+		return pass(GroupWatcher, name, source+" not created yet")
+	}
+
+	if err := database.Check(source); err != nil {
+		return fail(GroupWatcher, name, err.Error(), hint)
+	}
+	return pass(GroupWatcher, name, detail)
+}
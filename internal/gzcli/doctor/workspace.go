@@ -0,0 +1,77 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// Events checks the events directory of the workspace: every event needs a
+// valid .gzevent, and its challenges must be in category directories to be
+// synced
+func Events() []Check {
+	entries, err := os.ReadDir(config.EVENTS_DIR)
+	if err != nil {
+		return []Check{fail(GroupWorkspace, "events directory", err.Error(),
+			"Run 'gzcli init' to create the workspace, or run gzcli from its root")}
+	}
+
+	var checks []Check
+	events := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		eventPath := filepath.Join(config.EVENTS_DIR, entry.Name())
+		if _, err := os.Stat(filepath.Join(eventPath, config.GZEVENT_FILE)); err != nil {
+			checks = append(checks, warn(GroupWorkspace, eventPath, "no "+config.GZEVENT_FILE+", not an event",
+				"Add a "+config.GZEVENT_FILE+" or move the directory out of "+config.EVENTS_DIR+"/"))
+			continue
+		}
+		events++
+		checks = append(checks, checkEvent(entry.Name()))
+	}
+
+	if events == 0 {
+		checks = append(checks, fail(GroupWorkspace, "events", "no event in "+config.EVENTS_DIR+"/",
+			"Create one with 'gzcli event create <name>'"))
+	}
+	return checks
+}
+
+// checkEvent checks the .gzevent and the challenge directories of an event
+func checkEvent(event string) Check {
+	name := "event " + event
+	problems, err := config.ValidateFile(filepath.Join(config.EVENTS_DIR, event, config.GZEVENT_FILE))
+	if err != nil {
+		return fail(GroupWorkspace, name, err.Error(), "")
+	}
+	if len(problems) > 0 {
+		detail := problems[0].String()
+		if len(problems) > 1 {
+			detail += fmt.Sprintf(" (and %d more)", len(problems)-1)
+		}
+		return fail(GroupWorkspace, name, detail, "Run 'gzcli config validate' to list every problem")
+	}
+
+	unknown, err := config.UnknownCategoryDirs(event)
+	if err != nil {
+		return fail(GroupWorkspace, name, err.Error(), "")
+	}
+	if len(unknown) > 0 {
+		return warn(GroupWorkspace, name, "challenges outside of categories are not synced: "+strings.Join(unknown, ", "),
+			"Move them into one of "+strings.Join(config.CHALLENGE_CATEGORY, ", "))
+	}
+
+	files, err := config.ListChallengeFiles(event)
+	if err != nil {
+		return fail(GroupWorkspace, name, err.Error(), "")
+	}
+	if len(files) == 0 {
+		return warn(GroupWorkspace, name, "no challenges", "Create one with 'gzcli challenge new'")
+	}
+	return pass(GroupWorkspace, name, fmt.Sprintf("%d challenge(s)", len(files)))
+}
@@ -0,0 +1,30 @@
+package gzcli
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// ExportGame returns the configuration bundle of the game with gameID, or of
+// the event's game if gameID is 0
+func (gz *GZ) ExportGame(gameID int) (*gzapi.GameBundle, error) {
+	game := &gzapi.Game{Id: gameID, CS: gz.api}
+	if gameID == 0 {
+		var err error
+		if game, err = gz.EventGame(); err != nil {
+			return nil, err
+		}
+	}
+	return game.Export()
+}
+
+// ImportGame creates a new game on the server from a configuration bundle,
+// titled title if not empty
+func (gz *GZ) ImportGame(bundle *gzapi.GameBundle, title string) (*gzapi.ImportResult, error) {
+	result, err := gz.api.ImportGame(bundle, title)
+	if err != nil && result == nil {
+		return nil, fmt.Errorf("failed to import game: %w", err)
+	}
+	return result, err
+}
@@ -1,5 +1,7 @@
 package gzapi
 
+import "fmt"
+
 // LoginResponse represents the response from the login endpoint
 type LoginResponse struct {
 	Succeeded bool `json:"succeeded"`
@@ -41,6 +43,13 @@ func (cs *GZAPI) Logout() error {
 	if err := cs.post("/api/account/logout", nil, &response); err != nil {
 		return err
 	}
+	cs.ClearSession()
+	return nil
+}
+
+// ClearSession drops the session cookies of the client, including the cached
+// ones, without contacting the platform
+func (cs *GZAPI) ClearSession() {
 	if cs.cookieStore != nil {
 		if jar := cs.cookieStore.newJar(); jar != nil && cs.Client != nil {
 			cs.cookieJar = jar
@@ -48,5 +57,59 @@ func (cs *GZAPI) Logout() error {
 		}
 		cs.persistCookies()
 	}
+}
+
+// Relogin replaces the current session with a fresh login using the stored
+// credentials and refreshes the cookie cache
+func (cs *GZAPI) Relogin() error {
+	cs.ClearSession()
+	return cs.Login()
+}
+
+// PasswordChangeForm contains the data required to change the account password
+type PasswordChangeForm struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ChangePassword changes the password of the logged-in account and updates
+// the stored credentials. GZCTF renews the security stamp of the account,
+// which invalidates all of its other sessions.
+func (cs *GZAPI) ChangePassword(newPassword string) error {
+	if newPassword == "" {
+		return fmt.Errorf("new password cannot be empty")
+	}
+	if err := cs.put("/api/account/changepassword", &PasswordChangeForm{Old: cs.Creds.Password, New: newPassword}, nil); err != nil {
+		return err
+	}
+	cs.Creds.Password = newPassword
+	return nil
+}
+
+// LogoutAll invalidates every session of the account by rotating its
+// password to newPassword, then logs in again with it
+func (cs *GZAPI) LogoutAll(newPassword string) error {
+	if err := cs.ChangePassword(newPassword); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+	if err := cs.Relogin(); err != nil {
+		return fmt.Errorf("failed to log in with the new password: %w", err)
+	}
 	return nil
 }
+
+// Profile is the account information of the logged-in user
+type Profile struct {
+	UserId   string `json:"userId"`
+	UserName string `json:"userName"`
+	Email    string `json:"email"`
+}
+
+// Profile retrieves the account information of the logged-in user
+func (cs *GZAPI) Profile() (*Profile, error) {
+	var profile Profile
+	if err := cs.get("/api/account/profile", &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -6,9 +6,17 @@ type FileInfo struct {
 	Name string `json:"name"`
 }
 
-// CreateAssets uploads asset files to the GZCTF platform
+// CreateAssets uploads asset files to the GZCTF platform. Servers that
+// support resumable uploads receive the file in chunks, so an interrupted
+// upload of a large attachment continues where it stopped.
 func (cs *GZAPI) CreateAssets(file string) ([]FileInfo, error) {
 	var fileInfo []FileInfo
+	if cs.supportsResumableUpload("/api/assets") {
+		if err := cs.uploadResumable("/api/assets", file, &fileInfo); err != nil {
+			return nil, err
+		}
+		return fileInfo, nil
+	}
 	if err := cs.postMultiPart("/api/assets", file, &fileInfo); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,267 @@
+package gzapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GameBundleVersion is the format version of game bundles
+const GameBundleVersion = 1
+
+// GameBundle is the portable configuration of a game: its settings and its
+// challenges with their flags and hints. Submissions, teams, the poster and
+// server-specific IDs and keys are not part of it.
+type GameBundle struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exportedAt"`
+	Source     string            `json:"source,omitempty"` // URL of the exporting server
+	Game       Game              `json:"game"`
+	Challenges []BundleChallenge `json:"challenges"`
+}
+
+// BundleChallenge is a challenge of a game bundle
+//
+//nolint:revive // Field names match API responses
+type BundleChallenge struct {
+	Title                string            `json:"title"`
+	Content              string            `json:"content"`
+	Category             string            `json:"category"`
+	Type                 string            `json:"type"`
+	Hints                []string          `json:"hints,omitempty"`
+	FlagTemplate         string            `json:"flagTemplate,omitempty"`
+	IsEnabled            *bool             `json:"isEnabled,omitempty"`
+	Flags                []string          `json:"flags,omitempty"`
+	Attachment           *BundleAttachment `json:"attachment,omitempty"`
+	ContainerImage       string            `json:"containerImage,omitempty"`
+	MemoryLimit          int               `json:"memoryLimit"`
+	CpuCount             int               `json:"cpuCount"`
+	StorageLimit         int               `json:"storageLimit"`
+	ContainerExposePort  int               `json:"exposePort"`
+	NetworkMode          string            `json:"networkMode,omitempty"`
+	EnableTrafficCapture bool              `json:"enableTrafficCapture"`
+	DisableBloodBonus    bool              `json:"disableBloodBonus"`
+	DeadlineUtc          int64             `json:"deadlineUtc"`
+	SubmissionLimit      int               `json:"submissionLimit"`
+	OriginalScore        int               `json:"originalScore"`
+	MinScoreRate         float64           `json:"minScoreRate"`
+	Difficulty           float64           `json:"difficulty"`
+}
+
+// BundleAttachment is the attachment of a bundled challenge: a remote URL,
+// or the URL of a local asset identified by its hash
+type BundleAttachment struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+// AssetHash returns the hash of a local attachment from its /assets/<hash>/<name> URL
+func (a *BundleAttachment) AssetHash() string {
+	parts := strings.Split(strings.TrimPrefix(a.Url, "/"), "/")
+	if len(parts) >= 2 && parts[0] == "assets" {
+		return parts[1]
+	}
+	return ""
+}
+
+// Export returns the bundle of the game's configuration
+func (g *Game) Export() (*GameBundle, error) {
+	if g.CS == nil {
+		return nil, fmt.Errorf("GZAPI client is not initialized")
+	}
+	game, err := g.CS.GetGameById(g.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game %d: %w", g.Id, err)
+	}
+	challenges, err := game.GetChallenges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenges: %w", err)
+	}
+
+	bundle := &GameBundle{
+		Version:    GameBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Source:     g.CS.Url,
+		Game:       *game,
+		Challenges: make([]BundleChallenge, 0, len(challenges)),
+	}
+	bundle.Game.Id = 0
+	bundle.Game.PublicKey = ""
+	bundle.Game.Poster = ""
+	bundle.Game.CS = nil
+
+	for _, c := range challenges {
+		bc := BundleChallenge{
+			Title:                c.Title,
+			Content:              c.Content,
+			Category:             c.Category,
+			Type:                 c.Type,
+			Hints:                c.Hints,
+			FlagTemplate:         c.FlagTemplate,
+			IsEnabled:            c.IsEnabled,
+			ContainerImage:       c.ContainerImage,
+			MemoryLimit:          c.MemoryLimit,
+			CpuCount:             c.CpuCount,
+			StorageLimit:         c.StorageLimit,
+			ContainerExposePort:  c.ContainerExposePort,
+			NetworkMode:          c.NetworkMode,
+			EnableTrafficCapture: c.EnableTrafficCapture,
+			DisableBloodBonus:    c.DisableBloodBonus,
+			DeadlineUtc:          c.DeadlineUtc,
+			SubmissionLimit:      c.SubmissionLimit,
+			OriginalScore:        c.OriginalScore,
+			MinScoreRate:         c.MinScoreRate,
+			Difficulty:           c.Difficulty,
+		}
+		for _, f := range c.Flags {
+			bc.Flags = append(bc.Flags, f.Flag)
+		}
+		if c.Attachment != nil && c.Attachment.Url != "" {
+			bc.Attachment = &BundleAttachment{Type: c.Attachment.Type, Url: c.Attachment.Url}
+		}
+		bundle.Challenges = append(bundle.Challenges, bc)
+	}
+	return bundle, nil
+}
+
+// ImportResult reports what ImportGame created
+type ImportResult struct {
+	Game       *Game    `json:"-"`
+	GameID     int      `json:"gameId"`
+	Title      string   `json:"title"`
+	Challenges int      `json:"challenges"`
+	Flags      int      `json:"flags"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// ImportGame creates a new game from a bundle, titled title if not empty,
+// with its settings, challenges, flags and attachments. Local attachments are
+// only attached when the server already has their asset. A game with the
+// same title must not exist. Challenge failures are returned joined after
+// the others are imported.
+func (cs *GZAPI) ImportGame(bundle *GameBundle, title string) (*ImportResult, error) {
+	if bundle.Version != GameBundleVersion {
+		return nil, fmt.Errorf("unsupported game bundle version %d, expected %d", bundle.Version, GameBundleVersion)
+	}
+	if title == "" {
+		title = bundle.Game.Title
+	}
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("game bundle has no title")
+	}
+	if _, err := cs.GetGameByTitle(title); err == nil {
+		return nil, fmt.Errorf("game %q already exists", title)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+
+	game, err := cs.CreateGame(CreateGameForm{Title: title, Start: bundle.Game.Start.Time, End: bundle.Game.End.Time})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create game: %w", err)
+	}
+	settings := bundle.Game
+	settings.Id = game.Id
+	settings.Title = title
+	settings.PublicKey = game.PublicKey
+	settings.Poster = game.Poster
+	if err := game.Update(&settings); err != nil {
+		return nil, fmt.Errorf("failed to apply settings to game %d: %w", game.Id, err)
+	}
+
+	result := &ImportResult{Game: game, GameID: game.Id, Title: title}
+	assets := map[string]bool{}
+	if bundle.hasLocalAttachments() {
+		files, err := cs.GetAssets()
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to list assets, local attachments are skipped: %v", err))
+		}
+		for _, f := range files {
+			assets[f.Hash] = true
+		}
+	}
+
+	var errs []error
+	for _, bc := range bundle.Challenges {
+		flags, warning, err := game.importChallenge(bc, assets)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("challenge %q: %w", bc.Title, err))
+			continue
+		}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("challenge %q: %s", bc.Title, warning))
+		}
+		result.Challenges++
+		result.Flags += flags
+	}
+	return result, errors.Join(errs...)
+}
+
+// hasLocalAttachments reports whether a challenge of the bundle has a local
+// attachment
+func (b *GameBundle) hasLocalAttachments() bool {
+	for _, c := range b.Challenges {
+		if c.Attachment != nil && c.Attachment.Type == "Local" {
+			return true
+		}
+	}
+	return false
+}
+
+// importChallenge creates a bundled challenge in the game and returns the
+// number of flags created and a warning about its attachment, if any
+func (g *Game) importChallenge(bc BundleChallenge, assets map[string]bool) (int, string, error) {
+	created, err := g.CreateChallenge(CreateChallengeForm{Title: bc.Title, Category: bc.Category, Type: bc.Type})
+	if err != nil {
+		return 0, "", fmt.Errorf("create: %w", err)
+	}
+
+	update := *created
+	update.Content = bc.Content
+	update.Hints = bc.Hints
+	update.FlagTemplate = bc.FlagTemplate
+	update.IsEnabled = bc.IsEnabled
+	update.ContainerImage = bc.ContainerImage
+	update.MemoryLimit = bc.MemoryLimit
+	update.CpuCount = bc.CpuCount
+	update.StorageLimit = bc.StorageLimit
+	update.ContainerExposePort = bc.ContainerExposePort
+	update.NetworkMode = bc.NetworkMode
+	update.EnableTrafficCapture = bc.EnableTrafficCapture
+	update.DisableBloodBonus = bc.DisableBloodBonus
+	update.DeadlineUtc = bc.DeadlineUtc
+	update.SubmissionLimit = bc.SubmissionLimit
+	update.OriginalScore = bc.OriginalScore
+	update.MinScoreRate = bc.MinScoreRate
+	update.Difficulty = bc.Difficulty
+	update.Flags = nil
+	update.Attachment = nil
+	challenge, err := created.Update(update)
+	if err != nil {
+		return 0, "", fmt.Errorf("update: %w", err)
+	}
+
+	flags := make([]CreateFlagForm, 0, len(bc.Flags))
+	for _, f := range bc.Flags {
+		flags = append(flags, CreateFlagForm{Flag: f})
+	}
+	if err := challenge.CreateFlags(flags); err != nil {
+		return 0, "", fmt.Errorf("create flags: %w", err)
+	}
+
+	warning := ""
+	if a := bc.Attachment; a != nil {
+		switch {
+		case a.Type == "Remote":
+			err = challenge.CreateAttachment(CreateAttachmentForm{AttachmentType: "Remote", RemoteUrl: a.Url})
+		case a.Type == "Local" && assets[a.AssetHash()]:
+			err = challenge.CreateAttachment(CreateAttachmentForm{AttachmentType: "Local", FileHash: a.AssetHash()})
+		default:
+			warning = fmt.Sprintf("attachment %s is not on this server, sync the challenge to upload it", a.Url)
+		}
+		if err != nil {
+			return len(flags), "", fmt.Errorf("attachment: %w", err)
+		}
+	}
+	return len(flags), warning, nil
+}
@@ -0,0 +1,198 @@
+//nolint:revive // Test file with unused parameters
+package gzapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGame_Export(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/3": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":3,"title":"CTF","summary":"yearly","publicKey":"secret","poster":"/assets/p/poster.png"}`))
+		},
+		"/api/edit/games/3/challenges": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"id":11,"title":"web1"}]`))
+		},
+		"/api/edit/games/3/challenges/11": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":11,"title":"web1","category":"Web","type":"StaticAttachment","hints":["look"],
+				"flags":[{"id":1,"flag":"flag{a}"},{"id":2,"flag":"flag{b}"}],
+				"attachment":{"id":5,"type":"Local","url":"/assets/abc/dist.zip"},"originalScore":500}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	bundle, err := (&Game{Id: 3, CS: api}).Export()
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	if bundle.Version != GameBundleVersion || bundle.Source != server.URL {
+		t.Errorf("unexpected bundle header: version %d, source %q", bundle.Version, bundle.Source)
+	}
+	if bundle.Game.Id != 0 || bundle.Game.PublicKey != "" || bundle.Game.Poster != "" {
+		t.Errorf("server-specific game fields exported: %+v", bundle.Game)
+	}
+	if bundle.Game.Title != "CTF" || bundle.Game.Summary != "yearly" {
+		t.Errorf("game settings not exported: %+v", bundle.Game)
+	}
+	if len(bundle.Challenges) != 1 {
+		t.Fatalf("expected 1 challenge, got %d", len(bundle.Challenges))
+	}
+	c := bundle.Challenges[0]
+	if strings.Join(c.Flags, ",") != "flag{a},flag{b}" || c.OriginalScore != 500 || c.Category != "Web" {
+		t.Errorf("unexpected challenge: %+v", c)
+	}
+	if c.Attachment == nil || c.Attachment.AssetHash() != "abc" {
+		t.Errorf("unexpected attachment: %+v", c.Attachment)
+	}
+}
+
+// importServer is a GZCTF server recording the game an import creates
+type importServer struct {
+	mu          sync.Mutex
+	settings    Game
+	challenges  []Challenge
+	flags       map[int][]string
+	attachments map[int]CreateAttachmentForm
+}
+
+func (s *importServer) handlers(t *testing.T) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/api/edit/games": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				_, _ = w.Write([]byte(`{"id":7,"title":"Imported","publicKey":"new-key"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":1,"title":"Existing"}]}`))
+		},
+		"/api/admin/files": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data":[{"hash":"abc","name":"dist.zip"}]}`))
+		},
+		"/api/edit/games/7": func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if err := json.NewDecoder(r.Body).Decode(&s.settings); err != nil {
+				t.Errorf("invalid game settings: %v", err)
+			}
+			_, _ = w.Write([]byte(`{}`))
+		},
+		"/api/edit/games/7/challenges": func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if r.Method != http.MethodPost {
+				_ = json.NewEncoder(w).Encode(s.challenges)
+				return
+			}
+			var form CreateChallengeForm
+			_ = json.NewDecoder(r.Body).Decode(&form)
+			c := Challenge{Id: len(s.challenges) + 1, Title: form.Title, Category: form.Category, Type: form.Type}
+			s.challenges = append(s.challenges, c)
+			_ = json.NewEncoder(w).Encode(c)
+		},
+		"/api/edit/games/7/challenges/": func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			var id int
+			var rest string
+			_, _ = fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/api/edit/games/7/challenges/"), "%d%s", &id, &rest)
+			switch rest {
+			case "":
+				var c Challenge
+				_ = json.NewDecoder(r.Body).Decode(&c)
+				s.challenges[id-1] = c
+			case "/flags":
+				var flags []CreateFlagForm
+				_ = json.NewDecoder(r.Body).Decode(&flags)
+				for _, f := range flags {
+					s.flags[id] = append(s.flags[id], f.Flag)
+				}
+			case "/attachment":
+				var form CreateAttachmentForm
+				_ = json.NewDecoder(r.Body).Decode(&form)
+				s.attachments[id] = form
+			}
+			_, _ = w.Write([]byte(`{}`))
+		},
+	}
+}
+
+func TestGZAPI_ImportGame(t *testing.T) {
+	recorder := &importServer{flags: map[int][]string{}, attachments: map[int]CreateAttachmentForm{}}
+	server := mockServer(t, recorder.handlers(t))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	bundle := &GameBundle{
+		Version: GameBundleVersion,
+		Game:    Game{Title: "CTF", Summary: "yearly", PublicKey: "old-key"},
+		Challenges: []BundleChallenge{
+			{Title: "web1", Category: "Web", Type: "StaticAttachment", Flags: []string{"flag{a}", "flag{b}"},
+				Attachment: &BundleAttachment{Type: "Local", Url: "/assets/abc/dist.zip"}, OriginalScore: 500},
+			{Title: "pwn1", Category: "Pwn", Type: "StaticAttachment", Flags: []string{"flag{c}"},
+				Attachment: &BundleAttachment{Type: "Local", Url: "/assets/missing/pwn.zip"}},
+			{Title: "misc1", Category: "Misc", Type: "StaticAttachment",
+				Attachment: &BundleAttachment{Type: "Remote", Url: "https://example.com/misc.zip"}},
+		},
+	}
+
+	result, err := api.ImportGame(bundle, "Imported")
+	if err != nil {
+		t.Fatalf("ImportGame() failed: %v", err)
+	}
+	if result.GameID != 7 || result.Challenges != 3 || result.Flags != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "pwn1") {
+		t.Errorf("expected a warning about the missing asset of pwn1, got %v", result.Warnings)
+	}
+
+	if recorder.settings.Title != "Imported" || recorder.settings.Summary != "yearly" || recorder.settings.PublicKey != "new-key" {
+		t.Errorf("unexpected game settings: %+v", recorder.settings)
+	}
+	if recorder.challenges[0].OriginalScore != 500 {
+		t.Errorf("challenge settings not applied: %+v", recorder.challenges[0])
+	}
+	if strings.Join(recorder.flags[1], ",") != "flag{a},flag{b}" {
+		t.Errorf("unexpected flags of web1: %v", recorder.flags[1])
+	}
+	if got := recorder.attachments[1]; got.AttachmentType != "Local" || got.FileHash != "abc" {
+		t.Errorf("unexpected attachment of web1: %+v", got)
+	}
+	if _, ok := recorder.attachments[2]; ok {
+		t.Error("attachment of pwn1 created without its asset")
+	}
+	if got := recorder.attachments[3]; got.AttachmentType != "Remote" || got.RemoteUrl != "https://example.com/misc.zip" {
+		t.Errorf("unexpected attachment of misc1: %+v", got)
+	}
+}
+
+func TestGZAPI_ImportGame_Rejected(t *testing.T) {
+	recorder := &importServer{flags: map[int][]string{}, attachments: map[int]CreateAttachmentForm{}}
+	server := mockServer(t, recorder.handlers(t))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if _, err := api.ImportGame(&GameBundle{Version: GameBundleVersion + 1, Game: Game{Title: "New"}}, ""); err == nil {
+		t.Error("expected an error for an unsupported bundle version")
+	}
+	if _, err := api.ImportGame(&GameBundle{Version: GameBundleVersion, Game: Game{Title: "Existing"}}, ""); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error for an existing title, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@
 package gzapi
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -102,34 +103,34 @@ func (c *gameChallengeCache) deleteByID(gameID int, api *GZAPI, challengeID int)
 }
 
 type Challenge struct {
-	Id                   int         `json:"id" yaml:"id"`
-	Title                string      `json:"title" yaml:"title"`
-	Content              string      `json:"content" yaml:"content"`
-	Category             string      `json:"category" yaml:"category"`
-	Type                 string      `json:"type" yaml:"type"`
-	Hints                []string    `json:"hints" yaml:"hints"`
-	FlagTemplate         string      `json:"flagTemplate" yaml:"flagTemplate"`
-	IsEnabled            *bool       `json:"isEnabled,omitempty" yaml:"isEnabled,omitempty"`
-	AcceptedCount        int         `json:"acceptedCount" yaml:"acceptedCount"`
-	FileName             string      `json:"fileName" yaml:"fileName"`
-	Attachment           *Attachment `json:"attachment" yaml:"attachment"`
-	TestContainer        interface{} `json:"testContainer" yaml:"testContainer"`
-	Flags                []Flag      `json:"flags" yaml:"flags"`
-	ContainerImage       string      `json:"containerImage" yaml:"containerImage"`
-	MemoryLimit          int         `json:"memoryLimit" yaml:"memoryLimit"`
-	CpuCount             int         `json:"cpuCount" yaml:"cpuCount"`
-	StorageLimit         int         `json:"storageLimit" yaml:"storageLimit"`
-	ContainerExposePort  int         `json:"exposePort" yaml:"exposePort"`
-	NetworkMode          string      `json:"networkMode" yaml:"networkMode"`
-	EnableTrafficCapture bool        `json:"enableTrafficCapture" yaml:"enableTrafficCapture"`
-	DisableBloodBonus    bool        `json:"disableBloodBonus" yaml:"disableBloodBonus"`
-	DeadlineUtc          int64       `json:"deadlineUtc" yaml:"deadlineUtc"`
-	SubmissionLimit      int         `json:"submissionLimit" yaml:"submissionLimit"`
-	OriginalScore        int         `json:"originalScore" yaml:"originalScore"`
-	MinScoreRate         float64     `json:"minScoreRate" yaml:"minScoreRate"`
-	Difficulty           float64     `json:"difficulty" yaml:"difficulty"`
-	GameId               int         `json:"-" yaml:"gameId"`
-	CS                   *GZAPI      `json:"-" yaml:"-"`
+	Id                   int            `json:"id" yaml:"id"`
+	Title                string         `json:"title" yaml:"title"`
+	Content              string         `json:"content" yaml:"content"`
+	Category             string         `json:"category" yaml:"category"`
+	Type                 string         `json:"type" yaml:"type"`
+	Hints                []string       `json:"hints" yaml:"hints"`
+	FlagTemplate         string         `json:"flagTemplate" yaml:"flagTemplate"`
+	IsEnabled            *bool          `json:"isEnabled,omitempty" yaml:"isEnabled,omitempty"`
+	AcceptedCount        int            `json:"acceptedCount" yaml:"acceptedCount"`
+	FileName             string         `json:"fileName" yaml:"fileName"`
+	Attachment           *Attachment    `json:"attachment" yaml:"attachment"`
+	TestContainer        *ContainerInfo `json:"testContainer" yaml:"testContainer"`
+	Flags                []Flag         `json:"flags" yaml:"flags"`
+	ContainerImage       string         `json:"containerImage" yaml:"containerImage"`
+	MemoryLimit          int            `json:"memoryLimit" yaml:"memoryLimit"`
+	CpuCount             int            `json:"cpuCount" yaml:"cpuCount"`
+	StorageLimit         int            `json:"storageLimit" yaml:"storageLimit"`
+	ContainerExposePort  int            `json:"exposePort" yaml:"exposePort"`
+	NetworkMode          string         `json:"networkMode" yaml:"networkMode"`
+	EnableTrafficCapture bool           `json:"enableTrafficCapture" yaml:"enableTrafficCapture"`
+	DisableBloodBonus    bool           `json:"disableBloodBonus" yaml:"disableBloodBonus"`
+	DeadlineUtc          int64          `json:"deadlineUtc" yaml:"deadlineUtc"`
+	SubmissionLimit      int            `json:"submissionLimit" yaml:"submissionLimit"`
+	OriginalScore        int            `json:"originalScore" yaml:"originalScore"`
+	MinScoreRate         float64        `json:"minScoreRate" yaml:"minScoreRate"`
+	Difficulty           float64        `json:"difficulty" yaml:"difficulty"`
+	GameId               int            `json:"-" yaml:"gameId"`
+	CS                   *GZAPI         `json:"-" yaml:"-"`
 }
 
 func (c *Challenge) Delete() error {
@@ -183,7 +184,14 @@ func (g *Game) CreateChallenge(challenge CreateChallengeForm) (*Challenge, error
 	}
 
 	var data *Challenge
-	if err := g.CS.post(fmt.Sprintf("/api/edit/games/%d/challenges", g.Id), challenge, &data); err != nil {
+	applied := func() (bool, error) {
+		existing, err := g.findChallenge(challenge.Title)
+		if existing != nil {
+			data = existing
+		}
+		return existing != nil, err
+	}
+	if err := g.CS.postIdempotent(fmt.Sprintf("/api/edit/games/%d/challenges", g.Id), challenge, &data, applied); err != nil {
 		return nil, err
 	}
 	data.GameId = g.Id
@@ -192,66 +200,189 @@ func (g *Game) CreateChallenge(challenge CreateChallengeForm) (*Challenge, error
 	return data, nil
 }
 
-func (g *Game) GetChallenges() ([]Challenge, error) {
+// findChallenge fetches the challenge titled title from the platform,
+// bypassing the cache, or returns nil if the game has none
+func (g *Game) findChallenge(title string) (*Challenge, error) {
+	list, err := g.ListChallenges()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		if c.Title != title {
+			continue
+		}
+		details, err := g.GetChallengesByID([]int{c.Id})
+		if err != nil {
+			return nil, err
+		}
+		return &details[0], nil
+	}
+	return nil, nil
+}
+
+// ListChallenges returns the game's challenges as listed by the API, in a
+// single request. Only summary fields (id, title, category, type, scores and
+// enabled state) are populated; use GetChallengesByID for full details.
+func (g *Game) ListChallenges() ([]Challenge, error) {
 	if g.CS == nil {
 		return nil, fmt.Errorf("GZAPI client is not initialized")
 	}
 
-	var tmp []Challenge
 	var data []Challenge
-	if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges", g.Id), &tmp); err != nil {
+	if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges", g.Id), &data); err != nil {
 		return nil, err
 	}
-	if len(tmp) == 0 {
-		return data, nil
+	for i := range data {
+		data[i].GameId = g.Id
+		data[i].CS = g.CS
 	}
+	return data, nil
+}
 
-	workers := resolveChallengeFetchWorkers(len(tmp))
-	var wg sync.WaitGroup
-	jobs := make(chan int, len(tmp))
-	errs := make([]error, len(tmp))
-	details := make([]Challenge, len(tmp))
-	ok := make([]bool, len(tmp))
+// GetChallenges returns every challenge of the game with full details. The
+// details are fetched in parallel, see GetChallengesByID.
+func (g *Game) GetChallenges() ([]Challenge, error) {
+	list, err := g.ListChallenges()
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return []Challenge{}, nil
+	}
 
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for idx := range jobs {
-				var c Challenge
-				if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges/%d", g.Id, tmp[idx].Id), &c); err != nil {
-					errs[idx] = fmt.Errorf("fetch challenge id %d: %w", tmp[idx].Id, err)
-					continue
-				}
-				c.GameId = g.Id
-				c.CS = g.CS
-				details[idx] = c
-				ok[idx] = true
-			}
-		}()
+	ids := make([]int, len(list))
+	for i := range list {
+		ids[i] = list[i].Id
+	}
+	data, err := g.GetChallengesByID(ids)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := range tmp {
-		jobs <- i
+	challengeCache.setGameChallenges(g.Id, g.CS, data)
+	return data, nil
+}
+
+// GetChallengesSelective returns every challenge of the game, fetching full
+// details only for the challenges want selects. The others keep the summary
+// fields from ListChallenges. This refreshes a game in 1 + selected requests
+// instead of one request per challenge.
+func (g *Game) GetChallengesSelective(want func(Challenge) bool) ([]Challenge, error) {
+	list, err := g.ListChallenges()
+	if err != nil {
+		return nil, err
 	}
-	close(jobs)
-	wg.Wait()
 
-	for i := range errs {
-		if errs[i] != nil {
-			return nil, errs[i]
+	var ids []int
+	positions := make(map[int]int)
+	for i := range list {
+		if want(list[i]) {
+			ids = append(ids, list[i].Id)
+			positions[list[i].Id] = i
 		}
 	}
+	if len(ids) == 0 {
+		return list, nil
+	}
 
-	data = make([]Challenge, 0, len(tmp))
-	for i := range details {
-		if ok[i] {
-			data = append(data, details[i])
+	details, err := g.GetChallengesByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range details {
+		list[positions[c.Id]] = c
+		challengeCache.upsertChallenge(g.Id, g.CS, c)
+	}
+	return list, nil
+}
+
+// GetChallengesByID fetches full details of the given challenges in parallel,
+// bounded by GZCLI_GET_CHALLENGES_WORKERS (default 6). Results keep the order
+// of ids; the first failure is returned.
+func (g *Game) GetChallengesByID(ids []int) ([]Challenge, error) {
+	if g.CS == nil {
+		return nil, fmt.Errorf("GZAPI client is not initialized")
+	}
+
+	details := make([]Challenge, len(ids))
+	errs := runChallengeBatch(len(ids), func(idx int) error {
+		var c Challenge
+		if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges/%d", g.Id, ids[idx]), &c); err != nil {
+			return fmt.Errorf("fetch challenge id %d: %w", ids[idx], err)
 		}
+		c.GameId = g.Id
+		c.CS = g.CS
+		details[idx] = c
+		return nil
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+// BulkUpdate applies updates to existing challenges of the game in parallel,
+// with the same worker limit as GetChallengesByID. Each update is identified
+// by its Id. The updated challenges are returned in order, skipping failed
+// ones, together with the joined errors of the failures.
+func (g *Game) BulkUpdate(updates []Challenge) ([]Challenge, error) {
+	if g.CS == nil {
+		return nil, fmt.Errorf("GZAPI client is not initialized")
 	}
 
-	challengeCache.setGameChallenges(g.Id, g.CS, data)
-	return data, nil
+	updated := make([]Challenge, len(updates))
+	errs := runChallengeBatch(len(updates), func(idx int) error {
+		c := updates[idx]
+		if c.Id == 0 {
+			return fmt.Errorf("update challenge %q: missing id", c.Title)
+		}
+		if err := g.CS.put(fmt.Sprintf("/api/edit/games/%d/challenges/%d", g.Id, c.Id), &c, nil); err != nil {
+			return fmt.Errorf("update challenge id %d: %w", c.Id, err)
+		}
+		c.GameId = g.Id
+		c.CS = g.CS
+		updated[idx] = c
+		challengeCache.upsertChallenge(g.Id, g.CS, c)
+		return nil
+	})
+
+	data := make([]Challenge, 0, len(updates))
+	for i := range updated {
+		if errs[i] == nil {
+			data = append(data, updated[i])
+		}
+	}
+	return data, errors.Join(errs...)
+}
+
+// runChallengeBatch runs fn for indexes [0, total) on a bounded worker pool
+// and returns the error of each call
+func runChallengeBatch(total int, fn func(idx int) error) []error {
+	errs := make([]error, total)
+	if total == 0 {
+		return errs
+	}
+
+	jobs := make(chan int, total)
+	for i := 0; i < total; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < resolveChallengeFetchWorkers(total); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				errs[idx] = fn(idx)
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
 }
 
 func resolveChallengeFetchWorkers(total int) int {
@@ -297,7 +428,7 @@ func (g *Game) GetChallenge(name string) (*Challenge, error) {
 		}
 	}
 	if challenge == nil {
-		return nil, fmt.Errorf("challenge not found")
+		return nil, fmt.Errorf("challenge %w", ErrNotFound)
 	}
 	if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges/%d", g.Id, challenge.Id), &challenge); err != nil {
 		return nil, err
@@ -0,0 +1,114 @@
+package gzapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Container statuses reported by GZCTF
+const (
+	ContainerPending   = "Pending"
+	ContainerRunning   = "Running"
+	ContainerDestroyed = "Destroyed"
+)
+
+const (
+	// TestContainerStartTimeout bounds how long StartTestContainer polls a
+	// pending test container before giving up
+	TestContainerStartTimeout = 2 * time.Minute
+
+	// testContainerPollInterval is the delay between polls of a pending test container
+	testContainerPollInterval = 2 * time.Second
+)
+
+// ContainerInfo is the state of a challenge container, such as the test
+// container admins start from the challenge editor
+type ContainerInfo struct {
+	Status       string     `json:"status" yaml:"status"` // One of the Container* constants
+	StartedAt    CustomTime `json:"startedAt" yaml:"startedAt"`
+	ExpectStopAt CustomTime `json:"expectStopAt" yaml:"expectStopAt"`
+	Entry        string     `json:"entry" yaml:"entry"` // host:port players connect to
+}
+
+// CreateTestContainer starts the test container of a container challenge
+// and returns its state, including the entry to connect to
+func (c *Challenge) CreateTestContainer() (*ContainerInfo, error) {
+	if c.CS == nil {
+		return nil, fmt.Errorf("GZAPI client is not initialized")
+	}
+	var info ContainerInfo
+	if err := c.CS.post(fmt.Sprintf("/api/edit/games/%d/challenges/%d/container", c.GameId, c.Id), nil, &info); err != nil {
+		return nil, err
+	}
+	c.TestContainer = &info
+	return &info, nil
+}
+
+// StartTestContainer starts the test container of the challenge and waits
+// until it runs, for at most TestContainerStartTimeout
+func (c *Challenge) StartTestContainer(ctx context.Context) (*ContainerInfo, error) {
+	info, err := c.CreateTestContainer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the test container: %w", err)
+	}
+
+	deadline := time.Now().Add(TestContainerStartTimeout)
+	for info.Status == ContainerPending {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("test container still pending after %s", TestContainerStartTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(testContainerPollInterval):
+		}
+		if info, err = c.GetContainerInfo(); err != nil {
+			return nil, fmt.Errorf("failed to get the test container: %w", err)
+		}
+	}
+	if info.Status != ContainerRunning {
+		return nil, fmt.Errorf("test container is %s", info.Status)
+	}
+	return info, nil
+}
+
+// HostPort splits the entry of a container into its host and port
+func (info *ContainerInfo) HostPort() (string, int, error) {
+	host, portStr, err := net.SplitHostPort(info.Entry)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid container entry %q: %w", info.Entry, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid container entry %q: %w", info.Entry, err)
+	}
+	return host, port, nil
+}
+
+// DestroyTestContainer stops the test container of the challenge
+func (c *Challenge) DestroyTestContainer() error {
+	if c.CS == nil {
+		return fmt.Errorf("GZAPI client is not initialized")
+	}
+	if err := c.CS.delete(fmt.Sprintf("/api/edit/games/%d/challenges/%d/container", c.GameId, c.Id), nil); err != nil {
+		return err
+	}
+	c.TestContainer = nil
+	return nil
+}
+
+// GetContainerInfo fetches the state of the test container of the challenge,
+// or returns ErrNotFound when none is running
+func (c *Challenge) GetContainerInfo() (*ContainerInfo, error) {
+	refreshed, err := c.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.TestContainer == nil {
+		return nil, fmt.Errorf("test container %w", ErrNotFound)
+	}
+	return refreshed.TestContainer, nil
+}
@@ -0,0 +1,98 @@
+package gzapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestChallenge_TestContainer(t *testing.T) {
+	running := false
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges/5/container": func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "POST":
+				running = true
+				_, _ = w.Write([]byte(`{"status":"Running","startedAt":1700000000000,"expectStopAt":1700007200000,"entry":"10.0.0.5:31337"}`))
+			case "DELETE":
+				running = false
+				w.WriteHeader(http.StatusOK)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		},
+		"/api/edit/games/1/challenges/5": func(w http.ResponseWriter, _ *http.Request) {
+			if running {
+				_, _ = w.Write([]byte(`{"id":5,"title":"web","testContainer":{"status":"Running","startedAt":1700000000000,"expectStopAt":1700007200000,"entry":"10.0.0.5:31337"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":5,"title":"web","testContainer":null}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "container", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	challenge := &Challenge{Id: 5, GameId: 1, CS: api}
+
+	info, err := challenge.CreateTestContainer()
+	if err != nil {
+		t.Fatalf("CreateTestContainer() failed: %v", err)
+	}
+	if info.Status != ContainerRunning || info.Entry != "10.0.0.5:31337" || info.StartedAt.UnixMilli() != 1700000000000 {
+		t.Errorf("CreateTestContainer() = %+v", info)
+	}
+
+	info, err = challenge.GetContainerInfo()
+	if err != nil || info.Entry != "10.0.0.5:31337" {
+		t.Errorf("GetContainerInfo() = %+v, %v", info, err)
+	}
+
+	if err := challenge.DestroyTestContainer(); err != nil {
+		t.Fatalf("DestroyTestContainer() failed: %v", err)
+	}
+	if _, err := challenge.GetContainerInfo(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetContainerInfo() after destroy error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChallenge_StartTestContainer(t *testing.T) {
+	status := ContainerRunning
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges/5/container": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"status":"` + status + `","entry":"10.0.0.5:31337"}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "container", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	challenge := &Challenge{Id: 5, GameId: 1, CS: api}
+
+	info, err := challenge.StartTestContainer(context.Background())
+	if err != nil || info.Entry != "10.0.0.5:31337" {
+		t.Fatalf("StartTestContainer() = %+v, %v", info, err)
+	}
+
+	status = ContainerDestroyed
+	if _, err := challenge.StartTestContainer(context.Background()); err == nil {
+		t.Error("StartTestContainer() succeeded for a destroyed container")
+	}
+}
+
+func TestContainerInfo_HostPort(t *testing.T) {
+	host, port, err := (&ContainerInfo{Entry: "10.0.0.5:31337"}).HostPort()
+	if err != nil || host != "10.0.0.5" || port != 31337 {
+		t.Errorf("HostPort() = %q, %d, %v", host, port, err)
+	}
+	for _, entry := range []string{"", "10.0.0.5", "10.0.0.5:http"} {
+		if _, _, err := (&ContainerInfo{Entry: entry}).HostPort(); err == nil {
+			t.Errorf("HostPort() of %q succeeded", entry)
+		}
+	}
+}
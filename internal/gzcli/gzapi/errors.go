@@ -0,0 +1,106 @@
+package gzapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched with errors.Is. An *APIError matches the one its
+// status code stands for.
+var (
+	// ErrNotFound is returned for 404 responses and for lookups by name that
+	// find nothing
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is returned for 401 responses, including after a
+	// failed re-login
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned for 403 responses
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict is returned for 409 responses
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited is returned for 429 responses
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// maxErrorBody is the number of bytes of a response body kept in an APIError
+const maxErrorBody = 2048
+
+// APIError is a response from the GZCTF API with an unexpected status code
+type APIError struct {
+	Method     string
+	Endpoint   string // Path relative to the server URL
+	StatusCode int
+	Body       string // Response body, truncated to maxErrorBody bytes
+}
+
+// newAPIError returns the error of a response with an unexpected status,
+// keeping the body the server sent with it
+func newAPIError(method, endpoint string, statusCode int, body []byte) *APIError {
+	return &APIError{Method: method, Endpoint: endpoint, StatusCode: statusCode, Body: truncateErrorBody(body)}
+}
+
+// truncateErrorBody returns body as a string of at most maxErrorBody bytes
+func truncateErrorBody(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if len(text) <= maxErrorBody {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxErrorBody], "") + "... (truncated)"
+}
+
+// errorResponse is the JSON body of GZCTF error responses. Title holds the
+// message, in the language of the request.
+type errorResponse struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// Message returns the error message of the server: the title (and detail) of
+// a GZCTF error response, otherwise the body as sent
+func (e *APIError) Message() string {
+	var resp errorResponse
+	if json.Unmarshal([]byte(e.Body), &resp) == nil && resp.Title != "" {
+		if resp.Detail != "" {
+			return resp.Title + ": " + resp.Detail
+		}
+		return resp.Title
+	}
+	return e.Body
+}
+
+func (e *APIError) Error() string {
+	if message := e.Message(); message != "" {
+		return fmt.Sprintf("request end with %d status, %s", e.StatusCode, message)
+	}
+	return fmt.Sprintf("request end with %d status", e.StatusCode)
+}
+
+// Is reports whether target is the sentinel error for the status code
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	}
+	return false
+}
+
+// StatusCode returns the HTTP status code of the API error wrapped in err,
+// or 0 if err is not an API error
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
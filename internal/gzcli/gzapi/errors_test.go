@@ -0,0 +1,143 @@
+package gzapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoRequest_APIError(t *testing.T) {
+	statuses := map[string]int{
+		"/api/missing":    http.StatusNotFound,
+		"/api/forbidden":  http.StatusForbidden,
+		"/api/conflict":   http.StatusConflict,
+		"/api/throttled":  http.StatusTooManyRequests,
+		"/api/unexpected": http.StatusInternalServerError,
+	}
+	handlers := map[string]http.HandlerFunc{}
+	for path, status := range statuses {
+		status := status
+		handlers[path] = func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("body"))
+		}
+	}
+	server := mockServer(t, handlers)
+	defer server.Close()
+	api := &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}
+
+	sentinels := map[string]error{
+		"/api/missing":   ErrNotFound,
+		"/api/forbidden": ErrForbidden,
+		"/api/conflict":  ErrConflict,
+		"/api/throttled": ErrRateLimited,
+	}
+	all := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrConflict, ErrRateLimited}
+
+	for path, status := range statuses {
+		err := fmt.Errorf("wrapped: %w", api.get(path, nil))
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%s: error %v is not an *APIError", path, err)
+		}
+		if apiErr.StatusCode != status || apiErr.Endpoint != path || apiErr.Method != "GET" || apiErr.Body != "body" {
+			t.Errorf("%s: APIError = %+v", path, apiErr)
+		}
+		if StatusCode(err) != status {
+			t.Errorf("%s: StatusCode() = %d, want %d", path, StatusCode(err), status)
+		}
+		for _, sentinel := range all {
+			if got, want := errors.Is(err, sentinel), sentinel == sentinels[path]; got != want {
+				t.Errorf("%s: errors.Is(%v) = %v, want %v", path, sentinel, got, want)
+			}
+		}
+	}
+
+	if StatusCode(errors.New("request end with 404 status")) != 0 {
+		t.Error("StatusCode() of a plain error should be 0")
+	}
+}
+
+func TestDoRequest_UnauthorizedAfterRelogin(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/account/login": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		},
+		"/api/private": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		},
+	})
+	defer server.Close()
+
+	api := &GZAPI{Url: server.URL, Client: createOptimizedClient(nil), Creds: &Creds{Username: "u", Password: "p"}}
+	if err := api.get("/api/private", nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("error %v does not match ErrUnauthorized", err)
+	}
+}
+
+func TestGetChallenge_NotFound(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		},
+	})
+	defer server.Close()
+
+	game := &Game{Id: 1, CS: &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}}
+	if _, err := game.GetChallenge("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error %v does not match ErrNotFound", err)
+	}
+}
+
+func TestDoRequest_LocalizedErrorBody(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/game/1": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			if r.Header.Get("Accept-Language") == "zh-CN" {
+				_, _ = w.Write([]byte(`{"title":"比赛未开始","status":400}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"title":"Game has not started","status":400}`))
+		},
+		"/api/huge": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(strings.Repeat("x", 3*maxErrorBody)))
+		},
+		"/assets/missing": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"title":"File not found","status":404}`))
+		},
+	})
+	defer server.Close()
+	defer SetAcceptLanguage("")
+	api := &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}
+
+	err := api.get("/api/game/1", nil)
+	if err == nil || err.Error() != "request end with 400 status, Game has not started" {
+		t.Errorf("error = %v, want the server message", err)
+	}
+
+	SetAcceptLanguage("zh-CN")
+	var apiErr *APIError
+	if err := api.get("/api/game/1", nil); !errors.As(err, &apiErr) || apiErr.Message() != "比赛未开始" {
+		t.Errorf("error = %v, want the localized server message", err)
+	}
+
+	if err := api.get("/api/huge", nil); !errors.As(err, &apiErr) || len(apiErr.Body) > maxErrorBody+len("... (truncated)") || !strings.HasSuffix(apiErr.Body, "(truncated)") {
+		t.Errorf("body of %d bytes was not truncated", len(apiErr.Body))
+	}
+
+	dest := filepath.Join(t.TempDir(), "file.zip")
+	err = api.DownloadFile("/assets/missing", dest)
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "File not found") {
+		t.Errorf("DownloadFile() error = %v, want the server message", err)
+	}
+	if _, statErr := os.Stat(dest); !os.IsNotExist(statErr) {
+		t.Error("the error page of a failed download should not be saved")
+	}
+}
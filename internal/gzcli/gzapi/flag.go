@@ -33,12 +33,34 @@ func (c *Challenge) CreateFlags(flags []CreateFlagForm) error {
 	if len(flags) == 0 {
 		return nil
 	}
-	if err := c.CS.post(fmt.Sprintf("/api/edit/games/%d/challenges/%d/flags", c.GameId, c.Id), flags, nil); err != nil {
+	url := fmt.Sprintf("/api/edit/games/%d/challenges/%d/flags", c.GameId, c.Id)
+	if err := c.CS.postIdempotent(url, flags, nil, c.hasFlags(flags)); err != nil {
 		return err
 	}
 	return nil
 }
 
+// hasFlags returns a check of whether the platform already has all of the
+// flags of the challenge, e.g. after a timed out CreateFlags
+func (c *Challenge) hasFlags(flags []CreateFlagForm) func() (bool, error) {
+	return func() (bool, error) {
+		var current Challenge
+		if err := c.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges/%d", c.GameId, c.Id), &current); err != nil {
+			return false, err
+		}
+		existing := make(map[string]bool, len(current.Flags))
+		for _, f := range current.Flags {
+			existing[f.Flag] = true
+		}
+		for _, f := range flags {
+			if !existing[f.Flag] {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
 // GetFlags returns all flags for the challenge with proper metadata
 func (c *Challenge) GetFlags() []Flag {
 	for i := range c.Flags {
@@ -99,7 +99,7 @@ func (cs *GZAPI) GetGameByTitle(title string) (*Game, error) {
 			return game, nil
 		}
 	}
-	return nil, fmt.Errorf("game not found")
+	return nil, fmt.Errorf("game %w", ErrNotFound)
 }
 
 // Delete removes the game from the platform
@@ -122,6 +122,33 @@ func (g *Game) Update(game *Game) error {
 	return g.CS.put(fmt.Sprintf("/api/edit/games/%d", g.Id), &gameCopy, nil)
 }
 
+// WriteupSettings are the writeup requirements of a game
+type WriteupSettings struct {
+	Required bool
+	Deadline time.Time // Zero for no deadline
+	Note     string    // Instructions shown to teams
+}
+
+// UpdateWriteupSettings sets the writeup requirements of the game, keeping
+// its other settings as they are on the platform
+func (g *Game) UpdateWriteupSettings(settings WriteupSettings) error {
+	current, err := g.CS.GetGameById(g.Id)
+	if err != nil {
+		return err
+	}
+	current.WriteupRequired = settings.Required
+	current.WriteupDeadline = CustomTime{Time: settings.Deadline}
+	current.WriteupNote = settings.Note
+	if err := g.Update(current); err != nil {
+		return err
+	}
+
+	g.WriteupRequired = current.WriteupRequired
+	g.WriteupDeadline = current.WriteupDeadline
+	g.WriteupNote = current.WriteupNote
+	return nil
+}
+
 // UploadPoster uploads a poster image for the game
 func (g *Game) UploadPoster(poster string) (string, error) {
 	var path string
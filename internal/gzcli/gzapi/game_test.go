@@ -225,6 +225,40 @@ func TestGame_Update(t *testing.T) {
 	}
 }
 
+func TestGame_UpdateWriteupSettings(t *testing.T) {
+	deadline := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	var updated Game
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/5": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				_, _ = w.Write([]byte(`{"id":5,"title":"Game","summary":"Kept","start":1700000000000,"end":1700086400000}`))
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			w.WriteHeader(http.StatusOK)
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 5, CS: api}
+
+	if err := game.UpdateWriteupSettings(WriteupSettings{Required: true, Deadline: deadline, Note: "PDF only"}); err != nil {
+		t.Fatalf("UpdateWriteupSettings() failed: %v", err)
+	}
+	if updated.Summary != "Kept" || !updated.WriteupRequired || !updated.WriteupDeadline.Equal(deadline) || updated.WriteupNote != "PDF only" {
+		t.Errorf("updated game = %+v, want the writeup settings on the current game", updated)
+	}
+	if !game.WriteupRequired || game.WriteupNote != "PDF only" {
+		t.Errorf("game = %+v, want its writeup settings updated", game)
+	}
+}
+
 func TestGame_UploadPoster(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "poster-*.png")
 	if err != nil {
@@ -459,4 +493,62 @@ func TestGame_GetScoreboard(t *testing.T) {
 	}
 }
 
+func TestGame_GetScoreboardPage(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/game/1/scoreboard": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{
+				"updateTimeUtc": "2025-01-01T12:00:00Z",
+				"bloodBonus": 3276850,
+				"challenges": {"Web": [{"id": 7, "title": "Login", "category": "Web", "score": 500, "solved": 2,
+					"bloods": [{"id": 1, "name": "Team 1", "submitTimeUtc": "2025-01-01T10:00:00Z"}]}]},
+				"challengeCount": 1,
+				"items": [
+					{"id": 1, "name": "Team 1", "rank": 1, "score": 500, "solvedCount": 1,
+						"solvedChallenges": [{"id": 7, "score": 500, "type": "FirstBlood", "userName": "alice", "time": 1735725600000}]},
+					{"id": 2, "name": "Team 2", "rank": 2, "score": 450, "solvedCount": 1},
+					{"id": 3, "name": "Team 3", "rank": 3, "score": 0}
+				]
+			}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	page, err := game.GetScoreboardPage(1, 1)
+	if err != nil {
+		t.Fatalf("GetScoreboardPage() failed: %v", err)
+	}
+	if page.Total != 3 || page.Skip != 1 || len(page.Items) != 1 || page.Items[0].Name != "Team 2" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	page, err = game.GetScoreboardPage(2, 0)
+	if err != nil || len(page.Items) != 1 || page.Items[0].Id != 3 {
+		t.Errorf("GetScoreboardPage(2, 0) = %+v, %v", page, err)
+	}
+	page, err = game.GetScoreboardPage(10, 5)
+	if err != nil || len(page.Items) != 0 || page.Skip != 3 {
+		t.Errorf("GetScoreboardPage(10, 5) = %+v, %v", page, err)
+	}
+
+	scoreboard, err := game.GetScoreboard()
+	if err != nil {
+		t.Fatalf("GetScoreboard() failed: %v", err)
+	}
+	solve := scoreboard.Items[0].SolvedChallenges[0]
+	if solve.Type != BloodFirst || solve.UserName != "alice" || solve.Time.Unix() != 1735725600 {
+		t.Errorf("unexpected solve: %+v", solve)
+	}
+	challenge, ok := scoreboard.Challenge(7)
+	if !ok || len(challenge.Bloods) != 1 || challenge.Bloods[0].Name != "Team 1" {
+		t.Errorf("Challenge(7) = %+v, %v", challenge, ok)
+	}
+}
+
 // Helper functions are in common_test.go
@@ -3,15 +3,18 @@ package gzapi
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/cookiejar"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/imroc/req/v3"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/dimasma0305/gzcli/internal/log"
 )
@@ -49,6 +52,9 @@ func init() {
 type Creds struct {
 	Username string `json:"username" yaml:"username"`
 	Password string `json:"password" yaml:"password"`
+	// Token is an API token sent as a bearer token instead of logging in;
+	// never part of the login request
+	Token string `json:"-" yaml:"token,omitempty"`
 }
 
 type GZAPI struct {
@@ -59,6 +65,18 @@ type GZAPI struct {
 	cookieJar *cookiejar.Jar
 	// cookieStore persists cookies between CLI invocations.
 	cookieStore *cookieStore
+	// tusOnce guards the probe for resumable upload support.
+	tusOnce      sync.Once
+	tusSupported bool
+	// timeouts overrides the process-wide timeouts for this client; see WithTimeouts
+	timeouts *Timeouts
+	// token is the API token requests are sent with until it is rejected
+	token  string
+	authMu sync.Mutex
+	// session counts the logins after a 401, so requests rejected by a
+	// session that was already replaced just retry; see refreshSession
+	session uint64
+	relogin singleflight.Group
 }
 
 func Init(url string, creds *Creds) (*GZAPI, error) {
@@ -91,8 +109,10 @@ func Init(url string, creds *Creds) (*GZAPI, error) {
 		Creds:       creds,
 		cookieJar:   jar,
 		cookieStore: cookies,
+		token:       credsToken(creds),
 	}
-	if !hasCachedCookies {
+	// API tokens need no login; cookies are only used once a token is rejected
+	if newGz.token == "" && !hasCachedCookies {
 		if err := newGz.Login(); err != nil {
 			return nil, err
 		}
@@ -156,8 +176,8 @@ func createOptimizedClient(jar *cookiejar.Jar) *req.Client {
 	client := req.C().
 		SetUserAgent("Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/110.0").
 		SetTLSClientConfig(tlsConfig).
-		SetTimeout(30 * time.Second). // Default timeout for most operations
-		EnableKeepAlives()            // Enable connection keep-alive (auto-negotiates HTTP/2 for HTTPS)
+		SetDial(dialContext). // Connect timeout; request timeouts are set per call by operation class
+		EnableKeepAlives()    // Enable connection keep-alive (auto-negotiates HTTP/2 for HTTPS)
 
 	// Configure transport for optimal connection pooling
 	transport := client.GetTransport()
@@ -165,6 +185,9 @@ func createOptimizedClient(jar *cookiejar.Jar) *req.Client {
 		transport.SetMaxIdleConns(100). // Increase connection pool
 						SetIdleConnTimeout(90 * time.Second). // Keep connections alive longer
 						SetMaxConnsPerHost(10)                // Max connections per host
+		if d := GetTimeouts().Connect; d > 0 {
+			transport.SetTLSHandshakeTimeout(d)
+		}
 	}
 
 	if jar != nil {
@@ -177,8 +200,9 @@ func createOptimizedClient(jar *cookiejar.Jar) *req.Client {
 // requestExecutor is a function that executes an HTTP request
 type requestExecutor func(*req.Request, string) (*req.Response, error)
 
-// doRequest handles common HTTP request logic
-func (cs *GZAPI) doRequest(method, url string, data any, executor requestExecutor) error {
+// doRequest handles common HTTP request logic. Each attempt is bounded by
+// the timeout of its operation class.
+func (cs *GZAPI) doRequest(method, url string, class opClass, data any, executor requestExecutor) error {
 	if cs == nil || cs.Client == nil {
 		return fmt.Errorf("GZAPI client is not initialized")
 	}
@@ -191,27 +215,38 @@ func (cs *GZAPI) doRequest(method, url string, data any, executor requestExecuto
 	fullURL := urlBuilder.String()
 
 	// Execute the request
-	resp, err := executor(cs.Client.R(), fullURL)
+	limiter.wait(fullURL)
+	ctx, cancel := cs.requestContext(class)
+	defer cancel()
+	session := cs.sessionID()
+	resp, err := executor(cs.newRequest(ctx), fullURL)
 	if err != nil {
+		recordRequest(method, 0, err)
 		log.Error("%s request failed for %s: %v", method, fullURL, err)
 		return fmt.Errorf("%s request failed for %s: %w", method, fullURL, err)
 	}
 
 	if resp.StatusCode == http.StatusUnauthorized && url != "/api/account/login" && cs.Creds != nil {
-		if err := cs.Login(); err != nil {
-			return fmt.Errorf("authentication failed after 401 for %s: %w", fullURL, err)
+		if err := cs.refreshSession(session); err != nil {
+			return fmt.Errorf("authentication failed after 401 for %s: %w: %w", fullURL, ErrUnauthorized, err)
 		}
-		resp, err = executor(cs.Client.R(), fullURL)
+		limiter.wait(fullURL)
+		retryCtx, retryCancel := cs.requestContext(class)
+		defer retryCancel()
+		resp, err = executor(cs.newRequest(retryCtx), fullURL)
 		if err != nil {
+			recordRequest(method, 0, err)
 			log.Error("%s retry failed for %s: %v", method, fullURL, err)
 			return fmt.Errorf("%s retry failed for %s: %w", method, fullURL, err)
 		}
 	}
 
 	// Validate status code
+	recordRequest(method, resp.StatusCode, nil)
 	if resp.StatusCode != 200 {
-		log.Error("%s request returned status %d for %s: %s", method, resp.StatusCode, fullURL, resp.String())
-		return fmt.Errorf("request end with %d status, %s", resp.StatusCode, resp.String())
+		apiErr := newAPIError(method, url, resp.StatusCode, resp.Bytes())
+		log.Error("%s request returned status %d for %s: %s", method, resp.StatusCode, fullURL, apiErr.Message())
+		return apiErr
 	}
 
 	// Unmarshal response if data pointer provided
@@ -221,6 +256,9 @@ func (cs *GZAPI) doRequest(method, url string, data any, executor requestExecuto
 				log.Error("Failed to unmarshal JSON response from %s: %v", fullURL, err)
 				return fmt.Errorf("error unmarshal json: %w, %s", err, resp.String())
 			}
+			if schemaCheck.Load() {
+				recordSchemaDrift(method, url, resp.Bytes(), data)
+			}
 		}
 	}
 
@@ -228,25 +266,25 @@ func (cs *GZAPI) doRequest(method, url string, data any, executor requestExecuto
 }
 
 func (cs *GZAPI) get(url string, data any) error {
-	return cs.doRequest("GET", url, data, func(r *req.Request, url string) (*req.Response, error) {
+	return cs.doRequest("GET", url, opRequest, data, func(r *req.Request, url string) (*req.Response, error) {
 		return r.Get(url)
 	})
 }
 
 func (cs *GZAPI) delete(url string, data any) error {
-	return cs.doRequest("DELETE", url, data, func(r *req.Request, url string) (*req.Response, error) {
+	return cs.doRequest("DELETE", url, opRequest, data, func(r *req.Request, url string) (*req.Response, error) {
 		return r.Delete(url)
 	})
 }
 
 func (cs *GZAPI) post(url string, json any, data any) error {
-	return cs.doRequest("POST", url, data, func(r *req.Request, url string) (*req.Response, error) {
+	return cs.doRequest("POST", url, opRequest, data, func(r *req.Request, url string) (*req.Response, error) {
 		return r.SetBodyJsonMarshal(json).Post(url)
 	})
 }
 
 func (cs *GZAPI) put(url string, json any, data any) error {
-	return cs.doRequest("PUT", url, data, func(r *req.Request, url string) (*req.Response, error) {
+	return cs.doRequest("PUT", url, opRequest, data, func(r *req.Request, url string) (*req.Response, error) {
 		return r.SetBodyJsonMarshal(json).Put(url)
 	})
 }
@@ -259,8 +297,8 @@ func (cs *GZAPI) postMultiPart(url string, file string, data any) error {
 	}
 
 	// Use "files" for /api/assets endpoint as per API specification
-	return cs.doRequest("POST", url, data, func(r *req.Request, url string) (*req.Response, error) {
-		return r.SetFile("files", file).Post(url)
+	return cs.doRequest("POST", url, opUpload, data, func(r *req.Request, url string) (*req.Response, error) {
+		return withUploadProgress(r.SetFile("files", file), file).Post(url)
 	})
 }
 
@@ -272,11 +310,28 @@ func (cs *GZAPI) putMultiPart(url string, file string, data any) error {
 	}
 
 	// Use "file" for PUT operations (poster/avatar uploads) as per API specification
-	return cs.doRequest("PUT", url, data, func(r *req.Request, url string) (*req.Response, error) {
-		return r.SetFile("file", file).Put(url)
+	return cs.doRequest("PUT", url, opUpload, data, func(r *req.Request, url string) (*req.Response, error) {
+		return withUploadProgress(r.SetFile("file", file), file).Put(url)
 	})
 }
 
+// DownloadFile saves the file served at url, relative to the server, to dest.
+// The error page of a failed download is returned in the error, not saved.
+func (cs *GZAPI) DownloadFile(url, dest string) error {
+	err := cs.doRequest("GET", url, opDownload, nil, func(r *req.Request, url string) (*req.Response, error) {
+		return r.SetOutputFile(dest).Get(url)
+	})
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		//nolint:gosec // G304: dest is the download destination chosen by the caller
+		if body, readErr := os.ReadFile(dest); readErr == nil && apiErr.Body == "" {
+			apiErr.Body = truncateErrorBody(body)
+		}
+		_ = os.Remove(dest)
+	}
+	return err
+}
+
 // persistCookies writes the current session cookies to the shared cache.
 func (cs *GZAPI) persistCookies() {
 	if cs == nil || cs.cookieStore == nil || cs.cookieJar == nil {
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -633,6 +636,83 @@ func TestGZAPI_Logout(t *testing.T) {
 	}
 }
 
+func TestGZAPI_LogoutAll(t *testing.T) {
+	originalWD, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to switch working directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalWD) })
+
+	password := "old"
+	var logins []string
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/account/login": func(w http.ResponseWriter, r *http.Request) {
+			var creds Creds
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			logins = append(logins, creds.Password)
+			if creds.Password != password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: creds.Password, Path: "/", Expires: time.Now().Add(time.Hour)})
+			w.Write([]byte(`{"succeeded": true}`))
+		},
+		"/api/account/changepassword": func(w http.ResponseWriter, r *http.Request) {
+			var form PasswordChangeForm
+			_ = json.NewDecoder(r.Body).Decode(&form)
+			if r.Method != "PUT" || form.Old != password {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			password = form.New
+			w.WriteHeader(http.StatusOK)
+		},
+		"/api/account/profile": func(w http.ResponseWriter, r *http.Request) {
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"userId": "1", "userName": "admin", "email": "admin@localhost"}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "admin", Password: "old"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if err := api.ChangePassword(""); err == nil {
+		t.Error("ChangePassword(\"\") succeeded, want an error")
+	}
+	if err := api.LogoutAll("new"); err != nil {
+		t.Fatalf("LogoutAll() failed: %v", err)
+	}
+	if api.Creds.Password != "new" {
+		t.Errorf("stored password = %q, want new", api.Creds.Password)
+	}
+
+	profile, err := api.Profile()
+	if err != nil {
+		t.Fatalf("Profile() with the new session failed: %v", err)
+	}
+	if profile.UserName != "admin" {
+		t.Errorf("profile user = %q, want admin", profile.UserName)
+	}
+
+	// The refreshed cookie cache is reused without logging in again
+	cached, err := Init(server.URL, &Creds{Username: "admin", Password: "new"})
+	if err != nil {
+		t.Fatalf("Init() with refreshed cache failed: %v", err)
+	}
+	if _, err := cached.Profile(); err != nil {
+		t.Errorf("Profile() with cached cookies failed: %v", err)
+	}
+	if want := []string{"old", "new"}; len(logins) != len(want) || logins[0] != want[0] || logins[1] != want[1] {
+		t.Errorf("logins = %v, want %v", logins, want)
+	}
+}
+
 // Test Register error case
 func TestRegister_Failure(t *testing.T) {
 	server := mockServer(t, map[string]http.HandlerFunc{
@@ -886,6 +966,115 @@ func TestGame_GetChallenges(t *testing.T) {
 	}
 }
 
+func TestGame_GetChallengesSelective(t *testing.T) {
+	var detailRequests atomic.Int32
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode([]Challenge{
+				{Id: 1, Title: "Challenge 1"},
+				{Id: 2, Title: "Challenge 2"},
+				{Id: 3, Title: "Challenge 3"},
+			})
+		},
+		"/api/edit/games/1/challenges/2": func(w http.ResponseWriter, r *http.Request) {
+			detailRequests.Add(1)
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(Challenge{Id: 2, Title: "Challenge 2", Content: "Full content 2"})
+		},
+		"/api/edit/games/1/challenges/": func(w http.ResponseWriter, r *http.Request) {
+			detailRequests.Add(1)
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(Challenge{})
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	challenges, err := game.GetChallengesSelective(func(c Challenge) bool { return c.Title == "Challenge 2" })
+	if err != nil {
+		t.Fatalf("GetChallengesSelective() failed: %v", err)
+	}
+	if len(challenges) != 3 {
+		t.Fatalf("Expected 3 challenges, got %d", len(challenges))
+	}
+	if challenges[1].Content != "Full content 2" || challenges[0].Content != "" {
+		t.Errorf("Expected details only for the selected challenge, got %+v", challenges)
+	}
+	if n := detailRequests.Load(); n != 1 {
+		t.Errorf("Expected 1 detail request, got %d", n)
+	}
+	for _, c := range challenges {
+		if c.CS == nil || c.GameId != 1 {
+			t.Errorf("Expected CS and GameId to be set for %s", c.Title)
+		}
+	}
+
+	list, err := game.ListChallenges()
+	if err != nil || len(list) != 3 {
+		t.Fatalf("ListChallenges() = %d challenges, %v", len(list), err)
+	}
+	if n := detailRequests.Load(); n != 1 {
+		t.Errorf("ListChallenges() should not fetch details, got %d detail requests", n)
+	}
+}
+
+func TestGame_BulkUpdate(t *testing.T) {
+	var mu sync.Mutex
+	updated := map[string]string{}
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges/": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "PUT" {
+				t.Errorf("Expected PUT method, got %s", r.Method)
+			}
+			if r.URL.Path == "/api/edit/games/1/challenges/3" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var c Challenge
+			_ = json.NewDecoder(r.Body).Decode(&c)
+			mu.Lock()
+			updated[r.URL.Path] = c.Title
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	result, err := game.BulkUpdate([]Challenge{
+		{Id: 1, Title: "One"},
+		{Id: 2, Title: "Two"},
+		{Id: 3, Title: "Three"},
+		{Title: "No ID"},
+	})
+	if err == nil {
+		t.Fatal("Expected error for the failed and invalid updates")
+	}
+	if !strings.Contains(err.Error(), "id 3") || !strings.Contains(err.Error(), "missing id") {
+		t.Errorf("Expected both failures to be reported, got %v", err)
+	}
+	if len(result) != 2 || result[0].Title != "One" || result[1].Title != "Two" {
+		t.Errorf("Expected the two successful updates in order, got %+v", result)
+	}
+	if updated["/api/edit/games/1/challenges/1"] != "One" || updated["/api/edit/games/1/challenges/2"] != "Two" {
+		t.Errorf("Unexpected updates sent: %v", updated)
+	}
+	if result[0].CS == nil || result[0].GameId != 1 {
+		t.Error("Expected CS and GameId to be set on updated challenges")
+	}
+}
+
 func TestGame_GetChallenge(t *testing.T) {
 	server := mockServer(t, map[string]http.HandlerFunc{
 		"/api/edit/games/1/challenges": func(w http.ResponseWriter, r *http.Request) {
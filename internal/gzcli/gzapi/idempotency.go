@@ -0,0 +1,85 @@
+package gzapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/imroc/req/v3"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// IdempotencyHeader carries the client-generated key of a mutating request.
+// Every attempt of the same request sends the same key, so a platform that
+// honours it applies the request once.
+const IdempotencyHeader = "Idempotency-Key"
+
+// maxMutationAttempts bounds the attempts of an idempotent POST
+const maxMutationAttempts = 3
+
+// mutationRetryDelay is the delay before the first retry of an idempotent
+// POST, doubled on each further retry. A variable so tests can shorten it.
+var mutationRetryDelay = time.Second
+
+// newIdempotencyKey returns a random key for a mutating request
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isTransient reports whether a request failed without a definite answer
+// from the platform: a network error, a timeout or a gateway error. The
+// request may or may not have been applied.
+func isTransient(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case 0:
+	default:
+		return false
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
+
+// postIdempotent POSTs json like post, with an idempotency key kept across
+// attempts, and retries it after transient failures. Before each retry,
+// applied reports whether the failed attempt created the resource anyway,
+// for platforms ignoring the key; if so, the POST is done and applied is
+// responsible for filling data. Without applied, the POST is not retried.
+func (cs *GZAPI) postIdempotent(url string, json any, data any, applied func() (bool, error)) error {
+	key := newIdempotencyKey()
+	executor := func(r *req.Request, url string) (*req.Response, error) {
+		return r.SetHeader(IdempotencyHeader, key).SetBodyJsonMarshal(json).Post(url)
+	}
+
+	delay := mutationRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := cs.doRequest("POST", url, opRequest, data, executor)
+		if err == nil || applied == nil || attempt == maxMutationAttempts || !isTransient(err) {
+			return err
+		}
+
+		done, checkErr := applied()
+		if checkErr != nil {
+			return fmt.Errorf("%w (checking whether it was applied: %v)", err, checkErr)
+		}
+		if done {
+			log.Info("POST %s was applied despite %v", url, err)
+			return nil
+		}
+		log.Info("Retrying POST %s (attempt %d/%d) after %v", url, attempt+1, maxMutationAttempts, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
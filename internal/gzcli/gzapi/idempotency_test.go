@@ -0,0 +1,144 @@
+package gzapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateChallenge_TimedOutPostIsNotDuplicated(t *testing.T) {
+	mutationRetryDelay = 0
+	t.Cleanup(func() { mutationRetryDelay = time.Second })
+
+	var mu sync.Mutex
+	var posts int
+	var keys []string
+	created := false
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			if r.Method == http.MethodPost {
+				posts++
+				keys = append(keys, r.Header.Get(IdempotencyHeader))
+				created = true
+				mu.Unlock()
+				// Applied, but answered too late
+				time.Sleep(200 * time.Millisecond)
+				_, _ = w.Write([]byte(`{"id":7,"title":"web"}`))
+				return
+			}
+			defer mu.Unlock()
+			if created {
+				_, _ = w.Write([]byte(`[{"id":7,"title":"web"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		},
+		"/api/edit/games/1/challenges/7": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":7,"title":"web","category":"Web"}`))
+		},
+	})
+	t.Cleanup(server.Close)
+
+	api := (&GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}).WithTimeouts(Timeouts{Request: 50 * time.Millisecond})
+	game := &Game{Id: 1, CS: api}
+	challenge, err := game.CreateChallenge(CreateChallengeForm{Title: "web", Category: "Web"})
+	if err != nil {
+		t.Fatalf("CreateChallenge() error = %v", err)
+	}
+	if challenge.Id != 7 || challenge.Category != "Web" {
+		t.Errorf("CreateChallenge() = %+v, want the created challenge", challenge)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if posts != 1 {
+		t.Errorf("got %d POSTs, want 1", posts)
+	}
+	if len(keys) != 1 || len(keys[0]) != 32 {
+		t.Errorf("unexpected idempotency keys %q", keys)
+	}
+}
+
+func TestCreateFlags_RetriesWithSameKey(t *testing.T) {
+	mutationRetryDelay = 0
+	t.Cleanup(func() { mutationRetryDelay = time.Second })
+
+	var mu sync.Mutex
+	var keys []string
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges/7/flags": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			keys = append(keys, r.Header.Get(IdempotencyHeader))
+			if len(keys) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		},
+		"/api/edit/games/1/challenges/7": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":7,"flags":[]}`))
+		},
+	})
+	t.Cleanup(server.Close)
+
+	c := &Challenge{Id: 7, GameId: 1, CS: &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}}
+	if err := c.CreateFlags([]CreateFlagForm{{Flag: "flag{a}"}}); err != nil {
+		t.Fatalf("CreateFlags() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("got keys %q, want two attempts with the same key", keys)
+	}
+}
+
+func TestCreateFlags_NotRetriedOnClientError(t *testing.T) {
+	var posts int
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/challenges/7/flags": func(w http.ResponseWriter, _ *http.Request) {
+			posts++
+			w.WriteHeader(http.StatusBadRequest)
+		},
+	})
+	t.Cleanup(server.Close)
+
+	c := &Challenge{Id: 7, GameId: 1, CS: &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}}
+	if err := c.CreateFlags([]CreateFlagForm{{Flag: "flag{a}"}}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if posts != 1 {
+		t.Errorf("got %d POSTs, want 1", posts)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&APIError{StatusCode: http.StatusBadGateway}, true},
+		{&APIError{StatusCode: http.StatusGatewayTimeout}, true},
+		{&APIError{StatusCode: http.StatusConflict}, false},
+		{&APIError{StatusCode: http.StatusInternalServerError}, false},
+		{fmt.Errorf("POST request failed: %w", &timeoutError{}), true},
+		{fmt.Errorf("authentication failed: %w", ErrUnauthorized), false},
+		{errors.New("error unmarshal json"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.want {
+			t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (*timeoutError) Error() string   { return "i/o timeout" }
+func (*timeoutError) Timeout() bool   { return true }
+func (*timeoutError) Temporary() bool { return true }
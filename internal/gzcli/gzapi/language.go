@@ -0,0 +1,40 @@
+package gzapi
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/imroc/req/v3"
+)
+
+// acceptLanguage is the Accept-Language header sent with every API request,
+// so GZCTF answers with error messages in that language. Empty leaves the
+// header out and the server uses its default language.
+var acceptLanguage atomic.Value
+
+func init() {
+	SetAcceptLanguage(os.Getenv("GZCLI_ACCEPT_LANGUAGE"))
+}
+
+// SetAcceptLanguage sets the Accept-Language of the API requests of every
+// client of the process, e.g. "zh-CN" or "en-US,en;q=0.9". Empty restores the
+// server default.
+func SetAcceptLanguage(lang string) {
+	acceptLanguage.Store(strings.TrimSpace(lang))
+}
+
+// AcceptLanguage returns the Accept-Language sent with API requests, "" when
+// none is
+func AcceptLanguage() string {
+	lang, _ := acceptLanguage.Load().(string)
+	return lang
+}
+
+// withLanguage sets the configured Accept-Language on r
+func withLanguage(r *req.Request) *req.Request {
+	if lang := AcceptLanguage(); lang != "" {
+		r.SetHeader("Accept-Language", lang)
+	}
+	return r
+}
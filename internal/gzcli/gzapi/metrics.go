@@ -0,0 +1,71 @@
+package gzapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/metrics"
+)
+
+// Results of API requests counted by the metrics
+const (
+	resultSuccess      = "success"
+	resultClientError  = "client_error"
+	resultServerError  = "server_error"
+	resultNetworkError = "network_error"
+)
+
+// requestCounts counts the API requests of the process by method and result
+var requestCounts = struct {
+	mu     sync.Mutex
+	counts map[[2]string]uint64 // {method, result} -> count
+}{counts: make(map[[2]string]uint64)}
+
+func init() {
+	metrics.Default.Register("gzapi", metrics.CollectorFunc(writeMetrics))
+}
+
+// recordRequest counts a finished request from its status, or err when no
+// response was received
+func recordRequest(method string, status int, err error) {
+	result := resultSuccess
+	switch {
+	case err != nil:
+		result = resultNetworkError
+	case status >= http.StatusInternalServerError:
+		result = resultServerError
+	case status != http.StatusOK:
+		result = resultClientError
+	}
+	requestCounts.mu.Lock()
+	requestCounts.counts[[2]string{method, result}]++
+	requestCounts.mu.Unlock()
+}
+
+// writeMetrics writes the request counts in the Prometheus text format
+func writeMetrics(w io.Writer) error {
+	requestCounts.mu.Lock()
+	keys := make([][2]string, 0, len(requestCounts.counts))
+	for key := range requestCounts.counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	var b strings.Builder
+	metrics.WriteHeader(&b, "gzcli_api_requests_total", "counter", "GZCTF API requests by method and result.")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "gzcli_api_requests_total{method=%q,result=%q} %d\n", key[0], key[1], requestCounts.counts[key])
+	}
+	requestCounts.mu.Unlock()
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,40 @@
+package gzapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecordRequest(t *testing.T) {
+	requestCounts.mu.Lock()
+	orig := requestCounts.counts
+	requestCounts.counts = make(map[[2]string]uint64)
+	requestCounts.mu.Unlock()
+	t.Cleanup(func() {
+		requestCounts.mu.Lock()
+		requestCounts.counts = orig
+		requestCounts.mu.Unlock()
+	})
+
+	recordRequest("GET", 200, nil)
+	recordRequest("GET", 200, nil)
+	recordRequest("PUT", 404, nil)
+	recordRequest("PUT", 502, nil)
+	recordRequest("POST", 0, errors.New("connection refused"))
+
+	var b strings.Builder
+	if err := writeMetrics(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`gzcli_api_requests_total{method="GET",result="success"} 2`,
+		`gzcli_api_requests_total{method="PUT",result="client_error"} 1`,
+		`gzcli_api_requests_total{method="PUT",result="server_error"} 1`,
+		`gzcli_api_requests_total{method="POST",result="network_error"} 1`,
+	} {
+		if !strings.Contains(b.String(), want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gzapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notice types of a game. Only NoticeNormal notices are written by
+// organizers; the others are published by GZCTF itself.
+const (
+	NoticeNormal       = "Normal"
+	NoticeFirstBlood   = "FirstBlood"
+	NoticeSecondBlood  = "SecondBlood"
+	NoticeThirdBlood   = "ThirdBlood"
+	NoticeNewHint      = "NewHint"
+	NoticeNewChallenge = "NewChallenge"
+)
+
+// GameNotice is a notice shown to the participants of a game
+//
+//nolint:revive // Field names match API responses
+type GameNotice struct {
+	Id     int        `json:"id"`
+	Type   string     `json:"type"`   // One of the Notice* constants
+	Values []string   `json:"values"` // Content of a normal notice, or the team and challenge of the others
+	Time   CustomTime `json:"time"`
+}
+
+// Content returns the text of a normal notice, or the values of the others
+// joined by ", "
+func (n GameNotice) Content() string {
+	return strings.Join(n.Values, ", ")
+}
+
+// GameNoticeForm contains the content of a notice written by organizers
+type GameNoticeForm struct {
+	Content string `json:"content"`
+}
+
+// GetNotices retrieves the notices of the game, those published by GZCTF
+// included
+func (g *Game) GetNotices() ([]GameNotice, error) {
+	var notices []GameNotice
+	if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/notices", g.Id), &notices); err != nil {
+		return nil, err
+	}
+	return notices, nil
+}
+
+// CreateNotice publishes a notice to the participants of the game
+func (g *Game) CreateNotice(content string) (*GameNotice, error) {
+	var notice GameNotice
+	if err := g.CS.post(fmt.Sprintf("/api/edit/games/%d/notices", g.Id), &GameNoticeForm{Content: content}, &notice); err != nil {
+		return nil, err
+	}
+	return &notice, nil
+}
+
+// UpdateNotice replaces the content of a notice. Only normal notices can be
+// updated.
+//
+//nolint:revive // Parameter name matches API specification
+func (g *Game) UpdateNotice(noticeId int, content string) (*GameNotice, error) {
+	var notice GameNotice
+	if err := g.CS.put(fmt.Sprintf("/api/edit/games/%d/notices/%d", g.Id, noticeId), &GameNoticeForm{Content: content}, &notice); err != nil {
+		return nil, err
+	}
+	return &notice, nil
+}
+
+// DeleteNotice removes a notice of the game
+//
+//nolint:revive // Parameter name matches API specification
+func (g *Game) DeleteNotice(noticeId int) error {
+	return g.CS.delete(fmt.Sprintf("/api/edit/games/%d/notices/%d", g.Id, noticeId), nil)
+}
@@ -0,0 +1,63 @@
+package gzapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGame_Notices(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1/notices": func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				_, _ = w.Write([]byte(`[{"id":1,"type":"FirstBlood","values":["alpha","Login"],"time":1700000000000},{"id":2,"type":"Normal","values":["Welcome"],"time":1700000100000}]`))
+			case "POST":
+				var form GameNoticeForm
+				if err := json.NewDecoder(r.Body).Decode(&form); err != nil || form.Content != "Hint: look at the cookies" {
+					t.Errorf("unexpected notice form %+v (%v)", form, err)
+				}
+				_, _ = w.Write([]byte(`{"id":3,"type":"Normal","values":["Hint: look at the cookies"],"time":1700000200000}`))
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		},
+		"/api/edit/games/1/notices/3": func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "PUT":
+				_, _ = w.Write([]byte(`{"id":3,"type":"Normal","values":["Hint: look at the session cookie"],"time":1700000200000}`))
+			case "DELETE":
+				w.WriteHeader(http.StatusOK)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "notice", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	notices, err := game.GetNotices()
+	if err != nil {
+		t.Fatalf("GetNotices() failed: %v", err)
+	}
+	if len(notices) != 2 || notices[0].Type != NoticeFirstBlood || notices[0].Content() != "alpha, Login" || notices[0].Time.IsZero() {
+		t.Errorf("unexpected notices: %+v", notices)
+	}
+
+	notice, err := game.CreateNotice("Hint: look at the cookies")
+	if err != nil || notice.Id != 3 {
+		t.Fatalf("CreateNotice() = %+v, %v", notice, err)
+	}
+	notice, err = game.UpdateNotice(3, "Hint: look at the session cookie")
+	if err != nil || notice.Content() != "Hint: look at the session cookie" {
+		t.Fatalf("UpdateNotice() = %+v, %v", notice, err)
+	}
+	if err := game.DeleteNotice(3); err != nil {
+		t.Errorf("DeleteNotice() failed: %v", err)
+	}
+}
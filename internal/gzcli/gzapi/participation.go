@@ -0,0 +1,80 @@
+package gzapi
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Participation is a team registered to a game, with its division
+//
+//nolint:revive // Field names match API responses
+type Participation struct {
+	Id       int    `json:"id"`
+	Team     Team   `json:"team"`
+	Division string `json:"division,omitempty"`
+	Status   string `json:"status"`
+	CS       *GZAPI `json:"-"`
+}
+
+// ParticipationEditModel is the data of a participation update
+type ParticipationEditModel struct {
+	Status   string `json:"status,omitempty"`
+	Division string `json:"division,omitempty"`
+}
+
+// Participations retrieves the teams registered to the game
+func (g *Game) Participations() ([]*Participation, error) {
+	var participations []*Participation
+	if err := g.CS.get(fmt.Sprintf("/api/game/%d/participations", g.Id), &participations); err != nil {
+		return nil, err
+	}
+	for _, p := range participations {
+		p.CS = g.CS
+	}
+	return participations, nil
+}
+
+// GetParticipation retrieves the participation of a team in the game, or
+// returns ErrNotFound when the team has not joined it
+//
+//nolint:revive // Parameter name matches API specification
+func (g *Game) GetParticipation(teamId int) (*Participation, error) {
+	participations, err := g.Participations()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participations {
+		if p.Team.Id == teamId {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("participation of team %d %w", teamId, ErrNotFound)
+}
+
+// SetDivision moves the participation to another division of its game
+func (p *Participation) SetDivision(division string) error {
+	if err := p.CS.put(fmt.Sprintf("/api/admin/participation/%d", p.Id), &ParticipationEditModel{Division: division}, nil); err != nil {
+		return err
+	}
+	p.Division = division
+	return nil
+}
+
+// EnsureDivision adds division to the divisions (organizations) teams can
+// join the game with, keeping its other settings as they are on the platform.
+// It reports whether the division was added.
+func (g *Game) EnsureDivision(division string) (bool, error) {
+	current, err := g.CS.GetGameById(g.Id)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(current.Organizations, division) {
+		return false, nil
+	}
+	current.Organizations = append(current.Organizations, division)
+	if err := g.Update(current); err != nil {
+		return false, err
+	}
+	g.Organizations = current.Organizations
+	return true, nil
+}
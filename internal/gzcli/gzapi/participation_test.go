@@ -0,0 +1,82 @@
+package gzapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestGame_Participations(t *testing.T) {
+	var edit ParticipationEditModel
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/game/1/participations": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"id":7,"team":{"id":10,"name":"alpha"},"division":"Open","status":"Accepted"}]`))
+		},
+		"/api/admin/participation/7": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("Expected PUT method, got %s", r.Method)
+			}
+			_ = json.NewDecoder(r.Body).Decode(&edit)
+			w.WriteHeader(http.StatusOK)
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	p, err := game.GetParticipation(10)
+	if err != nil {
+		t.Fatalf("GetParticipation() failed: %v", err)
+	}
+	if p.Id != 7 || p.Division != "Open" || p.CS != api {
+		t.Errorf("GetParticipation() = %+v, want participation 7 in Open", p)
+	}
+	if _, err := game.GetParticipation(11); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetParticipation() of another team error = %v, want ErrNotFound", err)
+	}
+
+	if err := p.SetDivision("Students"); err != nil {
+		t.Fatalf("SetDivision() failed: %v", err)
+	}
+	if edit.Division != "Students" || p.Division != "Students" {
+		t.Errorf("SetDivision() sent %+v, participation %+v; want the Students division", edit, p)
+	}
+}
+
+func TestGame_EnsureDivision(t *testing.T) {
+	var updated Game
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/1": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut {
+				_ = json.NewDecoder(r.Body).Decode(&updated)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":1,"title":"CTF","organizations":["Open"]}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	if added, err := game.EnsureDivision("Open"); err != nil || added {
+		t.Errorf("EnsureDivision() of an existing division = %v, %v; want false", added, err)
+	}
+	added, err := game.EnsureDivision("Students")
+	if err != nil || !added {
+		t.Fatalf("EnsureDivision() of a new division = %v, %v; want true", added, err)
+	}
+	if want := []string{"Open", "Students"}; !slices.Equal(updated.Organizations, want) || updated.Title != "CTF" {
+		t.Errorf("EnsureDivision() updated the game to %+v, want organizations %v", updated, want)
+	}
+}
@@ -0,0 +1,73 @@
+package gzapi
+
+import "net/url"
+
+// Post is an announcement published on the home page of the platform
+//
+//nolint:revive // Field names match API responses
+type Post struct {
+	Id         string     `json:"id"`
+	Title      string     `json:"title"`
+	Summary    string     `json:"summary"`
+	Content    string     `json:"content,omitempty"` // Only set by GetPost
+	IsPinned   bool       `json:"isPinned"`
+	Tags       []string   `json:"tags,omitempty"`
+	AuthorName string     `json:"authorName,omitempty"`
+	Time       CustomTime `json:"time"`
+}
+
+// PostForm contains the fields of a post to create or update
+type PostForm struct {
+	Title    string   `json:"title"`
+	Summary  string   `json:"summary"`
+	Content  string   `json:"content"`
+	IsPinned bool     `json:"isPinned"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// GetPosts retrieves the posts of the platform, without their content
+func (cs *GZAPI) GetPosts() ([]Post, error) {
+	var posts []Post
+	if err := cs.get("/api/posts", &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetPost retrieves a post with its content
+//
+//nolint:revive // Parameter name matches API specification
+func (cs *GZAPI) GetPost(postId string) (*Post, error) {
+	var post Post
+	if err := cs.get("/api/posts/"+url.PathEscape(postId), &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// CreatePost publishes a post and returns its ID
+func (cs *GZAPI) CreatePost(form PostForm) (string, error) {
+	var postId string
+	if err := cs.post("/api/edit/posts", &form, &postId); err != nil {
+		return "", err
+	}
+	return postId, nil
+}
+
+// UpdatePost replaces the fields of a post
+//
+//nolint:revive // Parameter name matches API specification
+func (cs *GZAPI) UpdatePost(postId string, form PostForm) (*Post, error) {
+	var post Post
+	if err := cs.put("/api/edit/posts/"+url.PathEscape(postId), &form, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// DeletePost removes a post
+//
+//nolint:revive // Parameter name matches API specification
+func (cs *GZAPI) DeletePost(postId string) error {
+	return cs.delete("/api/edit/posts/"+url.PathEscape(postId), nil)
+}
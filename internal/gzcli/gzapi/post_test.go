@@ -0,0 +1,58 @@
+package gzapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGZAPI_Posts(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/posts": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"id":"a1b2c3d4","title":"Welcome","summary":"Rules","isPinned":true,"time":1700000000000}]`))
+		},
+		"/api/posts/a1b2c3d4": func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"a1b2c3d4","title":"Welcome","summary":"Rules","content":"Be nice","isPinned":true,"time":1700000000000}`))
+		},
+		"/api/edit/posts": func(w http.ResponseWriter, r *http.Request) {
+			var form PostForm
+			if err := json.NewDecoder(r.Body).Decode(&form); err != nil || form.Title != "Welcome" || !form.IsPinned {
+				t.Errorf("unexpected post form %+v (%v)", form, err)
+			}
+			_, _ = w.Write([]byte(`"a1b2c3d4"`))
+		},
+		"/api/edit/posts/a1b2c3d4": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"a1b2c3d4","title":"Welcome!","summary":"Rules","content":"Be nice","time":1700000000000}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "post", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	id, err := api.CreatePost(PostForm{Title: "Welcome", Summary: "Rules", Content: "Be nice", IsPinned: true})
+	if err != nil || id != "a1b2c3d4" {
+		t.Fatalf("CreatePost() = %q, %v", id, err)
+	}
+	posts, err := api.GetPosts()
+	if err != nil || len(posts) != 1 || !posts[0].IsPinned {
+		t.Fatalf("GetPosts() = %+v, %v", posts, err)
+	}
+	post, err := api.GetPost(id)
+	if err != nil || post.Content != "Be nice" {
+		t.Fatalf("GetPost() = %+v, %v", post, err)
+	}
+	post, err = api.UpdatePost(id, PostForm{Title: "Welcome!", Summary: "Rules", Content: "Be nice"})
+	if err != nil || post.Title != "Welcome!" {
+		t.Fatalf("UpdatePost() = %+v, %v", post, err)
+	}
+	if err := api.DeletePost(id); err != nil {
+		t.Errorf("DeletePost() failed: %v", err)
+	}
+}
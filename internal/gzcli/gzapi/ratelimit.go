@@ -0,0 +1,100 @@
+package gzapi
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// rateLimiter holds API clients to a request rate per host, shared by every
+// client of the process so concurrent sync workers cannot exceed it together
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // Requests per second; 0 disables limiting
+	burst   float64
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+	sleep   func(time.Duration)
+}
+
+// tokenBucket holds the tokens left for one host
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+var limiter = &rateLimiter{
+	buckets: make(map[string]*tokenBucket),
+	now:     time.Now,
+	sleep:   time.Sleep,
+}
+
+// SetRateLimit limits API requests to rate per second for each host, allowing
+// bursts of up to burst requests. A rate of 0 or less disables limiting; a
+// burst below 1 allows one request at a time.
+func SetRateLimit(rate float64, burst int) {
+	limiter.set(rate, burst)
+}
+
+// RateLimit returns the configured requests per second per host, 0 if unlimited
+func RateLimit() float64 {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return limiter.rate
+}
+
+func (l *rateLimiter) set(rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rate < 0 {
+		rate = 0
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	l.rate = rate
+	l.burst = float64(burst)
+	l.buckets = make(map[string]*tokenBucket)
+}
+
+// wait blocks until a request to rawURL is allowed
+func (l *rateLimiter) wait(rawURL string) {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	if delay := l.reserve(host); delay > 0 {
+		l.sleep(delay)
+	}
+}
+
+// reserve takes a token for host and returns how long the caller must wait
+// before using it. Tokens may go negative so that waiting callers are served
+// in order.
+func (l *rateLimiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return 0
+	}
+
+	now := l.now()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[host] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * l.rate
+		if b.tokens > l.burst {
+			b.tokens = l.burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / l.rate * float64(time.Second))
+}
@@ -0,0 +1,101 @@
+package gzapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(rate float64, burst int) (*rateLimiter, *time.Time) {
+	now := time.Unix(0, 0)
+	l := &rateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		now:     func() time.Time { return now },
+		sleep:   func(d time.Duration) { now = now.Add(d) },
+	}
+	l.set(rate, burst)
+	return l, &now
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	l, now := newTestLimiter(2, 2)
+
+	// The burst is available at once
+	for i := 0; i < 2; i++ {
+		if d := l.reserve("a"); d != 0 {
+			t.Fatalf("request %d waited %v within the burst", i, d)
+		}
+	}
+	// Further requests queue behind each other at the rate
+	if d := l.reserve("a"); d != 500*time.Millisecond {
+		t.Errorf("third request waits %v, want 500ms", d)
+	}
+	if d := l.reserve("a"); d != time.Second {
+		t.Errorf("fourth request waits %v, want 1s", d)
+	}
+	// Other hosts have their own bucket
+	if d := l.reserve("b"); d != 0 {
+		t.Errorf("other host waited %v", d)
+	}
+
+	// Tokens refill over time, up to the burst
+	*now = now.Add(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		if d := l.reserve("a"); d != 0 {
+			t.Fatalf("request %d after refill waited %v", i, d)
+		}
+	}
+	if d := l.reserve("a"); d == 0 {
+		t.Error("refill exceeded the burst")
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l, _ := newTestLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if d := l.reserve("a"); d != 0 {
+			t.Fatalf("unlimited request waited %v", d)
+		}
+	}
+}
+
+func TestRateLimiterWaitUsesHost(t *testing.T) {
+	l, now := newTestLimiter(1, 1)
+	start := *now
+
+	l.wait("http://ctf.example/api/game")
+	l.wait("http://ctf.example/api/edit/games")
+	l.wait("http://other.example/api/game")
+
+	if got := now.Sub(start); got != time.Second {
+		t.Errorf("waited %v in total, want 1s for the second request to the same host", got)
+	}
+}
+
+func TestDoRequestRateLimited(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	SetRateLimit(20, 1)
+	defer SetRateLimit(0, 0)
+
+	api := &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := api.get("/api/game", nil); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests at 20/s took %v, want at least 100ms", elapsed)
+	}
+	if hits.Load() != 3 {
+		t.Errorf("server saw %d requests, want 3", hits.Load())
+	}
+}
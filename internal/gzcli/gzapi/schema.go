@@ -0,0 +1,292 @@
+package gzapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// schemaCheck enables strict-decode diagnostics: every JSON response is
+// compared against the Go type it is decoded into, and fields the type does
+// not know about (silently dropped) or expected fields the response lacks
+// (silently zeroed) are recorded per endpoint. Enabled by SetSchemaCheck or
+// the GZCLI_API_STRICT environment variable.
+var schemaCheck atomic.Bool
+
+func init() {
+	if v := os.Getenv("GZCLI_API_STRICT"); v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes") {
+		schemaCheck.Store(true)
+	}
+}
+
+// SetSchemaCheck enables or disables response schema drift detection
+func SetSchemaCheck(enabled bool) {
+	schemaCheck.Store(enabled)
+}
+
+// SchemaCheckEnabled reports whether response schema drift detection is enabled
+func SchemaCheckEnabled() bool {
+	return schemaCheck.Load()
+}
+
+// SchemaDrift lists the schema differences observed on one endpoint
+type SchemaDrift struct {
+	Endpoint string   `json:"endpoint"`
+	Unknown  []string `json:"unknown,omitempty"` // In the response but not in the Go type
+	Missing  []string `json:"missing,omitempty"` // Expected by the Go type but absent from the response
+}
+
+type driftEntry struct {
+	unknown map[string]struct{}
+	missing map[string]struct{}
+}
+
+var schemaDrift = struct {
+	mu         sync.Mutex
+	byEndpoint map[string]*driftEntry
+}{byEndpoint: make(map[string]*driftEntry)}
+
+// partialEndpoints return summaries decoded into the full detail types, so
+// absent fields there are expected and not reported as missing
+var partialEndpoints = map[string]bool{
+	"GET /api/edit/games/{id}/challenges": true,
+}
+
+// SchemaDriftReport returns the drift recorded since the last reset, sorted by endpoint
+func SchemaDriftReport() []SchemaDrift {
+	schemaDrift.mu.Lock()
+	defer schemaDrift.mu.Unlock()
+
+	report := make([]SchemaDrift, 0, len(schemaDrift.byEndpoint))
+	for endpoint, entry := range schemaDrift.byEndpoint {
+		if len(entry.unknown) == 0 && len(entry.missing) == 0 {
+			continue
+		}
+		report = append(report, SchemaDrift{
+			Endpoint: endpoint,
+			Unknown:  sortedKeys(entry.unknown),
+			Missing:  sortedKeys(entry.missing),
+		})
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Endpoint < report[j].Endpoint })
+	return report
+}
+
+// ResetSchemaDrift clears the recorded drift
+func ResetSchemaDrift() {
+	schemaDrift.mu.Lock()
+	defer schemaDrift.mu.Unlock()
+	schemaDrift.byEndpoint = make(map[string]*driftEntry)
+}
+
+var numericSegment = regexp.MustCompile(`/\d+(/|$)`)
+
+// normalizeEndpoint strips the query and replaces numeric path segments with {id}
+func normalizeEndpoint(method, url string) string {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		url = url[:i]
+	}
+	for numericSegment.MatchString(url) {
+		url = numericSegment.ReplaceAllString(url, "/{id}$1")
+	}
+	return method + " " + url
+}
+
+// recordSchemaDrift compares a response body with the value it was decoded into
+func recordSchemaDrift(method, url string, body []byte, target any) {
+	endpoint := normalizeEndpoint(method, url)
+	unknown, missing := DiffSchema(body, target, partialEndpoints[endpoint])
+	if len(unknown) == 0 && len(missing) == 0 {
+		return
+	}
+
+	schemaDrift.mu.Lock()
+	entry, ok := schemaDrift.byEndpoint[endpoint]
+	if !ok {
+		entry = &driftEntry{unknown: make(map[string]struct{}), missing: make(map[string]struct{})}
+		schemaDrift.byEndpoint[endpoint] = entry
+	}
+	var newUnknown, newMissing []string
+	for _, f := range unknown {
+		if _, seen := entry.unknown[f]; !seen {
+			entry.unknown[f] = struct{}{}
+			newUnknown = append(newUnknown, f)
+		}
+	}
+	for _, f := range missing {
+		if _, seen := entry.missing[f]; !seen {
+			entry.missing[f] = struct{}{}
+			newMissing = append(newMissing, f)
+		}
+	}
+	schemaDrift.mu.Unlock()
+
+	// Log each field once per endpoint
+	if len(newUnknown) > 0 {
+		log.Error("API schema drift on %s: unknown field(s) %s", endpoint, strings.Join(newUnknown, ", "))
+	}
+	if len(newMissing) > 0 {
+		log.Error("API schema drift on %s: missing field(s) %s", endpoint, strings.Join(newMissing, ", "))
+	}
+}
+
+// DiffSchema decodes body generically and compares it with the type of target.
+// It returns dotted field paths present in the body but unknown to the type,
+// and paths the type expects (no omitempty) that the body lacks. When partial
+// is true, missing fields are not reported.
+func DiffSchema(body []byte, target any, partial bool) (unknown, missing []string) {
+	if len(body) == 0 || target == nil {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, nil
+	}
+
+	d := &schemaDiffer{
+		partial: partial,
+		unknown: make(map[string]struct{}),
+		missing: make(map[string]struct{}),
+	}
+	d.compare(raw, reflect.TypeOf(target), "")
+	return sortedKeys(d.unknown), sortedKeys(d.missing)
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+type schemaField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+type schemaDiffer struct {
+	partial bool
+	unknown map[string]struct{}
+	missing map[string]struct{}
+}
+
+func (d *schemaDiffer) compare(value interface{}, typ reflect.Type, path string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	// Custom decoders (e.g. CustomTime) define their own format
+	if reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch typ.Kind() {
+		case reflect.Struct:
+			d.compareStruct(v, typ, path)
+		case reflect.Map:
+			for key, elem := range v {
+				d.compare(elem, typ.Elem(), joinPath(path, key))
+			}
+		}
+	case []interface{}:
+		if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+			return
+		}
+		for _, elem := range v {
+			d.compare(elem, typ.Elem(), path+"[]")
+		}
+	}
+}
+
+func (d *schemaDiffer) compareStruct(obj map[string]interface{}, typ reflect.Type, path string) {
+	fields := structFields(typ)
+
+	present := make(map[string]bool, len(obj))
+	for key, elem := range obj {
+		// encoding/json matches field names case-insensitively
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			d.unknown[joinPath(path, key)] = struct{}{}
+			continue
+		}
+		present[strings.ToLower(key)] = true
+		if elem != nil {
+			d.compare(elem, field.typ, joinPath(path, field.name))
+		}
+	}
+
+	if d.partial {
+		return
+	}
+	for key, field := range fields {
+		if !field.optional && !present[key] {
+			d.missing[joinPath(path, field.name)] = struct{}{}
+		}
+	}
+}
+
+// structFields returns the JSON fields of a struct keyed by lowercase name,
+// flattening untagged embedded structs like encoding/json does
+func structFields(typ reflect.Type) map[string]schemaField {
+	fields := make(map[string]schemaField)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, f := range structFields(ft) {
+				if _, exists := fields[k]; !exists {
+					fields[k] = f
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[strings.ToLower(name)] = schemaField{
+			name:     name,
+			typ:      sf.Type,
+			optional: strings.Contains(opts, "omitempty"),
+		}
+	}
+	return fields
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,123 @@
+package gzapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDiffSchema(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+		Note string `json:"note,omitempty"`
+	}
+	type embedded struct {
+		Shared int `json:"shared"`
+	}
+	type target struct {
+		embedded
+		Id      int        `json:"id"`
+		Items   []inner    `json:"items"`
+		When    CustomTime `json:"when"`
+		Ignored string     `json:"-"`
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		partial     bool
+		wantUnknown []string
+		wantMissing []string
+	}{
+		{
+			name: "exact match",
+			body: `{"id":1,"shared":2,"items":[{"name":"a"}],"when":1700000000000}`,
+		},
+		{
+			name:        "renamed field",
+			body:        `{"ID":1,"shared":2,"items":[{"title":"a"}],"when":"2024-01-01T00:00:00Z"}`,
+			wantUnknown: []string{"items[].title"},
+			wantMissing: []string{"items[].name"},
+		},
+		{
+			name:        "partial endpoint ignores missing",
+			body:        `{"id":1,"extra":true}`,
+			partial:     true,
+			wantUnknown: []string{"extra"},
+		},
+		{
+			name:        "ignored field is unknown to the decoder",
+			body:        `{"id":1,"shared":2,"items":[],"when":0,"Ignored":"x"}`,
+			wantUnknown: []string{"Ignored"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v target
+			unknown, missing := DiffSchema([]byte(tt.body), &v, tt.partial)
+			if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Errorf("unknown = %v, want %v", unknown, tt.wantUnknown)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/edit/games?count=100&skip=0": "GET /api/edit/games",
+		"/api/edit/games/12/challenges/7":  "GET /api/edit/games/{id}/challenges/{id}",
+		"/api/game/3/scoreboard":           "GET /api/game/{id}/scoreboard",
+	}
+	for url, want := range tests {
+		if got := normalizeEndpoint("GET", url); got != want {
+			t.Errorf("normalizeEndpoint(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestSchemaCheck_RecordsDriftPerEndpoint(t *testing.T) {
+	prev := SchemaCheckEnabled()
+	SetSchemaCheck(true)
+	ResetSchemaDrift()
+	defer func() {
+		SetSchemaCheck(prev)
+		ResetSchemaDrift()
+	}()
+
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/edit/games/5": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"id":5,"title":"ctf","newField":1}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "schema", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := api.GetGameById(5); err != nil {
+		t.Fatalf("GetGameById() failed: %v", err)
+	}
+
+	var drift *SchemaDrift
+	for _, d := range SchemaDriftReport() {
+		if d.Endpoint == "GET /api/edit/games/{id}" {
+			d := d
+			drift = &d
+		}
+	}
+	if drift == nil {
+		t.Fatalf("no drift recorded for game endpoint: %+v", SchemaDriftReport())
+	}
+	if !reflect.DeepEqual(drift.Unknown, []string{"newField"}) {
+		t.Errorf("unknown = %v, want [newField]", drift.Unknown)
+	}
+	if len(drift.Missing) == 0 {
+		t.Error("expected missing fields for partial game response")
+	}
+}
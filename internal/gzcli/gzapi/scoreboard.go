@@ -4,24 +4,95 @@ import (
 	"fmt"
 )
 
+// Blood types of a solve on the scoreboard
+const (
+	BloodFirst  = "FirstBlood"
+	BloodSecond = "SecondBlood"
+	BloodThird  = "ThirdBlood"
+	BloodNormal = "Normal"
+)
+
 // ScoreboardChallenge represents a challenge on the scoreboard
+//
+//nolint:revive // Field names match API responses
 type ScoreboardChallenge struct {
-	Score    int    `json:"score"`
-	Category string `json:"category"`
-	Title    string `json:"title"`
+	Id                int     `json:"id"`
+	Score             int     `json:"score"`
+	Category          string  `json:"category"`
+	Title             string  `json:"title"`
+	Solved            int     `json:"solved"`
+	Bloods            []Blood `json:"bloods,omitempty"`
+	DisableBloodBonus bool    `json:"disableBloodBonus"`
+}
+
+// Blood is one of the first three teams to solve a challenge
+//
+//nolint:revive // Field names match API responses
+type Blood struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Avatar     string     `json:"avatar,omitempty"`
+	SubmitTime CustomTime `json:"submitTimeUtc"`
+}
+
+// SolvedChallenge is a challenge solved by a team on the scoreboard
+//
+//nolint:revive // Field names match API responses
+type SolvedChallenge struct {
+	Id       int        `json:"id"`
+	Score    int        `json:"score"`
+	Type     string     `json:"type"` // One of the Blood* constants
+	UserName string     `json:"userName"`
+	Time     CustomTime `json:"time"`
 }
 
 // ScoreboardItem represents a team's score and ranking
+//
+//nolint:revive // Field names match API responses
 type ScoreboardItem struct {
-	Name  string `json:"name"`
-	Rank  int    `json:"rank"`
-	Score int    `json:"score"`
+	Id                 int               `json:"id"`
+	Name               string            `json:"name"`
+	Bio                string            `json:"bio,omitempty"`
+	Avatar             string            `json:"avatar,omitempty"`
+	Division           string            `json:"division,omitempty"`
+	Rank               int               `json:"rank"`
+	DivisionRank       int               `json:"divisionRank,omitempty"`
+	Score              int               `json:"score"`
+	SolvedCount        int               `json:"solvedCount"`
+	LastSubmissionTime CustomTime        `json:"lastSubmissionTime"`
+	SolvedChallenges   []SolvedChallenge `json:"solvedChallenges,omitempty"`
+}
+
+// TimeLinePoint is the score of a team at a point in time
+type TimeLinePoint struct {
+	Time  CustomTime `json:"time"`
+	Score int        `json:"score"`
+}
+
+// TeamTimeLine is the score history of one of the top teams
+//
+//nolint:revive // Field names match API responses
+type TeamTimeLine struct {
+	Id    int             `json:"id"`
+	Name  string          `json:"name"`
+	Items []TimeLinePoint `json:"items"`
 }
 
 // Scoreboard represents the game scoreboard with challenges and team rankings
 type Scoreboard struct {
-	Challenges map[string][]ScoreboardChallenge `json:"challenges"`
-	Items      []ScoreboardItem                 `json:"items"`
+	UpdateTime     CustomTime                       `json:"updateTimeUtc"`
+	BloodBonus     int64                            `json:"bloodBonus"`
+	TimeLines      map[string][]TeamTimeLine        `json:"timeLines,omitempty"` // By division
+	Challenges     map[string][]ScoreboardChallenge `json:"challenges"`
+	ChallengeCount int                              `json:"challengeCount"`
+	Items          []ScoreboardItem                 `json:"items"`
+}
+
+// ScoreboardPage is a page of the ranking of a scoreboard
+type ScoreboardPage struct {
+	Items []ScoreboardItem
+	Skip  int
+	Total int // Teams on the whole scoreboard
 }
 
 // GetScoreboard retrieves the current scoreboard for the game
@@ -33,3 +104,38 @@ func (g *Game) GetScoreboard() (*Scoreboard, error) {
 	}
 	return &scoreboard, nil
 }
+
+// GetScoreboardPage retrieves count teams of the ranking starting at skip;
+// a count of 0 or less returns every team after skip. GZCTF serves the
+// scoreboard whole, so the page is cut from a single scoreboard call.
+func (g *Game) GetScoreboardPage(skip, count int) (*ScoreboardPage, error) {
+	scoreboard, err := g.GetScoreboard()
+	if err != nil {
+		return nil, err
+	}
+	return scoreboard.Page(skip, count), nil
+}
+
+// Page returns count teams of the ranking starting at skip; a count of 0 or
+// less returns every team after skip
+func (s *Scoreboard) Page(skip, count int) *ScoreboardPage {
+	total := len(s.Items)
+	start := min(max(skip, 0), total)
+	end := total
+	if count > 0 {
+		end = min(start+count, total)
+	}
+	return &ScoreboardPage{Items: s.Items[start:end], Skip: start, Total: total}
+}
+
+// Challenge returns the scoreboard entry of a challenge by ID
+func (s *Scoreboard) Challenge(id int) (ScoreboardChallenge, bool) {
+	for _, challenges := range s.Challenges {
+		for _, challenge := range challenges {
+			if challenge.Id == id {
+				return challenge, true
+			}
+		}
+	}
+	return ScoreboardChallenge{}, false
+}
@@ -0,0 +1,144 @@
+package gzapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Submission judgement results returned by GetSubmissionStatus
+const (
+	AnswerFlagSubmitted = "FlagSubmitted"
+	AnswerAccepted      = "Accepted"
+	AnswerWrongAnswer   = "WrongAnswer"
+	AnswerCheatDetected = "CheatDetected"
+	AnswerNotFound      = "NotFound"
+)
+
+// FlagSubmitForm contains the flag submitted for a challenge
+type FlagSubmitForm struct {
+	Flag string `json:"flag"`
+}
+
+// GameChallengeInfo is a challenge as listed to participants of a game
+//
+//nolint:revive // Field names match API responses
+type GameChallengeInfo struct {
+	Id       int    `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+	Score    int    `json:"score"`
+	Solved   int    `json:"solved"`
+}
+
+// GameDetails is the participant view of a game
+type GameDetails struct {
+	Challenges     map[string][]GameChallengeInfo `json:"challenges"`
+	ChallengeCount int                            `json:"challengeCount"`
+}
+
+// GetDetails retrieves the challenges visible to the current user's team
+func (g *Game) GetDetails() (*GameDetails, error) {
+	var details GameDetails
+	if err := g.CS.get(fmt.Sprintf("/api/game/%d/details", g.Id), &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// SubmitFlag submits a flag for a challenge as the current user's team and
+// returns the submission ID used to poll its judgement
+//
+//nolint:revive // Parameter name matches API specification
+func (g *Game) SubmitFlag(challengeId int, flag string) (int, error) {
+	var submissionId int
+	if err := g.CS.post(fmt.Sprintf("/api/game/%d/challenges/%d", g.Id, challengeId), &FlagSubmitForm{Flag: flag}, &submissionId); err != nil {
+		return 0, err
+	}
+	return submissionId, nil
+}
+
+// GetSubmissionStatus returns the judgement of a submission; it stays
+// AnswerFlagSubmitted until the flag has been checked
+//
+//nolint:revive // Parameter names match API specification
+func (g *Game) GetSubmissionStatus(challengeId, submissionId int) (string, error) {
+	var status string
+	if err := g.CS.get(fmt.Sprintf("/api/game/%d/challenges/%d/status/%d", g.Id, challengeId, submissionId), &status); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// submissionPageSize is the number of submissions requested per page
+const submissionPageSize = 100
+
+// Submission is a flag submitted by a team, as listed to monitors
+type Submission struct {
+	Answer    string     `json:"answer"`
+	Status    string     `json:"status"` // One of the Answer* constants
+	Time      CustomTime `json:"time"`
+	User      string     `json:"user"`
+	Team      string     `json:"team"`
+	Challenge string     `json:"challenge"`
+}
+
+// SubmissionFilter selects submissions by judgement and time; zero fields
+// match everything
+type SubmissionFilter struct {
+	Status string    // One of the Answer* constants
+	Since  time.Time // Inclusive
+	Until  time.Time // Exclusive
+}
+
+func (f SubmissionFilter) matches(s Submission) bool {
+	if !f.Since.IsZero() && s.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !s.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// GetSubmissionsPage retrieves count submissions of the game, newest first,
+// after skipping skip of them. status filters by judgement when not empty.
+func (g *Game) GetSubmissionsPage(status string, skip, count int) ([]Submission, error) {
+	query := url.Values{}
+	query.Set("count", strconv.Itoa(count))
+	query.Set("skip", strconv.Itoa(skip))
+	if status != "" {
+		query.Set("type", status)
+	}
+
+	var submissions []Submission
+	if err := g.CS.get(fmt.Sprintf("/api/game/%d/submissions?%s", g.Id, query.Encode()), &submissions); err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// GetSubmissions retrieves every submission of the game matching filter,
+// newest first, paging through the monitor API. Paging stops at the first
+// page reaching past filter.Since, since submissions are listed newest first.
+func (g *Game) GetSubmissions(filter SubmissionFilter) ([]Submission, error) {
+	var submissions []Submission
+	for skip := 0; ; skip += submissionPageSize {
+		page, err := g.GetSubmissionsPage(filter.Status, skip, submissionPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, submission := range page {
+			if filter.matches(submission) {
+				submissions = append(submissions, submission)
+			}
+		}
+		if len(page) < submissionPageSize {
+			return submissions, nil
+		}
+		if oldest := page[len(page)-1]; !filter.Since.IsZero() && oldest.Time.Before(filter.Since) {
+			return submissions, nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package gzapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGame_SubmitFlagAndStatus(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/game/1/challenges/7": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" {
+				t.Errorf("Expected POST method, got %s", r.Method)
+			}
+
+			var form FlagSubmitForm
+			if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			if form.Flag != "flag{test}" {
+				t.Errorf("Expected flag 'flag{test}', got %s", form.Flag)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`42`))
+		},
+		"/api/game/1/challenges/7/status/42": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`"WrongAnswer"`))
+		},
+		"/api/game/1/details": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"challenges":{"Web":[{"id":7,"title":"Login","category":"Web","score":100}]},"challengeCount":1}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "submit", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	details, err := game.GetDetails()
+	if err != nil {
+		t.Fatalf("Game.GetDetails() failed: %v", err)
+	}
+	if details.ChallengeCount != 1 || len(details.Challenges["Web"]) != 1 || details.Challenges["Web"][0].Id != 7 {
+		t.Errorf("unexpected details: %+v", details)
+	}
+
+	id, err := game.SubmitFlag(7, "flag{test}")
+	if err != nil {
+		t.Fatalf("Game.SubmitFlag() failed: %v", err)
+	}
+	if id != 42 {
+		t.Errorf("Expected submission ID 42, got %d", id)
+	}
+
+	status, err := game.GetSubmissionStatus(7, id)
+	if err != nil {
+		t.Fatalf("Game.GetSubmissionStatus() failed: %v", err)
+	}
+	if status != AnswerWrongAnswer {
+		t.Errorf("Expected %s, got %s", AnswerWrongAnswer, status)
+	}
+}
+
+func TestGame_GetSubmissions_PagesAndFilters(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// 150 submissions, one per minute, newest first
+	all := make([]Submission, 150)
+	for i := range all {
+		all[i] = Submission{
+			Answer:    "flag{x}",
+			Status:    AnswerAccepted,
+			Time:      CustomTime{base.Add(-time.Duration(i) * time.Minute)},
+			Team:      "Team",
+			Challenge: "Chal",
+		}
+	}
+
+	var requests int
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/game/1/submissions": func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if got := r.URL.Query().Get("type"); got != AnswerAccepted {
+				t.Errorf("type = %q, want %q", got, AnswerAccepted)
+			}
+			skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+			count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+			end := min(skip+count, len(all))
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(all[min(skip, end):end])
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "monitor", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	game := &Game{Id: 1, CS: api}
+
+	submissions, err := game.GetSubmissions(SubmissionFilter{Status: AnswerAccepted})
+	if err != nil {
+		t.Fatalf("GetSubmissions() failed: %v", err)
+	}
+	if len(submissions) != len(all) || requests != 2 {
+		t.Errorf("got %d submissions in %d requests, want %d in 2", len(submissions), requests, len(all))
+	}
+
+	// Only the last 10 minutes, excluding the newest one; paging stops on the first page
+	requests = 0
+	submissions, err = game.GetSubmissions(SubmissionFilter{
+		Status: AnswerAccepted,
+		Since:  base.Add(-10 * time.Minute),
+		Until:  base,
+	})
+	if err != nil {
+		t.Fatalf("GetSubmissions() failed: %v", err)
+	}
+	if len(submissions) != 10 || requests != 1 {
+		t.Errorf("got %d submissions in %d requests, want 10 in 1", len(submissions), requests)
+	}
+	if !submissions[0].Time.Equal(base.Add(-time.Minute)) {
+		t.Errorf("newest submission at %v, want %v", submissions[0].Time, base.Add(-time.Minute))
+	}
+}
@@ -9,6 +9,7 @@ type Team struct {
 	Id      int    `json:"id"`
 	Name    string `json:"name"`
 	Bio     string `json:"bio"`
+	Avatar  string `json:"avatar,omitempty"`
 	Locked  bool   `json:"locked"`
 	Members []User `json:"members"`
 	CS      *GZAPI `json:"-"`
@@ -45,6 +46,18 @@ func (cs *GZAPI) GetTeams() ([]*Team, error) {
 	return team, nil
 }
 
+// GetTeamDetails retrieves a team with its members by ID
+//
+//nolint:revive // Parameter name matches API specification
+func (cs *GZAPI) GetTeamDetails(teamId int) (*Team, error) {
+	var team Team
+	if err := cs.get(fmt.Sprintf("/api/team/%d", teamId), &team); err != nil {
+		return nil, err
+	}
+	team.CS = cs
+	return &team, nil
+}
+
 // Teams retrieves all teams from the platform with pagination support
 func (cs *GZAPI) Teams() ([]*Team, error) {
 	var teams struct {
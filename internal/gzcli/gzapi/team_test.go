@@ -116,6 +116,32 @@ func TestGZAPI_Teams(t *testing.T) {
 	}
 }
 
+func TestGZAPI_GetTeamDetails(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/team/5": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "GET" {
+				t.Errorf("Expected GET method, got %s", r.Method)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":5,"name":"Team 5","bio":"bio","locked":true,"members":[{"id":"u1","username":"alice","captain":true}]}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	team, err := api.GetTeamDetails(5)
+	if err != nil {
+		t.Fatalf("GetTeamDetails() failed: %v", err)
+	}
+	if team.Name != "Team 5" || !team.Locked || len(team.Members) != 1 || !team.Members[0].Captain || team.CS != api {
+		t.Errorf("unexpected team: %+v", team)
+	}
+}
+
 func TestTeam_Delete(t *testing.T) {
 	server := mockServer(t, map[string]http.HandlerFunc{
 		"/api/admin/teams/5": func(w http.ResponseWriter, r *http.Request) {
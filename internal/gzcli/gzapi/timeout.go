@@ -0,0 +1,164 @@
+package gzapi
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Timeouts are the time limits of API calls by operation class. A zero field
+// keeps the default; a negative one disables the limit.
+type Timeouts struct {
+	Connect  time.Duration `yaml:"connect,omitempty"`  // Establishing a connection, TLS handshake included
+	Request  time.Duration `yaml:"request,omitempty"`  // Regular JSON API calls
+	Upload   time.Duration `yaml:"upload,omitempty"`   // Attachment, asset and poster uploads, per resumable chunk
+	Download time.Duration `yaml:"download,omitempty"` // File downloads
+}
+
+// DefaultTimeouts are the timeouts used when none are configured
+var DefaultTimeouts = Timeouts{
+	Connect:  10 * time.Second,
+	Request:  30 * time.Second,
+	Upload:   30 * time.Minute,
+	Download: 30 * time.Minute,
+}
+
+// opClass is the operation class of an API call, selecting its timeout
+type opClass int
+
+const (
+	opRequest opClass = iota
+	opUpload
+	opDownload
+)
+
+var (
+	timeouts   = DefaultTimeouts
+	timeoutsMu sync.RWMutex
+)
+
+// Timeout environment variables, overriding the defaults before SetTimeouts
+var timeoutEnv = map[string]*time.Duration{
+	"GZCLI_TIMEOUT_CONNECT":  &timeouts.Connect,
+	"GZCLI_TIMEOUT_REQUEST":  &timeouts.Request,
+	"GZCLI_TIMEOUT_UPLOAD":   &timeouts.Upload,
+	"GZCLI_TIMEOUT_DOWNLOAD": &timeouts.Download,
+}
+
+func init() {
+	for name, field := range timeoutEnv {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Error("Ignoring invalid %s %q: %v", name, v, err)
+			continue
+		}
+		*field = d
+	}
+}
+
+// SetTimeouts sets the timeouts of every client of the process. Zero fields
+// leave the current value unchanged.
+func SetTimeouts(t Timeouts) {
+	timeoutsMu.Lock()
+	defer timeoutsMu.Unlock()
+	timeouts = t.merge(timeouts)
+}
+
+// GetTimeouts returns the timeouts in effect for clients without overrides
+func GetTimeouts() Timeouts {
+	timeoutsMu.RLock()
+	defer timeoutsMu.RUnlock()
+	return timeouts
+}
+
+// merge returns t with its zero fields taken from base
+func (t Timeouts) merge(base Timeouts) Timeouts {
+	if t.Connect == 0 {
+		t.Connect = base.Connect
+	}
+	if t.Request == 0 {
+		t.Request = base.Request
+	}
+	if t.Upload == 0 {
+		t.Upload = base.Upload
+	}
+	if t.Download == 0 {
+		t.Download = base.Download
+	}
+	return t
+}
+
+func (t Timeouts) forClass(class opClass) time.Duration {
+	switch class {
+	case opUpload:
+		return t.Upload
+	case opDownload:
+		return t.Download
+	default:
+		return t.Request
+	}
+}
+
+// WithTimeouts returns a client sharing the session of cs whose calls use
+// the non-zero fields of t instead of the process-wide timeouts, e.g. to
+// allow a single large attachment more time:
+//
+//	api.WithTimeouts(gzapi.Timeouts{Upload: 2 * time.Hour}).CreateAssets(file)
+func (cs *GZAPI) WithTimeouts(t Timeouts) *GZAPI {
+	if cs.timeouts != nil {
+		t = t.merge(*cs.timeouts)
+	}
+	return &GZAPI{
+		Url:         cs.Url,
+		Creds:       cs.Creds,
+		Client:      cs.Client,
+		cookieJar:   cs.cookieJar,
+		cookieStore: cs.cookieStore,
+		timeouts:    &t,
+		token:       cs.authToken(),
+	}
+}
+
+// connectTimeoutKey carries the connect timeout of a client in the context
+// of its requests, for dialContext
+type connectTimeoutKey struct{}
+
+// requestContext returns the context bounding a call of the given class
+func (cs *GZAPI) requestContext(class opClass) (context.Context, context.CancelFunc) {
+	t := GetTimeouts()
+	if cs.timeouts != nil {
+		t = cs.timeouts.merge(t)
+	}
+	ctx := context.WithValue(context.Background(), connectTimeoutKey{}, t.Connect)
+	if d := t.forClass(class); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// connectTimeout returns the connect timeout of the client a request context
+// was created for, or the process-wide one
+func connectTimeout(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(connectTimeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	return GetTimeouts().Connect
+}
+
+// dialContext connects with the connect timeout of the requesting client,
+// read on every dial so SetTimeouts applies to clients created before it
+func dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := net.Dialer{KeepAlive: 30 * time.Second}
+	if d := connectTimeout(ctx); d > 0 {
+		dialer.Timeout = d
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
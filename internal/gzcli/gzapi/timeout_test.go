@@ -0,0 +1,127 @@
+package gzapi
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTimeoutsMerge(t *testing.T) {
+	base := Timeouts{Connect: time.Second, Request: 2 * time.Second, Upload: 3 * time.Second, Download: 4 * time.Second}
+	got := Timeouts{Upload: time.Hour, Download: -1}.merge(base)
+	want := Timeouts{Connect: time.Second, Request: 2 * time.Second, Upload: time.Hour, Download: -1}
+	if got != want {
+		t.Errorf("merge() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSetTimeouts_KeepsUnsetFields(t *testing.T) {
+	saved := GetTimeouts()
+	t.Cleanup(func() {
+		timeoutsMu.Lock()
+		timeouts = saved
+		timeoutsMu.Unlock()
+	})
+
+	SetTimeouts(Timeouts{Request: 5 * time.Second})
+	got := GetTimeouts()
+	if got.Request != 5*time.Second {
+		t.Errorf("Request = %v, want 5s", got.Request)
+	}
+	if got.Upload != saved.Upload || got.Connect != saved.Connect || got.Download != saved.Download {
+		t.Errorf("unset timeouts changed: %+v, was %+v", got, saved)
+	}
+}
+
+func slowServer(t *testing.T, delay time.Duration) *GZAPI {
+	t.Helper()
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/slow": func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+		},
+		"/api/upload": func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`[]`))
+		},
+		"/assets/file.txt": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("content"))
+		},
+	})
+	t.Cleanup(server.Close)
+	return &GZAPI{Url: server.URL, Client: createOptimizedClient(nil)}
+}
+
+func TestRequestTimeout_PerClass(t *testing.T) {
+	api := slowServer(t, 200*time.Millisecond).WithTimeouts(Timeouts{Request: 50 * time.Millisecond, Upload: 5 * time.Second})
+
+	err := api.get("/api/slow", nil)
+	if err == nil {
+		t.Fatal("expected regular request to time out")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Fatalf("expected transport timeout, got API error %v", err)
+	}
+
+	// Uploads use their own, longer timeout
+	file := filepath.Join(t.TempDir(), "attachment.zip")
+	if err := os.WriteFile(file, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.postMultiPart("/api/upload", file, nil); err != nil {
+		t.Fatalf("upload failed under upload timeout: %v", err)
+	}
+}
+
+func TestWithTimeouts_OverridesOnlyCopy(t *testing.T) {
+	api := slowServer(t, 100*time.Millisecond)
+	short := api.WithTimeouts(Timeouts{Request: 20 * time.Millisecond})
+
+	if err := short.get("/api/slow", nil); err == nil {
+		t.Error("expected overridden client to time out")
+	}
+	if err := api.get("/api/slow", nil); err != nil {
+		t.Errorf("original client should keep the default timeout: %v", err)
+	}
+
+	// Overrides stack on top of earlier ones
+	both := short.WithTimeouts(Timeouts{Upload: time.Minute})
+	if both.timeouts.Request != 20*time.Millisecond || both.timeouts.Upload != time.Minute {
+		t.Errorf("stacked overrides = %+v", *both.timeouts)
+	}
+}
+
+func TestWithTimeouts_Connect(t *testing.T) {
+	api := (&GZAPI{}).WithTimeouts(Timeouts{Connect: 5 * time.Millisecond})
+
+	ctx, cancel := api.requestContext(opRequest)
+	defer cancel()
+	if got := connectTimeout(ctx); got != 5*time.Millisecond {
+		t.Errorf("connect timeout of the overridden client = %v, want 5ms", got)
+	}
+
+	ctx, cancel = (&GZAPI{}).requestContext(opRequest)
+	defer cancel()
+	if got, want := connectTimeout(ctx), GetTimeouts().Connect; got != want {
+		t.Errorf("connect timeout = %v, want the process-wide %v", got, want)
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	api := slowServer(t, 0)
+	dest := filepath.Join(t.TempDir(), "file.txt")
+	if err := api.DownloadFile("/assets/file.txt", dest); err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	content, err := os.ReadFile(dest) //nolint:gosec // G304: test file in temp dir
+	if err != nil || string(content) != "content" {
+		t.Errorf("downloaded %q, %v", content, err)
+	}
+}
@@ -0,0 +1,93 @@
+package gzapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/imroc/req/v3"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// TokenEnv is the environment variable holding the API token used when the
+// credentials have none, e.g. on CI
+const TokenEnv = "GZCLI_API_TOKEN"
+
+// credsToken returns the API token of creds, or the one of TokenEnv
+func credsToken(creds *Creds) string {
+	if creds.Token != "" {
+		return creds.Token
+	}
+	return os.Getenv(TokenEnv)
+}
+
+// HasToken reports whether creds authenticate with an API token
+func (c Creds) HasToken() bool {
+	return credsToken(&c) != ""
+}
+
+// authToken returns the API token requests are sent with, empty without one
+// or once it was rejected
+func (cs *GZAPI) authToken() string {
+	cs.authMu.Lock()
+	defer cs.authMu.Unlock()
+	return cs.token
+}
+
+// newRequest starts a request bounded by ctx, with the language and API token
+// of the client
+func (cs *GZAPI) newRequest(ctx context.Context) *req.Request {
+	r := withLanguage(cs.Client.R().SetContext(ctx))
+	if token := cs.authToken(); token != "" {
+		r.SetBearerAuthToken(token)
+	}
+	return r
+}
+
+// reauthenticate logs in again after a 401. A rejected API token, e.g. an
+// expired or revoked one, is dropped for the cookie login of the username and
+// password when the credentials have them.
+func (cs *GZAPI) reauthenticate() error {
+	cs.authMu.Lock()
+	if cs.token != "" {
+		if cs.Creds.Username == "" || cs.Creds.Password == "" {
+			cs.authMu.Unlock()
+			return fmt.Errorf("API token rejected and no username and password to log in with")
+		}
+		log.Error("API token rejected, falling back to username and password login")
+		cs.token = ""
+	}
+	cs.authMu.Unlock()
+	return cs.Login()
+}
+
+// sessionID returns the login count of the client, taken before a request so
+// a 401 can tell whether the session it was sent with is still current
+func (cs *GZAPI) sessionID() uint64 {
+	cs.authMu.Lock()
+	defer cs.authMu.Unlock()
+	return cs.session
+}
+
+// refreshSession logs in again after a request sent with session was
+// rejected. Concurrent requests rejected together share a single login, and
+// requests whose session was replaced meanwhile reuse the new one.
+func (cs *GZAPI) refreshSession(session uint64) error {
+	if cs.sessionID() != session {
+		return nil
+	}
+	_, err, _ := cs.relogin.Do("login", func() (any, error) {
+		if cs.sessionID() != session {
+			return nil, nil
+		}
+		if err := cs.reauthenticate(); err != nil {
+			return nil, err
+		}
+		cs.authMu.Lock()
+		cs.session++
+		cs.authMu.Unlock()
+		return nil, nil
+	})
+	return err
+}
@@ -0,0 +1,173 @@
+//nolint:revive // Test file with unused parameters
+package gzapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer accepts requests to /api/protected with the bearer token valid
+// or a session cookie from logging in, counting logins
+func tokenServer(t *testing.T, valid string, logins *atomic.Int32) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/api/account/login": func(w http.ResponseWriter, r *http.Request) {
+			logins.Add(1)
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if _, ok := body["token"]; ok {
+				t.Error("API token sent in the login request")
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "cookie", Path: "/", Expires: time.Now().Add(time.Hour)})
+			_, _ = w.Write([]byte(`{"succeeded": true}`))
+		},
+		"/api/protected": func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie("session")
+			if r.Header.Get("Authorization") != "Bearer "+valid && (err != nil || cookie.Value != "cookie") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		},
+	}
+}
+
+// inTempDir runs the test in a temporary directory, away from cached cookies
+func inTempDir(t *testing.T) {
+	originalWD, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to switch working directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalWD) })
+}
+
+func TestInit_Token(t *testing.T) {
+	inTempDir(t)
+	var logins atomic.Int32
+	server := mockServer(t, tokenServer(t, "gz_valid", &logins))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Token: "gz_valid"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	var resp map[string]bool
+	if err := api.get("/api/protected", &resp); err != nil {
+		t.Fatalf("request with API token failed: %v", err)
+	}
+	if logins.Load() != 0 {
+		t.Errorf("got %d logins, want none with an API token", logins.Load())
+	}
+	if err := api.WithTimeouts(Timeouts{Request: time.Minute}).get("/api/protected", &resp); err != nil {
+		t.Errorf("request of a client with other timeouts failed: %v", err)
+	}
+}
+
+func TestInit_TokenFromEnv(t *testing.T) {
+	inTempDir(t)
+	t.Setenv(TokenEnv, "gz_env")
+	var logins atomic.Int32
+	server := mockServer(t, tokenServer(t, "gz_env", &logins))
+	defer server.Close()
+
+	creds := &Creds{}
+	if !creds.HasToken() {
+		t.Errorf("HasToken() = false with %s set", TokenEnv)
+	}
+	api, err := Init(server.URL, creds)
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	var resp map[string]bool
+	if err := api.get("/api/protected", &resp); err != nil || logins.Load() != 0 {
+		t.Errorf("request with the token of %s: %v, %d logins", TokenEnv, err, logins.Load())
+	}
+}
+
+func TestInit_RejectedTokenFallsBackToLogin(t *testing.T) {
+	inTempDir(t)
+	var logins atomic.Int32
+	server := mockServer(t, tokenServer(t, "gz_valid", &logins))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "admin", Password: "secret", Token: "gz_revoked"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	var resp map[string]bool
+	if err := api.get("/api/protected", &resp); err != nil {
+		t.Fatalf("request after falling back to login failed: %v", err)
+	}
+	if err := api.get("/api/protected", &resp); err != nil {
+		t.Fatalf("second request failed: %v", err)
+	}
+	if logins.Load() != 1 {
+		t.Errorf("got %d logins, want 1 after the token was rejected", logins.Load())
+	}
+	if api.authToken() != "" {
+		t.Error("rejected token still in use")
+	}
+}
+
+func TestInit_RejectedTokenWithoutPassword(t *testing.T) {
+	inTempDir(t)
+	var logins atomic.Int32
+	server := mockServer(t, tokenServer(t, "gz_valid", &logins))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Token: "gz_revoked"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	var resp map[string]bool
+	err = api.get("/api/protected", &resp)
+	if !errors.Is(err, ErrUnauthorized) || !strings.Contains(err.Error(), "API token rejected") {
+		t.Errorf("get() error = %v, want a rejected token error", err)
+	}
+	if logins.Load() != 0 {
+		t.Errorf("got %d logins without a password", logins.Load())
+	}
+}
+
+func TestDoRequest_ConcurrentReloginOnce(t *testing.T) {
+	inTempDir(t)
+	var logins atomic.Int32
+	server := mockServer(t, tokenServer(t, "gz_valid", &logins))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	// Expire the session
+	jar, _ := cookiejar.New(nil)
+	api.Client.SetCookieJar(jar)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp map[string]bool
+			errs <- api.get("/api/protected", &resp)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("request after the session expired failed: %v", err)
+		}
+	}
+	if got := logins.Load(); got != 2 {
+		t.Errorf("logins = %d, want Init and a single login after the 401s", got)
+	}
+}
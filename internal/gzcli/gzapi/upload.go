@@ -0,0 +1,321 @@
+package gzapi
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/imroc/req/v3"
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// UploadProgressFunc receives the progress of a file upload: bytes sent so
+// far out of total. It may be called concurrently for different files.
+type UploadProgressFunc func(file string, sent, total int64)
+
+var (
+	uploadProgress   UploadProgressFunc
+	uploadProgressMu sync.RWMutex
+)
+
+// SetUploadProgress sets the callback reporting the progress of attachment
+// and poster uploads; nil disables progress reporting
+func SetUploadProgress(fn UploadProgressFunc) {
+	uploadProgressMu.Lock()
+	defer uploadProgressMu.Unlock()
+	uploadProgress = fn
+}
+
+func getUploadProgress() UploadProgressFunc {
+	uploadProgressMu.RLock()
+	defer uploadProgressMu.RUnlock()
+	return uploadProgress
+}
+
+// withUploadProgress reports the progress of the multipart upload of r
+func withUploadProgress(r *req.Request, file string) *req.Request {
+	progress := getUploadProgress()
+	if progress == nil {
+		return r
+	}
+	return r.SetUploadCallback(func(info req.UploadInfo) {
+		progress(file, info.UploadedSize, info.FileSize)
+	})
+}
+
+const tusVersion = "1.0.0"
+
+// resumableUploads enables resumable chunked uploads on servers that support
+// them. It is opt-in, since stock GZCTF does not and probing for support costs
+// a request. Enabled by SetResumableUploads or the GZCLI_RESUMABLE_UPLOADS
+// environment variable.
+var resumableUploads atomic.Bool
+
+func init() {
+	if v := os.Getenv("GZCLI_RESUMABLE_UPLOADS"); v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes") {
+		resumableUploads.Store(true)
+	}
+}
+
+// SetResumableUploads enables or disables resumable uploads
+func SetResumableUploads(enabled bool) {
+	resumableUploads.Store(enabled)
+}
+
+// uploadChunkSize is the size of the chunks of a resumable upload
+var uploadChunkSize = 8 << 20
+
+// supportsResumableUpload reports whether the server accepts resumable
+// uploads on url, i.e. advertises the creation extension of the tus protocol
+// (https://tus.io) for it. The answer is cached per client.
+func (cs *GZAPI) supportsResumableUpload(url string) bool {
+	if !resumableUploads.Load() {
+		return false
+	}
+	cs.tusOnce.Do(func() {
+		resp, err := cs.rawRequest(http.MethodOptions, cs.Url+url, opRequest, nil, nil)
+		if err != nil {
+			return
+		}
+		cs.tusSupported = resp.Header.Get("Tus-Resumable") != "" &&
+			containsToken(resp.Header.Get("Tus-Extension"), "creation")
+		if cs.tusSupported {
+			log.Debug("Server supports resumable uploads on %s", url)
+		}
+	})
+	return cs.tusSupported
+}
+
+// uploadState is the on-disk record of an unfinished resumable upload
+type uploadState struct {
+	Location string `yaml:"location"`
+	File     string `yaml:"file"`
+	Size     int64  `yaml:"size"`
+}
+
+// uploadResumable uploads file to url in chunks with the tus protocol. The
+// upload location is recorded under .gzcli/cache/uploads, so an interrupted
+// upload continues where it stopped on the next call for the same file. The
+// server answers the final chunk with the response of a regular upload,
+// decoded into data.
+func (cs *GZAPI) uploadResumable(url, file string, data any) error {
+	f, err := os.Open(file) //nolint:gosec // G304: uploading user-selected files is intended
+	if err != nil {
+		return fmt.Errorf("file not found: %s", file)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	statePath, err := cs.uploadStatePath(file, info)
+	if err != nil {
+		return err
+	}
+
+	location, offset := cs.resumeUpload(statePath, size)
+	if location == "" {
+		if location, err = cs.createUpload(url, filepath.Base(file), size); err != nil {
+			return err
+		}
+		saveUploadState(statePath, uploadState{Location: location, File: file, Size: size})
+	} else {
+		log.Info("Resuming upload of %s at %d/%d bytes", filepath.Base(file), offset, size)
+	}
+
+	progress := getUploadProgress()
+	if progress != nil {
+		progress(file, offset, size)
+	}
+
+	chunk := make([]byte, uploadChunkSize)
+	var resp *req.Response
+	for offset < size || resp == nil {
+		n, err := f.ReadAt(chunk, offset)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		resp, err = cs.rawRequest(http.MethodPatch, location, opUpload, map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": strconv.FormatInt(offset, 10),
+		}, chunk[:n])
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+			return newAPIError(http.MethodPatch, url, resp.StatusCode, resp.Bytes())
+		}
+		next, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+		if err != nil || next > size || (next <= offset && n > 0) {
+			return fmt.Errorf("invalid Upload-Offset %q in response", resp.Header.Get("Upload-Offset"))
+		}
+		offset = next
+		if progress != nil {
+			progress(file, offset, size)
+		}
+	}
+
+	_ = os.Remove(statePath)
+
+	if data == nil {
+		return nil
+	}
+	if len(resp.Bytes()) == 0 {
+		return fmt.Errorf("server did not return the uploaded file for %s", url)
+	}
+	if err := json.Unmarshal(resp.Bytes(), data); err != nil {
+		return fmt.Errorf("error unmarshal json: %w, %s", err, resp.String())
+	}
+	return nil
+}
+
+// createUpload creates a resumable upload of size bytes and returns its
+// location
+func (cs *GZAPI) createUpload(url, name string, size int64) (string, error) {
+	resp, err := cs.rawRequest(http.MethodPost, cs.Url+url, opRequest, map[string]string{
+		"Upload-Length":   strconv.FormatInt(size, 10),
+		"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte(name)),
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", newAPIError(http.MethodPost, url, resp.StatusCode, resp.Bytes())
+	}
+
+	location, err := resolveLocation(cs.Url+url, resp.Header.Get("Location"))
+	if err != nil {
+		return "", fmt.Errorf("invalid upload location: %w", err)
+	}
+	return location, nil
+}
+
+// resumeUpload returns the location and offset of the unfinished upload
+// recorded at statePath, or "" when there is none the server still knows
+func (cs *GZAPI) resumeUpload(statePath string, size int64) (string, int64) {
+	buf, err := os.ReadFile(statePath) //nolint:gosec // G304: path is derived from the cache directory
+	if err != nil {
+		return "", 0
+	}
+	var state uploadState
+	if err := yaml.Unmarshal(buf, &state); err != nil || state.Location == "" || state.Size != size {
+		_ = os.Remove(statePath)
+		return "", 0
+	}
+
+	resp, err := cs.rawRequest(http.MethodHead, state.Location, opRequest, nil, nil)
+	if err != nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent) {
+		_ = os.Remove(statePath)
+		return "", 0
+	}
+	offset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 || offset > size {
+		_ = os.Remove(statePath)
+		return "", 0
+	}
+	return state.Location, offset
+}
+
+// rawRequest sends a tus request, logging in again once on 401. Unlike
+// doRequest, it leaves the status code to the caller.
+func (cs *GZAPI) rawRequest(method, fullURL string, class opClass, headers map[string]string, body []byte) (*req.Response, error) {
+	if cs == nil || cs.Client == nil {
+		return nil, fmt.Errorf("GZAPI client is not initialized")
+	}
+
+	send := func() (*req.Response, error) {
+		limiter.wait(fullURL)
+		ctx, cancel := cs.requestContext(class)
+		defer cancel()
+		r := cs.newRequest(ctx).SetHeader("Tus-Resumable", tusVersion).SetHeaders(headers)
+		if body != nil {
+			r.SetBodyBytes(body)
+		}
+		return r.Send(method, fullURL)
+	}
+
+	resp, err := send()
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && cs.Creds != nil {
+		if err := cs.reauthenticate(); err != nil {
+			return nil, fmt.Errorf("authentication failed after 401 for %s: %w: %w", fullURL, ErrUnauthorized, err)
+		}
+		resp, err = send()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed for %s: %w", method, fullURL, err)
+	}
+	return resp, nil
+}
+
+// uploadStatePath returns where the state of a resumable upload of file to
+// this server is recorded. The file size and modification time are part of
+// the key, so a changed file starts a new upload.
+func (cs *GZAPI) uploadStatePath(file string, info os.FileInfo) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("determine working directory: %w", err)
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%d\n%d", cs.Url, abs, info.Size(), info.ModTime().UnixNano())))
+	return filepath.Join(cwd, ".gzcli", "cache", "uploads", hex.EncodeToString(key[:16])+".yaml"), nil
+}
+
+func saveUploadState(path string, state uploadState) {
+	buf, err := yaml.Marshal(state)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+			err = os.WriteFile(path, buf, 0o600)
+		}
+	}
+	if err != nil {
+		log.Error("Failed to record upload state, an interrupted upload will restart: %v", err)
+	}
+}
+
+// resolveLocation resolves a Location header against the request URL
+func resolveLocation(requestURL, location string) (string, error) {
+	if location == "" {
+		return "", fmt.Errorf("missing Location header")
+	}
+	base, err := url.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// containsToken reports whether the comma-separated header value list
+// contains token
+func containsToken(list, token string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package gzapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// tusServer is a minimal tus server for /api/assets. One PATCH request
+// writing at offset failAt fails, as if the connection dropped.
+type tusServer struct {
+	mu     sync.Mutex
+	data   []byte
+	length int64
+	failAt int
+}
+
+func (s *tusServer) handlers(t *testing.T) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/api/assets": func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodOptions:
+				w.Header().Set("Tus-Resumable", "1.0.0")
+				w.Header().Set("Tus-Extension", "creation, termination")
+				w.WriteHeader(http.StatusNoContent)
+			case http.MethodPost:
+				length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+				if err != nil {
+					t.Errorf("invalid Upload-Length %q", r.Header.Get("Upload-Length"))
+				}
+				s.mu.Lock()
+				s.length, s.data = length, nil
+				s.mu.Unlock()
+				w.Header().Set("Location", "/api/assets/uploads/1")
+				w.WriteHeader(http.StatusCreated)
+			default:
+				t.Errorf("unexpected %s /api/assets", r.Method)
+			}
+		},
+		"/api/assets/uploads/1": func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			switch r.Method {
+			case http.MethodHead:
+				w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+			case http.MethodPatch:
+				if len(s.data) == s.failAt {
+					s.failAt = -1
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				if r.Header.Get("Upload-Offset") != strconv.Itoa(len(s.data)) {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				chunk, _ := io.ReadAll(r.Body)
+				s.data = append(s.data, chunk...)
+				w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+				if int64(len(s.data)) == s.length {
+					_ = json.NewEncoder(w).Encode([]FileInfo{{Hash: "resumed", Name: "dist.zip"}})
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+		},
+	}
+}
+
+func TestGZAPI_CreateAssets_Resumable(t *testing.T) {
+	t.Chdir(t.TempDir())
+	SetResumableUploads(true)
+	t.Cleanup(func() { SetResumableUploads(false) })
+
+	oldChunkSize := uploadChunkSize
+	uploadChunkSize = 4
+	t.Cleanup(func() { uploadChunkSize = oldChunkSize })
+
+	content := []byte("0123456789abcdef!")
+	file := filepath.Join(t.TempDir(), "dist.zip")
+	if err := os.WriteFile(file, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []int64
+	SetUploadProgress(func(_ string, n, total int64) {
+		if total != int64(len(content)) {
+			t.Errorf("progress total = %d, want %d", total, len(content))
+		}
+		sent = append(sent, n)
+	})
+	t.Cleanup(func() { SetUploadProgress(nil) })
+
+	tus := &tusServer{failAt: 8}
+	server := mockServer(t, tus.handlers(t))
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if _, err := api.CreateAssets(file); err == nil {
+		t.Fatal("CreateAssets() succeeded although the upload was interrupted")
+	}
+	sent = nil
+
+	infos, err := api.CreateAssets(file)
+	if err != nil {
+		t.Fatalf("CreateAssets() resume failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Hash != "resumed" {
+		t.Errorf("CreateAssets() = %v, want the file info of the final chunk", infos)
+	}
+	if !bytes.Equal(tus.data, content) {
+		t.Errorf("server received %q, want %q", tus.data, content)
+	}
+	if len(sent) == 0 || sent[0] != 8 || sent[len(sent)-1] != int64(len(content)) {
+		t.Errorf("progress = %v, want a resume from 8 up to %d", sent, len(content))
+	}
+	if entries, _ := os.ReadDir(filepath.Join(".gzcli", "cache", "uploads")); len(entries) != 0 {
+		t.Errorf("upload state left behind after completion: %v", entries)
+	}
+}
+
+func TestGZAPI_CreateAssets_Progress(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 4096)
+	file := filepath.Join(t.TempDir(), "dist.zip")
+	if err := os.WriteFile(file, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var last, total int64
+	SetUploadProgress(func(_ string, n, size int64) {
+		last, total = n, size
+	})
+	t.Cleanup(func() { SetUploadProgress(nil) })
+
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/assets": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			_, _ = io.Copy(io.Discard, r.Body)
+			_ = json.NewEncoder(w).Encode([]FileInfo{{Hash: "abc", Name: "dist.zip"}})
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := api.CreateAssets(file); err != nil {
+		t.Fatalf("CreateAssets() failed: %v", err)
+	}
+	if last != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("progress = %d/%d, want %d/%d", last, total, len(content), len(content))
+	}
+}
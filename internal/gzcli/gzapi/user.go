@@ -1,16 +1,47 @@
 package gzapi
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Roles of GZCTF users
+const (
+	RoleBanned  = "Banned"
+	RoleUser    = "User"
+	RoleMonitor = "Monitor"
+	RoleAdmin   = "Admin"
+)
+
+// UserRoles returns the roles a user can be given
+func UserRoles() []string {
+	return []string{RoleBanned, RoleUser, RoleMonitor, RoleAdmin}
+}
+
+// userPageSize is the number of users requested per page
+const userPageSize = 100
 
 // User represents a user in the GZCTF platform
 //
 //nolint:revive // Field names match API responses
 type User struct {
-	Id       string `json:"id"`
-	UserName string `json:"username"`
-	Bio      string `json:"bio"`
-	Captain  bool   `json:"captain"`
-	API      *GZAPI `json:"-"`
+	Id             string `json:"id"`
+	UserName       string `json:"username"`
+	Bio            string `json:"bio"`
+	Captain        bool   `json:"captain"`
+	Email          string `json:"email,omitempty"`
+	RealName       string `json:"realName,omitempty"`
+	StdNumber      string `json:"stdNumber,omitempty"`
+	Role           string `json:"role,omitempty"` // One of the Role* constants
+	EmailConfirmed bool   `json:"emailConfirmed,omitempty"`
+	API            *GZAPI `json:"-"`
+}
+
+// userInfoUpdate is the part of the admin user form changed by gzcli; fields
+// left nil are kept
+type userInfoUpdate struct {
+	Role *string `json:"role,omitempty"`
 }
 
 // Delete removes the user from the platform
@@ -21,12 +52,67 @@ func (user *User) Delete() error {
 	return nil
 }
 
-// Users retrieves all users from the platform (admin only)
+// SetRole changes the role of the user to one of the Role* constants
+func (user *User) SetRole(role string) error {
+	if err := user.API.put(fmt.Sprintf("/api/admin/users/%s", user.Id), &userInfoUpdate{Role: &role}, nil); err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
+
+// ResetPassword replaces the password of the user with a random one, which
+// is returned
+func (user *User) ResetPassword() (string, error) {
+	var password string
+	if err := user.API.delete(fmt.Sprintf("/api/admin/users/%s/password", user.Id), &password); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// ListUsers retrieves count users after skipping skip of them, with the
+// number of users on the platform (admin only)
+func (api *GZAPI) ListUsers(skip, count int) ([]*User, int, error) {
+	query := url.Values{}
+	query.Set("count", strconv.Itoa(count))
+	query.Set("skip", strconv.Itoa(skip))
+
+	var users struct {
+		Data  []*User `json:"data"`
+		Total int     `json:"total"`
+	}
+	if err := api.get("/api/admin/users?"+query.Encode(), &users); err != nil {
+		return nil, 0, err
+	}
+	for t := range users.Data {
+		users.Data[t].API = api
+	}
+	return users.Data, users.Total, nil
+}
+
+// Users retrieves all users from the platform, paging through them (admin only)
 func (api *GZAPI) Users() ([]*User, error) {
+	var users []*User
+	for skip := 0; ; skip += userPageSize {
+		page, _, err := api.ListUsers(skip, userPageSize)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+		if len(page) < userPageSize {
+			return users, nil
+		}
+	}
+}
+
+// SearchUsers retrieves the users whose name, email, real name, student
+// number or ID contains hint (admin only)
+func (api *GZAPI) SearchUsers(hint string) ([]*User, error) {
 	var users struct {
 		Data []*User `json:"data"`
 	}
-	if err := api.get("/api/admin/users", &users); err != nil {
+	if err := api.post("/api/admin/users/search?hint="+url.QueryEscape(hint), nil, &users); err != nil {
 		return nil, err
 	}
 	for t := range users.Data {
@@ -35,6 +121,25 @@ func (api *GZAPI) Users() ([]*User, error) {
 	return users.Data, nil
 }
 
+// GetUser retrieves a user by ID (admin only)
+//
+//nolint:revive // Parameter name matches API specification
+func (api *GZAPI) GetUser(userId string) (*User, error) {
+	var profile struct {
+		User
+		UserId string `json:"userId"`
+	}
+	if err := api.get(fmt.Sprintf("/api/admin/users/%s", url.PathEscape(userId)), &profile); err != nil {
+		return nil, err
+	}
+	user := profile.User
+	if user.Id == "" {
+		user.Id = profile.UserId
+	}
+	user.API = api
+	return &user, nil
+}
+
 // JoinGame allows a user/team to join a game
 //
 //nolint:revive // gameId parameter name matches API specification
@@ -77,4 +77,127 @@ func TestUser_Delete(t *testing.T) {
 	}
 }
 
+func TestGZAPI_ListUsers(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/admin/users": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query(); got.Get("skip") != "100" || got.Get("count") != "50" {
+				t.Errorf("Expected skip=100&count=50, got %s", r.URL.RawQuery)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":[{"id":"user1","userName":"alice","email":"alice@example.com","role":"Admin"}],"length":1,"total":151}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	users, total, err := api.ListUsers(100, 50)
+	if err != nil {
+		t.Fatalf("ListUsers() failed: %v", err)
+	}
+	if total != 151 || len(users) != 1 {
+		t.Fatalf("ListUsers() = %d users of %d, want 1 of 151", len(users), total)
+	}
+	if u := users[0]; u.UserName != "alice" || u.Email != "alice@example.com" || u.Role != RoleAdmin || u.API == nil {
+		t.Errorf("ListUsers() user = %+v", u)
+	}
+}
+
+func TestGZAPI_SearchUsers(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/admin/users/search": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" || r.URL.Query().Get("hint") != "bob smith" {
+				t.Errorf("Expected POST with hint 'bob smith', got %s %s", r.Method, r.URL.RawQuery)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":[{"id":"user2","userName":"bob"}],"length":1,"total":1}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	users, err := api.SearchUsers("bob smith")
+	if err != nil {
+		t.Fatalf("SearchUsers() failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Id != "user2" || users[0].API == nil {
+		t.Errorf("SearchUsers() = %+v", users)
+	}
+}
+
+func TestGZAPI_GetUser(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/admin/users/user3": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"userId":"user3","userName":"carol","role":"Monitor"}`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	user, err := api.GetUser("user3")
+	if err != nil {
+		t.Fatalf("GetUser() failed: %v", err)
+	}
+	if user.Id != "user3" || user.UserName != "carol" || user.Role != RoleMonitor {
+		t.Errorf("GetUser() = %+v", user)
+	}
+}
+
+func TestUser_SetRoleAndResetPassword(t *testing.T) {
+	server := mockServer(t, map[string]http.HandlerFunc{
+		"/api/admin/users/user123": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "PUT" {
+				t.Errorf("Expected PUT method, got %s", r.Method)
+			}
+			var form map[string]any
+			json.NewDecoder(r.Body).Decode(&form)
+			if len(form) != 1 || form["role"] != RoleMonitor {
+				t.Errorf("Expected only the role in the form, got %v", form)
+			}
+			w.WriteHeader(http.StatusOK)
+		},
+		"/api/admin/users/user123/password": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "DELETE" {
+				t.Errorf("Expected DELETE method, got %s", r.Method)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`"N3w-Passw0rd"`))
+		},
+	})
+	defer server.Close()
+
+	api, err := Init(server.URL, &Creds{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	user := &User{Id: "user123", Role: RoleUser, API: api}
+
+	if err := user.SetRole(RoleMonitor); err != nil {
+		t.Fatalf("SetRole() failed: %v", err)
+	}
+	if user.Role != RoleMonitor {
+		t.Errorf("Role = %s after SetRole(), want %s", user.Role, RoleMonitor)
+	}
+
+	password, err := user.ResetPassword()
+	if err != nil {
+		t.Fatalf("ResetPassword() failed: %v", err)
+	}
+	if password != "N3w-Passw0rd" {
+		t.Errorf("ResetPassword() = %q", password)
+	}
+}
+
 // Helper functions are in common_test.go
@@ -2,6 +2,7 @@ package gzcli
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,11 +12,14 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 	"github.com/dimasma0305/gzcli/internal/gzcli/event"
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
+	"github.com/dimasma0305/gzcli/internal/gzcli/structure"
 	"github.com/dimasma0305/gzcli/internal/gzcli/team"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher"
 	"github.com/dimasma0305/gzcli/internal/log"
@@ -115,10 +119,18 @@ const DefaultScriptTimeout = challenge.DefaultScriptTimeout
 
 // GZ is the main application struct for GZCTF CLI operations
 type GZ struct {
-	api        *gzapi.GZAPI
-	UpdateGame bool
-	watcher    *watcher.Watcher
-	eventName  string // Store the event name for this instance
+	api         *gzapi.GZAPI
+	UpdateGame  bool
+	FormatYaml  bool                      // Normalize the challenge.yml of successfully synced challenges
+	Concurrency int                       // Challenges synced in parallel; overrides sync.concurrency when positive
+	RateLimit   float64                   // API requests per second; overrides sync.rateLimit when positive
+	Tags        []string                  // Sync only challenges carrying one of these tags
+	Filter      SyncFilter                // Sync only the challenges it selects
+	Policy      challenge.PolicyOverrides // Exemptions from the event's attachment policy
+	ForceUpload bool                      // Attach local attachments even when their hash is unchanged
+	watcher     *watcher.Watcher
+	eventName   string // Store the event name for this instance
+	preflight   *preflightState
 }
 
 // Cache frequently used paths and configurations
@@ -200,23 +212,29 @@ func InitWithEvent(eventName string) (*GZ, error) {
 	return &GZ{api: api, eventName: conf.EventName}, nil
 }
 
-// GenerateStructure generates challenge directory structure from templates
-func (gz *GZ) GenerateStructure() error {
+// GenerateStructure generates challenge directory structure from templates.
+// Challenges without a solver language in challenge.yml get a defaultSolver
+// skeleton, unless defaultSolver is empty. The report holds the outcome of
+// every challenge; a failing challenge does not stop the others.
+func (gz *GZ) GenerateStructure(defaultSolver string) (*structure.Report, error) {
 	appsettings, err := config.GetAppSettings()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	conf := &Config{
 		AppSettings: appsettings,
 	}
 	challenges, err := config.GetChallengesYaml(conf.ToConfigPackage())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Convert to interface for structure package
 	challengeData := make([]challengeDataImpl, len(challenges))
 	for i, c := range challenges {
+		if c.Solver == "" {
+			c.Solver = defaultSolver
+		}
 		challengeData[i] = challengeDataImpl{c}
 	}
 
@@ -246,11 +264,17 @@ func (gz *GZ) Scoreboard2CTFTimeFeed() (*event.CTFTimeFeed, error) {
 
 // Sync synchronizes challenges from local configuration to the GZCTF server
 func (gz *GZ) Sync() error {
-	return gz.syncWithRetry(0)
+	return gz.syncWithRetry(0, "")
+}
+
+// SyncChallenge synchronizes a single challenge, identified by name, to the GZCTF server
+func (gz *GZ) SyncChallenge(name string) error {
+	return gz.syncWithRetry(0, name)
 }
 
-// syncWithRetry is the internal sync implementation with retry logic
-func (gz *GZ) syncWithRetry(retryCount int) error {
+// syncWithRetry is the internal sync implementation with retry logic.
+// If only is non-empty, just the challenge with that name is synced.
+func (gz *GZ) syncWithRetry(retryCount int, only string) error {
 	const maxRetries = 2 // Prevent infinite recursion
 
 	// Step 1: Get configuration
@@ -259,12 +283,24 @@ func (gz *GZ) syncWithRetry(retryCount int) error {
 		return fmt.Errorf("config error: %w", err)
 	}
 
+	gz.applyRateLimit(conf)
+
 	// Step 2: Get local challenge configurations
 	challengesConf, err := config.GetChallengesYaml(conf)
 	if err != nil {
 		return fmt.Errorf("challenges config error: %w", err)
 	}
 
+	if only != "" {
+		challengesConf, err = filterChallengeByName(challengesConf, only)
+		if err != nil {
+			return err
+		}
+	}
+	if challengesConf, err = gz.selectChallenges(conf, challengesConf); err != nil {
+		return err
+	}
+
 	// Step 3: Find the current game on the server
 	games, err := gz.api.GetGames()
 	if err != nil {
@@ -278,7 +314,7 @@ func (gz *GZ) syncWithRetry(retryCount int) error {
 			return fmt.Errorf("game '%s' not found", conf.Event.Title)
 		}
 		_ = DeleteCache(fmt.Sprintf("config-%s", gz.eventName))
-		return gz.syncWithRetry(retryCount + 1)
+		return gz.syncWithRetry(retryCount+1, only)
 	}
 
 	// Step 4: Update game if needed
@@ -292,12 +328,29 @@ func (gz *GZ) syncWithRetry(retryCount int) error {
 	if err := challenge.ValidateChallenges(challengesConf); err != nil {
 		return fmt.Errorf("validation error: %w", err)
 	}
+	if err := validateServiceReferences(conf.EventName, challengesConf); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	policy := challenge.CheckPolicy(challengesConf, gz.Policy)
+	if len(policy.Violations) > 0 {
+		policy.Print(os.Stderr)
+	}
+	if err := policy.Err(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	for i := range challengesConf {
+		challengesConf[i] = gz.Policy.Apply(challengesConf[i])
+		challengesConf[i].ForceUpload = gz.ForceUpload
+	}
 
 	// Step 6: Get remote challenges
 	conf.Event.CS = gz.api
-	remoteChallenges, err := conf.Event.GetChallenges()
-	if err != nil {
-		return fmt.Errorf("API challenges fetch error: %w", err)
+	remoteChallenges, ok := gz.takePreflightChallenges(conf.Event.Id)
+	if !ok || only != "" {
+		remoteChallenges, err = conf.Event.GetChallenges()
+		if err != nil {
+			return fmt.Errorf("API challenges fetch error: %w", err)
+		}
 	}
 
 	remoteChallenges, deleted, err := challenge.RemoveDuplicateChallenges(remoteChallenges, nil)
@@ -311,10 +364,56 @@ func (gz *GZ) syncWithRetry(retryCount int) error {
 		}
 	}
 
-	// Step 7: Process all challenges concurrently
+	// Step 7: Release the challenges whose release time passed while disabled
+	releaseDueChallenges(&conf.Event, challengesConf, remoteChallenges)
+
+	// Step 8: Process all challenges concurrently
 	return gz.processChallenges(conf, challengesConf, remoteChallenges)
 }
 
+// releaseDueChallenges enables in one batch the remote challenges whose
+// release_at passed while no watcher was running to release them, and marks
+// them enabled in remoteChallenges. Failures are logged; they do not stop the sync.
+func releaseDueChallenges(game *gzapi.Game, challengesConf []config.ChallengeYaml, remoteChallenges []gzapi.Challenge) {
+	ids := challenge.DueReleases(challengesConf, remoteChallenges, time.Now())
+	if len(ids) == 0 {
+		return
+	}
+	released, err := challenge.EnableChallenges(game, ids)
+	if err != nil {
+		log.Error("Failed to release challenges whose release time passed: %v", err)
+	}
+	for _, r := range released {
+		log.Info("🚀 Released challenge %s, its release time passed", r.Title)
+		for i := range remoteChallenges {
+			if remoteChallenges[i].Id == r.Id {
+				remoteChallenges[i].IsEnabled = r.IsEnabled
+			}
+		}
+	}
+}
+
+// applyRateLimit limits API requests to the rate from the command line, or
+// else from sync.rateLimit of the server config
+func (gz *GZ) applyRateLimit(conf *config.Config) {
+	rate := conf.Sync.RateLimit
+	if gz.RateLimit > 0 {
+		rate = gz.RateLimit
+	}
+	if rate != gzapi.RateLimit() {
+		gzapi.SetRateLimit(rate, conf.Sync.Burst)
+	}
+	if rate > 0 {
+		log.Debug("Limiting API requests to %.2f/s per host", rate)
+	}
+}
+
+// A challenge whose sync is rejected with 429 is retried after a growing delay
+const (
+	syncRateLimitRetries = 3
+	syncRateLimitBackoff = 5 * time.Second
+)
+
 // processChallenges handles the concurrent processing of challenges
 func (gz *GZ) processChallenges(conf *config.Config, challengesConf []config.ChallengeYaml, remoteChallenges []gzapi.Challenge) error {
 	total := len(challengesConf)
@@ -323,7 +422,11 @@ func (gz *GZ) processChallenges(conf *config.Config, challengesConf []config.Cha
 		return nil
 	}
 
-	workers := resolveSyncWorkerCount(total)
+	concurrency := conf.Sync.Concurrency
+	if gz.Concurrency > 0 {
+		concurrency = gz.Concurrency
+	}
+	workers := resolveSyncWorkerCount(total, concurrency)
 	log.Info("Syncing %d challenges with %d worker(s)...", total, workers)
 
 	var wg sync.WaitGroup
@@ -331,10 +434,23 @@ func (gz *GZ) processChallenges(conf *config.Config, challengesConf []config.Cha
 	jobs := make(chan config.ChallengeYaml, total)
 	var successCount, failureCount, processedCount int32
 
+	deployments := openDeploymentLog()
+	if deployments != nil {
+		defer func() { _ = deployments.Close() }()
+	}
+
 	worker := func() {
 		defer wg.Done()
 		for c := range jobs {
-			err := challenge.SyncChallenge(conf, c, remoteChallenges, gz.api, GetCache, setCache)
+			orchestrator := challenge.NewSyncOrchestrator(conf, c, remoteChallenges, gz.api, GetCache, setCache, nil)
+			err := orchestrator.Execute()
+			for attempt := 1; errors.Is(err, gzapi.ErrRateLimited) && attempt <= syncRateLimitRetries; attempt++ {
+				delay := time.Duration(attempt) * syncRateLimitBackoff
+				log.InfoH3("Rate limited while syncing %s, retrying in %v", c.Name, delay)
+				time.Sleep(delay)
+				orchestrator = challenge.NewSyncOrchestrator(conf, c, remoteChallenges, gz.api, GetCache, setCache, nil)
+				err = orchestrator.Execute()
+			}
 
 			done := atomic.AddInt32(&processedCount, 1)
 			if err != nil {
@@ -344,6 +460,10 @@ func (gz *GZ) processChallenges(conf *config.Config, challengesConf []config.Cha
 				continue
 			}
 
+			if gz.FormatYaml {
+				formatChallengeFile(c)
+			}
+			recordDeployment(deployments, conf.EventName, orchestrator.Provenance())
 			if done%25 == 0 || int(done) == total {
 				log.Info("[%d/%d] Sync progress...", done, total)
 			} else {
@@ -367,13 +487,81 @@ func (gz *GZ) processChallenges(conf *config.Config, challengesConf []config.Cha
 	close(errChan)
 
 	log.Info("Sync completed. Success: %d, Failures: %d", successCount, failureCount)
-	if len(errChan) > 0 {
-		return <-errChan
+	errs := make([]error, 0, len(errChan))
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d challenge(s) failed to sync: %w", len(errs), total, errors.Join(errs...))
 	}
 	return nil
 }
 
-func resolveSyncWorkerCount(total int) int {
+// formatChallengeFile normalizes the challenge.yml of a synced challenge.
+// Failures are logged and never fail the sync.
+func formatChallengeFile(c config.ChallengeYaml) {
+	path := challenge.FindChallengeFile(c.Cwd)
+	if path == "" {
+		return
+	}
+	changed, err := challenge.FormatFile(path, true)
+	if err != nil {
+		log.Error("Failed to format %s: %v", path, err)
+		return
+	}
+	if changed {
+		log.InfoH3("Formatted %s", path)
+	}
+}
+
+// selectChallenges narrows challengesConf to the challenges of the sync's
+// tags and filter
+func (gz *GZ) selectChallenges(conf *config.Config, challengesConf []config.ChallengeYaml) ([]config.ChallengeYaml, error) {
+	if len(gz.Tags) > 0 {
+		challengesConf = filterChallengesByTags(challengesConf, gz.Tags)
+		log.Info("Selected %d challenge(s) tagged %s", len(challengesConf), strings.Join(gz.Tags, ", "))
+	}
+	if gz.Filter.IsEmpty() {
+		return challengesConf, nil
+	}
+
+	eventPath, err := config.GetEventPath(conf.EventName)
+	if err != nil {
+		return nil, err
+	}
+	if challengesConf, err = gz.Filter.Apply(eventPath, challengesConf); err != nil {
+		return nil, fmt.Errorf("sync filter error: %w", err)
+	}
+	log.Info("Selected %d challenge(s) with the sync filters", len(challengesConf))
+	return challengesConf, nil
+}
+
+// filterChallengesByTags narrows challengesConf to the challenges carrying at
+// least one of tags
+func filterChallengesByTags(challengesConf []config.ChallengeYaml, tags []string) []config.ChallengeYaml {
+	filtered := make([]config.ChallengeYaml, 0, len(challengesConf))
+	for _, c := range challengesConf {
+		if challenge.HasAnyTag(c.Tags, tags) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// filterChallengeByName narrows challengesConf to the challenge named name
+func filterChallengeByName(challengesConf []config.ChallengeYaml, name string) ([]config.ChallengeYaml, error) {
+	for _, c := range challengesConf {
+		if c.Name == name {
+			return []config.ChallengeYaml{c}, nil
+		}
+	}
+	return nil, fmt.Errorf("challenge %q not found in event", name)
+}
+
+// resolveSyncWorkerCount returns how many challenges are synced in parallel:
+// configured when positive, else GZCLI_SYNC_WORKERS, else min(4, NumCPU),
+// never more than total
+func resolveSyncWorkerCount(total, configured int) int {
 	if total <= 0 {
 		return 1
 	}
@@ -383,7 +571,9 @@ func resolveSyncWorkerCount(total int) int {
 		workers = cpuCount
 	}
 
-	if raw := strings.TrimSpace(os.Getenv("GZCLI_SYNC_WORKERS")); raw != "" {
+	if configured > 0 {
+		workers = configured
+	} else if raw := strings.TrimSpace(os.Getenv("GZCLI_SYNC_WORKERS")); raw != "" {
 		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
 			workers = parsed
 		} else {
@@ -509,7 +699,7 @@ func (gz *GZ) MustStopWatcher() {
 // CreateTeams creates teams from a CSV file
 func (gz *GZ) CreateTeams(csvURL string, isSendEmail bool, eventID int, inviteCode string, forceInitMapping bool, communicationType string, communicationLink string) error {
 	// Step 1: Get configuration
-	conf, err := getConfigWrapper(gz.api)
+	conf, err := config.GetConfigWithEvent(gz.api, gz.eventName, GetCache, setCache, deleteCacheWrapper, createNewGameWrapper)
 	if err != nil {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
@@ -595,6 +785,14 @@ func (gz *GZ) CreateTeams(csvURL string, isSendEmail bool, eventID int, inviteCo
 					Default: "(Skip)",
 				},
 			},
+			{
+				Name: "division",
+				Prompt: &survey.Select{
+					Message: "Select column for Division (Optional):",
+					Options: append([]string{"(Skip)"}, options...),
+					Default: "(Skip)",
+				},
+			},
 		}
 
 		answers := struct {
@@ -602,6 +800,7 @@ func (gz *GZ) CreateTeams(csvURL string, isSendEmail bool, eventID int, inviteCo
 			Email    string `survey:"email"`
 			TeamName string `survey:"teamname"`
 			Events   string `survey:"events"`
+			Division string `survey:"division"`
 		}{}
 
 		if err := survey.Ask(prompts, &answers); err != nil {
@@ -614,6 +813,9 @@ func (gz *GZ) CreateTeams(csvURL string, isSendEmail bool, eventID int, inviteCo
 		if answers.Events != "(Skip)" {
 			mapping.Events = getOriginalHeader(answers.Events)
 		}
+		if answers.Division != "(Skip)" {
+			mapping.Division = getOriginalHeader(answers.Division)
+		}
 		teamConfig.ColumnMapping = mapping
 
 		// Persist to cache
@@ -623,11 +825,19 @@ func (gz *GZ) CreateTeams(csvURL string, isSendEmail bool, eventID int, inviteCo
 	}
 
 	// Step 4: Load existing team credentials from cache
-	var teamsCredsCache []*team.TeamCreds
-	if err := GetCache("teams_creds", &teamsCredsCache); err != nil {
+	teamsCredsCache, err := LoadTeamsCreds(conf.EventName, conf.Url)
+	if err != nil {
 		log.Info("Could not load team credentials cache: %v", err)
 	}
 
+	// Credentials are written encrypted and scoped to this event and server
+	cacheWriter := func(key string, data interface{}) error {
+		if creds, ok := data.([]*team.TeamCreds); ok && key == legacyTeamsCredsKey {
+			return SaveTeamsCreds(conf.EventName, conf.Url, creds)
+		}
+		return setCache(key, data)
+	}
+
 	// Step 5: Parse CSV and create teams
 	configAdapter := &teamConfigAdapter{
 		conf:       conf,
@@ -643,7 +853,7 @@ func (gz *GZ) CreateTeams(csvURL string, isSendEmail bool, eventID int, inviteCo
 		isSendEmail,
 		team.CreateTeamAndUser,
 		generateUsername,
-		setCache,
+		cacheWriter,
 		team.CommunicationOptions{
 			Type: communicationType,
 			Link: communicationLink,
@@ -679,6 +889,17 @@ func (c challengeDataImpl) GetCwd() string {
 	return c.Cwd
 }
 
+func (c challengeDataImpl) GetSolver() string {
+	return c.Solver
+}
+
+func (c challengeDataImpl) SolverTemplateData() solver.TemplateData {
+	return solver.TemplateData{
+		Name:       c.Name,
+		FlagFormat: solver.FlagFormat(c.Flags, c.Container.FlagTemplate),
+	}
+}
+
 type teamConfigAdapter struct {
 	conf       *config.Config
 	adminAPI   *gzapi.GZAPI // Admin API client for privileged operations
@@ -717,23 +938,45 @@ func (t *teamConfigAdapter) GetInviteCode() string {
 }
 
 func (t *teamConfigAdapter) GetAppSettings() team.AppSettingsInterface {
-	return &appSettingsAdapter{settings: t.conf.Appsettings}
+	return &appSettingsAdapter{settings: t.conf.Appsettings, email: t.conf.Email}
 }
 
 type appSettingsAdapter struct {
 	settings *config.AppSettings
+	email    config.MailConfig // Provider and branding from conf.yaml
 }
 
 func (a *appSettingsAdapter) GetEmailConfig() team.EmailConfig {
+	smtp := a.settings.EmailConfig
+	from := a.email.From
+	if from == "" {
+		from = smtp.SenderAddress
+	}
+	fromName := a.email.FromName
+	if fromName == "" {
+		fromName = smtp.SenderName
+	}
 	return team.EmailConfig{
-		UserName: a.settings.EmailConfig.UserName,
-		Password: a.settings.EmailConfig.Password,
+		UserName: smtp.UserName,
+		Password: smtp.Password,
 		SMTP: struct {
 			Host string
 			Port int
 		}{
-			Host: a.settings.EmailConfig.Smtp.Host,
-			Port: a.settings.EmailConfig.Smtp.Port,
+			Host: smtp.Smtp.Host,
+			Port: smtp.Smtp.Port,
 		},
+		Provider:        a.email.Provider,
+		From:            from,
+		FromName:        fromName,
+		APIKey:          a.email.APIKey,
+		Domain:          a.email.Domain,
+		Region:          a.email.Region,
+		AccessKeyID:     a.email.AccessKeyID,
+		SecretAccessKey: a.email.SecretAccessKey,
+		RateLimit:       a.email.RateLimit,
+		Subject:         a.email.Subject,
+		Template:        a.email.Template,
+		Branding:        team.EmailBranding(a.email.Branding),
 	}
 }
@@ -0,0 +1,202 @@
+// Package gzignore matches paths against .gzignore files, which use the
+// gitignore syntax to keep files of challenges from triggering the watcher.
+package gzignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// FileName is the name of ignore files, read from a challenge directory and
+// each of its parents up to the event directory
+const FileName = ".gzignore"
+
+// DefaultPatterns are ignored everywhere; a .gzignore can re-include them
+// with "!"
+var DefaultPatterns = []string{
+	"node_modules/",
+	"__pycache__/",
+	"*.pyc",
+	"*.swp",
+	"*.swo",
+	"*~",
+	".#*",
+	"#*#",
+	"*.tmp",
+	".DS_Store",
+	"Thumbs.db",
+}
+
+// rule is one pattern of an ignore file
+type rule struct {
+	base     string // Directory of the ignore file
+	pattern  string
+	negate   bool // "!" re-includes matching paths
+	dirOnly  bool // A trailing "/" only matches directories
+	anchored bool // A "/" other than a trailing one matches from base only
+}
+
+// Matcher matches paths against the default patterns and the ignore files
+// added to it. Later rules take precedence, so the ignore file of a challenge
+// overrides the one of its event.
+type Matcher struct {
+	rules []rule
+}
+
+// New returns a matcher of the default patterns, relative to root
+func New(root string) *Matcher {
+	m := &Matcher{}
+	m.addPatterns(root, DefaultPatterns)
+	return m
+}
+
+// ForDir returns a matcher of the ignore files of dir and its parents up to
+// the event directory holding the .gzevent file, or up to the filesystem root
+// outside of events
+func ForDir(dir string) *Matcher {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	dirs := []string{absDir}
+	for current := absDir; ; {
+		if _, err := os.Stat(filepath.Join(current, config.GZEVENT_FILE)); err == nil {
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+		dirs = append(dirs, current)
+	}
+
+	m := New(dirs[len(dirs)-1])
+	for i := len(dirs) - 1; i >= 0; i-- {
+		m.AddFile(dirs[i])
+	}
+	return m
+}
+
+// AddFile adds the ignore file of dir, if any, whose patterns apply below dir
+func (m *Matcher) AddFile(dir string) {
+	//nolint:gosec // G304: Ignore files of the event directory
+	f, err := os.Open(filepath.Join(dir, FileName))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	m.addPatterns(dir, patterns)
+}
+
+// addPatterns parses gitignore patterns applying below base
+func (m *Matcher) addPatterns(base string, patterns []string) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		absBase = base
+	}
+	for _, line := range patterns {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := rule{base: absBase}
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`) // Escaped leading "#" or "!"
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		r.pattern = line
+		m.rules = append(m.rules, r)
+	}
+}
+
+// Match reports whether the path is ignored, either itself or because one of
+// its parent directories is
+func (m *Matcher) Match(p string, isDir bool) bool {
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return false
+	}
+	// A file below an ignored directory cannot be re-included, as in git
+	for parent := filepath.Dir(absPath); parent != filepath.Dir(parent); parent = filepath.Dir(parent) {
+		if m.matchRules(parent, true) {
+			return true
+		}
+	}
+	return m.matchRules(absPath, isDir)
+}
+
+// matchRules applies the rules to the path itself; the last matching rule
+// decides
+func (m *Matcher) matchRules(absPath string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(r.base, absPath)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		var matched bool
+		if r.anchored {
+			matched = matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+		} else {
+			matched, _ = path.Match(r.pattern, path.Base(rel))
+		}
+		if matched {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches any number of segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
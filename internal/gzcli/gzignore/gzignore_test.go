@@ -0,0 +1,68 @@
+package gzignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestForDir(t *testing.T) {
+	eventDir := t.TempDir()
+	challengeDir := filepath.Join(eventDir, "Web", "login")
+	writeFile(t, filepath.Join(eventDir, ".gzevent"), "title: CTF\n")
+	writeFile(t, filepath.Join(eventDir, FileName), "*.log\n**/build/\n")
+	writeFile(t, filepath.Join(challengeDir, FileName), "# Keep the server log\n!server.log\n/notes.md\n")
+
+	m := ForDir(challengeDir)
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"src/app.py", false, false},
+		{"src/debug.log", false, true},
+		{"server.log", false, false},
+		{"src/server.log", false, false},
+		{"notes.md", false, true},
+		{"src/notes.md", false, false},
+		{"src/node_modules", true, true},
+		{"src/node_modules/lib/index.js", false, true},
+		{"src/web/build", true, true},
+		{"src/web/build/app.js", false, true},
+		{"src/build.go", false, false},
+		{"src/.main.py.swp", false, true},
+		{"dist/flag.txt", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(filepath.Join(challengeDir, filepath.FromSlash(tt.path)), tt.isDir); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Patterns of a challenge do not leak to its siblings
+	if m := ForDir(filepath.Join(eventDir, "Web", "other")); m.Match(filepath.Join(eventDir, "Web", "other", "notes.md"), false) {
+		t.Error("notes.md of another challenge is ignored")
+	}
+}
+
+func TestDefaultPatternsCanBeReincluded(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, FileName), "!node_modules/\n")
+
+	if ForDir(dir).Match(filepath.Join(dir, "node_modules", "dep.js"), false) {
+		t.Error("node_modules is ignored despite being re-included")
+	}
+	if !New(dir).Match(filepath.Join(dir, "node_modules", "dep.js"), false) {
+		t.Error("node_modules is not ignored by default")
+	}
+}
@@ -22,13 +22,13 @@ func createNewGameWrapper(conf *config.Config, api *gzapi.GZAPI) (*gzapi.Game, e
 	return challenge.CreateNewGame(conf, api, createPosterIfNotExistOrDifferent, setCache)
 }
 
-func genStructureWrapper(challenges []interface{ GetCwd() string }) error {
+func genStructureWrapper(challenges []interface{ GetCwd() string }) (*structure.Report, error) {
 	// Convert to structure.ChallengeData
 	converted := make([]structure.ChallengeData, len(challenges))
 	for i, c := range challenges {
 		converted[i] = c
 	}
-	return structure.GenerateStructure(converted)
+	return structure.Generate(converted)
 }
 
 // RunScripts executes scripts for all challenges using a worker pool
@@ -0,0 +1,190 @@
+// Package linkcheck finds the URLs in challenge descriptions and checks that
+// they still answer, so broken handout links are caught before players do.
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency is the number of URLs checked at once
+const DefaultConcurrency = 8
+
+// DefaultTimeout bounds each check, redirects included
+const DefaultTimeout = 10 * time.Second
+
+var urlRegex = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// ExtractURLs returns the distinct http(s) URLs of text in order of first
+// appearance. Punctuation ending a sentence or closing Markdown and HTML
+// around a URL is not part of it.
+func ExtractURLs(text string) []string {
+	var urls []string
+	seen := make(map[string]struct{})
+	for _, raw := range urlRegex.FindAllString(text, -1) {
+		u := trimURL(raw)
+		if _, ok := seen[u]; ok || u == "" {
+			continue
+		}
+		seen[u] = struct{}{}
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// trimURL drops trailing punctuation, and closing brackets without an
+// opening one in the URL, e.g. "[handout](https://x/a.zip)."
+func trimURL(u string) string {
+	for u != "" {
+		last := u[len(u)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*_", last) >= 0:
+			u = u[:len(u)-1]
+		case last == ')' && strings.Count(u, "(") < strings.Count(u, ")"),
+			last == ']' && strings.Count(u, "[") < strings.Count(u, "]"):
+			u = u[:len(u)-1]
+		default:
+			return u
+		}
+	}
+	return u
+}
+
+// Result is the outcome of checking a URL
+type Result struct {
+	StatusCode int       `json:"statusCode,omitempty"` // Final status after redirects; 0 if no response
+	Err        string    `json:"err,omitempty"`        // Why the request failed, if it did
+	CheckedAt  time.Time `json:"checkedAt"`
+}
+
+// OK reports whether the URL answered with a 2xx status
+func (r Result) OK() bool {
+	return r.Err == "" && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// String describes the result as the status or the error
+func (r Result) String() string {
+	if r.Err != "" {
+		return r.Err
+	}
+	return fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+}
+
+// Checker checks URLs concurrently. Successful results younger than MaxAge in
+// Cache are reused instead of requesting the URL again; dead links are always
+// checked again.
+type Checker struct {
+	Client      *http.Client
+	Concurrency int
+	Cache       map[string]Result // Updated with every new result
+	MaxAge      time.Duration     // 0 disables reusing cached results
+
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+// NewChecker creates a checker with the default concurrency and timeout
+func NewChecker() *Checker {
+	return &Checker{
+		Client:      &http.Client{Timeout: DefaultTimeout},
+		Concurrency: DefaultConcurrency,
+	}
+}
+
+// Check checks every URL and returns the result of each
+func (c *Checker) Check(ctx context.Context, urls []string) map[string]Result {
+	if c.now == nil {
+		c.now = time.Now
+	}
+	if c.Cache == nil {
+		c.Cache = make(map[string]Result)
+	}
+
+	results := make(map[string]Result, len(urls))
+	var pending []string
+	now := c.now()
+	for _, u := range urls {
+		if _, done := results[u]; done {
+			continue
+		}
+		if cached, ok := c.Cache[u]; ok && cached.OK() && c.MaxAge > 0 && now.Sub(cached.CheckedAt) < c.MaxAge {
+			results[u] = cached
+			continue
+		}
+		results[u] = Result{}
+		pending = append(pending, u)
+	}
+	sort.Strings(pending)
+
+	workers := c.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				r := c.checkURL(ctx, u)
+				c.mu.Lock()
+				results[u] = r
+				c.Cache[u] = r
+				c.mu.Unlock()
+			}
+		}()
+	}
+	for _, u := range pending {
+		jobs <- u
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// checkURL requests u with HEAD, falling back to GET for servers that do not
+// answer HEAD requests properly
+func (c *Checker) checkURL(ctx context.Context, u string) Result {
+	status, err := c.request(ctx, http.MethodHead, u)
+	if err != nil || status == http.StatusMethodNotAllowed || status == http.StatusForbidden || status == http.StatusNotImplemented {
+		status, err = c.request(ctx, http.MethodGet, u)
+	}
+
+	r := Result{StatusCode: status, CheckedAt: c.now()}
+	if err != nil {
+		r.StatusCode = 0
+		r.Err = err.Error()
+	}
+	return r
+}
+
+func (c *Checker) request(ctx context.Context, method, u string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "gzcli-linkcheck")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,113 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtractURLs(t *testing.T) {
+	text := "Download [the handout](https://example.com/handout.zip).\n" +
+		"Docs: <https://example.com/docs?page=1>, see https://en.wikipedia.org/wiki/Go_(programming_language).\n" +
+		"Again https://example.com/handout.zip and `http://inline.example/code`; nothing at ftp://example.com"
+
+	want := []string{
+		"https://example.com/handout.zip",
+		"https://example.com/docs?page=1",
+		"https://en.wikipedia.org/wiki/Go_(programming_language)",
+		"http://inline.example/code",
+	}
+	if got := ExtractURLs(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractURLs() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := ExtractURLs("no links here"); got != nil {
+		t.Errorf("ExtractURLs() without links = %q, want nil", got)
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	var heads, gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads.Add(1)
+		} else {
+			gets.Add(1)
+		}
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewChecker()
+	c.Concurrency = 2
+	urls := []string{srv.URL + "/ok", srv.URL + "/no-head", srv.URL + "/moved", srv.URL + "/gone", srv.URL + "/ok"}
+	results := c.Check(context.Background(), urls)
+
+	for _, path := range []string{"/ok", "/no-head", "/moved"} {
+		if r := results[srv.URL+path]; !r.OK() {
+			t.Errorf("%s: got %s, want OK", path, r)
+		}
+	}
+	if r := results[srv.URL+"/gone"]; r.OK() || r.StatusCode != http.StatusNotFound {
+		t.Errorf("/gone: got %s, want 404", r)
+	}
+	if got := gets.Load(); got != 1 {
+		t.Errorf("GET requests = %d, want 1 (HEAD fallback only)", got)
+	}
+
+	unreachable := c.Check(context.Background(), []string{"http://127.0.0.1:1/closed"})
+	if r := unreachable["http://127.0.0.1:1/closed"]; r.OK() || r.Err == "" {
+		t.Errorf("unreachable: got %+v, want an error", r)
+	}
+}
+
+func TestChecker_Cache(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1000, 0)
+	c := NewChecker()
+	c.MaxAge = time.Hour
+	c.now = func() time.Time { return now }
+	urls := []string{srv.URL + "/ok", srv.URL + "/gone"}
+
+	c.Check(context.Background(), urls)
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("first check: %d requests, want 2", got)
+	}
+
+	// Links that answered are reused, dead ones rechecked
+	c.Check(context.Background(), urls)
+	if got := requests.Load(); got != 3 {
+		t.Errorf("cached check: %d requests, want 3", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	c.Check(context.Background(), urls)
+	if got := requests.Load(); got != 5 {
+		t.Errorf("expired check: %d requests, want 5", got)
+	}
+}
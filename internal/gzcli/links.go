@@ -0,0 +1,117 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/linkcheck"
+)
+
+// linkCacheKey is the cache entry holding link check results across runs
+const linkCacheKey = "link_check"
+
+// ChallengeLinks are the URLs found in the description of a challenge
+type ChallengeLinks struct {
+	Event     string
+	Challenge string
+	Source    string // "local" for challenge.yaml, "remote" for GZCTF
+	URLs      []string
+}
+
+// DeadLink is a URL of a challenge description that did not answer with 2xx
+type DeadLink struct {
+	ChallengeLinks
+	URL    string
+	Result linkcheck.Result
+}
+
+// LocalChallengeLinks returns the URLs in the local challenge descriptions of
+// an event, without contacting the server
+func LocalChallengeLinks(eventName string) ([]ChallengeLinks, error) {
+	challengesConf, err := LoadEventChallenges(eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []ChallengeLinks
+	for _, c := range challengesConf {
+		if urls := linkcheck.ExtractURLs(c.Description); len(urls) > 0 {
+			links = append(links, ChallengeLinks{Event: eventName, Challenge: c.Name, Source: "local", URLs: urls})
+		}
+	}
+	return links, nil
+}
+
+// RemoteChallengeLinks returns the URLs in the descriptions of the event's
+// challenges on the server. An event whose game does not exist yet has none.
+func (gz *GZ) RemoteChallengeLinks() ([]ChallengeLinks, error) {
+	conf, err := config.GetConfigWithEvent(nil, gz.eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+	game := challenge.FindCurrentGame(games, conf.Event.Title, gz.api)
+	if game == nil {
+		return nil, nil
+	}
+	remote, err := game.GetChallenges()
+	if err != nil {
+		return nil, fmt.Errorf("API challenges fetch error: %w", err)
+	}
+
+	var links []ChallengeLinks
+	for _, c := range remote {
+		if urls := linkcheck.ExtractURLs(c.Content); len(urls) > 0 {
+			links = append(links, ChallengeLinks{Event: gz.eventName, Challenge: c.Title, Source: "remote", URLs: urls})
+		}
+	}
+	return links, nil
+}
+
+// CheckLinks checks every URL of links once and returns the dead ones sorted
+// by event, challenge and URL. Results are cached across runs for the checker's
+// MaxAge.
+func CheckLinks(ctx context.Context, checker *linkcheck.Checker, links []ChallengeLinks) []DeadLink {
+	if checker.MaxAge > 0 && checker.Cache == nil {
+		cache := make(map[string]linkcheck.Result)
+		if err := GetCache(linkCacheKey, &cache); err == nil {
+			checker.Cache = cache
+		}
+	}
+
+	var urls []string
+	for _, l := range links {
+		urls = append(urls, l.URLs...)
+	}
+	results := checker.Check(ctx, urls)
+
+	if checker.MaxAge > 0 {
+		_ = setCache(linkCacheKey, checker.Cache)
+	}
+
+	var dead []DeadLink
+	for _, l := range links {
+		for _, u := range l.URLs {
+			if r := results[u]; !r.OK() {
+				dead = append(dead, DeadLink{ChallengeLinks: l, URL: u, Result: r})
+			}
+		}
+	}
+	sort.SliceStable(dead, func(i, j int) bool {
+		a, b := dead[i], dead[j]
+		if a.Event != b.Event {
+			return a.Event < b.Event
+		}
+		if a.Challenge != b.Challenge {
+			return a.Challenge < b.Challenge
+		}
+		return a.URL < b.URL
+	})
+	return dead
+}
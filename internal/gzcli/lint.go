@@ -0,0 +1,52 @@
+package gzcli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// LintEvent validates the local challenge files of an event without
+// contacting the server. Unlike LoadEventChallenges it does not stop at the
+// first file that fails to parse.
+func LintEvent(eventName string) (*challenge.LintReport, error) {
+	conf, err := config.GetConfigWithEvent(nil, eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	config.InitHostCache(conf.Appsettings.ContainerProvider.PublicEntry)
+
+	paths, err := config.ListChallengeFiles(eventName)
+	if err != nil {
+		return nil, err
+	}
+	unknownDirs, err := config.UnknownCategoryDirs(eventName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event directories: %w", err)
+	}
+	eventPath, err := config.GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]challenge.LintFile, 0, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(eventPath, path)
+		if err != nil {
+			rel = path
+		}
+		file := challenge.LintFile{Path: rel}
+		file.Category, _, _ = strings.Cut(filepath.ToSlash(rel), "/")
+		chall, err := config.LoadChallengeFile(eventName, path)
+		if err != nil {
+			file.Err = err
+		} else {
+			file.Challenge = config.ApplyChallengeDefaults(chall, conf.Defaults)
+		}
+		files = append(files, file)
+	}
+	return challenge.Lint(eventName, files, unknownDirs), nil
+}
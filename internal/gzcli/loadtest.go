@@ -0,0 +1,115 @@
+package gzcli
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/loadtest"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// loadTestLoginWorkers bounds concurrent test account logins
+const loadTestLoginWorkers = 16
+
+// LoadTestTargets logs in up to teams test accounts from the team credentials
+// created by "gzcli team create" for the current event, and lists the
+// challenges visible to them with their known static flags
+func (gz *GZ) LoadTestTargets(teams int) ([]loadtest.Participant, []loadtest.Challenge, error) {
+	conf, err := config.GetConfigWithEvent(gz.api, gz.eventName, GetCache, setCache, deleteCacheWrapper, createNewGameWrapper)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	game, err := gz.api.GetGameByTitle(conf.Event.Title)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find game %q: %w", conf.Event.Title, err)
+	}
+
+	creds, err := LoadTeamsCreds(conf.EventName, conf.Url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load team credentials: %w", err)
+	}
+	if len(creds) == 0 {
+		return nil, nil, fmt.Errorf("no team credentials for event %s; create test teams with \"gzcli team create\"", conf.EventName)
+	}
+	if teams > 0 && teams < len(creds) {
+		creds = creds[:teams]
+	} else if teams > len(creds) {
+		log.Error("Only %d team credential(s) available, using all of them instead of %d", len(creds), teams)
+	}
+
+	log.Info("Logging in %d test account(s)...", len(creds))
+	participants := make([]loadtest.Participant, len(creds))
+	loginErrs := make([]error, len(creds))
+	sem := make(chan struct{}, loadTestLoginWorkers)
+	var wg sync.WaitGroup
+	for i, c := range creds {
+		wg.Add(1)
+		go func(i int, username, password string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			api, err := gzapi.Init(conf.Url, &gzapi.Creds{Username: username, Password: password})
+			if err != nil {
+				loginErrs[i] = fmt.Errorf("login %s: %w", username, err)
+				return
+			}
+			participants[i] = &loadtest.GameParticipant{Game: &gzapi.Game{Id: game.Id, CS: api}}
+		}(i, c.Username, c.Password)
+	}
+	wg.Wait()
+
+	var loggedIn []loadtest.Participant
+	for i, p := range participants {
+		if loginErrs[i] != nil {
+			log.Error("%v", loginErrs[i])
+			continue
+		}
+		loggedIn = append(loggedIn, p)
+	}
+	if len(loggedIn) == 0 {
+		return nil, nil, fmt.Errorf("no test account could log in")
+	}
+
+	details, err := loggedIn[0].(*loadtest.GameParticipant).Game.GetDetails()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list challenges as a participant (has the game started and has the team joined?): %w", err)
+	}
+
+	challenges := loadTestChallenges(details, localFlags(conf))
+	if len(challenges) == 0 {
+		return nil, nil, fmt.Errorf("no challenges are visible to participants of %s", conf.Event.Title)
+	}
+	return loggedIn, challenges, nil
+}
+
+// localFlags maps challenge names to their static flags from challenge.yml
+func localFlags(conf *config.Config) map[string][]string {
+	flags := make(map[string][]string)
+	challenges, err := config.GetChallengesYaml(conf)
+	if err != nil {
+		log.Error("Failed to read local challenges, only wrong flags will be submitted: %v", err)
+		return flags
+	}
+	for _, c := range challenges {
+		if len(c.Flags) > 0 {
+			flags[c.Name] = c.Flags
+		}
+	}
+	return flags
+}
+
+func loadTestChallenges(details *gzapi.GameDetails, flags map[string][]string) []loadtest.Challenge {
+	var challenges []loadtest.Challenge
+	for _, list := range details.Challenges {
+		for _, c := range list {
+			challenges = append(challenges, loadtest.Challenge{ID: c.Id, Title: c.Title, Flags: flags[c.Title]})
+		}
+	}
+	sort.Slice(challenges, func(i, j int) bool { return challenges[i].ID < challenges[j].ID })
+	return challenges
+}
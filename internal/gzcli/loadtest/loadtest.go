@@ -0,0 +1,214 @@
+// Package loadtest drives flag submissions and scoreboard reads against a
+// GZCTF game with test accounts and reports latency percentiles and error
+// rates, to size a deployment before an event
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// Operation names used in reports
+const (
+	OpSubmit     = "submit"
+	OpJudge      = "judge"
+	OpScoreboard = "scoreboard"
+)
+
+// Participant is one logged-in test team
+type Participant interface {
+	SubmitFlag(challengeID int, flag string) (int, error)
+	GetSubmissionStatus(challengeID, submissionID int) (string, error)
+	GetScoreboard() error
+}
+
+// GameParticipant adapts a game bound to a test account's API client
+type GameParticipant struct {
+	Game *gzapi.Game
+}
+
+// SubmitFlag implements Participant
+func (p *GameParticipant) SubmitFlag(challengeID int, flag string) (int, error) {
+	return p.Game.SubmitFlag(challengeID, flag)
+}
+
+// GetSubmissionStatus implements Participant
+func (p *GameParticipant) GetSubmissionStatus(challengeID, submissionID int) (string, error) {
+	return p.Game.GetSubmissionStatus(challengeID, submissionID)
+}
+
+// GetScoreboard implements Participant
+func (p *GameParticipant) GetScoreboard() error {
+	_, err := p.Game.GetScoreboard()
+	return err
+}
+
+// Challenge is a submission target. Flags holds the known correct static
+// flags, used for the CorrectRatio share of submissions.
+type Challenge struct {
+	ID    int
+	Title string
+	Flags []string
+}
+
+// Options configures a load test run
+type Options struct {
+	RPS                float64       // Target flag submissions per second across all teams
+	Duration           time.Duration // How long to submit
+	ScoreboardInterval time.Duration // Scoreboard read interval, 0 disables
+	CorrectRatio       float64       // Share of submissions using a correct flag (0-1)
+	JudgeTimeout       time.Duration // How long to poll a submission's judgement, 0 disables
+	PollInterval       time.Duration // Judgement polling interval
+	MaxInFlight        int           // Outstanding submissions before ticks are skipped
+}
+
+// DefaultOptions returns options for a one minute test at 50 submissions per second
+func DefaultOptions() Options {
+	return Options{
+		RPS:                50,
+		Duration:           time.Minute,
+		ScoreboardInterval: 2 * time.Second,
+		JudgeTimeout:       10 * time.Second,
+		PollInterval:       250 * time.Millisecond,
+		MaxInFlight:        500,
+	}
+}
+
+// Validate checks that the options describe a runnable test
+func (o Options) Validate() error {
+	switch {
+	case o.RPS <= 0:
+		return fmt.Errorf("rps must be positive")
+	case o.Duration <= 0:
+		return fmt.Errorf("duration must be positive")
+	case o.CorrectRatio < 0 || o.CorrectRatio > 1:
+		return fmt.Errorf("correct ratio must be between 0 and 1")
+	case o.MaxInFlight <= 0:
+		return fmt.Errorf("max in-flight must be positive")
+	case o.JudgeTimeout > 0 && o.PollInterval <= 0:
+		return fmt.Errorf("poll interval must be positive when judging is enabled")
+	}
+	return nil
+}
+
+// Run submits flags at opts.RPS, spreading submissions round-robin over the
+// participants and randomly over the challenges, until opts.Duration elapses
+// or ctx is cancelled. In-flight requests are awaited before reporting.
+func Run(ctx context.Context, participants []Participant, challenges []Challenge, opts Options) (*Report, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("no participants")
+	}
+	if len(challenges) == 0 {
+		return nil, fmt.Errorf("no challenges to submit to")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	rec := newRecorder()
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	if opts.ScoreboardInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scoreboardLoop(ctx, participants, opts.ScoreboardInterval, rec)
+		}()
+	}
+
+	sem := make(chan struct{}, opts.MaxInFlight)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RPS))
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return rec.report(time.Since(started), len(participants), opts.RPS), nil
+		case <-ticker.C:
+		}
+
+		select {
+		case sem <- struct{}{}:
+		default:
+			rec.skip()
+			continue
+		}
+
+		participant := participants[i%len(participants)]
+		//nolint:gosec // G404: Target selection does not need a secure source
+		challenge := challenges[rand.IntN(len(challenges))]
+		flag := pickFlag(challenge, opts.CorrectRatio)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			submitOnce(participant, challenge, flag, opts, rec)
+		}()
+	}
+}
+
+// pickFlag returns a correct flag for a CorrectRatio share of submissions
+// when one is known, otherwise a random wrong flag
+func pickFlag(challenge Challenge, correctRatio float64) string {
+	//nolint:gosec // G404: Flag selection does not need a secure source
+	if len(challenge.Flags) > 0 && rand.Float64() < correctRatio {
+		return challenge.Flags[rand.IntN(len(challenge.Flags))]
+	}
+	//nolint:gosec // G404: Wrong flags only need to be distinct
+	return fmt.Sprintf("loadtest{%016x}", rand.Uint64())
+}
+
+func submitOnce(p Participant, challenge Challenge, flag string, opts Options, rec *recorder) {
+	start := time.Now()
+	submissionID, err := p.SubmitFlag(challenge.ID, flag)
+	rec.record(OpSubmit, time.Since(start), err)
+	if err != nil || opts.JudgeTimeout <= 0 {
+		return
+	}
+
+	// Judgement latency covers the time from submission until the flag was checked
+	deadline := start.Add(opts.JudgeTimeout)
+	for {
+		status, err := p.GetSubmissionStatus(challenge.ID, submissionID)
+		if err != nil {
+			rec.record(OpJudge, time.Since(start), err)
+			return
+		}
+		if status != gzapi.AnswerFlagSubmitted {
+			rec.record(OpJudge, time.Since(start), nil)
+			rec.result(status)
+			return
+		}
+		if time.Now().After(deadline) {
+			rec.record(OpJudge, time.Since(start), fmt.Errorf("judgement timeout after %s", opts.JudgeTimeout))
+			return
+		}
+		time.Sleep(opts.PollInterval)
+	}
+}
+
+func scoreboardLoop(ctx context.Context, participants []Participant, interval time.Duration, rec *recorder) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		start := time.Now()
+		err := participants[i%len(participants)].GetScoreboard()
+		rec.record(OpScoreboard, time.Since(start), err)
+	}
+}
@@ -0,0 +1,157 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+type fakeParticipant struct {
+	mu          sync.Mutex
+	submissions map[int]string
+	nextID      atomic.Int64
+	failEvery   int64
+	scoreboards atomic.Int64
+}
+
+func newFakeParticipant(failEvery int64) *fakeParticipant {
+	return &fakeParticipant{submissions: make(map[int]string), failEvery: failEvery}
+}
+
+func (f *fakeParticipant) SubmitFlag(_ int, flag string) (int, error) {
+	id := int(f.nextID.Add(1))
+	if f.failEvery > 0 && int64(id)%f.failEvery == 0 {
+		return 0, errors.New("request end with 429 status, Too Many Requests")
+	}
+	f.mu.Lock()
+	f.submissions[id] = flag
+	f.mu.Unlock()
+	return id, nil
+}
+
+func (f *fakeParticipant) GetSubmissionStatus(_, submissionID int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.submissions[submissionID] == "flag{correct}" {
+		return gzapi.AnswerAccepted, nil
+	}
+	return gzapi.AnswerWrongAnswer, nil
+}
+
+func (f *fakeParticipant) GetScoreboard() error {
+	f.scoreboards.Add(1)
+	return nil
+}
+
+func TestRun_ReportsSubmissionsAndErrors(t *testing.T) {
+	p1, p2 := newFakeParticipant(5), newFakeParticipant(5)
+	opts := DefaultOptions()
+	opts.RPS = 200
+	opts.Duration = 300 * time.Millisecond
+	opts.ScoreboardInterval = 50 * time.Millisecond
+	opts.PollInterval = time.Millisecond
+	opts.CorrectRatio = 1
+
+	report, err := Run(context.Background(), []Participant{p1, p2}, []Challenge{{ID: 1, Flags: []string{"flag{correct}"}}}, opts)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	submit, ok := report.Operation(OpSubmit)
+	if !ok || submit.Requests < 10 {
+		t.Fatalf("expected submissions to be recorded, got %+v", submit)
+	}
+	if submit.Errors == 0 || submit.ErrorKind["HTTP 429"] != submit.Errors {
+		t.Errorf("expected HTTP 429 errors, got %+v", submit.ErrorKind)
+	}
+	if p1.nextID.Load() == 0 || p2.nextID.Load() == 0 {
+		t.Error("expected submissions to be spread over both participants")
+	}
+
+	judge, ok := report.Operation(OpJudge)
+	if !ok || judge.Requests != submit.Requests-submit.Errors {
+		t.Errorf("judge requests = %d, want %d", judge.Requests, submit.Requests-submit.Errors)
+	}
+	if report.Results[gzapi.AnswerAccepted] != judge.Requests {
+		t.Errorf("results = %v, want all accepted", report.Results)
+	}
+
+	if _, ok := report.Operation(OpScoreboard); !ok || p1.scoreboards.Load()+p2.scoreboards.Load() == 0 {
+		t.Error("expected scoreboard reads")
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	for _, want := range []string{"OPERATION", "submit", "judge", "scoreboard", "HTTP 429", "Accepted"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRun_Validation(t *testing.T) {
+	p := []Participant{newFakeParticipant(0)}
+	c := []Challenge{{ID: 1}}
+
+	opts := DefaultOptions()
+	opts.RPS = 0
+	if _, err := Run(context.Background(), p, c, opts); err == nil {
+		t.Error("expected error for zero rps")
+	}
+	if _, err := Run(context.Background(), nil, c, DefaultOptions()); err == nil {
+		t.Error("expected error without participants")
+	}
+	if _, err := Run(context.Background(), p, nil, DefaultOptions()); err == nil {
+		t.Error("expected error without challenges")
+	}
+}
+
+func TestPickFlag(t *testing.T) {
+	c := Challenge{Flags: []string{"flag{a}"}}
+	if got := pickFlag(c, 1); got != "flag{a}" {
+		t.Errorf("pickFlag(ratio 1) = %q, want flag{a}", got)
+	}
+	if got := pickFlag(c, 0); !strings.HasPrefix(got, "loadtest{") {
+		t.Errorf("pickFlag(ratio 0) = %q, want a wrong flag", got)
+	}
+	if got := pickFlag(Challenge{}, 1); !strings.HasPrefix(got, "loadtest{") {
+		t.Errorf("pickFlag without known flags = %q, want a wrong flag", got)
+	}
+}
+
+func TestPercentileMs(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tests := map[float64]float64{50: 50, 95: 95, 99: 99, 100: 100}
+	for p, want := range tests {
+		if got := percentileMs(sorted, p); got != want {
+			t.Errorf("percentileMs(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentileMs(nil, 50); got != 0 {
+		t.Errorf("percentileMs(nil) = %v, want 0", got)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := map[string]string{
+		"request end with 503 status, Service Unavailable": "HTTP 503",
+		"judgement timeout after 10s":                      "timeout",
+		"dial tcp: connection refused":                     "connection",
+		"something else":                                   "other",
+	}
+	for msg, want := range tests {
+		if got := classifyError(errors.New(msg)); got != want {
+			t.Errorf("classifyError(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
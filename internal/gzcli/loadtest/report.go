@@ -0,0 +1,239 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// Report summarizes a load test run
+type Report struct {
+	DurationSeconds float64          `json:"duration_seconds"`
+	Teams           int              `json:"teams"`
+	TargetRPS       float64          `json:"target_rps"`
+	Skipped         int              `json:"skipped"` // Submissions not sent because MaxInFlight were outstanding
+	Operations      []OperationStats `json:"operations"`
+	Results         map[string]int   `json:"results,omitempty"` // Judgement results by name
+	opsByName       map[string]*OperationStats
+}
+
+// OperationStats holds latency percentiles and errors for one operation
+type OperationStats struct {
+	Name      string         `json:"name"`
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	ErrorRate float64        `json:"error_rate"`
+	RPS       float64        `json:"rps"`
+	P50Ms     float64        `json:"p50_ms"`
+	P90Ms     float64        `json:"p90_ms"`
+	P95Ms     float64        `json:"p95_ms"`
+	P99Ms     float64        `json:"p99_ms"`
+	MaxMs     float64        `json:"max_ms"`
+	ErrorKind map[string]int `json:"error_kinds,omitempty"`
+}
+
+// Operation returns the stats of the named operation, if it ran
+func (r *Report) Operation(name string) (OperationStats, bool) {
+	op, ok := r.opsByName[name]
+	if !ok {
+		return OperationStats{}, false
+	}
+	return *op, true
+}
+
+// Print writes a human-readable summary of the report
+func (r *Report) Print(w io.Writer) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Load test: %d teams, target %.1f submissions/s, %.1fs\n", r.Teams, r.TargetRPS, r.DurationSeconds)
+	fmt.Fprintf(&b, "%-11s %9s %8s %7s %8s %9s %9s %9s %9s %9s\n", "OPERATION", "REQUESTS", "ERRORS", "ERR%", "RPS", "P50", "P90", "P95", "P99", "MAX")
+	for _, op := range r.Operations {
+		fmt.Fprintf(&b, "%-11s %9d %8d %6.2f%% %8.1f %9s %9s %9s %9s %9s\n",
+			op.Name, op.Requests, op.Errors, op.ErrorRate*100, op.RPS,
+			formatMs(op.P50Ms), formatMs(op.P90Ms), formatMs(op.P95Ms), formatMs(op.P99Ms), formatMs(op.MaxMs))
+	}
+
+	for _, op := range r.Operations {
+		if len(op.ErrorKind) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s errors:\n", op.Name)
+		for _, kind := range sortedKeys(op.ErrorKind) {
+			fmt.Fprintf(&b, "  %-24s %d\n", kind, op.ErrorKind[kind])
+		}
+	}
+
+	if len(r.Results) > 0 {
+		b.WriteString("\nJudgement results:\n")
+		for _, result := range sortedKeys(r.Results) {
+			fmt.Fprintf(&b, "  %-24s %d\n", result, r.Results[result])
+		}
+	}
+	if r.Skipped > 0 {
+		fmt.Fprintf(&b, "\n⚠️  %d submissions skipped: too many requests in flight, the platform could not keep up with the target rate\n", r.Skipped)
+	}
+
+	_, _ = io.WriteString(w, b.String())
+}
+
+func formatMs(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+type opSamples struct {
+	latencies []time.Duration
+	errors    int
+	errorKind map[string]int
+}
+
+// recorder collects samples from concurrent requests
+type recorder struct {
+	mu      sync.Mutex
+	ops     map[string]*opSamples
+	results map[string]int
+	skipped int
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		ops:     make(map[string]*opSamples),
+		results: make(map[string]int),
+	}
+}
+
+func (r *recorder) record(op string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.ops[op]
+	if !ok {
+		s = &opSamples{errorKind: make(map[string]int)}
+		r.ops[op] = s
+	}
+	s.latencies = append(s.latencies, latency)
+	if err != nil {
+		s.errors++
+		s.errorKind[classifyError(err)]++
+	}
+}
+
+func (r *recorder) result(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[status]++
+}
+
+func (r *recorder) skip() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skipped++
+}
+
+var operationOrder = map[string]int{OpSubmit: 0, OpJudge: 1, OpScoreboard: 2}
+
+func (r *recorder) report(elapsed time.Duration, teams int, targetRPS float64) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seconds := elapsed.Seconds()
+	report := &Report{
+		DurationSeconds: math.Round(seconds*10) / 10,
+		Teams:           teams,
+		TargetRPS:       targetRPS,
+		Skipped:         r.skipped,
+		opsByName:       make(map[string]*OperationStats),
+	}
+	if len(r.results) > 0 {
+		report.Results = make(map[string]int, len(r.results))
+		for k, v := range r.results {
+			report.Results[k] = v
+		}
+	}
+
+	for name, s := range r.ops {
+		sorted := append([]time.Duration(nil), s.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		op := OperationStats{
+			Name:     name,
+			Requests: len(sorted),
+			Errors:   s.errors,
+			P50Ms:    percentileMs(sorted, 50),
+			P90Ms:    percentileMs(sorted, 90),
+			P95Ms:    percentileMs(sorted, 95),
+			P99Ms:    percentileMs(sorted, 99),
+			MaxMs:    percentileMs(sorted, 100),
+		}
+		if op.Requests > 0 {
+			op.ErrorRate = float64(op.Errors) / float64(op.Requests)
+		}
+		if seconds > 0 {
+			op.RPS = float64(op.Requests) / seconds
+		}
+		if len(s.errorKind) > 0 {
+			op.ErrorKind = make(map[string]int, len(s.errorKind))
+			for k, v := range s.errorKind {
+				op.ErrorKind[k] = v
+			}
+		}
+		report.Operations = append(report.Operations, op)
+	}
+	sort.Slice(report.Operations, func(i, j int) bool {
+		return operationOrder[report.Operations[i].Name] < operationOrder[report.Operations[j].Name]
+	})
+	for i := range report.Operations {
+		report.opsByName[report.Operations[i].Name] = &report.Operations[i]
+	}
+	return report
+}
+
+// percentileMs returns the nearest-rank percentile of sorted latencies in milliseconds
+func percentileMs(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return float64(sorted[rank-1].Microseconds()) / 1000
+}
+
+// statusPattern matches API errors that were flattened to text
+var statusPattern = regexp.MustCompile(`request end with (\d+) status`)
+
+// classifyError groups errors by HTTP status or failure kind
+func classifyError(err error) string {
+	if status := gzapi.StatusCode(err); status != 0 {
+		return fmt.Sprintf("HTTP %d", status)
+	}
+	msg := err.Error()
+	if m := statusPattern.FindStringSubmatch(msg); m != nil {
+		return "HTTP " + m[1]
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), strings.Contains(msg, "timeout"):
+		return "timeout"
+	case strings.Contains(msg, "connection refused"), strings.Contains(msg, "connection reset"):
+		return "connection"
+	default:
+		return "other"
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
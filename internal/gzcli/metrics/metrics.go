@@ -0,0 +1,144 @@
+// Package metrics exports the metrics of the launcher, the watcher and the
+// GZCTF API client in the Prometheus text exposition format. Each component
+// registers a collector, so a process running several of them serves all
+// their metrics on one /metrics endpoint.
+package metrics
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Collector writes its metrics in the Prometheus text exposition format
+type Collector interface {
+	Collect(w io.Writer) error
+}
+
+// CollectorFunc adapts a function to a Collector
+type CollectorFunc func(w io.Writer) error
+
+// Collect implements Collector
+func (f CollectorFunc) Collect(w io.Writer) error { return f(w) }
+
+// Registry holds the collectors exported together
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]Collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// Default is the registry of the running process
+var Default = NewRegistry()
+
+// Register adds a collector under name, replacing one registered before
+func (r *Registry) Register(name string, c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[name] = c
+}
+
+// Unregister removes the collector registered under name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.collectors, name)
+}
+
+// Export writes the metrics of every collector, ordered by name
+func (r *Registry) Export(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]Collector, len(names))
+	for i, name := range names {
+		collectors[i] = r.collectors[name]
+	}
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		if err := c.Collect(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the metrics of the registry to Prometheus. A non-empty
+// token is required as a bearer token.
+func (r *Registry) Handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if token != "" {
+			got, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.Export(w); err != nil {
+			log.Error("Failed to write metrics: %v", err)
+		}
+	})
+}
+
+// WriteHeader writes the HELP and TYPE lines of a metric
+func WriteHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// Histogram is a Prometheus histogram with cumulative-on-export buckets.
+// It is not safe for concurrent use; callers guard it with their own lock.
+type Histogram struct {
+	buckets []float64 // Upper bounds, ascending
+	counts  []uint64  // Observations per bucket, +Inf last
+	sum     float64
+	count   uint64
+}
+
+// NewHistogram creates an empty histogram with the given ascending upper
+// bounds
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets)+1)}
+}
+
+// Observe records a value
+func (h *Histogram) Observe(v float64) {
+	h.counts[sort.SearchFloat64s(h.buckets, v)]++
+	h.sum += v
+	h.count++
+}
+
+// Write writes the bucket, sum and count series of the histogram. labels
+// are the labels of every series, e.g. `operation="start"`, or empty.
+func (h *Histogram) Write(b *strings.Builder, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(b, "%s_bucket{%s%sle=\"%g\"} %d\n", name, labels, sep, bound, cumulative)
+	}
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %g\n", name, labels, h.sum)
+	fmt.Fprintf(b, "%s_count%s %d\n", name, labels, h.count)
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Export(t *testing.T) {
+	r := NewRegistry()
+	r.Register("b", CollectorFunc(func(w io.Writer) error {
+		_, err := io.WriteString(w, "b 2\n")
+		return err
+	}))
+	r.Register("a", CollectorFunc(func(w io.Writer) error {
+		_, err := io.WriteString(w, "a 1\n")
+		return err
+	}))
+	r.Register("c", CollectorFunc(func(w io.Writer) error {
+		_, err := io.WriteString(w, "c 3\n")
+		return err
+	}))
+	r.Unregister("c")
+
+	var b strings.Builder
+	if err := r.Export(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "a 1\nb 2\n" {
+		t.Fatalf("Export = %q", got)
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.Register("a", CollectorFunc(func(w io.Writer) error {
+		_, err := io.WriteString(w, "a 1\n")
+		return err
+	}))
+
+	get := func(h http.Handler, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get(r.Handler(""), ""); rec.Code != http.StatusOK || rec.Body.String() != "a 1\n" {
+		t.Fatalf("open handler = %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := get(r.Handler("secret"), "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong token: status = %d, want 401", rec.Code)
+	}
+	if rec := get(r.Handler("secret"), "secret"); rec.Code != http.StatusOK {
+		t.Fatalf("token: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestHistogram_Write(t *testing.T) {
+	h := NewHistogram([]float64{1, 5})
+	h.Observe(0.5)
+	h.Observe(3)
+	h.Observe(10)
+
+	var b strings.Builder
+	h.Write(&b, "x_seconds", `op="a"`)
+	want := `x_seconds_bucket{op="a",le="1"} 1
+x_seconds_bucket{op="a",le="5"} 2
+x_seconds_bucket{op="a",le="+Inf"} 3
+x_seconds_sum{op="a"} 13.5
+x_seconds_count{op="a"} 3
+`
+	if b.String() != want {
+		t.Fatalf("Write =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	NewHistogram([]float64{1}).Write(&b, "y", "")
+	if !strings.Contains(b.String(), "y_bucket{le=\"1\"} 0\n") || !strings.Contains(b.String(), "y_count 0\n") {
+		t.Fatalf("unlabelled Write =\n%s", b.String())
+	}
+}
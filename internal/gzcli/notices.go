@@ -0,0 +1,80 @@
+package gzcli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// EventGame returns the game of the event on the server, to manage its
+// notices and settings
+func (gz *GZ) EventGame() (*gzapi.Game, error) {
+	conf, err := config.GetConfigWithEvent(nil, gz.eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+	game := challenge.FindCurrentGame(games, conf.Event.Title, gz.api)
+	if game == nil {
+		return nil, fmt.Errorf("game %q of event %s not found on the server", conf.Event.Title, gz.eventName)
+	}
+	return game, nil
+}
+
+// openNoticeSchedule opens the watcher database the scheduled notices are
+// stored in, a file or a postgres:// URL
+func openNoticeSchedule(dbPath string) (*database.DB, error) {
+	db := database.New(dbPath, true)
+	if err := db.Init(); err != nil {
+		return nil, fmt.Errorf("failed to open watcher database: %w", err)
+	}
+	return db, nil
+}
+
+// ScheduleNotice stores a notice of the event in the watcher database at
+// dbPath; the watcher of the event posts it at postAt
+func (gz *GZ) ScheduleNotice(dbPath, content string, postAt time.Time) (watchertypes.ScheduledNotice, error) {
+	db, err := openNoticeSchedule(dbPath)
+	if err != nil {
+		return watchertypes.ScheduledNotice{}, err
+	}
+	defer func() { _ = db.Close() }()
+	return db.ScheduleNotice(watchertypes.ScheduledNotice{Event: gz.eventName, Content: content, PostAt: postAt})
+}
+
+// ScheduledNotices returns the scheduled notices of the event stored in the
+// watcher database at dbPath, posted ones included, by posting time
+func (gz *GZ) ScheduledNotices(dbPath string) ([]watchertypes.ScheduledNotice, error) {
+	db, err := openNoticeSchedule(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	return db.GetScheduledNotices(gz.eventName, false)
+}
+
+// CancelScheduledNotice deletes a scheduled notice of the event that was not
+// posted yet from the watcher database at dbPath
+func (gz *GZ) CancelScheduledNotice(dbPath string, id int64) error {
+	db, err := openNoticeSchedule(dbPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	deleted, err := db.CancelScheduledNotice(gz.eventName, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("no pending scheduled notice %d in event %s", id, gz.eventName)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package gzcli
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// preflightState keeps the remote challenges fetched by Preflight so the
+// following Sync does not fetch them again
+type preflightState struct {
+	gameID     int
+	challenges []gzapi.Challenge
+}
+
+// PreflightResult is the outcome of Preflight
+type PreflightResult struct {
+	Estimate *challenge.SyncEstimate
+	Policy   *challenge.PolicyReport
+	// GameMissing is set when the event's game does not exist on the server
+	// yet; Sync will create it and every challenge
+	GameMissing bool
+}
+
+// Preflight prepares a Sync of the current event without changing anything
+// on the server. It concurrently fetches the games list (which requires
+// admin credentials), the event's challenges with full details and the
+// server's asset list, loads the local challenges, and estimates the API
+// calls and upload bytes the sync will need, and checks the attachment
+// policy. The fetched challenges and assets are reused by the next Sync.
+func (gz *GZ) Preflight() (*PreflightResult, error) {
+	conf, err := config.GetConfigWithEvent(nil, gz.eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
+	var (
+		wg                   sync.WaitGroup
+		game                 *gzapi.Game
+		remote               []gzapi.Challenge
+		hasAsset             func(string) bool
+		challengesConf       []config.ChallengeYaml
+		remoteErr, assetsErr error
+		localErr             error
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		games, err := gz.api.GetGames()
+		if err != nil {
+			remoteErr = fmt.Errorf("credentials check failed (listing games needs an admin account): %w", err)
+			return
+		}
+		game = challenge.FindCurrentGame(games, conf.Event.Title, gz.api)
+		if game == nil {
+			return
+		}
+		remote, err = game.GetChallenges()
+		if err != nil {
+			remoteErr = fmt.Errorf("API challenges fetch error: %w", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		hasAsset, assetsErr = challenge.WarmAssetsCache(gz.api)
+	}()
+	go func() {
+		defer wg.Done()
+		challengesConf, localErr = config.GetChallengesYaml(conf)
+	}()
+	wg.Wait()
+
+	if remoteErr != nil {
+		return nil, remoteErr
+	}
+	if assetsErr != nil {
+		return nil, fmt.Errorf("assets fetch error: %w", assetsErr)
+	}
+	if localErr != nil {
+		return nil, fmt.Errorf("challenges config error: %w", localErr)
+	}
+	if challengesConf, localErr = gz.selectChallenges(conf, challengesConf); localErr != nil {
+		return nil, localErr
+	}
+
+	if game != nil {
+		gz.preflight = &preflightState{gameID: game.Id, challenges: remote}
+	}
+
+	return &PreflightResult{
+		Estimate:    challenge.EstimateSync(challengesConf, remote, hasAsset),
+		Policy:      challenge.CheckPolicy(challengesConf, gz.Policy),
+		GameMissing: game == nil,
+	}, nil
+}
+
+// takePreflightChallenges returns the challenges fetched by Preflight for
+// gameID, once
+func (gz *GZ) takePreflightChallenges(gameID int) ([]gzapi.Challenge, bool) {
+	state := gz.preflight
+	gz.preflight = nil
+	if state == nil || state.gameID != gameID {
+		return nil, false
+	}
+	return state.challenges, true
+}
@@ -0,0 +1,136 @@
+package gzcli
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/team"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// PruneTeams diffs a CSV import against the accounts created by earlier
+// imports of the current event: accounts whose email left the CSV are
+// deleted with their team, and accounts whose real name or team name changed
+// are deleted and created again with new credentials. Teams and users not
+// created by "gzcli team create" are never touched. With dryRun, the plan is
+// returned without changes.
+func (gz *GZ) PruneTeams(csvURL string, dryRun, isSendEmail bool) (*team.PrunePlan, error) {
+	conf, err := config.GetConfigWithEvent(gz.api, gz.eventName, GetCache, setCache, deleteCacheWrapper, createNewGameWrapper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	var teamConfig team.Config
+	if err := GetCache("teams_config", &teamConfig); err != nil || teamConfig.ColumnMapping.Email == "" {
+		return nil, fmt.Errorf("no CSV column mapping cached, run 'gzcli team create' first")
+	}
+
+	credsCache, err := LoadTeamsCreds(conf.EventName, conf.Url)
+	if err != nil {
+		return nil, fmt.Errorf("no team credentials cached for event %s: %w", conf.EventName, err)
+	}
+
+	csvData, err := team.GetData(csvURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSV data: %w", err)
+	}
+	rows, err := team.ReadRows(csvData, &teamConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := team.PlanPrune(rows, credsCache)
+	if dryRun {
+		return plan, nil
+	}
+
+	teams, err := gz.api.Teams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	users, err := gz.api.Users()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	staleCreds := append([]*team.TeamCreds{}, plan.Remove...)
+	for _, change := range plan.Recreate {
+		staleCreds = append(staleCreds, change.Current)
+	}
+
+	stale := make(map[*team.TeamCreds]struct{}, len(staleCreds))
+	deletedTeams := make(map[string]struct{})
+	deletedUsers := make(map[string]struct{})
+	for _, creds := range staleCreds {
+		deleteAccount(creds, teams, users)
+		stale[creds] = struct{}{}
+		deletedTeams[creds.TeamName] = struct{}{}
+		deletedUsers[creds.Username] = struct{}{}
+	}
+
+	remaining := make([]*team.TeamCreds, 0, len(credsCache))
+	for _, creds := range credsCache {
+		if _, ok := stale[creds]; !ok {
+			remaining = append(remaining, creds)
+		}
+	}
+
+	// Recreated accounts must not collide with the names still in use
+	existingTeamNames := make(map[string]struct{})
+	for _, t := range teams {
+		if _, ok := deletedTeams[t.Name]; !ok {
+			existingTeamNames[t.Name] = struct{}{}
+		}
+	}
+	existingUserNames := make(map[string]struct{})
+	for _, u := range users {
+		if _, ok := deletedUsers[u.UserName]; !ok {
+			existingUserNames[u.UserName] = struct{}{}
+		}
+	}
+
+	configAdapter := &teamConfigAdapter{conf: conf, adminAPI: gz.api}
+	for _, change := range plan.Recreate {
+		creds, err := team.CreateTeamAndUser(&team.TeamCreds{
+			Username:          change.Row.RealName,
+			Email:             change.Row.Email,
+			TeamName:          change.Row.TeamName,
+			CommunicationType: change.Current.CommunicationType,
+			CommunicationLink: change.Current.CommunicationLink,
+			Events:            change.Row.Events,
+		}, configAdapter, existingTeamNames, existingUserNames, remaining, isSendEmail, generateUsername)
+		if creds != nil {
+			remaining = append(remaining, creds)
+		}
+		if err != nil {
+			log.Error("Failed to recreate account of %s: %v", change.Row.Email, err)
+		}
+	}
+
+	if err := SaveTeamsCreds(conf.EventName, conf.Url, remaining); err != nil {
+		return plan, fmt.Errorf("failed to save team credentials: %w", err)
+	}
+	return plan, nil
+}
+
+// deleteAccount deletes the team and user created for creds. Failures are
+// logged, so one account does not stop the others.
+func deleteAccount(creds *team.TeamCreds, teams []*gzapi.Team, users []*gzapi.User) {
+	for _, t := range teams {
+		if t.Name == creds.TeamName {
+			log.Info("deleting team %s", t.Name)
+			if err := t.Delete(); err != nil {
+				log.Error("Failed to delete team %s: %v", t.Name, err)
+			}
+		}
+	}
+	for _, u := range users {
+		if u.UserName == creds.Username {
+			log.Info("deleting user %s", u.UserName)
+			if err := u.Delete(); err != nil {
+				log.Error("Failed to delete user %s: %v", u.UserName, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package registry discovers the challenges of an event and derives their
+// identities, so the launcher and the watcher agree on which directories are
+// challenges, what they are keyed by and which slug they get
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzignore"
+)
+
+var challengeFileRegex = regexp.MustCompile(`^challenge\.(yaml|yml)$`)
+
+// challengeFileNames are the accepted challenge definition files, in lookup order
+var challengeFileNames = []string{"challenge.yaml", "challenge.yml"}
+
+// ErrNoChallengeFile is returned when a directory has no challenge definition
+var ErrNoChallengeFile = errors.New("no challenge.yaml/challenge.yml found")
+
+// Entry is a challenge directory found in an event
+type Entry struct {
+	EventName string
+	Category  string // Top-level directory of the challenge under the event, as named on disk
+	Dir       string // Challenge directory
+	File      string // Challenge definition file inside Dir
+}
+
+// Key returns the identifier of the challenge within its event
+// ("category/directory")
+func (e Entry) Key() string {
+	return e.Category + "/" + filepath.Base(e.Dir)
+}
+
+// KnownCategory reports whether the challenge is in one of the categories
+// synced to GZCTF
+func (e Entry) KnownCategory() bool {
+	return slices.Contains(config.CHALLENGE_CATEGORY, e.Category)
+}
+
+// IsChallengeFile reports whether a file name is a challenge definition
+func IsChallengeFile(name string) bool {
+	return challengeFileRegex.MatchString(name)
+}
+
+// FindChallengeFile returns the challenge definition file of a directory,
+// preferring challenge.yaml over challenge.yml
+func FindChallengeFile(dir string) (string, error) {
+	for _, name := range challengeFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s: %w", dir, ErrNoChallengeFile)
+}
+
+// Category returns the category of the challenge in dir: the first path
+// component below the event directory, or the parent directory name when dir
+// is not inside the event
+func Category(eventPath, dir string) string {
+	rel, err := filepath.Rel(eventPath, dir)
+	if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		category, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		return category
+	}
+	return filepath.Base(filepath.Dir(dir))
+}
+
+// Key returns the identifier of the challenge in dir within its event
+func Key(eventPath, dir string) string {
+	rel, err := filepath.Rel(eventPath, dir)
+	if err != nil || rel == "." {
+		return filepath.Base(dir)
+	}
+	return Category(eventPath, dir) + "/" + filepath.Base(dir)
+}
+
+// Slug returns the slug of a challenge, after the same category
+// normalization that is applied before syncing, so the launcher, image tags
+// and challenge templates all use the same one
+func Slug(eventName, category, name string) string {
+	category, name = config.NormalizeChallengeCategory(category, name)
+	return config.GenerateSlug(eventName, category, name)
+}
+
+// Discover walks an event directory and returns every challenge in it,
+// sorted by directory. Hidden directories and those ignored by .gzignore
+// files are skipped.
+func Discover(eventName, eventPath string) ([]Entry, error) {
+	var entries []Entry
+	ignore := gzignore.ForDir(eventPath)
+	err := filepath.Walk(eventPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip unreadable paths
+		}
+		if info.IsDir() {
+			if path != eventPath {
+				if strings.HasPrefix(info.Name(), ".") || ignore.Match(path, true) {
+					return filepath.SkipDir
+				}
+				ignore.AddFile(path)
+			}
+			return nil
+		}
+		if !IsChallengeFile(info.Name()) {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if dir == eventPath {
+			return nil
+		}
+		// A directory with both files is listed once, by its preferred file
+		if file, err := FindChallengeFile(dir); err == nil && file != path {
+			return nil
+		}
+		entries = append(entries, Entry{
+			EventName: eventName,
+			Category:  Category(eventPath, dir),
+			Dir:       dir,
+			File:      path,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk event directory: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Dir < entries[j].Dir })
+	return entries, nil
+}
+
+// Load parses the challenge definition of an entry without template
+// processing or event defaults. Category and Cwd are set from the entry; the
+// name and category are left as written, so use Slug for the identity.
+func (e Entry) Load() (config.ChallengeYaml, error) {
+	var challenge config.ChallengeYaml
+	if err := fileutil.ParseYamlFromFile(e.File, &challenge); err != nil {
+		return challenge, fmt.Errorf("failed to parse: %w", err)
+	}
+	challenge.Category = e.Category
+	challenge.Cwd = e.Dir
+	return challenge, nil
+}
+
+// Slug returns the slug of the challenge named name in this entry
+func (e Entry) Slug(name string) string {
+	return Slug(e.EventName, e.Category, name)
+}
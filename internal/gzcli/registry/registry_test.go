@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeChallenge(t *testing.T, dir, file, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	//nolint:gosec // G306: Test file permissions are acceptable
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write challenge file: %v", err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	eventPath := t.TempDir()
+	writeChallenge(t, filepath.Join(eventPath, "Web", "login"), "challenge.yaml", "name: Login\n")
+	writeChallenge(t, filepath.Join(eventPath, "Web", "login"), "challenge.yml", "name: Login Old\n")
+	writeChallenge(t, filepath.Join(eventPath, "web", "lower"), "challenge.yml", "name: Lower\n")
+	writeChallenge(t, filepath.Join(eventPath, ".git", "hidden"), "challenge.yaml", "name: Hidden\n")
+	writeChallenge(t, filepath.Join(eventPath, "Pwn", "group", "bof"), "challenge.yaml", "name: BOF\n")
+	writeChallenge(t, filepath.Join(eventPath, "Web", "login", "node_modules", "pkg"), "challenge.yml", "name: Vendored\n")
+	writeChallenge(t, filepath.Join(eventPath, "Misc", "archive", "old"), "challenge.yml", "name: Archived\n")
+	writeChallenge(t, eventPath, ".gzignore", "# Retired challenges\n/Misc/archive/\n")
+
+	entries, err := Discover("ctf", eventPath)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Pwn/bof":   "challenge.yaml",
+		"Web/login": "challenge.yaml",
+		"web/lower": "challenge.yml",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Discover() returned %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for _, entry := range entries {
+		file, ok := want[entry.Key()]
+		if !ok {
+			t.Errorf("unexpected entry %s", entry.Key())
+			continue
+		}
+		if filepath.Base(entry.File) != file {
+			t.Errorf("entry %s file = %s, want %s", entry.Key(), filepath.Base(entry.File), file)
+		}
+		if entry.EventName != "ctf" {
+			t.Errorf("entry %s event = %q", entry.Key(), entry.EventName)
+		}
+		if entry.Key() != Key(eventPath, entry.Dir) {
+			t.Errorf("entry key %s does not match Key() %s", entry.Key(), Key(eventPath, entry.Dir))
+		}
+	}
+}
+
+func TestEntryKnownCategory(t *testing.T) {
+	if !(Entry{Category: "Web"}).KnownCategory() {
+		t.Error("Web should be a known category")
+	}
+	if (Entry{Category: "web"}).KnownCategory() {
+		t.Error("web should not be a known category")
+	}
+}
+
+func TestEntryLoad(t *testing.T) {
+	eventPath := t.TempDir()
+	dir := filepath.Join(eventPath, "Game Hacking", "aimbot")
+	writeChallenge(t, dir, "challenge.yaml", "name: Aimbot\ndashboard:\n  type: compose\n  config: docker-compose.yml\n")
+
+	entries, err := Discover("ctf", eventPath)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Discover() = %v, %v", entries, err)
+	}
+	challenge, err := entries[0].Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if challenge.Name != "Aimbot" || challenge.Category != "Game Hacking" || challenge.Cwd != dir {
+		t.Errorf("Load() = name %q category %q cwd %q", challenge.Name, challenge.Category, challenge.Cwd)
+	}
+	if challenge.Dashboard == nil || challenge.Dashboard.Type != "compose" {
+		t.Errorf("Load() dashboard = %+v", challenge.Dashboard)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		category, name, want string
+	}{
+		{"Web", "SQL Injection", "ctf-web-sql-injection"},
+		{"Reverse", "[Game Hacking] Aimbot", "ctf-reverse-game-hacking-aimbot"},
+		// Matches the slug of the normalized challenge used when syncing
+		{"Game Hacking", "Aimbot", "ctf-reverse-game-hacking-aimbot"},
+	}
+	for _, tt := range tests {
+		if got := Slug("ctf", tt.category, tt.name); got != tt.want {
+			t.Errorf("Slug(%q, %q) = %q, want %q", tt.category, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryAndKey(t *testing.T) {
+	eventPath := filepath.Join("events", "ctf")
+	dir := filepath.Join(eventPath, "Web", "nested", "chal")
+	if got := Category(eventPath, dir); got != "Web" {
+		t.Errorf("Category() = %q, want Web", got)
+	}
+	if got := Key(eventPath, dir); got != "Web/chal" {
+		t.Errorf("Key() = %q, want Web/chal", got)
+	}
+	outside := filepath.Join("elsewhere", "Crypto", "rsa")
+	if got := Category(eventPath, outside); got != "Crypto" {
+		t.Errorf("Category() outside event = %q, want Crypto", got)
+	}
+}
+
+func TestFindChallengeFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := FindChallengeFile(dir); err == nil {
+		t.Error("FindChallengeFile() on empty dir should fail")
+	}
+	writeChallenge(t, dir, "challenge.yml", "name: A\n")
+	if got, err := FindChallengeFile(dir); err != nil || filepath.Base(got) != "challenge.yml" {
+		t.Errorf("FindChallengeFile() = %q, %v", got, err)
+	}
+	writeChallenge(t, dir, "challenge.yaml", "name: A\n")
+	if got, err := FindChallengeFile(dir); err != nil || filepath.Base(got) != "challenge.yaml" {
+		t.Errorf("FindChallengeFile() = %q, %v", got, err)
+	}
+}
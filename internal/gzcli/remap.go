@@ -0,0 +1,255 @@
+package gzcli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// DefaultRemapThreshold is the lowest title similarity proposed as a fuzzy
+// match when rebuilding challenge mappings
+const DefaultRemapThreshold = 0.8
+
+// LocalChallenge is a challenge folder of an event as the watcher maps it
+type LocalChallenge struct {
+	Folder   string // Relative to the event directory, e.g. "Web/my-challenge"
+	Category string
+	Name     string // Title the challenge is synced under
+}
+
+// MappingProposal pairs a local challenge with the remote challenge it was
+// most likely synced to
+type MappingProposal struct {
+	LocalChallenge
+	ChallengeID int
+	Title       string  // Title of the remote challenge
+	Score       float64 // Title similarity, 1 for an exact match
+}
+
+// Exact reports whether the titles matched after normalization
+func (p MappingProposal) Exact() bool {
+	return p.Score >= 1
+}
+
+// MappingPlan is the proposed mapping of an event's challenge folders to the
+// challenges of its game
+type MappingPlan struct {
+	Event           string
+	GameID          int
+	Proposals       []MappingProposal
+	UnmatchedLocal  []LocalChallenge
+	UnmatchedRemote []gzapi.Challenge
+}
+
+// ProposeMappings matches the local challenges of the event with the remote
+// challenges of its game by normalized title and category. Titles without an
+// exact match are paired with the most similar title of the same category
+// whose similarity is at least threshold.
+func (gz *GZ) ProposeMappings(threshold float64) (*MappingPlan, error) {
+	conf, err := config.GetConfigWithEvent(nil, gz.eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+	game := challenge.FindCurrentGame(games, conf.Event.Title, gz.api)
+	if game == nil {
+		return nil, fmt.Errorf("game %q of event %s not found on the server", conf.Event.Title, gz.eventName)
+	}
+	remote, err := game.GetChallenges()
+	if err != nil {
+		return nil, fmt.Errorf("API challenges fetch error: %w", err)
+	}
+
+	local, err := localChallenges(gz.eventName)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := matchChallenges(local, remote, threshold)
+	plan.Event = gz.eventName
+	plan.GameID = game.Id
+	return plan, nil
+}
+
+// localChallenges lists the challenge folders of an event with the title and
+// category they are synced under
+func localChallenges(eventName string) ([]LocalChallenge, error) {
+	eventPath, err := config.GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := registry.Discover(eventName, eventPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var local []LocalChallenge
+	for _, entry := range entries {
+		if !entry.KnownCategory() {
+			continue
+		}
+		c, err := entry.Load()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.File, err)
+		}
+		folder, err := filepath.Rel(eventPath, entry.Dir)
+		if err != nil {
+			folder = entry.Key()
+		}
+		category, name := config.NormalizeChallengeCategory(entry.Category, c.Name)
+		local = append(local, LocalChallenge{Folder: folder, Category: category, Name: name})
+	}
+	return local, nil
+}
+
+// matchChallenges pairs local and remote challenges, exact title matches
+// first, then the most similar remaining titles within each category
+func matchChallenges(local []LocalChallenge, remote []gzapi.Challenge, threshold float64) *MappingPlan {
+	plan := &MappingPlan{}
+	takenLocal := make([]bool, len(local))
+	takenRemote := make([]bool, len(remote))
+
+	exact := make(map[string]int, len(remote))
+	for j := len(remote) - 1; j >= 0; j-- {
+		exact[matchKey(remote[j].Category, remote[j].Title)] = j
+	}
+	for i, l := range local {
+		j, ok := exact[matchKey(l.Category, l.Name)]
+		if !ok || takenRemote[j] {
+			continue
+		}
+		takenLocal[i], takenRemote[j] = true, true
+		plan.Proposals = append(plan.Proposals, MappingProposal{LocalChallenge: l, ChallengeID: remote[j].Id, Title: remote[j].Title, Score: 1})
+	}
+
+	type candidate struct {
+		local, remote int
+		score         float64
+	}
+	var candidates []candidate
+	for i, l := range local {
+		if takenLocal[i] {
+			continue
+		}
+		for j, r := range remote {
+			if takenRemote[j] || !strings.EqualFold(l.Category, r.Category) {
+				continue
+			}
+			if score := titleSimilarity(l.Name, r.Title); score >= threshold {
+				candidates = append(candidates, candidate{i, j, score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].score > candidates[b].score })
+	for _, c := range candidates {
+		if takenLocal[c.local] || takenRemote[c.remote] {
+			continue
+		}
+		takenLocal[c.local], takenRemote[c.remote] = true, true
+		r := remote[c.remote]
+		plan.Proposals = append(plan.Proposals, MappingProposal{LocalChallenge: local[c.local], ChallengeID: r.Id, Title: r.Title, Score: c.score})
+	}
+
+	for i, l := range local {
+		if !takenLocal[i] {
+			plan.UnmatchedLocal = append(plan.UnmatchedLocal, l)
+		}
+	}
+	for j, r := range remote {
+		if !takenRemote[j] {
+			plan.UnmatchedRemote = append(plan.UnmatchedRemote, r)
+		}
+	}
+	sort.Slice(plan.Proposals, func(a, b int) bool { return plan.Proposals[a].Folder < plan.Proposals[b].Folder })
+	return plan
+}
+
+func matchKey(category, title string) string {
+	return strings.ToLower(category) + "/" + normalizeTitle(title)
+}
+
+// normalizeTitle lowercases a title and drops everything but letters and
+// digits, so "Baby's First Pwn!" and "babys first pwn" compare equal
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// titleSimilarity is one minus the edit distance of the normalized titles
+// relative to the longer one
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(normalizeTitle(a)), []rune(normalizeTitle(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// ProposalMappings returns the watcher mapping records of proposals
+func ProposalMappings(proposals []MappingProposal) []watchertypes.ChallengeMapping {
+	mappings := make([]watchertypes.ChallengeMapping, 0, len(proposals))
+	for _, proposal := range proposals {
+		mappings = append(mappings, watchertypes.ChallengeMapping{
+			Folder:      proposal.Folder,
+			ChallengeID: proposal.ChallengeID,
+			Title:       proposal.Title,
+		})
+	}
+	return mappings
+}
+
+// SaveChallengeMappings writes mapping records of an event straight into the
+// watcher database at dbPath, a file or a postgres:// URL, for when the
+// watcher is not running
+func SaveChallengeMappings(dbPath, eventName string, gameID int, mappings []watchertypes.ChallengeMapping) error {
+	db := database.New(dbPath, true)
+	if err := db.Init(); err != nil {
+		return fmt.Errorf("failed to open watcher database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	for _, m := range mappings {
+		if err := db.SetChallengeMapping(eventName, filepath.Clean(m.Folder), m.ChallengeID, gameID, m.Title); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package gzcli
+
+import (
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func TestMatchChallenges(t *testing.T) {
+	local := []LocalChallenge{
+		{Folder: "Web/login", Category: "Web", Name: "Login Bypass"},
+		{Folder: "Web/notes", Category: "Web", Name: "Secure Notes v2"},
+		{Folder: "Crypto/rsa", Category: "Crypto", Name: "Baby's RSA"},
+		{Folder: "Pwn/new", Category: "Pwn", Name: "Brand New"},
+	}
+	remote := []gzapi.Challenge{
+		{Id: 1, Title: "login bypass", Category: "Web"},
+		{Id: 2, Title: "Secure Notes", Category: "Web"},
+		{Id: 3, Title: "Babys RSA!", Category: "Crypto"},
+		{Id: 4, Title: "Secure Notes v2", Category: "Misc"}, // Same title, other category
+		{Id: 5, Title: "Leftover", Category: "Pwn"},
+	}
+
+	plan := matchChallenges(local, remote, DefaultRemapThreshold)
+
+	got := make(map[string]MappingProposal)
+	for _, p := range plan.Proposals {
+		got[p.Folder] = p
+	}
+	if p := got["Web/login"]; p.ChallengeID != 1 || !p.Exact() {
+		t.Errorf("Web/login = %+v, want exact match with 1", p)
+	}
+	if p := got["Crypto/rsa"]; p.ChallengeID != 3 || !p.Exact() {
+		t.Errorf("Crypto/rsa = %+v, want exact match with 3", p)
+	}
+	if p := got["Web/notes"]; p.ChallengeID != 2 || p.Exact() {
+		t.Errorf("Web/notes = %+v, want fuzzy match with 2", p)
+	}
+	if _, ok := got["Pwn/new"]; ok {
+		t.Errorf("Pwn/new should not match a dissimilar title")
+	}
+
+	if len(plan.UnmatchedLocal) != 1 || plan.UnmatchedLocal[0].Folder != "Pwn/new" {
+		t.Errorf("UnmatchedLocal = %+v", plan.UnmatchedLocal)
+	}
+	if len(plan.UnmatchedRemote) != 2 {
+		t.Errorf("UnmatchedRemote = %+v, want Misc and Pwn leftovers", plan.UnmatchedRemote)
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Hello", "hello!", 1},
+		{"abcd", "abce", 0.75},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		if got := titleSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package rpc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/server"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// launcherTimeout bounds requests to the launcher socket
+const launcherTimeout = 10 * time.Second
+
+// DefaultBackend implements Backend using the local workspace, the watcher
+// daemon socket and the challenge launcher socket
+type DefaultBackend struct {
+	WatcherSocketPath  string
+	LauncherSocketPath string
+
+	// syncMu serializes syncs; caches and attachments are shared per workspace
+	syncMu sync.Mutex
+}
+
+// NewDefaultBackend creates a backend using the given socket paths; empty paths use the defaults
+func NewDefaultBackend(watcherSocketPath, launcherSocketPath string) *DefaultBackend {
+	if watcherSocketPath == "" {
+		watcherSocketPath = gzcli.DefaultWatcherConfig.SocketPath
+	}
+	if launcherSocketPath == "" {
+		launcherSocketPath = server.DefaultSocketPath
+	}
+	return &DefaultBackend{
+		WatcherSocketPath:  watcherSocketPath,
+		LauncherSocketPath: launcherSocketPath,
+	}
+}
+
+// ListEvents returns the events in the workspace
+func (b *DefaultBackend) ListEvents() ([]string, error) {
+	return config.ListEvents()
+}
+
+// Sync syncs an event, or a single challenge of it when challenge is set
+func (b *DefaultBackend) Sync(event, challenge string) error {
+	b.syncMu.Lock()
+	defer b.syncMu.Unlock()
+
+	gz, err := gzcli.InitWithEvent(event)
+	if err != nil {
+		return err
+	}
+	if challenge != "" {
+		return gz.SyncChallenge(challenge)
+	}
+	return gz.Sync()
+}
+
+// WatcherStatus returns the status reported by the watcher daemon
+func (b *DefaultBackend) WatcherStatus() (map[string]interface{}, error) {
+	resp, err := socket.NewClient(b.WatcherSocketPath).Status()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Data, nil
+}
+
+// ListInstances returns launcher challenges and their status
+func (b *DefaultBackend) ListInstances() (interface{}, error) {
+	resp, err := b.launcherCommand(server.ActionListInstances, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data["instances"], nil
+}
+
+// StartInstance asks the launcher to start the challenge with the given slug
+func (b *DefaultBackend) StartInstance(slug string) (string, error) {
+	resp, err := b.launcherCommand(server.ActionStartInstance, map[string]interface{}{"slug": slug})
+	if err != nil {
+		return "", err
+	}
+	return resp.Message, nil
+}
+
+func (b *DefaultBackend) launcherCommand(action string, data map[string]interface{}) (*watchertypes.WatcherResponse, error) {
+	client := socket.NewClient(b.LauncherSocketPath)
+	client.SetTimeout(launcherTimeout)
+
+	resp, err := client.SendCommand(action, data)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,273 @@
+// Package rpc exposes core gzcli operations over a local JSON-RPC 2.0 HTTP API
+// so organizer tooling can embed gzcli without parsing CLI output.
+package rpc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// maxRequestSize limits the size of a single JSON-RPC request body
+const maxRequestSize = 1 << 20
+
+// Backend performs the operations exposed over RPC
+type Backend interface {
+	ListEvents() ([]string, error)
+	Sync(event, challenge string) error
+	WatcherStatus() (map[string]interface{}, error)
+	ListInstances() (interface{}, error)
+	StartInstance(slug string) (string, error)
+}
+
+// Request is a JSON-RPC 2.0 request
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Error is a JSON-RPC 2.0 error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+type methodFunc func(params json.RawMessage) (interface{}, *Error)
+
+// Server dispatches JSON-RPC requests to a Backend
+type Server struct {
+	backend Backend
+	token   string
+	methods map[string]methodFunc
+}
+
+// NewServer creates a JSON-RPC server. If token is non-empty, requests must
+// send it as a bearer token in the Authorization header.
+func NewServer(backend Backend, token string) *Server {
+	s := &Server{
+		backend: backend,
+		token:   token,
+	}
+	s.methods = map[string]methodFunc{
+		"events.list":     s.listEvents,
+		"challenges.sync": s.syncChallenges,
+		"watcher.status":  s.watcherStatus,
+		"instances.list":  s.listInstances,
+		"instances.start": s.startInstance,
+	}
+	return s
+}
+
+// Methods returns the sorted names of the supported methods
+func (s *Server) Methods() []string {
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Requiring JSON forces a CORS preflight, so web pages cannot drive the API
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		writeJSON(w, Response{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: "failed to read request"}})
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, Response{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: "invalid JSON"}})
+		return
+	}
+
+	resp := s.Handle(req)
+	if len(req.ID) == 0 {
+		// Notification: no response body
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// Handle executes a single request
+func (s *Server) Handle(req Request) Response {
+	resp := Response{JSONRPC: "2.0", ID: req.ID}
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+		return resp
+	}
+
+	method, ok := s.methods[req.Method]
+	if !ok {
+		resp.Error = &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+		return resp
+	}
+
+	log.Debug("RPC call: %s", req.Method)
+	result, rpcErr := method(req.Params)
+	if rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Failed to write RPC response: %v", err)
+	}
+}
+
+// decodeParams unmarshals params into v, treating missing params as empty
+func decodeParams(params json.RawMessage, v interface{}) *Error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+func internalError(err error) *Error {
+	return &Error{Code: CodeInternalError, Message: err.Error()}
+}
+
+func (s *Server) listEvents(_ json.RawMessage) (interface{}, *Error) {
+	events, err := s.backend.ListEvents()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return map[string]interface{}{"events": events}, nil
+}
+
+// SyncParams are the parameters of challenges.sync
+type SyncParams struct {
+	Event     string `json:"event"`
+	Challenge string `json:"challenge,omitempty"`
+}
+
+func (s *Server) syncChallenges(params json.RawMessage) (interface{}, *Error) {
+	var p SyncParams
+	if rpcErr := decodeParams(params, &p); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if p.Event == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "event is required"}
+	}
+
+	if err := s.backend.Sync(p.Event, p.Challenge); err != nil {
+		return nil, internalError(err)
+	}
+	return map[string]interface{}{"event": p.Event, "challenge": p.Challenge, "synced": true}, nil
+}
+
+func (s *Server) watcherStatus(_ json.RawMessage) (interface{}, *Error) {
+	status, err := s.backend.WatcherStatus()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return status, nil
+}
+
+func (s *Server) listInstances(_ json.RawMessage) (interface{}, *Error) {
+	instances, err := s.backend.ListInstances()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return map[string]interface{}{"instances": instances}, nil
+}
+
+// StartInstanceParams are the parameters of instances.start
+type StartInstanceParams struct {
+	Slug string `json:"slug"`
+}
+
+func (s *Server) startInstance(params json.RawMessage) (interface{}, *Error) {
+	var p StartInstanceParams
+	if rpcErr := decodeParams(params, &p); rpcErr != nil {
+		return nil, rpcErr
+	}
+	if p.Slug == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "slug is required"}
+	}
+
+	message, err := s.backend.StartInstance(p.Slug)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return map[string]interface{}{"slug": p.Slug, "message": message}, nil
+}
+
+// IsLoopbackAddr reports whether addr (host:port) binds only to a loopback interface
+func IsLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
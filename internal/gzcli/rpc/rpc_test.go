@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeBackend struct {
+	syncedEvent     string
+	syncedChallenge string
+	syncErr         error
+}
+
+func (f *fakeBackend) ListEvents() ([]string, error) { return []string{"ctf2024", "ctf2025"}, nil }
+
+func (f *fakeBackend) Sync(event, challenge string) error {
+	f.syncedEvent, f.syncedChallenge = event, challenge
+	return f.syncErr
+}
+
+func (f *fakeBackend) WatcherStatus() (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "running"}, nil
+}
+
+func (f *fakeBackend) ListInstances() (interface{}, error) { return []string{}, nil }
+
+func (f *fakeBackend) StartInstance(slug string) (string, error) { return "Starting " + slug, nil }
+
+func call(t *testing.T, s *Server, body, token string) (*http.Response, Response) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response JSON: %v (%s)", err, rec.Body.String())
+		}
+	}
+	return rec.Result(), resp
+}
+
+func TestServer_ListEvents(t *testing.T) {
+	s := NewServer(&fakeBackend{}, "")
+
+	_, resp := call(t, s, `{"jsonrpc":"2.0","id":1,"method":"events.list"}`, "")
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if string(resp.ID) != "1" {
+		t.Errorf("ID = %s, want 1", resp.ID)
+	}
+	result, _ := json.Marshal(resp.Result)
+	if !strings.Contains(string(result), "ctf2025") {
+		t.Errorf("result = %s, want events", result)
+	}
+}
+
+func TestServer_SyncChallenge(t *testing.T) {
+	backend := &fakeBackend{}
+	s := NewServer(backend, "")
+
+	_, resp := call(t, s, `{"jsonrpc":"2.0","id":"a","method":"challenges.sync","params":{"event":"ctf2025","challenge":"Web 1"}}`, "")
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if backend.syncedEvent != "ctf2025" || backend.syncedChallenge != "Web 1" {
+		t.Errorf("Sync called with (%q, %q)", backend.syncedEvent, backend.syncedChallenge)
+	}
+
+	backend.syncErr = errors.New("boom")
+	_, resp = call(t, s, `{"jsonrpc":"2.0","id":2,"method":"challenges.sync","params":{"event":"ctf2025"}}`, "")
+	if resp.Error == nil || resp.Error.Code != CodeInternalError {
+		t.Errorf("Error = %v, want internal error", resp.Error)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s := NewServer(&fakeBackend{}, "")
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"invalid json", `{`, CodeParseError},
+		{"missing version", `{"id":1,"method":"events.list"}`, CodeInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"nope"}`, CodeMethodNotFound},
+		{"missing event", `{"jsonrpc":"2.0","id":1,"method":"challenges.sync","params":{}}`, CodeInvalidParams},
+		{"bad params", `{"jsonrpc":"2.0","id":1,"method":"instances.start","params":[1]}`, CodeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp := call(t, s, tt.body, "")
+			if resp.Error == nil || resp.Error.Code != tt.code {
+				t.Errorf("Error = %v, want code %d", resp.Error, tt.code)
+			}
+		})
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := NewServer(&fakeBackend{}, "secret")
+	body := `{"jsonrpc":"2.0","id":1,"method":"watcher.status"}`
+
+	if httpResp, _ := call(t, s, body, ""); httpResp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 without token", httpResp.StatusCode)
+	}
+	if httpResp, _ := call(t, s, body, "wrong"); httpResp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 with wrong token", httpResp.StatusCode)
+	}
+
+	// The bare token without the Bearer scheme is not accepted
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "secret")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 without the Bearer scheme", rec.Code)
+	}
+
+	if httpResp, resp := call(t, s, body, "secret"); httpResp.StatusCode != http.StatusOK || resp.Error != nil {
+		t.Errorf("status = %d, error = %v, want success", httpResp.StatusCode, resp.Error)
+	}
+}
+
+func TestServer_RejectsNonJSONAndNotifications(t *testing.T) {
+	s := NewServer(&fakeBackend{}, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"events.list"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want 415 for text/plain", rec.Code)
+	}
+
+	if httpResp, _ := call(t, s, `{"jsonrpc":"2.0","method":"events.list"}`, ""); httpResp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204 for notification", httpResp.StatusCode)
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:7373": true,
+		"localhost:7373": true,
+		"[::1]:7373":     true,
+		"0.0.0.0:7373":   false,
+		":7373":          false,
+		"10.0.0.5:7373":  false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopbackAddr(addr); got != want {
+			t.Errorf("IsLoopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
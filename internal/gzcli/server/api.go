@@ -0,0 +1,244 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// APITokenEnv is the environment variable holding the REST API token when
+// none is set explicitly
+const APITokenEnv = "GZCLI_LAUNCHER_API_TOKEN"
+
+var (
+	apiToken   string
+	apiTokenMu sync.RWMutex
+)
+
+// SetAPIToken enables the REST API under /api/challenges, authenticated with
+// the token as a bearer token. Listing challenges reveals their secret slugs,
+// so the API is disabled while the token is empty.
+func SetAPIToken(token string) {
+	apiTokenMu.Lock()
+	defer apiTokenMu.Unlock()
+	apiToken = token
+}
+
+func getAPIToken() string {
+	apiTokenMu.RLock()
+	defer apiTokenMu.RUnlock()
+	return apiToken
+}
+
+// APIResponse is the body of every REST API response
+type APIResponse struct {
+	Success   bool           `json:"success"`
+	Message   string         `json:"message,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Instance  *InstanceInfo  `json:"instance,omitempty"`
+	Instances []InstanceInfo `json:"instances,omitempty"`
+}
+
+// setupAPIRoutes registers the REST API for challenge lifecycle control
+func (s *Server) setupAPIRoutes(mux *http.ServeMux) {
+	token := getAPIToken()
+	if token == "" {
+		return
+	}
+
+	auth := func(next func(http.ResponseWriter, *http.Request)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				writeAPIResponse(w, http.StatusUnauthorized, APIResponse{Error: "unauthorized"})
+				return
+			}
+			next(w, r)
+		}
+	}
+
+	mux.HandleFunc("GET /api/challenges", auth(s.handleAPIList))
+	mux.HandleFunc("GET /api/challenges/{slug}/status", auth(s.handleAPIStatus))
+	mux.HandleFunc("POST /api/challenges/{slug}/start", auth(s.handleAPIStart))
+	mux.HandleFunc("POST /api/challenges/{slug}/stop", auth(s.handleAPIStop))
+	mux.HandleFunc("POST /api/challenges/{slug}/restart", auth(s.handleAPIRestart))
+}
+
+func (s *Server) handleAPIList(w http.ResponseWriter, _ *http.Request) {
+	writeAPIResponse(w, http.StatusOK, APIResponse{Success: true, Instances: s.wsManager.listInstances()})
+}
+
+func (s *Server) handleAPIStatus(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := s.apiChallenge(w, r)
+	if !ok {
+		return
+	}
+	info := instanceInfo(challenge)
+	writeAPIResponse(w, http.StatusOK, APIResponse{Success: true, Instance: &info})
+}
+
+func (s *Server) handleAPIStart(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := s.apiChallenge(w, r)
+	if !ok {
+		return
+	}
+
+	switch status := challenge.GetStatus(); status {
+	case StatusRunning, StatusStarting, StatusRestarting:
+		s.writeAPIInstance(w, http.StatusOK, challenge, fmt.Sprintf("Challenge %s is already %s", challenge.Name, status))
+		return
+	case StatusStopping:
+		s.writeAPIConflict(w, challenge)
+		return
+	}
+
+	log.Info("REST API: starting challenge %s", challenge.Name)
+	s.wsManager.startChallenge(challenge)
+	s.writeAPIInstance(w, http.StatusAccepted, challenge, fmt.Sprintf("Starting challenge %s", challenge.Name))
+}
+
+func (s *Server) handleAPIStop(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := s.apiChallenge(w, r)
+	if !ok {
+		return
+	}
+
+	switch challenge.GetStatus() {
+	case StatusStopped:
+		s.writeAPIInstance(w, http.StatusOK, challenge, fmt.Sprintf("Challenge %s is already stopped", challenge.Name))
+		return
+	case StatusStarting, StatusStopping, StatusRestarting:
+		s.writeAPIConflict(w, challenge)
+		return
+	}
+
+	log.Info("REST API: stopping challenge %s", challenge.Name)
+	s.wsManager.stopChallenge(challenge)
+	s.writeAPIInstance(w, http.StatusAccepted, challenge, fmt.Sprintf("Stopping challenge %s", challenge.Name))
+}
+
+func (s *Server) handleAPIRestart(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := s.apiChallenge(w, r)
+	if !ok {
+		return
+	}
+
+	switch challenge.GetStatus() {
+	case StatusStarting, StatusStopping, StatusRestarting:
+		s.writeAPIConflict(w, challenge)
+		return
+	}
+
+	// An organizer restart supersedes any restart vote of the players
+	if s.wsManager.voting != nil && s.wsManager.voting.HasActiveVote(challenge.InstanceKey()) {
+		s.wsManager.voting.EndVote(challenge.InstanceKey(), "restarted by organizer")
+		s.wsManager.broadcastVoteEnded(challenge.InstanceKey(), VoteMessage{Result: "approved"})
+	}
+
+	log.Info("REST API: restarting challenge %s", challenge.Name)
+	s.wsManager.executeRestart(challenge)
+	s.writeAPIInstance(w, http.StatusAccepted, challenge, fmt.Sprintf("Restarting challenge %s", challenge.Name))
+}
+
+// apiChallenge looks up the instance named by the slug path value and the
+// optional team query parameter, writing a 404 response if it does not exist
+func (s *Server) apiChallenge(w http.ResponseWriter, r *http.Request) (*ChallengeInfo, bool) {
+	slug := r.PathValue("slug")
+	team := r.URL.Query().Get("team")
+	challenge, exists := s.challenges.GetInstance(slug, team, false)
+	if !exists {
+		msg := fmt.Sprintf("challenge not found: %s", slug)
+		if team != "" {
+			msg = fmt.Sprintf("no instance of %s for team %s", slug, team)
+		}
+		writeAPIResponse(w, http.StatusNotFound, APIResponse{Error: msg})
+		return nil, false
+	}
+	return challenge, true
+}
+
+func (s *Server) writeAPIInstance(w http.ResponseWriter, code int, challenge *ChallengeInfo, message string) {
+	info := instanceInfo(challenge)
+	writeAPIResponse(w, code, APIResponse{Success: true, Message: message, Instance: &info})
+}
+
+func (s *Server) writeAPIConflict(w http.ResponseWriter, challenge *ChallengeInfo) {
+	info := instanceInfo(challenge)
+	writeAPIResponse(w, http.StatusConflict, APIResponse{
+		Error:    fmt.Sprintf("challenge %s is %s, try again later", challenge.Name, challenge.GetStatus()),
+		Instance: &info,
+	})
+}
+
+func writeAPIResponse(w http.ResponseWriter, code int, resp APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("Failed to write API response: %v", err)
+	}
+}
+
+// instanceInfo snapshots the state of a challenge
+func instanceInfo(c *ChallengeInfo) InstanceInfo {
+	return InstanceInfo{
+		Slug:           c.Slug,
+		Team:           c.Team,
+		Event:          c.EventName,
+		Category:       c.Category,
+		Name:           c.Name,
+		Status:         string(c.GetStatus()),
+		ConnectedUsers: c.GetConnectedUsers(),
+		AllocatedPorts: c.GetAllocatedPorts(),
+		PortChecks:     c.GetPortChecks(),
+		Usage:          c.GetResourceUsage(),
+		TTL:            c.GetTTL(),
+	}
+}
+
+// listInstances snapshots every launcher instance, sorted by slug and team
+func (wm *WSManager) listInstances() []InstanceInfo {
+	challenges := wm.challenges.ListInstances()
+	instances := make([]InstanceInfo, 0, len(challenges))
+	for _, c := range challenges {
+		instances = append(instances, instanceInfo(c))
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Slug != instances[j].Slug {
+			return instances[i].Slug < instances[j].Slug
+		}
+		return instances[i].Team < instances[j].Team
+	})
+	return instances
+}
+
+// stopChallenge marks a challenge as stopping and stops it in the background
+func (wm *WSManager) stopChallenge(challenge *ChallengeInfo) {
+	wm.autoStopMu.Lock()
+	key := challenge.InstanceKey()
+	if timer, exists := wm.autoStopTimers[key]; exists {
+		timer.Stop()
+		delete(wm.autoStopTimers, key)
+	}
+	wm.autoStopMu.Unlock()
+
+	challenge.SetStatus(StatusStopping)
+	wm.broadcastStatus(key)
+
+	go func() {
+		if err := wm.executor.Stop(challenge); err != nil {
+			log.Error("Failed to stop challenge %s: %v", challenge.Name, err)
+			challenge.SetStatus(StatusRunning)
+			wm.broadcastError(key, "Failed to stop challenge. Please check server logs.")
+		} else {
+			challenge.SetStatus(StatusStopped)
+			wm.broadcastInfo(key, "Challenge stopped by organizers")
+		}
+		wm.broadcastStatus(key)
+	}()
+}
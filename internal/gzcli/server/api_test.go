@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestAPIServer(t *testing.T, token string) (*httptest.Server, *ChallengeManager) {
+	t.Helper()
+
+	handler, cm, _ := newTestNotifyHandler(t)
+	SetAPIToken(token)
+	t.Cleanup(func() { SetAPIToken("") })
+
+	s := NewServer(cm, handler.wsManager)
+	ts := httptest.NewServer(s.SetupRoutes())
+	t.Cleanup(ts.Close)
+	return ts, cm
+}
+
+func apiRequest(t *testing.T, ts *httptest.Server, method, path, token string) (int, APIResponse) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, ts.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body APIResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestAPI_DisabledWithoutToken(t *testing.T) {
+	ts, _ := newTestAPIServer(t, "")
+
+	if code, _ := apiRequest(t, ts, http.MethodGet, "/api/challenges", ""); code != http.StatusNotFound {
+		t.Errorf("GET /api/challenges without a configured token = %d, want 404", code)
+	}
+}
+
+func TestAPI_Unauthorized(t *testing.T) {
+	ts, _ := newTestAPIServer(t, "secret")
+
+	for _, token := range []string{"", "wrong"} {
+		if code, _ := apiRequest(t, ts, http.MethodGet, "/api/challenges", token); code != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, code)
+		}
+	}
+}
+
+func TestAPI_ListAndStatus(t *testing.T) {
+	ts, _ := newTestAPIServer(t, "secret")
+
+	code, body := apiRequest(t, ts, http.MethodGet, "/api/challenges", "secret")
+	if code != http.StatusOK || len(body.Instances) != 1 || body.Instances[0].Slug != "ctf_web_chall" {
+		t.Fatalf("list = %d %+v, want one instance", code, body)
+	}
+
+	code, body = apiRequest(t, ts, http.MethodGet, "/api/challenges/ctf_web_chall/status", "secret")
+	if code != http.StatusOK || body.Instance == nil || body.Instance.Status != string(StatusStopped) {
+		t.Errorf("status = %d %+v, want stopped instance", code, body)
+	}
+
+	if code, _ := apiRequest(t, ts, http.MethodGet, "/api/challenges/missing/status", "secret"); code != http.StatusNotFound {
+		t.Errorf("unknown slug status = %d, want 404", code)
+	}
+}
+
+func TestAPI_Lifecycle(t *testing.T) {
+	ts, cm := newTestAPIServer(t, "secret")
+	challenge, _ := cm.GetChallenge("ctf_web_chall")
+
+	code, body := apiRequest(t, ts, http.MethodPost, "/api/challenges/ctf_web_chall/stop", "secret")
+	if code != http.StatusOK || !body.Success {
+		t.Errorf("stop of a stopped challenge = %d %+v, want 200", code, body)
+	}
+
+	challenge.SetStatus(StatusRunning)
+	code, _ = apiRequest(t, ts, http.MethodPost, "/api/challenges/ctf_web_chall/start", "secret")
+	if code != http.StatusOK {
+		t.Errorf("start of a running challenge = %d, want 200", code)
+	}
+
+	challenge.SetStatus(StatusStarting)
+	for _, action := range []string{"stop", "restart"} {
+		code, body = apiRequest(t, ts, http.MethodPost, "/api/challenges/ctf_web_chall/"+action, "secret")
+		if code != http.StatusConflict || body.Success {
+			t.Errorf("%s while starting = %d %+v, want 409", action, code, body)
+		}
+	}
+}
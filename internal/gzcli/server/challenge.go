@@ -4,19 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sync"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
-	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
-var challengeFileRegex = regexp.MustCompile(`challenge\.(yaml|yml)$`)
-
 // ChallengeManager manages all discovered challenges
 type ChallengeManager struct {
 	challenges map[string]*ChallengeInfo // slug -> ChallengeInfo
+	instances  map[string]*ChallengeInfo // instance key -> per-team instance
 	mu         sync.RWMutex
 }
 
@@ -24,17 +22,18 @@ type ChallengeManager struct {
 func NewChallengeManager() *ChallengeManager {
 	return &ChallengeManager{
 		challenges: make(map[string]*ChallengeInfo),
+		instances:  make(map[string]*ChallengeInfo),
 	}
 }
 
 // portParser is used to extract ports from configuration files
 var portParser = NewPortParser()
 
-// processChallengeFile processes a single challenge file and adds it to the manager
-func (cm *ChallengeManager) processChallengeFile(path, eventName, category string) error {
-	var challYaml config.ChallengeYaml
-	if err := fileutil.ParseYamlFromFile(path, &challYaml); err != nil {
-		return fmt.Errorf("failed to parse: %w", err)
+// addChallenge loads a discovered challenge and adds it to the manager
+func (cm *ChallengeManager) addChallenge(entry registry.Entry) error {
+	challYaml, err := entry.Load()
+	if err != nil {
+		return err
 	}
 
 	// Only include challenges with Dashboard configuration
@@ -42,12 +41,7 @@ func (cm *ChallengeManager) processChallengeFile(path, eventName, category strin
 		return nil
 	}
 
-	// Set challenge metadata
-	challYaml.Category = category
-	challYaml.Cwd = filepath.Dir(path)
-
-	// Generate slug
-	slug := config.GenerateSlug(eventName, category, challYaml.Name)
+	slug := entry.Slug(challYaml.Name)
 
 	// Parse ports from configuration file
 	ports := portParser.ParsePorts(
@@ -58,16 +52,22 @@ func (cm *ChallengeManager) processChallengeFile(path, eventName, category strin
 
 	// Convert to our Dashboard type
 	dashboard := &Dashboard{
-		Type:   challYaml.Dashboard.Type,
-		Config: challYaml.Dashboard.Config,
-		Ports:  ports,
+		Type:            challYaml.Dashboard.Type,
+		Config:          challYaml.Dashboard.Config,
+		Ports:           ports,
+		WarmPool:        challYaml.Dashboard.WarmPool,
+		Idle:            challYaml.Dashboard.Idle,
+		Resources:       challYaml.Dashboard.Resources,
+		IsolatedNetwork: challYaml.Dashboard.IsolatedNetwork,
+		TTL:             challYaml.Dashboard.TTL,
+		Gate:            challYaml.Dashboard.Gate,
 	}
 
 	// Create ChallengeInfo
 	challengeInfo := &ChallengeInfo{
 		Slug:         slug,
-		EventName:    eventName,
-		Category:     category,
+		EventName:    entry.EventName,
+		Category:     entry.Category,
 		Name:         challYaml.Name,
 		Description:  challYaml.Description,
 		Cwd:          challYaml.Cwd,
@@ -86,36 +86,6 @@ func (cm *ChallengeManager) processChallengeFile(path, eventName, category strin
 	return nil
 }
 
-// scanCategory scans a category directory for challenges
-func (cm *ChallengeManager) scanCategory(eventPath, eventName, category string) int {
-	categoryPath := filepath.Join(eventPath, category)
-
-	if _, err := os.Stat(categoryPath); os.IsNotExist(err) {
-		return 0
-	}
-
-	count := 0
-	err := filepath.Walk(categoryPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || !challengeFileRegex.MatchString(info.Name()) {
-			return nil
-		}
-
-		if err := cm.processChallengeFile(path, eventName, category); err != nil {
-			log.Error("Failed to process %s: %v", path, err)
-			return nil
-		}
-
-		count++
-		return nil
-	})
-
-	if err != nil {
-		log.Error("Error walking category %s: %v", category, err)
-	}
-
-	return count
-}
-
 // scanEvent scans an event for all challenges
 func (cm *ChallengeManager) scanEvent(eventName string) (int, error) {
 	eventPath, err := config.GetEventPath(eventName)
@@ -125,9 +95,22 @@ func (cm *ChallengeManager) scanEvent(eventName string) (int, error) {
 
 	log.InfoH2("Scanning event: %s", eventName)
 
+	entries, err := registry.Discover(eventName, eventPath)
+	if err != nil {
+		return 0, err
+	}
+
 	count := 0
-	for _, category := range config.CHALLENGE_CATEGORY {
-		count += cm.scanCategory(eventPath, eventName, category)
+	for _, entry := range entries {
+		// Challenges outside the known categories are never synced
+		if !entry.KnownCategory() {
+			continue
+		}
+		if err := cm.addChallenge(entry); err != nil {
+			log.Error("Failed to process %s: %v", entry.File, err)
+			continue
+		}
+		count++
 	}
 
 	return count, nil
@@ -188,14 +171,63 @@ func (cm *ChallengeManager) DiscoverChallenges() error {
 	return nil
 }
 
-// GetChallenge retrieves a challenge by slug
+// GetChallenge retrieves a challenge by slug, or a team instance by its
+// instance key
 func (cm *ChallengeManager) GetChallenge(slug string) (*ChallengeInfo, bool) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
-	challenge, ok := cm.challenges[slug]
+	if challenge, ok := cm.challenges[slug]; ok {
+		return challenge, true
+	}
+	challenge, ok := cm.instances[slug]
 	return challenge, ok
 }
 
+// GetInstance retrieves the instance of a challenge owned by team, creating
+// it if create is set. An empty team is the shared instance.
+func (cm *ChallengeManager) GetInstance(slug, team string, create bool) (*ChallengeInfo, bool) {
+	if team == "" {
+		return cm.GetChallenge(slug)
+	}
+
+	key := instanceKey(slug, team)
+	cm.mu.RLock()
+	base, ok := cm.challenges[slug]
+	instance, exists := cm.instances[key]
+	cm.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if exists || !create {
+		return instance, exists
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if instance, exists := cm.instances[key]; exists {
+		return instance, true
+	}
+	instance = base.newTeamInstance(team)
+	cm.instances[key] = instance
+	return instance, true
+}
+
+// ListInstances returns the shared instance of every challenge and every
+// team instance
+func (cm *ChallengeManager) ListInstances() []*ChallengeInfo {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	instances := make([]*ChallengeInfo, 0, len(cm.challenges)+len(cm.instances))
+	for _, challenge := range cm.challenges {
+		instances = append(instances, challenge)
+	}
+	for _, instance := range cm.instances {
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
 // ListChallenges returns all discovered challenges
 func (cm *ChallengeManager) ListChallenges() []*ChallengeInfo {
 	cm.mu.RLock()
@@ -215,3 +247,36 @@ func (cm *ChallengeManager) GetChallengeCount() int {
 	defer cm.mu.RUnlock()
 	return len(cm.challenges)
 }
+
+// FindChallengeByDir retrieves a challenge of an event by its directory
+func (cm *ChallengeManager) FindChallengeByDir(eventName, dir string) (*ChallengeInfo, bool) {
+	target := absPath(dir)
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	for _, challenge := range cm.challenges {
+		if challenge.EventName == eventName && absPath(challenge.Cwd) == target {
+			return challenge, true
+		}
+	}
+	return nil, false
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// RemoveChallenge removes a challenge and its team instances by slug
+func (cm *ChallengeManager) RemoveChallenge(slug string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	delete(cm.challenges, slug)
+	for key, instance := range cm.instances {
+		if instance.Slug == slug {
+			delete(cm.instances, key)
+		}
+	}
+}
@@ -59,6 +59,13 @@ func NewExecutor() *Executor {
 
 // Start starts a challenge
 func (e *Executor) Start(challenge *ChallengeInfo) error {
+	startedAt := time.Now()
+	err := e.start(challenge)
+	launcherMetrics.observeOperation(opStart, time.Since(startedAt), err)
+	return err
+}
+
+func (e *Executor) start(challenge *ChallengeInfo) error {
 	if challenge.Dashboard == nil {
 		return fmt.Errorf("challenge has no dashboard configuration")
 	}
@@ -80,6 +87,13 @@ func (e *Executor) Start(challenge *ChallengeInfo) error {
 
 // Stop stops a challenge
 func (e *Executor) Stop(challenge *ChallengeInfo) error {
+	startedAt := time.Now()
+	err := e.stop(challenge)
+	launcherMetrics.observeOperation(opStop, time.Since(startedAt), err)
+	return err
+}
+
+func (e *Executor) stop(challenge *ChallengeInfo) error {
 	if challenge.Dashboard == nil {
 		return fmt.Errorf("challenge has no dashboard configuration")
 	}
@@ -87,26 +101,39 @@ func (e *Executor) Stop(challenge *ChallengeInfo) error {
 	dashboard := challenge.Dashboard
 	launcherType := LauncherType(dashboard.Type)
 
+	var err error
 	switch launcherType {
 	case LauncherTypeCompose:
-		return e.stopCompose(challenge, dashboard)
+		err = e.stopCompose(challenge, dashboard)
 	case LauncherTypeDockerfile:
-		return e.stopDockerfile(challenge)
+		err = e.stopDockerfile(challenge)
 	case LauncherTypeKubernetes:
-		return e.stopKubernetes(challenge, dashboard)
+		err = e.stopKubernetes(challenge, dashboard)
 	default:
 		return fmt.Errorf("unknown launcher type: %s", dashboard.Type)
 	}
+	if err == nil {
+		// A warm pool instance handed to this one is gone once stopped
+		challenge.setProject("")
+	}
+	return err
 }
 
 // Restart restarts a challenge (stop then start)
 func (e *Executor) Restart(challenge *ChallengeInfo) error {
+	startedAt := time.Now()
+	err := e.restart(challenge)
+	launcherMetrics.observeOperation(opRestart, time.Since(startedAt), err)
+	return err
+}
+
+func (e *Executor) restart(challenge *ChallengeInfo) error {
 	log.InfoH2("Restarting challenge: %s", challenge.Name)
 
 	// Save allocated ports before stopping
 	allocatedPorts := challenge.GetAllocatedPorts()
 
-	if err := e.Stop(challenge); err != nil {
+	if err := e.stop(challenge); err != nil {
 		log.Error("Stop failed during restart: %v", err)
 		// Continue anyway - the service might not be running
 	}
@@ -117,7 +144,7 @@ func (e *Executor) Restart(challenge *ChallengeInfo) error {
 	// Small delay between stop and start
 	time.Sleep(2 * time.Second)
 
-	if err := e.Start(challenge); err != nil {
+	if err := e.start(challenge); err != nil {
 		return fmt.Errorf("start failed during restart: %w", err)
 	}
 
@@ -243,6 +270,20 @@ func randomizeComposePorts(compose map[string]interface{}, usedDockerPorts map[i
 	return modifiedCompose, allocatedPorts, nil
 }
 
+// dropContainerNames removes fixed container names from compose services so
+// several team instances of the same challenge can run side by side
+func dropContainerNames(compose map[string]interface{}) {
+	services, ok := compose["services"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	for _, service := range services {
+		if serviceMap, ok := service.(map[interface{}]interface{}); ok {
+			delete(serviceMap, "container_name")
+		}
+	}
+}
+
 // startCompose starts a Docker Compose challenge
 func (e *Executor) startCompose(challenge *ChallengeInfo, dashboard *Dashboard) error {
 	configPath := dashboard.Config
@@ -250,8 +291,12 @@ func (e *Executor) startCompose(challenge *ChallengeInfo, dashboard *Dashboard)
 		configPath = filepath.Join(challenge.Cwd, configPath)
 	}
 
+	if err := validateResources(dashboard.Resources); err != nil {
+		return err
+	}
+
 	log.InfoH2("Starting Docker Compose: %s", challenge.Name)
-	log.InfoH3("Config: %s, Project: %s", configPath, challenge.Slug)
+	log.InfoH3("Config: %s, Project: %s", configPath, challenge.projectName())
 
 	// Read and parse the compose file
 	//nolint:gosec // G304: Reading challenge configuration files is intentional
@@ -280,10 +325,17 @@ func (e *Executor) startCompose(challenge *ChallengeInfo, dashboard *Dashboard)
 	if err != nil {
 		return fmt.Errorf("failed to randomize ports: %w", err)
 	}
+	if challenge.Team != "" {
+		dropContainerNames(modifiedCompose)
+	}
+	applyComposeLimits(modifiedCompose, dashboard.Resources)
+	if dashboard.IsolatedNetwork {
+		isolateComposeNetwork(modifiedCompose)
+	}
 
 	// Create temporary compose file in the same directory
 	composeDir := filepath.Dir(configPath)
-	tempFile, err := os.CreateTemp(composeDir, fmt.Sprintf("docker-compose.%s.tmp.yml", challenge.Slug))
+	tempFile, err := os.CreateTemp(composeDir, fmt.Sprintf("docker-compose.%s.tmp.yml", challenge.projectName()))
 	if err != nil {
 		return fmt.Errorf("failed to create temp compose file: %w", err)
 	}
@@ -326,7 +378,7 @@ func (e *Executor) startCompose(challenge *ChallengeInfo, dashboard *Dashboard)
 	//nolint:gosec // G204: Docker commands with challenge config are intentional
 	cmd := exec.CommandContext(ctx, "docker", "compose",
 		"-f", tempFilePath,
-		"-p", challenge.Slug,
+		"-p", challenge.projectName(),
 		"up", "-d", "--build")
 	cmd.Dir = challenge.Cwd
 
@@ -372,7 +424,7 @@ func (e *Executor) stopCompose(challenge *ChallengeInfo, dashboard *Dashboard) e
 	//nolint:gosec // G204: Docker commands with challenge config are intentional and configPath is validated
 	cmd := exec.CommandContext(ctx, "docker", "compose",
 		"-f", configPath,
-		"-p", challenge.Slug,
+		"-p", challenge.projectName(),
 		"down", "--volumes")
 	cmd.Dir = challenge.Cwd
 
@@ -392,6 +444,10 @@ func (e *Executor) startDockerfile(challenge *ChallengeInfo, dashboard *Dashboar
 		configPath = filepath.Join(challenge.Cwd, configPath)
 	}
 
+	if err := validateResources(dashboard.Resources); err != nil {
+		return err
+	}
+
 	log.InfoH2("Starting Dockerfile: %s", challenge.Name)
 
 	// Build the image
@@ -413,9 +469,16 @@ func (e *Executor) startDockerfile(challenge *ChallengeInfo, dashboard *Dashboar
 	}
 
 	// Start the container
-	log.InfoH3("Starting container: %s", challenge.Slug)
+	log.InfoH3("Starting container: %s", challenge.projectName())
 
-	args := []string{"run", "-d", "--name", challenge.Slug}
+	args := []string{"run", "-d", "--name", challenge.projectName()}
+	args = append(args, dockerRunLimitArgs(dashboard.Resources)...)
+	if dashboard.IsolatedNetwork {
+		if err := createInstanceNetwork(ctx, challenge); err != nil {
+			return err
+		}
+		args = append(args, "--network", instanceNetwork(challenge))
+	}
 
 	// Get currently used ports on Docker host
 	usedDockerPorts, err := GetDockerUsedPorts()
@@ -497,6 +560,9 @@ func (e *Executor) startDockerfile(challenge *ChallengeInfo, dashboard *Dashboar
 	if err != nil {
 		// Clear allocated ports on failure
 		challenge.SetAllocatedPorts(nil)
+		if dashboard.IsolatedNetwork {
+			removeInstanceNetwork(ctx, challenge)
+		}
 		return fmt.Errorf("docker run failed: %w\nOutput: %s", err, string(output))
 	}
 
@@ -516,7 +582,7 @@ func (e *Executor) stopDockerfile(challenge *ChallengeInfo) error {
 
 	// Stop the container
 	//nolint:gosec // G204: Docker commands with challenge config are intentional
-	stopCmd := exec.CommandContext(ctx, "docker", "stop", challenge.Slug)
+	stopCmd := exec.CommandContext(ctx, "docker", "stop", challenge.projectName())
 	if output, err := stopCmd.CombinedOutput(); err != nil {
 		log.Error("docker stop failed: %v\nOutput: %s", err, string(output))
 		// Continue to try removing
@@ -524,11 +590,14 @@ func (e *Executor) stopDockerfile(challenge *ChallengeInfo) error {
 
 	// Remove the container
 	//nolint:gosec // G204: Docker commands with challenge config are intentional
-	rmCmd := exec.Command("docker", "rm", challenge.Slug)
+	rmCmd := exec.Command("docker", "rm", challenge.projectName())
 	output, err := rmCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("docker rm failed: %w\nOutput: %s", err, string(output))
 	}
+	if challenge.Dashboard != nil && challenge.Dashboard.IsolatedNetwork {
+		removeInstanceNetwork(ctx, challenge)
+	}
 
 	log.InfoH3("Dockerfile container stopped and removed successfully")
 	return nil
@@ -549,6 +618,10 @@ func (e *Executor) startKubernetes(challenge *ChallengeInfo, dashboard *Dashboar
 		return fmt.Errorf("invalid challenge slug: %s", challenge.Slug)
 	}
 
+	if challenge.Team != "" {
+		return fmt.Errorf("kubernetes challenges do not support per-team instances")
+	}
+
 	log.InfoH2("Starting Kubernetes: %s", challenge.Name)
 	log.InfoH3("Manifest: %s", configPath)
 
@@ -642,7 +715,7 @@ func (e *Executor) checkHealthCompose(challenge *ChallengeInfo) (bool, error) {
 	//nolint:gosec // G204: Docker commands with challenge config are intentional
 	cmd := exec.CommandContext(ctx, "docker", "compose",
 		"-f", configPath,
-		"-p", challenge.Slug,
+		"-p", challenge.projectName(),
 		"ps", "--format", "json")
 	cmd.Dir = challenge.Cwd
 
@@ -681,7 +754,7 @@ func (e *Executor) checkHealthDockerfile(challenge *ChallengeInfo) (bool, error)
 
 	//nolint:gosec // G204: Docker commands for health checks are intentional
 	cmd := exec.CommandContext(ctx, "docker", "ps",
-		"--filter", fmt.Sprintf("name=%s", challenge.Slug),
+		"--filter", fmt.Sprintf("name=^%s$", challenge.projectName()),
 		"--format", "json")
 
 	output, err := cmd.Output()
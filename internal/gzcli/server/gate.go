@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+// Gate types
+const (
+	GateTypePoW       = protocol.GateTypePoW
+	GateTypeTurnstile = protocol.GateTypeTurnstile
+	GateTypeHCaptcha  = protocol.GateTypeHCaptcha
+)
+
+const (
+	// defaultPoWDifficulty is the number of leading zero bits of a proof of
+	// work when no difficulty is configured, about a second in a browser
+	defaultPoWDifficulty = 18
+
+	// maxPoWDifficulty bounds configured difficulties, as each bit doubles
+	// the work of players
+	maxPoWDifficulty = 32
+
+	// powLifetime is how long a proof of work challenge may be solved
+	powLifetime = 5 * time.Minute
+
+	// captchaVerifyTimeout bounds the verification of a CAPTCHA token
+	captchaVerifyTimeout = 10 * time.Second
+)
+
+// Verification endpoints of the CAPTCHA providers. Variables so tests can
+// replace them.
+var (
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	hcaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+)
+
+// GateMessage tells a challenge page what to solve before a start or restart
+// request
+type GateMessage = protocol.Gate
+
+// powChallenge is a proof of work issued to a client
+type powChallenge struct {
+	value    string
+	issuedAt time.Time
+}
+
+// gateConfig returns the gate of the instance, or nil without one
+func (c *ChallengeInfo) gateConfig() *config.Gate {
+	if c.Dashboard == nil || c.Dashboard.Gate == nil || c.Dashboard.Gate.Type == "" {
+		return nil
+	}
+	return c.Dashboard.Gate
+}
+
+// powDifficulty returns the configured difficulty of a proof of work gate
+func powDifficulty(gate *config.Gate) int {
+	switch {
+	case gate.Difficulty <= 0:
+		return defaultPoWDifficulty
+	case gate.Difficulty > maxPoWDifficulty:
+		return maxPoWDifficulty
+	default:
+		return gate.Difficulty
+	}
+}
+
+// newPoWChallenge returns a random proof of work challenge
+func newPoWChallenge() *powChallenge {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return &powChallenge{value: hex.EncodeToString(b), issuedAt: time.Now()}
+}
+
+// validPoW reports whether SHA-256(challenge + ":" + solution) starts with
+// difficulty zero bits
+func validPoW(challenge, solution string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + solution))
+	zeros := 0
+	for _, b := range sum {
+		zeros += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+	return zeros >= difficulty
+}
+
+// gateMessage returns what the client must solve for the gate of challenge,
+// issuing it a new proof of work if needed, or nil without a gate
+func gateMessage(client *Client, challenge *ChallengeInfo) *GateMessage {
+	gate := challenge.gateConfig()
+	if gate == nil {
+		return nil
+	}
+	if !strings.EqualFold(gate.Type, GateTypePoW) {
+		return &GateMessage{Type: strings.ToLower(gate.Type), SiteKey: gate.SiteKey}
+	}
+	if client.pow == nil || time.Since(client.pow.issuedAt) > powLifetime {
+		client.pow = newPoWChallenge()
+	}
+	return &GateMessage{Type: GateTypePoW, Difficulty: powDifficulty(gate), Challenge: client.pow.value}
+}
+
+// sendGate sends the client what to solve before its next request, if the
+// challenge has a gate
+func (wm *WSManager) sendGate(client *Client, challenge *ChallengeInfo) {
+	if msg := gateMessage(client, challenge); msg != nil {
+		wm.sendToClient(client, WSMessage{Type: protocol.TypeGate, Data: msg})
+	}
+}
+
+// passGate checks the gate solution of a start or restart request. Each
+// proof of work is accepted once; the client is sent a new one either way.
+func (wm *WSManager) passGate(client *Client, challenge *ChallengeInfo, msg WSMessage) error {
+	gate := challenge.gateConfig()
+	if gate == nil {
+		return nil
+	}
+	defer wm.sendGate(client, challenge)
+
+	data, _ := msg.Data.(map[string]interface{})
+	solution, _ := data["gate"].(string)
+	if solution == "" {
+		return fmt.Errorf("solve the challenge check before starting the instance")
+	}
+
+	switch strings.ToLower(gate.Type) {
+	case GateTypePoW:
+		pow := client.pow
+		if pow == nil || time.Since(pow.issuedAt) > powLifetime {
+			return fmt.Errorf("proof of work expired, try again")
+		}
+		if !validPoW(pow.value, solution, powDifficulty(gate)) {
+			return fmt.Errorf("invalid proof of work")
+		}
+		client.pow = nil
+		return nil
+	case GateTypeTurnstile:
+		return verifyCaptcha(turnstileVerifyURL, gate, solution, client.IP)
+	case GateTypeHCaptcha:
+		return verifyCaptcha(hcaptchaVerifyURL, gate, solution, client.IP)
+	default:
+		log.Error("Unknown gate type %q of %s, refusing starts", gate.Type, challenge.Name)
+		return fmt.Errorf("challenge check is misconfigured, contact the organizers")
+	}
+}
+
+// verifyCaptcha checks a Turnstile or hCaptcha token with the siteverify
+// endpoint of its provider
+func verifyCaptcha(endpoint string, gate *config.Gate, token, ip string) error {
+	secret := os.Getenv(gate.SecretEnv)
+	if gate.SecretEnv == "" || secret == "" {
+		log.Error("Secret of the %s gate is not set (secretEnv %q)", gate.Type, gate.SecretEnv)
+		return fmt.Errorf("challenge check is misconfigured, contact the organizers")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), captchaVerifyTimeout)
+	defer cancel()
+	form := url.Values{"secret": {secret}, "response": {token}, "remoteip": {ip}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Error("Failed to verify %s token: %v", gate.Type, err)
+		return fmt.Errorf("failed to verify the challenge check, try again")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Invalid %s verification response: %v", gate.Type, err)
+		return fmt.Errorf("failed to verify the challenge check, try again")
+	}
+	if !result.Success {
+		log.Debug("Rejected %s token from %s: %v", gate.Type, maskIP(ip), result.ErrorCodes)
+		return fmt.Errorf("challenge check failed, try again")
+	}
+	return nil
+}
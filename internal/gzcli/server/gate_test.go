@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func newGateChallenge(gate *config.Gate) *ChallengeInfo {
+	return &ChallengeInfo{
+		Slug:      "ctf_web_chall",
+		Name:      "Chall",
+		Dashboard: &Dashboard{Type: "compose", Gate: gate},
+		Status:    StatusStopped,
+	}
+}
+
+// lastGate returns the gate message last sent to the client
+func lastGate(t *testing.T, client *Client) GateMessage {
+	t.Helper()
+	var gate GateMessage
+	for {
+		select {
+		case data := <-client.Send:
+			var msg struct {
+				Type string      `json:"type"`
+				Data GateMessage `json:"data"`
+			}
+			if err := json.Unmarshal(data, &msg); err != nil || msg.Type != "gate" {
+				t.Fatalf("sent %s, want a gate message", data)
+			}
+			gate = msg.Data
+		default:
+			return gate
+		}
+	}
+}
+
+func solve(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		if solution := strconv.Itoa(i); validPoW(challenge, solution, difficulty) {
+			return solution
+		}
+	}
+}
+
+func TestValidPoW(t *testing.T) {
+	// Solution found by the challenge page for a difficulty of 18
+	if !validPoW("0123456789abcdef0123456789abcdef", "261544", 18) {
+		t.Error("validPoW() rejected a solution of the challenge page")
+	}
+	if validPoW("0123456789abcdef0123456789abcdef", "261545", 18) {
+		t.Error("validPoW() accepted a wrong solution")
+	}
+	if !validPoW("anything", "0", 0) {
+		t.Error("validPoW() rejected a solution without difficulty")
+	}
+}
+
+func TestPassGate_PoW(t *testing.T) {
+	wm := &WSManager{}
+	challenge := newGateChallenge(&config.Gate{Type: "pow", Difficulty: 8})
+	client := &Client{IP: "10.0.0.1", Send: make(chan []byte, 16)}
+
+	wm.sendGate(client, challenge)
+	gate := lastGate(t, client)
+	if gate.Type != GateTypePoW || gate.Difficulty != 8 || gate.Challenge == "" {
+		t.Fatalf("gate = %+v, want a pow challenge of difficulty 8", gate)
+	}
+
+	if err := wm.passGate(client, challenge, WSMessage{Type: "start"}); err == nil {
+		t.Error("passGate() accepted a request without a solution")
+	}
+	solution := solve(gate.Challenge, 8)
+	start := WSMessage{Type: "start", Data: map[string]interface{}{"gate": solution}}
+	if err := wm.passGate(client, challenge, start); err != nil {
+		t.Fatalf("passGate() = %v, want the solution accepted", err)
+	}
+	next := lastGate(t, client)
+	if next.Challenge == "" || next.Challenge == gate.Challenge {
+		t.Errorf("next gate = %+v, want a new challenge", next)
+	}
+	if err := wm.passGate(client, challenge, start); err == nil {
+		t.Error("passGate() accepted a solution twice")
+	}
+}
+
+func TestPassGate_Captcha(t *testing.T) {
+	var gotSecret, gotToken string
+	verify := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSecret, gotToken = r.FormValue("secret"), r.FormValue("response")
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": gotToken == "good"})
+	}))
+	defer verify.Close()
+	original := turnstileVerifyURL
+	turnstileVerifyURL = verify.URL
+	defer func() { turnstileVerifyURL = original }()
+	t.Setenv("TEST_TURNSTILE_SECRET", "s3cret")
+
+	wm := &WSManager{}
+	challenge := newGateChallenge(&config.Gate{Type: "turnstile", SiteKey: "site", SecretEnv: "TEST_TURNSTILE_SECRET"})
+	client := &Client{IP: "10.0.0.1", Send: make(chan []byte, 16)}
+
+	wm.sendGate(client, challenge)
+	if gate := lastGate(t, client); gate.Type != GateTypeTurnstile || gate.SiteKey != "site" {
+		t.Errorf("gate = %+v, want the turnstile site key", gate)
+	}
+	if err := wm.passGate(client, challenge, WSMessage{Data: map[string]interface{}{"gate": "good"}}); err != nil {
+		t.Errorf("passGate() = %v, want the token accepted", err)
+	}
+	if gotSecret != "s3cret" || gotToken != "good" {
+		t.Errorf("verified secret %q and token %q", gotSecret, gotToken)
+	}
+	if err := wm.passGate(client, challenge, WSMessage{Data: map[string]interface{}{"gate": "bad"}}); err == nil {
+		t.Error("passGate() accepted a rejected token")
+	}
+
+	challenge.Dashboard.Gate.SecretEnv = "TEST_UNSET_SECRET"
+	if err := wm.passGate(client, challenge, WSMessage{Data: map[string]interface{}{"gate": "good"}}); err == nil {
+		t.Error("passGate() accepted a token without a secret")
+	}
+}
+
+func TestPassGate_NoGate(t *testing.T) {
+	wm := &WSManager{}
+	client := &Client{Send: make(chan []byte, 1)}
+	if err := wm.passGate(client, newGateChallenge(nil), WSMessage{Type: "start"}); err != nil {
+		t.Errorf("passGate() = %v, want requests allowed without a gate", err)
+	}
+	if len(client.Send) != 0 {
+		t.Error("no gate message should be sent without a gate")
+	}
+}
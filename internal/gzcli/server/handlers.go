@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/metrics"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
@@ -53,6 +56,89 @@ const homeTemplate = `{{define "home"}}
 </html>
 {{end}}`
 
+const eventTemplate = `{{define "event"}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}} - GZCLI Launcher</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            background: #0d1117;
+            min-height: 100vh;
+            color: #c9d1d9;
+            padding: 40px 20px;
+        }
+        .container { max-width: 960px; margin: 0 auto; }
+        h1 { font-size: 2em; margin-bottom: 6px; color: #58a6ff; font-weight: 600; }
+        .subtitle { color: #8b949e; margin-bottom: 30px; }
+        h2 { font-size: 1.1em; margin: 28px 0 12px; color: #8b949e; text-transform: uppercase; letter-spacing: 0.05em; }
+        .challenge {
+            display: flex;
+            align-items: center;
+            justify-content: space-between;
+            gap: 16px;
+            padding: 14px 18px;
+            margin-bottom: 10px;
+            background: #161b22;
+            border: 1px solid #30363d;
+            border-radius: 8px;
+        }
+        .name { font-weight: 600; color: #c9d1d9; text-decoration: none; }
+        .name:hover { color: #58a6ff; }
+        .badge {
+            display: inline-block;
+            margin-left: 10px;
+            padding: 2px 8px;
+            border-radius: 12px;
+            font-size: 0.75em;
+            border: 1px solid #30363d;
+            color: #8b949e;
+        }
+        .badge.running { color: #3fb950; border-color: #238636; }
+        .badge.starting, .badge.restarting, .badge.stopping { color: #d29922; border-color: #9e6a03; }
+        .badge.unhealthy { color: #f85149; border-color: #da3633; }
+        .actions { display: flex; gap: 8px; flex-shrink: 0; }
+        .button {
+            padding: 6px 14px;
+            border-radius: 6px;
+            font-size: 0.9em;
+            text-decoration: none;
+            border: 1px solid #30363d;
+            color: #c9d1d9;
+            background: #21262d;
+        }
+        .button:hover { border-color: #8b949e; }
+        .button.primary { background: #238636; border-color: #2ea043; color: #fff; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>🚀 {{.Title}}</h1>
+        <p class="subtitle">{{len .Challenges}} launchable challenge(s){{if .Team}} · team {{.Team}}{{end}}</p>
+        {{range .Categories}}
+        <h2>{{.Name}}</h2>
+        {{range .Challenges}}
+        <div class="challenge">
+            <div>
+                <a class="name" href="{{.URL}}">{{.Name}}</a>
+                <span class="badge {{.Status}}">{{.Status}}</span>
+            </div>
+            <div class="actions">
+                {{if eq .Status "stopped"}}<a class="button primary" href="{{.StartURL}}">Quick start</a>{{end}}
+                <a class="button" href="{{.URL}}">Open</a>
+            </div>
+        </div>
+        {{end}}
+        {{end}}
+    </div>
+</body>
+</html>
+{{end}}`
+
 const challengeTemplate = `{{define "challenge"}}
 <!DOCTYPE html>
 <html lang="en">
@@ -220,6 +306,29 @@ const challengeTemplate = `{{define "challenge"}}
                         <svg class="w-8 h-8 stroke-current group-hover:rotate-180 transition-transform duration-500" fill="none" viewBox="0 0 24 24" stroke="currentColor" stroke-width="2"><path stroke-linecap="round" stroke-linejoin="round" d="M4 4v5h.582m15.356 2A8.001 8.001 0 004.582 9m0 0H9m11 11v-5h-.581m0 0a8.003 8.003 0 01-15.357-2m15.357 2H15" /></svg>
                     </button>
                 </div>
+
+                <div id="gate-widget" class="hidden mt-6 flex justify-center"></div>
+
+                <div id="ttl-panel" class="hidden mt-6 flex items-center justify-center gap-3">
+                    <div class="text-left">
+                        <div class="text-[10px] font-mono text-gray-500 uppercase tracking-widest">Time Left</div>
+                        <div id="ttl-remaining" class="font-mono text-lg text-white">-</div>
+                    </div>
+                    <button id="btn-extend" onclick="requestExtend()" class="hidden px-4 py-2 rounded-xl bg-white/5 border border-white/10 text-sm text-white hover:bg-white/10 transition-all disabled:opacity-30 disabled:cursor-not-allowed">Extend</button>
+                </div>
+
+                <div id="resource-usage" class="hidden mt-6 grid grid-cols-2 gap-3 text-left">
+                    <div class="p-3 rounded-lg bg-white/5 border border-white/5">
+                        <div class="text-[10px] font-mono text-gray-500 uppercase tracking-widest mb-1">CPU</div>
+                        <div id="cpu-usage" class="font-mono text-sm text-white">-</div>
+                        <div class="h-1 mt-2 bg-white/10 rounded-full overflow-hidden"><div id="cpu-bar" class="h-full bg-brand transition-all duration-500" style="width: 0%"></div></div>
+                    </div>
+                    <div class="p-3 rounded-lg bg-white/5 border border-white/5">
+                        <div class="text-[10px] font-mono text-gray-500 uppercase tracking-widest mb-1">Memory</div>
+                        <div id="mem-usage" class="font-mono text-sm text-white">-</div>
+                        <div class="h-1 mt-2 bg-white/10 rounded-full overflow-hidden"><div id="mem-bar" class="h-full bg-brand transition-all duration-500" style="width: 0%"></div></div>
+                    </div>
+                </div>
             </div>
         </div>
 
@@ -231,7 +340,7 @@ const challengeTemplate = `{{define "challenge"}}
                         <span class="text-2xl">🗳️</span>
                     </div>
                     <div>
-                        <h3 class="font-bold text-lg">Restart Requested</h3>
+                        <h3 id="vote-title" class="font-bold text-lg">Restart Requested</h3>
                         <p id="vote-info" class="text-gray-400 text-sm">Consensus required to reboot instance.</p>
                     </div>
                 </div>
@@ -304,6 +413,20 @@ const challengeTemplate = `{{define "challenge"}}
         // Configuration
         const slug = '{{.Slug}}';
 
+        // Quick start from an event page: start once the current status is
+        // known, and drop the parameter so reloading does not start again
+        const pageParams = new URLSearchParams(window.location.search);
+        let autoStart = pageParams.get('start') === '1';
+        if (autoStart) {
+            pageParams.delete('start');
+            const query = pageParams.toString();
+            history.replaceState(null, '', window.location.pathname + (query ? '?' + query : ''));
+        }
+
+        // Launcher WebSocket API version (see the protocol package)
+        const PROTOCOL = 'gzcli.v2';
+        const PROTOCOL_VERSION = 2;
+
         let ws = null;
         let reconnectAttempts = 0;
         const maxReconnectDelay = 30000;
@@ -313,9 +436,9 @@ const challengeTemplate = `{{define "challenge"}}
             updateConnectionStatus('connecting');
 
             const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-            const wsUrl = protocol + '//' + window.location.host + '/' + slug + '/ws';
+            const wsUrl = protocol + '//' + window.location.host + '/' + slug + '/ws' + window.location.search;
 
-            ws = new WebSocket(wsUrl);
+            ws = new WebSocket(wsUrl, [PROTOCOL]);
 
             ws.onopen = () => {
                 console.log('WebSocket connected');
@@ -361,7 +484,7 @@ const challengeTemplate = `{{define "challenge"}}
 
         function send(type, data = {}) {
             if (ws && ws.readyState === WebSocket.OPEN) {
-                ws.send(JSON.stringify({ type, data }));
+                ws.send(JSON.stringify({ type, v: PROTOCOL_VERSION, data }));
             }
         }
 
@@ -369,12 +492,23 @@ const challengeTemplate = `{{define "challenge"}}
             console.log('Received:', msg);
 
             switch (msg.type) {
+                case 'hello':
                 case 'pong': break;
-                case 'status': updateStatus(msg.data); break;
+                case 'status':
+                    updateStatus(msg.data);
+                    if (autoStart) {
+                        autoStart = false;
+                        if (msg.data.status === 'stopped') startChallenge();
+                    }
+                    break;
+                case 'stats': updateResourceUsage(msg.data); break;
+                case 'gate': setGate(msg.data); break;
+                case 'ttl': updateTTL(msg.data); break;
                 case 'vote_started':
+                    voteAction = msg.data.action || 'restart';
                     showVotingPanel();
                     playAlarm();
-                    showMessage('info', 'Restart vote initiated by user');
+                    showMessage('info', (voteAction === 'extend' ? 'Extension' : 'Restart') + ' vote initiated by user');
                     break;
                 case 'vote_update': updateVoteProgress(msg.data); break;
                 case 'vote_ended':
@@ -383,6 +517,10 @@ const challengeTemplate = `{{define "challenge"}}
                     showMessage('info', 'Vote ended: ' + msg.data.result);
                     break;
                 case 'error': showMessage('error', msg.message); break;
+                case 'removed':
+                    showMessage('error', msg.message);
+                    showNotification('Challenge Removed', msg.message);
+                    break;
                 case 'info':
                     showMessage('info', msg.message);
                     if (msg.message.includes('started successfully') || msg.message.includes('ready')) {
@@ -435,10 +573,95 @@ const challengeTemplate = `{{define "challenge"}}
             });
         }
 
+        function reachabilityBadge(check) {
+            if (!check) return '';
+            const styles = {
+                checking: ['text-gray-400 border-gray-600', 'Checking…'],
+                reachable: ['text-green-400 border-green-500/40', 'Reachable'],
+                unreachable: ['text-red-400 border-red-500/40', 'Unreachable'],
+                no_reply: ['text-yellow-400 border-yellow-500/40', 'No reply']
+            };
+            const style = styles[check.state] || styles.checking;
+            const title = check.error ? String(check.error).split('&').join('&amp;').split('"').join('&quot;').split('<').join('&lt;') : style[1];
+            return '<span class="text-xs font-mono px-2 py-0.5 rounded border ' + style[0] + '" title="' + title + '">' + style[1] + '</span>';
+        }
+
+        function formatBytes(bytes) {
+            const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB'];
+            let i = 0;
+            while (bytes >= 1024 && i < units.length - 1) { bytes /= 1024; i++; }
+            return bytes.toFixed(i === 0 ? 0 : 1) + ' ' + units[i];
+        }
+
+        function usageBar(id, percent) {
+            const bar = document.getElementById(id);
+            if (!bar) return;
+            bar.style.width = Math.min(percent, 100) + '%';
+            bar.className = 'h-full transition-all duration-500 ' + (percent >= 90 ? 'bg-danger' : percent >= 70 ? 'bg-yellow-500' : 'bg-brand');
+        }
+
+        function updateResourceUsage(data) {
+            const panel = document.getElementById('resource-usage');
+            if (!panel || !data) return;
+            panel.classList.remove('hidden');
+
+            // CPU is relative to one core, like docker stats
+            document.getElementById('cpu-usage').textContent = data.cpu_percent.toFixed(1) + '%';
+            usageBar('cpu-bar', data.cpu_percent);
+
+            document.getElementById('mem-usage').textContent = formatBytes(data.memory_usage) +
+                (data.memory_limit ? ' / ' + formatBytes(data.memory_limit) : '');
+            usageBar('mem-bar', data.memory_percent);
+        }
+
+        // Countdown of the instance TTL, counted locally between broadcasts
+        let ttlDeadline = null;
+        let ttlTimer = null;
+
+        function renderTTL() {
+            const el = document.getElementById('ttl-remaining');
+            if (!el || ttlDeadline === null) return;
+            const left = Math.max(0, Math.floor((ttlDeadline - Date.now()) / 1000));
+            const h = Math.floor(left / 3600);
+            const m = Math.floor((left % 3600) / 60);
+            const s = left % 60;
+            el.textContent = (h > 0 ? h + ':' + String(m).padStart(2, '0') : m) + ':' + String(s).padStart(2, '0');
+            el.className = 'font-mono text-lg ' + (left <= 300 ? 'text-danger' : 'text-white');
+        }
+
+        function updateTTL(data) {
+            const panel = document.getElementById('ttl-panel');
+            if (!panel) return;
+            if (!data) {
+                panel.classList.add('hidden');
+                ttlDeadline = null;
+                if (ttlTimer) { clearInterval(ttlTimer); ttlTimer = null; }
+                return;
+            }
+            panel.classList.remove('hidden');
+            ttlDeadline = Date.now() + data.remaining_seconds * 1000;
+            if (!ttlTimer) ttlTimer = setInterval(renderTTL, 1000);
+            renderTTL();
+
+            const extendBtn = document.getElementById('btn-extend');
+            if (extendBtn) {
+                extendBtn.classList.toggle('hidden', !data.max_extensions);
+                extendBtn.disabled = data.extensions >= data.max_extensions;
+                extendBtn.textContent = 'Extend (' + (data.max_extensions - data.extensions) + ' left)';
+            }
+        }
+
         function updateStatus(data) {
             const statusEl = document.getElementById('status-text');
             if (statusEl) statusEl.textContent = data.status || 'Unknown';
 
+            updateTTL(data.status === 'running' ? data.ttl : null);
+
+            if (data.status !== 'running') {
+                const usagePanel = document.getElementById('resource-usage');
+                if (usagePanel) usagePanel.classList.add('hidden');
+            }
+
             const countEl = document.getElementById('user-count');
             if (countEl) {
                 countEl.innerHTML =
@@ -450,31 +673,41 @@ const challengeTemplate = `{{define "challenge"}}
             const portsList = document.getElementById('ports-list');
             if (portsList) {
                 if (data.status === 'running' && data.allocated_ports && data.allocated_ports.length > 0) {
+                    const checks = {};
+                    (data.port_checks || []).forEach(function(check) { checks[check.mapping] = check; });
+
                     let html = '';
                     data.allocated_ports.forEach(function(portMapping) {
-                        const parts = portMapping.split(':');
-                        const extPort = parts[0];
-                        const intPort = parts[1];
+                        const protoParts = portMapping.split('/');
+                        const protocol = (protoParts[1] || 'tcp').toLowerCase();
+                        const parts = protoParts[0].split(':');
+                        const extPort = parts[parts.length - 2];
+                        const intPort = parts[parts.length - 1];
                         const hostname = window.location.hostname;
-                        const httpUrl = 'http://' + hostname + ':' + extPort;
-                        const ncCmd = 'nc ' + hostname + ' ' + extPort;
+                        const httpUrl = data.proxy_path ?
+                            window.location.origin + data.proxy_path + ':' + intPort + '/' + window.location.search :
+                            'http://' + hostname + ':' + extPort;
+                        const ncCmd = (protocol === 'udp' ? 'nc -u ' : 'nc ') + hostname + ' ' + extPort;
+                        const reachability = reachabilityBadge(checks[portMapping]);
 
                         html += '<div class="group flex flex-col p-3 rounded-lg bg-white/5 border border-white/5 hover:bg-white/10 hover:border-white/20 transition-all gap-2">' +
                             '<div class="flex items-center justify-between">' +
                                 '<div class="flex flex-col">' +
-                                    '<span class="text-xs text-gray-500 font-mono text-gray-400">TCP Port Mapping</span>' +
+                                    '<span class="text-xs text-gray-500 font-mono text-gray-400">' + protocol.toUpperCase() + ' Port Mapping</span>' +
                                     '<div class="flex items-center gap-2">' +
                                         '<span class="text-lg font-mono font-bold text-white transition-colors">' + extPort + '</span>' +
                                         '<span class="text-sm text-gray-500 font-mono">→</span>' +
                                         '<span class="text-sm text-gray-400 font-mono">' + intPort + '</span>' +
                                     '</div>' +
                                 '</div>' +
+                                reachability +
                             '</div>' +
                             '<div class="flex gap-2">' +
+                                (protocol === 'tcp' ?
                                 '<button onclick="copyToClipboard(\'' + httpUrl + '\', this)" class="flex-1 flex items-center justify-center gap-2 p-2 rounded-lg bg-brand/10 border border-brand/20 hover:bg-brand/20 text-xs font-mono text-brand transition-all" title="Copy HTTP URL">' +
                                     '<span class="copy-icon flex items-center gap-1"><svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24"><path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M13.828 10.172a4 4 0 00-5.656 0l-4 4a4 4 0 105.656 5.656l1.102-1.101m-.758-4.899a4 4 0 005.656 0l4-4a4 4 0 00-5.656-5.656l-1.1 1.1"></path></svg> HTTP</span>' +
                                     '<span class="check-icon hidden text-green-500 font-bold">Copied!</span>' +
-                                '</button>' +
+                                '</button>' : '') +
                                 '<button onclick="copyToClipboard(\'' + ncCmd + '\', this)" class="flex-1 flex items-center justify-center gap-2 p-2 rounded-lg bg-white/5 border border-white/10 hover:bg-white/10 text-xs font-mono text-gray-300 transition-all" title="Copy NC Command">' +
                                     '<span class="copy-icon flex items-center gap-1"><svg class="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24"><path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M8 9l3 3-3 3m5 0h3M5 20h14a2 2 0 002-2V6a2 2 0 00-2-2H5a2 2 0 00-2 2v14a2 2 0 002 2z"></path></svg> NC</span>' +
                                     '<span class="check-icon hidden text-green-500 font-bold">Copied!</span>' +
@@ -499,6 +732,10 @@ const challengeTemplate = `{{define "challenge"}}
         }
 
         function showVotingPanel() {
+            const title = document.getElementById('vote-title');
+            const info = document.getElementById('vote-info');
+            if (title) title.textContent = voteAction === 'extend' ? 'Extension Requested' : 'Restart Requested';
+            if (info) info.textContent = voteAction === 'extend' ? 'Consensus required to extend the time limit.' : 'Consensus required to reboot instance.';
             const panel = document.getElementById('voting-panel');
             if (panel) panel.style.display = 'block';
         }
@@ -538,9 +775,11 @@ const challengeTemplate = `{{define "challenge"}}
             }
         }
 
-        function startChallenge() { send('start'); }
-        function requestRestart() { send('restart'); }
-        function vote(value) { send('vote', { value }); }
+        function startChallenge() { withGate(solution => send('start', { gate: solution })); }
+        function requestRestart() { withGate(solution => send('restart', { gate: solution })); }
+        function requestExtend() { send('extend'); }
+        let voteAction = 'restart';
+        function vote(value) { send('vote', { value, action: voteAction }); }
 
         function requestNotificationPermission() {
             if ('Notification' in window && Notification.permission === 'default') {
@@ -568,6 +807,137 @@ const challengeTemplate = `{{define "challenge"}}
             }
         }
 
+        // --- Start Gate ---
+        // Starts and restarts may require a proof of work or a CAPTCHA,
+        // announced by a 'gate' message
+        let gate = null;
+        let captchaToken = null;
+        let captchaWidget = null;
+        let solvingPoW = false;
+        const captchaProviders = {
+            turnstile: { src: 'https://challenges.cloudflare.com/turnstile/v0/api.js?render=explicit', api: () => window.turnstile },
+            hcaptcha: { src: 'https://js.hcaptcha.com/1/api.js?render=explicit', api: () => window.hcaptcha },
+        };
+
+        function setGate(data) {
+            const captchaChanged = !gate || gate.type !== data.type;
+            gate = data;
+            const provider = captchaProviders[gate.type];
+            if (!provider || !captchaChanged || captchaWidget !== null) return;
+
+            const container = document.getElementById('gate-widget');
+            container.classList.remove('hidden');
+            const script = document.createElement('script');
+            script.src = provider.src;
+            script.async = true;
+            script.onload = () => {
+                captchaWidget = provider.api().render(container, {
+                    sitekey: gate.site_key,
+                    theme: 'dark',
+                    callback: token => { captchaToken = token; },
+                    'expired-callback': () => { captchaToken = null; },
+                });
+            };
+            document.head.appendChild(script);
+        }
+
+        function withGate(request) {
+            if (!gate) {
+                request();
+                return;
+            }
+            if (gate.type === 'pow') {
+                if (solvingPoW) return;
+                solvingPoW = true;
+                showMessage('info', 'Solving proof of work...');
+                solvePoW(gate.challenge, gate.difficulty).then(solution => {
+                    solvingPoW = false;
+                    request(solution);
+                });
+                return;
+            }
+            if (!captchaToken) {
+                showMessage('error', 'Complete the challenge check first');
+                return;
+            }
+            // Tokens are accepted once
+            request(captchaToken);
+            captchaToken = null;
+            const provider = captchaProviders[gate.type];
+            if (provider && provider.api() && captchaWidget !== null) provider.api().reset(captchaWidget);
+        }
+
+        // solvePoW finds a solution whose SHA-256(challenge + ':' + solution)
+        // starts with difficulty zero bits, yielding to the page regularly
+        function solvePoW(challenge, difficulty) {
+            const encoder = new TextEncoder();
+            let counter = 0;
+            return new Promise(resolve => {
+                function batch() {
+                    for (let end = counter + 5000; counter < end; counter++) {
+                        if (leadingZeroBits(sha256(encoder.encode(challenge + ':' + counter))) >= difficulty) {
+                            resolve(String(counter));
+                            return;
+                        }
+                    }
+                    setTimeout(batch, 0);
+                }
+                batch();
+            });
+        }
+
+        function leadingZeroBits(hash) {
+            let zeros = 0;
+            for (const word of hash) {
+                zeros += Math.clz32(word);
+                if (word !== 0) break;
+            }
+            return zeros;
+        }
+
+        // Plain SHA-256, as crypto.subtle is missing on plain HTTP pages
+        const sha256K = new Uint32Array([
+            0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+            0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+            0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+            0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+            0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+            0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+            0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+            0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+        ]);
+
+        function sha256(bytes) {
+            const length = ((bytes.length + 9 + 63) >> 6) << 6;
+            const block = new Uint8Array(length);
+            block.set(bytes);
+            block[bytes.length] = 0x80;
+            const view = new DataView(block.buffer);
+            view.setUint32(length - 4, bytes.length * 8);
+
+            const h = new Uint32Array([0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19]);
+            const w = new Uint32Array(64);
+            const rotr = (x, n) => (x >>> n) | (x << (32 - n));
+            for (let offset = 0; offset < length; offset += 64) {
+                for (let i = 0; i < 16; i++) w[i] = view.getUint32(offset + i * 4);
+                for (let i = 16; i < 64; i++) {
+                    const s0 = rotr(w[i - 15], 7) ^ rotr(w[i - 15], 18) ^ (w[i - 15] >>> 3);
+                    const s1 = rotr(w[i - 2], 17) ^ rotr(w[i - 2], 19) ^ (w[i - 2] >>> 10);
+                    w[i] = w[i - 16] + s0 + w[i - 7] + s1;
+                }
+                let [a, b, c, d, e, f, g, hh] = h;
+                for (let i = 0; i < 64; i++) {
+                    const t1 = (hh + (rotr(e, 6) ^ rotr(e, 11) ^ rotr(e, 25)) + ((e & f) ^ (~e & g)) + sha256K[i] + w[i]) | 0;
+                    const t2 = ((rotr(a, 2) ^ rotr(a, 13) ^ rotr(a, 22)) + ((a & b) ^ (a & c) ^ (b & c))) | 0;
+                    hh = g; g = f; f = e; e = (d + t1) | 0;
+                    d = c; c = b; b = a; a = (t1 + t2) | 0;
+                }
+                h[0] += a; h[1] += b; h[2] += c; h[3] += d;
+                h[4] += e; h[5] += f; h[6] += g; h[7] += hh;
+            }
+            return h;
+        }
+
         // Start connection
         connect();
 
@@ -605,6 +975,11 @@ func (s *Server) LoadTemplates() error {
 		return err
 	}
 
+	tmpl, err = tmpl.Parse(eventTemplate)
+	if err != nil {
+		return err
+	}
+
 	tmpl, err = tmpl.Parse(challengeTemplate)
 	if err != nil {
 		return err
@@ -632,6 +1007,99 @@ func (s *Server) HandleHome(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// eventChallenge is a challenge listed on an event page
+type eventChallenge struct {
+	Name     string
+	Status   ChallengeStatus // Of the caller's instance
+	URL      string
+	StartURL string // Opens the challenge page and starts the instance
+}
+
+// eventCategory groups the challenges of an event page
+type eventCategory struct {
+	Name       string
+	Challenges []eventChallenge
+}
+
+// HandleEvent lists the launchable challenges of an event with the status of
+// the caller's instances, so players need a single URL per event. Quick start
+// goes through the challenge page, which enforces the same team token and
+// rate limits as starting a challenge by hand.
+func (s *Server) HandleEvent(w http.ResponseWriter, r *http.Request) {
+	eventName := r.PathValue("event")
+
+	var challenges []*ChallengeInfo
+	for _, c := range s.challenges.ListChallenges() {
+		if c.EventName == eventName {
+			challenges = append(challenges, c)
+		}
+	}
+	if len(challenges) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	team, err := requestTeam(r)
+	if err != nil {
+		http.Error(w, "A valid team token is required to access this event", http.StatusUnauthorized)
+		return
+	}
+
+	// Challenge links keep the team token of the event page
+	query := url.Values{}
+	if token := r.URL.Query().Get(TeamTokenParam); token != "" {
+		query.Set(TeamTokenParam, token)
+	}
+	link := func(slug string) string {
+		if len(query) == 0 {
+			return "/" + slug
+		}
+		return "/" + slug + "?" + query.Encode()
+	}
+	startQuery := url.Values{}
+	for k, v := range query {
+		startQuery[k] = v
+	}
+	startQuery.Set("start", "1")
+
+	sort.Slice(challenges, func(i, j int) bool {
+		if challenges[i].Category != challenges[j].Category {
+			return challenges[i].Category < challenges[j].Category
+		}
+		return challenges[i].Name < challenges[j].Name
+	})
+
+	var categories []eventCategory
+	for _, c := range challenges {
+		status := StatusStopped
+		if instance, ok := s.challenges.GetInstance(c.Slug, team, false); ok {
+			status = instance.GetStatus()
+		}
+		if len(categories) == 0 || categories[len(categories)-1].Name != c.Category {
+			categories = append(categories, eventCategory{Name: c.Category})
+		}
+		current := &categories[len(categories)-1]
+		current.Challenges = append(current.Challenges, eventChallenge{
+			Name:     c.Name,
+			Status:   status,
+			URL:      link(c.Slug),
+			StartURL: "/" + c.Slug + "?" + startQuery.Encode(),
+		})
+	}
+
+	data := map[string]interface{}{
+		"Title":      eventName,
+		"Team":       team,
+		"Challenges": challenges,
+		"Categories": categories,
+	}
+
+	if err := s.templates.ExecuteTemplate(w, "event", data); err != nil {
+		log.Error("Template error: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
 // HandleChallenge handles the challenge launcher page
 func (s *Server) HandleChallenge(w http.ResponseWriter, r *http.Request) {
 	// Extract slug from path
@@ -646,15 +1114,21 @@ func (s *Server) HandleChallenge(w http.ResponseWriter, r *http.Request) {
 
 	// Get challenge info
 	challenge, exists := s.challenges.GetChallenge(slug)
-	if !exists {
+	if !exists || challenge.Team != "" {
 		http.NotFound(w, r)
 		return
 	}
 
-	// Determine initial ports to display
+	team, err := requestTeam(r)
+	if err != nil {
+		http.Error(w, "A valid team token is required to access this challenge", http.StatusUnauthorized)
+		return
+	}
+
+	// Determine initial ports to display from the caller's own instance
 	var displayPorts []string
-	if challenge.GetStatus() == StatusRunning {
-		displayPorts = challenge.GetAllocatedPorts()
+	if instance, ok := s.challenges.GetInstance(slug, team, false); ok && instance.GetStatus() == StatusRunning {
+		displayPorts = instance.GetAllocatedPorts()
 	}
 
 	// Render challenge page
@@ -687,6 +1161,14 @@ func (s *Server) SetupRoutes() *http.ServeMux {
 		}
 	})
 
+	s.setupAPIRoutes(mux)
+	s.setupProxyRoutes(mux)
+	metrics.Default.Register("launcher", s)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+
+	// Event index pages
+	mux.HandleFunc("GET /events/{event}", s.HandleEvent)
+
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
 			s.HandleHome(w, r)
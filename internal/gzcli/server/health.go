@@ -63,7 +63,7 @@ func (hm *HealthMonitor) monitorLoop() {
 
 // performHealthChecks checks the health of all challenges
 func (hm *HealthMonitor) performHealthChecks() {
-	challenges := hm.challenges.ListChallenges()
+	challenges := hm.challenges.ListInstances()
 
 	for _, challenge := range challenges {
 		// Only check challenges that should be running
@@ -86,8 +86,8 @@ func (hm *HealthMonitor) performHealthChecks() {
 
 			// Broadcast status update
 			if hm.wsManager != nil {
-				hm.wsManager.broadcastStatus(challenge.Slug)
-				hm.wsManager.broadcastError(challenge.Slug, "Challenge is unhealthy. Please restart.")
+				hm.wsManager.broadcastStatus(challenge.InstanceKey())
+				hm.wsManager.broadcastError(challenge.InstanceKey(), "Challenge is unhealthy. Please restart.")
 			}
 		}
 	}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+const (
+	// IdleModeWebSocket stops an instance once its challenge page has been
+	// closed for the grace period
+	IdleModeWebSocket = "websocket"
+	// IdleModeTraffic also requires the containers of the instance to have
+	// moved less than the minimum traffic during the grace period
+	IdleModeTraffic = "traffic"
+
+	// defaultMinIdleTraffic is the traffic per grace period keeping an
+	// instance in traffic mode running when no minimum is configured
+	defaultMinIdleTraffic = 4 << 10
+)
+
+// sampleTraffic returns the bytes received and sent by the containers of a
+// running instance since they started. A variable so tests can replace it.
+var sampleTraffic = instanceTraffic
+
+// idleTraffic returns whether the instance detects idleness by traffic and
+// the traffic per grace period keeping it running
+func (c *ChallengeInfo) idleTraffic() (bool, uint64) {
+	if c.Dashboard == nil || c.Dashboard.Idle == nil || LauncherType(c.Dashboard.Type) == LauncherTypeKubernetes {
+		return false, 0
+	}
+	idle := c.Dashboard.Idle
+	if !strings.EqualFold(idle.Mode, IdleModeTraffic) {
+		return false, 0
+	}
+	if idle.MinTraffic == "" {
+		return true, defaultMinIdleTraffic
+	}
+	minTraffic, err := parseByteSize(idle.MinTraffic)
+	if err != nil {
+		log.Error("Invalid idle minTraffic of %s: %v, using %d bytes", c.Name, err, defaultMinIdleTraffic)
+		return true, defaultMinIdleTraffic
+	}
+	return true, minTraffic
+}
+
+// instanceTraffic sums the network I/O of the containers of an instance as
+// reported by docker stats
+func instanceTraffic(ctx context.Context, challenge *ChallengeInfo) (uint64, error) {
+	stats, err := dockerStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	projects, err := dockerComposeProjects(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	project := challenge.projectName()
+	var total uint64
+	found := false
+	for _, s := range stats {
+		owner := projects[s.Name]
+		if owner == "" {
+			owner = s.Name // Dockerfile instances run as a container named after the project
+		}
+		if owner != project {
+			continue
+		}
+		found = true
+		total += parseNetIO(s.NetIO)
+	}
+	if !found {
+		return 0, fmt.Errorf("no running containers of %s", project)
+	}
+	return total, nil
+}
+
+// parseNetIO parses a docker stats network I/O such as "1.2kB / 648B" into
+// the bytes received and sent
+func parseNetIO(s string) uint64 {
+	rx, tx, _ := strings.Cut(s, "/")
+	var total uint64
+	for _, part := range []string{rx, tx} {
+		if n, err := parseByteSize(part); err == nil {
+			total += n
+		}
+	}
+	return total
+}
+
+// trafficBaseline samples the traffic of an instance in traffic mode when its
+// auto-stop is scheduled; ok is false when the instance does not detect
+// idleness by traffic or could not be sampled
+func (wm *WSManager) trafficBaseline(challenge *ChallengeInfo) (uint64, bool) {
+	if enabled, _ := challenge.idleTraffic(); !enabled {
+		return 0, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+	traffic, err := sampleTraffic(ctx, challenge)
+	if err != nil {
+		log.Error("Failed to sample traffic of %s, auto-stopping by page presence only: %v", challenge.Name, err)
+		return 0, false
+	}
+	return traffic, true
+}
+
+// stillInUse reports whether an instance in traffic mode moved at least its
+// minimum traffic since baseline, i.e. players use it without the page open
+func (wm *WSManager) stillInUse(challenge *ChallengeInfo, baseline uint64) bool {
+	_, minTraffic := challenge.idleTraffic()
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+	traffic, err := sampleTraffic(ctx, challenge)
+	if err != nil {
+		log.Error("Failed to sample traffic of %s, treating it as idle: %v", challenge.Name, err)
+		return false
+	}
+	// Counters restart with the containers; a lower sample is all new traffic
+	moved := traffic
+	if traffic >= baseline {
+		moved = traffic - baseline
+	}
+	if moved < minTraffic {
+		return false
+	}
+	log.InfoH3("Keeping %s running: %d bytes of traffic during the grace period", challenge.Name, moved)
+	return true
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func TestParseNetIO(t *testing.T) {
+	if got := parseNetIO("1.5kB / 648B"); got != 2148 {
+		t.Errorf("parseNetIO() = %d, want 2148", got)
+	}
+	if got := parseNetIO("--"); got != 0 {
+		t.Errorf("parseNetIO(--) = %d, want 0", got)
+	}
+}
+
+func TestChallengeInfo_IdleTraffic(t *testing.T) {
+	tests := []struct {
+		name      string
+		dashboard *Dashboard
+		enabled   bool
+		min       uint64
+	}{
+		{"no idle config", &Dashboard{Type: "compose"}, false, 0},
+		{"websocket mode", &Dashboard{Type: "compose", Idle: &config.Idle{Mode: IdleModeWebSocket}}, false, 0},
+		{"default minimum", &Dashboard{Type: "compose", Idle: &config.Idle{Mode: "traffic"}}, true, defaultMinIdleTraffic},
+		{"configured minimum", &Dashboard{Type: "dockerfile", Idle: &config.Idle{Mode: "Traffic", MinTraffic: "64KiB"}}, true, 64 << 10},
+		{"invalid minimum", &Dashboard{Type: "compose", Idle: &config.Idle{Mode: "traffic", MinTraffic: "lots"}}, true, defaultMinIdleTraffic},
+		{"kubernetes", &Dashboard{Type: "kubernetes", Idle: &config.Idle{Mode: "traffic"}}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ChallengeInfo{Name: "chall", Dashboard: tt.dashboard}
+			enabled, minTraffic := c.idleTraffic()
+			if enabled != tt.enabled || minTraffic != tt.min {
+				t.Errorf("idleTraffic() = %v, %d; want %v, %d", enabled, minTraffic, tt.enabled, tt.min)
+			}
+		})
+	}
+}
+
+func TestPerformAutoStop_KeepsInstanceWithTraffic(t *testing.T) {
+	traffic := uint64(100 << 10)
+	orig := sampleTraffic
+	sampleTraffic = func(context.Context, *ChallengeInfo) (uint64, error) { return traffic, nil }
+	t.Cleanup(func() { sampleTraffic = orig })
+
+	cm := NewChallengeManager()
+	challenge := &ChallengeInfo{
+		Slug:         "ctf_web_chall",
+		Name:         "Chall",
+		Dashboard:    &Dashboard{Type: "compose", Idle: &config.Idle{Mode: IdleModeTraffic, MinTraffic: "16KiB", GracePeriod: time.Hour}},
+		Status:       StatusRunning,
+		ConnectedIPs: make(map[string]bool),
+	}
+	cm.challenges[challenge.Slug] = challenge
+	wm := NewWSManager(cm, NewExecutor(), NewVotingManager(), NewRateLimiter())
+	t.Cleanup(func() { wm.cancelAutoStop(challenge.Slug) })
+
+	baseline, byTraffic := wm.trafficBaseline(challenge)
+	if !byTraffic || baseline != traffic {
+		t.Fatalf("trafficBaseline() = %d, %v", baseline, byTraffic)
+	}
+
+	// Players moved 32 KiB through nc with the page closed
+	traffic += 32 << 10
+	wm.performAutoStop(challenge.Slug, baseline, byTraffic)
+
+	if status := challenge.GetStatus(); status != StatusRunning {
+		t.Fatalf("status = %s, want the instance kept running", status)
+	}
+	wm.autoStopMu.Lock()
+	_, rescheduled := wm.autoStopTimers[challenge.Slug]
+	wm.autoStopMu.Unlock()
+	if !rescheduled {
+		t.Error("expected the auto-stop to be scheduled again")
+	}
+
+	if wm.stillInUse(challenge, traffic-1024) {
+		t.Error("1 KiB of traffic should count as idle")
+	}
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ActionInventory lists running launcher instances with their endpoints and
+// container IDs, for export to infrastructure automation
+const ActionInventory = "inventory"
+
+// Inventory export formats
+const (
+	InventoryFormatTerraform = "terraform"
+	InventoryFormatAnsible   = "ansible"
+)
+
+// InventoryFormats lists the supported inventory export formats
+func InventoryFormats() []string {
+	return []string{InventoryFormatAnsible, InventoryFormatTerraform}
+}
+
+// Endpoint is a host port a running instance is reachable on
+type Endpoint struct {
+	Host          string `json:"host"`
+	Port          int    `json:"port"`
+	ContainerPort string `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+// InventoryInstance describes a running instance in an inventory export
+type InventoryInstance struct {
+	Slug       string     `json:"slug"`
+	Team       string     `json:"team"`
+	Event      string     `json:"event"`
+	Category   string     `json:"category"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`
+	Host       string     `json:"host"`
+	Endpoints  []Endpoint `json:"endpoints"`
+	Containers []string   `json:"containers"`
+}
+
+// key identifies the instance in inventories, as slug or slug--team
+func (inst InventoryInstance) key() string {
+	return instanceKey(inst.Slug, inst.Team)
+}
+
+// inventory snapshots the running instances, sorted by slug and team. host is the
+// address their allocated ports are published on.
+func (wm *WSManager) inventory(ctx context.Context, host string) []InventoryInstance {
+	var instances []InventoryInstance
+	for _, c := range wm.challenges.ListInstances() {
+		status := c.GetStatus()
+		if status != StatusRunning && status != StatusUnhealthy {
+			continue
+		}
+
+		inst := InventoryInstance{
+			Slug:       c.Slug,
+			Team:       c.Team,
+			Event:      c.EventName,
+			Category:   c.Category,
+			Name:       c.Name,
+			Status:     string(status),
+			Host:       host,
+			Endpoints:  []Endpoint{},
+			Containers: []string{},
+		}
+		if c.Dashboard != nil {
+			inst.Type = c.Dashboard.Type
+		}
+		for _, mapping := range c.GetAllocatedPorts() {
+			port, containerPort, protocol, err := ParsePortMapping(mapping)
+			if err != nil {
+				continue
+			}
+			inst.Endpoints = append(inst.Endpoints, Endpoint{Host: host, Port: port, ContainerPort: containerPort, Protocol: protocol})
+		}
+		if ids, err := wm.executor.ContainerIDs(ctx, c); err == nil {
+			inst.Containers = ids
+		}
+
+		instances = append(instances, inst)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Slug != instances[j].Slug {
+			return instances[i].Slug < instances[j].Slug
+		}
+		return instances[i].Team < instances[j].Team
+	})
+	return instances
+}
+
+// ContainerIDs returns the IDs of the containers of a running challenge, or
+// pod names for Kubernetes challenges
+func (e *Executor) ContainerIDs(ctx context.Context, challenge *ChallengeInfo) ([]string, error) {
+	if challenge.Dashboard == nil {
+		return nil, fmt.Errorf("challenge has no dashboard configuration")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch LauncherType(challenge.Dashboard.Type) {
+	case LauncherTypeCompose:
+		//nolint:gosec // G204: Docker commands with challenge config are intentional
+		cmd = exec.CommandContext(ctx, "docker", "ps", "-q", "--no-trunc",
+			"--filter", fmt.Sprintf("label=com.docker.compose.project=%s", challenge.InstanceKey()))
+	case LauncherTypeDockerfile:
+		//nolint:gosec // G204: Docker commands with challenge config are intentional
+		cmd = exec.CommandContext(ctx, "docker", "ps", "-q", "--no-trunc",
+			"--filter", fmt.Sprintf("name=^%s$", challenge.InstanceKey()))
+	case LauncherTypeKubernetes:
+		//nolint:gosec // G204: Kubectl commands with challenge config are intentional
+		cmd = exec.CommandContext(ctx, "kubectl", "get", "pods",
+			"-l", fmt.Sprintf("app=%s", challenge.Slug),
+			"-o", "jsonpath={.items[*].metadata.name}")
+	default:
+		return nil, fmt.Errorf("unknown launcher type: %s", challenge.Dashboard.Type)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	ids := strings.Fields(string(output))
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// DecodeInventory converts the "instances" of an ActionInventory response,
+// decoded as generic JSON, back into inventory instances
+func DecodeInventory(data interface{}) ([]InventoryInstance, error) {
+	if data == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	var instances []InventoryInstance
+	if err := json.Unmarshal(raw, &instances); err != nil {
+		return nil, fmt.Errorf("failed to decode inventory: %w", err)
+	}
+	return instances, nil
+}
+
+// FormatInventory renders instances in the given export format
+func FormatInventory(format string, instances []InventoryInstance) ([]byte, error) {
+	var v interface{}
+	switch format {
+	case InventoryFormatTerraform:
+		v = terraformInventory(instances)
+	case InventoryFormatAnsible:
+		v = ansibleInventory(instances)
+	default:
+		return nil, fmt.Errorf("unknown inventory format %q (want one of %s)", format, strings.Join(InventoryFormats(), ", "))
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// terraformInstanceType is the Terraform type expression of an instance in
+// the "instances" output
+var terraformInstanceType = []interface{}{"object", map[string]interface{}{
+	"slug":     "string",
+	"team":     "string",
+	"event":    "string",
+	"category": "string",
+	"name":     "string",
+	"type":     "string",
+	"status":   "string",
+	"host":     "string",
+	"endpoints": []interface{}{"list", []interface{}{"object", map[string]interface{}{
+		"host":           "string",
+		"port":           "number",
+		"container_port": "string",
+		"protocol":       "string",
+	}}},
+	"containers": []interface{}{"list", "string"},
+}}
+
+// terraformInventory builds a Terraform v4 state document. The instances are
+// exposed as the "instances" output, keyed by instance key, so they can be read with
+// the terraform_remote_state data source (local backend), and as one
+// gzcli_launcher_instance resource per instance.
+func terraformInventory(instances []InventoryInstance) map[string]interface{} {
+	byslug := make(map[string]InventoryInstance, len(instances))
+	resources := make([]map[string]interface{}, 0, len(instances))
+	for _, inst := range instances {
+		byslug[inst.key()] = inst
+		resources = append(resources, map[string]interface{}{
+			"mode":     "managed",
+			"type":     "gzcli_launcher_instance",
+			"name":     inst.key(),
+			"provider": `provider["registry.terraform.io/dimasma0305/gzcli"]`,
+			"instances": []map[string]interface{}{{
+				"schema_version": 0,
+				"attributes":     inst,
+			}},
+		})
+	}
+
+	return map[string]interface{}{
+		"version":           4,
+		"terraform_version": "1.0.0",
+		"serial":            1,
+		"lineage":           "gzcli-launcher",
+		"outputs": map[string]interface{}{
+			"instances": map[string]interface{}{
+				"value": byslug,
+				"type":  []interface{}{"map", terraformInstanceType},
+			},
+		},
+		"resources": resources,
+	}
+}
+
+// ansibleInventory builds an Ansible YAML/JSON inventory with every instance
+// in the gzcli_launcher group and in a group per event and launcher type.
+// The instance details are host variables prefixed with gzcli_.
+func ansibleInventory(instances []InventoryInstance) map[string]interface{} {
+	hosts := make(map[string]interface{}, len(instances))
+	children := map[string]interface{}{}
+	addToGroup := func(group, slug string) {
+		g, ok := children[group].(map[string]interface{})
+		if !ok {
+			g = map[string]interface{}{"hosts": map[string]interface{}{}}
+			children[group] = g
+		}
+		g["hosts"].(map[string]interface{})[slug] = map[string]interface{}{}
+	}
+
+	for _, inst := range instances {
+		ports := make([]int, 0, len(inst.Endpoints))
+		for _, ep := range inst.Endpoints {
+			ports = append(ports, ep.Port)
+		}
+		hosts[inst.key()] = map[string]interface{}{
+			"ansible_host":     inst.Host,
+			"gzcli_team":       inst.Team,
+			"gzcli_event":      inst.Event,
+			"gzcli_category":   inst.Category,
+			"gzcli_name":       inst.Name,
+			"gzcli_type":       inst.Type,
+			"gzcli_status":     inst.Status,
+			"gzcli_ports":      ports,
+			"gzcli_endpoints":  inst.Endpoints,
+			"gzcli_containers": inst.Containers,
+		}
+		addToGroup(ansibleGroupName("event", inst.Event), inst.key())
+		if inst.Type != "" {
+			addToGroup(ansibleGroupName("type", inst.Type), inst.key())
+		}
+	}
+
+	children["gzcli_launcher"] = map[string]interface{}{"hosts": hosts}
+	return map[string]interface{}{
+		"all": map[string]interface{}{"children": children},
+	}
+}
+
+// ansibleGroupName builds a valid Ansible group name from a prefix and value
+func ansibleGroupName(prefix, value string) string {
+	var b strings.Builder
+	b.WriteString("gzcli_" + prefix + "_")
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
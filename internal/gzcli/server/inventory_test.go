@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func sampleInventory() []InventoryInstance {
+	return []InventoryInstance{{
+		Slug:       "ctf_web_chall",
+		Event:      "ctf",
+		Category:   "Web",
+		Name:       "Chall",
+		Type:       "compose",
+		Status:     "running",
+		Host:       "ctf.example.com",
+		Endpoints:  []Endpoint{{Host: "ctf.example.com", Port: 31337, ContainerPort: "80", Protocol: "tcp"}},
+		Containers: []string{"abc123"},
+	}}
+}
+
+func TestWSManager_Inventory(t *testing.T) {
+	handler, cm, _ := newTestNotifyHandler(t)
+	running := &ChallengeInfo{
+		Slug:           "ctf_pwn_bof",
+		EventName:      "ctf",
+		Category:       "Pwn",
+		Name:           "BOF",
+		Status:         StatusRunning,
+		AllocatedPorts: []string{"31337:1337", "31338:53/udp"},
+	}
+	cm.challenges[running.Slug] = running
+
+	instances := handler.wsManager.inventory(context.Background(), "ctf.example.com")
+	if len(instances) != 1 || instances[0].Slug != "ctf_pwn_bof" {
+		t.Fatalf("inventory() = %+v, want only the running instance", instances)
+	}
+
+	want := []Endpoint{
+		{Host: "ctf.example.com", Port: 31337, ContainerPort: "1337", Protocol: "tcp"},
+		{Host: "ctf.example.com", Port: 31338, ContainerPort: "53", Protocol: "udp"},
+	}
+	got := instances[0].Endpoints
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Endpoints = %+v, want %+v", got, want)
+	}
+	if instances[0].Containers == nil {
+		t.Error("Containers should be an empty list, not null, when they cannot be looked up")
+	}
+}
+
+func TestFormatInventory_Ansible(t *testing.T) {
+	data, err := FormatInventory(InventoryFormatAnsible, sampleInventory())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inv struct {
+		All struct {
+			Children map[string]struct {
+				Hosts map[string]map[string]interface{} `json:"hosts"`
+			} `json:"children"`
+		} `json:"all"`
+	}
+	if err := json.Unmarshal(data, &inv); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	host := inv.All.Children["gzcli_launcher"].Hosts["ctf_web_chall"]
+	if host["ansible_host"] != "ctf.example.com" {
+		t.Errorf("ansible_host = %v, want ctf.example.com", host["ansible_host"])
+	}
+	if ports, _ := host["gzcli_ports"].([]interface{}); len(ports) != 1 || ports[0] != float64(31337) {
+		t.Errorf("gzcli_ports = %v, want [31337]", host["gzcli_ports"])
+	}
+	for _, group := range []string{"gzcli_event_ctf", "gzcli_type_compose"} {
+		if _, ok := inv.All.Children[group].Hosts["ctf_web_chall"]; !ok {
+			t.Errorf("host missing from group %s", group)
+		}
+	}
+}
+
+func TestFormatInventory_Terraform(t *testing.T) {
+	data, err := FormatInventory(InventoryFormatTerraform, sampleInventory())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state struct {
+		Version int `json:"version"`
+		Outputs map[string]struct {
+			Value map[string]InventoryInstance `json:"value"`
+			Type  interface{}                  `json:"type"`
+		} `json:"outputs"`
+		Resources []struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if state.Version != 4 {
+		t.Errorf("version = %d, want 4", state.Version)
+	}
+	inst, ok := state.Outputs["instances"].Value["ctf_web_chall"]
+	if !ok || inst.Endpoints[0].Port != 31337 || inst.Containers[0] != "abc123" {
+		t.Errorf("instances output = %+v", state.Outputs["instances"].Value)
+	}
+	if state.Outputs["instances"].Type == nil {
+		t.Error("output is missing its type")
+	}
+	if len(state.Resources) != 1 || state.Resources[0].Name != "ctf_web_chall" {
+		t.Errorf("resources = %+v, want one per instance", state.Resources)
+	}
+}
+
+func TestFormatInventory_Unknown(t *testing.T) {
+	if _, err := FormatInventory("csv", nil); err == nil {
+		t.Error("FormatInventory() should reject unknown formats")
+	}
+}
+
+func TestDecodeInventory(t *testing.T) {
+	raw, _ := json.Marshal(sampleInventory())
+	var generic interface{}
+	_ = json.Unmarshal(raw, &generic)
+
+	instances, err := DecodeInventory(generic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].Endpoints[0].ContainerPort != "80" {
+		t.Errorf("DecodeInventory() = %+v", instances)
+	}
+}
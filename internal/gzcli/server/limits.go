@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// memoryLimitRegex matches a docker memory limit such as "512m" or "1.5g"
+var memoryLimitRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[bkmgBKMG]?$`)
+
+// validateResources checks the resource limits of a dashboard before they
+// are passed to docker
+func validateResources(res *config.Resources) error {
+	if res == nil {
+		return nil
+	}
+	if res.CPUs != "" {
+		if cpus, err := strconv.ParseFloat(res.CPUs, 64); err != nil || cpus <= 0 {
+			return fmt.Errorf("invalid cpus %q: must be a positive number of cores", res.CPUs)
+		}
+	}
+	if res.Memory != "" && !memoryLimitRegex.MatchString(res.Memory) {
+		return fmt.Errorf("invalid memory %q: must be a size such as 256m or 1g", res.Memory)
+	}
+	if res.PidsLimit < 0 {
+		return fmt.Errorf("invalid pidsLimit %d: must be positive", res.PidsLimit)
+	}
+	return nil
+}
+
+// dockerRunLimitArgs returns the docker run flags applying the resource
+// limits of a dashboard
+func dockerRunLimitArgs(res *config.Resources) []string {
+	if res == nil {
+		return nil
+	}
+	var args []string
+	if res.CPUs != "" {
+		args = append(args, "--cpus", res.CPUs)
+	}
+	if res.Memory != "" {
+		args = append(args, "--memory", res.Memory)
+	}
+	if res.PidsLimit > 0 {
+		args = append(args, "--pids-limit", strconv.Itoa(res.PidsLimit))
+	}
+	return args
+}
+
+// applyComposeLimits overrides the resource limits of every compose service
+// with those of the dashboard
+func applyComposeLimits(compose map[string]interface{}, res *config.Resources) {
+	if res == nil {
+		return
+	}
+	forEachComposeService(compose, func(service map[interface{}]interface{}) {
+		if res.CPUs != "" {
+			service["cpus"] = res.CPUs
+		}
+		if res.Memory != "" {
+			service["mem_limit"] = res.Memory
+		}
+		if res.PidsLimit > 0 {
+			service["pids_limit"] = res.PidsLimit
+		}
+	})
+}
+
+// isolateComposeNetwork attaches every compose service only to the default
+// bridge network of the project, dropping host networking and shared or
+// external networks so instances cannot reach each other
+func isolateComposeNetwork(compose map[string]interface{}) {
+	forEachComposeService(compose, func(service map[interface{}]interface{}) {
+		if mode, ok := service["network_mode"].(string); ok && !strings.HasPrefix(mode, "service:") {
+			delete(service, "network_mode")
+		}
+		delete(service, "networks")
+	})
+	compose["networks"] = map[interface{}]interface{}{
+		"default": map[interface{}]interface{}{"driver": "bridge"},
+	}
+}
+
+func forEachComposeService(compose map[string]interface{}, fn func(service map[interface{}]interface{})) {
+	services, ok := compose["services"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	for _, service := range services {
+		if serviceMap, ok := service.(map[interface{}]interface{}); ok {
+			fn(serviceMap)
+		}
+	}
+}
+
+// instanceNetwork is the name of the isolated bridge network of a Dockerfile
+// instance
+func instanceNetwork(challenge *ChallengeInfo) string {
+	return challenge.projectName() + "_net"
+}
+
+// createInstanceNetwork creates the isolated bridge network of a Dockerfile
+// instance, reusing one left by an earlier run
+func createInstanceNetwork(ctx context.Context, challenge *ChallengeInfo) error {
+	name := instanceNetwork(challenge)
+	//nolint:gosec // G204: Docker commands with challenge config are intentional
+	output, err := exec.CommandContext(ctx, "docker", "network", "create", "--driver", "bridge", name).CombinedOutput()
+	if err != nil && !strings.Contains(string(output), "already exists") {
+		return fmt.Errorf("docker network create failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// removeInstanceNetwork removes the isolated bridge network of a Dockerfile
+// instance
+func removeInstanceNetwork(ctx context.Context, challenge *ChallengeInfo) {
+	name := instanceNetwork(challenge)
+	//nolint:gosec // G204: Docker commands with challenge config are intentional
+	output, err := exec.CommandContext(ctx, "docker", "network", "rm", name).CombinedOutput()
+	if err != nil && !strings.Contains(string(output), "not found") {
+		log.Error("docker network rm %s failed: %v\nOutput: %s", name, err, string(output))
+	}
+}
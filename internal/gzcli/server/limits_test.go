@@ -0,0 +1,99 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func TestValidateResources(t *testing.T) {
+	valid := []*config.Resources{
+		nil,
+		{},
+		{CPUs: "0.5", Memory: "256m", PidsLimit: 100},
+		{Memory: "1.5G"},
+	}
+	for _, res := range valid {
+		if err := validateResources(res); err != nil {
+			t.Errorf("validateResources(%+v) = %v", res, err)
+		}
+	}
+
+	invalid := []*config.Resources{
+		{CPUs: "half"},
+		{CPUs: "0"},
+		{Memory: "256 MiB"},
+		{PidsLimit: -1},
+	}
+	for _, res := range invalid {
+		if err := validateResources(res); err == nil {
+			t.Errorf("validateResources(%+v) = nil, want an error", res)
+		}
+	}
+}
+
+func TestDockerRunLimitArgs(t *testing.T) {
+	got := dockerRunLimitArgs(&config.Resources{CPUs: "0.5", Memory: "256m", PidsLimit: 64})
+	want := []string{"--cpus", "0.5", "--memory", "256m", "--pids-limit", "64"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dockerRunLimitArgs() = %v, want %v", got, want)
+	}
+	if got := dockerRunLimitArgs(nil); got != nil {
+		t.Errorf("dockerRunLimitArgs(nil) = %v, want none", got)
+	}
+}
+
+func TestComposeLimitsAndIsolation(t *testing.T) {
+	var compose map[string]interface{}
+	err := yaml.Unmarshal([]byte(`
+services:
+  web:
+    image: web
+    mem_limit: 4g
+    networks: [shared]
+  bot:
+    image: bot
+    network_mode: host
+  sidecar:
+    image: sidecar
+    network_mode: "service:web"
+networks:
+  shared:
+    external: true
+`), &compose)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applyComposeLimits(compose, &config.Resources{CPUs: "1", Memory: "512m", PidsLimit: 128})
+	isolateComposeNetwork(compose)
+
+	services := compose["services"].(map[interface{}]interface{})
+	for name, service := range services {
+		s := service.(map[interface{}]interface{})
+		if s["cpus"] != "1" || s["mem_limit"] != "512m" || s["pids_limit"] != 128 {
+			t.Errorf("service %v has limits %v %v %v", name, s["cpus"], s["mem_limit"], s["pids_limit"])
+		}
+		if _, ok := s["networks"]; ok {
+			t.Errorf("service %v still joins shared networks", name)
+		}
+	}
+	if _, ok := services["bot"].(map[interface{}]interface{})["network_mode"]; ok {
+		t.Error("host networking should be dropped")
+	}
+	if mode := services["sidecar"].(map[interface{}]interface{})["network_mode"]; mode != "service:web" {
+		t.Errorf("network_mode of a service sharing another's network = %v, want kept", mode)
+	}
+
+	out, err := yaml.Marshal(compose)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "external") || !strings.Contains(string(out), "driver: bridge") {
+		t.Errorf("unexpected networks:\n%s", out)
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/metrics"
+)
+
+// Executor operations counted and timed by the metrics
+const (
+	opStart   = "start"
+	opStop    = "stop"
+	opRestart = "restart"
+)
+
+// operationBuckets are the upper bounds, in seconds, of the executor
+// operation duration histogram. Builds can take the whole executor timeout.
+var operationBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600}
+
+// instanceStatuses are the statuses instance counts are always reported for
+var instanceStatuses = []ChallengeStatus{StatusStopped, StatusStarting, StatusRunning, StatusStopping, StatusRestarting, StatusUnhealthy}
+
+// Metrics holds the launcher counters exported in the Prometheus text format
+// on /metrics. Gauges are computed when scraped.
+type Metrics struct {
+	mu           sync.Mutex
+	operations   map[[2]string]uint64          // {operation, result} -> count
+	durations    map[string]*metrics.Histogram // operation -> durations
+	votesStarted uint64
+	voteOutcomes map[string]uint64 // outcome -> count
+	wsAccepted   uint64
+}
+
+// NewMetrics creates empty launcher metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		operations:   make(map[[2]string]uint64),
+		durations:    make(map[string]*metrics.Histogram),
+		voteOutcomes: make(map[string]uint64),
+	}
+}
+
+// launcherMetrics are the metrics of the running launcher
+var launcherMetrics = NewMetrics()
+
+// observeOperation records an executor operation, its result and duration
+func (m *Metrics) observeOperation(operation string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations[[2]string{operation, result}]++
+	h, ok := m.durations[operation]
+	if !ok {
+		h = metrics.NewHistogram(operationBuckets)
+		m.durations[operation] = h
+	}
+	h.Observe(duration.Seconds())
+}
+
+// voteStarted counts a restart or extension vote being started
+func (m *Metrics) voteStarted() {
+	m.mu.Lock()
+	m.votesStarted++
+	m.mu.Unlock()
+}
+
+// voteEnded counts the outcome of a restart or extension vote
+func (m *Metrics) voteEnded(outcome string) {
+	m.mu.Lock()
+	m.voteOutcomes[outcome]++
+	m.mu.Unlock()
+}
+
+// websocketAccepted counts an upgraded WebSocket connection
+func (m *Metrics) websocketAccepted() {
+	m.mu.Lock()
+	m.wsAccepted++
+	m.mu.Unlock()
+}
+
+// Export writes the metrics in the Prometheus text exposition format, with
+// the instance counts of challenges and the open connections of wsManager
+func (m *Metrics) Export(w io.Writer, challenges *ChallengeManager, wsManager *WSManager) error {
+	var b strings.Builder
+
+	byStatus := make(map[ChallengeStatus]int)
+	if challenges != nil {
+		for _, instance := range challenges.ListInstances() {
+			byStatus[instance.GetStatus()]++
+		}
+	}
+	metrics.WriteHeader(&b, "gzcli_launcher_instances", "gauge", "Challenge instances by status.")
+	for _, status := range instanceStatuses {
+		fmt.Fprintf(&b, "gzcli_launcher_instances{status=%q} %d\n", status, byStatus[status])
+	}
+
+	connections := 0
+	byInstance := make(map[string]int)
+	if wsManager != nil {
+		wsManager.mu.RLock()
+		for key, clients := range wsManager.clients {
+			connections += len(clients)
+			if len(clients) > 0 {
+				byInstance[key] = len(clients)
+			}
+		}
+		wsManager.mu.RUnlock()
+	}
+	metrics.WriteHeader(&b, "gzcli_launcher_websocket_connections", "gauge", "Open WebSocket connections.")
+	fmt.Fprintf(&b, "gzcli_launcher_websocket_connections %d\n", connections)
+
+	metrics.WriteHeader(&b, "gzcli_launcher_challenge_websocket_connections", "gauge", "Open WebSocket connections by challenge instance.")
+	keys := make([]string, 0, len(byInstance))
+	for key := range byInstance {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "gzcli_launcher_challenge_websocket_connections{instance=%q} %d\n", key, byInstance[key])
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics.WriteHeader(&b, "gzcli_launcher_websocket_connections_total", "counter", "WebSocket connections accepted.")
+	fmt.Fprintf(&b, "gzcli_launcher_websocket_connections_total %d\n", m.wsAccepted)
+
+	metrics.WriteHeader(&b, "gzcli_launcher_operations_total", "counter", "Executor operations by operation and result.")
+	for _, operation := range []string{opStart, opStop, opRestart} {
+		for _, result := range []string{"success", "failure"} {
+			fmt.Fprintf(&b, "gzcli_launcher_operations_total{operation=%q,result=%q} %d\n", operation, result, m.operations[[2]string{operation, result}])
+		}
+	}
+
+	metrics.WriteHeader(&b, "gzcli_launcher_operation_duration_seconds", "histogram", "Duration of executor operations.")
+	for _, operation := range []string{opStart, opStop, opRestart} {
+		h := m.durations[operation]
+		if h == nil {
+			h = metrics.NewHistogram(operationBuckets)
+		}
+		h.Write(&b, "gzcli_launcher_operation_duration_seconds", fmt.Sprintf("operation=%q", operation))
+	}
+
+	metrics.WriteHeader(&b, "gzcli_launcher_votes_started_total", "counter", "Restart and extension votes started.")
+	fmt.Fprintf(&b, "gzcli_launcher_votes_started_total %d\n", m.votesStarted)
+
+	metrics.WriteHeader(&b, "gzcli_launcher_votes_total", "counter", "Restart and extension votes ended by outcome.")
+	outcomes := make([]string, 0, len(m.voteOutcomes))
+	for outcome := range m.voteOutcomes {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+	for _, outcome := range outcomes {
+		fmt.Fprintf(&b, "gzcli_launcher_votes_total{outcome=%q} %d\n", outcome, m.voteOutcomes[outcome])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Collect implements metrics.Collector with the metrics of the launcher
+func (s *Server) Collect(w io.Writer) error {
+	return launcherMetrics.Export(w, s.challenges, s.wsManager)
+}
+
+// handleMetrics serves the metrics of the process to Prometheus: those of
+// the launcher and of the watcher or API client running with it. When the
+// REST API is enabled, its token is required as a bearer token.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics.Default.Handler(getAPIToken()).ServeHTTP(w, r)
+}
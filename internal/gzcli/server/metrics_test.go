@@ -0,0 +1,107 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Export(t *testing.T) {
+	_, cm, _ := newTestNotifyHandler(t)
+	m := NewMetrics()
+	m.observeOperation(opStart, 3*time.Second, nil)
+	m.observeOperation(opStart, 45*time.Second, errors.New("build failed"))
+	m.observeOperation(opRestart, time.Second, nil)
+	m.voteStarted()
+	m.voteEnded("approved")
+	m.websocketAccepted()
+
+	var b strings.Builder
+	if err := m.Export(&b, cm, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`gzcli_launcher_instances{status="stopped"} 1`,
+		`gzcli_launcher_instances{status="running"} 0`,
+		`gzcli_launcher_websocket_connections 0`,
+		`gzcli_launcher_websocket_connections_total 1`,
+		`gzcli_launcher_operations_total{operation="start",result="success"} 1`,
+		`gzcli_launcher_operations_total{operation="start",result="failure"} 1`,
+		`gzcli_launcher_operations_total{operation="stop",result="success"} 0`,
+		`gzcli_launcher_operation_duration_seconds_bucket{operation="start",le="1"} 0`,
+		`gzcli_launcher_operation_duration_seconds_bucket{operation="start",le="5"} 1`,
+		`gzcli_launcher_operation_duration_seconds_bucket{operation="start",le="60"} 2`,
+		`gzcli_launcher_operation_duration_seconds_bucket{operation="start",le="+Inf"} 2`,
+		`gzcli_launcher_operation_duration_seconds_sum{operation="start"} 48`,
+		`gzcli_launcher_operation_duration_seconds_bucket{operation="restart",le="1"} 1`,
+		`gzcli_launcher_votes_started_total 1`,
+		`gzcli_launcher_votes_total{outcome="approved"} 1`,
+		`# TYPE gzcli_launcher_operation_duration_seconds histogram`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetrics_Endpoint(t *testing.T) {
+	ts, _ := newTestAPIServer(t, "secret")
+
+	get := func(token string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/metrics", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, _ := get("wrong"); code != http.StatusUnauthorized {
+		t.Errorf("wrong token: status = %d, want 401", code)
+	}
+	code, body := get("secret")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if !strings.Contains(body, `gzcli_launcher_instances{status="stopped"} 1`) {
+		t.Errorf("metrics missing the instance count:\n%s", body)
+	}
+}
+
+func TestMetrics_ExportChallengeConnections(t *testing.T) {
+	handler, cm, _ := newTestNotifyHandler(t)
+	handler.wsManager.clients["ctf_web_chall--team-a"] = map[*Client]bool{{}: true, {}: true}
+	handler.wsManager.clients["ctf_web_chall"] = map[*Client]bool{{}: true}
+	handler.wsManager.clients["ctf_web_gone"] = map[*Client]bool{}
+
+	var b strings.Builder
+	if err := NewMetrics().Export(&b, cm, handler.wsManager); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`gzcli_launcher_websocket_connections 3`,
+		`gzcli_launcher_challenge_websocket_connections{instance="ctf_web_chall"} 1`,
+		`gzcli_launcher_challenge_websocket_connections{instance="ctf_web_chall--team-a"} 2`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ctf_web_gone") {
+		t.Errorf("metrics list an instance without connections:\n%s", out)
+	}
+}
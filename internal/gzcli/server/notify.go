@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+// DefaultSocketPath is the Unix socket the launcher listens on for watcher
+// notifications and programmatic instance control
+const DefaultSocketPath = ".gzcli/launcher/launcher.sock"
+
+// ActionChallengeRemoved notifies the launcher that a challenge was removed from the workspace.
+// The command carries Data["event"], Data["path"] with the challenge directory
+// and Data["challenge"] with the watcher's challenge name.
+const ActionChallengeRemoved = "challenge_removed"
+
+// Launcher socket actions for programmatic instance control
+const (
+	// ActionListInstances lists launcher challenges and their status
+	ActionListInstances = "list_instances"
+	// ActionStartInstance starts the challenge with Data["slug"]
+	ActionStartInstance = "start_instance"
+)
+
+// InstanceInfo describes a launcher challenge returned by ActionListInstances
+type InstanceInfo struct {
+	Slug           string         `json:"slug"`
+	Team           string         `json:"team,omitempty"`
+	Event          string         `json:"event"`
+	Category       string         `json:"category"`
+	Name           string         `json:"name"`
+	Status         string         `json:"status"`
+	ConnectedUsers int            `json:"connected_users"`
+	AllocatedPorts []string       `json:"allocated_ports,omitempty"`
+	PortChecks     []PortCheck    `json:"port_checks,omitempty"`
+	Usage          *ResourceUsage `json:"usage,omitempty"`
+	TTL            *InstanceTTL   `json:"ttl,omitempty"`
+}
+
+// notifyHandler processes commands received on the launcher socket
+type notifyHandler struct {
+	wsManager *WSManager
+}
+
+// HandleCommand implements socket.CommandHandler
+func (h *notifyHandler) HandleCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	switch cmd.Action {
+	case ActionChallengeRemoved:
+		event, _ := cmd.Data["event"].(string)
+		if event == "" {
+			event = cmd.Event
+		}
+		dir, _ := cmd.Data["path"].(string)
+		name, _ := cmd.Data["challenge"].(string)
+		if event == "" || dir == "" {
+			return watchertypes.WatcherResponse{
+				Success: false,
+				Error:   "event and path are required",
+			}
+		}
+		if name == "" {
+			name = dir
+		}
+
+		if !h.wsManager.removeChallenge(event, dir) {
+			return watchertypes.WatcherResponse{
+				Success: true,
+				Message: fmt.Sprintf("Challenge %s is not managed by the launcher", name),
+			}
+		}
+		return watchertypes.WatcherResponse{
+			Success: true,
+			Message: fmt.Sprintf("Challenge %s removed from launcher", name),
+		}
+	case ActionListInstances:
+		return h.handleListInstances()
+	case ActionStartInstance:
+		return h.handleStartInstance(cmd)
+	case ActionInventory:
+		host, _ := cmd.Data["host"].(string)
+		if host == "" {
+			host = getProbeHost()
+		}
+		return watchertypes.WatcherResponse{
+			Success: true,
+			Data:    map[string]interface{}{"instances": h.wsManager.inventory(context.Background(), host)},
+		}
+	default:
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Unknown action: %s", cmd.Action),
+		}
+	}
+}
+
+func (h *notifyHandler) handleListInstances() watchertypes.WatcherResponse {
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Data:    map[string]interface{}{"instances": h.wsManager.listInstances()},
+	}
+}
+
+func (h *notifyHandler) handleStartInstance(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	slug, _ := cmd.Data["slug"].(string)
+	challenge, exists := h.wsManager.challenges.GetChallenge(slug)
+	if !exists {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Challenge not found: %s", slug),
+		}
+	}
+
+	switch challenge.GetStatus() {
+	case StatusRunning, StatusStarting, StatusRestarting:
+		return watchertypes.WatcherResponse{
+			Success: true,
+			Message: fmt.Sprintf("Challenge %s is already %s", challenge.Name, challenge.GetStatus()),
+		}
+	}
+
+	h.wsManager.startChallenge(challenge)
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Starting challenge %s", challenge.Name),
+	}
+}
+
+// removeChallenge stops any running instance of a removed challenge, notifies
+// connected users and drops it from the launcher. Returns false if the
+// challenge is unknown to the launcher.
+func (wm *WSManager) removeChallenge(eventName, dir string) bool {
+	challenge, exists := wm.challenges.FindChallengeByDir(eventName, dir)
+	if !exists {
+		return false
+	}
+	slug := challenge.Slug
+
+	log.InfoH2("Challenge removed from workspace, cleaning up launcher instance: %s", challenge.Name)
+
+	for _, instance := range wm.challenges.ListInstances() {
+		if instance.Slug == slug {
+			wm.removeInstance(instance)
+		}
+	}
+
+	wm.warmPool.Remove(slug)
+	wm.challenges.RemoveChallenge(slug)
+	return true
+}
+
+// removeInstance stops one instance of a removed challenge and tells its
+// connected users
+func (wm *WSManager) removeInstance(challenge *ChallengeInfo) {
+	key := challenge.InstanceKey()
+
+	wm.autoStopMu.Lock()
+	if timer, exists := wm.autoStopTimers[key]; exists {
+		timer.Stop()
+		delete(wm.autoStopTimers, key)
+	}
+	wm.autoStopMu.Unlock()
+
+	if wm.voting != nil && wm.voting.HasActiveVote(key) {
+		wm.voting.EndVote(key, "challenge removed")
+	}
+
+	wm.broadcastRemoved(key, "This challenge has been removed by the organizers. Its instance is being stopped.")
+
+	if challenge.GetStatus() != StatusStopped {
+		challenge.SetStatus(StatusStopping)
+		wm.broadcastStatus(key)
+
+		if err := wm.executor.Stop(challenge); err != nil {
+			log.Error("Failed to stop removed challenge %s: %v", challenge.Name, err)
+		}
+	}
+	challenge.SetStatus(StatusStopped)
+	challenge.SetAllocatedPorts(nil)
+	wm.broadcastStatus(key)
+}
+
+func (wm *WSManager) broadcastRemoved(slug, message string) {
+	msg := WSMessage{
+		Type:    protocol.TypeRemoved,
+		Message: message,
+	}
+	wm.broadcast(slug, msg)
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func newTestNotifyHandler(t *testing.T) (*notifyHandler, *ChallengeManager, string) {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "web", "chall")
+	cm := NewChallengeManager()
+	cm.challenges["ctf_web_chall"] = &ChallengeInfo{
+		Slug:         "ctf_web_chall",
+		EventName:    "ctf",
+		Category:     "Web",
+		Name:         "Chall",
+		Cwd:          dir,
+		Status:       StatusStopped,
+		ConnectedIPs: make(map[string]bool),
+	}
+
+	wm := NewWSManager(cm, NewExecutor(), NewVotingManager(), NewRateLimiter())
+	return &notifyHandler{wsManager: wm}, cm, dir
+}
+
+func TestNotifyHandler_ChallengeRemoved(t *testing.T) {
+	handler, cm, dir := newTestNotifyHandler(t)
+
+	resp := handler.HandleCommand(watchertypes.WatcherCommand{
+		Action: ActionChallengeRemoved,
+		Data:   map[string]interface{}{"event": "ctf", "path": dir, "challenge": "web/chall"},
+	})
+	if !resp.Success {
+		t.Fatalf("HandleCommand() failed: %s", resp.Error)
+	}
+	if _, exists := cm.GetChallenge("ctf_web_chall"); exists {
+		t.Error("removed challenge should no longer be served by the launcher")
+	}
+}
+
+func TestNotifyHandler_ChallengeRemoved_OtherEvent(t *testing.T) {
+	handler, cm, dir := newTestNotifyHandler(t)
+
+	resp := handler.HandleCommand(watchertypes.WatcherCommand{
+		Action: ActionChallengeRemoved,
+		Data:   map[string]interface{}{"event": "other", "path": dir},
+	})
+	if !resp.Success {
+		t.Fatalf("HandleCommand() failed: %s", resp.Error)
+	}
+	if _, exists := cm.GetChallenge("ctf_web_chall"); !exists {
+		t.Error("challenge of another event must not be removed")
+	}
+}
+
+func TestNotifyHandler_InvalidCommands(t *testing.T) {
+	handler, _, _ := newTestNotifyHandler(t)
+
+	tests := []struct {
+		name string
+		cmd  watchertypes.WatcherCommand
+	}{
+		{"missing path", watchertypes.WatcherCommand{Action: ActionChallengeRemoved, Data: map[string]interface{}{"event": "ctf"}}},
+		{"unknown action", watchertypes.WatcherCommand{Action: "bogus"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := handler.HandleCommand(tt.cmd); resp.Success {
+				t.Error("HandleCommand() should fail")
+			}
+		})
+	}
+}
+
+func TestNotifyHandler_ListInstances(t *testing.T) {
+	handler, _, _ := newTestNotifyHandler(t)
+
+	resp := handler.HandleCommand(watchertypes.WatcherCommand{Action: ActionListInstances})
+	if !resp.Success {
+		t.Fatalf("HandleCommand() failed: %s", resp.Error)
+	}
+	instances, ok := resp.Data["instances"].([]InstanceInfo)
+	if !ok || len(instances) != 1 {
+		t.Fatalf("instances = %#v, want one instance", resp.Data["instances"])
+	}
+	if instances[0].Slug != "ctf_web_chall" || instances[0].Status != string(StatusStopped) {
+		t.Errorf("instance = %+v", instances[0])
+	}
+}
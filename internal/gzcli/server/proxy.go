@@ -0,0 +1,224 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+const (
+	// ProxyPathPrefix is the path under which proxy mode exposes running
+	// instances: /c/{slug}/... reaches the first TCP port of the instance, and
+	// /c/{slug}:{port}/... the one mapped to that container port
+	ProxyPathPrefix = "/c/"
+
+	// proxyTokenCookie keeps the team token of proxied requests, since pages
+	// served by a challenge do not carry it in their links
+	proxyTokenCookie = "gzcli_team_token"
+
+	// proxyUpstreamHost is where the allocated host ports of instances listen
+	proxyUpstreamHost = "127.0.0.1"
+
+	// proxyContentSecurityPolicy sandboxes proxied pages into an opaque
+	// origin: challenges are served from the launcher's origin and must not
+	// script its pages, read its storage or call its API as the player
+	proxyContentSecurityPolicy = "sandbox allow-scripts allow-forms allow-popups allow-modals allow-downloads"
+)
+
+var (
+	proxyEnabled   bool
+	proxyEnabledMu sync.RWMutex
+)
+
+// SetProxyMode makes the launcher reverse-proxy HTTP requests under
+// ProxyPathPrefix to the running instances, so only the launcher's port needs
+// to be exposed publicly. WebSocket upgrades are proxied too.
+func SetProxyMode(enabled bool) {
+	proxyEnabledMu.Lock()
+	defer proxyEnabledMu.Unlock()
+	proxyEnabled = enabled
+}
+
+func getProxyMode() bool {
+	proxyEnabledMu.RLock()
+	defer proxyEnabledMu.RUnlock()
+	return proxyEnabled
+}
+
+// proxyPath returns the path proxying to an instance of a challenge, or ""
+// when proxy mode is disabled
+func proxyPath(slug string) string {
+	if !getProxyMode() {
+		return ""
+	}
+	return ProxyPathPrefix + slug
+}
+
+// setupProxyRoutes registers the reverse proxy to running instances
+func (s *Server) setupProxyRoutes(mux *http.ServeMux) {
+	if !getProxyMode() {
+		return
+	}
+	mux.HandleFunc(ProxyPathPrefix+"{target}", func(w http.ResponseWriter, r *http.Request) {
+		target := *r.URL
+		target.Path += "/"
+		http.Redirect(w, r, target.String(), http.StatusMovedPermanently)
+	})
+	mux.HandleFunc(ProxyPathPrefix+"{target}/{path...}", s.handleProxy)
+}
+
+// handleProxy forwards a request to the port of a running instance selected
+// by the {target} of its path
+func (s *Server) handleProxy(w http.ResponseWriter, r *http.Request) {
+	slug, containerPort, _ := strings.Cut(r.PathValue("target"), ":")
+
+	team, err := proxyTeam(w, r)
+	if err != nil {
+		http.Error(w, "A valid team token is required to access this challenge", http.StatusUnauthorized)
+		return
+	}
+	instance, ok := s.challenges.GetInstance(slug, team, false)
+	if !ok || instance.Team != team {
+		http.NotFound(w, r)
+		return
+	}
+	if instance.GetStatus() != StatusRunning {
+		http.Error(w, "The challenge is not running, start it from its launcher page", http.StatusServiceUnavailable)
+		return
+	}
+	hostPort, ok := proxyPort(instance.GetAllocatedPorts(), containerPort)
+	if !ok {
+		http.Error(w, "The challenge exposes no such TCP port", http.StatusNotFound)
+		return
+	}
+
+	// Proxied responses may stream or be upgraded to WebSockets, which the
+	// server's timeouts would cut
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	prefix := ProxyPathPrefix + r.PathValue("target")
+	upstream := &url.URL{Scheme: "http", Host: net.JoinHostPort(proxyUpstreamHost, strconv.Itoa(hostPort))}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(upstream)
+			pr.Out.URL.Path = "/" + r.PathValue("path")
+			pr.Out.URL.RawPath = ""
+			query := pr.Out.URL.Query()
+			if query.Has(TeamTokenParam) {
+				query.Del(TeamTokenParam)
+				pr.Out.URL.RawQuery = query.Encode()
+			}
+			pr.Out.Header.Del("Cookie")
+			if cookies := withoutProxyCookie(pr.In.Cookies()); cookies != "" {
+				pr.Out.Header.Set("Cookie", cookies)
+			}
+			pr.SetXForwarded()
+			pr.Out.Header.Set("X-Forwarded-Prefix", prefix)
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			// Keep redirects of the instance under the proxy path
+			if location := resp.Header.Get("Location"); strings.HasPrefix(location, "/") && !strings.HasPrefix(location, "//") {
+				resp.Header.Set("Location", prefix+location)
+			}
+			// Added to the policy of the instance, if any, since both apply
+			resp.Header.Add("Content-Security-Policy", proxyContentSecurityPolicy)
+			scopeProxyCookies(resp.Header, prefix)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, _ *http.Request, err error) {
+			log.Error("Proxy to %s port %d failed: %v", instance.InstanceKey(), hostPort, err)
+			http.Error(w, "The challenge did not answer", http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// proxyTeam returns the team of a proxied request from its token parameter,
+// remembered in a cookie scoped to the proxied instance, or from that cookie
+func proxyTeam(w http.ResponseWriter, r *http.Request) (string, error) {
+	if getTeamIsolation() == nil {
+		return "", nil
+	}
+	token := r.URL.Query().Get(TeamTokenParam)
+	if token == "" {
+		if cookie, err := r.Cookie(proxyTokenCookie); err == nil {
+			token = cookie.Value
+		}
+	}
+	team, err := getTeamIsolation().Team(token)
+	if err != nil {
+		return "", err
+	}
+	if r.URL.Query().Has(TeamTokenParam) {
+		http.SetCookie(w, &http.Cookie{
+			Name:     proxyTokenCookie,
+			Value:    token,
+			Path:     ProxyPathPrefix + r.PathValue("target") + "/",
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return team, nil
+}
+
+// withoutProxyCookie returns the Cookie header of cookies without the team
+// token, which must not leak to the challenge
+func withoutProxyCookie(cookies []*http.Cookie) string {
+	kept := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		if c.Name != proxyTokenCookie {
+			kept = append(kept, c.String())
+		}
+	}
+	return strings.Join(kept, "; ")
+}
+
+// scopeProxyCookies keeps the cookies set by an instance under its proxy path
+// and host, so a challenge cannot overwrite the cookies of the launcher or of
+// other challenges
+func scopeProxyCookies(header http.Header, prefix string) {
+	setCookies := header.Values("Set-Cookie")
+	if len(setCookies) == 0 {
+		return
+	}
+	header.Del("Set-Cookie")
+	for _, line := range setCookies {
+		cookie, err := http.ParseSetCookie(line)
+		if err != nil || cookie.Name == proxyTokenCookie {
+			continue
+		}
+		path := cookie.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/"
+		}
+		cookie.Path = prefix + path
+		cookie.Domain = ""
+		header.Add("Set-Cookie", cookie.String())
+	}
+}
+
+// proxyPort returns the host port of the TCP mapping of containerPort among
+// the allocated ports of an instance, or of the first TCP mapping when
+// containerPort is empty
+func proxyPort(mappings []string, containerPort string) (int, bool) {
+	for _, mapping := range mappings {
+		hostPort, port, protocol, err := ParsePortMapping(mapping)
+		if err != nil || protocol != "tcp" {
+			continue
+		}
+		if containerPort == "" || port == containerPort {
+			return hostPort, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestProxyServer(t *testing.T) (*httptest.Server, *ChallengeManager, int) {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		if r.URL.Path == "/cookies" {
+			w.Header().Set("Content-Security-Policy", "default-src 'self'")
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", Domain: "example.com"})
+			http.SetCookie(w, &http.Cookie{Name: "admin", Value: "1", Path: "/admin"})
+			http.SetCookie(w, &http.Cookie{Name: proxyTokenCookie, Value: "forged"})
+		}
+		_, _ = fmt.Fprintf(w, "%s?%s cookie=%s prefix=%s", r.URL.Path, r.URL.RawQuery, r.Header.Get("Cookie"), r.Header.Get("X-Forwarded-Prefix"))
+	}))
+	t.Cleanup(upstream.Close)
+	u, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := 0
+	_, _ = fmt.Sscanf(u.Port(), "%d", &port)
+
+	handler, cm, _ := newTestNotifyHandler(t)
+	SetProxyMode(true)
+	t.Cleanup(func() { SetProxyMode(false) })
+
+	s := NewServer(cm, handler.wsManager)
+	ts := httptest.NewServer(s.SetupRoutes())
+	t.Cleanup(ts.Close)
+	return ts, cm, port
+}
+
+func proxyGet(t *testing.T, client *http.Client, rawURL string) (int, string, *http.Response) {
+	t.Helper()
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), resp
+}
+
+func TestProxy_ForwardsToRunningInstance(t *testing.T) {
+	ts, cm, port := newTestProxyServer(t)
+	client := ts.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	if code, _, _ := proxyGet(t, client, ts.URL+"/c/ctf_web_chall/"); code != http.StatusServiceUnavailable {
+		t.Errorf("stopped instance = %d, want 503", code)
+	}
+
+	challenge, _ := cm.GetChallenge("ctf_web_chall")
+	challenge.SetAllocatedPorts([]string{"1/udp", fmt.Sprintf("%d:80", port), "9:1337"})
+	challenge.SetStatus(StatusRunning)
+
+	code, body, _ := proxyGet(t, client, ts.URL+"/c/ctf_web_chall/a/b?x=1")
+	if code != http.StatusOK || body != "/a/b?x=1 cookie= prefix=/c/ctf_web_chall" {
+		t.Errorf("proxied request = %d %q", code, body)
+	}
+	if code, body, _ := proxyGet(t, client, ts.URL+"/c/ctf_web_chall:80/"); code != http.StatusOK || !strings.HasPrefix(body, "/?") {
+		t.Errorf("request to container port 80 = %d %q", code, body)
+	}
+	if code, _, _ := proxyGet(t, client, ts.URL+"/c/ctf_web_chall:8080/"); code != http.StatusNotFound {
+		t.Errorf("request to an unmapped container port = %d, want 404", code)
+	}
+	if code, _, resp := proxyGet(t, client, ts.URL+"/c/ctf_web_chall/login"); code != http.StatusFound || resp.Header.Get("Location") != "/c/ctf_web_chall/home" {
+		t.Errorf("redirect = %d to %q, want it kept under the proxy path", code, resp.Header.Get("Location"))
+	}
+	if code, _, resp := proxyGet(t, client, ts.URL+"/c/ctf_web_chall"); code != http.StatusMovedPermanently || resp.Header.Get("Location") != "/c/ctf_web_chall/" {
+		t.Errorf("path without trailing slash = %d to %q", code, resp.Header.Get("Location"))
+	}
+	if code, _, _ := proxyGet(t, client, ts.URL+"/c/missing/"); code != http.StatusNotFound {
+		t.Errorf("unknown slug = %d, want 404", code)
+	}
+}
+
+func TestProxy_SandboxesResponses(t *testing.T) {
+	ts, cm, port := newTestProxyServer(t)
+	challenge, _ := cm.GetChallenge("ctf_web_chall")
+	challenge.SetAllocatedPorts([]string{fmt.Sprintf("%d:80", port)})
+	challenge.SetStatus(StatusRunning)
+
+	code, _, resp := proxyGet(t, ts.Client(), ts.URL+"/c/ctf_web_chall/cookies")
+	if code != http.StatusOK {
+		t.Fatalf("proxied request = %d", code)
+	}
+	policies := resp.Header.Values("Content-Security-Policy")
+	if len(policies) != 2 || policies[0] != "default-src 'self'" || policies[1] != proxyContentSecurityPolicy {
+		t.Errorf("Content-Security-Policy = %q, want the instance's policy and the sandbox", policies)
+	}
+
+	got := map[string]string{}
+	for _, c := range resp.Cookies() {
+		got[c.Name] = c.Path
+		if c.Domain != "" {
+			t.Errorf("cookie %s has domain %q, want a host-only cookie", c.Name, c.Domain)
+		}
+	}
+	want := map[string]string{"session": "/c/ctf_web_chall/", "admin": "/c/ctf_web_chall/admin"}
+	if len(got) != len(want) || got["session"] != want["session"] || got["admin"] != want["admin"] {
+		t.Errorf("cookie paths = %v, want %v", got, want)
+	}
+}
+
+func TestProxy_TeamIsolation(t *testing.T) {
+	ts, cm, port := newTestProxyServer(t)
+	ti, _ := NewTeamIsolation(map[string]string{"red": "s3cret"})
+	SetTeamIsolation(ti)
+	t.Cleanup(func() { SetTeamIsolation(nil) })
+
+	instance, _ := cm.GetInstance("ctf_web_chall", "red", true)
+	instance.SetAllocatedPorts([]string{fmt.Sprintf("%d:80", port)})
+	instance.SetStatus(StatusRunning)
+
+	if code, _, _ := proxyGet(t, ts.Client(), ts.URL+"/c/ctf_web_chall/"); code != http.StatusUnauthorized {
+		t.Errorf("request without a token = %d, want 401", code)
+	}
+	if code, _, _ := proxyGet(t, ts.Client(), ts.URL+"/c/ctf_web_chall--red/?token=s3cret"); code != http.StatusNotFound {
+		t.Errorf("request to a team instance key = %d, want 404", code)
+	}
+
+	code, body, resp := proxyGet(t, ts.Client(), ts.URL+"/c/ctf_web_chall/?token=s3cret&x=1")
+	if code != http.StatusOK || body != "/?x=1 cookie= prefix=/c/ctf_web_chall" {
+		t.Fatalf("request with a token = %d %q, want the token stripped", code, body)
+	}
+	var token *http.Cookie
+	for _, c := range resp.Cookies() {
+		if c.Name == proxyTokenCookie {
+			token = c
+		}
+	}
+	if token == nil || token.Path != "/c/ctf_web_chall/" {
+		t.Fatalf("token cookie = %+v, want one scoped to the proxy path", token)
+	}
+
+	// Later requests of the challenge's pages only carry the cookie
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/c/ctf_web_chall/page", nil)
+	req.AddCookie(token)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(b) != "/page? cookie=session=abc prefix=/c/ctf_web_chall" {
+		t.Errorf("request with the token cookie = %d %q, want the token cookie not forwarded", resp.StatusCode, b)
+	}
+}
+
+func TestProxyPort(t *testing.T) {
+	mappings := []string{"4000:53/udp", "4001:80", "4002:1337/tcp"}
+	for containerPort, want := range map[string]int{"": 4001, "80": 4001, "1337": 4002, "53": 0} {
+		if got, ok := proxyPort(mappings, containerPort); got != want || ok != (want != 0) {
+			t.Errorf("proxyPort(%q) = %d, %v, want %d", containerPort, got, ok, want)
+		}
+	}
+}
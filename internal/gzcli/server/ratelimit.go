@@ -39,7 +39,7 @@ func (rl *RateLimiter) AllowAction(ip, actionType string) (bool, time.Duration)
 
 	// Define rate limits per action type
 	switch actionType {
-	case "start", "stop", "restart":
+	case "start", "stop", "restart", "extend":
 		maxTokens = 5
 		refillRate = time.Minute / 5 // 5 actions per minute
 	case "vote":
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+// Port check states reported to the challenge page
+const (
+	// PortChecking indicates the self-test for the port has not finished yet
+	PortChecking = "checking"
+	// PortReachable indicates the port accepted a connection (TCP) or replied (UDP)
+	PortReachable = "reachable"
+	// PortUnreachable indicates the port refused or dropped connections
+	PortUnreachable = "unreachable"
+	// PortNoReply indicates a UDP port neither replied nor was rejected, which
+	// is normal for services that only answer valid requests
+	PortNoReply = "no_reply"
+)
+
+const (
+	defaultProbeHost    = "127.0.0.1"
+	probeAttemptTimeout = 2 * time.Second
+	// probeStartupGrace is how long TCP ports are retried after start, since
+	// services inside the container may still be booting
+	probeStartupGrace = 20 * time.Second
+)
+
+var (
+	probeHost   = defaultProbeHost
+	probeHostMu sync.RWMutex
+)
+
+// SetProbeHost sets the address used to self-test allocated ports after a
+// challenge starts. The default probes from the launcher host itself; set it
+// to the public address players use to also catch firewall and NAT issues.
+func SetProbeHost(host string) {
+	probeHostMu.Lock()
+	defer probeHostMu.Unlock()
+	if host == "" {
+		host = defaultProbeHost
+	}
+	probeHost = host
+}
+
+func getProbeHost() string {
+	probeHostMu.RLock()
+	defer probeHostMu.RUnlock()
+	return probeHost
+}
+
+// PortCheck is the result of the connectivity self-test for one allocated port
+type PortCheck = protocol.PortCheck
+
+// ParsePortMapping splits an allocated "host:container[/protocol]" mapping
+// into the host port, the container port and the protocol (default tcp)
+func ParsePortMapping(mapping string) (int, string, string, error) {
+	protocol := "tcp"
+	spec := mapping
+	if i := strings.LastIndexByte(spec, '/'); i >= 0 {
+		protocol = strings.ToLower(spec[i+1:])
+		spec = spec[:i]
+	}
+	if protocol != "tcp" && protocol != "udp" {
+		return 0, "", "", fmt.Errorf("unsupported protocol %q in port mapping %q", protocol, mapping)
+	}
+
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return 0, "", "", fmt.Errorf("port mapping %q has no host port", mapping)
+	}
+	hostPort, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil || hostPort <= 0 || hostPort > 65535 {
+		return 0, "", "", fmt.Errorf("invalid host port in port mapping %q", mapping)
+	}
+	return hostPort, parts[len(parts)-1], protocol, nil
+}
+
+// PortProber self-tests that allocated ports accept connections
+type PortProber struct {
+	Host           string
+	AttemptTimeout time.Duration
+	StartupGrace   time.Duration
+}
+
+// NewPortProber creates a prober for the configured probe host
+func NewPortProber() *PortProber {
+	return &PortProber{
+		Host:           getProbeHost(),
+		AttemptTimeout: probeAttemptTimeout,
+		StartupGrace:   probeStartupGrace,
+	}
+}
+
+// PendingChecks returns a checking-state entry for every mapping, shown while
+// the self-test runs
+func PendingChecks(mappings []string) []PortCheck {
+	checks := make([]PortCheck, 0, len(mappings))
+	for _, mapping := range mappings {
+		check := PortCheck{Mapping: mapping, State: PortChecking}
+		if port, _, protocol, err := ParsePortMapping(mapping); err == nil {
+			check.Port = port
+			check.Protocol = protocol
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// Check probes every mapping concurrently and returns the results in order
+func (p *PortProber) Check(ctx context.Context, mappings []string) []PortCheck {
+	checks := PendingChecks(mappings)
+
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(check *PortCheck) {
+			defer wg.Done()
+			p.checkOne(ctx, check)
+		}(&checks[i])
+	}
+	wg.Wait()
+	return checks
+}
+
+func (p *PortProber) checkOne(ctx context.Context, check *PortCheck) {
+	defer func() { check.CheckedAt = time.Now() }()
+
+	if check.Port == 0 {
+		_, _, _, err := ParsePortMapping(check.Mapping)
+		check.State = PortUnreachable
+		check.Error = err.Error()
+		return
+	}
+
+	addr := net.JoinHostPort(p.Host, strconv.Itoa(check.Port))
+	if check.Protocol == "udp" {
+		check.State, check.Error = p.probeUDP(addr)
+		return
+	}
+
+	deadline := time.Now().Add(p.StartupGrace)
+	for {
+		err := p.probeTCP(ctx, addr)
+		if err == nil {
+			check.State, check.Error = PortReachable, ""
+			return
+		}
+		check.State, check.Error = PortUnreachable, err.Error()
+
+		if time.Now().After(deadline) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// probeTCP connects to addr. A connection closed before any data and before
+// the read timeout counts as a failure: Docker's userland proxy accepts on
+// the host port even when nothing listens inside the container.
+func (p *PortProber) probeTCP(ctx context.Context, addr string) error {
+	dialer := net.Dialer{Timeout: p.AttemptTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetReadDeadline(time.Now().Add(p.AttemptTimeout / 4))
+	buf := make([]byte, 1)
+	if _, err := conn.Read(buf); errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) {
+		return fmt.Errorf("connection to %s closed immediately (service not listening in the container?)", addr)
+	}
+	return nil
+}
+
+// probeUDP sends an empty datagram and waits for a reply. A rejection (ICMP
+// port unreachable) marks the port unreachable; silence is reported as
+// no_reply since many UDP services only answer valid requests.
+func (p *PortProber) probeUDP(addr string) (string, string) {
+	conn, err := net.DialTimeout("udp", addr, p.AttemptTimeout)
+	if err != nil {
+		return PortUnreachable, err.Error()
+	}
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetDeadline(time.Now().Add(p.AttemptTimeout))
+	if _, err := conn.Write([]byte("\n")); err != nil {
+		return PortUnreachable, err.Error()
+	}
+
+	buf := make([]byte, 512)
+	_, err = conn.Read(buf)
+	switch {
+	case err == nil:
+		return PortReachable, ""
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return PortUnreachable, fmt.Sprintf("%s rejected the probe (port unreachable)", addr)
+	default:
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return PortNoReply, ""
+		}
+		return PortUnreachable, err.Error()
+	}
+}
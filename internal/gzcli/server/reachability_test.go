@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		mapping   string
+		wantPort  int
+		wantInner string
+		wantProto string
+		wantErr   bool
+	}{
+		{mapping: "31337:1337", wantPort: 31337, wantInner: "1337", wantProto: "tcp"},
+		{mapping: "31337:1337/udp", wantPort: 31337, wantInner: "1337", wantProto: "udp"},
+		{mapping: "0.0.0.0:31337:1337/TCP", wantPort: 31337, wantInner: "1337", wantProto: "tcp"},
+		{mapping: "1337", wantErr: true},
+		{mapping: "abc:1337", wantErr: true},
+		{mapping: "31337:1337/sctp", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mapping, func(t *testing.T) {
+			port, inner, proto, err := ParsePortMapping(tt.mapping)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if port != tt.wantPort || inner != tt.wantInner || proto != tt.wantProto {
+				t.Errorf("ParsePortMapping() = %d, %s, %s; want %d, %s, %s", port, inner, proto, tt.wantPort, tt.wantInner, tt.wantProto)
+			}
+		})
+	}
+}
+
+func testProber() *PortProber {
+	return &PortProber{Host: "127.0.0.1", AttemptTimeout: 400 * time.Millisecond, StartupGrace: 0}
+}
+
+// freePort returns a local port with nothing listening on it
+func freePort(t *testing.T, network string) int {
+	t.Helper()
+	if network == "udp" {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		port := conn.LocalAddr().(*net.UDPAddr).Port
+		_ = conn.Close()
+		return port
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+	return port
+}
+
+func TestPortProber_TCP(t *testing.T) {
+	// Listener that keeps connections open, like a real service
+	open, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = open.Close() }()
+	go func() {
+		for {
+			conn, err := open.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				time.Sleep(time.Second)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	// Listener that closes immediately, like a proxy with no backend
+	closing, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = closing.Close() }()
+	go func() {
+		for {
+			conn, err := closing.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	mappings := []string{
+		fmt.Sprintf("%d:80", open.Addr().(*net.TCPAddr).Port),
+		fmt.Sprintf("%d:80", closing.Addr().(*net.TCPAddr).Port),
+		fmt.Sprintf("%d:80/tcp", freePort(t, "tcp")),
+	}
+	checks := testProber().Check(context.Background(), mappings)
+
+	want := []string{PortReachable, PortUnreachable, PortUnreachable}
+	for i, check := range checks {
+		if check.Mapping != mappings[i] || check.Protocol != "tcp" {
+			t.Errorf("checks[%d] = %+v, want mapping %s over tcp", i, check, mappings[i])
+		}
+		if check.State != want[i] {
+			t.Errorf("checks[%d].State = %s, want %s (error: %s)", i, check.State, want[i], check.Error)
+		}
+		if check.CheckedAt.IsZero() {
+			t.Errorf("checks[%d].CheckedAt not set", i)
+		}
+	}
+}
+
+func TestPortProber_UDP(t *testing.T) {
+	echo, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = echo.Close() }()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := echo.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = echo.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = silent.Close() }()
+
+	echoPort := echo.LocalAddr().(*net.UDPAddr).Port
+	silentPort := silent.LocalAddr().(*net.UDPAddr).Port
+	checks := testProber().Check(context.Background(), []string{
+		strconv.Itoa(echoPort) + ":53/udp",
+		strconv.Itoa(silentPort) + ":53/udp",
+	})
+
+	if checks[0].State != PortReachable {
+		t.Errorf("echo port state = %s, want %s (error: %s)", checks[0].State, PortReachable, checks[0].Error)
+	}
+	if checks[1].State != PortNoReply {
+		t.Errorf("silent port state = %s, want %s (error: %s)", checks[1].State, PortNoReply, checks[1].Error)
+	}
+}
+
+func TestPendingChecks(t *testing.T) {
+	checks := PendingChecks([]string{"31337:1337/udp", "bogus"})
+	if len(checks) != 2 {
+		t.Fatalf("len(checks) = %d, want 2", len(checks))
+	}
+	if checks[0].State != PortChecking || checks[0].Port != 31337 || checks[0].Protocol != "udp" {
+		t.Errorf("checks[0] = %+v", checks[0])
+	}
+
+	// Invalid mappings resolve to unreachable instead of hanging in checking
+	result := testProber().Check(context.Background(), []string{"bogus"})
+	if result[0].State != PortUnreachable || result[0].Error == "" {
+		t.Errorf("bogus mapping check = %+v", result[0])
+	}
+}
+
+func TestChallengeInfo_SetAllocatedPortsClearsChecks(t *testing.T) {
+	c := &ChallengeInfo{}
+	c.SetAllocatedPorts([]string{"31337:1337"})
+	c.SetPortChecks(PendingChecks(c.GetAllocatedPorts()))
+	if len(c.GetPortChecks()) != 1 {
+		t.Fatal("expected port checks to be stored")
+	}
+
+	c.SetAllocatedPorts(nil)
+	if c.GetPortChecks() != nil {
+		t.Error("expected port checks to be cleared with the allocation")
+	}
+}
@@ -12,11 +12,22 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
-// RunServer starts the HTTP server with all components
-func RunServer(host string, port int) error {
+// RunServer starts the HTTP server with all components and runs until interrupted.
+// socketPath is the Unix socket used to receive watcher notifications; empty disables it.
+func RunServer(host string, port int, socketPath string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return RunServerContext(ctx, host, port, socketPath)
+}
+
+// RunServerContext starts the launcher server and shuts it down, stopping all
+// running challenges unless they are kept for the next start, when ctx is
+// cancelled
+func RunServerContext(ctx context.Context, host string, port int, socketPath string) error {
 	// Initialize components
 	log.Info("Initializing server components...")
 
@@ -38,10 +49,55 @@ func RunServer(host string, port int) error {
 	// Create WebSocket manager
 	wsManager := NewWSManager(challengeManager, executor, voting, rateLimiter)
 
+	// Pre-start the idle instances of challenges with a warm pool
+	warmPool := NewWarmPool(executor, challengeManager.ListChallenges())
+	wsManager.warmPool = warmPool
+
+	// Take over the instances left running by a previous run
+	path, keep := getStateConfig()
+	if path != "" {
+		store, err := OpenStateStore(path)
+		if err != nil {
+			log.Error("Instance state will not persist: %v", err)
+		} else {
+			defer func() {
+				setInstanceStore(nil)
+				_ = store.Close()
+			}()
+			restored := RestoreInstances(store, challengeManager, executor)
+			projects := make([]string, 0, len(restored))
+			for _, instance := range restored {
+				projects = append(projects, instance.projectName())
+				go wsManager.scheduleAutoStop(instance.InstanceKey())
+			}
+			warmPool.Reserve(projects)
+			setInstanceStore(store)
+		}
+	}
+	warmPool.Start()
+
 	// Create health monitor
 	healthMonitor := NewHealthMonitor(challengeManager, executor, wsManager)
 	healthMonitor.Start()
 
+	// Sample the CPU and memory usage of running instances
+	statsMonitor := NewStatsMonitor(challengeManager, wsManager)
+	statsMonitor.Start()
+
+	// Stop instances at the end of their TTL
+	ttlMonitor := NewTTLMonitor(challengeManager, wsManager)
+	ttlMonitor.Start()
+
+	// Listen for watcher notifications (e.g. removed challenges)
+	notifyCtx, cancelNotify := context.WithCancel(ctx)
+	defer cancelNotify()
+	notifyServer := socket.NewServer(socketPath, socketPath != "", &notifyHandler{wsManager: wsManager})
+	if err := notifyServer.Init(); err != nil {
+		log.Error("Failed to start launcher notification socket: %v", err)
+	} else {
+		go notifyServer.Run(notifyCtx)
+	}
+
 	// Create HTTP server
 	httpServer := NewServer(challengeManager, wsManager)
 	if err := httpServer.LoadTemplates(); err != nil {
@@ -72,6 +128,9 @@ func RunServer(host string, port int) error {
 	for _, challenge := range challengeManager.ListChallenges() {
 		log.Info("  • %s", challenge.Name)
 		log.Info("    URL: http://%s:%d/%s", host, port, challenge.Slug)
+		if path := proxyPath(challenge.Slug); path != "" {
+			log.Info("    Proxy: http://%s:%d%s/", host, port, path)
+		}
 	}
 	log.Info("")
 	log.Info("Press Ctrl+C to stop the server")
@@ -86,16 +145,12 @@ func RunServer(host string, port int) error {
 		}
 	}()
 
-	// Channel to listen for interrupt signals.
-	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
-
 	// Blocking main and waiting for shutdown.
 	select {
 	case err := <-serverErrors:
 		return fmt.Errorf("server error: %w", err)
-	case sig := <-shutdown:
-		log.Info("Start shutdown... signal: %v", sig)
+	case <-ctx.Done():
+		log.Info("Start shutdown...")
 
 		// Give outstanding requests a deadline for completion.
 		if err := GracefulShutdown(srv, 5*time.Second); err != nil {
@@ -108,11 +163,21 @@ func RunServer(host string, port int) error {
 
 	// Cleanup on shutdown
 	healthMonitor.Stop()
+	statsMonitor.Stop()
+	ttlMonitor.Stop()
+	warmPool.Stop()
+	cancelNotify()
+	_ = notifyServer.Close()
+
+	if keep && getInstanceStore() != nil {
+		log.Info("Leaving running challenges up for the next start")
+		return nil
+	}
 
 	// Stop all running challenges
 	log.Info("Stopping all running challenges...")
 	var wg sync.WaitGroup
-	for _, challenge := range challengeManager.ListChallenges() {
+	for _, challenge := range challengeManager.ListInstances() {
 		if challenge.GetStatus() != StatusStopped {
 			wg.Add(1)
 			go func(c *ChallengeInfo) {
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// DefaultStatePath is the default file persisting instance state across
+// launcher restarts
+const DefaultStatePath = ".gzcli/launcher/state.db"
+
+var (
+	statePath     = DefaultStatePath
+	keepInstances bool
+	stateMu       sync.RWMutex
+
+	// instanceStore records instance state changes while the server runs
+	instanceStore *StateStore
+)
+
+// SetStatePath sets the file persisting instance state; empty disables
+// persistence
+func SetStatePath(path string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	statePath = path
+}
+
+// SetKeepInstances makes the server leave running instances up when it shuts
+// down, to take them over again on the next start
+func SetKeepInstances(keep bool) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	keepInstances = keep
+}
+
+func getStateConfig() (string, bool) {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+	return statePath, keepInstances
+}
+
+func setInstanceStore(store *StateStore) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	instanceStore = store
+}
+
+func getInstanceStore() *StateStore {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+	return instanceStore
+}
+
+// InstanceState is the runtime state of an instance kept across restarts
+type InstanceState struct {
+	Key            string
+	Slug           string
+	Team           string
+	Project        string // Compose project or container the instance runs under
+	Status         ChallengeStatus
+	AllocatedPorts []string
+	LastRestart    time.Time
+	ConnectedUsers int
+}
+
+// StateStore persists instance state in a SQLite database
+type StateStore struct {
+	db *sql.DB
+	mu sync.Mutex
+}
+
+// OpenStateStore opens or creates the state database at path
+func OpenStateStore(path string) (*StateStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	const schema = `
+		CREATE TABLE IF NOT EXISTS instances (
+			key TEXT PRIMARY KEY,
+			slug TEXT NOT NULL,
+			team TEXT NOT NULL,
+			project TEXT NOT NULL,
+			status TEXT NOT NULL,
+			allocated_ports TEXT NOT NULL,
+			last_restart INTEGER NOT NULL,
+			connected_users INTEGER NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create state table: %w", err)
+	}
+	if _, err := db.Exec(usageSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create usage tables: %w", err)
+	}
+	return &StateStore{db: db}, nil
+}
+
+// Close closes the state database
+func (s *StateStore) Close() error {
+	return s.db.Close()
+}
+
+// Save records the current state of an instance
+func (s *StateStore) Save(c *ChallengeInfo) {
+	c.mu.RLock()
+	state := InstanceState{
+		Key:            instanceKey(c.Slug, c.Team),
+		Slug:           c.Slug,
+		Team:           c.Team,
+		Project:        c.project,
+		Status:         c.Status,
+		AllocatedPorts: c.AllocatedPorts,
+		LastRestart:    c.LastRestart,
+		ConnectedUsers: len(c.ConnectedIPs),
+	}
+	c.mu.RUnlock()
+
+	ports, _ := json.Marshal(state.AllocatedPorts)
+	var lastRestart int64
+	if !state.LastRestart.IsZero() {
+		lastRestart = state.LastRestart.UnixMilli()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO instances (key, slug, team, project, status, allocated_ports, last_restart, connected_users, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		state.Key, state.Slug, state.Team, state.Project, string(state.Status), string(ports), lastRestart, state.ConnectedUsers)
+	if err != nil {
+		log.Error("Failed to save state of %s: %v", state.Key, err)
+	}
+}
+
+// Delete drops the recorded state of an instance
+func (s *StateStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.db.Exec(`DELETE FROM instances WHERE key = ?`, key); err != nil {
+		log.Error("Failed to delete state of %s: %v", key, err)
+	}
+}
+
+// Load returns the recorded state of every instance
+func (s *StateStore) Load() ([]InstanceState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.db.Query(`
+		SELECT key, slug, team, project, status, allocated_ports, last_restart, connected_users
+		FROM instances ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instance state: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var states []InstanceState
+	for rows.Next() {
+		var state InstanceState
+		var status, ports string
+		var lastRestart int64
+		if err := rows.Scan(&state.Key, &state.Slug, &state.Team, &state.Project, &status, &ports, &lastRestart, &state.ConnectedUsers); err != nil {
+			return nil, fmt.Errorf("failed to scan instance state: %w", err)
+		}
+		state.Status = ChallengeStatus(status)
+		_ = json.Unmarshal([]byte(ports), &state.AllocatedPorts)
+		if lastRestart > 0 {
+			state.LastRestart = time.UnixMilli(lastRestart)
+		}
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
+// persist records the instance's state if persistence is enabled. Idle warm
+// pool instances are not recorded: the pool stops them on shutdown.
+func (c *ChallengeInfo) persist() {
+	if isWarmTeam(c.Team) {
+		return
+	}
+	if store := getInstanceStore(); store != nil {
+		store.Save(c)
+	}
+}
+
+// healthChecker reports whether a launcher instance is running
+type healthChecker interface {
+	CheckHealth(challenge *ChallengeInfo) (bool, error)
+}
+
+// RestoreInstances reconciles the recorded instance state with the running
+// containers. Instances still running are taken over with their project and
+// ports, and returned; the others are marked stopped. Restart cooldowns are
+// restored either way.
+func RestoreInstances(store *StateStore, challenges *ChallengeManager, checker healthChecker) []*ChallengeInfo {
+	states, err := store.Load()
+	if err != nil {
+		log.Error("Failed to restore instance state: %v", err)
+		return nil
+	}
+
+	var restored []*ChallengeInfo
+	for _, state := range states {
+		base, ok := challenges.GetChallenge(state.Slug)
+		if !ok || base.Team != "" {
+			log.InfoH3("Dropping state of %s: challenge no longer exists", state.Key)
+			store.Delete(state.Key)
+			store.StopSession(state.Key, time.Now())
+			continue
+		}
+
+		if isWarmTeam(state.Team) {
+			store.Delete(state.Key)
+			continue
+		}
+
+		instance, _ := challenges.GetInstance(state.Slug, state.Team, true)
+		instance.mu.Lock()
+		instance.LastRestart = state.LastRestart
+		instance.project = state.Project
+		instance.mu.Unlock()
+
+		wasUp := state.Status != StatusStopped
+		running := false
+		if wasUp {
+			running, _ = checker.CheckHealth(instance)
+		}
+		if running {
+			instance.mu.Lock()
+			instance.Status = StatusRunning
+			instance.AllocatedPorts = state.AllocatedPorts
+			instance.mu.Unlock()
+			log.Info("Restored running instance %s (%d user(s) were connected)", state.Key, state.ConnectedUsers)
+			store.StartSession(instance, time.Now())
+			restored = append(restored, instance)
+		} else {
+			instance.mu.Lock()
+			instance.Status = StatusStopped
+			instance.project = ""
+			instance.mu.Unlock()
+			if wasUp {
+				log.InfoH3("Instance %s is no longer running", state.Key)
+			}
+			// Its session ended while the launcher was down, at a time
+			// unknown; it is closed now
+			store.StopSession(state.Key, time.Now())
+		}
+		store.Save(instance)
+	}
+	return restored
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeHealth reports the projects in running as up
+type fakeHealth struct {
+	running map[string]bool
+}
+
+func (f *fakeHealth) CheckHealth(c *ChallengeInfo) (bool, error) {
+	return f.running[c.projectName()], nil
+}
+
+func newStateChallenge(slug string) *ChallengeInfo {
+	return &ChallengeInfo{
+		Slug:         slug,
+		Name:         slug,
+		Dashboard:    &Dashboard{Type: string(LauncherTypeCompose), Config: "docker-compose.yml"},
+		Status:       StatusStopped,
+		ConnectedIPs: make(map[string]bool),
+	}
+}
+
+func openTestStore(t *testing.T) *StateStore {
+	t.Helper()
+	store, err := OpenStateStore(filepath.Join(t.TempDir(), "launcher", "state.db"))
+	if err != nil {
+		t.Fatalf("OpenStateStore: %v", err)
+	}
+	t.Cleanup(func() {
+		setInstanceStore(nil)
+		_ = store.Close()
+	})
+	return store
+}
+
+func TestStateStore_Persist(t *testing.T) {
+	store := openTestStore(t)
+	setInstanceStore(store)
+
+	restart := time.UnixMilli(1700000000123)
+	c := newStateChallenge("ctf-web-a")
+	c.SetStatus(StatusRunning)
+	c.SetAllocatedPorts([]string{"31337:80"})
+	c.SetLastRestart(restart)
+	c.AddConnectedIP("10.0.0.1")
+
+	warm := c.newTeamInstance(warmTeamPrefix + "1")
+	warm.SetStatus(StatusRunning)
+
+	states, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []InstanceState{{
+		Key:            "ctf-web-a",
+		Slug:           "ctf-web-a",
+		Status:         StatusRunning,
+		AllocatedPorts: []string{"31337:80"},
+		LastRestart:    restart,
+		ConnectedUsers: 1,
+	}}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("Load() = %+v, want %+v (warm instances are not recorded)", states, want)
+	}
+}
+
+func TestRestoreInstances(t *testing.T) {
+	store := openTestStore(t)
+	cm := NewChallengeManager()
+	cm.challenges["ctf-web-a"] = newStateChallenge("ctf-web-a")
+	cm.challenges["ctf-web-b"] = newStateChallenge("ctf-web-b")
+
+	restart := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	up := newStateChallenge("ctf-web-a").newTeamInstance("red")
+	up.Status = StatusRunning
+	up.AllocatedPorts = []string{"31337:80"}
+	up.project = "ctf-web-a--warm--3"
+	store.Save(up)
+
+	gone := newStateChallenge("ctf-web-b")
+	gone.Status = StatusRunning
+	gone.LastRestart = restart
+	store.Save(gone)
+
+	store.Save(newStateChallenge("ctf-removed"))
+	store.Save(newStateChallenge("ctf-web-a").newTeamInstance(warmTeamPrefix + "1"))
+
+	restored := RestoreInstances(store, cm, &fakeHealth{running: map[string]bool{"ctf-web-a--warm--3": true}})
+
+	if len(restored) != 1 || restored[0].InstanceKey() != "ctf-web-a--red" {
+		t.Fatalf("restored = %v, want only ctf-web-a--red", restored)
+	}
+	red, ok := cm.GetInstance("ctf-web-a", "red", false)
+	if !ok || red != restored[0] {
+		t.Fatal("restored instance is not registered")
+	}
+	if red.GetStatus() != StatusRunning || red.projectName() != "ctf-web-a--warm--3" ||
+		!reflect.DeepEqual(red.GetAllocatedPorts(), []string{"31337:80"}) {
+		t.Errorf("restored instance = %s %s %v, want running ctf-web-a--warm--3 [31337:80]",
+			red.GetStatus(), red.projectName(), red.GetAllocatedPorts())
+	}
+
+	b, _ := cm.GetChallenge("ctf-web-b")
+	if b.GetStatus() != StatusStopped {
+		t.Errorf("instance no longer running has status %s, want stopped", b.GetStatus())
+	}
+	if inCooldown, _ := b.IsInCooldown(); !inCooldown {
+		t.Error("restart cooldown was not restored")
+	}
+
+	states, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var keys []string
+	for _, s := range states {
+		keys = append(keys, s.Key)
+	}
+	if want := []string{"ctf-web-a--red", "ctf-web-b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("recorded instances = %v, want %v", keys, want)
+	}
+}
+
+func TestWarmPool_Reserve(t *testing.T) {
+	wp := NewWarmPool(&fakeStarter{}, nil)
+	wp.Reserve([]string{"ctf-web-a", "ctf-web-a--warm--3", "ctf-web-b--warm--2"})
+	if wp.seq != 3 {
+		t.Errorf("seq = %d, want 3", wp.seq)
+	}
+}
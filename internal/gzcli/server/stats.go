@@ -0,0 +1,254 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+const (
+	statsInterval = 10 * time.Second
+	statsTimeout  = 8 * time.Second
+)
+
+// ResourceUsage is the CPU and memory usage of the containers of an instance
+type ResourceUsage = protocol.Usage
+
+// containerStats is a line of "docker stats --format '{{json .}}'"
+type containerStats struct {
+	Name     string `json:"Name"`
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+	NetIO    string `json:"NetIO"`
+}
+
+// StatsMonitor polls the resource usage of running instances and broadcasts
+// it to their challenge pages
+type StatsMonitor struct {
+	challenges *ChallengeManager
+	wsManager  *WSManager
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewStatsMonitor creates a new resource usage monitor
+func NewStatsMonitor(challenges *ChallengeManager, wsManager *WSManager) *StatsMonitor {
+	return &StatsMonitor{
+		challenges: challenges,
+		wsManager:  wsManager,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start starts the polling loop
+func (sm *StatsMonitor) Start() {
+	sm.wg.Add(1)
+	go sm.monitorLoop()
+	log.Info("Resource usage monitor started")
+}
+
+// Stop stops the polling loop
+func (sm *StatsMonitor) Stop() {
+	close(sm.stopChan)
+	sm.wg.Wait()
+	log.Info("Resource usage monitor stopped")
+}
+
+// monitorLoop is the main polling loop
+func (sm *StatsMonitor) monitorLoop() {
+	defer sm.wg.Done()
+
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sm.stopChan:
+			return
+		case <-ticker.C:
+			sm.collect()
+		}
+	}
+}
+
+// collect samples the usage of every running Docker instance with a single
+// "docker stats" call and broadcasts it. Usage of instances that are not
+// running is cleared.
+func (sm *StatsMonitor) collect() {
+	var running []*ChallengeInfo
+	for _, challenge := range sm.challenges.ListInstances() {
+		if challenge.GetStatus() != StatusRunning || challenge.Dashboard == nil ||
+			LauncherType(challenge.Dashboard.Type) == LauncherTypeKubernetes {
+			challenge.SetResourceUsage(nil)
+			continue
+		}
+		running = append(running, challenge)
+	}
+	if len(running) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+
+	stats, err := dockerStats(ctx)
+	if err != nil {
+		log.Debug("Failed to collect resource usage: %v", err)
+		return
+	}
+	projects, err := dockerComposeProjects(ctx)
+	if err != nil {
+		log.Debug("Failed to list compose projects: %v", err)
+		return
+	}
+
+	byProject := make(map[string][]containerStats)
+	for _, s := range stats {
+		project := projects[s.Name]
+		if project == "" {
+			project = s.Name // Dockerfile instances run as a container named after the project
+		}
+		byProject[project] = append(byProject[project], s)
+	}
+
+	now := time.Now()
+	for _, challenge := range running {
+		usage := aggregateUsage(byProject[challenge.projectName()])
+		if usage == nil {
+			challenge.SetResourceUsage(nil)
+			continue
+		}
+		usage.UpdatedAt = now
+		challenge.SetResourceUsage(usage)
+		challenge.recordUsage(func(store *StateStore, key string) { store.RecordResourceUsage(key, usage) })
+		if sm.wsManager != nil {
+			sm.wsManager.broadcastStats(challenge.InstanceKey(), usage)
+		}
+	}
+}
+
+// dockerStats samples the usage of all running containers
+func dockerStats(ctx context.Context) ([]containerStats, error) {
+	cmd := exec.CommandContext(ctx, "docker", "stats", "--no-stream", "--format", "{{json .}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker stats failed: %w", err)
+	}
+	return parseDockerStats(output), nil
+}
+
+// dockerComposeProjects maps the names of running containers started by
+// Docker Compose to their project
+func dockerComposeProjects(ctx context.Context) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "docker", "ps",
+		"--filter", "label=com.docker.compose.project",
+		"--format", `{{.Names}}	{{.Label "com.docker.compose.project"}}`)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker ps failed: %w", err)
+	}
+
+	projects := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		name, project, ok := strings.Cut(scanner.Text(), "\t")
+		if ok && project != "" {
+			projects[name] = project
+		}
+	}
+	return projects, nil
+}
+
+// parseDockerStats parses the JSON lines printed by docker stats, skipping
+// malformed ones
+func parseDockerStats(output []byte) []containerStats {
+	var stats []containerStats
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var s containerStats
+		if err := json.Unmarshal(line, &s); err != nil || s.Name == "" {
+			continue
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// aggregateUsage totals the usage of the containers of one instance; nil
+// when there are none
+func aggregateUsage(stats []containerStats) *ResourceUsage {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	usage := &ResourceUsage{Containers: len(stats)}
+	for _, s := range stats {
+		if cpu, err := parsePercent(s.CPUPerc); err == nil {
+			usage.CPUPercent += cpu
+		}
+		used, limit, ok := strings.Cut(s.MemUsage, "/")
+		if !ok {
+			continue
+		}
+		if n, err := parseByteSize(used); err == nil {
+			usage.MemoryUsage += n
+		}
+		if n, err := parseByteSize(limit); err == nil && n > usage.MemoryLimit {
+			usage.MemoryLimit = n
+		}
+	}
+	if usage.MemoryLimit > 0 {
+		usage.MemoryPercent = float64(usage.MemoryUsage) / float64(usage.MemoryLimit) * 100
+	}
+	return usage
+}
+
+// parsePercent parses a docker stats percentage such as "12.34%"
+func parsePercent(s string) (float64, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+	if s == "" || s == "--" {
+		return 0, fmt.Errorf("no value")
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// byteUnits are the size suffixes printed by docker stats
+var byteUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// parseByteSize parses a docker stats size such as "12.5MiB" or "1.9GB"
+func parseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	for _, unit := range byteUnits {
+		value, ok := strings.CutSuffix(s, unit.suffix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid size %q", s)
+		}
+		return uint64(n * unit.multiplier), nil
+	}
+	return 0, fmt.Errorf("invalid size %q", s)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    uint64
+		wantErr bool
+	}{
+		{size: "512B", want: 512},
+		{size: "1.5KiB", want: 1536},
+		{size: " 12MiB ", want: 12 << 20},
+		{size: "1.5GiB", want: 3 << 29},
+		{size: "3kB", want: 3000},
+		{size: "2MB", want: 2e6},
+		{size: "1GB", want: 1e9},
+		{size: "--", wantErr: true},
+		{size: "12", wantErr: true},
+		{size: "xMiB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseByteSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateUsage(t *testing.T) {
+	output := []byte(`{"BlockIO":"0B / 0B","CPUPerc":"150.25%","Container":"abc","ID":"abc","MemPerc":"1.00%","MemUsage":"256MiB / 1GiB","Name":"web-app-1","NetIO":"1kB / 0B","PIDs":"4"}
+not json
+{"CPUPerc":"0.75%","MemUsage":"256MiB / 512MiB","Name":"web-db-1"}
+{"CPUPerc":"--","MemUsage":"-- / --","Name":"web-init-1"}
+`)
+
+	stats := parseDockerStats(output)
+	if len(stats) != 3 {
+		t.Fatalf("parseDockerStats() returned %d containers, want 3", len(stats))
+	}
+
+	usage := aggregateUsage(stats)
+	if usage.Containers != 3 {
+		t.Errorf("Containers = %d, want 3", usage.Containers)
+	}
+	if math.Abs(usage.CPUPercent-151) > 1e-9 {
+		t.Errorf("CPUPercent = %v, want 151", usage.CPUPercent)
+	}
+	if usage.MemoryUsage != 512<<20 || usage.MemoryLimit != 1<<30 {
+		t.Errorf("memory = %d / %d, want %d / %d", usage.MemoryUsage, usage.MemoryLimit, 512<<20, 1<<30)
+	}
+	if usage.MemoryPercent != 50 {
+		t.Errorf("MemoryPercent = %v, want 50", usage.MemoryPercent)
+	}
+
+	if aggregateUsage(nil) != nil {
+		t.Error("aggregateUsage(nil) should be nil")
+	}
+}
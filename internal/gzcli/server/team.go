@@ -0,0 +1,180 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+)
+
+// TeamTokenParam is the query parameter carrying a team token on challenge
+// pages and their WebSocket in team isolation mode
+const TeamTokenParam = "token"
+
+// TeamIsolation gives each team its own instance of every challenge. Teams
+// authenticate with one of the listed tokens and are named after their team.
+type TeamIsolation struct {
+	teams map[string]string // team key -> token
+}
+
+// NewTeamIsolation creates a team isolation mode. teams maps team names to
+// their tokens. Names whose team keys collide are rejected, since their teams
+// would share instances.
+func NewTeamIsolation(teams map[string]string) (*TeamIsolation, error) {
+	if len(teams) == 0 {
+		return nil, fmt.Errorf("team isolation requires a list of team tokens")
+	}
+	seen := make(map[string]string, len(teams))
+	keys := make(map[string]string, len(teams))
+	names := make(map[string]string, len(teams))
+	for name, token := range teams {
+		if token == "" {
+			return nil, fmt.Errorf("team %q has an empty token", name)
+		}
+		if other, dup := seen[token]; dup {
+			return nil, fmt.Errorf("teams %q and %q share a token", other, name)
+		}
+		seen[token] = name
+		key := teamKey(name)
+		if other, dup := names[key]; dup {
+			return nil, fmt.Errorf("teams %q and %q both have the team key %q, rename one of them", other, name, key)
+		}
+		names[key] = name
+		keys[key] = token
+	}
+	return &TeamIsolation{teams: keys}, nil
+}
+
+// LoadTeamTokens reads a YAML map of team names to tokens and creates a team
+// isolation mode accepting only those tokens
+func LoadTeamTokens(path string) (*TeamIsolation, error) {
+	var teams map[string]string
+	if err := fileutil.ParseYamlFromFile(path, &teams); err != nil {
+		return nil, fmt.Errorf("failed to read team tokens %s: %w", path, err)
+	}
+	if len(teams) == 0 {
+		return nil, fmt.Errorf("team tokens file %s lists no teams", path)
+	}
+	return NewTeamIsolation(teams)
+}
+
+// Team returns the team key authenticated by token
+func (ti *TeamIsolation) Team(token string) (string, error) {
+	if token == "" {
+		return "", fmt.Errorf("team token required")
+	}
+	for key, want := range ti.teams {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("invalid team token")
+}
+
+var (
+	teamIsolation   *TeamIsolation
+	teamIsolationMu sync.RWMutex
+)
+
+// SetTeamIsolation enables per-team instances; nil restores a single shared
+// instance per challenge
+func SetTeamIsolation(ti *TeamIsolation) {
+	teamIsolationMu.Lock()
+	defer teamIsolationMu.Unlock()
+	teamIsolation = ti
+}
+
+// ConfigureTeamIsolation enables team isolation with the tokens of a team
+// tokens file, and disables it when none is given. Enabling it without a
+// file is an error: accepting any token would let anyone start unlimited
+// instances by varying it.
+func ConfigureTeamIsolation(enabled bool, tokensFile string) error {
+	switch {
+	case tokensFile != "":
+		ti, err := LoadTeamTokens(tokensFile)
+		if err != nil {
+			return err
+		}
+		SetTeamIsolation(ti)
+	case enabled:
+		return fmt.Errorf("team isolation requires a team tokens file")
+	default:
+		SetTeamIsolation(nil)
+	}
+	return nil
+}
+
+func getTeamIsolation() *TeamIsolation {
+	teamIsolationMu.RLock()
+	defer teamIsolationMu.RUnlock()
+	return teamIsolation
+}
+
+// requestTeam returns the team of a player request, or "" when team
+// isolation is disabled
+func requestTeam(r *http.Request) (string, error) {
+	ti := getTeamIsolation()
+	if ti == nil {
+		return "", nil
+	}
+	return ti.Team(r.URL.Query().Get(TeamTokenParam))
+}
+
+// teamKey turns a team name into the lowercase letters, digits and hyphens
+// allowed in Docker Compose project names
+func teamKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	key := strings.TrimSuffix(b.String(), "-")
+	if len(key) > 32 {
+		key = strings.TrimSuffix(key[:32], "-")
+	}
+	if key == "" {
+		sum := sha256.Sum256([]byte(name))
+		key = "t" + hex.EncodeToString(sum[:6])
+	}
+	return key
+}
+
+// instanceKey identifies the instance of a challenge owned by team. It names
+// the instance's Compose project or container.
+func instanceKey(slug, team string) string {
+	if team == "" {
+		return slug
+	}
+	return slug + "--" + team
+}
+
+// InstanceKey identifies this instance among all launcher instances
+func (c *ChallengeInfo) InstanceKey() string {
+	return instanceKey(c.Slug, c.Team)
+}
+
+// newTeamInstance creates a stopped instance of the challenge owned by team
+func (c *ChallengeInfo) newTeamInstance(team string) *ChallengeInfo {
+	return &ChallengeInfo{
+		Slug:         c.Slug,
+		Team:         team,
+		EventName:    c.EventName,
+		Category:     c.Category,
+		Name:         c.Name,
+		Description:  c.Description,
+		Cwd:          c.Cwd,
+		Dashboard:    c.Dashboard,
+		Scripts:      c.Scripts,
+		Status:       StatusStopped,
+		ConnectedIPs: make(map[string]bool),
+	}
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestChallengeManager_GetInstance(t *testing.T) {
+	_, cm, _ := newTestNotifyHandler(t)
+
+	if _, ok := cm.GetInstance("ctf_web_chall", "red", false); ok {
+		t.Fatal("GetInstance() without create should not create a team instance")
+	}
+
+	red, ok := cm.GetInstance("ctf_web_chall", "red", true)
+	if !ok {
+		t.Fatal("GetInstance() should create a team instance")
+	}
+	if red.InstanceKey() != "ctf_web_chall--red" || red.Team != "red" || red.Name != "Chall" {
+		t.Errorf("team instance = %+v", red)
+	}
+	if again, _ := cm.GetInstance("ctf_web_chall", "red", true); again != red {
+		t.Error("GetInstance() should return the existing team instance")
+	}
+
+	blue, _ := cm.GetInstance("ctf_web_chall", "blue", true)
+	red.SetStatus(StatusRunning)
+	red.AddConnectedIP("10.0.0.1")
+	if blue.GetStatus() != StatusStopped || blue.GetConnectedUsers() != 0 {
+		t.Error("team instances should not share state")
+	}
+	if base, _ := cm.GetChallenge("ctf_web_chall"); base.GetStatus() != StatusStopped {
+		t.Error("team instance should not change the shared instance")
+	}
+	if got, ok := cm.GetChallenge(red.InstanceKey()); !ok || got != red {
+		t.Error("GetChallenge() should resolve instance keys")
+	}
+	if n := len(cm.ListInstances()); n != 3 {
+		t.Errorf("ListInstances() = %d instances, want 3", n)
+	}
+	if n := cm.GetChallengeCount(); n != 1 {
+		t.Errorf("GetChallengeCount() = %d, want 1", n)
+	}
+
+	if _, ok := cm.GetInstance("missing", "red", true); ok {
+		t.Error("GetInstance() should not create instances of unknown challenges")
+	}
+}
+
+func TestNotifyHandler_ChallengeRemovedDropsTeamInstances(t *testing.T) {
+	handler, cm, dir := newTestNotifyHandler(t)
+	cm.GetInstance("ctf_web_chall", "red", true)
+
+	resp := handler.HandleCommand(watchertypes.WatcherCommand{
+		Action: ActionChallengeRemoved,
+		Data:   map[string]interface{}{"event": "ctf", "path": dir},
+	})
+	if !resp.Success {
+		t.Fatalf("HandleCommand() failed: %s", resp.Error)
+	}
+	if n := len(cm.ListInstances()); n != 0 {
+		t.Errorf("ListInstances() = %d instances after removal, want 0", n)
+	}
+}
+
+func TestTeamIsolation_Team(t *testing.T) {
+	if _, err := NewTeamIsolation(nil); err == nil {
+		t.Error("NewTeamIsolation() should require team tokens")
+	}
+
+	listed, err := NewTeamIsolation(map[string]string{"Red Team!": "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team, err := listed.Team("s3cret"); err != nil || team != "red-team" {
+		t.Errorf("Team() = %q, %v; want red-team", team, err)
+	}
+	if _, err := listed.Team("other"); err == nil {
+		t.Error("Team() should reject unlisted tokens")
+	}
+
+	if _, err := listed.Team(""); err == nil {
+		t.Error("Team() should reject an empty token")
+	}
+
+	if _, err := NewTeamIsolation(map[string]string{"a": "x", "b": "x"}); err == nil {
+		t.Error("NewTeamIsolation() should reject shared tokens")
+	}
+	for _, colliding := range [][2]string{
+		{"Team A", "team_a"},
+		{"战队1", "队伍1"},
+		{strings.Repeat("a", 32) + "-red", strings.Repeat("a", 32) + "-blue"},
+	} {
+		if _, err := NewTeamIsolation(map[string]string{colliding[0]: "x", colliding[1]: "y"}); err == nil {
+			t.Errorf("NewTeamIsolation() should reject %q and %q sharing a team key", colliding[0], colliding[1])
+		}
+	}
+}
+
+func TestConfigureTeamIsolation(t *testing.T) {
+	t.Cleanup(func() { SetTeamIsolation(nil) })
+	if err := ConfigureTeamIsolation(true, ""); err == nil {
+		t.Error("ConfigureTeamIsolation() without a tokens file should fail")
+	}
+	if err := ConfigureTeamIsolation(false, ""); err != nil || getTeamIsolation() != nil {
+		t.Errorf("ConfigureTeamIsolation(false) = %v, want isolation disabled", err)
+	}
+}
+
+func TestLoadTeamTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teams.yaml")
+	if err := os.WriteFile(path, []byte("alpha: one\nbeta: two\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ti, err := LoadTeamTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team, _ := ti.Team("two"); team != "beta" {
+		t.Errorf("Team() = %q, want beta", team)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.yaml")
+	if err := os.WriteFile(empty, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTeamTokens(empty); err == nil {
+		t.Error("LoadTeamTokens() should reject a file without teams")
+	}
+}
+
+func TestHandleChallenge_RequiresTeamToken(t *testing.T) {
+	handler, cm, _ := newTestNotifyHandler(t)
+	s := NewServer(cm, handler.wsManager)
+	if err := s.LoadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	ti, _ := NewTeamIsolation(map[string]string{"red": "s3cret"})
+	SetTeamIsolation(ti)
+	t.Cleanup(func() { SetTeamIsolation(nil) })
+
+	for query, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"?token=wrong":  http.StatusUnauthorized,
+		"?token=s3cret": http.StatusOK,
+	} {
+		rec := httptest.NewRecorder()
+		s.HandleChallenge(rec, httptest.NewRequest(http.MethodGet, "/ctf_web_chall"+query, nil))
+		if rec.Code != want {
+			t.Errorf("GET /ctf_web_chall%s = %d, want %d", query, rec.Code, want)
+		}
+	}
+
+	cm.GetInstance("ctf_web_chall", "red", true)
+	rec := httptest.NewRecorder()
+	s.HandleChallenge(rec, httptest.NewRequest(http.MethodGet, "/ctf_web_chall--red?token=s3cret", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("team instance keys should not be served as challenge pages, got %d", rec.Code)
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	handler, cm, _ := newTestNotifyHandler(t)
+	s := NewServer(cm, handler.wsManager)
+	if err := s.LoadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	ti, _ := NewTeamIsolation(map[string]string{"red": "s3cret"})
+	SetTeamIsolation(ti)
+	t.Cleanup(func() { SetTeamIsolation(nil) })
+	routes := s.SetupRoutes()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/events/ctf"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("GET /events/ctf without token = %d, want 401", rec.Code)
+	}
+	if rec := get("/events/missing?token=s3cret"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /events/missing = %d, want 404", rec.Code)
+	}
+
+	rec := get("/events/ctf?token=s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /events/ctf = %d, want 200", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Chall", `href="/ctf_web_chall?token=s3cret"`, `href="/ctf_web_chall?start=1&amp;token=s3cret"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("event page is missing %s:\n%s", want, body)
+		}
+	}
+
+	// Badges show the status of the caller's own instance
+	instance, _ := cm.GetInstance("ctf_web_chall", "red", true)
+	instance.SetStatus(StatusRunning)
+	body = get("/events/ctf?token=s3cret").Body.String()
+	if !strings.Contains(body, `badge running`) || strings.Contains(body, "Quick start") {
+		t.Errorf("running instance should show its badge without quick start:\n%s", body)
+	}
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+const (
+	// ttlInterval is how often instance TTLs are checked and the remaining
+	// time broadcast to challenge pages
+	ttlInterval = 10 * time.Second
+
+	// defaultTTLExtension is the time added by an approved extension vote
+	// when no extension is configured
+	defaultTTLExtension = 30 * time.Minute
+)
+
+// Vote actions
+const (
+	voteActionRestart = protocol.ActionRestart
+	voteActionExtend  = protocol.ActionExtend
+)
+
+// voteKey is the VotingManager key of a vote on an instance, so an extension
+// vote can run next to a restart vote
+func voteKey(key, action string) string {
+	if action == voteActionExtend {
+		return key + "/" + voteActionExtend
+	}
+	return key
+}
+
+// InstanceTTL is the remaining lifetime of a running instance with a TTL
+type InstanceTTL = protocol.TTL
+
+// ttlConfig returns the TTL of the instance, or nil without one
+func (c *ChallengeInfo) ttlConfig() *config.TTL {
+	if c.Dashboard == nil || c.Dashboard.TTL == nil || c.Dashboard.TTL.Duration <= 0 {
+		return nil
+	}
+	return c.Dashboard.TTL
+}
+
+// GetTTL returns the remaining lifetime of the instance, or nil while its
+// TTL has not started
+func (c *ChallengeInfo) GetTTL() *InstanceTTL {
+	ttl := c.ttlConfig()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if ttl == nil || c.ExpiresAt.IsZero() {
+		return nil
+	}
+	remaining := max(time.Until(c.ExpiresAt), 0)
+	return &InstanceTTL{
+		ExpiresAt:        c.ExpiresAt,
+		RemainingSeconds: int(remaining.Seconds()),
+		Extensions:       c.Extensions,
+		MaxExtensions:    ttl.MaxExtensions,
+	}
+}
+
+// startTTL starts the TTL of a running instance at now, unless it already
+// started. Instances taken over from a previous run get a full TTL.
+func (c *ChallengeInfo) startTTL(now time.Time) {
+	ttl := c.ttlConfig()
+	if ttl == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ExpiresAt.IsZero() {
+		c.ExpiresAt = now.Add(ttl.Duration)
+	}
+}
+
+// ttlExpired reports whether the TTL of the instance ended before now
+func (c *ChallengeInfo) ttlExpired(now time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// canExtendTTL returns why the TTL of the instance cannot be extended, or nil
+func (c *ChallengeInfo) canExtendTTL() error {
+	ttl := c.ttlConfig()
+	if ttl == nil || ttl.MaxExtensions <= 0 {
+		return fmt.Errorf("this challenge cannot be extended")
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.ExpiresAt.IsZero() {
+		return fmt.Errorf("challenge is not running")
+	}
+	if c.Extensions >= ttl.MaxExtensions {
+		return fmt.Errorf("maximum of %d extension(s) reached", ttl.MaxExtensions)
+	}
+	return nil
+}
+
+// ExtendTTL adds one extension to the TTL of the instance and returns the
+// time added
+func (c *ChallengeInfo) ExtendTTL() (time.Duration, error) {
+	if err := c.canExtendTTL(); err != nil {
+		return 0, err
+	}
+	extension := c.ttlConfig().Extension
+	if extension <= 0 {
+		extension = defaultTTLExtension
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ExpiresAt = c.ExpiresAt.Add(extension)
+	c.Extensions++
+	return extension, nil
+}
+
+// TTLMonitor stops running instances whose TTL ended and broadcasts the
+// remaining time of the others to their challenge pages
+type TTLMonitor struct {
+	challenges *ChallengeManager
+	wsManager  *WSManager
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewTTLMonitor creates a new TTL monitor
+func NewTTLMonitor(challenges *ChallengeManager, wsManager *WSManager) *TTLMonitor {
+	return &TTLMonitor{
+		challenges: challenges,
+		wsManager:  wsManager,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start starts the monitoring loop
+func (tm *TTLMonitor) Start() {
+	tm.wg.Add(1)
+	go tm.monitorLoop()
+	log.Info("Instance TTL monitor started")
+}
+
+// Stop stops the monitoring loop
+func (tm *TTLMonitor) Stop() {
+	close(tm.stopChan)
+	tm.wg.Wait()
+	log.Info("Instance TTL monitor stopped")
+}
+
+// monitorLoop is the main monitoring loop
+func (tm *TTLMonitor) monitorLoop() {
+	defer tm.wg.Done()
+
+	ticker := time.NewTicker(ttlInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-tm.stopChan:
+			return
+		case <-ticker.C:
+			tm.check(time.Now())
+		}
+	}
+}
+
+// check starts the TTL of newly running instances, stops those whose TTL
+// ended and broadcasts the remaining time of the others
+func (tm *TTLMonitor) check(now time.Time) {
+	for _, challenge := range tm.challenges.ListInstances() {
+		if challenge.GetStatus() != StatusRunning || challenge.ttlConfig() == nil {
+			continue
+		}
+		challenge.startTTL(now)
+		if challenge.ttlExpired(now) {
+			tm.wsManager.expireInstance(challenge)
+			continue
+		}
+		tm.wsManager.broadcastTTL(challenge.InstanceKey(), challenge.GetTTL())
+	}
+}
+
+// expireInstance stops an instance whose TTL ended, with players connected
+// or not
+func (wm *WSManager) expireInstance(challenge *ChallengeInfo) {
+	key := challenge.InstanceKey()
+	log.InfoH2("TTL of %s expired, stopping it", challenge.Name)
+
+	wm.autoStopMu.Lock()
+	if timer, exists := wm.autoStopTimers[key]; exists {
+		timer.Stop()
+		delete(wm.autoStopTimers, key)
+	}
+	wm.autoStopMu.Unlock()
+	wm.voting.EndVote(voteKey(key, voteActionExtend), "expired")
+
+	challenge.SetStatus(StatusStopping)
+	wm.broadcastStatus(key)
+
+	go func() {
+		if err := wm.executor.Stop(challenge); err != nil {
+			log.Error("Failed to stop expired challenge %s: %v", challenge.Name, err)
+			challenge.SetStatus(StatusRunning)
+			wm.broadcastError(key, "Failed to stop challenge after its time limit. Please check server logs.")
+		} else {
+			challenge.SetStatus(StatusStopped)
+			wm.broadcastInfo(key, "Challenge stopped: time limit reached")
+		}
+		wm.broadcastStatus(key)
+	}()
+}
+
+// handleExtendRequest starts a vote to extend the TTL of an instance
+func (wm *WSManager) handleExtendRequest(client *Client) {
+	if allowed, waitTime := wm.rateLimiter.AllowAction(client.IP, "extend"); !allowed {
+		wm.sendError(client, fmt.Sprintf("Rate limit exceeded. Try again in %v", waitTime))
+		return
+	}
+
+	challenge, exists := wm.challenges.GetChallenge(client.Challenge)
+	if !exists {
+		wm.sendError(client, "Challenge not found")
+		return
+	}
+	if err := challenge.canExtendTTL(); err != nil {
+		wm.sendError(client, fmt.Sprintf("Cannot extend: %v", err))
+		return
+	}
+
+	key := voteKey(client.Challenge, voteActionExtend)
+	if wm.voting.HasActiveVote(key) {
+		wm.sendError(client, "Extension vote already in progress")
+		return
+	}
+	if err := wm.voting.StartVote(key, func() {
+		wm.handleExtendVoteTimeout(client.Challenge)
+	}); err != nil {
+		log.Error("Failed to start extension vote for %s: %v", challenge.Name, err)
+		wm.sendError(client, "Failed to start vote")
+		return
+	}
+
+	wm.broadcastVoteStarted(client.Challenge, VoteMessage{
+		InitiatorIP: maskIP(client.IP),
+		Action:      voteActionExtend,
+	})
+
+	// Automatically vote yes for the initiator
+	_ = wm.voting.CastVote(key, client.IP, true)
+	wm.checkAndBroadcastVoteUpdate(client.Challenge, voteActionExtend)
+}
+
+// handleExtendVoteTimeout extends the TTL of an instance if its extension
+// vote was approved
+func (wm *WSManager) handleExtendVoteTimeout(slug string) {
+	challenge, exists := wm.challenges.GetChallenge(slug)
+	if !exists {
+		return
+	}
+	key := voteKey(slug, voteActionExtend)
+	if !wm.voting.HasActiveVote(key) {
+		return // Ended when the instance expired
+	}
+
+	yesPercent, noPercent, _, _ := wm.voting.GetVoteStatus(key, challenge.ConnectedIPs)
+	if yesPercent <= noPercent {
+		wm.voting.EndVote(key, "rejected")
+		wm.broadcastVoteEnded(slug, VoteMessage{Result: "rejected", Action: voteActionExtend})
+		return
+	}
+
+	extension, err := challenge.ExtendTTL()
+	if err != nil {
+		wm.voting.EndVote(key, "rejected")
+		wm.broadcastVoteEnded(slug, VoteMessage{Result: "rejected", Action: voteActionExtend})
+		wm.broadcastError(slug, fmt.Sprintf("Cannot extend: %v", err))
+		return
+	}
+	wm.voting.EndVote(key, "approved")
+	wm.broadcastVoteEnded(slug, VoteMessage{Result: "approved", Action: voteActionExtend})
+	log.InfoH2("Extended TTL of %s by %v", challenge.Name, extension)
+	wm.broadcastInfo(slug, fmt.Sprintf("Challenge time extended by %v", extension))
+	wm.broadcastTTL(slug, challenge.GetTTL())
+}
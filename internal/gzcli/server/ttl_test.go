@@ -0,0 +1,113 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func newTTLChallenge(ttl *config.TTL) *ChallengeInfo {
+	return &ChallengeInfo{
+		Slug:         "ctf_web_chall",
+		Name:         "Chall",
+		Dashboard:    &Dashboard{Type: "compose", TTL: ttl},
+		Status:       StatusRunning,
+		ConnectedIPs: map[string]bool{"10.0.0.1": true},
+	}
+}
+
+func TestChallengeInfo_TTL(t *testing.T) {
+	c := newTTLChallenge(&config.TTL{Duration: 2 * time.Hour, Extension: 15 * time.Minute, MaxExtensions: 1})
+	now := time.Now()
+
+	if c.GetTTL() != nil || c.canExtendTTL() == nil {
+		t.Fatal("TTL must not run before it starts")
+	}
+	c.startTTL(now)
+	c.startTTL(now.Add(time.Hour)) // Already started
+	if want := now.Add(2 * time.Hour); !c.ExpiresAt.Equal(want) {
+		t.Fatalf("ExpiresAt = %v, want %v", c.ExpiresAt, want)
+	}
+
+	if extension, err := c.ExtendTTL(); err != nil || extension != 15*time.Minute {
+		t.Fatalf("ExtendTTL() = %v, %v", extension, err)
+	}
+	if _, err := c.ExtendTTL(); err == nil {
+		t.Error("expected the second extension to exceed the maximum")
+	}
+	ttl := c.GetTTL()
+	if ttl == nil || ttl.Extensions != 1 || ttl.MaxExtensions != 1 || ttl.RemainingSeconds < 8000 {
+		t.Errorf("GetTTL() = %+v", ttl)
+	}
+
+	if c.ttlExpired(now.Add(2*time.Hour)) || !c.ttlExpired(now.Add(2*time.Hour+15*time.Minute)) {
+		t.Error("TTL should expire after the extended deadline")
+	}
+
+	c.SetStatus(StatusStopped)
+	if c.GetTTL() != nil || c.Extensions != 0 {
+		t.Error("stopping must reset the TTL for the next start")
+	}
+}
+
+func TestChallengeInfo_TTLWithoutExtensions(t *testing.T) {
+	c := newTTLChallenge(&config.TTL{Duration: time.Hour})
+	c.startTTL(time.Now())
+	if _, err := c.ExtendTTL(); err == nil {
+		t.Error("expected extending to be disabled without maxExtensions")
+	}
+
+	c = newTTLChallenge(nil)
+	c.startTTL(time.Now())
+	if c.GetTTL() != nil {
+		t.Error("challenge without TTL must not get one")
+	}
+}
+
+func TestTTLMonitor_StartsRunningInstances(t *testing.T) {
+	cm := NewChallengeManager()
+	running := newTTLChallenge(&config.TTL{Duration: time.Hour})
+	stopped := newTTLChallenge(&config.TTL{Duration: time.Hour})
+	stopped.Slug, stopped.Status = "ctf_web_other", StatusStopped
+	cm.challenges[running.Slug] = running
+	cm.challenges[stopped.Slug] = stopped
+	wm := NewWSManager(cm, NewExecutor(), NewVotingManager(), NewRateLimiter())
+
+	NewTTLMonitor(cm, wm).check(time.Now())
+
+	if running.GetTTL() == nil {
+		t.Error("TTL of the running instance should have started")
+	}
+	if stopped.GetTTL() != nil {
+		t.Error("TTL of the stopped instance must not start")
+	}
+}
+
+func TestHandleExtendVoteTimeout(t *testing.T) {
+	cm := NewChallengeManager()
+	c := newTTLChallenge(&config.TTL{Duration: time.Hour, Extension: 30 * time.Minute, MaxExtensions: 2})
+	cm.challenges[c.Slug] = c
+	voting := NewVotingManager()
+	wm := NewWSManager(cm, NewExecutor(), voting, NewRateLimiter())
+
+	c.startTTL(time.Now())
+	expiresAt := c.ExpiresAt
+
+	key := voteKey(c.Slug, voteActionExtend)
+	if err := voting.StartVote(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	if voting.HasActiveVote(c.Slug) {
+		t.Fatal("extension vote must not block a restart vote")
+	}
+	_ = voting.CastVote(key, "10.0.0.1", true)
+	wm.handleExtendVoteTimeout(c.Slug)
+
+	if !c.ExpiresAt.Equal(expiresAt.Add(30*time.Minute)) || c.Extensions != 1 {
+		t.Errorf("ExpiresAt = %v (%d extensions), want extended by 30m", c.ExpiresAt, c.Extensions)
+	}
+	if voting.HasActiveVote(key) {
+		t.Error("vote should have ended")
+	}
+}
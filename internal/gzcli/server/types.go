@@ -1,12 +1,14 @@
 package server
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
 )
 
 // ChallengeStatus represents the current state of a challenge
@@ -43,14 +45,22 @@ const (
 
 // Dashboard represents the dashboard configuration from challenge.yml
 type Dashboard struct {
-	Type   string   `yaml:"type"`
-	Config string   `yaml:"config"`
-	Ports  []string `yaml:"ports"` // For dockerfile type
+	Type     string           `yaml:"type"`
+	Config   string           `yaml:"config"`
+	Ports    []string         `yaml:"ports"`              // For dockerfile type
+	WarmPool *config.WarmPool `yaml:"warmPool,omitempty"` // Pre-started idle instances
+	Idle     *config.Idle     `yaml:"idle,omitempty"`     // Idle detection before auto-stop
+	// Resource limits and network isolation of launched containers
+	Resources       *config.Resources `yaml:"resources,omitempty"`
+	IsolatedNetwork bool              `yaml:"isolatedNetwork,omitempty"`
+	TTL             *config.TTL       `yaml:"ttl,omitempty"`  // Hard lifetime of an instance
+	Gate            *config.Gate      `yaml:"gate,omitempty"` // Proof of work or CAPTCHA before starts and restarts
 }
 
 // ChallengeInfo holds information about a discovered challenge
 type ChallengeInfo struct {
 	Slug           string
+	Team           string // Team owning this instance in team isolation mode; empty for the shared instance
 	EventName      string
 	Category       string
 	Name           string
@@ -61,7 +71,12 @@ type ChallengeInfo struct {
 	Status         ChallengeStatus
 	LastRestart    time.Time
 	AllocatedPorts []string        // Dynamically allocated ports (host:container)
+	PortChecks     []PortCheck     // Connectivity self-test results for AllocatedPorts
+	Usage          *ResourceUsage  // Last sampled CPU and memory usage while running
+	ExpiresAt      time.Time       // End of the TTL of the running instance; zero until its TTL starts
+	Extensions     int             // TTL extensions approved since the instance started
 	ConnectedIPs   map[string]bool // Track unique IPs connected
+	project        string          // Compose project or container of a warm pool instance handed to this one
 	mu             sync.RWMutex
 }
 
@@ -70,32 +85,38 @@ type Client struct {
 	Conn      *websocket.Conn
 	IP        string
 	Challenge string // Challenge slug
+	Version   int    // Protocol version of the connection; 0 is protocol.V1
 	Send      chan []byte
+	pow       *powChallenge // Proof of work the client must solve next; only used by its read pump
+	requestID string        // ID of the request being handled, copied into replies; only used by its read pump
 }
 
-// WSMessage represents a WebSocket message
+// WSMessage represents a WebSocket message, see the protocol package
 type WSMessage struct {
 	Type    string      `json:"type"`
+	Version int         `json:"v,omitempty"`  // Protocol version, set from v2 on
+	ID      string      `json:"id,omitempty"` // Request ID of v2 requests and their replies
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// StatusMessage represents a status update message
-type StatusMessage struct {
-	Status         string   `json:"status"`
-	ConnectedUsers int      `json:"connected_users"`
-	AllocatedPorts []string `json:"allocated_ports,omitempty"`
+// encode marshals a message in the protocol version of the client. v1
+// messages carry neither version nor request ID.
+func (c *Client) encode(msg WSMessage) []byte {
+	if c.Version >= protocol.V2 {
+		msg.Version = c.Version
+	} else {
+		msg.Version, msg.ID = 0, ""
+	}
+	data, _ := json.Marshal(msg)
+	return data
 }
 
+// StatusMessage represents a status update message
+type StatusMessage = protocol.Status
+
 // VoteMessage represents a vote-related message
-type VoteMessage struct {
-	InitiatorIP  string  `json:"initiator_ip,omitempty"`
-	YesPercent   float64 `json:"yes_percent,omitempty"`
-	NoPercent    float64 `json:"no_percent,omitempty"`
-	TotalUsers   int     `json:"total_users,omitempty"`
-	Result       string  `json:"result,omitempty"`
-	RemainingMin int     `json:"remaining_min,omitempty"`
-}
+type VoteMessage = protocol.Vote
 
 // Vote represents a restart vote
 type Vote struct {
@@ -122,25 +143,35 @@ func (c *ChallengeInfo) GetConnectedUsers() int {
 // AddConnectedIP adds an IP to the connected users
 func (c *ChallengeInfo) AddConnectedIP(ip string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	if c.ConnectedIPs == nil {
 		c.ConnectedIPs = make(map[string]bool)
 	}
 	c.ConnectedIPs[ip] = true
+	c.mu.Unlock()
+	c.persist()
+	c.recordUsage(func(store *StateStore, key string) { store.RecordConnection(key, ip) })
 }
 
 // RemoveConnectedIP removes an IP from the connected users
 func (c *ChallengeInfo) RemoveConnectedIP(ip string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	delete(c.ConnectedIPs, ip)
+	c.mu.Unlock()
+	c.persist()
 }
 
 // SetStatus safely sets the challenge status
 func (c *ChallengeInfo) SetStatus(status ChallengeStatus) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.Status = status
+	if status == StatusStopped {
+		// The next start gets a full TTL
+		c.ExpiresAt = time.Time{}
+		c.Extensions = 0
+	}
+	c.mu.Unlock()
+	c.persist()
+	c.recordSession(status)
 }
 
 // GetStatus safely gets the challenge status
@@ -150,11 +181,14 @@ func (c *ChallengeInfo) GetStatus() ChallengeStatus {
 	return c.Status
 }
 
-// SetAllocatedPorts safely sets the allocated ports
+// SetAllocatedPorts safely sets the allocated ports, discarding port checks
+// made for the previous allocation
 func (c *ChallengeInfo) SetAllocatedPorts(ports []string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.AllocatedPorts = ports
+	c.PortChecks = nil
+	c.mu.Unlock()
+	c.persist()
 }
 
 // GetAllocatedPorts safely gets the allocated ports
@@ -164,6 +198,55 @@ func (c *ChallengeInfo) GetAllocatedPorts() []string {
 	return c.AllocatedPorts
 }
 
+// SetPortChecks safely sets the port self-test results
+func (c *ChallengeInfo) SetPortChecks(checks []PortCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.PortChecks = checks
+}
+
+// GetPortChecks safely gets the port self-test results
+func (c *ChallengeInfo) GetPortChecks() []PortCheck {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.PortChecks
+}
+
+// SetResourceUsage safely sets the last sampled resource usage
+func (c *ChallengeInfo) SetResourceUsage(usage *ResourceUsage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Usage = usage
+}
+
+// GetResourceUsage safely gets the last sampled resource usage
+func (c *ChallengeInfo) GetResourceUsage() *ResourceUsage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Usage
+}
+
+// projectName returns the Compose project or container name the instance
+// runs under: its instance key, unless it took over a warm pool instance
+func (c *ChallengeInfo) projectName() string {
+	c.mu.RLock()
+	project := c.project
+	c.mu.RUnlock()
+	if project != "" {
+		return project
+	}
+	return c.InstanceKey()
+}
+
+// setProject makes the instance run under the project of a warm pool
+// instance; "" returns it to its own instance key
+func (c *ChallengeInfo) setProject(project string) {
+	c.mu.Lock()
+	c.project = project
+	c.mu.Unlock()
+	c.persist()
+}
+
 // IsInCooldown checks if the challenge is in restart cooldown period
 // Uses a fixed 5-minute cooldown period
 func (c *ChallengeInfo) IsInCooldown() (bool, time.Duration) {
@@ -183,12 +266,17 @@ func (c *ChallengeInfo) IsInCooldown() (bool, time.Duration) {
 // SetLastRestart sets the last restart time
 func (c *ChallengeInfo) SetLastRestart(t time.Time) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.LastRestart = t
+	c.mu.Unlock()
+	c.persist()
+	c.recordUsage(func(store *StateStore, key string) { store.RecordRestart(key) })
 }
 
-// CalculateGracePeriod calculates the auto-stop grace period
-// Uses a fixed 2-minute grace period
+// CalculateGracePeriod calculates the auto-stop grace period: the one
+// configured for idle detection, otherwise 2 minutes
 func (c *ChallengeInfo) CalculateGracePeriod() time.Duration {
+	if c.Dashboard != nil && c.Dashboard.Idle != nil && c.Dashboard.Idle.GracePeriod > 0 {
+		return c.Dashboard.Idle.GracePeriod
+	}
 	return 2 * time.Minute
 }
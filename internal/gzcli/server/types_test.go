@@ -3,6 +3,8 @@ package server
 import (
 	"testing"
 	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 )
 
 func TestChallengeInfo_ConnectedUsers(t *testing.T) {
@@ -101,11 +103,16 @@ func TestChallengeInfo_GracePeriod(t *testing.T) {
 		Name: "Test Challenge",
 	}
 
-	// Grace period is now fixed at 2 minutes
+	// Grace period defaults to 2 minutes
 	gracePeriod := challenge.CalculateGracePeriod()
 	expected := 2 * time.Minute
 
 	if gracePeriod != expected {
 		t.Errorf("Expected grace period %v, got %v", expected, gracePeriod)
 	}
+
+	challenge.Dashboard = &Dashboard{Idle: &config.Idle{GracePeriod: 10 * time.Minute}}
+	if gracePeriod := challenge.CalculateGracePeriod(); gracePeriod != 10*time.Minute {
+		t.Errorf("Expected configured grace period 10m, got %v", gracePeriod)
+	}
 }
@@ -0,0 +1,454 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Usage report formats
+const (
+	UsageFormatText = "text"
+	UsageFormatJSON = "json"
+	UsageFormatCSV  = "csv"
+)
+
+// usageSchema records the sessions of instances, from start to stop, and
+// the votes held on them, for usage reports after the event
+const usageSchema = `
+	CREATE TABLE IF NOT EXISTS sessions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		key TEXT NOT NULL,
+		slug TEXT NOT NULL,
+		team TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		stopped_at INTEGER NOT NULL DEFAULT 0,
+		restarts INTEGER NOT NULL DEFAULT 0,
+		peak_cpu REAL NOT NULL DEFAULT 0,
+		peak_memory INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_sessions_open ON sessions(key, stopped_at);
+	CREATE TABLE IF NOT EXISTS session_ips (
+		session_id INTEGER NOT NULL,
+		ip TEXT NOT NULL,
+		PRIMARY KEY (session_id, ip)
+	);
+	CREATE TABLE IF NOT EXISTS votes (
+		key TEXT NOT NULL,
+		action TEXT NOT NULL,
+		outcome TEXT NOT NULL,
+		yes INTEGER NOT NULL,
+		no INTEGER NOT NULL,
+		ended_at INTEGER NOT NULL
+	);
+`
+
+// StartSession records that an instance started running, with the users
+// already connected. An instance with a session still open keeps it, e.g.
+// one taken over after a launcher restart.
+func (s *StateStore) StartSession(c *ChallengeInfo, at time.Time) {
+	c.mu.RLock()
+	key, slug, team := instanceKey(c.Slug, c.Team), c.Slug, c.Team
+	ips := make([]string, 0, len(c.ConnectedIPs))
+	for ip := range c.ConnectedIPs {
+		ips = append(ips, ip)
+	}
+	c.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(`
+		INSERT INTO sessions (key, slug, team, started_at)
+		SELECT ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM sessions WHERE key = ? AND stopped_at = 0)`,
+		key, slug, team, at.UnixMilli(), key)
+	if err != nil {
+		log.Error("Failed to record session of %s: %v", key, err)
+		return
+	}
+	for _, ip := range ips {
+		s.addSessionIP(key, ip)
+	}
+}
+
+// StopSession closes the open session of an instance
+func (s *StateStore) StopSession(key string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.db.Exec(`UPDATE sessions SET stopped_at = ? WHERE key = ? AND stopped_at = 0`, at.UnixMilli(), key); err != nil {
+		log.Error("Failed to close session of %s: %v", key, err)
+	}
+}
+
+// RecordConnection records a user connecting to the open session of an
+// instance
+func (s *StateStore) RecordConnection(key, ip string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addSessionIP(key, ip)
+}
+
+func (s *StateStore) addSessionIP(key, ip string) {
+	_, err := s.db.Exec(`
+		INSERT OR IGNORE INTO session_ips (session_id, ip)
+		SELECT id, ? FROM sessions WHERE key = ? AND stopped_at = 0`, ip, key)
+	if err != nil {
+		log.Error("Failed to record connection to %s: %v", key, err)
+	}
+}
+
+// RecordRestart counts a restart of the open session of an instance
+func (s *StateStore) RecordRestart(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.db.Exec(`UPDATE sessions SET restarts = restarts + 1 WHERE key = ? AND stopped_at = 0`, key); err != nil {
+		log.Error("Failed to record restart of %s: %v", key, err)
+	}
+}
+
+// RecordResourceUsage keeps the peak CPU and memory usage of the open
+// session of an instance
+func (s *StateStore) RecordResourceUsage(key string, usage *ResourceUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(`
+		UPDATE sessions SET peak_cpu = MAX(peak_cpu, ?), peak_memory = MAX(peak_memory, ?)
+		WHERE key = ? AND stopped_at = 0`, usage.CPUPercent, int64(usage.MemoryUsage), key) //nolint:gosec // G115: Memory usage fits in int64
+	if err != nil {
+		log.Error("Failed to record resource usage of %s: %v", key, err)
+	}
+}
+
+// RecordVote records the outcome of a restart or extension vote, keyed by
+// voteKey
+func (s *StateStore) RecordVote(voteKey, outcome string, yes, no int, at time.Time) {
+	key, action := voteKey, voteActionRestart
+	if k, ok := strings.CutSuffix(voteKey, "/"+voteActionExtend); ok {
+		key, action = k, voteActionExtend
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.db.Exec(`INSERT INTO votes (key, action, outcome, yes, no, ended_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		key, action, outcome, yes, no, at.UnixMilli())
+	if err != nil {
+		log.Error("Failed to record vote on %s: %v", key, err)
+	}
+}
+
+// recordSession opens or closes the session of the instance as its status
+// changes
+func (c *ChallengeInfo) recordSession(status ChallengeStatus) {
+	store := getInstanceStore()
+	if store == nil || isWarmTeam(c.Team) {
+		return
+	}
+	switch status {
+	case StatusRunning:
+		store.StartSession(c, time.Now())
+	case StatusStopped:
+		store.StopSession(c.InstanceKey(), time.Now())
+	}
+}
+
+// recordUsage runs record with the state store, if persistence is enabled
+// and the instance is not an idle warm pool instance
+func (c *ChallengeInfo) recordUsage(record func(store *StateStore, key string)) {
+	if isWarmTeam(c.Team) {
+		return
+	}
+	if store := getInstanceStore(); store != nil {
+		record(store, c.InstanceKey())
+	}
+}
+
+// ChallengeUsage summarizes the recorded sessions of a challenge
+type ChallengeUsage struct {
+	Slug           string        `json:"slug"`
+	Sessions       int           `json:"sessions"`
+	Teams          int           `json:"teams"`          // Teams that ran an instance; 0 without team isolation
+	PeakInstances  int           `json:"peak_instances"` // Most instances running at once
+	RunTime        time.Duration `json:"run_time_ns"`
+	LongestSession time.Duration `json:"longest_session_ns"`
+	UniqueIPs      int           `json:"unique_ips"`
+	Restarts       int           `json:"restarts"`
+	PeakCPUPercent float64       `json:"peak_cpu_percent"`
+	PeakMemory     uint64        `json:"peak_memory"` // Bytes
+	VotesApproved  int           `json:"votes_approved"`
+	VotesRejected  int           `json:"votes_rejected"`
+	VotesOther     int           `json:"votes_other"` // Expired, or ended by the organizer or a removal
+}
+
+// AverageSession returns the mean duration of the sessions
+func (u ChallengeUsage) AverageSession() time.Duration {
+	if u.Sessions == 0 {
+		return 0
+	}
+	return u.RunTime / time.Duration(u.Sessions)
+}
+
+// usageSession is a recorded session; stopped is zero while it runs
+type usageSession struct {
+	slug, team       string
+	started, stopped time.Time
+}
+
+// UsageReport summarizes, per challenge, the sessions running after since
+// (all when zero) and the votes ended after it, sorted by run time. Sessions
+// still running count up to now.
+func (s *StateStore) UsageReport(since, now time.Time) ([]ChallengeUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage := make(map[string]*ChallengeUsage)
+	get := func(slug string) *ChallengeUsage {
+		if u, ok := usage[slug]; ok {
+			return u
+		}
+		u := &ChallengeUsage{Slug: slug}
+		usage[slug] = u
+		return u
+	}
+
+	rows, err := s.db.Query(`
+		SELECT slug, team, started_at, stopped_at, restarts, peak_cpu, peak_memory
+		FROM sessions WHERE stopped_at = 0 OR stopped_at >= ?`, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	var sessions []usageSession
+	teams := make(map[string]map[string]bool)
+	for rows.Next() {
+		var session usageSession
+		var startedAt, stoppedAt, peakMemory int64
+		var restarts int
+		var peakCPU float64
+		if err := rows.Scan(&session.slug, &session.team, &startedAt, &stoppedAt, &restarts, &peakCPU, &peakMemory); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		session.started = time.UnixMilli(startedAt)
+		end := now
+		if stoppedAt > 0 {
+			session.stopped = time.UnixMilli(stoppedAt)
+			end = session.stopped
+		}
+		sessions = append(sessions, session)
+
+		u := get(session.slug)
+		u.Sessions++
+		u.Restarts += restarts
+		if d := end.Sub(session.started); d > 0 {
+			u.RunTime += d
+			u.LongestSession = max(u.LongestSession, d)
+		}
+		u.PeakCPUPercent = max(u.PeakCPUPercent, peakCPU)
+		u.PeakMemory = max(u.PeakMemory, uint64(max(peakMemory, 0)))
+		if session.team != "" {
+			if teams[session.slug] == nil {
+				teams[session.slug] = make(map[string]bool)
+			}
+			teams[session.slug][session.team] = true
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	for slug, set := range teams {
+		usage[slug].Teams = len(set)
+	}
+	for slug, peak := range peakInstances(sessions, now) {
+		usage[slug].PeakInstances = peak
+	}
+
+	err = s.scanCounts(`
+		SELECT s.slug, COUNT(DISTINCT i.ip) FROM session_ips i JOIN sessions s ON s.id = i.session_id
+		WHERE s.stopped_at = 0 OR s.stopped_at >= ?
+		GROUP BY s.slug`, func(slug, _ string, n int) {
+		get(slug).UniqueIPs = n
+	}, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count connections: %w", err)
+	}
+
+	err = s.scanCounts(`
+		SELECT k.slug, v.outcome, COUNT(*) FROM votes v
+		JOIN (SELECT DISTINCT key, slug FROM sessions) k ON k.key = v.key
+		WHERE v.ended_at >= ? GROUP BY k.slug, v.outcome`, func(slug, outcome string, n int) {
+		u := get(slug)
+		switch outcome {
+		case "approved":
+			u.VotesApproved += n
+		case "rejected":
+			u.VotesRejected += n
+		default:
+			u.VotesOther += n
+		}
+	}, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count votes: %w", err)
+	}
+
+	report := make([]ChallengeUsage, 0, len(usage))
+	for _, u := range usage {
+		report = append(report, *u)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].RunTime != report[j].RunTime {
+			return report[i].RunTime > report[j].RunTime
+		}
+		return report[i].Slug < report[j].Slug
+	})
+	return report, nil
+}
+
+// scanCounts runs a query returning a slug, optionally a label, and a count
+// per row
+func (s *StateStore) scanCounts(query string, fn func(slug, label string, n int), args ...any) error {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var slug, label string
+		var n int
+		dest := []any{&slug, &n}
+		if len(columns) == 3 {
+			dest = []any{&slug, &label, &n}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		fn(slug, label, n)
+	}
+	return rows.Err()
+}
+
+// peakInstances returns the most sessions of each challenge running at once
+func peakInstances(sessions []usageSession, now time.Time) map[string]int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	edges := make(map[string][]edge)
+	for _, session := range sessions {
+		end := session.stopped
+		if end.IsZero() {
+			end = now
+		}
+		edges[session.slug] = append(edges[session.slug], edge{session.started, 1}, edge{end, -1})
+	}
+
+	peaks := make(map[string]int)
+	for slug, list := range edges {
+		// Stops sort before starts at the same time, so back-to-back
+		// sessions do not overlap
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].at.Equal(list[j].at) {
+				return list[i].at.Before(list[j].at)
+			}
+			return list[i].delta < list[j].delta
+		})
+		running := 0
+		for _, e := range list {
+			running += e.delta
+			peaks[slug] = max(peaks[slug], running)
+		}
+	}
+	return peaks
+}
+
+// UsageFormats lists the supported usage report formats
+func UsageFormats() []string {
+	return []string{UsageFormatText, UsageFormatJSON, UsageFormatCSV}
+}
+
+// RenderUsageReport writes a usage report in format
+func RenderUsageReport(w io.Writer, format string, report []ChallengeUsage) error {
+	switch format {
+	case UsageFormatText:
+		return renderUsageText(w, report)
+	case UsageFormatJSON:
+		if report == nil {
+			report = []ChallengeUsage{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case UsageFormatCSV:
+		return renderUsageCSV(w, report)
+	default:
+		return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(UsageFormats(), ", "))
+	}
+}
+
+func renderUsageText(w io.Writer, report []ChallengeUsage) error {
+	if len(report) == 0 {
+		_, err := fmt.Fprintln(w, "No recorded sessions")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CHALLENGE\tSESSIONS\tTEAMS\tPEAK\tRUN TIME\tAVG\tLONGEST\tIPS\tRESTARTS\tCPU\tMEMORY\tVOTES (+/-/other)")
+	for _, u := range report {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%d\t%d\t%.1f%%\t%s\t%d/%d/%d\n",
+			u.Slug, u.Sessions, u.Teams, u.PeakInstances,
+			formatUsageDuration(u.RunTime), formatUsageDuration(u.AverageSession()), formatUsageDuration(u.LongestSession),
+			u.UniqueIPs, u.Restarts, u.PeakCPUPercent, formatMemory(u.PeakMemory),
+			u.VotesApproved, u.VotesRejected, u.VotesOther)
+	}
+	return tw.Flush()
+}
+
+func renderUsageCSV(w io.Writer, report []ChallengeUsage) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"slug", "sessions", "teams", "peak_instances", "run_time_seconds", "average_session_seconds",
+		"longest_session_seconds", "unique_ips", "restarts", "peak_cpu_percent", "peak_memory_bytes",
+		"votes_approved", "votes_rejected", "votes_other"})
+	for _, u := range report {
+		_ = cw.Write([]string{
+			u.Slug, strconv.Itoa(u.Sessions), strconv.Itoa(u.Teams), strconv.Itoa(u.PeakInstances),
+			strconv.Itoa(int(u.RunTime.Seconds())), strconv.Itoa(int(u.AverageSession().Seconds())),
+			strconv.Itoa(int(u.LongestSession.Seconds())), strconv.Itoa(u.UniqueIPs), strconv.Itoa(u.Restarts),
+			strconv.FormatFloat(u.PeakCPUPercent, 'f', 1, 64), strconv.FormatUint(u.PeakMemory, 10),
+			strconv.Itoa(u.VotesApproved), strconv.Itoa(u.VotesRejected), strconv.Itoa(u.VotesOther),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatUsageDuration prints a duration rounded to the minute, or the second
+// below one minute
+func formatUsageDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
+// formatMemory prints a size in bytes with a binary unit
+func formatMemory(n uint64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fGiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMiB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%.1fKiB", float64(n)/(1<<10))
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStateStore_UsageReport(t *testing.T) {
+	store := openTestStore(t)
+	setInstanceStore(store)
+
+	web := newStateChallenge("ctf-web-a")
+	web.AddConnectedIP("10.0.0.1") // Connected before the start
+	web.SetStatus(StatusRunning)
+	web.AddConnectedIP("10.0.0.2")
+	web.SetLastRestart(time.Now())
+	web.recordUsage(func(store *StateStore, key string) {
+		store.RecordResourceUsage(key, &ResourceUsage{CPUPercent: 40, MemoryUsage: 64 << 20})
+	})
+	web.recordUsage(func(store *StateStore, key string) {
+		store.RecordResourceUsage(key, &ResourceUsage{CPUPercent: 10, MemoryUsage: 128 << 20})
+	})
+	web.SetStatus(StatusStopped)
+	web.SetStatus(StatusRunning)
+	web.AddConnectedIP("10.0.0.1")
+
+	// Two team instances running at once, and an idle warm pool instance
+	pwn := newStateChallenge("ctf-pwn-b")
+	red, blue := pwn.newTeamInstance("red"), pwn.newTeamInstance("blue")
+	started := time.Now().Add(-10 * time.Minute)
+	store.StartSession(red, started)
+	store.StartSession(blue, started.Add(time.Minute))
+	store.StopSession(red.InstanceKey(), started.Add(2*time.Minute))
+	pwn.newTeamInstance(warmTeamPrefix + "1").SetStatus(StatusRunning)
+
+	voting := NewVotingManager()
+	for _, outcome := range []string{"approved", "rejected", "expired"} {
+		key := web.InstanceKey()
+		if outcome == "expired" {
+			key = voteKey(key, voteActionExtend)
+		}
+		if err := voting.StartVote(key, func() {}); err != nil {
+			t.Fatalf("StartVote: %v", err)
+		}
+		_ = voting.CastVote(key, "10.0.0.1", true)
+		voting.EndVote(key, outcome)
+	}
+
+	report, err := store.UsageReport(time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("UsageReport: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("report = %+v, want 2 challenges", report)
+	}
+	byslug := map[string]ChallengeUsage{report[0].Slug: report[0], report[1].Slug: report[1]}
+
+	got := byslug["ctf-web-a"]
+	if got.Sessions != 2 || got.UniqueIPs != 2 || got.Restarts != 1 || got.Teams != 0 || got.PeakInstances != 1 {
+		t.Errorf("web usage = %+v, want 2 sessions, 2 IPs, 1 restart, 1 instance at once", got)
+	}
+	if got.PeakCPUPercent != 40 || got.PeakMemory != 128<<20 {
+		t.Errorf("web peaks = %.0f%%, %d bytes, want 40%% and 128MiB", got.PeakCPUPercent, got.PeakMemory)
+	}
+	if got.VotesApproved != 1 || got.VotesRejected != 1 || got.VotesOther != 1 {
+		t.Errorf("web votes = %d/%d/%d, want 1/1/1", got.VotesApproved, got.VotesRejected, got.VotesOther)
+	}
+	if got.RunTime < time.Hour || got.LongestSession < time.Hour {
+		t.Errorf("web run time = %v, longest %v, want the running session counted up to now", got.RunTime, got.LongestSession)
+	}
+
+	got = byslug["ctf-pwn-b"]
+	if got.Sessions != 2 || got.Teams != 2 || got.PeakInstances != 2 {
+		t.Errorf("pwn usage = %+v, want 2 team sessions running at once, without the warm instance", got)
+	}
+
+	if recent, err := store.UsageReport(time.Now().Add(time.Minute), time.Now()); err != nil || len(recent) != 2 || recent[0].Sessions != 1 {
+		t.Errorf("UsageReport(since) = %+v, %v, want only the running sessions", recent, err)
+	}
+}
+
+func TestRenderUsageReport(t *testing.T) {
+	report := []ChallengeUsage{{Slug: "ctf-web-a", Sessions: 2, RunTime: 90 * time.Minute, UniqueIPs: 3, PeakMemory: 3 << 29}}
+
+	var text bytes.Buffer
+	if err := RenderUsageReport(&text, UsageFormatText, report); err != nil {
+		t.Fatalf("RenderUsageReport(text): %v", err)
+	}
+	if out := text.String(); !strings.Contains(out, "ctf-web-a") || !strings.Contains(out, "1h30m") ||
+		!strings.Contains(out, "45m") || !strings.Contains(out, "1.5GiB") {
+		t.Errorf("text report = %q, want the run time, average and memory", out)
+	}
+
+	var csv bytes.Buffer
+	if err := RenderUsageReport(&csv, UsageFormatCSV, report); err != nil {
+		t.Fatalf("RenderUsageReport(csv): %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], "ctf-web-a,2,0,0,5400,2700,") {
+		t.Errorf("csv report = %q", csv.String())
+	}
+
+	var json bytes.Buffer
+	if err := RenderUsageReport(&json, UsageFormatJSON, nil); err != nil || strings.TrimSpace(json.String()) != "[]" {
+		t.Errorf("empty json report = %q, %v", json.String(), err)
+	}
+	if err := RenderUsageReport(&json, "xml", report); err == nil {
+		t.Error("RenderUsageReport accepted an unknown format")
+	}
+}
@@ -16,7 +16,8 @@ const (
 	VoteThreshold = 0.5 // 50%
 )
 
-// VotingManager manages restart votes for challenges
+// VotingManager manages restart and extension votes for challenges, keyed
+// by voteKey
 type VotingManager struct {
 	votes map[string]*Vote // challenge slug -> Vote
 	mu    sync.RWMutex
@@ -29,7 +30,7 @@ func NewVotingManager() *VotingManager {
 	}
 }
 
-// StartVote starts a new restart vote for a challenge
+// StartVote starts a new vote for a challenge
 // onTimeout is called when the vote expires
 func (vm *VotingManager) StartVote(slug string, onTimeout func()) error {
 	vm.mu.Lock()
@@ -47,8 +48,9 @@ func (vm *VotingManager) StartVote(slug string, onTimeout func()) error {
 	}
 
 	vm.votes[slug] = vote
+	launcherMetrics.voteStarted()
 
-	log.InfoH2("Restart vote started for challenge: %s", slug)
+	log.InfoH2("Vote started for challenge: %s", slug)
 
 	// Start timeout timer
 	go func() {
@@ -157,9 +159,14 @@ func (vm *VotingManager) EndVote(slug string, reason string) {
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
 
-	if _, exists := vm.votes[slug]; exists {
+	if vote, exists := vm.votes[slug]; exists {
 		delete(vm.votes, slug)
-		log.InfoH2("Restart vote ended for challenge: %s (reason: %s)", slug, reason)
+		launcherMetrics.voteEnded(reason)
+		if store := getInstanceStore(); store != nil {
+			yes, no := vote.tally()
+			store.RecordVote(slug, reason, yes, no, time.Now())
+		}
+		log.InfoH2("Vote ended for challenge: %s (reason: %s)", slug, reason)
 	}
 }
 
@@ -197,3 +204,17 @@ func maskIP(ip string) string {
 	}
 	return "x.x.x.x"
 }
+
+// tally counts the yes and no votes cast
+func (v *Vote) tally() (yes, no int) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for _, voteYes := range v.Votes {
+		if voteYes {
+			yes++
+		} else {
+			no++
+		}
+	}
+	return yes, no
+}
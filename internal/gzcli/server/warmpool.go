@@ -0,0 +1,282 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// warmPoolInterval is how often warm pools drop expired instances and are
+// refilled after failed starts
+const warmPoolInterval = 30 * time.Second
+
+// warmTeamPrefix prefixes the team of idle warm pool instances
+const warmTeamPrefix = "warm--"
+
+// instanceStarter starts and stops launcher instances
+type instanceStarter interface {
+	Start(challenge *ChallengeInfo) error
+	Stop(challenge *ChallengeInfo) error
+}
+
+// WarmPool keeps pre-started idle instances of challenges whose dashboard
+// configures a warm pool. A requested instance takes over an idle one and
+// is running at once, and the pool is refilled in the background.
+type WarmPool struct {
+	starter  instanceStarter
+	pools    map[string]*challengePool // slug -> pool
+	seq      int
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	stopChan chan struct{}
+	now      func() time.Time
+}
+
+// challengePool holds the idle instances of one challenge
+type challengePool struct {
+	base     *ChallengeInfo
+	size     int
+	maxAge   time.Duration
+	ready    []*warmInstance // Oldest first
+	starting int
+	closed   bool
+}
+
+// warmInstance is an idle running instance
+type warmInstance struct {
+	info    *ChallengeInfo
+	started time.Time
+}
+
+// NewWarmPool creates the warm pools of the challenges configuring one
+func NewWarmPool(starter instanceStarter, challenges []*ChallengeInfo) *WarmPool {
+	wp := &WarmPool{
+		starter:  starter,
+		pools:    make(map[string]*challengePool),
+		stopChan: make(chan struct{}),
+		now:      time.Now,
+	}
+	for _, challenge := range challenges {
+		pool := challenge.Dashboard.WarmPool
+		if pool == nil || pool.Size <= 0 {
+			continue
+		}
+		if LauncherType(challenge.Dashboard.Type) == LauncherTypeKubernetes {
+			log.Error("Warm pool of %s ignored: not supported for kubernetes challenges", challenge.Name)
+			continue
+		}
+		wp.pools[challenge.Slug] = &challengePool{base: challenge, size: pool.Size, maxAge: pool.MaxAge}
+	}
+	return wp
+}
+
+// isWarmTeam reports whether team is the team of an idle warm pool instance
+func isWarmTeam(team string) bool {
+	return strings.HasPrefix(team, warmTeamPrefix)
+}
+
+// Reserve keeps the pool from naming new idle instances after the projects
+// of instances restored from a previous run, which may have taken over a
+// warm instance
+func (wp *WarmPool) Reserve(projects []string) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	for _, project := range projects {
+		i := strings.LastIndex(project, "--"+warmTeamPrefix)
+		if i < 0 {
+			continue
+		}
+		if n, err := strconv.Atoi(project[i+len("--"+warmTeamPrefix):]); err == nil && n > wp.seq {
+			wp.seq = n
+		}
+	}
+}
+
+// Start fills the pools and keeps them filled until Stop
+func (wp *WarmPool) Start() {
+	if len(wp.pools) == 0 {
+		return
+	}
+	log.Info("Warming %d challenge pool(s)", len(wp.pools))
+	wp.maintain()
+
+	wp.wg.Add(1)
+	go func() {
+		defer wp.wg.Done()
+		ticker := time.NewTicker(warmPoolInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-wp.stopChan:
+				return
+			case <-ticker.C:
+				wp.maintain()
+			}
+		}
+	}()
+}
+
+// Stop stops the refill loop and every idle instance
+func (wp *WarmPool) Stop() {
+	close(wp.stopChan)
+	wp.mu.Lock()
+	slugs := make([]string, 0, len(wp.pools))
+	for slug := range wp.pools {
+		slugs = append(slugs, slug)
+	}
+	wp.mu.Unlock()
+	for _, slug := range slugs {
+		wp.Remove(slug)
+	}
+	wp.wg.Wait()
+}
+
+// Take hands an idle instance of the challenge to instance, which then runs
+// under the idle instance's project with its ports. It returns false if the
+// challenge has no warm pool or no idle instance is ready.
+func (wp *WarmPool) Take(instance *ChallengeInfo) bool {
+	if wp == nil {
+		return false
+	}
+	wp.mu.Lock()
+	pool, ok := wp.pools[instance.Slug]
+	if !ok {
+		wp.mu.Unlock()
+		return false
+	}
+	expired := pool.dropExpired(wp.now())
+	var warm *warmInstance
+	if len(pool.ready) > 0 {
+		warm = pool.ready[0]
+		pool.ready = pool.ready[1:]
+	}
+	wp.mu.Unlock()
+
+	if len(expired) > 0 {
+		wp.wg.Add(1)
+		go func() {
+			defer wp.wg.Done()
+			wp.stopAll(expired)
+		}()
+	}
+	wp.fill(instance.Slug)
+	if warm == nil {
+		return false
+	}
+
+	instance.setProject(warm.info.projectName())
+	instance.SetAllocatedPorts(warm.info.GetAllocatedPorts())
+	log.InfoH3("Handed warm instance %s to %s", warm.info.InstanceKey(), instance.InstanceKey())
+	return true
+}
+
+// Remove stops the idle instances of a challenge and drops its pool
+func (wp *WarmPool) Remove(slug string) {
+	if wp == nil {
+		return
+	}
+	wp.mu.Lock()
+	pool, ok := wp.pools[slug]
+	if !ok {
+		wp.mu.Unlock()
+		return
+	}
+	delete(wp.pools, slug)
+	pool.closed = true
+	ready := pool.ready
+	pool.ready = nil
+	wp.mu.Unlock()
+
+	wp.stopAll(ready)
+}
+
+// maintain replaces expired idle instances and refills every pool
+func (wp *WarmPool) maintain() {
+	wp.mu.Lock()
+	var expired []*warmInstance
+	slugs := make([]string, 0, len(wp.pools))
+	for slug, pool := range wp.pools {
+		expired = append(expired, pool.dropExpired(wp.now())...)
+		slugs = append(slugs, slug)
+	}
+	wp.mu.Unlock()
+
+	wp.stopAll(expired)
+	for _, slug := range slugs {
+		wp.fill(slug)
+	}
+}
+
+// fill starts instances in the background until the pool is full
+func (wp *WarmPool) fill(slug string) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	pool, ok := wp.pools[slug]
+	if !ok {
+		return
+	}
+	for len(pool.ready)+pool.starting < pool.size {
+		pool.starting++
+		wp.seq++
+		info := pool.base.newTeamInstance(fmt.Sprintf("%s%d", warmTeamPrefix, wp.seq))
+		wp.wg.Add(1)
+		go wp.startInstance(pool, info)
+	}
+}
+
+// startInstance starts an idle instance and adds it to its pool. Failed
+// starts are retried by the next maintenance round.
+func (wp *WarmPool) startInstance(pool *challengePool, info *ChallengeInfo) {
+	defer wp.wg.Done()
+	err := wp.starter.Start(info)
+
+	wp.mu.Lock()
+	pool.starting--
+	closed := pool.closed
+	if err == nil && !closed {
+		info.SetStatus(StatusRunning)
+		pool.ready = append(pool.ready, &warmInstance{info: info, started: wp.now()})
+	}
+	wp.mu.Unlock()
+
+	switch {
+	case err != nil:
+		log.Error("Failed to start warm instance of %s: %v", pool.base.Name, err)
+	case closed:
+		wp.stopAll([]*warmInstance{{info: info}})
+	default:
+		log.InfoH3("Warm instance of %s ready: %s", pool.base.Name, info.InstanceKey())
+	}
+}
+
+// stopAll stops idle instances that left their pool
+func (wp *WarmPool) stopAll(instances []*warmInstance) {
+	for _, warm := range instances {
+		if err := wp.starter.Stop(warm.info); err != nil {
+			log.Error("Failed to stop warm instance %s: %v", warm.info.InstanceKey(), err)
+		}
+		warm.info.SetStatus(StatusStopped)
+	}
+}
+
+// dropExpired removes and returns the idle instances older than the max age
+func (p *challengePool) dropExpired(now time.Time) []*warmInstance {
+	if p.maxAge <= 0 {
+		return nil
+	}
+	var expired []*warmInstance
+	kept := p.ready[:0]
+	for _, warm := range p.ready {
+		if now.Sub(warm.started) >= p.maxAge {
+			expired = append(expired, warm)
+		} else {
+			kept = append(kept, warm)
+		}
+	}
+	p.ready = kept
+	return expired
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// fakeStarter records started and stopped projects and gives every started
+// instance a port
+type fakeStarter struct {
+	mu      sync.Mutex
+	started []string
+	stopped []string
+	fail    bool
+}
+
+func (f *fakeStarter) Start(c *ChallengeInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail {
+		return errors.New("start failed")
+	}
+	f.started = append(f.started, c.projectName())
+	c.SetAllocatedPorts([]string{"31337:80"})
+	return nil
+}
+
+func (f *fakeStarter) Stop(c *ChallengeInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = append(f.stopped, c.projectName())
+	return nil
+}
+
+func (f *fakeStarter) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.started), len(f.stopped)
+}
+
+func newWarmChallenge(pool *config.WarmPool) *ChallengeInfo {
+	return &ChallengeInfo{
+		Slug:         "ctf-pwn-heavy",
+		Name:         "Heavy",
+		Dashboard:    &Dashboard{Type: string(LauncherTypeCompose), Config: "docker-compose.yml", WarmPool: pool},
+		Status:       StatusStopped,
+		ConnectedIPs: make(map[string]bool),
+	}
+}
+
+// waitIdle waits until the pool of slug has n idle instances
+func waitIdle(t *testing.T, wp *WarmPool, slug string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		wp.mu.Lock()
+		idle := len(wp.pools[slug].ready)
+		wp.mu.Unlock()
+		if idle == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pool has %d idle instances, want %d", idle, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWarmPool_TakeAndReplenish(t *testing.T) {
+	starter := &fakeStarter{}
+	base := newWarmChallenge(&config.WarmPool{Size: 2})
+	wp := NewWarmPool(starter, []*ChallengeInfo{base})
+	wp.Start()
+	defer wp.Stop()
+	waitIdle(t, wp, base.Slug, 2)
+
+	instance := base.newTeamInstance("team-a")
+	if !wp.Take(instance) {
+		t.Fatal("Take() = false with idle instances")
+	}
+	if project := instance.projectName(); project == instance.InstanceKey() {
+		t.Errorf("instance runs under its own project %q, want the warm one", project)
+	}
+	if ports := instance.GetAllocatedPorts(); len(ports) != 1 {
+		t.Errorf("instance ports = %v, want the warm instance's", ports)
+	}
+
+	// The taken instance is replaced in the background
+	waitIdle(t, wp, base.Slug, 2)
+	if started, _ := starter.counts(); started != 3 {
+		t.Errorf("started %d instances, want 3", started)
+	}
+}
+
+func TestWarmPool_Expired(t *testing.T) {
+	starter := &fakeStarter{}
+	base := newWarmChallenge(&config.WarmPool{Size: 1, MaxAge: time.Minute})
+	wp := NewWarmPool(starter, []*ChallengeInfo{base})
+	now := time.Now()
+	wp.now = func() time.Time { return now }
+	wp.Start()
+	defer wp.Stop()
+	waitIdle(t, wp, base.Slug, 1)
+
+	now = now.Add(2 * time.Minute)
+	wp.maintain()
+	if _, stopped := starter.counts(); stopped != 1 {
+		t.Errorf("stopped %d expired instances, want 1", stopped)
+	}
+	waitIdle(t, wp, base.Slug, 1)
+}
+
+func TestWarmPool_NoPool(t *testing.T) {
+	starter := &fakeStarter{}
+	plain := newWarmChallenge(nil)
+	wp := NewWarmPool(starter, []*ChallengeInfo{plain})
+	wp.Start()
+	defer wp.Stop()
+
+	if wp.Take(plain) {
+		t.Error("Take() = true for a challenge without a warm pool")
+	}
+	if plain.projectName() != plain.InstanceKey() {
+		t.Errorf("project = %q, want the instance key", plain.projectName())
+	}
+	var nilPool *WarmPool
+	if nilPool.Take(plain) {
+		t.Error("nil pool handed out an instance")
+	}
+}
+
+func TestWarmPool_FailedStart(t *testing.T) {
+	starter := &fakeStarter{fail: true}
+	base := newWarmChallenge(&config.WarmPool{Size: 1})
+	wp := NewWarmPool(starter, []*ChallengeInfo{base})
+	wp.Start()
+	defer wp.Stop()
+
+	// The start in flight fails; a cold start is needed meanwhile
+	if wp.Take(base.newTeamInstance("team-a")) {
+		t.Error("Take() = true without a running idle instance")
+	}
+}
+
+func TestWarmPool_StopStopsIdle(t *testing.T) {
+	starter := &fakeStarter{}
+	base := newWarmChallenge(&config.WarmPool{Size: 2})
+	wp := NewWarmPool(starter, []*ChallengeInfo{base})
+	wp.Start()
+	waitIdle(t, wp, base.Slug, 2)
+
+	wp.Stop()
+	if _, stopped := starter.counts(); stopped != 2 {
+		t.Errorf("stopped %d idle instances, want 2", stopped)
+	}
+}
+
+func TestExecutorStop_KeepsProjectOnFailure(t *testing.T) {
+	c := newWarmChallenge(nil)
+	c.setProject("ctf-pwn-heavy--warm--1")
+	// Stopping an unknown launcher type fails and keeps the project
+	c.Dashboard.Type = "unknown"
+	if err := NewExecutor().Stop(c); err == nil {
+		t.Fatal("Stop() of an unknown launcher type succeeded")
+	}
+	if c.projectName() != "ctf-pwn-heavy--warm--1" {
+		t.Errorf("project = %q after a failed stop", c.projectName())
+	}
+}
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/dimasma0305/gzcli/internal/log"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
 )
 
 const (
@@ -23,13 +26,14 @@ const (
 	// Send pings to peer with this period (must be less than pongWait)
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	// Maximum message size allowed from peer, fitting CAPTCHA tokens of gates
+	maxMessageSize = 4096
 )
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    protocol.Subprotocols(),
 	CheckOrigin: func(_ *http.Request) bool {
 		return true // Allow all origins (adjust for production)
 	},
@@ -37,14 +41,15 @@ var upgrader = websocket.Upgrader{
 
 // WSManager manages WebSocket connections
 type WSManager struct {
-	clients        map[string]map[*Client]bool // challenge slug -> set of clients
+	clients        map[string]map[*Client]bool // instance key -> set of clients
 	challenges     *ChallengeManager
 	executor       *Executor
 	voting         *VotingManager
 	rateLimiter    *RateLimiter
 	mu             sync.RWMutex
-	autoStopTimers map[string]*time.Timer // challenge slug -> auto-stop timer
+	autoStopTimers map[string]*time.Timer // instance key -> auto-stop timer
 	autoStopMu     sync.Mutex
+	warmPool       *WarmPool // Idle pre-started instances; nil without warm pools
 }
 
 // NewWSManager creates a new WebSocket manager
@@ -70,12 +75,19 @@ func (wm *WSManager) HandleWebSocket(w http.ResponseWriter, r *http.Request, slu
 		return
 	}
 
+	team, err := requestTeam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
 	// Verify challenge exists
-	challenge, exists := wm.challenges.GetChallenge(slug)
+	challenge, exists := wm.challenges.GetInstance(slug, team, true)
 	if !exists {
 		http.Error(w, "Challenge not found", http.StatusNotFound)
 		return
 	}
+	key := challenge.InstanceKey()
 
 	// Upgrade connection
 	conn, err := upgrader.Upgrade(w, r, nil)
@@ -83,14 +95,23 @@ func (wm *WSManager) HandleWebSocket(w http.ResponseWriter, r *http.Request, slu
 		log.Error("Failed to upgrade connection: %v", err)
 		return
 	}
+	launcherMetrics.websocketAccepted()
 
-	// Create client
+	// Create client, speaking the protocol version it asked for
 	client := &Client{
 		Conn:      conn,
 		IP:        ip,
-		Challenge: slug,
+		Challenge: key,
+		Version:   protocol.ParseSubprotocol(conn.Subprotocol()),
 		Send:      make(chan []byte, 256),
 	}
+	if client.Version >= protocol.V2 {
+		wm.sendToClient(client, WSMessage{Type: protocol.TypeHello, Data: protocol.Hello{
+			Version:  client.Version,
+			Versions: protocol.Versions(),
+			Instance: key,
+		}})
+	}
 
 	// Register client
 	wm.register(client)
@@ -99,12 +120,13 @@ func (wm *WSManager) HandleWebSocket(w http.ResponseWriter, r *http.Request, slu
 	challenge.AddConnectedIP(ip)
 
 	// Cancel auto-stop if any
-	wm.cancelAutoStop(slug)
+	wm.cancelAutoStop(key)
 
 	// Broadcast updated status
-	wm.broadcastStatus(slug)
+	wm.broadcastStatus(key)
+	wm.sendGate(client, challenge)
 
-	log.InfoH3("WebSocket connected: %s (IP: %s)", slug, maskIP(ip))
+	log.InfoH3("WebSocket connected: %s (IP: %s)", key, maskIP(ip))
 
 	// Start client goroutines
 	go wm.writePump(client)
@@ -158,8 +180,9 @@ func (wm *WSManager) unregister(client *Client) {
 	}
 }
 
-// broadcast sends a message to all clients of a challenge
-func (wm *WSManager) broadcast(slug string, message []byte) {
+// broadcast sends a message to all clients of a challenge, encoded once per
+// protocol version
+func (wm *WSManager) broadcast(slug string, msg WSMessage) {
 	wm.mu.RLock()
 	clients, exists := wm.clients[slug]
 	wm.mu.RUnlock()
@@ -168,7 +191,13 @@ func (wm *WSManager) broadcast(slug string, message []byte) {
 		return // No clients to broadcast to
 	}
 
+	encoded := make(map[int][]byte)
 	for client := range clients {
+		message, ok := encoded[client.Version]
+		if !ok {
+			message = client.encode(msg)
+			encoded[client.Version] = message
+		}
 		select {
 		case client.Send <- message:
 		default:
@@ -246,15 +275,25 @@ func (wm *WSManager) handleMessage(client *Client, message []byte) {
 		return
 	}
 
+	// Replies to this request carry its ID
+	client.requestID = msg.ID
+	defer func() { client.requestID = "" }()
+	if msg.Version > protocol.Latest {
+		wm.sendError(client, fmt.Sprintf("Unsupported protocol version %d (latest: %d)", msg.Version, protocol.Latest))
+		return
+	}
+
 	switch msg.Type {
-	case "ping":
+	case protocol.TypePing:
 		wm.handlePing(client)
-	case "start":
-		wm.handleStart(client)
-	case "restart":
-		wm.handleRestartRequest(client)
-	case "vote":
+	case protocol.TypeStart:
+		wm.handleStart(client, msg)
+	case protocol.TypeRestart:
+		wm.handleRestartRequest(client, msg)
+	case protocol.TypeVote:
 		wm.handleVote(client, msg)
+	case protocol.TypeExtend:
+		wm.handleExtendRequest(client)
 	default:
 		wm.sendError(client, fmt.Sprintf("Unknown message type: %s", msg.Type))
 	}
@@ -263,13 +302,13 @@ func (wm *WSManager) handleMessage(client *Client, message []byte) {
 // handlePing responds to ping messages
 func (wm *WSManager) handlePing(client *Client) {
 	response := WSMessage{
-		Type: "pong",
+		Type: protocol.TypePong,
 	}
 	wm.sendToClient(client, response)
 }
 
 // handleStart handles challenge start requests
-func (wm *WSManager) handleStart(client *Client) {
+func (wm *WSManager) handleStart(client *Client, msg WSMessage) {
 	// Check rate limit
 	if allowed, waitTime := wm.rateLimiter.AllowAction(client.IP, "start"); !allowed {
 		wm.sendError(client, fmt.Sprintf("Rate limit exceeded. Try again in %v", waitTime))
@@ -288,26 +327,45 @@ func (wm *WSManager) handleStart(client *Client) {
 		return
 	}
 
+	if err := wm.passGate(client, challenge, msg); err != nil {
+		wm.sendError(client, err.Error())
+		return
+	}
+
+	wm.startChallenge(challenge)
+}
+
+// startChallenge marks a challenge as starting and starts it in the background
+func (wm *WSManager) startChallenge(challenge *ChallengeInfo) {
+	if wm.warmPool.Take(challenge) {
+		challenge.SetStatus(StatusRunning)
+		wm.broadcastInfo(challenge.InstanceKey(), "Challenge started successfully")
+		wm.broadcastStatus(challenge.InstanceKey())
+		go wm.selfTestPorts(challenge)
+		return
+	}
+
 	// Set status to starting
 	challenge.SetStatus(StatusStarting)
-	wm.broadcastStatus(client.Challenge)
+	wm.broadcastStatus(challenge.InstanceKey())
 
 	// Start in background
 	go func() {
 		if err := wm.executor.Start(challenge); err != nil {
 			log.Error("Failed to start challenge %s: %v", challenge.Name, err)
 			challenge.SetStatus(StatusStopped)
-			wm.broadcastError(client.Challenge, "Failed to start challenge. Please check server logs.")
+			wm.broadcastError(challenge.InstanceKey(), "Failed to start challenge. Please check server logs.")
 		} else {
 			challenge.SetStatus(StatusRunning)
-			wm.broadcastInfo(client.Challenge, "Challenge started successfully")
+			wm.broadcastInfo(challenge.InstanceKey(), "Challenge started successfully")
 		}
-		wm.broadcastStatus(client.Challenge)
+		wm.broadcastStatus(challenge.InstanceKey())
+		wm.selfTestPorts(challenge)
 	}()
 }
 
 // handleRestartRequest handles restart vote initiation
-func (wm *WSManager) handleRestartRequest(client *Client) {
+func (wm *WSManager) handleRestartRequest(client *Client, msg WSMessage) {
 	// Check rate limit
 	if allowed, waitTime := wm.rateLimiter.AllowAction(client.IP, "restart"); !allowed {
 		wm.sendError(client, fmt.Sprintf("Rate limit exceeded. Try again in %v", waitTime))
@@ -332,6 +390,11 @@ func (wm *WSManager) handleRestartRequest(client *Client) {
 		return
 	}
 
+	if err := wm.passGate(client, challenge, msg); err != nil {
+		wm.sendError(client, err.Error())
+		return
+	}
+
 	// Start vote
 	if err := wm.voting.StartVote(client.Challenge, func() {
 		// Vote ended (timeout)
@@ -345,12 +408,13 @@ func (wm *WSManager) handleRestartRequest(client *Client) {
 	// Broadcast vote started
 	voteMsg := VoteMessage{
 		InitiatorIP: maskIP(client.IP),
+		Action:      voteActionRestart,
 	}
 	wm.broadcastVoteStarted(client.Challenge, voteMsg)
 
 	// Automatically vote yes for the initiator
 	_ = wm.voting.CastVote(client.Challenge, client.IP, true)
-	wm.checkAndBroadcastVoteUpdate(client.Challenge)
+	wm.checkAndBroadcastVoteUpdate(client.Challenge, voteActionRestart)
 }
 
 // handleVoteTimeout handles vote completion
@@ -369,12 +433,12 @@ func (wm *WSManager) handleVoteTimeout(slug string) {
 	if approved {
 		// Execute restart
 		wm.voting.EndVote(slug, "approved")
-		wm.broadcastVoteEnded(slug, VoteMessage{Result: "approved"})
+		wm.broadcastVoteEnded(slug, VoteMessage{Result: "approved", Action: voteActionRestart})
 		wm.executeRestart(challenge)
 	} else {
 		// Reject
 		wm.voting.EndVote(slug, "rejected")
-		wm.broadcastVoteEnded(slug, VoteMessage{Result: "rejected"})
+		wm.broadcastVoteEnded(slug, VoteMessage{Result: "rejected", Action: voteActionRestart})
 	}
 }
 
@@ -387,40 +451,49 @@ func (wm *WSManager) handleVote(client *Client, msg WSMessage) {
 	}
 
 	// Parse vote value
-	voteValue, ok := msg.Data.(map[string]interface{})["value"].(string)
+	data, _ := msg.Data.(map[string]interface{})
+	voteValue, ok := data["value"].(string)
 	if !ok {
 		wm.sendError(client, "Invalid vote value")
 		return
 	}
 
-	voteYes := voteValue == "yes"
+	voteYes := voteValue == protocol.VoteYes
+
+	// Votes without an action are on the restart vote
+	action, _ := data["action"].(string)
+	if action != voteActionExtend {
+		action = voteActionRestart
+	}
 
 	// Cast vote
-	if err := wm.voting.CastVote(client.Challenge, client.IP, voteYes); err != nil {
+	if err := wm.voting.CastVote(voteKey(client.Challenge, action), client.IP, voteYes); err != nil {
 		log.Error("Failed to cast vote for %s from %s: %v", client.Challenge, maskIP(client.IP), err)
 		wm.sendError(client, "Failed to cast vote")
 		return
 	}
 
 	// Check threshold and broadcast update
-	wm.checkAndBroadcastVoteUpdate(client.Challenge)
+	wm.checkAndBroadcastVoteUpdate(client.Challenge, action)
 }
 
 // checkAndBroadcastVoteUpdate checks vote threshold and broadcasts updates
-func (wm *WSManager) checkAndBroadcastVoteUpdate(slug string) {
+// of the vote on action
+func (wm *WSManager) checkAndBroadcastVoteUpdate(slug, action string) {
 	challenge, exists := wm.challenges.GetChallenge(slug)
 	if !exists {
 		return
 	}
 
 	// Get vote status
-	yesPercent, noPercent, totalVoters, _ := wm.voting.GetVoteStatus(slug, challenge.ConnectedIPs)
+	yesPercent, noPercent, totalVoters, _ := wm.voting.GetVoteStatus(voteKey(slug, action), challenge.ConnectedIPs)
 
 	// Broadcast vote update
 	voteMsg := VoteMessage{
 		YesPercent: yesPercent,
 		NoPercent:  noPercent,
 		TotalUsers: totalVoters,
+		Action:     action,
 	}
 	wm.broadcastVoteUpdate(slug, voteMsg)
 }
@@ -428,19 +501,20 @@ func (wm *WSManager) checkAndBroadcastVoteUpdate(slug string) {
 // executeRestart executes a challenge restart
 func (wm *WSManager) executeRestart(challenge *ChallengeInfo) {
 	challenge.SetStatus(StatusRestarting)
-	wm.broadcastStatus(challenge.Slug)
+	wm.broadcastStatus(challenge.InstanceKey())
 
 	go func() {
 		if err := wm.executor.Restart(challenge); err != nil {
 			log.Error("Failed to restart challenge %s: %v", challenge.Name, err)
 			challenge.SetStatus(StatusStopped)
-			wm.broadcastError(challenge.Slug, "Failed to restart challenge. Please check server logs.")
+			wm.broadcastError(challenge.InstanceKey(), "Failed to restart challenge. Please check server logs.")
 		} else {
 			challenge.SetStatus(StatusRunning)
 			challenge.SetLastRestart(time.Now())
-			wm.broadcastInfo(challenge.Slug, "Challenge restarted successfully")
+			wm.broadcastInfo(challenge.InstanceKey(), "Challenge restarted successfully")
 		}
-		wm.broadcastStatus(challenge.Slug)
+		wm.broadcastStatus(challenge.InstanceKey())
+		wm.selfTestPorts(challenge)
 	}()
 }
 
@@ -457,6 +531,7 @@ func (wm *WSManager) scheduleAutoStop(slug string) {
 	}
 
 	gracePeriod := challenge.CalculateGracePeriod()
+	baseline, byTraffic := wm.trafficBaseline(challenge)
 
 	log.InfoH3("Scheduling auto-stop for %s in %v", challenge.Name, gracePeriod)
 
@@ -469,7 +544,7 @@ func (wm *WSManager) scheduleAutoStop(slug string) {
 
 	// Create new timer
 	timer := time.AfterFunc(gracePeriod, func() {
-		wm.performAutoStop(slug)
+		wm.performAutoStop(slug, baseline, byTraffic)
 	})
 
 	wm.autoStopTimers[slug] = timer
@@ -497,8 +572,10 @@ func (wm *WSManager) cancelAutoStop(slug string) {
 	}
 }
 
-// performAutoStop performs the auto-stop action
-func (wm *WSManager) performAutoStop(slug string) {
+// performAutoStop performs the auto-stop action. With byTraffic, an instance
+// that moved its minimum traffic since baseline is kept running and its
+// auto-stop scheduled again.
+func (wm *WSManager) performAutoStop(slug string, baseline uint64, byTraffic bool) {
 	challenge, exists := wm.challenges.GetChallenge(slug)
 	if !exists {
 		return
@@ -509,6 +586,10 @@ func (wm *WSManager) performAutoStop(slug string) {
 		log.InfoH3("Auto-stop cancelled for %s (users reconnected)", challenge.Name)
 		return
 	}
+	if byTraffic && wm.stillInUse(challenge, baseline) {
+		wm.scheduleAutoStop(slug)
+		return
+	}
 
 	log.InfoH2("Auto-stopping challenge: %s", challenge.Name)
 
@@ -526,6 +607,36 @@ func (wm *WSManager) performAutoStop(slug string) {
 	wm.broadcastStatus(slug)
 }
 
+// selfTestPorts verifies that the ports allocated to a running challenge
+// accept connections and shows the result on the challenge page. Results are
+// dropped if the challenge was stopped or reallocated in the meantime.
+func (wm *WSManager) selfTestPorts(challenge *ChallengeInfo) {
+	ports := challenge.GetAllocatedPorts()
+	if challenge.GetStatus() != StatusRunning || len(ports) == 0 {
+		return
+	}
+
+	challenge.SetPortChecks(PendingChecks(ports))
+	wm.broadcastStatus(challenge.InstanceKey())
+
+	prober := NewPortProber()
+	checks := prober.Check(context.Background(), ports)
+
+	if challenge.GetStatus() != StatusRunning || !slices.Equal(challenge.GetAllocatedPorts(), ports) {
+		return
+	}
+	challenge.SetPortChecks(checks)
+	wm.broadcastStatus(challenge.InstanceKey())
+
+	for _, check := range checks {
+		if check.State != PortUnreachable {
+			continue
+		}
+		log.Error("Port self-test failed for %s: port %d/%s (%s) not reachable from %s: %s", challenge.Name, check.Port, check.Protocol, check.Mapping, prober.Host, check.Error)
+		wm.broadcastError(challenge.InstanceKey(), fmt.Sprintf("Port %d/%s is not reachable. Organizers: check firewall/NAT rules.", check.Port, check.Protocol))
+	}
+}
+
 // Broadcast helper methods
 
 func (wm *WSManager) broadcastStatus(slug string) {
@@ -538,66 +649,84 @@ func (wm *WSManager) broadcastStatus(slug string) {
 		Status:         string(challenge.GetStatus()),
 		ConnectedUsers: challenge.GetConnectedUsers(),
 		AllocatedPorts: challenge.GetAllocatedPorts(),
+		PortChecks:     challenge.GetPortChecks(),
+		TTL:            challenge.GetTTL(),
+		ProxyPath:      proxyPath(challenge.Slug),
 	}
 
 	msg := WSMessage{
-		Type: "status",
+		Type: protocol.TypeStatus,
 		Data: statusMsg,
 	}
+	wm.broadcast(slug, msg)
+}
+
+func (wm *WSManager) broadcastStats(slug string, usage *ResourceUsage) {
+	msg := WSMessage{
+		Type: protocol.TypeStats,
+		Data: usage,
+	}
+	wm.broadcast(slug, msg)
+}
 
-	data, _ := json.Marshal(msg)
-	wm.broadcast(slug, data)
+func (wm *WSManager) broadcastTTL(slug string, ttl *InstanceTTL) {
+	if ttl == nil {
+		return
+	}
+	msg := WSMessage{
+		Type: protocol.TypeTTL,
+		Data: ttl,
+	}
+	wm.broadcast(slug, msg)
 }
 
 func (wm *WSManager) broadcastError(slug, message string) {
 	msg := WSMessage{
-		Type:    "error",
+		Type:    protocol.TypeError,
 		Message: message,
 	}
-	data, _ := json.Marshal(msg)
-	wm.broadcast(slug, data)
+	wm.broadcast(slug, msg)
 }
 
 func (wm *WSManager) broadcastInfo(slug, message string) {
 	msg := WSMessage{
-		Type:    "info",
+		Type:    protocol.TypeInfo,
 		Message: message,
 	}
-	data, _ := json.Marshal(msg)
-	wm.broadcast(slug, data)
+	wm.broadcast(slug, msg)
 }
 
 func (wm *WSManager) broadcastVoteStarted(slug string, voteMsg VoteMessage) {
 	msg := WSMessage{
-		Type: "vote_started",
+		Type: protocol.TypeVoteStarted,
 		Data: voteMsg,
 	}
-	data, _ := json.Marshal(msg)
-	wm.broadcast(slug, data)
+	wm.broadcast(slug, msg)
 }
 
 func (wm *WSManager) broadcastVoteUpdate(slug string, voteMsg VoteMessage) {
 	msg := WSMessage{
-		Type: "vote_update",
+		Type: protocol.TypeVoteUpdate,
 		Data: voteMsg,
 	}
-	data, _ := json.Marshal(msg)
-	wm.broadcast(slug, data)
+	wm.broadcast(slug, msg)
 }
 
 func (wm *WSManager) broadcastVoteEnded(slug string, voteMsg VoteMessage) {
 	msg := WSMessage{
-		Type: "vote_ended",
+		Type: protocol.TypeVoteEnded,
 		Data: voteMsg,
 	}
-	data, _ := json.Marshal(msg)
-	wm.broadcast(slug, data)
+	wm.broadcast(slug, msg)
 }
 
+// sendToClient sends a message to one client, as a reply to the request
+// being handled if any. Only called from the read pump of the client, or
+// before it starts.
 func (wm *WSManager) sendToClient(client *Client, msg WSMessage) {
-	data, _ := json.Marshal(msg)
+	msg.ID = client.requestID
 	select {
-	case client.Send <- data:
+	case client.Send <- client.encode(msg):
 	default:
 		// Client's send buffer is full, skip
 	}
@@ -605,7 +734,7 @@ func (wm *WSManager) sendToClient(client *Client, msg WSMessage) {
 
 func (wm *WSManager) sendError(client *Client, message string) {
 	msg := WSMessage{
-		Type:    "error",
+		Type:    protocol.TypeError,
 		Message: message,
 	}
 	wm.sendToClient(client, msg)
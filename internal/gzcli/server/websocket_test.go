@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/pkg/launcher/client"
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+// newWSTestServer serves the WebSocket API of a running challenge
+func newWSTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cm := NewChallengeManager()
+	cm.challenges["ctf_web_chall"] = &ChallengeInfo{
+		Slug:      "ctf_web_chall",
+		Name:      "Chall",
+		Dashboard: &Dashboard{Type: "compose"},
+		Status:    StatusRunning,
+	}
+	wm := NewWSManager(cm, nil, NewVotingManager(), NewRateLimiter())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wm.HandleWebSocket(w, r, "ctf_web_chall")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// nextEvent returns the next event of a client
+func nextEvent(t *testing.T, c *client.Client) client.Event {
+	t.Helper()
+	select {
+	case event, ok := <-c.Events():
+		if !ok {
+			t.Fatalf("connection closed: %v", c.Err())
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event within 5s")
+	}
+	return client.Event{}
+}
+
+func TestWebSocket_V2(t *testing.T) {
+	server := newWSTestServer(t)
+	c, err := client.Dial(context.Background(), server.URL+"/ctf_web_chall", client.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if c.Version() != protocol.V2 {
+		t.Fatalf("Version() = %d, want %d", c.Version(), protocol.V2)
+	}
+	hello := nextEvent(t, c)
+	if hello.Type != protocol.TypeHello || hello.Hello == nil || hello.Hello.Instance != "ctf_web_chall" {
+		t.Fatalf("first event = %+v, want a hello", hello)
+	}
+	status := nextEvent(t, c)
+	if status.Type != protocol.TypeStatus || status.Version != protocol.V2 || status.Status.Status != protocol.StatusRunning || status.Status.ConnectedUsers != 1 {
+		t.Fatalf("second event = %+v, want the running status", status)
+	}
+
+	id, err := c.Start("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := nextEvent(t, c)
+	if reply.Type != protocol.TypeError || reply.ID != id || id == "" {
+		t.Errorf("reply to start %s = %+v, want an error with its ID", id, reply)
+	}
+
+	id, _ = c.Ping()
+	if pong := nextEvent(t, c); pong.Type != protocol.TypePong || pong.ID != id {
+		t.Errorf("reply to ping %s = %+v, want a pong with its ID", id, pong)
+	}
+}
+
+func TestWebSocket_V1(t *testing.T) {
+	server := newWSTestServer(t)
+	c, err := client.Dial(context.Background(), server.URL+"/ctf_web_chall", client.Options{Version: protocol.V1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if c.Version() != protocol.V1 {
+		t.Fatalf("Version() = %d, want %d", c.Version(), protocol.V1)
+	}
+	status := nextEvent(t, c)
+	if status.Type != protocol.TypeStatus || status.Version != 0 {
+		t.Fatalf("first event = %+v, want an unversioned status", status)
+	}
+	if id, _ := c.Ping(); id != "" {
+		t.Errorf("Ping() ID = %q, want none with v1", id)
+	}
+	if pong := nextEvent(t, c); pong.Type != protocol.TypePong || pong.ID != "" {
+		t.Errorf("reply to ping = %+v, want a pong without ID", pong)
+	}
+}
+
+func TestBroadcast_EncodesPerVersion(t *testing.T) {
+	wm := &WSManager{clients: make(map[string]map[*Client]bool)}
+	v1 := &Client{Challenge: "chall", Send: make(chan []byte, 1)}
+	v2 := &Client{Challenge: "chall", Version: protocol.V2, Send: make(chan []byte, 1)}
+	wm.register(v1)
+	wm.register(v2)
+
+	wm.broadcast("chall", WSMessage{Type: protocol.TypeInfo, ID: "7", Message: "hi"})
+
+	if got := string(<-v1.Send); got != `{"type":"info","message":"hi"}` {
+		t.Errorf("v1 message = %s, want the unversioned format", got)
+	}
+	var msg WSMessage
+	if err := json.Unmarshal(<-v2.Send, &msg); err != nil || msg.Version != protocol.V2 || msg.Message != "hi" {
+		t.Errorf("v2 message = %+v (%v), want version %d", msg, err, protocol.V2)
+	}
+}
+
+func TestHandleMessage_UnsupportedVersion(t *testing.T) {
+	wm := &WSManager{}
+	c := &Client{Version: protocol.V2, Send: make(chan []byte, 1)}
+	wm.handleMessage(c, []byte(`{"type":"ping","v":99,"id":"a"}`))
+
+	var msg WSMessage
+	if err := json.Unmarshal(<-c.Send, &msg); err != nil || msg.Type != protocol.TypeError || msg.ID != "a" {
+		t.Errorf("reply = %+v (%v), want an error with the request ID", msg, err)
+	}
+	if c.requestID != "" {
+		t.Errorf("requestID = %q after the request, want it cleared", c.requestID)
+	}
+}
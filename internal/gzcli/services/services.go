@@ -0,0 +1,270 @@
+// Package services manages the shared services of an event: infrastructure
+// such as a common bot checker or database that several challenges rely on.
+// Each service is a Docker Compose project in events/<event>/services/<name>/.
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// Dir is the directory of an event holding its shared services
+const Dir = "services"
+
+// composeFiles are the compose definitions looked up in a service directory,
+// in order of preference
+var composeFiles = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+var (
+	nameRegex    = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	projectRegex = regexp.MustCompile(`[^a-z0-9_-]+`)
+)
+
+// Service is a shared service of an event
+type Service struct {
+	Name        string // Directory name, referenced from challenge.yaml
+	Event       string
+	Dir         string
+	ComposeFile string
+}
+
+// Project returns the Compose project name of the service. Challenges reach
+// it on the project's default network, <project>_default.
+func (s Service) Project() string {
+	return "gzcli-svc-" + projectRegex.ReplaceAllString(strings.ToLower(s.Event), "-") + "-" + s.Name
+}
+
+// Status is the state of a service's containers
+type Status struct {
+	Running int // Running containers
+	Total   int // Containers of the project, running or not
+}
+
+// String describes the status as "running", "stopped" or "degraded (n/m running)"
+func (s Status) String() string {
+	switch {
+	case s.Total == 0 || s.Running == 0:
+		return "stopped"
+	case s.Running == s.Total:
+		return "running"
+	default:
+		return fmt.Sprintf("degraded (%d/%d running)", s.Running, s.Total)
+	}
+}
+
+// Discover lists the shared services of the event at eventPath, sorted by
+// name. Directories without a compose definition are skipped; an event
+// without a services directory has none.
+func Discover(eventName, eventPath string) ([]Service, error) {
+	root := filepath.Join(eventPath, Dir)
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+
+	var services []Service
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		composeFile := findComposeFile(dir)
+		if composeFile == "" {
+			continue
+		}
+		if !nameRegex.MatchString(entry.Name()) {
+			return nil, fmt.Errorf("invalid service name %q: use lowercase letters, digits, '-' and '_'", entry.Name())
+		}
+		services = append(services, Service{
+			Name:        entry.Name(),
+			Event:       eventName,
+			Dir:         dir,
+			ComposeFile: composeFile,
+		})
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+// DiscoverEvent lists the shared services of an event by name
+func DiscoverEvent(eventName string) ([]Service, error) {
+	eventPath, err := config.GetEventPath(eventName)
+	if err != nil {
+		return nil, err
+	}
+	return Discover(eventName, eventPath)
+}
+
+func findComposeFile(dir string) string {
+	for _, name := range composeFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Select returns the services with the given names, or all services when no
+// names are given
+func Select(services []Service, names []string) ([]Service, error) {
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	byName := make(map[string]Service, len(services))
+	for _, s := range services {
+		byName[s.Name] = s
+	}
+	selected := make([]Service, 0, len(names))
+	for _, name := range names {
+		s, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown service: %s", name)
+		}
+		selected = append(selected, s)
+	}
+	return selected, nil
+}
+
+func compose(ctx context.Context, s Service, args ...string) *exec.Cmd {
+	args = append([]string{"compose", "-f", s.ComposeFile, "-p", s.Project()}, args...)
+	//nolint:gosec // G204: Docker commands with service config are intentional
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Dir = s.Dir
+	return cmd
+}
+
+// Up starts a service and waits for Compose to report it started
+func Up(ctx context.Context, s Service) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	if output, err := compose(ctx, s, "up", "-d", "--build").CombinedOutput(); err != nil {
+		return fmt.Errorf("docker compose up failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// Down stops a service and removes its containers. Volumes are kept so
+// shared databases survive a restart.
+func Down(ctx context.Context, s Service) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	if output, err := compose(ctx, s, "down").CombinedOutput(); err != nil {
+		return fmt.Errorf("docker compose down failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// GetStatus reports the containers of a service
+func GetStatus(ctx context.Context, s Service) (Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	output, err := compose(ctx, s, "ps", "--all", "--format", "json").Output()
+	if err != nil {
+		return Status{}, fmt.Errorf("docker compose ps failed: %w", err)
+	}
+	return parseStatus(output), nil
+}
+
+// parseStatus counts the containers in "docker compose ps --format json"
+// output, which is a JSON array or one object per line depending on the
+// Compose version
+func parseStatus(output []byte) Status {
+	var containers []map[string]interface{}
+	trimmed := bytes.TrimSpace(output)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		_ = json.Unmarshal(trimmed, &containers)
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		for decoder.More() {
+			var container map[string]interface{}
+			if err := decoder.Decode(&container); err != nil {
+				break
+			}
+			containers = append(containers, container)
+		}
+	}
+
+	var status Status
+	for _, container := range containers {
+		status.Total++
+		if state, ok := container["State"].(string); ok && strings.EqualFold(state, "running") {
+			status.Running++
+		}
+	}
+	return status
+}
+
+// Dependents returns the names of the challenges referencing a service
+func Dependents(challenges []config.ChallengeYaml, name string) []string {
+	var dependents []string
+	for _, c := range challenges {
+		for _, ref := range c.Services {
+			if ref == name {
+				dependents = append(dependents, c.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// MissingReference is a challenge referencing a service the event does not define
+type MissingReference struct {
+	Challenge string
+	Service   string
+}
+
+// MissingReferences returns the service references of challenges that do
+// not match a service
+func MissingReferences(challenges []config.ChallengeYaml, services []Service) []MissingReference {
+	known := make(map[string]bool, len(services))
+	for _, s := range services {
+		known[s.Name] = true
+	}
+
+	var missing []MissingReference
+	for _, c := range challenges {
+		for _, ref := range c.Services {
+			if !known[ref] {
+				missing = append(missing, MissingReference{Challenge: c.Name, Service: ref})
+			}
+		}
+	}
+	return missing
+}
+
+// ValidateReferences checks that every service referenced by a challenge is
+// defined by the event
+func ValidateReferences(challenges []config.ChallengeYaml, services []Service) error {
+	missing := MissingReferences(challenges, services)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(missing))
+	for _, m := range missing {
+		lines = append(lines, fmt.Sprintf("%s: service %q not found in %s/", m.Challenge, m.Service, Dir))
+	}
+	return fmt.Errorf("unknown shared services:\n  - %s", strings.Join(lines, "\n  - "))
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("services: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	eventPath := t.TempDir()
+	writeFile(t, filepath.Join(eventPath, Dir, "postgres", "compose.yaml"))
+	writeFile(t, filepath.Join(eventPath, Dir, "bot", "docker-compose.yml"))
+	writeFile(t, filepath.Join(eventPath, Dir, "notes", "README.md"))
+
+	svcs, err := Discover("CTF 2024", eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != 2 || svcs[0].Name != "bot" || svcs[1].Name != "postgres" {
+		t.Fatalf("Discover() = %+v, want bot and postgres", svcs)
+	}
+	if filepath.Base(svcs[0].ComposeFile) != "docker-compose.yml" {
+		t.Errorf("ComposeFile = %s", svcs[0].ComposeFile)
+	}
+	if got := svcs[0].Project(); got != "gzcli-svc-ctf-2024-bot" {
+		t.Errorf("Project() = %s", got)
+	}
+}
+
+func TestDiscover_NoServicesDir(t *testing.T) {
+	svcs, err := Discover("ctf", t.TempDir())
+	if err != nil || len(svcs) != 0 {
+		t.Errorf("Discover() = %v, %v; want no services", svcs, err)
+	}
+}
+
+func TestDiscover_InvalidName(t *testing.T) {
+	eventPath := t.TempDir()
+	writeFile(t, filepath.Join(eventPath, Dir, "Bad Name", "compose.yml"))
+
+	if _, err := Discover("ctf", eventPath); err == nil {
+		t.Error("Discover() should reject service names unusable as Compose projects")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	svcs := []Service{{Name: "bot"}, {Name: "db"}}
+
+	if got, _ := Select(svcs, nil); len(got) != 2 {
+		t.Errorf("Select() without names = %d services, want all", len(got))
+	}
+	if got, err := Select(svcs, []string{"db"}); err != nil || len(got) != 1 || got[0].Name != "db" {
+		t.Errorf("Select(db) = %v, %v", got, err)
+	}
+	if _, err := Select(svcs, []string{"redis"}); err == nil {
+		t.Error("Select() should reject unknown services")
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"empty", "", "stopped"},
+		{"array", `[{"State":"running"},{"State":"running"}]`, "running"},
+		{"lines", "{\"State\":\"running\"}\n{\"State\":\"exited\"}\n", "degraded (1/2 running)"},
+		{"exited", `[{"State":"exited"}]`, "stopped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStatus([]byte(tt.output)).String(); got != tt.want {
+				t.Errorf("parseStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	challenges := []config.ChallengeYaml{
+		{Name: "xss", Services: []string{"bot"}},
+		{Name: "sqli", Services: []string{"db", "bot"}},
+		{Name: "misc"},
+	}
+	svcs := []Service{{Name: "bot"}}
+
+	if got := Dependents(challenges, "bot"); strings.Join(got, ",") != "sqli,xss" {
+		t.Errorf("Dependents(bot) = %v", got)
+	}
+
+	missing := MissingReferences(challenges, svcs)
+	if len(missing) != 1 || missing[0] != (MissingReference{Challenge: "sqli", Service: "db"}) {
+		t.Errorf("MissingReferences() = %+v", missing)
+	}
+
+	err := ValidateReferences(challenges, svcs)
+	if err == nil || !strings.Contains(err.Error(), `sqli: service "db"`) {
+		t.Errorf("ValidateReferences() = %v", err)
+	}
+	if err := ValidateReferences(challenges, append(svcs, Service{Name: "db"})); err != nil {
+		t.Errorf("ValidateReferences() = %v, want nil", err)
+	}
+}
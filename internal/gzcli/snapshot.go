@@ -0,0 +1,369 @@
+package gzcli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// SnapshotVersion is the format version of event snapshots
+const SnapshotVersion = 1
+
+// Entries of a snapshot archive; event files are stored under snapshotEventDir
+const (
+	snapshotManifestFile = "snapshot.json"
+	snapshotGameFile     = "game.json"
+	snapshotEventDir     = "event/"
+)
+
+// Snapshot is the portable state of an event: the configuration of its game
+// and challenges on GZCTF, its local configuration files and the IDs of the
+// remote challenges its challenge folders are synced to
+type Snapshot struct {
+	Manifest SnapshotManifest
+	Game     *gzapi.GameBundle
+	Files    map[string][]byte // Event files by slash-separated path relative to the event directory
+}
+
+// SnapshotManifest describes the content of a snapshot
+type SnapshotManifest struct {
+	Version     int                             `json:"version"`
+	ExportedAt  time.Time                       `json:"exportedAt"`
+	Source      string                          `json:"source,omitempty"` // URL of the exporting server
+	Event       string                          `json:"event"`
+	GameID      int                             `json:"gameId"` // Game on the exporting server
+	Files       []string                        `json:"files"`
+	Mappings    []watchertypes.ChallengeMapping `json:"mappings"` // Challenge IDs on the exporting server
+	Attachments []SnapshotAttachment            `json:"attachments,omitempty"`
+}
+
+// SnapshotAttachment lists the attachment of a challenge of a snapshot.
+// Local attachment files are not part of the snapshot: the importing server
+// must already have them, or the next sync of the challenge uploads them.
+type SnapshotAttachment struct {
+	Challenge string `json:"challenge"`
+	Type      string `json:"type"`
+	Url       string `json:"url"`
+	Hash      string `json:"hash,omitempty"` // Asset hash of a local attachment
+}
+
+// ExportSnapshot returns the snapshot of the event: the bundle of its game,
+// its .gzevent and challenge files and the challenge mappings the watcher
+// recorded for the game
+func (gz *GZ) ExportSnapshot() (*Snapshot, error) {
+	eventPath, err := config.GetEventPath(gz.eventName)
+	if err != nil {
+		return nil, err
+	}
+	game, err := gz.EventGame()
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := game.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := snapshotFiles(gz.eventName, eventPath)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := snapshotMappings(WatcherDatabase(), gz.eventName, game.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Manifest: SnapshotManifest{
+			Version:    SnapshotVersion,
+			ExportedAt: bundle.ExportedAt,
+			Source:     bundle.Source,
+			Event:      gz.eventName,
+			GameID:     game.Id,
+			Mappings:   mappings,
+		},
+		Game:  bundle,
+		Files: files,
+	}
+	for name := range files {
+		snapshot.Manifest.Files = append(snapshot.Manifest.Files, name)
+	}
+	sort.Strings(snapshot.Manifest.Files)
+	for _, c := range bundle.Challenges {
+		if c.Attachment == nil {
+			continue
+		}
+		snapshot.Manifest.Attachments = append(snapshot.Manifest.Attachments, SnapshotAttachment{
+			Challenge: c.Title,
+			Type:      c.Attachment.Type,
+			Url:       c.Attachment.Url,
+			Hash:      c.Attachment.AssetHash(),
+		})
+	}
+	return snapshot, nil
+}
+
+// snapshotFiles reads the .gzevent and challenge files of the event
+func snapshotFiles(eventName, eventPath string) (map[string][]byte, error) {
+	paths := []string{filepath.Join(eventPath, config.GZEVENT_FILE)}
+	entries, err := registry.Discover(eventName, eventPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		paths = append(paths, entry.File)
+	}
+
+	files := make(map[string][]byte, len(paths))
+	for _, p := range paths {
+		rel, err := filepath.Rel(eventPath, p)
+		if err != nil {
+			return nil, err
+		}
+		//nolint:gosec // G304: Files of the event directory
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		files[filepath.ToSlash(rel)] = data
+	}
+	return files, nil
+}
+
+// snapshotMappings returns the challenge mappings of the event recorded in
+// the watcher database at dbPath for its game
+func snapshotMappings(dbPath, eventName string, gameID int) ([]watchertypes.ChallengeMapping, error) {
+	db := database.New(dbPath, true)
+	if err := db.Init(); err != nil {
+		return nil, fmt.Errorf("failed to open watcher database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	recorded, err := db.ListChallengeMappings(eventName)
+	if err != nil {
+		return nil, err
+	}
+	mappings := []watchertypes.ChallengeMapping{}
+	for _, m := range recorded {
+		if m.GameID != 0 && m.GameID != gameID {
+			continue
+		}
+		mappings = append(mappings, watchertypes.ChallengeMapping{
+			Folder:      filepath.ToSlash(m.FolderPath),
+			ChallengeID: m.ChallengeID,
+			Title:       m.ChallengeTitle,
+		})
+	}
+	return mappings, nil
+}
+
+// WriteSnapshot writes a snapshot to a gzipped tar archive
+func WriteSnapshot(file string, snapshot *Snapshot) (err error) {
+	//nolint:gosec // G304: Archive chosen by the user
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) // Snapshots hold flags
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	zw := gzip.NewWriter(f)
+	tw := tar.NewWriter(zw)
+	add := func(name string, data []byte) error {
+		header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: snapshot.Manifest.ExportedAt}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		return add(name, append(data, '\n'))
+	}
+
+	if err := addJSON(snapshotManifestFile, snapshot.Manifest); err != nil {
+		return err
+	}
+	if err := addJSON(snapshotGameFile, snapshot.Game); err != nil {
+		return err
+	}
+	for _, name := range snapshot.Manifest.Files {
+		if err := add(snapshotEventDir+name, snapshot.Files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot
+func ReadSnapshot(file string) (*Snapshot, error) {
+	//nolint:gosec // G304: Archive chosen by the user
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a snapshot archive: %w", err)
+	}
+	tr := tar.NewReader(zr)
+
+	snapshot := &Snapshot{Files: map[string][]byte{}}
+	var hasManifest bool
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		switch name := header.Name; {
+		case name == snapshotManifestFile:
+			if err := json.Unmarshal(data, &snapshot.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			hasManifest = true
+		case name == snapshotGameFile:
+			if err := json.Unmarshal(data, &snapshot.Game); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		case strings.HasPrefix(name, snapshotEventDir):
+			rel := strings.TrimPrefix(name, snapshotEventDir)
+			if !filepath.IsLocal(filepath.FromSlash(rel)) {
+				return nil, fmt.Errorf("snapshot file %q escapes the event directory", rel)
+			}
+			snapshot.Files[path.Clean(rel)] = data
+		}
+	}
+
+	if !hasManifest || snapshot.Game == nil {
+		return nil, fmt.Errorf("snapshot archive lacks %s or %s", snapshotManifestFile, snapshotGameFile)
+	}
+	if snapshot.Manifest.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Manifest.Version, SnapshotVersion)
+	}
+	return snapshot, nil
+}
+
+// RestoreFiles writes the event files of the snapshot into the directory of
+// eventName, keeping files that already exist, and returns the paths written
+// and kept
+func (s *Snapshot) RestoreFiles(eventName string) (written, kept []string, err error) {
+	if eventName == "" || strings.ContainsAny(eventName, `/\`) || !filepath.IsLocal(eventName) {
+		return nil, nil, fmt.Errorf("invalid event name %q", eventName)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+	eventPath := filepath.Join(cwd, config.EVENTS_DIR, eventName)
+
+	names := make([]string, 0, len(s.Files))
+	for name := range s.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		target := filepath.Join(eventPath, filepath.FromSlash(name))
+		if _, err := os.Stat(target); err == nil {
+			kept = append(kept, name)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return written, kept, err
+		}
+		if err := os.WriteFile(target, s.Files[name], 0600); err != nil {
+			return written, kept, err
+		}
+		written = append(written, name)
+	}
+	return written, kept, nil
+}
+
+// SnapshotImportResult reports what ImportSnapshot created
+type SnapshotImportResult struct {
+	*gzapi.ImportResult
+	Mappings []watchertypes.ChallengeMapping `json:"mappings"`           // Challenge IDs on the importing server
+	Unmapped []string                        `json:"unmapped,omitempty"` // Folders whose challenge was not imported
+}
+
+// ImportSnapshot creates the game of the snapshot on the server and returns
+// the challenge mappings of the event rewritten to the IDs of the new
+// challenges. The cached game of the event is dropped so the next command
+// finds the new game by title.
+func (gz *GZ) ImportSnapshot(snapshot *Snapshot) (*SnapshotImportResult, error) {
+	imported, importErr := gz.ImportGame(snapshot.Game, "")
+	if imported == nil {
+		return nil, importErr
+	}
+	deleteCacheWrapper(fmt.Sprintf("config-%s", gz.eventName))
+
+	challenges, err := imported.Game.GetChallenges()
+	if err != nil {
+		return nil, errors.Join(importErr, fmt.Errorf("failed to list imported challenges: %w", err))
+	}
+	result := &SnapshotImportResult{ImportResult: imported}
+	result.Mappings, result.Unmapped = remapSnapshot(snapshot.Manifest.Mappings, challenges)
+	return result, importErr
+}
+
+// remapSnapshot points the mappings of a snapshot at the challenges of the
+// same title on the importing server, and returns the folders left unmapped
+func remapSnapshot(mappings []watchertypes.ChallengeMapping, challenges []gzapi.Challenge) ([]watchertypes.ChallengeMapping, []string) {
+	ids := make(map[string]int, len(challenges))
+	for _, c := range challenges {
+		if _, ok := ids[c.Title]; !ok {
+			ids[c.Title] = c.Id
+		}
+	}
+
+	remapped := make([]watchertypes.ChallengeMapping, 0, len(mappings))
+	var unmapped []string
+	for _, m := range mappings {
+		id, ok := ids[m.Title]
+		if !ok {
+			unmapped = append(unmapped, m.Folder)
+			continue
+		}
+		m.ChallengeID = id
+		m.Folder = filepath.FromSlash(m.Folder)
+		remapped = append(remapped, m)
+	}
+	return remapped, unmapped
+}
@@ -0,0 +1,87 @@
+package gzcli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestSnapshot_WriteReadRestore(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "ctf-snapshot.tar.gz")
+	snapshot := &Snapshot{
+		Manifest: SnapshotManifest{
+			Version:  SnapshotVersion,
+			Event:    "ctf",
+			GameID:   7,
+			Files:    []string{".gzevent", "Web/login/challenge.yml"},
+			Mappings: []watchertypes.ChallengeMapping{{Folder: "Web/login", ChallengeID: 12, Title: "Login"}},
+		},
+		Game: &gzapi.GameBundle{Version: gzapi.GameBundleVersion, Game: gzapi.Game{Title: "CTF"}},
+		Files: map[string][]byte{
+			".gzevent":                []byte("title: CTF\n"),
+			"Web/login/challenge.yml": []byte("name: Login\n"),
+		},
+	}
+
+	if err := WriteSnapshot(file, snapshot); err != nil {
+		t.Fatalf("WriteSnapshot() failed: %v", err)
+	}
+	read, err := ReadSnapshot(file)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() failed: %v", err)
+	}
+	if read.Manifest.GameID != 7 || len(read.Manifest.Mappings) != 1 || read.Game.Game.Title != "CTF" {
+		t.Errorf("ReadSnapshot() = %+v, want the written manifest and game", read.Manifest)
+	}
+	if string(read.Files["Web/login/challenge.yml"]) != "name: Login\n" {
+		t.Errorf("ReadSnapshot() files = %v, want the challenge file", read.Files)
+	}
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalDir) })
+
+	existing := filepath.Join(dir, "events", "restored", ".gzevent")
+	if err := os.MkdirAll(filepath.Dir(existing), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("title: Local\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	written, kept, err := read.RestoreFiles("restored")
+	if err != nil {
+		t.Fatalf("RestoreFiles() failed: %v", err)
+	}
+	if len(written) != 1 || len(kept) != 1 || kept[0] != ".gzevent" {
+		t.Errorf("RestoreFiles() = %v, %v, want the challenge written and .gzevent kept", written, kept)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "title: Local\n" {
+		t.Errorf(".gzevent = %q, want the existing file kept", data)
+	}
+	if _, _, err := read.RestoreFiles("../escape"); err == nil {
+		t.Error("RestoreFiles() outside the events directory succeeded, want an error")
+	}
+}
+
+func TestRemapSnapshot(t *testing.T) {
+	mappings := []watchertypes.ChallengeMapping{
+		{Folder: "Web/login", ChallengeID: 12, Title: "Login"},
+		{Folder: "Pwn/heap", ChallengeID: 13, Title: "Heap"},
+	}
+	challenges := []gzapi.Challenge{{Id: 101, Title: "Login"}, {Id: 102, Title: "Other"}}
+
+	remapped, unmapped := remapSnapshot(mappings, challenges)
+	if len(remapped) != 1 || remapped[0].ChallengeID != 101 || remapped[0].Title != "Login" {
+		t.Errorf("remapped = %+v, want Web/login mapped to 101", remapped)
+	}
+	if len(unmapped) != 1 || unmapped[0] != "Pwn/heap" {
+		t.Errorf("unmapped = %v, want Pwn/heap", unmapped)
+	}
+}
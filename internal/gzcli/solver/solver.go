@@ -0,0 +1,155 @@
+// Package solver scaffolds challenge solvers and defines how they are run.
+//
+// Every solver lives in the challenge's solver/ directory and follows the same
+// interface regardless of language: the target is passed through the HOST and
+// PORT environment variables, FLAG_FORMAT holds a regular expression matching
+// the flag, and the solver prints the recovered flag to stdout and exits
+// non-zero on failure.
+package solver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/template"
+)
+
+// Supported solver languages
+const (
+	LangPython = "python"
+	LangGo     = "go"
+	LangBash   = "bash"
+)
+
+// Environment variables passed to solvers
+const (
+	EnvHost       = "HOST"
+	EnvPort       = "PORT"
+	EnvFlagFormat = "FLAG_FORMAT"
+)
+
+// Dir is the solver directory inside a challenge
+const Dir = "solver"
+
+// DefaultFlagFormat matches flags of the common prefix{...} form
+const DefaultFlagFormat = `[A-Za-z0-9_]+\{[^}]*\}`
+
+type language struct {
+	entrypoint string
+	command    []string
+}
+
+var languages = map[string]language{
+	LangPython: {entrypoint: "solve.py", command: []string{"python3", "solve.py"}},
+	LangGo:     {entrypoint: "main.go", command: []string{"go", "run", "."}},
+	LangBash:   {entrypoint: "solve.sh", command: []string{"bash", "solve.sh"}},
+}
+
+// Languages returns the supported solver languages
+func Languages() []string {
+	langs := make([]string, 0, len(languages))
+	for lang := range languages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsLanguage reports whether lang is a supported solver language
+func IsLanguage(lang string) bool {
+	_, ok := languages[lang]
+	return ok
+}
+
+// TemplateData is passed to the solver templates
+type TemplateData struct {
+	Name       string
+	FlagFormat string
+}
+
+// FlagFormat derives a FLAG_FORMAT regular expression from a challenge's
+// flags or dynamic flag template, falling back to DefaultFlagFormat
+func FlagFormat(flags []string, flagTemplate string) string {
+	candidates := append([]string{flagTemplate}, flags...)
+	for _, flag := range candidates {
+		prefix, _, ok := strings.Cut(flag, "{")
+		if ok && prefix != "" && strings.HasSuffix(flag, "}") {
+			return regexp.QuoteMeta(prefix) + `\{[^}]*\}`
+		}
+	}
+	return DefaultFlagFormat
+}
+
+// Detect returns the language of the solver in challengeDir, if any
+func Detect(challengeDir string) (string, bool) {
+	for _, lang := range Languages() {
+		if _, err := os.Stat(filepath.Join(challengeDir, Dir, languages[lang].entrypoint)); err == nil {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Scaffold writes a solver skeleton for lang into the challenge's solver
+// directory. It does nothing if a solver already exists.
+func Scaffold(challengeDir, lang string, data TemplateData) error {
+	if !IsLanguage(lang) {
+		return fmt.Errorf("unsupported solver language %q (supported: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	if existing, ok := Detect(challengeDir); ok {
+		if existing != lang {
+			return fmt.Errorf("%s solver already exists in %s", existing, filepath.Join(challengeDir, Dir))
+		}
+		return nil
+	}
+	if data.FlagFormat == "" {
+		data.FlagFormat = DefaultFlagFormat
+	}
+
+	if errs := template.TemplateFSToDestination("templates/solvers/"+lang, data, filepath.Join(challengeDir, Dir)); len(errs) > 0 {
+		return fmt.Errorf("failed to scaffold %s solver: %v", lang, errs)
+	}
+	return nil
+}
+
+// Target is the deployed challenge a solver runs against
+type Target struct {
+	Host       string
+	Port       int
+	FlagFormat string
+}
+
+// Environ returns the solver environment variables for the target
+func (t Target) Environ() []string {
+	flagFormat := t.FlagFormat
+	if flagFormat == "" {
+		flagFormat = DefaultFlagFormat
+	}
+	return []string{
+		EnvHost + "=" + t.Host,
+		EnvPort + "=" + strconv.Itoa(t.Port),
+		EnvFlagFormat + "=" + flagFormat,
+	}
+}
+
+// Command builds the command running the solver in challengeDir against target
+func Command(ctx context.Context, challengeDir string, target Target) (*exec.Cmd, error) {
+	lang, ok := Detect(challengeDir)
+	if !ok {
+		return nil, fmt.Errorf("no solver found in %s", filepath.Join(challengeDir, Dir))
+	}
+	argv := languages[lang].command
+
+	//nolint:gosec // G204: Solver commands are fixed per language
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = filepath.Join(challengeDir, Dir)
+	cmd.Env = append(os.Environ(), target.Environ()...)
+	return cmd, nil
+}
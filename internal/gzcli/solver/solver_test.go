@@ -0,0 +1,120 @@
+package solver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScaffold_Languages(t *testing.T) {
+	tests := map[string][]string{
+		LangPython: {"solve.py", "requirements.txt"},
+		LangGo:     {"main.go", "go.mod"},
+		LangBash:   {"solve.sh"},
+	}
+	for lang, files := range tests {
+		t.Run(lang, func(t *testing.T) {
+			dir := t.TempDir()
+			data := TemplateData{Name: "baby-pwn", FlagFormat: FlagFormat([]string{"CTF{test}"}, "")}
+			if err := Scaffold(dir, lang, data); err != nil {
+				t.Fatalf("Scaffold() failed: %v", err)
+			}
+
+			for _, f := range files {
+				if _, err := os.Stat(filepath.Join(dir, Dir, f)); err != nil {
+					t.Errorf("expected %s to be scaffolded: %v", f, err)
+				}
+			}
+
+			entrypoint, err := os.ReadFile(filepath.Join(dir, Dir, languages[lang].entrypoint))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"baby-pwn", EnvHost, EnvPort, EnvFlagFormat, `CTF\{[^}]*\}`} {
+				if !strings.Contains(string(entrypoint), want) {
+					t.Errorf("entrypoint missing %q:\n%s", want, entrypoint)
+				}
+			}
+
+			if got, ok := Detect(dir); !ok || got != lang {
+				t.Errorf("Detect() = %q, %v, want %q", got, ok, lang)
+			}
+		})
+	}
+}
+
+func TestScaffold_KeepsExistingSolver(t *testing.T) {
+	dir := t.TempDir()
+	solve := filepath.Join(dir, Dir, "solve.py")
+	if err := os.MkdirAll(filepath.Dir(solve), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(solve, []byte("print('mine')"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Scaffold(dir, LangPython, TemplateData{Name: "x"}); err != nil {
+		t.Fatalf("Scaffold() failed: %v", err)
+	}
+	if content, _ := os.ReadFile(solve); string(content) != "print('mine')" {
+		t.Errorf("existing solver was overwritten: %s", content)
+	}
+
+	if err := Scaffold(dir, LangGo, TemplateData{Name: "x"}); err == nil {
+		t.Error("expected error when a solver in another language exists")
+	}
+}
+
+func TestScaffold_UnsupportedLanguage(t *testing.T) {
+	if err := Scaffold(t.TempDir(), "ruby", TemplateData{}); err == nil {
+		t.Error("expected error for unsupported language")
+	}
+}
+
+func TestFlagFormat(t *testing.T) {
+	tests := []struct {
+		flags    []string
+		template string
+		match    string
+	}{
+		{flags: []string{"flag{static}"}, match: "flag{abc_123}"},
+		{template: "FLAG{dyn_[TEAM_HASH]}", match: "FLAG{deadbeef}"},
+		{flags: []string{"no-braces"}, match: "anything{goes}"},
+	}
+	for _, tt := range tests {
+		format := FlagFormat(tt.flags, tt.template)
+		if !regexp.MustCompile(format).MatchString(tt.match) {
+			t.Errorf("FlagFormat(%v, %q) = %q, does not match %q", tt.flags, tt.template, format, tt.match)
+		}
+	}
+	if format := FlagFormat([]string{"a.b{x}"}, ""); regexp.MustCompile(format).MatchString("axb{x}") {
+		t.Errorf("FlagFormat() = %q, prefix must be matched literally", format)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Command(context.Background(), dir, Target{}); err == nil {
+		t.Error("expected error without a solver")
+	}
+
+	if err := Scaffold(dir, LangBash, TemplateData{Name: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := Command(context.Background(), dir, Target{Host: "10.0.0.1", Port: 31337})
+	if err != nil {
+		t.Fatalf("Command() failed: %v", err)
+	}
+	if cmd.Dir != filepath.Join(dir, Dir) || !slices.Equal(cmd.Args, []string{"bash", "solve.sh"}) {
+		t.Errorf("unexpected command %v in %s", cmd.Args, cmd.Dir)
+	}
+	for _, want := range []string{"HOST=10.0.0.1", "PORT=31337", EnvFlagFormat + "=" + DefaultFlagFormat} {
+		if !slices.Contains(cmd.Env, want) {
+			t.Errorf("environment missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Verification outcomes
+const (
+	VerifyPassed = "passed" // Solver printed a flag of the challenge
+	VerifyFailed = "failed" // Solver failed, timed out or printed no flag of the challenge
+	VerifyError  = "error"  // Solver could not be run
+)
+
+// DefaultVerifyTimeout bounds a verification run
+const DefaultVerifyTimeout = 2 * time.Minute
+
+// maxOutputSize bounds how much solver output is kept per run
+const maxOutputSize = 64 << 10
+
+// VerifyResult is the outcome of running a solver against a deployed challenge
+type VerifyResult struct {
+	Status   string // passed, failed, error
+	Flag     string // Flag found in the solver's stdout
+	ExitCode int
+	Output   string // Combined stdout and stderr, truncated to maxOutputSize
+	Duration time.Duration
+	Err      error // Why the run did not pass
+}
+
+// Verify runs the solver in challengeDir against target and checks that it
+// prints one of flags, or a flag matching target.FlagFormat when the
+// challenge has no static flags (e.g. dynamic flags). A zero timeout uses
+// DefaultVerifyTimeout.
+func Verify(ctx context.Context, challengeDir string, target Target, flags []string, timeout time.Duration) VerifyResult {
+	result := VerifyResult{Status: VerifyError, ExitCode: -1}
+	if timeout <= 0 {
+		timeout = DefaultVerifyTimeout
+	}
+
+	flagFormat := target.FlagFormat
+	if flagFormat == "" {
+		flagFormat = DefaultFlagFormat
+	}
+	format, err := regexp.Compile(flagFormat)
+	if err != nil {
+		result.Err = fmt.Errorf("invalid flag format: %w", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd, err := Command(ctx, challengeDir, target)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	var stdout, output bytes.Buffer
+	combined := &lockedWriter{w: &output}
+	cmd.Stdout = io.MultiWriter(&stdout, combined)
+	cmd.Stderr = combined
+	// Processes the solver started may outlive it and hold the output open
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	result.Duration = time.Since(start)
+	result.Output = truncate(output.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case ctx.Err() != nil:
+		result.Status = VerifyFailed
+		result.Err = fmt.Errorf("solver timed out after %s", timeout)
+		return result
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		result.Status = VerifyFailed
+		result.ExitCode = exitErr.ExitCode()
+		result.Err = fmt.Errorf("solver exited with status %d", result.ExitCode)
+		return result
+	default:
+		result.Err = fmt.Errorf("failed to run solver: %w", err)
+		return result
+	}
+
+	result.Status = VerifyFailed
+	if len(flags) == 0 {
+		result.Flag = format.FindString(stdout.String())
+	}
+	for _, flag := range flags {
+		if flag != "" && strings.Contains(stdout.String(), flag) {
+			result.Flag = flag
+			break
+		}
+	}
+	if result.Flag == "" {
+		result.Err = fmt.Errorf("solver printed no flag of the challenge")
+		return result
+	}
+	result.Status = VerifyPassed
+	return result
+}
+
+// lockedWriter serializes the writes of the stdout and stderr copies of a
+// solver, which run concurrently, into one buffer
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// truncate keeps the tail of solver output, where the flag and errors are printed
+func truncate(s string) string {
+	if len(s) <= maxOutputSize {
+		return s
+	}
+	return "[truncated]\n" + s[len(s)-maxOutputSize:]
+}
@@ -0,0 +1,92 @@
+package solver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeBashSolver writes a bash solver running script into a new challenge
+// directory
+func writeBashSolver(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, Dir), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, Dir, "solve.sh"), []byte(script), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		flags      []string
+		flagFormat string
+		timeout    time.Duration
+		wantStatus string
+		wantFlag   string
+	}{
+		{
+			name:       "static flag",
+			script:     `echo "[+] got it"; echo "CTF{$HOST:$PORT}"`,
+			flags:      []string{"CTF{other}", "CTF{127.0.0.1:1337}"},
+			wantStatus: VerifyPassed,
+			wantFlag:   "CTF{127.0.0.1:1337}",
+		},
+		{
+			name:       "wrong static flag",
+			script:     `echo "CTF{wrong}"`,
+			flags:      []string{"CTF{right}"},
+			wantStatus: VerifyFailed,
+		},
+		{
+			name:       "dynamic flag",
+			script:     `echo "flag: CTF{3f2a}"`,
+			flagFormat: `CTF\{[^}]*\}`,
+			wantStatus: VerifyPassed,
+			wantFlag:   "CTF{3f2a}",
+		},
+		{
+			name:       "solver failure",
+			script:     `echo "CTF{right}"; exit 3`,
+			flags:      []string{"CTF{right}"},
+			wantStatus: VerifyFailed,
+		},
+		{
+			name:       "timeout",
+			script:     `sleep 5`,
+			flags:      []string{"CTF{right}"},
+			timeout:    100 * time.Millisecond,
+			wantStatus: VerifyFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeBashSolver(t, tt.script)
+			target := Target{Host: "127.0.0.1", Port: 1337, FlagFormat: tt.flagFormat}
+
+			result := Verify(context.Background(), dir, target, tt.flags, tt.timeout)
+			if result.Status != tt.wantStatus || result.Flag != tt.wantFlag {
+				t.Errorf("Verify() = %s with flag %q (%v), want %s with flag %q\noutput: %s",
+					result.Status, result.Flag, result.Err, tt.wantStatus, tt.wantFlag, result.Output)
+			}
+			if (result.Status == VerifyPassed) != (result.Err == nil) {
+				t.Errorf("Verify() error %v with status %s", result.Err, result.Status)
+			}
+		})
+	}
+}
+
+func TestVerify_NoSolver(t *testing.T) {
+	result := Verify(context.Background(), t.TempDir(), Target{}, nil, 0)
+	if result.Status != VerifyError || result.Err == nil {
+		t.Errorf("Verify() = %s (%v), want an error without a solver", result.Status, result.Err)
+	}
+}
@@ -0,0 +1,54 @@
+package gzcli
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/stats"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Stats computes the solve analytics of the event's game from its scoreboard.
+// With withSubmissions, every submission of the game is fetched as well to
+// count attempts per challenge; failing to fetch them only drops those counts.
+// With tags, only the challenges whose local challenge.yml carries one of them
+// are reported; teams and rankings still cover the whole game.
+func (gz *GZ) Stats(withSubmissions bool, tags []string) (*stats.Report, error) {
+	conf, err := config.GetConfigWithEvent(nil, gz.eventName, GetCache, setCache, deleteCacheWrapper, nil)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+	game := challenge.FindCurrentGame(games, conf.Event.Title, gz.api)
+	if game == nil {
+		return nil, fmt.Errorf("game %q of event %s not found on the server", conf.Event.Title, gz.eventName)
+	}
+
+	scoreboard, err := game.GetScoreboard()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scoreboard: %w", err)
+	}
+	if len(tags) > 0 {
+		titles, err := taggedChallengeTitles(gz.eventName, tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tagged challenges: %w", err)
+		}
+		scoreboard = stats.OnlyChallenges(scoreboard, titles)
+	}
+
+	var submissions []gzapi.Submission
+	if withSubmissions {
+		submissions, err = game.GetSubmissions(gzapi.SubmissionFilter{})
+		if err != nil {
+			log.Error("Failed to get submissions, reporting solves only: %v", err)
+			submissions = nil
+		}
+	}
+
+	return stats.Build(game.Title, scoreboard, submissions), nil
+}
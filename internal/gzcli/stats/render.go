@@ -0,0 +1,172 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// rankingTopTeams is the number of teams shown per ranking checkpoint
+const rankingTopTeams = 5
+
+// table is one section of a report as rows of cells
+type table struct {
+	title  string
+	header []string
+	rows   [][]string
+}
+
+// Render writes the report in the given format
+func (r *Report) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatText, "":
+		return r.renderText(w)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatCSV:
+		return r.renderCSV(w)
+	case FormatMarkdown:
+		return r.renderMarkdown(w)
+	default:
+		return fmt.Errorf("unsupported format %q (expected text, json, csv or markdown)", format)
+	}
+}
+
+func (r *Report) tables() []table {
+	teams := table{title: "Ranking", header: []string{"Rank", "Team", "Division", "Score", "Solves"}}
+	for _, t := range r.Teams {
+		teams.rows = append(teams.rows, []string{strconv.Itoa(t.Rank), t.Name, t.Division, strconv.Itoa(t.Score), strconv.Itoa(t.Solves)})
+	}
+
+	challenges := table{title: "Challenges", header: []string{"Challenge", "Category", "Score", "Solves", "Submissions", "Accuracy", "First Blood", "First Blood Time"}}
+	for _, c := range r.Challenges {
+		accuracy := "-"
+		if a := c.Accuracy(); a >= 0 {
+			accuracy = formatPercent(a)
+		}
+		blood, bloodTime := "-", "-"
+		if c.FirstBlood != nil {
+			blood = c.FirstBlood.Team
+			bloodTime = c.FirstBlood.Time.Local().Format(time.DateTime)
+		}
+		challenges.rows = append(challenges.rows, []string{
+			c.Title, c.Category, strconv.Itoa(c.Score), strconv.Itoa(c.Solves),
+			strconv.Itoa(c.Submissions), accuracy, blood, bloodTime,
+		})
+	}
+
+	categories := table{title: "Categories (hardest first)", header: []string{"Category", "Challenges", "Unsolved", "Solves", "Solve Rate"}}
+	for _, c := range r.Categories {
+		categories.rows = append(categories.rows, []string{
+			c.Category, strconv.Itoa(c.Challenges), strconv.Itoa(c.Unsolved), strconv.Itoa(c.Solves), formatPercent(c.SolveRate),
+		})
+	}
+
+	rankings := table{title: "Ranking over time", header: []string{"Time"}}
+	for i := range rankingTopTeams {
+		rankings.header = append(rankings.header, "#"+strconv.Itoa(i+1))
+	}
+	for _, cp := range r.Rankings {
+		row := []string{cp.Time.Local().Format(time.DateTime)}
+		for i := range rankingTopTeams {
+			cell := ""
+			if i < len(cp.Teams) {
+				cell = fmt.Sprintf("%s (%d)", cp.Teams[i].Name, cp.Teams[i].Score)
+			}
+			row = append(row, cell)
+		}
+		rankings.rows = append(rankings.rows, row)
+	}
+
+	return []table{teams, challenges, categories, rankings}
+}
+
+func (r *Report) renderText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📊 %s (updated %s)\n", r.Game, r.UpdatedAt.Local().Format(time.DateTime))
+	b.WriteString("==========================================\n")
+
+	for _, t := range r.tables() {
+		fmt.Fprintf(&b, "\n%s\n", t.title)
+		if len(t.rows) == 0 {
+			b.WriteString("  (none)\n")
+			continue
+		}
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  %s\n", strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintf(tw, "  %s\n", strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderCSV writes each table under a row holding its title, separated by
+// empty lines
+func (r *Report) renderCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	for i, t := range r.tables() {
+		if i > 0 {
+			cw.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		_ = cw.Write([]string{t.title})
+		_ = cw.Write(t.header)
+		for _, row := range t.rows {
+			_ = cw.Write(row)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *Report) renderMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n_Updated %s_\n", r.Game, r.UpdatedAt.Local().Format(time.DateTime))
+
+	for _, t := range r.tables() {
+		fmt.Fprintf(&b, "\n## %s\n\n", t.title)
+		if len(t.rows) == 0 {
+			b.WriteString("_None_\n")
+			continue
+		}
+		writeMarkdownRow(&b, t.header)
+		separator := make([]string, len(t.header))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		writeMarkdownRow(&b, separator)
+		for _, row := range t.rows {
+			writeMarkdownRow(&b, row)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		fmt.Fprintf(b, " %s |", strings.ReplaceAll(cell, "|", `\|`))
+	}
+	b.WriteString("\n")
+}
+
+func formatPercent(f float64) string {
+	return fmt.Sprintf("%.1f%%", f*100)
+}
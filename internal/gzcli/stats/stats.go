@@ -0,0 +1,266 @@
+// Package stats computes solve and submission analytics of a game from its
+// scoreboard and renders them as text, JSON, CSV or Markdown
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// Supported output formats
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// rankingCheckpoints is the number of points in time the ranking is taken at
+const rankingCheckpoints = 6
+
+// FirstBlood is the first solve of a challenge
+type FirstBlood struct {
+	Team string    `json:"team"`
+	Time time.Time `json:"time"`
+}
+
+// ChallengeStats are the solves and submissions of one challenge
+type ChallengeStats struct {
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Category    string      `json:"category"`
+	Score       int         `json:"score"`
+	Solves      int         `json:"solves"`
+	Submissions int         `json:"submissions"` // Flags submitted, 0 without submission data
+	Accepted    int         `json:"accepted"`    // Submissions judged correct
+	FirstBlood  *FirstBlood `json:"firstBlood,omitempty"`
+}
+
+// Accuracy is the share of submissions that were accepted, or -1 without
+// submission data
+func (c ChallengeStats) Accuracy() float64 {
+	if c.Submissions == 0 {
+		return -1
+	}
+	return float64(c.Accepted) / float64(c.Submissions)
+}
+
+// CategoryStats summarize how hard the challenges of a category were
+type CategoryStats struct {
+	Category   string  `json:"category"`
+	Challenges int     `json:"challenges"`
+	Unsolved   int     `json:"unsolved"`
+	Solves     int     `json:"solves"`
+	SolveRate  float64 `json:"solveRate"` // Solves over challenges times teams
+}
+
+// TeamStats is a team of the final ranking
+type TeamStats struct {
+	Rank     int    `json:"rank"`
+	Name     string `json:"name"`
+	Division string `json:"division,omitempty"`
+	Score    int    `json:"score"`
+	Solves   int    `json:"solves"`
+}
+
+// RankedTeam is the score of a team at a checkpoint
+type RankedTeam struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// Checkpoint is the ranking of the top teams at a point in time
+type Checkpoint struct {
+	Time  time.Time    `json:"time"`
+	Teams []RankedTeam `json:"teams"` // Best first
+}
+
+// Report holds the analytics of a game
+type Report struct {
+	Game       string           `json:"game"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+	Teams      []TeamStats      `json:"teams"`
+	Challenges []ChallengeStats `json:"challenges"`
+	Categories []CategoryStats  `json:"categories"`
+	Rankings   []Checkpoint     `json:"rankings"`
+}
+
+// Build computes the report of a game from its scoreboard and, when not nil,
+// its submissions
+func Build(game string, scoreboard *gzapi.Scoreboard, submissions []gzapi.Submission) *Report {
+	report := &Report{
+		Game:       game,
+		UpdatedAt:  scoreboard.UpdateTime.Time,
+		Teams:      []TeamStats{},
+		Challenges: []ChallengeStats{},
+		Categories: []CategoryStats{},
+		Rankings:   []Checkpoint{},
+	}
+
+	for _, item := range scoreboard.Items {
+		report.Teams = append(report.Teams, TeamStats{
+			Rank:     item.Rank,
+			Name:     item.Name,
+			Division: item.Division,
+			Score:    item.Score,
+			Solves:   item.SolvedCount,
+		})
+	}
+	sort.SliceStable(report.Teams, func(i, j int) bool { return report.Teams[i].Rank < report.Teams[j].Rank })
+
+	submitted := make(map[string]int)
+	accepted := make(map[string]int)
+	for _, s := range submissions {
+		submitted[s.Challenge]++
+		if s.Status == gzapi.AnswerAccepted {
+			accepted[s.Challenge]++
+		}
+	}
+
+	byCategory := make(map[string]*CategoryStats)
+	for category, challenges := range scoreboard.Challenges {
+		cs := &CategoryStats{Category: category}
+		byCategory[category] = cs
+		for _, c := range challenges {
+			stats := ChallengeStats{
+				ID:          c.Id,
+				Title:       c.Title,
+				Category:    category,
+				Score:       c.Score,
+				Solves:      c.Solved,
+				Submissions: submitted[c.Title],
+				Accepted:    accepted[c.Title],
+				FirstBlood:  firstBlood(c.Bloods),
+			}
+			report.Challenges = append(report.Challenges, stats)
+
+			cs.Challenges++
+			cs.Solves += c.Solved
+			if c.Solved == 0 {
+				cs.Unsolved++
+			}
+		}
+	}
+	sort.SliceStable(report.Challenges, func(i, j int) bool {
+		a, b := report.Challenges[i], report.Challenges[j]
+		if a.Solves != b.Solves {
+			return a.Solves > b.Solves
+		}
+		return a.Title < b.Title
+	})
+
+	for _, cs := range byCategory {
+		if teams := len(scoreboard.Items); teams > 0 && cs.Challenges > 0 {
+			cs.SolveRate = float64(cs.Solves) / float64(cs.Challenges*teams)
+		}
+		report.Categories = append(report.Categories, *cs)
+	}
+	// Hardest categories first
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if a.SolveRate != b.SolveRate {
+			return a.SolveRate < b.SolveRate
+		}
+		return a.Category < b.Category
+	})
+
+	report.Rankings = rankings(scoreboard.TimeLines)
+	return report
+}
+
+// OnlyChallenges returns a copy of scoreboard keeping only the challenges
+// whose title is in titles
+func OnlyChallenges(scoreboard *gzapi.Scoreboard, titles map[string]bool) *gzapi.Scoreboard {
+	filtered := *scoreboard
+	filtered.Challenges = make(map[string][]gzapi.ScoreboardChallenge)
+	filtered.ChallengeCount = 0
+	for category, challenges := range scoreboard.Challenges {
+		for _, c := range challenges {
+			if titles[c.Title] {
+				filtered.Challenges[category] = append(filtered.Challenges[category], c)
+				filtered.ChallengeCount++
+			}
+		}
+	}
+	return &filtered
+}
+
+// firstBlood returns the earliest of the bloods of a challenge
+func firstBlood(bloods []gzapi.Blood) *FirstBlood {
+	var first *FirstBlood
+	for _, b := range bloods {
+		if first == nil || b.SubmitTime.Before(first.Time) {
+			first = &FirstBlood{Team: b.Name, Time: b.SubmitTime.Time}
+		}
+	}
+	return first
+}
+
+// rankings ranks the teams of the score timelines of every division at
+// evenly spaced checkpoints between the first and the last score change
+func rankings(timeLines map[string][]gzapi.TeamTimeLine) []Checkpoint {
+	// A team may appear in the timeline of its division and of the whole game
+	teams := make(map[string][]gzapi.TimeLinePoint)
+	var start, end time.Time
+	for _, lines := range timeLines {
+		for _, line := range lines {
+			if _, seen := teams[line.Name]; seen {
+				continue
+			}
+			points := append([]gzapi.TimeLinePoint(nil), line.Items...)
+			sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time.Time) })
+			teams[line.Name] = points
+			if len(points) == 0 {
+				continue
+			}
+			if start.IsZero() || points[0].Time.Before(start) {
+				start = points[0].Time.Time
+			}
+			if last := points[len(points)-1].Time.Time; last.After(end) {
+				end = last
+			}
+		}
+	}
+	if len(teams) == 0 || start.IsZero() {
+		return []Checkpoint{}
+	}
+
+	checkpoints := make([]Checkpoint, 0, rankingCheckpoints)
+	step := end.Sub(start) / (rankingCheckpoints - 1)
+	for i := range rankingCheckpoints {
+		at := start.Add(time.Duration(i) * step)
+		if i == rankingCheckpoints-1 {
+			at = end
+		}
+		checkpoint := Checkpoint{Time: at}
+		for name, points := range teams {
+			checkpoint.Teams = append(checkpoint.Teams, RankedTeam{Name: name, Score: scoreAt(points, at)})
+		}
+		sort.Slice(checkpoint.Teams, func(a, b int) bool {
+			ta, tb := checkpoint.Teams[a], checkpoint.Teams[b]
+			if ta.Score != tb.Score {
+				return ta.Score > tb.Score
+			}
+			return ta.Name < tb.Name
+		})
+		checkpoints = append(checkpoints, checkpoint)
+		if step == 0 {
+			break
+		}
+	}
+	return checkpoints
+}
+
+// scoreAt returns the score of the last point at or before t
+func scoreAt(points []gzapi.TimeLinePoint, t time.Time) int {
+	score := 0
+	for _, p := range points {
+		if p.Time.After(t) {
+			break
+		}
+		score = p.Score
+	}
+	return score
+}
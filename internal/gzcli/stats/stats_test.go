@@ -0,0 +1,134 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+func at(minutes int) gzapi.CustomTime {
+	return gzapi.CustomTime{Time: time.Date(2024, 1, 1, 0, minutes, 0, 0, time.UTC)}
+}
+
+func sampleScoreboard() *gzapi.Scoreboard {
+	return &gzapi.Scoreboard{
+		UpdateTime: at(100),
+		Challenges: map[string][]gzapi.ScoreboardChallenge{
+			"Web": {
+				{Id: 1, Title: "Login", Score: 100, Solved: 2, Bloods: []gzapi.Blood{
+					{Name: "beta", SubmitTime: at(20)},
+					{Name: "alpha", SubmitTime: at(10)},
+				}},
+				{Id: 2, Title: "Notes", Score: 500, Solved: 0},
+			},
+			"Crypto": {
+				{Id: 3, Title: "RSA", Score: 200, Solved: 2, Bloods: []gzapi.Blood{{Name: "alpha", SubmitTime: at(50)}}},
+			},
+		},
+		Items: []gzapi.ScoreboardItem{
+			{Name: "beta", Rank: 2, Score: 100, SolvedCount: 1},
+			{Name: "alpha", Rank: 1, Score: 300, SolvedCount: 2},
+		},
+		TimeLines: map[string][]gzapi.TeamTimeLine{
+			"all": {
+				{Name: "alpha", Items: []gzapi.TimeLinePoint{{Time: at(10), Score: 100}, {Time: at(50), Score: 300}}},
+				{Name: "beta", Items: []gzapi.TimeLinePoint{{Time: at(0), Score: 0}, {Time: at(20), Score: 100}}},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	submissions := []gzapi.Submission{
+		{Challenge: "Login", Status: gzapi.AnswerAccepted},
+		{Challenge: "Login", Status: gzapi.AnswerAccepted},
+		{Challenge: "Login", Status: gzapi.AnswerWrongAnswer},
+		{Challenge: "Login", Status: gzapi.AnswerWrongAnswer},
+	}
+	report := Build("Game", sampleScoreboard(), submissions)
+
+	if len(report.Teams) != 2 || report.Teams[0].Name != "alpha" {
+		t.Errorf("Teams = %+v, want alpha first", report.Teams)
+	}
+
+	login := report.Challenges[0]
+	if login.Title != "Login" || login.FirstBlood == nil || login.FirstBlood.Team != "alpha" {
+		t.Errorf("most solved challenge = %+v, want Login with alpha's first blood", login)
+	}
+	if got := login.Accuracy(); got != 0.5 {
+		t.Errorf("Login accuracy = %v, want 0.5", got)
+	}
+	if got := report.Challenges[2].Accuracy(); got != -1 {
+		t.Errorf("accuracy without submissions = %v, want -1", got)
+	}
+
+	// Web: 2 solves out of 2 challenges x 2 teams; Crypto: 2 out of 1 x 2
+	if report.Categories[0].Category != "Web" || report.Categories[0].SolveRate != 0.5 || report.Categories[0].Unsolved != 1 {
+		t.Errorf("hardest category = %+v, want Web at 50%%", report.Categories[0])
+	}
+
+	if len(report.Rankings) != rankingCheckpoints {
+		t.Fatalf("got %d ranking checkpoints, want %d", len(report.Rankings), rankingCheckpoints)
+	}
+	first, last := report.Rankings[0], report.Rankings[len(report.Rankings)-1]
+	if !first.Time.Equal(at(0).Time) || !last.Time.Equal(at(50).Time) {
+		t.Errorf("checkpoints span %v to %v", first.Time, last.Time)
+	}
+	if last.Teams[0].Name != "alpha" || last.Teams[0].Score != 300 {
+		t.Errorf("final ranking = %+v", last.Teams)
+	}
+}
+
+func TestRender(t *testing.T) {
+	report := Build("Game", sampleScoreboard(), nil)
+
+	for _, format := range []string{FormatText, FormatJSON, FormatCSV, FormatMarkdown} {
+		var buf bytes.Buffer
+		if err := report.Render(&buf, format); err != nil {
+			t.Fatalf("Render(%s) error = %v", format, err)
+		}
+		if !strings.Contains(buf.String(), "Login") {
+			t.Errorf("Render(%s) is missing challenges:\n%s", format, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	r := csv.NewReader(&buf)
+	r.FieldsPerRecord = -1
+	if _, err := r.ReadAll(); err != nil {
+		t.Errorf("CSV output does not parse: %v", err)
+	}
+
+	if err := report.Render(&buf, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestOnlyChallenges(t *testing.T) {
+	scoreboard := sampleScoreboard()
+	report := Build("Game", OnlyChallenges(scoreboard, map[string]bool{"Login": true, "RSA": true}), nil)
+
+	if len(report.Challenges) != 2 || len(report.Teams) != 2 {
+		t.Fatalf("report = %d challenges and %d teams, want 2 and 2", len(report.Challenges), len(report.Teams))
+	}
+	for _, c := range report.Challenges {
+		if c.Title == "Notes" {
+			t.Error("untagged challenge Notes was reported")
+		}
+	}
+	for _, cs := range report.Categories {
+		if cs.Category == "Web" && (cs.Challenges != 1 || cs.Unsolved != 0) {
+			t.Errorf("Web category = %+v, want only Login", cs)
+		}
+	}
+	if len(scoreboard.Challenges["Web"]) != 2 {
+		t.Error("OnlyChallenges modified the scoreboard")
+	}
+}
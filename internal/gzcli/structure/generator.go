@@ -1,7 +1,8 @@
 // Package structure provides utilities for generating challenge directory structures.
 //
 // This package helps maintain consistent directory layouts across challenges by
-// copying template structures from a .structure directory to challenge directories.
+// copying template structures from a .structure directory to challenge directories,
+// and scaffolds a solver skeleton for challenges that select a solver language.
 //
 // Example usage:
 //
@@ -10,15 +11,25 @@
 //	    &Challenge{cwd: "./challenges/crypto/rsa"},
 //	}
 //
-//	if err := structure.GenerateStructure(challenges); err != nil {
+//	report, err := structure.Generate(challenges)
+//	if err != nil {
 //	    log.Fatalf("Failed to generate structures: %v", err)
 //	}
+//	_ = report.Render(os.Stdout)
 package structure
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
 	"github.com/dimasma0305/gzcli/internal/log"
 	"github.com/dimasma0305/gzcli/internal/template"
 )
@@ -28,39 +39,195 @@ type ChallengeData interface {
 	GetCwd() string
 }
 
-// GenerateStructure generates challenge structure from template
+// SolverChallenge is implemented by challenges that select a solver language
+type SolverChallenge interface {
+	ChallengeData
+	GetSolver() string
+	SolverTemplateData() solver.TemplateData
+}
+
+// maxParallelGenerations bounds how many challenges are generated at once
+const maxParallelGenerations = 8
+
+// Outcomes of generating the structure of a challenge
+const (
+	StatusGenerated = "generated"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
+// Result is the outcome of generating the structure of one challenge
+type Result struct {
+	Cwd    string
+	Status string
+	Reason string  // Why the challenge was skipped
+	Errs   []error // Why the challenge failed
+}
+
+// Report holds the result of every challenge, in the order they were given
+type Report struct {
+	Results []Result
+}
+
+// Count returns the number of challenges with the given status
+func (r *Report) Count(status string) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Render writes the results as a table followed by the totals
+func (r *Report) Render(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STATUS\tCHALLENGE\tDETAILS")
+	for _, res := range r.Results {
+		details := res.Reason
+		if len(res.Errs) > 0 {
+			msgs := make([]string, len(res.Errs))
+			for i, err := range res.Errs {
+				msgs[i] = err.Error()
+			}
+			details = strings.Join(msgs, "; ")
+		}
+		if details == "" {
+			details = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", res.Status, displayPath(res.Cwd), details)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d generated, %d skipped, %d failed\n",
+		r.Count(StatusGenerated), r.Count(StatusSkipped), r.Count(StatusFailed))
+	return err
+}
+
+// displayPath shortens path relative to the working directory when possible
+func displayPath(path string) string {
+	if path == "" {
+		return "-"
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// GenerateStructure generates challenge structure from template. Failures of
+// single challenges are logged; use Generate to get them.
 func GenerateStructure(challenges []ChallengeData) error {
+	_, err := Generate(challenges)
+	return err
+}
+
+// Generate copies the .structure template to every challenge, several at a
+// time. A failing challenge does not stop the others: its errors are
+// collected in the report. Challenges where every file already exists are
+// skipped. The error is only set when nothing could be generated at all.
+func Generate(challenges []ChallengeData) (*Report, error) {
 	// Validate input
 	if len(challenges) == 0 {
-		return fmt.Errorf("no challenges provided")
+		return nil, fmt.Errorf("no challenges provided")
 	}
 
 	// Read the .structure file
-	_, err := os.ReadDir(".structure")
+	if _, err := os.ReadDir(".structure"); err != nil {
+		return nil, fmt.Errorf(".structure dir doesn't exist: %w", err)
+	}
+	templateFiles, err := countFiles(".structure")
 	if err != nil {
-		return fmt.Errorf(".structure dir doesn't exist: %w", err)
+		return nil, fmt.Errorf("failed to read .structure dir: %w", err)
 	}
 
-	// Iterate over each challenge in the challenges slice
-	for _, challenge := range challenges {
-		if challenge == nil {
-			log.Error("Nil challenge encountered, skipping")
-			continue
-		}
+	report := &Report{Results: make([]Result, len(challenges))}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(maxParallelGenerations, len(challenges)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				report.Results[i] = generateOne(challenges[i], templateFiles)
+			}
+		}()
+	}
+	for i := range challenges {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
 
-		cwd := challenge.GetCwd()
-		if cwd == "" {
-			log.Error("Challenge has empty working directory, skipping")
-			continue
+	return report, nil
+}
+
+// generateOne generates the structure of a single challenge
+func generateOne(challenge ChallengeData, templateFiles int) Result {
+	if challenge == nil {
+		log.Error("Nil challenge encountered, skipping")
+		return Result{Status: StatusSkipped, Reason: "nil challenge"}
+	}
+
+	cwd := challenge.GetCwd()
+	if cwd == "" {
+		log.Error("Challenge has empty working directory, skipping")
+		return Result{Status: StatusSkipped, Reason: "empty working directory"}
+	}
+
+	res := Result{Cwd: cwd}
+	wrote := false
+	if sc, ok := challenge.(SolverChallenge); ok && sc.GetSolver() != "" {
+		_, existed := solver.Detect(cwd)
+		if err := solver.Scaffold(cwd, sc.GetSolver(), sc.SolverTemplateData()); err != nil {
+			log.Error("Failed to scaffold solver in %s: %v", cwd, err)
+			res.Errs = append(res.Errs, err)
+		} else if !existed {
+			wrote = true
 		}
+	}
 
-		// Construct the challenge path using the challenge data
-		if err := template.TemplateToDestination(".structure", challenge, cwd); err != nil {
-			log.Error("Failed to copy .structure to %s: %v", cwd, err)
+	// Files already present are kept; any other error fails the challenge
+	existing := 0
+	for _, err := range template.TemplateToDestination(".structure", challenge, cwd) {
+		if errors.Is(err, os.ErrExist) {
+			existing++
 			continue
 		}
+		res.Errs = append(res.Errs, err)
+	}
+
+	switch {
+	case len(res.Errs) > 0:
+		log.Error("Failed to copy .structure to %s: %v", cwd, res.Errs)
+		res.Status = StatusFailed
+	case !wrote && existing == templateFiles:
+		res.Status = StatusSkipped
+		res.Reason = "already up to date"
+	default:
 		log.Info("Successfully copied .structure to %s", cwd)
+		res.Status = StatusGenerated
 	}
+	return res
+}
 
-	return nil
+// countFiles returns the number of files under dir
+func countFiles(dir string) (int, error) {
+	n := 0
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			n++
+		}
+		return nil
+	})
+	return n, err
 }
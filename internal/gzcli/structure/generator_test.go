@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
 )
 
 // mockChallengeData implements ChallengeData for testing
@@ -17,6 +20,20 @@ func (m *mockChallengeData) GetCwd() string {
 	return m.cwd
 }
 
+// mockSolverChallenge implements SolverChallenge for testing
+type mockSolverChallenge struct {
+	mockChallengeData
+	solver string
+}
+
+func (m *mockSolverChallenge) GetSolver() string {
+	return m.solver
+}
+
+func (m *mockSolverChallenge) SolverTemplateData() solver.TemplateData {
+	return solver.TemplateData{Name: "test"}
+}
+
 // TestGenerateStructure_Success tests successful structure generation
 func TestGenerateStructure_Success(t *testing.T) {
 	// Create temporary directories
@@ -440,3 +457,105 @@ func TestGenerateStructure_PermissionHandling(t *testing.T) {
 		t.Errorf("GenerateStructure() with permission error failed: %v", err)
 	}
 }
+
+// TestGenerateStructure_ScaffoldsSolver tests solver scaffolding for challenges selecting a language
+func TestGenerateStructure_ScaffoldsSolver(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".structure"), 0755); err != nil {
+		t.Fatalf("Failed to create structure dir: %v", err)
+	}
+	withSolver := filepath.Join(tmpDir, "with-solver")
+	withoutSolver := filepath.Join(tmpDir, "without-solver")
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	challenges := []ChallengeData{
+		&mockSolverChallenge{mockChallengeData: mockChallengeData{cwd: withSolver}, solver: solver.LangGo},
+		&mockSolverChallenge{mockChallengeData: mockChallengeData{cwd: withoutSolver}},
+	}
+
+	if err := GenerateStructure(challenges); err != nil {
+		t.Errorf("GenerateStructure() failed: %v", err)
+	}
+
+	if lang, ok := solver.Detect(withSolver); !ok || lang != solver.LangGo {
+		t.Errorf("expected go solver to be scaffolded, got %q", lang)
+	}
+	if _, ok := solver.Detect(withoutSolver); ok {
+		t.Error("expected no solver for challenge without a solver language")
+	}
+}
+
+// TestGenerate_Report tests that every challenge gets a result and failures do not stop the others
+func TestGenerate_Report(t *testing.T) {
+	tmpDir := t.TempDir()
+	structureDir := filepath.Join(tmpDir, ".structure")
+	if err := os.MkdirAll(structureDir, 0755); err != nil {
+		t.Fatalf("Failed to create structure dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(structureDir, "README.md"), []byte("Test README"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	fresh := filepath.Join(tmpDir, "fresh")
+	upToDate := filepath.Join(tmpDir, "up-to-date")
+	if err := os.MkdirAll(upToDate, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(upToDate, "README.md"), []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A file where the challenge directory should be cannot be generated into
+	blocked := filepath.Join(tmpDir, "blocked")
+	if err := os.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Generate([]ChallengeData{
+		&mockChallengeData{cwd: fresh},
+		&mockChallengeData{cwd: blocked},
+		&mockChallengeData{cwd: upToDate},
+		nil,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := []string{StatusGenerated, StatusFailed, StatusSkipped, StatusSkipped}
+	for i, status := range want {
+		if got := report.Results[i].Status; got != status {
+			t.Errorf("result %d status = %q, want %q (%+v)", i, got, status, report.Results[i])
+		}
+	}
+	if len(report.Results[1].Errs) == 0 {
+		t.Error("expected the errors of the failed challenge")
+	}
+	if content, _ := os.ReadFile(filepath.Join(upToDate, "README.md")); string(content) != "kept" {
+		t.Errorf("existing file was overwritten: %q", content)
+	}
+
+	var buf strings.Builder
+	if err := report.Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "1 generated, 2 skipped, 1 failed") {
+		t.Errorf("Render() summary missing:\n%s", buf.String())
+	}
+}
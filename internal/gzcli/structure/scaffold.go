@@ -0,0 +1,133 @@
+package structure
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/template"
+)
+
+// Challenge types a new challenge can be scaffolded as
+const (
+	TypeStaticAttachment = "StaticAttachment"
+	TypeStaticContainer  = "StaticContainer"
+	TypeDynamicContainer = "DynamicContainer"
+)
+
+// ChallengeTypes are the challenge types a new challenge can be scaffolded as
+var ChallengeTypes = []string{TypeStaticAttachment, TypeStaticContainer, TypeDynamicContainer}
+
+// exampleTemplatesPath holds the example challenges of the event template,
+// also offered by the upload server
+const exampleTemplatesPath = "templates/others/event-template/.example"
+
+var (
+	scaffoldNameRegex   = regexp.MustCompile(`(?m)^name:.*$`)
+	scaffoldAuthorRegex = regexp.MustCompile(`(?m)^author:.*$`)
+	scaffoldDirRegex    = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ScaffoldOptions describes a new challenge
+type ScaffoldOptions struct {
+	EventPath string
+	Category  string
+	Name      string
+	Author    string
+	Type      string
+	// Compose gives a StaticAttachment challenge a docker-compose launcher
+	Compose bool
+}
+
+// scaffoldTemplate returns the example challenge a new challenge is copied
+// from
+func scaffoldTemplate(opts ScaffoldOptions) (string, error) {
+	switch opts.Type {
+	case TypeStaticAttachment:
+		if opts.Compose {
+			return "static-attachment-with-compose-launcher", nil
+		}
+		return "static-attachment", nil
+	case TypeStaticContainer:
+		return "static-container", nil
+	case TypeDynamicContainer:
+		return "dynamic-container", nil
+	}
+	return "", fmt.Errorf("unknown challenge type %q, must be one of %s", opts.Type, strings.Join(ChallengeTypes, ", "))
+}
+
+// ScaffoldDir returns the directory name of a challenge
+func ScaffoldDir(name string) string {
+	return strings.Trim(scaffoldDirRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// Scaffold creates the directory of a new challenge in a category of an
+// event from the example challenge of its type: challenge.yml with its name
+// and author, src/, dist/ and solver/, and a Dockerfile and docker-compose.yml
+// for challenges running containers. It returns the directory created.
+func Scaffold(opts ScaffoldOptions) (string, error) {
+	if !slices.Contains(config.CHALLENGE_CATEGORY, opts.Category) {
+		return "", fmt.Errorf("unknown category %q, must be one of %s", opts.Category, strings.Join(config.CHALLENGE_CATEGORY, ", "))
+	}
+	dirName := ScaffoldDir(opts.Name)
+	if dirName == "" {
+		return "", fmt.Errorf("challenge name %q has no letters or digits", opts.Name)
+	}
+	tpl, err := scaffoldTemplate(opts)
+	if err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(opts.EventPath, opts.Category, dirName)
+	if _, err := os.Stat(dest); err == nil {
+		return "", fmt.Errorf("%s already exists", dest)
+	}
+
+	src := path.Join(exampleTemplatesPath, tpl)
+	err = fs.WalkDir(template.File, src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, src), "/")
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+		data, err := template.File.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if d.Name() == "challenge.yml" {
+			data = scaffoldChallengeYaml(data, opts)
+		}
+		return os.WriteFile(target, data, 0600)
+	})
+	if err != nil {
+		_ = os.RemoveAll(dest)
+		return "", fmt.Errorf("failed to copy template %s: %w", tpl, err)
+	}
+
+	for _, dir := range []string{"src", "dist", "solver"} {
+		if err := os.MkdirAll(filepath.Join(dest, dir), 0750); err != nil {
+			return "", err
+		}
+	}
+	return dest, nil
+}
+
+// scaffoldChallengeYaml sets the name and author of the example challenge.yml,
+// keeping its comments
+func scaffoldChallengeYaml(data []byte, opts ScaffoldOptions) []byte {
+	data = scaffoldNameRegex.ReplaceAllLiteral(data, []byte("name: "+strconv.Quote(opts.Name)))
+	if opts.Author != "" {
+		data = scaffoldAuthorRegex.ReplaceAllLiteral(data, []byte("author: "+strconv.Quote(opts.Author)))
+	}
+	return data
+}
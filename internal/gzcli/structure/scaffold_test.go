@@ -0,0 +1,67 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScaffold(t *testing.T) {
+	tests := []struct {
+		opts  ScaffoldOptions
+		files []string
+	}{
+		{ScaffoldOptions{Category: "Misc", Name: "Sanity Check", Type: TypeStaticAttachment}, []string{"challenge.yml", "src/flag.txt", "dist", "solver/solve.py"}},
+		{ScaffoldOptions{Category: "Pwn", Name: "heap", Type: TypeStaticAttachment, Compose: true}, []string{"src/docker-compose.yml", "src/Dockerfile", "dist"}},
+		{ScaffoldOptions{Category: "Web", Name: "Baby SQLi!", Type: TypeDynamicContainer}, []string{"src/Dockerfile", "src/docker-compose.yml", "dist", "solver"}},
+		{ScaffoldOptions{Category: "Web", Name: "Static", Type: TypeStaticContainer}, []string{"src/Dockerfile", "dist", "solver"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opts.Name, func(t *testing.T) {
+			tt.opts.EventPath = t.TempDir()
+			tt.opts.Author = "alice"
+			dir, err := Scaffold(tt.opts)
+			if err != nil {
+				t.Fatalf("Scaffold() error = %v", err)
+			}
+			if want := filepath.Join(tt.opts.EventPath, tt.opts.Category, ScaffoldDir(tt.opts.Name)); dir != want {
+				t.Errorf("Scaffold() = %s, want %s", dir, want)
+			}
+			for _, file := range tt.files {
+				if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+					t.Errorf("missing %s: %v", file, err)
+				}
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "challenge.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := string(data)
+			if !strings.Contains(content, "name: \""+tt.opts.Name+"\"\n") || !strings.Contains(content, "author: \"alice\"\n") {
+				t.Errorf("challenge.yml lacks the name or author:\n%s", content)
+			}
+			if !strings.Contains(content, "type: \""+tt.opts.Type+"\"") || !strings.HasPrefix(content, "# yaml-language-server") {
+				t.Errorf("challenge.yml lost the template content:\n%s", content)
+			}
+
+			if _, err := Scaffold(tt.opts); err == nil {
+				t.Error("expected an error scaffolding over an existing challenge")
+			}
+		})
+	}
+}
+
+func TestScaffold_InvalidOptions(t *testing.T) {
+	for _, opts := range []ScaffoldOptions{
+		{Category: "Cooking", Name: "x", Type: TypeStaticAttachment},
+		{Category: "Web", Name: "x", Type: "Container"},
+		{Category: "Web", Name: "!!!", Type: TypeStaticAttachment},
+	} {
+		opts.EventPath = t.TempDir()
+		if _, err := Scaffold(opts); err == nil {
+			t.Errorf("Scaffold(%+v) should fail", opts)
+		}
+	}
+}
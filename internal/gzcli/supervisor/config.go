@@ -0,0 +1,152 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+)
+
+// DefaultConfigPath is the shared daemon configuration file
+const DefaultConfigPath = ".gzctf/daemon.yaml"
+
+// DefaultShutdownTimeout bounds how long subsystems get to stop
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Config is the shared configuration of the combined daemon
+type Config struct {
+	// LogFile receives the output of every subsystem (empty logs to the terminal)
+	LogFile string `yaml:"logFile"`
+	// StatusAddr serves GET /status with subsystem state and metrics (empty disables)
+	StatusAddr      string          `yaml:"statusAddr"`
+	ShutdownTimeout time.Duration   `yaml:"shutdownTimeout"`
+	Watcher         WatcherConfig   `yaml:"watcher"`
+	Launcher        LauncherConfig  `yaml:"launcher"`
+	UploadServer    UploadSrvConfig `yaml:"uploadServer"`
+}
+
+// WatcherConfig configures the file watcher subsystem
+type WatcherConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	Events        []string      `yaml:"events"`
+	ExcludeEvents []string      `yaml:"excludeEvents"`
+	Debounce      time.Duration `yaml:"debounce"`
+	PollInterval  time.Duration `yaml:"pollInterval"`
+	Poll          bool          `yaml:"poll"`
+	Ignore        []string      `yaml:"ignore"`
+	GitPull       bool          `yaml:"gitPull"`
+	GitInterval   time.Duration `yaml:"gitInterval"`
+	GitRepository string        `yaml:"gitRepository"`
+	DryRun        bool          `yaml:"dryRun"`
+	FormatYaml    bool          `yaml:"formatYaml"`
+	// ImageScan scans container images before full redeploys
+	ImageScan bool `yaml:"imageScan"`
+	// ImageScanCommand overrides the scan command template (default: trivy)
+	ImageScanCommand string `yaml:"imageScanCommand"`
+	// ImageScanBlock aborts redeploys on findings instead of warning (implies ImageScan)
+	ImageScanBlock bool `yaml:"imageScanBlock"`
+	// AnnounceWebhook is notified of newly created challenges (empty falls back to $GZCLI_ANNOUNCE_WEBHOOK)
+	AnnounceWebhook string `yaml:"announceWebhook"`
+	// LiveLock holds attachment updates and redeploys during the game until approved
+	LiveLock bool `yaml:"liveLock"`
+	// Targets restricts mirroring to the conf.yaml targets with these names or tags
+	Targets []string `yaml:"targets,omitempty"`
+}
+
+// LauncherConfig configures the challenge launcher subsystem
+type LauncherConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Host      string `yaml:"host"`
+	Port      int    `yaml:"port"`
+	Socket    string `yaml:"socket"`
+	ProbeHost string `yaml:"probeHost"`
+	// APIToken enables the REST API (empty falls back to $GZCLI_LAUNCHER_API_TOKEN)
+	APIToken string `yaml:"apiToken"`
+	// TeamIsolation gives each team its own challenge instances (requires TeamTokens)
+	TeamIsolation bool `yaml:"teamIsolation"`
+	// TeamTokens is a YAML file mapping team names to tokens (implies TeamIsolation)
+	TeamTokens string `yaml:"teamTokens"`
+	// StateFile persists instance state across restarts (empty disables it)
+	StateFile string `yaml:"stateFile"`
+	// KeepInstances leaves running instances up on shutdown for the next start
+	KeepInstances bool `yaml:"keepInstances"`
+}
+
+// UploadSrvConfig configures the upload server subsystem
+type UploadSrvConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+	Event   string `yaml:"event"`
+	Workers int    `yaml:"workers,omitempty"` // Uploads processed concurrently; 0 uses the default
+	// AllowAnonymous accepts uploads without a token while no token exists
+	AllowAnonymous bool `yaml:"allow_anonymous,omitempty"`
+}
+
+// DefaultConfig returns the configuration used when no file exists: the
+// watcher and launcher are enabled and the upload server is disabled
+func DefaultConfig() Config {
+	return Config{
+		ShutdownTimeout: DefaultShutdownTimeout,
+		Watcher: WatcherConfig{
+			Enabled:       true,
+			Debounce:      2 * time.Second,
+			PollInterval:  5 * time.Second,
+			GitPull:       true,
+			GitInterval:   1 * time.Minute,
+			GitRepository: ".",
+		},
+		Launcher: LauncherConfig{
+			Enabled:   true,
+			Host:      "localhost",
+			Port:      8080,
+			Socket:    ".gzcli/launcher/launcher.sock",
+			StateFile: ".gzcli/launcher/state.db",
+		},
+		UploadServer: UploadSrvConfig{
+			Enabled: false,
+			Host:    "localhost",
+			Port:    8090,
+		},
+	}
+}
+
+// LoadConfig reads the daemon configuration, filling unset values from
+// DefaultConfig. A missing file at the default path is not an error.
+func LoadConfig(path string) (Config, error) {
+	conf := DefaultConfig()
+	if path == "" {
+		path = DefaultConfigPath
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) && path == DefaultConfigPath {
+			return conf, nil
+		}
+		return conf, fmt.Errorf("daemon config %s: %w", path, err)
+	}
+
+	if err := fileutil.ParseYamlFromFile(path, &conf); err != nil {
+		return conf, fmt.Errorf("failed to parse daemon config %s: %w", path, err)
+	}
+	return conf, conf.Validate()
+}
+
+// Validate checks the configuration for values the subsystems cannot use
+func (c Config) Validate() error {
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdownTimeout must not be negative")
+	}
+	if c.Launcher.Enabled && (c.Launcher.Port <= 0 || c.Launcher.Port > 65535) {
+		return fmt.Errorf("launcher.port %d is out of range", c.Launcher.Port)
+	}
+	if c.UploadServer.Enabled && (c.UploadServer.Port <= 0 || c.UploadServer.Port > 65535) {
+		return fmt.Errorf("uploadServer.port %d is out of range", c.UploadServer.Port)
+	}
+	if c.Launcher.Enabled && c.UploadServer.Enabled &&
+		c.Launcher.Host == c.UploadServer.Host && c.Launcher.Port == c.UploadServer.Port {
+		return fmt.Errorf("launcher and uploadServer cannot share %s:%d", c.Launcher.Host, c.Launcher.Port)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli"
+	"github.com/dimasma0305/gzcli/internal/gzcli/server"
+	"github.com/dimasma0305/gzcli/internal/gzcli/uploadserver"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher"
+)
+
+// WatcherSubsystem runs the file watcher in-process
+type WatcherSubsystem struct {
+	gz     *gzcli.GZ
+	config watcher.WatcherConfig
+}
+
+// NewWatcherSubsystem creates a watcher subsystem; daemon mode is always
+// disabled since the supervisor owns the process
+func NewWatcherSubsystem(gz *gzcli.GZ, config watcher.WatcherConfig) *WatcherSubsystem {
+	config.DaemonMode = false
+	return &WatcherSubsystem{gz: gz, config: config}
+}
+
+// Name implements Subsystem
+func (w *WatcherSubsystem) Name() string { return "watcher" }
+
+// Run implements Subsystem
+func (w *WatcherSubsystem) Run(ctx context.Context) error {
+	if err := w.gz.StartWatcher(w.config); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return w.gz.StopWatcher()
+}
+
+// Status implements StatusReporter
+func (w *WatcherSubsystem) Status() map[string]interface{} {
+	return w.gz.GetWatcherStatus()
+}
+
+// LauncherSubsystem runs the challenge launcher server
+type LauncherSubsystem struct {
+	config LauncherConfig
+}
+
+// NewLauncherSubsystem creates a launcher subsystem
+func NewLauncherSubsystem(config LauncherConfig) *LauncherSubsystem {
+	return &LauncherSubsystem{config: config}
+}
+
+// Name implements Subsystem
+func (l *LauncherSubsystem) Name() string { return "launcher" }
+
+// Run implements Subsystem
+func (l *LauncherSubsystem) Run(ctx context.Context) error {
+	server.SetProbeHost(l.config.ProbeHost)
+	server.SetStatePath(l.config.StateFile)
+	server.SetKeepInstances(l.config.KeepInstances)
+	token := l.config.APIToken
+	if token == "" {
+		token = os.Getenv(server.APITokenEnv)
+	}
+	server.SetAPIToken(token)
+	if err := server.ConfigureTeamIsolation(l.config.TeamIsolation, l.config.TeamTokens); err != nil {
+		return err
+	}
+	return server.RunServerContext(ctx, l.config.Host, l.config.Port, l.config.Socket)
+}
+
+// Status implements StatusReporter
+func (l *LauncherSubsystem) Status() map[string]interface{} {
+	return map[string]interface{}{
+		"address": fmt.Sprintf("http://%s:%d", l.config.Host, l.config.Port),
+		"socket":  l.config.Socket,
+	}
+}
+
+// UploadSubsystem runs the challenge upload server
+type UploadSubsystem struct {
+	config UploadSrvConfig
+}
+
+// NewUploadSubsystem creates an upload server subsystem
+func NewUploadSubsystem(config UploadSrvConfig) *UploadSubsystem {
+	return &UploadSubsystem{config: config}
+}
+
+// Name implements Subsystem
+func (u *UploadSubsystem) Name() string { return "upload-server" }
+
+// Run implements Subsystem
+func (u *UploadSubsystem) Run(ctx context.Context) error {
+	return uploadserver.RunContext(ctx, uploadserver.Options{
+		Host:           u.config.Host,
+		Port:           u.config.Port,
+		Event:          u.config.Event,
+		Workers:        u.config.Workers,
+		AllowAnonymous: u.config.AllowAnonymous,
+	})
+}
+
+// Status implements StatusReporter
+func (u *UploadSubsystem) Status() map[string]interface{} {
+	return map[string]interface{}{
+		"address": fmt.Sprintf("http://%s:%d", u.config.Host, u.config.Port),
+		"event":   u.config.Event,
+	}
+}
+
+// RedirectOutput sends stdout and stderr, and so every subsystem's logs, to
+// the given file. The returned function restores the original streams.
+func RedirectOutput(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	//nolint:gosec // G304: log path comes from the daemon configuration
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = f, f
+	return func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		_ = f.Close()
+	}, nil
+}
@@ -0,0 +1,191 @@
+// Package supervisor hosts several long-running gzcli subsystems (watcher,
+// launcher, upload server) in a single process with coordinated shutdown.
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// Subsystem is a long-running component hosted by the supervisor
+type Subsystem interface {
+	// Name identifies the subsystem in logs and status output
+	Name() string
+	// Run blocks until ctx is cancelled or the subsystem fails. Returning nil
+	// after ctx is cancelled signals a clean shutdown.
+	Run(ctx context.Context) error
+}
+
+// StatusReporter is implemented by subsystems that expose extra status or metrics
+type StatusReporter interface {
+	Status() map[string]interface{}
+}
+
+// SubsystemStatus is the state of one subsystem
+type SubsystemStatus struct {
+	Name      string                 `json:"name"`
+	Running   bool                   `json:"running"`
+	StartedAt time.Time              `json:"started_at"`
+	StoppedAt *time.Time             `json:"stopped_at,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+type subsystemState struct {
+	subsystem Subsystem
+	status    SubsystemStatus
+}
+
+// Supervisor runs subsystems and stops all of them when one fails or the
+// context is cancelled
+type Supervisor struct {
+	shutdownTimeout time.Duration
+
+	mu     sync.RWMutex
+	states []*subsystemState
+}
+
+// New creates a supervisor. shutdownTimeout bounds how long Run waits for
+// subsystems to stop after shutdown begins.
+func New(shutdownTimeout time.Duration) *Supervisor {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+	return &Supervisor{shutdownTimeout: shutdownTimeout}
+}
+
+// Add registers a subsystem; it must be called before Run
+func (s *Supervisor) Add(sub Subsystem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states = append(s.states, &subsystemState{
+		subsystem: sub,
+		status:    SubsystemStatus{Name: sub.Name()},
+	})
+}
+
+// Run starts all subsystems and blocks until ctx is cancelled or any subsystem
+// exits. The remaining subsystems are then cancelled and awaited. The returned
+// error joins every subsystem failure.
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.mu.RLock()
+	states := append([]*subsystemState{}, s.states...)
+	s.mu.RUnlock()
+
+	if len(states) == 0 {
+		return fmt.Errorf("no subsystems enabled")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(states))
+
+	for _, st := range states {
+		s.setRunning(st)
+		log.Info("Starting %s...", st.subsystem.Name())
+		go func(st *subsystemState) {
+			err := st.subsystem.Run(runCtx)
+			s.setStopped(st, err)
+			results <- result{name: st.subsystem.Name(), err: err}
+		}(st)
+	}
+
+	var errs []error
+	remaining := len(states)
+
+	// Wait for shutdown or the first subsystem to exit
+	select {
+	case <-ctx.Done():
+		log.Info("Shutdown requested, stopping %d subsystem(s)...", remaining)
+	case r := <-results:
+		remaining--
+		if r.err != nil {
+			log.Error("%s failed: %v", r.name, r.err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+		} else {
+			log.Info("%s exited", r.name)
+		}
+		log.Info("Stopping remaining %d subsystem(s)...", remaining)
+	}
+	cancel()
+
+	timeout := time.NewTimer(s.shutdownTimeout)
+	defer timeout.Stop()
+	for remaining > 0 {
+		select {
+		case r := <-results:
+			remaining--
+			if r.err != nil && !errors.Is(r.err, context.Canceled) {
+				log.Error("%s stopped with error: %v", r.name, r.err)
+				errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+			} else {
+				log.Info("%s stopped", r.name)
+			}
+		case <-timeout.C:
+			errs = append(errs, fmt.Errorf("timed out after %v waiting for %d subsystem(s) to stop", s.shutdownTimeout, remaining))
+			return errors.Join(errs...)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Status returns the state of every subsystem, including reported details
+func (s *Supervisor) Status() []SubsystemStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]SubsystemStatus, 0, len(s.states))
+	for _, st := range s.states {
+		status := st.status
+		if reporter, ok := st.subsystem.(StatusReporter); ok && status.Running {
+			status.Details = reporter.Status()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// StatusHandler serves the subsystem status as JSON
+func (s *Supervisor) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"subsystems": s.Status()}); err != nil {
+			log.Error("Failed to write daemon status: %v", err)
+		}
+	})
+}
+
+func (s *Supervisor) setRunning(st *subsystemState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.status.Running = true
+	st.status.StartedAt = time.Now()
+	st.status.StoppedAt = nil
+	st.status.Error = ""
+}
+
+func (s *Supervisor) setStopped(st *subsystemState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	st.status.Running = false
+	st.status.StoppedAt = &now
+	if err != nil {
+		st.status.Error = err.Error()
+	}
+}
@@ -0,0 +1,169 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeSubsystem struct {
+	name    string
+	failErr error         // returned immediately when set
+	delay   time.Duration // shutdown delay after cancellation
+	stopped atomic.Bool
+}
+
+func (f *fakeSubsystem) Name() string { return f.name }
+
+func (f *fakeSubsystem) Run(ctx context.Context) error {
+	if f.failErr != nil {
+		return f.failErr
+	}
+	<-ctx.Done()
+	time.Sleep(f.delay)
+	f.stopped.Store(true)
+	return nil
+}
+
+func TestSupervisor_ShutdownStopsAll(t *testing.T) {
+	a := &fakeSubsystem{name: "a"}
+	b := &fakeSubsystem{name: "b"}
+
+	sup := New(time.Second)
+	sup.Add(a)
+	sup.Add(b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sup.Run(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	for _, st := range sup.Status() {
+		if !st.Running {
+			t.Errorf("subsystem %s not running", st.Name)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !a.stopped.Load() || !b.stopped.Load() {
+		t.Error("not all subsystems were stopped")
+	}
+}
+
+func TestSupervisor_FailureStopsOthers(t *testing.T) {
+	healthy := &fakeSubsystem{name: "healthy"}
+	broken := &fakeSubsystem{name: "broken", failErr: errors.New("bind: address in use")}
+
+	sup := New(time.Second)
+	sup.Add(healthy)
+	sup.Add(broken)
+
+	err := sup.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "broken: bind: address in use") {
+		t.Fatalf("Run() error = %v, want broken subsystem failure", err)
+	}
+	if !healthy.stopped.Load() {
+		t.Error("healthy subsystem was not stopped after failure")
+	}
+
+	for _, st := range sup.Status() {
+		if st.Running {
+			t.Errorf("subsystem %s still marked running", st.Name)
+		}
+		if st.Name == "broken" && st.Error == "" {
+			t.Error("broken subsystem status has no error")
+		}
+	}
+}
+
+func TestSupervisor_ShutdownTimeout(t *testing.T) {
+	sup := New(10 * time.Millisecond)
+	sup.Add(&fakeSubsystem{name: "slow", delay: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := sup.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Run() error = %v, want timeout", err)
+	}
+}
+
+func TestSupervisor_NoSubsystems(t *testing.T) {
+	if err := New(0).Run(context.Background()); err == nil {
+		t.Error("Run() with no subsystems: expected error")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("missing default file uses defaults", func(t *testing.T) {
+		wd, _ := os.Getwd()
+		t.Cleanup(func() { _ = os.Chdir(wd) })
+		if err := os.Chdir(t.TempDir()); err != nil {
+			t.Fatalf("chdir: %v", err)
+		}
+
+		conf, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if !conf.Watcher.Enabled || !conf.Launcher.Enabled || conf.UploadServer.Enabled {
+			t.Errorf("unexpected default subsystems: %+v", conf)
+		}
+	})
+
+	t.Run("missing explicit file is an error", func(t *testing.T) {
+		if _, err := LoadConfig(filepath.Join(t.TempDir(), "nope.yaml")); err == nil {
+			t.Error("LoadConfig() expected error for missing explicit file")
+		}
+	})
+
+	t.Run("file overrides defaults", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "daemon.yaml")
+		content := `shutdownTimeout: 5s
+watcher:
+  enabled: false
+uploadServer:
+  enabled: true
+  port: 9000
+`
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+
+		conf, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if conf.Watcher.Enabled {
+			t.Error("watcher should be disabled")
+		}
+		if !conf.UploadServer.Enabled || conf.UploadServer.Port != 9000 || conf.UploadServer.Host != "localhost" {
+			t.Errorf("uploadServer = %+v", conf.UploadServer)
+		}
+		if conf.ShutdownTimeout != 5*time.Second {
+			t.Errorf("shutdownTimeout = %v, want 5s", conf.ShutdownTimeout)
+		}
+		if conf.Launcher.Port != 8080 {
+			t.Errorf("launcher port = %d, want default 8080", conf.Launcher.Port)
+		}
+	})
+
+	t.Run("port clash is rejected", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "daemon.yaml")
+		content := "uploadServer:\n  enabled: true\n  port: 8080\n"
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Error("LoadConfig() expected error for clashing ports")
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package gzcli
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestResolveSyncWorkerCount(t *testing.T) {
+	defaultWorkers := 4
+	if cpus := runtime.NumCPU(); cpus < defaultWorkers {
+		defaultWorkers = cpus
+	}
+
+	tests := []struct {
+		name       string
+		total      int
+		configured int
+		env        string
+		want       int
+	}{
+		{name: "default", total: 100, want: defaultWorkers},
+		{name: "configured", total: 100, configured: 8, want: 8},
+		{name: "configured wins over env", total: 100, configured: 3, env: "6", want: 3},
+		{name: "env", total: 100, env: "6", want: 6},
+		{name: "invalid env", total: 100, env: "many", want: defaultWorkers},
+		{name: "capped by total", total: 2, configured: 8, want: 2},
+		{name: "no challenges", total: 0, configured: 8, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GZCLI_SYNC_WORKERS", tt.env)
+			if got := resolveSyncWorkerCount(tt.total, tt.configured); got != tt.want {
+				t.Errorf("resolveSyncWorkerCount(%d, %d) = %d, want %d", tt.total, tt.configured, got, tt.want)
+			}
+		})
+	}
+}
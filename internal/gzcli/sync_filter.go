@@ -0,0 +1,150 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+// SyncFilter selects the challenges of an event a sync is limited to. Empty
+// fields select every challenge; set fields must all match.
+type SyncFilter struct {
+	Only         []string // Challenge directories relative to the event, as <category>/<challenge>, or challenge names
+	Categories   []string // Challenge categories
+	ChangedSince string   // Git ref; only challenges with files changed since it
+}
+
+// IsEmpty reports whether the filter selects every challenge
+func (f SyncFilter) IsEmpty() bool {
+	return len(f.Only) == 0 && len(f.Categories) == 0 && f.ChangedSince == ""
+}
+
+// Apply narrows the challenges of the event at eventPath to those selected by
+// the filter. An --only entry matching no challenge is an error, so typos are
+// not silently synced as nothing.
+func (f SyncFilter) Apply(eventPath string, challengesConf []config.ChallengeYaml) ([]config.ChallengeYaml, error) {
+	if f.IsEmpty() {
+		return challengesConf, nil
+	}
+
+	var changed []string
+	if f.ChangedSince != "" {
+		var err error
+		if changed, err = changedFiles(eventPath, f.ChangedSince); err != nil {
+			return nil, err
+		}
+	}
+
+	matchedOnly := make([]bool, len(f.Only))
+	filtered := make([]config.ChallengeYaml, 0, len(challengesConf))
+	for _, c := range challengesConf {
+		dir := challengeDir(eventPath, c)
+		if len(f.Only) > 0 {
+			selected := false
+			for i, only := range f.Only {
+				if strings.EqualFold(strings.Trim(filepath.ToSlash(only), "/"), dir) || only == c.Name {
+					matchedOnly[i] = true
+					selected = true
+				}
+			}
+			if !selected {
+				continue
+			}
+		}
+		if len(f.Categories) > 0 && !matchesCategory(c, dir, f.Categories) {
+			continue
+		}
+		if f.ChangedSince != "" && !containsChangedFile(c.Cwd, changed) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	for i, matched := range matchedOnly {
+		if !matched {
+			return nil, fmt.Errorf("challenge %q not found in event", f.Only[i])
+		}
+	}
+	return filtered, nil
+}
+
+// challengeDir returns the directory of a challenge relative to the event as
+// <category>/<challenge>, lowercased for case-insensitive matching
+func challengeDir(eventPath string, c config.ChallengeYaml) string {
+	rel, err := filepath.Rel(eventPath, c.Cwd)
+	if err != nil {
+		rel = c.Cwd
+	}
+	return strings.ToLower(filepath.ToSlash(rel))
+}
+
+// matchesCategory reports whether the category of a challenge, or the name of
+// its category directory, is one of categories
+func matchesCategory(c config.ChallengeYaml, dir string, categories []string) bool {
+	categoryDir, _, _ := strings.Cut(dir, "/")
+	for _, category := range categories {
+		if strings.EqualFold(category, c.Category) || strings.EqualFold(category, categoryDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsChangedFile reports whether one of the changed files is in dir
+func containsChangedFile(dir string, changed []string) bool {
+	dir = filepath.Clean(dir) + string(filepath.Separator)
+	for _, file := range changed {
+		if strings.HasPrefix(file, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedFiles returns the paths of the files under dir changed since ref:
+// committed, staged or not, and untracked files not ignored
+func changedFiles(dir, ref string) ([]string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+	if _, err := runGit(dir, "rev-parse", "--show-toplevel"); err != nil {
+		return nil, fmt.Errorf("--changed-since needs the event in a git repository: %w", err)
+	}
+
+	// Both list paths relative to dir
+	diff, err := runGit(dir, "diff", "--name-only", "--relative", ref, "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s failed: %w", ref, err)
+	}
+	untracked, err := runGit(dir, "ls-files", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, filepath.Join(dir, filepath.FromSlash(line)))
+		}
+	}
+	return files, nil
+}
+
+// runGit runs git in dir and returns its output, with stderr in the error
+func runGit(dir string, args ...string) (string, error) {
+	//nolint:gosec // G204: program is the literal "git" and the ref is checked not to be an option
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = os.Environ()
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
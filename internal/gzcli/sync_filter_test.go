@@ -0,0 +1,111 @@
+package gzcli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+)
+
+func filterTestEvent(t *testing.T) (string, []config.ChallengeYaml) {
+	t.Helper()
+	eventPath := t.TempDir()
+	var challenges []config.ChallengeYaml
+	for _, c := range []struct{ category, dir, name string }{
+		{"Web", "my-chall", "My Chall"},
+		{"Web", "other", "Other"},
+		{"Pwn", "heap", "Heap"},
+	} {
+		dir := filepath.Join(eventPath, c.category, c.dir)
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "challenge.yml"), []byte("name: "+c.name+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		challenges = append(challenges, config.ChallengeYaml{Name: c.name, Category: c.category, Cwd: dir})
+	}
+	return eventPath, challenges
+}
+
+func challengeNames(challenges []config.ChallengeYaml) []string {
+	names := make([]string, len(challenges))
+	for i, c := range challenges {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestSyncFilter_Apply(t *testing.T) {
+	eventPath, challenges := filterTestEvent(t)
+
+	tests := []struct {
+		name   string
+		filter SyncFilter
+		want   []string
+	}{
+		{"empty", SyncFilter{}, []string{"My Chall", "Other", "Heap"}},
+		{"only directory", SyncFilter{Only: []string{"web/my-chall"}}, []string{"My Chall"}},
+		{"only name", SyncFilter{Only: []string{"Heap", "Web/Other/"}}, []string{"Other", "Heap"}},
+		{"category", SyncFilter{Categories: []string{"pwn"}}, []string{"Heap"}},
+		{"only and category", SyncFilter{Only: []string{"web/other", "pwn/heap"}, Categories: []string{"Web"}}, []string{"Other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.filter.Apply(eventPath, challenges)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if names := challengeNames(got); strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Apply() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	if _, err := (SyncFilter{Only: []string{"web/typo"}}).Apply(eventPath, challenges); err == nil {
+		t.Error("Apply() with an unknown --only challenge succeeded")
+	}
+}
+
+func TestSyncFilter_ChangedSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	eventPath, challenges := filterTestEvent(t)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", eventPath}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	git("tag", "base")
+
+	// A committed change, and an untracked file in another challenge
+	if err := os.WriteFile(filepath.Join(eventPath, "Web", "my-chall", "challenge.yml"), []byte("name: My Chall\nvalue: 100\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "-q", "-am", "change")
+	if err := os.WriteFile(filepath.Join(eventPath, "Pwn", "heap", "solve.py"), []byte("print()\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := SyncFilter{ChangedSince: "base"}.Apply(eventPath, challenges)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if names := challengeNames(got); strings.Join(names, ",") != "My Chall,Heap" {
+		t.Errorf("Apply() = %v, want [My Chall Heap]", names)
+	}
+
+	if _, err := (SyncFilter{ChangedSince: "--output=/tmp/x"}).Apply(eventPath, challenges); err == nil {
+		t.Error("Apply() accepted an option as git ref")
+	}
+}
@@ -6,6 +6,7 @@ type ColumnMapping struct {
 	Email    string `yaml:"email"`
 	TeamName string `yaml:"team_name"`
 	Events   string `yaml:"events"`
+	Division string `yaml:"division"`
 }
 
 // Config holds the configuration for team operations
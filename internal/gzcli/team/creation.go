@@ -12,6 +12,12 @@ import (
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
+// Length limits of generated usernames and team names
+const (
+	maxUsernameLength = 15
+	maxTeamNameLength = 20
+)
+
 // initializeCredentials initializes credentials for a new user
 func initializeCredentials(teamCreds *TeamCreds, existingTeamNames, existingUserNames map[string]struct{}, credsCache []*TeamCreds, generateUsername func(string, int, map[string]struct{}) (string, error)) (*TeamCreds, error) {
 	pass, err := password.Generate(24, 10, 0, false, false)
@@ -20,13 +26,12 @@ func initializeCredentials(teamCreds *TeamCreds, existingTeamNames, existingUser
 	}
 
 	// Generate a unique username
-	username, err := generateUsername(teamCreds.Username, 15, existingUserNames)
+	username, err := generateUsername(teamCreds.Username, maxUsernameLength, existingUserNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate username: %v", err)
 	}
 
 	// Normalize the team name
-	const maxTeamNameLength = 20
 	teamName := NormalizeTeamName(teamCreds.TeamName, maxTeamNameLength, existingTeamNames)
 
 	// If registration fails, attempt to initialize API with cached credentials
@@ -97,18 +102,56 @@ func sendCredentialsEmail(teamCreds *TeamCreds, currentCreds *TeamCreds, config
 		return
 	}
 
+	if err := deliverCredentials(teamCreds.Username, currentCreds, config); err != nil {
+		log.ErrorH2("Failed to send email to %s: %v", currentCreds.Email, err)
+		return
+	}
+	log.InfoH2("Successfully sending email to %s", currentCreds.Email)
+}
+
+// deliverCredentials emails creds and records the outcome: a failed delivery
+// is kept in EmailError, to be retried with ResendFailedEmails
+func deliverCredentials(realName string, creds *TeamCreds, config ConfigInterface) error {
 	environtURL := os.Getenv("URL")
 	if environtURL == "" {
 		environtURL = config.GetUrl()
 	}
 
-	if err := SendEmail(teamCreds.Username, environtURL, currentCreds, config.GetAppSettings(), config.GetTeamMemberCountLimit() == 1); err != nil {
-		log.ErrorH2("Failed to send email to %s: %v", currentCreds.Email, err)
-		return
+	if err := SendEmail(realName, environtURL, creds, config.GetAppSettings(), config.GetTeamMemberCountLimit() == 1); err != nil {
+		creds.EmailError = err.Error()
+		return err
+	}
+	creds.IsEmailAlreadySent = true
+	creds.EmailError = ""
+	return nil
+}
+
+// FailedEmails returns the credentials whose email delivery failed and was
+// not sent since
+func FailedEmails(creds []*TeamCreds) []*TeamCreds {
+	var failed []*TeamCreds
+	for _, c := range creds {
+		if c.EmailError != "" && !c.IsEmailAlreadySent {
+			failed = append(failed, c)
+		}
 	}
+	return failed
+}
 
-	log.InfoH2("Successfully sending email to %s", currentCreds.Email)
-	currentCreds.IsEmailAlreadySent = true
+// ResendFailedEmails emails the credentials whose delivery failed before
+// again, and returns how many were sent. Outcomes are recorded on creds.
+func ResendFailedEmails(creds []*TeamCreds, config ConfigInterface) (sent int, failed int) {
+	for _, c := range FailedEmails(creds) {
+		previous := c.EmailError
+		if err := deliverCredentials(c.Username, c, config); err != nil {
+			log.ErrorH2("Failed to send email to %s again: %v (previously: %s)", c.Email, err, previous)
+			failed++
+			continue
+		}
+		log.InfoH2("Successfully sending email to %s", c.Email)
+		sent++
+	}
+	return sent, failed
 }
 
 // joinTeamToGame joins a team to the game
@@ -124,15 +167,31 @@ func (tc *Creator) joinTeamToGame() error {
 		return nil
 	}
 
+	// Division of the CSV; cached credentials keep the one joined before
+	division := tc.teamCreds.Division
+
 	// Helper to join a specific event ID
 	joinGame := func(eventID int, eventName string) error {
+		if division != "" {
+			if err := tc.ensureDivision(eventID, eventName, division); err != nil {
+				log.Error("Failed to create division '%s' in event '%s': %s", division, eventName, err.Error())
+				return err
+			}
+		}
 		if err := tc.api.JoinGame(eventID, &gzapi.GameJoinModel{
 			TeamId:     team[0].Id,
+			Division:   division,
 			InviteCode: tc.config.GetInviteCode(),
 		}); err != nil {
+			// A team that joined before may have changed division in the CSV
+			if division != "" && tc.syncDivision(eventID, eventName, team[0], division) == nil {
+				tc.currentCreds.Division = division
+				return nil
+			}
 			log.Error("Failed to join event '%s' (ID: %d): %s", eventName, eventID, err.Error())
 			return err
 		}
+		tc.currentCreds.Division = division
 		log.InfoH2("Successfully joining team %s to game %s", team[0].Name, eventName)
 		return nil
 	}
@@ -177,6 +236,40 @@ func (tc *Creator) joinTeamToGame() error {
 	return nil
 }
 
+// ensureDivision creates the division in the game if it does not exist yet,
+// so teams can join it
+func (tc *Creator) ensureDivision(eventID int, eventName, division string) error {
+	game := &gzapi.Game{Id: eventID, CS: tc.config.GetAdminAPI()}
+	added, err := game.EnsureDivision(division)
+	if err != nil {
+		return err
+	}
+	if added {
+		log.InfoH2("Created division %s in game %s", division, eventName)
+	}
+	return nil
+}
+
+// syncDivision moves a team that already joined the game to division. It
+// fails if the team has not joined the game.
+func (tc *Creator) syncDivision(eventID int, eventName string, team *gzapi.Team, division string) error {
+	game := &gzapi.Game{Id: eventID, CS: tc.config.GetAdminAPI()}
+	participation, err := game.GetParticipation(team.Id)
+	if err != nil {
+		return err
+	}
+	if participation.Division == division {
+		log.InfoH2("Team %s already in division %s of game %s", team.Name, division, eventName)
+		return nil
+	}
+	if err := participation.SetDivision(division); err != nil {
+		log.Error("Failed to move team %s to division %s in game %s: %s", team.Name, division, eventName, err.Error())
+		return err
+	}
+	log.InfoH2("Moved team %s to division %s in game %s", team.Name, division, eventName)
+	return nil
+}
+
 // Creator handles the logic for creating a team and user.
 type Creator struct {
 	teamCreds         *TeamCreds
@@ -267,12 +360,7 @@ func CreateTeamAndUser(teamCreds *TeamCreds, config ConfigInterface, existingTea
 
 // NormalizeTeamName ensures team name is unique and within length limit
 func NormalizeTeamName(teamName string, maxLen int, existingTeamNames map[string]struct{}) string {
-	// Sanitize: remove null bytes and other problematic characters
-	teamName = strings.ReplaceAll(teamName, "\x00", "")
-	teamName = strings.ReplaceAll(teamName, "\n", " ")
-	teamName = strings.ReplaceAll(teamName, "\r", " ")
-	teamName = strings.ReplaceAll(teamName, "\t", " ")
-	teamName = strings.TrimSpace(teamName)
+	teamName = sanitizeTeamName(teamName)
 
 	// Truncate if too long
 	if len(teamName) > maxLen {
@@ -299,6 +387,15 @@ func NormalizeTeamName(teamName string, maxLen int, existingTeamNames map[string
 	return teamName
 }
 
+// sanitizeTeamName removes null bytes and other problematic characters
+func sanitizeTeamName(teamName string) string {
+	teamName = strings.ReplaceAll(teamName, "\x00", "")
+	teamName = strings.ReplaceAll(teamName, "\n", " ")
+	teamName = strings.ReplaceAll(teamName, "\r", " ")
+	teamName = strings.ReplaceAll(teamName, "\t", " ")
+	return strings.TrimSpace(teamName)
+}
+
 // ConfigInterface provides access to configuration values needed by team creation
 type ConfigInterface interface {
 	GetUrl() string
@@ -323,4 +420,16 @@ type EmailConfig struct {
 		Host string
 		Port int
 	}
+	Provider        string  // smtp (default), sendgrid, mailgun or ses
+	From            string  // Sender address (default: UserName)
+	FromName        string  // Sender name
+	APIKey          string  // SendGrid or Mailgun API key
+	Domain          string  // Mailgun sending domain
+	Region          string  // Mailgun region (us or eu) or SES region
+	AccessKeyID     string  // SES access key
+	SecretAccessKey string  // SES secret key
+	RateLimit       float64 // Emails per second; 0 uses the provider default
+	Subject         string  // Subject (default: "Your Team Credentials")
+	Template        string  // HTML template file replacing the default email
+	Branding        EmailBranding
 }
@@ -0,0 +1,147 @@
+package team
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CredsKeyEnv overrides the on-disk credentials key. Any secret string is
+// accepted; it is hashed with SHA-256 to derive the AES-256 key.
+const CredsKeyEnv = "GZCLI_CREDS_KEY"
+
+const encryptedCredsVersion = 1
+
+// EncryptedCreds is the at-rest representation of the team credentials cache
+type EncryptedCreds struct {
+	Version    int    `yaml:"version"`
+	Nonce      string `yaml:"nonce"`
+	Ciphertext string `yaml:"ciphertext"`
+}
+
+// CredsKeyPath returns the location of the credentials key file. It lives in
+// the user config directory so it is never committed alongside the cache.
+var CredsKeyPath = func() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gzcli", "creds.key"), nil
+}
+
+// LoadCredsKey returns the key used to encrypt team credentials, creating a
+// random key file on first use when no key is provided via CredsKeyEnv.
+func LoadCredsKey() ([]byte, error) {
+	if secret := os.Getenv(CredsKeyEnv); secret != "" {
+		sum := sha256.Sum256([]byte(secret))
+		return sum[:], nil
+	}
+
+	path, err := CredsKeyPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate credentials key: %w", err)
+	}
+
+	//nolint:gosec // G304: Key path is derived from the user config directory
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, decodeErr := hex.DecodeString(strings.TrimSpace(string(data)))
+		if decodeErr != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid credentials key in %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read credentials key: %w", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate credentials key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write credentials key: %w", err)
+	}
+	return key, nil
+}
+
+// EncryptCreds seals creds with AES-256-GCM. The scope (event and server) is
+// bound as additional data so a blob cannot be replayed under another scope.
+func EncryptCreds(creds []*TeamCreds, key []byte, scope string) (*EncryptedCreds, error) {
+	plaintext, err := yaml.Marshal(creds)
+	if err != nil {
+		return nil, fmt.Errorf("encoding failed: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return &EncryptedCreds{
+		Version:    encryptedCredsVersion,
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, []byte(scope))),
+	}, nil
+}
+
+// DecryptCreds opens a blob produced by EncryptCreds for the same scope
+func DecryptCreds(enc *EncryptedCreds, key []byte, scope string) ([]*TeamCreds, error) {
+	if enc.Version != encryptedCredsVersion {
+		return nil, fmt.Errorf("unsupported credentials cache version %d", enc.Version)
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(enc.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(enc.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(scope))
+	if err != nil {
+		return nil, errors.New("failed to decrypt credentials: wrong key or corrupted cache")
+	}
+
+	var creds []*TeamCreds
+	if err := yaml.Unmarshal(plaintext, &creds); err != nil {
+		return nil, fmt.Errorf("decoding failed: %w", err)
+	}
+	return creds, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,88 @@
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptCreds_RoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	creds := []*TeamCreds{{Username: "alice", Password: "s3cret", Email: "a@example.com", TeamName: "Alpha"}}
+
+	enc, err := EncryptCreds(creds, key, "ctf2025/abc")
+	if err != nil {
+		t.Fatalf("EncryptCreds() failed: %v", err)
+	}
+	if strings.Contains(enc.Ciphertext, "s3cret") {
+		t.Error("ciphertext contains plaintext password")
+	}
+
+	got, err := DecryptCreds(enc, key, "ctf2025/abc")
+	if err != nil {
+		t.Fatalf("DecryptCreds() failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Password != "s3cret" || got[0].TeamName != "Alpha" {
+		t.Errorf("DecryptCreds() = %+v, want original creds", got)
+	}
+}
+
+func TestDecryptCreds_WrongScopeOrKey(t *testing.T) {
+	key := make([]byte, 32)
+	enc, err := EncryptCreds([]*TeamCreds{{Username: "bob"}}, key, "ctf2025/abc")
+	if err != nil {
+		t.Fatalf("EncryptCreds() failed: %v", err)
+	}
+
+	if _, err := DecryptCreds(enc, key, "other/abc"); err == nil {
+		t.Error("DecryptCreds() should fail for a different scope")
+	}
+
+	otherKey := make([]byte, 32)
+	otherKey[0] = 1
+	if _, err := DecryptCreds(enc, otherKey, "ctf2025/abc"); err == nil {
+		t.Error("DecryptCreds() should fail with a different key")
+	}
+}
+
+func TestLoadCredsKey_CreatesKeyFile(t *testing.T) {
+	t.Setenv(CredsKeyEnv, "")
+	keyPath := filepath.Join(t.TempDir(), "gzcli", "creds.key")
+
+	orig := CredsKeyPath
+	CredsKeyPath = func() (string, error) { return keyPath, nil }
+	defer func() { CredsKeyPath = orig }()
+
+	first, err := LoadCredsKey()
+	if err != nil {
+		t.Fatalf("LoadCredsKey() failed: %v", err)
+	}
+	info, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatalf("key file was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode = %o, want 0600", info.Mode().Perm())
+	}
+
+	second, err := LoadCredsKey()
+	if err != nil {
+		t.Fatalf("LoadCredsKey() second call failed: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Error("LoadCredsKey() should return the persisted key")
+	}
+}
+
+func TestLoadCredsKey_FromEnv(t *testing.T) {
+	t.Setenv(CredsKeyEnv, "passphrase")
+
+	key, err := LoadCredsKey()
+	if err != nil {
+		t.Fatalf("LoadCredsKey() failed: %v", err)
+	}
+	if len(key) != 32 {
+		t.Errorf("len(key) = %d, want 32", len(key))
+	}
+}
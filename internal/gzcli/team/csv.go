@@ -54,18 +54,29 @@ func GetData(source string) ([]byte, error) {
 	return output, nil
 }
 
-// ParseCSV parses CSV data and creates teams
-func ParseCSV(data []byte, config ConfigInterface, teamConfig *Config, credsCache []*TeamCreds, isSendEmail bool, createTeamFunc func(*TeamCreds, ConfigInterface, map[string]struct{}, map[string]struct{}, []*TeamCreds, bool, func(string, int, map[string]struct{}) (string, error)) (*TeamCreds, error), generateUsername func(string, int, map[string]struct{}) (string, error), setCache func(string, interface{}) error, communicationOptions ...CommunicationOptions) error {
+// Row is one team registration read from a CSV import
+type Row struct {
+	RealName string
+	Email    string
+	TeamName string
+	Events   []string
+	Division string // Division (organization) to join the games in, if mapped
+}
+
+// ReadRows reads the team registrations of CSV data using the column mapping
+// of teamConfig. Malformed rows, rows without an email and repeated emails
+// are skipped.
+func ReadRows(data []byte, teamConfig *Config) ([]Row, error) {
 	reader := csv.NewReader(strings.NewReader(string(data)))
 
 	// Read all records
 	records, err := reader.ReadAll()
 	if err != nil {
-		return fmt.Errorf("failed to read CSV data: %v", err)
+		return nil, fmt.Errorf("failed to read CSV data: %v", err)
 	}
 
 	if len(records) == 0 {
-		return errors.New("CSV is empty")
+		return nil, errors.New("CSV is empty")
 	}
 
 	// Map to store the column indices for each header
@@ -86,26 +97,11 @@ func ParseCSV(data []byte, config ConfigInterface, teamConfig *Config, credsCach
 
 	for field, header := range requiredMappings {
 		if _, ok := colIndices[header]; !ok {
-			return fmt.Errorf("missing required header for %s: %s", field, header)
+			return nil, fmt.Errorf("missing required header for %s: %s", field, header)
 		}
 	}
 
-	// Maps for storing unique usernames and existing team names
-	uniqueUsernames := make(map[string]struct{})
-	existingTeamNames := make(map[string]struct{})
-
-	// Create a map for quick lookup of existing credentials by email
-	credsCacheMap := make(map[string]*TeamCreds)
-	for _, creds := range credsCache {
-		credsCacheMap[creds.Email] = creds
-	}
-
-	// List to hold the merged team credentials
-	teamsCreds := make([]*TeamCreds, 0, len(records)-1)
-	globalCommunication := CommunicationOptions{}
-	if len(communicationOptions) > 0 {
-		globalCommunication = communicationOptions[0]
-	}
+	rows := make([]Row, 0, len(records)-1)
 	seenEmails := make(map[string]struct{})
 
 	for _, row := range records[1:] {
@@ -146,14 +142,51 @@ func ParseCSV(data []byte, config ConfigInterface, teamConfig *Config, credsCach
 			}
 		}
 
+		var division string
+		if idx, ok := colIndices[teamConfig.ColumnMapping.Division]; ok && teamConfig.ColumnMapping.Division != "" {
+			division = strings.TrimSpace(row[idx])
+		}
+
+		rows = append(rows, Row{RealName: realName, Email: email, TeamName: teamName, Events: events, Division: division})
+	}
+
+	return rows, nil
+}
+
+// ParseCSV parses CSV data and creates teams
+func ParseCSV(data []byte, config ConfigInterface, teamConfig *Config, credsCache []*TeamCreds, isSendEmail bool, createTeamFunc func(*TeamCreds, ConfigInterface, map[string]struct{}, map[string]struct{}, []*TeamCreds, bool, func(string, int, map[string]struct{}) (string, error)) (*TeamCreds, error), generateUsername func(string, int, map[string]struct{}) (string, error), setCache func(string, interface{}) error, communicationOptions ...CommunicationOptions) error {
+	rows, err := ReadRows(data, teamConfig)
+	if err != nil {
+		return err
+	}
+
+	// Maps for storing unique usernames and existing team names
+	uniqueUsernames := make(map[string]struct{})
+	existingTeamNames := make(map[string]struct{})
+
+	// Create a map for quick lookup of existing credentials by email
+	credsCacheMap := make(map[string]*TeamCreds)
+	for _, creds := range credsCache {
+		credsCacheMap[creds.Email] = creds
+	}
+
+	// List to hold the merged team credentials
+	teamsCreds := make([]*TeamCreds, 0, len(rows))
+	globalCommunication := CommunicationOptions{}
+	if len(communicationOptions) > 0 {
+		globalCommunication = communicationOptions[0]
+	}
+
+	for _, row := range rows {
 		// Create or update team and user based on the generated username
 		creds, err := createTeamFunc(&TeamCreds{
-			Username:          realName,
-			Email:             email,
-			TeamName:          teamName,
+			Username:          row.RealName,
+			Email:             row.Email,
+			TeamName:          row.TeamName,
 			CommunicationType: globalCommunication.Type,
 			CommunicationLink: globalCommunication.Link,
-			Events:            events,
+			Events:            row.Events,
+			Division:          row.Division,
 		}, config, existingTeamNames, uniqueUsernames, credsCache, isSendEmail, generateUsername)
 		if creds != nil {
 			// Merge credentials if already exist in cache
@@ -164,6 +197,7 @@ func ParseCSV(data []byte, config ConfigInterface, teamConfig *Config, credsCach
 				existingCreds.TeamName = creds.TeamName
 				existingCreds.CommunicationType = creds.CommunicationType
 				existingCreds.CommunicationLink = creds.CommunicationLink
+				existingCreds.Division = creds.Division
 			} else {
 				// Add new credentials to the list
 				teamsCreds = append(teamsCreds, creds)
@@ -1,12 +1,19 @@
 package team
 
 import (
+	"bytes"
 	"fmt"
+	"html/template"
+	"os"
 	"strings"
-
-	"gopkg.in/gomail.v2"
 )
 
+// defaultEmailSubject is the subject of credential emails unless configured
+const defaultEmailSubject = "Your Team Credentials"
+
+// defaultEmailColor is the accent color of the default email without branding
+const defaultEmailColor = "#2563eb"
+
 // DetectCommunicationType infers the platform name from a communication link.
 func DetectCommunicationType(link string) string {
 	lower := strings.ToLower(strings.TrimSpace(link))
@@ -31,27 +38,48 @@ func DetectCommunicationType(link string) string {
 	}
 }
 
-// GenerateEmailBody generates the HTML body for the email
-func GenerateEmailBody(realName, website string, creds *TeamCreds, isSolo bool) string {
-	modeLabel := "Team CTF"
-	modeInstructions := `
-		<p>After logging in, open the <strong>/teams</strong> page to copy your team invitation code.</p>
-		<p>Ask teammates to register first, then join from the <strong>/team</strong> page using that code.</p>
-		<p>Your team has already been joined to the event automatically. Go to <strong>/games</strong> to verify status and prepare.</p>
-	`
-
-	if isSolo {
-		modeLabel = "Solo CTF"
-		modeInstructions = `
-		<p>This event is configured as <strong>Solo CTF</strong>, so no team invitation code is required.</p>
-		<p>Your account has already been joined to the event automatically. Go to <strong>/games</strong> to verify status and prepare.</p>
-	`
+// EmailBranding customizes the default credentials email
+type EmailBranding struct {
+	Name   string // Shown above the greeting
+	Logo   string // Logo image URL
+	Color  string // Accent color of the button
+	Footer string // Text at the bottom of the email
+}
+
+// EmailData is what credential email templates are rendered with
+type EmailData struct {
+	RealName          string
+	Website           string
+	Username          string
+	Password          string
+	Email             string
+	TeamName          string
+	Solo              bool
+	CommunicationType string
+	CommunicationLink string
+	CommunicationURL  string
+	Branding          EmailBranding
+}
+
+// NewEmailData returns the data of the credentials email of creds
+func NewEmailData(realName, website string, creds *TeamCreds, isSolo bool, branding EmailBranding) EmailData {
+	data := EmailData{
+		RealName: realName,
+		Website:  website,
+		Username: creds.Username,
+		Password: creds.Password,
+		Email:    creds.Email,
+		TeamName: creds.TeamName,
+		Solo:     isSolo,
+		Branding: branding,
+	}
+	if data.Branding.Color == "" {
+		data.Branding.Color = defaultEmailColor
 	}
 
-	communicationSection := ""
-	communicationType := strings.TrimSpace(creds.CommunicationType)
 	communicationLink := strings.TrimSpace(creds.CommunicationLink)
 	if communicationLink != "" {
+		communicationType := strings.TrimSpace(creds.CommunicationType)
 		if communicationType == "" {
 			communicationType = DetectCommunicationType(communicationLink)
 		}
@@ -64,15 +92,15 @@ func GenerateEmailBody(realName, website string, creds *TeamCreds, isSolo bool)
 			normalizedLink = "https://" + normalizedLink
 		}
 
-		communicationSection = fmt.Sprintf(
-			`<p><strong>%s:</strong> <a href="%s">%s</a></p>`,
-			communicationType,
-			normalizedLink,
-			communicationLink,
-		)
+		data.CommunicationType = communicationType
+		data.CommunicationLink = communicationLink
+		data.CommunicationURL = normalizedLink
 	}
+	return data
+}
 
-	return fmt.Sprintf(`
+// defaultEmailTemplate is the credentials email used without a custom template
+var defaultEmailTemplate = template.Must(template.New("email").Parse(`
 	<html>
 	<head>
 		<style>
@@ -93,6 +121,17 @@ func GenerateEmailBody(realName, website string, creds *TeamCreds, isSolo bool)
 				background-color: #ffffff;
 				box-shadow: 0 2px 8px rgba(0, 0, 0, 0.05);
 			}
+			.brand {
+				margin-top: 0;
+				margin-bottom: 16px;
+				color: #4b5563;
+				font-weight: 700;
+			}
+			.brand img {
+				max-height: 48px;
+				vertical-align: middle;
+				margin-right: 8px;
+			}
 			h1 {
 				color: #111827;
 				margin-top: 0;
@@ -135,71 +174,114 @@ func GenerateEmailBody(realName, website string, creds *TeamCreds, isSolo bool)
 				padding: 10px 18px;
 				text-decoration: none;
 				color: white;
-				background-color: #2563eb;
+				background-color: {{.Branding.Color}};
 				border-radius: 5px;
 				font-weight: 600;
 			}
 			.cta a:hover {
-				background-color: #1d4ed8;
+				opacity: 0.9;
+			}
+			.footer {
+				margin-top: 24px;
+				color: #6b7280;
+				font-size: 12px;
+				text-align: center;
 			}
 		</style>
 	</head>
 	<body>
 		<div class="block">
-		<h1>Hello %s,</h1>
+		{{- if or .Branding.Logo .Branding.Name}}
+		<p class="brand">{{with .Branding.Logo}}<img src="{{.}}" alt="">{{end}}{{.Branding.Name}}</p>
+		{{- end}}
+		<h1>Hello {{.RealName}},</h1>
 		<p class="subtitle">Your account has been created successfully.</p>
-		<div class="mode">%s</div>
+		<div class="mode">{{if .Solo}}Solo CTF{{else}}Team CTF{{end}}</div>
 		<div class="creds">
 			<p><strong>Credentials</strong></p>
-			<p><strong>Username:</strong> %s</p>
-			<p><strong>Password:</strong> %s</p>
-			<p><strong>Team Name:</strong> %s</p>
-			<p><strong>Website:</strong> <a href="%s">%s</a></p>
-			%s
+			<p><strong>Username:</strong> {{.Username}}</p>
+			<p><strong>Password:</strong> {{.Password}}</p>
+			<p><strong>Team Name:</strong> {{.TeamName}}</p>
+			<p><strong>Website:</strong> <a href="{{.Website}}">{{.Website}}</a></p>
+			{{- if .CommunicationLink}}
+			<p><strong>{{.CommunicationType}}:</strong> <a href="{{.CommunicationURL}}">{{.CommunicationLink}}</a></p>
+			{{- end}}
 		</div>
 		<div class="steps">
-			%s
+		{{- if .Solo}}
+			<p>This event is configured as <strong>Solo CTF</strong>, so no team invitation code is required.</p>
+			<p>Your account has already been joined to the event automatically. Go to <strong>/games</strong> to verify status and prepare.</p>
+		{{- else}}
+			<p>After logging in, open the <strong>/teams</strong> page to copy your team invitation code.</p>
+			<p>Ask teammates to register first, then join from the <strong>/team</strong> page using that code.</p>
+			<p>Your team has already been joined to the event automatically. Go to <strong>/games</strong> to verify status and prepare.</p>
+		{{- end}}
 		</div>
 		<p>If anything looks wrong, reply to this email so we can help quickly.</p>
 		<div class="cta">
-			<a href="%s">Go to Website</a>
+			<a href="{{.Website}}">Go to Website</a>
 		</div>
+		{{- with .Branding.Footer}}
+		<p class="footer">{{.}}</p>
+		{{- end}}
 		</div>
 	</body>
 	</html>
-	`,
-		realName, modeLabel, creds.Username, creds.Password, creds.TeamName, website, website, communicationSection, modeInstructions, website,
-	)
-}
+`))
 
-// SendEmail sends the team credentials to the specified email address using gomail
-func SendEmail(realName string, website string, creds *TeamCreds, appsettings AppSettingsInterface, isSolo bool) error {
-	emailConfig := appsettings.GetEmailConfig()
-	smtp := emailConfig.SMTP
-
-	// Extract the necessary fields from the emailConfig map
-	smtpHost := smtp.Host
-	smtpPort := smtp.Port
-	smtpUsername := emailConfig.UserName
-	smtpPassword := emailConfig.Password
+// RenderEmailBody renders the HTML credentials email of data with the
+// html/template file templatePath, or the default email if it is empty
+func RenderEmailBody(data EmailData, templatePath string) (string, error) {
+	tmpl := defaultEmailTemplate
+	if templatePath != "" {
+		//nolint:gosec // G304: Template path comes from user config
+		content, err := os.ReadFile(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read email template: %w", err)
+		}
+		tmpl, err = template.New("email").Parse(string(content))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse email template %s: %w", templatePath, err)
+		}
+	}
 
-	m := gomail.NewMessage()
-	m.SetHeader("From", smtpUsername)
-	m.SetHeader("To", creds.Email)
-	m.SetHeader("Subject", "Your Team Credentials")
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template: %w", err)
+	}
+	return buf.String(), nil
+}
 
-	htmlBody := GenerateEmailBody(realName, website, creds, isSolo)
+// GenerateEmailBody generates the HTML body for the email
+func GenerateEmailBody(realName, website string, creds *TeamCreds, isSolo bool) string {
+	body, err := RenderEmailBody(NewEmailData(realName, website, creds, isSolo, EmailBranding{}), "")
+	if err != nil {
+		return ""
+	}
+	return body
+}
 
-	// Set the email body as HTML
-	m.SetBody("text/html", htmlBody)
+// SendEmail sends the team credentials to the specified email address with
+// the configured provider
+func SendEmail(realName string, website string, creds *TeamCreds, appsettings AppSettingsInterface, isSolo bool) error {
+	emailConfig := appsettings.GetEmailConfig()
 
-	// Dial the SMTP server
-	d := gomail.NewDialer(smtpHost, smtpPort, smtpUsername, smtpPassword)
+	htmlBody, err := RenderEmailBody(NewEmailData(realName, website, creds, isSolo, emailConfig.Branding), emailConfig.Template)
+	if err != nil {
+		return err
+	}
 
-	// Send the email
-	if err := d.DialAndSend(m); err != nil {
-		return fmt.Errorf("failed to send email: %v", err)
+	mailer, err := NewMailer(emailConfig)
+	if err != nil {
+		return err
 	}
 
+	subject := emailConfig.Subject
+	if subject == "" {
+		subject = defaultEmailSubject
+	}
+	if err := mailer.Send(EmailMessage{To: creds.Email, Subject: subject, HTML: htmlBody}); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,272 @@
+package team
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+// Email providers
+const (
+	ProviderSMTP     = "smtp"
+	ProviderSendGrid = "sendgrid"
+	ProviderMailgun  = "mailgun"
+	ProviderSES      = "ses"
+)
+
+// EmailProviders are the providers credential emails can be delivered with
+var EmailProviders = []string{ProviderSMTP, ProviderSendGrid, ProviderMailgun, ProviderSES}
+
+// defaultEmailRates are the emails per second sent through each provider
+// unless configured otherwise, below their usual account limits
+var defaultEmailRates = map[string]float64{
+	ProviderSMTP:     2,
+	ProviderSendGrid: 10,
+	ProviderMailgun:  5,
+	ProviderSES:      1,
+}
+
+// API endpoints of the providers, replaced in tests
+var (
+	sendGridURL     = "https://api.sendgrid.com/v3/mail/send"
+	mailgunURLs     = map[string]string{"us": "https://api.mailgun.net", "eu": "https://api.eu.mailgun.net"}
+	sesEndpointURL  = "https://email.%s.amazonaws.com"
+	emailHTTPClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+// EmailMessage is an HTML email to one recipient
+type EmailMessage struct {
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Mailer delivers emails through a provider
+type Mailer interface {
+	Send(msg EmailMessage) error
+}
+
+// NewMailer returns the mailer of the provider of conf, SMTP by default,
+// holding it to the provider's rate limit. API keys and AWS credentials
+// missing from conf are read from the environment.
+func NewMailer(conf EmailConfig) (Mailer, error) {
+	provider := strings.ToLower(strings.TrimSpace(conf.Provider))
+	if provider == "" {
+		provider = ProviderSMTP
+	}
+
+	from := conf.From
+	if from == "" {
+		from = conf.UserName
+	}
+	sender := (&mail.Address{Name: conf.FromName, Address: from}).String()
+	apiKey := firstNonEmpty(conf.APIKey, os.Getenv("GZCLI_EMAIL_API_KEY"))
+
+	var mailer Mailer
+	switch provider {
+	case ProviderSMTP:
+		if conf.SMTP.Host == "" {
+			return nil, fmt.Errorf("smtp: no SMTP host in the EmailConfig of appsettings.json")
+		}
+		mailer = &smtpMailer{conf: conf, from: sender}
+	case ProviderSendGrid:
+		if apiKey == "" {
+			return nil, fmt.Errorf("sendgrid: missing apiKey")
+		}
+		mailer = &sendGridMailer{apiKey: apiKey, from: from, fromName: conf.FromName}
+	case ProviderMailgun:
+		if apiKey == "" || conf.Domain == "" {
+			return nil, fmt.Errorf("mailgun: missing apiKey or domain")
+		}
+		base, ok := mailgunURLs[strings.ToLower(firstNonEmpty(conf.Region, "us"))]
+		if !ok {
+			return nil, fmt.Errorf("mailgun: unknown region %q, must be us or eu", conf.Region)
+		}
+		mailer = &mailgunMailer{baseURL: base, apiKey: apiKey, domain: conf.Domain, from: sender}
+	case ProviderSES:
+		ses := &sesMailer{
+			region:       firstNonEmpty(conf.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+			accessKey:    firstNonEmpty(conf.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID")),
+			secretKey:    firstNonEmpty(conf.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+			from:         sender,
+		}
+		if ses.region == "" || ses.accessKey == "" || ses.secretKey == "" {
+			return nil, fmt.Errorf("ses: missing region or AWS credentials")
+		}
+		mailer = ses
+	default:
+		return nil, fmt.Errorf("unknown email provider %q, must be one of %s", conf.Provider, strings.Join(EmailProviders, ", "))
+	}
+
+	if from == "" {
+		return nil, fmt.Errorf("%s: missing sender address", provider)
+	}
+
+	rate := conf.RateLimit
+	if rate <= 0 {
+		rate = defaultEmailRates[provider]
+	}
+	return &rateLimitedMailer{Mailer: mailer, provider: provider, rate: rate}, nil
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// mailRateLimiter spaces the emails sent through each provider, shared by
+// every mailer of the process
+type mailRateLimiter struct {
+	mu    sync.Mutex
+	next  map[string]time.Time
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+var mailLimiter = &mailRateLimiter{
+	next:  make(map[string]time.Time),
+	now:   time.Now,
+	sleep: time.Sleep,
+}
+
+// wait blocks until an email may be sent through provider at rate emails
+// per second
+func (l *mailRateLimiter) wait(provider string, rate float64) {
+	if rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := l.now()
+	slot := l.next[provider]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[provider] = slot.Add(time.Duration(float64(time.Second) / rate))
+	l.mu.Unlock()
+
+	if delay := slot.Sub(now); delay > 0 {
+		l.sleep(delay)
+	}
+}
+
+// rateLimitedMailer holds a mailer to the rate limit of its provider
+type rateLimitedMailer struct {
+	Mailer
+	provider string
+	rate     float64
+}
+
+func (m *rateLimitedMailer) Send(msg EmailMessage) error {
+	mailLimiter.wait(m.provider, m.rate)
+	return m.Mailer.Send(msg)
+}
+
+// smtpMailer sends emails through the SMTP server of appsettings.json
+type smtpMailer struct {
+	conf EmailConfig
+	from string
+}
+
+func (m *smtpMailer) Send(msg EmailMessage) error {
+	message := gomail.NewMessage()
+	message.SetHeader("From", m.from)
+	message.SetHeader("To", msg.To)
+	message.SetHeader("Subject", msg.Subject)
+	message.SetBody("text/html", msg.HTML)
+
+	d := gomail.NewDialer(m.conf.SMTP.Host, m.conf.SMTP.Port, m.conf.UserName, m.conf.Password)
+	if err := d.DialAndSend(message); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
+
+// sendGridMailer sends emails with the SendGrid v3 API
+type sendGridMailer struct {
+	apiKey   string
+	from     string
+	fromName string
+}
+
+func (m *sendGridMailer) Send(msg EmailMessage) error {
+	type address struct {
+		Email string `json:"email"`
+		Name  string `json:"name,omitempty"`
+	}
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{{"to": []address{{Email: msg.To}}}},
+		"from":             address{Email: m.from, Name: m.fromName},
+		"subject":          msg.Subject,
+		"content":          []map[string]string{{"type": "text/html", "value": msg.HTML}},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sendGridURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	return doEmailRequest(ProviderSendGrid, req)
+}
+
+// mailgunMailer sends emails with the Mailgun messages API
+type mailgunMailer struct {
+	baseURL string
+	apiKey  string
+	domain  string
+	from    string
+}
+
+func (m *mailgunMailer) Send(msg EmailMessage) error {
+	form := url.Values{
+		"from":    {m.from},
+		"to":      {msg.To},
+		"subject": {msg.Subject},
+		"html":    {msg.HTML},
+	}
+	endpoint := fmt.Sprintf("%s/v3/%s/messages", strings.TrimRight(m.baseURL, "/"), url.PathEscape(m.domain))
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("api", m.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doEmailRequest(ProviderMailgun, req)
+}
+
+// doEmailRequest sends a provider API request, failing on non-2xx responses
+// with the start of the body the provider explained the error in
+func doEmailRequest(provider string, req *http.Request) error {
+	resp, err := emailHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", provider, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s: %s", provider, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,236 @@
+package team
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// emailSettings serves a fixed EmailConfig
+type emailSettings EmailConfig
+
+func (e emailSettings) GetEmailConfig() EmailConfig { return EmailConfig(e) }
+
+// emailTestConfig is a mockConfig with its own email settings
+type emailTestConfig struct {
+	mockConfig
+	email EmailConfig
+}
+
+func (c *emailTestConfig) GetAppSettings() AppSettingsInterface { return emailSettings(c.email) }
+
+func TestSendGridMailer(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer SG.key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	defer func(orig string) { sendGridURL = orig }(sendGridURL)
+	sendGridURL = server.URL
+
+	mailer, err := NewMailer(EmailConfig{Provider: "SendGrid", APIKey: "SG.key", From: "ctf@example.com", FromName: "CTF", RateLimit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mailer.Send(EmailMessage{To: "alice@example.com", Subject: "Hi", HTML: "<p>x</p>"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got["subject"] != "Hi" || !strings.Contains(mustJSON(t, got["personalizations"]), "alice@example.com") {
+		t.Errorf("unexpected payload %v", got)
+	}
+}
+
+func TestMailgunMailer_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "api" || pass != "key-1" {
+			t.Errorf("basic auth = %s:%s", user, pass)
+		}
+		if r.URL.Path != "/v3/mg.example.com/messages" || r.FormValue("to") != "bob@example.com" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Form)
+		}
+		http.Error(w, `{"message":"to parameter is not a valid address"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+	defer func(orig map[string]string) { mailgunURLs = orig }(mailgunURLs)
+	mailgunURLs = map[string]string{"eu": server.URL}
+
+	mailer, err := NewMailer(EmailConfig{Provider: ProviderMailgun, APIKey: "key-1", Domain: "mg.example.com", Region: "EU", From: "ctf@example.com", RateLimit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mailer.Send(EmailMessage{To: "bob@example.com", Subject: "Hi", HTML: "x"})
+	if err == nil || !strings.Contains(err.Error(), "not a valid address") {
+		t.Errorf("Send() error = %v, want the provider's explanation", err)
+	}
+}
+
+func TestNewMailer_InvalidConfig(t *testing.T) {
+	t.Setenv("GZCLI_EMAIL_API_KEY", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	for _, conf := range []EmailConfig{
+		{Provider: "pigeon", From: "a@example.com"},
+		{From: "a@example.com"},
+		{Provider: ProviderSendGrid, From: "a@example.com"},
+		{Provider: ProviderMailgun, APIKey: "k", From: "a@example.com"},
+		{Provider: ProviderMailgun, APIKey: "k", Domain: "d", Region: "ap", From: "a@example.com"},
+		{Provider: ProviderSES, From: "a@example.com", Region: "us-east-1"},
+		{Provider: ProviderSendGrid, APIKey: "k"},
+	} {
+		if _, err := NewMailer(conf); err == nil {
+			t.Errorf("NewMailer(%+v) should fail", conf)
+		}
+	}
+}
+
+func TestSignSigV4(t *testing.T) {
+	// Example request of the AWS Signature Version 4 documentation
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	signSigV4(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "iam", now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSESMailer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/email/outbound-emails" || !strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/ses/aws4_request") {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"ToAddresses":["carol@example.com"]`) {
+			t.Errorf("unexpected body %s", body)
+		}
+		_, _ = w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+	defer func(orig string) { sesEndpointURL = orig }(sesEndpointURL)
+	sesEndpointURL = server.URL + "%.0s" // Drops the region
+
+	mailer, err := NewMailer(EmailConfig{Provider: ProviderSES, Region: "eu-west-1", AccessKeyID: "AK", SecretAccessKey: "SK", From: "ctf@example.com", RateLimit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mailer.Send(EmailMessage{To: "carol@example.com", Subject: "Hi", HTML: "x"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+}
+
+func TestMailRateLimiter(t *testing.T) {
+	now := time.Now()
+	var slept []time.Duration
+	l := &mailRateLimiter{
+		next:  make(map[string]time.Time),
+		now:   func() time.Time { return now },
+		sleep: func(d time.Duration) { slept = append(slept, d) },
+	}
+
+	l.wait(ProviderSES, 2)
+	l.wait(ProviderSES, 2)
+	l.wait(ProviderSES, 2)
+	l.wait(ProviderSendGrid, 2)
+
+	want := []time.Duration{500 * time.Millisecond, time.Second}
+	if len(slept) != len(want) || slept[0] != want[0] || slept[1] != want[1] {
+		t.Errorf("slept %v, want %v (providers are limited separately)", slept, want)
+	}
+}
+
+func TestRenderEmailBody_Branding(t *testing.T) {
+	creds := &TeamCreds{Username: "alice", Password: "p<w>", TeamName: "Team"}
+	data := NewEmailData("Alice", "https://ctf.example.com", creds, false, EmailBranding{Name: "Example CTF", Logo: "https://example.com/logo.png", Color: "#e11d48", Footer: "Sent by Example"})
+
+	body, err := RenderEmailBody(data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Example CTF", `src="https://example.com/logo.png"`, "#e11d48", "Sent by Example", "p&lt;w&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q", want)
+		}
+	}
+
+	tmpl := filepath.Join(t.TempDir(), "email.html")
+	if err := os.WriteFile(tmpl, []byte(`<p>{{.Branding.Name}}: {{.Username}} / {{if .Solo}}solo{{else}}team{{end}}</p>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	body, err = RenderEmailBody(data, tmpl)
+	if err != nil || body != "<p>Example CTF: alice / team</p>" {
+		t.Errorf("RenderEmailBody(custom) = %q, %v", body, err)
+	}
+
+	if _, err := RenderEmailBody(data, filepath.Join(t.TempDir(), "missing.html")); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+}
+
+func TestResendFailedEmails(t *testing.T) {
+	var recipients []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Personalizations []struct {
+				To []struct {
+					Email string `json:"email"`
+				} `json:"to"`
+			} `json:"personalizations"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		to := payload.Personalizations[0].To[0].Email
+		recipients = append(recipients, to)
+		if to == "bounce@example.com" {
+			http.Error(w, "blocked", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	defer func(orig string) { sendGridURL = orig }(sendGridURL)
+	sendGridURL = server.URL
+
+	creds := []*TeamCreds{
+		{Username: "sent", Email: "sent@example.com", IsEmailAlreadySent: true},
+		{Username: "never", Email: "never@example.com"},
+		{Username: "failed", Email: "failed@example.com", EmailError: "timeout"},
+		{Username: "bounce", Email: "bounce@example.com", EmailError: "timeout"},
+	}
+	config := &emailTestConfig{email: EmailConfig{Provider: ProviderSendGrid, APIKey: "k", From: "ctf@example.com", RateLimit: 1000}}
+
+	sent, failed := ResendFailedEmails(creds, config)
+	if sent != 1 || failed != 1 {
+		t.Fatalf("ResendFailedEmails() = %d sent, %d failed", sent, failed)
+	}
+	if strings.Join(recipients, ",") != "failed@example.com,bounce@example.com" {
+		t.Errorf("emails sent to %v, want only the failed ones", recipients)
+	}
+	if !creds[2].IsEmailAlreadySent || creds[2].EmailError != "" {
+		t.Errorf("resent credentials not recorded as sent: %+v", creds[2])
+	}
+	if creds[3].IsEmailAlreadySent || !strings.Contains(creds[3].EmailError, "blocked") {
+		t.Errorf("failed credentials must keep the new error: %+v", creds[3])
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,82 @@
+package team
+
+import (
+	"strings"
+)
+
+// Change pairs the cached credentials of an account with the CSV row that
+// no longer matches them
+type Change struct {
+	Current *TeamCreds
+	Row     Row
+}
+
+// PrunePlan is the difference between a CSV import and the accounts created
+// from earlier imports
+type PrunePlan struct {
+	Remove   []*TeamCreds // Accounts whose email is no longer in the CSV
+	Recreate []Change     // Accounts whose real name or team name changed in the CSV
+	Keep     []*TeamCreds // Accounts still matching the CSV
+	Missing  []Row        // Rows without an account yet, left to "gzcli team create"
+}
+
+// PlanPrune diffs the rows of a CSV import against the cached credentials of
+// the accounts created from earlier imports. Rows are matched to accounts by
+// email, case-insensitively.
+func PlanPrune(rows []Row, credsCache []*TeamCreds) *PrunePlan {
+	plan := &PrunePlan{}
+
+	rowsByEmail := make(map[string]Row, len(rows))
+	for _, row := range rows {
+		rowsByEmail[strings.ToLower(row.Email)] = row
+	}
+
+	cached := make(map[string]struct{}, len(credsCache))
+	for _, creds := range credsCache {
+		key := strings.ToLower(creds.Email)
+		cached[key] = struct{}{}
+
+		row, ok := rowsByEmail[key]
+		switch {
+		case !ok:
+			plan.Remove = append(plan.Remove, creds)
+		case !generatedFrom(creds.Username, strings.TrimSpace(row.RealName), maxUsernameLength) ||
+			!generatedFrom(creds.TeamName, sanitizeTeamName(row.TeamName), maxTeamNameLength):
+			plan.Recreate = append(plan.Recreate, Change{Current: creds, Row: row})
+		default:
+			plan.Keep = append(plan.Keep, creds)
+		}
+	}
+
+	for _, row := range rows {
+		if _, ok := cached[strings.ToLower(row.Email)]; !ok {
+			plan.Missing = append(plan.Missing, row)
+		}
+	}
+
+	return plan
+}
+
+// generatedFrom reports whether name could have been generated from source:
+// source truncated to maxLen, possibly with a numeric suffix added for
+// uniqueness, as done for usernames and team names
+func generatedFrom(name, source string, maxLen int) bool {
+	base := source
+	if len(base) > maxLen {
+		base = base[:maxLen]
+	}
+	if name == base {
+		return true
+	}
+
+	for i := len(name) - 1; i > 0 && name[i] >= '0' && name[i] <= '9'; i-- {
+		prefix, suffix := name[:i], name[i:]
+		if prefix == base {
+			return true
+		}
+		if trimLen := maxLen - len(suffix); len(base)+len(suffix) > maxLen && trimLen >= 0 && trimLen <= len(base) && prefix == base[:trimLen] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package team
+
+import (
+	"testing"
+)
+
+func TestPlanPrune(t *testing.T) {
+	csvData := []byte(`RealName,Email,TeamName
+John Doe,john@example.com,Team1
+Jane Smith,JANE@example.com,Renamed Team
+Bob Builder,bob@example.com,Builders
+Alexander Hamilton The Third,alex@example.com,A Very Long Team Name Indeed`)
+
+	rows, err := ReadRows(csvData, &Config{ColumnMapping: ColumnMapping{RealName: "RealName", Email: "Email", TeamName: "TeamName"}})
+	if err != nil {
+		t.Fatalf("ReadRows() failed: %v", err)
+	}
+
+	john := &TeamCreds{Username: "John Doe", Email: "john@example.com", TeamName: "Team11"}
+	jane := &TeamCreds{Username: "Jane Smith", Email: "jane@example.com", TeamName: "Team2"}
+	alex := &TeamCreds{Username: "Alexander Hami1", Email: "alex@example.com", TeamName: "A Very Long Team Na2"}
+	gone := &TeamCreds{Username: "Gone", Email: "gone@example.com", TeamName: "Gone Team"}
+
+	plan := PlanPrune(rows, []*TeamCreds{john, jane, alex, gone})
+
+	if len(plan.Remove) != 1 || plan.Remove[0] != gone {
+		t.Errorf("Remove = %v, want [gone]", plan.Remove)
+	}
+	if len(plan.Recreate) != 1 || plan.Recreate[0].Current != jane || plan.Recreate[0].Row.TeamName != "Renamed Team" {
+		t.Errorf("Recreate = %+v, want jane with her new team", plan.Recreate)
+	}
+	if len(plan.Keep) != 2 || plan.Keep[0] != john || plan.Keep[1] != alex {
+		t.Errorf("Keep = %v, want [john alex] (suffixed and truncated names still match)", plan.Keep)
+	}
+	if len(plan.Missing) != 1 || plan.Missing[0].Email != "bob@example.com" {
+		t.Errorf("Missing = %v, want [bob]", plan.Missing)
+	}
+}
+
+func TestGeneratedFrom(t *testing.T) {
+	tests := []struct {
+		name, source string
+		maxLen       int
+		want         bool
+	}{
+		{"Team1", "Team1", 20, true},
+		{"Team12", "Team1", 20, true},
+		{"Team", "Team1", 20, false},
+		{"Tea1", "Team", 4, true},
+		{"Other", "Team", 20, false},
+		{"Team", "Team Renamed", 20, false},
+	}
+
+	for _, tt := range tests {
+		if got := generatedFrom(tt.name, tt.source, tt.maxLen); got != tt.want {
+			t.Errorf("generatedFrom(%q, %q, %d) = %v, want %v", tt.name, tt.source, tt.maxLen, got, tt.want)
+		}
+	}
+}
@@ -97,3 +97,66 @@ func TestJoinTeamToGame_MultiEvents(t *testing.T) {
 	// Note: We can't easily verify the exact API calls without a more complex mock or spy,
 	// but success implies no errors logged for joining.
 }
+
+func TestJoinTeamToGame_MovesDivision(t *testing.T) {
+	var organizations []string
+	var edit gzapi.ParticipationEditModel
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/account/login":
+			w.Write([]byte(`{"succeeded": true}`))
+		case "/api/team/":
+			json.NewEncoder(w).Encode([]gzapi.Team{{Id: 101, Name: "Alpha"}})
+		case "/api/edit/games/1":
+			if r.Method == http.MethodPut {
+				var game gzapi.Game
+				json.NewDecoder(r.Body).Decode(&game)
+				organizations = game.Organizations
+				return
+			}
+			w.Write([]byte(`{"id": 1, "title": "CTF", "organizations": ["Open"]}`))
+		case "/api/game/1":
+			// The team joined the game before
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"title": "Team already joined"}`))
+		case "/api/game/1/participations":
+			w.Write([]byte(`[{"id": 5, "team": {"id": 101, "name": "Alpha"}, "division": "Open"}]`))
+		case "/api/admin/participation/5":
+			json.NewDecoder(r.Body).Decode(&edit)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adminAPI, _ := gzapi.Init(server.URL, &gzapi.Creds{Username: "admin", Password: "password"})
+	config := &mockConfig{url: server.URL, eventId: 1, eventTitle: "CTF", adminApi: adminAPI}
+	cached := &TeamCreds{
+		Username:      "alice",
+		Password:      "secret",
+		Email:         "alice@example.com",
+		TeamName:      "Alpha",
+		IsTeamCreated: true,
+		Division:      "Open",
+	}
+	generateUsername := func(name string, _ int, _ map[string]struct{}) (string, error) {
+		return strings.ToLower(name), nil
+	}
+
+	creds, err := CreateTeamAndUser(&TeamCreds{Username: "Alice", Email: "alice@example.com", TeamName: "Alpha", Division: "Students"},
+		config, map[string]struct{}{}, map[string]struct{}{}, []*TeamCreds{cached}, false, generateUsername)
+	if err != nil {
+		t.Fatalf("CreateTeamAndUser() failed: %v", err)
+	}
+
+	if want := []string{"Open", "Students"}; strings.Join(organizations, ",") != strings.Join(want, ",") {
+		t.Errorf("Game divisions = %v, want %v", organizations, want)
+	}
+	if edit.Division != "Students" {
+		t.Errorf("Participation update = %+v, want the Students division", edit)
+	}
+	if creds.Division != "Students" {
+		t.Errorf("Cached division = %q, want Students", creds.Division)
+	}
+}
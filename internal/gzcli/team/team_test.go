@@ -222,6 +222,25 @@ Jane Smith,jane@example.com,Team2`)
 	}
 }
 
+func TestReadRows_Division(t *testing.T) {
+	csvData := []byte(`Name,Email,Team,Bracket
+John Doe,john@example.com,Team1, Students
+Jane Smith,jane@example.com,Team2,`)
+
+	rows, err := ReadRows(csvData, &Config{ColumnMapping: ColumnMapping{RealName: "Name", Email: "Email", TeamName: "Team", Division: "Bracket"}})
+	if err != nil {
+		t.Fatalf("ReadRows() failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Division != "Students" || rows[1].Division != "" {
+		t.Errorf("ReadRows() = %+v, want the Students division for the first row only", rows)
+	}
+
+	rows, err = ReadRows(csvData, &Config{ColumnMapping: ColumnMapping{RealName: "Name", Email: "Email", TeamName: "Team"}})
+	if err != nil || rows[0].Division != "" {
+		t.Errorf("ReadRows() without a division column = %+v, %v; want no division", rows, err)
+	}
+}
+
 func TestParseCSV_DuplicateEmailInCSV_SkipsDuplicateRow(t *testing.T) {
 	csvData := []byte(`RealName,Email,TeamName
 John Doe,john@example.com,Team1
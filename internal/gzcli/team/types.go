@@ -11,6 +11,8 @@ type TeamCreds struct {
 	CommunicationType  string   `json:"communication_type,omitempty" yaml:"communication_type,omitempty"`
 	CommunicationLink  string   `json:"communication_link,omitempty" yaml:"communication_link,omitempty"`
 	IsEmailAlreadySent bool     `json:"is_email_already_sent" yaml:"is_email_already_sent"`
+	EmailError         string   `json:"email_error,omitempty" yaml:"email_error,omitempty"`
 	IsTeamCreated      bool     `json:"is_team_created" yaml:"is_team_created"`
 	Events             []string `json:"events" yaml:"events"`
+	Division           string   `json:"division,omitempty" yaml:"division,omitempty"` // Division the team joins its games in
 }
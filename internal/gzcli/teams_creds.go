@@ -0,0 +1,107 @@
+package gzcli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/team"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// legacyTeamsCredsKey is the plaintext, global cache used before credentials
+// were encrypted and scoped per event and server
+const legacyTeamsCredsKey = "teams_creds"
+
+// teamsCredsScope identifies the credentials of one event on one GZCTF server
+func teamsCredsScope(eventName, serverURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(strings.ToLower(serverURL), "/")))
+	return fmt.Sprintf("%s/%s", eventName, hex.EncodeToString(sum[:])[:12])
+}
+
+// teamsCredsCacheKey returns the cache key holding the encrypted credentials
+func teamsCredsCacheKey(eventName, serverURL string) string {
+	return "teams_creds/" + teamsCredsScope(eventName, serverURL)
+}
+
+// LoadTeamsCreds decrypts the team credentials cached for an event and server.
+// A legacy plaintext cache is migrated to the encrypted store on first read.
+func LoadTeamsCreds(eventName, serverURL string) ([]*team.TeamCreds, error) {
+	key, err := team.LoadCredsKey()
+	if err != nil {
+		return nil, err
+	}
+
+	scope := teamsCredsScope(eventName, serverURL)
+	var enc team.EncryptedCreds
+	if err := GetCache(teamsCredsCacheKey(eventName, serverURL), &enc); err == nil {
+		return team.DecryptCreds(&enc, key, scope)
+	}
+
+	var legacy []*team.TeamCreds
+	if err := GetCache(legacyTeamsCredsKey, &legacy); err != nil {
+		return nil, fmt.Errorf("cache not found")
+	}
+
+	log.Info("Migrating plaintext team credentials cache to encrypted storage for event %s", eventName)
+	if err := SaveTeamsCreds(eventName, serverURL, legacy); err != nil {
+		return nil, fmt.Errorf("failed to migrate team credentials: %w", err)
+	}
+	if err := DeleteCache(legacyTeamsCredsKey); err != nil {
+		log.Error("Failed to remove plaintext team credentials cache: %v", err)
+	}
+	return legacy, nil
+}
+
+// SaveTeamsCreds encrypts and caches team credentials for an event and server
+func SaveTeamsCreds(eventName, serverURL string, creds []*team.TeamCreds) error {
+	key, err := team.LoadCredsKey()
+	if err != nil {
+		return err
+	}
+
+	enc, err := team.EncryptCreds(creds, key, teamsCredsScope(eventName, serverURL))
+	if err != nil {
+		return err
+	}
+	return setCache(teamsCredsCacheKey(eventName, serverURL), enc)
+}
+
+// TeamsCreds decrypts the team credentials cached for an event (the current
+// event when empty) and the configured server. Only the local cache is read:
+// the server is not contacted, so credentials stay readable while it is down.
+func TeamsCreds(eventName string) ([]*team.TeamCreds, error) {
+	eventName, err := config.GetCurrentEvent(eventName)
+	if err != nil {
+		return nil, err
+	}
+	serverConfig, err := config.GetServerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server config: %w", err)
+	}
+	return LoadTeamsCreds(eventName, serverConfig.Url)
+}
+
+// ResendFailedEmails emails again the team credentials of the current event
+// whose delivery failed before, and saves the outcome of each attempt
+func (gz *GZ) ResendFailedEmails() (sent int, failed int, err error) {
+	conf, err := config.GetConfigWithEvent(gz.api, gz.eventName, GetCache, setCache, deleteCacheWrapper, createNewGameWrapper)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get config: %w", err)
+	}
+	creds, err := LoadTeamsCreds(conf.EventName, conf.Url)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load team credentials: %w", err)
+	}
+	if len(team.FailedEmails(creds)) == 0 {
+		return 0, 0, nil
+	}
+
+	sent, failed = team.ResendFailedEmails(creds, &teamConfigAdapter{conf: conf, adminAPI: gz.api})
+	if err := SaveTeamsCreds(conf.EventName, conf.Url, creds); err != nil {
+		return sent, failed, fmt.Errorf("failed to save team credentials: %w", err)
+	}
+	return sent, failed, nil
+}
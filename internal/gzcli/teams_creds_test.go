@@ -0,0 +1,19 @@
+package gzcli
+
+import (
+	"testing"
+)
+
+func TestTeamsCredsScope_SeparatesEventsAndServers(t *testing.T) {
+	base := teamsCredsScope("ctf2025", "https://ctf.example.com")
+
+	if got := teamsCredsScope("ctf2025", "https://CTF.example.com/"); got != base {
+		t.Errorf("scope should ignore case and trailing slash: %s != %s", got, base)
+	}
+	if teamsCredsScope("ctf2026", "https://ctf.example.com") == base {
+		t.Error("different events must not share a scope")
+	}
+	if teamsCredsScope("ctf2025", "https://staging.example.com") == base {
+		t.Error("different servers must not share a scope")
+	}
+}
@@ -0,0 +1,97 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
+)
+
+// TestContainer is the test container of a challenge of the event on GZCTF
+type TestContainer struct {
+	Challenge config.ChallengeYaml
+	Remote    *gzapi.Challenge
+	Info      *gzapi.ContainerInfo
+}
+
+// testContainerChallenge returns the local and remote definitions of a
+// container challenge of the event, which must have been synced
+func (gz *GZ) testContainerChallenge(name string) (config.ChallengeYaml, *gzapi.Challenge, error) {
+	conf, err := config.GetConfigWithEvent(gz.api, gz.eventName, GetCache, setCache, deleteCacheWrapper, createNewGameWrapper)
+	if err != nil {
+		return config.ChallengeYaml{}, nil, fmt.Errorf("config error: %w", err)
+	}
+	challengesConf, err := config.GetChallengesYaml(conf)
+	if err != nil {
+		return config.ChallengeYaml{}, nil, fmt.Errorf("challenges config error: %w", err)
+	}
+	found, err := filterChallengeByName(challengesConf, name)
+	if err != nil {
+		return config.ChallengeYaml{}, nil, err
+	}
+	local := found[0]
+	if local.Type != "StaticContainer" && local.Type != "DynamicContainer" {
+		return local, nil, fmt.Errorf("challenge %q is a %s challenge, only container challenges have test containers", name, local.Type)
+	}
+
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return local, nil, fmt.Errorf("games fetch error: %w", err)
+	}
+	game := challenge.FindCurrentGame(games, conf.Event.Title, gz.api)
+	if game == nil {
+		return local, nil, fmt.Errorf("game '%s' not found, sync the event first", conf.Event.Title)
+	}
+	remote, err := game.GetChallenge(name)
+	if err != nil {
+		return local, nil, fmt.Errorf("challenge %q not found on GZCTF, sync it first: %w", name, err)
+	}
+	return local, remote, nil
+}
+
+// StartTestContainer starts the test container of a synced container
+// challenge of the event and waits until it runs
+func (gz *GZ) StartTestContainer(ctx context.Context, name string) (*TestContainer, error) {
+	local, remote, err := gz.testContainerChallenge(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := remote.StartTestContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &TestContainer{Challenge: local, Remote: remote, Info: info}, nil
+}
+
+// StopTestContainer destroys the test container of a synced container
+// challenge of the event
+func (gz *GZ) StopTestContainer(name string) error {
+	_, remote, err := gz.testContainerChallenge(name)
+	if err != nil {
+		return err
+	}
+	return remote.DestroyTestContainer()
+}
+
+// Verify runs the solver of the challenge against the test container
+func (tc *TestContainer) Verify(ctx context.Context, timeout time.Duration) (solver.VerifyResult, error) {
+	host, port, err := tc.Info.HostPort()
+	if err != nil {
+		return solver.VerifyResult{}, err
+	}
+	if _, ok := solver.Detect(tc.Challenge.Cwd); !ok {
+		return solver.VerifyResult{}, fmt.Errorf("challenge %q has no solver", tc.Challenge.Name)
+	}
+
+	target := solver.Target{
+		Host:       host,
+		Port:       port,
+		FlagFormat: solver.FlagFormat(tc.Challenge.Flags, tc.Challenge.Container.FlagTemplate),
+	}
+	return solver.Verify(ctx, tc.Challenge.Cwd, target, tc.Challenge.Flags, timeout), nil
+}
@@ -0,0 +1,260 @@
+package uploadserver
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultTokensPath is where the author tokens of the upload server are stored
+	DefaultTokensPath = ".gzcli/uploadserver/tokens.yaml"
+	// DefaultManifestPath is where the authors of installed challenges are recorded
+	DefaultManifestPath = ".gzcli/uploadserver/manifest.yaml"
+
+	tokenPrefix = "gzu_"
+)
+
+var (
+	errUnauthorized = errors.New("a valid upload token is required")
+	errNoTokens     = errors.New("uploads are closed until an upload token is issued")
+)
+
+// AuthorToken is an upload token issued to a challenge author. Only the
+// SHA-256 of the token is stored; the token itself is shown once on creation.
+type AuthorToken struct {
+	ID        string    `yaml:"id" json:"id"`
+	Author    string    `yaml:"author" json:"author"`
+	Hash      string    `yaml:"hash" json:"-"`
+	CreatedAt time.Time `yaml:"createdAt" json:"createdAt"`
+}
+
+// TokenStore manages the author tokens in a YAML file. The file is read on
+// each lookup so that tokens created or revoked apply to a running server.
+type TokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewTokenStore returns the token store at path, or at DefaultTokensPath
+// when path is empty
+func NewTokenStore(path string) *TokenStore {
+	if path == "" {
+		path = DefaultTokensPath
+	}
+	return &TokenStore{path: path}
+}
+
+// List returns the issued tokens
+func (s *TokenStore) List() ([]AuthorToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Create issues a token to author and returns it with its record
+func (s *TokenStore) Create(author string) (string, AuthorToken, error) {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return "", AuthorToken{}, errors.New("author is required")
+	}
+
+	secret := make([]byte, 24)
+	id := make([]byte, 4)
+	if _, err := rand.Read(secret); err != nil {
+		return "", AuthorToken{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+	if _, err := rand.Read(id); err != nil {
+		return "", AuthorToken{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := tokenPrefix + hex.EncodeToString(secret)
+	record := AuthorToken{
+		ID:        hex.EncodeToString(id),
+		Author:    author,
+		Hash:      hashToken(token),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return "", AuthorToken{}, err
+	}
+	if err := s.save(append(tokens, record)); err != nil {
+		return "", AuthorToken{}, err
+	}
+	return token, record, nil
+}
+
+// Revoke deletes the token with the given ID
+func (s *TokenStore) Revoke(id string) (AuthorToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return AuthorToken{}, err
+	}
+	for i, token := range tokens {
+		if token.ID == id {
+			if err := s.save(append(tokens[:i:i], tokens[i+1:]...)); err != nil {
+				return AuthorToken{}, err
+			}
+			return token, nil
+		}
+	}
+	return AuthorToken{}, fmt.Errorf("no upload token with ID %q", id)
+}
+
+// Authenticate returns the record of token. ok is false when the token is
+// unknown; required is false when no token is issued at all, in which case
+// uploads are refused unless the server allows anonymous uploads.
+func (s *TokenStore) Authenticate(token string) (record AuthorToken, ok, required bool, err error) {
+	tokens, err := s.List()
+	if err != nil {
+		return AuthorToken{}, false, true, err
+	}
+	if len(tokens) == 0 {
+		return AuthorToken{}, false, false, nil
+	}
+	if token == "" {
+		return AuthorToken{}, false, true, nil
+	}
+	hash := hashToken(token)
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hash)) == 1 {
+			return t, true, true, nil
+		}
+	}
+	return AuthorToken{}, false, true, nil
+}
+
+func (s *TokenStore) load() ([]AuthorToken, error) {
+	//nolint:gosec // G304: Path configured by the operator
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload tokens: %w", err)
+	}
+	var tokens []AuthorToken
+	if err := yaml.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse upload tokens %s: %w", s.path, err)
+	}
+	return tokens, nil
+}
+
+func (s *TokenStore) save(tokens []AuthorToken) error {
+	if tokens == nil {
+		tokens = []AuthorToken{}
+	}
+	data, err := yaml.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode upload tokens: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create upload tokens directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write upload tokens: %w", err)
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestToken returns the upload token of a request, from its bearer
+// Authorization header or the token form field of the upload page
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(r.FormValue("token"))
+}
+
+// ManifestEntry records who uploaded an installed challenge
+type ManifestEntry struct {
+	Event       string    `yaml:"event" json:"event"`
+	Category    string    `yaml:"category" json:"category"`
+	Challenge   string    `yaml:"challenge" json:"challenge"`
+	Directory   string    `yaml:"directory" json:"directory"`
+	Author      string    `yaml:"author" json:"author"`
+	TokenID     string    `yaml:"tokenId,omitempty" json:"tokenId,omitempty"`
+	FileName    string    `yaml:"fileName" json:"fileName"`
+	SHA256      string    `yaml:"sha256" json:"sha256"`
+	InstalledAt time.Time `yaml:"installedAt" json:"installedAt"`
+}
+
+// Manifest is the append-only record of installed challenges in a YAML file
+type Manifest struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewManifest returns the manifest at path, or at DefaultManifestPath when
+// path is empty
+func NewManifest(path string) *Manifest {
+	if path == "" {
+		path = DefaultManifestPath
+	}
+	return &Manifest{path: path}
+}
+
+// Entries returns the recorded installs, oldest first
+func (m *Manifest) Entries() ([]ManifestEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.load()
+}
+
+// Record appends an install to the manifest
+func (m *Manifest) Record(entry ManifestEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries, err := m.load()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(append(entries, entry))
+	if err != nil {
+		return fmt.Errorf("failed to encode upload manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create upload manifest directory: %w", err)
+	}
+	if err := os.WriteFile(m.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write upload manifest: %w", err)
+	}
+	return nil
+}
+
+func (m *Manifest) load() ([]ManifestEntry, error) {
+	//nolint:gosec // G304: Path configured by the operator
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload manifest: %w", err)
+	}
+	var entries []ManifestEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse upload manifest %s: %w", m.path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,147 @@
+package uploadserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTokenStore(t *testing.T) {
+	store := NewTokenStore(filepath.Join(t.TempDir(), "tokens.yaml"))
+
+	if _, _, required, err := store.Authenticate(""); err != nil || required {
+		t.Fatalf("Authenticate without tokens: required = %v, err = %v", required, err)
+	}
+
+	token, record, err := store.Create("alice")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(token, tokenPrefix) || record.ID == "" || record.Hash == token {
+		t.Fatalf("unexpected token %q with record %+v", token, record)
+	}
+	if _, _, err := store.Create(" "); err == nil {
+		t.Fatal("expected an error for an empty author")
+	}
+
+	got, ok, required, err := store.Authenticate(token)
+	if err != nil || !ok || !required || got.Author != "alice" {
+		t.Fatalf("Authenticate(token) = %+v, %v, %v, %v", got, ok, required, err)
+	}
+	if _, ok, required, _ := store.Authenticate("gzu_wrong"); ok || !required {
+		t.Fatalf("Authenticate(wrong) ok = %v, required = %v", ok, required)
+	}
+	if _, ok, _, _ := store.Authenticate(""); ok {
+		t.Fatal("expected a missing token to be rejected")
+	}
+
+	if _, err := store.Revoke("missing"); err == nil {
+		t.Fatal("expected an error revoking an unknown token")
+	}
+	if _, err := store.Revoke(record.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, _, required, _ := store.Authenticate(token); required {
+		t.Fatal("expected uploads to be open once every token is revoked")
+	}
+}
+
+func TestUploadJob_RequiresTokenAndRecordsAuthor(t *testing.T) {
+	const (
+		event    = "AuthEvent"
+		category = "Web"
+	)
+
+	_ = setupWorkspace(t, event, category)
+	token, record, err := NewTokenStore("").Create("alice")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{
+		ChallengeYAML: sampleChallengeYAML,
+		IncludeSolver: true,
+		SolverReadme:  "initial solver with enough content to pass the fifty bytes limit check................",
+		SrcFiles: map[string]string{
+			"README.md": "source file",
+		},
+	})
+
+	_, handler := startTestServer(t)
+	if rec := postArchive(t, handler, event, category, archive); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("POST /upload without token status = %d, want 401", rec.Code)
+	}
+
+	// The token of the upload page form
+	content, err := os.ReadFile(filepath.Clean(archive)) // #nosec G304 -- archive resides in a controlled temp directory
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	_ = mw.WriteField("event", event)
+	_ = mw.WriteField("category", category)
+	_ = mw.WriteField("token", token)
+	part, _ := mw.CreateFormFile("challenge", "challenge.zip")
+	_, _ = part.Write(content)
+	_ = mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /upload with token status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var queued uploadJob
+	if err := json.Unmarshal(rec.Body.Bytes(), &queued); err != nil || queued.Author != "alice" {
+		t.Fatalf("expected job by alice, got %s (%v)", rec.Body.String(), err)
+	}
+	job := waitForJob(t, handler, queued.ID)
+	if job.Status != jobSucceeded {
+		t.Fatalf("job status = %s, error = %s", job.Status, job.Error)
+	}
+
+	entries, err := NewManifest("").Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("manifest has %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Author != "alice" || entry.TokenID != record.ID || entry.Event != event || entry.Category != category ||
+		entry.Directory != "uploadsample" || len(entry.SHA256) != 64 {
+		t.Fatalf("unexpected manifest entry: %+v", entry)
+	}
+}
+
+func TestUpload_RefusedWithoutTokens(t *testing.T) {
+	const (
+		event    = "ClosedEvent"
+		category = "Web"
+	)
+
+	_ = setupWorkspace(t, event, category)
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{
+		ChallengeYAML: sampleChallengeYAML,
+		IncludeSolver: true,
+		SolverReadme:  "initial solver with enough content to pass the fifty bytes limit check................",
+		SrcFiles: map[string]string{
+			"README.md": "source file",
+		},
+	})
+
+	srv := newTestServer(t)
+	srv.opts.AllowAnonymous = false
+	rec := postArchive(t, srv.routes(), event, category, archive)
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), errNoTokens.Error()) {
+		t.Fatalf("POST /upload without any token issued = %d %s, want 401", rec.Code, rec.Body.String())
+	}
+}
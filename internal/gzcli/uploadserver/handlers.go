@@ -4,11 +4,16 @@ package uploadserver
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"mime/multipart"
 	"net/http"
+	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
@@ -28,7 +33,10 @@ type viewData struct {
 	Events      []string
 	Categories  []string
 	Templates   []templateInfo
+	EventRules  []eventRulesInfo
 	SuccessMsg  string
+	NeedToken   bool   // Uploads require an author token
+	JobID       string // Upload job whose progress the page follows
 	ErrorMsg    string
 	DefaultHost string
 	DefaultPort int
@@ -37,6 +45,15 @@ type viewData struct {
 	MaxEntry    string
 }
 
+// eventRulesInfo describes the upload rules an event sets for a category;
+// an empty category stands for every category without rules of its own
+type eventRulesInfo struct {
+	Event        string
+	Category     string
+	RequiredDirs []string
+	NamePattern  string
+}
+
 func (s *server) loadTemplates() error {
 	tmpl, err := template.New(templateHome).ParseFS(assetsFS, path.Join("assets", templateHomeFile))
 	if err != nil {
@@ -52,6 +69,8 @@ func (s *server) routes() http.Handler {
 
 	mux.HandleFunc("/", s.handleHome)
 	mux.HandleFunc("/upload", s.handleUpload)
+	mux.HandleFunc("/jobs/", s.handleJob)
+	mux.HandleFunc("/reviews/", s.handleReview)
 	mux.HandleFunc("/templates/", s.handleTemplateDownload)
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -84,35 +103,172 @@ func (s *server) handleUpload(w http.ResponseWriter, r *http.Request) {
 
 	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
 	if err := r.ParseMultipartForm(maxUploadBytes); err != nil { // #nosec G120 -- request body is bounded by MaxBytesReader above
-		data.ErrorMsg = friendlyError(err)
-		s.renderWithStatus(w, data, http.StatusBadRequest)
+		s.uploadError(w, r, data, friendlyError(err), http.StatusBadRequest)
+		return
+	}
+
+	author, ok, required, err := s.tokens.Authenticate(requestToken(r))
+	if err != nil {
+		log.Error("Failed to check upload token: %v", err)
+		s.uploadError(w, r, data, "failed to check upload token", http.StatusInternalServerError)
+		return
+	}
+	if !required && !s.opts.AllowAnonymous {
+		s.uploadError(w, r, data, errNoTokens.Error(), http.StatusUnauthorized)
+		return
+	}
+	if required && !ok {
+		s.uploadError(w, r, data, errUnauthorized.Error(), http.StatusUnauthorized)
 		return
 	}
 
 	event := strings.TrimSpace(r.FormValue("event"))
 	if s.opts.Event != "" && event != s.opts.Event {
-		data.ErrorMsg = fmt.Sprintf("upload restricted to event: %s", s.opts.Event)
-		s.renderWithStatus(w, data, http.StatusBadRequest)
+		s.uploadError(w, r, data, fmt.Sprintf("upload restricted to event: %s", s.opts.Event), http.StatusBadRequest)
 		return
 	}
 	category := strings.TrimSpace(r.FormValue("category"))
+	if _, _, err := resolveUploadTarget(event, category); err != nil {
+		s.uploadError(w, r, data, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	file, header, err := r.FormFile("challenge")
 	if err != nil {
-		data.ErrorMsg = "challenge ZIP is required"
-		s.renderWithStatus(w, data, http.StatusBadRequest)
+		s.uploadError(w, r, data, "challenge ZIP is required", http.StatusBadRequest)
 		return
 	}
 	defer func() { _ = file.Close() }()
 
-	if err := s.processUpload(r.Context(), event, category, file, header.Filename); err != nil {
-		data.ErrorMsg = err.Error()
-		s.renderWithStatus(w, data, http.StatusBadRequest)
+	job, err := s.queueUpload(event, category, file, header.Filename, author)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errQueueFull) {
+			status = http.StatusServiceUnavailable
+		}
+		s.uploadError(w, r, data, err.Error(), status)
+		return
+	}
+	if job.Author != "" {
+		log.Info("Queued upload job %s: %s for %s/%s by %s", job.ID, job.FileName, event, category, job.Author)
+	} else {
+		log.Info("Queued upload job %s: %s for %s/%s", job.ID, job.FileName, event, category)
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusAccepted, job)
+		return
+	}
+	data.SuccessMsg = fmt.Sprintf("Challenge upload queued as job %s.", job.ID)
+	data.JobID = job.ID
+	s.renderWithStatus(w, data, http.StatusAccepted)
+}
+
+// queueUpload stores an uploaded archive in its own temporary directory and
+// queues it for processing
+func (s *server) queueUpload(event, category string, file multipart.File, fileName string, author AuthorToken) (uploadJob, error) {
+	dir, err := os.MkdirTemp("", "gzcli-upload-job-*")
+	if err != nil {
+		return uploadJob{}, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := writeTempArchive(file, filepath.Join(dir, sanitizeFileName(fileName))); err != nil {
+		_ = os.RemoveAll(dir)
+		return uploadJob{}, err
+	}
+	job, err := s.jobs.enqueue(event, category, fileName, dir, author)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return uploadJob{}, err
+	}
+	return job, nil
+}
+
+// uploadError answers a rejected upload as JSON or with the home page
+func (s *server) uploadError(w http.ResponseWriter, r *http.Request, data viewData, msg string, status int) {
+	if wantsJSON(r) {
+		writeJSON(w, status, map[string]string{"error": msg})
+		return
+	}
+	data.ErrorMsg = msg
+	s.renderWithStatus(w, data, status)
+}
+
+// handleJob reports the status of an upload job at /jobs/{id}, or streams it
+// as server-sent events at /jobs/{id}/events until the job finishes
+func (s *server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+	job, _, ok := s.jobs.get(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch rest {
+	case "":
+		writeJSON(w, http.StatusOK, job)
+	case "events":
+		s.streamJob(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *server) streamJob(w http.ResponseWriter, r *http.Request, id string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
 		return
 	}
 
-	data.SuccessMsg = "Challenge uploaded successfully."
-	s.renderWithStatus(w, data, http.StatusOK)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	var last jobStatus
+	for {
+		job, changed, ok := s.jobs.get(id)
+		if !ok {
+			return
+		}
+		if job.Status != last {
+			payload, err := json.Marshal(job)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", payload); err != nil {
+				return
+			}
+			flusher.Flush()
+			last = job.Status
+		}
+		if job.Status.done() {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Failed writing JSON response: %v", err)
+	}
 }
 
 func (s *server) handleTemplateDownload(w http.ResponseWriter, r *http.Request) {
@@ -134,8 +290,23 @@ func (s *server) handleTemplateDownload(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Templates for an event include the directories its rules require
+	rules := config.UploadRules{RequiredDirs: config.DefaultUploadDirs}
+	if event := strings.TrimSpace(r.URL.Query().Get("event")); event != "" {
+		if s.opts.Event != "" && event != s.opts.Event {
+			http.Error(w, "templates restricted to event: "+s.opts.Event, http.StatusBadRequest)
+			return
+		}
+		profile, err := config.GetUploadProfile(event)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rules = profile.Rules(strings.TrimSpace(r.URL.Query().Get("category")))
+	}
+
 	buf := &bytes.Buffer{}
-	if err := writeTemplateArchive(buf, template); err != nil {
+	if err := writeTemplateArchive(buf, template, rules); err != nil {
 		log.Error("Failed generating template archive %s: %v", slug, err)
 		http.Error(w, "failed to build template", http.StatusInternalServerError)
 		return
@@ -159,17 +330,50 @@ func (s *server) baseViewData() viewData {
 		events = []string{s.opts.Event}
 	}
 
+	tokens, err := s.tokens.List()
+	if err != nil {
+		log.Error("Failed to list upload tokens: %v", err)
+	}
+
 	return viewData{
 		Title:       "GZCLI Challenge Upload Server",
 		Events:      events,
 		Categories:  config.CHALLENGE_CATEGORY,
 		Templates:   listTemplateInfo(),
+		EventRules:  listEventRules(events),
 		DefaultHost: s.opts.Host,
 		DefaultPort: s.opts.Port,
 		MaxUpload:   formatBytes(uint64(maxUploadBytes)),
 		MaxExtract:  formatBytes(maxExtractedBytes),
 		MaxEntry:    formatBytes(maxEntryBytes),
+		NeedToken:   err != nil || len(tokens) > 0,
+	}
+}
+
+// listEventRules returns the upload rules of the events customizing them
+func listEventRules(events []string) []eventRulesInfo {
+	var infos []eventRulesInfo
+	for _, event := range events {
+		profile, err := config.GetUploadProfile(event)
+		if err != nil {
+			log.Error("Failed to read upload rules of %s: %v", event, err)
+			continue
+		}
+		if profile.RequiredDirs != nil || profile.NamePattern != "" {
+			rules := profile.Rules("")
+			infos = append(infos, eventRulesInfo{Event: event, RequiredDirs: rules.RequiredDirs, NamePattern: rules.NamePattern})
+		}
+		categories := make([]string, 0, len(profile.Categories))
+		for category := range profile.Categories {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			rules := profile.Rules(category)
+			infos = append(infos, eventRulesInfo{Event: event, Category: category, RequiredDirs: rules.RequiredDirs, NamePattern: rules.NamePattern})
+		}
 	}
+	return infos
 }
 
 func (s *server) renderWithStatus(w http.ResponseWriter, data viewData, status int) {
@@ -0,0 +1,199 @@
+package uploadserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// jobStatus is the processing stage of an upload job
+type jobStatus string
+
+const (
+	jobQueued     jobStatus = "queued"
+	jobExtracting jobStatus = "extracting"
+	jobValidating jobStatus = "validating"
+	jobInstalling jobStatus = "installing"
+	jobSyncing    jobStatus = "syncing"
+	jobSucceeded  jobStatus = "succeeded"
+	jobFailed     jobStatus = "failed"
+)
+
+const (
+	defaultJobWorkers = 2
+	jobQueueSize      = 64
+	jobRetention      = time.Hour // Finished jobs are kept this long for polling
+)
+
+var errQueueFull = errors.New("upload queue is full, try again later")
+
+// done reports whether the job has finished
+func (s jobStatus) done() bool {
+	return s == jobSucceeded || s == jobFailed
+}
+
+// uploadJob is an uploaded archive waiting for or going through processing
+type uploadJob struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	Category  string    `json:"category"`
+	FileName  string    `json:"fileName"`
+	Author    string    `json:"author,omitempty"`
+	Status    jobStatus `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Sync      string    `json:"sync,omitempty"`      // Result of the automatic sync: succeeded or failed
+	SyncError string    `json:"syncError,omitempty"` // Why the automatic sync failed
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	dir     string // Temporary directory holding the archive until the job finishes
+	tokenID string // Upload token the archive was sent with
+}
+
+// archivePath returns where the uploaded archive of the job is stored
+func (j *uploadJob) archivePath() string {
+	return filepath.Join(j.dir, sanitizeFileName(j.FileName))
+}
+
+// jobQueue holds upload jobs and hands them to a pool of workers
+type jobQueue struct {
+	mu      sync.Mutex
+	jobs    map[string]*uploadJob
+	pending chan *uploadJob
+	changed chan struct{} // Closed and replaced whenever a job changes
+}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		jobs:    make(map[string]*uploadJob),
+		pending: make(chan *uploadJob, jobQueueSize),
+		changed: make(chan struct{}),
+	}
+}
+
+// start runs workers that process queued jobs with process until ctx is done
+func (q *jobQueue) start(ctx context.Context, workers int, process func(context.Context, *uploadJob, func(jobStatus)) error) {
+	if workers <= 0 {
+		workers = defaultJobWorkers
+	}
+	for range workers {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-q.pending:
+					q.run(ctx, job, process)
+				}
+			}
+		}()
+	}
+}
+
+func (q *jobQueue) run(ctx context.Context, job *uploadJob, process func(context.Context, *uploadJob, func(jobStatus)) error) {
+	defer func() { _ = os.RemoveAll(job.dir) }()
+
+	err := process(ctx, job, func(status jobStatus) { q.update(job.ID, status, "") })
+	if err != nil {
+		log.Error("Upload job %s (%s) failed: %v", job.ID, job.FileName, err)
+		q.update(job.ID, jobFailed, err.Error())
+		return
+	}
+	q.update(job.ID, jobSucceeded, "")
+}
+
+// enqueue registers a job for an archive stored in dir, uploaded by the
+// holder of author, and queues it
+func (q *jobQueue) enqueue(event, category, fileName, dir string, author AuthorToken) (uploadJob, error) {
+	now := time.Now()
+	job := &uploadJob{
+		ID:        newJobID(),
+		Event:     event,
+		Category:  category,
+		FileName:  fileName,
+		Author:    author.Author,
+		Status:    jobQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+		dir:       dir,
+		tokenID:   author.ID,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pruneLocked(now)
+	select {
+	case q.pending <- job:
+	default:
+		return uploadJob{}, errQueueFull
+	}
+	q.jobs[job.ID] = job
+	return *job, nil
+}
+
+// get returns a snapshot of a job and a channel closed on its next change
+func (q *jobQueue) get(id string) (uploadJob, <-chan struct{}, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return uploadJob{}, nil, false
+	}
+	return *job, q.changed, true
+}
+
+func (q *jobQueue) update(id string, status jobStatus, errMsg string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return
+	}
+	job.Status = status
+	job.Error = errMsg
+	job.UpdatedAt = time.Now()
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// recordSync records the result of the automatic sync of a job's challenge
+func (q *jobQueue) recordSync(id string, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return
+	}
+	job.Sync, job.SyncError = string(jobSucceeded), ""
+	if err != nil {
+		job.Sync, job.SyncError = string(jobFailed), err.Error()
+	}
+	job.UpdatedAt = time.Now()
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// pruneLocked drops finished jobs past the retention period
+func (q *jobQueue) pruneLocked(now time.Time) {
+	for id, job := range q.jobs {
+		if job.Status.done() && now.Sub(job.UpdatedAt) > jobRetention {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,220 @@
+package uploadserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postArchive uploads archive to the server as a JSON client
+func postArchive(t *testing.T, handler http.Handler, event, category, archive string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Clean(archive)) // #nosec G304 -- archive resides in a controlled temp directory
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	_ = mw.WriteField("event", event)
+	_ = mw.WriteField("category", category)
+	part, err := mw.CreateFormFile("challenge", "challenge.zip")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	_, _ = part.Write(content)
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// waitForJob polls /jobs/{id} until the job finishes
+func waitForJob(t *testing.T, handler http.Handler, id string) uploadJob {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/"+id, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /jobs/%s status = %d", id, rec.Code)
+		}
+		var job uploadJob
+		if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+			t.Fatalf("failed to decode job: %v", err)
+		}
+		if job.Status.done() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return uploadJob{}
+}
+
+func startTestServer(t *testing.T) (*server, http.Handler) {
+	t.Helper()
+	srv := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv.jobs.start(ctx, 1, srv.processJob)
+	return srv, srv.routes()
+}
+
+func TestUploadJob_Succeeds(t *testing.T) {
+	const (
+		event    = "JobEvent"
+		category = "Web"
+	)
+
+	workspace := setupWorkspace(t, event, category)
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{
+		ChallengeYAML: sampleChallengeYAML,
+		IncludeSolver: true,
+		SolverReadme:  "initial solver with enough content to pass the fifty bytes limit check................",
+		SrcFiles: map[string]string{
+			"README.md": "source file",
+		},
+	})
+
+	_, handler := startTestServer(t)
+	rec := postArchive(t, handler, event, category, archive)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /upload status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var queued uploadJob
+	if err := json.Unmarshal(rec.Body.Bytes(), &queued); err != nil || queued.ID == "" {
+		t.Fatalf("expected job in response, got %s (%v)", rec.Body.String(), err)
+	}
+
+	job := waitForJob(t, handler, queued.ID)
+	if job.Status != jobSucceeded {
+		t.Fatalf("job status = %s, error = %s", job.Status, job.Error)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, "events", event, category, "uploadsample", "challenge.yml")); err != nil {
+		t.Fatalf("expected installed challenge: %v", err)
+	}
+}
+
+func TestUploadJob_AutoSync(t *testing.T) {
+	const (
+		event    = "JobAutoSync"
+		category = "Web"
+	)
+
+	workspace := setupWorkspace(t, event, category)
+	if err := os.WriteFile(filepath.Join(workspace, "events", event, ".gzevent"), []byte("upload:\n  autoSync: true\n"), 0o600); err != nil {
+		t.Fatalf("failed to write .gzevent: %v", err)
+	}
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{
+		ChallengeYAML: sampleChallengeYAML,
+		IncludeSolver: true,
+		SolverReadme:  "initial solver with enough content to pass the fifty bytes limit check................",
+	})
+
+	srv, handler := startTestServer(t)
+	var synced []string
+	syncErr := errors.New("watcher unreachable")
+	srv.syncChallenge = func(event, challengeName string) error {
+		synced = append(synced, event+":"+challengeName)
+		return syncErr
+	}
+
+	upload := func() uploadJob {
+		t.Helper()
+		rec := postArchive(t, handler, event, category, archive)
+		var queued uploadJob
+		if err := json.Unmarshal(rec.Body.Bytes(), &queued); err != nil || queued.ID == "" {
+			t.Fatalf("expected job in response, got %s (%v)", rec.Body.String(), err)
+		}
+		return waitForJob(t, handler, queued.ID)
+	}
+
+	// A failed sync is reported without failing the install
+	job := upload()
+	if job.Status != jobSucceeded || job.Sync != "failed" || job.SyncError != syncErr.Error() {
+		t.Fatalf("job = %+v, want succeeded with a failed sync", job)
+	}
+	if len(synced) != 1 || synced[0] != event+":Web/uploadsample" {
+		t.Fatalf("synced = %v, want the installed challenge by its watcher key", synced)
+	}
+
+	syncErr = nil
+	if job := upload(); job.Sync != "succeeded" || job.SyncError != "" {
+		t.Fatalf("job = %+v, want a successful sync", job)
+	}
+}
+
+func TestUploadJob_ReportsValidationError(t *testing.T) {
+	const (
+		event    = "JobInvalid"
+		category = "Web"
+	)
+
+	_ = setupWorkspace(t, event, category)
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{
+		IncludeSolver: false,
+	})
+
+	_, handler := startTestServer(t)
+	rec := postArchive(t, handler, event, category, archive)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /upload status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var queued uploadJob
+	_ = json.Unmarshal(rec.Body.Bytes(), &queued)
+
+	job := waitForJob(t, handler, queued.ID)
+	if job.Status != jobFailed || !strings.Contains(job.Error, errMissingSolver.Error()) {
+		t.Fatalf("job = %+v, want failure with %q", job, errMissingSolver)
+	}
+
+	// The event stream of a finished job sends its final status and ends
+	stream := httptest.NewRecorder()
+	handler.ServeHTTP(stream, httptest.NewRequest(http.MethodGet, "/jobs/"+queued.ID+"/events", nil))
+	body, _ := io.ReadAll(stream.Body)
+	if !strings.Contains(string(body), "event: status") || !strings.Contains(string(body), `"status":"failed"`) {
+		t.Fatalf("unexpected event stream: %s", body)
+	}
+}
+
+func TestUploadJob_RejectsInvalidCategoryImmediately(t *testing.T) {
+	const event = "JobCategory"
+
+	_ = setupWorkspace(t, event, "Web")
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{IncludeSolver: true})
+
+	_, handler := startTestServer(t)
+	rec := postArchive(t, handler, event, "NotACategory", archive)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("POST /upload status = %d, want 400", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "invalid category") {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestJobEndpoint_UnknownJob(t *testing.T) {
+	_, handler := startTestServer(t)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,467 @@
+package uploadserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// DefaultReviewsPath is where the review state of uploaded challenges is stored
+const DefaultReviewsPath = ".gzcli/uploadserver/reviews.yaml"
+
+// Environment variables holding the SMTP credentials of review emails
+const (
+	SMTPUsernameEnv = "GZCLI_SMTP_USERNAME"
+	SMTPPasswordEnv = "GZCLI_SMTP_PASSWORD"
+)
+
+// reviewNotifyTimeout bounds the delivery of a review notification
+const reviewNotifyTimeout = 10 * time.Second
+
+// ReviewStatus is the review state of an uploaded challenge
+type ReviewStatus string
+
+// Review states. A new upload of a challenge puts it back to pending.
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+// ParseReviewStatus returns the review state named s
+func ParseReviewStatus(s string) (ReviewStatus, error) {
+	switch status := ReviewStatus(strings.ToLower(strings.TrimSpace(s))); status {
+	case ReviewPending, ReviewApproved, ReviewRejected:
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid review status %q: must be %s, %s or %s", s, ReviewPending, ReviewApproved, ReviewRejected)
+}
+
+// Review is the review of an uploaded challenge, identified by its
+// installed location as "event/category/directory"
+type Review struct {
+	ID         string                `yaml:"id" json:"id"`
+	Event      string                `yaml:"event" json:"event"`
+	Category   string                `yaml:"category" json:"category"`
+	Challenge  string                `yaml:"challenge" json:"challenge"`
+	Author     string                `yaml:"author,omitempty" json:"author,omitempty"`
+	Status     ReviewStatus          `yaml:"status" json:"status"`
+	Note       string                `yaml:"note,omitempty" json:"note,omitempty"`
+	Issues     []challenge.LintIssue `yaml:"issues,omitempty" json:"issues"`
+	Uploads    int                   `yaml:"uploads" json:"uploads"`
+	UploadedAt time.Time             `yaml:"uploadedAt" json:"uploadedAt"`
+	ReviewedAt *time.Time            `yaml:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+
+	// Key is the secret of the review link sent to reviewers
+	Key string `yaml:"key" json:"-"`
+}
+
+// reviewID returns the ID of the review of a challenge installed in directory
+func reviewID(event, category, directory string) string {
+	return event + "/" + category + "/" + directory
+}
+
+// ReviewStore tracks the reviews of uploaded challenges in a YAML file
+type ReviewStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewReviewStore returns the review store at path, or at
+// DefaultReviewsPath when path is empty
+func NewReviewStore(path string) *ReviewStore {
+	if path == "" {
+		path = DefaultReviewsPath
+	}
+	return &ReviewStore{path: path}
+}
+
+// List returns the reviews in the given state, or all of them when status
+// is empty, oldest upload first
+func (s *ReviewStore) List(status ReviewStatus) ([]Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reviews, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	filtered := reviews[:0]
+	for _, r := range reviews {
+		if status == "" || r.Status == status {
+			filtered = append(filtered, r)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].UploadedAt.Before(filtered[j].UploadedAt) })
+	return filtered, nil
+}
+
+// Get returns the review with the given ID
+func (s *ReviewStore) Get(id string) (Review, error) {
+	return s.find(func(r Review) bool { return r.ID == id })
+}
+
+// GetByKey returns the review whose link carries key
+func (s *ReviewStore) GetByKey(key string) (Review, error) {
+	if key == "" {
+		return Review{}, errors.New("no review key given")
+	}
+	return s.find(func(r Review) bool { return r.Key == key })
+}
+
+func (s *ReviewStore) find(match func(Review) bool) (Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reviews, err := s.load()
+	if err != nil {
+		return Review{}, err
+	}
+	for _, r := range reviews {
+		if match(r) {
+			return r, nil
+		}
+	}
+	return Review{}, errors.New("review not found")
+}
+
+// Submit records an upload of a challenge as pending review with its
+// validation issues, keeping the link key of an earlier upload
+func (s *ReviewStore) Submit(r Review) (Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reviews, err := s.load()
+	if err != nil {
+		return Review{}, err
+	}
+
+	r.Status = ReviewPending
+	r.Note = ""
+	r.ReviewedAt = nil
+	r.Uploads = 1
+	index := -1
+	for i, existing := range reviews {
+		if existing.ID == r.ID {
+			index = i
+			r.Key = existing.Key
+			r.Uploads = existing.Uploads + 1
+		}
+	}
+	if r.Key == "" {
+		key := make([]byte, 16)
+		if _, err := rand.Read(key); err != nil {
+			return Review{}, fmt.Errorf("failed to generate review key: %w", err)
+		}
+		r.Key = hex.EncodeToString(key)
+	}
+	if index >= 0 {
+		reviews[index] = r
+	} else {
+		reviews = append(reviews, r)
+	}
+	if err := s.save(reviews); err != nil {
+		return Review{}, err
+	}
+	return r, nil
+}
+
+// SetStatus records the decision of a reviewer on the review with the given ID
+func (s *ReviewStore) SetStatus(id string, status ReviewStatus, note string) (Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reviews, err := s.load()
+	if err != nil {
+		return Review{}, err
+	}
+	for i, r := range reviews {
+		if r.ID != id {
+			continue
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		r.Status = status
+		r.Note = strings.TrimSpace(note)
+		r.ReviewedAt = &now
+		if status == ReviewPending {
+			r.ReviewedAt = nil
+		}
+		reviews[i] = r
+		if err := s.save(reviews); err != nil {
+			return Review{}, err
+		}
+		return r, nil
+	}
+	return Review{}, fmt.Errorf("no review of %q", id)
+}
+
+func (s *ReviewStore) load() ([]Review, error) {
+	//nolint:gosec // G304: Path configured by the operator
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reviews: %w", err)
+	}
+	var reviews []Review
+	if err := yaml.Unmarshal(data, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to parse reviews %s: %w", s.path, err)
+	}
+	return reviews, nil
+}
+
+func (s *ReviewStore) save(reviews []Review) error {
+	data, err := yaml.Marshal(reviews)
+	if err != nil {
+		return fmt.Errorf("failed to encode reviews: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create reviews directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write reviews: %w", err)
+	}
+	return nil
+}
+
+// lintInstalled returns the lint issues of the challenge installed at
+// challengeFile, as the validation report sent to reviewers
+func lintInstalled(event, category, challengeFile string) []challenge.LintIssue {
+	file := challenge.LintFile{Path: challengeFile, Category: category}
+	chall, err := config.LoadChallengeFile(event, challengeFile)
+	if err != nil {
+		file.Err = err
+	} else {
+		file.Challenge = chall
+	}
+	report := challenge.Lint(event, []challenge.LintFile{file}, nil)
+	var issues []challenge.LintIssue
+	for _, result := range report.Results {
+		issues = append(issues, result.Issues...)
+	}
+	return issues
+}
+
+// ReviewNotification is the payload posted to the reviewer webhook
+type ReviewNotification struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"` // Summary, shown by Slack-compatible webhooks
+	Link   string `json:"link,omitempty"`
+	Review Review `json:"review"`
+}
+
+// smtpSendMail delivers review emails; replaced in tests
+var smtpSendMail = smtp.SendMail
+
+// reviewNotifier tells reviewers about new uploads by webhook and email
+type reviewNotifier struct {
+	webhook   string
+	smtpAddr  string
+	from      string
+	to        []string
+	publicURL string
+	client    *http.Client
+}
+
+// enabled reports whether any reviewer channel is configured
+func (n *reviewNotifier) enabled() bool {
+	return n.webhook != "" || len(n.to) > 0
+}
+
+// link returns the review page of r, if the server's public URL is known
+func (n *reviewNotifier) link(r Review) string {
+	if n.publicURL == "" {
+		return ""
+	}
+	return strings.TrimRight(n.publicURL, "/") + "/reviews/" + r.Key
+}
+
+// summary renders the review of an upload as plain text
+func (n *reviewNotifier) summary(r Review) string {
+	var b strings.Builder
+	author := r.Author
+	if author == "" {
+		author = "an unauthenticated uploader"
+	}
+	fmt.Fprintf(&b, "New upload of %q to %s/%s by %s (upload #%d).\n", r.Challenge, r.Event, r.Category, author, r.Uploads)
+	if len(r.Issues) == 0 {
+		b.WriteString("Validation report: no issues.\n")
+	} else {
+		b.WriteString("Validation report:\n")
+		for _, issue := range r.Issues {
+			fmt.Fprintf(&b, "  %s %s: %s\n", issue.Severity, issue.Check, issue.Message)
+		}
+	}
+	if link := n.link(r); link != "" {
+		fmt.Fprintf(&b, "Review it at %s\n", link)
+	}
+	fmt.Fprintf(&b, "Or run: gzcli upload-server review approve %s\n", r.ID)
+	return b.String()
+}
+
+// notify sends the review of an upload to every reviewer channel
+func (n *reviewNotifier) notify(ctx context.Context, r Review) error {
+	ctx, cancel := context.WithTimeout(ctx, reviewNotifyTimeout)
+	defer cancel()
+
+	var errs []error
+	if n.webhook != "" {
+		if err := n.postWebhook(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(n.to) > 0 {
+		if err := n.sendEmail(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *reviewNotifier) postWebhook(ctx context.Context, r Review) error {
+	body, err := json.Marshal(ReviewNotification{Type: "challenge_uploaded", Text: n.summary(r), Link: n.link(r), Review: r})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gzcli-upload-server")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// The URL holds the webhook secret; keep it out of logs
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("review webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("review webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (n *reviewNotifier) sendEmail(r Review) error {
+	host, _, _ := strings.Cut(n.smtpAddr, ":")
+	var auth smtp.Auth
+	if username := os.Getenv(SMTPUsernameEnv); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv(SMTPPasswordEnv), host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [gzcli] Review %s/%s: %s\r\n", r.Event, r.Category, strings.ReplaceAll(r.Challenge, "\n", " "))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(n.summary(r), "\n", "\r\n"))
+
+	if err := smtpSendMail(n.smtpAddr, auth, n.from, n.to, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to email reviewers: %w", err)
+	}
+	return nil
+}
+
+// requestReview puts an installed challenge up for review and notifies the
+// reviewers. Failures are logged: the challenge is already installed.
+func (s *server) requestReview(ctx context.Context, event, category, name, destination string, author AuthorToken) {
+	review, err := s.reviews.Submit(Review{
+		ID:         reviewID(event, category, filepath.Base(destination)),
+		Event:      event,
+		Category:   category,
+		Challenge:  name,
+		Author:     author.Author,
+		Issues:     lintInstalled(event, category, filepath.Join(destination, "challenge.yml")),
+		UploadedAt: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		log.Error("Failed to record review of challenge %q: %v", name, err)
+		return
+	}
+	if !s.notifier.enabled() {
+		return
+	}
+	if err := s.notifier.notify(ctx, review); err != nil {
+		log.Error("Failed to notify reviewers of challenge %q: %v", name, err)
+	}
+}
+
+// reviewPage is the page of the review link sent to reviewers
+var reviewPage = template.Must(template.New("review").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Review {{.Review.Challenge}}</title></head>
+<body style="font-family: sans-serif; max-width: 48rem; margin: 2rem auto">
+<h1>{{.Review.Challenge}}</h1>
+<p>{{.Review.Event}}/{{.Review.Category}}{{if .Review.Author}}, uploaded by {{.Review.Author}}{{end}} ({{.Review.Uploads}} upload(s), last {{.Review.UploadedAt.Format "2006-01-02 15:04:05"}} UTC)</p>
+<p>Status: <strong>{{.Review.Status}}</strong>{{if .Review.Note}}: {{.Review.Note}}{{end}}</p>
+<h2>Validation report</h2>
+{{if .Review.Issues}}<ul>{{range .Review.Issues}}<li>{{.Severity}} {{.Check}}: {{.Message}}</li>{{end}}</ul>{{else}}<p>No issues.</p>{{end}}
+<form method="post">
+<p><textarea name="note" rows="3" cols="60" placeholder="Note for the author"></textarea></p>
+<button name="status" value="approved">Approve</button>
+<button name="status" value="rejected">Reject</button>
+</form>
+</body></html>
+`))
+
+// handleReview shows the review behind a review link at /reviews/{key}, and
+// records the reviewer's decision when posted
+func (s *server) handleReview(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/reviews/")
+	review, err := s.reviews.GetByKey(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		status, err := ParseReviewStatus(r.FormValue("status"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review, err = s.reviews.SetStatus(review.ID, status, r.FormValue("note"))
+		if err != nil {
+			log.Error("Failed to record review of %s: %v", review.ID, err)
+			http.Error(w, "failed to record review", http.StatusInternalServerError)
+			return
+		}
+		log.Info("Review of %s set to %s", review.ID, review.Status)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, review)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := reviewPage.Execute(w, struct{ Review Review }{review}); err != nil {
+		log.Error("Template render error: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package uploadserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUploadJob_NotifiesReviewers(t *testing.T) {
+	const (
+		event    = "ReviewEvent"
+		category = "Web"
+	)
+
+	_ = setupWorkspace(t, event, category)
+
+	var mu sync.Mutex
+	var notifications []ReviewNotification
+	webhook := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var n ReviewNotification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("failed to decode notification: %v", err)
+		}
+		mu.Lock()
+		notifications = append(notifications, n)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	var emails []string
+	origSend := smtpSendMail
+	smtpSendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.test:25" || from != "gzcli@test" || len(to) != 1 || to[0] != "reviewer@test" {
+			t.Errorf("unexpected email envelope %s %s %v", addr, from, to)
+		}
+		mu.Lock()
+		emails = append(emails, string(msg))
+		mu.Unlock()
+		return nil
+	}
+	t.Cleanup(func() { smtpSendMail = origSend })
+
+	srv, err := newServer(Options{
+		Host:          "localhost",
+		Port:          8090,
+		ReviewWebhook: webhook.URL,
+		ReviewEmails:  []string{"reviewer@test"},
+		SMTPAddr:      "smtp.test:25",
+		SMTPFrom:      "gzcli@test",
+		PublicURL:     "https://upload.test",
+
+		AllowAnonymous: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv.jobs.start(ctx, 1, srv.processJob)
+	handler := srv.routes()
+
+	archive := buildChallengeArchive(t, buildChallengeArchiveConfig{
+		ChallengeYAML: sampleChallengeYAML,
+		IncludeSolver: true,
+		SolverReadme:  "initial solver with enough content to pass the fifty bytes limit check................",
+		SrcFiles: map[string]string{
+			"README.md": "source file",
+		},
+	})
+	upload := func() {
+		t.Helper()
+		rec := postArchive(t, handler, event, category, archive)
+		var queued uploadJob
+		_ = json.Unmarshal(rec.Body.Bytes(), &queued)
+		if job := waitForJob(t, handler, queued.ID); job.Status != jobSucceeded {
+			t.Fatalf("job status = %s, error = %s", job.Status, job.Error)
+		}
+	}
+	upload()
+
+	id := reviewID(event, category, "uploadsample")
+	review, err := srv.reviews.Get(id)
+	if err != nil {
+		t.Fatalf("Get review: %v", err)
+	}
+	if review.Status != ReviewPending || review.Uploads != 1 || review.Key == "" {
+		t.Fatalf("unexpected review %+v", review)
+	}
+
+	mu.Lock()
+	if len(notifications) != 1 || len(emails) != 1 {
+		t.Fatalf("got %d notification(s) and %d email(s), want 1 each", len(notifications), len(emails))
+	}
+	link := "https://upload.test/reviews/" + review.Key
+	if n := notifications[0]; n.Type != "challenge_uploaded" || n.Link != link || n.Review.ID != id || !strings.Contains(n.Text, "approve "+id) {
+		t.Fatalf("unexpected notification %+v", n)
+	}
+	if !strings.Contains(emails[0], "Subject: [gzcli] Review "+event+"/"+category) || !strings.Contains(emails[0], link) {
+		t.Fatalf("unexpected email:\n%s", emails[0])
+	}
+	mu.Unlock()
+
+	// The review link shows and decides the review
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reviews/wrong", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET unknown review status = %d, want 404", rec.Code)
+	}
+	form := url.Values{"status": {"approved"}, "note": {"looks good"}}
+	req := httptest.NewRequest(http.MethodPost, "/reviews/"+review.Key, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var decided Review
+	if err := json.Unmarshal(rec.Body.Bytes(), &decided); err != nil || decided.Status != ReviewApproved || decided.Note != "looks good" || decided.ReviewedAt == nil {
+		t.Fatalf("POST review = %d %s (%v)", rec.Code, rec.Body.String(), err)
+	}
+
+	// Uploading again puts the challenge back up for review
+	upload()
+	review, _ = srv.reviews.Get(id)
+	if review.Status != ReviewPending || review.Uploads != 2 || review.Note != "" {
+		t.Fatalf("unexpected review after a new upload %+v", review)
+	}
+	if pending, _ := srv.reviews.List(ReviewPending); len(pending) != 1 {
+		t.Fatalf("List(pending) = %d reviews, want 1", len(pending))
+	}
+	if approved, _ := srv.reviews.List(ReviewApproved); len(approved) != 0 {
+		t.Fatalf("List(approved) = %d reviews, want 0", len(approved))
+	}
+}
+
+func TestParseReviewStatus(t *testing.T) {
+	if status, err := ParseReviewStatus(" Approved "); err != nil || status != ReviewApproved {
+		t.Fatalf("ParseReviewStatus = %q, %v", status, err)
+	}
+	if _, err := ParseReviewStatus("done"); err == nil {
+		t.Fatal("expected an error for an unknown status")
+	}
+}
+
+func TestNewServer_ReviewEmailsNeedSMTP(t *testing.T) {
+	if _, err := newServer(Options{ReviewEmails: []string{"reviewer@test"}}); err == nil {
+		t.Fatal("expected an error without an SMTP server")
+	}
+}
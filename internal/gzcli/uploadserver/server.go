@@ -1,9 +1,11 @@
 package uploadserver
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/dimasma0305/gzcli/internal/log"
@@ -15,18 +17,64 @@ const (
 
 // Options configures the upload server runtime.
 type Options struct {
-	Host  string
-	Port  int
-	Event string
+	Host    string
+	Port    int
+	Event   string
+	Workers int // Uploads processed concurrently; 0 uses the default
+
+	TokensPath     string // Author tokens; empty uses DefaultTokensPath
+	AllowAnonymous bool   // Accept uploads without a token while no token exists
+	ManifestPath   string // Record of installed challenges; empty uses DefaultManifestPath
+	ReviewsPath    string // Review state of uploads; empty uses DefaultReviewsPath
+
+	ReviewWebhook string   // Webhook notified of uploads to review
+	ReviewEmails  []string // Reviewers emailed about uploads through SMTPAddr
+	SMTPAddr      string   // host:port of the SMTP server sending review emails
+	SMTPFrom      string   // Sender of review emails
+	PublicURL     string   // Base URL of review links; empty uses http://Host:Port
+
+	WatcherSocket string // Watcher socket syncing uploads of events with autoSync; empty uses the default
 }
 
 type server struct {
 	opts      Options
 	templates *template.Template
+	jobs      *jobQueue
+	tokens    *TokenStore
+	manifest  *Manifest
+	reviews   *ReviewStore
+	notifier  *reviewNotifier
+	installMu sync.Mutex
+
+	syncChallenge func(event, challengeName string) error // Syncs an installed challenge when its event enables autoSync
 }
 
 func newServer(opts Options) (*server, error) {
-	s := &server{opts: opts}
+	s := &server{
+		opts:     opts,
+		jobs:     newJobQueue(),
+		tokens:   NewTokenStore(opts.TokensPath),
+		manifest: NewManifest(opts.ManifestPath),
+		reviews:  NewReviewStore(opts.ReviewsPath),
+
+		syncChallenge: syncThroughWatcher(opts.WatcherSocket),
+	}
+
+	if len(opts.ReviewEmails) > 0 && (opts.SMTPAddr == "" || opts.SMTPFrom == "") {
+		return nil, fmt.Errorf("review emails need an SMTP server address and sender")
+	}
+	publicURL := opts.PublicURL
+	if publicURL == "" {
+		publicURL = fmt.Sprintf("http://%s:%d", opts.Host, opts.Port)
+	}
+	s.notifier = &reviewNotifier{
+		webhook:   opts.ReviewWebhook,
+		smtpAddr:  opts.SMTPAddr,
+		from:      opts.SMTPFrom,
+		to:        opts.ReviewEmails,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: reviewNotifyTimeout},
+	}
 
 	if err := ensureTemplatePaths(); err != nil {
 		return nil, fmt.Errorf("template assets unavailable: %w", err)
@@ -41,11 +89,28 @@ func newServer(opts Options) (*server, error) {
 
 // Run starts the upload server with the provided options.
 func Run(opts Options) error {
+	return RunContext(context.Background(), opts)
+}
+
+// RunContext starts the upload server and shuts it down gracefully when ctx is cancelled.
+func RunContext(ctx context.Context, opts Options) error {
 	srv, err := newServer(opts)
 	if err != nil {
 		return fmt.Errorf("failed to initialize upload server: %w", err)
 	}
 
+	if tokens, err := srv.tokens.List(); err != nil {
+		return err
+	} else if len(tokens) == 0 && opts.AllowAnonymous {
+		log.Info("⚠️  No upload tokens exist, anyone reaching the server can upload; create one with 'gzcli upload-server token create'")
+	} else if len(tokens) == 0 {
+		log.Info("⚠️  No upload tokens exist, uploads are refused until one is created with 'gzcli upload-server token create'")
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	srv.jobs.start(workerCtx, opts.Workers, srv.processJob)
+
 	addr := fmt.Sprintf("%s:%d", opts.Host, opts.Port)
 	httpServer := &http.Server{
 		Addr:              addr,
@@ -53,6 +118,22 @@ func Run(opts Options) error {
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
-	log.Info("Upload server listening on http://%s", addr)
-	return httpServer.ListenAndServe()
+	serverErrors := make(chan error, 1)
+	go func() {
+		log.Info("Upload server listening on http://%s", addr)
+		serverErrors <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErrors:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("upload server shutdown error: %w", err)
+		}
+		log.Info("Upload server stopped")
+		return nil
+	}
 }
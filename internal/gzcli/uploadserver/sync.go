@@ -0,0 +1,58 @@
+package uploadserver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// uploadSyncTimeout bounds the automatic sync of an uploaded challenge, which
+// may build and push its image
+const uploadSyncTimeout = 15 * time.Minute
+
+// syncThroughWatcher returns a function syncing a challenge of an event
+// through the watcher daemon listening on socketPath
+func syncThroughWatcher(socketPath string) func(event, challengeName string) error {
+	return func(event, challengeName string) error {
+		client := socket.NewClient(socketPath)
+		client.SetTimeout(uploadSyncTimeout)
+		response, err := client.SyncChallenge(event, challengeName)
+		if err != nil {
+			return fmt.Errorf("watcher unreachable, is 'gzcli watch start' running? %w", err)
+		}
+		if !response.Success {
+			return errors.New(response.Error)
+		}
+		return nil
+	}
+}
+
+// autoSync syncs the challenge a job installed into destination when its
+// event enables autoSync in its upload profile, and records the result on
+// the job. A failed sync leaves the challenge installed.
+func (s *server) autoSync(job *uploadJob, destination string, report func(jobStatus)) {
+	profile, err := config.GetUploadProfile(job.Event)
+	if err != nil {
+		log.Error("Failed to read upload profile of event %s: %v", job.Event, err)
+		return
+	}
+	if !profile.AutoSync {
+		return
+	}
+
+	report(jobSyncing)
+	// Challenges are watched by their category/directory key
+	challengeName := job.Category + "/" + filepath.Base(destination)
+	err = s.syncChallenge(job.Event, challengeName)
+	if err != nil {
+		log.Error("Failed to sync uploaded challenge %s of event %s: %v", challengeName, job.Event, err)
+	} else {
+		log.Info("Synced uploaded challenge %s of event %s", challengeName, job.Event)
+	}
+	s.jobs.recordSync(job.ID, err)
+}
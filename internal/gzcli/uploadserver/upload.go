@@ -31,6 +31,8 @@ var (
 	errInvalidRootContents        = errors.New("challenge root contains unexpected entries")
 	errMissingDist                = errors.New("dist directory missing from challenge package")
 	errMissingSrc                 = errors.New("src directory missing from challenge package")
+	errMissingRequiredDir         = errors.New("directory required by the event missing from challenge package")
+	errNamingConvention           = errors.New("challenge name does not follow the event's naming convention")
 	errEmptyDistProvided          = errors.New("dist directory is empty while challenge.yml provides it")
 	errChallengeTemplateUnchanged = errors.New("challenge.yml matches a default template")
 
@@ -47,81 +49,138 @@ const (
 
 // processUpload handles parsing, validating, and installing the uploaded challenge archive.
 func (s *server) processUpload(ctx context.Context, event, category string, file multipart.File, originalName string) error {
+	if _, _, err := resolveUploadTarget(event, category); err != nil {
+		return err
+	}
+
+	tempRoot, err := os.MkdirTemp("", "gzcli-upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tempRoot)
+	}()
+
+	archivePath := filepath.Join(tempRoot, sanitizeFileName(originalName))
+	if err := writeTempArchive(file, archivePath); err != nil {
+		return err
+	}
+
+	_, err = s.installArchive(ctx, event, category, archivePath, AuthorToken{}, func(jobStatus) {})
+	return err
+}
+
+// processJob installs the archive of a queued upload job, then syncs the
+// challenge if its event enables autoSync
+func (s *server) processJob(ctx context.Context, job *uploadJob, report func(jobStatus)) error {
+	destination, err := s.installArchive(ctx, job.Event, job.Category, job.archivePath(), AuthorToken{ID: job.tokenID, Author: job.Author}, report)
+	if err != nil {
+		return err
+	}
+	s.autoSync(job, destination, report)
+	return nil
+}
+
+// resolveUploadTarget checks the event and category of an upload and returns
+// the event directory and the upload rules that apply
+func resolveUploadTarget(event, category string) (string, config.UploadRules, error) {
 	event = strings.TrimSpace(event)
 	category = strings.TrimSpace(category)
 
 	if event == "" {
-		return errors.New("event selection is required")
+		return "", config.UploadRules{}, errors.New("event selection is required")
 	}
 	if category == "" {
-		return errors.New("category selection is required")
+		return "", config.UploadRules{}, errors.New("category selection is required")
 	}
 	if !isValidCategory(category) {
-		return fmt.Errorf("%w: %s", errInvalidCategory, category)
+		return "", config.UploadRules{}, fmt.Errorf("%w: %s", errInvalidCategory, category)
 	}
 
 	eventPath, err := config.GetEventPath(event)
 	if err != nil {
-		return fmt.Errorf("invalid event %q: %w", event, err)
+		return "", config.UploadRules{}, fmt.Errorf("invalid event %q: %w", event, err)
 	}
-
-	tempRoot, err := os.MkdirTemp("", "gzcli-upload-*")
+	profile, err := config.GetUploadProfile(event)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary directory: %w", err)
+		return "", config.UploadRules{}, err
 	}
-	defer func() {
-		_ = os.RemoveAll(tempRoot)
-	}()
+	return eventPath, profile.Rules(category), nil
+}
 
-	archivePath := filepath.Join(tempRoot, sanitizeFileName(originalName))
-	if err := writeTempArchive(file, archivePath); err != nil {
-		return err
+// installArchive extracts, validates and installs the challenge archive at
+// archivePath, reporting each stage it enters, and records the install with
+// its author in the manifest, putting it up for review. The archive is
+// extracted next to itself. It returns the directory of the installed
+// challenge.
+func (s *server) installArchive(ctx context.Context, event, category, archivePath string, author AuthorToken, report func(jobStatus)) (string, error) {
+	event = strings.TrimSpace(event)
+	category = strings.TrimSpace(category)
+
+	eventPath, rules, err := resolveUploadTarget(event, category)
+	if err != nil {
+		return "", err
 	}
 
-	extractDir := filepath.Join(tempRoot, "extracted")
+	report(jobExtracting)
+	extractDir := filepath.Join(filepath.Dir(archivePath), "extracted")
 	if err := extractArchive(ctx, archivePath, extractDir); err != nil {
-		return err
+		return "", err
 	}
 
 	challengeYMLPath, err := locateChallengeYML(extractDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	challengeRoot := filepath.Dir(challengeYMLPath)
 	var chall config.ChallengeYaml
 	if err := fileutil.ParseYamlFromFile(challengeYMLPath, &chall); err != nil {
-		return fmt.Errorf("failed to parse challenge.yml: %w", err)
+		return "", fmt.Errorf("failed to parse challenge.yml: %w", err)
 	}
 
-	if err := validateChallengeRoot(challengeRoot, challengeYMLPath, chall); err != nil {
-		return err
+	report(jobValidating)
+	if err := validateChallengeRoot(challengeRoot, challengeYMLPath, chall, rules); err != nil {
+		return "", err
+	}
+
+	if err := ensureNamingConvention(chall, rules); err != nil {
+		return "", err
 	}
 
 	if err := ensureChallengeCustomized(chall); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := challenge.IsGoodChallenge(chall); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := ensureProvideDistConsistency(challengeRoot, chall); err != nil {
-		return err
+		return "", err
 	}
 
-	if err := validateUploadChallenge(challengeRoot, chall); err != nil {
-		return err
+	if err := validateUploadChallenge(challengeRoot, chall, rules); err != nil {
+		return "", err
+	}
+
+	if err := validateAttachmentPolicy(event, challengeRoot, chall); err != nil {
+		return "", err
 	}
 
+	report(jobInstalling)
+	// Jobs of the same challenge must not replace its directory concurrently
+	s.installMu.Lock()
+	defer s.installMu.Unlock()
+
 	// Containment check: destCategoryDir must live beneath eventPath even
 	// after normalising the user-supplied category token.
 	destCategoryDir, err := safeJoin(eventPath, category)
 	if err != nil {
-		return fmt.Errorf("invalid category path: %w", err)
+		return "", fmt.Errorf("invalid category path: %w", err)
 	}
 	if err := os.MkdirAll(destCategoryDir, 0750); err != nil {
-		return fmt.Errorf("failed to ensure category directory: %w", err)
+		return "", fmt.Errorf("failed to ensure category directory: %w", err)
 	}
 
 	finalName := sanitizeChallengeDirName(chall.Name)
@@ -129,23 +188,48 @@ func (s *server) processUpload(ctx context.Context, event, category string, file
 		finalName = sanitizeChallengeDirName(filepath.Base(challengeRoot))
 	}
 	if finalName == "" {
-		return fmt.Errorf("unable to derive a safe challenge directory name")
+		return "", fmt.Errorf("unable to derive a safe challenge directory name")
 	}
 
 	destination, err := safeJoin(destCategoryDir, finalName)
 	if err != nil {
-		return fmt.Errorf("invalid challenge destination: %w", err)
+		return "", fmt.Errorf("invalid challenge destination: %w", err)
 	}
 	if err := os.RemoveAll(destination); err != nil {
-		return fmt.Errorf("failed to replace existing challenge: %w", err)
+		return "", fmt.Errorf("failed to replace existing challenge: %w", err)
 	}
 
 	if err := copyDir(challengeRoot, destination); err != nil {
-		return fmt.Errorf("failed to install challenge: %w", err)
+		return "", fmt.Errorf("failed to install challenge: %w", err)
 	}
 
 	log.Info("Installed challenge %q into %s/%s", chall.Name, event, category)
-	return nil
+	s.recordInstall(event, category, chall.Name, destination, archivePath, author)
+	s.requestReview(ctx, event, category, chall.Name, destination, author)
+	return destination, nil
+}
+
+// recordInstall appends an installed challenge to the manifest. The install
+// is kept when recording fails, since the challenge is already in place.
+func (s *server) recordInstall(event, category, name, destination, archivePath string, author AuthorToken) {
+	sum, err := fileutil.GetFileHashHex(archivePath)
+	if err != nil {
+		log.Error("Failed to hash uploaded archive %s: %v", archivePath, err)
+	}
+	entry := ManifestEntry{
+		Event:       event,
+		Category:    category,
+		Challenge:   name,
+		Directory:   filepath.Base(destination),
+		Author:      author.Author,
+		TokenID:     author.ID,
+		FileName:    filepath.Base(archivePath),
+		SHA256:      sum,
+		InstalledAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := s.manifest.Record(entry); err != nil {
+		log.Error("Failed to record author of challenge %q: %v", name, err)
+	}
 }
 
 func writeTempArchive(src multipart.File, dst string) error {
@@ -386,7 +470,10 @@ func ensureDirWritable(mode fs.FileMode) fs.FileMode {
 	return (mode | 0700) & fs.ModePerm
 }
 
-func validateChallengeRoot(root, challengeYMLPath string, chall config.ChallengeYaml) error {
+// validateChallengeRoot checks the entries of the challenge root: the
+// directories required by the event's rules must exist, and besides the
+// standard directories only required ones and known files are accepted
+func validateChallengeRoot(root, challengeYMLPath string, chall config.ChallengeYaml, rules config.UploadRules) error {
 	if filepath.Base(challengeYMLPath) != "challenge.yml" {
 		return fmt.Errorf("challenge definition file must be named challenge.yml")
 	}
@@ -396,37 +483,23 @@ func validateChallengeRoot(root, challengeYMLPath string, chall config.Challenge
 		return fmt.Errorf("failed to inspect challenge root: %w", err)
 	}
 
-	var (
-		hasChallenge bool
-		hasDist      bool
-		hasSolver    bool
-		hasSrc       bool
-	)
+	hasChallenge := false
+	dirs := make(map[string]bool)
 
 	for _, entry := range entries {
 		name := entry.Name()
-		switch name {
-		case "challenge.yml":
+		switch {
+		case name == "challenge.yml":
 			if entry.IsDir() {
 				return fmt.Errorf("challenge.yml must be a file, not a directory")
 			}
 			hasChallenge = true
-		case "dist":
-			if !entry.IsDir() {
-				return fmt.Errorf("dist exists but is not a directory")
-			}
-			hasDist = true
-		case "solver":
+		case name == "dist" || name == "solver" || name == "src" || rules.Requires(name):
 			if !entry.IsDir() {
-				return fmt.Errorf("solver exists but is not a directory")
+				return fmt.Errorf("%s exists but is not a directory", name)
 			}
-			hasSolver = true
-		case "src":
-			if !entry.IsDir() {
-				return fmt.Errorf("src exists but is not a directory")
-			}
-			hasSrc = true
-		case "docker-compose.yml", "Dockerfile", ".dockerignore", ".gitignore":
+			dirs[name] = true
+		case name == "docker-compose.yml" || name == "Dockerfile" || name == ".dockerignore" || name == ".gitignore":
 			// Allowed optional files
 		default:
 			if chall.Dashboard != nil && chall.Dashboard.Config == name {
@@ -439,23 +512,47 @@ func validateChallengeRoot(root, challengeYMLPath string, chall config.Challenge
 	if !hasChallenge {
 		return errNoChallengeYML
 	}
-	if !hasDist {
-		return errMissingDist
-	}
-	if !hasSrc {
-		return errMissingSrc
-	}
-	if !hasSolver {
-		return errMissingSolver
+	for _, dir := range rules.RequiredDirs {
+		if dirs[dir] {
+			continue
+		}
+		switch dir {
+		case "dist":
+			return errMissingDist
+		case "src":
+			return errMissingSrc
+		case "solver":
+			return errMissingSolver
+		default:
+			return fmt.Errorf("%w: %s", errMissingRequiredDir, dir)
+		}
 	}
 
-	if err := ensureSolverDir(root); err != nil {
-		return err
+	if rules.Requires("solver") {
+		if err := ensureSolverDir(root); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// ensureNamingConvention checks the challenge name against the event's
+// naming convention, if it sets one
+func ensureNamingConvention(chall config.ChallengeYaml, rules config.UploadRules) error {
+	if rules.NamePattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(rules.NamePattern)
+	if err != nil {
+		return fmt.Errorf("invalid naming convention %q: %w", rules.NamePattern, err)
+	}
+	if !re.MatchString(chall.Name) {
+		return fmt.Errorf("%w: %q does not match %s", errNamingConvention, chall.Name, rules.NamePattern)
+	}
+	return nil
+}
+
 func ensureChallengeCustomized(chall config.ChallengeYaml) error {
 	for _, tpl := range challengeTemplates {
 		templatePath := path.Join(tpl.SourcePath, "challenge.yml")
@@ -755,12 +852,13 @@ func copyFile(src, dst string, mode fs.FileMode, trustedRoot string, srcRoot str
 	return out.Sync()
 }
 
-// writeTemplateArchive packages the embedded challenge template into a ZIP archive.
-func writeTemplateArchive(w io.Writer, tpl challengeTemplate) error {
+// writeTemplateArchive packages the embedded challenge template into a ZIP archive,
+// adding empty directories for the ones required by rules that it lacks.
+func writeTemplateArchive(w io.Writer, tpl challengeTemplate, rules config.UploadRules) error {
 	zw := zip.NewWriter(w)
 	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	hasSolver := false
+	dirs := make(map[string]bool)
 
 	err := fs.WalkDir(templateFS, tpl.SourcePath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -779,11 +877,10 @@ func writeTemplateArchive(w io.Writer, tpl challengeTemplate) error {
 		}
 		zipPath := filepath.ToSlash(filepath.Join(tpl.Slug, rel))
 
-		if d.IsDir() {
-			if rel == "solver" {
-				hasSolver = true
-			}
+		topDir, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		dirs[topDir] = true
 
+		if d.IsDir() {
 			header := &zip.FileHeader{
 				Name:     zipPath + "/",
 				Method:   zip.Deflate,
@@ -794,8 +891,6 @@ func writeTemplateArchive(w io.Writer, tpl challengeTemplate) error {
 				return err
 			}
 			return nil
-		} else if strings.HasPrefix(rel, "solver/") {
-			hasSolver = true
 		}
 
 		info, err := d.Info()
@@ -835,16 +930,19 @@ func writeTemplateArchive(w io.Writer, tpl challengeTemplate) error {
 		return fmt.Errorf("failed to package template %s: %w", tpl.Slug, err)
 	}
 
-	if !hasSolver {
+	for _, dir := range rules.RequiredDirs {
+		if dirs[dir] {
+			continue
+		}
 		header := &zip.FileHeader{
-			Name:     filepath.ToSlash(filepath.Join(tpl.Slug, "solver")) + "/",
+			Name:     filepath.ToSlash(filepath.Join(tpl.Slug, dir)) + "/",
 			Method:   zip.Deflate,
 			Modified: timestamp,
 		}
 		header.SetMode(0755)
 		if _, err := zw.CreateHeader(header); err != nil {
 			_ = zw.Close()
-			return fmt.Errorf("failed to add solver directory: %w", err)
+			return fmt.Errorf("failed to add %s directory: %w", dir, err)
 		}
 	}
 
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
 )
 
@@ -263,12 +264,121 @@ func TestProcessUpload_DefaultChallengeYAML(t *testing.T) {
 	}
 }
 
+func TestProcessUpload_EventProfile(t *testing.T) {
+	const event = "ProfileEvent"
+
+	workspace := setupWorkspace(t, event, "OSINT")
+	if err := os.MkdirAll(filepath.Join(workspace, "events", event, "Hardware"), 0o750); err != nil {
+		t.Fatalf("failed to create category directory: %v", err)
+	}
+	gzevent := `title: "Profile Event"
+upload:
+  namePattern: "^Upload "
+  categories:
+    OSINT:
+      requiredDirs: [dist, solver]
+    Hardware:
+      requiredDirs: [dist, src, solver, docs]
+`
+	if err := os.WriteFile(filepath.Join(workspace, "events", event, ".gzevent"), []byte(gzevent), 0o600); err != nil {
+		t.Fatalf("failed to write .gzevent: %v", err)
+	}
+
+	upload := func(category string, cfg buildChallengeArchiveConfig) error {
+		t.Helper()
+		cfg.IncludeSolver = true
+		file, err := os.Open(filepath.Clean(buildChallengeArchive(t, cfg))) // #nosec G304 -- archive resides in a controlled temp directory
+		if err != nil {
+			t.Fatalf("failed to open archive: %v", err)
+		}
+		t.Cleanup(func() { _ = file.Close() })
+		return newTestServer(t).processUpload(context.Background(), event, category, file, "challenge.zip")
+	}
+
+	if err := upload("OSINT", buildChallengeArchiveConfig{OmitSrc: true}); err != nil {
+		t.Fatalf("OSINT upload without src: %v", err)
+	}
+	if err := upload("Hardware", buildChallengeArchiveConfig{}); !errors.Is(err, errMissingRequiredDir) {
+		t.Fatalf("Hardware upload without docs: expected errMissingRequiredDir, got %v", err)
+	}
+	if err := upload("Hardware", buildChallengeArchiveConfig{
+		ExtraRootDirs:  []string{"docs"},
+		ExtraRootFiles: map[string]string{"docs/wiring.md": "pinout"},
+	}); err != nil {
+		t.Fatalf("Hardware upload with docs: %v", err)
+	}
+	misnamed := strings.Replace(sampleChallengeYAML, "Upload Sample", "Sample", 1)
+	if err := upload("OSINT", buildChallengeArchiveConfig{ChallengeYAML: misnamed, OmitSrc: true}); !errors.Is(err, errNamingConvention) {
+		t.Fatalf("misnamed upload: expected errNamingConvention, got %v", err)
+	}
+}
+
+func TestProcessUpload_AttachmentPolicy(t *testing.T) {
+	const (
+		event    = "PolicyEvent"
+		category = "Misc"
+	)
+
+	workspace := setupWorkspace(t, event, category)
+	gzevent := `title: "Policy Event"
+defaults:
+  forbiddenExtensions: [".env"]
+  requireReadme: true
+`
+	if err := os.WriteFile(filepath.Join(workspace, "events", event, ".gzevent"), []byte(gzevent), 0o600); err != nil {
+		t.Fatalf("failed to write .gzevent: %v", err)
+	}
+
+	upload := func(dist map[string]string) error {
+		t.Helper()
+		file, err := os.Open(filepath.Clean(buildChallengeArchive(t, buildChallengeArchiveConfig{
+			ChallengeYAML: sampleChallengeProvideDist,
+			IncludeSolver: true,
+			DistFiles:     dist,
+		}))) // #nosec G304 -- archive resides in a controlled temp directory
+		if err != nil {
+			t.Fatalf("failed to open archive: %v", err)
+		}
+		t.Cleanup(func() { _ = file.Close() })
+		return newTestServer(t).processUpload(context.Background(), event, category, file, "challenge.zip")
+	}
+
+	var validationErr *ValidationError
+	if err := upload(map[string]string{"README.md": "how to start", "app/.env": "SECRET=1"}); !errors.As(err, &validationErr) || !strings.Contains(err.Error(), ".env") {
+		t.Fatalf("upload with a forbidden file: expected a policy ValidationError, got %v", err)
+	}
+	if err := upload(map[string]string{"chall.py": "print(1)"}); !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "README") {
+		t.Fatalf("upload without README: expected a policy ValidationError, got %v", err)
+	}
+	if err := upload(map[string]string{"README.md": "how to start", "chall.py": "print(1)"}); err != nil {
+		t.Fatalf("compliant upload: %v", err)
+	}
+}
+
+func TestWriteTemplateArchive_RequiredDirs(t *testing.T) {
+	tpl, _ := getTemplateBySlug("static-attachment")
+	var buf bytes.Buffer
+	if err := writeTemplateArchive(&buf, tpl, config.UploadRules{RequiredDirs: []string{"dist", "solver", "docs"}}); err != nil {
+		t.Fatalf("writeTemplateArchive error: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open generated archive: %v", err)
+	}
+	for _, file := range zr.File {
+		if file.Name == tpl.Slug+"/docs/" {
+			return
+		}
+	}
+	t.Fatalf("expected archive to include %s/docs/", tpl.Slug)
+}
+
 func TestWriteTemplateArchive(t *testing.T) {
 	for _, tpl := range challengeTemplates {
 		tpl := tpl
 		t.Run(tpl.Slug, func(t *testing.T) {
 			var buf bytes.Buffer
-			if err := writeTemplateArchive(&buf, tpl); err != nil {
+			if err := writeTemplateArchive(&buf, tpl, config.UploadRules{RequiredDirs: config.DefaultUploadDirs}); err != nil {
 				t.Fatalf("writeTemplateArchive error: %v", err)
 			}
 
@@ -303,8 +413,9 @@ func TestWriteTemplateArchive(t *testing.T) {
 func newTestServer(t *testing.T) *server {
 	t.Helper()
 	srv, err := newServer(Options{
-		Host: "localhost",
-		Port: 8090,
+		Host:           "localhost",
+		Port:           8090,
+		AllowAnonymous: true,
 	})
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
@@ -347,6 +458,7 @@ type buildChallengeArchiveConfig struct {
 	SrcFiles       map[string]string
 	ExtraRootFiles map[string]string
 	ExtraRootDirs  []string
+	OmitSrc        bool
 }
 
 func buildChallengeArchive(t *testing.T, cfg buildChallengeArchiveConfig) string {
@@ -376,13 +488,15 @@ func buildChallengeArchive(t *testing.T, cfg buildChallengeArchiveConfig) string
 		ensurePlaceholderFile(t, distDir)
 	}
 
-	if err := os.MkdirAll(filepath.Join(challengeDir, "src"), 0o750); err != nil {
-		t.Fatalf("failed to create src directory: %v", err)
-	}
-	srcDir := filepath.Join(challengeDir, "src")
-	writeFiles(t, srcDir, cfg.SrcFiles)
-	if len(cfg.SrcFiles) == 0 {
-		ensurePlaceholderFile(t, srcDir)
+	if !cfg.OmitSrc {
+		if err := os.MkdirAll(filepath.Join(challengeDir, "src"), 0o750); err != nil {
+			t.Fatalf("failed to create src directory: %v", err)
+		}
+		srcDir := filepath.Join(challengeDir, "src")
+		writeFiles(t, srcDir, cfg.SrcFiles)
+		if len(cfg.SrcFiles) == 0 {
+			ensurePlaceholderFile(t, srcDir)
+		}
 	}
 
 	if cfg.IncludeSolver {
@@ -401,18 +515,18 @@ func buildChallengeArchive(t *testing.T, cfg buildChallengeArchiveConfig) string
 		writeFiles(t, solverDir, cfg.SolverFiles)
 	}
 
-	for name, content := range cfg.ExtraRootFiles {
-		if err := os.WriteFile(filepath.Join(challengeDir, name), []byte(content), 0o600); err != nil {
-			t.Fatalf("failed to write extra root file %s: %v", name, err)
-		}
-	}
-
 	for _, dir := range cfg.ExtraRootDirs {
 		if err := os.MkdirAll(filepath.Join(challengeDir, dir), 0o750); err != nil {
 			t.Fatalf("failed to create extra root directory %s: %v", dir, err)
 		}
 	}
 
+	for name, content := range cfg.ExtraRootFiles {
+		if err := os.WriteFile(filepath.Join(challengeDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write extra root file %s: %v", name, err)
+		}
+	}
+
 	archivePath := filepath.Join(root, "challenge.zip")
 	if err := fileutil.ZipSource(challengeDir, archivePath); err != nil {
 		t.Fatalf("failed to zip challenge: %v", err)
@@ -11,6 +11,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 )
 
@@ -25,7 +26,7 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("Validation Error:\n  What: %s\n  Where: %s\n  How to Fix: %s", e.What, e.Where, e.HowToFix)
 }
 
-func validateUploadChallenge(root string, chall config.ChallengeYaml) error {
+func validateUploadChallenge(root string, chall config.ChallengeYaml, rules config.UploadRules) error {
 	if err := ensureDashboardConfigExists(root, chall); err != nil {
 		return err
 	}
@@ -52,8 +53,10 @@ func validateUploadChallenge(root string, chall config.ChallengeYaml) error {
 		return err
 	}
 
-	if err := validateSolverContent(root); err != nil {
-		return err
+	if rules.Requires("solver") {
+		if err := validateSolverContent(root); err != nil {
+			return err
+		}
 	}
 
 	if err := validateDefaultValues(chall); err != nil {
@@ -495,3 +498,28 @@ func validateDefaultValues(chall config.ChallengeYaml) error {
 	}
 	return nil
 }
+
+// validateAttachmentPolicy checks the attachment of chall against the
+// attachment policy of the event it is uploaded to
+func validateAttachmentPolicy(event, root string, chall config.ChallengeYaml) error {
+	defaults, err := config.GetChallengeDefaults(event)
+	if err != nil {
+		return fmt.Errorf("failed to read defaults of event %q: %w", event, err)
+	}
+	chall = config.ApplyAttachmentPolicy(chall, defaults)
+	chall.Cwd = root
+
+	violations := challenge.CheckAttachmentPolicy(chall)
+	if len(violations) == 0 {
+		return nil
+	}
+	details := make([]string, len(violations))
+	for i, v := range violations {
+		details[i] = v.Detail
+	}
+	return &ValidationError{
+		What:     fmt.Sprintf("Attachment breaks the event's attachment policy: %s", strings.Join(details, "; ")),
+		Where:    "challenge.yml (provide)",
+		HowToFix: "Shrink the attachment, remove forbidden files or add a README to it, or ask the organizers for an exemption.",
+	}
+}
@@ -0,0 +1,65 @@
+package gzcli
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+)
+
+// userIDPattern matches the GUIDs GZCTF identifies users with
+var userIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ListUsers returns count users of the server after skipping skip of them,
+// with the number of users on the server
+func (gz *GZ) ListUsers(skip, count int) ([]*gzapi.User, int, error) {
+	return gz.api.ListUsers(skip, count)
+}
+
+// SearchUsers returns the users whose name, email, real name, student number
+// or ID contains hint
+func (gz *GZ) SearchUsers(hint string) ([]*gzapi.User, error) {
+	return gz.api.SearchUsers(hint)
+}
+
+// FindUser returns the user with the ID, username or email ref. Usernames
+// and emails are matched exactly, ignoring case.
+func (gz *GZ) FindUser(ref string) (*gzapi.User, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("empty user")
+	}
+	if userIDPattern.MatchString(ref) {
+		return gz.api.GetUser(ref)
+	}
+
+	users, err := gz.api.SearchUsers(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.UserName, ref) || strings.EqualFold(user.Email, ref) {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("no user named %q (%d partial match(es), see 'gzcli user search')", ref, len(users))
+}
+
+// SetUserRole changes the role of the user ref to one of gzapi.UserRoles,
+// matched ignoring case, and returns the user
+func (gz *GZ) SetUserRole(ref, role string) (*gzapi.User, error) {
+	index := slices.IndexFunc(gzapi.UserRoles(), func(r string) bool { return strings.EqualFold(r, role) })
+	if index < 0 {
+		return nil, fmt.Errorf("unknown role %q (valid: %s)", role, strings.Join(gzapi.UserRoles(), ", "))
+	}
+	user, err := gz.FindUser(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.SetRole(gzapi.UserRoles()[index]); err != nil {
+		return nil, fmt.Errorf("failed to change the role of %s: %w", user.UserName, err)
+	}
+	return user, nil
+}
@@ -0,0 +1,218 @@
+// Package announce posts webhook notifications when the watcher adds a new
+// challenge to an event or a challenge fails its healthcheck or solver
+// verification, so organizer channels learn about it during development
+package announce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookEnv is the environment variable holding the webhook URL when
+// --announce-webhook is not given
+const WebhookEnv = "GZCLI_ANNOUNCE_WEBHOOK"
+
+// DefaultTimeout bounds a single webhook delivery
+const DefaultTimeout = 10 * time.Second
+
+// TypeChallengeCreated is the type of announcements of new challenges
+const TypeChallengeCreated = "challenge_created"
+
+// TypeChallengeUnhealthy is the type of announcements of challenges that
+// failed their healthcheck and are being redeployed
+const TypeChallengeUnhealthy = "challenge_unhealthy"
+
+// TypeChallengeVerifyFailed is the type of announcements of challenges whose
+// solver failed against the deployed challenge after a sync
+const TypeChallengeVerifyFailed = "challenge_verify_failed"
+
+// TypeChallengeReleased is the type of announcements of challenges enabled
+// by the watcher at their release_at time
+const TypeChallengeReleased = "challenge_released"
+
+// maxErrorBody bounds how much of a failed response is kept in the error
+const maxErrorBody = 512
+
+// Embed colors of Discord announcements
+const (
+	discordColor          = 0x2ecc71
+	discordUnhealthyColor = 0xe74c3c
+	discordReleasedColor  = 0x3498db
+)
+
+// Announcement is the payload posted for a newly created, unhealthy,
+// unverified or released challenge
+type Announcement struct {
+	Type        string    `json:"type"`
+	Event       string    `json:"event"`
+	Challenge   string    `json:"challenge"`
+	Category    string    `json:"category"`
+	Value       int       `json:"value"`
+	Author      string    `json:"author,omitempty"`
+	ChallengeID int       `json:"challenge_id"`
+	Message     string    `json:"message,omitempty"` // Details of unhealthy and failed verification announcements
+	Time        time.Time `json:"time"`
+}
+
+// Notifier posts announcements to a webhook. Discord webhook URLs receive
+// an embed; any other URL receives the Announcement as JSON.
+type Notifier struct {
+	url     string
+	discord bool
+	client  *http.Client
+}
+
+// NewNotifier creates a notifier posting to the http(s) webhook URL.
+// A zero timeout uses DefaultTimeout.
+func NewNotifier(webhook string, timeout time.Duration) (*Notifier, error) {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q: must be an http(s) URL", redact(u))
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Notifier{
+		url:     webhook,
+		discord: isDiscord(u),
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// isDiscord reports whether u is a Discord webhook
+func isDiscord(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	discordHost := host == "discord.com" || host == "discordapp.com" ||
+		strings.HasSuffix(host, ".discord.com") || strings.HasSuffix(host, ".discordapp.com")
+	return discordHost && strings.HasPrefix(u.Path, "/api/webhooks/")
+}
+
+// redact drops the path and query of u, which hold the secret of most webhooks
+func redact(u *url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+}
+
+// Send posts a. Non-2xx responses are errors.
+func (n *Notifier) Send(ctx context.Context, a Announcement) error {
+	if a.Type == "" {
+		a.Type = TypeChallengeCreated
+	}
+	if a.Time.IsZero() {
+		a.Time = time.Now().UTC()
+	}
+
+	var payload any = a
+	if n.discord {
+		payload = discordPayload(a)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gzcli-watcher")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// The URL holds the webhook secret; keep it out of logs
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+type discordMessage struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields"`
+	Timestamp   string         `json:"timestamp"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordPayload renders a as a Discord embed
+func discordPayload(a Announcement) discordMessage {
+	if a.Type == TypeChallengeUnhealthy || a.Type == TypeChallengeVerifyFailed {
+		message := a.Message
+		if message == "" {
+			message = "-"
+		}
+		title, description := "Unhealthy challenge: ", "Healthcheck failing in event **%s**, redeploying"
+		if a.Type == TypeChallengeVerifyFailed {
+			title, description = "Solver failed: ", "Solver failing against the deployed challenge in event **%s**"
+		}
+		return discordMessage{
+			Username: "gzcli",
+			Embeds: []discordEmbed{{
+				Title:       title + a.Challenge,
+				Description: fmt.Sprintf(description, a.Event),
+				Color:       discordUnhealthyColor,
+				Fields: []discordField{
+					{Name: "Category", Value: a.Category, Inline: true},
+					{Name: "Details", Value: message},
+				},
+				Timestamp: a.Time.Format(time.RFC3339),
+			}},
+		}
+	}
+
+	author := a.Author
+	if author == "" {
+		author = "-"
+	}
+	title, description, color := "New challenge: ", "Added to event **%s** (challenge ID %d)", discordColor
+	if a.Type == TypeChallengeReleased {
+		title, description, color = "Challenge released: ", "Released in event **%s** (challenge ID %d)", discordReleasedColor
+	}
+	return discordMessage{
+		Username: "gzcli",
+		Embeds: []discordEmbed{{
+			Title:       title + a.Challenge,
+			Description: fmt.Sprintf(description, a.Event, a.ChallengeID),
+			Color:       color,
+			Fields: []discordField{
+				{Name: "Category", Value: a.Category, Inline: true},
+				{Name: "Value", Value: strconv.Itoa(a.Value), Inline: true},
+				{Name: "Author", Value: author, Inline: true},
+			},
+			Timestamp: a.Time.Format(time.RFC3339),
+		}},
+	}
+}
@@ -0,0 +1,139 @@
+package announce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifierSend(t *testing.T) {
+	var got Announcement
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(srv.URL+"/hook", 0)
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+	if n.discord {
+		t.Error("NewNotifier() took a local URL for a Discord webhook")
+	}
+	a := Announcement{Event: "ctf", Challenge: "baby-rop", Category: "Pwn", Value: 500, Author: "alice", ChallengeID: 7}
+	if err := n.Send(context.Background(), a); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Type != TypeChallengeCreated || got.Challenge != "baby-rop" || got.Category != "Pwn" ||
+		got.Value != 500 || got.Author != "alice" || got.ChallengeID != 7 || got.Time.IsZero() {
+		t.Errorf("posted %+v", got)
+	}
+}
+
+func TestNotifierSend_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unknown webhook", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(srv.URL+"/api/webhooks/1/secret-token", time.Second)
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+	err = n.Send(context.Background(), Announcement{Challenge: "x"})
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "unknown webhook") {
+		t.Errorf("Send() error = %v, want the status and body", err)
+	}
+
+	srv.Close()
+	err = n.Send(context.Background(), Announcement{Challenge: "x"})
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Send() error = %v, want an error without the webhook URL", err)
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+		discord bool
+	}{
+		{url: "https://discord.com/api/webhooks/1/abc", discord: true},
+		{url: "https://canary.discordapp.com/api/webhooks/1/abc", discord: true},
+		{url: "https://discord.com/channels/1"},
+		{url: "https://example.com/api/webhooks/1/abc"},
+		{url: "ftp://example.com/hook", wantErr: true},
+		{url: "discord.com/api/webhooks/1/abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			n, err := NewNotifier(tt.url, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewNotifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && n.discord != tt.discord {
+				t.Errorf("discord = %v, want %v", n.discord, tt.discord)
+			}
+		})
+	}
+}
+
+func TestDiscordPayload(t *testing.T) {
+	msg := discordPayload(Announcement{Event: "ctf", Challenge: "baby-rop", Category: "Pwn", Value: 500, ChallengeID: 7, Time: time.Unix(0, 0).UTC()})
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(data)
+	for _, want := range []string{`"title":"New challenge: baby-rop"`, `"value":"Pwn"`, `"value":"500"`, `"value":"-"`, `"timestamp":"1970-01-01T00:00:00Z"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("payload %s does not contain %s", body, want)
+		}
+	}
+}
+
+func TestDiscordPayload_Unhealthy(t *testing.T) {
+	msg := discordPayload(Announcement{Type: TypeChallengeUnhealthy, Event: "ctf", Challenge: "baby-rop", Category: "Pwn", Message: "3 consecutive failures"})
+	if len(msg.Embeds) != 1 {
+		t.Fatalf("got %d embeds, want 1", len(msg.Embeds))
+	}
+	embed := msg.Embeds[0]
+	if embed.Title != "Unhealthy challenge: baby-rop" || embed.Color != discordUnhealthyColor {
+		t.Errorf("embed = %+v", embed)
+	}
+	if embed.Fields[1].Value != "3 consecutive failures" {
+		t.Errorf("details = %q", embed.Fields[1].Value)
+	}
+}
+
+func TestDiscordPayload_VerifyFailed(t *testing.T) {
+	msg := discordPayload(Announcement{Type: TypeChallengeVerifyFailed, Event: "ctf", Challenge: "baby-rop", Category: "Pwn", Message: "solver exited with status 1"})
+	embed := msg.Embeds[0]
+	if embed.Title != "Solver failed: baby-rop" || embed.Color != discordUnhealthyColor {
+		t.Errorf("embed = %+v", embed)
+	}
+	if embed.Fields[1].Value != "solver exited with status 1" {
+		t.Errorf("details = %q", embed.Fields[1].Value)
+	}
+}
+
+func TestDiscordPayload_Released(t *testing.T) {
+	msg := discordPayload(Announcement{Type: TypeChallengeReleased, Event: "ctf", Challenge: "baby-rop", Category: "Pwn", Value: 500, ChallengeID: 7})
+	embed := msg.Embeds[0]
+	if embed.Title != "Challenge released: baby-rop" || embed.Color != discordReleasedColor {
+		t.Errorf("embed = %+v", embed)
+	}
+	if embed.Description != "Released in event **ctf** (challenge ID 7)" {
+		t.Errorf("description = %q", embed.Description)
+	}
+}
@@ -1,17 +1,33 @@
 package challenge
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzignore"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
+// ErrWatchLimit is returned when a directory cannot be watched because the
+// inotify limits of the user are reached
+var ErrWatchLimit = errors.New("inotify watch limit reached")
+
+// WatchLimitHint tells how to get past the inotify limits
+const WatchLimitHint = "raise it with 'sudo sysctl fs.inotify.max_user_watches=524288' (persist it in /etc/sysctl.d/) or start the watcher with --poll"
+
+// IsWatchLimit reports whether err comes from reaching the inotify limits:
+// ENOSPC for watches and EMFILE for watcher instances
+func IsWatchLimit(err error) bool {
+	return errors.Is(err, ErrWatchLimit) || errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
+
 // Manager manages challenge watch operations with optimized path lookups
 type Manager struct {
 	watcher    *fsnotify.Watcher
@@ -27,7 +43,8 @@ type pathIndexEntry struct {
 	pathLength    int // Used for finding the most specific match
 }
 
-// NewManager creates a new challenge manager with path indexing
+// NewManager creates a new challenge manager with path indexing. A nil
+// watcher only indexes challenges, for polling them instead.
 func NewManager(watcher *fsnotify.Watcher) *Manager {
 	return &Manager{
 		watcher:    watcher,
@@ -36,7 +53,10 @@ func NewManager(watcher *fsnotify.Watcher) *Manager {
 	}
 }
 
-// AddChallenge adds a challenge directory to the watcher with path indexing
+// AddChallenge adds a challenge directory to the watcher with path indexing.
+// When the inotify limits are reached the challenge is still indexed, and an
+// error wrapping ErrWatchLimit is returned as its directories are not all
+// watched.
 func (m *Manager) AddChallenge(name, cwd string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -53,16 +73,29 @@ func (m *Manager) AddChallenge(name, cwd string) error {
 	}
 
 	// Add the challenge directory
-	err = m.watcher.Add(cwd)
-	if err != nil {
-		return fmt.Errorf("failed to add directory %s: %w", cwd, err)
+	var limitErr error
+	if m.watcher != nil {
+		if err := m.watcher.Add(cwd); err != nil {
+			if !IsWatchLimit(err) {
+				return fmt.Errorf("failed to add directory %s: %w", cwd, err)
+			}
+			limitErr = err
+		}
 	}
 
-	// Build path index while walking subdirectories
+	// Build path index while walking subdirectories, leaving out those
+	// ignored by .gzignore
+	ignore := gzignore.ForDir(cwd)
 	err = filepath.Walk(cwd, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
+		if info.IsDir() && path != cwd {
+			if ignore.Match(path, true) {
+				return filepath.SkipDir
+			}
+			ignore.AddFile(path)
+		}
 
 		absPath, err := filepath.Abs(path)
 		if err != nil {
@@ -72,9 +105,13 @@ func (m *Manager) AddChallenge(name, cwd string) error {
 		// Index this path for fast lookups
 		m.indexPath(absPath, name, absCwd)
 
-		if info.IsDir() && !shouldIgnoreDir(path) {
+		if info.IsDir() && !shouldIgnoreDir(path) && m.watcher != nil && limitErr == nil {
 			if err := m.watcher.Add(path); err != nil {
-				log.Error("Failed to watch directory %s: %v", path, err)
+				if IsWatchLimit(err) {
+					limitErr = err
+				} else {
+					log.Error("Failed to watch directory %s: %v", path, err)
+				}
 			}
 		}
 		return nil
@@ -86,10 +123,21 @@ func (m *Manager) AddChallenge(name, cwd string) error {
 
 	// Mark as watched
 	m.challenges[name] = cwd
+	if limitErr != nil {
+		return fmt.Errorf("%w: failed to watch %s: %v", ErrWatchLimit, cwd, limitErr)
+	}
 	log.InfoH2("Now watching: %s (%s)", name, cwd)
 	return nil
 }
 
+// StopWatching drops the watcher, so challenges added afterwards are only
+// indexed. The watcher itself is closed by its owner.
+func (m *Manager) StopWatching() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watcher = nil
+}
+
 // indexPath adds a path to the index for O(1) lookups
 func (m *Manager) indexPath(absPath, challengeName, challengeCwd string) {
 	// Normalize challenge directory path
@@ -119,9 +167,11 @@ func (m *Manager) RemoveChallenge(name string) error {
 		return nil
 	}
 
-	if err := m.watcher.Remove(cwd); err != nil {
-		// Directory may no longer exist; log but don't fail
-		log.DebugH3("Watcher remove for %s returned: %v", cwd, err)
+	if m.watcher != nil {
+		if err := m.watcher.Remove(cwd); err != nil {
+			// Directory may no longer exist; log but don't fail
+			log.DebugH3("Watcher remove for %s returned: %v", cwd, err)
+		}
 	}
 
 	// Remove from path index
@@ -2,11 +2,14 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,16 +19,24 @@ import (
 	"github.com/dimasma0305/gzcli/internal/gzcli/config"
 	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/challenge"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/filesystem"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/git"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/imagescan"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/scripts"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
 
-var challengeFileRegex = regexp.MustCompile(`^challenge\.(yaml|yml)$`)
+const (
+	// launcherActionChallengeRemoved must match server.ActionChallengeRemoved
+	launcherActionChallengeRemoved = "challenge_removed"
+	launcherNotifyTimeout          = 5 * time.Second
+)
 
 // EventWatcher manages file watching for a single event
 type EventWatcher struct {
@@ -50,11 +61,65 @@ type EventWatcher struct {
 	scriptMgr    *scripts.Manager
 	db           *database.DB // Shared reference
 	gitMgrs      []*git.Manager
+	committer    *git.Committer     // nil unless GitCommitEnabled
+	commitRoot   string             // Repository root used by committer
+	scanner      *imagescan.Scanner // nil unless ImageScanEnabled
+	announcer    *announce.Notifier // nil unless AnnounceWebhook is set
 
 	// Challenge mapping cache (folder path -> GZCTF challenge ID)
-	challengeMappings   map[string]int // folderPath -> challengeID
+	challengeMappings   map[string]challengeMapping // folderPath -> remote challenge
 	challengeMappingsMu sync.RWMutex
 
+	// Latest sync computed per challenge in dry-run mode
+	dryRunPending   map[string]watchertypes.DryRunSync
+	dryRunPendingMu sync.RWMutex
+
+	// End of the last sync attempt per challenge, for cooldowns
+	lastSyncAt   map[string]time.Time
+	lastSyncAtMu sync.Mutex
+
+	// challenge.yaml contents written by the formatter, so the file events
+	// of its own rewrites do not trigger syncs
+	formatted   map[string][]byte
+	formattedMu sync.Mutex
+
+	// Update types requested regardless of the changed files, e.g. redeploys
+	// of unhealthy challenges
+	forcedUpdates   map[string]watchertypes.UpdateType
+	forcedUpdatesMu sync.Mutex
+
+	// Syncs held by the live lock during the game, and the ones approved
+	liveLockMu    sync.Mutex
+	heldUpdates   map[string]watchertypes.UpdateType
+	approvedSyncs map[string]bool
+
+	// Syncs paused per challenge, or for the whole event under the empty
+	// name, and the update types skipped while paused
+	pauseMu        sync.Mutex
+	paused         map[string]watchertypes.PausedSync
+	skippedUpdates map[string]watchertypes.UpdateType
+
+	// Pending releases of challenges with a future release_at
+	releaseTimers   map[string]*time.Timer
+	releaseTimersMu sync.Mutex
+
+	// Progress of the latest sync of all watched challenges
+	bulkSync   *bulkSync
+	bulkSyncMu sync.Mutex
+
+	// Challenges whose latest healthcheck failed
+	healthFailing   map[string]bool
+	healthFailingMu sync.Mutex
+
+	// API clients of the mirror targets of the event, by target name
+	mirrors   map[string]mirrorClient
+	mirrorsMu sync.Mutex
+
+	// Polling of the challenge directories, used instead of inotify with
+	// --poll or once its limits are reached
+	polling     bool
+	pollingOnce sync.Once
+
 	// Additional state
 	debounceTimers map[string]*time.Timer
 }
@@ -79,10 +144,18 @@ func NewEventWatcher(eventName string, api *gzapi.GZAPI, config watchertypes.Wat
 		return nil, fmt.Errorf("event directory does not exist: %s", eventPath)
 	}
 
-	// Create fsnotify watcher for this event
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	// Create fsnotify watcher for this event, unless polling
+	var watcher *fsnotify.Watcher
+	polling := config.Polling
+	if !polling {
+		watcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			if !challenge.IsWatchLimit(err) {
+				return nil, fmt.Errorf("failed to create fsnotify watcher: %w", err)
+			}
+			log.Error("[%s] Failed to create inotify watcher: %v; %s. Polling for changes instead", eventName, err, challenge.WatchLimitHint)
+			polling = true
+		}
 	}
 
 	ctx, cancel := context.WithCancel(parentCtx)
@@ -107,7 +180,17 @@ func NewEventWatcher(eventName string, api *gzapi.GZAPI, config watchertypes.Wat
 		challengeMutexes:   make(map[string]*sync.Mutex),
 		pendingUpdates:     make(map[string]string),
 		updatingChallenges: make(map[string]bool),
-		challengeMappings:  make(map[string]int),
+		challengeMappings:  make(map[string]challengeMapping),
+		dryRunPending:      make(map[string]watchertypes.DryRunSync),
+		lastSyncAt:         make(map[string]time.Time),
+		forcedUpdates:      make(map[string]watchertypes.UpdateType),
+		healthFailing:      make(map[string]bool),
+		heldUpdates:        make(map[string]watchertypes.UpdateType),
+		approvedSyncs:      make(map[string]bool),
+		paused:             make(map[string]watchertypes.PausedSync),
+		skippedUpdates:     make(map[string]watchertypes.UpdateType),
+		releaseTimers:      make(map[string]*time.Timer),
+		polling:            polling,
 	}
 
 	// Initialize component managers
@@ -133,6 +216,11 @@ func (ew *EventWatcher) Start() error {
 		if err != nil {
 			log.Info("[%s] WARNING: Failed to resolve git repository path: %v", ew.eventName, err)
 		} else {
+			pullConf, confErr := config.GetGitPullConfig(ew.eventName)
+			if confErr != nil {
+				log.Error("[%s] Ignoring git configuration of .gzevent: %v", ew.eventName, confErr)
+				pullConf = &config.GitPullConfig{}
+			}
 			repoPaths, err := git.ResolveRepoPaths(absRepoPath, ew.eventName)
 			if err != nil {
 				log.Info("[%s] WARNING: Git monitoring enabled but no git repositories found: %v", ew.eventName, err)
@@ -152,28 +240,75 @@ func (ew *EventWatcher) Start() error {
 						// pulled challenges are pushed to GZCTF even if fsnotify misses events.
 						ew.enqueueSyncForWatchedChallenges()
 					})
+					mgr.SetPullConfig(git.PullConfig{
+						Remote:      pullConf.Remote,
+						Branch:      pullConf.Branch,
+						SparsePaths: pullConf.SparsePaths,
+						ForceReset:  pullConf.ForceReset,
+					})
+					mgr.SetPullObserver(func(startedAt, endedAt time.Time, err error) {
+						ew.recordActivity("", watchertypes.ActivityGitPull, startedAt, endedAt, err)
+						var conflict *git.ConflictError
+						if errors.As(err, &conflict) {
+							ew.LogToDatabase("ERROR", "git", "", "", conflict.Error(), conflict.Output, endedAt.Sub(startedAt).Milliseconds())
+						}
+					})
 					ew.gitMgrs = append(ew.gitMgrs, mgr)
 				}
 			}
 		}
 	}
 
+	if ew.config.GitCommitEnabled {
+		if err := ew.initCommitter(); err != nil {
+			return fmt.Errorf("failed to initialize git commits: %w", err)
+		}
+	}
+
+	if ew.config.ImageScanEnabled {
+		scanner, err := imagescan.NewScanner(ew.config.ImageScanCommand, 0)
+		if err != nil {
+			return fmt.Errorf("failed to initialize image scanning: %w", err)
+		}
+		ew.scanner = scanner
+	}
+
+	if ew.config.AnnounceWebhook != "" {
+		announcer, err := announce.NewNotifier(ew.config.AnnounceWebhook, 0)
+		if err != nil {
+			return fmt.Errorf("failed to initialize challenge announcements: %w", err)
+		}
+		ew.announcer = announcer
+	}
+
+	ew.loadPausedSyncs()
+
 	// Discover and watch challenges
 	if err := ew.discoverChallenges(); err != nil {
 		return fmt.Errorf("failed to discover challenges: %w", err)
 	}
-
-	// Start file system watcher loop
+	ew.startHealthchecks()
 	ew.wg.Add(1)
 	go func() {
 		defer ew.wg.Done()
-		done := make(chan struct{})
+		ew.startReleases()
+	}()
+
+	// Start file system watcher loop
+	if ew.polling {
+		ew.startPolling()
+	} else {
+		ew.wg.Add(1)
 		go func() {
-			<-ew.ctx.Done()
-			close(done)
+			defer ew.wg.Done()
+			done := make(chan struct{})
+			go func() {
+				<-ew.ctx.Done()
+				close(done)
+			}()
+			filesystem.WatchLoop(ew.watcher, ew.config, ew, done)
 		}()
-		filesystem.WatchLoop(ew.watcher, ew.config, ew, done)
-	}()
+	}
 
 	// Start git pull loops if enabled
 	if ew.config.GitPullEnabled && len(ew.gitMgrs) > 0 {
@@ -186,6 +321,15 @@ func (ew *EventWatcher) Start() error {
 		}
 	}
 
+	// Start posting scheduled notices
+	if ew.db != nil && ew.db.IsEnabled() {
+		ew.wg.Add(1)
+		go func() {
+			defer ew.wg.Done()
+			ew.runNotices()
+		}()
+	}
+
 	ew.LogToDatabase("INFO", "event_watcher", "", "", fmt.Sprintf("Event watcher started for %s", ew.eventName), "", 0)
 	log.Info("[%s] Event watcher started successfully", ew.eventName)
 
@@ -202,6 +346,7 @@ func (ew *EventWatcher) Stop() error {
 	if ew.scriptMgr != nil {
 		ew.scriptMgr.StopAllScripts(5 * time.Second)
 	}
+	ew.stopReleases()
 
 	// Cancel context
 	ew.cancel()
@@ -237,65 +382,73 @@ func (ew *EventWatcher) Stop() error {
 func (ew *EventWatcher) discoverChallenges() error {
 	log.InfoH3("[%s] Discovering challenges in %s", ew.eventName, ew.eventPath)
 
-	var discoveredCount int
-	err := filepath.Walk(ew.eventPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip errors
-		}
+	entries, err := registry.Discover(ew.eventName, ew.eventPath)
+	if err != nil {
+		return err
+	}
 
-		// Skip hidden directories (starting with .)
-		if info.IsDir() {
-			dirName := filepath.Base(path)
-			if dirName != "." && dirName != ".." && dirName[0] == '.' {
-				log.DebugH3("[%s] Skipping hidden directory: %s", ew.eventName, dirName)
-				return filepath.SkipDir
+	var discoveredCount int
+	var limitErr error
+	for _, entry := range entries {
+		// Add challenge to watcher with its category/directory key
+		if err := ew.challengeMgr.AddChallenge(entry.Key(), entry.Dir); err != nil {
+			if errors.Is(err, challenge.ErrWatchLimit) {
+				// Still indexed, so polling covers it
+				limitErr = err
+				ew.challengeMgr.StopWatching()
+			} else {
+				log.Error("[%s] Failed to add challenge %s: %v", ew.eventName, entry.Key(), err)
+				continue // Continue with other challenges
 			}
-			return nil
 		}
+		discoveredCount++
+	}
 
-		// Skip if not a challenge file
-		if !challengeFileRegex.MatchString(info.Name()) {
-			return nil
-		}
+	log.Info("[%s] Discovered %d challenge(s)", ew.eventName, discoveredCount)
+	if limitErr != nil {
+		msg := fmt.Sprintf("Cannot watch every challenge directory, the inotify watch limit is reached; %s", challenge.WatchLimitHint)
+		log.Error("[%s] %s. Polling for changes every %s instead (%v)", ew.eventName, msg, ew.pollInterval(), limitErr)
+		ew.LogToDatabase("ERROR", "watcher", "", "", msg, limitErr.Error(), 0)
+		ew.startPolling()
+	}
+	return nil
+}
 
-		// Found a challenge.yaml file
-		challengeDir := filepath.Dir(path)
-		challengeName := filepath.Base(challengeDir)
+// pollInterval returns the interval between polls of the challenge directories
+func (ew *EventWatcher) pollInterval() time.Duration {
+	if ew.config.PollInterval > 0 {
+		return ew.config.PollInterval
+	}
+	return watchertypes.DefaultWatcherConfig.PollInterval
+}
 
-		// Determine category from path to create unique challenge identifier
-		// Path format: events/{event}/{category}/{challenge}/
-		relPath, err := filepath.Rel(ew.eventPath, challengeDir)
-		var uniqueName string
-		if err == nil && relPath != "." {
-			// Split by path separator
-			parts := splitPath(relPath)
-			if len(parts) > 0 {
-				category := parts[0]
-				// Use category/challengeName as unique identifier
-				uniqueName = category + "/" + challengeName
-			} else {
-				uniqueName = challengeName
+// startPolling polls the watched challenge directories for changes until the
+// watcher stops. Its inotify watcher, if any, is closed as polling replaces it.
+func (ew *EventWatcher) startPolling() {
+	ew.pollingOnce.Do(func() {
+		ew.challengeMgr.StopWatching()
+		if ew.watcher != nil {
+			if err := ew.watcher.Close(); err != nil {
+				log.Error("[%s] Failed to close file watcher: %v", ew.eventName, err)
 			}
-		} else {
-			uniqueName = challengeName
 		}
 
-		// Add challenge to watcher with unique name
-		if err := ew.challengeMgr.AddChallenge(uniqueName, challengeDir); err != nil {
-			log.Error("[%s] Failed to add challenge %s: %v", ew.eventName, uniqueName, err)
-			return nil // Continue with other challenges
-		}
+		poller := filesystem.NewPoller(func() []string {
+			challenges := ew.challengeMgr.GetChallenges()
+			dirs := make([]string, 0, len(challenges))
+			for _, dir := range challenges {
+				dirs = append(dirs, dir)
+			}
+			return dirs
+		}, ew.config, ew)
+		log.Info("[%s] Polling challenge directories for changes every %s", ew.eventName, ew.pollInterval())
 
-		discoveredCount++
-		return nil
+		ew.wg.Add(1)
+		go func() {
+			defer ew.wg.Done()
+			poller.Run(ew.pollInterval(), ew.ctx.Done())
+		}()
 	})
-
-	if err != nil {
-		return fmt.Errorf("failed to walk event directory: %w", err)
-	}
-
-	log.Info("[%s] Discovered %d challenge(s)", ew.eventName, discoveredCount)
-	return nil
 }
 
 // GetChallengeUpdateMutex gets or creates a mutex for a specific challenge
@@ -335,14 +488,36 @@ func (ew *EventWatcher) LogScriptExecution(challengeName, scriptName, scriptType
 	}
 }
 
+// recordActivity stores a timed sync or git pull for the activity timeline
+func (ew *EventWatcher) recordActivity(challengeName, kind string, startedAt, endedAt time.Time, err error) {
+	if ew.db == nil {
+		return
+	}
+	status, errorMsg := "success", ""
+	if err != nil {
+		status, errorMsg = "failed", err.Error()
+	}
+	ew.db.LogSyncActivity(ew.eventName, challengeName, kind, status, startedAt, endedAt, errorMsg)
+}
+
 func (ew *EventWatcher) UpdateChallengeState(challengeName, status, errorMessage string, activeScripts map[string][]string) {
 	if ew.db != nil {
-		ew.db.UpdateChallengeState(challengeName, status, errorMessage, activeScripts)
+		ew.db.UpdateChallengeState(ew.eventName, challengeName, status, errorMessage, activeScripts)
 	}
 }
 
 // Implement filesystem.EventHandler interface
 func (ew *EventWatcher) HandleFileChange(filePath string) {
+	if ew.isOwnFormatting(filePath) {
+		log.DebugH3("[%s] Ignoring formatter rewrite of %s", ew.eventName, filePath)
+		return
+	}
+	ew.processChange(filePath)
+}
+
+// processChange syncs the challenge filePath belongs to, or queues the change
+// when a sync of that challenge is already running
+func (ew *EventWatcher) processChange(filePath string) {
 	log.InfoH2("[%s] Processing file change: %s", ew.eventName, filePath)
 
 	// Find which challenge this file belongs to
@@ -398,12 +573,16 @@ func (ew *EventWatcher) HandleFileChange(filePath string) {
 		nextFilePath := filePath
 		first := true
 		for {
-			// Add a small delay to batch rapid file changes.
-			if first {
-				time.Sleep(100 * time.Millisecond)
-				first = false
-			} else {
-				time.Sleep(50 * time.Millisecond)
+			// Batch rapid file changes, then honor the challenge's cooldown.
+			// Changes arriving meanwhile become pending and are drained below.
+			debounce, cooldown := ew.syncWindows(challengeCwd)
+			if !first {
+				debounce = pendingDebounce
+			}
+			first = false
+			if !ew.sleep(debounce) || !ew.sleep(ew.cooldownRemaining(challengeName, cooldown)) {
+				ew.setUpdating(challengeName, false)
+				return
 			}
 
 			updateType := filesystem.DetermineUpdateType(nextFilePath, challengeCwd)
@@ -423,10 +602,17 @@ func (ew *EventWatcher) HandleFileChange(filePath string) {
 					log.InfoH3("[%s] Upgraded update type to: %v", ew.eventName, updateType)
 				}
 			}
+			if forced, ok := ew.takeForcedUpdate(challengeName); ok && forced > updateType {
+				updateType = forced
+				log.InfoH3("[%s] Upgraded update type to forced: %v", ew.eventName, updateType)
+			}
 
-			// Skip if no update needed, but keep looping if new pending updates appear.
-			if updateType == watchertypes.UpdateNone {
-				log.InfoH3("[%s] No update needed for %s", ew.eventName, challengeName)
+			// Skip if no update needed, paused or held during the game, but keep looping if new pending updates appear.
+			if updateType == watchertypes.UpdateNone || ew.syncPaused(challengeName, updateType) || !ew.liveLockAllows(challengeName, updateType) {
+				if updateType == watchertypes.UpdateNone {
+					log.InfoH3("[%s] No update needed for %s", ew.eventName, challengeName)
+				}
+				ew.bulkSyncFinished(challengeName, 0, nil)
 				if pendingFilePath, shouldContinue := finishOrContinue(); shouldContinue {
 					nextFilePath = pendingFilePath
 					continue
@@ -444,7 +630,15 @@ func (ew *EventWatcher) HandleFileChange(filePath string) {
 			}
 
 			// Perform the actual sync
-			if err := ew.syncSingleChallenge(challengeName, challengeCwd); err != nil {
+			syncStartedAt := time.Now()
+			ew.bulkSyncStarted(challengeName)
+			err := ew.syncSingleChallenge(challengeName, challengeCwd, updateType)
+			syncEndedAt := time.Now()
+			ew.bulkSyncFinished(challengeName, syncEndedAt.Sub(syncStartedAt), err)
+			watcherMetrics.observeSync(ew.eventName, updateType, syncEndedAt.Sub(syncStartedAt), err)
+			ew.recordActivity(challengeName, watchertypes.ActivitySync, syncStartedAt, syncEndedAt, err)
+			ew.setLastSyncAt(challengeName, syncEndedAt)
+			if err != nil {
 				log.Error("[%s] Failed to sync challenge %s: %v", ew.eventName, challengeName, err)
 				if ew.scriptMgr != nil {
 					activeScripts := ew.scriptMgr.GetActiveIntervalScripts()
@@ -476,6 +670,77 @@ func (ew *EventWatcher) HandleFileChange(filePath string) {
 	}()
 }
 
+// Default scheduling of a challenge without watch overrides
+const (
+	defaultDebounce = 100 * time.Millisecond
+	pendingDebounce = 50 * time.Millisecond
+)
+
+// syncWindows returns the debounce and cooldown of the challenge in
+// challengeCwd, from the watch block of its challenge.yaml
+func (ew *EventWatcher) syncWindows(challengeCwd string) (debounce, cooldown time.Duration) {
+	debounce = defaultDebounce
+	path, err := registry.FindChallengeFile(challengeCwd)
+	if err != nil {
+		return debounce, 0
+	}
+	//nolint:gosec // G304: File paths come from validated challenges directory
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return debounce, 0
+	}
+	var conf struct {
+		Watch *config.WatchConfig `yaml:"watch"`
+	}
+	if err := fileutil.ParseYamlFromBytes(content, &conf); err != nil || conf.Watch == nil {
+		return debounce, 0
+	}
+	if conf.Watch.Debounce > 0 {
+		debounce = min(conf.Watch.Debounce, challengepkg.MaxWatchWindow)
+	}
+	return debounce, min(max(conf.Watch.Cooldown, 0), challengepkg.MaxWatchWindow)
+}
+
+// cooldownRemaining returns how long a challenge must wait before its next
+// sync so that syncs are at least cooldown apart
+func (ew *EventWatcher) cooldownRemaining(challengeName string, cooldown time.Duration) time.Duration {
+	if cooldown <= 0 {
+		return 0
+	}
+	ew.lastSyncAtMu.Lock()
+	last, ok := ew.lastSyncAt[challengeName]
+	ew.lastSyncAtMu.Unlock()
+	if !ok {
+		return 0
+	}
+	remaining := cooldown - time.Since(last)
+	if remaining > 0 {
+		log.InfoH3("[%s] Challenge %s is cooling down, next sync in %v", ew.eventName, challengeName, remaining.Round(time.Second))
+	}
+	return remaining
+}
+
+func (ew *EventWatcher) setLastSyncAt(challengeName string, t time.Time) {
+	ew.lastSyncAtMu.Lock()
+	ew.lastSyncAt[challengeName] = t
+	ew.lastSyncAtMu.Unlock()
+}
+
+// sleep waits for d, returning false if the watcher stops first
+func (ew *EventWatcher) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ew.ctx.Done():
+		return false
+	}
+}
+
 func (ew *EventWatcher) HandleFileRemoval(filePath string) {
 	log.InfoH2("[%s] Processing file removal: %s", ew.eventName, filePath)
 
@@ -529,6 +794,8 @@ func (ew *EventWatcher) removeChallenge(challengeName string) {
 		ew.scriptMgr.StopAllScriptsForChallenge(challengeName)
 	}
 
+	challengeDir := ew.challengeMgr.GetChallenges()[challengeName]
+
 	// Remove from challenge manager
 	if err := ew.challengeMgr.RemoveChallenge(challengeName); err != nil {
 		log.Error("[%s] Failed to remove challenge %s: %v", ew.eventName, challengeName, err)
@@ -549,10 +816,66 @@ func (ew *EventWatcher) removeChallenge(challengeName string) {
 	delete(ew.pendingUpdates, challengeName)
 	ew.pendingUpdatesMu.Unlock()
 
+	ew.forcedUpdatesMu.Lock()
+	delete(ew.forcedUpdates, challengeName)
+	ew.forcedUpdatesMu.Unlock()
+	ew.liveLockMu.Lock()
+	delete(ew.heldUpdates, challengeName)
+	delete(ew.approvedSyncs, challengeName)
+	ew.liveLockMu.Unlock()
+	ew.pauseMu.Lock()
+	delete(ew.skippedUpdates, challengeName)
+	ew.pauseMu.Unlock()
+	ew.releaseTimersMu.Lock()
+	if timer, ok := ew.releaseTimers[challengeName]; ok {
+		timer.Stop()
+		delete(ew.releaseTimers, challengeName)
+	}
+	ew.releaseTimersMu.Unlock()
+	ew.setHealthFailing(challengeName, false)
+
 	// Update database
 	if ew.db != nil {
-		ew.db.UpdateChallengeState(challengeName, "removed", "", nil)
+		ew.db.UpdateChallengeState(ew.eventName, challengeName, "removed", "", nil)
 	}
+
+	ew.notifyLauncherRemoval(challengeName, challengeDir)
+}
+
+// notifyLauncherRemoval tells a running launcher to stop and drop instances of a
+// removed challenge. It is best-effort: no launcher running is not an error.
+func (ew *EventWatcher) notifyLauncherRemoval(challengeName, challengeDir string) {
+	socketPath := ew.config.LauncherSocketPath
+	if socketPath == "" || challengeDir == "" {
+		return
+	}
+	if ew.config.DryRun {
+		log.InfoH3("[%s] [dry-run] Would notify launcher of removed challenge: %s", ew.eventName, challengeName)
+		return
+	}
+	if _, err := os.Stat(socketPath); err != nil {
+		log.DebugH3("[%s] Launcher socket not available, skipping removal notice: %v", ew.eventName, err)
+		return
+	}
+
+	go func() {
+		client := socket.NewClient(socketPath)
+		client.SetTimeout(launcherNotifyTimeout)
+		resp, err := client.SendCommand(launcherActionChallengeRemoved, map[string]interface{}{
+			"event":     ew.eventName,
+			"path":      challengeDir,
+			"challenge": challengeName,
+		})
+		if err != nil {
+			log.Error("[%s] Failed to notify launcher of removed challenge %s: %v", ew.eventName, challengeName, err)
+			return
+		}
+		if !resp.Success {
+			log.Error("[%s] Launcher rejected removal of %s: %s", ew.eventName, challengeName, resp.Error)
+			return
+		}
+		log.InfoH3("[%s] Launcher: %s", ew.eventName, resp.Message)
+	}()
 }
 
 // triggerRediscovery triggers a background rediscovery of challenges
@@ -569,17 +892,13 @@ func (ew *EventWatcher) triggerRediscovery() {
 }
 
 // syncSingleChallenge performs a sync operation for a single challenge
-func (ew *EventWatcher) syncSingleChallenge(challengeName, challengePath string) error {
+func (ew *EventWatcher) syncSingleChallenge(challengeName, challengePath string, updateType watchertypes.UpdateType) error {
 	log.InfoH2("[%s] 🔄 Syncing challenge to GZCTF: %s", ew.eventName, challengeName)
 
 	// Find and load the challenge.yaml file
-	challengeYamlPath := filepath.Join(challengePath, "challenge.yaml")
-	if _, err := os.Stat(challengeYamlPath); os.IsNotExist(err) {
-		// Try challenge.yml
-		challengeYamlPath = filepath.Join(challengePath, "challenge.yml")
-		if _, err := os.Stat(challengeYamlPath); os.IsNotExist(err) {
-			return fmt.Errorf("challenge YAML file not found in %s", challengePath)
-		}
+	challengeYamlPath, err := registry.FindChallengeFile(challengePath)
+	if err != nil {
+		return fmt.Errorf("challenge YAML file not found in %s", challengePath)
 	}
 
 	// Read raw YAML content for template processing
@@ -600,19 +919,7 @@ func (ew *EventWatcher) syncSingleChallenge(challengeName, challengePath string)
 
 	// Determine category from path
 	// Path format: events/{event}/{category}/{challenge}/
-	relPath, err := filepath.Rel(ew.eventPath, challengePath)
-	if err == nil && relPath != "." {
-		// Split by path separator
-		parts := splitPath(relPath)
-		if len(parts) > 0 {
-			challengeConf.Category = parts[0]
-		}
-	}
-	if challengeConf.Category == "" {
-		// Fallback: extract category from parent directory name
-		categoryDir := filepath.Dir(challengePath)
-		challengeConf.Category = filepath.Base(categoryDir)
-	}
+	challengeConf.Category = registry.Category(ew.eventPath, challengePath)
 
 	// Normalize category and update name if needed (e.g., "Game Hacking" -> "Reverse")
 	challengeConf.Category, challengeConf.Name = config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
@@ -638,25 +945,317 @@ func (ew *EventWatcher) syncSingleChallenge(challengeName, challengePath string)
 
 	// Re-set the challenge directory after template processing
 	challengeConf.Cwd = challengePath
+	challengeConf = config.ApplyChallengeDefaults(challengeConf, conf.Defaults)
 
-	// Get existing challenges from API
+	// Get existing challenges from API, with full details only for this one
 	conf.Event.CS = ew.api
-	challenges, err := conf.Event.GetChallenges()
+	challenges, err := conf.Event.GetChallengesSelective(ew.challengeSelector(challengeConf))
 	if err != nil {
 		return fmt.Errorf("failed to get challenges from API: %w", err)
 	}
 
+	// In dry-run mode, compute and record the sync plan without mutating the API
+	if ew.config.DryRun {
+		ew.planChallengeSync(challengeConf, challenges, conf.Event.Id, updateType)
+		return nil
+	}
+
 	// Sync the challenge using the challenge package
-	if err := ew.syncChallengeInternal(conf, challengeConf, challenges); err != nil {
+	provenance, err := ew.syncChallengeInternal(challengeName, conf, challengeConf, challenges, updateType)
+	if err != nil {
 		return fmt.Errorf("sync failed: %w", err)
 	}
 
 	log.Info("[%s] ✅ Successfully synced challenge: %s", ew.eventName, challengeName)
+	ew.formatChallengeYaml(challengeName, challengeYamlPath)
+	ew.recordDeployment(challengeName, provenance)
+	ew.commitGenerated(challengeName, challengePath)
+	ew.startHealthcheck(challengeName, challengeConf)
+	ew.scheduleRelease(challengeName, challengeConf, provenance.ChallengeID, conf.Event.Id)
+	ew.verifyChallenge(challengeName, challengeConf, conf.Appsettings.ContainerProvider.PublicEntry, provenance.ChallengeID, conf.Event.Id)
+	return ew.syncMirrors(challengeName, challengeConf, updateType)
+}
+
+// announceChallenge posts the announcement of a newly created challenge in
+// the background. Failures are logged and never fail the sync itself.
+func (ew *EventWatcher) announceChallenge(challengeConf config.ChallengeYaml, name, category string, challengeID int) {
+	if ew.announcer == nil || ew.config.DryRun {
+		return
+	}
+
+	a := announce.Announcement{
+		Type:        announce.TypeChallengeCreated,
+		Event:       ew.eventName,
+		Challenge:   name,
+		Category:    category,
+		Value:       challengeConf.Value,
+		Author:      challengeConf.Author,
+		ChallengeID: challengeID,
+		Time:        time.Now().UTC(),
+	}
+	ew.wg.Add(1)
+	go func() {
+		defer ew.wg.Done()
+		startedAt := time.Now()
+		err := ew.announcer.Send(ew.ctx, a)
+		duration := time.Since(startedAt).Milliseconds()
+		if err != nil {
+			log.Error("[%s] Failed to announce new challenge %s: %v", ew.eventName, name, err)
+			ew.LogToDatabase("ERROR", "announce", name, "", "Failed to announce new challenge", err.Error(), duration)
+			return
+		}
+		log.Info("[%s] 📣 Announced new challenge %s", ew.eventName, name)
+		ew.LogToDatabase("INFO", "announce", name, "", fmt.Sprintf("Announced new challenge %s (%s, %d points)", name, category, a.Value), "", duration)
+	}()
+}
+
+// recordDeployment stores the provenance of a sync for post-event audits
+func (ew *EventWatcher) recordDeployment(challengeName string, provenance challengepkg.Provenance) {
+	if ew.db == nil || !ew.db.IsEnabled() {
+		return
+	}
+	dep, err := ew.db.RecordDeployment(provenance.Deployment(ew.eventName, watchertypes.DeploymentSourceWatcher))
+	if err != nil {
+		log.Error("[%s] Failed to record the deployment of %s: %v", ew.eventName, challengeName, err)
+		return
+	}
+	log.InfoH3("[%s] Recorded deployment of %s (revision %d)", ew.eventName, challengeName, dep.Revision)
+}
+
+// deploymentAttachments answers the attachment hash of a challenge from its
+// latest deployment in the watcher database, so unchanged attachments are not
+// uploaded again by every sync
+type deploymentAttachments struct {
+	ew    *EventWatcher
+	title string
+}
+
+func (d deploymentAttachments) AttachmentHash(challengeID int) string {
+	if d.ew.db == nil || !d.ew.db.IsEnabled() {
+		return ""
+	}
+	deployments, err := d.ew.db.GetDeployments(d.ew.eventName, d.title, true, 0)
+	if err != nil {
+		log.Error("[%s] Failed to get the deployments of %s: %v", d.ew.eventName, d.title, err)
+		return ""
+	}
+	for _, dep := range deployments {
+		if dep.ChallengeID == challengeID {
+			return dep.DistHash
+		}
+	}
+	return ""
+}
+
+// SetAttachmentHash does nothing: the hash is recorded with the deployment
+func (deploymentAttachments) SetAttachmentHash(int, string) {}
+
+// scanImage scans the container image of a challenge before a full redeploy
+// and records the result. Findings and scanner errors fail the sync when
+// ImageScanBlock is set and are only reported otherwise.
+func (ew *EventWatcher) scanImage(challengeName, challengePath, image string) error {
+	if ew.scanner == nil || image == "" {
+		return nil
+	}
+
+	log.Info("[%s] 🔍 Scanning image %s of %s", ew.eventName, image, challengeName)
+	result := ew.scanner.Scan(ew.ctx, ew.eventName, challengeName, image, challengePath)
+	blocked := ew.config.ImageScanBlock && result.Status != imagescan.StatusPassed
+
+	if ew.db != nil {
+		ew.db.LogImageScan(watchertypes.ImageScan{
+			Event:         ew.eventName,
+			ChallengeName: challengeName,
+			Image:         image,
+			Command:       result.Command,
+			Status:        result.Status,
+			Blocked:       blocked,
+			ExitCode:      result.ExitCode,
+			Output:        result.Output,
+			Duration:      result.Duration.Milliseconds(),
+		})
+	}
+
+	var problem string
+	switch result.Status {
+	case imagescan.StatusPassed:
+		log.Info("[%s] ✅ Image %s passed the scan", ew.eventName, image)
+		ew.LogToDatabase("INFO", "image_scan", challengeName, "", fmt.Sprintf("Image %s passed the scan", image), "", result.Duration.Milliseconds())
+		return nil
+	case imagescan.StatusFindings:
+		problem = fmt.Sprintf("image %s has findings (scanner exit status %d)", image, result.ExitCode)
+	default:
+		problem = fmt.Sprintf("image %s could not be scanned: %v", image, result.Err)
+	}
+
+	if blocked {
+		log.Error("[%s] ⛔ Deployment of %s blocked: %s", ew.eventName, challengeName, problem)
+		ew.LogToDatabase("ERROR", "image_scan", challengeName, "", "Deployment blocked by image scan", problem, result.Duration.Milliseconds())
+		return fmt.Errorf("deployment blocked: %s", problem)
+	}
+	log.Error("[%s] ⚠️  Deploying %s despite scan result: %s", ew.eventName, challengeName, problem)
+	ew.LogToDatabase("WARN", "image_scan", challengeName, "", "Deploying despite image scan result", problem, result.Duration.Milliseconds())
+	return nil
+}
+
+// formatChallengeYaml normalizes a challenge.yaml after a successful sync.
+// Failures are logged and never fail the sync itself.
+func (ew *EventWatcher) formatChallengeYaml(challengeName, path string) {
+	if !ew.config.FormatYaml || ew.config.DryRun {
+		return
+	}
+
+	//nolint:gosec // G304: Path is the challenge file that was just synced
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Error("[%s] Failed to read %s for formatting: %v", ew.eventName, path, err)
+		return
+	}
+	formatted, err := challengepkg.FormatYaml(data)
+	if err != nil {
+		log.Error("[%s] Failed to format %s: %v", ew.eventName, path, err)
+		return
+	}
+	if bytes.Equal(data, formatted) {
+		return
+	}
+
+	// Record the content before writing, so the resulting file event is
+	// recognized however quickly it arrives
+	key := filepath.Clean(path)
+	ew.formattedMu.Lock()
+	if ew.formatted == nil {
+		ew.formatted = make(map[string][]byte)
+	}
+	ew.formatted[key] = formatted
+	ew.formattedMu.Unlock()
+
+	info, err := os.Stat(path)
+	if err == nil {
+		err = os.WriteFile(path, formatted, info.Mode().Perm())
+	}
+	if err != nil {
+		ew.formattedMu.Lock()
+		delete(ew.formatted, key)
+		ew.formattedMu.Unlock()
+		log.Error("[%s] Failed to write formatted %s: %v", ew.eventName, path, err)
+		return
+	}
+	log.InfoH3("[%s] Formatted challenge.yaml of %s", ew.eventName, challengeName)
+}
+
+// isOwnFormatting reports whether filePath still holds the content the
+// formatter wrote to it. Once the file is edited it is forgotten.
+func (ew *EventWatcher) isOwnFormatting(filePath string) bool {
+	key := filepath.Clean(filePath)
+	ew.formattedMu.Lock()
+	defer ew.formattedMu.Unlock()
+
+	written, ok := ew.formatted[key]
+	if !ok {
+		return false
+	}
+	//nolint:gosec // G304: Path comes from the event directory watcher
+	current, err := os.ReadFile(filePath)
+	if err == nil && bytes.Equal(current, written) {
+		return true
+	}
+	delete(ew.formatted, key)
+	return false
+}
+
+// initCommitter sets up automatic commits of generated files to the repository containing the event
+func (ew *EventWatcher) initCommitter() error {
+	root, err := git.FindGitRepoRoot(ew.eventPath)
+	if err != nil {
+		return err
+	}
+
+	commitConf := git.CommitConfig{
+		Branch:          ew.config.GitCommitBranch,
+		MessageTemplate: ew.config.GitCommitMessage,
+	}
+	if ew.config.GitPushEnabled {
+		commitConf.Remote = ew.config.GitPushRemote
+		if commitConf.Remote == "" {
+			commitConf.Remote = "origin"
+		}
+	}
+
+	committer, err := git.NewCommitter(root, commitConf)
+	if err != nil {
+		return err
+	}
+	ew.committer = committer
+	ew.commitRoot = root
+	log.Info("[%s] Git commits of generated files enabled in %s", ew.eventName, root)
 	return nil
 }
 
-// syncChallengeInternal performs the actual sync operation
-func (ew *EventWatcher) syncChallengeInternal(conf *config.Config, challengeConf config.ChallengeYaml, challenges []gzapi.Challenge) error {
+// commitPaths returns the paths, relative to each challenge directory, of the
+// generated files committed after a sync
+func (ew *EventWatcher) commitPaths() []string {
+	if len(ew.config.GitCommitPaths) > 0 {
+		return ew.config.GitCommitPaths
+	}
+	return watchertypes.DefaultWatcherConfig.GitCommitPaths
+}
+
+// commitGenerated commits files generated for a challenge after a successful sync.
+// Failures are logged and never fail the sync itself.
+func (ew *EventWatcher) commitGenerated(challengeName, challengePath string) {
+	if ew.committer == nil || ew.config.DryRun {
+		return
+	}
+
+	relDir, err := filepath.Rel(ew.commitRoot, challengePath)
+	if err != nil || relDir == ".." || strings.HasPrefix(relDir, ".."+string(filepath.Separator)) {
+		log.Error("[%s] Challenge %s is outside git repository %s", ew.eventName, challengePath, ew.commitRoot)
+		return
+	}
+
+	var paths []string
+	for _, p := range ew.commitPaths() {
+		joined := filepath.Join(relDir, p)
+		if joined != relDir && !strings.HasPrefix(joined, relDir+string(filepath.Separator)) {
+			log.Error("[%s] Ignoring git commit path %q outside challenge directory", ew.eventName, p)
+			continue
+		}
+		paths = append(paths, filepath.ToSlash(joined))
+	}
+
+	sha, err := ew.committer.Commit(git.CommitContext{
+		Event:     ew.eventName,
+		Challenge: challengeName,
+		Paths:     paths,
+	})
+	if err != nil {
+		log.Error("[%s] Failed to commit generated files for %s: %v", ew.eventName, challengeName, err)
+		ew.LogToDatabase("ERROR", "git", challengeName, "", "Failed to commit generated files", err.Error(), 0)
+		return
+	}
+	if sha != "" {
+		ew.LogToDatabase("INFO", "git", challengeName, "", fmt.Sprintf("Committed generated files (%s)", sha), "", 0)
+	}
+}
+
+// challengeSelector matches the remote challenge a sync of challengeConf
+// reads: the one mapped to its folder, or the one with its title
+func (ew *EventWatcher) challengeSelector(challengeConf config.ChallengeYaml) func(gzapi.Challenge) bool {
+	relPath, err := filepath.Rel(ew.eventPath, challengeConf.Cwd)
+	if err != nil {
+		relPath = challengeConf.Category + "/" + filepath.Base(challengeConf.Cwd)
+	}
+	mappedID, mapped := ew.getChallengeID(relPath)
+	_, normalizedName := config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
+
+	return func(c gzapi.Challenge) bool {
+		return (mapped && c.Id == mappedID) || c.Title == challengeConf.Name || c.Title == normalizedName
+	}
+}
+
+// syncChallengeInternal performs the actual sync operation and returns what it deployed
+func (ew *EventWatcher) syncChallengeInternal(challengeName string, conf *config.Config, challengeConf config.ChallengeYaml, challenges []gzapi.Challenge, updateType watchertypes.UpdateType) (challengepkg.Provenance, error) {
 	// Build folder path relative to event (e.g., "Crypto/my-challenge")
 	relPath, err := filepath.Rel(ew.eventPath, challengeConf.Cwd)
 	if err != nil {
@@ -665,11 +1264,23 @@ func (ew *EventWatcher) syncChallengeInternal(conf *config.Config, challengeConf
 	folderPath := relPath
 
 	// Step 1: Check if we have a mapping for this folder
-	if challengeID, exists := ew.getChallengeID(folderPath); exists {
+	if mapping, exists := ew.getChallengeMapping(folderPath); exists {
+		challengeID := mapping.id
 		log.InfoH3("[%s] Found existing challenge mapping: %s → ID %d", ew.eventName, folderPath, challengeID)
 
+		// Never update a challenge of another game, e.g. one of the event this
+		// event was cloned from
+		if err := ew.checkMappingGame(folderPath, challengeID, mapping.gameID, conf.Event.Id); err != nil {
+			return challengepkg.Provenance{}, err
+		}
+
 		// Try to fetch the challenge by ID using the provided challenges list
 		existingChallenge, err := ew.fetchChallengeByID(challengeID, challenges)
+		if err == nil {
+			if err := ew.checkMappingGame(folderPath, challengeID, existingChallenge.GameId, conf.Event.Id); err != nil {
+				return challengepkg.Provenance{}, err
+			}
+		}
 		if err != nil {
 			// Challenge might have been deleted in GZCTF - remove mapping and continue
 			log.InfoH3("[%s] Challenge ID %d not found in GZCTF (may have been deleted), removing mapping", ew.eventName, challengeID)
@@ -679,31 +1290,46 @@ func (ew *EventWatcher) syncChallengeInternal(conf *config.Config, challengeConf
 			log.InfoH3("[%s] Updating existing challenge ID %d: %s → %s", ew.eventName, challengeID, existingChallenge.Title, challengeConf.Name)
 
 			// Perform the sync with the existing challenge, passing challenges list to avoid redundant API calls
-			if err := ew.syncToExistingChallenge(conf, challengeConf, existingChallenge, challenges); err != nil {
-				return fmt.Errorf("failed to update existing challenge: %w", err)
+			provenance, err := ew.syncToExistingChallenge(challengeName, conf, challengeConf, existingChallenge, challenges, updateType)
+			switch {
+			case err == nil:
+				// Update mapping with new title
+				ew.setChallengeID(folderPath, challengeID, conf.Event.Id, challengeConf.Name)
+				return provenance, nil
+			case errors.Is(err, gzapi.ErrNotFound):
+				// Deleted in GZCTF after the challenge list was fetched
+				log.InfoH3("[%s] Challenge ID %d was deleted in GZCTF during sync, removing mapping", ew.eventName, challengeID)
+				ew.deleteChallengeID(folderPath)
+			default:
+				return challengepkg.Provenance{}, fmt.Errorf("failed to update existing challenge: %w", err)
 			}
-
-			// Update mapping with new title
-			ew.setChallengeID(folderPath, challengeID, challengeConf.Name)
-			return nil
 		}
 	}
 
 	// Step 2: No mapping found - use normal sync flow (create or find by name)
 	log.InfoH3("[%s] No mapping found for %s, using normal sync flow", ew.eventName, folderPath)
+	normalizedCategory, normalizedName := config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
+	existed := false
+	for _, ch := range challenges {
+		if ch.Title == normalizedName && ch.Category == normalizedCategory {
+			existed = true
+			break
+		}
+	}
 
 	// Call the challenge sync function with config.ChallengeYaml directly
-	if err := challengepkg.SyncChallenge(conf, challengeConf, challenges, ew.api, ew.noOpGetCache, ew.noOpSetCache); err != nil {
-		return err
+	orchestrator := ew.newOrchestrator(challengeName, conf, challengeConf, challenges, nil, updateType)
+	if err := orchestrator.Execute(); err != nil {
+		return challengepkg.Provenance{}, err
 	}
+	provenance := orchestrator.Provenance()
 
 	// Step 3: After successful sync, find the challenge ID from the updated challenges list
 	// Try to find by the normalized name first
-	normalizedCategory, normalizedName := config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
 	var syncedChallengeID int
 
 	// Fetch fresh challenges list to get the newly created/updated challenge
-	freshChallenges, err := conf.Event.GetChallenges()
+	freshChallenges, err := conf.Event.ListChallenges()
 	if err == nil {
 		for _, ch := range freshChallenges {
 			if ch.Title == normalizedName && ch.Category == normalizedCategory {
@@ -715,13 +1341,84 @@ func (ew *EventWatcher) syncChallengeInternal(conf *config.Config, challengeConf
 
 	if syncedChallengeID > 0 {
 		// Store the mapping for future syncs
-		ew.setChallengeID(folderPath, syncedChallengeID, normalizedName)
+		ew.setChallengeID(folderPath, syncedChallengeID, conf.Event.Id, normalizedName)
 		log.InfoH3("[%s] Created new challenge mapping: %s → ID %d", ew.eventName, folderPath, syncedChallengeID)
+		// Challenges matched by title were already in the game and are not announced
+		if !existed {
+			ew.announceChallenge(challengeConf, normalizedName, normalizedCategory, syncedChallengeID)
+		}
 	} else {
 		log.Error("[%s] Failed to find synced challenge %s for mapping", ew.eventName, normalizedName)
 	}
 
-	return nil
+	return provenance, nil
+}
+
+// planChallengeSync computes what a sync would change and records it in the
+// database and the pending changes reported by the status command
+func (ew *EventWatcher) planChallengeSync(challengeConf config.ChallengeYaml, challenges []gzapi.Challenge, gameID int, updateType watchertypes.UpdateType) {
+	relPath, err := filepath.Rel(ew.eventPath, challengeConf.Cwd)
+	if err != nil {
+		relPath = challengeConf.Category + "/" + filepath.Base(challengeConf.Cwd)
+	}
+
+	var existing *gzapi.Challenge
+	if mapping, exists := ew.getChallengeMapping(relPath); exists {
+		if mapping.gameID != 0 && gameID != 0 && mapping.gameID != gameID {
+			log.Error("[%s] [dry-run] %s is mapped to challenge ID %d of game %d, not game %d; a sync would be refused", ew.eventName, relPath, mapping.id, mapping.gameID, gameID)
+		} else {
+			existing, _ = ew.fetchChallengeByID(mapping.id, challenges)
+		}
+	}
+
+	plan := challengepkg.PlanSync(challengeConf, challenges, existing)
+	changes := fmt.Sprintf("flags +%d/-%d, tags +%d/-%d", len(plan.FlagsToAdd), len(plan.FlagsToRemove), len(plan.TagsToAdd), len(plan.TagsToRemove))
+	log.Info("[%s] [dry-run] %s: would %s challenge (%s, %s)", ew.eventName, plan.Challenge, plan.Action, updateType, changes)
+	if plan.Diff != "" {
+		log.DebugH3("[%s] [dry-run] diff for %s:\n%s", ew.eventName, plan.Challenge, plan.Diff)
+	}
+
+	diff := plan.Diff
+	if len(plan.FlagsToAdd) > 0 || len(plan.FlagsToRemove) > 0 {
+		diff += fmt.Sprintf("\nflags to add: %v\nflags to remove: %v", plan.FlagsToAdd, plan.FlagsToRemove)
+	}
+	if len(plan.TagsToAdd) > 0 || len(plan.TagsToRemove) > 0 {
+		diff += fmt.Sprintf("\ntags to add: %v\ntags to remove: %v", plan.TagsToAdd, plan.TagsToRemove)
+	}
+
+	ew.LogToDatabase("INFO", "dry_run", plan.Challenge, "", fmt.Sprintf("Would %s challenge (%s, %s)", plan.Action, updateType, changes), "", 0)
+	if ew.db != nil {
+		ew.db.LogDryRunSync(ew.eventName, plan.Challenge, plan.Action, updateType.String(), plan.ChallengeID, diff)
+	}
+
+	ew.dryRunPendingMu.Lock()
+	if plan.HasChanges() {
+		ew.dryRunPending[plan.Challenge] = watchertypes.DryRunSync{
+			Timestamp:     time.Now(),
+			Event:         ew.eventName,
+			ChallengeName: plan.Challenge,
+			Action:        plan.Action,
+			UpdateType:    updateType.String(),
+			ChallengeID:   plan.ChallengeID,
+		}
+	} else {
+		delete(ew.dryRunPending, plan.Challenge)
+	}
+	ew.dryRunPendingMu.Unlock()
+}
+
+// GetDryRunPending returns the latest sync computed for each challenge in
+// dry-run mode, sorted by challenge name
+func (ew *EventWatcher) GetDryRunPending() []watchertypes.DryRunSync {
+	ew.dryRunPendingMu.RLock()
+	defer ew.dryRunPendingMu.RUnlock()
+
+	pending := make([]watchertypes.DryRunSync, 0, len(ew.dryRunPending))
+	for _, s := range ew.dryRunPending {
+		pending = append(pending, s)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ChallengeName < pending[j].ChallengeName })
+	return pending
 }
 
 // fetchChallengeByID fetches a challenge from GZCTF by its ID using provided challenges list
@@ -733,17 +1430,88 @@ func (ew *EventWatcher) fetchChallengeByID(challengeID int, challenges []gzapi.C
 		}
 	}
 
-	return nil, fmt.Errorf("challenge with ID %d not found", challengeID)
+	return nil, fmt.Errorf("challenge with ID %d %w", challengeID, gzapi.ErrNotFound)
 }
 
 // syncToExistingChallenge syncs changes to an existing challenge (handles name changes)
-func (ew *EventWatcher) syncToExistingChallenge(conf *config.Config, challengeConf config.ChallengeYaml, existingChallenge *gzapi.Challenge, challenges []gzapi.Challenge) error {
+func (ew *EventWatcher) syncToExistingChallenge(challengeName string, conf *config.Config, challengeConf config.ChallengeYaml, existingChallenge *gzapi.Challenge, challenges []gzapi.Challenge, updateType watchertypes.UpdateType) (challengepkg.Provenance, error) {
 	// Set the existing challenge data
 	existingChallenge.CS = ew.api
 
 	// Use the new SyncChallengeWithExisting to force update mode, passing existing challenge directly
 	// This avoids name-based lookup that would fail when category normalization changes the name
-	return challengepkg.SyncChallengeWithExisting(conf, challengeConf, challenges, ew.api, ew.noOpGetCache, ew.noOpSetCache, existingChallenge)
+	orchestrator := ew.newOrchestrator(challengeName, conf, challengeConf, challenges, existingChallenge, updateType)
+	if err := orchestrator.Execute(); err != nil {
+		return challengepkg.Provenance{}, err
+	}
+	return orchestrator.Provenance(), nil
+}
+
+// newOrchestrator creates the orchestrator syncing a challenge to the server
+// of the event. Full redeploys scan the image the orchestrator built or
+// pushed, before the challenge is updated to run it.
+func (ew *EventWatcher) newOrchestrator(challengeName string, conf *config.Config, challengeConf config.ChallengeYaml, challenges []gzapi.Challenge, existingChallenge *gzapi.Challenge, updateType watchertypes.UpdateType) *challengepkg.SyncOrchestrator {
+	orchestrator := challengepkg.NewSyncOrchestrator(conf, challengeConf, challenges, ew.api, ew.noOpGetCache, ew.noOpSetCache, existingChallenge)
+	orchestrator.SetAttachmentStore(deploymentAttachments{ew: ew, title: challengeConf.Name})
+	orchestrator.SetUpdateType(updateType.String())
+	if updateType == watchertypes.UpdateFullRedeploy {
+		orchestrator.SetImageCheck(func(image string) error {
+			return ew.scanImage(challengeName, challengeConf.Cwd, image)
+		})
+	}
+	return orchestrator
+}
+
+// RemapChallenges drops the mappings of the given challenge folders so their
+// next sync matches the challenge by title in the event's game. Without
+// folders, it drops every mapping recorded for another game than the event's.
+// It returns the folders whose mapping was dropped.
+func (ew *EventWatcher) RemapChallenges(folders []string) ([]string, error) {
+	if ew.db == nil {
+		return nil, fmt.Errorf("database logging is disabled")
+	}
+
+	if len(folders) == 0 {
+		conf, err := config.GetConfigWithEvent(ew.api, ew.eventName,
+			ew.noOpGetCache,
+			ew.noOpSetCache,
+			ew.noOpDeleteCache,
+			nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+		mappings, err := ew.db.ListChallengeMappings(ew.eventName)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mappings {
+			if m.GameID != 0 && m.GameID != conf.Event.Id {
+				folders = append(folders, m.FolderPath)
+			}
+		}
+	}
+
+	var remapped []string
+	for _, folder := range folders {
+		folder = filepath.ToSlash(filepath.Clean(folder))
+		if _, exists := ew.getChallengeMapping(folder); !exists {
+			continue
+		}
+		ew.deleteChallengeID(folder)
+		ew.LogToDatabase("INFO", "mapping", folder, "", "Mapping dropped for remapping", "", 0)
+		remapped = append(remapped, folder)
+	}
+	return remapped, nil
+}
+
+// SetChallengeMappings records the given mappings of challenge folders to
+// challenges of game gameID, replacing existing ones
+func (ew *EventWatcher) SetChallengeMappings(gameID int, mappings []watchertypes.ChallengeMapping) {
+	for _, m := range mappings {
+		folder := filepath.Clean(m.Folder)
+		ew.setChallengeID(folder, m.ChallengeID, gameID, m.Title)
+		ew.LogToDatabase("INFO", "mapping", folder, "", fmt.Sprintf("Mapping rebuilt to challenge ID %d (%s)", m.ChallengeID, m.Title), "", 0)
+	}
 }
 
 // Helper methods for update state management
@@ -782,53 +1550,85 @@ func splitPath(path string) []string {
 	return parts
 }
 
+// challengeMapping is the remote challenge a challenge folder syncs to
+type challengeMapping struct {
+	id     int
+	gameID int // 0 for mappings stored before game IDs were recorded
+}
+
+// errWrongGameMapping is returned when a challenge folder is mapped to a
+// challenge of another game than the event's
+var errWrongGameMapping = errors.New("challenge mapping points at another game")
+
+// checkMappingGame returns errWrongGameMapping, and alerts about it, when the
+// mapped challenge of folderPath belongs to another game than gameID. An
+// unknown game (0) is not checked.
+func (ew *EventWatcher) checkMappingGame(folderPath string, challengeID, mappedGameID, gameID int) error {
+	if mappedGameID == 0 || gameID == 0 || mappedGameID == gameID {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%s is mapped to challenge ID %d of game %d, but event %s is game %d; refusing to update it. Run 'gzcli watch remap --event %s %s' to remap it",
+		folderPath, challengeID, mappedGameID, ew.eventName, gameID, ew.eventName, folderPath)
+	log.Error("[%s] %s", ew.eventName, msg)
+	ew.LogToDatabase("ERROR", "mapping", folderPath, "", msg, "", 0)
+	return fmt.Errorf("%w: %s", errWrongGameMapping, msg)
+}
+
 // getChallengeID retrieves a challenge ID from cache or database
 func (ew *EventWatcher) getChallengeID(folderPath string) (int, bool) {
+	mapping, exists := ew.getChallengeMapping(folderPath)
+	return mapping.id, exists
+}
+
+// getChallengeMapping retrieves a challenge mapping from cache or database
+func (ew *EventWatcher) getChallengeMapping(folderPath string) (challengeMapping, bool) {
 	// Check in-memory cache first
 	ew.challengeMappingsMu.RLock()
-	if id, exists := ew.challengeMappings[folderPath]; exists {
+	if mapping, exists := ew.challengeMappings[folderPath]; exists {
 		ew.challengeMappingsMu.RUnlock()
-		log.DebugH3("[%s] Cache hit for challenge mapping: %s → ID %d", ew.eventName, folderPath, id)
-		return id, true
+		log.DebugH3("[%s] Cache hit for challenge mapping: %s → ID %d", ew.eventName, folderPath, mapping.id)
+		return mapping, true
 	}
 	ew.challengeMappingsMu.RUnlock()
 
 	// Cache miss - check database
 	if ew.db != nil {
-		mapping, err := ew.db.GetChallengeMapping(ew.eventName, folderPath)
+		stored, err := ew.db.GetChallengeMapping(ew.eventName, folderPath)
 		if err != nil {
 			log.DebugH3("[%s] Database query error for mapping %s: %v", ew.eventName, folderPath, err)
-			return 0, false
+			return challengeMapping{}, false
 		}
-		if mapping != nil {
+		if stored != nil {
 			// Found in database - update cache
+			mapping := challengeMapping{id: stored.ChallengeID, gameID: stored.GameID}
 			ew.challengeMappingsMu.Lock()
-			ew.challengeMappings[folderPath] = mapping.ChallengeID
+			ew.challengeMappings[folderPath] = mapping
 			ew.challengeMappingsMu.Unlock()
-			log.DebugH3("[%s] Database hit for challenge mapping: %s → ID %d", ew.eventName, folderPath, mapping.ChallengeID)
-			return mapping.ChallengeID, true
+			log.DebugH3("[%s] Database hit for challenge mapping: %s → ID %d", ew.eventName, folderPath, mapping.id)
+			return mapping, true
 		}
 	}
 
 	log.DebugH3("[%s] No mapping found for: %s", ew.eventName, folderPath)
-	return 0, false
+	return challengeMapping{}, false
 }
 
-// setChallengeID stores a challenge ID in cache and database
-func (ew *EventWatcher) setChallengeID(folderPath string, challengeID int, challengeTitle string) {
+// setChallengeID stores a challenge ID and its game in cache and database
+func (ew *EventWatcher) setChallengeID(folderPath string, challengeID, gameID int, challengeTitle string) {
 	// Update in-memory cache
 	ew.challengeMappingsMu.Lock()
-	ew.challengeMappings[folderPath] = challengeID
+	ew.challengeMappings[folderPath] = challengeMapping{id: challengeID, gameID: gameID}
 	ew.challengeMappingsMu.Unlock()
 
 	// Store in database for persistence
 	if ew.db != nil {
-		if err := ew.db.SetChallengeMapping(ew.eventName, folderPath, challengeID, challengeTitle); err != nil {
+		if err := ew.db.SetChallengeMapping(ew.eventName, folderPath, challengeID, gameID, challengeTitle); err != nil {
 			log.Error("[%s] Failed to store challenge mapping in database: %v", ew.eventName, err)
 		}
 	}
 
-	log.DebugH3("[%s] Stored challenge mapping: %s → ID %d (%s)", ew.eventName, folderPath, challengeID, challengeTitle)
+	log.DebugH3("[%s] Stored challenge mapping: %s → ID %d in game %d (%s)", ew.eventName, folderPath, challengeID, gameID, challengeTitle)
 }
 
 // deleteChallengeID removes a challenge ID mapping
@@ -890,6 +1690,92 @@ func (ew *EventWatcher) GetEventName() string {
 	return ew.eventName
 }
 
+// ChallengeStatus returns the recorded sync state, latest sync and error,
+// active interval scripts and mapped GZCTF challenge ID of a watched challenge
+func (ew *EventWatcher) ChallengeStatus(challengeName string) (watchertypes.ChallengeStatus, error) {
+	status := watchertypes.ChallengeStatus{Event: ew.eventName, Challenge: challengeName, ActiveScripts: []string{}}
+
+	challengeCwd, watched := ew.challengeMgr.GetChallenges()[challengeName]
+	if !watched {
+		return status, fmt.Errorf("challenge '%s' is not watched in event '%s'", challengeName, ew.eventName)
+	}
+	if ew.db == nil {
+		return status, fmt.Errorf("database logging is disabled")
+	}
+
+	state, err := ew.db.GetChallengeState(ew.eventName, challengeName)
+	if err != nil {
+		return status, fmt.Errorf("failed to get challenge state: %w", err)
+	}
+	if state != nil {
+		status.Status = state.Status
+		status.LastUpdate = &state.LastUpdate
+		if state.Status == "error" {
+			status.LastError = state.ErrorMessage
+		}
+	}
+
+	lastSync, err := ew.db.GetLastSyncActivity(ew.eventName, challengeName, "")
+	if err != nil {
+		return status, fmt.Errorf("failed to get last sync: %w", err)
+	}
+	if lastSync != nil {
+		status.LastSync = &lastSync.EndedAt
+		status.LastSyncStatus = lastSync.Status
+	}
+	lastFailed, err := ew.db.GetLastSyncActivity(ew.eventName, challengeName, "failed")
+	if err != nil {
+		return status, fmt.Errorf("failed to get last sync error: %w", err)
+	}
+	if lastFailed != nil {
+		status.LastError = lastFailed.Error
+		status.LastErrorAt = &lastFailed.EndedAt
+	}
+	stats, err := ew.db.GetSyncStats(ew.eventName, challengeName)
+	if err != nil {
+		return status, fmt.Errorf("failed to get sync stats: %w", err)
+	}
+	if len(stats) > 0 {
+		status.Stats = &stats[0]
+	}
+
+	if ew.scriptMgr != nil {
+		if scripts := ew.scriptMgr.GetActiveIntervalScripts()[challengeName]; len(scripts) > 0 {
+			sort.Strings(scripts)
+			status.ActiveScripts = scripts
+		}
+	}
+
+	if relPath, err := filepath.Rel(ew.eventPath, challengeCwd); err == nil {
+		if id, exists := ew.getChallengeID(relPath); exists {
+			status.MappingID = id
+		}
+	}
+
+	if held, ok := ew.heldUpdate(challengeName); ok {
+		status.HeldUpdate = held.String()
+	}
+	status.Paused = ew.challengePaused(challengeName)
+
+	scans, err := ew.db.GetImageScans(ew.eventName, challengeName, 1)
+	if err != nil {
+		return status, fmt.Errorf("failed to get last image scan: %w", err)
+	}
+	if len(scans) > 0 {
+		status.LastImageScan = &scans[0]
+	}
+
+	runs, err := ew.db.GetSolverRuns(ew.eventName, challengeName, 1)
+	if err != nil {
+		return status, fmt.Errorf("failed to get last solver run: %w", err)
+	}
+	if len(runs) > 0 {
+		status.LastSolverRun = &runs[0]
+	}
+
+	return status, nil
+}
+
 // GetScriptManager returns the script manager for this event
 func (ew *EventWatcher) GetScriptManager() *scripts.Manager {
 	return ew.scriptMgr
@@ -902,16 +1788,18 @@ func (ew *EventWatcher) enqueueSyncForWatchedChallenges() {
 		return
 	}
 
+	challengeFiles := make(map[string]string, len(challenges))
 	for challengeName, challengePath := range challenges {
-		challengeFile := filepath.Join(challengePath, "challenge.yaml")
-		if _, err := os.Stat(challengeFile); os.IsNotExist(err) {
-			challengeFile = filepath.Join(challengePath, "challenge.yml")
-			if _, err := os.Stat(challengeFile); os.IsNotExist(err) {
-				log.InfoH3("[%s] Skipping %s: no challenge.yaml/challenge.yml found", ew.eventName, challengeName)
-				continue
-			}
+		challengeFile, err := registry.FindChallengeFile(challengePath)
+		if err != nil {
+			log.InfoH3("[%s] Skipping %s: no challenge.yaml/challenge.yml found", ew.eventName, challengeName)
+			continue
 		}
+		challengeFiles[challengeName] = challengeFile
+	}
 
+	ew.startBulkSync(challengeFiles)
+	for _, challengeFile := range challengeFiles {
 		ew.HandleFileChange(challengeFile)
 	}
 }
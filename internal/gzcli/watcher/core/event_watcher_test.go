@@ -0,0 +1,514 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/imagescan"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestEventWatcher_SyncWindows(t *testing.T) {
+	ew := &EventWatcher{lastSyncAt: make(map[string]time.Time)}
+
+	dir := t.TempDir()
+	if debounce, cooldown := ew.syncWindows(dir); debounce != defaultDebounce || cooldown != 0 {
+		t.Errorf("no challenge.yaml: got %v/%v, want %v/0", debounce, cooldown, defaultDebounce)
+	}
+
+	yaml := "name: big\nwatch:\n  debounce: 5s\n  cooldown: 1m\n"
+	if err := os.WriteFile(filepath.Join(dir, "challenge.yml"), []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if debounce, cooldown := ew.syncWindows(dir); debounce != 5*time.Second || cooldown != time.Minute {
+		t.Errorf("overrides: got %v/%v, want 5s/1m", debounce, cooldown)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "challenge.yml"), []byte("name: small\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if debounce, cooldown := ew.syncWindows(dir); debounce != defaultDebounce || cooldown != 0 {
+		t.Errorf("no watch block: got %v/%v, want %v/0", debounce, cooldown, defaultDebounce)
+	}
+}
+
+func TestEventWatcher_CooldownRemaining(t *testing.T) {
+	ew := &EventWatcher{lastSyncAt: make(map[string]time.Time)}
+
+	if got := ew.cooldownRemaining("chal", time.Minute); got != 0 {
+		t.Errorf("never synced: remaining = %v, want 0", got)
+	}
+
+	ew.setLastSyncAt("chal", time.Now().Add(-20*time.Second))
+	if got := ew.cooldownRemaining("chal", time.Minute); got < 39*time.Second || got > 40*time.Second {
+		t.Errorf("synced 20s ago: remaining = %v, want ~40s", got)
+	}
+	if got := ew.cooldownRemaining("chal", 10*time.Second); got > 0 {
+		t.Errorf("cooldown elapsed: remaining = %v, want <= 0", got)
+	}
+	if got := ew.cooldownRemaining("chal", 0); got != 0 {
+		t.Errorf("no cooldown: remaining = %v, want 0", got)
+	}
+}
+
+func TestEventWatcher_SleepStopsWithWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ew := &EventWatcher{ctx: ctx}
+	cancel()
+
+	start := time.Now()
+	if ew.sleep(time.Hour) {
+		t.Error("sleep() = true after the watcher stopped, want false")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleep() did not return promptly after the watcher stopped")
+	}
+}
+
+func TestEventWatcher_FormatChallengeYaml(t *testing.T) {
+	ew := &EventWatcher{config: watchertypes.WatcherConfig{FormatYaml: true}}
+
+	path := filepath.Join(t.TempDir(), "challenge.yml")
+	if err := os.WriteFile(path, []byte("value: 1\nname: chal\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ew.formatChallengeYaml("chal", path)
+	data, _ := os.ReadFile(path)
+	if string(data) != "name: \"chal\"\nvalue: 1\n" {
+		t.Fatalf("formatted file = %q", data)
+	}
+	if !ew.isOwnFormatting(path) {
+		t.Error("the formatter's own rewrite should not trigger a sync")
+	}
+
+	if err := os.WriteFile(path, []byte("name: \"chal\"\nvalue: 2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if ew.isOwnFormatting(path) {
+		t.Error("edits after formatting should trigger a sync")
+	}
+}
+
+func TestEventWatcher_FormatChallengeYamlDisabled(t *testing.T) {
+	ew := &EventWatcher{}
+
+	path := filepath.Join(t.TempDir(), "challenge.yml")
+	if err := os.WriteFile(path, []byte("value: 1\nname: chal\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ew.formatChallengeYaml("chal", path)
+	if data, _ := os.ReadFile(path); string(data) != "value: 1\nname: chal\n" {
+		t.Errorf("formatting without FormatYaml rewrote the file: %q", data)
+	}
+}
+
+func TestEventWatcher_SyncRefusesMappingOfAnotherGame(t *testing.T) {
+	ew := &EventWatcher{
+		eventName:         "clone",
+		eventPath:         "/events/clone",
+		challengeMappings: make(map[string]challengeMapping),
+	}
+	ew.setChallengeID("web/chal", 42, 1, "Chal")
+
+	conf := &config.Config{Event: gzapi.Game{Id: 2}}
+	challengeConf := config.ChallengeYaml{Name: "Chal", Category: "Web", Cwd: "/events/clone/web/chal"}
+	challenges := []gzapi.Challenge{{Id: 42, GameId: 1, Title: "Chal"}}
+
+	_, err := ew.syncChallengeInternal("web/chall", conf, challengeConf, challenges, watchertypes.UpdateMetadata)
+	if !errors.Is(err, errWrongGameMapping) {
+		t.Fatalf("mapping of game 1 synced into game 2: got %v, want errWrongGameMapping", err)
+	}
+	if id, ok := ew.getChallengeID("web/chal"); !ok || id != 42 {
+		t.Errorf("refused mapping changed: got %d/%v, want 42/true", id, ok)
+	}
+}
+
+func TestEventWatcher_CheckMappingGame(t *testing.T) {
+	ew := &EventWatcher{eventName: "ctf"}
+	tests := []struct {
+		mapped, game int
+		wantErr      bool
+	}{
+		{mapped: 1, game: 1},
+		{mapped: 0, game: 1}, // Stored before game IDs were recorded
+		{mapped: 1, game: 0},
+		{mapped: 1, game: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ew.checkMappingGame("web/chal", 42, tt.mapped, tt.game)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkMappingGame(%d, %d) = %v, wantErr %v", tt.mapped, tt.game, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEventWatcher_RemapChallenges(t *testing.T) {
+	db := database.New(filepath.Join(t.TempDir(), "test.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ew := &EventWatcher{eventName: "ctf", db: db, challengeMappings: make(map[string]challengeMapping)}
+	ew.setChallengeID("web/a", 1, 7, "A")
+	ew.setChallengeID("web/b", 2, 7, "B")
+
+	remapped, err := ew.RemapChallenges([]string{"web/a", "web/missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remapped) != 1 || remapped[0] != "web/a" {
+		t.Errorf("remapped = %v, want [web/a]", remapped)
+	}
+	if _, ok := ew.getChallengeID("web/a"); ok {
+		t.Error("web/a still mapped")
+	}
+	if mapping, err := db.GetChallengeMapping("ctf", "web/a"); err != nil || mapping != nil {
+		t.Errorf("web/a still stored: %v, %v", mapping, err)
+	}
+	if _, ok := ew.getChallengeID("web/b"); !ok {
+		t.Error("web/b should stay mapped")
+	}
+}
+
+func TestEventWatcher_ChallengeStatus(t *testing.T) {
+	db := database.New(filepath.Join(t.TempDir(), "test.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	eventPath := t.TempDir()
+	challengeDir := filepath.Join(eventPath, "web", "chal")
+	if err := os.MkdirAll(challengeDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = fsWatcher.Close() }()
+
+	ew := &EventWatcher{
+		eventName:         "ctf",
+		eventPath:         eventPath,
+		db:                db,
+		challengeMgr:      challenge.NewManager(fsWatcher),
+		challengeMappings: make(map[string]challengeMapping),
+	}
+	if err := ew.challengeMgr.AddChallenge("web/chal", challengeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ew.ChallengeStatus("web/missing"); err == nil {
+		t.Error("status of an unwatched challenge should fail")
+	}
+
+	status, err := ew.ChallengeStatus("web/chal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "" || status.LastSync != nil || status.MappingID != 0 || len(status.ActiveScripts) != 0 {
+		t.Errorf("unexpected status before any sync: %+v", status)
+	}
+
+	now := time.Now()
+	ew.recordActivity("web/chal", watchertypes.ActivitySync, now.Add(-time.Minute), now.Add(-time.Minute), errors.New("upload failed"))
+	ew.recordActivity("web/chal", watchertypes.ActivitySync, now, now, nil)
+	ew.UpdateChallengeState("web/chal", "watching", "", nil)
+	ew.setChallengeID(filepath.Join("web", "chal"), 42, 7, "Chal")
+
+	status, err = ew.ChallengeStatus("web/chal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "watching" || status.LastSyncStatus != "success" || status.LastSync == nil {
+		t.Errorf("unexpected sync state: %+v", status)
+	}
+	if status.LastError != "upload failed" || status.LastErrorAt == nil {
+		t.Errorf("last error = %q at %v, want upload failed", status.LastError, status.LastErrorAt)
+	}
+	if status.MappingID != 42 {
+		t.Errorf("mapping ID = %d, want 42", status.MappingID)
+	}
+	if status.LastImageScan != nil {
+		t.Errorf("last image scan = %+v, want none", status.LastImageScan)
+	}
+
+	db.LogImageScan(watchertypes.ImageScan{Event: "ctf", ChallengeName: "web/chal", Image: "web:1", Status: "passed"})
+	status, err = ew.ChallengeStatus("web/chal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.LastImageScan == nil || status.LastImageScan.Image != "web:1" {
+		t.Errorf("last image scan = %+v, want web:1", status.LastImageScan)
+	}
+}
+
+func TestEventWatcher_ScanImage(t *testing.T) {
+	db := database.New(filepath.Join(t.TempDir(), "test.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tests := []struct {
+		name        string
+		command     string
+		block       bool
+		wantErr     bool
+		wantStatus  string
+		wantBlocked bool
+	}{
+		{"passed", "true", true, false, imagescan.StatusPassed, false},
+		{"findings warn", "exit 1", false, false, imagescan.StatusFindings, false},
+		{"findings block", "exit 1", true, true, imagescan.StatusFindings, true},
+		{"scanner missing block", "gzcli-no-such-scanner {{.Image}}", true, true, imagescan.StatusError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, err := imagescan.NewScanner(tt.command, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ew := &EventWatcher{
+				eventName: "ctf",
+				ctx:       context.Background(),
+				db:        db,
+				scanner:   scanner,
+				config:    watchertypes.WatcherConfig{ImageScanBlock: tt.block},
+			}
+
+			err = ew.scanImage(tt.name, t.TempDir(), "registry/web:1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("scanImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			scans, err := db.GetImageScans("ctf", tt.name, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(scans) != 1 || scans[0].Status != tt.wantStatus || scans[0].Blocked != tt.wantBlocked {
+				t.Errorf("recorded scans = %+v, want one %s scan (blocked %v)", scans, tt.wantStatus, tt.wantBlocked)
+			}
+		})
+	}
+
+	// Challenges without a container image are not scanned
+	ew := &EventWatcher{eventName: "ctf", db: db, config: watchertypes.WatcherConfig{ImageScanBlock: true}}
+	ew.scanner, _ = imagescan.NewScanner("exit 1", time.Minute)
+	if err := ew.scanImage("static", t.TempDir(), ""); err != nil {
+		t.Errorf("scanImage() without image error = %v", err)
+	}
+}
+
+func writeGameWindow(t *testing.T, eventPath string, start, end time.Time) {
+	t.Helper()
+	gzevent := "title: CTF\nstart: " + start.Format(time.RFC3339) + "\nend: " + end.Format(time.RFC3339) + "\n"
+	if err := os.WriteFile(filepath.Join(eventPath, config.GZEVENT_FILE), []byte(gzevent), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEventWatcher_LiveWindow(t *testing.T) {
+	ew := &EventWatcher{eventName: "ctf", eventPath: t.TempDir()}
+	now := time.Now()
+
+	if live, err := ew.liveWindow(now); !live || err == nil {
+		t.Errorf("no .gzevent: liveWindow() = %v, %v, want true and an error", live, err)
+	}
+	if err := os.WriteFile(filepath.Join(ew.eventPath, config.GZEVENT_FILE), []byte("title: CTF\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if live, err := ew.liveWindow(now); !live || err == nil {
+		t.Errorf("no start or end: liveWindow() = %v, %v, want true and an error", live, err)
+	}
+	writeGameWindow(t, ew.eventPath, now.Add(-time.Hour), now.Add(time.Hour))
+	if live, err := ew.liveWindow(now); !live || err != nil {
+		t.Errorf("during the game: liveWindow() = %v, %v, want true", live, err)
+	}
+	if live, err := ew.liveWindow(now.Add(2 * time.Hour)); live || err != nil {
+		t.Errorf("after the game: liveWindow() = %v, %v, want false", live, err)
+	}
+	if live, err := ew.liveWindow(now.Add(-2 * time.Hour)); live || err != nil {
+		t.Errorf("before the game: liveWindow() = %v, %v, want false", live, err)
+	}
+}
+
+func TestEventWatcher_LiveLockAllows(t *testing.T) {
+	newWatcher := func(liveLock bool) *EventWatcher {
+		return &EventWatcher{
+			eventName:     "ctf",
+			eventPath:     t.TempDir(),
+			config:        watchertypes.WatcherConfig{LiveLock: liveLock},
+			heldUpdates:   make(map[string]watchertypes.UpdateType),
+			approvedSyncs: make(map[string]bool),
+		}
+	}
+	now := time.Now()
+
+	ew := newWatcher(false)
+	writeGameWindow(t, ew.eventPath, now.Add(-time.Hour), now.Add(time.Hour))
+	if !ew.liveLockAllows("web/a", watchertypes.UpdateFullRedeploy) {
+		t.Error("live lock disabled: redeploy held, want allowed")
+	}
+
+	ew = newWatcher(true)
+	writeGameWindow(t, ew.eventPath, now.Add(-time.Hour), now.Add(time.Hour))
+	if !ew.liveLockAllows("web/a", watchertypes.UpdateMetadata) {
+		t.Error("metadata sync held, want allowed")
+	}
+	if ew.liveLockAllows("web/a", watchertypes.UpdateAttachment) {
+		t.Error("attachment sync allowed, want held")
+	}
+	if ew.liveLockAllows("web/a", watchertypes.UpdateFullRedeploy) {
+		t.Error("redeploy allowed, want held")
+	}
+	if ew.liveLockAllows("web/a", watchertypes.UpdateMetadata) {
+		t.Error("metadata sync of a challenge with a held sync allowed, want held")
+	}
+	if held, ok := ew.heldUpdate("web/a"); !ok || held != watchertypes.UpdateFullRedeploy {
+		t.Errorf("heldUpdate() = %v, %v, want full_redeploy, true", held, ok)
+	}
+
+	ew.approvedSyncs["web/a"] = true
+	if !ew.liveLockAllows("web/a", watchertypes.UpdateFullRedeploy) {
+		t.Error("approved redeploy held, want allowed")
+	}
+	if _, ok := ew.heldUpdate("web/a"); ok {
+		t.Error("held sync kept after the approved sync ran")
+	}
+	if ew.liveLockAllows("web/a", watchertypes.UpdateFullRedeploy) {
+		t.Error("approval reused by a second redeploy, want held")
+	}
+
+	writeGameWindow(t, ew.eventPath, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	if !ew.liveLockAllows("web/a", watchertypes.UpdateFullRedeploy) {
+		t.Error("after the game: redeploy held, want allowed")
+	}
+	if _, ok := ew.heldUpdate("web/a"); ok {
+		t.Error("held sync kept after the game ended")
+	}
+
+	// An unreadable game window holds syncs instead of letting them through
+	if err := os.WriteFile(filepath.Join(ew.eventPath, config.GZEVENT_FILE), []byte("start: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if ew.liveLockAllows("web/a", watchertypes.UpdateFullRedeploy) {
+		t.Error("unreadable .gzevent: redeploy allowed, want held")
+	}
+	if !ew.liveLockAllows("web/b", watchertypes.UpdateMetadata) {
+		t.Error("unreadable .gzevent: metadata sync held, want allowed")
+	}
+}
+
+func TestEventWatcher_PauseSync(t *testing.T) {
+	db := database.New(filepath.Join(t.TempDir(), "watcher.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = fsWatcher.Close() }()
+
+	newWatcher := func() *EventWatcher {
+		ew := &EventWatcher{
+			eventName:      "ctf",
+			db:             db,
+			challengeMgr:   challenge.NewManager(fsWatcher),
+			paused:         make(map[string]watchertypes.PausedSync),
+			skippedUpdates: make(map[string]watchertypes.UpdateType),
+		}
+		if err := ew.challengeMgr.AddChallenge("web/a", t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		return ew
+	}
+	ew := newWatcher()
+
+	if err := ew.PauseSync("web/missing", ""); err == nil {
+		t.Error("PauseSync() of an unwatched challenge succeeded")
+	}
+	if ew.syncPaused("web/a", watchertypes.UpdateMetadata) {
+		t.Error("sync skipped before pausing")
+	}
+	if err := ew.PauseSync("", "freeze"); err != nil {
+		t.Fatalf("PauseSync() of the event failed: %v", err)
+	}
+	if err := ew.PauseSync("web/a", "broken"); err != nil {
+		t.Fatalf("PauseSync() of a challenge failed: %v", err)
+	}
+	if !ew.syncPaused("web/a", watchertypes.UpdateFullRedeploy) || !ew.syncPaused("web/a", watchertypes.UpdateMetadata) {
+		t.Error("sync of a paused challenge not skipped")
+	}
+
+	// Pauses are restored from the database
+	restarted := newWatcher()
+	restarted.loadPausedSyncs()
+	if paused := restarted.PausedSyncs(); len(paused) != 2 || paused[0].Challenge != "" || paused[1].Reason != "broken" {
+		t.Errorf("restored pauses = %+v, want the event then web/a", paused)
+	}
+
+	// The challenge stays paused by itself when the event is resumed
+	synced, err := ew.ResumeSync("")
+	if err != nil || len(synced) != 0 {
+		t.Errorf("ResumeSync() of the event = %v, %v, want nothing synced", synced, err)
+	}
+	if !ew.challengePaused("web/a") || ew.skippedUpdates["web/a"] != watchertypes.UpdateFullRedeploy {
+		t.Errorf("web/a paused = %v, skipped = %v, want paused with a full redeploy skipped", ew.challengePaused("web/a"), ew.skippedUpdates["web/a"])
+	}
+	if _, err := ew.ResumeSync(""); err == nil {
+		t.Error("ResumeSync() of an event not paused succeeded")
+	}
+	if paused, err := db.GetPausedSyncs("ctf"); err != nil || len(paused) != 1 {
+		t.Errorf("stored pauses = %+v, %v, want only web/a", paused, err)
+	}
+}
+
+func TestEventWatcher_SyncChallengeRefused(t *testing.T) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = fsWatcher.Close() }()
+
+	ew := &EventWatcher{
+		eventName:      "ctf",
+		eventPath:      t.TempDir(),
+		challengeMgr:   challenge.NewManager(fsWatcher),
+		paused:         make(map[string]watchertypes.PausedSync),
+		skippedUpdates: make(map[string]watchertypes.UpdateType),
+	}
+	if err := ew.challengeMgr.AddChallenge("web/a", t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ew.SyncChallenge(context.Background(), "web/missing"); err == nil {
+		t.Error("SyncChallenge() of a challenge missing from the event succeeded")
+	}
+	if err := ew.PauseSync("web/a", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.SyncChallenge(context.Background(), "web/a"); err == nil {
+		t.Error("SyncChallenge() of a paused challenge succeeded")
+	}
+	if ew.skippedUpdates["web/a"] != watchertypes.UpdateFullRedeploy {
+		t.Errorf("skipped = %v, want the full redeploy synced on resume", ew.skippedUpdates["web/a"])
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/scripts"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// healthcheckChallenge exposes the scripts of a challenge.yaml to the script manager
+type healthcheckChallenge struct {
+	name    string
+	cwd     string
+	scripts map[string]scripts.ScriptValue
+}
+
+func (c healthcheckChallenge) GetName() string                            { return c.name }
+func (c healthcheckChallenge) GetCwd() string                             { return c.cwd }
+func (c healthcheckChallenge) GetScripts() map[string]scripts.ScriptValue { return c.scripts }
+
+// startHealthchecks starts the healthchecks of all watched challenges from
+// their challenge.yaml as found on disk
+func (ew *EventWatcher) startHealthchecks() {
+	for challengeName, challengePath := range ew.challengeMgr.GetChallenges() {
+		challengeConf, ok, err := readChallengeYaml(challengePath)
+		if err != nil {
+			log.Error("[%s] Failed to parse challenge YAML of %s for its healthcheck: %v", ew.eventName, challengeName, err)
+			continue
+		}
+		if ok {
+			ew.startHealthcheck(challengeName, challengeConf)
+		}
+	}
+}
+
+// readChallengeYaml reads the challenge.yaml of a challenge directory as
+// found on disk, without processing its templates. It returns false when the
+// directory has no readable challenge file.
+func readChallengeYaml(challengePath string) (config.ChallengeYaml, bool, error) {
+	var challengeConf config.ChallengeYaml
+	path, err := registry.FindChallengeFile(challengePath)
+	if err != nil {
+		return challengeConf, false, nil
+	}
+	//nolint:gosec // G304: File paths come from validated challenges directory
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return challengeConf, false, nil
+	}
+	if err := fileutil.ParseYamlFromBytes(content, &challengeConf); err != nil {
+		return challengeConf, false, err
+	}
+	challengeConf.Cwd = challengePath
+	return challengeConf, true, nil
+}
+
+// startHealthcheck (re)starts the healthcheck script of a challenge, or stops
+// it when the script was removed. Healthchecks do not run in dry-run mode,
+// where they could not redeploy anything.
+func (ew *EventWatcher) startHealthcheck(challengeName string, challengeConf config.ChallengeYaml) {
+	if ew.scriptMgr == nil || ew.config.DryRun {
+		return
+	}
+
+	var threshold int
+	challengeScripts := make(map[string]scripts.ScriptValue)
+	if sv, ok := challengeConf.Scripts[scripts.HealthcheckScript]; ok {
+		challengeScripts[scripts.HealthcheckScript] = &sv
+		threshold = sv.GetFailureThreshold()
+	}
+	hc := healthcheckChallenge{name: challengeName, cwd: challengeConf.Cwd, scripts: challengeScripts}
+
+	ew.setHealthFailing(challengeName, false)
+	if err := ew.scriptMgr.StartHealthcheck(hc, threshold, ew.handleHealthcheck); err != nil {
+		log.Error("[%s] %v", ew.eventName, err)
+		ew.LogToDatabase("ERROR", "healthcheck", challengeName, scripts.HealthcheckScript, "Healthcheck not started", err.Error(), 0)
+	}
+}
+
+// handleHealthcheck records the result of a healthcheck and redeploys the
+// challenge once it is unhealthy
+func (ew *EventWatcher) handleHealthcheck(challengeName string, failures int, unhealthy bool, err error) {
+	if err == nil {
+		if ew.setHealthFailing(challengeName, false) {
+			log.Info("[%s] 💚 Challenge %s is healthy again", ew.eventName, challengeName)
+			ew.LogToDatabase("INFO", "healthcheck", challengeName, scripts.HealthcheckScript, "Healthcheck passing again", "", 0)
+			if !ew.isUpdating(challengeName) {
+				ew.UpdateChallengeState(challengeName, "watching", "", ew.scriptMgr.GetActiveIntervalScripts())
+			}
+		}
+		return
+	}
+
+	ew.setHealthFailing(challengeName, true)
+	message := fmt.Sprintf("Healthcheck failed %d time(s) in a row", failures)
+	if !unhealthy {
+		log.Info("[%s] ⚠️  %s for %s: %v", ew.eventName, message, challengeName, err)
+		ew.LogToDatabase("WARN", "healthcheck", challengeName, scripts.HealthcheckScript, message, err.Error(), 0)
+		return
+	}
+
+	log.Error("[%s] Challenge %s is unhealthy (%s), redeploying: %v", ew.eventName, challengeName, message, err)
+	ew.LogToDatabase("ERROR", "healthcheck", challengeName, scripts.HealthcheckScript, message+", redeploying", err.Error(), 0)
+	ew.UpdateChallengeState(challengeName, "unhealthy", err.Error(), ew.scriptMgr.GetActiveIntervalScripts())
+	ew.announceUnhealthy(challengeName, fmt.Sprintf("%s: %v", message, err))
+	ew.Redeploy(challengeName)
+}
+
+// setHealthFailing records whether the latest healthcheck of a challenge
+// failed and returns the previous value
+func (ew *EventWatcher) setHealthFailing(challengeName string, failing bool) bool {
+	ew.healthFailingMu.Lock()
+	defer ew.healthFailingMu.Unlock()
+	previous := ew.healthFailing[challengeName]
+	if failing {
+		ew.healthFailing[challengeName] = true
+	} else {
+		delete(ew.healthFailing, challengeName)
+	}
+	return previous
+}
+
+// Redeploy queues a full redeploy of a watched challenge through the regular
+// sync pipeline, whatever files changed
+func (ew *EventWatcher) Redeploy(challengeName string) {
+	challengePath, ok := ew.challengeMgr.GetChallenges()[challengeName]
+	if !ok {
+		return
+	}
+	challengeFile, err := registry.FindChallengeFile(challengePath)
+	if err != nil {
+		log.Error("[%s] Cannot redeploy %s: %v", ew.eventName, challengeName, err)
+		return
+	}
+
+	ew.forcedUpdatesMu.Lock()
+	ew.forcedUpdates[challengeName] = watchertypes.UpdateFullRedeploy
+	ew.forcedUpdatesMu.Unlock()
+
+	// Bypass the formatter check of HandleFileChange: the file is unchanged
+	ew.processChange(challengeFile)
+}
+
+// takeForcedUpdate returns and clears the forced update type of a challenge
+func (ew *EventWatcher) takeForcedUpdate(challengeName string) (watchertypes.UpdateType, bool) {
+	ew.forcedUpdatesMu.Lock()
+	defer ew.forcedUpdatesMu.Unlock()
+	updateType, ok := ew.forcedUpdates[challengeName]
+	delete(ew.forcedUpdates, challengeName)
+	return updateType, ok
+}
+
+// announceUnhealthy posts the notification of an unhealthy challenge in the
+// background. Failures are logged only.
+func (ew *EventWatcher) announceUnhealthy(challengeName, message string) {
+	if ew.announcer == nil {
+		return
+	}
+
+	category := ""
+	if challengePath, ok := ew.challengeMgr.GetChallenges()[challengeName]; ok {
+		category = registry.Category(ew.eventPath, challengePath)
+	}
+	a := announce.Announcement{
+		Type:      announce.TypeChallengeUnhealthy,
+		Event:     ew.eventName,
+		Challenge: challengeName,
+		Category:  category,
+		Message:   message,
+		Time:      time.Now().UTC(),
+	}
+	ew.wg.Add(1)
+	go func() {
+		defer ew.wg.Done()
+		if err := ew.announcer.Send(ew.ctx, a); err != nil {
+			log.Error("[%s] Failed to announce unhealthy challenge %s: %v", ew.eventName, challengeName, err)
+			ew.LogToDatabase("ERROR", "announce", challengeName, "", "Failed to announce unhealthy challenge", err.Error(), 0)
+		}
+	}()
+}
@@ -1,12 +1,15 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	godaemon "github.com/sevlyar/go-daemon"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/metrics"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/daemon"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
@@ -107,10 +110,37 @@ func (w *Watcher) startAsDaemon() error {
 	return fmt.Errorf("unexpected daemon state")
 }
 
-// startWatcher starts the actual watcher functionality
+// startWatcher starts the actual watcher functionality, in safe mode after
+// repeated crashes. The error it fails with is kept for the next run.
 func (w *Watcher) startWatcher() error {
+	crashState, err := daemon.RecordStart(w.config.PidFile, time.Now())
+	if err != nil {
+		log.Error("Failed to record watcher start, crash loops will not be detected: %v", err)
+	} else {
+		w.crashState = crashState
+	}
+	if err := daemon.CaptureCrashes(w.config.PidFile); err != nil {
+		log.Error("Failed to capture crash traces: %v", err)
+	}
+
+	if err := w.runWatcher(); err != nil {
+		if recordErr := daemon.RecordError(w.config.PidFile, err); recordErr != nil {
+			log.Error("Failed to record watcher error: %v", recordErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// runWatcher initializes the database and socket server, then the event
+// watchers unless in safe mode
+func (w *Watcher) runWatcher() error {
 	// Initialize database
-	w.db = database.New(w.config.DatabasePath, w.config.DatabaseEnabled)
+	source := w.config.DatabasePath
+	if w.config.DatabaseURL != "" {
+		source = w.config.DatabaseURL
+	}
+	w.db = database.New(source, w.config.DatabaseEnabled)
 	if err := w.db.Init(); err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
@@ -122,6 +152,20 @@ func (w *Watcher) startWatcher() error {
 		return fmt.Errorf("failed to initialize socket server: %w", err)
 	}
 
+	// Start socket server if enabled
+	if w.config.SocketEnabled && w.socketServer != nil {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.socketServer.Run(w.ctx)
+		}()
+	}
+
+	if w.SafeMode() {
+		w.logSafeMode()
+		return nil
+	}
+
 	// Log to database
 	if w.db != nil {
 		w.db.LogToDatabase("INFO", "watcher", "", "", "File watcher started", "", 0)
@@ -132,13 +176,9 @@ func (w *Watcher) startWatcher() error {
 		return fmt.Errorf("failed to start event watchers: %w", err)
 	}
 
-	// Start socket server if enabled
-	if w.config.SocketEnabled && w.socketServer != nil {
-		w.wg.Add(1)
-		go func() {
-			defer w.wg.Done()
-			w.socketServer.Run(w.ctx)
-		}()
+	metrics.Default.Register("watcher", w)
+	if w.config.MetricsAddr != "" {
+		w.startMetricsServer()
 	}
 
 	log.Info("File watcher started successfully")
@@ -213,6 +253,13 @@ func (w *Watcher) Stop() error {
 		log.Error("Timeout waiting for goroutines to finish")
 	}
 
+	metrics.Default.Unregister("watcher")
+	if w.metricsServer != nil {
+		if err := w.metricsServer.Close(); err != nil {
+			log.Error("Failed to close metrics endpoint: %v", err)
+		}
+	}
+
 	// Close socket server
 	if w.socketServer != nil {
 		if err := w.socketServer.Close(); err != nil {
@@ -231,10 +278,58 @@ func (w *Watcher) Stop() error {
 		}
 	}
 
+	if err := daemon.RecordCleanStop(w.config.PidFile); err != nil {
+		log.Error("Failed to record watcher stop: %v", err)
+	}
+
 	log.Info("File watcher stopped")
 	return nil
 }
 
+// SafeMode reports whether the watcher started in safe mode after repeated
+// crashes: challenges are not watched, only the socket API answers
+func (w *Watcher) SafeMode() bool {
+	return w.crashState != nil && w.crashState.SafeMode
+}
+
+// logSafeMode reports the crash loop that made the watcher start in safe mode
+func (w *Watcher) logSafeMode() {
+	state := w.crashState
+	log.Error("⚠️  Watcher crashed %d times in a row, starting in SAFE MODE: challenges are not watched", state.Crashes)
+	if state.LastCrashAt != nil {
+		log.Error("Last crash: %s", state.LastCrashAt.Local().Format(time.DateTime))
+	}
+	if state.LastError != "" {
+		log.Error("Last error: %s", state.LastError)
+	}
+	if state.LastTrace != "" {
+		log.Error("Last panic trace kept in %s", daemon.CrashFile(w.config.PidFile))
+	}
+	log.Error("Inspect 'gzcli watch status', fix the cause, then run 'gzcli watch stop' and 'gzcli watch start'")
+
+	if w.db != nil {
+		w.db.LogToDatabase("ERROR", "watcher", "", "", fmt.Sprintf("File watcher started in safe mode after %d crashes", state.Crashes), state.LastError, 0)
+	}
+}
+
+// startMetricsServer serves the metrics of the process, including the API
+// client's, at http://<MetricsAddr>/metrics
+func (w *Watcher) startMetricsServer() {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Default.Handler(""))
+	w.metricsServer = &http.Server{
+		Addr:              w.config.MetricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := w.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Metrics endpoint error: %v", err)
+		}
+	}()
+	log.Info("Metrics available at http://%s/metrics", w.config.MetricsAddr)
+}
+
 // IsWatching returns true if the watcher is currently active
 func (w *Watcher) IsWatching() bool {
 	select {
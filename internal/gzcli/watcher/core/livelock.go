@@ -0,0 +1,128 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/fileutil"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// liveWindow reports whether now is within the start-end window of the
+// event's .gzevent. The file is read on each call so that rescheduling the
+// event applies without restarting the watcher. When the window cannot be
+// read the game is assumed live, so the live lock fails closed.
+func (ew *EventWatcher) liveWindow(now time.Time) (bool, error) {
+	var window struct {
+		Start gzapi.CustomTime `yaml:"start"`
+		End   gzapi.CustomTime `yaml:"end"`
+	}
+	if err := fileutil.ParseYamlFromFile(filepath.Join(ew.eventPath, config.GZEVENT_FILE), &window); err != nil {
+		return true, fmt.Errorf("failed to read the game window: %w", err)
+	}
+	if window.Start.IsZero() || window.End.IsZero() {
+		return true, fmt.Errorf("%s has no start or end", config.GZEVENT_FILE)
+	}
+	return !now.Before(window.Start.Time) && now.Before(window.End.Time), nil
+}
+
+// liveLockAllows reports whether a sync of updateType may run now. While the
+// game is live, or its window cannot be read, metadata syncs run with a
+// warning, and attachment updates and redeploys are held until approved with
+// ApproveSync. Once a sync is held,
+// later metadata syncs of the challenge are held with it, since they would
+// apply its changes too.
+func (ew *EventWatcher) liveLockAllows(challengeName string, updateType watchertypes.UpdateType) bool {
+	if !ew.config.LiveLock {
+		return true
+	}
+
+	live, windowErr := ew.liveWindow(time.Now())
+	if windowErr != nil {
+		log.Error("[%s] Live lock assumes the game is live: %v", ew.eventName, windowErr)
+		ew.LogToDatabase("ERROR", "livelock", challengeName, "", "Live lock assumes the game is live", windowErr.Error(), 0)
+	}
+
+	ew.liveLockMu.Lock()
+	if !live {
+		// The sync applies whatever was held
+		delete(ew.approvedSyncs, challengeName)
+		delete(ew.heldUpdates, challengeName)
+		ew.liveLockMu.Unlock()
+		return true
+	}
+	held, isHeld := ew.heldUpdates[challengeName]
+	approved := ew.approvedSyncs[challengeName]
+	allowed := approved || (updateType == watchertypes.UpdateMetadata && !isHeld)
+	switch {
+	case approved:
+		delete(ew.approvedSyncs, challengeName)
+		delete(ew.heldUpdates, challengeName)
+	case !allowed:
+		ew.heldUpdates[challengeName] = max(held, updateType)
+	}
+	ew.liveLockMu.Unlock()
+
+	if allowed {
+		message := fmt.Sprintf("Syncing %v during the live game", updateType)
+		if approved {
+			message = fmt.Sprintf("Syncing approved %v during the live game", updateType)
+		}
+		log.Info("[%s] ⚠️  %s: %s", ew.eventName, message, challengeName)
+		ew.LogToDatabase("WARN", "livelock", challengeName, "", message, "", 0)
+		return true
+	}
+
+	message := fmt.Sprintf("Held %v sync during the live game, run 'gzcli watch approve --event %s %s' to apply it", max(held, updateType), ew.eventName, challengeName)
+	log.Error("[%s] 🔒 %s", ew.eventName, message)
+	ew.LogToDatabase("WARN", "livelock", challengeName, "", message, "", 0)
+	if ew.scriptMgr != nil {
+		ew.UpdateChallengeState(challengeName, "held", "", ew.scriptMgr.GetActiveIntervalScripts())
+	}
+	return false
+}
+
+// heldUpdate returns the update type held for a challenge by the live lock
+func (ew *EventWatcher) heldUpdate(challengeName string) (watchertypes.UpdateType, bool) {
+	ew.liveLockMu.Lock()
+	defer ew.liveLockMu.Unlock()
+	updateType, ok := ew.heldUpdates[challengeName]
+	return updateType, ok
+}
+
+// ApproveSync lets the held sync of a challenge through the live lock and
+// runs it, returning the update type applied
+func (ew *EventWatcher) ApproveSync(challengeName string) (watchertypes.UpdateType, error) {
+	challengePath, ok := ew.challengeMgr.GetChallenges()[challengeName]
+	if !ok {
+		return watchertypes.UpdateNone, fmt.Errorf("challenge '%s' is not watched in event '%s'", challengeName, ew.eventName)
+	}
+	challengeFile, err := registry.FindChallengeFile(challengePath)
+	if err != nil {
+		return watchertypes.UpdateNone, err
+	}
+	updateType, held := ew.heldUpdate(challengeName)
+	if !held {
+		return watchertypes.UpdateNone, fmt.Errorf("no sync of challenge '%s' is held", challengeName)
+	}
+
+	ew.liveLockMu.Lock()
+	ew.approvedSyncs[challengeName] = true
+	ew.liveLockMu.Unlock()
+	ew.forcedUpdatesMu.Lock()
+	if updateType > ew.forcedUpdates[challengeName] {
+		ew.forcedUpdates[challengeName] = updateType
+	}
+	ew.forcedUpdatesMu.Unlock()
+
+	log.Info("[%s] Approved held %v sync of %s", ew.eventName, updateType, challengeName)
+	ew.LogToDatabase("WARN", "livelock", challengeName, "", fmt.Sprintf("Approved held %v sync", updateType), "", 0)
+	// Bypass the formatter check of HandleFileChange: the file is unchanged
+	ew.processChange(challengeFile)
+	return updateType, nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+const (
+	// logsPollInterval is how often followed logs are read from the database
+	logsPollInterval = 500 * time.Millisecond
+
+	// followBatchLimit bounds the log entries sent at once while following
+	followBatchLimit = 500
+)
+
+// logFilterFrom reads the filter of a logs command
+func logFilterFrom(cmd watchertypes.WatcherCommand) watchertypes.LogFilter {
+	filter := watchertypes.LogFilter{Limit: 100}
+	if cmd.Data == nil {
+		return filter
+	}
+	if id, ok := cmd.Data["after_id"].(float64); ok {
+		filter.AfterID = int64(id)
+	}
+	filter.Level, _ = cmd.Data["level"].(string)
+	filter.Challenge, _ = cmd.Data["challenge"].(string)
+	if l, ok := cmd.Data["limit"].(float64); ok {
+		filter.Limit = int(l)
+	}
+	return filter
+}
+
+// HandleLogsCommand returns the watcher log entries selected by the filter
+// of the command, oldest first
+func (w *Watcher) HandleLogsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	if !w.config.DatabaseEnabled {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Database logging is disabled",
+		}
+	}
+
+	logs, err := w.db.GetLogs(logFilterFrom(cmd))
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to get logs: %v", err),
+		}
+	}
+	return logsResponse(logs)
+}
+
+// StreamLogsCommand sends the latest log entries selected by the filter of
+// the command, then those written afterwards as they are stored, until ctx
+// is done or the watcher stops
+func (w *Watcher) StreamLogsCommand(ctx context.Context, cmd watchertypes.WatcherCommand, send func(watchertypes.WatcherResponse) error) {
+	if !w.config.DatabaseEnabled {
+		_ = send(watchertypes.WatcherResponse{Success: false, Error: "Database logging is disabled"})
+		return
+	}
+
+	filter := logFilterFrom(cmd)
+	initial := filter
+	if filter.AfterID == 0 {
+		// Entries filtered out must not be read again when following
+		filter.AfterID = w.lastLogID()
+	}
+	logs, err := w.db.GetLogs(initial)
+	filter.Limit = followBatchLimit
+
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		if err != nil {
+			_ = send(watchertypes.WatcherResponse{Success: false, Error: fmt.Sprintf("Failed to get logs: %v", err)})
+			return
+		}
+		if len(logs) > 0 {
+			filter.AfterID = max(filter.AfterID, logs[len(logs)-1].ID)
+			if err := send(logsResponse(logs)); err != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		logs, err = w.db.GetLogs(filter)
+	}
+}
+
+// lastLogID returns the ID of the latest watcher log entry, or 0
+func (w *Watcher) lastLogID() int64 {
+	logs, err := w.db.GetLogs(watchertypes.LogFilter{Limit: 1})
+	if err != nil || len(logs) == 0 {
+		return 0
+	}
+	return logs[0].ID
+}
+
+func logsResponse(logs []watchertypes.WatcherLog) watchertypes.WatcherResponse {
+	if logs == nil {
+		logs = []watchertypes.WatcherLog{}
+	}
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Retrieved %d log entries", len(logs)),
+		Data:    map[string]interface{}{"logs": logs},
+	}
+}
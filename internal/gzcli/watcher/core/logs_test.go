@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestWatcher_StreamLogsCommand(t *testing.T) {
+	db := database.New(filepath.Join(t.TempDir(), "test.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	db.LogToDatabase("INFO", "watcher", "web/login", "", "before follow", "", 0)
+	db.LogToDatabase("ERROR", "watcher", "web/login", "", "failed before follow", "", 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &Watcher{db: db, ctx: ctx, config: watchertypes.WatcherConfig{DatabaseEnabled: true}}
+
+	batches := make(chan []watchertypes.WatcherLog, 10)
+	done := make(chan struct{})
+	cmd := watchertypes.WatcherCommand{Action: "logs", Data: map[string]interface{}{"follow": true, "level": "WARN"}}
+	go func() {
+		defer close(done)
+		w.StreamLogsCommand(ctx, cmd, func(response watchertypes.WatcherResponse) error {
+			if !response.Success {
+				t.Errorf("StreamLogsCommand() sent an error: %s", response.Error)
+			}
+			batches <- response.Data["logs"].([]watchertypes.WatcherLog)
+			return nil
+		})
+	}()
+
+	next := func() []watchertypes.WatcherLog {
+		select {
+		case logs := <-batches:
+			return logs
+		case <-time.After(5 * time.Second):
+			t.Fatal("no log batch streamed")
+			return nil
+		}
+	}
+
+	if logs := next(); len(logs) != 1 || logs[0].Message != "failed before follow" {
+		t.Errorf("first batch = %+v, want the existing error", logs)
+	}
+
+	db.LogToDatabase("INFO", "watcher", "web/login", "", "skipped", "", 0)
+	db.LogToDatabase("WARN", "watcher", "web/login", "", "slow", "", 0)
+	if logs := next(); len(logs) != 1 || logs[0].Message != "slow" {
+		t.Errorf("followed batch = %+v, want the new warning only", logs)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("StreamLogsCommand() did not return once cancelled")
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/metrics"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// syncBuckets are the upper bounds, in seconds, of the sync duration
+// histogram. Full redeploys include image builds.
+var syncBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+// syncMetrics holds the sync counters of the watcher exported on /metrics
+type syncMetrics struct {
+	mu        sync.Mutex
+	syncs     map[[3]string]uint64          // {event, update type, result} -> count
+	durations map[string]*metrics.Histogram // event -> sync durations
+}
+
+func newSyncMetrics() *syncMetrics {
+	return &syncMetrics{
+		syncs:     make(map[[3]string]uint64),
+		durations: make(map[string]*metrics.Histogram),
+	}
+}
+
+// watcherMetrics are the sync metrics of the running watcher
+var watcherMetrics = newSyncMetrics()
+
+// observeSync records a sync of a challenge of event, its result and duration
+func (m *syncMetrics) observeSync(event string, updateType watchertypes.UpdateType, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.syncs[[3]string{event, updateType.String(), result}]++
+	h, ok := m.durations[event]
+	if !ok {
+		h = metrics.NewHistogram(syncBuckets)
+		m.durations[event] = h
+	}
+	h.Observe(duration.Seconds())
+}
+
+// Collect implements metrics.Collector with the watched challenges and
+// held syncs of each event and the sync metrics
+func (w *Watcher) Collect(out io.Writer) error {
+	var b strings.Builder
+
+	eventWatchers := w.GetAllEventWatchers()
+	events := make([]string, 0, len(eventWatchers))
+	for event := range eventWatchers {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	metrics.WriteHeader(&b, "gzcli_watcher_challenges", "gauge", "Challenges watched by event.")
+	for _, event := range events {
+		fmt.Fprintf(&b, "gzcli_watcher_challenges{event=%q} %d\n", event, len(eventWatchers[event].challengeMgr.GetChallenges()))
+	}
+	metrics.WriteHeader(&b, "gzcli_watcher_held_syncs", "gauge", "Syncs held by the live lock by event.")
+	for _, event := range events {
+		ew := eventWatchers[event]
+		ew.liveLockMu.Lock()
+		held := len(ew.heldUpdates)
+		ew.liveLockMu.Unlock()
+		fmt.Fprintf(&b, "gzcli_watcher_held_syncs{event=%q} %d\n", event, held)
+	}
+
+	m := watcherMetrics
+	m.mu.Lock()
+	keys := make([][3]string, 0, len(m.syncs))
+	for key := range m.syncs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		for k := range keys[i] {
+			if keys[i][k] != keys[j][k] {
+				return keys[i][k] < keys[j][k]
+			}
+		}
+		return false
+	})
+	metrics.WriteHeader(&b, "gzcli_watcher_syncs_total", "counter", "Challenge syncs by event, update type and result.")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "gzcli_watcher_syncs_total{event=%q,type=%q,result=%q} %d\n", key[0], key[1], key[2], m.syncs[key])
+	}
+
+	syncedEvents := make([]string, 0, len(m.durations))
+	for event := range m.durations {
+		syncedEvents = append(syncedEvents, event)
+	}
+	sort.Strings(syncedEvents)
+	metrics.WriteHeader(&b, "gzcli_watcher_sync_duration_seconds", "histogram", "Duration of challenge syncs.")
+	for _, event := range syncedEvents {
+		m.durations[event].Write(&b, "gzcli_watcher_sync_duration_seconds", fmt.Sprintf("event=%q", event))
+	}
+	m.mu.Unlock()
+
+	if w.db != nil && w.db.IsEnabled() {
+		if err := writeChallengeSyncStats(&b, w.db); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// writeChallengeSyncStats writes the sync statistics of each challenge
+// recorded in the database, which survive restarts of the watcher
+func writeChallengeSyncStats(b *strings.Builder, db *database.DB) error {
+	stats, err := db.GetSyncStats("", "")
+	if err != nil {
+		return fmt.Errorf("failed to get sync stats: %w", err)
+	}
+	metrics.WriteHeader(b, "gzcli_watcher_challenge_syncs_total", "counter", "Recorded syncs by challenge.")
+	for _, s := range stats {
+		fmt.Fprintf(b, "gzcli_watcher_challenge_syncs_total{event=%q,challenge=%q} %d\n", s.Event, s.Challenge, s.Syncs)
+	}
+	metrics.WriteHeader(b, "gzcli_watcher_challenge_sync_failures_total", "counter", "Recorded failed syncs by challenge.")
+	for _, s := range stats {
+		fmt.Fprintf(b, "gzcli_watcher_challenge_sync_failures_total{event=%q,challenge=%q} %d\n", s.Event, s.Challenge, s.Failures)
+	}
+	metrics.WriteHeader(b, "gzcli_watcher_challenge_sync_duration_avg_seconds", "gauge", "Average duration of the recorded syncs by challenge.")
+	for _, s := range stats {
+		fmt.Fprintf(b, "gzcli_watcher_challenge_sync_duration_avg_seconds{event=%q,challenge=%q} %g\n", s.Event, s.Challenge, float64(s.AvgDurationMs)/1000)
+	}
+	return nil
+}
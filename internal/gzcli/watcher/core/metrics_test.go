@@ -0,0 +1,72 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestWatcher_CollectSyncMetrics(t *testing.T) {
+	orig := watcherMetrics
+	watcherMetrics = newSyncMetrics()
+	t.Cleanup(func() { watcherMetrics = orig })
+
+	watcherMetrics.observeSync("ctf", watchertypes.UpdateMetadata, 800*time.Millisecond, nil)
+	watcherMetrics.observeSync("ctf", watchertypes.UpdateFullRedeploy, 45*time.Second, errors.New("build failed"))
+	watcherMetrics.observeSync("ctf", watchertypes.UpdateMetadata, 2*time.Second, nil)
+
+	w := &Watcher{eventWatchers: make(map[string]*EventWatcher)}
+	var b strings.Builder
+	if err := w.Collect(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`# TYPE gzcli_watcher_challenges gauge`,
+		`gzcli_watcher_syncs_total{event="ctf",type="metadata",result="success"} 2`,
+		`gzcli_watcher_syncs_total{event="ctf",type="full_redeploy",result="failure"} 1`,
+		`gzcli_watcher_sync_duration_seconds_bucket{event="ctf",le="1"} 1`,
+		`gzcli_watcher_sync_duration_seconds_bucket{event="ctf",le="2.5"} 2`,
+		`gzcli_watcher_sync_duration_seconds_bucket{event="ctf",le="60"} 3`,
+		`gzcli_watcher_sync_duration_seconds_count{event="ctf"} 3`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWatcher_CollectChallengeSyncStats(t *testing.T) {
+	db := database.New(filepath.Join(t.TempDir(), "watcher.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	now := time.Now()
+	db.LogSyncActivity("ctf", "web/flaky", "sync", "failed", now, now.Add(time.Second), "build failed")
+	db.LogSyncActivity("ctf", "web/flaky", "sync", "success", now, now.Add(3*time.Second), "")
+
+	w := &Watcher{db: db, eventWatchers: make(map[string]*EventWatcher)}
+	var b strings.Builder
+	if err := w.Collect(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		`gzcli_watcher_challenge_syncs_total{event="ctf",challenge="web/flaky"} 2`,
+		`gzcli_watcher_challenge_sync_failures_total{event="ctf",challenge="web/flaky"} 1`,
+		`gzcli_watcher_challenge_sync_duration_avg_seconds{event="ctf",challenge="web/flaky"} 2`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	challengepkg "github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// mirrorClient is the API client of a mirror target, kept while the target
+// of conf.yaml is unchanged
+type mirrorClient struct {
+	target config.Target
+	api    *gzapi.GZAPI
+}
+
+// mirrorAPI returns a logged in API client of a mirror target
+func (ew *EventWatcher) mirrorAPI(target config.Target) (*gzapi.GZAPI, error) {
+	ew.mirrorsMu.Lock()
+	defer ew.mirrorsMu.Unlock()
+	if client, ok := ew.mirrors[target.Name]; ok && client.target.Url == target.Url && client.target.Creds == target.Creds {
+		return client.api, nil
+	}
+
+	creds := target.Creds
+	api, err := gzapi.Init(target.Url, &creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in to %s: %w", target.Url, err)
+	}
+	if ew.mirrors == nil {
+		ew.mirrors = make(map[string]mirrorClient)
+	}
+	ew.mirrors[target.Name] = mirrorClient{target: target, api: api}
+	return api, nil
+}
+
+// syncMirrors syncs a challenge that synced to the server of the event to
+// the mirror targets of the event. Every target is tried; their failures are
+// returned together.
+func (ew *EventWatcher) syncMirrors(challengeName string, challengeConf config.ChallengeYaml, updateType watchertypes.UpdateType) error {
+	targets, err := config.GetEventTargets(ew.eventName, ew.config.Targets)
+	if err != nil {
+		return fmt.Errorf("failed to read mirror targets: %w", err)
+	}
+
+	var errs []error
+	for _, target := range targets {
+		startedAt := time.Now()
+		err := ew.syncMirror(target, challengeName, challengeConf, updateType)
+		duration := time.Since(startedAt).Milliseconds()
+		if err != nil {
+			log.Error("[%s] Failed to mirror %s to %s: %v", ew.eventName, challengeName, target.Name, err)
+			ew.LogToDatabase("ERROR", "mirror", challengeName, "", fmt.Sprintf("Failed to mirror to %s", target.Name), err.Error(), duration)
+			errs = append(errs, fmt.Errorf("%s: %w", target.Name, err))
+			continue
+		}
+		log.Info("[%s] ✅ Mirrored %s to %s", ew.eventName, challengeName, target.Name)
+		ew.LogToDatabase("INFO", "mirror", challengeName, "", fmt.Sprintf("Mirrored to %s", target.Name), "", duration)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("mirroring failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// syncMirror syncs a challenge to the game of the event on a mirror target,
+// updating the challenge its folder is mapped to on the target, or the one
+// with its title, and records the mapping
+func (ew *EventWatcher) syncMirror(target config.Target, challengeName string, challengeConf config.ChallengeYaml, updateType watchertypes.UpdateType) error {
+	api, err := ew.mirrorAPI(target)
+	if err != nil {
+		return err
+	}
+	conf, err := config.GetConfigWithEvent(api, ew.eventName, ew.noOpGetCache, ew.noOpSetCache, ew.noOpDeleteCache, missingMirrorGame)
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	folderPath := mirrorFolderPath(ew.eventPath, challengeConf)
+	mappedID, err := ew.targetChallengeID(target.Name, folderPath, conf.Event.Id)
+	if err != nil {
+		return err
+	}
+
+	normalizedCategory, normalizedName := config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
+	challenges, err := conf.Event.GetChallengesSelective(mirrorMatcher(mappedID, challengeConf))
+	if err != nil {
+		return fmt.Errorf("failed to get challenges from API: %w", err)
+	}
+	var existing *gzapi.Challenge
+	if mappedID != 0 {
+		existing, _ = ew.fetchChallengeByID(mappedID, challenges)
+	}
+	if existing != nil {
+		existing.CS = api
+	}
+
+	orchestrator := challengepkg.NewSyncOrchestrator(conf, challengeConf, challenges, api, ew.noOpGetCache, ew.noOpSetCache, existing)
+	orchestrator.SetUpdateType(updateType.String())
+	if err := orchestrator.Execute(); err != nil {
+		return err
+	}
+
+	challengeID := 0
+	if existing != nil {
+		challengeID = existing.Id
+	} else if fresh, err := conf.Event.ListChallenges(); err == nil {
+		for _, ch := range fresh {
+			if ch.Title == normalizedName && ch.Category == normalizedCategory {
+				challengeID = ch.Id
+				break
+			}
+		}
+	}
+	if challengeID == 0 {
+		log.Error("[%s] Failed to find challenge %s mirrored to %s for mapping", ew.eventName, normalizedName, target.Name)
+		return nil
+	}
+	if ew.db != nil {
+		if err := ew.db.SetTargetMapping(ew.eventName, target.Name, folderPath, challengeID, conf.Event.Id, normalizedName); err != nil {
+			log.Error("[%s] Failed to store mapping of %s on %s: %v", ew.eventName, folderPath, target.Name, err)
+		}
+	}
+	// Challenges with a release_at are created disabled on the target too
+	ew.scheduleReleaseOn(api, target.Name, challengeName, challengeConf, challengeID, conf.Event.Id)
+	return nil
+}
+
+// mirrorFolderPath returns the folder of a challenge relative to the event,
+// which keys its mappings
+func mirrorFolderPath(eventPath string, challengeConf config.ChallengeYaml) string {
+	folderPath, err := filepath.Rel(eventPath, challengeConf.Cwd)
+	if err != nil {
+		folderPath = challengeConf.Category + "/" + filepath.Base(challengeConf.Cwd)
+	}
+	return folderPath
+}
+
+// targetChallengeID returns the ID of the challenge a folder is mapped to in
+// the game of a mirror target, or 0 when it is not mapped
+func (ew *EventWatcher) targetChallengeID(target, folderPath string, gameID int) (int, error) {
+	if ew.db == nil {
+		return 0, nil
+	}
+	mapping, err := ew.db.GetTargetMapping(ew.eventName, target, folderPath)
+	if err != nil {
+		return 0, err
+	}
+	// Mappings to another game, e.g. one deleted and recreated, are stale
+	if mapping == nil || mapping.GameID != gameID {
+		return 0, nil
+	}
+	return mapping.ChallengeID, nil
+}
+
+// mirrorSelector selects the challenge of a mirror target's game that a
+// challenge is synced to: the one its folder is mapped to, or by title
+func (ew *EventWatcher) mirrorSelector(target string, challengeConf config.ChallengeYaml, gameID int) func(gzapi.Challenge) bool {
+	mappedID, err := ew.targetChallengeID(target, mirrorFolderPath(ew.eventPath, challengeConf), gameID)
+	if err != nil {
+		log.DebugH3("[%s] Failed to read mapping of %s on %s: %v", ew.eventName, challengeConf.Name, target, err)
+	}
+	return mirrorMatcher(mappedID, challengeConf)
+}
+
+// mirrorMatcher matches the challenge with ID mappedID, if not 0, or with
+// the title of challengeConf
+func mirrorMatcher(mappedID int, challengeConf config.ChallengeYaml) func(gzapi.Challenge) bool {
+	_, normalizedName := config.NormalizeChallengeCategory(challengeConf.Category, challengeConf.Name)
+	return func(c gzapi.Challenge) bool {
+		return (mappedID != 0 && c.Id == mappedID) || c.Title == challengeConf.Name || c.Title == normalizedName
+	}
+}
+
+// missingMirrorGame refuses to create the game of an event on a mirror
+// target, where it is created beforehand, e.g. with "gzcli snapshot import"
+func missingMirrorGame(conf *config.Config, _ *gzapi.GZAPI) (*gzapi.Game, error) {
+	return nil, fmt.Errorf("game %q does not exist on the target", conf.Event.Title)
+}
@@ -1453,6 +1453,7 @@ func TestAutoSync_UpdateTypeHandling(t *testing.T) {
 		expectedUpdateType watchertypes.UpdateType
 	}{
 		{filepath.Join(challengeDir, "challenge.yaml"), watchertypes.UpdateMetadata},
+		{filepath.Join(challengeDir, "README.md"), watchertypes.UpdateMetadata},
 		{filepath.Join(srcDir, "main.py"), watchertypes.UpdateFullRedeploy},
 		{filepath.Join(distDir, "flag.txt"), watchertypes.UpdateAttachment},
 		{filepath.Join(solverDir, "solve.py"), watchertypes.UpdateNone},
@@ -1587,7 +1588,7 @@ func TestChallengeMapping_DatabasePersistence(t *testing.T) {
 	}
 
 	// Store a mapping
-	if err := db1.SetChallengeMapping(eventName, "Web/test-challenge", 12345, "Test Challenge"); err != nil {
+	if err := db1.SetChallengeMapping(eventName, "Web/test-challenge", 12345, 0, "Test Challenge"); err != nil {
 		t.Fatalf("Failed to set mapping: %v", err)
 	}
 
@@ -1662,7 +1663,7 @@ func TestChallengeMapping_CacheHit(t *testing.T) {
 	}
 
 	// Store a mapping directly in database
-	if err := w.db.SetChallengeMapping(eventName, "web/test-challenge", 999, "Test Challenge"); err != nil {
+	if err := w.db.SetChallengeMapping(eventName, "web/test-challenge", 999, 0, "Test Challenge"); err != nil {
 		t.Fatalf("Failed to set mapping: %v", err)
 	}
 
@@ -1692,8 +1693,8 @@ func TestChallengeMapping_CacheHit(t *testing.T) {
 	if !inCache {
 		t.Error("Mapping should be cached in memory")
 	}
-	if cachedID != 999 {
-		t.Errorf("Cached ID should be 999, got %d", cachedID)
+	if cachedID.id != 999 {
+		t.Errorf("Cached ID should be 999, got %d", cachedID.id)
 	}
 
 	t.Log("Successfully tested cache hit performance")
@@ -1739,7 +1740,7 @@ func TestChallengeMapping_NoDuplicates(t *testing.T) {
 	}
 
 	// Simulate first sync - store mapping
-	ew.setChallengeID("web/test-challenge", 100, "Original Name")
+	ew.setChallengeID("web/test-challenge", 100, 0, "Original Name")
 
 	// Verify mapping was stored
 	id1, exists1 := ew.getChallengeID("web/test-challenge")
@@ -1748,7 +1749,7 @@ func TestChallengeMapping_NoDuplicates(t *testing.T) {
 	}
 
 	// Simulate name change - update mapping with new title
-	ew.setChallengeID("web/test-challenge", 100, "New Name")
+	ew.setChallengeID("web/test-challenge", 100, 0, "New Name")
 
 	// Verify ID stays the same (no duplicate)
 	id2, exists2 := ew.getChallengeID("web/test-challenge")
@@ -1866,7 +1867,7 @@ func TestChallengeMapping_DeletedInGZCTF(t *testing.T) {
 	}
 
 	// Store a mapping for a challenge that doesn't exist in GZCTF
-	ew.setChallengeID("web/test-challenge", 99999, "Ghost Challenge")
+	ew.setChallengeID("web/test-challenge", 99999, 0, "Ghost Challenge")
 
 	// Verify mapping exists
 	id, exists := ew.getChallengeID("web/test-challenge")
@@ -1916,11 +1917,11 @@ func TestChallengeMapping_MultiEvent(t *testing.T) {
 	defer db.Close()
 
 	// Store mappings for different events with same folder path
-	if err := db.SetChallengeMapping("event1", "Web/same-challenge", 100, "Event1 Challenge"); err != nil {
+	if err := db.SetChallengeMapping("event1", "Web/same-challenge", 100, 0, "Event1 Challenge"); err != nil {
 		t.Fatalf("Failed to set mapping for event1: %v", err)
 	}
 
-	if err := db.SetChallengeMapping("event2", "Web/same-challenge", 200, "Event2 Challenge"); err != nil {
+	if err := db.SetChallengeMapping("event2", "Web/same-challenge", 200, 0, "Event2 Challenge"); err != nil {
 		t.Fatalf("Failed to set mapping for event2: %v", err)
 	}
 
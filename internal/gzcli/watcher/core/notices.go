@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// noticeInterval is how often the watcher looks for scheduled notices to post
+const noticeInterval = 30 * time.Second
+
+// runNotices posts the scheduled notices of the event as they come due,
+// until the watcher stops. Notices that came due while no watcher was
+// running are posted on start.
+func (ew *EventWatcher) runNotices() {
+	ticker := time.NewTicker(noticeInterval)
+	defer ticker.Stop()
+	for {
+		ew.postDueNotices(time.Now())
+		select {
+		case <-ew.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// postDueNotices posts the scheduled notices due at now to the game of the
+// event and marks them sent. Failed notices keep their error and are retried
+// on the next pass, up to watchertypes.MaxNoticeAttempts times.
+func (ew *EventWatcher) postDueNotices(now time.Time) {
+	if ew.db == nil || !ew.db.IsEnabled() || ew.config.DryRun {
+		return
+	}
+	notices, err := ew.db.DueNotices(ew.eventName, now)
+	if err != nil {
+		log.Error("[%s] Failed to read scheduled notices: %v", ew.eventName, err)
+		return
+	}
+	if len(notices) == 0 {
+		return
+	}
+
+	conf, err := config.GetConfigWithEvent(ew.api, ew.eventName,
+		ew.noOpGetCache,
+		ew.noOpSetCache,
+		ew.noOpDeleteCache,
+		nil)
+	if err != nil {
+		log.Error("[%s] Failed to get config for scheduled notices: %v", ew.eventName, err)
+		return
+	}
+	ew.postNotices(&gzapi.Game{Id: conf.Event.Id, CS: ew.api}, notices)
+}
+
+// postNotices posts scheduled notices to game and records the outcome of each
+func (ew *EventWatcher) postNotices(game *gzapi.Game, notices []watchertypes.ScheduledNotice) {
+	for _, n := range notices {
+		if ew.ctx.Err() != nil {
+			return
+		}
+		startedAt := time.Now()
+		notice, err := game.CreateNotice(n.Content)
+		duration := time.Since(startedAt).Milliseconds()
+		if err != nil {
+			giveUp := !noticeRetryable(err) || n.Attempts+1 >= watchertypes.MaxNoticeAttempts
+			message := fmt.Sprintf("Failed to post scheduled notice %d", n.ID)
+			if giveUp {
+				message = fmt.Sprintf("Gave up on scheduled notice %d after %d attempt(s)", n.ID, n.Attempts+1)
+			}
+			log.Error("[%s] %s: %v", ew.eventName, message, err)
+			ew.LogToDatabase("ERROR", "notice", "", "", message, err.Error(), duration)
+			if err := ew.db.MarkNoticeFailed(n.ID, err.Error(), giveUp); err != nil {
+				log.Error("[%s] Failed to record the error of scheduled notice %d: %v", ew.eventName, n.ID, err)
+			}
+			continue
+		}
+		if err := ew.db.MarkNoticeSent(n.ID, notice.Id, time.Now()); err != nil {
+			log.Error("[%s] Posted scheduled notice %d but failed to mark it sent, it may be posted again: %v", ew.eventName, n.ID, err)
+		}
+		log.Info("[%s] 📢 Posted scheduled notice %d as notice %d", ew.eventName, n.ID, notice.Id)
+		ew.LogToDatabase("INFO", "notice", "", "", fmt.Sprintf("Posted scheduled notice %d as notice %d", n.ID, notice.Id), "", duration)
+	}
+}
+
+// noticeRetryable reports whether posting a notice may succeed on a later
+// attempt: client errors other than timeouts, rate limits and expired
+// sessions are answered the same way every time
+func noticeRetryable(err error) bool {
+	status := gzapi.StatusCode(err)
+	if status < 400 || status >= 500 {
+		return true
+	}
+	switch status {
+	case http.StatusUnauthorized, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return false
+}
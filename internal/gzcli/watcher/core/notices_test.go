@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestEventWatcher_PostNotices(t *testing.T) {
+	var posted []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/account/login", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"succeeded": true}`))
+	})
+	mux.HandleFunc("/api/edit/games/3/notices", func(w http.ResponseWriter, r *http.Request) {
+		var form gzapi.GameNoticeForm
+		_ = json.NewDecoder(r.Body).Decode(&form)
+		switch form.Content {
+		case "fails":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"title": "invalid notice"}`))
+			return
+		case "server down":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		posted = append(posted, form.Content)
+		_, _ = w.Write([]byte(`{"id": 11, "type": "Normal", "values": ["` + form.Content + `"]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	api, err := gzapi.Init(server.URL, &gzapi.Creds{Username: "admin", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := database.New(filepath.Join(t.TempDir(), "test.db"), true)
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	past := time.Now().Add(-time.Minute)
+	for _, content := range []string{"Hint 1", "fails", "server down"} {
+		if _, err := db.ScheduleNotice(watchertypes.ScheduledNotice{Event: "ctf", Content: content, PostAt: past}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	due, err := db.DueNotices("ctf", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ew := &EventWatcher{eventName: "ctf", api: api, db: db, ctx: context.Background()}
+	ew.postNotices(&gzapi.Game{Id: 3, CS: api}, due)
+
+	if len(posted) != 1 || posted[0] != "Hint 1" {
+		t.Errorf("posted notices = %v, want [Hint 1]", posted)
+	}
+	notices, err := db.GetScheduledNotices("ctf", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notices[0].SentAt == nil || notices[0].NoticeID != 11 {
+		t.Errorf("posted notice = %+v, want it marked sent as notice 11", notices[0])
+	}
+	if notices[1].SentAt != nil || notices[1].Error == "" || notices[1].FailedAt == nil {
+		t.Errorf("rejected notice = %+v, want it given up on with its error", notices[1])
+	}
+	if notices[2].FailedAt != nil || notices[2].Attempts != 1 {
+		t.Errorf("failed notice = %+v, want it pending after 1 attempt", notices[2])
+	}
+
+	// The last attempt gives up on a notice that keeps failing
+	due, err = db.DueNotices("ctf", time.Now())
+	if err != nil || len(due) != 1 {
+		t.Fatalf("due notices = %+v (err %v), want the failed one", due, err)
+	}
+	due[0].Attempts = watchertypes.MaxNoticeAttempts - 1
+	ew.postNotices(&gzapi.Game{Id: 3, CS: api}, due)
+	if due, _ := db.DueNotices("ctf", time.Now()); len(due) != 0 {
+		t.Errorf("due notices = %+v, want none after the last attempt", due)
+	}
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// bulkSync tracks a sync of all watched challenges
+type bulkSync struct {
+	total      int
+	pending    map[string]bool      // Challenges not done yet
+	running    map[string]time.Time // Challenges syncing now -> start
+	failed     int
+	synced     int           // Challenges actually synced, not skipped
+	syncTime   time.Duration // Total duration of the synced challenges
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// startBulkSync starts tracking the sync of challenges, replacing any
+// previous bulk sync
+func (ew *EventWatcher) startBulkSync(challenges map[string]string) {
+	pending := make(map[string]bool, len(challenges))
+	for challengeName := range challenges {
+		pending[challengeName] = true
+	}
+
+	ew.bulkSyncMu.Lock()
+	ew.bulkSync = &bulkSync{
+		total:     len(pending),
+		pending:   pending,
+		running:   make(map[string]time.Time),
+		startedAt: time.Now(),
+	}
+	ew.bulkSyncMu.Unlock()
+	log.Info("[%s] Syncing %d challenge(s)", ew.eventName, len(pending))
+}
+
+// bulkSyncStarted marks a challenge of the bulk sync as syncing
+func (ew *EventWatcher) bulkSyncStarted(challengeName string) {
+	ew.bulkSyncMu.Lock()
+	defer ew.bulkSyncMu.Unlock()
+	if b := ew.bulkSync; b != nil && b.pending[challengeName] {
+		b.running[challengeName] = time.Now()
+	}
+}
+
+// bulkSyncFinished marks a challenge of the bulk sync as done, after a sync
+// of duration or, with a zero duration, after it was skipped
+func (ew *EventWatcher) bulkSyncFinished(challengeName string, duration time.Duration, err error) {
+	ew.bulkSyncMu.Lock()
+	b := ew.bulkSync
+	if b == nil || !b.pending[challengeName] {
+		ew.bulkSyncMu.Unlock()
+		return
+	}
+	delete(b.pending, challengeName)
+	delete(b.running, challengeName)
+	if err != nil {
+		b.failed++
+	}
+	if duration > 0 {
+		b.synced++
+		b.syncTime += duration
+	}
+	now := time.Now()
+	if len(b.pending) == 0 {
+		b.finishedAt = now
+	}
+	progress := b.progress(ew.eventName, now)
+	ew.bulkSyncMu.Unlock()
+
+	if progress.FinishedAt == nil {
+		log.Info("[%s] Synced %d/%d challenge(s), ETA %s", ew.eventName, progress.Done, progress.Total, formatETA(progress.ETASeconds))
+		return
+	}
+	message := fmt.Sprintf("Synced %d challenge(s) in %s, %d failed", progress.Total, now.Sub(progress.StartedAt).Round(time.Second), progress.Failed)
+	log.Info("[%s] %s", ew.eventName, message)
+	ew.LogToDatabase("INFO", "event_watcher", "", "", message, "", now.Sub(progress.StartedAt).Milliseconds())
+}
+
+// SyncProgress returns the progress of the latest sync of all watched
+// challenges, if there was one
+func (ew *EventWatcher) SyncProgress() (watchertypes.SyncProgress, bool) {
+	ew.bulkSyncMu.Lock()
+	defer ew.bulkSyncMu.Unlock()
+	if ew.bulkSync == nil {
+		return watchertypes.SyncProgress{}, false
+	}
+	return ew.bulkSync.progress(ew.eventName, time.Now()), true
+}
+
+// progress snapshots b at now. The ETA extrapolates the rate challenges
+// were done at so far, which accounts for challenges syncing in parallel.
+func (b *bulkSync) progress(event string, now time.Time) watchertypes.SyncProgress {
+	p := watchertypes.SyncProgress{
+		Event:      event,
+		Total:      b.total,
+		Done:       b.total - len(b.pending),
+		Failed:     b.failed,
+		StartedAt:  b.startedAt,
+		ETASeconds: -1,
+	}
+	for challengeName := range b.running {
+		p.Current = append(p.Current, challengeName)
+	}
+	sort.Strings(p.Current)
+	if b.synced > 0 {
+		p.AverageMs = (b.syncTime / time.Duration(b.synced)).Milliseconds()
+	}
+
+	switch {
+	case !b.finishedAt.IsZero():
+		finishedAt := b.finishedAt
+		p.FinishedAt = &finishedAt
+		p.ETASeconds = 0
+	case p.Done > 0:
+		perChallenge := now.Sub(b.startedAt) / time.Duration(p.Done)
+		p.ETASeconds = int64((perChallenge * time.Duration(p.Total-p.Done)).Round(time.Second).Seconds())
+	}
+	return p
+}
+
+// formatETA formats an ETA in seconds, -1 meaning unknown
+func formatETA(seconds int64) string {
+	if seconds < 0 {
+		return "unknown"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
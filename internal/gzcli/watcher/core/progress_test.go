@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventWatcher_BulkSyncProgress(t *testing.T) {
+	ew := &EventWatcher{eventName: "ctf"}
+	if _, ok := ew.SyncProgress(); ok {
+		t.Fatal("SyncProgress() reported progress before any bulk sync")
+	}
+
+	ew.startBulkSync(map[string]string{
+		"web/a":    "web/a/challenge.yml",
+		"web/b":    "web/b/challenge.yml",
+		"crypto/c": "crypto/c/challenge.yml",
+		"misc/d":   "misc/d/challenge.yml",
+	})
+	ew.bulkSyncStarted("web/a")
+	ew.bulkSyncStarted("web/b")
+	ew.bulkSyncStarted("other/not-in-bulk")
+
+	progress, ok := ew.SyncProgress()
+	if !ok {
+		t.Fatal("SyncProgress() reported no progress")
+	}
+	if progress.Total != 4 || progress.Done != 0 || progress.ETASeconds != -1 {
+		t.Errorf("progress = %+v, want 0/4 with an unknown ETA", progress)
+	}
+	if len(progress.Current) != 2 || progress.Current[0] != "web/a" || progress.Current[1] != "web/b" {
+		t.Errorf("current = %v, want [web/a web/b]", progress.Current)
+	}
+
+	ew.bulkSyncFinished("web/a", 2*time.Second, nil)
+	ew.bulkSyncFinished("web/b", 4*time.Second, errors.New("boom"))
+	ew.bulkSyncFinished("web/b", time.Second, nil) // A pending change synced again
+	ew.bulkSyncFinished("crypto/c", 0, nil)        // Skipped as unchanged
+
+	progress, _ = ew.SyncProgress()
+	if progress.Done != 3 || progress.Failed != 1 || len(progress.Current) != 0 {
+		t.Errorf("progress = %+v, want 3/4 done with 1 failure", progress)
+	}
+	if progress.AverageMs != 3000 {
+		t.Errorf("average = %dms, want 3000ms over the synced challenges", progress.AverageMs)
+	}
+	if progress.ETASeconds < 0 || progress.FinishedAt != nil {
+		t.Errorf("progress = %+v, want an ETA and not finished", progress)
+	}
+
+	ew.bulkSyncFinished("misc/d", time.Second, nil)
+	progress, _ = ew.SyncProgress()
+	if progress.FinishedAt == nil || progress.ETASeconds != 0 || progress.Done != 4 {
+		t.Errorf("progress = %+v, want finished", progress)
+	}
+}
+
+func TestBulkSync_ETA(t *testing.T) {
+	startedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := &bulkSync{
+		total:     10,
+		pending:   map[string]bool{"a": true, "b": true, "c": true, "d": true, "e": true, "f": true},
+		running:   map[string]time.Time{},
+		startedAt: startedAt,
+	}
+	// 4 done in 2 minutes: 30s each for the remaining 6
+	if got := b.progress("ctf", startedAt.Add(2*time.Minute)).ETASeconds; got != 180 {
+		t.Errorf("ETA = %ds, want 180s", got)
+	}
+}
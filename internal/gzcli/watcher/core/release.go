@@ -0,0 +1,248 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	challengepkg "github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// startReleases arms the releases of the watched challenges from their
+// challenge.yaml as found on disk, so releases survive restarts of the
+// watcher and apply to challenges synced with "gzcli sync". Challenges whose
+// release time passed while the watcher was not running and that are still
+// disabled in GZCTF are released right away.
+func (ew *EventWatcher) startReleases() {
+	if ew.config.DryRun {
+		return
+	}
+	pending := make(map[string]config.ChallengeYaml)
+	for challengeName, challengePath := range ew.challengeMgr.GetChallenges() {
+		challengeConf, ok, err := readChallengeYaml(challengePath)
+		if err != nil {
+			log.Error("[%s] Failed to parse challenge YAML of %s for its release: %v", ew.eventName, challengeName, err)
+			continue
+		}
+		if ok && challengeConf.ReleaseAt != nil {
+			challengeConf.Category, challengeConf.Name = config.NormalizeChallengeCategory(registry.Category(ew.eventPath, challengePath), challengeConf.Name)
+			pending[challengeName] = challengeConf
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	conf, err := config.GetConfigWithEvent(ew.api, ew.eventName,
+		ew.noOpGetCache,
+		ew.noOpSetCache,
+		ew.noOpDeleteCache,
+		nil)
+	if err != nil {
+		log.Error("[%s] Failed to get config for challenge releases: %v", ew.eventName, err)
+		return
+	}
+	challenges, err := (&gzapi.Game{Id: conf.Event.Id, CS: ew.api}).ListChallenges()
+	if err != nil {
+		log.Error("[%s] Failed to list challenges for their releases: %v", ew.eventName, err)
+		return
+	}
+	ew.armReleases(ew.api, "", pending, challenges, conf.Event.Id, ew.challengeSelector)
+
+	targets, err := config.GetEventTargets(ew.eventName, ew.config.Targets)
+	if err != nil {
+		log.Error("[%s] Failed to read mirror targets for challenge releases: %v", ew.eventName, err)
+		return
+	}
+	for _, target := range targets {
+		api, err := ew.mirrorAPI(target)
+		if err != nil {
+			log.Error("[%s] Failed to arm challenge releases on %s: %v", ew.eventName, target.Name, err)
+			continue
+		}
+		conf, err := config.GetConfigWithEvent(api, ew.eventName, ew.noOpGetCache, ew.noOpSetCache, ew.noOpDeleteCache, missingMirrorGame)
+		if err != nil {
+			log.Error("[%s] Failed to get config of %s for challenge releases: %v", ew.eventName, target.Name, err)
+			continue
+		}
+		challenges, err := conf.Event.ListChallenges()
+		if err != nil {
+			log.Error("[%s] Failed to list challenges of %s for their releases: %v", ew.eventName, target.Name, err)
+			continue
+		}
+		ew.armReleases(api, target.Name, pending, challenges, conf.Event.Id, func(challengeConf config.ChallengeYaml) func(gzapi.Challenge) bool {
+			return ew.mirrorSelector(target.Name, challengeConf, conf.Event.Id)
+		})
+	}
+}
+
+// dueRelease is a disabled challenge whose release time has passed
+type dueRelease struct {
+	challengeName string
+	conf          config.ChallengeYaml
+	id            int
+}
+
+// armReleases schedules the future releases of challenges on the server of
+// api and releases the disabled challenges whose release time has passed in
+// one batch. target names the mirror target of api, or is empty for the
+// event's server.
+func (ew *EventWatcher) armReleases(api *gzapi.GZAPI, target string, pending map[string]config.ChallengeYaml, challenges []gzapi.Challenge, gameID int, selector func(config.ChallengeYaml) func(gzapi.Challenge) bool) {
+	var due []dueRelease
+	for challengeName, challengeConf := range pending {
+		selected := selector(challengeConf)
+		for _, c := range challenges {
+			if !selected(c) {
+				continue
+			}
+			switch {
+			case time.Until(*challengeConf.ReleaseAt) > 0:
+				ew.scheduleReleaseOn(api, target, challengeName, challengeConf, c.Id, gameID)
+			case c.IsEnabled != nil && !*c.IsEnabled:
+				log.Info("[%s] ⏰ Release time of %s passed while the watcher was stopped", ew.eventName, challengeName)
+				due = append(due, dueRelease{challengeName: challengeName, conf: challengeConf, id: c.Id})
+			}
+			break
+		}
+	}
+	if len(due) > 0 {
+		ew.releaseChallenges(api, target, due, gameID)
+	}
+}
+
+// scheduleRelease (re)schedules the release of a synced challenge whose
+// release_at is in the future, replacing any release scheduled before.
+func (ew *EventWatcher) scheduleRelease(challengeName string, challengeConf config.ChallengeYaml, challengeID, gameID int) {
+	ew.scheduleReleaseOn(ew.api, "", challengeName, challengeConf, challengeID, gameID)
+}
+
+// scheduleReleaseOn schedules the release of a challenge on the server of
+// api, the one of mirror target target when it is not empty
+func (ew *EventWatcher) scheduleReleaseOn(api *gzapi.GZAPI, target, challengeName string, challengeConf config.ChallengeYaml, challengeID, gameID int) {
+	key := releaseKey(challengeName, target)
+	ew.releaseTimersMu.Lock()
+	defer ew.releaseTimersMu.Unlock()
+
+	if timer, ok := ew.releaseTimers[key]; ok {
+		timer.Stop()
+		delete(ew.releaseTimers, key)
+	}
+	if ew.config.DryRun || challengeConf.ReleaseAt == nil || challengeID == 0 {
+		return
+	}
+	releaseAt := *challengeConf.ReleaseAt
+	delay := time.Until(releaseAt)
+	if delay <= 0 {
+		return
+	}
+
+	log.Info("[%s] ⏰ Challenge %s is hidden%s until its release at %s", ew.eventName, challengeName, onTarget(target), releaseAt.Local().Format(time.DateTime))
+	ew.LogToDatabase("INFO", "release", challengeName, "", fmt.Sprintf("Release%s scheduled at %s", onTarget(target), releaseAt.UTC().Format(time.RFC3339)), "", 0)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		ew.releaseTimersMu.Lock()
+		current := ew.releaseTimers[key] == timer
+		if current {
+			delete(ew.releaseTimers, key)
+		}
+		ew.releaseTimersMu.Unlock()
+		if !current || ew.ctx.Err() != nil {
+			return
+		}
+		ew.releaseChallenge(api, target, challengeName, challengeConf, challengeID, gameID)
+	})
+	ew.releaseTimers[key] = timer
+}
+
+// releaseKey identifies the release of a challenge on a mirror target, or
+// on the event's server when target is empty
+func releaseKey(challengeName, target string) string {
+	if target == "" {
+		return challengeName
+	}
+	return challengeName + "@" + target
+}
+
+// onTarget names a mirror target in messages
+func onTarget(target string) string {
+	if target == "" {
+		return ""
+	}
+	return " on " + target
+}
+
+// releaseChallenge enables a challenge on the server of api and, on the
+// event's server, announces it
+func (ew *EventWatcher) releaseChallenge(api *gzapi.GZAPI, target, challengeName string, challengeConf config.ChallengeYaml, challengeID, gameID int) {
+	ew.releaseChallenges(api, target, []dueRelease{{challengeName: challengeName, conf: challengeConf, id: challengeID}}, gameID)
+}
+
+// releaseChallenges enables challenges on the server of api in one batch of
+// requests and, on the event's server, announces each one released
+func (ew *EventWatcher) releaseChallenges(api *gzapi.GZAPI, target string, due []dueRelease, gameID int) {
+	ew.wg.Add(1)
+	defer ew.wg.Done()
+
+	ids := make([]int, len(due))
+	for i, d := range due {
+		ids[i] = d.id
+	}
+	startedAt := time.Now()
+	released, err := challengepkg.EnableChallenges(&gzapi.Game{Id: gameID, CS: api}, ids)
+	duration := time.Since(startedAt).Milliseconds()
+	enabled := make(map[int]bool, len(released))
+	for _, c := range released {
+		enabled[c.Id] = true
+	}
+	if err == nil && len(released) < len(due) {
+		err = fmt.Errorf("challenge not enabled")
+	}
+	for _, d := range due {
+		if enabled[d.id] {
+			ew.challengeReleased(target, d.challengeName, d.conf, d.id, duration)
+			continue
+		}
+		log.Error("[%s] Failed to release challenge %s%s: %v", ew.eventName, d.challengeName, onTarget(target), err)
+		ew.LogToDatabase("ERROR", "release", d.challengeName, "", "Failed to release challenge"+onTarget(target), err.Error(), duration)
+	}
+}
+
+// challengeReleased records the release of a challenge and, on the event's
+// server, announces it
+func (ew *EventWatcher) challengeReleased(target, challengeName string, challengeConf config.ChallengeYaml, challengeID int, duration int64) {
+	log.Info("[%s] 🚀 Released challenge %s%s", ew.eventName, challengeName, onTarget(target))
+	ew.LogToDatabase("INFO", "release", challengeName, "", "Released challenge"+onTarget(target), "", duration)
+
+	if ew.announcer == nil || target != "" {
+		return
+	}
+	a := announce.Announcement{
+		Type:        announce.TypeChallengeReleased,
+		Event:       ew.eventName,
+		Challenge:   challengeConf.Name,
+		Category:    challengeConf.Category,
+		Value:       challengeConf.Value,
+		Author:      challengeConf.Author,
+		ChallengeID: challengeID,
+		Time:        time.Now().UTC(),
+	}
+	if err := ew.announcer.Send(ew.ctx, a); err != nil {
+		log.Error("[%s] Failed to announce released challenge %s: %v", ew.eventName, challengeName, err)
+		ew.LogToDatabase("ERROR", "announce", challengeName, "", "Failed to announce released challenge", err.Error(), 0)
+	}
+}
+
+// stopReleases cancels every scheduled release
+func (ew *EventWatcher) stopReleases() {
+	ew.releaseTimersMu.Lock()
+	defer ew.releaseTimersMu.Unlock()
+	for challengeName, timer := range ew.releaseTimers {
+		timer.Stop()
+		delete(ew.releaseTimers, challengeName)
+	}
+}
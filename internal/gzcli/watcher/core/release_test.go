@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func TestEventWatcher_ScheduleRelease(t *testing.T) {
+	var mu sync.Mutex
+	var enabled *bool
+	released := make(chan struct{}, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/account/login", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"succeeded": true}`))
+	})
+	mux.HandleFunc("/api/edit/games/3/challenges/7", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"id": 7, "title": "Wave 2", "isEnabled": false}`))
+		case http.MethodPut:
+			var body gzapi.Challenge
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			mu.Lock()
+			enabled = body.IsEnabled
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{}`))
+			released <- struct{}{}
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	api, err := gzapi.Init(server.URL, &gzapi.Creds{Username: "admin", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ew := &EventWatcher{eventName: "ctf", api: api, ctx: ctx, releaseTimers: make(map[string]*time.Timer)}
+
+	past := time.Now().Add(-time.Minute)
+	ew.scheduleRelease("web/wave-2", config.ChallengeYaml{Name: "Wave 2", ReleaseAt: &past}, 7, 3)
+	if len(ew.releaseTimers) != 0 {
+		t.Fatal("release in the past was scheduled")
+	}
+
+	later := time.Now().Add(time.Hour)
+	ew.scheduleRelease("web/wave-2", config.ChallengeYaml{Name: "Wave 2", ReleaseAt: &later}, 7, 3)
+	soon := time.Now().Add(50 * time.Millisecond)
+	ew.scheduleRelease("web/wave-2", config.ChallengeYaml{Name: "Wave 2", ReleaseAt: &soon}, 7, 3)
+
+	select {
+	case <-released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("challenge was not released")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if enabled == nil || !*enabled {
+		t.Errorf("update sent isEnabled = %v, want true", enabled)
+	}
+
+	ew.releaseTimersMu.Lock()
+	pending := len(ew.releaseTimers)
+	ew.releaseTimersMu.Unlock()
+	if pending != 0 {
+		t.Errorf("%d release(s) still scheduled, want the rescheduled one replaced and fired", pending)
+	}
+}
+
+func TestEventWatcher_ScheduleReleaseDryRun(t *testing.T) {
+	ew := &EventWatcher{
+		eventName:     "ctf",
+		config:        watchertypes.WatcherConfig{DryRun: true},
+		releaseTimers: make(map[string]*time.Timer),
+	}
+	later := time.Now().Add(time.Hour)
+	ew.scheduleRelease("web/wave-2", config.ChallengeYaml{Name: "Wave 2", ReleaseAt: &later}, 7, 3)
+	if len(ew.releaseTimers) != 0 {
+		t.Error("release scheduled in dry-run mode")
+	}
+}
+
+func TestEventWatcher_ArmReleases(t *testing.T) {
+	var mu sync.Mutex
+	var updated []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/account/login", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"succeeded": true}`))
+	})
+	mux.HandleFunc("/api/edit/games/3/challenges/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			mu.Lock()
+			updated = append(updated, r.PathValue("id"))
+			mu.Unlock()
+		}
+		_, _ = w.Write([]byte(`{"id": ` + r.PathValue("id") + `, "isEnabled": false}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	api, err := gzapi.Init(server.URL, &gzapi.Creds{Username: "admin", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventPath := t.TempDir()
+	ew := &EventWatcher{eventName: "ctf", eventPath: eventPath, api: api, ctx: ctx, releaseTimers: make(map[string]*time.Timer)}
+	defer ew.stopReleases()
+
+	past, later := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	disabled, enabled := false, true
+	pending := map[string]config.ChallengeYaml{
+		"web/future":   {Name: "Future", Cwd: eventPath + "/web/future", ReleaseAt: &later},
+		"web/missed":   {Name: "Missed", Cwd: eventPath + "/web/missed", ReleaseAt: &past},
+		"web/released": {Name: "Released", Cwd: eventPath + "/web/released", ReleaseAt: &past},
+	}
+	challenges := []gzapi.Challenge{
+		{Id: 1, Title: "Future", IsEnabled: &disabled},
+		{Id: 2, Title: "Missed", IsEnabled: &disabled},
+		{Id: 3, Title: "Released", IsEnabled: &enabled},
+	}
+	ew.armReleases(api, "", pending, challenges, 3, ew.challengeSelector)
+
+	if _, ok := ew.releaseTimers["web/future"]; !ok || len(ew.releaseTimers) != 1 {
+		t.Errorf("scheduled releases = %v, want only web/future", ew.releaseTimers)
+	}
+
+	// Mirror targets get their own release of the challenge
+	ew.armReleases(api, "staging", map[string]config.ChallengeYaml{"web/future": pending["web/future"]}, challenges, 3, func(challengeConf config.ChallengeYaml) func(gzapi.Challenge) bool {
+		return ew.mirrorSelector("staging", challengeConf, 3)
+	})
+	if _, ok := ew.releaseTimers["web/future@staging"]; !ok || len(ew.releaseTimers) != 2 {
+		t.Errorf("scheduled releases = %v, want web/future on the server and on staging", ew.releaseTimers)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updated) != 1 || updated[0] != "2" {
+		t.Errorf("enabled challenges = %v, want only the missed release 2", updated)
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// syncWaitInterval is how often SyncChallenge checks whether a running sync
+// of the challenge finished
+const syncWaitInterval = 200 * time.Millisecond
+
+// SyncChallenge fully syncs a challenge right away and returns the result,
+// for callers such as the upload server that just installed it. Challenges
+// not watched yet are discovered first, and a running sync of the challenge
+// is waited for. Paused syncs and syncs held by the live lock are refused.
+func (ew *EventWatcher) SyncChallenge(ctx context.Context, challengeName string) error {
+	challengePath, ok := ew.challengeMgr.GetChallenges()[challengeName]
+	if !ok {
+		if err := ew.discoverChallenges(); err != nil {
+			return fmt.Errorf("failed to discover challenges: %w", err)
+		}
+		if challengePath, ok = ew.challengeMgr.GetChallenges()[challengeName]; !ok {
+			return fmt.Errorf("challenge '%s' is not watched in event '%s'", challengeName, ew.eventName)
+		}
+	}
+
+	updateType := watchertypes.UpdateFullRedeploy
+	if ew.syncPaused(challengeName, updateType) {
+		return fmt.Errorf("syncs of challenge '%s' are paused", challengeName)
+	}
+	if !ew.liveLockAllows(challengeName, updateType) {
+		return fmt.Errorf("sync of challenge '%s' is held by the live lock until approved", challengeName)
+	}
+
+	if err := ew.claimSync(ctx, challengeName); err != nil {
+		return err
+	}
+	defer ew.releaseSync(challengeName)
+
+	if ew.scriptMgr != nil {
+		ew.UpdateChallengeState(challengeName, "syncing", "", ew.scriptMgr.GetActiveIntervalScripts())
+	}
+	syncStartedAt := time.Now()
+	err := ew.syncSingleChallenge(challengeName, challengePath, updateType)
+	syncEndedAt := time.Now()
+	watcherMetrics.observeSync(ew.eventName, updateType, syncEndedAt.Sub(syncStartedAt), err)
+	ew.recordActivity(challengeName, watchertypes.ActivitySync, syncStartedAt, syncEndedAt, err)
+	ew.setLastSyncAt(challengeName, syncEndedAt)
+	if err != nil {
+		log.Error("[%s] Failed to sync challenge %s: %v", ew.eventName, challengeName, err)
+		if ew.scriptMgr != nil {
+			ew.UpdateChallengeState(challengeName, "error", err.Error(), ew.scriptMgr.GetActiveIntervalScripts())
+		}
+		return err
+	}
+	if ew.scriptMgr != nil {
+		ew.UpdateChallengeState(challengeName, "watching", "", ew.scriptMgr.GetActiveIntervalScripts())
+	}
+	return nil
+}
+
+// claimSync marks the challenge as updating, once a running sync of it
+// finished
+func (ew *EventWatcher) claimSync(ctx context.Context, challengeName string) error {
+	challengeMutex := ew.GetChallengeUpdateMutex(challengeName)
+	for {
+		challengeMutex.Lock()
+		if !ew.isUpdating(challengeName) {
+			ew.setUpdating(challengeName, true)
+			challengeMutex.Unlock()
+			return nil
+		}
+		challengeMutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ew.ctx.Done():
+			return fmt.Errorf("watcher of event '%s' stopped", ew.eventName)
+		case <-time.After(syncWaitInterval):
+		}
+	}
+}
+
+// releaseSync marks the challenge as no longer updating and processes the
+// file changes that arrived during the sync
+func (ew *EventWatcher) releaseSync(challengeName string) {
+	challengeMutex := ew.GetChallengeUpdateMutex(challengeName)
+	challengeMutex.Lock()
+	pendingFilePath, hasPending := ew.getPendingUpdate(challengeName)
+	ew.setUpdating(challengeName, false)
+	challengeMutex.Unlock()
+
+	if hasPending {
+		log.InfoH3("[%s] Pending updates detected after sync for %s; syncing again", ew.eventName, challengeName)
+		ew.processChange(pendingFilePath)
+	}
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// verifyTarget returns the target of a solver without a test container:
+// verify.host and verify.port, defaulting to the public entry of the
+// container provider and the exposed port of the container
+func verifyTarget(challengeConf config.ChallengeYaml, publicEntry string) solver.Target {
+	target := solver.Target{
+		Host:       publicEntry,
+		Port:       challengeConf.Container.ContainerExposePort,
+		FlagFormat: solver.FlagFormat(challengeConf.Flags, challengeConf.Container.FlagTemplate),
+	}
+	if challengeConf.Verify.Host != "" {
+		target.Host = challengeConf.Verify.Host
+	}
+	if challengeConf.Verify.Port != 0 {
+		target.Port = challengeConf.Verify.Port
+	}
+	return target
+}
+
+// usesTestContainer reports whether the solver of a challenge runs against a
+// GZCTF test container. Container challenges get one unless verify.host or
+// verify.port point the solver elsewhere, since GZCTF maps the exposed port
+// of their instances to random host ports.
+func usesTestContainer(challengeConf config.ChallengeYaml) bool {
+	if challengeConf.Verify != nil && (challengeConf.Verify.Host != "" || challengeConf.Verify.Port != 0) {
+		return false
+	}
+	return challengeConf.Type == "StaticContainer" || challengeConf.Type == "DynamicContainer"
+}
+
+// verifyChallenge runs the solver of a synced challenge against its
+// deployment in the background when verification is enabled, then records
+// the result and announces failures. Container challenges are verified
+// against a test container started for the run. Challenges without a solver
+// are skipped.
+func (ew *EventWatcher) verifyChallenge(challengeName string, challengeConf config.ChallengeYaml, publicEntry string, challengeID, gameID int) {
+	if !challengeConf.VerifyEnabled() || ew.config.DryRun {
+		return
+	}
+	if _, ok := solver.Detect(challengeConf.Cwd); !ok {
+		log.InfoH3("[%s] %s has no solver, skipping verification", ew.eventName, challengeName)
+		return
+	}
+
+	target := verifyTarget(challengeConf, publicEntry)
+	ew.wg.Add(1)
+	go func() {
+		defer ew.wg.Done()
+		if usesTestContainer(challengeConf) {
+			remote := &gzapi.Challenge{Id: challengeID, GameId: gameID, CS: ew.api}
+			log.Info("[%s] 🧪 Starting a test container to verify %s", ew.eventName, challengeName)
+			info, err := remote.StartTestContainer(ew.ctx)
+			if err == nil {
+				target.Host, target.Port, err = info.HostPort()
+			}
+			defer func() {
+				if err := remote.DestroyTestContainer(); err != nil {
+					log.Error("[%s] Failed to stop the test container of %s: %v", ew.eventName, challengeName, err)
+				}
+			}()
+			if err != nil {
+				if ew.ctx.Err() != nil {
+					return
+				}
+				ew.recordSolverRun(challengeName, challengeConf.Cwd, "test container", solver.VerifyResult{Status: solver.VerifyError, Err: err})
+				return
+			}
+		}
+
+		address := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+		log.Info("[%s] 🧪 Verifying %s with its solver against %s", ew.eventName, challengeName, address)
+		result := solver.Verify(ew.ctx, challengeConf.Cwd, target, challengeConf.Flags, challengeConf.Verify.Timeout)
+		if ew.ctx.Err() != nil {
+			// Stopped with the watcher, not a failure of the challenge
+			return
+		}
+		ew.recordSolverRun(challengeName, challengeConf.Cwd, address, result)
+	}()
+}
+
+// recordSolverRun stores the result of a solver verification, logs it and
+// announces failures
+func (ew *EventWatcher) recordSolverRun(challengeName, challengePath, address string, result solver.VerifyResult) {
+	errorMsg := ""
+	if result.Err != nil {
+		errorMsg = result.Err.Error()
+	}
+	if ew.db != nil {
+		ew.db.LogSolverRun(watchertypes.SolverRun{
+			Event:         ew.eventName,
+			ChallengeName: challengeName,
+			Target:        address,
+			Status:        result.Status,
+			ExitCode:      result.ExitCode,
+			Error:         errorMsg,
+			Output:        result.Output,
+			Duration:      result.Duration.Milliseconds(),
+		})
+	}
+
+	if result.Status == solver.VerifyPassed {
+		log.Info("[%s] ✅ Solver of %s recovered the flag", ew.eventName, challengeName)
+		ew.LogToDatabase("INFO", "verify", challengeName, "", "Solver recovered the flag", "", result.Duration.Milliseconds())
+		return
+	}
+
+	log.Error("[%s] ❌ Solver of %s %s against %s: %v", ew.eventName, challengeName, result.Status, address, result.Err)
+	ew.LogToDatabase("ERROR", "verify", challengeName, "", fmt.Sprintf("Solver %s against %s", result.Status, address), errorMsg, result.Duration.Milliseconds())
+	ew.announceVerifyFailed(challengeName, challengePath, fmt.Sprintf("%s against %s: %s", result.Status, address, errorMsg))
+}
+
+// announceVerifyFailed posts the notification of a failed solver
+// verification. Failures are logged only.
+func (ew *EventWatcher) announceVerifyFailed(challengeName, challengePath, message string) {
+	if ew.announcer == nil {
+		return
+	}
+
+	a := announce.Announcement{
+		Type:      announce.TypeChallengeVerifyFailed,
+		Event:     ew.eventName,
+		Challenge: challengeName,
+		Category:  registry.Category(ew.eventPath, challengePath),
+		Message:   message,
+		Time:      time.Now().UTC(),
+	}
+	if err := ew.announcer.Send(ew.ctx, a); err != nil {
+		log.Error("[%s] Failed to announce the failed verification of %s: %v", ew.eventName, challengeName, err)
+		ew.LogToDatabase("ERROR", "announce", challengeName, "", "Failed to announce failed verification", err.Error(), 0)
+	}
+}
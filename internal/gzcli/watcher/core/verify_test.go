@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/config"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/solver"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/announce"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
+)
+
+func TestVerifyTarget(t *testing.T) {
+	conf := config.ChallengeYaml{
+		Flags:     []string{"CTF{static}"},
+		Container: config.Container{ContainerExposePort: 80},
+		Verify:    &config.VerifyConfig{},
+	}
+	target := verifyTarget(conf, "ctf.example.com")
+	if target.Host != "ctf.example.com" || target.Port != 80 || target.FlagFormat != `CTF\{[^}]*\}` {
+		t.Errorf("default target = %+v", target)
+	}
+
+	conf.Verify = &config.VerifyConfig{Host: "10.0.0.5", Port: 31337}
+	if target := verifyTarget(conf, "ctf.example.com"); target.Host != "10.0.0.5" || target.Port != 31337 {
+		t.Errorf("configured target = %+v", target)
+	}
+}
+
+func TestEventWatcher_VerifyChallenge(t *testing.T) {
+	announced := make(chan announce.Announcement, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a announce.Announcement
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Errorf("decode announcement: %v", err)
+		}
+		announced <- a
+	}))
+	defer webhook.Close()
+	announcer, err := announce.NewNotifier(webhook.URL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := database.New(filepath.Join(t.TempDir(), "watcher.db"), true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	eventPath := t.TempDir()
+	challengePath := filepath.Join(eventPath, "pwn", "baby-rop")
+	if err := os.MkdirAll(filepath.Join(challengePath, solver.Dir), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(challengePath, solver.Dir, "solve.sh"), []byte(`echo "CTF{stale}"`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ew := &EventWatcher{eventName: "ctf", eventPath: eventPath, ctx: ctx, db: db, announcer: announcer}
+
+	conf := config.ChallengeYaml{Name: "baby-rop", Cwd: challengePath, Flags: []string{"CTF{fresh}"},
+		Verify: &config.VerifyConfig{Host: "127.0.0.1", Port: 1337}}
+	ew.verifyChallenge("baby-rop", conf, "", 0, 0)
+	ew.wg.Wait()
+
+	runs, err := db.GetSolverRuns("ctf", "baby-rop", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != solver.VerifyFailed || runs[0].Target != "127.0.0.1:1337" || runs[0].Output != "CTF{stale}\n" {
+		t.Fatalf("solver runs = %+v, want one failed run", runs)
+	}
+
+	select {
+	case a := <-announced:
+		if a.Type != announce.TypeChallengeVerifyFailed || a.Category != "pwn" || !strings.Contains(a.Message, "no flag") {
+			t.Errorf("announcement = %+v", a)
+		}
+	default:
+		t.Error("failed verification was not announced")
+	}
+
+	// Challenges without a verify block are not verified
+	conf.Verify = nil
+	ew.verifyChallenge("baby-rop", conf, "", 0, 0)
+	ew.wg.Wait()
+	if runs, _ := db.GetSolverRuns("ctf", "baby-rop", 10); len(runs) != 1 {
+		t.Errorf("got %d solver runs, want no new run without verification", len(runs))
+	}
+}
+
+func TestEventWatcher_VerifyChallengeTestContainer(t *testing.T) {
+	destroyed := make(chan struct{}, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/account/login", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"succeeded": true}`))
+	})
+	mux.HandleFunc("/api/edit/games/3/challenges/7/container", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			_, _ = w.Write([]byte(`{"status": "Running", "entry": "10.0.0.9:32768"}`))
+		case http.MethodDelete:
+			destroyed <- struct{}{}
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	api, err := gzapi.Init(server.URL, &gzapi.Creds{Username: "admin", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := database.New(filepath.Join(t.TempDir(), "watcher.db"), true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	challengePath := filepath.Join(t.TempDir(), "pwn", "baby-rop")
+	if err := os.MkdirAll(filepath.Join(challengePath, solver.Dir), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(challengePath, solver.Dir, "solve.sh"), []byte(`echo "CTF{$HOST:$PORT}"`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ew := &EventWatcher{eventName: "ctf", ctx: ctx, api: api, db: db}
+
+	conf := config.ChallengeYaml{Name: "baby-rop", Type: "DynamicContainer", Cwd: challengePath,
+		Flags: []string{"CTF{10.0.0.9:32768}"}, Container: config.Container{ContainerExposePort: 1337},
+		Verify: &config.VerifyConfig{}}
+	ew.verifyChallenge("baby-rop", conf, "ctf.example.com", 7, 3)
+	ew.wg.Wait()
+
+	runs, err := db.GetSolverRuns("ctf", "baby-rop", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != solver.VerifyPassed || runs[0].Target != "10.0.0.9:32768" {
+		t.Fatalf("solver runs = %+v, want one passed run against the test container", runs)
+	}
+	select {
+	case <-destroyed:
+	default:
+		t.Error("test container was not destroyed")
+	}
+}
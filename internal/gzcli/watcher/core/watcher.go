@@ -4,9 +4,14 @@ package core
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/gzapi"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/daemon"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/database"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/socket"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
@@ -22,8 +27,10 @@ type Watcher struct {
 	wg     sync.WaitGroup
 
 	// Shared components
-	db           *database.DB
-	socketServer *socket.Server
+	db            *database.DB
+	socketServer  *socket.Server
+	metricsServer *http.Server
+	crashState    *daemon.CrashState // Runs of the watcher, nil when not recorded
 
 	// Event-specific watchers
 	eventWatchers   map[string]*EventWatcher // eventName -> EventWatcher
@@ -102,6 +109,9 @@ func (w *Watcher) HandleStatusCommand(cmd watchertypes.WatcherCommand) watcherty
 	eventWatchers := w.GetAllEventWatchers()
 	totalChallenges := 0
 	allActiveScripts := make(map[string]map[string][]string) // event -> challenge -> []scripts
+	dryRunPending := []watchertypes.DryRunSync{}
+	syncProgress := []watchertypes.SyncProgress{}
+	pausedSyncs := []watchertypes.PausedSync{}
 	events := []string{}
 
 	for eventName, ew := range eventWatchers {
@@ -118,7 +128,17 @@ func (w *Watcher) HandleStatusCommand(cmd watchertypes.WatcherCommand) watcherty
 		if scriptMgr != nil {
 			allActiveScripts[eventName] = scriptMgr.GetActiveIntervalScripts()
 		}
+
+		if w.config.DryRun {
+			dryRunPending = append(dryRunPending, ew.GetDryRunPending()...)
+		}
+		if progress, ok := ew.SyncProgress(); ok {
+			syncProgress = append(syncProgress, progress)
+		}
+		pausedSyncs = append(pausedSyncs, ew.PausedSyncs()...)
 	}
+	sort.Slice(syncProgress, func(i, j int) bool { return syncProgress[i].Event < syncProgress[j].Event })
+	sort.SliceStable(pausedSyncs, func(i, j int) bool { return pausedSyncs[i].Event < pausedSyncs[j].Event })
 
 	status := map[string]interface{}{
 		"status":             "running",
@@ -127,6 +147,29 @@ func (w *Watcher) HandleStatusCommand(cmd watchertypes.WatcherCommand) watcherty
 		"active_scripts":     allActiveScripts,
 		"database_enabled":   w.config.DatabaseEnabled,
 		"socket_enabled":     w.config.SocketEnabled,
+		"dry_run":            w.config.DryRun,
+		"sync_progress":      syncProgress,
+		"paused_syncs":       pausedSyncs,
+	}
+	if w.config.DryRun {
+		status["dry_run_pending"] = dryRunPending
+	}
+	if w.SafeMode() {
+		status["status"] = "safe_mode"
+		status["crash"] = w.crashState
+	}
+	if verbose, _ := cmd.Data["verbose"].(bool); verbose && w.config.DatabaseEnabled {
+		stats, err := w.db.GetSyncStats(filterEvent, "")
+		if err != nil {
+			return watchertypes.WatcherResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Failed to get sync stats: %v", err),
+			}
+		}
+		if stats == nil {
+			stats = []watchertypes.SyncStats{}
+		}
+		status["sync_stats"] = stats
 	}
 
 	return watchertypes.WatcherResponse{
@@ -381,6 +424,457 @@ func (w *Watcher) HandleGetScriptExecutionsCommand(cmd watchertypes.WatcherComma
 	}
 }
 
+func (w *Watcher) HandleGetDryRunSyncsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	if !w.config.DatabaseEnabled {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Database logging is disabled",
+		}
+	}
+
+	filterEvent := cmd.Event
+	limit := 100
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && filterEvent == "" {
+			filterEvent = ev
+		}
+		if l, ok := cmd.Data["limit"].(float64); ok {
+			limit = int(l)
+		}
+	}
+
+	syncs, err := w.db.GetDryRunSyncs(filterEvent, limit)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to get dry-run syncs: %v", err),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Retrieved %d dry-run syncs", len(syncs)),
+		Data:    map[string]interface{}{"syncs": syncs},
+	}
+}
+
+func (w *Watcher) HandleGetImageScansCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	if !w.config.DatabaseEnabled {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Database logging is disabled",
+		}
+	}
+
+	filterEvent := cmd.Event
+	filterChallenge := ""
+	limit := 100
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && filterEvent == "" {
+			filterEvent = ev
+		}
+		if name, ok := cmd.Data["challenge_name"].(string); ok {
+			filterChallenge = name
+		}
+		if l, ok := cmd.Data["limit"].(float64); ok {
+			limit = int(l)
+		}
+	}
+
+	scans, err := w.db.GetImageScans(filterEvent, filterChallenge, limit)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to get image scans: %v", err),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Retrieved %d image scans", len(scans)),
+		Data:    map[string]interface{}{"scans": scans},
+	}
+}
+
+func (w *Watcher) HandleGetSyncActivityCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	if !w.config.DatabaseEnabled {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Database logging is disabled",
+		}
+	}
+
+	filterEvent := cmd.Event
+	since := time.Now().Add(-24 * time.Hour)
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && filterEvent == "" {
+			filterEvent = ev
+		}
+		if ms, ok := cmd.Data["since"].(float64); ok {
+			since = time.UnixMilli(int64(ms))
+		}
+	}
+
+	activity, err := w.db.GetSyncActivity(filterEvent, since)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to get sync activity: %v", err),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Retrieved %d sync activity entries", len(activity)),
+		Data:    map[string]interface{}{"activity": activity},
+	}
+}
+
+func (w *Watcher) HandleSearchLogsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	if !w.config.DatabaseEnabled {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Database logging is disabled",
+		}
+	}
+
+	var query string
+	raw := false
+	limit := 50
+	since := time.Now().Add(-24 * time.Hour)
+	if cmd.Data != nil {
+		query, _ = cmd.Data["query"].(string)
+		raw, _ = cmd.Data["raw"].(bool)
+		if l, ok := cmd.Data["limit"].(float64); ok {
+			limit = int(l)
+		}
+		if ms, ok := cmd.Data["since"].(float64); ok {
+			since = time.UnixMilli(int64(ms))
+		}
+	}
+	if !raw {
+		query = database.QuoteSearchTerms(query)
+	}
+
+	results, err := w.db.SearchLogs(query, since, limit)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to search logs: %v", err),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Found %d matches", len(results)),
+		Data:    map[string]interface{}{"results": results},
+	}
+}
+
+func (w *Watcher) HandleChallengeStatusCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	if !w.config.DatabaseEnabled {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Database logging is disabled",
+		}
+	}
+
+	eventName := cmd.Event
+	var challengeName string
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && eventName == "" {
+			eventName = ev
+		}
+		challengeName, _ = cmd.Data["challenge_name"].(string)
+	}
+
+	if eventName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing event parameter",
+		}
+	}
+	if challengeName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing challenge_name parameter",
+		}
+	}
+
+	ew, exists := w.GetEventWatcher(eventName)
+	if !exists {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Event '%s' is not being watched", eventName),
+		}
+	}
+
+	status, err := ew.ChallengeStatus(challengeName)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Status of challenge '%s' in event '%s'", challengeName, eventName),
+		Data:    map[string]interface{}{"challenge": status},
+	}
+}
+
+func (w *Watcher) HandleApproveSyncCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	eventName := cmd.Event
+	var challengeName string
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && eventName == "" {
+			eventName = ev
+		}
+		challengeName, _ = cmd.Data["challenge_name"].(string)
+	}
+
+	if eventName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing event parameter",
+		}
+	}
+	if challengeName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing challenge_name parameter",
+		}
+	}
+
+	ew, exists := w.GetEventWatcher(eventName)
+	if !exists {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Event '%s' is not being watched", eventName),
+		}
+	}
+
+	updateType, err := ew.ApproveSync(challengeName)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Approved the held %v sync of challenge '%s' in event '%s'", updateType, challengeName, eventName),
+		Data:    map[string]interface{}{"update_type": updateType.String()},
+	}
+}
+
+// HandleSyncChallengeCommand fully syncs a challenge of an event and answers
+// once the sync finished, with its error if it failed
+func (w *Watcher) HandleSyncChallengeCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	ew, challengeName, failed := w.pauseTarget(cmd)
+	if failed != nil {
+		return *failed
+	}
+	if challengeName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing challenge_name parameter",
+		}
+	}
+
+	if err := ew.SyncChallenge(w.ctx, challengeName); err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Synced challenge '%s' in event '%s'", challengeName, ew.eventName),
+	}
+}
+
+// pauseTarget returns the event and optional challenge of a pause, resume or
+// sync command, and a failed response if the event is missing or not watched
+func (w *Watcher) pauseTarget(cmd watchertypes.WatcherCommand) (*EventWatcher, string, *watchertypes.WatcherResponse) {
+	eventName := cmd.Event
+	var challengeName string
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && eventName == "" {
+			eventName = ev
+		}
+		challengeName, _ = cmd.Data["challenge_name"].(string)
+	}
+
+	if eventName == "" {
+		return nil, "", &watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing event parameter",
+		}
+	}
+
+	ew, exists := w.GetEventWatcher(eventName)
+	if !exists {
+		return nil, "", &watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Event '%s' is not being watched", eventName),
+		}
+	}
+	return ew, challengeName, nil
+}
+
+func (w *Watcher) HandlePauseSyncCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	ew, challengeName, failed := w.pauseTarget(cmd)
+	if failed != nil {
+		return *failed
+	}
+	reason, _ := cmd.Data["reason"].(string)
+
+	if err := ew.PauseSync(challengeName, reason); err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	message := fmt.Sprintf("Paused syncs of event '%s'", ew.GetEventName())
+	if challengeName != "" {
+		message = fmt.Sprintf("Paused syncs of challenge '%s' in event '%s'", challengeName, ew.GetEventName())
+	}
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: message,
+	}
+}
+
+func (w *Watcher) HandleResumeSyncCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	ew, challengeName, failed := w.pauseTarget(cmd)
+	if failed != nil {
+		return *failed
+	}
+
+	synced, err := ew.ResumeSync(challengeName)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	message := fmt.Sprintf("Resumed syncs of event '%s'", ew.GetEventName())
+	if challengeName != "" {
+		message = fmt.Sprintf("Resumed syncs of challenge '%s' in event '%s'", challengeName, ew.GetEventName())
+	}
+	if len(synced) > 0 {
+		message += fmt.Sprintf(", syncing the changes skipped by %s", strings.Join(synced, ", "))
+	}
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: message,
+		Data:    map[string]interface{}{"synced": synced},
+	}
+}
+
+func (w *Watcher) HandleRemapChallengesCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	eventName := cmd.Event
+	var folders []string
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && eventName == "" {
+			eventName = ev
+		}
+		if list, ok := cmd.Data["folders"].([]interface{}); ok {
+			for _, f := range list {
+				if folder, ok := f.(string); ok {
+					folders = append(folders, folder)
+				}
+			}
+		}
+	}
+
+	if eventName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing event parameter",
+		}
+	}
+
+	ew, exists := w.GetEventWatcher(eventName)
+	if !exists {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Event '%s' is not being watched", eventName),
+		}
+	}
+
+	remapped, err := ew.RemapChallenges(folders)
+	if err != nil {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to remap challenges: %v", err),
+		}
+	}
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Dropped %d challenge mapping(s) in event '%s'", len(remapped), eventName),
+		Data:    map[string]interface{}{"remapped": remapped},
+	}
+}
+
+func (w *Watcher) HandleSetMappingsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse {
+	eventName := cmd.Event
+	gameID := 0
+	var mappings []watchertypes.ChallengeMapping
+	if cmd.Data != nil {
+		if ev, ok := cmd.Data["event"].(string); ok && eventName == "" {
+			eventName = ev
+		}
+		if id, ok := cmd.Data["game_id"].(float64); ok {
+			gameID = int(id)
+		}
+		if list, ok := cmd.Data["mappings"].([]interface{}); ok {
+			for _, item := range list {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				folder, _ := m["folder"].(string)
+				id, _ := m["challenge_id"].(float64)
+				title, _ := m["title"].(string)
+				if folder == "" || id <= 0 {
+					continue
+				}
+				mappings = append(mappings, watchertypes.ChallengeMapping{Folder: folder, ChallengeID: int(id), Title: title})
+			}
+		}
+	}
+
+	if eventName == "" {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   "Missing event parameter",
+		}
+	}
+
+	ew, exists := w.GetEventWatcher(eventName)
+	if !exists {
+		return watchertypes.WatcherResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Event '%s' is not being watched", eventName),
+		}
+	}
+
+	ew.SetChallengeMappings(gameID, mappings)
+
+	return watchertypes.WatcherResponse{
+		Success: true,
+		Message: fmt.Sprintf("Recorded %d challenge mapping(s) in event '%s'", len(mappings), eventName),
+	}
+}
+
 // StopEventWatcher stops a specific event watcher
 func (w *Watcher) StopEventWatcher(eventName string) error {
 	ew, exists := w.GetEventWatcher(eventName)
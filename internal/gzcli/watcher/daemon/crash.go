@@ -0,0 +1,159 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	// CrashLoopThreshold is the number of consecutive crashes after which the
+	// watcher starts in safe mode
+	CrashLoopThreshold = 3
+
+	// CrashLoopWindow is the uptime below which a crashed run counts towards
+	// a crash loop; a crash after a longer run starts counting again
+	CrashLoopWindow = 10 * time.Minute
+
+	// crashTraceLimit is the size of the end of a crash trace kept in the
+	// crash state
+	crashTraceLimit = 64 << 10
+)
+
+// CrashState records the runs of the watcher to detect crash loops. A run is
+// marked running when it starts and cleared when it stops cleanly, so a run
+// still marked running at the next start has crashed.
+type CrashState struct {
+	Running     bool       `json:"running"`
+	Crashes     int        `json:"crashes"`
+	LastStartAt time.Time  `json:"last_start_at"`
+	LastCrashAt *time.Time `json:"last_crash_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastTrace   string     `json:"last_trace,omitempty"`
+	SafeMode    bool       `json:"safe_mode"`
+	// RunError is the error the current run failed with, if any
+	RunError string `json:"run_error,omitempty"`
+}
+
+// CrashFile returns the crash state file kept next to the PID file
+func CrashFile(pidFile string) string {
+	return filepath.Join(filepath.Dir(pidFile), "crash.json")
+}
+
+// CrashTraceFile returns the file receiving the trace of a fatal panic,
+// kept next to the PID file
+func CrashTraceFile(pidFile string) string {
+	return filepath.Join(filepath.Dir(pidFile), "crash.log")
+}
+
+// ReadCrashState reads the crash state of the watcher, empty when none was
+// recorded
+func ReadCrashState(pidFile string) (*CrashState, error) {
+	state := &CrashState{}
+	//nolint:gosec // G304: Crash file path is constructed by application
+	data, err := os.ReadFile(CrashFile(pidFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid crash file: %w", err)
+	}
+	return state, nil
+}
+
+// writeCrashState writes the crash state of the watcher
+func writeCrashState(pidFile string, state *CrashState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0750); err != nil {
+		return fmt.Errorf("failed to create crash file directory: %w", err)
+	}
+	return os.WriteFile(CrashFile(pidFile), data, 0600)
+}
+
+// RecordStart marks a new run of the watcher. If the previous run did not
+// stop cleanly it counts as a crash, with the trace of its panic if one was
+// captured; after CrashLoopThreshold consecutive crashes the returned state
+// is in safe mode. Stopping cleanly with RecordCleanStop resets the count.
+func RecordStart(pidFile string, now time.Time) (*CrashState, error) {
+	state, err := ReadCrashState(pidFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Running {
+		if now.Sub(state.LastStartAt) > CrashLoopWindow {
+			state.Crashes = 0
+		}
+		state.Crashes++
+		state.LastError, state.LastTrace = state.RunError, ""
+		crashedAt := now
+		//nolint:gosec // G304: Crash trace path is constructed by application
+		if trace, err := os.ReadFile(CrashTraceFile(pidFile)); err == nil && len(trace) > 0 {
+			if len(trace) > crashTraceLimit {
+				trace = trace[len(trace)-crashTraceLimit:]
+			}
+			state.LastTrace = string(trace)
+			if info, err := os.Stat(CrashTraceFile(pidFile)); err == nil {
+				crashedAt = info.ModTime()
+			}
+		}
+		state.LastCrashAt = &crashedAt
+	}
+
+	state.Running = true
+	state.RunError = ""
+	state.LastStartAt = now
+	state.SafeMode = state.Crashes >= CrashLoopThreshold
+	if err := writeCrashState(pidFile, state); err != nil {
+		return nil, fmt.Errorf("failed to write crash file: %w", err)
+	}
+	return state, nil
+}
+
+// RecordError keeps the error the current run failed with, shown with the
+// crash diagnostics of the next run
+func RecordError(pidFile string, runErr error) error {
+	state, err := ReadCrashState(pidFile)
+	if err != nil {
+		return err
+	}
+	state.RunError = runErr.Error()
+	return writeCrashState(pidFile, state)
+}
+
+// RecordCleanStop marks the current run as stopped on purpose, resetting the
+// crash count
+func RecordCleanStop(pidFile string) error {
+	state, err := ReadCrashState(pidFile)
+	if err != nil {
+		return err
+	}
+	if !state.Running && state.Crashes == 0 {
+		return nil
+	}
+	state.Running = false
+	state.Crashes = 0
+	state.SafeMode = false
+	return writeCrashState(pidFile, state)
+}
+
+// CaptureCrashes writes the trace of a fatal panic of any goroutine of the
+// process to the crash trace file, read by RecordStart on the next run
+func CaptureCrashes(pidFile string) error {
+	//nolint:gosec // G304: Crash trace path is constructed by application
+	f, err := os.OpenFile(CrashTraceFile(pidFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return debug.SetCrashOutput(f, debug.CrashOptions{})
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordStart_SafeModeAfterCrashLoop(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "watcher", "watcher.pid")
+	now := time.Now()
+
+	for i := 0; i < CrashLoopThreshold; i++ {
+		state, err := RecordStart(pidFile, now.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatalf("RecordStart() error = %v", err)
+		}
+		if state.Crashes != i || state.SafeMode {
+			t.Fatalf("start %d: crashes = %d, safe mode = %v", i, state.Crashes, state.SafeMode)
+		}
+	}
+
+	if err := RecordError(pidFile, errors.New("failed to start event watchers")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(CrashTraceFile(pidFile), []byte("panic: boom\n\ngoroutine 1 [running]:\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := RecordStart(pidFile, now.Add(5*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.SafeMode || state.Crashes != CrashLoopThreshold {
+		t.Fatalf("expected safe mode after %d crashes, got %+v", CrashLoopThreshold, state)
+	}
+	if state.LastError != "failed to start event watchers" || state.RunError != "" {
+		t.Errorf("LastError = %q, RunError = %q", state.LastError, state.RunError)
+	}
+	if state.LastTrace == "" || state.LastCrashAt == nil {
+		t.Error("expected the panic trace of the last crash")
+	}
+
+	if err := RecordCleanStop(pidFile); err != nil {
+		t.Fatal(err)
+	}
+	state, err = RecordStart(pidFile, now.Add(6*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.SafeMode || state.Crashes != 0 {
+		t.Errorf("a clean stop must reset the crash count, got %+v", state)
+	}
+}
+
+func TestRecordStart_CrashAfterLongRun(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "watcher.pid")
+	now := time.Now()
+
+	for i := 0; i < CrashLoopThreshold; i++ {
+		if _, err := RecordStart(pidFile, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state, err := RecordStart(pidFile, now.Add(CrashLoopWindow+time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Crashes != 1 || state.SafeMode {
+		t.Errorf("a crash after a long run must start counting again, got %+v", state)
+	}
+}
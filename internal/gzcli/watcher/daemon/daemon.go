@@ -98,6 +98,11 @@ func StopDaemon(pidFile string) error {
 		return fmt.Errorf("failed to remove PID file: %w", err)
 	}
 
+	// A stopped daemon did not crash, the next start runs normally
+	if err := RecordCleanStop(pidFile); err != nil {
+		log.Error("Failed to record watcher stop: %v", err)
+	}
+
 	log.Info("✅ GZCTF Watcher daemon stopped successfully")
 	return nil
 }
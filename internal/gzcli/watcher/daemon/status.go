@@ -3,6 +3,8 @@ package daemon
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 	"github.com/dimasma0305/gzcli/internal/log"
@@ -61,6 +63,12 @@ func ShowStatus(pidFile, logFile string, jsonOutput bool) error {
 		log.Info("📄 PID File: %s", pidFile)
 	}
 
+	crashState, err := ReadCrashState(pidFile)
+	if err != nil {
+		log.Error("Failed to read crash state: %v", err)
+	}
+	showCrashState(crashState)
+
 	log.Info("")
 	log.Info("🛠️  Available Commands:")
 	log.Info("   - Start daemon: gzcli --watch")
@@ -69,14 +77,14 @@ func ShowStatus(pidFile, logFile string, jsonOutput bool) error {
 
 	// Output JSON format if requested
 	if jsonOutput {
-		return outputStatusJSON(daemonStatus, pidFile, logFile, isDaemon, daemonState)
+		return outputStatusJSON(daemonStatus, pidFile, logFile, isDaemon, daemonState, crashState)
 	}
 
 	return nil
 }
 
 // outputStatusJSON outputs status in JSON format
-func outputStatusJSON(daemonStatus map[string]interface{}, pidFile, logFile string, isDaemon bool, daemonState string) error {
+func outputStatusJSON(daemonStatus map[string]interface{}, pidFile, logFile string, isDaemon bool, daemonState string, crashState *CrashState) error {
 	// Create a cleaner status object for JSON
 	jsonStatus := map[string]interface{}{
 		"daemon_running": isDaemon && daemonState == "running",
@@ -95,6 +103,11 @@ func outputStatusJSON(daemonStatus map[string]interface{}, pidFile, logFile stri
 		jsonStatus["message"] = msg
 	}
 
+	if crashState != nil && (crashState.Crashes > 0 || crashState.LastCrashAt != nil) {
+		jsonStatus["safe_mode"] = crashState.SafeMode
+		jsonStatus["crash"] = crashState
+	}
+
 	log.Info("")
 	jsonData, err := json.MarshalIndent(jsonStatus, "", "  ")
 	if err != nil {
@@ -103,3 +116,33 @@ func outputStatusJSON(daemonStatus map[string]interface{}, pidFile, logFile stri
 	fmt.Println(string(jsonData))
 	return nil
 }
+
+// showCrashState displays the crashes of the watcher, with the diagnostics of
+// the last one while they are in a row
+func showCrashState(state *CrashState) {
+	if state == nil || state.Crashes == 0 {
+		return
+	}
+
+	log.Info("")
+	if state.SafeMode {
+		log.Info("⚠️  SAFE MODE: crashed %d times in a row, challenges are not watched", state.Crashes)
+	} else {
+		log.Info("⚠️  Crashed %d time(s) in a row (safe mode after %d)", state.Crashes, CrashLoopThreshold)
+	}
+	if state.LastCrashAt != nil {
+		log.Info("   - Last crash: %s", state.LastCrashAt.Local().Format(time.DateTime))
+	}
+	if state.LastError != "" {
+		log.Info("   - Last error: %s", state.LastError)
+	}
+	if state.LastTrace != "" {
+		log.Info("   - Last panic trace:")
+		for _, line := range strings.Split(strings.TrimRight(state.LastTrace, "\n"), "\n") {
+			log.Info("     %s", line)
+		}
+	}
+	if state.SafeMode {
+		log.Info("🔧 Suggestion: fix the cause, then run 'gzcli watch stop' and 'gzcli watch start'")
+	}
+}
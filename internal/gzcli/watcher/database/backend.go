@@ -0,0 +1,257 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	// Register the pgx Postgres driver for database/sql
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// URLEnv is the environment variable holding the Postgres URL of a watcher
+// database shared by the watchers of several hosts
+const URLEnv = "GZCLI_WATCHER_DATABASE_URL"
+
+// Backend is the database server the watcher database is stored in. Queries
+// are written for SQLite with ? placeholders; a backend rewrites what its
+// server does not understand.
+type Backend interface {
+	// Name of the backend in logs
+	Name() string
+	// Open connects to the database at dsn, creating it if needed
+	Open(dsn string) (*sql.DB, error)
+	// Rebind rewrites the ? placeholders of a query
+	Rebind(query string) string
+	// Schema rewrites SQLite column definitions and CREATE TABLE statements
+	Schema(ddl string) string
+	// Columns returns the column names of a table
+	Columns(db *sql.DB, table string) ([]string, error)
+	// InsertID runs an INSERT into a table with an id column and returns the
+	// id of the inserted row
+	InsertID(tx *sql.Tx, query string, args ...any) (int64, error)
+	// CreateSearchIndexes creates the indexes used by SearchQuery
+	CreateSearchIndexes(db *sql.DB) error
+	// SearchQuery returns the query of SearchLogs
+	SearchQuery() string
+}
+
+// backendFor returns the backend of a database source: Postgres for
+// postgres:// and postgresql:// URLs, SQLite for file paths
+func backendFor(dsn string) Backend {
+	if IsURL(dsn) {
+		return postgresBackend{}
+	}
+	return sqliteBackend{}
+}
+
+// IsURL reports whether source is a Postgres URL rather than a file path
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "postgres://") || strings.HasPrefix(source, "postgresql://")
+}
+
+// Source returns the Postgres URL of URLEnv if set, otherwise path
+func Source(path string) string {
+	if dsn := os.Getenv(URLEnv); dsn != "" {
+		return dsn
+	}
+	return path
+}
+
+// redact hides the password of a Postgres URL for logs
+func redact(dsn string) string {
+	if !IsURL(dsn) {
+		return dsn
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "postgres://"
+	}
+	return u.Redacted()
+}
+
+// sqliteBackend stores the database in a local file
+type sqliteBackend struct{}
+
+func (sqliteBackend) Name() string { return "SQLite" }
+
+func (sqliteBackend) Open(dsn string) (*sql.DB, error) {
+	// Create database directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(dsn), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	// Open database with pragmas for better concurrency and performance
+	// Use WAL mode for concurrent reads/writes and set busy timeout
+	db, err := sql.Open("sqlite", dsn+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	// Set connection pool settings for better concurrency
+	db.SetMaxOpenConns(1) // SQLite works best with a single writer
+	db.SetMaxIdleConns(1)
+	return db, nil
+}
+
+func (sqliteBackend) Rebind(query string) string { return query }
+
+func (sqliteBackend) Schema(ddl string) string { return ddl }
+
+func (sqliteBackend) Columns(db *sql.DB, table string) ([]string, error) {
+	return queryStrings(db, `SELECT name FROM pragma_table_info(?)`, table)
+}
+
+func (sqliteBackend) InsertID(tx *sql.Tx, query string, args ...any) (int64, error) {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (sqliteBackend) CreateSearchIndexes(db *sql.DB) error {
+	return createSearchIndexes(db)
+}
+
+func (sqliteBackend) SearchQuery() string {
+	return sqliteSearchQuery
+}
+
+// postgresBackend stores the database in a Postgres server, such as the one
+// of GZCTF, so the watchers of several hosts share it
+type postgresBackend struct{}
+
+func (postgresBackend) Name() string { return "Postgres" }
+
+func (postgresBackend) Open(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(4)
+	db.SetMaxIdleConns(2)
+	return db, nil
+}
+
+// Rebind numbers the ? placeholders outside of quoted strings and
+// identifiers as $1, $2, ...
+func (postgresBackend) Rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	var quote rune
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// postgresTypes maps the SQLite column types of the schema to Postgres,
+// with times as TIMESTAMPTZ and integers wide enough for unix milliseconds
+var postgresTypes = strings.NewReplacer(
+	"INTEGER PRIMARY KEY AUTOINCREMENT", "BIGSERIAL PRIMARY KEY",
+	"DATETIME", "TIMESTAMPTZ",
+	"INTEGER", "BIGINT",
+)
+
+func (postgresBackend) Schema(ddl string) string {
+	return postgresTypes.Replace(ddl)
+}
+
+func (postgresBackend) Columns(db *sql.DB, table string) ([]string, error) {
+	return queryStrings(db, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+	`, table)
+}
+
+func (p postgresBackend) InsertID(tx *sql.Tx, query string, args ...any) (int64, error) {
+	var id int64
+	err := tx.QueryRow(p.Rebind(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+// Columns searched in each table, as in the FTS5 indexes of SQLite
+var (
+	logSearchColumns    = []string{"message", "error", "challenge", "script"}
+	scriptSearchColumns = []string{"output", "error_output", "command", "challenge_name", "script_name"}
+)
+
+// tsDocument returns the text search vector of columns, prefixed with a
+// table alias if not empty. Indexes and queries must build it the same way.
+func tsDocument(alias string, columns []string) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		if alias != "" {
+			c = alias + "." + c
+		}
+		parts[i] = "coalesce(" + c + ", '')"
+	}
+	return "to_tsvector('simple', " + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+func (postgresBackend) CreateSearchIndexes(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_logs_search ON watcher_logs USING GIN (` + tsDocument("", logSearchColumns) + `);
+		CREATE INDEX IF NOT EXISTS idx_executions_search ON script_executions USING GIN (` + tsDocument("", scriptSearchColumns) + `);
+	`)
+	return err
+}
+
+// SearchQuery takes the same arguments as the FTS5 query of SQLite. Quoted
+// terms of QuoteSearchTerms are matched as words by websearch_to_tsquery.
+func (postgresBackend) SearchQuery() string {
+	search := func(source, alias, table, columns string, searched []string) string {
+		document := tsDocument(alias, searched)
+		return `
+		SELECT '` + source + `', ` + columns + `,
+			ts_headline('simple', concat_ws(' ', ` + alias + `.` + strings.Join(searched, ", "+alias+".") + `), q,
+				CONCAT('StartSel=', ?::text, ', StopSel=', ?::text, ', MaxWords=16, MinWords=4')),
+			-ts_rank(` + document + `, q)
+		FROM ` + table + ` ` + alias + `, websearch_to_tsquery('simple', ?) q
+		WHERE ` + document + ` @@ q AND ` + alias + `.timestamp >= ?`
+	}
+	return search("log", "l", "watcher_logs", "l.id, l.timestamp, l.challenge, l.script, l.level", logSearchColumns) + `
+		UNION ALL` +
+		search("script", "e", "script_executions", "e.id, e.timestamp, e.challenge_name, e.script_name, e.status", scriptSearchColumns) + `
+		ORDER BY 3 DESC
+		LIMIT ?
+	`
+}
+
+// queryStrings returns the first column of the rows of a query
+func queryStrings(db *sql.DB, query string, args ...any) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
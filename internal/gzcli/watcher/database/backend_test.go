@@ -0,0 +1,59 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBackendFor(t *testing.T) {
+	tests := map[string]string{
+		".gzcli/watcher/watcher.db":        "SQLite",
+		"postgres://gzctf@db/gzcli":        "Postgres",
+		"postgresql://gzctf@db:5432/gzcli": "Postgres",
+	}
+	for source, want := range tests {
+		if got := backendFor(source).Name(); got != want {
+			t.Errorf("backendFor(%q) = %s, want %s", source, got, want)
+		}
+	}
+}
+
+func TestPostgresBackend_Rebind(t *testing.T) {
+	got := postgresBackend{}.Rebind(`SELECT '?' FROM t WHERE a = ? AND "b?" = ? LIMIT ?`)
+	want := `SELECT '?' FROM t WHERE a = $1 AND "b?" = $2 LIMIT $3`
+	if got != want {
+		t.Errorf("Rebind() = %q, want %q", got, want)
+	}
+}
+
+func TestPostgresBackend_Schema(t *testing.T) {
+	got := postgresBackend{}.Schema(`CREATE TABLE t (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+		started_at INTEGER NOT NULL
+	)`)
+	for _, want := range []string{"id BIGSERIAL PRIMARY KEY", "timestamp TIMESTAMPTZ DEFAULT", "started_at BIGINT NOT NULL"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Schema() = %s, missing %q", got, want)
+		}
+	}
+}
+
+func TestPostgresBackend_SearchQuery(t *testing.T) {
+	query := postgresBackend{}.Rebind(postgresBackend{}.SearchQuery())
+	if !strings.Contains(query, "$9") || strings.Contains(query, "$10") {
+		t.Errorf("SearchQuery() does not take the 9 arguments of SearchLogs:\n%s", query)
+	}
+	if !strings.Contains(query, tsDocument("l", logSearchColumns)+" @@ q") {
+		t.Error("SearchQuery() does not match the indexed log document")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := redact("postgres://gzctf:s3cret@db/gzcli"); strings.Contains(got, "s3cret") {
+		t.Errorf("redact() = %q, leaks the password", got)
+	}
+	if got := redact("/tmp/watcher.db"); got != "/tmp/watcher.db" {
+		t.Errorf("redact() = %q, want the path", got)
+	}
+}
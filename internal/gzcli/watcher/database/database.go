@@ -1,11 +1,10 @@
-// Package database provides SQLite database operations for watcher logging and state management
+// Package database provides the watcher database for logging and state
+// management, stored in a local SQLite file or a shared Postgres server
 package database
 
 import (
 	"database/sql"
 	"fmt"
-	"os"
-	"path/filepath"
 	"sync"
 
 	"github.com/dimasma0305/gzcli/internal/log"
@@ -20,13 +19,17 @@ type DB struct {
 	mu      sync.RWMutex
 	enabled bool
 	path    string
+	backend Backend
 }
 
-// New creates a new database instance
-func New(dbPath string, enabled bool) *DB {
+// New creates a new database instance stored at source: a SQLite file path,
+// or a postgres:// URL to share the database between the watchers of several
+// hosts
+func New(source string, enabled bool) *DB {
 	return &DB{
-		path:    dbPath,
+		path:    source,
 		enabled: enabled,
+		backend: backendFor(source),
 	}
 }
 
@@ -37,27 +40,12 @@ func (d *DB) Init() error {
 		return nil
 	}
 
-	dbPath := d.path
-	log.Info("Initializing SQLite database: %s", dbPath)
-
-	// Create database directory if it doesn't exist
-	dbDir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dbDir, 0750); err != nil {
-		return fmt.Errorf("failed to create database directory: %w", err)
-	}
-
-	// Open database with pragmas for better concurrency and performance
-	// Use WAL mode for concurrent reads/writes and set busy timeout
-	dbPath += "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
-	db, err := sql.Open("sqlite", dbPath)
+	log.Info("Initializing %s database: %s", d.backend.Name(), redact(d.path))
+	db, err := d.backend.Open(d.path)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Set connection pool settings for better concurrency
-	db.SetMaxOpenConns(1) // SQLite works best with a single writer
-	db.SetMaxIdleConns(1)
-
 	// Test connection
 	if err := db.Ping(); err != nil {
 		_ = db.Close()
@@ -109,13 +97,15 @@ func (d *DB) createTables() error {
 	createStatesTable := `
 		CREATE TABLE IF NOT EXISTS challenge_states (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			challenge_name TEXT UNIQUE NOT NULL,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
 			status TEXT NOT NULL,
 			last_update DATETIME DEFAULT CURRENT_TIMESTAMP,
 			error_message TEXT,
-			script_states TEXT
+			script_states TEXT,
+			UNIQUE (event, challenge_name)
 		);
-		CREATE INDEX IF NOT EXISTS idx_states_name ON challenge_states(challenge_name);
+		CREATE INDEX IF NOT EXISTS idx_states_name ON challenge_states(event, challenge_name);
 		CREATE INDEX IF NOT EXISTS idx_states_status ON challenge_states(status);
 	`
 
@@ -146,6 +136,7 @@ func (d *DB) createTables() error {
 			event TEXT NOT NULL,
 			folder_path TEXT NOT NULL,
 			challenge_id INTEGER NOT NULL,
+			game_id INTEGER NOT NULL DEFAULT 0,
 			challenge_title TEXT NOT NULL,
 			last_synced DATETIME DEFAULT CURRENT_TIMESTAMP,
 			PRIMARY KEY (event, folder_path)
@@ -154,32 +145,282 @@ func (d *DB) createTables() error {
 		CREATE INDEX IF NOT EXISTS idx_mappings_event ON challenge_mappings(event);
 	`
 
+	// Create dry_run_syncs table for syncs computed but not applied in dry-run mode
+	createDryRunTable := `
+		CREATE TABLE IF NOT EXISTS dry_run_syncs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			action TEXT NOT NULL,
+			challenge_id INTEGER,
+			diff TEXT,
+			update_type TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_dry_run_timestamp ON dry_run_syncs(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_dry_run_event ON dry_run_syncs(event);
+	`
+
+	// Create image_scans table for scans run before full redeploys
+	createImageScansTable := `
+		CREATE TABLE IF NOT EXISTS image_scans (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			image TEXT NOT NULL,
+			command TEXT,
+			status TEXT NOT NULL,
+			blocked BOOLEAN NOT NULL DEFAULT FALSE,
+			exit_code INTEGER,
+			output TEXT,
+			duration INTEGER
+		);
+		CREATE INDEX IF NOT EXISTS idx_image_scans_challenge ON image_scans(event, challenge_name);
+	`
+
+	// Create solver_runs table for solver verifications run after syncs
+	createSolverRunsTable := `
+		CREATE TABLE IF NOT EXISTS solver_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			target TEXT,
+			status TEXT NOT NULL,
+			exit_code INTEGER,
+			error TEXT,
+			output TEXT,
+			duration INTEGER
+		);
+		CREATE INDEX IF NOT EXISTS idx_solver_runs_challenge ON solver_runs(event, challenge_name);
+	`
+
+	// Create sync_activity table for the sync/git pull timeline (times in unix milliseconds)
+	createActivityTable := `
+		CREATE TABLE IF NOT EXISTS sync_activity (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event TEXT NOT NULL,
+			challenge_name TEXT,
+			kind TEXT NOT NULL,
+			status TEXT NOT NULL,
+			started_at INTEGER NOT NULL,
+			ended_at INTEGER NOT NULL,
+			error TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_activity_started ON sync_activity(started_at);
+		CREATE INDEX IF NOT EXISTS idx_activity_event ON sync_activity(event);
+	`
+
+	// Create deployments table with the provenance of every sync (times in unix milliseconds)
+	createDeploymentsTable := `
+		CREATE TABLE IF NOT EXISTS deployments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			deployed_at INTEGER NOT NULL,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			category TEXT,
+			challenge_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			yaml_hash TEXT,
+			dist_hash TEXT,
+			image TEXT,
+			image_digest TEXT,
+			source TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_deployments_challenge ON deployments(event, challenge_id);
+	`
+
+	// Create scheduled_notices table with the game notices to post later (times in unix milliseconds)
+	createNoticesTable := `
+		CREATE TABLE IF NOT EXISTS scheduled_notices (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event TEXT NOT NULL,
+			content TEXT NOT NULL,
+			post_at INTEGER NOT NULL,
+			sent_at INTEGER,
+			notice_id INTEGER,
+			error TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_notices_pending ON scheduled_notices(event, sent_at, post_at);
+	`
+
+	// Create paused_syncs table for the challenges whose syncs are paused, with an
+	// empty challenge_name for a whole event (times in unix milliseconds)
+	createPausedTable := `
+		CREATE TABLE IF NOT EXISTS paused_syncs (
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			reason TEXT,
+			paused_at INTEGER NOT NULL,
+			PRIMARY KEY (event, challenge_name)
+		);
+	`
+
+	// Create target_mappings table for tracking folder → challenge ID on each
+	// mirror target of an event
+	createTargetMappingsTable := `
+		CREATE TABLE IF NOT EXISTS target_mappings (
+			event TEXT NOT NULL,
+			target TEXT NOT NULL,
+			folder_path TEXT NOT NULL,
+			challenge_id INTEGER NOT NULL,
+			game_id INTEGER NOT NULL,
+			challenge_title TEXT NOT NULL,
+			last_synced DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (event, target, folder_path)
+		);
+	`
+
 	// Execute table creation statements
-	if _, err := db.Exec(createLogsTable); err != nil {
+	if _, err := db.Exec(d.backend.Schema(createLogsTable)); err != nil {
 		return fmt.Errorf("failed to create watcher_logs table: %w", err)
 	}
 
-	if _, err := db.Exec(createStatesTable); err != nil {
+	if err := d.dropTableWithoutColumn(db, "challenge_states", "event"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(d.backend.Schema(createStatesTable)); err != nil {
 		return fmt.Errorf("failed to create challenge_states table: %w", err)
 	}
 
-	if _, err := db.Exec(createExecutionsTable); err != nil {
+	if _, err := db.Exec(d.backend.Schema(createExecutionsTable)); err != nil {
 		return fmt.Errorf("failed to create script_executions table: %w", err)
 	}
 
-	if _, err := db.Exec(createMappingsTable); err != nil {
+	if _, err := db.Exec(d.backend.Schema(createMappingsTable)); err != nil {
 		return fmt.Errorf("failed to create challenge_mappings table: %w", err)
 	}
+	if err := d.ensureColumn(db, "challenge_mappings", "game_id", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createDryRunTable)); err != nil {
+		return fmt.Errorf("failed to create dry_run_syncs table: %w", err)
+	}
+	if err := d.ensureColumn(db, "dry_run_syncs", "update_type", "TEXT"); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createActivityTable)); err != nil {
+		return fmt.Errorf("failed to create sync_activity table: %w", err)
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createImageScansTable)); err != nil {
+		return fmt.Errorf("failed to create image_scans table: %w", err)
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createSolverRunsTable)); err != nil {
+		return fmt.Errorf("failed to create solver_runs table: %w", err)
+	}
+
+	if err := d.renameColumn(db, "deployments", "version", "revision"); err != nil {
+		return err
+	}
+	if _, err := db.Exec(d.backend.Schema(createDeploymentsTable)); err != nil {
+		return fmt.Errorf("failed to create deployments table: %w", err)
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createNoticesTable)); err != nil {
+		return fmt.Errorf("failed to create scheduled_notices table: %w", err)
+	}
+	if err := d.ensureColumn(db, "scheduled_notices", "attempts", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := d.ensureColumn(db, "scheduled_notices", "failed_at", "INTEGER"); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createPausedTable)); err != nil {
+		return fmt.Errorf("failed to create paused_syncs table: %w", err)
+	}
+
+	if _, err := db.Exec(d.backend.Schema(createTargetMappingsTable)); err != nil {
+		return fmt.Errorf("failed to create target_mappings table: %w", err)
+	}
+
+	if err := d.backend.CreateSearchIndexes(db); err != nil {
+		return fmt.Errorf("failed to create search indexes: %w", err)
+	}
 
 	log.Info("Database tables created successfully")
 	return nil
 }
 
+// ensureColumn adds a column missing from a table created by an older version
+func (d *DB) ensureColumn(db *sql.DB, table, column, definition string) error {
+	columns, err := d.backend.Columns(db, table)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	for _, name := range columns {
+		if name == column {
+			return nil
+		}
+	}
+
+	//nolint:gosec // G201: Table and column names are constants
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, d.backend.Schema(definition))); err != nil {
+		return fmt.Errorf("failed to add %s.%s column: %w", table, column, err)
+	}
+	return nil
+}
+
+// renameColumn renames a column of a table created by an older version
+func (d *DB) renameColumn(db *sql.DB, table, from, to string) error {
+	columns, err := d.backend.Columns(db, table)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	found := false
+	for _, name := range columns {
+		if name == to {
+			return nil
+		}
+		found = found || name == from
+	}
+	if !found {
+		return nil
+	}
+
+	//nolint:gosec // G201: Table and column names are constants
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN %s TO %s`, table, from, to)); err != nil {
+		return fmt.Errorf("failed to rename %s.%s column: %w", table, from, err)
+	}
+	return nil
+}
+
+// dropTableWithoutColumn drops a table created by an older version without
+// the given column when its rows cannot be migrated, so it is recreated.
+// Only tables rewritten as the watcher runs are dropped this way.
+func (d *DB) dropTableWithoutColumn(db *sql.DB, table, column string) error {
+	columns, err := d.backend.Columns(db, table)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	for _, name := range columns {
+		if name == column {
+			return nil
+		}
+	}
+
+	//nolint:gosec // G202: Table name is a constant
+	if _, err := db.Exec(`DROP TABLE ` + table); err != nil {
+		return fmt.Errorf("failed to drop %s table: %w", table, err)
+	}
+	return nil
+}
+
 // ChallengeMapping represents a mapping between folder path and GZCTF challenge ID
 type ChallengeMapping struct {
 	Event          string
+	Target         string // Mirror target of the mapping; empty for the server of the event
 	FolderPath     string
 	ChallengeID    int
+	GameID         int // Game of the challenge; 0 for mappings stored before game IDs were recorded
 	ChallengeTitle string
 	LastSynced     string
 }
@@ -194,15 +435,16 @@ func (d *DB) GetChallengeMapping(event, folderPath string) (*ChallengeMapping, e
 	db := d.db
 	d.mu.RUnlock()
 
-	query := `SELECT event, folder_path, challenge_id, challenge_title, last_synced
+	query := `SELECT event, folder_path, challenge_id, game_id, challenge_title, last_synced
 	          FROM challenge_mappings
 	          WHERE event = ? AND folder_path = ?`
 
 	var mapping ChallengeMapping
-	err := db.QueryRow(query, event, folderPath).Scan(
+	err := db.QueryRow(d.rebind(query), event, folderPath).Scan(
 		&mapping.Event,
 		&mapping.FolderPath,
 		&mapping.ChallengeID,
+		&mapping.GameID,
 		&mapping.ChallengeTitle,
 		&mapping.LastSynced,
 	)
@@ -218,7 +460,7 @@ func (d *DB) GetChallengeMapping(event, folderPath string) (*ChallengeMapping, e
 }
 
 // SetChallengeMapping stores or updates a challenge mapping
-func (d *DB) SetChallengeMapping(event, folderPath string, challengeID int, challengeTitle string) error {
+func (d *DB) SetChallengeMapping(event, folderPath string, challengeID, gameID int, challengeTitle string) error {
 	if !d.enabled || d.db == nil {
 		return nil // Silently skip if database not enabled
 	}
@@ -227,17 +469,17 @@ func (d *DB) SetChallengeMapping(event, folderPath string, challengeID int, chal
 	db := d.db
 	d.mu.RUnlock()
 
-	query := `INSERT INTO challenge_mappings (event, folder_path, challenge_id, challenge_title, last_synced)
-	          VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	query := `INSERT INTO challenge_mappings (event, folder_path, challenge_id, game_id, challenge_title, last_synced)
+	          VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 	          ON CONFLICT(event, folder_path)
-	          DO UPDATE SET challenge_id = ?, challenge_title = ?, last_synced = CURRENT_TIMESTAMP`
+	          DO UPDATE SET challenge_id = ?, game_id = ?, challenge_title = ?, last_synced = CURRENT_TIMESTAMP`
 
-	_, err := db.Exec(query, event, folderPath, challengeID, challengeTitle, challengeID, challengeTitle)
+	_, err := db.Exec(d.rebind(query), event, folderPath, challengeID, gameID, challengeTitle, challengeID, gameID, challengeTitle)
 	if err != nil {
 		return fmt.Errorf("failed to set challenge mapping: %w", err)
 	}
 
-	log.DebugH3("Stored challenge mapping: %s/%s → ID %d (%s)", event, folderPath, challengeID, challengeTitle)
+	log.DebugH3("Stored challenge mapping: %s/%s → ID %d in game %d (%s)", event, folderPath, challengeID, gameID, challengeTitle)
 	return nil
 }
 
@@ -252,7 +494,7 @@ func (d *DB) DeleteChallengeMapping(event, folderPath string) error {
 	d.mu.RUnlock()
 
 	query := `DELETE FROM challenge_mappings WHERE event = ? AND folder_path = ?`
-	_, err := db.Exec(query, event, folderPath)
+	_, err := db.Exec(d.rebind(query), event, folderPath)
 	if err != nil {
 		return fmt.Errorf("failed to delete challenge mapping: %w", err)
 	}
@@ -271,12 +513,12 @@ func (d *DB) ListChallengeMappings(event string) ([]ChallengeMapping, error) {
 	db := d.db
 	d.mu.RUnlock()
 
-	query := `SELECT event, folder_path, challenge_id, challenge_title, last_synced
+	query := `SELECT event, folder_path, challenge_id, game_id, challenge_title, last_synced
 	          FROM challenge_mappings
 	          WHERE event = ?
 	          ORDER BY folder_path`
 
-	rows, err := db.Query(query, event)
+	rows, err := db.Query(d.rebind(query), event)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list challenge mappings: %w", err)
 	}
@@ -291,6 +533,7 @@ func (d *DB) ListChallengeMappings(event string) ([]ChallengeMapping, error) {
 			&mapping.Event,
 			&mapping.FolderPath,
 			&mapping.ChallengeID,
+			&mapping.GameID,
 			&mapping.ChallengeTitle,
 			&mapping.LastSynced,
 		); err != nil {
@@ -323,6 +566,11 @@ func (d *DB) GetDB() *sql.DB {
 	return d.db
 }
 
+// rebind rewrites the ? placeholders of a query for the backend
+func (d *DB) rebind(query string) string {
+	return d.backend.Rebind(query)
+}
+
 // IsEnabled returns whether the database is enabled
 func (d *DB) IsEnabled() bool {
 	return d.enabled
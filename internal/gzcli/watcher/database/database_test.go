@@ -1,10 +1,15 @@
 package database
 
 import (
+	"database/sql"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 )
 
 // TestNew_Creation tests database instance creation
@@ -132,6 +137,9 @@ func TestDB_Init_TablesCreated(t *testing.T) {
 		"challenge_states",
 		"script_executions",
 		"challenge_mappings",
+		"dry_run_syncs",
+		"image_scans",
+		"deployments",
 	}
 
 	for _, table := range tables {
@@ -207,9 +215,10 @@ func TestDB_ChallengeMapping_SetAndGet(t *testing.T) {
 	event := "ctf2025"
 	folderPath := "web/challenge1"
 	challengeID := 42
+	gameID := 7
 	challengeTitle := "Test Challenge"
 
-	err := db.SetChallengeMapping(event, folderPath, challengeID, challengeTitle)
+	err := db.SetChallengeMapping(event, folderPath, challengeID, gameID, challengeTitle)
 	if err != nil {
 		t.Fatalf("SetChallengeMapping() failed: %v", err)
 	}
@@ -234,6 +243,9 @@ func TestDB_ChallengeMapping_SetAndGet(t *testing.T) {
 	if mapping.ChallengeID != challengeID {
 		t.Errorf("mapping.ChallengeID = %d, want %d", mapping.ChallengeID, challengeID)
 	}
+	if mapping.GameID != gameID {
+		t.Errorf("mapping.GameID = %d, want %d", mapping.GameID, gameID)
+	}
 	if mapping.ChallengeTitle != challengeTitle {
 		t.Errorf("mapping.ChallengeTitle = %s, want %s", mapping.ChallengeTitle, challengeTitle)
 	}
@@ -255,13 +267,13 @@ func TestDB_ChallengeMapping_Update(t *testing.T) {
 	folderPath := "web/challenge1"
 
 	// Set initial mapping
-	err := db.SetChallengeMapping(event, folderPath, 42, "Original Title")
+	err := db.SetChallengeMapping(event, folderPath, 42, 0, "Original Title")
 	if err != nil {
 		t.Fatalf("SetChallengeMapping() initial failed: %v", err)
 	}
 
 	// Update mapping
-	err = db.SetChallengeMapping(event, folderPath, 99, "Updated Title")
+	err = db.SetChallengeMapping(event, folderPath, 99, 0, "Updated Title")
 	if err != nil {
 		t.Fatalf("SetChallengeMapping() update failed: %v", err)
 	}
@@ -318,7 +330,7 @@ func TestDB_ChallengeMapping_Delete(t *testing.T) {
 	folderPath := "web/challenge1"
 
 	// Set a mapping
-	err := db.SetChallengeMapping(event, folderPath, 42, "Test")
+	err := db.SetChallengeMapping(event, folderPath, 42, 0, "Test")
 	if err != nil {
 		t.Fatalf("SetChallengeMapping() failed: %v", err)
 	}
@@ -365,7 +377,7 @@ func TestDB_ChallengeMapping_List(t *testing.T) {
 	}
 
 	for _, m := range mappings {
-		err := db.SetChallengeMapping(event, m.path, m.id, m.title)
+		err := db.SetChallengeMapping(event, m.path, m.id, 0, m.title)
 		if err != nil {
 			t.Fatalf("SetChallengeMapping() failed: %v", err)
 		}
@@ -402,7 +414,7 @@ func TestDB_ChallengeMapping_DisabledDatabase(t *testing.T) {
 	defer func() { _ = db.Close() }()
 
 	// All operations should silently succeed or return empty results
-	err := db.SetChallengeMapping("event", "path", 1, "title")
+	err := db.SetChallengeMapping("event", "path", 1, 0, "title")
 	if err != nil {
 		t.Errorf("SetChallengeMapping() on disabled db should not error: %v", err)
 	}
@@ -476,7 +488,7 @@ func TestDB_ConcurrentAccess(t *testing.T) {
 			path := filepath.Join("web", "challenge", strconv.Itoa(id))
 
 			// Set mapping
-			err := db.SetChallengeMapping(event, path, id, "Test")
+			err := db.SetChallengeMapping(event, path, id, 0, "Test")
 			if err != nil {
 				t.Errorf("Concurrent SetChallengeMapping failed: %v", err)
 			}
@@ -496,3 +508,743 @@ func TestDB_ConcurrentAccess(t *testing.T) {
 		<-done
 	}
 }
+
+// TestDB_DryRunSyncs_LogAndGet tests recording and filtering dry-run syncs
+func TestDB_DryRunSyncs_LogAndGet(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	db.LogDryRunSync("ctf2025", "Web Challenge", "update", "metadata", 42, "-OriginalScore: 100\n+OriginalScore: 200")
+	db.LogDryRunSync("ctf2025", "Pwn Challenge", "create", "full_redeploy", 0, "")
+	db.LogDryRunSync("other", "Misc Challenge", "none", "attachment", 7, "")
+
+	syncs, err := db.GetDryRunSyncs("ctf2025", 10)
+	if err != nil {
+		t.Fatalf("GetDryRunSyncs() failed: %v", err)
+	}
+	if len(syncs) != 2 {
+		t.Fatalf("len(syncs) = %d, want 2", len(syncs))
+	}
+
+	// Most recent first
+	if syncs[0].ChallengeName != "Pwn Challenge" || syncs[0].Action != "create" {
+		t.Errorf("syncs[0] = %+v, want Pwn Challenge/create", syncs[0])
+	}
+	if syncs[1].ChallengeID != 42 || syncs[1].Diff == "" || syncs[1].UpdateType != "metadata" {
+		t.Errorf("syncs[1] = %+v, want ID 42 metadata update with diff", syncs[1])
+	}
+
+	all, err := db.GetDryRunSyncs("", 10)
+	if err != nil {
+		t.Fatalf("GetDryRunSyncs() without event failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("len(all) = %d, want 3", len(all))
+	}
+}
+
+// TestDB_ImageScans_LogAndGet tests recording and filtering image scans
+func TestDB_ImageScans_LogAndGet(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	db.LogImageScan(watchertypes.ImageScan{Event: "ctf2025", ChallengeName: "web", Image: "registry/web:1", Status: "passed"})
+	db.LogImageScan(watchertypes.ImageScan{Event: "ctf2025", ChallengeName: "web", Image: "registry/web:2", Command: "trivy image registry/web:2",
+		Status: "findings", Blocked: true, ExitCode: 1, Output: "CRITICAL: 1", Duration: 1500})
+	db.LogImageScan(watchertypes.ImageScan{Event: "ctf2025", ChallengeName: "pwn", Image: "registry/pwn:1", Status: "error", ExitCode: -1})
+	db.LogImageScan(watchertypes.ImageScan{Event: "other", ChallengeName: "web", Image: "registry/web:1", Status: "passed"})
+
+	last, err := db.GetImageScans("ctf2025", "web", 1)
+	if err != nil {
+		t.Fatalf("GetImageScans() failed: %v", err)
+	}
+	want := watchertypes.ImageScan{Event: "ctf2025", ChallengeName: "web", Image: "registry/web:2", Command: "trivy image registry/web:2",
+		Status: "findings", Blocked: true, ExitCode: 1, Output: "CRITICAL: 1", Duration: 1500}
+	if len(last) != 1 {
+		t.Fatalf("len(last) = %d, want 1", len(last))
+	}
+	last[0].ID, last[0].Timestamp = 0, time.Time{}
+	if last[0] != want {
+		t.Errorf("last scan = %+v, want %+v", last[0], want)
+	}
+
+	event, err := db.GetImageScans("ctf2025", "", 10)
+	if err != nil {
+		t.Fatalf("GetImageScans() without challenge failed: %v", err)
+	}
+	if len(event) != 3 || event[0].ChallengeName != "pwn" || event[0].ExitCode != -1 {
+		t.Errorf("event scans = %+v, want 3 scans, pwn first", event)
+	}
+}
+
+// TestDB_SolverRuns_LogAndGet tests recording and filtering solver runs
+func TestDB_SolverRuns_LogAndGet(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	db.LogSolverRun(watchertypes.SolverRun{Event: "ctf2025", ChallengeName: "web", Target: "ctf.example.com:8080", Status: "passed"})
+	db.LogSolverRun(watchertypes.SolverRun{Event: "ctf2025", ChallengeName: "web", Target: "ctf.example.com:8080", Status: "failed",
+		ExitCode: 1, Error: "solver exited with status 1", Output: "Traceback", Duration: 2500})
+	db.LogSolverRun(watchertypes.SolverRun{Event: "other", ChallengeName: "web", Status: "passed"})
+
+	last, err := db.GetSolverRuns("ctf2025", "web", 1)
+	if err != nil {
+		t.Fatalf("GetSolverRuns() failed: %v", err)
+	}
+	want := watchertypes.SolverRun{Event: "ctf2025", ChallengeName: "web", Target: "ctf.example.com:8080", Status: "failed",
+		ExitCode: 1, Error: "solver exited with status 1", Output: "Traceback", Duration: 2500}
+	if len(last) != 1 {
+		t.Fatalf("len(last) = %d, want 1", len(last))
+	}
+	last[0].ID, last[0].Timestamp = 0, time.Time{}
+	if last[0] != want {
+		t.Errorf("last run = %+v, want %+v", last[0], want)
+	}
+
+	all, err := db.GetSolverRuns("", "web", 10)
+	if err != nil {
+		t.Fatalf("GetSolverRuns() without event failed: %v", err)
+	}
+	if len(all) != 3 || all[0].Event != "other" {
+		t.Errorf("runs = %+v, want 3 runs, other first", all)
+	}
+}
+
+func TestDB_DryRunSyncs_MigratesOldTable(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	old, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(`
+		CREATE TABLE dry_run_syncs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			action TEXT NOT NULL,
+			challenge_id INTEGER,
+			diff TEXT
+		);
+		INSERT INTO dry_run_syncs (event, challenge_name, action) VALUES ('ctf2025', 'Old', 'update');
+	`); err != nil {
+		t.Fatal(err)
+	}
+	_ = old.Close()
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() on old schema failed: %v", err)
+	}
+
+	db.LogDryRunSync("ctf2025", "New", "update", "attachment", 1, "")
+	syncs, err := db.GetDryRunSyncs("ctf2025", 10)
+	if err != nil {
+		t.Fatalf("GetDryRunSyncs() failed: %v", err)
+	}
+	if len(syncs) != 2 || syncs[0].UpdateType != "attachment" || syncs[1].UpdateType != "" {
+		t.Errorf("syncs = %+v, want new row with update type and old row without", syncs)
+	}
+}
+
+func TestDB_ChallengeStates_RecreatesOldTable(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	old, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(`
+		CREATE TABLE challenge_states (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			challenge_name TEXT UNIQUE NOT NULL,
+			status TEXT NOT NULL,
+			last_update DATETIME DEFAULT CURRENT_TIMESTAMP,
+			error_message TEXT,
+			script_states TEXT
+		);
+		INSERT INTO challenge_states (challenge_name, status) VALUES ('web/chal', 'watching');
+	`); err != nil {
+		t.Fatal(err)
+	}
+	_ = old.Close()
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() on old schema failed: %v", err)
+	}
+
+	db.UpdateChallengeState("ctf2025", "web/chal", "error", "sync failed", nil)
+	db.UpdateChallengeState("other", "web/chal", "watching", "", nil)
+	if state, err := db.GetChallengeState("ctf2025", "web/chal"); err != nil || state == nil || state.Status != "error" {
+		t.Errorf("GetChallengeState() = %+v, %v; want the error state of ctf2025", state, err)
+	}
+}
+
+func TestDB_Deployments_RenamesVersionColumn(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	old, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(`
+		CREATE TABLE deployments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			deployed_at INTEGER NOT NULL,
+			event TEXT NOT NULL,
+			challenge_name TEXT NOT NULL,
+			category TEXT,
+			challenge_id INTEGER NOT NULL,
+			version INTEGER NOT NULL,
+			yaml_hash TEXT,
+			dist_hash TEXT,
+			image TEXT,
+			image_digest TEXT,
+			source TEXT NOT NULL
+		);
+		INSERT INTO deployments (deployed_at, event, challenge_name, challenge_id, version, yaml_hash, source)
+		VALUES (0, 'ctf2025', 'web', 1, 3, 'y1', 'sync');
+	`); err != nil {
+		t.Fatal(err)
+	}
+	_ = old.Close()
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() on old schema failed: %v", err)
+	}
+
+	dep, err := db.RecordDeployment(watchertypes.Deployment{Event: "ctf2025", Challenge: "web", ChallengeID: 1, YamlHash: "y2"})
+	if err != nil {
+		t.Fatalf("RecordDeployment() failed: %v", err)
+	}
+	if dep.Revision != 4 {
+		t.Errorf("revision = %d, want 4 after the migrated revision 3", dep.Revision)
+	}
+}
+
+func TestDB_SyncActivity_LogAndGet(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	now := time.Now()
+	db.LogSyncActivity("ctf2025", "", "git_pull", "success", now.Add(-3*time.Hour), now.Add(-3*time.Hour+time.Second), "")
+	db.LogSyncActivity("ctf2025", "Web Challenge", "sync", "failed", now.Add(-time.Hour), now.Add(-time.Hour+2*time.Second), "upload failed")
+	db.LogSyncActivity("ctf2025", "Pwn Challenge", "sync", "success", now.Add(-2*time.Hour), now.Add(-2*time.Hour+time.Second), "")
+	db.LogSyncActivity("other", "Misc Challenge", "sync", "success", now.Add(-time.Minute), now, "")
+
+	activity, err := db.GetSyncActivity("ctf2025", now.Add(-150*time.Minute))
+	if err != nil {
+		t.Fatalf("GetSyncActivity() failed: %v", err)
+	}
+	if len(activity) != 2 {
+		t.Fatalf("len(activity) = %d, want 2", len(activity))
+	}
+
+	// Oldest first
+	if activity[0].Challenge != "Pwn Challenge" || activity[1].Error != "upload failed" {
+		t.Errorf("unexpected activity order: %+v", activity)
+	}
+	if got := activity[1].EndedAt.Sub(activity[1].StartedAt); got != 2*time.Second {
+		t.Errorf("duration = %v, want 2s", got)
+	}
+
+	all, err := db.GetSyncActivity("", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetSyncActivity() without event failed: %v", err)
+	}
+	if len(all) != 4 || all[0].Kind != "git_pull" || all[0].Challenge != "" {
+		t.Errorf("unexpected activity: %+v", all)
+	}
+}
+
+func TestDB_ChallengeStatusQueries(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if state, err := db.GetChallengeState("ctf2025", "web/chal"); err != nil || state != nil {
+		t.Fatalf("GetChallengeState() before any update = %+v, %v; want nil, nil", state, err)
+	}
+	db.UpdateChallengeState("ctf2025", "web/chal", "error", "sync failed", map[string][]string{"web/chal": {"checker"}})
+	db.UpdateChallengeState("other", "web/chal", "watching", "", nil)
+	state, err := db.GetChallengeState("ctf2025", "web/chal")
+	if err != nil || state == nil {
+		t.Fatalf("GetChallengeState() = %+v, %v", state, err)
+	}
+	if state.Event != "ctf2025" || state.Status != "error" || state.ErrorMessage != "sync failed" || state.ScriptStates != `["checker"]` {
+		t.Errorf("unexpected state: %+v", state)
+	}
+	if state, err := db.GetChallengeState("other", "web/chal"); err != nil || state == nil || state.Status != "watching" {
+		t.Errorf("GetChallengeState() of another event = %+v, %v", state, err)
+	}
+
+	now := time.Now()
+	db.LogSyncActivity("ctf2025", "web/chal", "sync", "failed", now.Add(-2*time.Hour), now.Add(-2*time.Hour), "upload failed")
+	db.LogSyncActivity("ctf2025", "web/chal", "sync", "success", now.Add(-time.Hour), now.Add(-time.Hour), "")
+	db.LogSyncActivity("ctf2025", "", "git_pull", "success", now, now, "")
+	db.LogSyncActivity("other", "web/chal", "sync", "failed", now, now, "other event")
+
+	last, err := db.GetLastSyncActivity("ctf2025", "web/chal", "")
+	if err != nil || last == nil {
+		t.Fatalf("GetLastSyncActivity() = %+v, %v", last, err)
+	}
+	if last.Status != "success" {
+		t.Errorf("last sync status = %s, want success", last.Status)
+	}
+
+	failed, err := db.GetLastSyncActivity("ctf2025", "web/chal", "failed")
+	if err != nil || failed == nil {
+		t.Fatalf("GetLastSyncActivity(failed) = %+v, %v", failed, err)
+	}
+	if failed.Error != "upload failed" {
+		t.Errorf("last failed sync error = %q, want %q", failed.Error, "upload failed")
+	}
+
+	if none, err := db.GetLastSyncActivity("ctf2025", "pwn/chal", ""); err != nil || none != nil {
+		t.Errorf("GetLastSyncActivity() of unsynced challenge = %+v, %v; want nil, nil", none, err)
+	}
+}
+
+func TestDB_GetSyncStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	now := time.Now()
+	db.LogSyncActivity("ctf2025", "web/flaky", "sync", "failed", now.Add(-3*time.Hour), now.Add(-3*time.Hour+time.Second), "build failed")
+	db.LogSyncActivity("ctf2025", "web/flaky", "sync", "success", now.Add(-2*time.Hour), now.Add(-2*time.Hour+3*time.Second), "")
+	db.LogSyncActivity("ctf2025", "web/flaky", "sync", "failed", now.Add(-time.Hour), now.Add(-time.Hour+2*time.Second), "upload failed")
+	db.LogSyncActivity("ctf2025", "pwn/stable", "sync", "success", now, now.Add(4*time.Second), "")
+	db.LogSyncActivity("ctf2025", "", "git_pull", "failed", now, now, "pull failed")
+	db.LogSyncActivity("other", "web/flaky", "sync", "success", now, now, "")
+
+	stats, err := db.GetSyncStats("ctf2025", "")
+	if err != nil {
+		t.Fatalf("GetSyncStats() failed: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("len(stats) = %d, want 2: %+v", len(stats), stats)
+	}
+
+	stable, flaky := stats[0], stats[1]
+	if stable.Challenge != "pwn/stable" || stable.Syncs != 1 || stable.Failures != 0 || stable.AvgDurationMs != 4000 || stable.LastFailureAt != nil {
+		t.Errorf("unexpected stats of the stable challenge: %+v", stable)
+	}
+	if flaky.Challenge != "web/flaky" || flaky.Syncs != 3 || flaky.Failures != 2 || flaky.AvgDurationMs != 2000 {
+		t.Errorf("unexpected stats of the flaky challenge: %+v", flaky)
+	}
+	if flaky.FailureRate < 0.66 || flaky.FailureRate > 0.67 {
+		t.Errorf("failure rate = %v, want 2/3", flaky.FailureRate)
+	}
+	if flaky.LastFailure != "upload failed" || flaky.LastFailureAt == nil {
+		t.Errorf("last failure = %q at %v, want the latest one", flaky.LastFailure, flaky.LastFailureAt)
+	}
+
+	one, err := db.GetSyncStats("other", "web/flaky")
+	if err != nil || len(one) != 1 || one[0].Syncs != 1 || one[0].Failures != 0 {
+		t.Errorf("GetSyncStats(other, web/flaky) = %+v, %v", one, err)
+	}
+}
+
+func TestDB_SearchLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	db.LogToDatabase("ERROR", "sync", "Web Challenge", "", "Failed to sync challenge", "dial tcp 10.0.0.5:443: connection refused", 0)
+	db.LogToDatabase("INFO", "sync", "Pwn Challenge", "", "Sync completed", "", 0)
+	db.LogScriptExecution("Pwn Challenge", "deploy", "one-time", "docker compose up -d", "failed", 10, "", "Error: connection refused by daemon", 1)
+	db.LogScriptExecution("Pwn Challenge", "healthcheck", "interval", "curl localhost", "completed", 10, "ok", "", 0)
+
+	results, err := db.SearchLogs(QuoteSearchTerms("connection refused"), time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("SearchLogs() failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2: %+v", len(results), results)
+	}
+
+	sources := map[string]bool{}
+	for _, r := range results {
+		sources[r.Source] = true
+		if !strings.Contains(r.Snippet, SnippetMatchStart+"connection"+SnippetMatchEnd) {
+			t.Errorf("snippet %q does not mark the match", r.Snippet)
+		}
+		if r.Timestamp.IsZero() {
+			t.Errorf("result %+v has no timestamp", r)
+		}
+	}
+	if !sources["log"] || !sources["script"] {
+		t.Errorf("expected matches from logs and scripts, got %v", sources)
+	}
+
+	// Punctuation is searched literally when quoted
+	results, err = db.SearchLogs(QuoteSearchTerms("10.0.0.5:443"), time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("SearchLogs() with punctuation failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Challenge != "Web Challenge" {
+		t.Errorf("unexpected results for address search: %+v", results)
+	}
+
+	// Entries older than since are excluded
+	results, err = db.SearchLogs(QuoteSearchTerms("connection"), time.Now().Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("SearchLogs() with future since failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results after since, got %+v", results)
+	}
+
+	if _, err := db.SearchLogs(`"unterminated`, time.Time{}, 10); err == nil {
+		t.Error("expected error for invalid FTS5 syntax")
+	}
+}
+
+func TestDB_SearchLogs_BackfillsExistingRows(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	db.LogToDatabase("ERROR", "sync", "Web Challenge", "", "upstream timeout", "", 0)
+
+	// Simulate a database created before search indexes existed
+	raw := db.GetDB()
+	for _, stmt := range []string{
+		"DROP TRIGGER watcher_logs_fts_insert",
+		"DROP TRIGGER watcher_logs_fts_delete",
+		"DROP TABLE watcher_logs_fts",
+	} {
+		if _, err := raw.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	_ = db.Close()
+
+	db = New(dbPath, true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("re-Init() failed: %v", err)
+	}
+
+	results, err := db.SearchLogs(QuoteSearchTerms("timeout"), time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("SearchLogs() failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected backfilled log to be searchable, got %+v", results)
+	}
+}
+
+// TestDB_RecordDeployment tests deployment revisions and the latest/history views
+func TestDB_RecordDeployment(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	web := watchertypes.Deployment{Event: "ctf2025", Challenge: "web", Category: "Web", ChallengeID: 1,
+		YamlHash: "y1", Image: "registry/web@sha256:a", ImageDigest: "sha256:a", Source: watchertypes.DeploymentSourceSync}
+
+	steps := []struct {
+		name     string
+		change   func(*watchertypes.Deployment)
+		revision int
+	}{
+		{"first deployment", func(*watchertypes.Deployment) {}, 1},
+		{"same content", func(d *watchertypes.Deployment) { d.Source = watchertypes.DeploymentSourceWatcher }, 1},
+		{"new image digest", func(d *watchertypes.Deployment) { d.ImageDigest = "sha256:b" }, 2},
+	}
+	for _, step := range steps {
+		step.change(&web)
+		dep, err := db.RecordDeployment(web)
+		if err != nil {
+			t.Fatalf("%s: RecordDeployment() failed: %v", step.name, err)
+		}
+		if dep.Revision != step.revision || dep.ID == 0 {
+			t.Errorf("%s: revision = %d (id %d), want %d", step.name, dep.Revision, dep.ID, step.revision)
+		}
+	}
+	if _, err := db.RecordDeployment(watchertypes.Deployment{Event: "ctf2025", Challenge: "pwn", Category: "Pwn", ChallengeID: 2, YamlHash: "y2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := db.GetDeployments("ctf2025", "", true, 0)
+	if err != nil {
+		t.Fatalf("GetDeployments() failed: %v", err)
+	}
+	if len(latest) != 2 || latest[0].Challenge != "pwn" || latest[1].ImageDigest != "sha256:b" {
+		t.Errorf("latest deployments = %+v, want pwn then the last web deployment", latest)
+	}
+
+	history, err := db.GetDeployments("ctf2025", "web", false, 2)
+	if err != nil {
+		t.Fatalf("GetDeployments() failed: %v", err)
+	}
+	if len(history) != 2 || history[0].Revision != 2 || history[1].Revision != 1 {
+		t.Errorf("web history = %+v, want revisions 2 then 1", history)
+	}
+	if !history[0].Timestamp.After(time.Time{}) {
+		t.Error("expected deployment timestamps to be stored")
+	}
+}
+
+func TestDB_PausedSyncs(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	for _, pause := range []watchertypes.PausedSync{
+		{Event: "ctf2025", Challenge: "web/login", Reason: "broken"},
+		{Event: "ctf2025", Challenge: "web/login", Reason: "rewriting"},
+		{Event: "ctf2025"},
+		{Event: "other", Challenge: "pwn/heap"},
+	} {
+		if err := db.PauseSync(pause); err != nil {
+			t.Fatalf("PauseSync(%+v) failed: %v", pause, err)
+		}
+	}
+
+	paused, err := db.GetPausedSyncs("ctf2025")
+	if err != nil {
+		t.Fatalf("GetPausedSyncs() failed: %v", err)
+	}
+	if len(paused) != 2 || paused[0].Challenge != "" || paused[1].Reason != "rewriting" || paused[1].PausedAt.IsZero() {
+		t.Errorf("paused syncs = %+v, want the event then web/login paused for rewriting", paused)
+	}
+
+	if resumed, err := db.ResumeSync("ctf2025", ""); err != nil || !resumed {
+		t.Errorf("ResumeSync() = %v, %v, want true", resumed, err)
+	}
+	if resumed, err := db.ResumeSync("ctf2025", ""); err != nil || resumed {
+		t.Errorf("ResumeSync() of a resumed event = %v, %v, want false", resumed, err)
+	}
+	if all, err := db.GetPausedSyncs(""); err != nil || len(all) != 2 {
+		t.Errorf("GetPausedSyncs(\"\") = %+v, %v, want 2 paused challenges", all, err)
+	}
+}
+
+func TestDB_GetLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	db.LogToDatabase("INFO", "watcher", "web/login", "", "synced", "", 0)
+	db.LogToDatabase("WARN", "watcher", "pwn/heap", "", "slow sync", "", 0)
+	db.LogToDatabase("ERROR", "watcher", "web/login", "start", "script failed", "exit status 1", 0)
+	db.LogToDatabase("INFO", "git", "", "", "pulled", "", 0)
+
+	logs, err := db.GetLogs(watchertypes.LogFilter{Limit: 3})
+	if err != nil {
+		t.Fatalf("GetLogs() failed: %v", err)
+	}
+	if len(logs) != 3 || logs[0].Message != "slow sync" || logs[2].Message != "pulled" {
+		t.Errorf("GetLogs() = %+v, want the latest 3 entries oldest first", logs)
+	}
+
+	logs, err = db.GetLogs(watchertypes.LogFilter{Level: "warning"})
+	if err != nil {
+		t.Fatalf("GetLogs(warning) failed: %v", err)
+	}
+	if len(logs) != 2 || logs[0].Level != "WARN" || logs[1].Level != "ERROR" {
+		t.Errorf("GetLogs(warning) = %+v, want the WARN and ERROR entries", logs)
+	}
+
+	logs, err = db.GetLogs(watchertypes.LogFilter{Challenge: "web/login", AfterID: logs[0].ID})
+	if err != nil {
+		t.Fatalf("GetLogs(after) failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Script != "start" || logs[0].Error != "exit status 1" {
+		t.Errorf("GetLogs(after) = %+v, want the web/login error only", logs)
+	}
+
+	if _, err := db.GetLogs(watchertypes.LogFilter{Level: "verbose"}); err == nil {
+		t.Error("GetLogs() with an invalid level succeeded, want an error")
+	}
+}
+
+func TestDB_TargetMapping(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	if err := db.SetTargetMapping("ctf2025", "staging", "web/login", 7, 2, "Login"); err != nil {
+		t.Fatalf("SetTargetMapping() failed: %v", err)
+	}
+	if err := db.SetTargetMapping("ctf2025", "staging", "web/login", 9, 3, "Login"); err != nil {
+		t.Fatalf("SetTargetMapping() update failed: %v", err)
+	}
+	if err := db.SetTargetMapping("ctf2025", "prod", "web/login", 1, 1, "Login"); err != nil {
+		t.Fatalf("SetTargetMapping() failed: %v", err)
+	}
+
+	mapping, err := db.GetTargetMapping("ctf2025", "staging", "web/login")
+	if err != nil {
+		t.Fatalf("GetTargetMapping() failed: %v", err)
+	}
+	if mapping == nil || mapping.Target != "staging" || mapping.ChallengeID != 9 || mapping.GameID != 3 {
+		t.Errorf("GetTargetMapping() = %+v, want challenge 9 of game 3 on staging", mapping)
+	}
+	if mapping, err := db.GetChallengeMapping("ctf2025", "web/login"); err != nil || mapping != nil {
+		t.Errorf("GetChallengeMapping() = %+v, %v, want target mappings kept apart", mapping, err)
+	}
+
+	if err := db.DeleteTargetMapping("ctf2025", "staging", "web/login"); err != nil {
+		t.Fatalf("DeleteTargetMapping() failed: %v", err)
+	}
+	if mapping, err := db.GetTargetMapping("ctf2025", "staging", "web/login"); err != nil || mapping != nil {
+		t.Errorf("GetTargetMapping() after delete = %+v, %v, want nil", mapping, err)
+	}
+	if mapping, err := db.GetTargetMapping("ctf2025", "prod", "web/login"); err != nil || mapping == nil || mapping.ChallengeID != 1 {
+		t.Errorf("GetTargetMapping() of prod = %+v, %v, want challenge 1", mapping, err)
+	}
+}
+
+func TestDB_ScheduledNotices(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db := New(dbPath, true)
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	now := time.Now()
+	hint, err := db.ScheduleNotice(watchertypes.ScheduledNotice{Event: "ctf2025", Content: "Hint 1", PostAt: now.Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("ScheduleNotice() failed: %v", err)
+	}
+	later, _ := db.ScheduleNotice(watchertypes.ScheduledNotice{Event: "ctf2025", Content: "Wave 2", PostAt: now.Add(time.Hour)})
+	_, _ = db.ScheduleNotice(watchertypes.ScheduledNotice{Event: "other", Content: "Other", PostAt: now.Add(-time.Minute)})
+
+	due, err := db.DueNotices("ctf2025", now)
+	if err != nil {
+		t.Fatalf("DueNotices() failed: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != hint.ID || due[0].Content != "Hint 1" {
+		t.Fatalf("due notices = %+v, want only Hint 1", due)
+	}
+
+	if err := db.MarkNoticeFailed(hint.ID, "server down", false); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := db.DueNotices("ctf2025", now); len(due) != 1 || due[0].Error != "server down" || due[0].Attempts != 1 {
+		t.Errorf("failed notice = %+v, want it still due with its error", due)
+	}
+	if err := db.MarkNoticeSent(hint.ID, 42, now); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := db.DueNotices("ctf2025", now); len(due) != 0 {
+		t.Errorf("due notices after sending = %+v, want none", due)
+	}
+
+	all, err := db.GetScheduledNotices("ctf2025", false)
+	if err != nil {
+		t.Fatalf("GetScheduledNotices() failed: %v", err)
+	}
+	if len(all) != 2 || all[0].SentAt == nil || all[0].NoticeID != 42 || all[0].Error != "" || all[1].ID != later.ID {
+		t.Errorf("scheduled notices = %+v, want the sent hint then Wave 2", all)
+	}
+
+	if ok, err := db.CancelScheduledNotice("ctf2025", hint.ID); err != nil || ok {
+		t.Errorf("CancelScheduledNotice() of a sent notice = %v, %v; want false", ok, err)
+	}
+	if ok, err := db.CancelScheduledNotice("ctf2025", later.ID); err != nil || !ok {
+		t.Errorf("CancelScheduledNotice() = %v, %v; want true", ok, err)
+	}
+	if pending, _ := db.GetScheduledNotices("ctf2025", true); len(pending) != 0 {
+		t.Errorf("pending notices = %+v, want none", pending)
+	}
+
+	rejected, _ := db.ScheduleNotice(watchertypes.ScheduledNotice{Event: "ctf2025", Content: "Bad", PostAt: now.Add(-time.Minute)})
+	if err := db.MarkNoticeFailed(rejected.ID, "invalid notice", true); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := db.DueNotices("ctf2025", now); len(due) != 0 {
+		t.Errorf("due notices = %+v, want none after giving up", due)
+	}
+	if all, _ := db.GetScheduledNotices("ctf2025", false); len(all) != 2 || all[1].FailedAt == nil {
+		t.Errorf("scheduled notices = %+v, want the notice given up on", all)
+	}
+}
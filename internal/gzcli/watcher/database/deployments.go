@@ -0,0 +1,136 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// RecordDeployment stores the provenance of a sync. The revision of the
+// deployment is taken from the previous one of the same GZCTF challenge and
+// increased when its content differs. The stored deployment is returned.
+func (d *DB) RecordDeployment(dep watchertypes.Deployment) (watchertypes.Deployment, error) {
+	db := d.GetDB()
+	if db == nil {
+		return dep, fmt.Errorf("database not initialized")
+	}
+	if dep.Timestamp.IsZero() {
+		dep.Timestamp = time.Now().UTC()
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return dep, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	previous, err := scanDeployment(tx.QueryRow(d.rebind(`
+		SELECT `+deploymentColumns+`
+		FROM deployments
+		WHERE event = ? AND challenge_id = ?
+		ORDER BY id DESC LIMIT 1
+	`), dep.Event, dep.ChallengeID))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		dep.Revision = 1
+	case err != nil:
+		return dep, err
+	case previous.SameContent(dep):
+		dep.Revision = previous.Revision
+	default:
+		dep.Revision = previous.Revision + 1
+	}
+
+	dep.ID, err = d.backend.InsertID(tx, `
+		INSERT INTO deployments (deployed_at, event, challenge_name, category, challenge_id, revision,
+			yaml_hash, dist_hash, image, image_digest, source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, dep.Timestamp.UnixMilli(), dep.Event, dep.Challenge, dep.Category, dep.ChallengeID, dep.Revision,
+		dep.YamlHash, dep.DistHash, dep.Image, dep.ImageDigest, dep.Source)
+	if err != nil {
+		return dep, err
+	}
+	return dep, tx.Commit()
+}
+
+// GetDeployments retrieves recorded deployments, optionally filtered by event
+// and challenge title. With latest, only the most recent deployment of each
+// GZCTF challenge is returned, ordered by event, category and title;
+// otherwise deployments are returned newest first. A limit of 0 or less
+// returns them all.
+func (d *DB) GetDeployments(event, challengeName string, latest bool, limit int) ([]watchertypes.Deployment, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `SELECT ` + deploymentColumns + ` FROM deployments`
+	var conditions []string
+	var args []interface{}
+	if event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, event)
+	}
+	if challengeName != "" {
+		conditions = append(conditions, "challenge_name = ?")
+		args = append(args, challengeName)
+	}
+	if latest {
+		conditions = append(conditions, "id IN (SELECT MAX(id) FROM deployments GROUP BY event, challenge_id)")
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if latest {
+		query += " ORDER BY event, category, challenge_name"
+	} else {
+		query += " ORDER BY id DESC"
+	}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	deployments := []watchertypes.Deployment{}
+	for rows.Next() {
+		dep, err := scanDeployment(rows)
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, dep)
+	}
+	return deployments, rows.Err()
+}
+
+const deploymentColumns = `id, deployed_at, event, challenge_name, category, challenge_id, revision,
+	yaml_hash, dist_hash, image, image_digest, source`
+
+// scanDeployment reads a row selected with deploymentColumns
+func scanDeployment(row interface{ Scan(dest ...any) error }) (watchertypes.Deployment, error) {
+	var dep watchertypes.Deployment
+	var deployedAt int64
+	var category, yamlHash, distHash, image, imageDigest sql.NullString
+	if err := row.Scan(&dep.ID, &deployedAt, &dep.Event, &dep.Challenge, &category, &dep.ChallengeID, &dep.Revision,
+		&yamlHash, &distHash, &image, &imageDigest, &dep.Source); err != nil {
+		return dep, err
+	}
+	dep.Timestamp = time.UnixMilli(deployedAt).UTC()
+	dep.Category = category.String
+	dep.YamlHash = yamlHash.String
+	dep.DistHash = distHash.String
+	dep.Image = image.String
+	dep.ImageDigest = imageDigest.String
+	return dep, nil
+}
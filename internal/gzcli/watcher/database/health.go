@@ -0,0 +1,34 @@
+package database
+
+import (
+	"fmt"
+)
+
+// Check connects to the database at source and verifies it without creating
+// or migrating tables. SQLite files also get a quick integrity check.
+func Check(source string) error {
+	backend := backendFor(source)
+	db, err := backend.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	if IsURL(source) {
+		return nil
+	}
+
+	var result string
+	if err := db.QueryRow(`PRAGMA quick_check`).Scan(&result); err != nil {
+		return fmt.Errorf("failed to check database integrity: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("database is corrupted: %s", result)
+	}
+	return nil
+}
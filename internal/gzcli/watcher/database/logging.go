@@ -3,6 +3,9 @@ package database
 import (
 	"encoding/json"
 	"fmt"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 )
 
 // LogToDatabase logs a message to the database
@@ -21,15 +24,15 @@ func (d *DB) LogToDatabase(level, component, challenge, script, message, errorMs
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := db.Exec(query, level, component, challenge, script, message, errorMsg, duration)
+	_, err := db.Exec(d.rebind(query), level, component, challenge, script, message, errorMsg, duration)
 	if err != nil {
 		// Don't use log.Error here to avoid potential recursion
 		fmt.Printf("Failed to log to database: %v\n", err)
 	}
 }
 
-// UpdateChallengeState updates or inserts the state of a challenge in the database
-func (d *DB) UpdateChallengeState(challengeName, status, errorMessage string, activeScripts map[string][]string) {
+// UpdateChallengeState updates or inserts the state of a challenge of an event in the database
+func (d *DB) UpdateChallengeState(event, challengeName, status, errorMessage string, activeScripts map[string][]string) {
 	if !d.enabled {
 		return
 	}
@@ -43,11 +46,13 @@ func (d *DB) UpdateChallengeState(challengeName, status, errorMessage string, ac
 	scriptStatesJSON, _ := json.Marshal(activeScripts[challengeName])
 
 	query := `
-		INSERT OR REPLACE INTO challenge_states (challenge_name, status, last_update, error_message, script_states)
-		VALUES (?, ?, CURRENT_TIMESTAMP, ?, ?)
+		INSERT INTO challenge_states (event, challenge_name, status, last_update, error_message, script_states)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP, ?, ?)
+		ON CONFLICT(event, challenge_name) DO UPDATE SET status = excluded.status, last_update = CURRENT_TIMESTAMP,
+			error_message = excluded.error_message, script_states = excluded.script_states
 	`
 
-	_, err := db.Exec(query, challengeName, status, errorMessage, string(scriptStatesJSON))
+	_, err := db.Exec(d.rebind(query), event, challengeName, status, errorMessage, string(scriptStatesJSON))
 	if err != nil {
 		fmt.Printf("Failed to update challenge state: %v\n", err)
 	}
@@ -69,8 +74,98 @@ func (d *DB) LogScriptExecution(challengeName, scriptName, scriptType, command,
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := db.Exec(query, challengeName, scriptName, scriptType, command, status, duration, output, errorOutput, exitCode)
+	_, err := db.Exec(d.rebind(query), challengeName, scriptName, scriptType, command, status, duration, output, errorOutput, exitCode)
 	if err != nil {
 		fmt.Printf("Failed to log script execution: %v\n", err)
 	}
 }
+
+// LogDryRunSync records a sync that was computed but not applied in dry-run mode
+func (d *DB) LogDryRunSync(event, challengeName, action, updateType string, challengeID int, diff string) {
+	if !d.enabled {
+		return
+	}
+
+	db := d.GetDB()
+	if db == nil {
+		return
+	}
+
+	query := `
+		INSERT INTO dry_run_syncs (event, challenge_name, action, update_type, challenge_id, diff)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.Exec(d.rebind(query), event, challengeName, action, updateType, challengeID, diff)
+	if err != nil {
+		fmt.Printf("Failed to log dry-run sync: %v\n", err)
+	}
+}
+
+// LogImageScan records the scan of a challenge image run before a full redeploy
+func (d *DB) LogImageScan(scan watchertypes.ImageScan) {
+	if !d.enabled {
+		return
+	}
+
+	db := d.GetDB()
+	if db == nil {
+		return
+	}
+
+	query := `
+		INSERT INTO image_scans (event, challenge_name, image, command, status, blocked, exit_code, output, duration)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.Exec(d.rebind(query), scan.Event, scan.ChallengeName, scan.Image, scan.Command, scan.Status,
+		scan.Blocked, scan.ExitCode, scan.Output, scan.Duration)
+	if err != nil {
+		fmt.Printf("Failed to log image scan: %v\n", err)
+	}
+}
+
+// LogSolverRun records a solver verification run after a sync
+func (d *DB) LogSolverRun(run watchertypes.SolverRun) {
+	if !d.enabled {
+		return
+	}
+
+	db := d.GetDB()
+	if db == nil {
+		return
+	}
+
+	query := `
+		INSERT INTO solver_runs (event, challenge_name, target, status, exit_code, error, output, duration)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.Exec(d.rebind(query), run.Event, run.ChallengeName, run.Target, run.Status, run.ExitCode,
+		run.Error, run.Output, run.Duration)
+	if err != nil {
+		fmt.Printf("Failed to log solver run: %v\n", err)
+	}
+}
+
+// LogSyncActivity records a timed sync or git pull for the activity timeline
+func (d *DB) LogSyncActivity(event, challengeName, kind, status string, startedAt, endedAt time.Time, errorMsg string) {
+	if !d.enabled {
+		return
+	}
+
+	db := d.GetDB()
+	if db == nil {
+		return
+	}
+
+	query := `
+		INSERT INTO sync_activity (event, challenge_name, kind, status, started_at, ended_at, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.Exec(d.rebind(query), event, challengeName, kind, status, startedAt.UnixMilli(), endedAt.UnixMilli(), errorMsg)
+	if err != nil {
+		fmt.Printf("Failed to log sync activity: %v\n", err)
+	}
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// ScheduleNotice stores a notice for the watcher of its event to post at
+// n.PostAt. The stored notice is returned with its ID.
+func (d *DB) ScheduleNotice(n watchertypes.ScheduledNotice) (watchertypes.ScheduledNotice, error) {
+	db := d.GetDB()
+	if db == nil {
+		return n, fmt.Errorf("database not initialized")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return n, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	n.ID, err = d.backend.InsertID(tx, `
+		INSERT INTO scheduled_notices (event, content, post_at)
+		VALUES (?, ?, ?)
+	`, n.Event, n.Content, n.PostAt.UnixMilli())
+	if err != nil {
+		return n, err
+	}
+	n.PostAt = time.UnixMilli(n.PostAt.UnixMilli()).UTC()
+	return n, tx.Commit()
+}
+
+// GetScheduledNotices retrieves the scheduled notices of an event, or of all
+// events if event is empty, by posting time. With pending, notices already
+// posted or given up on are left out.
+func (d *DB) GetScheduledNotices(event string, pending bool) ([]watchertypes.ScheduledNotice, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `SELECT ` + noticeColumns + ` FROM scheduled_notices`
+	var conditions []string
+	var args []interface{}
+	if event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, event)
+	}
+	if pending {
+		conditions = append(conditions, "sent_at IS NULL AND failed_at IS NULL")
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY post_at, id"
+	return queryNotices(db, d.rebind(query), args...)
+}
+
+// DueNotices retrieves the notices of an event that were neither posted nor
+// given up on yet and whose posting time is not after now
+func (d *DB) DueNotices(event string, now time.Time) ([]watchertypes.ScheduledNotice, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	return queryNotices(db, d.rebind(`
+		SELECT `+noticeColumns+` FROM scheduled_notices
+		WHERE event = ? AND sent_at IS NULL AND failed_at IS NULL AND post_at <= ?
+		ORDER BY post_at, id
+	`), event, now.UnixMilli())
+}
+
+// MarkNoticeSent records that a scheduled notice was posted as the GZCTF
+// notice noticeID
+func (d *DB) MarkNoticeSent(id int64, noticeID int, sentAt time.Time) error {
+	db := d.GetDB()
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	_, err := db.Exec(d.rebind(`UPDATE scheduled_notices SET sent_at = ?, notice_id = ?, error = NULL WHERE id = ?`),
+		sentAt.UnixMilli(), noticeID, id)
+	return err
+}
+
+// MarkNoticeFailed records why posting a scheduled notice failed and counts
+// the attempt. The notice stays pending, to be retried, unless giveUp is set.
+func (d *DB) MarkNoticeFailed(id int64, reason string, giveUp bool) error {
+	db := d.GetDB()
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	var failedAt sql.NullInt64
+	if giveUp {
+		failedAt = sql.NullInt64{Int64: time.Now().UnixMilli(), Valid: true}
+	}
+	_, err := db.Exec(d.rebind(`UPDATE scheduled_notices SET error = ?, attempts = attempts + 1, failed_at = ? WHERE id = ?`),
+		reason, failedAt, id)
+	return err
+}
+
+// CancelScheduledNotice deletes a scheduled notice of an event that was not
+// posted yet, and reports whether there was one
+func (d *DB) CancelScheduledNotice(event string, id int64) (bool, error) {
+	db := d.GetDB()
+	if db == nil {
+		return false, fmt.Errorf("database not initialized")
+	}
+
+	result, err := db.Exec(d.rebind(`DELETE FROM scheduled_notices WHERE id = ? AND event = ? AND sent_at IS NULL`), id, event)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+const noticeColumns = `id, event, content, post_at, sent_at, notice_id, error, attempts, failed_at`
+
+// queryNotices runs a query selecting noticeColumns
+func queryNotices(db *sql.DB, query string, args ...any) ([]watchertypes.ScheduledNotice, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notices := []watchertypes.ScheduledNotice{}
+	for rows.Next() {
+		var n watchertypes.ScheduledNotice
+		var postAt int64
+		var sentAt, noticeID, failedAt sql.NullInt64
+		var reason sql.NullString
+		if err := rows.Scan(&n.ID, &n.Event, &n.Content, &postAt, &sentAt, &noticeID, &reason, &n.Attempts, &failedAt); err != nil {
+			return nil, err
+		}
+		n.PostAt = time.UnixMilli(postAt).UTC()
+		if sentAt.Valid {
+			t := time.UnixMilli(sentAt.Int64).UTC()
+			n.SentAt = &t
+		}
+		if failedAt.Valid {
+			t := time.UnixMilli(failedAt.Int64).UTC()
+			n.FailedAt = &t
+		}
+		n.NoticeID = int(noticeID.Int64)
+		n.Error = reason.String
+		notices = append(notices, n)
+	}
+	return notices, rows.Err()
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// PauseSync stores a paused challenge, or a whole event when the challenge is
+// empty. Pausing again replaces the reason and time.
+func (d *DB) PauseSync(pause watchertypes.PausedSync) error {
+	if !d.enabled {
+		return nil
+	}
+	db := d.GetDB()
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if pause.PausedAt.IsZero() {
+		pause.PausedAt = time.Now().UTC()
+	}
+
+	query := `
+		INSERT INTO paused_syncs (event, challenge_name, reason, paused_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(event, challenge_name) DO UPDATE SET reason = excluded.reason, paused_at = excluded.paused_at
+	`
+	if _, err := db.Exec(d.rebind(query), pause.Event, pause.Challenge, pause.Reason, pause.PausedAt.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store paused sync: %w", err)
+	}
+	return nil
+}
+
+// ResumeSync removes a paused challenge, or the pause of the whole event when
+// the challenge is empty, and reports whether it was paused
+func (d *DB) ResumeSync(event, challengeName string) (bool, error) {
+	if !d.enabled {
+		return false, nil
+	}
+	db := d.GetDB()
+	if db == nil {
+		return false, fmt.Errorf("database not initialized")
+	}
+
+	result, err := db.Exec(d.rebind(`DELETE FROM paused_syncs WHERE event = ? AND challenge_name = ?`), event, challengeName)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete paused sync: %w", err)
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// GetPausedSyncs returns the paused challenges and events, optionally
+// filtered by event, ordered by event and challenge
+func (d *DB) GetPausedSyncs(event string) ([]watchertypes.PausedSync, error) {
+	if !d.enabled {
+		return []watchertypes.PausedSync{}, nil
+	}
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `SELECT event, challenge_name, reason, paused_at FROM paused_syncs`
+	var args []interface{}
+	if event != "" {
+		query += ` WHERE event = ?`
+		args = append(args, event)
+	}
+	query += ` ORDER BY event, challenge_name`
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query paused syncs: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	paused := []watchertypes.PausedSync{}
+	for rows.Next() {
+		var (
+			pause    watchertypes.PausedSync
+			reason   *string
+			pausedAt int64
+		)
+		if err := rows.Scan(&pause.Event, &pause.Challenge, &reason, &pausedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan paused sync: %w", err)
+		}
+		if reason != nil {
+			pause.Reason = *reason
+		}
+		pause.PausedAt = time.UnixMilli(pausedAt).UTC()
+		paused = append(paused, pause)
+	}
+	return paused, rows.Err()
+}
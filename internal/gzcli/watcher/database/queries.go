@@ -3,6 +3,9 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 )
@@ -21,7 +24,7 @@ func (d *DB) GetRecentLogs(limit int) ([]watchertypes.WatcherLog, error) {
 		LIMIT ?
 	`
 
-	rows, err := db.Query(query, limit)
+	rows, err := db.Query(d.rebind(query), limit)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +57,101 @@ func (d *DB) GetRecentLogs(limit int) ([]watchertypes.WatcherLog, error) {
 	return logs, rows.Err()
 }
 
+// GetLogs retrieves the log entries selected by filter, oldest first: those
+// written after filter.AfterID, or the latest ones when it is 0. Levels below
+// filter.Level are skipped.
+func (d *DB) GetLogs(filter watchertypes.LogFilter) ([]watchertypes.WatcherLog, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	var where []string
+	var args []any
+	if filter.AfterID > 0 {
+		where = append(where, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+	if filter.Level != "" {
+		levels, err := levelsFrom(filter.Level)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, "level IN (?"+strings.Repeat(", ?", len(levels)-1)+")")
+		for _, level := range levels {
+			args = append(args, level)
+		}
+	}
+	if filter.Challenge != "" {
+		where = append(where, "challenge = ?")
+		args = append(args, filter.Challenge)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT id, timestamp, level, component, challenge, script, message, error, duration FROM watcher_logs`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	// Without a cursor, the latest entries are wanted, then put back in order
+	if filter.AfterID > 0 {
+		query += " ORDER BY id ASC LIMIT ?"
+	} else {
+		query += " ORDER BY id DESC LIMIT ?"
+	}
+	args = append(args, limit)
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var logs []watchertypes.WatcherLog
+	for rows.Next() {
+		var log watchertypes.WatcherLog
+		var challenge, script, errorMsg sql.NullString
+		var duration sql.NullInt64
+
+		err := rows.Scan(
+			&log.ID, &log.Timestamp, &log.Level, &log.Component,
+			&challenge, &script, &log.Message, &errorMsg, &duration,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Challenge = challenge.String
+		log.Script = script.String
+		log.Error = errorMsg.String
+		log.Duration = duration.Int64
+
+		logs = append(logs, log)
+	}
+	if filter.AfterID <= 0 {
+		slices.Reverse(logs)
+	}
+
+	return logs, rows.Err()
+}
+
+// levelsFrom returns the log levels at least as severe as level
+func levelsFrom(level string) ([]string, error) {
+	level = strings.ToUpper(level)
+	if level == "WARNING" {
+		level = "WARN"
+	}
+	i := slices.Index(watchertypes.LogLevels, level)
+	if i < 0 {
+		return nil, fmt.Errorf("invalid log level %q: must be one of %s", level, strings.Join(watchertypes.LogLevels, ", "))
+	}
+	return watchertypes.LogLevels[i:], nil
+}
+
 // GetScriptExecutions retrieves script execution records from the database
 func (d *DB) GetScriptExecutions(challengeName string, limit int) ([]watchertypes.ScriptExecution, error) {
 	db := d.GetDB()
@@ -83,7 +181,7 @@ func (d *DB) GetScriptExecutions(challengeName string, limit int) ([]watchertype
 		args = []interface{}{limit}
 	}
 
-	rows, err := db.Query(query, args...)
+	rows, err := db.Query(d.rebind(query), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -121,3 +219,343 @@ func (d *DB) GetScriptExecutions(challengeName string, limit int) ([]watchertype
 
 	return executions, rows.Err()
 }
+
+// GetDryRunSyncs retrieves dry-run sync records, optionally filtered by event
+func (d *DB) GetDryRunSyncs(event string, limit int) ([]watchertypes.DryRunSync, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT id, timestamp, event, challenge_name, action, update_type, challenge_id, diff
+		FROM dry_run_syncs
+	`
+	var args []interface{}
+	if event != "" {
+		query += " WHERE event = ?"
+		args = append(args, event)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var syncs []watchertypes.DryRunSync
+	for rows.Next() {
+		var s watchertypes.DryRunSync
+		var challengeID sql.NullInt64
+		var updateType, diff sql.NullString
+
+		if err := rows.Scan(&s.ID, &s.Timestamp, &s.Event, &s.ChallengeName, &s.Action, &updateType, &challengeID, &diff); err != nil {
+			return nil, err
+		}
+
+		s.UpdateType = updateType.String
+		s.ChallengeID = int(challengeID.Int64)
+		s.Diff = diff.String
+		syncs = append(syncs, s)
+	}
+
+	return syncs, rows.Err()
+}
+
+// GetImageScans retrieves image scans, newest first, optionally filtered by
+// event and challenge
+func (d *DB) GetImageScans(event, challengeName string, limit int) ([]watchertypes.ImageScan, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT id, timestamp, event, challenge_name, image, command, status, blocked, exit_code, output, duration
+		FROM image_scans
+	`
+	var conditions []string
+	var args []interface{}
+	if event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, event)
+	}
+	if challengeName != "" {
+		conditions = append(conditions, "challenge_name = ?")
+		args = append(args, challengeName)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var scans []watchertypes.ImageScan
+	for rows.Next() {
+		var s watchertypes.ImageScan
+		var command, output sql.NullString
+		var exitCode, duration sql.NullInt64
+
+		if err := rows.Scan(&s.ID, &s.Timestamp, &s.Event, &s.ChallengeName, &s.Image, &command, &s.Status,
+			&s.Blocked, &exitCode, &output, &duration); err != nil {
+			return nil, err
+		}
+
+		s.Command = command.String
+		s.ExitCode = int(exitCode.Int64)
+		s.Output = output.String
+		s.Duration = duration.Int64
+		scans = append(scans, s)
+	}
+
+	return scans, rows.Err()
+}
+
+// GetSolverRuns retrieves solver verification runs, newest first, optionally
+// filtered by event and challenge
+func (d *DB) GetSolverRuns(event, challengeName string, limit int) ([]watchertypes.SolverRun, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT id, timestamp, event, challenge_name, target, status, exit_code, error, output, duration
+		FROM solver_runs
+	`
+	var conditions []string
+	var args []interface{}
+	if event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, event)
+	}
+	if challengeName != "" {
+		conditions = append(conditions, "challenge_name = ?")
+		args = append(args, challengeName)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var runs []watchertypes.SolverRun
+	for rows.Next() {
+		var r watchertypes.SolverRun
+		var target, errorMsg, output sql.NullString
+		var exitCode, duration sql.NullInt64
+
+		if err := rows.Scan(&r.ID, &r.Timestamp, &r.Event, &r.ChallengeName, &target, &r.Status,
+			&exitCode, &errorMsg, &output, &duration); err != nil {
+			return nil, err
+		}
+
+		r.Target = target.String
+		r.ExitCode = int(exitCode.Int64)
+		r.Error = errorMsg.String
+		r.Output = output.String
+		r.Duration = duration.Int64
+		runs = append(runs, r)
+	}
+
+	return runs, rows.Err()
+}
+
+// GetSyncActivity retrieves sync and git pull activity that ended at or after
+// since, oldest first, optionally filtered by event
+func (d *DB) GetSyncActivity(event string, since time.Time) ([]watchertypes.SyncActivity, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT id, event, challenge_name, kind, status, started_at, ended_at, error
+		FROM sync_activity
+		WHERE ended_at >= ?
+	`
+	args := []interface{}{since.UnixMilli()}
+	if event != "" {
+		query += " AND event = ?"
+		args = append(args, event)
+	}
+	query += " ORDER BY started_at ASC"
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var activity []watchertypes.SyncActivity
+	for rows.Next() {
+		var a watchertypes.SyncActivity
+		var challenge, errorMsg sql.NullString
+		var startedAt, endedAt int64
+
+		if err := rows.Scan(&a.ID, &a.Event, &challenge, &a.Kind, &a.Status, &startedAt, &endedAt, &errorMsg); err != nil {
+			return nil, err
+		}
+
+		a.Challenge = challenge.String
+		a.Error = errorMsg.String
+		a.StartedAt = time.UnixMilli(startedAt)
+		a.EndedAt = time.UnixMilli(endedAt)
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
+}
+
+// GetChallengeState retrieves the stored state of a challenge of an event, or
+// nil if none was recorded
+func (d *DB) GetChallengeState(event, challengeName string) (*watchertypes.ChallengeState, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT id, event, challenge_name, status, last_update, error_message, script_states
+		FROM challenge_states
+		WHERE event = ? AND challenge_name = ?
+	`
+
+	var state watchertypes.ChallengeState
+	var errorMessage, scriptStates sql.NullString
+	err := db.QueryRow(d.rebind(query), event, challengeName).Scan(&state.ID, &state.Event, &state.ChallengeName, &state.Status, &state.LastUpdate, &errorMessage, &scriptStates)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state.ErrorMessage = errorMessage.String
+	state.ScriptStates = scriptStates.String
+	return &state, nil
+}
+
+// GetLastSyncActivity retrieves the latest sync of a challenge in an event,
+// optionally only among syncs with the given status, or nil if there is none
+func (d *DB) GetLastSyncActivity(event, challengeName, status string) (*watchertypes.SyncActivity, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT id, event, challenge_name, kind, status, started_at, ended_at, error
+		FROM sync_activity
+		WHERE event = ? AND challenge_name = ? AND kind = 'sync'
+	`
+	args := []interface{}{event, challengeName}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY ended_at DESC, id DESC LIMIT 1"
+
+	var a watchertypes.SyncActivity
+	var challenge, errorMsg sql.NullString
+	var startedAt, endedAt int64
+	err := db.QueryRow(d.rebind(query), args...).Scan(&a.ID, &a.Event, &challenge, &a.Kind, &a.Status, &startedAt, &endedAt, &errorMsg)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.Challenge = challenge.String
+	a.Error = errorMsg.String
+	a.StartedAt = time.UnixMilli(startedAt)
+	a.EndedAt = time.UnixMilli(endedAt)
+	return &a, nil
+}
+
+// GetSyncStats aggregates the recorded syncs of each challenge, ordered by
+// event and challenge, optionally filtered by event and challenge
+func (d *DB) GetSyncStats(event, challengeName string) ([]watchertypes.SyncStats, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `
+		SELECT s.event, s.challenge_name, COUNT(*),
+			SUM(CASE WHEN s.status = 'failed' THEN 1 ELSE 0 END),
+			AVG(s.ended_at - s.started_at),
+			(SELECT f.error FROM sync_activity f
+				WHERE f.event = s.event AND f.challenge_name = s.challenge_name AND f.kind = 'sync' AND f.status = 'failed'
+				ORDER BY f.ended_at DESC, f.id DESC LIMIT 1),
+			(SELECT MAX(f.ended_at) FROM sync_activity f
+				WHERE f.event = s.event AND f.challenge_name = s.challenge_name AND f.kind = 'sync' AND f.status = 'failed')
+		FROM sync_activity s
+		WHERE s.kind = 'sync'
+	`
+	var args []interface{}
+	if event != "" {
+		query += " AND s.event = ?"
+		args = append(args, event)
+	}
+	if challengeName != "" {
+		query += " AND s.challenge_name = ?"
+		args = append(args, challengeName)
+	}
+	query += " GROUP BY s.event, s.challenge_name ORDER BY s.event, s.challenge_name"
+
+	rows, err := db.Query(d.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var stats []watchertypes.SyncStats
+	for rows.Next() {
+		var s watchertypes.SyncStats
+		var challenge, lastFailure sql.NullString
+		var avgDuration float64
+		var lastFailureAt sql.NullInt64
+
+		if err := rows.Scan(&s.Event, &challenge, &s.Syncs, &s.Failures, &avgDuration, &lastFailure, &lastFailureAt); err != nil {
+			return nil, err
+		}
+
+		s.Challenge = challenge.String
+		s.FailureRate = float64(s.Failures) / float64(s.Syncs)
+		s.AvgDurationMs = int64(avgDuration)
+		s.LastFailure = lastFailure.String
+		if lastFailureAt.Valid {
+			at := time.UnixMilli(lastFailureAt.Int64)
+			s.LastFailureAt = &at
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// Markers around matched terms in search snippets
+const (
+	SnippetMatchStart = "«"
+	SnippetMatchEnd   = "»"
+)
+
+// searchIndexes define FTS5 indexes over watcher logs and script output.
+// They are external-content tables kept in sync by triggers, so the text is
+// stored once in the source tables.
+var searchIndexes = []struct {
+	name   string
+	schema string
+}{
+	{
+		name: "watcher_logs_fts",
+		schema: `
+			CREATE VIRTUAL TABLE IF NOT EXISTS watcher_logs_fts USING fts5(
+				message, error, challenge, script,
+				content='watcher_logs', content_rowid='id'
+			);
+			CREATE TRIGGER IF NOT EXISTS watcher_logs_fts_insert AFTER INSERT ON watcher_logs BEGIN
+				INSERT INTO watcher_logs_fts(rowid, message, error, challenge, script)
+				VALUES (new.id, new.message, new.error, new.challenge, new.script);
+			END;
+			CREATE TRIGGER IF NOT EXISTS watcher_logs_fts_delete AFTER DELETE ON watcher_logs BEGIN
+				INSERT INTO watcher_logs_fts(watcher_logs_fts, rowid, message, error, challenge, script)
+				VALUES ('delete', old.id, old.message, old.error, old.challenge, old.script);
+			END;
+		`,
+	},
+	{
+		name: "script_executions_fts",
+		schema: `
+			CREATE VIRTUAL TABLE IF NOT EXISTS script_executions_fts USING fts5(
+				output, error_output, command, challenge_name, script_name,
+				content='script_executions', content_rowid='id'
+			);
+			CREATE TRIGGER IF NOT EXISTS script_executions_fts_insert AFTER INSERT ON script_executions BEGIN
+				INSERT INTO script_executions_fts(rowid, output, error_output, command, challenge_name, script_name)
+				VALUES (new.id, new.output, new.error_output, new.command, new.challenge_name, new.script_name);
+			END;
+			CREATE TRIGGER IF NOT EXISTS script_executions_fts_delete AFTER DELETE ON script_executions BEGIN
+				INSERT INTO script_executions_fts(script_executions_fts, rowid, output, error_output, command, challenge_name, script_name)
+				VALUES ('delete', old.id, old.output, old.error_output, old.command, old.challenge_name, old.script_name);
+			END;
+		`,
+	},
+}
+
+// createSearchIndexes creates the FTS5 indexes, backfilling rows written
+// before the index existed
+func createSearchIndexes(db *sql.DB) error {
+	for _, idx := range searchIndexes {
+		var existing int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, idx.name).Scan(&existing); err != nil {
+			return fmt.Errorf("failed to check %s: %w", idx.name, err)
+		}
+
+		if _, err := db.Exec(idx.schema); err != nil {
+			return fmt.Errorf("failed to create %s: %w", idx.name, err)
+		}
+
+		if existing == 0 {
+			//nolint:gosec // G201: Index names are constants
+			if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES ('rebuild')`, idx.name, idx.name)); err != nil {
+				return fmt.Errorf("failed to build %s: %w", idx.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// QuoteSearchTerms turns free text into an FTS5 query matching every word,
+// so punctuation in the input is searched literally instead of parsed as
+// query syntax
+func QuoteSearchTerms(text string) string {
+	fields := strings.Fields(text)
+	for i, f := range fields {
+		fields[i] = `"` + strings.ReplaceAll(f, `"`, `""`) + `"`
+	}
+	return strings.Join(fields, " ")
+}
+
+// sqliteSearchQuery searches the FTS5 indexes, taking the snippet markers,
+// query and start time of each index, then the limit
+const sqliteSearchQuery = `
+	SELECT 'log', l.id, l.timestamp, l.challenge, l.script, l.level,
+		snippet(watcher_logs_fts, -1, ?, ?, '…', 16), bm25(watcher_logs_fts)
+	FROM watcher_logs_fts
+	JOIN watcher_logs l ON l.id = watcher_logs_fts.rowid
+	WHERE watcher_logs_fts MATCH ? AND l.timestamp >= ?
+	UNION ALL
+	SELECT 'script', e.id, e.timestamp, e.challenge_name, e.script_name, e.status,
+		snippet(script_executions_fts, -1, ?, ?, '…', 16), bm25(script_executions_fts)
+	FROM script_executions_fts
+	JOIN script_executions e ON e.id = script_executions_fts.rowid
+	WHERE script_executions_fts MATCH ? AND e.timestamp >= ?
+	ORDER BY 3 DESC
+	LIMIT ?
+`
+
+// SearchLogs runs a full-text query over watcher logs and script output
+// written at or after since, newest first. Snippets mark matches with
+// SnippetMatchStart and SnippetMatchEnd.
+func (d *DB) SearchLogs(query string, since time.Time, limit int) ([]watchertypes.SearchResult, error) {
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	// CURRENT_TIMESTAMP stores UTC as "YYYY-MM-DD HH:MM:SS"
+	sinceStr := since.UTC().Format(time.DateTime)
+
+	rows, err := db.Query(d.rebind(d.backend.SearchQuery()),
+		SnippetMatchStart, SnippetMatchEnd, query, sinceStr,
+		SnippetMatchStart, SnippetMatchEnd, query, sinceStr,
+		limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			return nil, fmt.Errorf("invalid search query %q: %w", query, err)
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var results []watchertypes.SearchResult
+	for rows.Next() {
+		var r watchertypes.SearchResult
+		var challenge, script, status sql.NullString
+
+		if err := rows.Scan(&r.Source, &r.ID, &r.Timestamp, &challenge, &script, &status, &r.Snippet, &r.Rank); err != nil {
+			return nil, err
+		}
+
+		r.Challenge = challenge.String
+		r.Script = script.String
+		r.Status = status.String
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
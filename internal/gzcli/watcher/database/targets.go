@@ -0,0 +1,80 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GetTargetMapping retrieves the challenge a folder of an event is synced to
+// on a mirror target, or nil if it has none
+func (d *DB) GetTargetMapping(event, target, folderPath string) (*ChallengeMapping, error) {
+	if !d.enabled {
+		return nil, nil
+	}
+	db := d.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	query := `SELECT event, target, folder_path, challenge_id, game_id, challenge_title, last_synced
+	          FROM target_mappings
+	          WHERE event = ? AND target = ? AND folder_path = ?`
+
+	var mapping ChallengeMapping
+	err := db.QueryRow(d.rebind(query), event, target, folderPath).Scan(
+		&mapping.Event,
+		&mapping.Target,
+		&mapping.FolderPath,
+		&mapping.ChallengeID,
+		&mapping.GameID,
+		&mapping.ChallengeTitle,
+		&mapping.LastSynced,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query target mapping: %w", err)
+	}
+	return &mapping, nil
+}
+
+// SetTargetMapping stores or updates the challenge a folder of an event is
+// synced to on a mirror target
+func (d *DB) SetTargetMapping(event, target, folderPath string, challengeID, gameID int, challengeTitle string) error {
+	if !d.enabled {
+		return nil
+	}
+	db := d.GetDB()
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	query := `INSERT INTO target_mappings (event, target, folder_path, challenge_id, game_id, challenge_title, last_synced)
+	          VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	          ON CONFLICT(event, target, folder_path)
+	          DO UPDATE SET challenge_id = excluded.challenge_id, game_id = excluded.game_id,
+	                        challenge_title = excluded.challenge_title, last_synced = CURRENT_TIMESTAMP`
+	if _, err := db.Exec(d.rebind(query), event, target, folderPath, challengeID, gameID, challengeTitle); err != nil {
+		return fmt.Errorf("failed to set target mapping: %w", err)
+	}
+	return nil
+}
+
+// DeleteTargetMapping removes the mapping of a folder of an event on a
+// mirror target
+func (d *DB) DeleteTargetMapping(event, target, folderPath string) error {
+	if !d.enabled {
+		return nil
+	}
+	db := d.GetDB()
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	query := `DELETE FROM target_mappings WHERE event = ? AND target = ? AND folder_path = ?`
+	if _, err := db.Exec(d.rebind(query), event, target, folderPath); err != nil {
+		return fmt.Errorf("failed to delete target mapping: %w", err)
+	}
+	return nil
+}
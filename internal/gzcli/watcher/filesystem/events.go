@@ -2,12 +2,14 @@
 package filesystem
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
@@ -45,8 +47,7 @@ func CheckFileRemoval(path string, watchedDirs map[string]string) (bool, string,
 	}
 
 	// If a challenge.yml or challenge.yaml is removed, infer which challenge it belonged to by path prefix
-	base := filepath.Base(path)
-	if base == "challenge.yml" || base == "challenge.yaml" {
+	if registry.IsChallengeFile(filepath.Base(path)) {
 		// The parent directory represents the challenge cwd
 		dir := filepath.Dir(path)
 		return true, "", dir
@@ -66,9 +67,7 @@ func IsChallengeDirectoryRemoved(removedDir string) bool {
 	}
 
 	// Directory still exists, check if challenge files are missing
-	chalYml := filepath.Join(absRemoved, "challenge.yml")
-	chalYaml := filepath.Join(absRemoved, "challenge.yaml")
-	if os.IsNotExist(fileStat(chalYml)) && os.IsNotExist(fileStat(chalYaml)) {
+	if _, err := registry.FindChallengeFile(absRemoved); err != nil {
 		return true // Challenge files are gone
 	}
 
@@ -127,12 +126,6 @@ func IsInChallengeCategory(filePath string) bool {
 	return false
 }
 
-// fileStat returns error if path does not exist; helper to simplify logic
-func fileStat(path string) error {
-	_, err := os.Stat(path)
-	return err
-}
-
 // WatchLoop is the main event loop for file watching
 func WatchLoop(watcher *fsnotify.Watcher, config watchertypes.WatcherConfig, handler EventHandler, ctx <-chan struct{}) {
 	for {
@@ -162,6 +155,10 @@ func WatchLoop(watcher *fsnotify.Watcher, config watchertypes.WatcherConfig, han
 			if !ok {
 				return
 			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				log.Error("Watcher error: %v; changes were missed, raise fs.inotify.max_queued_events with sysctl or start the watcher with --poll", err)
+				continue
+			}
 			log.Error("Watcher error: %v", err)
 		}
 	}
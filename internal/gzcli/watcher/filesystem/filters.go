@@ -1,13 +1,18 @@
 package filesystem
 
 import (
+	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/dimasma0305/gzcli/internal/gzcli/challenge"
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzignore"
+	"github.com/dimasma0305/gzcli/internal/gzcli/registry"
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 	"github.com/dimasma0305/gzcli/internal/log"
 )
@@ -177,6 +182,16 @@ func DetermineUpdateType(filePath string, challengeCwd string) watchertypes.Upda
 	// Convert backslashes to forward slashes
 	relPath = filepath.ToSlash(relPath)
 
+	// Files ignored by .gzignore never trigger syncs
+	isDir := false
+	if info, err := os.Stat(absFilePath); err == nil {
+		isDir = info.IsDir()
+	}
+	if gzignore.ForDir(absChallengePath).Match(absFilePath, isDir) {
+		log.InfoH3("File %s is ignored by %s, skipping update", relPath, gzignore.FileName)
+		return watchertypes.UpdateNone
+	}
+
 	// Check if it's in solver directory - no update needed
 	if strings.HasPrefix(relPath, "solver/") || strings.HasPrefix(relPath, "writeup/") {
 		log.InfoH3("File is in solver/writeup directory, skipping update")
@@ -184,12 +199,18 @@ func DetermineUpdateType(filePath string, challengeCwd string) watchertypes.Upda
 	}
 
 	// Check if it's challenge.yml or challenge.yaml - metadata update only
-	base := filepath.Base(relPath)
-	if base == "challenge.yml" || base == "challenge.yaml" {
+	if registry.IsChallengeFile(filepath.Base(relPath)) {
 		log.InfoH3("Challenge configuration file changed, updating metadata and attachment")
 		return watchertypes.UpdateMetadata
 	}
 
+	// The README and its images make up the description of a challenge
+	// without one
+	if strings.EqualFold(relPath, challenge.ReadmeFile) || slices.Contains(challenge.ReadmeImages(absChallengePath), relPath) {
+		log.InfoH3("README or one of its images changed, updating description")
+		return watchertypes.UpdateMetadata
+	}
+
 	// Check if it's in dist directory - attachment update only
 	if strings.HasPrefix(relPath, "dist/") {
 		log.InfoH3("File in dist directory changed, updating attachment only")
@@ -0,0 +1,139 @@
+package filesystem
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/gzignore"
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// fileState is what a poll compares to detect a changed file
+type fileState struct {
+	modTime time.Time
+	size    int64
+	isDir   bool
+}
+
+// Poller detects file changes by scanning directories, for filesystems where
+// inotify is unavailable, e.g. network filesystems, or its watch limit is
+// reached. Each poll compares the files of the directories to the previous
+// poll and hands the differences to the handler like fsnotify events.
+type Poller struct {
+	dirs      func() []string
+	config    watchertypes.WatcherConfig
+	handler   EventHandler
+	snapshots map[string]map[string]fileState // directory -> path -> state
+}
+
+// NewPoller creates a poller of the directories returned by dirs, which are
+// read again on every poll
+func NewPoller(dirs func() []string, config watchertypes.WatcherConfig, handler EventHandler) *Poller {
+	return &Poller{
+		dirs:      dirs,
+		config:    config,
+		handler:   handler,
+		snapshots: make(map[string]map[string]fileState),
+	}
+}
+
+// Poll scans the directories once and handles the files created, modified
+// and removed since the previous poll. The first poll of a directory only
+// records its files.
+func (p *Poller) Poll() {
+	snapshots := make(map[string]map[string]fileState)
+	var events []fsnotify.Event
+	for _, dir := range p.dirs() {
+		current := scanDir(dir)
+		snapshots[dir] = current
+		previous, known := p.snapshots[dir]
+		if !known {
+			continue
+		}
+		events = append(events, diffSnapshots(previous, current)...)
+	}
+	p.snapshots = snapshots
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+	for _, event := range events {
+		if ShouldProcessEvent(event, p.config) {
+			log.InfoH2("File change detected by polling: %s (%s)", event.Name, event.Op.String())
+			ProcessEvent(event, p.handler)
+		}
+	}
+}
+
+// Run polls every interval until ctx is closed
+func (p *Poller) Run(interval time.Duration, ctx <-chan struct{}) {
+	p.Poll()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx:
+			return
+		case <-ticker.C:
+			p.Poll()
+		}
+	}
+}
+
+// scanDir records the files and directories below dir, skipping hidden
+// directories and the directories ignored by .gzignore like the inotify
+// watches do
+func scanDir(dir string) map[string]fileState {
+	states := make(map[string]fileState)
+	ignore := gzignore.ForDir(dir)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if d.IsDir() && path != dir {
+			if strings.HasPrefix(d.Name(), ".") || ignore.Match(path, true) {
+				return filepath.SkipDir
+			}
+			ignore.AddFile(path)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		states[path] = fileState{modTime: info.ModTime(), size: info.Size(), isDir: d.IsDir()}
+		return nil
+	})
+	return states
+}
+
+// diffSnapshots returns the events turning previous into current. A removed
+// directory is one removal, without the removals of its contents.
+func diffSnapshots(previous, current map[string]fileState) []fsnotify.Event {
+	var events []fsnotify.Event
+	for path, state := range current {
+		old, existed := previous[path]
+		switch {
+		case !existed:
+			events = append(events, fsnotify.Event{Name: path, Op: fsnotify.Create})
+		case !state.isDir && (!old.modTime.Equal(state.modTime) || old.size != state.size):
+			events = append(events, fsnotify.Event{Name: path, Op: fsnotify.Write})
+		}
+	}
+	for path := range previous {
+		if _, exists := current[path]; exists {
+			continue
+		}
+		parent := filepath.Dir(path)
+		if _, parentExisted := previous[parent]; parentExisted {
+			if _, parentExists := current[parent]; !parentExists {
+				continue
+			}
+		}
+		events = append(events, fsnotify.Event{Name: path, Op: fsnotify.Remove})
+	}
+	return events
+}
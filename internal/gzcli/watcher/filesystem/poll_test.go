@@ -0,0 +1,85 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+type recordingHandler struct {
+	changed []string
+	removed []string
+}
+
+func (h *recordingHandler) HandleFileChange(filePath string) {
+	h.changed = append(h.changed, filePath)
+}
+
+func (h *recordingHandler) HandleFileRemoval(filePath string) {
+	h.removed = append(h.removed, filePath)
+}
+
+func (h *recordingHandler) HandleChallengeRemovalByDir(_ string) {}
+
+func TestPoller(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	write("challenge.yml", "name: a", past)
+	write("src/app.py", "print(1)", past)
+	write("dist/old.zip", "zip", past)
+	write(".git/HEAD", "ref", past)
+
+	handler := &recordingHandler{}
+	dirs := []string{dir}
+	poller := NewPoller(func() []string { return dirs }, watchertypes.WatcherConfig{}, handler)
+
+	poller.Poll()
+	if len(handler.changed) != 0 || len(handler.removed) != 0 {
+		t.Fatalf("first poll handled %v and %v, want nothing", handler.changed, handler.removed)
+	}
+
+	write("challenge.yml", "name: b", time.Now())
+	write("src/new.py", "print(2)", past)
+	write(".git/HEAD", "changed", time.Now())
+	if err := os.RemoveAll(filepath.Join(dir, "dist")); err != nil {
+		t.Fatal(err)
+	}
+	poller.Poll()
+
+	wantChanged := []string{filepath.Join(dir, "challenge.yml"), filepath.Join(dir, "src", "new.py")}
+	if !slices.Equal(handler.changed, wantChanged) {
+		t.Errorf("changed = %v, want %v", handler.changed, wantChanged)
+	}
+	if wantRemoved := []string{filepath.Join(dir, "dist")}; !slices.Equal(handler.removed, wantRemoved) {
+		t.Errorf("removed = %v, want %v", handler.removed, wantRemoved)
+	}
+
+	// A directory added later is recorded, not reported
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "challenge.yml"), []byte("name: c"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dirs = append(dirs, other)
+	handler.changed, handler.removed = nil, nil
+	poller.Poll()
+	if len(handler.changed) != 0 || len(handler.removed) != 0 {
+		t.Errorf("poll of a new directory handled %v and %v, want nothing", handler.changed, handler.removed)
+	}
+}
@@ -0,0 +1,335 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// DefaultCommitBranch is the branch generated artifacts are committed to
+const DefaultCommitBranch = "gzcli/generated"
+
+// DefaultCommitMessage is the default commit message template
+const DefaultCommitMessage = "chore(gzcli): update generated files for {{.Event}}{{if .Challenge}}/{{.Challenge}}{{end}}"
+
+// maxSecretScanSize bounds how much of each staged file is scanned for secrets
+const maxSecretScanSize = 1 << 20
+
+// CommitConfig configures automatic commits of generated artifacts
+type CommitConfig struct {
+	Branch          string // Dedicated branch receiving the commits (default: gzcli/generated)
+	Remote          string // Remote to push to (empty disables pushing)
+	MessageTemplate string // text/template with .Event, .Challenge, .Files and .Time
+	AuthorName      string // Optional commit author name (default: git config)
+	AuthorEmail     string // Optional commit author email (default: git config)
+}
+
+// CommitContext describes what triggered a commit
+type CommitContext struct {
+	Event     string
+	Challenge string
+	Paths     []string // Pathspecs (relative to the repository root) to commit
+}
+
+// commitMessageData is the data available to the commit message template
+type commitMessageData struct {
+	Event     string
+	Challenge string
+	Files     []string
+	Time      string
+}
+
+// repoLocks serializes commits per repository, since several event watchers
+// may share one repository and branch
+var repoLocks sync.Map // repoPath -> *sync.Mutex
+
+func lockRepo(repoPath string) func() {
+	mu, _ := repoLocks.LoadOrStore(repoPath, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// Committer commits generated files to a dedicated branch without touching
+// the working tree, the index or the checked-out branch
+type Committer struct {
+	repoPath string
+	config   CommitConfig
+	message  *template.Template
+}
+
+// NewCommitter creates a committer for the repository at repoPath
+func NewCommitter(repoPath string, config CommitConfig) (*Committer, error) {
+	if config.Branch == "" {
+		config.Branch = DefaultCommitBranch
+	}
+	if config.MessageTemplate == "" {
+		config.MessageTemplate = DefaultCommitMessage
+	}
+
+	if err := runGit(repoPath, nil, "check-ref-format", "--branch", config.Branch); err != nil {
+		return nil, fmt.Errorf("invalid commit branch %q: %w", config.Branch, err)
+	}
+
+	tmpl, err := template.New("commit").Option("missingkey=error").Parse(config.MessageTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit message template: %w", err)
+	}
+
+	return &Committer{
+		repoPath: repoPath,
+		config:   config,
+		message:  tmpl,
+	}, nil
+}
+
+// Commit stages the given paths on top of the dedicated branch, refuses to
+// continue if any staged file looks like a secret, commits and optionally
+// pushes. It returns the new commit SHA, or "" when there was nothing to commit.
+func (c *Committer) Commit(ctx CommitContext) (string, error) {
+	paths, ignored := c.existingPaths(ctx.Paths)
+	if len(paths) == 0 {
+		if len(ignored) > 0 {
+			log.Info("⚠️  Nothing committed: %s ignored by .gitignore", strings.Join(ignored, ", "))
+		}
+		return "", nil
+	}
+
+	unlock := lockRepo(c.repoPath)
+	defer unlock()
+
+	// Stage into a throwaway index so the user's index and checkout are untouched
+	indexFile, err := os.CreateTemp("", "gzcli-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	indexPath := indexFile.Name()
+	_ = indexFile.Close()
+	_ = os.Remove(indexPath)
+	defer func() { _ = os.Remove(indexPath) }()
+	env := []string{"GIT_INDEX_FILE=" + indexPath}
+
+	ref := "refs/heads/" + c.config.Branch
+	// oldValue makes update-ref fail instead of overwriting concurrent updates
+	oldValue := ""
+	parent, err := c.revParse(ref)
+	if err == nil {
+		oldValue = parent
+	} else {
+		// The dedicated branch does not exist yet; start it from HEAD
+		parent, err = c.revParse("HEAD")
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+		}
+	}
+
+	if err := runGit(c.repoPath, env, "read-tree", parent); err != nil {
+		return "", fmt.Errorf("failed to read tree of %s: %w", parent, err)
+	}
+	addArgs := append([]string{"add", "--all", "--"}, paths...)
+	if err := runGit(c.repoPath, env, addArgs...); err != nil {
+		return "", fmt.Errorf("failed to stage generated files: %w", err)
+	}
+
+	changed, err := c.output(env, "diff", "--cached", "--name-only", "--no-renames", parent)
+	if err != nil {
+		return "", fmt.Errorf("failed to list staged files: %w", err)
+	}
+	files := splitLines(changed)
+	if len(files) == 0 {
+		log.DebugH3("No generated changes to commit in %s", c.repoPath)
+		return "", nil
+	}
+
+	if flagged := c.findSecrets(files); len(flagged) > 0 {
+		return "", fmt.Errorf("refusing to commit files that may contain secrets: %s", strings.Join(flagged, ", "))
+	}
+
+	message, err := c.renderMessage(ctx, files)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err := c.output(env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write tree: %w", err)
+	}
+
+	commitEnv := append([]string{}, env...)
+	if c.config.AuthorName != "" {
+		commitEnv = append(commitEnv, "GIT_AUTHOR_NAME="+c.config.AuthorName, "GIT_COMMITTER_NAME="+c.config.AuthorName)
+	}
+	if c.config.AuthorEmail != "" {
+		commitEnv = append(commitEnv, "GIT_AUTHOR_EMAIL="+c.config.AuthorEmail, "GIT_COMMITTER_EMAIL="+c.config.AuthorEmail)
+	}
+	sha, err := c.output(commitEnv, "commit-tree", tree, "-p", parent, "-m", message)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	if err := runGit(c.repoPath, nil, "update-ref", ref, sha, oldValue); err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", ref, err)
+	}
+	log.InfoH3("📝 Committed %d generated file(s) to %s (%s)", len(files), c.config.Branch, shortSHA(sha))
+
+	if c.config.Remote != "" {
+		if err := runGit(c.repoPath, nil, "push", c.config.Remote, ref+":"+ref); err != nil {
+			return sha, fmt.Errorf("failed to push %s to %s: %w", c.config.Branch, c.config.Remote, err)
+		}
+		log.InfoH3("⬆️  Pushed %s to %s", c.config.Branch, c.config.Remote)
+	}
+
+	return sha, nil
+}
+
+// existingPaths drops pathspecs that are missing from the working tree or
+// ignored by .gitignore, which git add would otherwise reject. The ignored
+// ones are returned separately.
+func (c *Committer) existingPaths(paths []string) (existing, ignored []string) {
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(c.repoPath, p)); err != nil {
+			continue
+		}
+		if runGit(c.repoPath, nil, "check-ignore", "-q", "--", p) == nil {
+			log.DebugH3("Not committing %s: ignored by .gitignore", p)
+			ignored = append(ignored, p)
+			continue
+		}
+		existing = append(existing, p)
+	}
+	return existing, ignored
+}
+
+func (c *Committer) renderMessage(ctx CommitContext, files []string) (string, error) {
+	var buf bytes.Buffer
+	data := commitMessageData{
+		Event:     ctx.Event,
+		Challenge: ctx.Challenge,
+		Files:     files,
+		Time:      time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := c.message.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render commit message: %w", err)
+	}
+	message := strings.TrimSpace(buf.String())
+	if message == "" {
+		return "", fmt.Errorf("commit message template rendered an empty message")
+	}
+	return message, nil
+}
+
+// secretFilePatterns match file names that should never be committed
+var secretFilePatterns = []string{
+	".env", ".env.*", "*.p12", "*.pfx", "*.kdbx",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", ".netrc", ".npmrc", ".pypirc",
+	"creds.key", "*.sqlite", "*.db",
+}
+
+// secretContentPatterns match content that looks like credentials
+var secretContentPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY( BLOCK)?-----`),
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36,}`),
+	regexp.MustCompile(`xox[baprs]-[A-Za-z0-9-]{10,}`),
+}
+
+// findSecrets returns staged files whose name or content looks like a secret.
+// The workspace's .gzctf directory and .gzcli state are always rejected since
+// they hold server credentials and caches.
+func (c *Committer) findSecrets(files []string) []string {
+	var flagged []string
+	for _, file := range files {
+		if isSecretPath(file) {
+			flagged = append(flagged, file)
+			continue
+		}
+
+		// Deleted files have no content to scan
+		//nolint:gosec // G304: file comes from git's list of staged paths inside the repository
+		f, err := os.Open(filepath.Join(c.repoPath, file))
+		if err != nil {
+			continue
+		}
+		content, _ := io.ReadAll(io.LimitReader(f, maxSecretScanSize))
+		_ = f.Close()
+
+		for _, pattern := range secretContentPatterns {
+			if pattern.Match(content) {
+				flagged = append(flagged, file)
+				break
+			}
+		}
+	}
+	return flagged
+}
+
+func isSecretPath(file string) bool {
+	slashed := filepath.ToSlash(file)
+	for _, part := range strings.Split(slashed, "/") {
+		if part == ".gzctf" || part == ".gzcli" {
+			return true
+		}
+	}
+
+	base := filepath.Base(file)
+	for _, pattern := range secretFilePatterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Committer) revParse(rev string) (string, error) {
+	return c.output(nil, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+}
+
+func (c *Committer) output(env []string, args ...string) (string, error) {
+	//nolint:gosec // G204: program is the literal "git"; arguments are built by gzcli
+	cmd := exec.Command("git", append([]string{"-C", c.repoPath}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w (%s)", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func runGit(repoPath string, env []string, args ...string) error {
+	//nolint:gosec // G204: program is the literal "git"; arguments are built by gzcli
+	cmd := exec.Command("git", append([]string{"-C", repoPath}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %w (%s)", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 10 {
+		return sha[:10]
+	}
+	return sha
+}
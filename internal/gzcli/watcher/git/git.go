@@ -18,7 +18,35 @@ type Manager struct {
 	repoPath string
 	interval time.Duration
 	onUpdate func() // Callback to execute after successful pull
+	onPull   func(startedAt, endedAt time.Time, err error)
 	ctx      context.Context
+
+	pull          PullConfig
+	sparseApplied bool
+}
+
+// PullConfig selects the remote, branch and paths pulls update
+type PullConfig struct {
+	Remote      string   // Remote to pull from; empty uses the branch's upstream, or origin when Branch is set
+	Branch      string   // Branch to check out and pull; empty keeps the current branch
+	SparsePaths []string // Restrict the working tree to these directories (sparse-checkout)
+	ForceReset  bool     // Fetch and reset hard to the remote branch instead of merging
+}
+
+// ConflictError is a pull that could not be applied because it conflicts
+// with the local repository. Merges are aborted, so the working tree is left
+// as it was before the pull.
+type ConflictError struct {
+	Repo   string
+	Files  []string // Conflicting files, when git names them
+	Output string
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Files) > 0 {
+		return fmt.Sprintf("git pull in %s conflicts with local changes to %s", e.Repo, strings.Join(e.Files, ", "))
+	}
+	return fmt.Sprintf("git pull in %s cannot be applied: %s", e.Repo, firstLine(e.Output))
 }
 
 // NewManager creates a new git manager
@@ -30,6 +58,29 @@ func NewManager(repoPath string, interval time.Duration, onUpdate func()) *Manag
 	}
 }
 
+// SetPullConfig sets the remote, branch and sparse-checkout paths of pulls.
+// The zero value pulls the current branch from its upstream.
+func (m *Manager) SetPullConfig(conf PullConfig) {
+	m.pull = conf
+	m.sparseApplied = false
+}
+
+// SetPullObserver registers a callback invoked after every pull made by the
+// pull loop, with its start and end times and the pull error, if any
+func (m *Manager) SetPullObserver(fn func(startedAt, endedAt time.Time, err error)) {
+	m.onPull = fn
+}
+
+// observedPull performs a pull and reports it to the pull observer
+func (m *Manager) observedPull() error {
+	startedAt := time.Now()
+	err := m.PerformPull()
+	if m.onPull != nil {
+		m.onPull(startedAt, time.Now(), err)
+	}
+	return err
+}
+
 // StartPullLoop starts the periodic git pull loop
 func (m *Manager) StartPullLoop(ctx context.Context) {
 	m.ctx = ctx
@@ -43,7 +94,7 @@ func (m *Manager) StartPullLoop(ctx context.Context) {
 
 	// Initial pull on startup
 	log.Info("🔄 Performing initial git pull...")
-	if err := m.PerformPull(); err != nil {
+	if err := m.observedPull(); err != nil {
 		log.Error("Initial git pull failed: %v", err)
 	}
 
@@ -56,7 +107,7 @@ func (m *Manager) StartPullLoop(ctx context.Context) {
 			log.Info("Git pull loop stopped")
 			return
 		case <-ticker.C:
-			if err := m.PerformPull(); err != nil {
+			if err := m.observedPull(); err != nil {
 				log.Error("Git pull failed: %v", err)
 			}
 		}
@@ -74,23 +125,24 @@ func (m *Manager) PerformPull() error {
 		return fmt.Errorf("no git repository found at %s (looking for .git in event root only): %w", m.repoPath, err)
 	}
 
-	// Execute system git pull (inherits env; uses current credentials/SSH config)
 	oldHead, oldHeadErr := m.getHeadSHA(root)
 	if oldHeadErr != nil {
 		log.Debug("Unable to read HEAD before pull in %s: %v", root, oldHeadErr)
 	}
 
-	// Execute system git pull (inherits env; uses current credentials/SSH config)
-	//nolint:gosec // G204: Git repository path is validated and configured by user
-	cmd := exec.Command("git", "-C", root, "pull")
-	cmd.Env = os.Environ()
-	output, err := cmd.CombinedOutput()
+	if err := m.applySparseCheckout(root); err != nil {
+		return err
+	}
+
+	var output []byte
+	var err error
+	if m.pull.ForceReset {
+		output, err = m.fetchAndReset(root)
+	} else {
+		output, err = m.mergePull(root)
+	}
 	if err != nil {
-		log.Error("git pull failed: %v", err)
-		if len(output) > 0 {
-			log.Error("git output: %s", strings.TrimSpace(string(output)))
-		}
-		return fmt.Errorf("git pull failed: %w", err)
+		return err
 	}
 
 	// Log concise success and any non-empty output
@@ -124,11 +176,156 @@ func (m *Manager) PerformPull() error {
 	return nil
 }
 
+// mergePull checks out the configured branch and pulls it. Conflicting
+// merges are aborted and reported as a ConflictError.
+func (m *Manager) mergePull(root string) ([]byte, error) {
+	if m.pull.Branch != "" {
+		if err := m.checkoutBranch(root); err != nil {
+			return nil, err
+		}
+	}
+
+	args := []string{"pull"}
+	if remote := m.remote(); remote != "" {
+		branch, err := m.branch(root)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, remote, branch)
+	}
+
+	// Execute system git pull (inherits env; uses current credentials/SSH config)
+	output, err := execGit(root, args...)
+	if err != nil {
+		if conflict := m.conflict(root, output); conflict != nil {
+			return nil, conflict
+		}
+		log.Error("git pull failed: %v", err)
+		if len(output) > 0 {
+			log.Error("git output: %s", strings.TrimSpace(string(output)))
+		}
+		return nil, fmt.Errorf("git pull failed: %w", err)
+	}
+	return output, nil
+}
+
+// fetchAndReset fetches the configured branch and resets the working tree
+// to it, discarding local commits and changes
+func (m *Manager) fetchAndReset(root string) ([]byte, error) {
+	branch, err := m.branch(root)
+	if err != nil {
+		return nil, err
+	}
+	remote := m.remote()
+	if remote == "" {
+		remote = "origin"
+	}
+
+	if output, err := execGit(root, "fetch", remote, branch); err != nil {
+		return nil, fmt.Errorf("git fetch %s %s failed: %w (%s)", remote, branch, err, strings.TrimSpace(string(output)))
+	}
+
+	current, _ := currentBranch(root)
+	args := []string{"reset", "--hard", "FETCH_HEAD"}
+	if current != branch {
+		args = []string{"checkout", "-f", "-B", branch, "FETCH_HEAD"}
+	}
+	output, err := execGit(root, args...)
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w (%s)", args[0], err, strings.TrimSpace(string(output)))
+	}
+	log.InfoH3("♻️ Reset %s to %s/%s", root, remote, branch)
+	return output, nil
+}
+
+// checkoutBranch switches to the configured branch, creating it from the
+// remote when it does not exist locally
+func (m *Manager) checkoutBranch(root string) error {
+	branch := m.pull.Branch
+	if current, _ := currentBranch(root); current == branch {
+		return nil
+	}
+
+	args := []string{"checkout", branch}
+	if _, err := execGit(root, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		remote := m.remote()
+		if output, err := execGit(root, "fetch", remote, branch); err != nil {
+			return fmt.Errorf("git fetch %s %s failed: %w (%s)", remote, branch, err, strings.TrimSpace(string(output)))
+		}
+		args = []string{"checkout", "-b", branch, "--track", remote + "/" + branch}
+	}
+
+	output, err := execGit(root, args...)
+	if err != nil {
+		if files := overwrittenFiles(string(output)); len(files) > 0 {
+			return &ConflictError{Repo: root, Files: files, Output: string(output)}
+		}
+		return fmt.Errorf("git checkout %s failed: %w (%s)", branch, err, strings.TrimSpace(string(output)))
+	}
+	log.InfoH3("🔀 Checked out branch %s in %s", branch, root)
+	return nil
+}
+
+// applySparseCheckout restricts the working tree to the configured paths,
+// once per configuration
+func (m *Manager) applySparseCheckout(root string) error {
+	if len(m.pull.SparsePaths) == 0 || m.sparseApplied {
+		return nil
+	}
+	output, err := execGit(root, append([]string{"sparse-checkout", "set", "--cone"}, m.pull.SparsePaths...)...)
+	if err != nil {
+		return fmt.Errorf("git sparse-checkout failed: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	m.sparseApplied = true
+	log.InfoH3("📂 Sparse checkout of %s limited to %s", root, strings.Join(m.pull.SparsePaths, ", "))
+	return nil
+}
+
+// conflict returns a ConflictError when output shows a failed pull
+// conflicted with the local repository, after aborting the merge
+func (m *Manager) conflict(root string, output []byte) *ConflictError {
+	out := string(output)
+	switch {
+	case strings.Contains(out, "CONFLICT") || strings.Contains(out, "Automatic merge failed"):
+		unmerged, _ := execGit(root, "diff", "--name-only", "--diff-filter=U")
+		files := strings.Fields(string(unmerged))
+		if _, err := execGit(root, "merge", "--abort"); err != nil {
+			_, _ = execGit(root, "rebase", "--abort")
+		}
+		return &ConflictError{Repo: root, Files: files, Output: out}
+	case strings.Contains(out, "would be overwritten"):
+		return &ConflictError{Repo: root, Files: overwrittenFiles(out), Output: out}
+	case strings.Contains(out, "Not possible to fast-forward") || strings.Contains(out, "divergent branches"):
+		return &ConflictError{Repo: root, Output: out + "\n(set forceReset in the git block of .gzevent if the history was rewritten)"}
+	}
+	return nil
+}
+
+// remote returns the remote to pull from, "" for the branch's upstream
+func (m *Manager) remote() string {
+	if m.pull.Remote == "" && m.pull.Branch != "" {
+		return "origin"
+	}
+	return m.pull.Remote
+}
+
+// branch returns the branch to pull: the configured or the current one
+func (m *Manager) branch(root string) (string, error) {
+	if m.pull.Branch != "" {
+		return m.pull.Branch, nil
+	}
+	branch, err := currentBranch(root)
+	if err != nil {
+		return "", err
+	}
+	if branch == "HEAD" {
+		return "", fmt.Errorf("repository %s is in detached HEAD state; set branch in the git block of .gzevent", root)
+	}
+	return branch, nil
+}
+
 func (m *Manager) getHeadSHA(root string) (string, error) {
-	//nolint:gosec // G204: program is the literal "git"; root is the repo path
-	// configured by the user and the remaining arguments are hard-coded.
-	cmd := exec.Command("git", "-C", root, "rev-parse", "HEAD")
-	out, err := cmd.CombinedOutput()
+	out, err := execGit(root, "rev-parse", "HEAD")
 	if err != nil {
 		return "", fmt.Errorf("git rev-parse HEAD failed: %w (%s)", err, strings.TrimSpace(string(out)))
 	}
@@ -136,6 +333,41 @@ func (m *Manager) getHeadSHA(root string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+func currentBranch(root string) (string, error) {
+	out, err := execGit(root, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --abbrev-ref HEAD failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// execGit runs git in root with the inherited environment, so the current
+// credentials and SSH configuration apply
+func execGit(root string, args ...string) ([]byte, error) {
+	//nolint:gosec // G204: program is the literal "git"; root is the repo path
+	// configured by the user and the arguments are validated configuration.
+	cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+	cmd.Env = os.Environ()
+	return cmd.CombinedOutput()
+}
+
+// overwrittenFiles extracts the files git lists, tab-indented, when local
+// changes would be overwritten
+func overwrittenFiles(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			files = append(files, strings.TrimSpace(line))
+		}
+	}
+	return files
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
 // ResolveRepoPaths attempts to find git repositories in the following order:
 // 1. Current working directory (returns single path)
 // 2. ./events directory (returns single path)
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cloneWithRemotes creates two upstream repositories, "origin" and
+// "mirror", each with a main and a staging branch, and a clone tracking
+// origin/main that has mirror as a second remote
+func cloneWithRemotes(t *testing.T) (clone, origin, mirror string) {
+	t.Helper()
+	origin = initRealRepo(t)
+	mirror = initRealRepo(t)
+	for _, repo := range []string{origin, mirror} {
+		for _, args := range [][]string{
+			{"checkout", "-q", "-B", "main"},
+			{"branch", "staging"},
+			{"config", "receive.denyCurrentBranch", "ignore"},
+		} {
+			if err := runGit(repo, nil, args...); err != nil {
+				t.Fatalf("git %v: %v", args, err)
+			}
+		}
+	}
+
+	clone = filepath.Join(t.TempDir(), "clone")
+	if err := runGit(t.TempDir(), nil, "clone", "-q", "-b", "main", origin, clone); err != nil {
+		t.Fatalf("git clone: %v", err)
+	}
+	for _, args := range [][]string{
+		{"config", "user.name", "tester"},
+		{"config", "user.email", "tester@example.com"},
+		{"remote", "add", "mirror", mirror},
+	} {
+		if err := runGit(clone, nil, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	return clone, origin, mirror
+}
+
+// commitFile commits a file to branch of repo
+func commitFile(t *testing.T, repo, branch, name, content string) {
+	t.Helper()
+	if err := runGit(repo, nil, "checkout", "-q", branch); err != nil {
+		t.Fatalf("git checkout %s: %v", branch, err)
+	}
+	writeTestFile(t, filepath.Join(repo, name), content)
+	for _, args := range [][]string{{"add", name}, {"commit", "-q", "-m", "update " + name}} {
+		if err := runGit(repo, nil, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+}
+
+func TestPerformPull_RemoteAndBranch(t *testing.T) {
+	clone, _, mirror := cloneWithRemotes(t)
+	commitFile(t, mirror, "staging", "web/chall.txt", "from mirror")
+
+	updated := false
+	mgr := NewManager(clone, time.Minute, func() { updated = true })
+	mgr.SetPullConfig(PullConfig{Remote: "mirror", Branch: "staging"})
+	if err := mgr.PerformPull(); err != nil {
+		t.Fatalf("PerformPull() error = %v", err)
+	}
+
+	if branch := gitOutput(t, clone, "rev-parse", "--abbrev-ref", "HEAD"); branch != "staging" {
+		t.Errorf("current branch = %q, want staging", branch)
+	}
+	if content, err := os.ReadFile(filepath.Join(clone, "web", "chall.txt")); err != nil || string(content) != "from mirror" {
+		t.Errorf("web/chall.txt = %q, %v; want the mirror's commit", content, err)
+	}
+	if !updated {
+		t.Error("onUpdate was not called after new commits were pulled")
+	}
+}
+
+func TestPerformPull_ForceReset(t *testing.T) {
+	clone, origin, _ := cloneWithRemotes(t)
+	commitFile(t, origin, "main", "a.txt", "one")
+	mgr := NewManager(clone, time.Minute, nil)
+	if err := mgr.PerformPull(); err != nil {
+		t.Fatalf("PerformPull() error = %v", err)
+	}
+
+	// Rewrite the history of origin/main and diverge locally
+	if err := runGit(origin, nil, "reset", "-q", "--hard", "HEAD~1"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, origin, "main", "b.txt", "rewritten")
+	commitFile(t, clone, "main", "local.txt", "local")
+
+	var conflict *ConflictError
+	if err := mgr.PerformPull(); !errors.As(err, &conflict) {
+		t.Fatalf("PerformPull() of rewritten history = %v, want a ConflictError", err)
+	}
+
+	mgr.SetPullConfig(PullConfig{ForceReset: true})
+	if err := mgr.PerformPull(); err != nil {
+		t.Fatalf("PerformPull() with ForceReset error = %v", err)
+	}
+	if local, remote := gitOutput(t, clone, "rev-parse", "HEAD"), gitOutput(t, origin, "rev-parse", "main"); local != remote {
+		t.Errorf("HEAD = %s, want origin/main %s", local, remote)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "local.txt")); !os.IsNotExist(err) {
+		t.Error("ForceReset kept the local commit")
+	}
+}
+
+func TestPerformPull_Conflict(t *testing.T) {
+	clone, origin, _ := cloneWithRemotes(t)
+	commitFile(t, origin, "main", "flag.txt", "upstream")
+	commitFile(t, clone, "main", "flag.txt", "local")
+	if err := runGit(clone, nil, "config", "pull.rebase", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewManager(clone, time.Minute, nil).PerformPull()
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("PerformPull() = %v, want a ConflictError", err)
+	}
+	if len(conflict.Files) != 1 || conflict.Files[0] != "flag.txt" {
+		t.Errorf("conflicting files = %v, want [flag.txt]", conflict.Files)
+	}
+	if status := gitOutput(t, clone, "status", "--porcelain"); status != "" {
+		t.Errorf("merge was not aborted, status:\n%s", status)
+	}
+}
+
+func TestPerformPull_SparsePaths(t *testing.T) {
+	clone, origin, _ := cloneWithRemotes(t)
+	commitFile(t, origin, "main", "web/a.txt", "web")
+	commitFile(t, origin, "main", "pwn/b.txt", "pwn")
+
+	mgr := NewManager(clone, time.Minute, nil)
+	mgr.SetPullConfig(PullConfig{SparsePaths: []string{"web"}})
+	if err := mgr.PerformPull(); err != nil {
+		t.Fatalf("PerformPull() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "web", "a.txt")); err != nil {
+		t.Errorf("web/a.txt missing from sparse checkout: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "pwn", "b.txt")); !os.IsNotExist(err) {
+		t.Errorf("pwn/b.txt checked out outside the sparse paths: %v", err)
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	err := &ConflictError{Repo: "/repo", Files: []string{"a", "b"}}
+	if !strings.Contains(err.Error(), "a, b") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	files := overwrittenFiles("error: Your local changes to the following files would be overwritten by merge:\n\tweb/a.txt\n\tpwn/b.txt\nPlease commit")
+	if strings.Join(files, ",") != "web/a.txt,pwn/b.txt" {
+		t.Errorf("overwrittenFiles() = %v", files)
+	}
+}
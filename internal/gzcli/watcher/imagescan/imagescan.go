@@ -0,0 +1,139 @@
+// Package imagescan runs vulnerability scanners against challenge images before they are deployed
+package imagescan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultCommand scans an image with trivy and exits non-zero on critical findings.
+// A grype equivalent is "grype {{.Image}} --fail-on critical".
+const DefaultCommand = "trivy image --quiet --exit-code 1 --severity CRITICAL {{.Image}}"
+
+// DefaultTimeout bounds a single scan, including a possible image pull
+const DefaultTimeout = 10 * time.Minute
+
+// maxOutputSize bounds how much scanner output is kept per scan
+const maxOutputSize = 64 << 10
+
+// Scan outcomes
+const (
+	StatusPassed   = "passed"   // Scanner exited with status 0
+	StatusFindings = "findings" // Scanner exited with a non-zero status
+	StatusError    = "error"    // Scanner could not be run
+)
+
+// Result is the outcome of scanning one image
+type Result struct {
+	Image    string
+	Command  string
+	Status   string // passed, findings, error
+	ExitCode int
+	Output   string // Combined stdout and stderr, truncated to maxOutputSize
+	Duration time.Duration
+	Err      error // Set when Status is error
+}
+
+// commandData is the data available to the scan command template. Values
+// are shell-quoted, since the command runs through sh -c.
+type commandData struct {
+	Image     string
+	Event     string
+	Challenge string
+}
+
+// Scanner runs a configurable scan command against images
+type Scanner struct {
+	command *template.Template
+	timeout time.Duration
+}
+
+// NewScanner creates a scanner from a text/template command with .Image,
+// .Event and .Challenge. The command must exit non-zero when the image has
+// findings that should stop (or be reported for) a deployment.
+func NewScanner(command string, timeout time.Duration) (*Scanner, error) {
+	if command == "" {
+		command = DefaultCommand
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	tmpl, err := template.New("scan").Option("missingkey=error").Parse(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image scan command: %w", err)
+	}
+	return &Scanner{command: tmpl, timeout: timeout}, nil
+}
+
+// Scan runs the scan command for image in dir
+func (s *Scanner) Scan(ctx context.Context, event, challenge, image, dir string) Result {
+	result := Result{Image: image, Status: StatusError, ExitCode: -1}
+
+	var command bytes.Buffer
+	if err := s.command.Execute(&command, commandData{
+		Image:     shellQuote(image),
+		Event:     shellQuote(event),
+		Challenge: shellQuote(challenge),
+	}); err != nil {
+		result.Err = fmt.Errorf("failed to render scan command: %w", err)
+		return result
+	}
+	result.Command = command.String()
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	//nolint:gosec // G204: The scan command is configured by the operator
+	cmd := exec.CommandContext(ctx, "sh", "-c", result.Command)
+	cmd.Dir = dir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Processes the shell started may outlive it and hold the output open
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+	result.Output = truncate(output.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.Status = StatusPassed
+		result.ExitCode = 0
+	case ctx.Err() != nil:
+		result.Err = fmt.Errorf("scan timed out after %s", s.timeout)
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		result.ExitCode = exitErr.ExitCode()
+		// sh reports a missing scanner as 127 and a non-executable one as 126
+		if result.ExitCode == 126 || result.ExitCode == 127 {
+			result.Err = fmt.Errorf("scanner could not be run (exit status %d)", result.ExitCode)
+		} else {
+			result.Status = StatusFindings
+		}
+	default:
+		result.Err = fmt.Errorf("failed to run scanner: %w", err)
+	}
+	return result
+}
+
+// shellQuote quotes s as a single sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// truncate keeps the tail of scanner output, where summaries are printed
+func truncate(s string) string {
+	if len(s) <= maxOutputSize {
+		return s
+	}
+	return "[truncated]\n" + s[len(s)-maxOutputSize:]
+}
@@ -0,0 +1,58 @@
+package imagescan
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		wantStatus string
+		wantCode   int
+		wantOutput string
+	}{
+		{"passed", "echo scanning {{.Image}}", StatusPassed, 0, "scanning registry/web:1 it's"},
+		{"findings", "echo CRITICAL: 2; exit 1", StatusFindings, 1, "CRITICAL: 2"},
+		{"missing scanner", "gzcli-no-such-scanner {{.Image}}", StatusError, 127, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, err := NewScanner(tt.command, time.Minute)
+			if err != nil {
+				t.Fatalf("NewScanner() error = %v", err)
+			}
+			result := scanner.Scan(context.Background(), "ctf", "web", "registry/web:1 it's", t.TempDir())
+			if result.Status != tt.wantStatus || result.ExitCode != tt.wantCode {
+				t.Errorf("Scan() = %s (exit %d, err %v), want %s (exit %d)",
+					result.Status, result.ExitCode, result.Err, tt.wantStatus, tt.wantCode)
+			}
+			if !strings.Contains(result.Output, tt.wantOutput) {
+				t.Errorf("Output = %q, want it to contain %q", result.Output, tt.wantOutput)
+			}
+			if (result.Status == StatusError) != (result.Err != nil) {
+				t.Errorf("Err = %v for status %s", result.Err, result.Status)
+			}
+		})
+	}
+}
+
+func TestScanner_Timeout(t *testing.T) {
+	scanner, err := NewScanner("sleep 5", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	if result := scanner.Scan(context.Background(), "ctf", "web", "img", t.TempDir()); result.Status != StatusError {
+		t.Errorf("Scan() status = %s, want %s", result.Status, StatusError)
+	}
+}
+
+func TestNewScanner_InvalidTemplate(t *testing.T) {
+	if _, err := NewScanner("trivy image {{.Image", 0); err == nil {
+		t.Error("NewScanner() succeeded with an invalid template")
+	}
+}
@@ -0,0 +1,77 @@
+package scripts
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dimasma0305/gzcli/internal/log"
+)
+
+// HealthcheckScript is the name of the interval script checking that a
+// deployed challenge still works
+const HealthcheckScript = "healthcheck"
+
+// DefaultHealthcheckFailures is the number of consecutive failed healthchecks
+// after which a challenge is considered unhealthy
+const DefaultHealthcheckFailures = 3
+
+// HealthcheckHandler is notified of the health of a challenge: after every
+// run with the number of consecutive failures, unhealthy once they reach the
+// threshold. The failure count restarts after an unhealthy report, so a
+// challenge still failing is reported again after another threshold of runs.
+type HealthcheckHandler func(challengeName string, failures int, unhealthy bool, err error)
+
+// healthcheckState counts the consecutive failures of a healthcheck
+type healthcheckState struct {
+	mu        sync.Mutex
+	threshold int
+	failures  int
+}
+
+// record adds the outcome of a run and returns the consecutive failures so
+// far and whether they reached the threshold
+func (h *healthcheckState) record(err error) (int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err == nil {
+		h.failures = 0
+		return 0, false
+	}
+	h.failures++
+	failures := h.failures
+	if failures < h.threshold {
+		return failures, false
+	}
+	h.failures = 0
+	return failures, true
+}
+
+// StartHealthcheck runs the healthcheck script of a challenge at its interval
+// and reports each result to handler. A threshold of 0 or less uses
+// DefaultHealthcheckFailures. Challenges without a healthcheck script are
+// ignored; one without an interval is an error.
+func (m *Manager) StartHealthcheck(challenge ChallengeConfig, threshold int, handler HealthcheckHandler) error {
+	scriptValue, exists := challenge.GetScripts()[HealthcheckScript]
+	if !exists || scriptValue.GetCommand() == "" {
+		m.StopIntervalScript(challenge.GetName(), HealthcheckScript)
+		return nil
+	}
+	if !scriptValue.HasInterval() {
+		return fmt.Errorf("healthcheck script of %s needs an interval", challenge.GetName())
+	}
+	if threshold <= 0 {
+		threshold = DefaultHealthcheckFailures
+	}
+
+	state := &healthcheckState{threshold: threshold}
+	name := challenge.GetName()
+	log.InfoH3("Starting healthcheck of '%s' every %v (unhealthy after %d failures)", name, scriptValue.GetInterval(), threshold)
+	m.startIntervalScript(name, HealthcheckScript, challenge, scriptValue.GetCommand(), scriptValue.GetInterval(), func(err error) {
+		failures, unhealthy := state.record(err)
+		if handler != nil {
+			handler(name, failures, unhealthy, err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,81 @@
+package scripts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthcheckState_Record(t *testing.T) {
+	h := &healthcheckState{threshold: 3}
+	failed := errors.New("connection refused")
+
+	steps := []struct {
+		err       error
+		failures  int
+		unhealthy bool
+	}{
+		{failed, 1, false},
+		{failed, 2, false},
+		{nil, 0, false}, // Success resets the count
+		{failed, 1, false},
+		{failed, 2, false},
+		{failed, 3, true},
+		{failed, 1, false}, // Counting restarts after an unhealthy report
+	}
+	for i, step := range steps {
+		failures, unhealthy := h.record(step.err)
+		if failures != step.failures || unhealthy != step.unhealthy {
+			t.Errorf("step %d: record() = %d, %v; want %d, %v", i, failures, unhealthy, step.failures, step.unhealthy)
+		}
+	}
+}
+
+type testChallenge struct {
+	scripts map[string]ScriptValue
+}
+
+func (c testChallenge) GetName() string                    { return "web/chall" }
+func (c testChallenge) GetCwd() string                     { return "." }
+func (c testChallenge) GetScripts() map[string]ScriptValue { return c.scripts }
+
+type testScript struct {
+	command  string
+	interval time.Duration
+}
+
+func (s testScript) GetCommand() string         { return s.command }
+func (s testScript) HasInterval() bool          { return s.interval > 0 }
+func (s testScript) GetInterval() time.Duration { return s.interval }
+
+func TestStartHealthcheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewManager(ctx, nil)
+
+	if err := m.StartHealthcheck(testChallenge{}, 0, nil); err != nil {
+		t.Errorf("challenge without healthcheck: %v", err)
+	}
+
+	noInterval := testChallenge{scripts: map[string]ScriptValue{HealthcheckScript: testScript{command: "true"}}}
+	if err := m.StartHealthcheck(noInterval, 0, nil); err == nil {
+		t.Error("expected an error for a healthcheck without interval")
+	}
+
+	valid := testChallenge{scripts: map[string]ScriptValue{HealthcheckScript: testScript{command: "true", interval: time.Minute}}}
+	if err := m.StartHealthcheck(valid, 0, nil); err != nil {
+		t.Fatalf("StartHealthcheck() error = %v", err)
+	}
+	if active := m.GetActiveIntervalScripts()["web/chall"]; len(active) != 1 || active[0] != HealthcheckScript {
+		t.Errorf("active scripts = %v, want the healthcheck", active)
+	}
+
+	// Dropping the script from the challenge stops its healthcheck
+	if err := m.StartHealthcheck(testChallenge{}, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if active := m.GetActiveIntervalScripts()["web/chall"]; len(active) != 0 {
+		t.Errorf("active scripts = %v, want none", active)
+	}
+}
@@ -10,6 +10,12 @@ import (
 
 // StartIntervalScript starts an interval script for a challenge with proper tracking and validation
 func (m *Manager) StartIntervalScript(challengeName, scriptName string, challenge ChallengeConfig, command string, interval time.Duration) {
+	m.startIntervalScript(challengeName, scriptName, challenge, command, interval, nil)
+}
+
+// startIntervalScript starts an interval script, calling onResult, when not
+// nil, with the outcome of every run
+func (m *Manager) startIntervalScript(challengeName, scriptName string, challenge ChallengeConfig, command string, interval time.Duration, onResult func(error)) {
 	// Validate interval before starting
 	if !ValidateInterval(interval, scriptName) {
 		log.Error("Invalid interval for script '%s' in challenge '%s', skipping", scriptName, challengeName)
@@ -62,7 +68,7 @@ func (m *Manager) StartIntervalScript(challengeName, scriptName string, challeng
 	handedOff = true
 
 	// Start the interval script in a goroutine
-	go m.runIntervalScript(ctx, challengeName, scriptName, command, interval, challenge.GetCwd())
+	go m.runIntervalScript(ctx, challengeName, scriptName, command, interval, challenge.GetCwd(), onResult)
 }
 
 // updateScriptMetricsStart updates metrics at the start of execution
@@ -136,7 +142,7 @@ func (m *Manager) executeIntervalScriptOnce(ctx context.Context, challengeName,
 }
 
 // runIntervalScript runs an interval script with proper integration and database logging
-func (m *Manager) runIntervalScript(ctx context.Context, challengeName, scriptName, command string, interval time.Duration, cwd string) {
+func (m *Manager) runIntervalScript(ctx context.Context, challengeName, scriptName, command string, interval time.Duration, cwd string, onResult func(error)) {
 	// Validate interval
 	if !ValidateInterval(interval, scriptName) {
 		log.Error("Invalid interval for script '%s' in challenge '%s', skipping", scriptName, challengeName)
@@ -176,6 +182,11 @@ func (m *Manager) runIntervalScript(ctx context.Context, challengeName, scriptNa
 
 			m.updateScriptMetricsEnd(challengeName, scriptName, duration, err)
 			m.logScriptCompletion(challengeName, scriptName, command, output, errorOutput, duration, exitCode, success)
+
+			// A run cut short by stopping the script says nothing about the challenge
+			if onResult != nil && ctx.Err() == nil {
+				onResult(err)
+			}
 		}
 	}
 }
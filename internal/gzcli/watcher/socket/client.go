@@ -2,8 +2,11 @@
 package socket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"time"
 
@@ -121,6 +124,244 @@ func (c *Client) GetScriptExecutions(challengeName string, limit int) (*watchert
 	return c.SendCommand("get_script_executions", data)
 }
 
+// GetDryRunSyncs gets syncs computed by a dry-run watcher, optionally filtered by event
+func (c *Client) GetDryRunSyncs(event string, limit int) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"limit": limit,
+	}
+	if event != "" {
+		data["event"] = event
+	}
+	return c.SendCommand("get_dry_run_syncs", data)
+}
+
+// GetImageScans gets image scans run before full redeploys, optionally
+// filtered by event and challenge
+func (c *Client) GetImageScans(event, challengeName string, limit int) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"limit": limit,
+	}
+	if event != "" {
+		data["event"] = event
+	}
+	if challengeName != "" {
+		data["challenge_name"] = challengeName
+	}
+	return c.SendCommand("get_image_scans", data)
+}
+
+// GetSyncActivity gets timed syncs and git pulls that ended after since, optionally filtered by event
+func (c *Client) GetSyncActivity(event string, since time.Time) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"since": since.UnixMilli(),
+	}
+	if event != "" {
+		data["event"] = event
+	}
+	return c.SendCommand("get_sync_activity", data)
+}
+
+// SyncProgress gets the progress of the latest sync of all challenges of each
+// event, optionally filtered by event
+func (c *Client) SyncProgress(event string) ([]watchertypes.SyncProgress, error) {
+	var data map[string]interface{}
+	if event != "" {
+		data = map[string]interface{}{"event": event}
+	}
+	response, err := c.SendCommand("status", data)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		return nil, fmt.Errorf("status request failed: %s", response.Error)
+	}
+
+	raw, err := json.Marshal(response.Data["sync_progress"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sync progress: %w", err)
+	}
+	var progress []watchertypes.SyncProgress
+	if err := json.Unmarshal(raw, &progress); err != nil {
+		return nil, fmt.Errorf("failed to decode sync progress: %w", err)
+	}
+	return progress, nil
+}
+
+// Logs gets the watcher log entries selected by filter, oldest first
+func (c *Client) Logs(filter watchertypes.LogFilter) ([]watchertypes.WatcherLog, error) {
+	response, err := c.SendCommand("logs", logFilterData(filter, false))
+	if err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		return nil, fmt.Errorf("logs request failed: %s", response.Error)
+	}
+	return decodeLogs(response)
+}
+
+// FollowLogs streams the watcher log entries selected by filter as they are
+// written, starting with the latest filter.Limit ones, and passes each batch
+// to handle. It returns when ctx is done, handle fails or the watcher stops.
+func (c *Client) FollowLogs(ctx context.Context, filter watchertypes.LogFilter, handle func([]watchertypes.WatcherLog) error) error {
+	conn, err := net.DialTimeout("unix", c.socketPath, c.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to watcher socket %s: %w", c.socketPath, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	cmd := watchertypes.WatcherCommand{Action: "logs", Data: logFilterData(filter, true)}
+	if err := json.NewEncoder(conn).Encode(cmd); err != nil {
+		return fmt.Errorf("failed to send command: %w", err)
+	}
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var response watchertypes.WatcherResponse
+		if err := decoder.Decode(&response); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("watcher closed the log stream")
+			}
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if !response.Success {
+			return fmt.Errorf("logs request failed: %s", response.Error)
+		}
+		logs, err := decodeLogs(&response)
+		if err != nil {
+			return err
+		}
+		if err := handle(logs); err != nil {
+			return err
+		}
+	}
+}
+
+// logFilterData returns the command data of a logs request
+func logFilterData(filter watchertypes.LogFilter, follow bool) map[string]interface{} {
+	data := map[string]interface{}{"follow": follow}
+	if filter.AfterID > 0 {
+		data["after_id"] = filter.AfterID
+	}
+	if filter.Level != "" {
+		data["level"] = filter.Level
+	}
+	if filter.Challenge != "" {
+		data["challenge"] = filter.Challenge
+	}
+	if filter.Limit > 0 {
+		data["limit"] = filter.Limit
+	}
+	return data
+}
+
+// decodeLogs returns the log entries of a logs response
+func decodeLogs(response *watchertypes.WatcherResponse) ([]watchertypes.WatcherLog, error) {
+	raw, err := json.Marshal(response.Data["logs"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode logs: %w", err)
+	}
+	var logs []watchertypes.WatcherLog
+	if err := json.Unmarshal(raw, &logs); err != nil {
+		return nil, fmt.Errorf("failed to decode logs: %w", err)
+	}
+	return logs, nil
+}
+
+// SearchLogs runs a full-text search over watcher logs and script output written
+// after since. Unless raw is set, query is matched word by word literally
+// instead of being parsed as FTS5 syntax.
+func (c *Client) SearchLogs(query string, raw bool, since time.Time, limit int) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"query": query,
+		"raw":   raw,
+		"since": since.UnixMilli(),
+		"limit": limit,
+	}
+	return c.SendCommand("search_logs", data)
+}
+
+// ChallengeStatus gets the sync state, last sync and error, active scripts and
+// mapped challenge ID of a challenge in an event
+func (c *Client) ChallengeStatus(event, challengeName string) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":          event,
+		"challenge_name": challengeName,
+	}
+	return c.SendCommand("challenge_status", data)
+}
+
+// ApproveSync runs the sync of a challenge held by the live lock of an event
+func (c *Client) ApproveSync(event, challengeName string) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":          event,
+		"challenge_name": challengeName,
+	}
+	return c.SendCommand("approve_sync", data)
+}
+
+// PauseSync pauses the syncs of a challenge of an event, or of the whole event
+// when challengeName is empty, until ResumeSync
+func (c *Client) PauseSync(event, challengeName, reason string) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":          event,
+		"challenge_name": challengeName,
+		"reason":         reason,
+	}
+	return c.SendCommand("pause_sync", data)
+}
+
+// ResumeSync resumes the syncs paused with PauseSync, syncing the changes
+// skipped meanwhile
+func (c *Client) ResumeSync(event, challengeName string) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":          event,
+		"challenge_name": challengeName,
+	}
+	return c.SendCommand("resume_sync", data)
+}
+
+// SyncChallenge fully syncs a challenge of an event and waits for the sync to
+// finish. Syncs can take minutes; raise the timeout with SetTimeout.
+func (c *Client) SyncChallenge(event, challengeName string) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":          event,
+		"challenge_name": challengeName,
+	}
+	return c.SendCommand("sync_challenge", data)
+}
+
+// RemapChallenges drops the challenge mappings of the given folders of an
+// event, or every mapping pointing at another game than the event's when no
+// folder is given
+func (c *Client) RemapChallenges(event string, folders []string) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":   event,
+		"folders": folders,
+	}
+	return c.SendCommand("remap_challenges", data)
+}
+
+// SetMappings records mappings of challenge folders of an event to
+// challenges of the given game, replacing existing ones
+func (c *Client) SetMappings(event string, gameID int, mappings []watchertypes.ChallengeMapping) (*watchertypes.WatcherResponse, error) {
+	data := map[string]interface{}{
+		"event":    event,
+		"game_id":  gameID,
+		"mappings": mappings,
+	}
+	return c.SendCommand("set_mappings", data)
+}
+
 // IsWatcherRunning checks if the watcher daemon is running
 func (c *Client) IsWatcherRunning() bool {
 	response, err := c.Status()
@@ -1,6 +1,7 @@
 package socket
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
@@ -17,6 +18,19 @@ type Handler interface {
 	HandleRestartChallengeCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
 	HandleGetScriptExecutionsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
 	HandleStopEventCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleGetDryRunSyncsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleGetSyncActivityCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleGetImageScansCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleSearchLogsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleRemapChallengesCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleSetMappingsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleChallengeStatusCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleApproveSyncCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandlePauseSyncCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleResumeSyncCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleSyncChallengeCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	HandleLogsCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
+	StreamLogsCommand(ctx context.Context, cmd watchertypes.WatcherCommand, send func(watchertypes.WatcherResponse) error)
 }
 
 // DefaultCommandHandler implements CommandHandler by routing to Handler methods
@@ -48,6 +62,30 @@ func (h *DefaultCommandHandler) HandleCommand(cmd watchertypes.WatcherCommand) w
 		return h.handler.HandleGetScriptExecutionsCommand(cmd)
 	case "stop_event":
 		return h.handler.HandleStopEventCommand(cmd)
+	case "get_dry_run_syncs":
+		return h.handler.HandleGetDryRunSyncsCommand(cmd)
+	case "get_sync_activity":
+		return h.handler.HandleGetSyncActivityCommand(cmd)
+	case "get_image_scans":
+		return h.handler.HandleGetImageScansCommand(cmd)
+	case "search_logs":
+		return h.handler.HandleSearchLogsCommand(cmd)
+	case "remap_challenges":
+		return h.handler.HandleRemapChallengesCommand(cmd)
+	case "set_mappings":
+		return h.handler.HandleSetMappingsCommand(cmd)
+	case "challenge_status":
+		return h.handler.HandleChallengeStatusCommand(cmd)
+	case "approve_sync":
+		return h.handler.HandleApproveSyncCommand(cmd)
+	case "pause_sync":
+		return h.handler.HandlePauseSyncCommand(cmd)
+	case "resume_sync":
+		return h.handler.HandleResumeSyncCommand(cmd)
+	case "sync_challenge":
+		return h.handler.HandleSyncChallengeCommand(cmd)
+	case "logs":
+		return h.handler.HandleLogsCommand(cmd)
 	default:
 		return watchertypes.WatcherResponse{
 			Success: false,
@@ -55,3 +93,15 @@ func (h *DefaultCommandHandler) HandleCommand(cmd watchertypes.WatcherCommand) w
 		}
 	}
 }
+
+// IsStreamCommand reports whether a command is answered with a stream of
+// responses: "logs" with follow set
+func (h *DefaultCommandHandler) IsStreamCommand(cmd watchertypes.WatcherCommand) bool {
+	follow, _ := cmd.Data["follow"].(bool)
+	return cmd.Action == "logs" && follow
+}
+
+// HandleStreamCommand streams the responses of a stream command
+func (h *DefaultCommandHandler) HandleStreamCommand(ctx context.Context, cmd watchertypes.WatcherCommand, send func(watchertypes.WatcherResponse) error) {
+	h.handler.StreamLogsCommand(ctx, cmd, send)
+}
@@ -2,7 +2,10 @@ package socket
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
 )
 
 // StreamLiveLogs streams database logs in real-time
@@ -90,9 +93,13 @@ func displayLogEntry(logMap map[string]interface{}) {
 
 // printStatusInfo prints basic status information
 func printStatusInfo(data map[string]interface{}) {
-	if status, ok := data["status"].(string); ok && status == "running" {
+	switch status, _ := data["status"].(string); status {
+	case "running":
 		fmt.Println("🟢 Status: RUNNING")
-	} else {
+	case "safe_mode":
+		fmt.Println("🟠 Status: SAFE MODE (crash loop, challenges are not watched)")
+		printCrashInfo(data)
+	default:
 		fmt.Println("🔴 Status: UNKNOWN")
 	}
 
@@ -101,6 +108,27 @@ func printStatusInfo(data map[string]interface{}) {
 	}
 }
 
+// printCrashInfo prints the crashes that made the watcher start in safe mode
+func printCrashInfo(data map[string]interface{}) {
+	crash, ok := data["crash"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if crashes, ok := crash["crashes"].(float64); ok {
+		fmt.Printf("💥 Crashes in a row: %.0f\n", crashes)
+	}
+	if at, ok := crash["last_crash_at"].(string); ok {
+		fmt.Printf("🕒 Last crash: %s\n", at)
+	}
+	if lastErr, ok := crash["last_error"].(string); ok && lastErr != "" {
+		fmt.Printf("❌ Last error: %s\n", lastErr)
+	}
+	if trace, ok := crash["last_trace"].(string); ok && trace != "" {
+		fmt.Println("📜 Last panic trace:")
+		fmt.Println(strings.TrimRight(trace, "\n"))
+	}
+}
+
 // printFeatureStatus prints database and socket status
 func printFeatureStatus(data map[string]interface{}) {
 	if dbEnabled, ok := data["database_enabled"].(bool); ok {
@@ -118,6 +146,63 @@ func printFeatureStatus(data map[string]interface{}) {
 		}
 		fmt.Printf("🔌 Socket Server: %s\n", status)
 	}
+
+	if dryRun, ok := data["dry_run"].(bool); ok && dryRun {
+		fmt.Println("🧪 Dry-Run: ENABLED (no API mutations)")
+		printDryRunPending(data)
+	}
+}
+
+// printDryRunPending prints the changes a dry-run watcher would have synced
+func printDryRunPending(data map[string]interface{}) {
+	pending, ok := data["dry_run_pending"].([]interface{})
+	if !ok {
+		return
+	}
+	if len(pending) == 0 {
+		fmt.Println("   No pending changes")
+		return
+	}
+
+	fmt.Printf("   Pending changes (%d):\n", len(pending))
+	for _, syncInterface := range pending {
+		syncMap, ok := syncInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		eventName, _ := syncMap["event"].(string)
+		challenge, _ := syncMap["challenge_name"].(string)
+		action, _ := syncMap["action"].(string)
+		updateType, _ := syncMap["update_type"].(string)
+		fmt.Printf("   - [%s] %s → %s (%s)\n", eventName, challenge, action, updateType)
+	}
+}
+
+// PrintPausedSyncs prints the paused challenges and events of the
+// paused_syncs field of a status response, if any
+func PrintPausedSyncs(data interface{}) {
+	paused, ok := data.([]interface{})
+	if !ok || len(paused) == 0 {
+		return
+	}
+
+	fmt.Printf("⏸️  Paused Syncs (%d):\n", len(paused))
+	for _, pauseInterface := range paused {
+		pause, ok := pauseInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		eventName, _ := pause["event"].(string)
+		challenge, _ := pause["challenge"].(string)
+		if challenge == "" {
+			challenge = "all challenges"
+		}
+		line := fmt.Sprintf("   - [%s] %s since %s", eventName, challenge, formatDateTime(pause["paused_at"]))
+		if reason, _ := pause["reason"].(string); reason != "" {
+			line += ": " + reason
+		}
+		fmt.Println(line)
+	}
 }
 
 // printActiveScripts prints active interval scripts
@@ -172,6 +257,7 @@ func (c *Client) PrintStatus() error {
 
 	printStatusInfo(response.Data)
 	printFeatureStatus(response.Data)
+	PrintPausedSyncs(response.Data["paused_syncs"])
 	printActiveScripts(response.Data)
 	printAvailableCommands()
 
@@ -432,3 +518,277 @@ func (c *Client) PrintMetrics() error {
 
 	return nil
 }
+
+// PrintDryRunSyncs prints syncs computed by a dry-run watcher, including their diffs
+func (c *Client) PrintDryRunSyncs(event string, limit int) error {
+	response, err := c.GetDryRunSyncs(event, limit)
+	if err != nil {
+		return fmt.Errorf("failed to get dry-run syncs: %w", err)
+	}
+
+	if !response.Success {
+		return fmt.Errorf("get dry-run syncs request failed: %s", response.Error)
+	}
+
+	fmt.Printf("🧪 Dry-Run Syncs (last %d entries)\n", limit)
+	fmt.Println("==========================================")
+
+	data, ok := response.Data["syncs"].([]interface{})
+	if !ok || len(data) == 0 {
+		fmt.Println("No dry-run syncs recorded.")
+		return nil
+	}
+
+	for _, syncInterface := range data {
+		syncMap, ok := syncInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		timestamp := formatTimestamp(syncMap["timestamp"])
+		eventName, _ := syncMap["event"].(string)
+		challenge, _ := syncMap["challenge_name"].(string)
+		action, _ := syncMap["action"].(string)
+		if updateType, ok := syncMap["update_type"].(string); ok && updateType != "" {
+			action += " (" + updateType + ")"
+		}
+
+		fmt.Printf("[%s] [%s] %s → %s\n", timestamp, eventName, challenge, action)
+		if diff, ok := syncMap["diff"].(string); ok && diff != "" {
+			for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// PrintImageScans prints image scans run before full redeploys; verbose
+// includes the scanner output
+func (c *Client) PrintImageScans(event, challengeName string, limit int, verbose bool) error {
+	response, err := c.GetImageScans(event, challengeName, limit)
+	if err != nil {
+		return fmt.Errorf("failed to get image scans: %w", err)
+	}
+
+	if !response.Success {
+		return fmt.Errorf("get image scans request failed: %s", response.Error)
+	}
+
+	fmt.Printf("🔍 Image Scans (last %d entries)\n", limit)
+	fmt.Println("==========================================")
+
+	data, ok := response.Data["scans"].([]interface{})
+	if !ok || len(data) == 0 {
+		fmt.Println("No image scans recorded.")
+		return nil
+	}
+
+	for _, scanInterface := range data {
+		scanMap, ok := scanInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		timestamp := formatTimestamp(scanMap["timestamp"])
+		eventName, _ := scanMap["event"].(string)
+		challenge, _ := scanMap["challenge_name"].(string)
+		fmt.Printf("[%s] [%s] %s → %s\n", timestamp, eventName, challenge, formatImageScan(scanMap))
+		if output, ok := scanMap["output"].(string); verbose && ok && output != "" {
+			for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// formatImageScan summarizes an image scan as "image: status[, blocked]"
+func formatImageScan(scan map[string]interface{}) string {
+	image, _ := scan["image"].(string)
+	status, _ := scan["status"].(string)
+	summary := fmt.Sprintf("%s: %s", image, status)
+	if code, ok := scan["exit_code"].(float64); ok && status != "passed" {
+		summary += fmt.Sprintf(" (exit %.0f)", code)
+	}
+	if blocked, _ := scan["blocked"].(bool); blocked {
+		summary += ", deployment blocked"
+	}
+	return summary
+}
+
+// PrintSearchResults prints watcher log entries and script output matching a full-text search
+func (c *Client) PrintSearchResults(query string, raw bool, since time.Time, limit int) error {
+	response, err := c.SearchLogs(query, raw, since, limit)
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	if !response.Success {
+		return fmt.Errorf("search logs request failed: %s", response.Error)
+	}
+
+	fmt.Printf("🔎 Matches for %q since %s\n", query, since.Format(time.DateTime))
+	fmt.Println("==========================================")
+
+	data, ok := response.Data["results"].([]interface{})
+	if !ok || len(data) == 0 {
+		fmt.Println("No matches found.")
+		return nil
+	}
+
+	for _, resultInterface := range data {
+		result, ok := resultInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		timestamp := ""
+		if ts, ok := result["timestamp"].(string); ok {
+			if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+				timestamp = parsed.Local().Format(time.DateTime)
+			}
+		}
+		source, _ := result["source"].(string)
+		challenge, _ := result["challenge"].(string)
+		script, _ := result["script"].(string)
+		status, _ := result["status"].(string)
+		snippet, _ := result["snippet"].(string)
+
+		icon := "📋"
+		if source == "script" {
+			icon = "⚙️"
+		}
+		location := challenge
+		if script != "" {
+			location += "/" + script
+		}
+		fmt.Printf("[%s] %s %s %s\n", timestamp, icon, status, location)
+		for _, line := range strings.Split(strings.TrimSpace(snippet), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+
+	fmt.Printf("\n%d match(es)\n", len(data))
+	return nil
+}
+
+// PrintChallengeStatus prints the sync state of a challenge in an event
+func (c *Client) PrintChallengeStatus(event, challengeName string) error {
+	response, err := c.ChallengeStatus(event, challengeName)
+	if err != nil {
+		return fmt.Errorf("failed to get challenge status: %w", err)
+	}
+
+	if !response.Success {
+		return fmt.Errorf("challenge status request failed: %s", response.Error)
+	}
+
+	status, ok := response.Data["challenge"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("challenge status response has no challenge")
+	}
+
+	fmt.Printf("📋 Challenge %s [%s]\n", challengeName, event)
+	fmt.Println("==========================================")
+
+	state, _ := status["status"].(string)
+	if state == "" {
+		state = "unknown"
+	}
+	fmt.Printf("Status:         %s\n", state)
+	if at := formatDateTime(status["last_update"]); at != "" {
+		fmt.Printf("Updated:        %s\n", at)
+	}
+	if at := formatDateTime(status["last_sync"]); at != "" {
+		result, _ := status["last_sync_status"].(string)
+		fmt.Printf("Last sync:      %s (%s)\n", at, result)
+	} else {
+		fmt.Println("Last sync:      never")
+	}
+	if lastError, _ := status["last_error"].(string); lastError != "" {
+		if at := formatDateTime(status["last_error_at"]); at != "" {
+			fmt.Printf("Last error:     %s at %s\n", lastError, at)
+		} else {
+			fmt.Printf("Last error:     %s\n", lastError)
+		}
+	}
+	if stats, ok := status["stats"].(map[string]interface{}); ok {
+		syncs, _ := stats["syncs"].(float64)
+		failures, _ := stats["failures"].(float64)
+		rate, _ := stats["failure_rate"].(float64)
+		avg, _ := stats["avg_duration_ms"].(float64)
+		fmt.Printf("Syncs:          %.0f (%.0f failed, %.1f%%), avg %s\n", syncs, failures, rate*100,
+			(time.Duration(avg) * time.Millisecond).Round(100*time.Millisecond))
+	}
+	if paused, _ := status["paused"].(bool); paused {
+		fmt.Println("Paused:         yes (resume with 'gzcli watch resume')")
+	}
+	if held, _ := status["held_update"].(string); held != "" {
+		fmt.Printf("Held sync:      %s (live lock, approve with 'gzcli watch approve')\n", held)
+	}
+	if scan, ok := status["last_image_scan"].(map[string]interface{}); ok {
+		fmt.Printf("Image scan:     %s at %s\n", formatImageScan(scan), formatDateTime(scan["timestamp"]))
+	}
+	if run, ok := status["last_solver_run"].(map[string]interface{}); ok {
+		result, _ := run["status"].(string)
+		if runError, _ := run["error"].(string); runError != "" {
+			result += " (" + runError + ")"
+		}
+		fmt.Printf("Solver:         %s at %s\n", result, formatDateTime(run["timestamp"]))
+	}
+	if id, ok := status["mapping_id"].(float64); ok && id > 0 {
+		fmt.Printf("Challenge ID:   %.0f\n", id)
+	} else {
+		fmt.Println("Challenge ID:   not mapped")
+	}
+
+	var scriptNames []string
+	if scripts, ok := status["active_scripts"].([]interface{}); ok {
+		for _, script := range scripts {
+			if name, ok := script.(string); ok {
+				scriptNames = append(scriptNames, name)
+			}
+		}
+	}
+	if len(scriptNames) > 0 {
+		fmt.Printf("Active scripts: %s\n", strings.Join(scriptNames, ", "))
+	} else {
+		fmt.Println("Active scripts: none")
+	}
+
+	return nil
+}
+
+// formatDateTime formats an RFC 3339 timestamp in local time, or "" if ts is not one
+func formatDateTime(ts interface{}) string {
+	s, ok := ts.(string)
+	if !ok {
+		return ""
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return parsed.Local().Format("2006-01-02 15:04:05")
+}
+
+// PrintLogEntries prints watcher log entries one per line, as streamed by FollowLogs
+func (c *Client) PrintLogEntries(logs []watchertypes.WatcherLog) {
+	for _, entry := range logs {
+		challenge := ""
+		if entry.Challenge != "" {
+			challenge = fmt.Sprintf(" [%s]", entry.Challenge)
+			if entry.Script != "" {
+				challenge = fmt.Sprintf(" [%s/%s]", entry.Challenge, entry.Script)
+			}
+		}
+		message := entry.Message
+		if entry.Error != "" {
+			message = fmt.Sprintf("%s: %s", message, entry.Error)
+		}
+		fmt.Printf("[%s] %s %s%s %s\n", entry.Timestamp.Local().Format(time.DateTime), getLevelIcon(entry.Level), entry.Component, challenge, message)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -28,6 +29,18 @@ type CommandHandler interface {
 	HandleCommand(cmd watchertypes.WatcherCommand) watchertypes.WatcherResponse
 }
 
+// StreamCommandHandler is implemented by command handlers answering some
+// commands with a stream of responses. The stream ends when the handler
+// returns, the client disconnects or the server stops, which cancels ctx.
+type StreamCommandHandler interface {
+	CommandHandler
+	IsStreamCommand(cmd watchertypes.WatcherCommand) bool
+	HandleStreamCommand(ctx context.Context, cmd watchertypes.WatcherCommand, send func(watchertypes.WatcherResponse) error)
+}
+
+// streamWriteTimeout bounds each write of a streamed response to a client
+const streamWriteTimeout = 30 * time.Second
+
 // NewServer creates a new socket server
 func NewServer(socketPath string, enabled bool, handler CommandHandler) *Server {
 	return &Server{
@@ -129,13 +142,13 @@ func (s *Server) Run(ctx context.Context) {
 			}
 
 			// Handle connection in goroutine
-			go s.handleConnection(conn)
+			go s.handleConnection(ctx, conn)
 		}
 	}
 }
 
 // handleConnection handles a single socket connection
-func (s *Server) handleConnection(conn net.Conn) {
+func (s *Server) handleConnection(ctx context.Context, conn net.Conn) {
 	defer func() {
 		_ = conn.Close()
 	}()
@@ -156,6 +169,11 @@ func (s *Server) handleConnection(conn net.Conn) {
 		return
 	}
 
+	if stream, ok := s.handler.(StreamCommandHandler); ok && stream.IsStreamCommand(cmd) {
+		s.streamResponses(ctx, conn, stream, cmd)
+		return
+	}
+
 	// Process command using handler
 	response := s.handler.HandleCommand(cmd)
 
@@ -165,6 +183,26 @@ func (s *Server) handleConnection(conn net.Conn) {
 	}
 }
 
+// streamResponses sends the responses of a stream command as they come,
+// until the handler returns or the client hangs up
+func (s *Server) streamResponses(ctx context.Context, conn net.Conn, stream StreamCommandHandler, cmd watchertypes.WatcherCommand) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Clients send nothing more; a read returns once they hang up
+	_ = conn.SetReadDeadline(time.Time{})
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		cancel()
+	}()
+
+	encoder := json.NewEncoder(conn)
+	stream.HandleStreamCommand(ctx, cmd, func(response watchertypes.WatcherResponse) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return encoder.Encode(response)
+	})
+}
+
 // IsEnabled returns whether the socket server is enabled
 func (s *Server) IsEnabled() bool {
 	return s.enabled
@@ -0,0 +1,296 @@
+// Package timeline renders the watcher's sync and git pull activity as a
+// text summary, JSON, or a self-contained HTML Gantt chart
+package timeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+// Supported output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+// gitLane is the lane name used for git pulls, which have no challenge
+const gitLane = "git pull"
+
+// Lane groups the activity of one challenge (or of git pulls) in a timeline
+type Lane struct {
+	Name     string
+	Activity []watchertypes.SyncActivity
+}
+
+// Timeline is sync activity grouped into lanes over a time window
+type Timeline struct {
+	Since time.Time
+	Until time.Time
+	Lanes []Lane
+}
+
+// Build groups activity into lanes: git pulls first, then challenges by
+// name, each lane ordered by start time
+func Build(activity []watchertypes.SyncActivity, since, until time.Time) *Timeline {
+	byLane := make(map[string][]watchertypes.SyncActivity)
+	for _, a := range activity {
+		name := laneName(a)
+		byLane[name] = append(byLane[name], a)
+	}
+
+	names := make([]string, 0, len(byLane))
+	for name := range byLane {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == gitLane) != (names[j] == gitLane) {
+			return names[i] == gitLane
+		}
+		return names[i] < names[j]
+	})
+
+	tl := &Timeline{Since: since, Until: until}
+	for _, name := range names {
+		entries := byLane[name]
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartedAt.Before(entries[j].StartedAt) })
+		tl.Lanes = append(tl.Lanes, Lane{Name: name, Activity: entries})
+	}
+	return tl
+}
+
+func laneName(a watchertypes.SyncActivity) string {
+	if a.Kind == watchertypes.ActivityGitPull {
+		return gitLane
+	}
+	name := a.Challenge
+	if a.Event != "" {
+		name = a.Event + "/" + name
+	}
+	return name
+}
+
+// DecodeActivity converts the "activity" payload of a get_sync_activity
+// socket response back into typed entries
+func DecodeActivity(data interface{}) ([]watchertypes.SyncActivity, error) {
+	if data == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode activity: %w", err)
+	}
+	var activity []watchertypes.SyncActivity
+	if err := json.Unmarshal(raw, &activity); err != nil {
+		return nil, fmt.Errorf("failed to decode activity: %w", err)
+	}
+	return activity, nil
+}
+
+// Render writes the timeline in the given format
+func (tl *Timeline) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatText, "":
+		return tl.renderText(w)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tl.entries())
+	case FormatHTML:
+		return tl.renderHTML(w)
+	default:
+		return fmt.Errorf("unsupported format %q (expected text, json or html)", format)
+	}
+}
+
+func (tl *Timeline) entries() []watchertypes.SyncActivity {
+	entries := []watchertypes.SyncActivity{}
+	for _, lane := range tl.Lanes {
+		entries = append(entries, lane.Activity...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartedAt.Before(entries[j].StartedAt) })
+	return entries
+}
+
+func (tl *Timeline) renderText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🕒 Sync Timeline (%s → %s)\n", tl.Since.Format(time.DateTime), tl.Until.Format(time.DateTime))
+	b.WriteString("==========================================\n")
+
+	if len(tl.Lanes) == 0 {
+		b.WriteString("No sync activity recorded.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	for _, lane := range tl.Lanes {
+		failed := 0
+		for _, a := range lane.Activity {
+			if a.Status != "success" {
+				failed++
+			}
+		}
+		fmt.Fprintf(&b, "\n%s (%d runs, %d failed)\n", lane.Name, len(lane.Activity), failed)
+		for _, a := range lane.Activity {
+			icon := "✅"
+			if a.Status != "success" {
+				icon = "❌"
+			}
+			fmt.Fprintf(&b, "  %s %s  %8s", icon, a.StartedAt.Format(time.DateTime), formatDuration(a.EndedAt.Sub(a.StartedAt)))
+			if a.Error != "" {
+				fmt.Fprintf(&b, "  %s", firstLine(a.Error))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type htmlBar struct {
+	Left  string
+	Width string
+	Class string
+	Title string
+}
+
+type htmlLane struct {
+	Name   string
+	Bars   []htmlBar
+	Runs   int
+	Failed int
+}
+
+type htmlTick struct {
+	Left  string
+	Label string
+}
+
+func (tl *Timeline) renderHTML(w io.Writer) error {
+	span := tl.Until.Sub(tl.Since)
+	if span <= 0 {
+		span = time.Second
+	}
+	percent := func(t time.Time) float64 {
+		p := float64(t.Sub(tl.Since)) / float64(span) * 100
+		return min(max(p, 0), 100)
+	}
+
+	lanes := make([]htmlLane, 0, len(tl.Lanes))
+	for _, lane := range tl.Lanes {
+		hl := htmlLane{Name: lane.Name, Runs: len(lane.Activity)}
+		for _, a := range lane.Activity {
+			left := percent(a.StartedAt)
+			// Keep very short runs visible
+			width := max(percent(a.EndedAt)-left, 0.15)
+
+			class := "ok"
+			if a.Status != "success" {
+				class = "failed"
+				hl.Failed++
+			}
+			if a.Kind == watchertypes.ActivityGitPull {
+				class = "git " + class
+			}
+
+			title := fmt.Sprintf("%s – %s (%s) %s", a.StartedAt.Format(time.DateTime), a.EndedAt.Format(time.TimeOnly), formatDuration(a.EndedAt.Sub(a.StartedAt)), a.Status)
+			if a.Error != "" {
+				title += "\n" + a.Error
+			}
+			hl.Bars = append(hl.Bars, htmlBar{
+				Left:  fmt.Sprintf("%.3f%%", left),
+				Width: fmt.Sprintf("%.3f%%", width),
+				Class: class,
+				Title: title,
+			})
+		}
+		lanes = append(lanes, hl)
+	}
+
+	const tickCount = 6
+	ticks := make([]htmlTick, 0, tickCount+1)
+	for i := 0; i <= tickCount; i++ {
+		t := tl.Since.Add(span * time.Duration(i) / tickCount)
+		ticks = append(ticks, htmlTick{
+			Left:  fmt.Sprintf("%.3f%%", float64(i)/tickCount*100),
+			Label: t.Format("01-02 15:04"),
+		})
+	}
+
+	return htmlTemplate.Execute(w, map[string]interface{}{
+		"Since": tl.Since.Format(time.DateTime),
+		"Until": tl.Until.Format(time.DateTime),
+		"Lanes": lanes,
+		"Ticks": ticks,
+	})
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+var htmlTemplate = template.Must(template.New("timeline").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>gzcli sync timeline</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 24px; color: #222; }
+h1 { font-size: 20px; margin-bottom: 4px; }
+.range { color: #666; margin-bottom: 16px; }
+.legend span { display: inline-block; margin-right: 16px; }
+.legend i { display: inline-block; width: 12px; height: 12px; margin-right: 4px; vertical-align: middle; }
+.chart { display: grid; grid-template-columns: 240px 1fr; row-gap: 4px; margin-top: 16px; }
+.name { font-size: 13px; padding-right: 8px; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; line-height: 22px; }
+.name small { color: #888; }
+.track { position: relative; height: 22px; background: #f3f3f3; border-radius: 3px; }
+.bar { position: absolute; top: 3px; bottom: 3px; border-radius: 2px; }
+.ok { background: #2e9d5b; }
+.failed { background: #d64545; }
+.git.ok { background: #3c78d8; }
+.git.failed { background: #e69138; }
+.axis { position: relative; height: 20px; font-size: 11px; color: #666; }
+.axis span { position: absolute; transform: translateX(-50%); white-space: nowrap; }
+.empty { color: #666; }
+</style>
+</head>
+<body>
+<h1>Sync timeline</h1>
+<div class="range">{{.Since}} → {{.Until}}</div>
+<div class="legend">
+<span><i class="ok"></i>sync</span>
+<span><i class="failed"></i>failed sync</span>
+<span><i class="git ok"></i>git pull</span>
+<span><i class="git failed"></i>failed git pull</span>
+</div>
+{{if .Lanes}}
+<div class="chart">
+{{range .Lanes}}<div class="name" title="{{.Name}}">{{.Name}} <small>{{.Runs}} runs{{if .Failed}}, {{.Failed}} failed{{end}}</small></div>
+<div class="track">{{range .Bars}}<div class="bar {{.Class}}" style="left: {{.Left}}; width: {{.Width}}" title="{{.Title}}"></div>{{end}}</div>
+{{end}}<div></div>
+<div class="axis">{{range .Ticks}}<span style="left: {{.Left}}">{{.Label}}</span>{{end}}</div>
+</div>
+{{else}}
+<p class="empty">No sync activity recorded.</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,115 @@
+package timeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/gzcli/internal/gzcli/watcher/watchertypes"
+)
+
+func sampleActivity(base time.Time) []watchertypes.SyncActivity {
+	return []watchertypes.SyncActivity{
+		{Event: "ctf", Challenge: "web1", Kind: watchertypes.ActivitySync, Status: "success", StartedAt: base.Add(2 * time.Hour), EndedAt: base.Add(2*time.Hour + 3*time.Second)},
+		{Event: "ctf", Kind: watchertypes.ActivityGitPull, Status: "success", StartedAt: base.Add(time.Hour), EndedAt: base.Add(time.Hour + time.Second)},
+		{Event: "ctf", Challenge: "pwn1", Kind: watchertypes.ActivitySync, Status: "failed", StartedAt: base.Add(3 * time.Hour), EndedAt: base.Add(3*time.Hour + 500*time.Millisecond), Error: "upload failed\ndetails"},
+		{Event: "ctf", Challenge: "web1", Kind: watchertypes.ActivitySync, Status: "success", StartedAt: base.Add(30 * time.Minute), EndedAt: base.Add(31 * time.Minute)},
+	}
+}
+
+func TestBuild_GroupsLanes(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tl := Build(sampleActivity(base), base, base.Add(4*time.Hour))
+
+	var names []string
+	for _, lane := range tl.Lanes {
+		names = append(names, lane.Name)
+	}
+	want := []string{gitLane, "ctf/pwn1", "ctf/web1"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("lanes = %v, want %v", names, want)
+	}
+
+	web := tl.Lanes[2].Activity
+	if len(web) != 2 || !web[0].StartedAt.Before(web[1].StartedAt) {
+		t.Errorf("web1 lane not ordered by start time: %+v", web)
+	}
+}
+
+func TestRender_Formats(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tl := Build(sampleActivity(base), base, base.Add(4*time.Hour))
+
+	var text bytes.Buffer
+	if err := tl.Render(&text, FormatText); err != nil {
+		t.Fatalf("text render failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "ctf/pwn1 (1 runs, 1 failed)") || !strings.Contains(text.String(), "upload failed") {
+		t.Errorf("unexpected text output:\n%s", text.String())
+	}
+	if strings.Contains(text.String(), "details") {
+		t.Error("text output should only show the first line of an error")
+	}
+
+	var out bytes.Buffer
+	if err := tl.Render(&out, FormatJSON); err != nil {
+		t.Fatalf("json render failed: %v", err)
+	}
+	var decoded []watchertypes.SyncActivity
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("json output does not decode: %v", err)
+	}
+	if len(decoded) != 4 || decoded[0].Challenge != "web1" {
+		t.Errorf("json output not ordered by start time: %+v", decoded)
+	}
+
+	var page bytes.Buffer
+	if err := tl.Render(&page, FormatHTML); err != nil {
+		t.Fatalf("html render failed: %v", err)
+	}
+	html := page.String()
+	for _, want := range []string{"<!DOCTYPE html>", `class="bar git ok"`, `class="bar failed"`, "left: 50.000%", "ctf/web1"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html output missing %q", want)
+		}
+	}
+
+	if err := tl.Render(&out, "csv"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	now := time.Now()
+	tl := Build(nil, now.Add(-time.Hour), now)
+
+	var page bytes.Buffer
+	if err := tl.Render(&page, FormatHTML); err != nil {
+		t.Fatalf("html render failed: %v", err)
+	}
+	if !strings.Contains(page.String(), "No sync activity recorded.") {
+		t.Error("expected empty-state message in html output")
+	}
+}
+
+func TestDecodeActivity(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	raw, err := json.Marshal(sampleActivity(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		t.Fatal(err)
+	}
+
+	activity, err := DecodeActivity(generic)
+	if err != nil {
+		t.Fatalf("DecodeActivity failed: %v", err)
+	}
+	if len(activity) != 4 || !activity[0].StartedAt.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("unexpected decoded activity: %+v", activity)
+	}
+}
@@ -7,8 +7,9 @@ import (
 
 // WatcherConfig holds configuration for the watcher
 type WatcherConfig struct {
-	Events                    []string // Event names to watch (empty means use current event)
-	PollInterval              time.Duration
+	Events                    []string      // Event names to watch (empty means use current event)
+	PollInterval              time.Duration // Interval between scans of challenge directories when polling
+	Polling                   bool          // Poll challenge directories instead of using inotify, e.g. on network filesystems
 	DebounceTime              time.Duration
 	IgnorePatterns            []string
 	WatchPatterns             []string
@@ -19,12 +20,36 @@ type WatcherConfig struct {
 	GitPullEnabled            bool          // Enable automatic git pull
 	GitPullInterval           time.Duration // Interval for git pull (default: 1 minute)
 	GitRepository             string        // Git repository path (default: current directory)
+	DryRun                    bool          // Compute and log syncs without mutating the GZCTF API
+	FormatYaml                bool          // Normalize challenge.yaml after successful syncs
+	// Git commit configuration (opt-in)
+	GitCommitEnabled bool     // Commit generated files after successful syncs
+	GitCommitBranch  string   // Dedicated branch receiving the commits (default: gzcli/generated)
+	GitCommitMessage string   // Commit message template (.Event, .Challenge, .Files, .Time)
+	GitCommitPaths   []string // Paths relative to each challenge directory to commit (default: dist)
+	GitPushEnabled   bool     // Push the commit branch after committing
+	GitPushRemote    string   // Remote to push to (default: origin)
+	// Image scanning before full redeploys (opt-in)
+	ImageScanEnabled bool   // Scan the container image before each full redeploy
+	ImageScanCommand string // Scan command template (.Image, .Event, .Challenge); non-zero exit means findings
+	ImageScanBlock   bool   // Abort the redeploy on findings or scanner errors instead of warning
+	// Announcements of newly created challenges (opt-in)
+	AnnounceWebhook string // Webhook receiving a payload per new challenge (empty disables; Discord URLs get an embed)
+	// Restricted mode during the game (opt-in)
+	LiveLock bool // Between the .gzevent start and end, hold attachment updates and redeploys until approved
+	// Mirroring to the other servers of conf.yaml
+	Targets []string // Names or tags of the targets to mirror to (empty means the targets selected by each .gzevent)
 	// Database configuration
 	DatabaseEnabled bool   // Enable database logging
 	DatabasePath    string // SQLite database file path
+	DatabaseURL     string // Postgres URL of a database shared by several hosts, used instead of DatabasePath
 	// Socket configuration
 	SocketEnabled bool   // Enable socket server
 	SocketPath    string // Unix socket path for communication
+	// Launcher integration
+	LauncherSocketPath string // Launcher socket notified when challenges are removed (empty disables)
+	// Monitoring
+	MetricsAddr string // Serve Prometheus metrics at http://<addr>/metrics (empty disables)
 }
 
 // DefaultWatcherConfig provides default configuration values
@@ -40,10 +65,16 @@ var DefaultWatcherConfig = WatcherConfig{
 	GitPullEnabled:            true,            // Enable git pull by default
 	GitPullInterval:           1 * time.Minute, // Pull every minute
 	GitRepository:             ".",             // Current directory
+	GitCommitEnabled:          false,           // Opt-in
+	GitCommitBranch:           "gzcli/generated",
+	GitCommitPaths:            []string{"dist"},
+	GitPushRemote:             "origin",
 	// Database defaults
 	DatabaseEnabled: true, // Enable database logging by default
 	DatabasePath:    ".gzcli/watcher/watcher.db",
 	// Socket defaults
 	SocketEnabled: true, // Enable socket server by default
 	SocketPath:    ".gzcli/watcher/watcher.sock",
+	// Launcher defaults
+	LauncherSocketPath: ".gzcli/launcher/launcher.sock",
 }
@@ -20,9 +20,21 @@ type WatcherLog struct {
 	Duration  int64     `json:"duration,omitempty"` // milliseconds
 }
 
+// Log levels of watcher log entries, from the least severe
+var LogLevels = []string{"INFO", "WARN", "ERROR"}
+
+// LogFilter selects watcher log entries
+type LogFilter struct {
+	AfterID   int64  `json:"after_id,omitempty"`  // Only entries written after this one, else the latest Limit entries
+	Level     string `json:"level,omitempty"`     // Minimum level, one of LogLevels
+	Challenge string `json:"challenge,omitempty"` // Only entries of this challenge
+	Limit     int    `json:"limit,omitempty"`
+}
+
 // ChallengeState represents the state of a challenge in the database
 type ChallengeState struct {
 	ID            int64     `json:"id"`
+	Event         string    `json:"event"`
 	ChallengeName string    `json:"challenge_name"`
 	Status        string    `json:"status"` // watching, updating, deploying, error
 	LastUpdate    time.Time `json:"last_update"`
@@ -45,3 +57,187 @@ type ScriptExecution struct {
 	ExitCode      int       `json:"exit_code,omitempty"`
 	Success       bool      `json:"success"` // computed field based on status and exit code
 }
+
+// DryRunSync represents a sync computed in dry-run mode but not applied
+type DryRunSync struct {
+	ID            int64     `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Event         string    `json:"event"`
+	ChallengeName string    `json:"challenge_name"`
+	Action        string    `json:"action"`                // create, update, none
+	UpdateType    string    `json:"update_type,omitempty"` // metadata, attachment, full_redeploy
+	ChallengeID   int       `json:"challenge_id,omitempty"`
+	Diff          string    `json:"diff,omitempty"`
+}
+
+// ImageScan is a vulnerability scan of a challenge image run before a full redeploy
+type ImageScan struct {
+	ID            int64     `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Event         string    `json:"event"`
+	ChallengeName string    `json:"challenge_name"`
+	Image         string    `json:"image"`
+	Command       string    `json:"command"`
+	Status        string    `json:"status"`  // passed, findings, error
+	Blocked       bool      `json:"blocked"` // Whether the scan stopped the deployment
+	ExitCode      int       `json:"exit_code"`
+	Output        string    `json:"output,omitempty"`
+	Duration      int64     `json:"duration,omitempty"` // milliseconds
+}
+
+// SolverRun is a run of a challenge's solver against the deployed challenge
+// after a sync
+type SolverRun struct {
+	ID            int64     `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Event         string    `json:"event"`
+	ChallengeName string    `json:"challenge_name"`
+	Target        string    `json:"target"` // host:port passed to the solver
+	Status        string    `json:"status"` // passed, failed, error
+	ExitCode      int       `json:"exit_code"`
+	Error         string    `json:"error,omitempty"`
+	Output        string    `json:"output,omitempty"`
+	Duration      int64     `json:"duration,omitempty"` // milliseconds
+}
+
+// Sources of deployments
+const (
+	DeploymentSourceSync    = "sync"
+	DeploymentSourceWatcher = "watcher"
+)
+
+// Deployment records what a sync deployed to GZCTF, for post-event audits.
+// Revision is a counter kept by gzcli, not a GZCTF value: it numbers the
+// distinct contents deployed to the challenge and only increases when the
+// challenge.yaml, attachment or image changed.
+type Deployment struct {
+	ID          int64     `json:"id"`
+	Timestamp   time.Time `json:"timestamp"`
+	Event       string    `json:"event"`
+	Challenge   string    `json:"challenge"`
+	Category    string    `json:"category"`
+	ChallengeID int       `json:"challenge_id"`
+	Revision    int       `json:"revision"`
+	YamlHash    string    `json:"yaml_hash"`
+	DistHash    string    `json:"dist_hash,omitempty"`
+	Image       string    `json:"image,omitempty"`
+	ImageDigest string    `json:"image_digest,omitempty"`
+	Source      string    `json:"source"` // sync or watcher
+}
+
+// SameContent reports whether d deployed the same files and image as other
+func (d Deployment) SameContent(other Deployment) bool {
+	return d.YamlHash == other.YamlHash && d.DistHash == other.DistHash &&
+		d.Image == other.Image && d.ImageDigest == other.ImageDigest
+}
+
+// PausedSync is a challenge, or a whole event, whose syncs are paused until
+// resumed
+type PausedSync struct {
+	Event     string    `json:"event"`
+	Challenge string    `json:"challenge,omitempty"` // Empty when the whole event is paused
+	Reason    string    `json:"reason,omitempty"`
+	PausedAt  time.Time `json:"paused_at"`
+}
+
+// Sync activity kinds
+const (
+	ActivitySync    = "sync"
+	ActivityGitPull = "git_pull"
+)
+
+// SyncActivity is one timed sync or git pull performed by the watcher
+type SyncActivity struct {
+	ID        int64     `json:"id"`
+	Event     string    `json:"event"`
+	Challenge string    `json:"challenge,omitempty"` // Empty for git pulls
+	Kind      string    `json:"kind"`                // sync, git_pull
+	Status    string    `json:"status"`              // success, failed
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// SyncStats aggregates the recorded syncs of one challenge, to spot flaky
+// challenges that repeatedly fail to deploy
+type SyncStats struct {
+	Event         string     `json:"event"`
+	Challenge     string     `json:"challenge"`
+	Syncs         int        `json:"syncs"`
+	Failures      int        `json:"failures"`
+	FailureRate   float64    `json:"failure_rate"` // Failures / Syncs, from 0 to 1
+	AvgDurationMs int64      `json:"avg_duration_ms"`
+	LastFailure   string     `json:"last_failure,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+}
+
+// ChallengeStatus is the sync state of one watched challenge
+type ChallengeStatus struct {
+	Event          string     `json:"event"`
+	Challenge      string     `json:"challenge"`
+	Status         string     `json:"status,omitempty"` // Empty until the challenge state was first recorded
+	LastUpdate     *time.Time `json:"last_update,omitempty"`
+	LastSync       *time.Time `json:"last_sync,omitempty"`
+	LastSyncStatus string     `json:"last_sync_status,omitempty"` // success, failed
+	LastError      string     `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time `json:"last_error_at,omitempty"`
+	ActiveScripts  []string   `json:"active_scripts"`
+	MappingID      int        `json:"mapping_id,omitempty"` // GZCTF challenge ID; 0 if not mapped yet
+	LastImageScan  *ImageScan `json:"last_image_scan,omitempty"`
+	LastSolverRun  *SolverRun `json:"last_solver_run,omitempty"`
+	HeldUpdate     string     `json:"held_update,omitempty"` // Update type held by the live lock until approved
+	Paused         bool       `json:"paused,omitempty"`      // Syncs of the challenge or its event are paused
+	Stats          *SyncStats `json:"stats,omitempty"`       // Nil until the challenge was first synced
+}
+
+// SyncProgress is the progress of a sync of all challenges of an event, such
+// as the one following a git pull that brought new commits
+type SyncProgress struct {
+	Event      string     `json:"event"`
+	Total      int        `json:"total"`
+	Done       int        `json:"done"`              // Synced, failed or skipped as unchanged
+	Failed     int        `json:"failed"`            // Included in Done
+	Current    []string   `json:"current,omitempty"` // Challenges syncing now
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	AverageMs  int64      `json:"average_ms"`  // Mean duration of the challenges synced so far
+	ETASeconds int64      `json:"eta_seconds"` // Estimated time left; -1 until a challenge is done
+}
+
+// SearchResult is a watcher log entry or script execution matching a full-text search
+type SearchResult struct {
+	Source    string    `json:"source"` // log, script
+	ID        int64     `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Challenge string    `json:"challenge,omitempty"`
+	Script    string    `json:"script,omitempty"`
+	Status    string    `json:"status,omitempty"` // Log level or script execution status
+	Snippet   string    `json:"snippet"`
+	Rank      float64   `json:"rank"` // bm25 score, lower is more relevant
+}
+
+// ChallengeMapping maps a challenge folder of an event to a GZCTF challenge
+type ChallengeMapping struct {
+	Folder      string `json:"folder"` // Relative to the event directory
+	ChallengeID int    `json:"challenge_id"`
+	Title       string `json:"title"`
+}
+
+// MaxNoticeAttempts is how many times the watcher tries to post a scheduled
+// notice before giving up on it
+const MaxNoticeAttempts = 5
+
+// ScheduledNotice is a game notice stored to be posted by the watcher at
+// PostAt. SentAt and NoticeID are set once it was posted; Error keeps the
+// last failed attempt, which is retried until FailedAt is set.
+type ScheduledNotice struct {
+	ID       int64      `json:"id"`
+	Event    string     `json:"event"`
+	Content  string     `json:"content"`
+	PostAt   time.Time  `json:"post_at"`
+	SentAt   *time.Time `json:"sent_at,omitempty"`
+	NoticeID int        `json:"notice_id,omitempty"` // GZCTF notice ID once posted
+	Error    string     `json:"error,omitempty"`
+	Attempts int        `json:"attempts,omitempty"`  // Failed attempts to post it
+	FailedAt *time.Time `json:"failed_at,omitempty"` // Set when the watcher gave up on it
+}
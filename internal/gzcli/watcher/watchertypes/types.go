@@ -2,6 +2,7 @@
 package watchertypes
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -43,3 +44,19 @@ const (
 	UpdateMetadata
 	UpdateFullRedeploy
 )
+
+// String returns the name used for the update type in logs and dry-run records
+func (u UpdateType) String() string {
+	switch u {
+	case UpdateNone:
+		return "none"
+	case UpdateAttachment:
+		return "attachment"
+	case UpdateMetadata:
+		return "metadata"
+	case UpdateFullRedeploy:
+		return "full_redeploy"
+	default:
+		return fmt.Sprintf("UpdateType(%d)", int(u))
+	}
+}
@@ -2,37 +2,72 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 
 	"github.com/fatih/color"
 )
 
 var debugMode = false
 
+// infoOut receives informational and debug messages; nil is stdout
+var infoOut io.Writer
+
 // SetDebugMode enables or disables debug logging
 func SetDebugMode(enabled bool) {
 	debugMode = enabled
 }
 
+// SetInfoOutput sets where informational and debug messages are written,
+// e.g. stderr to keep stdout for machine-readable output
+func SetInfoOutput(w io.Writer) {
+	infoOut = w
+}
+
+// fatalHook runs before Fatal exits, e.g. to record the failure
+var fatalHook func(message string)
+
+// errorCount counts the messages logged with Error
+var errorCount atomic.Int64
+
+// SetFatalHook sets a function called with the message of Fatal before the
+// program exits; nil removes it
+func SetFatalHook(fn func(message string)) {
+	fatalHook = fn
+}
+
+// ErrorCount returns the number of messages logged with Error so far
+func ErrorCount() int64 {
+	return errorCount.Load()
+}
+
+func infoWriter() io.Writer {
+	if infoOut != nil {
+		return infoOut
+	}
+	return os.Stdout
+}
+
 // Debug logs debug messages when debug mode is enabled
 func Debug(format string, elem ...any) {
 	if debugMode {
-		fmt.Println(color.CyanString("[DEBUG] ") + fmt.Sprintf(format, elem...))
+		fmt.Fprintln(infoWriter(), color.CyanString("[DEBUG] ")+fmt.Sprintf(format, elem...))
 	}
 }
 
 // DebugH2 logs indented debug messages when debug mode is enabled
 func DebugH2(format string, elem ...any) {
 	if debugMode {
-		fmt.Println(color.CyanString("  [DEBUG] ") + fmt.Sprintf(format, elem...))
+		fmt.Fprintln(infoWriter(), color.CyanString("  [DEBUG] ")+fmt.Sprintf(format, elem...))
 	}
 }
 
 // DebugH3 logs more indented debug messages when debug mode is enabled
 func DebugH3(format string, elem ...any) {
 	if debugMode {
-		fmt.Println(color.CyanString("    [DEBUG] ") + fmt.Sprintf(format, elem...))
+		fmt.Fprintln(infoWriter(), color.CyanString("    [DEBUG] ")+fmt.Sprintf(format, elem...))
 	}
 }
 
@@ -68,11 +103,15 @@ func Fatal(args ...interface{}) {
 	for _, line := range lines {
 		fmt.Fprintln(os.Stderr, color.RedString("[x] ")+line)
 	}
+	if fatalHook != nil {
+		fatalHook(strings.TrimSpace(message))
+	}
 	os.Exit(1)
 }
 
 // Error logs an error message to stderr
 func Error(str string, elem ...any) {
+	errorCount.Add(1)
 	fmt.Fprintln(os.Stderr, color.RedString("[x] ")+fmt.Sprintf(str, elem...))
 }
 
@@ -83,17 +122,17 @@ func ErrorH2(format string, elem ...any) {
 
 // Info logs an informational message
 func Info(format string, elem ...any) {
-	fmt.Println(color.BlueString("[x] ") + fmt.Sprintf(format, elem...))
+	fmt.Fprintln(infoWriter(), color.BlueString("[x] ")+fmt.Sprintf(format, elem...))
 }
 
 // InfoH2 logs an indented informational message
 func InfoH2(format string, elem ...any) {
-	fmt.Println(color.GreenString("  [x] ") + fmt.Sprintf(format, elem...))
+	fmt.Fprintln(infoWriter(), color.GreenString("  [x] ")+fmt.Sprintf(format, elem...))
 }
 
 // InfoH3 logs a double-indented informational message
 func InfoH3(format string, elem ...any) {
-	fmt.Println(color.YellowString("    [x] ") + fmt.Sprintf(format, elem...))
+	fmt.Fprintln(infoWriter(), color.YellowString("    [x] ")+fmt.Sprintf(format, elem...))
 }
 
 // SuccessDownload logs a successful challenge download
@@ -179,12 +179,15 @@ func processTemplate(fsys fileSystem, file string, info interface{}) (io.Reader,
 // Shared Function
 // ==================================================
 
+// writeContent creates destination with content, returning os.ErrExist
+// instead of overwriting an existing file
+//
 //nolint:gosec // G304: Destination path is constructed from validated template config
 func writeContent(destination string, content io.Reader) error {
 	destFile, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
 	if err != nil {
 		if os.IsExist(err) {
-			return fmt.Errorf("file already exists")
+			return os.ErrExist
 		}
 		return fmt.Errorf("failed to create file: %w", err)
 	}
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+# Solver for {{.Name}}
+#
+# Run against a deployed instance with HOST and PORT set, e.g.:
+#   HOST=127.0.0.1 PORT=1337 bash solve.sh
+# Print the flag to stdout and exit non-zero if it could not be recovered.
+set -euo pipefail
+
+HOST="${HOST:-127.0.0.1}"
+PORT="${PORT:-1337}"
+DEFAULT_FLAG_FORMAT='{{.FlagFormat}}'
+FLAG_FORMAT="${FLAG_FORMAT:-$DEFAULT_FLAG_FORMAT}"
+
+# TODO: exploit the challenge and print everything that may contain the flag
+solve() {
+	nc -w 5 "$HOST" "$PORT" </dev/null
+}
+
+if ! solve | grep -oE "$FLAG_FORMAT" | head -n 1 | grep .; then
+	echo "flag not found" >&2
+	exit 1
+fi
@@ -0,0 +1,40 @@
+#!/usr/bin/env python3
+# Solver for {{.Name}}
+#
+# Run against a deployed instance with HOST and PORT set, e.g.:
+#   HOST=127.0.0.1 PORT=1337 python3 solve.py
+# Print the flag to stdout and exit non-zero if it could not be recovered.
+import os
+import re
+import sys
+
+from pwn import context, remote
+
+HOST = os.environ.get("HOST", "127.0.0.1")
+PORT = int(os.environ.get("PORT", "1337"))
+FLAG_FORMAT = os.environ.get("FLAG_FORMAT", r"{{.FlagFormat}}")
+
+context.log_level = "error"
+
+
+def solve(io):
+    # TODO: exploit the challenge and return everything that may contain the flag
+    return io.recvall(timeout=5).decode(errors="replace")
+
+
+def main():
+    io = remote(HOST, PORT)
+    try:
+        output = solve(io)
+    finally:
+        io.close()
+
+    match = re.search(FLAG_FORMAT, output)
+    if not match:
+        print("flag not found", file=sys.stderr)
+        sys.exit(1)
+    print(match.group(0))
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,334 @@
+// Package client is a Go client of the launcher WebSocket API of challenge
+// pages (see the protocol package): it subscribes to the status, vote and
+// other events of an instance and sends start, restart, vote and extend
+// requests. It asks for the latest protocol version and falls back to v1
+// against servers that do not negotiate one.
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+// writeWait bounds the write of a request
+const writeWait = 10 * time.Second
+
+// ErrClosed is returned by requests once the connection is closed
+var ErrClosed = errors.New("connection closed")
+
+// Options configures Dial
+type Options struct {
+	Header  http.Header       // Extra handshake headers, e.g. a Cookie
+	Version int               // Highest protocol version to ask for; 0 is protocol.Latest
+	Dialer  *websocket.Dialer // nil uses websocket.DefaultDialer
+}
+
+// Event is a message of the server, with its data decoded by type
+type Event struct {
+	Type    string // One of the event types of the protocol package
+	Version int    // Protocol version of the message; 0 for v1
+	ID      string // ID of the request the event replies to, from v2 on
+	Message string // Text of info, error and removed events
+
+	Hello  *protocol.Hello
+	Status *protocol.Status
+	Stats  *protocol.Usage
+	TTL    *protocol.TTL
+	Gate   *protocol.Gate
+	Vote   *protocol.Vote // vote_started, vote_update and vote_ended
+
+	Data json.RawMessage // Undecoded data, e.g. of event types added later
+}
+
+// message is the envelope of the messages of both directions
+type message struct {
+	Type    string          `json:"type"`
+	Version int             `json:"v,omitempty"`
+	ID      string          `json:"id,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Client is a connection to the WebSocket API of a challenge instance
+type Client struct {
+	conn    *websocket.Conn
+	version int
+	events  chan Event
+	nextID  atomic.Uint64
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	err  error          // Why the connection ended
+	gate *protocol.Gate // Last gate sent by the server
+}
+
+// Dial connects to the challenge page at pageURL, e.g.
+// "https://launcher.example.com/ctf_web_login?team=<token>", or directly to
+// its ws:// or wss:// endpoint
+func Dial(ctx context.Context, pageURL string, opts Options) (*Client, error) {
+	wsURL, err := WebSocketURL(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	version := opts.Version
+	if version <= 0 || version > protocol.Latest {
+		version = protocol.Latest
+	}
+	dialer := websocket.DefaultDialer
+	if opts.Dialer != nil {
+		dialer = opts.Dialer
+	}
+	d := *dialer
+	d.Subprotocols = nil
+	for v := version; v >= protocol.V1; v-- {
+		d.Subprotocols = append(d.Subprotocols, protocol.Subprotocol(v))
+	}
+
+	conn, resp, err := d.DialContext(ctx, wsURL, opts.Header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w (HTTP %d)", wsURL, err, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", wsURL, err)
+	}
+
+	c := &Client{
+		conn:    conn,
+		version: protocol.ParseSubprotocol(conn.Subprotocol()),
+		events:  make(chan Event, 64),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// WebSocketURL returns the WebSocket endpoint of a challenge page URL,
+// keeping its query, e.g. the team token
+func WebSocketURL(pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid challenge page URL %q", pageURL)
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("unsupported scheme %q of %s", u.Scheme, pageURL)
+	}
+	if !strings.HasSuffix(u.Path, "/ws") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/ws"
+	}
+	return u.String(), nil
+}
+
+// Version returns the protocol version negotiated with the server
+func (c *Client) Version() int {
+	return c.version
+}
+
+// Events returns the events of the server. The channel is closed when the
+// connection ends, see Err. It must be drained, as the connection stops
+// being read while it is full.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Err returns why the connection ended, or nil while it is open or after
+// Close
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Gate returns the last gate sent by the server, or nil if the instance has
+// none
+func (c *Client) Gate() *protocol.Gate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gate
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	c.writeMu.Lock()
+	_ = c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeWait))
+	c.writeMu.Unlock()
+	return c.conn.Close()
+}
+
+// Ping asks the server for a pong event
+func (c *Client) Ping() (string, error) {
+	return c.send(protocol.TypePing, nil)
+}
+
+// Start asks the server to start the instance. gate is the solution of the
+// gate of the instance, if it has one, see SolveGate.
+func (c *Client) Start(gate string) (string, error) {
+	return c.send(protocol.TypeStart, protocol.StartRequest{Gate: gate})
+}
+
+// Restart starts a vote to restart the instance, voting yes. gate is the
+// solution of the gate of the instance, if it has one.
+func (c *Client) Restart(gate string) (string, error) {
+	return c.send(protocol.TypeRestart, protocol.StartRequest{Gate: gate})
+}
+
+// Vote votes in the running vote on action, protocol.ActionRestart or
+// protocol.ActionExtend
+func (c *Client) Vote(action string, yes bool) (string, error) {
+	value := protocol.VoteNo
+	if yes {
+		value = protocol.VoteYes
+	}
+	return c.send(protocol.TypeVote, protocol.VoteRequest{Value: value, Action: action})
+}
+
+// Extend starts a vote to extend the TTL of the instance, voting yes
+func (c *Client) Extend() (string, error) {
+	return c.send(protocol.TypeExtend, nil)
+}
+
+// SolveGate returns the solution of the last proof of work gate sent by the
+// server, or "" if the instance has no gate. CAPTCHA gates need a browser
+// and fail.
+func (c *Client) SolveGate() (string, error) {
+	gate := c.Gate()
+	switch {
+	case gate == nil:
+		return "", nil
+	case gate.Type == protocol.GateTypePoW:
+		return SolvePoW(gate.Challenge, gate.Difficulty), nil
+	default:
+		return "", fmt.Errorf("%s gates must be solved in a browser", gate.Type)
+	}
+}
+
+// SolvePoW returns a solution of a proof of work: a decimal number whose
+// SHA-256(challenge + ":" + solution) starts with difficulty zero bits
+func SolvePoW(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		solution := strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(challenge + ":" + solution))
+		if leadingZeroBits(sum[:]) >= difficulty {
+			return solution
+		}
+	}
+}
+
+func leadingZeroBits(sum []byte) int {
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			return zeros + bits.LeadingZeros8(b)
+		}
+		zeros += 8
+	}
+	return zeros
+}
+
+// send writes a request and returns its ID, "" with v1 servers which do not
+// reply with request IDs
+func (c *Client) send(msgType string, data any) (string, error) {
+	msg := message{Type: msgType}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return "", err
+		}
+		msg.Data = raw
+	}
+	if c.version >= protocol.V2 {
+		msg.Version = c.version
+		msg.ID = strconv.FormatUint(c.nextID.Add(1), 10)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		if errors.Is(err, websocket.ErrCloseSent) || errors.Is(err, net.ErrClosed) {
+			return "", ErrClosed
+		}
+		return "", fmt.Errorf("failed to send %s request: %w", msgType, err)
+	}
+	return msg.ID, nil
+}
+
+// readLoop decodes the messages of the server into events until the
+// connection ends
+func (c *Client) readLoop() {
+	defer close(c.events)
+	for {
+		var msg message
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) && !errors.Is(err, net.ErrClosed) {
+				c.mu.Lock()
+				c.err = err
+				c.mu.Unlock()
+			}
+			return
+		}
+		event, err := decodeEvent(msg)
+		if err != nil {
+			continue // Keep following the instance past a malformed event
+		}
+		if event.Gate != nil {
+			c.mu.Lock()
+			c.gate = event.Gate
+			c.mu.Unlock()
+		}
+		c.events <- event
+	}
+}
+
+// decodeEvent decodes the data of a message by its type
+func decodeEvent(msg message) (Event, error) {
+	event := Event{Type: msg.Type, Version: msg.Version, ID: msg.ID, Message: msg.Message, Data: msg.Data}
+	var target any
+	switch msg.Type {
+	case protocol.TypeHello:
+		event.Hello = &protocol.Hello{}
+		target = event.Hello
+	case protocol.TypeStatus:
+		event.Status = &protocol.Status{}
+		target = event.Status
+	case protocol.TypeStats:
+		event.Stats = &protocol.Usage{}
+		target = event.Stats
+	case protocol.TypeTTL:
+		event.TTL = &protocol.TTL{}
+		target = event.TTL
+	case protocol.TypeGate:
+		event.Gate = &protocol.Gate{}
+		target = event.Gate
+	case protocol.TypeVoteStarted, protocol.TypeVoteUpdate, protocol.TypeVoteEnded:
+		event.Vote = &protocol.Vote{}
+		target = event.Vote
+	}
+	if target != nil && len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, target); err != nil {
+			return event, fmt.Errorf("invalid %s event: %w", msg.Type, err)
+		}
+	}
+	return event, nil
+}
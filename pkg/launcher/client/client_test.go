@@ -0,0 +1,57 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/dimasma0305/gzcli/pkg/launcher/protocol"
+)
+
+func TestWebSocketURL(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:8080/ctf_web":             "ws://localhost:8080/ctf_web/ws",
+		"https://launcher.example.com/ctf_web/?t=a": "wss://launcher.example.com/ctf_web/ws?t=a",
+		"wss://launcher.example.com/ctf_web/ws":     "wss://launcher.example.com/ctf_web/ws",
+	}
+	for in, want := range tests {
+		if got, err := WebSocketURL(in); err != nil || got != want {
+			t.Errorf("WebSocketURL(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"ftp://host/ctf_web", "ctf_web"} {
+		if _, err := WebSocketURL(in); err == nil {
+			t.Errorf("WebSocketURL(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestSolvePoW(t *testing.T) {
+	solution := SolvePoW("abc", 12)
+	sum := sha256.Sum256([]byte("abc:" + solution))
+	if zeros := leadingZeroBits(sum[:]); zeros < 12 {
+		t.Errorf("SolvePoW() = %s with %d leading zero bits, want at least 12", solution, zeros)
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	var msg message
+	raw := `{"type":"vote_started","v":2,"id":"3","data":{"action":"extend","total_users":2}}`
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		t.Fatal(err)
+	}
+	event, err := decodeEvent(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Vote == nil || event.Vote.Action != protocol.ActionExtend || event.Vote.TotalUsers != 2 || event.ID != "3" {
+		t.Errorf("decodeEvent() = %+v, want an extend vote replying to 3", event)
+	}
+
+	if _, err := decodeEvent(message{Type: protocol.TypeStatus, Data: json.RawMessage(`[]`)}); err == nil {
+		t.Error("decodeEvent() of a malformed status succeeded, want an error")
+	}
+	if event, err := decodeEvent(message{Type: "future", Data: json.RawMessage(`{}`)}); err != nil || string(event.Data) != "{}" {
+		t.Errorf("decodeEvent() of an unknown type = %+v, %v; want its raw data", event, err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package protocol defines the messages of the launcher WebSocket API, served
+// at /<slug>/ws of each challenge page, shared by the launcher server and Go
+// clients.
+//
+// Every message is a JSON object with a type, and a message or data
+// depending on the type. The version of the protocol is negotiated with the
+// Sec-WebSocket-Protocol header (see Subprotocol); connections that name none
+// speak v1, the unversioned messages of the first challenge pages. From v2 on,
+// messages carry their version in "v", and requests may carry an "id" the
+// server copies into its replies, so clients can tell which request failed.
+package protocol
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Versions of the protocol
+const (
+	V1     = 1 // Unversioned messages, the default
+	V2     = 2 // Versioned messages, request IDs and a hello on connect
+	Latest = V2
+)
+
+// subprotocolPrefix starts the Sec-WebSocket-Protocol names of versions
+const subprotocolPrefix = "gzcli.v"
+
+// Subprotocol returns the Sec-WebSocket-Protocol name of a version, e.g.
+// "gzcli.v2"
+func Subprotocol(version int) string {
+	return subprotocolPrefix + strconv.Itoa(version)
+}
+
+// Versions returns the supported versions, oldest first
+func Versions() []int {
+	var versions []int
+	for v := V1; v <= Latest; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// Subprotocols returns the names of the supported versions, latest first, as
+// servers prefer them
+func Subprotocols() []string {
+	var names []string
+	for v := Latest; v >= V1; v-- {
+		names = append(names, Subprotocol(v))
+	}
+	return names
+}
+
+// ParseSubprotocol returns the version of a Sec-WebSocket-Protocol name; V1
+// for an empty or unknown one
+func ParseSubprotocol(name string) int {
+	v, err := strconv.Atoi(strings.TrimPrefix(name, subprotocolPrefix))
+	if err != nil || !strings.HasPrefix(name, subprotocolPrefix) || v < V1 || v > Latest {
+		return V1
+	}
+	return v
+}
+
+// Types of the requests sent by clients
+const (
+	TypePing    = "ping"
+	TypeStart   = "start"   // Data: StartRequest
+	TypeRestart = "restart" // Starts a restart vote; Data: StartRequest
+	TypeVote    = "vote"    // Data: VoteRequest
+	TypeExtend  = "extend"  // Starts a vote to extend the TTL of the instance
+)
+
+// Types of the events sent by the server
+const (
+	TypeHello       = "hello" // v2; Data: Hello, first message of a connection
+	TypePong        = "pong"
+	TypeStatus      = "status"       // Data: Status
+	TypeStats       = "stats"        // Data: Usage
+	TypeTTL         = "ttl"          // Data: TTL
+	TypeGate        = "gate"         // Data: Gate
+	TypeVoteStarted = "vote_started" // Data: Vote
+	TypeVoteUpdate  = "vote_update"  // Data: Vote
+	TypeVoteEnded   = "vote_ended"   // Data: Vote
+	TypeInfo        = "info"         // Message
+	TypeError       = "error"        // Message
+	TypeRemoved     = "removed"      // Message; the challenge was removed from the event
+)
+
+// Vote values and actions
+const (
+	VoteYes       = "yes"
+	VoteNo        = "no"
+	ActionRestart = "restart"
+	ActionExtend  = "extend"
+)
+
+// Instance statuses of Status
+const (
+	StatusStopped    = "stopped"
+	StatusStarting   = "starting"
+	StatusRunning    = "running"
+	StatusStopping   = "stopping"
+	StatusRestarting = "restarting"
+	StatusUnhealthy  = "unhealthy"
+)
+
+// Gate types of Gate
+const (
+	GateTypePoW       = "pow"
+	GateTypeTurnstile = "turnstile"
+	GateTypeHCaptcha  = "hcaptcha"
+)
+
+// StartRequest is the data of start and restart requests
+type StartRequest struct {
+	Gate string `json:"gate,omitempty"` // Solution of the gate, if the challenge has one
+}
+
+// VoteRequest is the data of vote requests
+type VoteRequest struct {
+	Value  string `json:"value"`            // VoteYes or VoteNo
+	Action string `json:"action,omitempty"` // ActionRestart (default) or ActionExtend
+}
+
+// Hello is the first event of a v2 connection
+type Hello struct {
+	Version  int    `json:"version"`  // Version of the connection
+	Versions []int  `json:"versions"` // Versions the server speaks
+	Instance string `json:"instance"` // Instance key of the challenge
+}
+
+// Status is the state of the challenge instance
+type Status struct {
+	Status         string      `json:"status"`
+	ConnectedUsers int         `json:"connected_users"`
+	AllocatedPorts []string    `json:"allocated_ports,omitempty"`
+	PortChecks     []PortCheck `json:"port_checks,omitempty"`
+	TTL            *TTL        `json:"ttl,omitempty"`
+	ProxyPath      string      `json:"proxy_path,omitempty"` // Proxy mode path of the instance, followed by :{container port}/
+}
+
+// PortCheck is the connectivity self-test of an allocated port
+type PortCheck struct {
+	Mapping   string    `json:"mapping"`
+	Port      int       `json:"port"`
+	Protocol  string    `json:"protocol"`
+	State     string    `json:"state"` // pending, reachable or unreachable
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+}
+
+// TTL is the remaining lifetime of an instance with a time limit
+type TTL struct {
+	ExpiresAt        time.Time `json:"expires_at"`
+	RemainingSeconds int       `json:"remaining_seconds"`
+	Extensions       int       `json:"extensions"`
+	MaxExtensions    int       `json:"max_extensions"`
+}
+
+// Usage is the CPU and memory usage of the containers of an instance
+type Usage struct {
+	CPUPercent    float64   `json:"cpu_percent"`    // Sum over containers; 100 is one full core
+	MemoryUsage   uint64    `json:"memory_usage"`   // Bytes, summed over containers
+	MemoryLimit   uint64    `json:"memory_limit"`   // Bytes, the largest container limit
+	MemoryPercent float64   `json:"memory_percent"` // MemoryUsage relative to MemoryLimit
+	Containers    int       `json:"containers"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Gate is what a client must solve before a start or restart request
+type Gate struct {
+	Type       string `json:"type"`
+	Difficulty int    `json:"difficulty,omitempty"` // Leading zero bits of SHA-256(challenge + ":" + solution)
+	Challenge  string `json:"challenge,omitempty"`  // Proof of work challenge
+	SiteKey    string `json:"site_key,omitempty"`
+}
+
+// Vote is a restart or extension vote
+type Vote struct {
+	InitiatorIP  string  `json:"initiator_ip,omitempty"`
+	YesPercent   float64 `json:"yes_percent,omitempty"`
+	NoPercent    float64 `json:"no_percent,omitempty"`
+	TotalUsers   int     `json:"total_users,omitempty"`
+	Result       string  `json:"result,omitempty"` // approved or rejected, in vote_ended
+	RemainingMin int     `json:"remaining_min,omitempty"`
+	Action       string  `json:"action,omitempty"` // ActionRestart or ActionExtend
+}
@@ -0,0 +1,25 @@
+package protocol
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubprotocols(t *testing.T) {
+	if got, want := Subprotocols(), []string{"gzcli.v2", "gzcli.v1"}; !slices.Equal(got, want) {
+		t.Errorf("Subprotocols() = %v, want %v", got, want)
+	}
+	tests := map[string]int{
+		"":         V1,
+		"gzcli.v1": V1,
+		"gzcli.v2": V2,
+		"gzcli.v9": V1,
+		"chat":     V1,
+		"gzcli.vx": V1,
+	}
+	for name, want := range tests {
+		if got := ParseSubprotocol(name); got != want {
+			t.Errorf("ParseSubprotocol(%q) = %d, want %d", name, got, want)
+		}
+	}
+}